@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevWorkspaceBulkOperationSpec defines the desired state of DevWorkspaceBulkOperation
+type DevWorkspaceBulkOperationSpec struct {
+	// Action is the action to perform on each selected DevWorkspace. "Start" starts stopped DevWorkspaces,
+	// "Stop" stops running DevWorkspaces, "Restart" stops and starts again DevWorkspaces that are started, and
+	// "Delete" deletes DevWorkspaces.
+	// +kubebuilder:validation:Enum=Start;Stop;Restart;Delete
+	Action DevWorkspaceBulkAction `json:"action"`
+	// Selector selects the DevWorkspaces the action is performed on by label. If not specified, all
+	// DevWorkspaces in the selected namespaces are selected.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// NamespaceSelector selects the namespaces DevWorkspaces are selected from by label. It may only be set
+	// for DevWorkspaceBulkOperations in the namespace of the DevWorkspace Operator; in that case, an empty
+	// selector selects all namespaces. DevWorkspaceBulkOperations in other namespaces, or in the operator's
+	// namespace when this field is not set, only select DevWorkspaces in their own namespace.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// MaxConcurrent is the maximum number of DevWorkspaces the action is in progress for at any time.
+	// Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+	// TimeoutSeconds is the maximum duration the action may take for a single DevWorkspace, e.g. waiting for
+	// it to stop, before it is considered failed for that DevWorkspace. Defaults to 300 seconds.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+}
+
+type DevWorkspaceBulkAction string
+
+const (
+	BulkActionStart   DevWorkspaceBulkAction = "Start"
+	BulkActionStop    DevWorkspaceBulkAction = "Stop"
+	BulkActionRestart DevWorkspaceBulkAction = "Restart"
+	BulkActionDelete  DevWorkspaceBulkAction = "Delete"
+)
+
+// DevWorkspaceBulkOperationStatus defines the observed state of DevWorkspaceBulkOperation
+type DevWorkspaceBulkOperationStatus struct {
+	// Phase is the current phase of the bulk operation
+	Phase DevWorkspaceBulkOperationPhase `json:"phase,omitempty"`
+	// Message is a user-readable message explaining the current phase
+	Message string `json:"message,omitempty"`
+	// Progress is the number of DevWorkspaces the action has finished for, out of the total number of
+	// selected DevWorkspaces, e.g. "12/50"
+	Progress string `json:"progress,omitempty"`
+	// Succeeded is the number of DevWorkspaces the action succeeded for
+	Succeeded int32 `json:"succeeded,omitempty"`
+	// Failed is the number of DevWorkspaces the action failed for
+	Failed int32 `json:"failed,omitempty"`
+	// Skipped is the number of DevWorkspaces the action was not needed for, e.g. because they were
+	// already stopped
+	Skipped int32 `json:"skipped,omitempty"`
+	// StartTime is the time the operation started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the action finished for all selected DevWorkspaces
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Workspaces contains the status of the action for each DevWorkspace selected when the operation started.
+	// DevWorkspaces created after the operation started are not affected.
+	Workspaces []BulkOperationWorkspaceStatus `json:"workspaces,omitempty"`
+}
+
+type BulkOperationWorkspaceStatus struct {
+	// Name is the name of the DevWorkspace
+	Name string `json:"name"`
+	// Namespace is the namespace of the DevWorkspace
+	Namespace string `json:"namespace"`
+	// State is the state of the action for the DevWorkspace
+	State BulkOperationWorkspaceState `json:"state"`
+	// Message is a user-readable message describing the state, e.g. the reason for failure
+	Message string `json:"message,omitempty"`
+	// StartTime is the time the action was started for the DevWorkspace
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// Valid states for DevWorkspaces in a devworkspacebulkoperation
+type BulkOperationWorkspaceState string
+
+const (
+	BulkOperationWorkspacePending   BulkOperationWorkspaceState = "Pending"
+	BulkOperationWorkspaceStopping  BulkOperationWorkspaceState = "Stopping"
+	BulkOperationWorkspaceStarting  BulkOperationWorkspaceState = "Starting"
+	BulkOperationWorkspaceDeleting  BulkOperationWorkspaceState = "Deleting"
+	BulkOperationWorkspaceSucceeded BulkOperationWorkspaceState = "Succeeded"
+	BulkOperationWorkspaceFailed    BulkOperationWorkspaceState = "Failed"
+	BulkOperationWorkspaceSkipped   BulkOperationWorkspaceState = "Skipped"
+)
+
+// Valid phases for devworkspacebulkoperations
+type DevWorkspaceBulkOperationPhase string
+
+const (
+	BulkOperationPhaseRunning   DevWorkspaceBulkOperationPhase = "Running"
+	BulkOperationPhaseCompleted DevWorkspaceBulkOperationPhase = "Completed"
+	BulkOperationPhaseFailed    DevWorkspaceBulkOperationPhase = "Failed"
+)
+
+// DevWorkspaceBulkOperation is the Schema for the devworkspacebulkoperations API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=devworkspacebulkoperations,scope=Namespaced,shortName=dwbulk
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="The action performed on DevWorkspaces"
+// +kubebuilder:printcolumn:name="Progress",type="string",JSONPath=".status.progress",description="The number of DevWorkspaces processed"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// +kubebuilder:printcolumn:name="Info",type="string",JSONPath=".status.message",description="Additional info about DevWorkspaceBulkOperation state"
+type DevWorkspaceBulkOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspaceBulkOperationSpec   `json:"spec,omitempty"`
+	Status DevWorkspaceBulkOperationStatus `json:"status,omitempty"`
+}
+
+// DevWorkspaceBulkOperationList contains a list of DevWorkspaceBulkOperation
+// +kubebuilder:object:root=true
+type DevWorkspaceBulkOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevWorkspaceBulkOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevWorkspaceBulkOperation{}, &DevWorkspaceBulkOperationList{})
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevWorkspaceGuestSessionSpec defines the desired state of DevWorkspaceGuestSession
+type DevWorkspaceGuestSessionSpec struct {
+	// Template is the template of the DevWorkspace started for the guest session. The DevWorkspace
+	// always uses ephemeral storage, regardless of the storage type set in the template.
+	Template dw.DevWorkspaceTemplateSpec `json:"template"`
+}
+
+// DevWorkspaceGuestSessionStatus defines the observed state of DevWorkspaceGuestSession
+type DevWorkspaceGuestSessionStatus struct {
+	// Phase is the current phase of the guest session
+	Phase DevWorkspaceGuestSessionPhase `json:"phase,omitempty"`
+	// Message is a user-readable message explaining the current phase (e.g. reason for failure)
+	Message string `json:"message,omitempty"`
+	// Namespace is the namespace generated for the guest session
+	Namespace string `json:"namespace,omitempty"`
+	// DevWorkspaceName is the name of the DevWorkspace started for the guest session, within Namespace
+	DevWorkspaceName string `json:"devworkspaceName,omitempty"`
+	// MainUrl is the main URL of the guest session's DevWorkspace, once it is running
+	MainUrl string `json:"mainUrl,omitempty"`
+	// ExpirationTime is the time at which the guest session and all its resources are deleted
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// Valid phases for devworkspaceguestsessions
+type DevWorkspaceGuestSessionPhase string
+
+const (
+	GuestSessionPhasePending  DevWorkspaceGuestSessionPhase = "Pending"
+	GuestSessionPhaseStarting DevWorkspaceGuestSessionPhase = "Starting"
+	GuestSessionPhaseRunning  DevWorkspaceGuestSessionPhase = "Running"
+	GuestSessionPhaseFailed   DevWorkspaceGuestSessionPhase = "Failed"
+)
+
+// DevWorkspaceGuestSession is the Schema for the devworkspaceguestsessions API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=devworkspaceguestsessions,scope=Namespaced,shortName=dwguest
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".status.namespace",description="The namespace generated for the guest session"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".status.expirationTime",description="The time the guest session is deleted"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.mainUrl",description="Url endpoint for accessing the guest session's DevWorkspace"
+type DevWorkspaceGuestSession struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspaceGuestSessionSpec   `json:"spec,omitempty"`
+	Status DevWorkspaceGuestSessionStatus `json:"status,omitempty"`
+}
+
+// DevWorkspaceGuestSessionList contains a list of DevWorkspaceGuestSession
+// +kubebuilder:object:root=true
+type DevWorkspaceGuestSessionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevWorkspaceGuestSession `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevWorkspaceGuestSession{}, &DevWorkspaceGuestSessionList{})
+}
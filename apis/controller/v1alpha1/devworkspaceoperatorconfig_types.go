@@ -21,6 +21,8 @@ import (
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -43,10 +45,170 @@ type OperatorConfiguration struct {
 	// - The devworkspace-controller-manager pod must be terminated and recreated for the
 	//   DevWorkspace Webhook Server deployment to be updated.
 	Webhook *WebhookConfig `json:"webhook,omitempty"`
+	// Metrics defines configuration options related to the metrics exposed by the
+	// DevWorkspace Operator.
+	Metrics *MetricsConfig `json:"metrics,omitempty"`
+	// EventSink configures a webhook to which the DevWorkspace Operator sends lifecycle events
+	// (started, stopped, failed, idled) for DevWorkspaces. This configuration only takes effect
+	// when set in the global DevWorkspaceOperatorConfig.
+	EventSink *EventSinkConfig `json:"eventSink,omitempty"`
+	// Audit configures the audit log, which records when DevWorkspaces are created, started, stopped,
+	// fail and are deleted, along with the user whose request caused the event. This configuration
+	// only takes effect when set in the global DevWorkspaceOperatorConfig.
+	Audit *AuditConfig `json:"audit,omitempty"`
+	// Terminal configures the terminal broker, which provides authenticated shell access to the
+	// containers of running DevWorkspaces. This configuration only takes effect when set in the
+	// global DevWorkspaceOperatorConfig.
+	Terminal *TerminalConfig `json:"terminal,omitempty"`
+	// LogStreaming configures the log streaming endpoint, which streams the logs of the containers of
+	// DevWorkspaces to their users. This configuration only takes effect when set in the global
+	// DevWorkspaceOperatorConfig.
+	LogStreaming *LogStreamingConfig `json:"logStreaming,omitempty"`
+	// CommandExec configures the command execution endpoint, which runs the commands defined in the
+	// devfiles of running DevWorkspaces on behalf of their users, e.g. from CI pipelines. This
+	// configuration only takes effect when set in the global DevWorkspaceOperatorConfig.
+	CommandExec *CommandExecConfig `json:"commandExec,omitempty"`
+	// GitWebhook configures the Git webhook endpoint, which starts or restarts DevWorkspaces when commits
+	// are pushed to the branches they track. This configuration only takes effect when set in the global
+	// DevWorkspaceOperatorConfig.
+	GitWebhook *GitWebhookConfig `json:"gitWebhook,omitempty"`
 	// EnableExperimentalFeatures turns on in-development features of the controller.
 	// This option should generally not be enabled, as any capabilites are subject
 	// to removal without notice.
+	//
+	// Deprecated: use FeatureGates instead. If set to true, all features not explicitly
+	// configured in FeatureGates are enabled.
 	EnableExperimentalFeatures *bool `json:"enableExperimentalFeatures,omitempty"`
+	// FeatureGates is a comma-separated list of <feature>=<true|false> pairs that enable
+	// or disable individual in-development features of the controller, e.g.
+	// "sshAgentPostStart=true,debugLogging=false". Supported features are:
+	//
+	// - sshAgentPostStart: start an ssh-agent in workspaces when the mounted SSH key
+	//   is protected by a passphrase.
+	//
+	// - debugLogging: enable verbose logging in the controller.
+	//
+	// - restrictedSecurityContext: default workspace containers to a security context
+	//   that satisfies the restricted Pod Security Standard.
+	//
+	// Unknown features are ignored.
+	FeatureGates string `json:"featureGates,omitempty"`
+	// CanaryFeatureGates is a comma-separated list of <feature>=<true|false> pairs, in the
+	// same format as FeatureGates, that only apply to DevWorkspaces labelled
+	// `controller.devfile.io/canary: "true"`. For canary DevWorkspaces, features listed here
+	// take precedence over FeatureGates. This can be used to roll out a change in behavior
+	// to a subset of DevWorkspaces, and to compare their failure rates with those of other
+	// DevWorkspaces, before enabling it for all DevWorkspaces.
+	CanaryFeatureGates string `json:"canaryFeatureGates,omitempty"`
+}
+
+type MetricsConfig struct {
+	// EnableServiceMonitor determines whether the operator creates a ServiceMonitor in its
+	// namespace so that its metrics are scraped by the Prometheus Operator. Requires the
+	// monitoring.coreos.com API to be available on the cluster. Disabled by default.
+	EnableServiceMonitor *bool `json:"enableServiceMonitor,omitempty"`
+	// ScrapeInterval is the interval at which Prometheus scrapes the operator's metrics when
+	// the ServiceMonitor is enabled, e.g. "30s". If not specified, the default interval of
+	// the Prometheus instance is used.
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+}
+
+type EventSinkConfig struct {
+	// URL is the endpoint to which DevWorkspace lifecycle events are sent as JSON in HTTP POST
+	// requests. If not specified, events are not sent.
+	URL string `json:"url,omitempty"`
+	// SecretName is the name of a Secret in the DevWorkspace Operator's namespace. The value of its
+	// "secret" key is used to sign the body of each request with HMAC-SHA256; the signature is sent
+	// in the X-DevWorkspace-Signature header. If not specified, requests are not signed.
+	SecretName string `json:"secretName,omitempty"`
+	// Timeout is the maximum duration of a single request to the event sink, e.g. "5s". Events
+	// that cannot be delivered within the timeout are dropped. If not specified, the default
+	// value of "10s" is used.
+	Timeout string `json:"timeout,omitempty"`
+	// Format is the format in which events are sent. Supported values are "devworkspace", which
+	// sends the DevWorkspace Operator's own JSON format, and "cloudevents", which sends CNCF
+	// CloudEvents in HTTP binary content mode, e.g. to a Knative Broker or KafkaSink. If not
+	// specified, the "devworkspace" format is used.
+	// +kubebuilder:validation:Enum=devworkspace;cloudevents
+	Format EventSinkFormat `json:"format,omitempty"`
+	// PhaseTransitions enables sending a "phaseChanged" event for every DevWorkspace phase transition,
+	// in addition to lifecycle events. Each phaseChanged event includes the new and previous phase,
+	// and the time the DevWorkspace was started. Ignored for the audit sink. Disabled by default.
+	PhaseTransitions *bool `json:"phaseTransitions,omitempty"`
+}
+
+type AuditConfig struct {
+	// Enable enables the audit log. Audit records are written to the DevWorkspace Operator's logs as
+	// structured log entries with the logger name "audit". Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// ConfigMapEntries is the number of most recent audit records that are additionally stored in the
+	// "devworkspace-audit-log" ConfigMap in the namespace of each DevWorkspace. If not specified, audit
+	// records are not stored in ConfigMaps.
+	// +kubebuilder:validation:Minimum=0
+	ConfigMapEntries int `json:"configMapEntries,omitempty"`
+	// Sink configures an endpoint to which audit records are sent as JSON in HTTP POST requests, in the
+	// same formats as lifecycle events sent to the event sink. If not specified, audit records are not
+	// sent to an external endpoint.
+	Sink *EventSinkConfig `json:"sink,omitempty"`
+}
+
+type EventSinkFormat string
+
+const (
+	EventSinkFormatDevWorkspace EventSinkFormat = "devworkspace"
+	EventSinkFormatCloudEvents  EventSinkFormat = "cloudevents"
+)
+
+type TerminalConfig struct {
+	// Enable enables the terminal broker. When enabled, the DevWorkspace Operator serves WebSocket
+	// terminal sessions for the containers of running DevWorkspaces on the /terminal/ path of its
+	// manager service. Users authenticate using a bearer token and must either be the creator of
+	// the DevWorkspace or be allowed to create pods/exec in its namespace. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Command is the command used to start a shell in workspace containers. If not specified, bash
+	// is started if it is available in the container, otherwise sh.
+	Command []string `json:"command,omitempty"`
+	// SessionRecording configures a webhook that is notified of terminal sessions and receives a
+	// recording of each session once it ends.
+	SessionRecording *TerminalSessionRecordingConfig `json:"sessionRecording,omitempty"`
+}
+
+type LogStreamingConfig struct {
+	// Enable enables the log streaming endpoint. When enabled, the DevWorkspace Operator streams the
+	// logs of the containers (including init containers) of DevWorkspace pods on the /logs/ path of its
+	// manager service. Users authenticate using a bearer token and must either be the creator of the
+	// DevWorkspace or be allowed to get pods/log in its namespace. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type CommandExecConfig struct {
+	// Enable enables the command execution endpoint. When enabled, the DevWorkspace Operator runs exec
+	// commands from the flattened devfile of running DevWorkspaces in their containers on the /exec/
+	// path of its manager service. Users authenticate using a bearer token and must either be the
+	// creator of the DevWorkspace or be allowed to create pods/exec in its namespace. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type GitWebhookConfig struct {
+	// Enable enables the Git webhook endpoint. When enabled, GitHub and GitLab push events can be sent to
+	// the /git-webhook/<namespace> path of the DevWorkspace Operator's manager service. Webhooks are verified
+	// using the secrets in the namespace with the `controller.devfile.io/git-webhook-secret` label, and
+	// start or restart the DevWorkspaces in the namespace whose `controller.devfile.io/git-webhook-branch`
+	// annotation names the pushed branch and that have a project cloned from the pushed repository.
+	// Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type TerminalSessionRecordingConfig struct {
+	// URL is the endpoint to which terminal session records are sent as JSON in HTTP POST requests.
+	// A "started" record is sent before a session is opened; if the endpoint does not respond with
+	// a 2xx status code, the session is refused. An "ended" record, containing a recording of the
+	// session in asciicast v2 format, is sent once the session is closed. If not specified, sessions
+	// are not recorded.
+	URL string `json:"url,omitempty"`
+	// Timeout is the maximum duration of a single request to the session recording endpoint,
+	// e.g. "5s". If not specified, the default value of "10s" is used.
+	Timeout string `json:"timeout,omitempty"`
 }
 
 type RoutingConfig struct {
@@ -76,6 +238,33 @@ type RoutingConfig struct {
 	TLSCertificateConfigmapRef *ConfigmapReference `json:"tlsCertificateConfigmapRef,omitempty"`
 }
 
+// DevfileFeature is a devfile capability that can be disabled in the DevWorkspaceOperatorConfig.
+// Supported values are:
+//
+// - "kubernetes-components": components that apply Kubernetes objects to the cluster.
+//
+// - "openshift-components": components that apply OpenShift objects to the cluster.
+//
+// - "image-components": components that define images to be built.
+//
+// - "pod-overrides": the `pod-overrides` attribute on the DevWorkspace or its components.
+//
+// - "container-overrides": the `container-overrides` attribute on container components.
+//
+// - "scc": the `controller.devfile.io/scc` attribute, which adds SecurityContextConstraints to the workspace.
+//
+// +kubebuilder:validation:Enum=kubernetes-components;openshift-components;image-components;pod-overrides;container-overrides;scc
+type DevfileFeature string
+
+const (
+	KubernetesComponentsFeature DevfileFeature = "kubernetes-components"
+	OpenShiftComponentsFeature  DevfileFeature = "openshift-components"
+	ImageComponentsFeature      DevfileFeature = "image-components"
+	PodOverridesFeature         DevfileFeature = "pod-overrides"
+	ContainerOverridesFeature   DevfileFeature = "container-overrides"
+	SCCFeature                  DevfileFeature = "scc"
+)
+
 type WorkspaceConfig struct {
 	// ProjectCloneConfig defines configuration related to the project clone init container
 	// that is used to clone git projects into the DevWorkspace.
@@ -93,6 +282,13 @@ type WorkspaceConfig struct {
 	// If not specified, the default "Recreate" deployment strategy is used.
 	// +kubebuilder:validation:Enum=Recreate;RollingUpdate
 	DeploymentStrategy appsv1.DeploymentStrategyType `json:"deploymentStrategy,omitempty"`
+	// StatefulSetStorageTypes lists the storage strategies (e.g. "per-workspace") for which DevWorkspaces are run
+	// as StatefulSets instead of Deployments. StatefulSets give the DevWorkspace pod a stable name and hostname, and
+	// ensure the existing pod has terminated before its replacement is started. DevWorkspace PVCs are still managed
+	// by the operator rather than through volume claim templates, so that DevWorkspaces keep their data when this
+	// setting is changed. The DeploymentStrategy setting is ignored for DevWorkspaces that are run as StatefulSets.
+	// If not specified, all DevWorkspaces are run as Deployments.
+	StatefulSetStorageTypes []string `json:"statefulSetStorageTypes,omitempty"`
 	// PVCName defines the name used for the persistent volume claim created
 	// to support workspace storage when the 'common' storage class is used.
 	// If not specified, the default value of `claim-devworkspace` is used.
@@ -103,40 +299,151 @@ type WorkspaceConfig struct {
 	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
 	// +kubebuilder:validation:MaxLength=63
 	PVCName string `json:"pvcName,omitempty"`
+	// NamingTemplates defines the names of the Deployment (or StatefulSet), Service, DevWorkspaceRouting
+	// and per-workspace PVC generated for DevWorkspaces. Other objects, such as ServiceAccounts, Routes,
+	// Ingresses and ConfigMaps, are not affected. Naming templates are only read from the global
+	// DevWorkspaceOperatorConfig. Names are generated when a DevWorkspace is created and are kept for its
+	// lifetime, so changes to naming templates only apply to DevWorkspaces created afterwards.
+	NamingTemplates *NamingTemplatesConfig `json:"namingTemplates,omitempty"`
 	// ServiceAccount defines configuration options for the ServiceAccount used for
 	// DevWorkspaces.
 	ServiceAccount *ServiceAccountConfig `json:"serviceAccount,omitempty"`
 	// StorageClassName defines an optional storageClass to use for persistent
 	// volume claims created to support DevWorkspaces
 	StorageClassName *string `json:"storageClassName,omitempty"`
+	// StorageAccessModes maps the names of storage classes to the access modes, other than ReadWriteOnce, that
+	// volumes provisioned by the storage class support, e.g. mapping `nfs-client` to `[ReadWriteMany]`. Volume components
+	// can only request an access mode through the controller.devfile.io/access-mode attribute if it is
+	// listed for the storage class used by the DevWorkspace. The key "default" applies to PVCs that do not
+	// specify a storage class. ReadWriteOnce is supported by all storage classes.
+	StorageAccessModes map[string][]corev1.PersistentVolumeAccessMode `json:"storageAccessModes,omitempty"`
 	// DefaultStorageSize defines an optional struct with fields to specify the sizes of Persistent Volume Claims for storage
 	// classes used by DevWorkspaces.
 	DefaultStorageSize *StorageSizes `json:"defaultStorageSize,omitempty"`
+	// AutoResizeCommonPVC enables expanding the common PVC when the sum of the sizes of volumes
+	// defined by workspaces using the "common" or "per-user" storage strategy exceeds the PVC's
+	// size. Expansion is only performed if the PVC's storage class allows volume expansion. If
+	// not specified, the common PVC is not resized.
+	AutoResizeCommonPVC *bool `json:"autoResizeCommonPVC,omitempty"`
+	// CommonPVCGarbageCollection configures periodic removal of data left on the common PVC
+	// by DevWorkspaces that no longer exist, e.g. when the cleanup job run on DevWorkspace
+	// deletion failed or was skipped. This configuration only takes effect when set in the
+	// global DevWorkspaceOperatorConfig.
+	CommonPVCGarbageCollection *CommonPVCGarbageCollectionConfig `json:"commonPVCGarbageCollection,omitempty"`
+	// ProjectBackup configures periodic backups of the /projects volume of running DevWorkspaces
+	// to S3-compatible object storage. When enabled, backups are restored into the /projects volume
+	// when a DevWorkspace starts with an empty /projects volume.
+	ProjectBackup *ProjectBackupConfig `json:"projectBackup,omitempty"`
+	// GuestWorkspaces configures DevWorkspaceGuestSessions, which start short-lived DevWorkspaces
+	// in namespaces generated by the operator, e.g. for anonymous trial workspaces.
+	GuestWorkspaces *GuestWorkspacesConfig `json:"guestWorkspaces,omitempty"`
+	// UserNamespaces configures the provisioning of a dedicated namespace for each user when they
+	// first create a DevWorkspace. The namespace is created from a template and the user is granted
+	// access to it.
+	UserNamespaces *UserNamespacesConfig `json:"userNamespaces,omitempty"`
+	// Trash configures soft-deletion of DevWorkspaces. When enabled, the storage of deleted
+	// DevWorkspaces is retained for a configurable period, during which the DevWorkspace can be
+	// restored.
+	Trash *TrashConfig `json:"trash,omitempty"`
+	// DefaultStorageType defines the storage strategy used for DevWorkspaces that do not set the
+	// `controller.devfile.io/storage-type` attribute. Supported values are "per-user", "common",
+	// "per-workspace", "async", and "ephemeral". Note that with the "ephemeral" storage strategy, all
+	// workspace volumes are emptyDir volumes and data is lost when a workspace is stopped.
+	// If not specified, the "per-user" storage strategy is used.
+	// +kubebuilder:validation:Enum=common;per-user;per-workspace;async;ephemeral
+	DefaultStorageType string `json:"defaultStorageType,omitempty"`
+	// AsyncStorageSyncInterval defines how often the sidecar used by the "async" storage strategy
+	// synchronizes workspace data to the backing persistent volume claim, in addition to the
+	// synchronization performed when the workspace is stopped. The value must be a duration,
+	// e.g. "30s" or "5m". If not specified, the default interval of the sidecar image is used.
+	AsyncStorageSyncInterval string `json:"asyncStorageSyncInterval,omitempty"`
 	// PersistUserHome defines configuration options for persisting the `/home/user/`
 	// directory in workspaces.
 	PersistUserHome *PersistentHomeConfig `json:"persistUserHome,omitempty"`
+	// Dotfiles configures installing dotfiles from a Git repository configured by the user into the persistent
+	// home directory of DevWorkspaces when they start.
+	Dotfiles *DotfilesConfig `json:"dotfiles,omitempty"`
 	// IdleTimeout determines how long a workspace should sit idle before being
 	// automatically scaled down. Proper functionality of this configuration property
 	// requires support in the workspace being started. If not specified, the default
 	// value of "15m" is used.
 	IdleTimeout string `json:"idleTimeout,omitempty"`
+	// IdleWarningPeriod is how long before stopping an idle DevWorkspace the DevWorkspace Operator warns
+	// the DevWorkspace, so that editors can notify users and allow them to keep the DevWorkspace running.
+	// The DevWorkspace Operator only stops idle DevWorkspaces that record user activity in the
+	// controller.devfile.io/last-activity annotation; these are stopped once they have been idle for the
+	// IdleTimeout. If not specified, the default value of "5m" is used.
+	IdleWarningPeriod string `json:"idleWarningPeriod,omitempty"`
 	// ProgressTimeout determines the maximum duration a DevWorkspace can be in
 	// a "Starting" or "Failing" phase without progressing before it is automatically failed.
 	// Duration should be specified in a format parseable by Go's time package, e.g.
 	// "15m", "20s", "1h30m", etc. If not specified, the default value of "5m" is used.
 	ProgressTimeout string `json:"progressTimeout,omitempty"`
+	// PhaseTimeouts defines the maximum duration each phase of DevWorkspace startup may take
+	// before the DevWorkspace is automatically failed. Unlike ProgressTimeout, these timeouts apply
+	// even if the DevWorkspace's status is updated while the phase is in progress. Phases without
+	// a configured timeout are only limited by ProgressTimeout.
+	PhaseTimeouts *PhaseTimeoutsConfig `json:"phaseTimeouts,omitempty"`
+	// StartRetry configures automatically retrying the startup of DevWorkspaces that fail due to
+	// transient causes, such as image pull backoff or unschedulable pods due to node pressure.
+	StartRetry *StartRetryConfig `json:"startRetry,omitempty"`
+	// Limits restricts the resources requested by individual DevWorkspaces and the number of
+	// DevWorkspaces that may run at the same time for each user and namespace.
+	Limits *WorkspaceLimitsConfig `json:"limits,omitempty"`
+	// StartQueue configures an admission queue that limits the number of DevWorkspaces that may be
+	// starting at the same time across the cluster. This configuration only takes effect when set in
+	// the global DevWorkspaceOperatorConfig.
+	StartQueue *StartQueueConfig `json:"startQueue,omitempty"`
+	// RunningBudget limits how long each DevWorkspace may run per week, e.g. to enforce cost or sustainability
+	// policies. DevWorkspaces that exceed their budget are stopped and cannot be started again until the next
+	// week. This configuration only takes effect when set in the global DevWorkspaceOperatorConfig.
+	RunningBudget *RunningBudgetConfig `json:"runningBudget,omitempty"`
+	// UsageAccounting tracks the running time and requested CPU and memory of each DevWorkspace, for
+	// chargeback reports. This configuration only takes effect when set in the global
+	// DevWorkspaceOperatorConfig.
+	UsageAccounting *UsageAccountingConfig `json:"usageAccounting,omitempty"`
+	// RunSchedule stops DevWorkspaces at scheduled times and prevents starting them during blackout windows, e.g.
+	// to stop all DevWorkspaces at night to control cloud costs. This configuration only takes effect when set in
+	// the global DevWorkspaceOperatorConfig.
+	RunSchedule *RunScheduleConfig `json:"runSchedule,omitempty"`
+	// Maintenance puts the cluster in maintenance mode, e.g. for cluster upgrades. DevWorkspaces cannot be started in
+	// maintenance mode, and running DevWorkspaces can optionally be stopped. This configuration only takes effect when set in the global
+	// DevWorkspaceOperatorConfig.
+	Maintenance *MaintenanceConfig `json:"maintenance,omitempty"`
+	// GangScheduling configures scheduling all pods of a DevWorkspace together using a co-scheduling
+	// scheduler plugin, so that DevWorkspaces that run in multiple pods are not started partially.
+	GangScheduling *GangSchedulingConfig `json:"gangScheduling,omitempty"`
+	// NodeFailureRecovery configures recovering DevWorkspaces whose pods are stuck on nodes that
+	// are no longer available, e.g. because persistent volumes remain attached to the failed node.
+	NodeFailureRecovery *NodeFailureRecoveryConfig `json:"nodeFailureRecovery,omitempty"`
+	// PluginRegistry configures resolution of plugins and parents that are referenced by ID
+	// from a devfile registry.
+	PluginRegistry *PluginRegistryConfig `json:"pluginRegistry,omitempty"`
 	// IgnoredUnrecoverableEvents defines a list of Kubernetes event names that should
 	// be ignored when deciding to fail a DevWorkspace startup. This option should be used
 	// if a transient cluster issue is triggering false-positives (for example, if
 	// the cluster occasionally encounters FailedScheduling events). Events listed
 	// here will not trigger DevWorkspace failures.
 	IgnoredUnrecoverableEvents []string `json:"ignoredUnrecoverableEvents,omitempty"`
+	// DisabledDevfileFeatures defines a list of devfile features that may not be used by DevWorkspaces
+	// on the cluster. DevWorkspaces that use a disabled feature are rejected when they are created, and
+	// DevWorkspaces that use a disabled feature through a plugin or parent fail to start. Existing
+	// DevWorkspaces that already use a feature when it is disabled can still be updated, as long as the
+	// update does not add new uses of disabled features. This configuration only takes effect when set
+	// in the global DevWorkspaceOperatorConfig.
+	DisabledDevfileFeatures []DevfileFeature `json:"disabledDevfileFeatures,omitempty"`
+	// AccessControl configures who may exec into, attach to and modify the pods and deployments of
+	// DevWorkspaces. This configuration only takes effect when set in the global DevWorkspaceOperatorConfig.
+	AccessControl *AccessControlConfig `json:"accessControl,omitempty"`
 	// CleanupOnStop governs how the Operator handles stopped DevWorkspaces. If set to
 	// true, additional resources associated with a DevWorkspace (e.g. services, deployments,
 	// configmaps, etc.) will be removed from the cluster when a DevWorkspace has
 	// .spec.started = false. If set to false, resources will be scaled down (e.g. deployments
 	// but the objects will be left on the cluster). The default value is false.
 	CleanupOnStop *bool `json:"cleanupOnStop,omitempty"`
+	// GracefulStop configures running the commands bound to a DevWorkspace's preStop events in its
+	// containers before its pod is stopped, and waiting for them to complete.
+	GracefulStop *GracefulStopConfig `json:"gracefulStop,omitempty"`
 	// PodSecurityContext overrides the default PodSecurityContext used for all workspace-related
 	// pods created by the DevWorkspace Operator. If set, defined values are merged into the default
 	// configuration
@@ -145,6 +452,16 @@ type WorkspaceConfig struct {
 	// workspace-related containers created by the DevWorkspace Operator. If set, defined
 	// values are merged into the default configuration
 	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// SecurityContextPolicy configures a policy that is enforced on the security contexts of all pods
+	// and containers in DevWorkspace deployments, after PodSecurityContext, ContainerSecurityContext and
+	// any pod or container overrides have been applied. Unset fields use per-platform defaults that are
+	// compatible with the restricted-v2 SecurityContextConstraints on OpenShift and the restricted Pod
+	// Security Standard on Kubernetes.
+	SecurityContextPolicy *SecurityContextPolicyConfig `json:"securityContextPolicy,omitempty"`
+	// NetworkIsolation configures a NetworkPolicy in each namespace that contains DevWorkspaces, which
+	// only allows traffic to workspace pods from the same namespace, the ingress controller and the
+	// operator, as well as from additionally allowed namespaces and IP ranges.
+	NetworkIsolation *NetworkIsolationConfig `json:"networkIsolation,omitempty"`
 	// DefaultTemplate defines an optional DevWorkspace Spec Template which gets applied to the workspace
 	// if the workspace's Template Spec Components are not defined. The DefaultTemplate will overwrite the existing
 	// Template Spec, with the exception of Projects (if any are defined).
@@ -161,6 +478,160 @@ type WorkspaceConfig struct {
 	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
 	// RuntimeClassName defines the spec.runtimeClassName for DevWorkspace pods created by the DevWorkspace Operator.
 	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+	// PriorityClassName defines the spec.priorityClassName for DevWorkspace pods created by the
+	// DevWorkspace Operator, e.g. to make DevWorkspaces yield to production workloads when the cluster
+	// is short on resources. The PriorityClass must exist on the cluster. If not specified, the default
+	// priority of the cluster is used.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// PreemptionPolicy defines the spec.preemptionPolicy for DevWorkspace pods created by the DevWorkspace
+	// Operator. Supported values are "PreemptLowerPriority" and "Never". If PriorityClassName is set, the
+	// policy must match the preemptionPolicy of the PriorityClass. If not specified, the policy of the
+	// pod's PriorityClass is used.
+	// +kubebuilder:validation:Enum=PreemptLowerPriority;Never
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+	// TerminationGracePeriodSeconds defines the spec.terminationGracePeriodSeconds for DevWorkspace pods
+	// created by the DevWorkspace Operator, i.e. how long workspace containers are given to shut down
+	// when the DevWorkspace is stopped or its pod is preempted. The default value is 10 seconds.
+	// +kubebuilder:validation:Minimum=0
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+	// SharedMemorySize defines the size of the memory-backed emptyDir volume mounted at /dev/shm in the
+	// containers of DevWorkspace pods created by the DevWorkspace Operator, e.g. for browser tests or databases
+	// that need more than the container runtime's default of 64Mi. Memory used in /dev/shm counts towards the
+	// container's memory limit. Can be overridden for a DevWorkspace using the controller.devfile.io/shm-size
+	// attribute. If not specified, no volume is mounted at /dev/shm.
+	SharedMemorySize *resource.Quantity `json:"sharedMemorySize,omitempty"`
+	// Sysctls defines the namespaced sysctls set for DevWorkspace pods created by the DevWorkspace Operator
+	// (spec.securityContext.sysctls). Sysctls that are not considered safe by Kubernetes must be allowed on
+	// the cluster's nodes, otherwise DevWorkspace pods will fail to start. DevWorkspaces can set additional
+	// safe sysctls using the controller.devfile.io/sysctls attribute.
+	Sysctls []corev1.Sysctl `json:"sysctls,omitempty"`
+	// NodeSelector defines the spec.nodeSelector for DevWorkspace pods created by the DevWorkspace Operator,
+	// e.g. to run DevWorkspaces on a dedicated pool of nodes. Node selectors configured for a namespace via the
+	// controller.devfile.io/node-selector annotation are added to these, and take precedence for the same key.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations defines the spec.tolerations for DevWorkspace pods created by the DevWorkspace Operator.
+	// Tolerations configured for a namespace via the controller.devfile.io/pod-tolerations annotation are
+	// added to these.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity defines the spec.affinity for DevWorkspace pods created by the DevWorkspace Operator. If an
+	// affinity is configured for a namespace via the controller.devfile.io/pod-affinity annotation, it is
+	// used instead.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// GPUProfile defines the node selector and tolerations added to DevWorkspace pods that request GPUs or
+	// other accelerators, e.g. to schedule them on a dedicated pool of tainted GPU nodes. Resources can be
+	// requested for a container component using the controller.devfile.io/extended-resources attribute.
+	GPUProfile *GPUProfileConfig `json:"gpuProfile,omitempty"`
+	// ImageBuild configures how devfile image components are built when a DevWorkspace is started. Images
+	// are built by an OpenShift BuildConfig on OpenShift, and by a Kaniko Job on Kubernetes, and the built
+	// image replaces the image of container components that refer to the image component's imageName.
+	ImageBuild *ImageBuildConfig `json:"imageBuild,omitempty"`
+	// ImageScanning configures checking workspace container images for known vulnerabilities
+	// using an external scanner API before a DevWorkspace is started. Image scanning is
+	// disabled unless a scanner is configured.
+	ImageScanning *ImageScanningConfig `json:"imageScanning,omitempty"`
+	// PinImageDigests controls whether the tags of workspace container images are resolved to
+	// digests when a DevWorkspace is started. Resolved digests are stored in the
+	// `controller.devfile.io/image-digests` annotation on the DevWorkspace and reused when the
+	// DevWorkspace is restarted, so that restarted DevWorkspaces use the same images even if
+	// tags are moved. This can be overridden for a DevWorkspace using the
+	// `controller.devfile.io/pin-image-digests` attribute. Defaults to false.
+	PinImageDigests *bool `json:"pinImageDigests,omitempty"`
+	// ImageMirrors maps image prefixes to the prefix of a mirror that should be used instead, e.g.
+	// `quay.io: registry.example.com/quay-mirror`. All container images in DevWorkspace pods are
+	// rewritten to use the mirror before the DevWorkspace's deployment is created, including images
+	// added by the DevWorkspace Operator such as the project clone init container. Prefixes match
+	// whole components of an image reference, and the longest matching prefix is used.
+	ImageMirrors map[string]string `json:"imageMirrors,omitempty"`
+	// FinalizerHooks defines cleanup hooks that must succeed before a deleted DevWorkspace is removed,
+	// e.g. to deprovision external resources such as DNS records or licenses that are tied to the
+	// DevWorkspace. A finalizer is added to DevWorkspaces for each hook, and hooks are run before the
+	// DevWorkspace's storage and RBAC are cleaned up. This configuration only takes effect when set in
+	// the global DevWorkspaceOperatorConfig.
+	FinalizerHooks []FinalizerHook `json:"finalizerHooks,omitempty"`
+	// SSH configures how SSH keys are provided to DevWorkspaces. The keys in Secrets in a DevWorkspace's
+	// namespace that have the label controller.devfile.io/ssh-key set to "true" are copied to ~/.ssh in
+	// workspace containers by an init container, with permissions accepted by SSH clients.
+	SSH *SSHConfig `json:"ssh,omitempty"`
+	// Standby configures the standby deployment that keeps the containers of components with the
+	// `controller.devfile.io/standby` attribute (e.g. the editor) running with minimal resources after
+	// a DevWorkspace is stopped due to inactivity. When the DevWorkspace is restarted, its pod is
+	// preferably scheduled on the node running the standby deployment, where its images and volumes
+	// are already available, and the standby deployment is removed once the pod is scheduled.
+	Standby *StandbyConfig `json:"standby,omitempty"`
+	// EnvironmentUpdates configures how running DevWorkspaces are updated when the proxy configuration
+	// or the trusted CA certificates in their namespace change after they were started.
+	EnvironmentUpdates *EnvironmentUpdatesConfig `json:"environmentUpdates,omitempty"`
+	// KubernetesAPIProxy configures a sidecar that exposes a restricted proxy to the Kubernetes API in
+	// DevWorkspaces, allowing tools running in the DevWorkspace to deploy to the DevWorkspace's namespace
+	// without the ServiceAccount token being mounted in workspace containers.
+	KubernetesAPIProxy *KubernetesAPIProxyConfig `json:"kubernetesAPIProxy,omitempty"`
+	// ImagePuller configures a DaemonSet that pre-pulls the container images used by the most DevWorkspaces
+	// on the nodes DevWorkspaces are scheduled on, to reduce the time required to start DevWorkspaces. This
+	// field is only used in the global DevWorkspaceOperatorConfig.
+	ImagePuller *ImagePullerConfig `json:"imagePuller,omitempty"`
+	// WarmPools defines pools of pre-provisioned DevWorkspaces that are started once, so that their storage,
+	// routing and images are prepared, and then kept stopped until they are assigned to a user. Assigning
+	// a DevWorkspace from a pool only requires starting it, which is considerably faster than starting a
+	// new DevWorkspace. This field is only used in the global DevWorkspaceOperatorConfig.
+	WarmPools []WarmPoolConfig `json:"warmPools,omitempty"`
+}
+
+type GPUProfileConfig struct {
+	// ResourceNames is the list of resources that cause the GPU profile to be applied to a DevWorkspace pod
+	// when any of its containers requests or limits them. The default value is ["nvidia.com/gpu"].
+	ResourceNames []corev1.ResourceName `json:"resourceNames,omitempty"`
+	// NodeSelector is added to the spec.nodeSelector of DevWorkspace pods that request GPUs, and takes
+	// precedence over other node selectors for the same key.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations are added to the spec.tolerations of DevWorkspace pods that request GPUs.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+type ImageBuildConfig struct {
+	// Registry is the registry and repository prefix that images built for DevWorkspaces are pushed to,
+	// e.g. "quay.io/my-org/workspace-images". Images are pushed as "<registry>/<devworkspace-id>-<component>:<tag>".
+	// Required on Kubernetes. On OpenShift, images are pushed to an ImageStream in the DevWorkspace's
+	// namespace in the internal registry if not specified.
+	Registry string `json:"registry,omitempty"`
+	// PushSecretName is the name of a secret of type kubernetes.io/dockerconfigjson in the DevWorkspace's
+	// namespace that is used to push images to Registry. If not specified, images are pushed without
+	// credentials on Kubernetes, and with the builder ServiceAccount's credentials on OpenShift.
+	PushSecretName string `json:"pushSecretName,omitempty"`
+	// BuilderImage is the Kaniko executor image used to build images on Kubernetes. If not specified,
+	// the default value of "gcr.io/kaniko-project/executor:v1.23.2" is used.
+	BuilderImage string `json:"builderImage,omitempty"`
+	// Timeout is the maximum duration of an image build, after which the DevWorkspace fails to start.
+	// If not specified, the default value of "15m" is used.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type ImageScanningConfig struct {
+	// Scanner defines the type of scanner API used to retrieve vulnerability reports for
+	// images. Supported values are "quay", which reads security scan results for images
+	// hosted in a Quay registry, "clair", which reads vulnerability reports from a
+	// Clair v4 matcher for images referenced by digest, and "trivy", which requests scans
+	// from a Trivy server through the Harbor scanner adapter API (harbor-scanner-trivy)
+	// for images referenced by digest.
+	// +kubebuilder:validation:Enum=quay;clair;trivy
+	Scanner string `json:"scanner,omitempty"`
+	// URL is the base URL of the scanner API, e.g. "https://quay.io",
+	// "http://clairv4.clair.svc:8080" or "http://harbor-scanner-trivy.trivy.svc:8080".
+	URL string `json:"url,omitempty"`
+	// TokenSecretName is the name of a secret in the DevWorkspace Operator's namespace
+	// which contains a "token" key used to authenticate requests to the scanner API.
+	// If not specified, requests are not authenticated.
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+	// SeverityThreshold is the minimum severity for vulnerabilities reported by the
+	// scanner to trigger Policy. Supported values are "Low", "Medium", "High" and
+	// "Critical". If not specified, the default value of "High" is used.
+	// +kubebuilder:validation:Enum=Low;Medium;High;Critical
+	SeverityThreshold string `json:"severityThreshold,omitempty"`
+	// Policy determines how DevWorkspaces using images with vulnerabilities at or above
+	// SeverityThreshold are handled. If set to "Warn", the DevWorkspace is started and a
+	// warning is added to its status. If set to "Block", the DevWorkspace fails to start.
+	// If not specified, the default value of "Warn" is used.
+	// +kubebuilder:validation:Enum=Warn;Block
+	Policy string `json:"policy,omitempty"`
 }
 
 type WebhookConfig struct {
@@ -182,6 +653,508 @@ type WebhookConfig struct {
 	Replicas *int32 `json:"replicas,omitempty"`
 }
 
+type CommonPVCGarbageCollectionConfig struct {
+	// Enable determines whether orphaned DevWorkspace data is periodically removed from
+	// common PVCs. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Interval determines how often orphaned DevWorkspace data is removed. Duration should
+	// be specified in a format parseable by Go's time package, e.g. "12h". If not specified,
+	// the default value of "24h" is used.
+	Interval string `json:"interval,omitempty"`
+}
+
+type ProjectBackupConfig struct {
+	// Enable determines whether the /projects volume of DevWorkspaces is backed up. Disabled
+	// by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Interval determines how often the /projects volume is backed up while a DevWorkspace is
+	// running. Duration should be specified in a format parseable by Go's time package, e.g. "30m".
+	// If not specified, the default value of "1h" is used.
+	Interval string `json:"interval,omitempty"`
+	// URL is the S3-compatible bucket URL backups are stored in, optionally including a key prefix,
+	// e.g. "https://s3.us-east-1.amazonaws.com/my-bucket/backups". Backups are stored as
+	// "<URL>/<namespace>/<DevWorkspace name>/projects.tar.gz".
+	URL string `json:"url,omitempty"`
+	// Region is the region of the bucket, used to sign requests. If not specified, "us-east-1"
+	// is used.
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is the name of a secret in the operator's namespace which contains
+	// the keys "AWS_ACCESS_KEY_ID" and "AWS_SECRET_ACCESS_KEY" used to access the bucket. The
+	// credentials are copied into the namespace of each DevWorkspace that is backed up. If a
+	// secret named "devworkspace-project-backup" exists in a DevWorkspace's namespace, it is
+	// used instead, and may also define the keys "url" and "region" to override the bucket.
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+type AccessControlConfig struct {
+	// RestrictToCreator restricts access to all DevWorkspaces on the cluster to their creator, as if every
+	// DevWorkspace had the `controller.devfile.io/restricted-access` annotation set to "true". Only the
+	// creator of a DevWorkspace and members of the AdminGroups may exec into or attach to its pods, scale
+	// its deployment or modify its pods. If not specified, only DevWorkspaces with the annotation are
+	// restricted.
+	RestrictToCreator *bool `json:"restrictToCreator,omitempty"`
+	// AdminGroups is a list of groups whose members may access and modify the pods and deployments of
+	// restricted-access DevWorkspaces, in addition to the creator of each DevWorkspace.
+	AdminGroups []string `json:"adminGroups,omitempty"`
+}
+
+type GuestWorkspacesConfig struct {
+	// Enable determines whether DevWorkspaceGuestSessions are processed. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// NamespacePrefix is the prefix used for the names of namespaces generated for guest
+	// sessions. If not specified, the default value of "dw-guest-" is used.
+	// +kubebuilder:validation:MaxLength=27
+	NamespacePrefix string `json:"namespacePrefix,omitempty"`
+	// TTL determines how long a guest session lasts before its namespace and DevWorkspace are
+	// deleted, regardless of whether the DevWorkspace is in use. Duration should be specified in
+	// a format parseable by Go's time package, e.g. "30m". If not specified, the default value
+	// of "1h" is used.
+	TTL string `json:"ttl,omitempty"`
+	// Quota defines the hard limits of the ResourceQuota created in each guest session namespace.
+	// If not specified, guest namespaces are limited to 2 CPUs, 4Gi of memory, 10 pods and no
+	// persistent volume claims.
+	Quota corev1.ResourceList `json:"quota,omitempty"`
+}
+
+type NetworkIsolationConfig struct {
+	// Enable determines whether a NetworkPolicy that isolates workspace pods is created in namespaces
+	// that contain DevWorkspaces. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// IngressNamespaceSelector selects the namespaces of the ingress controller or routing gateway
+	// that is allowed to reach workspace pods. If not specified, namespaces labelled
+	// "network.openshift.io/policy-group: ingress" are selected on OpenShift, and the "ingress-nginx"
+	// namespace is selected on Kubernetes.
+	IngressNamespaceSelector *metav1.LabelSelector `json:"ingressNamespaceSelector,omitempty"`
+	// AllowedNamespaces is a list of names of additional namespaces that are allowed to reach
+	// workspace pods.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// AllowedCIDRs is a list of additional IP ranges, in CIDR notation (e.g. "10.0.0.0/16"), that are
+	// allowed to reach workspace pods.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+type NamingTemplatesConfig struct {
+	// Deployment is the template for the name of a DevWorkspace's Deployment, or StatefulSet when the
+	// DevWorkspace is run as a StatefulSet. Templates must contain
+	// the placeholder "<workspace-id>", which is replaced by the DevWorkspace's ID, and must result in
+	// a valid DNS label (at most 63 characters of lowercase alphanumerics and '-') for any DevWorkspace
+	// ID. If not specified, the default value of "<workspace-id>" is used.
+	Deployment string `json:"deployment,omitempty"`
+	// Service is the template for the name of the Service that exposes a DevWorkspace's endpoints.
+	// If not specified, the default value of "<workspace-id>-service" is used.
+	Service string `json:"service,omitempty"`
+	// Routing is the template for the name of a DevWorkspace's DevWorkspaceRouting. If not specified,
+	// the default value of "routing-<workspace-id>" is used.
+	Routing string `json:"routing,omitempty"`
+	// PVC is the template for the name of the PVC used by DevWorkspaces with the per-workspace storage
+	// type. If not specified, the default value of "storage-<workspace-id>" is used.
+	PVC string `json:"pvc,omitempty"`
+}
+
+type UserNamespacesConfig struct {
+	// Enable determines whether a namespace is provisioned for each user that creates a DevWorkspace.
+	// Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// NameTemplate defines the name of the namespace provisioned for a user. The placeholders
+	// "<username>" and "<userid>" are replaced by the name and UID of the user that created the
+	// DevWorkspace. The result is converted to a valid namespace name by lowercasing it and replacing
+	// unsupported characters with "-". If not specified, the default value of "<username>-devworkspaces"
+	// is used.
+	NameTemplate string `json:"nameTemplate,omitempty"`
+	// Labels are additional labels applied to provisioned user namespaces.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are additional annotations applied to provisioned user namespaces.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ClusterRoleName is the name of the ClusterRole bound to the user in their namespace. If not
+	// specified, the default value of "admin" is used.
+	ClusterRoleName string `json:"clusterRoleName,omitempty"`
+	// Quota defines the hard limits of the ResourceQuota created in each user namespace. If not
+	// specified, no ResourceQuota is created.
+	Quota corev1.ResourceList `json:"quota,omitempty"`
+	// LimitRange defines the limits of the LimitRange created in each user namespace. If not
+	// specified, no LimitRange is created.
+	LimitRange []corev1.LimitRangeItem `json:"limitRange,omitempty"`
+	// NetworkPolicy defines the spec of the NetworkPolicy created in each user namespace. If not
+	// specified, no NetworkPolicy is created.
+	NetworkPolicy *networkingv1.NetworkPolicySpec `json:"networkPolicy,omitempty"`
+}
+
+type PhaseTimeoutsConfig struct {
+	// RoutingReady is the maximum duration to wait for the DevWorkspace's networking (DevWorkspaceRouting)
+	// to be ready. Duration should be specified in a format parseable by Go's time package, e.g. "5m".
+	RoutingReady string `json:"routingReady,omitempty"`
+	// StorageReady is the maximum duration to wait for the DevWorkspace's storage to be provisioned
+	// and, when applicable, for its PVC to be bound.
+	StorageReady string `json:"storageReady,omitempty"`
+	// DeploymentReady is the maximum duration to wait for the DevWorkspace's pod to be ready.
+	DeploymentReady string `json:"deploymentReady,omitempty"`
+	// ServersReady is the maximum duration to wait for the DevWorkspace's main endpoint to pass its
+	// health check after the pod is ready.
+	ServersReady string `json:"serversReady,omitempty"`
+}
+
+type StartRetryConfig struct {
+	// MaxAttempts is the maximum number of times the startup of a DevWorkspace is retried after a
+	// transient failure before it is left in the "Failed" phase. The count is reset when the
+	// DevWorkspace is stopped. If not specified, the default value of 0 is used, which disables
+	// retrying startup.
+	// +kubebuilder:validation:Minimum=0
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+	// InitialBackoff is the duration to wait before the first retry. The duration is doubled for
+	// each subsequent retry, up to MaxBackoff. Duration should be specified in a format parseable
+	// by Go's time package, e.g. "30s". If not specified, the default value of "30s" is used.
+	InitialBackoff string `json:"initialBackoff,omitempty"`
+	// MaxBackoff is the maximum duration to wait between retries. Duration should be specified in
+	// a format parseable by Go's time package, e.g. "5m". If not specified, the default value of
+	// "5m" is used.
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+}
+
+type WorkspaceLimitsConfig struct {
+	// MaxResources defines the maximum total resources (e.g. "cpu" and "memory") that may be requested
+	// by the containers of a single DevWorkspace. For containers that do not define a request for a
+	// resource, their limit is used instead. DevWorkspaces that request more resources than allowed fail
+	// to start. If not specified, the resources requested by a DevWorkspace are not limited.
+	MaxResources corev1.ResourceList `json:"maxResources,omitempty"`
+	// MaxRunningPerUser is the maximum number of DevWorkspaces created by the same user that may be
+	// starting or running at the same time, across all namespaces. If not specified, the number of
+	// running DevWorkspaces per user is not limited.
+	// +kubebuilder:validation:Minimum=0
+	MaxRunningPerUser *int32 `json:"maxRunningPerUser,omitempty"`
+	// MaxRunningPerNamespace is the maximum number of DevWorkspaces in a namespace that may be starting
+	// or running at the same time. If not specified, the number of running DevWorkspaces per namespace
+	// is not limited.
+	// +kubebuilder:validation:Minimum=0
+	MaxRunningPerNamespace *int32 `json:"maxRunningPerNamespace,omitempty"`
+	// ExceededPolicy defines how DevWorkspaces that would exceed MaxRunningPerUser or
+	// MaxRunningPerNamespace are handled. With the "Queue" policy, the DevWorkspace waits in the
+	// "Starting" phase until other DevWorkspaces are stopped. With the "Reject" policy, the DevWorkspace
+	// fails to start. If not specified, the default value of "Queue" is used.
+	// +kubebuilder:validation:Enum=Queue;Reject
+	ExceededPolicy string `json:"exceededPolicy,omitempty"`
+}
+
+type StartQueueConfig struct {
+	// MaxConcurrentStarts is the maximum number of DevWorkspaces that may be starting at the same time
+	// across the cluster. Further DevWorkspaces wait in the "Starting" phase until other DevWorkspaces
+	// finish starting, with their position in the queue shown in their status message. If not specified,
+	// the number of DevWorkspaces starting at the same time is not limited.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentStarts *int32 `json:"maxConcurrentStarts,omitempty"`
+	// Ordering defines the order in which queued DevWorkspaces are started. With the "FIFO" ordering,
+	// DevWorkspaces are started in the order in which they were started by users. With the
+	// "FairPerNamespace" ordering, queued DevWorkspaces are started in turn from each namespace, so
+	// that a namespace that starts many DevWorkspaces at once does not delay DevWorkspaces in other
+	// namespaces. If not specified, the default value of "FIFO" is used.
+	// +kubebuilder:validation:Enum=FIFO;FairPerNamespace
+	Ordering string `json:"ordering,omitempty"`
+}
+
+type SecurityContextPolicyConfig struct {
+	// Enable enforces the security context policy on DevWorkspace pods. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// RunAsNonRoot requires all DevWorkspace pods and containers to run as a non-root user. The
+	// default value is true.
+	RunAsNonRoot *bool `json:"runAsNonRoot,omitempty"`
+	// AllowPrivilegeEscalation defines the allowPrivilegeEscalation field of all DevWorkspace containers.
+	// The default value is false.
+	AllowPrivilegeEscalation *bool `json:"allowPrivilegeEscalation,omitempty"`
+	// FSGroupStrategy defines how the fsGroup of DevWorkspace pods is set. With the "RunAsUser" strategy,
+	// the fsGroup is set to the pod's runAsUser, if defined. With the "None" strategy, the fsGroup is
+	// removed from the pod, e.g. so that it is assigned by SecurityContextConstraints. With the "Default"
+	// strategy, the fsGroup is not changed. The default value is "None" on OpenShift and "RunAsUser"
+	// on Kubernetes.
+	// +kubebuilder:validation:Enum=Default;RunAsUser;None
+	FSGroupStrategy string `json:"fsGroupStrategy,omitempty"`
+	// SeccompProfile defines the seccomp profile of DevWorkspace pods. On Kubernetes, the default is the
+	// "RuntimeDefault" profile. On OpenShift, no profile is set, as it is assigned by
+	// SecurityContextConstraints.
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
+	// DropCapabilities defines Linux capabilities that are dropped from all DevWorkspace containers, in
+	// addition to any capabilities dropped in their security context. The default value is ["ALL"].
+	DropCapabilities []corev1.Capability `json:"dropCapabilities,omitempty"`
+	// LabelNamespaces adds the pod-security.kubernetes.io/enforce label with the value "restricted" to
+	// namespaces that contain DevWorkspaces, so that the restricted Pod Security Standard is enforced
+	// for all pods in them. Namespaces that already have this label are not changed. Disabled by default.
+	LabelNamespaces *bool `json:"labelNamespaces,omitempty"`
+}
+
+type RunningBudgetConfig struct {
+	// Weekly is the maximum duration each DevWorkspace may run per week, e.g. "40h". Weeks start on Monday
+	// at 00:00 UTC. DevWorkspaces can set a smaller budget for themselves using the
+	// `controller.devfile.io/weekly-running-budget` attribute. If not specified, only DevWorkspaces that
+	// set the attribute are limited.
+	Weekly string `json:"weekly,omitempty"`
+}
+
+type GracefulStopConfig struct {
+	// Enable enables graceful stop. When a running DevWorkspace that defines preStop events is stopped,
+	// the commands bound to these events are run in its containers as DevWorkspaceTasks, and the
+	// DevWorkspace's pod is only stopped once they complete or the timeout expires. The result is reported
+	// in the DevWorkspace's PreStopCommandsSucceeded condition. When enabled, preStop commands are not added
+	// as lifecycle hooks to the DevWorkspace's containers. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Timeout is the maximum time to wait for preStop commands to complete before stopping the
+	// DevWorkspace's pod, e.g. "2m". Defaults to "2m".
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type UsageAccountingConfig struct {
+	// Enable enables usage accounting. When a DevWorkspace stops, the duration of the run and the CPU
+	// and memory requested by its pod during the run are added to the DevWorkspace's
+	// `controller.devfile.io/usage` annotation and to the monthly totals in the "devworkspace-usage"
+	// ConfigMap in its namespace. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type RunScheduleConfig struct {
+	// TimeZone is the IANA time zone that schedules are evaluated in, e.g. "Europe/Berlin". Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Stop is a list of cron expressions with five fields (minute, hour, day of month, month, day of week) at which
+	// running DevWorkspaces are stopped, e.g. "0 20 * * *" to stop DevWorkspaces every day at 20:00. DevWorkspaces
+	// can set an additional stop schedule for themselves using the `controller.devfile.io/stop-schedule` annotation.
+	Stop []string `json:"stop,omitempty"`
+	// Blackouts is a list of windows during which DevWorkspaces cannot be started. DevWorkspaces that are started
+	// during a blackout window are stopped immediately. DevWorkspaces that are already running when a window starts
+	// are not stopped; use Stop to stop them.
+	Blackouts []BlackoutWindowConfig `json:"blackouts,omitempty"`
+	// Selector restricts the stop schedules and blackout windows to DevWorkspaces with matching labels. If not
+	// specified, they apply to all DevWorkspaces.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+type BlackoutWindowConfig struct {
+	// Start is a cron expression at which the blackout window starts, e.g. "0 22 * * 1-5".
+	Start string `json:"start"`
+	// Duration is how long the blackout window lasts, e.g. "10h".
+	Duration string `json:"duration"`
+}
+
+type MaintenanceConfig struct {
+	// Enable blocks starting DevWorkspaces while the cluster is in maintenance mode. DevWorkspaces that are started
+	// are stopped immediately, and their StoppedForMaintenance condition is set to Message.
+	Enable *bool `json:"enable,omitempty"`
+	// Message is shown in the status of DevWorkspaces that are stopped because of maintenance mode, e.g. to tell users
+	// when maintenance is expected to end. Defaults to "DevWorkspaces cannot be started while the cluster is under
+	// maintenance".
+	Message string `json:"message,omitempty"`
+	// StopRunningWorkspaces stops DevWorkspaces that are already running when maintenance mode is enabled. Stopped
+	// DevWorkspaces run their preStop commands if graceful stop is enabled. Defaults to false.
+	StopRunningWorkspaces *bool `json:"stopRunningWorkspaces,omitempty"`
+	// DrainWindow is the duration over which running DevWorkspaces are stopped when StopRunningWorkspaces is enabled,
+	// so that the cluster is not overloaded by stopping all DevWorkspaces at once. Each DevWorkspace is stopped at a
+	// point in the window that is derived from its UID. Duration should be specified in a format parseable by Go's
+	// time package, e.g. "30m". If not specified, running DevWorkspaces are stopped immediately.
+	DrainWindow string `json:"drainWindow,omitempty"`
+}
+
+type GangSchedulingConfig struct {
+	// Enable creates a PodGroup (scheduling.x-k8s.io/v1alpha1) for DevWorkspaces that run in multiple
+	// pods, e.g. because they define background components, and adds all pods of the DevWorkspace to
+	// it. Pods in a PodGroup are only scheduled once all of them can be scheduled. This requires the
+	// co-scheduling plugin from kubernetes-sigs/scheduler-plugins to be installed and
+	// .workspace.schedulerName to be set to the scheduler that runs it. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// ScheduleTimeoutSeconds is how long the pods of a PodGroup may wait to be scheduled together
+	// before the scheduler gives up and retries. If not specified, the default of the co-scheduling
+	// plugin is used.
+	// +kubebuilder:validation:Minimum=1
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+}
+
+type NodeFailureRecoveryConfig struct {
+	// Policy defines how DevWorkspace pods on unavailable nodes are handled. With the "None" policy,
+	// pods are left for the cluster to clean up. With the "ForceDelete" policy, DevWorkspace pods that
+	// have been terminating on an unavailable node for longer than TerminationTimeout are force-deleted,
+	// and the attachments of the DevWorkspace's persistent volumes to that node are removed, so that the
+	// DevWorkspace can be restarted on another node. If not specified, the default value of "None" is used.
+	// +kubebuilder:validation:Enum=None;ForceDelete
+	Policy string `json:"policy,omitempty"`
+	// TerminationTimeout is how long a DevWorkspace pod may be terminating on an unavailable node
+	// before it is force-deleted. Duration should be specified in a format parseable by Go's time
+	// package, e.g. "5m". If not specified, the default value of "5m" is used.
+	TerminationTimeout string `json:"terminationTimeout,omitempty"`
+}
+
+type PluginRegistryConfig struct {
+	// DefaultRegistryURL is the registry used to resolve plugins and parents that are referenced by
+	// ID but do not specify a registryUrl. Elements are fetched from <registryUrl>/devfiles/<id>, or
+	// <registryUrl>/devfiles/<id>/<version> if a version is specified. If not specified, plugins and
+	// parents referenced by ID must specify a registryUrl.
+	DefaultRegistryURL string `json:"defaultRegistryURL,omitempty"`
+	// CacheTTL defines how long plugins and parents fetched from a registry are cached before they
+	// are fetched again. Duration should be specified in a format parseable by Go's time package,
+	// e.g. "5m". A duration of "0s" disables caching. If not specified, the default value of "5m"
+	// is used.
+	CacheTTL string `json:"cacheTTL,omitempty"`
+}
+
+type TrashConfig struct {
+	// Enable determines whether deleted DevWorkspaces are moved to the trash instead of having
+	// their storage cleaned up immediately. Only DevWorkspaces that use the "per-user", "common"
+	// or "per-workspace" storage types are moved to the trash. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// RetentionPeriod determines how long a deleted DevWorkspace is kept in the trash before its
+	// storage is removed. Duration should be specified in a format parseable by Go's time package,
+	// e.g. "24h". If not specified, the default value of "72h" is used.
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
+}
+
+type FinalizerHook struct {
+	// Name identifies the hook. DevWorkspaces that require the hook to be run when they are deleted
+	// have the finalizer "hooks.controller.devfile.io/<name>".
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	// +kubebuilder:validation:MaxLength=24
+	Name string `json:"name"`
+	// URL is an endpoint to which the DevWorkspace's metadata is sent as JSON in an HTTP POST request
+	// when the DevWorkspace is deleted. The hook succeeds once the endpoint responds with a 2xx status
+	// code; failed requests are retried. Exactly one of URL and Job must be specified.
+	URL string `json:"url,omitempty"`
+	// SecretName is the name of a Secret in the DevWorkspace Operator's namespace. The value of its
+	// "secret" key is used to sign the body of requests sent to URL with HMAC-SHA256; the signature is
+	// sent in the X-DevWorkspace-Signature header. If not specified, requests are not signed.
+	SecretName string `json:"secretName,omitempty"`
+	// Timeout is the maximum duration of a single request to URL, e.g. "5s". If not specified, the
+	// default value of "10s" is used.
+	Timeout string `json:"timeout,omitempty"`
+	// Job defines a Job that is run in the DevWorkspace's namespace when the DevWorkspace is deleted.
+	// The hook succeeds once the Job completes. Exactly one of URL and Job must be specified.
+	Job *FinalizerHookJob `json:"job,omitempty"`
+}
+
+type FinalizerHookJob struct {
+	// Image is the container image used for the Job.
+	Image string `json:"image"`
+	// Command is the entrypoint of the Job's container. If not specified, the image's entrypoint is used.
+	Command []string `json:"command,omitempty"`
+	// Args are the arguments to the Job's entrypoint.
+	Args []string `json:"args,omitempty"`
+	// Env defines additional environment variables for the Job's container. The environment variables
+	// DEVWORKSPACE_NAME, DEVWORKSPACE_NAMESPACE, DEVWORKSPACE_ID, DEVWORKSPACE_UID and DEVWORKSPACE_CREATOR
+	// are always set to describe the deleted DevWorkspace.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// ServiceAccountName is the name of the ServiceAccount in the DevWorkspace's namespace used to run
+	// the Job. If not specified, the namespace's default ServiceAccount is used.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// BackoffLimit is the number of times the Job's pod is retried before the hook is considered failed.
+	// A failed hook blocks the removal of the DevWorkspace until it is deleted manually or the hook is
+	// removed from the configuration. If not specified, the default value of 3 is used.
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+type SSHConfig struct {
+	// KnownHosts defines entries written to ~/.ssh/known_hosts in DevWorkspaces, in the format
+	// printed by ssh-keyscan, e.g. "github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5...".
+	KnownHosts []string `json:"knownHosts,omitempty"`
+	// Hosts defines Host entries written to ~/.ssh/config in DevWorkspaces.
+	Hosts []SSHHostConfig `json:"hosts,omitempty"`
+	// Agent configures an ssh-agent sidecar that loads the SSH keys mounted in the DevWorkspace.
+	Agent *SSHAgentConfig `json:"agent,omitempty"`
+	// Image is the container image used by the init container that copies SSH keys to ~/.ssh and by
+	// the ssh-agent sidecar. The image must provide sh and, if the agent is enabled, ssh-agent and
+	// ssh-add. If not specified, the project clone image is used.
+	Image string `json:"image,omitempty"`
+}
+
+type SSHHostConfig struct {
+	// Host is the host pattern the entry applies to, e.g. "github.com" or "*.example.com".
+	Host string `json:"host"`
+	// Options maps ssh_config options (e.g. Port, User or IdentityFile) to their values for the host.
+	Options map[string]string `json:"options,omitempty"`
+}
+
+type SSHAgentConfig struct {
+	// Enable determines whether an ssh-agent sidecar is added to DevWorkspaces that mount SSH keys.
+	// Keys are added to the agent using the value of the "passphrase" key of their Secret, if present,
+	// and the SSH_AUTH_SOCK environment variable is set in all workspace containers. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type StandbyConfig struct {
+	// Resources defines the resources used by standby containers. Requests and limits defined here
+	// replace those of the component's container; unset values are kept. By default, standby
+	// containers request 10m of CPU and 64Mi of memory.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Timeout defines how long the standby deployment is kept after the DevWorkspace is stopped,
+	// e.g. "8h". Duration should be specified in a format parseable by Go's time package. If not
+	// specified, the default value of "4h" is used.
+	Timeout string `json:"timeout,omitempty"`
+}
+
+type EnvironmentUpdatesConfig struct {
+	// Policy defines what happens to running DevWorkspaces when the proxy configuration or trusted CA
+	// certificates they were started with are outdated. Supported values are "AnnotateOutdated", which
+	// sets the controller.devfile.io/environment-outdated annotation on the DevWorkspace, "Notify", which
+	// additionally records a Warning event for the DevWorkspace, and "Restart", which restarts the
+	// DevWorkspace's pod so that it uses the current proxy configuration and certificates. With
+	// "AnnotateOutdated" and "Notify", DevWorkspaces keep the environment they were started with until they
+	// are restarted. If not specified, the default value of "AnnotateOutdated" is used.
+	// +kubebuilder:validation:Enum=AnnotateOutdated;Notify;Restart
+	Policy EnvironmentUpdatePolicy `json:"policy,omitempty"`
+	// CheckInterval defines how often running DevWorkspaces and the cluster-wide proxy configuration are
+	// checked for changes, e.g. "10m". Duration should be specified in a format parseable by Go's time
+	// package. If not specified, the default value of "5m" is used.
+	CheckInterval string `json:"checkInterval,omitempty"`
+}
+
+type EnvironmentUpdatePolicy string
+
+const (
+	EnvironmentUpdateAnnotateOutdated EnvironmentUpdatePolicy = "AnnotateOutdated"
+	EnvironmentUpdateNotify           EnvironmentUpdatePolicy = "Notify"
+	EnvironmentUpdateRestart          EnvironmentUpdatePolicy = "Restart"
+)
+
+type KubernetesAPIProxyConfig struct {
+	// Enable determines whether the Kubernetes API proxy sidecar is added to DevWorkspaces. When enabled,
+	// the ServiceAccount token is only mounted in the sidecar, and the KUBECONFIG environment variable in
+	// workspace containers points to a kubeconfig that uses the proxy. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Image is the container image used for the proxy sidecar. The image must provide sh and kubectl, and
+	// is required when the proxy is enabled.
+	Image string `json:"image,omitempty"`
+	// AllowedVerbs defines the requests the proxy forwards to the Kubernetes API. Supported values are
+	// "get", which allows get, list and watch requests, "create", "update", "patch" and "delete". Only
+	// requests for resources in the DevWorkspace's namespace and API discovery requests are forwarded.
+	// If not specified, only "get" is allowed.
+	AllowedVerbs []string `json:"allowedVerbs,omitempty"`
+}
+
+type ImagePullerConfig struct {
+	// Enable determines whether the DevWorkspace Operator maintains a DaemonSet in its namespace that
+	// pre-pulls frequently used workspace images. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Image is the container image that provides a statically linked sleep binary at /bin/sleep and the
+	// cp command. The sleep binary is copied into the DaemonSet's containers to keep them running, since
+	// pre-pulled images may not provide one. It is required when the image puller is enabled.
+	Image string `json:"image,omitempty"`
+	// MaxImages is the maximum number of frequently used images that are pre-pulled. Images are ranked
+	// by the number of DevWorkspaces that use them. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	MaxImages *int32 `json:"maxImages,omitempty"`
+	// Images is a list of images that are always pre-pulled, in addition to frequently used images.
+	Images []string `json:"images,omitempty"`
+	// Interval determines how often the images to pre-pull are recomputed from the images used by
+	// DevWorkspaces. Duration should be specified in a format parseable by Go's time package, e.g.
+	// "30m". If not specified, the default value of "1h" is used.
+	Interval string `json:"interval,omitempty"`
+}
+
+type WarmPoolConfig struct {
+	// Name is the name of the pool. Pooled DevWorkspaces are labelled with controller.devfile.io/warm-pool
+	// set to this name. Must be a valid DNS label.
+	Name string `json:"name"`
+	// Namespace is the namespace pooled DevWorkspaces are created in.
+	Namespace string `json:"namespace"`
+	// Template is the name of a DevWorkspaceTemplate in Namespace that is used as the template of pooled
+	// DevWorkspaces. Pooled DevWorkspaces that are not yet assigned are replaced when the template changes.
+	Template string `json:"template"`
+	// Size is the number of unassigned DevWorkspaces kept in the pool.
+	// +kubebuilder:validation:Minimum=0
+	Size int32 `json:"size"`
+}
+
 type PersistentHomeConfig struct {
 	// Determines whether the `/home/user/` directory in workspaces should persist between
 	// workspace shutdown and startup.
@@ -194,6 +1167,23 @@ type PersistentHomeConfig struct {
 	// This field is not used if the `workspace.persistUserHome.enabled` field is set to false.
 	// Enabled by default.
 	DisableInitContainer *bool `json:"disableInitContainer,omitempty"`
+	// Determines whether the persistent home directory is stored on a dedicated PVC for each workspace, instead
+	// of on a subpath of the PVC used for the workspace's other volumes. The dedicated PVC uses the default
+	// per-workspace storage size. This field is ignored for workspaces that use the 'async' storage class.
+	// Disabled by default.
+	DedicatedPVC *bool `json:"dedicatedPVC,omitempty"`
+}
+
+type DotfilesConfig struct {
+	// Enable determines whether dotfiles are installed into DevWorkspaces. The dotfiles repository is
+	// read from the `controller.devfile.io/dotfiles-repository` attribute of a DevWorkspace, or from
+	// a secret or configmap named "devworkspace-dotfiles" in the DevWorkspace's namespace. Dotfiles
+	// are only installed into DevWorkspaces that persist the `/home/user/` directory. Disabled by
+	// default.
+	Enable *bool `json:"enable,omitempty"`
+	// Image is the container image used to clone the dotfiles repository and run its install script.
+	// The image must provide git and a POSIX shell. If not specified, the project clone image is used.
+	Image string `json:"image,omitempty"`
 }
 
 type Proxy struct {
@@ -234,6 +1224,22 @@ type ServiceAccountConfig struct {
 	DisableCreation *bool `json:"disableCreation,omitempty"`
 	// List of ServiceAccount tokens that will be mounted into workspace pods as projected volumes.
 	ServiceAccountTokens []ServiceAccountToken `json:"serviceAccountTokens,omitempty"`
+	// ScopedRBAC configures a Role and RoleBinding that are created for each DevWorkspace and only grant
+	// permissions to the DevWorkspace's ServiceAccount. If enabled, ServiceAccounts are not added to the
+	// default Role shared by all DevWorkspaces in a namespace. Cannot be used together with serviceAccountName.
+	ScopedRBAC *ScopedRBACConfig `json:"scopedRBAC,omitempty"`
+}
+
+type ScopedRBACConfig struct {
+	// Enable determines whether a Role is created for each DevWorkspace's ServiceAccount instead of
+	// binding it to the default Role shared by all DevWorkspaces in a namespace. Disabled by default.
+	Enable *bool `json:"enable,omitempty"`
+	// Rules are the rules of the Role created for each DevWorkspace. The placeholders "<workspace-name>"
+	// and "<workspace-id>" in resourceNames are replaced by the DevWorkspace's name and ID, respectively.
+	// By default, the DevWorkspace's ServiceAccount is allowed to read pods, exec into pods and read and
+	// update its own DevWorkspace. The DevWorkspace Operator must itself have all permissions granted
+	// by these rules.
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
 }
 
 type ServiceAccountToken struct {
@@ -40,6 +40,9 @@ const (
 	DevWorkspaceRoutingCluster     DevWorkspaceRoutingClass = "cluster"
 	DevWorkspaceRoutingClusterTLS  DevWorkspaceRoutingClass = "cluster-tls"
 	DevWorkspaceRoutingWebTerminal DevWorkspaceRoutingClass = "web-terminal"
+	// DevWorkspaceRoutingInternal only creates Services for endpoints, so that they are only reachable via
+	// in-cluster DNS (e.g. through a VPN, port-forward or an external gateway)
+	DevWorkspaceRoutingInternal DevWorkspaceRoutingClass = "internal"
 )
 
 // DevWorkspaceRoutingStatus defines the observed state of DevWorkspaceRouting
@@ -49,10 +52,18 @@ type DevWorkspaceRoutingStatus struct {
 	PodAdditions *PodAdditions `json:"podAdditions,omitempty"`
 	// Machine name to exposed endpoint map
 	ExposedEndpoints map[string]ExposedEndpointList `json:"exposedEndpoints,omitempty"`
+	// Endpoints lists every endpoint of the DevWorkspace with its URL, exposure and whether it is ready
+	// to receive traffic, sorted by machine and endpoint name.
+	Endpoints []EndpointStatus `json:"endpoints,omitempty"`
 	// Routing reconcile phase
 	Phase DevWorkspaceRoutingPhase `json:"phase,omitempty"`
 	// Message is a user-readable message explaining the current phase (e.g. reason for failure)
 	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the DevWorkspaceRouting that the current status applies to. Routing
+	// controllers should set it whenever they update the status, so that the DevWorkspace controller does not use a
+	// Ready status that was computed for an earlier spec. If unset, the status is assumed to be up to date.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // Valid phases for devworkspacerouting
@@ -75,6 +86,20 @@ type ExposedEndpoint struct {
 	Attributes Attributes `json:"attributes,omitempty"`
 }
 
+type EndpointStatus struct {
+	// Name of the endpoint
+	Name string `json:"name"`
+	// Machine (container) that serves the endpoint
+	Machine string `json:"machine"`
+	// URL of the endpoint. Not set for endpoints that are not exposed outside the DevWorkspace pod.
+	// +optional
+	Url string `json:"url,omitempty"`
+	// Exposure of the endpoint
+	Exposure EndpointExposure `json:"exposure"`
+	// Ready is true when the container that serves the endpoint is ready in the DevWorkspace pod
+	Ready bool `json:"ready"`
+}
+
 type EndpointList []Endpoint
 
 type ExposedEndpointList []ExposedEndpoint
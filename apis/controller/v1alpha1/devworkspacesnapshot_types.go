@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevWorkspaceSnapshotSpec defines the desired state of DevWorkspaceSnapshot
+type DevWorkspaceSnapshotSpec struct {
+	// DevWorkspaceName is the name of the DevWorkspace, in the same namespace as the snapshot, whose
+	// persistent storage should be captured. The DevWorkspace must be stopped for the snapshot to be taken.
+	DevWorkspaceName string `json:"devworkspaceName"`
+	// Method determines how the DevWorkspace's storage is captured. If set to "VolumeSnapshot", a
+	// CSI VolumeSnapshot of the DevWorkspace's PVC is created; this is only supported for DevWorkspaces
+	// using the "per-workspace" storage type. If set to "ObjectStorage", the DevWorkspace's data is
+	// archived and uploaded to the location defined in ObjectStorage. If not specified, "VolumeSnapshot"
+	// is used when supported by the DevWorkspace and the cluster, and "ObjectStorage" otherwise.
+	// +kubebuilder:validation:Enum=VolumeSnapshot;ObjectStorage
+	Method DevWorkspaceSnapshotMethod `json:"method,omitempty"`
+	// VolumeSnapshotClassName is the VolumeSnapshotClass used when creating a VolumeSnapshot. If not
+	// specified, the cluster's default VolumeSnapshotClass is used.
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty"`
+	// ObjectStorage defines where the DevWorkspace's data is uploaded when the "ObjectStorage" method is
+	// used.
+	ObjectStorage *SnapshotObjectStorage `json:"objectStorage,omitempty"`
+}
+
+type DevWorkspaceSnapshotMethod string
+
+const (
+	SnapshotMethodVolumeSnapshot DevWorkspaceSnapshotMethod = "VolumeSnapshot"
+	SnapshotMethodObjectStorage  DevWorkspaceSnapshotMethod = "ObjectStorage"
+)
+
+type SnapshotObjectStorage struct {
+	// URL is the S3-compatible bucket URL the archive is uploaded to, optionally including a key prefix,
+	// e.g. "https://s3.us-east-1.amazonaws.com/my-bucket/snapshots". The archive is stored as
+	// "<URL>/<namespace>/<snapshot name>.tar.gz".
+	URL string `json:"url"`
+	// Region is the region of the bucket, used to sign requests. If not specified, "us-east-1" is used.
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is the name of a secret, in the same namespace as the snapshot, which contains
+	// the keys "AWS_ACCESS_KEY_ID" and "AWS_SECRET_ACCESS_KEY" used to access the bucket.
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// DevWorkspaceSnapshotStatus defines the observed state of DevWorkspaceSnapshot
+type DevWorkspaceSnapshotStatus struct {
+	// Phase is the current phase of the snapshot
+	Phase DevWorkspaceSnapshotPhase `json:"phase,omitempty"`
+	// Message is a user-readable message explaining the current phase (e.g. reason for failure)
+	Message string `json:"message,omitempty"`
+	// Method is the method used to capture the DevWorkspace's storage
+	Method DevWorkspaceSnapshotMethod `json:"method,omitempty"`
+	// DevWorkspaceId is the ID of the DevWorkspace the snapshot was taken from
+	DevWorkspaceId string `json:"devworkspaceId,omitempty"`
+	// StorageType is the storage type used by the DevWorkspace the snapshot was taken from
+	StorageType string `json:"storageType,omitempty"`
+	// VolumeSnapshotName is the name of the VolumeSnapshot containing the DevWorkspace's data, when
+	// the "VolumeSnapshot" method is used
+	VolumeSnapshotName string `json:"volumeSnapshotName,omitempty"`
+	// Location is the URL of the archive containing the DevWorkspace's data, when the "ObjectStorage"
+	// method is used
+	Location string `json:"location,omitempty"`
+	// Size is the size of the snapshot, if reported
+	Size string `json:"size,omitempty"`
+	// CompletionTime is the time the snapshot became ready
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// Valid phases for devworkspacesnapshots
+type DevWorkspaceSnapshotPhase string
+
+const (
+	SnapshotPhasePending    DevWorkspaceSnapshotPhase = "Pending"
+	SnapshotPhaseInProgress DevWorkspaceSnapshotPhase = "InProgress"
+	SnapshotPhaseReady      DevWorkspaceSnapshotPhase = "Ready"
+	SnapshotPhaseFailed     DevWorkspaceSnapshotPhase = "Failed"
+)
+
+// DevWorkspaceSnapshot is the Schema for the devworkspacesnapshots API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=devworkspacesnapshots,scope=Namespaced,shortName=dwsnap
+// +kubebuilder:printcolumn:name="DevWorkspace",type="string",JSONPath=".spec.devworkspaceName",description="The DevWorkspace the snapshot is taken from"
+// +kubebuilder:printcolumn:name="Method",type="string",JSONPath=".status.method",description="The method used to capture DevWorkspace storage"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// +kubebuilder:printcolumn:name="Info",type="string",JSONPath=".status.message",description="Additional info about DevWorkspaceSnapshot state"
+type DevWorkspaceSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspaceSnapshotSpec   `json:"spec,omitempty"`
+	Status DevWorkspaceSnapshotStatus `json:"status,omitempty"`
+}
+
+// DevWorkspaceSnapshotList contains a list of DevWorkspaceSnapshot
+// +kubebuilder:object:root=true
+type DevWorkspaceSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevWorkspaceSnapshot `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevWorkspaceSnapshot{}, &DevWorkspaceSnapshotList{})
+}
@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevWorkspaceTaskSpec defines the desired state of DevWorkspaceTask
+type DevWorkspaceTaskSpec struct {
+	// DevWorkspaceName is the name of the DevWorkspace, in the same namespace as the task, that defines
+	// the command to run.
+	DevWorkspaceName string `json:"devworkspaceName"`
+	// CommandId is the ID of the exec command, defined in the DevWorkspace's template, to run. Commands
+	// contributed by parents or plugins are not supported.
+	CommandId string `json:"commandId"`
+	// Mode determines where the command is run. If set to "Exec", the command is run in the corresponding
+	// container of the DevWorkspace's pod; the DevWorkspace must be running. If set to "Job", the command
+	// is run in a new pod that uses the image of the command's component; workspace volumes are not mounted
+	// in this pod. Defaults to "Exec".
+	// +kubebuilder:validation:Enum=Exec;Job
+	Mode DevWorkspaceTaskMode `json:"mode,omitempty"`
+	// TimeoutSeconds is the maximum duration the command is allowed to run for before the task is
+	// considered failed. Defaults to 600 seconds.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+	// HistoryLimit is the number of finished DevWorkspaceTasks for the same DevWorkspace that are retained
+	// once this task finishes, including this task. Older finished tasks are deleted. If not specified,
+	// finished tasks are not deleted.
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+	// OutputConfigMap is the name of a ConfigMap, in the same namespace as the task, that the output of the
+	// command is written to under the key "output". When the "Exec" mode is used, the ConfigMap is updated
+	// periodically while the command runs, and the last 512 KiB of output are stored. When the "Job" mode is
+	// used, the last lines of the job's logs are stored once it finishes. The ConfigMap is owned by the task.
+	// +optional
+	OutputConfigMap string `json:"outputConfigMap,omitempty"`
+}
+
+type DevWorkspaceTaskMode string
+
+const (
+	TaskModeExec DevWorkspaceTaskMode = "Exec"
+	TaskModeJob  DevWorkspaceTaskMode = "Job"
+)
+
+// DevWorkspaceTaskStatus defines the observed state of DevWorkspaceTask
+type DevWorkspaceTaskStatus struct {
+	// Phase is the current phase of the task
+	Phase DevWorkspaceTaskPhase `json:"phase,omitempty"`
+	// Message is a user-readable message explaining the current phase (e.g. reason for failure)
+	Message string `json:"message,omitempty"`
+	// PodName is the name of the pod the command is run in
+	PodName string `json:"podName,omitempty"`
+	// JobName is the name of the job used to run the command, when the "Job" mode is used
+	JobName string `json:"jobName,omitempty"`
+	// ExitCode is the exit code of the command, once it has finished
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Output is the combined standard output and standard error of the command. Only the last 4096 bytes of
+	// output are stored.
+	Output string `json:"output,omitempty"`
+	// StartTime is the time the command started running
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the command finished running
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// Valid phases for devworkspacetasks
+type DevWorkspaceTaskPhase string
+
+const (
+	TaskPhasePending   DevWorkspaceTaskPhase = "Pending"
+	TaskPhaseRunning   DevWorkspaceTaskPhase = "Running"
+	TaskPhaseSucceeded DevWorkspaceTaskPhase = "Succeeded"
+	TaskPhaseFailed    DevWorkspaceTaskPhase = "Failed"
+)
+
+// DevWorkspaceTask is the Schema for the devworkspacetasks API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=devworkspacetasks,scope=Namespaced,shortName=dwtask
+// +kubebuilder:printcolumn:name="DevWorkspace",type="string",JSONPath=".spec.devworkspaceName",description="The DevWorkspace that defines the command"
+// +kubebuilder:printcolumn:name="Command",type="string",JSONPath=".spec.commandId",description="The command that is run"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// +kubebuilder:printcolumn:name="Exit Code",type="integer",JSONPath=".status.exitCode",description="The exit code of the command"
+// +kubebuilder:printcolumn:name="Info",type="string",JSONPath=".status.message",description="Additional info about DevWorkspaceTask state"
+type DevWorkspaceTask struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspaceTaskSpec   `json:"spec,omitempty"`
+	Status DevWorkspaceTaskStatus `json:"status,omitempty"`
+}
+
+// DevWorkspaceTaskList contains a list of DevWorkspaceTask
+// +kubebuilder:object:root=true
+type DevWorkspaceTaskList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevWorkspaceTask `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevWorkspaceTask{}, &DevWorkspaceTaskList{})
+}
@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package v1alpha1
+
+import (
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DevWorkspaceWorkshopSpec defines the desired state of DevWorkspaceWorkshop
+type DevWorkspaceWorkshopSpec struct {
+	// Template is the template used for the DevWorkspace created for each participant
+	Template dw.DevWorkspaceTemplateSpec `json:"template"`
+	// Started determines whether participants' DevWorkspaces are started. Setting this field to false
+	// stops all DevWorkspaces in the workshop. If not specified, DevWorkspaces are started.
+	Started *bool `json:"started,omitempty"`
+	// Participants is the list of participants in the workshop. Each participant is given a DevWorkspace
+	// in a namespace named "<workshop name>-<participant>", which is created by the operator. Participant
+	// names must be valid DNS labels. Removing a participant from this list deletes their namespace.
+	// +kubebuilder:validation:MinItems=1
+	Participants []string `json:"participants"`
+}
+
+// DevWorkspaceWorkshopStatus defines the observed state of DevWorkspaceWorkshop
+type DevWorkspaceWorkshopStatus struct {
+	// Phase is the current phase of the workshop
+	Phase DevWorkspaceWorkshopPhase `json:"phase,omitempty"`
+	// Message is a user-readable message explaining the current phase
+	Message string `json:"message,omitempty"`
+	// Progress is the number of participants whose DevWorkspace is ready, out of the total number of
+	// participants, e.g. "12/50"
+	Progress string `json:"progress,omitempty"`
+	// Participants contains the status of each participant's DevWorkspace
+	Participants []WorkshopParticipantStatus `json:"participants,omitempty"`
+}
+
+type WorkshopParticipantStatus struct {
+	// Name is the name of the participant
+	Name string `json:"name"`
+	// Namespace is the namespace created for the participant
+	Namespace string `json:"namespace,omitempty"`
+	// Phase is the phase of the participant's DevWorkspace
+	Phase dw.DevWorkspacePhase `json:"phase,omitempty"`
+	// MainUrl is the main URL of the participant's DevWorkspace, once it is running
+	MainUrl string `json:"mainUrl,omitempty"`
+	// Message is a user-readable message describing the state of the participant's DevWorkspace
+	Message string `json:"message,omitempty"`
+}
+
+// Valid phases for devworkspaceworkshops
+type DevWorkspaceWorkshopPhase string
+
+const (
+	WorkshopPhaseProvisioning DevWorkspaceWorkshopPhase = "Provisioning"
+	WorkshopPhaseReady        DevWorkspaceWorkshopPhase = "Ready"
+	WorkshopPhaseDegraded     DevWorkspaceWorkshopPhase = "Degraded"
+)
+
+// DevWorkspaceWorkshop is the Schema for the devworkspaceworkshops API
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=devworkspaceworkshops,scope=Namespaced,shortName=dwworkshop
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.progress",description="The number of participants whose DevWorkspace is ready"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// +kubebuilder:printcolumn:name="Info",type="string",JSONPath=".status.message",description="Additional info about DevWorkspaceWorkshop state"
+type DevWorkspaceWorkshop struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DevWorkspaceWorkshopSpec   `json:"spec,omitempty"`
+	Status DevWorkspaceWorkshopStatus `json:"status,omitempty"`
+}
+
+// DevWorkspaceWorkshopList contains a list of DevWorkspaceWorkshop
+// +kubebuilder:object:root=true
+type DevWorkspaceWorkshopList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DevWorkspaceWorkshop `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DevWorkspaceWorkshop{}, &DevWorkspaceWorkshopList{})
+}
@@ -23,6 +23,9 @@ package v1alpha1
 import (
 	"github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -47,6 +50,130 @@ func (in Attributes) DeepCopy() Attributes {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessControlConfig) DeepCopyInto(out *AccessControlConfig) {
+	*out = *in
+	if in.RestrictToCreator != nil {
+		in, out := &in.RestrictToCreator, &out.RestrictToCreator
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AdminGroups != nil {
+		in, out := &in.AdminGroups, &out.AdminGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessControlConfig.
+func (in *AccessControlConfig) DeepCopy() *AccessControlConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessControlConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditConfig) DeepCopyInto(out *AuditConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Sink != nil {
+		in, out := &in.Sink, &out.Sink
+		*out = new(EventSinkConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfig.
+func (in *AuditConfig) DeepCopy() *AuditConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlackoutWindowConfig) DeepCopyInto(out *BlackoutWindowConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlackoutWindowConfig.
+func (in *BlackoutWindowConfig) DeepCopy() *BlackoutWindowConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BlackoutWindowConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BulkOperationWorkspaceStatus) DeepCopyInto(out *BulkOperationWorkspaceStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BulkOperationWorkspaceStatus.
+func (in *BulkOperationWorkspaceStatus) DeepCopy() *BulkOperationWorkspaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BulkOperationWorkspaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandExecConfig) DeepCopyInto(out *CommandExecConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandExecConfig.
+func (in *CommandExecConfig) DeepCopy() *CommandExecConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandExecConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommonPVCGarbageCollectionConfig) DeepCopyInto(out *CommonPVCGarbageCollectionConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommonPVCGarbageCollectionConfig.
+func (in *CommonPVCGarbageCollectionConfig) DeepCopy() *CommonPVCGarbageCollectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonPVCGarbageCollectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigmapReference) DeepCopyInto(out *ConfigmapReference) {
 	*out = *in
@@ -62,6 +189,224 @@ func (in *ConfigmapReference) DeepCopy() *ConfigmapReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceBulkOperation) DeepCopyInto(out *DevWorkspaceBulkOperation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceBulkOperation.
+func (in *DevWorkspaceBulkOperation) DeepCopy() *DevWorkspaceBulkOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceBulkOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceBulkOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceBulkOperationList) DeepCopyInto(out *DevWorkspaceBulkOperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevWorkspaceBulkOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceBulkOperationList.
+func (in *DevWorkspaceBulkOperationList) DeepCopy() *DevWorkspaceBulkOperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceBulkOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceBulkOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceBulkOperationSpec) DeepCopyInto(out *DevWorkspaceBulkOperationSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceBulkOperationSpec.
+func (in *DevWorkspaceBulkOperationSpec) DeepCopy() *DevWorkspaceBulkOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceBulkOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceBulkOperationStatus) DeepCopyInto(out *DevWorkspaceBulkOperationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Workspaces != nil {
+		in, out := &in.Workspaces, &out.Workspaces
+		*out = make([]BulkOperationWorkspaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceBulkOperationStatus.
+func (in *DevWorkspaceBulkOperationStatus) DeepCopy() *DevWorkspaceBulkOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceBulkOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceGuestSession) DeepCopyInto(out *DevWorkspaceGuestSession) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceGuestSession.
+func (in *DevWorkspaceGuestSession) DeepCopy() *DevWorkspaceGuestSession {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceGuestSession)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceGuestSession) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceGuestSessionList) DeepCopyInto(out *DevWorkspaceGuestSessionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevWorkspaceGuestSession, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceGuestSessionList.
+func (in *DevWorkspaceGuestSessionList) DeepCopy() *DevWorkspaceGuestSessionList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceGuestSessionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceGuestSessionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceGuestSessionSpec) DeepCopyInto(out *DevWorkspaceGuestSessionSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceGuestSessionSpec.
+func (in *DevWorkspaceGuestSessionSpec) DeepCopy() *DevWorkspaceGuestSessionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceGuestSessionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceGuestSessionStatus) DeepCopyInto(out *DevWorkspaceGuestSessionStatus) {
+	*out = *in
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceGuestSessionStatus.
+func (in *DevWorkspaceGuestSessionStatus) DeepCopy() *DevWorkspaceGuestSessionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceGuestSessionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DevWorkspaceOperatorConfig) DeepCopyInto(out *DevWorkspaceOperatorConfig) {
 	*out = *in
@@ -247,6 +592,11 @@ func (in *DevWorkspaceRoutingStatus) DeepCopyInto(out *DevWorkspaceRoutingStatus
 			(*out)[key] = outVal
 		}
 	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]EndpointStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceRoutingStatus.
@@ -260,385 +610,1687 @@ func (in *DevWorkspaceRoutingStatus) DeepCopy() *DevWorkspaceRoutingStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Endpoint) DeepCopyInto(out *Endpoint) {
+func (in *DevWorkspaceSnapshot) DeepCopyInto(out *DevWorkspaceSnapshot) {
 	*out = *in
-	if in.Attributes != nil {
-		in, out := &in.Attributes, &out.Attributes
-		*out = make(Attributes, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
-func (in *Endpoint) DeepCopy() *Endpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceSnapshot.
+func (in *DevWorkspaceSnapshot) DeepCopy() *DevWorkspaceSnapshot {
 	if in == nil {
 		return nil
 	}
-	out := new(Endpoint)
+	out := new(DevWorkspaceSnapshot)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceSnapshot) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in EndpointList) DeepCopyInto(out *EndpointList) {
-	{
-		in := &in
-		*out = make(EndpointList, len(*in))
+func (in *DevWorkspaceSnapshotList) DeepCopyInto(out *DevWorkspaceSnapshotList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevWorkspaceSnapshot, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointList.
-func (in EndpointList) DeepCopy() EndpointList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceSnapshotList.
+func (in *DevWorkspaceSnapshotList) DeepCopy() *DevWorkspaceSnapshotList {
 	if in == nil {
 		return nil
 	}
-	out := new(EndpointList)
+	out := new(DevWorkspaceSnapshotList)
 	in.DeepCopyInto(out)
-	return *out
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceSnapshotList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ExposedEndpoint) DeepCopyInto(out *ExposedEndpoint) {
+func (in *DevWorkspaceSnapshotSpec) DeepCopyInto(out *DevWorkspaceSnapshotSpec) {
 	*out = *in
-	if in.Attributes != nil {
-		in, out := &in.Attributes, &out.Attributes
-		*out = make(Attributes, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.VolumeSnapshotClassName != nil {
+		in, out := &in.VolumeSnapshotClassName, &out.VolumeSnapshotClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(SnapshotObjectStorage)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedEndpoint.
-func (in *ExposedEndpoint) DeepCopy() *ExposedEndpoint {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceSnapshotSpec.
+func (in *DevWorkspaceSnapshotSpec) DeepCopy() *DevWorkspaceSnapshotSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ExposedEndpoint)
+	out := new(DevWorkspaceSnapshotSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in ExposedEndpointList) DeepCopyInto(out *ExposedEndpointList) {
-	{
-		in := &in
-		*out = make(ExposedEndpointList, len(*in))
+func (in *DevWorkspaceSnapshotStatus) DeepCopyInto(out *DevWorkspaceSnapshotStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceSnapshotStatus.
+func (in *DevWorkspaceSnapshotStatus) DeepCopy() *DevWorkspaceSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceTask) DeepCopyInto(out *DevWorkspaceTask) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceTask.
+func (in *DevWorkspaceTask) DeepCopy() *DevWorkspaceTask {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceTask)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceTask) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceTaskList) DeepCopyInto(out *DevWorkspaceTaskList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevWorkspaceTask, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedEndpointList.
-func (in ExposedEndpointList) DeepCopy() ExposedEndpointList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceTaskList.
+func (in *DevWorkspaceTaskList) DeepCopy() *DevWorkspaceTaskList {
 	if in == nil {
 		return nil
 	}
-	out := new(ExposedEndpointList)
+	out := new(DevWorkspaceTaskList)
 	in.DeepCopyInto(out)
-	return *out
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceTaskList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeyNotFoundError) DeepCopyInto(out *KeyNotFoundError) {
+func (in *DevWorkspaceTaskSpec) DeepCopyInto(out *DevWorkspaceTaskSpec) {
 	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyNotFoundError.
-func (in *KeyNotFoundError) DeepCopy() *KeyNotFoundError {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceTaskSpec.
+func (in *DevWorkspaceTaskSpec) DeepCopy() *DevWorkspaceTaskSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KeyNotFoundError)
+	out := new(DevWorkspaceTaskSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OperatorConfiguration) DeepCopyInto(out *OperatorConfiguration) {
+func (in *DevWorkspaceTaskStatus) DeepCopyInto(out *DevWorkspaceTaskStatus) {
 	*out = *in
-	if in.Routing != nil {
-		in, out := &in.Routing, &out.Routing
-		*out = new(RoutingConfig)
-		(*in).DeepCopyInto(*out)
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
 	}
-	if in.Workspace != nil {
-		in, out := &in.Workspace, &out.Workspace
-		*out = new(WorkspaceConfig)
-		(*in).DeepCopyInto(*out)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Webhook != nil {
-		in, out := &in.Webhook, &out.Webhook
-		*out = new(WebhookConfig)
-		(*in).DeepCopyInto(*out)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
-	if in.EnableExperimentalFeatures != nil {
-		in, out := &in.EnableExperimentalFeatures, &out.EnableExperimentalFeatures
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceTaskStatus.
+func (in *DevWorkspaceTaskStatus) DeepCopy() *DevWorkspaceTaskStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(DevWorkspaceTaskStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfiguration.
-func (in *OperatorConfiguration) DeepCopy() *OperatorConfiguration {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceWorkshop) DeepCopyInto(out *DevWorkspaceWorkshop) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceWorkshop.
+func (in *DevWorkspaceWorkshop) DeepCopy() *DevWorkspaceWorkshop {
 	if in == nil {
 		return nil
 	}
-	out := new(OperatorConfiguration)
+	out := new(DevWorkspaceWorkshop)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceWorkshop) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PersistentHomeConfig) DeepCopyInto(out *PersistentHomeConfig) {
+func (in *DevWorkspaceWorkshopList) DeepCopyInto(out *DevWorkspaceWorkshopList) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DevWorkspaceWorkshop, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceWorkshopList.
+func (in *DevWorkspaceWorkshopList) DeepCopy() *DevWorkspaceWorkshopList {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceWorkshopList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DevWorkspaceWorkshopList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceWorkshopSpec) DeepCopyInto(out *DevWorkspaceWorkshopSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Started != nil {
+		in, out := &in.Started, &out.Started
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DisableInitContainer != nil {
-		in, out := &in.DisableInitContainer, &out.DisableInitContainer
+	if in.Participants != nil {
+		in, out := &in.Participants, &out.Participants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceWorkshopSpec.
+func (in *DevWorkspaceWorkshopSpec) DeepCopy() *DevWorkspaceWorkshopSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceWorkshopSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DevWorkspaceWorkshopStatus) DeepCopyInto(out *DevWorkspaceWorkshopStatus) {
+	*out = *in
+	if in.Participants != nil {
+		in, out := &in.Participants, &out.Participants
+		*out = make([]WorkshopParticipantStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DevWorkspaceWorkshopStatus.
+func (in *DevWorkspaceWorkshopStatus) DeepCopy() *DevWorkspaceWorkshopStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DevWorkspaceWorkshopStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DotfilesConfig) DeepCopyInto(out *DotfilesConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
 		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentHomeConfig.
-func (in *PersistentHomeConfig) DeepCopy() *PersistentHomeConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DotfilesConfig.
+func (in *DotfilesConfig) DeepCopy() *DotfilesConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(PersistentHomeConfig)
+	out := new(DotfilesConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodAdditions) DeepCopyInto(out *PodAdditions) {
+func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(Attributes, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.Containers != nil {
-		in, out := &in.Containers, &out.Containers
-		*out = make([]v1.Container, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Endpoint.
+func (in *Endpoint) DeepCopy() *Endpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(Endpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in EndpointList) DeepCopyInto(out *EndpointList) {
+	{
+		in := &in
+		*out = make(EndpointList, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointList.
+func (in EndpointList) DeepCopy() EndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointStatus) DeepCopyInto(out *EndpointStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointStatus.
+func (in *EndpointStatus) DeepCopy() *EndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvironmentUpdatesConfig) DeepCopyInto(out *EnvironmentUpdatesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentUpdatesConfig.
+func (in *EnvironmentUpdatesConfig) DeepCopy() *EnvironmentUpdatesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentUpdatesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventSinkConfig) DeepCopyInto(out *EventSinkConfig) {
+	*out = *in
+	if in.PhaseTransitions != nil {
+		in, out := &in.PhaseTransitions, &out.PhaseTransitions
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventSinkConfig.
+func (in *EventSinkConfig) DeepCopy() *EventSinkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EventSinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposedEndpoint) DeepCopyInto(out *ExposedEndpoint) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(Attributes, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]v1.Volume, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedEndpoint.
+func (in *ExposedEndpoint) DeepCopy() *ExposedEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposedEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ExposedEndpointList) DeepCopyInto(out *ExposedEndpointList) {
+	{
+		in := &in
+		*out = make(ExposedEndpointList, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.VolumeMounts != nil {
-		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposedEndpointList.
+func (in ExposedEndpointList) DeepCopy() ExposedEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposedEndpointList)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalizerHook) DeepCopyInto(out *FinalizerHook) {
+	*out = *in
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(FinalizerHookJob)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalizerHook.
+func (in *FinalizerHook) DeepCopy() *FinalizerHook {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalizerHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FinalizerHookJob) DeepCopyInto(out *FinalizerHookJob) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.PullSecrets != nil {
-		in, out := &in.PullSecrets, &out.PullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalizerHookJob.
+func (in *FinalizerHookJob) DeepCopy() *FinalizerHookJob {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalizerHookJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUProfileConfig) DeepCopyInto(out *GPUProfileConfig) {
+	*out = *in
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]v1.ResourceName, len(*in))
 		copy(*out, *in)
 	}
-	if in.ServiceAccountAnnotations != nil {
-		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodAdditions.
-func (in *PodAdditions) DeepCopy() *PodAdditions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUProfileConfig.
+func (in *GPUProfileConfig) DeepCopy() *GPUProfileConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUProfileConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GangSchedulingConfig) DeepCopyInto(out *GangSchedulingConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ScheduleTimeoutSeconds != nil {
+		in, out := &in.ScheduleTimeoutSeconds, &out.ScheduleTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GangSchedulingConfig.
+func (in *GangSchedulingConfig) DeepCopy() *GangSchedulingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GangSchedulingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitWebhookConfig) DeepCopyInto(out *GitWebhookConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitWebhookConfig.
+func (in *GitWebhookConfig) DeepCopy() *GitWebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitWebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GracefulStopConfig) DeepCopyInto(out *GracefulStopConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GracefulStopConfig.
+func (in *GracefulStopConfig) DeepCopy() *GracefulStopConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GracefulStopConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuestWorkspacesConfig) DeepCopyInto(out *GuestWorkspacesConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuestWorkspacesConfig.
+func (in *GuestWorkspacesConfig) DeepCopy() *GuestWorkspacesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GuestWorkspacesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullerConfig) DeepCopyInto(out *ImagePullerConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxImages != nil {
+		in, out := &in.MaxImages, &out.MaxImages
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullerConfig.
+func (in *ImagePullerConfig) DeepCopy() *ImagePullerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBuildConfig) DeepCopyInto(out *ImageBuildConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuildConfig.
+func (in *ImageBuildConfig) DeepCopy() *ImageBuildConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBuildConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageScanningConfig) DeepCopyInto(out *ImageScanningConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageScanningConfig.
+func (in *ImageScanningConfig) DeepCopy() *ImageScanningConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageScanningConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyNotFoundError) DeepCopyInto(out *KeyNotFoundError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyNotFoundError.
+func (in *KeyNotFoundError) DeepCopy() *KeyNotFoundError {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyNotFoundError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesAPIProxyConfig) DeepCopyInto(out *KubernetesAPIProxyConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowedVerbs != nil {
+		in, out := &in.AllowedVerbs, &out.AllowedVerbs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesAPIProxyConfig.
+func (in *KubernetesAPIProxyConfig) DeepCopy() *KubernetesAPIProxyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesAPIProxyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogStreamingConfig) DeepCopyInto(out *LogStreamingConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogStreamingConfig.
+func (in *LogStreamingConfig) DeepCopy() *LogStreamingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LogStreamingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceConfig) DeepCopyInto(out *MaintenanceConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.StopRunningWorkspaces != nil {
+		in, out := &in.StopRunningWorkspaces, &out.StopRunningWorkspaces
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceConfig.
+func (in *MaintenanceConfig) DeepCopy() *MaintenanceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
+	*out = *in
+	if in.EnableServiceMonitor != nil {
+		in, out := &in.EnableServiceMonitor, &out.EnableServiceMonitor
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfig.
+func (in *MetricsConfig) DeepCopy() *MetricsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamingTemplatesConfig) DeepCopyInto(out *NamingTemplatesConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamingTemplatesConfig.
+func (in *NamingTemplatesConfig) DeepCopy() *NamingTemplatesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NamingTemplatesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkIsolationConfig) DeepCopyInto(out *NetworkIsolationConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IngressNamespaceSelector != nil {
+		in, out := &in.IngressNamespaceSelector, &out.IngressNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkIsolationConfig.
+func (in *NetworkIsolationConfig) DeepCopy() *NetworkIsolationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkIsolationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeFailureRecoveryConfig) DeepCopyInto(out *NodeFailureRecoveryConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFailureRecoveryConfig.
+func (in *NodeFailureRecoveryConfig) DeepCopy() *NodeFailureRecoveryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeFailureRecoveryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorConfiguration) DeepCopyInto(out *OperatorConfiguration) {
+	*out = *in
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = new(RoutingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(WorkspaceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventSink != nil {
+		in, out := &in.EventSink, &out.EventSink
+		*out = new(EventSinkConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Terminal != nil {
+		in, out := &in.Terminal, &out.Terminal
+		*out = new(TerminalConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogStreaming != nil {
+		in, out := &in.LogStreaming, &out.LogStreaming
+		*out = new(LogStreamingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CommandExec != nil {
+		in, out := &in.CommandExec, &out.CommandExec
+		*out = new(CommandExecConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitWebhook != nil {
+		in, out := &in.GitWebhook, &out.GitWebhook
+		*out = new(GitWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableExperimentalFeatures != nil {
+		in, out := &in.EnableExperimentalFeatures, &out.EnableExperimentalFeatures
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorConfiguration.
+func (in *OperatorConfiguration) DeepCopy() *OperatorConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentHomeConfig) DeepCopyInto(out *PersistentHomeConfig) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DisableInitContainer != nil {
+		in, out := &in.DisableInitContainer, &out.DisableInitContainer
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DedicatedPVC != nil {
+		in, out := &in.DedicatedPVC, &out.DedicatedPVC
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentHomeConfig.
+func (in *PersistentHomeConfig) DeepCopy() *PersistentHomeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentHomeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTimeoutsConfig) DeepCopyInto(out *PhaseTimeoutsConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTimeoutsConfig.
+func (in *PhaseTimeoutsConfig) DeepCopy() *PhaseTimeoutsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTimeoutsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginRegistryConfig) DeepCopyInto(out *PluginRegistryConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginRegistryConfig.
+func (in *PluginRegistryConfig) DeepCopy() *PluginRegistryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginRegistryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodAdditions) DeepCopyInto(out *PodAdditions) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PullSecrets != nil {
+		in, out := &in.PullSecrets, &out.PullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccountAnnotations != nil {
+		in, out := &in.ServiceAccountAnnotations, &out.ServiceAccountAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodAdditions.
+func (in *PodAdditions) DeepCopy() *PodAdditions {
+	if in == nil {
+		return nil
+	}
+	out := new(PodAdditions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectBackupConfig) DeepCopyInto(out *ProjectBackupConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectBackupConfig.
+func (in *ProjectBackupConfig) DeepCopy() *ProjectBackupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectBackupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectCloneConfig) DeepCopyInto(out *ProjectCloneConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectCloneConfig.
+func (in *ProjectCloneConfig) DeepCopy() *ProjectCloneConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectCloneConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Proxy) DeepCopyInto(out *Proxy) {
+	*out = *in
+	if in.HttpProxy != nil {
+		in, out := &in.HttpProxy, &out.HttpProxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.HttpsProxy != nil {
+		in, out := &in.HttpsProxy, &out.HttpsProxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
+func (in *Proxy) DeepCopy() *Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoutingConfig) DeepCopyInto(out *RoutingConfig) {
+	*out = *in
+	if in.ProxyConfig != nil {
+		in, out := &in.ProxyConfig, &out.ProxyConfig
+		*out = new(Proxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSCertificateConfigmapRef != nil {
+		in, out := &in.TLSCertificateConfigmapRef, &out.TLSCertificateConfigmapRef
+		*out = new(ConfigmapReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingConfig.
+func (in *RoutingConfig) DeepCopy() *RoutingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RoutingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunScheduleConfig) DeepCopyInto(out *RunScheduleConfig) {
+	*out = *in
+	if in.Stop != nil {
+		in, out := &in.Stop, &out.Stop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Blackouts != nil {
+		in, out := &in.Blackouts, &out.Blackouts
+		*out = make([]BlackoutWindowConfig, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunScheduleConfig.
+func (in *RunScheduleConfig) DeepCopy() *RunScheduleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RunScheduleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunningBudgetConfig) DeepCopyInto(out *RunningBudgetConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunningBudgetConfig.
+func (in *RunningBudgetConfig) DeepCopy() *RunningBudgetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RunningBudgetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHAgentConfig) DeepCopyInto(out *SSHAgentConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHAgentConfig.
+func (in *SSHAgentConfig) DeepCopy() *SSHAgentConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHAgentConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHConfig) DeepCopyInto(out *SSHConfig) {
+	*out = *in
+	if in.KnownHosts != nil {
+		in, out := &in.KnownHosts, &out.KnownHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]SSHHostConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(SSHAgentConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHConfig.
+func (in *SSHConfig) DeepCopy() *SSHConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHHostConfig) DeepCopyInto(out *SSHHostConfig) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHHostConfig.
+func (in *SSHHostConfig) DeepCopy() *SSHHostConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHHostConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScopedRBACConfig) DeepCopyInto(out *ScopedRBACConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScopedRBACConfig.
+func (in *ScopedRBACConfig) DeepCopy() *ScopedRBACConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ScopedRBACConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityContextPolicyConfig) DeepCopyInto(out *SecurityContextPolicyConfig) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunAsNonRoot != nil {
+		in, out := &in.RunAsNonRoot, &out.RunAsNonRoot
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowPrivilegeEscalation != nil {
+		in, out := &in.AllowPrivilegeEscalation, &out.AllowPrivilegeEscalation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(v1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DropCapabilities != nil {
+		in, out := &in.DropCapabilities, &out.DropCapabilities
+		*out = make([]v1.Capability, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelNamespaces != nil {
+		in, out := &in.LabelNamespaces, &out.LabelNamespaces
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityContextPolicyConfig.
+func (in *SecurityContextPolicyConfig) DeepCopy() *SecurityContextPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityContextPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountConfig) DeepCopyInto(out *ServiceAccountConfig) {
+	*out = *in
+	if in.DisableCreation != nil {
+		in, out := &in.DisableCreation, &out.DisableCreation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceAccountTokens != nil {
+		in, out := &in.ServiceAccountTokens, &out.ServiceAccountTokens
+		*out = make([]ServiceAccountToken, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScopedRBAC != nil {
+		in, out := &in.ScopedRBAC, &out.ScopedRBAC
+		*out = new(ScopedRBACConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountConfig.
+func (in *ServiceAccountConfig) DeepCopy() *ServiceAccountConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountToken) DeepCopyInto(out *ServiceAccountToken) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountToken.
+func (in *ServiceAccountToken) DeepCopy() *ServiceAccountToken {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotObjectStorage) DeepCopyInto(out *SnapshotObjectStorage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotObjectStorage.
+func (in *SnapshotObjectStorage) DeepCopy() *SnapshotObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StandbyConfig) DeepCopyInto(out *StandbyConfig) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StandbyConfig.
+func (in *StandbyConfig) DeepCopy() *StandbyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StandbyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartQueueConfig) DeepCopyInto(out *StartQueueConfig) {
+	*out = *in
+	if in.MaxConcurrentStarts != nil {
+		in, out := &in.MaxConcurrentStarts, &out.MaxConcurrentStarts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartQueueConfig.
+func (in *StartQueueConfig) DeepCopy() *StartQueueConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StartQueueConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartRetryConfig) DeepCopyInto(out *StartRetryConfig) {
+	*out = *in
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartRetryConfig.
+func (in *StartRetryConfig) DeepCopy() *StartRetryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StartRetryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSizes) DeepCopyInto(out *StorageSizes) {
+	*out = *in
+	if in.Common != nil {
+		in, out := &in.Common, &out.Common
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PerWorkspace != nil {
+		in, out := &in.PerWorkspace, &out.PerWorkspace
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSizes.
+func (in *StorageSizes) DeepCopy() *StorageSizes {
 	if in == nil {
 		return nil
 	}
-	out := new(PodAdditions)
+	out := new(StorageSizes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProjectCloneConfig) DeepCopyInto(out *ProjectCloneConfig) {
+func (in *TerminalConfig) DeepCopyInto(out *TerminalConfig) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
 	}
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SessionRecording != nil {
+		in, out := &in.SessionRecording, &out.SessionRecording
+		*out = new(TerminalSessionRecordingConfig)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectCloneConfig.
-func (in *ProjectCloneConfig) DeepCopy() *ProjectCloneConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerminalConfig.
+func (in *TerminalConfig) DeepCopy() *TerminalConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ProjectCloneConfig)
+	out := new(TerminalConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Proxy) DeepCopyInto(out *Proxy) {
+func (in *TerminalSessionRecordingConfig) DeepCopyInto(out *TerminalSessionRecordingConfig) {
 	*out = *in
-	if in.HttpProxy != nil {
-		in, out := &in.HttpProxy, &out.HttpProxy
-		*out = new(string)
-		**out = **in
-	}
-	if in.HttpsProxy != nil {
-		in, out := &in.HttpsProxy, &out.HttpsProxy
-		*out = new(string)
-		**out = **in
-	}
-	if in.NoProxy != nil {
-		in, out := &in.NoProxy, &out.NoProxy
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
-func (in *Proxy) DeepCopy() *Proxy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TerminalSessionRecordingConfig.
+func (in *TerminalSessionRecordingConfig) DeepCopy() *TerminalSessionRecordingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(Proxy)
+	out := new(TerminalSessionRecordingConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoutingConfig) DeepCopyInto(out *RoutingConfig) {
+func (in *TrashConfig) DeepCopyInto(out *TrashConfig) {
 	*out = *in
-	if in.ProxyConfig != nil {
-		in, out := &in.ProxyConfig, &out.ProxyConfig
-		*out = new(Proxy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TLSCertificateConfigmapRef != nil {
-		in, out := &in.TLSCertificateConfigmapRef, &out.TLSCertificateConfigmapRef
-		*out = new(ConfigmapReference)
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoutingConfig.
-func (in *RoutingConfig) DeepCopy() *RoutingConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrashConfig.
+func (in *TrashConfig) DeepCopy() *TrashConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(RoutingConfig)
+	out := new(TrashConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceAccountConfig) DeepCopyInto(out *ServiceAccountConfig) {
+func (in *UsageAccountingConfig) DeepCopyInto(out *UsageAccountingConfig) {
 	*out = *in
-	if in.DisableCreation != nil {
-		in, out := &in.DisableCreation, &out.DisableCreation
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
 		*out = new(bool)
 		**out = **in
 	}
-	if in.ServiceAccountTokens != nil {
-		in, out := &in.ServiceAccountTokens, &out.ServiceAccountTokens
-		*out = make([]ServiceAccountToken, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountConfig.
-func (in *ServiceAccountConfig) DeepCopy() *ServiceAccountConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageAccountingConfig.
+func (in *UsageAccountingConfig) DeepCopy() *UsageAccountingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceAccountConfig)
+	out := new(UsageAccountingConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceAccountToken) DeepCopyInto(out *ServiceAccountToken) {
+func (in *UserNamespacesConfig) DeepCopyInto(out *UserNamespacesConfig) {
 	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LimitRange != nil {
+		in, out := &in.LimitRange, &out.LimitRange
+		*out = make([]v1.LimitRangeItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(networkingv1.NetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountToken.
-func (in *ServiceAccountToken) DeepCopy() *ServiceAccountToken {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserNamespacesConfig.
+func (in *UserNamespacesConfig) DeepCopy() *UserNamespacesConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceAccountToken)
+	out := new(UserNamespacesConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageSizes) DeepCopyInto(out *StorageSizes) {
+func (in *WarmPoolConfig) DeepCopyInto(out *WarmPoolConfig) {
 	*out = *in
-	if in.Common != nil {
-		in, out := &in.Common, &out.Common
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.PerWorkspace != nil {
-		in, out := &in.PerWorkspace, &out.PerWorkspace
-		x := (*in).DeepCopy()
-		*out = &x
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSizes.
-func (in *StorageSizes) DeepCopy() *StorageSizes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WarmPoolConfig.
+func (in *WarmPoolConfig) DeepCopy() *WarmPoolConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageSizes)
+	out := new(WarmPoolConfig)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -677,6 +2329,21 @@ func (in *WebhookConfig) DeepCopy() *WebhookConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkshopParticipantStatus) DeepCopyInto(out *WorkshopParticipantStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkshopParticipantStatus.
+func (in *WorkshopParticipantStatus) DeepCopy() *WorkshopParticipantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkshopParticipantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 	*out = *in
@@ -685,6 +2352,16 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 		*out = new(ProjectCloneConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StatefulSetStorageTypes != nil {
+		in, out := &in.StatefulSetStorageTypes, &out.StatefulSetStorageTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamingTemplates != nil {
+		in, out := &in.NamingTemplates, &out.NamingTemplates
+		*out = new(NamingTemplatesConfig)
+		**out = **in
+	}
 	if in.ServiceAccount != nil {
 		in, out := &in.ServiceAccount, &out.ServiceAccount
 		*out = new(ServiceAccountConfig)
@@ -695,26 +2372,146 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.StorageAccessModes != nil {
+		in, out := &in.StorageAccessModes, &out.StorageAccessModes
+		*out = make(map[string][]v1.PersistentVolumeAccessMode, len(*in))
+		for key, val := range *in {
+			var outVal []v1.PersistentVolumeAccessMode
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.DefaultStorageSize != nil {
 		in, out := &in.DefaultStorageSize, &out.DefaultStorageSize
 		*out = new(StorageSizes)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AutoResizeCommonPVC != nil {
+		in, out := &in.AutoResizeCommonPVC, &out.AutoResizeCommonPVC
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CommonPVCGarbageCollection != nil {
+		in, out := &in.CommonPVCGarbageCollection, &out.CommonPVCGarbageCollection
+		*out = new(CommonPVCGarbageCollectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProjectBackup != nil {
+		in, out := &in.ProjectBackup, &out.ProjectBackup
+		*out = new(ProjectBackupConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GuestWorkspaces != nil {
+		in, out := &in.GuestWorkspaces, &out.GuestWorkspaces
+		*out = new(GuestWorkspacesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UserNamespaces != nil {
+		in, out := &in.UserNamespaces, &out.UserNamespaces
+		*out = new(UserNamespacesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trash != nil {
+		in, out := &in.Trash, &out.Trash
+		*out = new(TrashConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PersistUserHome != nil {
 		in, out := &in.PersistUserHome, &out.PersistUserHome
 		*out = new(PersistentHomeConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Dotfiles != nil {
+		in, out := &in.Dotfiles, &out.Dotfiles
+		*out = new(DotfilesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PhaseTimeouts != nil {
+		in, out := &in.PhaseTimeouts, &out.PhaseTimeouts
+		*out = new(PhaseTimeoutsConfig)
+		**out = **in
+	}
+	if in.StartRetry != nil {
+		in, out := &in.StartRetry, &out.StartRetry
+		*out = new(StartRetryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(WorkspaceLimitsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartQueue != nil {
+		in, out := &in.StartQueue, &out.StartQueue
+		*out = new(StartQueueConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunningBudget != nil {
+		in, out := &in.RunningBudget, &out.RunningBudget
+		*out = new(RunningBudgetConfig)
+		**out = **in
+	}
+	if in.UsageAccounting != nil {
+		in, out := &in.UsageAccounting, &out.UsageAccounting
+		*out = new(UsageAccountingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunSchedule != nil {
+		in, out := &in.RunSchedule, &out.RunSchedule
+		*out = new(RunScheduleConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GangScheduling != nil {
+		in, out := &in.GangScheduling, &out.GangScheduling
+		*out = new(GangSchedulingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeFailureRecovery != nil {
+		in, out := &in.NodeFailureRecovery, &out.NodeFailureRecovery
+		*out = new(NodeFailureRecoveryConfig)
+		**out = **in
+	}
+	if in.PluginRegistry != nil {
+		in, out := &in.PluginRegistry, &out.PluginRegistry
+		*out = new(PluginRegistryConfig)
+		**out = **in
+	}
 	if in.IgnoredUnrecoverableEvents != nil {
 		in, out := &in.IgnoredUnrecoverableEvents, &out.IgnoredUnrecoverableEvents
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.DisabledDevfileFeatures != nil {
+		in, out := &in.DisabledDevfileFeatures, &out.DisabledDevfileFeatures
+		*out = make([]DevfileFeature, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessControl != nil {
+		in, out := &in.AccessControl, &out.AccessControl
+		*out = new(AccessControlConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CleanupOnStop != nil {
 		in, out := &in.CleanupOnStop, &out.CleanupOnStop
 		*out = new(bool)
 		**out = **in
 	}
+	if in.GracefulStop != nil {
+		in, out := &in.GracefulStop, &out.GracefulStop
+		*out = new(GracefulStopConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
 		*out = new(v1.PodSecurityContext)
@@ -725,6 +2522,16 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 		*out = new(v1.SecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SecurityContextPolicy != nil {
+		in, out := &in.SecurityContextPolicy, &out.SecurityContextPolicy
+		*out = new(SecurityContextPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkIsolation != nil {
+		in, out := &in.NetworkIsolation, &out.NetworkIsolation
+		*out = new(NetworkIsolationConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DefaultTemplate != nil {
 		in, out := &in.DefaultTemplate, &out.DefaultTemplate
 		*out = new(v1alpha2.DevWorkspaceTemplateSpecContent)
@@ -747,6 +2554,109 @@ func (in *WorkspaceConfig) DeepCopyInto(out *WorkspaceConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SharedMemorySize != nil {
+		in, out := &in.SharedMemorySize, &out.SharedMemorySize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make([]v1.Sysctl, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPUProfile != nil {
+		in, out := &in.GPUProfile, &out.GPUProfile
+		*out = new(GPUProfileConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageBuild != nil {
+		in, out := &in.ImageBuild, &out.ImageBuild
+		*out = new(ImageBuildConfig)
+		**out = **in
+	}
+	if in.ImageScanning != nil {
+		in, out := &in.ImageScanning, &out.ImageScanning
+		*out = new(ImageScanningConfig)
+		**out = **in
+	}
+	if in.PinImageDigests != nil {
+		in, out := &in.PinImageDigests, &out.PinImageDigests
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ImageMirrors != nil {
+		in, out := &in.ImageMirrors, &out.ImageMirrors
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FinalizerHooks != nil {
+		in, out := &in.FinalizerHooks, &out.FinalizerHooks
+		*out = make([]FinalizerHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SSH != nil {
+		in, out := &in.SSH, &out.SSH
+		*out = new(SSHConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(StandbyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnvironmentUpdates != nil {
+		in, out := &in.EnvironmentUpdates, &out.EnvironmentUpdates
+		*out = new(EnvironmentUpdatesConfig)
+		**out = **in
+	}
+	if in.KubernetesAPIProxy != nil {
+		in, out := &in.KubernetesAPIProxy, &out.KubernetesAPIProxy
+		*out = new(KubernetesAPIProxyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePuller != nil {
+		in, out := &in.ImagePuller, &out.ImagePuller
+		*out = new(ImagePullerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WarmPools != nil {
+		in, out := &in.WarmPools, &out.WarmPools
+		*out = make([]WarmPoolConfig, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceConfig.
@@ -758,3 +2668,35 @@ func (in *WorkspaceConfig) DeepCopy() *WorkspaceConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceLimitsConfig) DeepCopyInto(out *WorkspaceLimitsConfig) {
+	*out = *in
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxRunningPerUser != nil {
+		in, out := &in.MaxRunningPerUser, &out.MaxRunningPerUser
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRunningPerNamespace != nil {
+		in, out := &in.MaxRunningPerNamespace, &out.MaxRunningPerNamespace
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceLimitsConfig.
+func (in *WorkspaceLimitsConfig) DeepCopy() *WorkspaceLimitsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceLimitsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
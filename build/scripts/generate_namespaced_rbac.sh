@@ -0,0 +1,111 @@
+#!/bin/bash
+#
+# Copyright (c) 2019-2024 Red Hat, Inc.
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# This script generates a Role and RoleBinding granting the DevWorkspace controller
+# its permissions in each namespace it watches when running in namespace-scoped
+# mode (i.e. with the WATCHED_NAMESPACES environment variable set), as well as in
+# the namespace it is installed in. The rules are copied from the controller's
+# ClusterRole in deploy/templates/components/rbac/role.yaml. The generated objects
+# are printed to stdout, and can be applied by a user that is allowed to manage
+# RBAC in those namespaces.
+
+set -e
+
+SCRIPT_DIR=$(cd "$(dirname "$0")"; pwd)
+ROLE_FILE="$SCRIPT_DIR/../../deploy/templates/components/rbac/role.yaml"
+
+NAMESPACE=${NAMESPACE:-devworkspace-controller}
+SERVICE_ACCOUNT=${SERVICE_ACCOUNT:-devworkspace-controller-serviceaccount}
+
+function print_help() {
+  cat << EOF
+Usage: generate_namespaced_rbac.sh [ARGS] <watched namespace>...
+Arguments:
+  --namespace
+      Namespace the controller is installed in. Permissions are also generated
+      for this namespace. Defaults to the value of the NAMESPACE environment
+      variable, or 'devworkspace-controller'.
+  --service-account
+      Name of the controller's ServiceAccount. Defaults to the value of the
+      SERVICE_ACCOUNT environment variable, or 'devworkspace-controller-serviceaccount'.
+EOF
+}
+
+WATCHED_NAMESPACES=()
+while [[ "$#" -gt 0 ]]; do
+  case $1 in
+    --namespace)
+    NAMESPACE=$2
+    shift;;
+    --service-account)
+    SERVICE_ACCOUNT=$2
+    shift;;
+    -h|--help)
+    print_help
+    exit 0
+    ;;
+    *)
+    WATCHED_NAMESPACES+=("$1")
+    ;;
+  esac
+  shift
+done
+
+if [ ${#WATCHED_NAMESPACES[@]} -eq 0 ]; then
+  echo "At least one watched namespace must be specified"
+  print_help
+  exit 1
+fi
+
+RULES=$(sed -n '/^rules:/,$p' "$ROLE_FILE")
+
+# Generate permissions for the operator namespace first, and skip it if it is also watched
+for ns in "$NAMESPACE" "${WATCHED_NAMESPACES[@]}"; do
+  if [ "$ns" == "$NAMESPACE" ] && [ "$GENERATED_OPERATOR_NAMESPACE" == "true" ]; then
+    continue
+  fi
+  if [ "$ns" == "$NAMESPACE" ]; then
+    GENERATED_OPERATOR_NAMESPACE=true
+  fi
+  cat << EOF
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: devworkspace-controller-role
+  namespace: $ns
+  labels:
+    app.kubernetes.io/name: devworkspace-controller
+    app.kubernetes.io/part-of: devworkspace-operator
+$RULES
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: devworkspace-controller-rolebinding
+  namespace: $ns
+  labels:
+    app.kubernetes.io/name: devworkspace-controller
+    app.kubernetes.io/part-of: devworkspace-operator
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: devworkspace-controller-role
+subjects:
+- kind: ServiceAccount
+  name: $SERVICE_ACCOUNT
+  namespace: $NAMESPACE
+EOF
+done
@@ -0,0 +1,447 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacebulkoperation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	// bulkOperationRequeue is the interval at which the state of DevWorkspaces is checked while an operation is running
+	bulkOperationRequeue              = 5 * time.Second
+	defaultBulkOperationTimeout       = 300 * time.Second
+	defaultBulkOperationMaxConcurrent = int32(10)
+)
+
+// DevWorkspaceBulkOperationReconciler reconciles a DevWorkspaceBulkOperation object
+type DevWorkspaceBulkOperationReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspacebulkoperations,verbs=*
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspacebulkoperations/status,verbs=get;update;patch
+
+func (r *DevWorkspaceBulkOperationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	operation := &controllerv1alpha1.DevWorkspaceBulkOperation{}
+	if err := r.Get(ctx, req.NamespacedName, operation); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if operation.DeletionTimestamp != nil || isBulkOperationFinished(operation) {
+		return reconcile.Result{}, nil
+	}
+
+	if operation.Status.Phase == "" {
+		return r.startBulkOperation(ctx, operation, reqLogger)
+	}
+	return r.processBulkOperation(ctx, operation, reqLogger)
+}
+
+// startBulkOperation records the DevWorkspaces selected by a bulk operation in its status. Only DevWorkspaces that
+// exist when the operation starts are processed.
+func (r *DevWorkspaceBulkOperationReconciler) startBulkOperation(ctx context.Context, operation *controllerv1alpha1.DevWorkspaceBulkOperation, logger logr.Logger) (ctrl.Result, error) {
+	targets, err := r.getSelectedWorkspaces(ctx, operation)
+	if err != nil {
+		var failErr *dwerrors.FailError
+		if errors.As(err, &failErr) {
+			now := metav1.Now()
+			operation.Status.Phase = controllerv1alpha1.BulkOperationPhaseFailed
+			operation.Status.Message = failErr.Error()
+			operation.Status.CompletionTime = &now
+			return reconcile.Result{}, r.Status().Update(ctx, operation)
+		}
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Starting DevWorkspaceBulkOperation", "action", operation.Spec.Action, "devworkspaces", len(targets))
+	now := metav1.Now()
+	operation.Status.Phase = controllerv1alpha1.BulkOperationPhaseRunning
+	operation.Status.StartTime = &now
+	operation.Status.Workspaces = targets
+	setBulkOperationProgress(&operation.Status, operation.Spec.Action)
+	if err := r.Status().Update(ctx, operation); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// processBulkOperation checks the state of DevWorkspaces the operation's action is in progress for, and starts the
+// action for pending DevWorkspaces so that at most maxConcurrent DevWorkspaces are in progress at a time.
+func (r *DevWorkspaceBulkOperationReconciler) processBulkOperation(ctx context.Context, operation *controllerv1alpha1.DevWorkspaceBulkOperation, logger logr.Logger) (ctrl.Result, error) {
+	newStatus := operation.Status.DeepCopy()
+	action := operation.Spec.Action
+	timeout := getBulkOperationTimeout(operation)
+
+	inProgress := int32(0)
+	for idx := range newStatus.Workspaces {
+		target := &newStatus.Workspaces[idx]
+		if !isWorkspaceInProgress(target) {
+			continue
+		}
+		if err := r.checkWorkspace(ctx, action, target, timeout); err != nil {
+			return reconcile.Result{}, err
+		}
+		if isWorkspaceInProgress(target) {
+			inProgress++
+		}
+	}
+
+	maxConcurrent := getBulkOperationMaxConcurrent(operation)
+	for idx := range newStatus.Workspaces {
+		if inProgress >= maxConcurrent {
+			break
+		}
+		target := &newStatus.Workspaces[idx]
+		if target.State != controllerv1alpha1.BulkOperationWorkspacePending {
+			continue
+		}
+		if err := r.startWorkspaceAction(ctx, action, target); err != nil {
+			return reconcile.Result{}, err
+		}
+		if isWorkspaceInProgress(target) {
+			inProgress++
+		}
+	}
+
+	finished := setBulkOperationProgress(newStatus, action)
+	if finished {
+		now := metav1.Now()
+		newStatus.Phase = controllerv1alpha1.BulkOperationPhaseCompleted
+		newStatus.CompletionTime = &now
+		logger.Info("DevWorkspaceBulkOperation completed", "action", action, "succeeded", newStatus.Succeeded,
+			"failed", newStatus.Failed, "skipped", newStatus.Skipped)
+	}
+	if !equality.Semantic.DeepEqual(&operation.Status, newStatus) {
+		operation.Status = *newStatus
+		if err := r.Status().Update(ctx, operation); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if finished {
+		return reconcile.Result{}, nil
+	}
+	return reconcile.Result{RequeueAfter: bulkOperationRequeue}, nil
+}
+
+// startWorkspaceAction starts the action for a pending DevWorkspace and updates the state of target accordingly.
+func (r *DevWorkspaceBulkOperationReconciler) startWorkspaceAction(ctx context.Context, action controllerv1alpha1.DevWorkspaceBulkAction, target *controllerv1alpha1.BulkOperationWorkspaceStatus) error {
+	workspace := &dw.DevWorkspace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, workspace); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSkipped, "DevWorkspace no longer exists")
+			return nil
+		}
+		return err
+	}
+
+	now := metav1.Now()
+	target.StartTime = &now
+	switch action {
+	case controllerv1alpha1.BulkActionStart:
+		if workspace.Spec.Started && workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSkipped, "DevWorkspace is already running")
+			return nil
+		}
+		return r.updateWorkspaceStarted(ctx, workspace, true, target, controllerv1alpha1.BulkOperationWorkspaceStarting)
+	case controllerv1alpha1.BulkActionStop:
+		if !workspace.Spec.Started && workspace.Status.Phase == dw.DevWorkspaceStatusStopped {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSkipped, "DevWorkspace is already stopped")
+			return nil
+		}
+		return r.updateWorkspaceStarted(ctx, workspace, false, target, controllerv1alpha1.BulkOperationWorkspaceStopping)
+	case controllerv1alpha1.BulkActionRestart:
+		if !workspace.Spec.Started {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSkipped, "DevWorkspace is not started")
+			return nil
+		}
+		return r.updateWorkspaceStarted(ctx, workspace, false, target, controllerv1alpha1.BulkOperationWorkspaceStopping)
+	case controllerv1alpha1.BulkActionDelete:
+		err := r.Delete(ctx, workspace)
+		switch {
+		case err == nil:
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceDeleting, "Deleting DevWorkspace")
+		case k8sErrors.IsNotFound(err):
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSkipped, "DevWorkspace no longer exists")
+		default:
+			// Deletion may be rejected, e.g. for protected DevWorkspaces
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed, fmt.Sprintf("Failed to delete DevWorkspace: %s", err))
+		}
+		return nil
+	default:
+		setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed, fmt.Sprintf("Unsupported action %s", action))
+		return nil
+	}
+}
+
+// checkWorkspace checks whether the action in progress for a DevWorkspace has finished and updates the state of
+// target accordingly. For restarts, the DevWorkspace is started again once it is stopped.
+func (r *DevWorkspaceBulkOperationReconciler) checkWorkspace(ctx context.Context, action controllerv1alpha1.DevWorkspaceBulkAction, target *controllerv1alpha1.BulkOperationWorkspaceStatus, timeout time.Duration) error {
+	workspace := &dw.DevWorkspace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: target.Namespace}, workspace); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		if target.State == controllerv1alpha1.BulkOperationWorkspaceDeleting {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSucceeded, "DevWorkspace deleted")
+		} else {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed, "DevWorkspace was deleted")
+		}
+		return nil
+	}
+
+	switch target.State {
+	case controllerv1alpha1.BulkOperationWorkspaceStopping:
+		if workspace.Spec.Started {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed, "DevWorkspace was started before it stopped")
+			return nil
+		}
+		if workspace.Status.Phase != dw.DevWorkspaceStatusStopped {
+			break
+		}
+		if action != controllerv1alpha1.BulkActionRestart {
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSucceeded, "DevWorkspace stopped")
+			return nil
+		}
+		return r.updateWorkspaceStarted(ctx, workspace, true, target, controllerv1alpha1.BulkOperationWorkspaceStarting)
+	case controllerv1alpha1.BulkOperationWorkspaceStarting:
+		switch workspace.Status.Phase {
+		case dw.DevWorkspaceStatusRunning:
+			if action == controllerv1alpha1.BulkActionStart {
+				setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSucceeded, "DevWorkspace started")
+			} else {
+				setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceSucceeded, "DevWorkspace restarted")
+			}
+			return nil
+		case dw.DevWorkspaceStatusFailed:
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed,
+				fmt.Sprintf("DevWorkspace failed to start: %s", workspace.Status.Message))
+			return nil
+		}
+	}
+
+	if target.StartTime != nil && time.Since(target.StartTime.Time) > timeout {
+		setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed,
+			fmt.Sprintf("DevWorkspace did not finish %s within %s", getWorkspaceStateVerb(target.State), timeout))
+	}
+	return nil
+}
+
+// updateWorkspaceStarted sets whether a DevWorkspace is started and moves target to nextState. Stopped DevWorkspaces
+// are annotated to record that they were stopped by a bulk operation.
+func (r *DevWorkspaceBulkOperationReconciler) updateWorkspaceStarted(ctx context.Context, workspace *dw.DevWorkspace, started bool, target *controllerv1alpha1.BulkOperationWorkspaceStatus, nextState controllerv1alpha1.BulkOperationWorkspaceState) error {
+	if workspace.Spec.Started != started {
+		workspace.Spec.Started = started
+		if !started {
+			if workspace.Annotations == nil {
+				workspace.Annotations = map[string]string{}
+			}
+			workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = constants.DevWorkspaceStoppedByBulkOperation
+		}
+		if err := r.Update(ctx, workspace); err != nil {
+			if k8sErrors.IsConflict(err) {
+				return err
+			}
+			setWorkspaceState(target, controllerv1alpha1.BulkOperationWorkspaceFailed, fmt.Sprintf("Failed to update DevWorkspace: %s", err))
+			return nil
+		}
+	}
+	setWorkspaceState(target, nextState, fmt.Sprintf("%s DevWorkspace", nextState))
+	return nil
+}
+
+// getSelectedWorkspaces returns the DevWorkspaces selected by a bulk operation, sorted by namespace and name. Returns
+// a FailError if the operation's selectors are invalid.
+func (r *DevWorkspaceBulkOperationReconciler) getSelectedWorkspaces(ctx context.Context, operation *controllerv1alpha1.DevWorkspaceBulkOperation) ([]controllerv1alpha1.BulkOperationWorkspaceStatus, error) {
+	selector := labels.Everything()
+	if operation.Spec.Selector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(operation.Spec.Selector)
+		if err != nil {
+			return nil, &dwerrors.FailError{Message: "Invalid selector", Err: err}
+		}
+	}
+
+	namespaces := []string{operation.Namespace}
+	if operation.Spec.NamespaceSelector != nil {
+		operatorNamespace, err := infrastructure.GetNamespace()
+		if err != nil {
+			return nil, err
+		}
+		if operation.Namespace != operatorNamespace {
+			return nil, &dwerrors.FailError{
+				Message: fmt.Sprintf("namespaceSelector may only be used for DevWorkspaceBulkOperations in namespace %s", operatorNamespace),
+			}
+		}
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(operation.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, &dwerrors.FailError{Message: "Invalid namespaceSelector", Err: err}
+		}
+		namespaceList := &corev1.NamespaceList{}
+		if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+			return nil, err
+		}
+		namespaces = nil
+		for _, namespace := range namespaceList.Items {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	var targets []controllerv1alpha1.BulkOperationWorkspaceStatus
+	for _, namespace := range namespaces {
+		workspaceList := &dw.DevWorkspaceList{}
+		if err := r.List(ctx, workspaceList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, err
+		}
+		for _, workspace := range workspaceList.Items {
+			if workspace.DeletionTimestamp != nil {
+				continue
+			}
+			targets = append(targets, controllerv1alpha1.BulkOperationWorkspaceStatus{
+				Name:      workspace.Name,
+				Namespace: workspace.Namespace,
+				State:     controllerv1alpha1.BulkOperationWorkspacePending,
+			})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Namespace != targets[j].Namespace {
+			return targets[i].Namespace < targets[j].Namespace
+		}
+		return targets[i].Name < targets[j].Name
+	})
+	return targets, nil
+}
+
+// setBulkOperationProgress updates the counters, progress and message in a bulk operation's status based on the state
+// of each DevWorkspace. Returns whether the action has finished for all DevWorkspaces.
+func setBulkOperationProgress(status *controllerv1alpha1.DevWorkspaceBulkOperationStatus, action controllerv1alpha1.DevWorkspaceBulkAction) (finished bool) {
+	var succeeded, failed, skipped, inProgress int32
+	for _, target := range status.Workspaces {
+		switch {
+		case target.State == controllerv1alpha1.BulkOperationWorkspaceSucceeded:
+			succeeded++
+		case target.State == controllerv1alpha1.BulkOperationWorkspaceFailed:
+			failed++
+		case target.State == controllerv1alpha1.BulkOperationWorkspaceSkipped:
+			skipped++
+		case isWorkspaceInProgress(&target):
+			inProgress++
+		}
+	}
+	total := len(status.Workspaces)
+	processed := int(succeeded + failed + skipped)
+	status.Succeeded = succeeded
+	status.Failed = failed
+	status.Skipped = skipped
+	status.Progress = fmt.Sprintf("%d/%d", processed, total)
+	if processed == total {
+		status.Message = fmt.Sprintf("%s completed for %d DevWorkspaces: %d succeeded, %d failed, %d skipped", action, total, succeeded, failed, skipped)
+		return true
+	}
+	status.Message = fmt.Sprintf("%s in progress for %d DevWorkspaces", action, inProgress)
+	return false
+}
+
+func setWorkspaceState(target *controllerv1alpha1.BulkOperationWorkspaceStatus, state controllerv1alpha1.BulkOperationWorkspaceState, message string) {
+	target.State = state
+	target.Message = message
+}
+
+func isWorkspaceInProgress(target *controllerv1alpha1.BulkOperationWorkspaceStatus) bool {
+	switch target.State {
+	case controllerv1alpha1.BulkOperationWorkspaceStopping,
+		controllerv1alpha1.BulkOperationWorkspaceStarting,
+		controllerv1alpha1.BulkOperationWorkspaceDeleting:
+		return true
+	}
+	return false
+}
+
+func getWorkspaceStateVerb(state controllerv1alpha1.BulkOperationWorkspaceState) string {
+	switch state {
+	case controllerv1alpha1.BulkOperationWorkspaceStopping:
+		return "stopping"
+	case controllerv1alpha1.BulkOperationWorkspaceStarting:
+		return "starting"
+	default:
+		return "deleting"
+	}
+}
+
+func isBulkOperationFinished(operation *controllerv1alpha1.DevWorkspaceBulkOperation) bool {
+	return operation.Status.Phase == controllerv1alpha1.BulkOperationPhaseCompleted ||
+		operation.Status.Phase == controllerv1alpha1.BulkOperationPhaseFailed
+}
+
+func getBulkOperationTimeout(operation *controllerv1alpha1.DevWorkspaceBulkOperation) time.Duration {
+	if operation.Spec.TimeoutSeconds != nil {
+		return time.Duration(*operation.Spec.TimeoutSeconds) * time.Second
+	}
+	return defaultBulkOperationTimeout
+}
+
+func getBulkOperationMaxConcurrent(operation *controllerv1alpha1.DevWorkspaceBulkOperation) int32 {
+	if operation.Spec.MaxConcurrent != nil && *operation.Spec.MaxConcurrent > 0 {
+		return *operation.Spec.MaxConcurrent
+	}
+	return defaultBulkOperationMaxConcurrent
+}
+
+func (r *DevWorkspaceBulkOperationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	// Status updates do not trigger reconciles; running operations are requeued periodically to check the state of
+	// DevWorkspaces instead of being reconciled on every DevWorkspace change.
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&controllerv1alpha1.DevWorkspaceBulkOperation{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,307 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacebulkoperation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	testNamespace     = "test-namespace"
+	operatorNamespace = "devworkspace-controller"
+)
+
+func getTestWorkspace(name, namespace string, phase dw.DevWorkspacePhase, labels map[string]string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, phase)
+	workspace.Namespace = namespace
+	for key, value := range labels {
+		workspace.Labels[key] = value
+	}
+	workspace.Spec.Started = phase != dw.DevWorkspaceStatusStopped
+	return workspace
+}
+
+func getTestOperation(namespace string, action controllerv1alpha1.DevWorkspaceBulkAction) *controllerv1alpha1.DevWorkspaceBulkOperation {
+	return &controllerv1alpha1.DevWorkspaceBulkOperation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-operation",
+			Namespace: namespace,
+		},
+		Spec: controllerv1alpha1.DevWorkspaceBulkOperationSpec{
+			Action: action,
+		},
+	}
+}
+
+func getTestReconciler(t *testing.T, objs ...client.Object) *DevWorkspaceBulkOperationReconciler {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, operatorNamespace)
+	config.SetGlobalConfigForTesting(nil)
+	return &DevWorkspaceBulkOperationReconciler{
+		Client: testutil.NewFakeClient(objs...),
+		Log:    zap.New(),
+		Scheme: testutil.Scheme,
+	}
+}
+
+func reconcileOperation(t *testing.T, r *DevWorkspaceBulkOperationReconciler, namespace string) *controllerv1alpha1.DevWorkspaceBulkOperation {
+	operationNN := types.NamespacedName{Name: "test-operation", Namespace: namespace}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: operationNN})
+	if !assert.NoError(t, err, "Should not return error on reconcile") {
+		t.FailNow()
+	}
+	operation := &controllerv1alpha1.DevWorkspaceBulkOperation{}
+	if !assert.NoError(t, r.Get(context.Background(), operationNN, operation)) {
+		t.FailNow()
+	}
+	return operation
+}
+
+func getWorkspace(t *testing.T, r *DevWorkspaceBulkOperationReconciler, name, namespace string) *dw.DevWorkspace {
+	workspace := &dw.DevWorkspace{}
+	if !assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: namespace}, workspace)) {
+		t.FailNow()
+	}
+	return workspace
+}
+
+// setWorkspacePhase simulates the DevWorkspace controller updating a DevWorkspace's phase
+func setWorkspacePhase(t *testing.T, r *DevWorkspaceBulkOperationReconciler, name, namespace string, phase dw.DevWorkspacePhase) {
+	workspace := getWorkspace(t, r, name, namespace)
+	workspace.Status.Phase = phase
+	if !assert.NoError(t, r.Update(context.Background(), workspace)) {
+		t.FailNow()
+	}
+}
+
+func getWorkspaceStates(operation *controllerv1alpha1.DevWorkspaceBulkOperation) map[string]controllerv1alpha1.BulkOperationWorkspaceState {
+	states := map[string]controllerv1alpha1.BulkOperationWorkspaceState{}
+	for _, target := range operation.Status.Workspaces {
+		states[target.Name] = target.State
+	}
+	return states
+}
+
+func TestStopRespectsMaxConcurrent(t *testing.T) {
+	operation := getTestOperation(testNamespace, controllerv1alpha1.BulkActionStop)
+	operation.Spec.MaxConcurrent = pointer.Int32(2)
+	r := getTestReconciler(t, operation,
+		getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusRunning, nil),
+		getTestWorkspace("ws-b", testNamespace, dw.DevWorkspaceStatusRunning, nil),
+		getTestWorkspace("ws-c", testNamespace, dw.DevWorkspaceStatusRunning, nil))
+
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseRunning, operation.Status.Phase)
+	assert.Equal(t, "0/3", operation.Status.Progress)
+	assert.Equal(t, map[string]controllerv1alpha1.BulkOperationWorkspaceState{
+		"ws-a": controllerv1alpha1.BulkOperationWorkspacePending,
+		"ws-b": controllerv1alpha1.BulkOperationWorkspacePending,
+		"ws-c": controllerv1alpha1.BulkOperationWorkspacePending,
+	}, getWorkspaceStates(operation))
+
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, map[string]controllerv1alpha1.BulkOperationWorkspaceState{
+		"ws-a": controllerv1alpha1.BulkOperationWorkspaceStopping,
+		"ws-b": controllerv1alpha1.BulkOperationWorkspaceStopping,
+		"ws-c": controllerv1alpha1.BulkOperationWorkspacePending,
+	}, getWorkspaceStates(operation), "Should only stop maxConcurrent DevWorkspaces at a time")
+	stopping := getWorkspace(t, r, "ws-a", testNamespace)
+	assert.False(t, stopping.Spec.Started, "Should stop DevWorkspace")
+	assert.Equal(t, constants.DevWorkspaceStoppedByBulkOperation, stopping.Annotations[constants.DevWorkspaceStopReasonAnnotation])
+	assert.True(t, getWorkspace(t, r, "ws-c", testNamespace).Spec.Started, "Should not stop DevWorkspace before others are stopped")
+
+	setWorkspacePhase(t, r, "ws-a", testNamespace, dw.DevWorkspaceStatusStopped)
+	setWorkspacePhase(t, r, "ws-b", testNamespace, dw.DevWorkspaceStatusStopped)
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, map[string]controllerv1alpha1.BulkOperationWorkspaceState{
+		"ws-a": controllerv1alpha1.BulkOperationWorkspaceSucceeded,
+		"ws-b": controllerv1alpha1.BulkOperationWorkspaceSucceeded,
+		"ws-c": controllerv1alpha1.BulkOperationWorkspaceStopping,
+	}, getWorkspaceStates(operation))
+	assert.Equal(t, "2/3", operation.Status.Progress)
+
+	setWorkspacePhase(t, r, "ws-c", testNamespace, dw.DevWorkspaceStatusStopped)
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, "3/3", operation.Status.Progress)
+	assert.Equal(t, int32(3), operation.Status.Succeeded)
+	assert.Equal(t, "Stop completed for 3 DevWorkspaces: 3 succeeded, 0 failed, 0 skipped", operation.Status.Message)
+	assert.NotNil(t, operation.Status.CompletionTime)
+}
+
+func TestStopSkipsStoppedWorkspaces(t *testing.T) {
+	r := getTestReconciler(t, getTestOperation(testNamespace, controllerv1alpha1.BulkActionStop),
+		getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusStopped, nil))
+	reconcileOperation(t, r, testNamespace)
+	operation := reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, int32(1), operation.Status.Skipped)
+}
+
+func TestRestartStopsAndStartsWorkspaces(t *testing.T) {
+	r := getTestReconciler(t, getTestOperation(testNamespace, controllerv1alpha1.BulkActionRestart),
+		getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusRunning, nil),
+		getTestWorkspace("ws-b", testNamespace, dw.DevWorkspaceStatusStopped, nil))
+
+	reconcileOperation(t, r, testNamespace)
+	operation := reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, map[string]controllerv1alpha1.BulkOperationWorkspaceState{
+		"ws-a": controllerv1alpha1.BulkOperationWorkspaceStopping,
+		"ws-b": controllerv1alpha1.BulkOperationWorkspaceSkipped,
+	}, getWorkspaceStates(operation), "Should only restart started DevWorkspaces")
+	assert.False(t, getWorkspace(t, r, "ws-a", testNamespace).Spec.Started)
+
+	setWorkspacePhase(t, r, "ws-a", testNamespace, dw.DevWorkspaceStatusStopped)
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationWorkspaceStarting, getWorkspaceStates(operation)["ws-a"])
+	assert.True(t, getWorkspace(t, r, "ws-a", testNamespace).Spec.Started, "Should start DevWorkspace once it is stopped")
+
+	setWorkspacePhase(t, r, "ws-a", testNamespace, dw.DevWorkspaceStatusRunning)
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, controllerv1alpha1.BulkOperationWorkspaceSucceeded, getWorkspaceStates(operation)["ws-a"])
+}
+
+func TestStartStartsStoppedWorkspaces(t *testing.T) {
+	r := getTestReconciler(t, getTestOperation(testNamespace, controllerv1alpha1.BulkActionStart),
+		getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusStopped, nil),
+		getTestWorkspace("ws-b", testNamespace, dw.DevWorkspaceStatusRunning, nil))
+
+	reconcileOperation(t, r, testNamespace)
+	operation := reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, map[string]controllerv1alpha1.BulkOperationWorkspaceState{
+		"ws-a": controllerv1alpha1.BulkOperationWorkspaceStarting,
+		"ws-b": controllerv1alpha1.BulkOperationWorkspaceSkipped,
+	}, getWorkspaceStates(operation), "Should only start DevWorkspaces that are not running")
+	assert.True(t, getWorkspace(t, r, "ws-a", testNamespace).Spec.Started, "Should start DevWorkspace")
+
+	setWorkspacePhase(t, r, "ws-a", testNamespace, dw.DevWorkspaceStatusRunning)
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, controllerv1alpha1.BulkOperationWorkspaceSucceeded, getWorkspaceStates(operation)["ws-a"])
+	assert.Equal(t, "DevWorkspace started", operation.Status.Workspaces[0].Message)
+}
+
+func TestRestartFailsIfWorkspaceFailsToStart(t *testing.T) {
+	r := getTestReconciler(t, getTestOperation(testNamespace, controllerv1alpha1.BulkActionRestart),
+		getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusRunning, nil))
+	reconcileOperation(t, r, testNamespace)
+	reconcileOperation(t, r, testNamespace)
+	setWorkspacePhase(t, r, "ws-a", testNamespace, dw.DevWorkspaceStatusStopped)
+	reconcileOperation(t, r, testNamespace)
+
+	workspace := getWorkspace(t, r, "ws-a", testNamespace)
+	workspace.Status.Phase = dw.DevWorkspaceStatusFailed
+	workspace.Status.Message = "image pull failed"
+	assert.NoError(t, r.Update(context.Background(), workspace))
+	operation := reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, int32(1), operation.Status.Failed)
+	assert.Equal(t, "DevWorkspace failed to start: image pull failed", operation.Status.Workspaces[0].Message)
+}
+
+func TestDeleteWorkspacesMatchingSelector(t *testing.T) {
+	operation := getTestOperation(testNamespace, controllerv1alpha1.BulkActionDelete)
+	operation.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	r := getTestReconciler(t, operation,
+		getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusRunning, map[string]string{"team": "a"}),
+		getTestWorkspace("ws-b", testNamespace, dw.DevWorkspaceStatusRunning, map[string]string{"team": "b"}))
+
+	operation = reconcileOperation(t, r, testNamespace)
+	if !assert.Len(t, operation.Status.Workspaces, 1) {
+		return
+	}
+	assert.Equal(t, "ws-a", operation.Status.Workspaces[0].Name)
+
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationWorkspaceDeleting, operation.Status.Workspaces[0].State)
+	err := r.Get(context.Background(), types.NamespacedName{Name: "ws-a", Namespace: testNamespace}, &dw.DevWorkspace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete selected DevWorkspace")
+	getWorkspace(t, r, "ws-b", testNamespace)
+
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, int32(1), operation.Status.Succeeded)
+}
+
+func TestNamespaceSelectorInOperatorNamespace(t *testing.T) {
+	operation := getTestOperation(operatorNamespace, controllerv1alpha1.BulkActionStop)
+	operation.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"maintenance": "true"}}
+	r := getTestReconciler(t, operation,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "user-a", Labels: map[string]string{"maintenance": "true"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "user-b"}},
+		getTestWorkspace("ws-a", "user-a", dw.DevWorkspaceStatusRunning, nil),
+		getTestWorkspace("ws-b", "user-b", dw.DevWorkspaceStatusRunning, nil))
+
+	operation = reconcileOperation(t, r, operatorNamespace)
+	if !assert.Len(t, operation.Status.Workspaces, 1) {
+		return
+	}
+	assert.Equal(t, "ws-a", operation.Status.Workspaces[0].Name)
+	assert.Equal(t, "user-a", operation.Status.Workspaces[0].Namespace)
+}
+
+func TestNamespaceSelectorOutsideOperatorNamespaceFails(t *testing.T) {
+	operation := getTestOperation(testNamespace, controllerv1alpha1.BulkActionStop)
+	operation.Spec.NamespaceSelector = &metav1.LabelSelector{}
+	r := getTestReconciler(t, operation, getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusRunning, nil))
+
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseFailed, operation.Status.Phase)
+	assert.Equal(t, "namespaceSelector may only be used for DevWorkspaceBulkOperations in namespace devworkspace-controller", operation.Status.Message)
+	assert.True(t, getWorkspace(t, r, "ws-a", testNamespace).Spec.Started, "Should not stop DevWorkspaces")
+}
+
+func TestWorkspaceFailsAfterTimeout(t *testing.T) {
+	operation := getTestOperation(testNamespace, controllerv1alpha1.BulkActionStop)
+	operation.Status = controllerv1alpha1.DevWorkspaceBulkOperationStatus{
+		Phase: controllerv1alpha1.BulkOperationPhaseRunning,
+		Workspaces: []controllerv1alpha1.BulkOperationWorkspaceStatus{
+			{
+				Name:      "ws-a",
+				Namespace: testNamespace,
+				State:     controllerv1alpha1.BulkOperationWorkspaceStopping,
+				StartTime: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+			},
+		},
+	}
+	workspace := getTestWorkspace("ws-a", testNamespace, dw.DevWorkspaceStatusStopping, nil)
+	workspace.Spec.Started = false
+	r := getTestReconciler(t, operation, workspace)
+
+	operation = reconcileOperation(t, r, testNamespace)
+	assert.Equal(t, controllerv1alpha1.BulkOperationPhaseCompleted, operation.Status.Phase)
+	assert.Equal(t, controllerv1alpha1.BulkOperationWorkspaceFailed, operation.Status.Workspaces[0].State)
+	assert.Equal(t, "DevWorkspace did not finish stopping within 5m0s", operation.Status.Workspaces[0].Message)
+}
@@ -0,0 +1,296 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceguestsession
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// DevWorkspaceGuestSessionReconciler reconciles a DevWorkspaceGuestSession object
+type DevWorkspaceGuestSessionReconciler struct {
+	client.Client
+	// NonCachingClient is used to read ResourceQuotas, which are not otherwise watched by the controller
+	NonCachingClient client.Client
+	Log              logr.Logger
+	Scheme           *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspaceguestsessions,verbs=*
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspaceguestsessions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=create;delete
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;create;update
+
+func (r *DevWorkspaceGuestSessionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	session := &controllerv1alpha1.DevWorkspaceGuestSession{}
+	if err := r.Get(ctx, req.NamespacedName, session); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if session.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.finalize(ctx, session)
+	}
+
+	guestConfig := config.GetGlobalConfig().Workspace.GuestWorkspaces
+	if guestConfig == nil || guestConfig.Enable == nil || !*guestConfig.Enable {
+		return reconcile.Result{}, r.updateStatus(ctx, session, controllerv1alpha1.GuestSessionPhaseFailed,
+			"Guest workspaces are not enabled in the DevWorkspaceOperatorConfig")
+	}
+	ttl, err := time.ParseDuration(guestConfig.TTL)
+	if err != nil {
+		return reconcile.Result{}, r.updateStatus(ctx, session, controllerv1alpha1.GuestSessionPhaseFailed,
+			fmt.Sprintf("Invalid guest session TTL %q configured: %s", guestConfig.TTL, err))
+	}
+
+	// The TTL is a hard limit, counted from the creation of the session rather than the start of its DevWorkspace
+	expirationTime := session.CreationTimestamp.Add(ttl)
+	if !time.Now().Before(expirationTime) {
+		reqLogger.Info("Guest session expired; deleting")
+		if err := r.Delete(ctx, session); err != nil && !k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+	if session.Status.Phase == controllerv1alpha1.GuestSessionPhaseFailed {
+		return reconcile.Result{RequeueAfter: time.Until(expirationTime)}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(session, constants.GuestSessionCleanupFinalizer) {
+		controllerutil.AddFinalizer(session, constants.GuestSessionCleanupFinalizer)
+		if err := r.Update(ctx, session); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if session.Status.Namespace == "" {
+		session.Status.Namespace = common.GuestSessionNamespaceName(guestConfig.NamespacePrefix, string(session.UID))
+		session.Status.DevWorkspaceName = session.Name
+		session.Status.ExpirationTime = &metav1.Time{Time: expirationTime}
+		if err := r.updateStatus(ctx, session, controllerv1alpha1.GuestSessionPhasePending, "Creating guest namespace"); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if err := r.syncNamespace(ctx, session); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.syncResourceQuota(ctx, session, guestConfig.Quota); err != nil {
+		return reconcile.Result{}, err
+	}
+	workspace, err := r.syncDevWorkspace(ctx, session)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	phase, message := controllerv1alpha1.GuestSessionPhaseStarting, "Waiting for DevWorkspace to start"
+	switch workspace.Status.Phase {
+	case dw.DevWorkspaceStatusRunning:
+		phase, message = controllerv1alpha1.GuestSessionPhaseRunning, ""
+		session.Status.MainUrl = workspace.Status.MainUrl
+	case dw.DevWorkspaceStatusFailed:
+		phase, message = controllerv1alpha1.GuestSessionPhaseFailed, fmt.Sprintf("DevWorkspace failed: %s", workspace.Status.Message)
+	}
+	if err := r.updateStatus(ctx, session, phase, message); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: time.Until(expirationTime)}, nil
+}
+
+func (r *DevWorkspaceGuestSessionReconciler) syncNamespace(ctx context.Context, session *controllerv1alpha1.DevWorkspaceGuestSession) error {
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: session.Status.Namespace}, namespace)
+	if err == nil {
+		if namespace.Labels[constants.DevWorkspaceGuestSessionLabel] != session.Name ||
+			namespace.Labels[constants.DevWorkspaceGuestSessionNamespaceLabel] != session.Namespace {
+			return fmt.Errorf("namespace %s already exists and does not belong to this guest session", namespace.Name)
+		}
+		return nil
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	namespace = &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   session.Status.Namespace,
+			Labels: getGuestSessionLabels(session),
+		},
+	}
+	err = r.Create(ctx, namespace)
+	if k8sErrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *DevWorkspaceGuestSessionReconciler) syncResourceQuota(ctx context.Context, session *controllerv1alpha1.DevWorkspaceGuestSession, hard corev1.ResourceList) error {
+	quota := &corev1.ResourceQuota{}
+	err := r.NonCachingClient.Get(ctx, types.NamespacedName{Name: constants.GuestSessionQuotaName, Namespace: session.Status.Namespace}, quota)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		quota = &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.GuestSessionQuotaName,
+				Namespace: session.Status.Namespace,
+				Labels:    getGuestSessionLabels(session),
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard.DeepCopy(),
+			},
+		}
+		return r.NonCachingClient.Create(ctx, quota)
+	case err != nil:
+		return err
+	}
+	if equalResourceLists(quota.Spec.Hard, hard) {
+		return nil
+	}
+	quota.Spec.Hard = hard.DeepCopy()
+	return r.NonCachingClient.Update(ctx, quota)
+}
+
+func (r *DevWorkspaceGuestSessionReconciler) syncDevWorkspace(ctx context.Context, session *controllerv1alpha1.DevWorkspaceGuestSession) (*dw.DevWorkspace, error) {
+	workspace := &dw.DevWorkspace{}
+	workspaceNN := types.NamespacedName{Name: session.Status.DevWorkspaceName, Namespace: session.Status.Namespace}
+	err := r.Get(ctx, workspaceNN, workspace)
+	if err == nil || !k8sErrors.IsNotFound(err) {
+		return workspace, err
+	}
+
+	workspace = &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      session.Status.DevWorkspaceName,
+			Namespace: session.Status.Namespace,
+			Labels:    getGuestSessionLabels(session),
+		},
+		Spec: dw.DevWorkspaceSpec{
+			Started:  true,
+			Template: *session.Spec.Template.DeepCopy(),
+		},
+	}
+	// Guest sessions never use persistent storage; data is lost when the session ends.
+	if workspace.Spec.Template.Attributes == nil {
+		workspace.Spec.Template.Attributes = attributes.Attributes{}
+	}
+	workspace.Spec.Template.Attributes.PutString(constants.DevWorkspaceStorageTypeAttribute, constants.EphemeralStorageClassType)
+	if err := r.Create(ctx, workspace); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+func (r *DevWorkspaceGuestSessionReconciler) finalize(ctx context.Context, session *controllerv1alpha1.DevWorkspaceGuestSession) error {
+	if !controllerutil.ContainsFinalizer(session, constants.GuestSessionCleanupFinalizer) {
+		return nil
+	}
+	if session.Status.Namespace != "" {
+		namespace := &corev1.Namespace{}
+		err := r.Get(ctx, types.NamespacedName{Name: session.Status.Namespace}, namespace)
+		switch {
+		case err == nil:
+			if namespace.Labels[constants.DevWorkspaceGuestSessionLabel] == session.Name && namespace.DeletionTimestamp == nil {
+				r.Log.Info("Deleting guest session namespace", "namespace", namespace.Name)
+				if err := r.Delete(ctx, namespace, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !k8sErrors.IsNotFound(err) {
+					return err
+				}
+			}
+		case !k8sErrors.IsNotFound(err):
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(session, constants.GuestSessionCleanupFinalizer)
+	return r.Update(ctx, session)
+}
+
+func (r *DevWorkspaceGuestSessionReconciler) updateStatus(ctx context.Context, session *controllerv1alpha1.DevWorkspaceGuestSession, phase controllerv1alpha1.DevWorkspaceGuestSessionPhase, message string) error {
+	if session.Status.Phase == phase && session.Status.Message == message && phase != controllerv1alpha1.GuestSessionPhaseRunning {
+		return nil
+	}
+	session.Status.Phase = phase
+	session.Status.Message = message
+	return r.Status().Update(ctx, session)
+}
+
+func getGuestSessionLabels(session *controllerv1alpha1.DevWorkspaceGuestSession) map[string]string {
+	return map[string]string{
+		constants.DevWorkspaceGuestSessionLabel:          session.Name,
+		constants.DevWorkspaceGuestSessionNamespaceLabel: session.Namespace,
+	}
+}
+
+func equalResourceLists(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sessionForWorkspace enqueues a reconcile for the guest session that created a DevWorkspace, so that the session's
+// status follows the DevWorkspace's phase.
+func (r *DevWorkspaceGuestSessionReconciler) sessionForWorkspace(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	sessionName, sessionNamespace := labels[constants.DevWorkspaceGuestSessionLabel], labels[constants.DevWorkspaceGuestSessionNamespaceLabel]
+	if sessionName == "" || sessionNamespace == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: sessionName, Namespace: sessionNamespace}},
+	}
+}
+
+func (r *DevWorkspaceGuestSessionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&controllerv1alpha1.DevWorkspaceGuestSession{}).
+		Watches(&source.Kind{Type: &dw.DevWorkspace{}}, handler.EnqueueRequestsFromMapFunc(r.sessionForWorkspace)).
+		Complete(r)
+}
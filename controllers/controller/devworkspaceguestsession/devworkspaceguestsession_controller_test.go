@@ -0,0 +1,198 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceguestsession
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	testNamespace      = "test-namespace"
+	testGuestNamespace = "dw-guest-session-uid"
+)
+
+func getTestSession(age time.Duration) *controllerv1alpha1.DevWorkspaceGuestSession {
+	return &controllerv1alpha1.DevWorkspaceGuestSession{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-session",
+			Namespace:         testNamespace,
+			UID:               "session-uid",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec: controllerv1alpha1.DevWorkspaceGuestSessionSpec{
+			Template: dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Components: []dw.Component{
+						{
+							Name: "tools",
+							ComponentUnion: dw.ComponentUnion{
+								Container: &dw.ContainerComponent{
+									Container: dw.Container{Image: "quay.io/devfile/universal-developer-image:latest"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTestReconciler(enabled bool, objs ...client.Object) *DevWorkspaceGuestSessionReconciler {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	config.SetGlobalConfigForTesting(&controllerv1alpha1.OperatorConfiguration{
+		Workspace: &controllerv1alpha1.WorkspaceConfig{
+			GuestWorkspaces: &controllerv1alpha1.GuestWorkspacesConfig{
+				Enable: pointer.Bool(enabled),
+			},
+		},
+	})
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &DevWorkspaceGuestSessionReconciler{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Log:              zap.New(),
+		Scheme:           testutil.Scheme,
+	}
+}
+
+func reconcileSession(t *testing.T, r *DevWorkspaceGuestSessionReconciler) (ctrl.Result, *controllerv1alpha1.DevWorkspaceGuestSession) {
+	sessionNN := types.NamespacedName{Name: "test-session", Namespace: testNamespace}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: sessionNN})
+	if !assert.NoError(t, err, "Reconcile should not return error") {
+		t.FailNow()
+	}
+	session := &controllerv1alpha1.DevWorkspaceGuestSession{}
+	err = r.Get(context.Background(), sessionNN, session)
+	if k8sErrors.IsNotFound(err) {
+		return result, nil
+	}
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return result, session
+}
+
+func TestGuestSessionFailsWhenDisabled(t *testing.T) {
+	r := getTestReconciler(false, getTestSession(time.Minute))
+	_, session := reconcileSession(t, r)
+	assert.Equal(t, controllerv1alpha1.GuestSessionPhaseFailed, session.Status.Phase)
+	assert.Empty(t, session.Status.Namespace, "Should not create namespace when guest workspaces are disabled")
+}
+
+func TestGuestSessionProvisionsNamespace(t *testing.T) {
+	r := getTestReconciler(true, getTestSession(time.Minute))
+	result, session := reconcileSession(t, r)
+
+	assert.Equal(t, controllerv1alpha1.GuestSessionPhaseStarting, session.Status.Phase)
+	assert.Equal(t, testGuestNamespace, session.Status.Namespace)
+	assert.Contains(t, session.Finalizers, constants.GuestSessionCleanupFinalizer)
+	if assert.NotNil(t, session.Status.ExpirationTime) {
+		assert.Equal(t, session.CreationTimestamp.Add(time.Hour).Unix(), session.Status.ExpirationTime.Unix(), "Should use default TTL")
+	}
+	assert.True(t, result.RequeueAfter > 58*time.Minute && result.RequeueAfter <= 59*time.Minute, "Should requeue when session expires")
+
+	namespace := &corev1.Namespace{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: testGuestNamespace}, namespace)) {
+		assert.Equal(t, "test-session", namespace.Labels[constants.DevWorkspaceGuestSessionLabel])
+		assert.Equal(t, testNamespace, namespace.Labels[constants.DevWorkspaceGuestSessionNamespaceLabel])
+	}
+
+	quota := &corev1.ResourceQuota{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: constants.GuestSessionQuotaName, Namespace: testGuestNamespace}, quota)) {
+		pvcQuota := quota.Spec.Hard[corev1.ResourcePersistentVolumeClaims]
+		assert.True(t, pvcQuota.Equal(resource.MustParse("0")), "Should not allow guest sessions to create PVCs")
+	}
+
+	workspace := &dw.DevWorkspace{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-session", Namespace: testGuestNamespace}, workspace)) {
+		assert.True(t, workspace.Spec.Started)
+		assert.Equal(t, constants.EphemeralStorageClassType,
+			workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil), "Should use ephemeral storage")
+		assert.Len(t, workspace.Spec.Template.Components, 1)
+	}
+}
+
+func TestGuestSessionReportsRunningWorkspace(t *testing.T) {
+	r := getTestReconciler(true, getTestSession(time.Minute))
+	reconcileSession(t, r)
+
+	workspace := &dw.DevWorkspace{}
+	workspaceNN := types.NamespacedName{Name: "test-session", Namespace: testGuestNamespace}
+	if !assert.NoError(t, r.Get(context.Background(), workspaceNN, workspace)) {
+		return
+	}
+	workspace.Status.Phase = dw.DevWorkspaceStatusRunning
+	workspace.Status.MainUrl = "https://workspace.example.com"
+	if !assert.NoError(t, r.Status().Update(context.Background(), workspace)) {
+		return
+	}
+
+	_, session := reconcileSession(t, r)
+	assert.Equal(t, controllerv1alpha1.GuestSessionPhaseRunning, session.Status.Phase)
+	assert.Equal(t, "https://workspace.example.com", session.Status.MainUrl)
+}
+
+func TestGuestSessionTornDownOnExpiry(t *testing.T) {
+	r := getTestReconciler(true, getTestSession(time.Minute))
+	_, session := reconcileSession(t, r)
+
+	// Simulate the TTL passing by moving the creation timestamp back
+	session.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	if !assert.NoError(t, r.Update(context.Background(), session)) {
+		return
+	}
+	_, session = reconcileSession(t, r)
+	if assert.NotNil(t, session, "Session should be blocked from deletion by finalizer") {
+		assert.NotNil(t, session.DeletionTimestamp)
+	}
+
+	_, session = reconcileSession(t, r)
+	assert.Nil(t, session, "Session should be deleted once finalized")
+	err := r.Get(context.Background(), types.NamespacedName{Name: testGuestNamespace}, &corev1.Namespace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Guest namespace should be deleted")
+}
+
+func TestGuestSessionDoesNotAdoptExistingNamespace(t *testing.T) {
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testGuestNamespace}}
+	r := getTestReconciler(true, getTestSession(time.Minute), existing)
+	sessionNN := types.NamespacedName{Name: "test-session", Namespace: testNamespace}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: sessionNN})
+	assert.Error(t, err, "Should not use namespace that does not belong to session")
+	workspaces := &dw.DevWorkspaceList{}
+	assert.NoError(t, r.List(context.Background(), workspaces, client.InNamespace(testGuestNamespace)))
+	assert.Empty(t, workspaces.Items)
+}
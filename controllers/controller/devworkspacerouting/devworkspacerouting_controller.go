@@ -23,10 +23,13 @@ import (
 
 	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacerouting/solvers"
 	maputils "github.com/devfile/devworkspace-operator/internal/map"
+	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/pods"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	"github.com/devfile/devworkspace-operator/pkg/shard"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
@@ -36,10 +39,13 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 )
@@ -72,6 +78,13 @@ type DevWorkspaceRoutingReconciler struct {
 func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
 
+	// When running as multiple shards, only reconcile DevWorkspaceRoutings in namespaces assigned to this shard
+	if ownsNamespace, err := shard.OwnsNamespace(ctx, r.Client, req.Namespace); err != nil {
+		return reconcile.Result{}, err
+	} else if !ownsNamespace {
+		return reconcile.Result{}, nil
+	}
+
 	// Fetch the DevWorkspaceRouting instance
 	instance := &controllerv1alpha1.DevWorkspaceRouting{}
 	err := r.Get(ctx, req.NamespacedName, instance)
@@ -128,6 +141,7 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 		DevWorkspaceId: instance.Spec.DevWorkspaceId,
 		Namespace:      instance.Namespace,
 		PodSelector:    instance.Spec.PodSelector,
+		ServiceName:    common.ServiceName(instance.Spec.DevWorkspaceId, instance.Annotations),
 	}
 
 	restrictedAccess, setRestrictedAccess := instance.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]
@@ -140,7 +154,7 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 				duration = 1 * time.Second
 			}
 			reqLogger.Info("controller not ready for devworkspace routing. Retrying", "DelayMs", duration.Milliseconds())
-			return reconcile.Result{RequeueAfter: duration}, r.reconcileStatus(instance, nil, nil, false, "Waiting for DevWorkspaceRouting controller to be ready")
+			return reconcile.Result{RequeueAfter: duration}, r.reconcileStatus(instance, nil, nil, nil, false, "Waiting for DevWorkspaceRouting controller to be ready")
 		}
 
 		var invalid *solvers.RoutingInvalid
@@ -191,10 +205,10 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 			return reconcile.Result{}, r.markRoutingFailed(instance, err.Error())
 		}
 		reqLogger.Error(err, "Error syncing services")
-		return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, false, "Preparing services")
+		return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, nil, false, "Preparing services")
 	} else if !servicesInSync {
 		reqLogger.Info("Services not in sync")
-		return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, false, "Preparing services")
+		return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, nil, false, "Preparing services")
 	}
 
 	clusterRoutingObj := solvers.RoutingObjects{
@@ -209,10 +223,10 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 				return reconcile.Result{}, r.markRoutingFailed(instance, err.Error())
 			}
 			reqLogger.Error(err, "Error syncing routes")
-			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, false, "Preparing routes")
+			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, nil, false, "Preparing routes")
 		} else if !routesInSync {
 			reqLogger.Info("Routes not in sync")
-			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, false, "Preparing routes")
+			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, nil, false, "Preparing routes")
 		}
 		clusterRoutingObj.Routes = clusterRoutes
 	} else {
@@ -223,10 +237,10 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 				return reconcile.Result{}, r.markRoutingFailed(instance, err.Error())
 			}
 			reqLogger.Error(err, "Error syncing ingresses")
-			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, false, "Preparing ingresses")
+			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, nil, false, "Preparing ingresses")
 		} else if !ingressesInSync {
 			reqLogger.Info("Ingresses not in sync")
-			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, false, "Preparing ingresses")
+			return reconcile.Result{Requeue: true}, r.reconcileStatus(instance, nil, nil, nil, false, "Preparing ingresses")
 		}
 		clusterRoutingObj.Ingresses = clusterIngresses
 	}
@@ -237,7 +251,13 @@ func (r *DevWorkspaceRoutingReconciler) Reconcile(ctx context.Context, req ctrl.
 		return reconcile.Result{}, r.markRoutingFailed(instance, fmt.Sprintf("Could not get exposed endpoints for DevWorkspace: %s", err))
 	}
 
-	return reconcile.Result{}, r.reconcileStatus(instance, &routingObjects, exposedEndpoints, endpointsAreReady, "")
+	workspacePod, err := pods.GetNewestWorkspacePod(ctx, r.Client, instance.Namespace, instance.Spec.DevWorkspaceId)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	endpointStatuses := getEndpointStatuses(instance.Spec.Endpoints, exposedEndpoints, workspacePod)
+
+	return reconcile.Result{}, r.reconcileStatus(instance, &routingObjects, exposedEndpoints, endpointStatuses, endpointsAreReady, "")
 }
 
 // setFinalizer ensures a finalizer is set on a devWorkspaceRouting instance; no-op if finalizer is already present.
@@ -280,6 +300,7 @@ func (r *DevWorkspaceRoutingReconciler) finalize(solver solvers.RoutingSolver, i
 func (r *DevWorkspaceRoutingReconciler) markRoutingFailed(instance *controllerv1alpha1.DevWorkspaceRouting, message string) error {
 	instance.Status.Message = message
 	instance.Status.Phase = controllerv1alpha1.RoutingFailed
+	instance.Status.ObservedGeneration = instance.Generation
 	return r.Status().Update(context.TODO(), instance)
 }
 
@@ -287,23 +308,29 @@ func (r *DevWorkspaceRoutingReconciler) reconcileStatus(
 	instance *controllerv1alpha1.DevWorkspaceRouting,
 	routingObjects *solvers.RoutingObjects,
 	exposedEndpoints map[string]controllerv1alpha1.ExposedEndpointList,
+	endpointStatuses []controllerv1alpha1.EndpointStatus,
 	endpointsReady bool,
 	message string) error {
 
 	if !endpointsReady {
 		instance.Status.Phase = controllerv1alpha1.RoutingPreparing
 		instance.Status.Message = message
+		instance.Status.ObservedGeneration = instance.Generation
 		return r.Status().Update(context.TODO(), instance)
 	}
 	if instance.Status.Phase == controllerv1alpha1.RoutingReady &&
+		instance.Status.ObservedGeneration == instance.Generation &&
 		cmp.Equal(instance.Status.PodAdditions, routingObjects.PodAdditions) &&
-		cmp.Equal(instance.Status.ExposedEndpoints, exposedEndpoints) {
+		cmp.Equal(instance.Status.ExposedEndpoints, exposedEndpoints) &&
+		cmp.Equal(instance.Status.Endpoints, endpointStatuses) {
 		return nil
 	}
 	instance.Status.Phase = controllerv1alpha1.RoutingReady
 	instance.Status.Message = "DevWorkspaceRouting prepared"
 	instance.Status.PodAdditions = routingObjects.PodAdditions
 	instance.Status.ExposedEndpoints = exposedEndpoints
+	instance.Status.Endpoints = endpointStatuses
+	instance.Status.ObservedGeneration = instance.Generation
 	return r.Status().Update(context.TODO(), instance)
 }
 
@@ -312,6 +339,8 @@ func (r *DevWorkspaceRoutingReconciler) setStatusStopped(instance *controllerv1a
 	instance.Status.Message = "DevWorkspace is not started"
 	instance.Status.PodAdditions = nil
 	instance.Status.ExposedEndpoints = nil
+	instance.Status.Endpoints = nil
+	instance.Status.ObservedGeneration = instance.Generation
 	return r.Status().Update(context.TODO(), instance)
 }
 
@@ -343,7 +372,8 @@ func (r *DevWorkspaceRoutingReconciler) SetupWithManager(mgr ctrl.Manager) error
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		For(&controllerv1alpha1.DevWorkspaceRouting{}).
 		Owns(&corev1.Service{}).
-		Owns(&networkingv1.Ingress{})
+		Owns(&networkingv1.Ingress{}).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.workspacePodHandler), builder.WithPredicates(podReadinessPredicates))
 	if infrastructure.IsOpenShift() {
 		bld.Owns(&routeV1.Route{})
 	}
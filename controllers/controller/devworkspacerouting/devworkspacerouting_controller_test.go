@@ -56,7 +56,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 			Expect(createdDWR.Status.Message).Should(Equal("DevWorkspaceRouting prepared"), "Status message should indicate that the DevWorkspaceRouting is prepared")
 
 			deleteDevWorkspaceRouting(devWorkspaceRoutingName)
-			deleteService(common.ServiceName(testWorkspaceID), testNamespace)
+			deleteService(common.ServiceName(testWorkspaceID, nil), testNamespace)
 			deleteService(common.EndpointName(discoverableEndpointName), testNamespace)
 			deleteRoute(exposedEndPointName, testNamespace)
 			deleteRoute(discoverableEndpointName, testNamespace)
@@ -85,7 +85,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 			Expect(createdDWR.Status.Message).Should(Equal("DevWorkspaceRouting prepared"), "Status message should indicate that the DevWorkspaceRouting is prepared")
 
 			deleteDevWorkspaceRouting(devWorkspaceRoutingName)
-			deleteService(common.ServiceName(testWorkspaceID), testNamespace)
+			deleteService(common.ServiceName(testWorkspaceID, nil), testNamespace)
 			deleteService(common.EndpointName(discoverableEndpointName), testNamespace)
 			deleteIngress(exposedEndPointName, testNamespace)
 			deleteIngress(discoverableEndpointName, testNamespace)
@@ -99,7 +99,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 
 			AfterEach(func() {
 				deleteDevWorkspaceRouting(devWorkspaceRoutingName)
-				deleteService(common.ServiceName(testWorkspaceID), testNamespace)
+				deleteService(common.ServiceName(testWorkspaceID, nil), testNamespace)
 				deleteService(common.EndpointName(discoverableEndpointName), testNamespace)
 				deleteIngress(exposedEndPointName, testNamespace)
 				deleteIngress(discoverableEndpointName, testNamespace)
@@ -109,7 +109,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 
 				By("Checking single service is created for all exposed endpoints ")
 				createdService := &corev1.Service{}
-				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID), testNamespace)
+				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID, nil), testNamespace)
 				Eventually(func() bool {
 					err := k8sClient.Get(ctx, serviceNamespacedName, createdService)
 					return err == nil
@@ -178,7 +178,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 
 				By("Checking ingress points to service")
 				createdService := &corev1.Service{}
-				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID), testNamespace)
+				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID, nil), testNamespace)
 				Eventually(func() bool {
 					err := k8sClient.Get(ctx, serviceNamespacedName, createdService)
 					return err == nil
@@ -235,7 +235,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 
 			AfterEach(func() {
 				deleteDevWorkspaceRouting(devWorkspaceRoutingName)
-				deleteService(common.ServiceName(testWorkspaceID), testNamespace)
+				deleteService(common.ServiceName(testWorkspaceID, nil), testNamespace)
 				deleteService(common.EndpointName(discoverableEndpointName), testNamespace)
 				deleteRoute(exposedEndPointName, testNamespace)
 				deleteRoute(discoverableEndpointName, testNamespace)
@@ -245,7 +245,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 
 				By("Checking single service for all exposed endpoints is created")
 				createdService := &corev1.Service{}
-				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID), testNamespace)
+				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID, nil), testNamespace)
 				Eventually(func() bool {
 					err := k8sClient.Get(ctx, serviceNamespacedName, createdService)
 					return err == nil
@@ -312,7 +312,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 
 				By("Checking route points to service")
 				createdService := &corev1.Service{}
-				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID), testNamespace)
+				serviceNamespacedName := namespacedName(common.ServiceName(testWorkspaceID, nil), testNamespace)
 				Eventually(func() bool {
 					err := k8sClient.Get(ctx, serviceNamespacedName, createdService)
 					return err == nil
@@ -367,7 +367,7 @@ var _ = Describe("DevWorkspaceRouting Controller", func() {
 		AfterEach(func() {
 			config.SetGlobalConfigForTesting(testControllerCfg)
 			deleteDevWorkspaceRouting(devWorkspaceRoutingName)
-			deleteService(common.ServiceName(testWorkspaceID), testNamespace)
+			deleteService(common.ServiceName(testWorkspaceID, nil), testNamespace)
 			deleteService(common.EndpointName(discoverableEndpointName), testNamespace)
 			deleteIngress(exposedEndPointName, testNamespace)
 			deleteIngress(discoverableEndpointName, testNamespace)
@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacerouting
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// getEndpointStatuses returns the status of every endpoint of a DevWorkspaceRouting, sorted by machine and endpoint
+// name. URLs are read from the exposed endpoints returned by the solver, and an endpoint is ready when its machine's
+// container is ready in the DevWorkspace pod. The pod may be nil if it has not been created yet.
+func getEndpointStatuses(
+	endpoints map[string]controllerv1alpha1.EndpointList,
+	exposedEndpoints map[string]controllerv1alpha1.ExposedEndpointList,
+	pod *corev1.Pod) []controllerv1alpha1.EndpointStatus {
+
+	readyContainers := map[string]bool{}
+	if pod != nil {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			readyContainers[containerStatus.Name] = containerStatus.Ready
+		}
+	}
+
+	var statuses []controllerv1alpha1.EndpointStatus
+	for machineName, machineEndpoints := range endpoints {
+		urls := map[string]string{}
+		for _, exposedEndpoint := range exposedEndpoints[machineName] {
+			urls[exposedEndpoint.Name] = exposedEndpoint.Url
+		}
+		for _, endpoint := range machineEndpoints {
+			exposure := endpoint.Exposure
+			if exposure == "" {
+				exposure = controllerv1alpha1.PublicEndpointExposure
+			}
+			statuses = append(statuses, controllerv1alpha1.EndpointStatus{
+				Name:     endpoint.Name,
+				Machine:  machineName,
+				Url:      urls[endpoint.Name],
+				Exposure: exposure,
+				Ready:    readyContainers[machineName],
+			})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Machine != statuses[j].Machine {
+			return statuses[i].Machine < statuses[j].Machine
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// workspacePodHandler maps DevWorkspace pods to the DevWorkspaceRouting of their DevWorkspace, so that endpoint
+// readiness is kept up to date. The DevWorkspaceRouting is looked up by label, as its name depends on the naming
+// templates in effect when the DevWorkspace was created.
+func (r *DevWorkspaceRoutingReconciler) workspacePodHandler(obj client.Object) []reconcile.Request {
+	workspaceId, ok := obj.GetLabels()[constants.DevWorkspaceIDLabel]
+	if !ok {
+		return []reconcile.Request{}
+	}
+	routingList := &controllerv1alpha1.DevWorkspaceRoutingList{}
+	err := r.List(context.Background(), routingList,
+		client.InNamespace(obj.GetNamespace()), client.MatchingLabels{constants.DevWorkspaceIDLabel: workspaceId})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+	var requests []reconcile.Request
+	for _, routing := range routingList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      routing.Name,
+				Namespace: routing.Namespace,
+			},
+		})
+	}
+	return requests
+}
+
+// podReadinessPredicates filters pod updates to those that change whether any of the pod's containers are ready.
+var podReadinessPredicates = predicate.Funcs{
+	UpdateFunc: func(ev event.UpdateEvent) bool {
+		oldPod, oldOk := ev.ObjectOld.(*corev1.Pod)
+		newPod, newOk := ev.ObjectNew.(*corev1.Pod)
+		if !oldOk || !newOk {
+			return true
+		}
+		return !equalContainerReadiness(oldPod, newPod)
+	},
+}
+
+func equalContainerReadiness(a, b *corev1.Pod) bool {
+	if len(a.Status.ContainerStatuses) != len(b.Status.ContainerStatuses) {
+		return false
+	}
+	readiness := map[string]bool{}
+	for _, containerStatus := range a.Status.ContainerStatuses {
+		readiness[containerStatus.Name] = containerStatus.Ready
+	}
+	for _, containerStatus := range b.Status.ContainerStatuses {
+		if ready, ok := readiness[containerStatus.Name]; !ok || ready != containerStatus.Ready {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacerouting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+func TestGetEndpointStatuses(t *testing.T) {
+	endpoints := map[string]controllerv1alpha1.EndpointList{
+		"tools": {
+			{Name: "http", TargetPort: 8080},
+			{Name: "debug", TargetPort: 5005, Exposure: controllerv1alpha1.NoneEndpointExposure},
+		},
+		"db": {
+			{Name: "postgres", TargetPort: 5432, Exposure: controllerv1alpha1.InternalEndpointExposure},
+		},
+	}
+	exposedEndpoints := map[string]controllerv1alpha1.ExposedEndpointList{
+		"tools": {{Name: "http", Url: "https://workspace.example.com/http/"}},
+		"db":    {{Name: "postgres", Url: "tcp://workspace-service:5432"}},
+	}
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "tools", Ready: true},
+				{Name: "db", Ready: false},
+			},
+		},
+	}
+
+	expected := []controllerv1alpha1.EndpointStatus{
+		{Name: "postgres", Machine: "db", Url: "tcp://workspace-service:5432", Exposure: controllerv1alpha1.InternalEndpointExposure, Ready: false},
+		{Name: "debug", Machine: "tools", Exposure: controllerv1alpha1.NoneEndpointExposure, Ready: true},
+		{Name: "http", Machine: "tools", Url: "https://workspace.example.com/http/", Exposure: controllerv1alpha1.PublicEndpointExposure, Ready: true},
+	}
+	assert.Equal(t, expected, getEndpointStatuses(endpoints, exposedEndpoints, pod))
+}
+
+func TestGetEndpointStatusesWithoutPod(t *testing.T) {
+	endpoints := map[string]controllerv1alpha1.EndpointList{
+		"tools": {{Name: "http", TargetPort: 8080}},
+	}
+	statuses := getEndpointStatuses(endpoints, nil, nil)
+	if assert.Len(t, statuses, 1) {
+		assert.False(t, statuses[0].Ready, "Endpoints should not be ready before the workspace pod exists")
+		assert.Empty(t, statuses[0].Url)
+	}
+}
+
+func TestEqualContainerReadiness(t *testing.T) {
+	notReady := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "tools", Ready: false, RestartCount: 1}}}}
+	notReadyRestarted := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "tools", Ready: false, RestartCount: 2}}}}
+	ready := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "tools", Ready: true}}}}
+
+	assert.True(t, equalContainerReadiness(notReady, notReadyRestarted))
+	assert.False(t, equalContainerReadiness(notReady, ready))
+	assert.False(t, equalContainerReadiness(&corev1.Pod{}, ready))
+}
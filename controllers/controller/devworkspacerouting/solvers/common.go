@@ -32,6 +32,8 @@ type DevWorkspaceMetadata struct {
 	DevWorkspaceId string
 	Namespace      string
 	PodSelector    map[string]string
+	// ServiceName is the name of the service that exposes all endpoints of the DevWorkspace
+	ServiceName string
 }
 
 // GetDiscoverableServicesForEndpoints converts the endpoint list into a set of services, each corresponding to a single discoverable
@@ -125,7 +127,7 @@ func GetServiceForEndpoints(endpoints map[string]controllerv1alpha1.EndpointList
 
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      common.ServiceName(meta.DevWorkspaceId),
+			Name:      meta.ServiceName,
 			Namespace: meta.Namespace,
 			Labels: map[string]string{
 				constants.DevWorkspaceIDLabel: meta.DevWorkspaceId,
@@ -201,7 +203,7 @@ func getRouteForEndpoint(routingSuffix string, endpoint controllerv1alpha1.Endpo
 			},
 			To: routeV1.RouteTargetReference{
 				Kind: "Service",
-				Name: common.ServiceName(meta.DevWorkspaceId),
+				Name: meta.ServiceName,
 			},
 			Port: &routeV1.RoutePort{
 				TargetPort: targetEndpoint,
@@ -234,7 +236,7 @@ func getIngressForEndpoint(routingSuffix string, endpoint controllerv1alpha1.End
 								{
 									Backend: networkingv1.IngressBackend{
 										Service: &networkingv1.IngressServiceBackend{
-											Name: common.ServiceName(meta.DevWorkspaceId),
+											Name: meta.ServiceName,
 											Port: networkingv1.ServiceBackendPort{Number: int32(endpoint.TargetPort)},
 										},
 									},
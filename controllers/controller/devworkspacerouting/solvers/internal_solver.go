@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package solvers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// InternalSolver exposes endpoints only within the cluster: Services are created for all public and internal
+// endpoints, but no Ingresses or Routes. Endpoint URLs use the in-cluster DNS name of the corresponding service,
+// and exposed endpoints are marked with the EndpointExposureAttribute. This is intended for setups where DevWorkspaces
+// are accessed exclusively through e.g. a VPN, port-forwarding or a gateway that is not managed by the operator.
+type InternalSolver struct{}
+
+var _ RoutingSolver = (*InternalSolver)(nil)
+
+func (s *InternalSolver) FinalizerRequired(*controllerv1alpha1.DevWorkspaceRouting) bool {
+	return false
+}
+
+func (s *InternalSolver) Finalize(*controllerv1alpha1.DevWorkspaceRouting) error {
+	return nil
+}
+
+func (s *InternalSolver) GetSpecObjects(routing *controllerv1alpha1.DevWorkspaceRouting, workspaceMeta DevWorkspaceMetadata) (RoutingObjects, error) {
+	spec := routing.Spec
+	services := getServicesForEndpoints(spec.Endpoints, workspaceMeta)
+	services = append(services, GetDiscoverableServicesForEndpoints(spec.Endpoints, workspaceMeta)...)
+	return RoutingObjects{
+		Services: services,
+	}, nil
+}
+
+func (s *InternalSolver) GetExposedEndpoints(
+	endpoints map[string]controllerv1alpha1.EndpointList,
+	routingObj RoutingObjects) (exposedEndpoints map[string]controllerv1alpha1.ExposedEndpointList, ready bool, err error) {
+
+	exposedEndpoints = map[string]controllerv1alpha1.ExposedEndpointList{}
+
+	for machineName, machineEndpoints := range endpoints {
+		for _, endpoint := range machineEndpoints {
+			if endpoint.Exposure == controllerv1alpha1.NoneEndpointExposure {
+				continue
+			}
+			url, err := resolveInternalURLForEndpoint(endpoint, routingObj.Services)
+			if err != nil {
+				return nil, false, err
+			}
+
+			attributes := controllerv1alpha1.Attributes{}
+			for key, value := range endpoint.Attributes {
+				attributes[key] = value
+			}
+			attributes.PutString(constants.EndpointExposureAttribute, string(controllerv1alpha1.InternalEndpointExposure))
+
+			exposedEndpoints[machineName] = append(exposedEndpoints[machineName], controllerv1alpha1.ExposedEndpoint{
+				Name:       endpoint.Name,
+				Url:        url,
+				Attributes: attributes,
+			})
+		}
+	}
+
+	return exposedEndpoints, true, nil
+}
+
+// resolveInternalURLForEndpoint returns the URL for an endpoint using the in-cluster DNS name of the service
+// that exposes it. Discoverable endpoints use the service named after the endpoint.
+func resolveInternalURLForEndpoint(endpoint controllerv1alpha1.Endpoint, services []corev1.Service) (string, error) {
+	discoverable := endpoint.Attributes.GetBoolean(string(controllerv1alpha1.DiscoverableAttribute), nil)
+	for _, service := range services {
+		isDiscoverableService := service.Annotations[constants.DevWorkspaceDiscoverableServiceAnnotation] == "true"
+		if discoverable != isDiscoverableService {
+			continue
+		}
+		if isDiscoverableService && service.Name != common.EndpointName(endpoint.Name) {
+			continue
+		}
+		for _, servicePort := range service.Spec.Ports {
+			if servicePort.Port == int32(endpoint.TargetPort) {
+				if endpoint.Protocol == "" {
+					endpoint.Protocol = "http"
+				}
+				host := fmt.Sprintf("%s.%s.svc:%d", service.Name, service.Namespace, servicePort.Port)
+				return getURLForEndpoint(endpoint, host, "", false)
+			}
+		}
+	}
+	return "", fmt.Errorf("could not find service for endpoint %s", endpoint.Name)
+}
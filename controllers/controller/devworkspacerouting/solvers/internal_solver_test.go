@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package solvers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestInternalSolverExposesEndpointsViaServices(t *testing.T) {
+	routing := &controllerv1alpha1.DevWorkspaceRouting{
+		Spec: controllerv1alpha1.DevWorkspaceRoutingSpec{
+			DevWorkspaceId: "workspace-id",
+			RoutingClass:   controllerv1alpha1.DevWorkspaceRoutingInternal,
+			Endpoints: map[string]controllerv1alpha1.EndpointList{
+				"tools": {
+					{
+						Name:       "ide",
+						TargetPort: 3100,
+						Exposure:   controllerv1alpha1.PublicEndpointExposure,
+						Protocol:   "https",
+						Secure:     true,
+						Path:       "/status",
+						Attributes: controllerv1alpha1.Attributes{}.PutString(string(controllerv1alpha1.TypeEndpointAttribute), string(controllerv1alpha1.MainEndpointType)),
+					},
+					{
+						Name:       "debug",
+						TargetPort: 5005,
+						Exposure:   controllerv1alpha1.InternalEndpointExposure,
+						Protocol:   "tcp",
+					},
+					{
+						Name:       "database",
+						TargetPort: 5432,
+						Exposure:   controllerv1alpha1.InternalEndpointExposure,
+						Protocol:   "tcp",
+						Attributes: controllerv1alpha1.Attributes{}.PutBoolean(string(controllerv1alpha1.DiscoverableAttribute), true),
+					},
+					{
+						Name:       "local",
+						TargetPort: 8080,
+						Exposure:   controllerv1alpha1.NoneEndpointExposure,
+					},
+				},
+			},
+		},
+	}
+	meta := DevWorkspaceMetadata{
+		DevWorkspaceId: "workspace-id",
+		Namespace:      "test-namespace",
+		PodSelector:    map[string]string{constants.DevWorkspaceIDLabel: "workspace-id"},
+		ServiceName:    "workspace-id-service",
+	}
+	solver := &InternalSolver{}
+
+	routingObjects, err := solver.GetSpecObjects(routing, meta)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, routingObjects.Ingresses, "Should not create ingresses")
+	assert.Empty(t, routingObjects.Routes, "Should not create routes")
+	if !assert.Len(t, routingObjects.Services, 2, "Should create workspace service and discoverable service") {
+		return
+	}
+
+	exposedEndpoints, ready, err := solver.GetExposedEndpoints(routing.Spec.Endpoints, routingObjects)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, ready)
+	endpoints := exposedEndpoints["tools"]
+	if !assert.Len(t, endpoints, 3, "Should not expose endpoints with exposure none") {
+		return
+	}
+	urls := map[string]string{}
+	for _, endpoint := range endpoints {
+		urls[endpoint.Name] = endpoint.Url
+		assert.Equal(t, "internal", endpoint.Attributes.GetString(constants.EndpointExposureAttribute, nil), "Should mark endpoint %s as internal", endpoint.Name)
+	}
+	assert.Equal(t, "https://workspace-id-service.test-namespace.svc:3100/status", urls["ide"])
+	assert.Equal(t, "tcp://workspace-id-service.test-namespace.svc:5005", urls["debug"])
+	assert.Equal(t, "tcp://database.test-namespace.svc:5432", urls["database"])
+	assert.False(t, routing.Spec.Endpoints["tools"][1].Attributes.Exists(constants.EndpointExposureAttribute), "Should not modify routing spec")
+}
@@ -93,7 +93,8 @@ func (_ *SolverGetter) HasSolver(routingClass controllerv1alpha1.DevWorkspaceRou
 	case controllerv1alpha1.DevWorkspaceRoutingBasic,
 		controllerv1alpha1.DevWorkspaceRoutingCluster,
 		controllerv1alpha1.DevWorkspaceRoutingClusterTLS,
-		controllerv1alpha1.DevWorkspaceRoutingWebTerminal:
+		controllerv1alpha1.DevWorkspaceRoutingWebTerminal,
+		controllerv1alpha1.DevWorkspaceRoutingInternal:
 		return true
 	default:
 		return false
@@ -112,6 +113,8 @@ func (_ *SolverGetter) GetSolver(_ client.Client, routingClass controllerv1alpha
 			return nil, fmt.Errorf("routing class %s only supported on OpenShift", routingClass)
 		}
 		return &ClusterSolver{TLS: true}, nil
+	case controllerv1alpha1.DevWorkspaceRoutingInternal:
+		return &InternalSolver{}, nil
 	default:
 		return nil, RoutingNotSupported
 	}
@@ -134,7 +134,7 @@ var _ = BeforeSuite(func() {
 		Log:          ctrl.Log.WithName("controllers").WithName("DevWorkspaceRouting"),
 		Scheme:       mgr.GetScheme(),
 		SolverGetter: &solvers.SolverGetter{},
-		DebugLogging: config.ExperimentalFeaturesEnabled(),
+		DebugLogging: config.FeatureEnabled(config.DebugLogging),
 	}).SetupWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
@@ -0,0 +1,393 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacesnapshot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+var volumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   storage.VolumeSnapshotAPIGroup,
+	Version: "v1",
+	Kind:    storage.VolumeSnapshotKind,
+}
+
+const snapshotProgressRequeue = 5 * time.Second
+
+// DevWorkspaceSnapshotReconciler reconciles a DevWorkspaceSnapshot object
+type DevWorkspaceSnapshotReconciler struct {
+	client.Client
+	// NonCachingClient is used to read VolumeSnapshots, to avoid starting an informer for a resource
+	// that may not be available on the cluster
+	NonCachingClient client.Client
+	Log              logr.Logger
+	Scheme           *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspacesnapshots,verbs=*
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspacesnapshots/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create;delete
+
+func (r *DevWorkspaceSnapshotReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	snapshot := &controllerv1alpha1.DevWorkspaceSnapshot{}
+	if err := r.Get(ctx, req.NamespacedName, snapshot); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			// Owned VolumeSnapshots and jobs are garbage collected automatically
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if snapshot.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+	switch snapshot.Status.Phase {
+	case controllerv1alpha1.SnapshotPhaseReady, controllerv1alpha1.SnapshotPhaseFailed:
+		return reconcile.Result{}, nil
+	}
+
+	workspace := &dw.DevWorkspace{}
+	workspaceNN := types.NamespacedName{Name: snapshot.Spec.DevWorkspaceName, Namespace: snapshot.Namespace}
+	if err := r.Get(ctx, workspaceNN, workspace); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, r.updateStatus(ctx, snapshot, controllerv1alpha1.SnapshotPhaseFailed,
+				fmt.Sprintf("DevWorkspace %s does not exist", snapshot.Spec.DevWorkspaceName))
+		}
+		return reconcile.Result{}, err
+	}
+
+	// Only stopped workspaces are snapshotted, to make sure data on the PVC is consistent. Snapshots that are
+	// already in progress are allowed to complete.
+	if snapshot.Status.Phase != controllerv1alpha1.SnapshotPhaseInProgress {
+		if workspace.Status.DevWorkspaceId == "" || workspace.Spec.Started || workspace.Status.Phase != dw.DevWorkspaceStatusStopped {
+			return reconcile.Result{}, r.updateStatus(ctx, snapshot, controllerv1alpha1.SnapshotPhasePending,
+				fmt.Sprintf("Waiting for DevWorkspace %s to be stopped", workspace.Name))
+		}
+	}
+
+	workspaceConfig, err := config.ResolveConfigForWorkspace(workspace, r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read configuration for DevWorkspace; using global configuration")
+		workspaceConfig = config.GetGlobalConfig()
+	}
+	workspaceWithConfig := &common.DevWorkspaceWithConfig{DevWorkspace: workspace, Config: workspaceConfig}
+	clusterAPI := sync.ClusterAPI{
+		Client:           r.Client,
+		NonCachingClient: r.NonCachingClient,
+		Scheme:           r.Scheme,
+		Logger:           reqLogger,
+		Ctx:              ctx,
+	}
+
+	method, err := r.getSnapshotMethod(snapshot, workspaceWithConfig)
+	if err != nil {
+		return reconcile.Result{}, r.updateStatus(ctx, snapshot, controllerv1alpha1.SnapshotPhaseFailed, err.Error())
+	}
+	storageType := storage.GetStorageType(workspaceWithConfig)
+	if storageType == "" {
+		storageType = constants.CommonStorageClassType
+	}
+	snapshot.Status.Method = method
+	snapshot.Status.DevWorkspaceId = workspace.Status.DevWorkspaceId
+	snapshot.Status.StorageType = storageType
+
+	reqLogger = reqLogger.WithValues(constants.DevWorkspaceIDLoggerKey, workspace.Status.DevWorkspaceId)
+	reqLogger.Info("Reconciling DevWorkspaceSnapshot", "method", method)
+
+	var phase controllerv1alpha1.DevWorkspaceSnapshotPhase
+	var message string
+	switch method {
+	case controllerv1alpha1.SnapshotMethodVolumeSnapshot:
+		phase, message, err = r.syncVolumeSnapshot(ctx, snapshot, workspaceWithConfig)
+	default:
+		phase, message, err = r.syncSnapshotJob(ctx, snapshot, workspaceWithConfig, clusterAPI)
+	}
+	if err != nil {
+		switch t := err.(type) {
+		case *dwerrors.FailError:
+			return reconcile.Result{}, r.updateStatus(ctx, snapshot, controllerv1alpha1.SnapshotPhaseFailed, t.Error())
+		case *dwerrors.RetryError:
+			reqLogger.Info(t.Error())
+			if err := r.updateStatus(ctx, snapshot, controllerv1alpha1.SnapshotPhaseInProgress, t.Message); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{Requeue: true, RequeueAfter: t.RequeueAfter}, nil
+		default:
+			return reconcile.Result{}, err
+		}
+	}
+	if err := r.updateStatus(ctx, snapshot, phase, message); err != nil {
+		return reconcile.Result{}, err
+	}
+	if phase == controllerv1alpha1.SnapshotPhaseInProgress {
+		return reconcile.Result{RequeueAfter: snapshotProgressRequeue}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// getSnapshotMethod determines how a workspace's storage should be captured for a snapshot. If the snapshot does not
+// specify a method, VolumeSnapshots are used whenever the workspace and cluster support them. An error is returned
+// if the requested method cannot be used.
+func (r *DevWorkspaceSnapshotReconciler) getSnapshotMethod(snapshot *controllerv1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig) (controllerv1alpha1.DevWorkspaceSnapshotMethod, error) {
+	if snapshot.Status.Method != "" {
+		return snapshot.Status.Method, nil
+	}
+
+	storageType := storage.GetStorageType(workspace)
+	switch storageType {
+	case constants.EphemeralStorageClassType, constants.AsyncStorageClassType:
+		return "", fmt.Errorf("DevWorkspace %s uses %s storage, which does not support snapshots", workspace.Name, storageType)
+	}
+	if !storage.WorkspaceNeedsStorage(&workspace.Spec.Template) {
+		return "", fmt.Errorf("DevWorkspace %s does not use persistent storage", workspace.Name)
+	}
+
+	volumeSnapshotsSupported := storageType == constants.PerWorkspaceStorageClassType && r.volumeSnapshotsAvailable()
+	switch snapshot.Spec.Method {
+	case controllerv1alpha1.SnapshotMethodVolumeSnapshot:
+		if storageType != constants.PerWorkspaceStorageClassType {
+			return "", fmt.Errorf("method %s is only supported for DevWorkspaces that use %s storage",
+				controllerv1alpha1.SnapshotMethodVolumeSnapshot, constants.PerWorkspaceStorageClassType)
+		}
+		if !volumeSnapshotsSupported {
+			return "", fmt.Errorf("method %s is not supported: VolumeSnapshot API is not available on the cluster",
+				controllerv1alpha1.SnapshotMethodVolumeSnapshot)
+		}
+		return controllerv1alpha1.SnapshotMethodVolumeSnapshot, nil
+	case controllerv1alpha1.SnapshotMethodObjectStorage:
+		if snapshot.Spec.ObjectStorage == nil {
+			return "", fmt.Errorf("field objectStorage must be set to use method %s", controllerv1alpha1.SnapshotMethodObjectStorage)
+		}
+		return controllerv1alpha1.SnapshotMethodObjectStorage, nil
+	default:
+		if volumeSnapshotsSupported {
+			return controllerv1alpha1.SnapshotMethodVolumeSnapshot, nil
+		}
+		if snapshot.Spec.ObjectStorage == nil {
+			return "", fmt.Errorf("VolumeSnapshots cannot be used for this DevWorkspace and field objectStorage is not set")
+		}
+		return controllerv1alpha1.SnapshotMethodObjectStorage, nil
+	}
+}
+
+// volumeSnapshotsAvailable checks whether the snapshot.storage.k8s.io API is served by the cluster.
+func (r *DevWorkspaceSnapshotReconciler) volumeSnapshotsAvailable() bool {
+	_, err := r.RESTMapper().RESTMapping(volumeSnapshotGVK.GroupKind(), volumeSnapshotGVK.Version)
+	if err != nil {
+		if !meta.IsNoMatchError(err) {
+			r.Log.Error(err, "Failed to check whether VolumeSnapshots are supported")
+		}
+		return false
+	}
+	return true
+}
+
+func (r *DevWorkspaceSnapshotReconciler) syncVolumeSnapshot(ctx context.Context, snapshot *controllerv1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig) (controllerv1alpha1.DevWorkspaceSnapshotPhase, string, error) {
+	volumeSnapshot := &unstructured.Unstructured{}
+	volumeSnapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err := r.NonCachingClient.Get(ctx, types.NamespacedName{Name: snapshot.Name, Namespace: snapshot.Namespace}, volumeSnapshot)
+	if k8sErrors.IsNotFound(err) {
+		specVolumeSnapshot, err := getSpecVolumeSnapshot(snapshot, workspace, r.Scheme)
+		if err != nil {
+			return "", "", err
+		}
+		if err := r.NonCachingClient.Create(ctx, specVolumeSnapshot); err != nil {
+			return "", "", err
+		}
+		return controllerv1alpha1.SnapshotPhaseInProgress, "Created VolumeSnapshot", nil
+	} else if err != nil {
+		return "", "", err
+	}
+
+	if !metav1.IsControlledBy(volumeSnapshot, snapshot) {
+		return "", "", &dwerrors.FailError{
+			Message: fmt.Sprintf("VolumeSnapshot %s already exists and is not owned by this DevWorkspaceSnapshot", volumeSnapshot.GetName()),
+		}
+	}
+	if errMsg, found, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "error", "message"); found && errMsg != "" {
+		return controllerv1alpha1.SnapshotPhaseFailed, fmt.Sprintf("VolumeSnapshot failed: %s", errMsg), nil
+	}
+	if readyToUse, _, _ := unstructured.NestedBool(volumeSnapshot.Object, "status", "readyToUse"); !readyToUse {
+		return controllerv1alpha1.SnapshotPhaseInProgress, "Waiting for VolumeSnapshot to be ready", nil
+	}
+	snapshot.Status.VolumeSnapshotName = volumeSnapshot.GetName()
+	if restoreSize, found, _ := unstructured.NestedString(volumeSnapshot.Object, "status", "restoreSize"); found {
+		snapshot.Status.Size = restoreSize
+	}
+	return controllerv1alpha1.SnapshotPhaseReady, "Snapshot ready", nil
+}
+
+func getSpecVolumeSnapshot(snapshot *controllerv1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig, scheme *runtime.Scheme) (*unstructured.Unstructured, error) {
+	volumeSnapshot := &unstructured.Unstructured{}
+	volumeSnapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	volumeSnapshot.SetName(snapshot.Name)
+	volumeSnapshot.SetNamespace(snapshot.Namespace)
+	volumeSnapshot.SetLabels(map[string]string{
+		constants.DevWorkspaceIDLabel:   workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel: workspace.Name,
+	})
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{
+			"persistentVolumeClaimName": common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, workspace.Annotations),
+		},
+	}
+	if snapshot.Spec.VolumeSnapshotClassName != nil {
+		spec["volumeSnapshotClassName"] = *snapshot.Spec.VolumeSnapshotClassName
+	}
+	if err := unstructured.SetNestedMap(volumeSnapshot.Object, spec, "spec"); err != nil {
+		return nil, err
+	}
+	if err := controllerutil.SetControllerReference(snapshot, volumeSnapshot, scheme); err != nil {
+		return nil, err
+	}
+	return volumeSnapshot, nil
+}
+
+func (r *DevWorkspaceSnapshotReconciler) syncSnapshotJob(ctx context.Context, snapshot *controllerv1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (controllerv1alpha1.DevWorkspaceSnapshotPhase, string, error) {
+	specJob, err := storage.GetSpecSnapshotJob(snapshot, workspace, clusterAPI)
+	if err != nil {
+		return "", "", err
+	}
+	snapshot.Status.Location = storage.GetSnapshotArchiveURL(snapshot)
+
+	clusterObj, err := sync.SyncObjectWithCluster(specJob, clusterAPI)
+	if err != nil {
+		return "", "", dwerrors.WrapSyncError(err)
+	}
+	clusterJob := clusterObj.(*batchv1.Job)
+	for _, condition := range clusterJob.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			size, err := r.getArchiveSize(ctx, clusterJob)
+			if err != nil {
+				return "", "", err
+			}
+			snapshot.Status.Size = size
+			return controllerv1alpha1.SnapshotPhaseReady, "Snapshot ready", nil
+		case batchv1.JobFailed:
+			return controllerv1alpha1.SnapshotPhaseFailed, fmt.Sprintf("Snapshot job failed: see logs for job %q for details", clusterJob.Name), nil
+		}
+	}
+	return controllerv1alpha1.SnapshotPhaseInProgress, "Uploading DevWorkspace data", nil
+}
+
+// getArchiveSize reads the size of the uploaded archive from the termination message of a completed snapshot job,
+// formatted as a quantity. The empty string is returned if the size cannot be determined.
+func (r *DevWorkspaceSnapshotReconciler) getArchiveSize(ctx context.Context, job *batchv1.Job) (string, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.State.Terminated == nil {
+				continue
+			}
+			size, err := strconv.ParseInt(strings.TrimSpace(containerStatus.State.Terminated.Message), 10, 64)
+			if err != nil {
+				return "", nil
+			}
+			return resource.NewQuantity(size, resource.BinarySI).String(), nil
+		}
+	}
+	return "", nil
+}
+
+func (r *DevWorkspaceSnapshotReconciler) updateStatus(ctx context.Context, snapshot *controllerv1alpha1.DevWorkspaceSnapshot, phase controllerv1alpha1.DevWorkspaceSnapshotPhase, message string) error {
+	if snapshot.Status.Phase == phase && snapshot.Status.Message == message && phase != controllerv1alpha1.SnapshotPhaseReady {
+		return nil
+	}
+	snapshot.Status.Phase = phase
+	snapshot.Status.Message = message
+	if phase == controllerv1alpha1.SnapshotPhaseReady {
+		now := metav1.Now()
+		snapshot.Status.CompletionTime = &now
+	}
+	return r.Status().Update(ctx, snapshot)
+}
+
+// snapshotsForWorkspace enqueues reconciles for all DevWorkspaceSnapshots that refer to a DevWorkspace, so that pending
+// snapshots are processed once the DevWorkspace is stopped.
+func (r *DevWorkspaceSnapshotReconciler) snapshotsForWorkspace(obj client.Object) []reconcile.Request {
+	snapshots := &controllerv1alpha1.DevWorkspaceSnapshotList{}
+	if err := r.List(context.Background(), snapshots, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list DevWorkspaceSnapshots", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, snapshot := range snapshots.Items {
+		if snapshot.Spec.DevWorkspaceName != obj.GetName() || snapshot.Status.Phase == controllerv1alpha1.SnapshotPhaseReady || snapshot.Status.Phase == controllerv1alpha1.SnapshotPhaseFailed {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: snapshot.Name, Namespace: snapshot.Namespace},
+		})
+	}
+	return requests
+}
+
+func (r *DevWorkspaceSnapshotReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&controllerv1alpha1.DevWorkspaceSnapshot{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &dw.DevWorkspace{}}, handler.EnqueueRequestsFromMapFunc(r.snapshotsForWorkspace)).
+		Complete(r)
+}
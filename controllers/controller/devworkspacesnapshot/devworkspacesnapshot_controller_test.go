@@ -0,0 +1,222 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacesnapshot
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const testNamespace = "test-namespace"
+
+func getTestWorkspace(storageType string, phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", phase)
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.DevWorkspaceStorageTypeAttribute, storageType)
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "projects",
+			ComponentUnion: dw.ComponentUnion{
+				Volume: &dw.VolumeComponent{},
+			},
+		},
+	}
+	return workspace
+}
+
+func getTestSnapshot(method controllerv1alpha1.DevWorkspaceSnapshotMethod) *controllerv1alpha1.DevWorkspaceSnapshot {
+	return &controllerv1alpha1.DevWorkspaceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-snapshot",
+			Namespace: testNamespace,
+			UID:       "snapshot-uid",
+		},
+		Spec: controllerv1alpha1.DevWorkspaceSnapshotSpec{
+			DevWorkspaceName: "test-workspace",
+			Method:           method,
+			ObjectStorage: &controllerv1alpha1.SnapshotObjectStorage{
+				URL:                   "https://s3.example.com/bucket",
+				CredentialsSecretName: "s3-credentials",
+			},
+		},
+	}
+}
+
+func getTestReconciler(volumeSnapshotsAvailable bool, objs ...client.Object) *DevWorkspaceSnapshotReconciler {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	config.SetGlobalConfigForTesting(nil)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	if volumeSnapshotsAvailable {
+		mapper.Add(volumeSnapshotGVK, meta.RESTScopeNamespace)
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+	fakeClient := fake.NewClientBuilder().WithScheme(testutil.Scheme).WithRESTMapper(mapper).WithObjects(append(objs, namespace)...).Build()
+	return &DevWorkspaceSnapshotReconciler{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Log:              zap.New(),
+		Scheme:           testutil.Scheme,
+	}
+}
+
+func reconcileSnapshot(t *testing.T, r *DevWorkspaceSnapshotReconciler) *controllerv1alpha1.DevWorkspaceSnapshot {
+	snapshotNN := types.NamespacedName{Name: "test-snapshot", Namespace: testNamespace}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: snapshotNN})
+	if !assert.NoError(t, err, "Should not return error on reconcile") {
+		t.FailNow()
+	}
+	snapshot := &controllerv1alpha1.DevWorkspaceSnapshot{}
+	if !assert.NoError(t, r.Get(context.Background(), snapshotNN, snapshot)) {
+		t.FailNow()
+	}
+	return snapshot
+}
+
+func TestSnapshotWaitsForWorkspaceToStop(t *testing.T) {
+	r := getTestReconciler(true, getTestSnapshot(""), getTestWorkspace(constants.PerWorkspaceStorageClassType, dw.DevWorkspaceStatusRunning))
+	snapshot := reconcileSnapshot(t, r)
+	assert.Equal(t, controllerv1alpha1.SnapshotPhasePending, snapshot.Status.Phase)
+	assert.Equal(t, "Waiting for DevWorkspace test-workspace to be stopped", snapshot.Status.Message)
+}
+
+func TestSnapshotFailsForMissingWorkspace(t *testing.T) {
+	r := getTestReconciler(true, getTestSnapshot(""))
+	snapshot := reconcileSnapshot(t, r)
+	assert.Equal(t, controllerv1alpha1.SnapshotPhaseFailed, snapshot.Status.Phase)
+}
+
+func TestSnapshotUsesVolumeSnapshotWhenAvailable(t *testing.T) {
+	r := getTestReconciler(true, getTestSnapshot(""), getTestWorkspace(constants.PerWorkspaceStorageClassType, dw.DevWorkspaceStatusStopped))
+	snapshot := reconcileSnapshot(t, r)
+	assert.Equal(t, controllerv1alpha1.SnapshotPhaseInProgress, snapshot.Status.Phase)
+	assert.Equal(t, controllerv1alpha1.SnapshotMethodVolumeSnapshot, snapshot.Status.Method)
+	assert.Equal(t, constants.PerWorkspaceStorageClassType, snapshot.Status.StorageType)
+
+	volumeSnapshot := &unstructured.Unstructured{}
+	volumeSnapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	if !assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-snapshot", Namespace: testNamespace}, volumeSnapshot),
+		"Should create VolumeSnapshot") {
+		return
+	}
+	pvcName, _, _ := unstructured.NestedString(volumeSnapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	assert.Equal(t, common.PerWorkspacePVCName("test-workspaceid", nil), pvcName)
+
+	assert.NoError(t, unstructured.SetNestedField(volumeSnapshot.Object, true, "status", "readyToUse"))
+	assert.NoError(t, unstructured.SetNestedField(volumeSnapshot.Object, "5Gi", "status", "restoreSize"))
+	assert.NoError(t, r.Update(context.Background(), volumeSnapshot))
+
+	snapshot = reconcileSnapshot(t, r)
+	assert.Equal(t, controllerv1alpha1.SnapshotPhaseReady, snapshot.Status.Phase)
+	assert.Equal(t, "test-snapshot", snapshot.Status.VolumeSnapshotName)
+	assert.Equal(t, "5Gi", snapshot.Status.Size)
+	assert.NotNil(t, snapshot.Status.CompletionTime)
+}
+
+func TestSnapshotUsesObjectStorageForCommonStorage(t *testing.T) {
+	r := getTestReconciler(true, getTestSnapshot(""), getTestWorkspace(constants.PerUserStorageClassType, dw.DevWorkspaceStatusStopped))
+	snapshot := reconcileSnapshot(t, r)
+	assert.Equal(t, controllerv1alpha1.SnapshotPhaseInProgress, snapshot.Status.Phase)
+	assert.Equal(t, controllerv1alpha1.SnapshotMethodObjectStorage, snapshot.Status.Method)
+	assert.Equal(t, "https://s3.example.com/bucket/test-namespace/test-snapshot.tar.gz", snapshot.Status.Location)
+
+	job := &batchv1.Job{}
+	if !assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: common.SnapshotJobName("snapshot-uid"), Namespace: testNamespace}, job),
+		"Should create snapshot job") {
+		return
+	}
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, r.Status().Update(context.Background(), job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "snapshot-pod",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "2048\n"}}},
+			},
+		},
+	}
+	assert.NoError(t, r.Create(context.Background(), pod))
+
+	snapshot = reconcileSnapshot(t, r)
+	assert.Equal(t, controllerv1alpha1.SnapshotPhaseReady, snapshot.Status.Phase)
+	assert.Equal(t, "2Ki", snapshot.Status.Size)
+}
+
+func TestSnapshotMethodValidation(t *testing.T) {
+	tests := []struct {
+		name                     string
+		method                   controllerv1alpha1.DevWorkspaceSnapshotMethod
+		storageType              string
+		volumeSnapshotsAvailable bool
+		removeObjectStorage      bool
+	}{
+		{
+			name:                     "VolumeSnapshot with common storage",
+			method:                   controllerv1alpha1.SnapshotMethodVolumeSnapshot,
+			storageType:              constants.CommonStorageClassType,
+			volumeSnapshotsAvailable: true,
+		},
+		{
+			name:        "VolumeSnapshot API not available",
+			method:      controllerv1alpha1.SnapshotMethodVolumeSnapshot,
+			storageType: constants.PerWorkspaceStorageClassType,
+		},
+		{
+			name:                "Object storage not configured",
+			storageType:         constants.PerWorkspaceStorageClassType,
+			removeObjectStorage: true,
+		},
+		{
+			name:        "Ephemeral storage",
+			storageType: constants.EphemeralStorageClassType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := getTestSnapshot(tt.method)
+			if tt.removeObjectStorage {
+				snapshot.Spec.ObjectStorage = nil
+			}
+			r := getTestReconciler(tt.volumeSnapshotsAvailable, snapshot, getTestWorkspace(tt.storageType, dw.DevWorkspaceStatusStopped))
+			snapshot = reconcileSnapshot(t, r)
+			assert.Equal(t, controllerv1alpha1.SnapshotPhaseFailed, snapshot.Status.Phase, "Snapshot should fail: %s", snapshot.Status.Message)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetask
+
+import (
+	"fmt"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/imagemirror"
+)
+
+var taskJobBackoffLimit int32 = 0
+
+// getTaskCommand returns the exec command referenced by a DevWorkspaceTask from the DevWorkspace's template.
+func getTaskCommand(task *controllerv1alpha1.DevWorkspaceTask, workspace *dw.DevWorkspace) (*dw.Command, error) {
+	for _, command := range workspace.Spec.Template.Commands {
+		if command.Id != task.Spec.CommandId {
+			continue
+		}
+		if command.Exec == nil {
+			return nil, &dwerrors.FailError{
+				Message: fmt.Sprintf("Command %s is not an exec command; only exec commands can be run as tasks", command.Id),
+			}
+		}
+		return command.DeepCopy(), nil
+	}
+	return nil, &dwerrors.FailError{
+		Message: fmt.Sprintf("DevWorkspace %s does not define command %s", workspace.Name, task.Spec.CommandId),
+	}
+}
+
+// getCommandScript returns the shell script that runs an exec command. The script has the format
+//
+//	export <env name>='<env value>'
+//	cd <workingDir>
+//	<commandline>
+func getCommandScript(command *dw.ExecCommand) string {
+	var lines []string
+	for _, env := range command.Env {
+		lines = append(lines, fmt.Sprintf("export %s=%s", env.Name, shellQuote(env.Value)))
+	}
+	if command.WorkingDir != "" {
+		lines = append(lines, fmt.Sprintf("cd %s", command.WorkingDir))
+	}
+	lines = append(lines, command.CommandLine)
+	return strings.Join(lines, "\n")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// getSpecTaskJob returns the job that runs the command of a DevWorkspaceTask in a new pod, using the image and
+// environment of the command's container component.
+func getSpecTaskJob(task *controllerv1alpha1.DevWorkspaceTask, command *dw.Command, workspace *common.DevWorkspaceWithConfig, scheme *runtime.Scheme) (*batchv1.Job, error) {
+	var container *dw.ContainerComponent
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Name == command.Exec.Component && component.Container != nil {
+			container = component.Container
+			break
+		}
+	}
+	if container == nil {
+		return nil, &dwerrors.FailError{
+			Message: fmt.Sprintf("Command %s does not refer to a container component defined in DevWorkspace %s", command.Id, workspace.Name),
+		}
+	}
+
+	jobLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:      workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:    workspace.Name,
+		constants.DevWorkspaceCreatorLabel: workspace.Labels[constants.DevWorkspaceCreatorLabel],
+	}
+	if restrictedAccess, needsRestrictedAccess := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; needsRestrictedAccess {
+		jobLabels[constants.DevWorkspaceRestrictedAccessAnnotation] = restrictedAccess
+	}
+
+	var podSecurityContext *corev1.PodSecurityContext
+	if infrastructure.IsOpenShift() {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	} else {
+		podSecurityContext = workspace.Config.Workspace.PodSecurityContext
+	}
+
+	var env []corev1.EnvVar
+	for _, envVar := range container.Env {
+		env = append(env, corev1.EnvVar{Name: envVar.Name, Value: envVar.Value})
+	}
+
+	taskContainer := corev1.Container{
+		Name:            command.Exec.Component,
+		Image:           imagemirror.RewriteImage(container.Image, workspace.Config.Workspace.ImageMirrors),
+		ImagePullPolicy: corev1.PullPolicy(workspace.Config.Workspace.ImagePullPolicy),
+		Command:         []string{"/bin/sh", "-c", getCommandScript(command.Exec)},
+		Env:             env,
+		SecurityContext: workspace.Config.Workspace.ContainerSecurityContext,
+	}
+	if workspace.Config.Workspace.DefaultContainerResources != nil {
+		taskContainer.Resources = *workspace.Config.Workspace.DefaultContainerResources.DeepCopy()
+	}
+
+	timeout := getTaskTimeout(task)
+	activeDeadlineSeconds := int64(timeout.Seconds())
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.TaskJobName(string(task.UID)),
+			Namespace: task.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:          &taskJobBackoffLimit,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: common.ServiceAccountName(workspace),
+					SecurityContext:    podSecurityContext,
+					Containers:         []corev1.Container{taskContainer},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(task, job, scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,449 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/exec"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/pods"
+)
+
+const (
+	taskPendingRequeue = 5 * time.Second
+	defaultTaskTimeout = 600 * time.Second
+	// maxTaskOutputBytes is the maximum size of a task's output stored in its status
+	maxTaskOutputBytes = 4096
+)
+
+// DevWorkspaceTaskReconciler reconciles a DevWorkspaceTask object
+type DevWorkspaceTaskReconciler struct {
+	client.Client
+	// Executor is used to run commands in DevWorkspace pods and read the logs of task jobs
+	Executor PodExecutor
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspacetasks,verbs=*
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspacetasks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+
+func (r *DevWorkspaceTaskReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	task := &controllerv1alpha1.DevWorkspaceTask{}
+	if err := r.Get(ctx, req.NamespacedName, task); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			// Owned jobs are garbage collected automatically
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if task.DeletionTimestamp != nil || isTaskFinished(task) {
+		return reconcile.Result{}, nil
+	}
+
+	workspace := &dw.DevWorkspace{}
+	workspaceNN := types.NamespacedName{Name: task.Spec.DevWorkspaceName, Namespace: task.Namespace}
+	if err := r.Get(ctx, workspaceNN, workspace); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+				fmt.Sprintf("DevWorkspace %s does not exist", task.Spec.DevWorkspaceName), reqLogger)
+		}
+		return reconcile.Result{}, err
+	}
+
+	command, err := getTaskCommand(task, workspace)
+	if err != nil {
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed, err.Error(), reqLogger)
+	}
+
+	if workspace.Status.DevWorkspaceId != "" {
+		reqLogger = reqLogger.WithValues(constants.DevWorkspaceIDLoggerKey, workspace.Status.DevWorkspaceId)
+	}
+	switch task.Spec.Mode {
+	case controllerv1alpha1.TaskModeJob:
+		return r.reconcileJobTask(ctx, task, workspace, command, reqLogger)
+	default:
+		return r.reconcileExecTask(ctx, task, workspace, command, reqLogger)
+	}
+}
+
+// reconcileExecTask runs a task's command in the DevWorkspace's pod. The command is run synchronously, so the
+// reconcile does not return until the command finishes or times out.
+func (r *DevWorkspaceTaskReconciler) reconcileExecTask(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, workspace *dw.DevWorkspace, command *dw.Command, logger logr.Logger) (ctrl.Result, error) {
+	if task.Status.Phase == controllerv1alpha1.TaskPhaseRunning {
+		// Since commands are run within a single reconcile, a task that is already running was interrupted, e.g.
+		// by the controller restarting.
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			"Task was interrupted before the command completed", logger)
+	}
+	if workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation] == "true" {
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			fmt.Sprintf("Mode %s is not supported for DevWorkspaces with restricted access", controllerv1alpha1.TaskModeExec), logger)
+	}
+	// Commands bound to preStop events are run while the DevWorkspace is stopping, before its pod is stopped
+	isPreStopTask := task.Labels[constants.DevWorkspacePreStopTaskLabel] == "true" && workspace.Status.Phase == dw.DevWorkspaceStatusStopping
+	if workspace.Status.Phase != dw.DevWorkspaceStatusRunning && !isPreStopTask {
+		return reconcile.Result{}, r.updatePending(ctx, task, fmt.Sprintf("Waiting for DevWorkspace %s to be running", workspace.Name))
+	}
+
+	containerName := command.Exec.Component
+	pod, err := pods.GetRunningWorkspacePod(ctx, r.Client, workspace.Namespace, workspace.Status.DevWorkspaceId)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pod == nil {
+		if err := r.updatePending(ctx, task, fmt.Sprintf("Waiting for DevWorkspace %s pod to be running", workspace.Name)); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: taskPendingRequeue}, nil
+	}
+	if !podHasContainer(pod, containerName) {
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			fmt.Sprintf("DevWorkspace %s does not have a container %s", workspace.Name, containerName), logger)
+	}
+
+	now := metav1.Now()
+	task.Status.Phase = controllerv1alpha1.TaskPhaseRunning
+	task.Status.Message = "Running command"
+	task.Status.PodName = pod.Name
+	task.Status.StartTime = &now
+	if err := r.Status().Update(ctx, task); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Running DevWorkspaceTask command", "command", command.Id, "pod", pod.Name)
+	timeout := getTaskTimeout(task)
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	output := &outputBuffer{}
+	var execOutput io.Writer = output
+	stopStreaming := func() {}
+	if task.Spec.OutputConfigMap != "" {
+		configMapOutput := &streamingOutputBuffer{}
+		execOutput = io.MultiWriter(output, configMapOutput)
+		stopStreaming = r.streamOutput(ctx, task, configMapOutput, logger)
+	}
+	execErr := r.Executor.Exec(execCtx, pod, containerName, []string{"/bin/sh", "-c", getCommandScript(command.Exec)}, execOutput)
+	stopStreaming()
+	task.Status.Output = output.String()
+
+	var exitErr exec.ExitError
+	switch {
+	case execErr == nil:
+		task.Status.ExitCode = pointerInt32(0)
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseSucceeded, "Command completed", logger)
+	case errors.As(execErr, &exitErr):
+		task.Status.ExitCode = pointerInt32(exitErr.ExitStatus())
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			fmt.Sprintf("Command exited with code %d", exitErr.ExitStatus()), logger)
+	case errors.Is(execCtx.Err(), context.DeadlineExceeded):
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			fmt.Sprintf("Command did not complete within %s", timeout), logger)
+	default:
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			fmt.Sprintf("Failed to run command: %s", execErr), logger)
+	}
+}
+
+// reconcileJobTask runs a task's command in a job, and updates the task's status once the job finishes.
+func (r *DevWorkspaceTaskReconciler) reconcileJobTask(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, workspace *dw.DevWorkspace, command *dw.Command, logger logr.Logger) (ctrl.Result, error) {
+	// Jobs use the DevWorkspace's serviceaccount, which is only provisioned once the DevWorkspace is started.
+	if workspace.Status.DevWorkspaceId == "" {
+		return reconcile.Result{}, r.updatePending(ctx, task, fmt.Sprintf("Waiting for DevWorkspace %s to be started", workspace.Name))
+	}
+
+	job := &batchv1.Job{}
+	jobNN := types.NamespacedName{Name: common.TaskJobName(string(task.UID)), Namespace: task.Namespace}
+	err := r.Get(ctx, jobNN, job)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if task.Status.Phase == controllerv1alpha1.TaskPhaseRunning {
+			return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+				fmt.Sprintf("Job %s was deleted before the command completed", jobNN.Name), logger)
+		}
+		workspaceConfig, err := config.ResolveConfigForWorkspace(workspace, r.Client)
+		if err != nil {
+			logger.Error(err, "Failed to read configuration for DevWorkspace; using global configuration")
+			workspaceConfig = config.GetGlobalConfig()
+		}
+		workspaceWithConfig := &common.DevWorkspaceWithConfig{DevWorkspace: workspace, Config: workspaceConfig}
+		specJob, err := getSpecTaskJob(task, command, workspaceWithConfig, r.Scheme)
+		if err != nil {
+			var failErr *dwerrors.FailError
+			if errors.As(err, &failErr) {
+				return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed, failErr.Error(), logger)
+			}
+			return reconcile.Result{}, err
+		}
+		logger.Info("Creating job for DevWorkspaceTask", "command", command.Id, "job", specJob.Name)
+		if err := r.Create(ctx, specJob); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return reconcile.Result{}, err
+		}
+		now := metav1.Now()
+		task.Status.Phase = controllerv1alpha1.TaskPhaseRunning
+		task.Status.Message = "Running command in job"
+		task.Status.JobName = specJob.Name
+		task.Status.StartTime = &now
+		return reconcile.Result{}, r.Status().Update(ctx, task)
+	case err != nil:
+		return reconcile.Result{}, err
+	}
+
+	if !metav1.IsControlledBy(job, task) {
+		return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed,
+			fmt.Sprintf("Job %s already exists and is not owned by this DevWorkspaceTask", job.Name), logger)
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			if err := r.readJobResult(ctx, task, job, logger); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseSucceeded, "Command completed", logger)
+		case batchv1.JobFailed:
+			if err := r.readJobResult(ctx, task, job, logger); err != nil {
+				return reconcile.Result{}, err
+			}
+			message := fmt.Sprintf("Job %s failed: %s", job.Name, condition.Message)
+			if condition.Reason == "DeadlineExceeded" {
+				message = fmt.Sprintf("Command did not complete within %s", getTaskTimeout(task))
+			} else if task.Status.ExitCode != nil {
+				message = fmt.Sprintf("Command exited with code %d", *task.Status.ExitCode)
+			}
+			return reconcile.Result{}, r.finishTask(ctx, task, controllerv1alpha1.TaskPhaseFailed, message, logger)
+		}
+	}
+	// Job status changes trigger a reconcile, as the job is owned by the task.
+	return reconcile.Result{}, nil
+}
+
+// readJobResult sets the output and exit code of a task from the pod created by the task's job, if it exists.
+func (r *DevWorkspaceTaskReconciler) readJobResult(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, job *batchv1.Job, logger logr.Logger) error {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return err
+	}
+	for _, pod := range podList.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.State.Terminated == nil {
+				continue
+			}
+			task.Status.PodName = pod.Name
+			task.Status.ExitCode = pointerInt32(int(containerStatus.State.Terminated.ExitCode))
+			logs, err := r.Executor.GetLogs(ctx, &pod, containerStatus.Name)
+			if err != nil {
+				// Logs are informational; failing to read them should not prevent the task from completing.
+				logger.Error(err, "Failed to read logs for DevWorkspaceTask job", "pod", pod.Name)
+				return nil
+			}
+			output := &outputBuffer{}
+			output.Write([]byte(logs))
+			task.Status.Output = output.String()
+			if task.Spec.OutputConfigMap != "" {
+				if err := r.writeOutputConfigMap(ctx, task, strings.ToValidUTF8(logs, "")); err != nil {
+					logger.Error(err, "Failed to write DevWorkspaceTask output to ConfigMap", "configmap", task.Spec.OutputConfigMap)
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func podHasContainer(pod *corev1.Pod, containerName string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *DevWorkspaceTaskReconciler) updatePending(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, message string) error {
+	if task.Status.Phase == controllerv1alpha1.TaskPhasePending && task.Status.Message == message {
+		return nil
+	}
+	task.Status.Phase = controllerv1alpha1.TaskPhasePending
+	task.Status.Message = message
+	return r.Status().Update(ctx, task)
+}
+
+// finishTask sets the final phase of a task and removes finished tasks for the same DevWorkspace that exceed the
+// task's history limit.
+func (r *DevWorkspaceTaskReconciler) finishTask(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, phase controllerv1alpha1.DevWorkspaceTaskPhase, message string, logger logr.Logger) error {
+	now := metav1.Now()
+	task.Status.Phase = phase
+	task.Status.Message = message
+	task.Status.CompletionTime = &now
+	if err := r.Status().Update(ctx, task); err != nil {
+		return err
+	}
+	if err := r.pruneTaskHistory(ctx, task, logger); err != nil {
+		// The task is already finished, so returning an error would not cause pruning to be retried.
+		logger.Error(err, "Failed to remove old DevWorkspaceTasks")
+	}
+	return nil
+}
+
+// pruneTaskHistory deletes the oldest finished DevWorkspaceTasks for the same DevWorkspace as task, so that at most
+// task.Spec.HistoryLimit finished tasks remain.
+func (r *DevWorkspaceTaskReconciler) pruneTaskHistory(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, logger logr.Logger) error {
+	if task.Spec.HistoryLimit == nil {
+		return nil
+	}
+	taskList := &controllerv1alpha1.DevWorkspaceTaskList{}
+	if err := r.List(ctx, taskList, client.InNamespace(task.Namespace)); err != nil {
+		return err
+	}
+	var finished []controllerv1alpha1.DevWorkspaceTask
+	for _, existing := range taskList.Items {
+		if existing.Spec.DevWorkspaceName != task.Spec.DevWorkspaceName || existing.DeletionTimestamp != nil {
+			continue
+		}
+		if existing.Name == task.Name {
+			// The cache may not yet reflect that this task is finished
+			existing = *task
+		}
+		if isTaskFinished(&existing) {
+			finished = append(finished, existing)
+		}
+	}
+	if len(finished) <= int(*task.Spec.HistoryLimit) {
+		return nil
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		iTime, jTime := getCompletionTime(&finished[i]), getCompletionTime(&finished[j])
+		if !iTime.Equal(jTime) {
+			return iTime.After(jTime)
+		}
+		return finished[i].Name > finished[j].Name
+	})
+	for _, old := range finished[*task.Spec.HistoryLimit:] {
+		old := old
+		logger.Info("Removing DevWorkspaceTask exceeding history limit", "task", old.Name)
+		if err := r.Delete(ctx, &old); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func getCompletionTime(task *controllerv1alpha1.DevWorkspaceTask) time.Time {
+	if task.Status.CompletionTime != nil {
+		return task.Status.CompletionTime.Time
+	}
+	return task.CreationTimestamp.Time
+}
+
+func isTaskFinished(task *controllerv1alpha1.DevWorkspaceTask) bool {
+	return task.Status.Phase == controllerv1alpha1.TaskPhaseSucceeded || task.Status.Phase == controllerv1alpha1.TaskPhaseFailed
+}
+
+func getTaskTimeout(task *controllerv1alpha1.DevWorkspaceTask) time.Duration {
+	if task.Spec.TimeoutSeconds != nil {
+		return time.Duration(*task.Spec.TimeoutSeconds) * time.Second
+	}
+	return defaultTaskTimeout
+}
+
+func pointerInt32(value int) *int32 {
+	result := int32(value)
+	return &result
+}
+
+// outputBuffer is an io.Writer that retains only the last maxTaskOutputBytes bytes written to it.
+type outputBuffer struct {
+	data []byte
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > maxTaskOutputBytes {
+		b.data = b.data[len(b.data)-maxTaskOutputBytes:]
+	}
+	return len(p), nil
+}
+
+func (b *outputBuffer) String() string {
+	return strings.ToValidUTF8(string(b.data), "")
+}
+
+// tasksForWorkspace enqueues reconciles for all DevWorkspaceTasks that refer to a DevWorkspace, so that pending
+// tasks are run once the DevWorkspace is running.
+func (r *DevWorkspaceTaskReconciler) tasksForWorkspace(obj client.Object) []reconcile.Request {
+	tasks := &controllerv1alpha1.DevWorkspaceTaskList{}
+	if err := r.List(context.Background(), tasks, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list DevWorkspaceTasks", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, task := range tasks.Items {
+		if task.Spec.DevWorkspaceName != obj.GetName() || task.Status.Phase == controllerv1alpha1.TaskPhaseRunning || isTaskFinished(&task) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: task.Name, Namespace: task.Namespace},
+		})
+	}
+	return requests
+}
+
+func (r *DevWorkspaceTaskReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&controllerv1alpha1.DevWorkspaceTask{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &dw.DevWorkspace{}}, handler.EnqueueRequestsFromMapFunc(r.tasksForWorkspace)).
+		Complete(r)
+}
@@ -0,0 +1,385 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetask
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/exec"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const testNamespace = "test-namespace"
+
+type fakeExecutor struct {
+	output   string
+	err      error
+	logs     string
+	commands [][]string
+}
+
+func (e *fakeExecutor) Exec(_ context.Context, _ *corev1.Pod, _ string, command []string, output io.Writer) error {
+	e.commands = append(e.commands, command)
+	if _, err := output.Write([]byte(e.output)); err != nil {
+		return err
+	}
+	return e.err
+}
+
+func (e *fakeExecutor) GetLogs(_ context.Context, _ *corev1.Pod, _ string) (string, error) {
+	return e.logs, nil
+}
+
+func getTestWorkspace(phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", phase)
+	workspace.Spec.Started = phase == dw.DevWorkspaceStatusRunning
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "tools",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{
+						Image: "test-image",
+						Env:   []dw.EnvVar{{Name: "COMPONENT_ENV", Value: "component"}},
+					},
+				},
+			},
+		},
+	}
+	workspace.Spec.Template.Commands = []dw.Command{
+		{
+			Id: "build",
+			CommandUnion: dw.CommandUnion{
+				Exec: &dw.ExecCommand{
+					Component:   "tools",
+					CommandLine: "make build",
+					WorkingDir:  "${PROJECT_SOURCE}",
+					Env:         []dw.EnvVar{{Name: "TARGET", Value: "it's"}},
+				},
+			},
+		},
+		{
+			Id: "apply",
+			CommandUnion: dw.CommandUnion{
+				Apply: &dw.ApplyCommand{Component: "tools"},
+			},
+		},
+	}
+	return workspace
+}
+
+func getTestPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace-pod",
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: "test-workspaceid"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "tools"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func getTestTask(name, commandId string, mode controllerv1alpha1.DevWorkspaceTaskMode) *controllerv1alpha1.DevWorkspaceTask {
+	return &controllerv1alpha1.DevWorkspaceTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			UID:       types.UID(name + "-uid"),
+		},
+		Spec: controllerv1alpha1.DevWorkspaceTaskSpec{
+			DevWorkspaceName: "test-workspace",
+			CommandId:        commandId,
+			Mode:             mode,
+		},
+	}
+}
+
+func getTestReconciler(executor *fakeExecutor, objs ...client.Object) *DevWorkspaceTaskReconciler {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	config.SetGlobalConfigForTesting(nil)
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &DevWorkspaceTaskReconciler{
+		Client:   fakeClient,
+		Executor: executor,
+		Log:      zap.New(),
+		Scheme:   testutil.Scheme,
+	}
+}
+
+func reconcileTask(t *testing.T, r *DevWorkspaceTaskReconciler, name string) *controllerv1alpha1.DevWorkspaceTask {
+	taskNN := types.NamespacedName{Name: name, Namespace: testNamespace}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: taskNN})
+	if !assert.NoError(t, err, "Should not return error on reconcile") {
+		t.FailNow()
+	}
+	task := &controllerv1alpha1.DevWorkspaceTask{}
+	if !assert.NoError(t, r.Get(context.Background(), taskNN, task)) {
+		t.FailNow()
+	}
+	return task
+}
+
+func TestExecTaskRunsCommandInWorkspacePod(t *testing.T) {
+	executor := &fakeExecutor{output: "build output\n"}
+	r := getTestReconciler(executor, getTestTask("test-task", "build", ""), getTestWorkspace(dw.DevWorkspaceStatusRunning), getTestPod())
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseSucceeded, task.Status.Phase, "Task should succeed: %s", task.Status.Message)
+	assert.Equal(t, "build output\n", task.Status.Output)
+	assert.Equal(t, pointer.Int32(0), task.Status.ExitCode)
+	assert.Equal(t, "test-workspace-pod", task.Status.PodName)
+	assert.NotNil(t, task.Status.StartTime)
+	assert.NotNil(t, task.Status.CompletionTime)
+	if assert.Len(t, executor.commands, 1) {
+		assert.Equal(t, []string{"/bin/sh", "-c", "export TARGET='it'\\''s'\ncd ${PROJECT_SOURCE}\nmake build"}, executor.commands[0])
+	}
+}
+
+func TestExecTaskReportsExitCode(t *testing.T) {
+	executor := &fakeExecutor{output: "build failed\n", err: exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 2"), Code: 2}}
+	r := getTestReconciler(executor, getTestTask("test-task", "build", controllerv1alpha1.TaskModeExec), getTestWorkspace(dw.DevWorkspaceStatusRunning), getTestPod())
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseFailed, task.Status.Phase)
+	assert.Equal(t, "Command exited with code 2", task.Status.Message)
+	assert.Equal(t, pointer.Int32(2), task.Status.ExitCode)
+	assert.Equal(t, "build failed\n", task.Status.Output)
+}
+
+func TestExecTaskWritesOutputConfigMap(t *testing.T) {
+	task := getTestTask("test-task", "build", controllerv1alpha1.TaskModeExec)
+	task.Spec.OutputConfigMap = "test-output"
+	executor := &fakeExecutor{output: "build output\n"}
+	r := getTestReconciler(executor, task, getTestWorkspace(dw.DevWorkspaceStatusRunning), getTestPod())
+	task = reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseSucceeded, task.Status.Phase)
+
+	configMap := &corev1.ConfigMap{}
+	if !assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-output", Namespace: testNamespace}, configMap),
+		"Should create output ConfigMap") {
+		return
+	}
+	assert.Equal(t, "build output\n", configMap.Data["output"])
+	assert.True(t, metav1.IsControlledBy(configMap, task), "Output ConfigMap should be owned by task")
+
+	// A subsequent task takes over the ConfigMap
+	nextTask := getTestTask("next-task", "build", controllerv1alpha1.TaskModeExec)
+	nextTask.Spec.OutputConfigMap = "test-output"
+	assert.NoError(t, r.Create(context.Background(), nextTask))
+	executor.output = "next output\n"
+	nextTask = reconcileTask(t, r, "next-task")
+	assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-output", Namespace: testNamespace}, configMap))
+	assert.Equal(t, "next output\n", configMap.Data["output"])
+	if assert.Len(t, configMap.OwnerReferences, 1) {
+		assert.Equal(t, nextTask.UID, configMap.OwnerReferences[0].UID)
+	}
+}
+
+func TestStreamingOutputBufferKeepsLastBytes(t *testing.T) {
+	output := &streamingOutputBuffer{}
+	_, _ = output.Write([]byte(strings.Repeat("a", maxConfigMapOutputBytes)))
+	_, _ = output.Write([]byte("tail"))
+	content, changed := output.read()
+	assert.True(t, changed)
+	assert.Len(t, content, maxConfigMapOutputBytes)
+	assert.True(t, strings.HasSuffix(content, "tail"))
+	_, changed = output.read()
+	assert.False(t, changed, "Buffer should not be changed until it is written to again")
+}
+
+func TestExecTaskWaitsForWorkspaceToBeRunning(t *testing.T) {
+	executor := &fakeExecutor{}
+	r := getTestReconciler(executor, getTestTask("test-task", "build", ""), getTestWorkspace(dw.DevWorkspaceStatusStopped))
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhasePending, task.Status.Phase)
+	assert.Equal(t, "Waiting for DevWorkspace test-workspace to be running", task.Status.Message)
+	assert.Empty(t, executor.commands, "Should not run command")
+}
+
+func TestExecTaskRunsPreStopCommandInStoppingWorkspace(t *testing.T) {
+	preStopTask := getTestTask("test-task", "build", controllerv1alpha1.TaskModeExec)
+	preStopTask.Labels = map[string]string{constants.DevWorkspacePreStopTaskLabel: "true"}
+	executor := &fakeExecutor{}
+	r := getTestReconciler(executor, preStopTask, getTestTask("other-task", "build", controllerv1alpha1.TaskModeExec),
+		getTestWorkspace(dw.DevWorkspaceStatusStopping), getTestPod())
+
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseSucceeded, task.Status.Phase, "Task should succeed: %s", task.Status.Message)
+
+	task = reconcileTask(t, r, "other-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhasePending, task.Status.Phase, "Only preStop tasks should run in stopping workspaces")
+	assert.Len(t, executor.commands, 1)
+}
+
+func TestExecTaskFailsForRestrictedAccessWorkspace(t *testing.T) {
+	workspace := getTestWorkspace(dw.DevWorkspaceStatusRunning)
+	workspace.Annotations = map[string]string{constants.DevWorkspaceRestrictedAccessAnnotation: "true"}
+	executor := &fakeExecutor{}
+	r := getTestReconciler(executor, getTestTask("test-task", "build", ""), workspace, getTestPod())
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseFailed, task.Status.Phase)
+	assert.Empty(t, executor.commands, "Should not run command")
+}
+
+func TestExecTaskFailsIfInterrupted(t *testing.T) {
+	runningTask := getTestTask("test-task", "build", "")
+	runningTask.Status.Phase = controllerv1alpha1.TaskPhaseRunning
+	executor := &fakeExecutor{}
+	r := getTestReconciler(executor, runningTask, getTestWorkspace(dw.DevWorkspaceStatusRunning), getTestPod())
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseFailed, task.Status.Phase)
+	assert.Empty(t, executor.commands, "Should not rerun command")
+}
+
+func TestTaskFailsForInvalidCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		commandId string
+		workspace *dw.DevWorkspace
+	}{
+		{
+			name:      "Missing DevWorkspace",
+			commandId: "build",
+		},
+		{
+			name:      "Missing command",
+			commandId: "test",
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusRunning),
+		},
+		{
+			name:      "Non-exec command",
+			commandId: "apply",
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusRunning),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{getTestTask("test-task", tt.commandId, ""), getTestPod()}
+			if tt.workspace != nil {
+				objs = append(objs, tt.workspace)
+			}
+			r := getTestReconciler(&fakeExecutor{}, objs...)
+			task := reconcileTask(t, r, "test-task")
+			assert.Equal(t, controllerv1alpha1.TaskPhaseFailed, task.Status.Phase)
+		})
+	}
+}
+
+func TestJobTaskRunsCommandInJob(t *testing.T) {
+	executor := &fakeExecutor{logs: "job output\n"}
+	r := getTestReconciler(executor, getTestTask("test-task", "build", controllerv1alpha1.TaskModeJob), getTestWorkspace(dw.DevWorkspaceStatusStopped))
+	task := reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseRunning, task.Status.Phase)
+	assert.Equal(t, common.TaskJobName("test-task-uid"), task.Status.JobName)
+
+	job := &batchv1.Job{}
+	if !assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: task.Status.JobName, Namespace: testNamespace}, job),
+		"Should create task job") {
+		return
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "test-image", container.Image)
+	assert.Equal(t, []corev1.EnvVar{{Name: "COMPONENT_ENV", Value: "component"}}, container.Env)
+	assert.Equal(t, "test-workspaceid-sa", job.Spec.Template.Spec.ServiceAccountName)
+	assert.Equal(t, pointer.Int64(int64(defaultTaskTimeout.Seconds())), job.Spec.ActiveDeadlineSeconds)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, r.Status().Update(context.Background(), job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "task-pod",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "tools", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+			},
+		},
+	}
+	assert.NoError(t, r.Create(context.Background(), pod))
+
+	task = reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseSucceeded, task.Status.Phase)
+	assert.Equal(t, "job output\n", task.Status.Output)
+	assert.Equal(t, pointer.Int32(0), task.Status.ExitCode)
+	assert.Equal(t, "task-pod", task.Status.PodName)
+}
+
+func TestTaskHistoryLimit(t *testing.T) {
+	var objs []client.Object
+	for i := 0; i < 3; i++ {
+		oldTask := getTestTask(fmt.Sprintf("old-task-%d", i), "build", "")
+		oldTask.Status.Phase = controllerv1alpha1.TaskPhaseSucceeded
+		oldTask.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-time.Duration(i+1) * time.Hour)}
+		objs = append(objs, oldTask)
+	}
+	otherWorkspaceTask := getTestTask("other-workspace-task", "build", "")
+	otherWorkspaceTask.Spec.DevWorkspaceName = "other-workspace"
+	otherWorkspaceTask.Status.Phase = controllerv1alpha1.TaskPhaseSucceeded
+	otherWorkspaceTask.Status.CompletionTime = &metav1.Time{Time: time.Now().Add(-24 * time.Hour)}
+	task := getTestTask("test-task", "build", "")
+	task.Spec.HistoryLimit = pointer.Int32(2)
+	objs = append(objs, otherWorkspaceTask, task, getTestWorkspace(dw.DevWorkspaceStatusRunning), getTestPod())
+
+	r := getTestReconciler(&fakeExecutor{}, objs...)
+	task = reconcileTask(t, r, "test-task")
+	assert.Equal(t, controllerv1alpha1.TaskPhaseSucceeded, task.Status.Phase)
+
+	taskList := &controllerv1alpha1.DevWorkspaceTaskList{}
+	assert.NoError(t, r.List(context.Background(), taskList))
+	var remaining []string
+	for _, remainingTask := range taskList.Items {
+		remaining = append(remaining, remainingTask.Name)
+	}
+	assert.ElementsMatch(t, []string{"test-task", "old-task-0", "other-workspace-task"}, remaining)
+	err := r.Get(context.Background(), types.NamespacedName{Name: "old-task-2", Namespace: testNamespace}, &controllerv1alpha1.DevWorkspaceTask{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Oldest task should be deleted")
+}
+
+func TestOutputBufferKeepsLastBytes(t *testing.T) {
+	output := &outputBuffer{}
+	_, _ = output.Write([]byte(strings.Repeat("a", maxTaskOutputBytes)))
+	_, _ = output.Write([]byte("tail"))
+	assert.Len(t, output.String(), maxTaskOutputBytes)
+	assert.True(t, strings.HasSuffix(output.String(), "tail"))
+}
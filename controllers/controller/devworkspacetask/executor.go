@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetask
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// logTailLines is the number of lines of a job's logs that are read to populate a task's output
+const logTailLines int64 = 200
+
+// PodExecutor runs commands in and reads logs from pods. It is an interface to allow the streaming APIs, which are
+// not supported by controller-runtime clients, to be replaced in tests.
+type PodExecutor interface {
+	// Exec runs command in the container of a pod, writing both stdout and stderr to output. If the command exits
+	// with a non-zero exit code, the returned error implements k8s.io/client-go/util/exec.ExitError.
+	Exec(ctx context.Context, pod *corev1.Pod, container string, command []string, output io.Writer) error
+	// GetLogs returns the last lines of the logs of the container of a pod.
+	GetLogs(ctx context.Context, pod *corev1.Pod, container string) (string, error)
+}
+
+type clusterPodExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+var _ PodExecutor = (*clusterPodExecutor)(nil)
+
+// NewPodExecutor returns a PodExecutor that uses the Kubernetes API described by config.
+func NewPodExecutor(config *rest.Config) (PodExecutor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterPodExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *clusterPodExecutor) Exec(ctx context.Context, pod *corev1.Pod, container string, command []string, output io.Writer) error {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, clientgoscheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: output,
+		Stderr: output,
+	})
+}
+
+func (e *clusterPodExecutor) GetLogs(ctx context.Context, pod *corev1.Pod, container string) (string, error) {
+	tailLines := logTailLines
+	logs, err := e.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(logs), nil
+}
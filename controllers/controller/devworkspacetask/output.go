@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetask
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+const (
+	// outputConfigMapKey is the key under which a task's output is stored in its output ConfigMap
+	outputConfigMapKey = "output"
+	// maxConfigMapOutputBytes is the maximum size of a task's output stored in its output ConfigMap
+	maxConfigMapOutputBytes = 512 * 1024
+)
+
+// outputFlushInterval is how often the output ConfigMap of a task run in a DevWorkspace's pod is updated while the
+// command runs.
+var outputFlushInterval = 10 * time.Second
+
+// streamingOutputBuffer is an io.Writer that retains only the last maxConfigMapOutputBytes bytes written to it. It is
+// safe for concurrent use, so that its content can be written to a ConfigMap while a command writes to it.
+type streamingOutputBuffer struct {
+	mu      sync.Mutex
+	data    []byte
+	changed bool
+}
+
+func (b *streamingOutputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > maxConfigMapOutputBytes {
+		b.data = b.data[len(b.data)-maxConfigMapOutputBytes:]
+	}
+	b.changed = true
+	return len(p), nil
+}
+
+// read returns the content of the buffer and whether it changed since the last time it was read.
+func (b *streamingOutputBuffer) read() (content string, changed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	changed = b.changed
+	b.changed = false
+	return strings.ToValidUTF8(string(b.data), ""), changed
+}
+
+// streamOutput periodically writes the content of output to the output ConfigMap of a task until the returned function
+// is called, which writes the final content of output and waits for streaming to stop. Failures are logged, as the
+// ConfigMap is informational.
+func (r *DevWorkspaceTaskReconciler) streamOutput(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, output *streamingOutputBuffer, logger logr.Logger) (stop func()) {
+	flush := func() {
+		content, changed := output.read()
+		if !changed {
+			return
+		}
+		if err := r.writeOutputConfigMap(ctx, task, content); err != nil {
+			logger.Error(err, "Failed to write DevWorkspaceTask output to ConfigMap", "configmap", task.Spec.OutputConfigMap)
+		}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(outputFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				flush()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		flush()
+	}
+}
+
+// writeOutputConfigMap creates or updates the output ConfigMap of a task. The task becomes the ConfigMap's only owner,
+// so that a ConfigMap that is reused by a subsequent task is not deleted along with the previous task.
+func (r *DevWorkspaceTaskReconciler) writeOutputConfigMap(ctx context.Context, task *controllerv1alpha1.DevWorkspaceTask, output string) error {
+	ownerRef := metav1.NewControllerRef(task, controllerv1alpha1.GroupVersion.WithKind("DevWorkspaceTask"))
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: task.Spec.OutputConfigMap, Namespace: task.Namespace}, configMap)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            task.Spec.OutputConfigMap,
+				Namespace:       task.Namespace,
+				Labels:          task.Labels,
+				OwnerReferences: []metav1.OwnerReference{*ownerRef},
+			},
+			Data: map[string]string{outputConfigMapKey: output},
+		}
+		return r.Create(ctx, configMap)
+	case err != nil:
+		return err
+	}
+	configMap.Labels = task.Labels
+	configMap.OwnerReferences = []metav1.OwnerReference{*ownerRef}
+	configMap.Data = map[string]string{outputConfigMapKey: output}
+	return r.Update(ctx, configMap)
+}
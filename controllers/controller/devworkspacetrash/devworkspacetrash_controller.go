@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetrash
+
+import (
+	"context"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// DevWorkspaceTrashReconciler restores DevWorkspaces that were moved to the trash when they are requested to be
+// restored, and permanently removes them (along with their storage) once their retention period expires.
+type DevWorkspaceTrashReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+func (r *DevWorkspaceTrashReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	trashCM := &corev1.ConfigMap{}
+	if err := r.Get(ctx, req.NamespacedName, trashCM); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if trashCM.Labels[constants.DevWorkspaceTrashLabel] != "true" || trashCM.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if trashCM.Annotations[constants.DevWorkspaceRestoreAnnotation] == "true" {
+		return reconcile.Result{}, r.restoreWorkspace(ctx, trashCM, reqLogger)
+	}
+
+	expiration, err := storage.GetTrashExpiration(trashCM)
+	if err != nil {
+		reqLogger.Error(err, "Failed to read trash expiration; removing DevWorkspace from trash")
+		return reconcile.Result{}, r.deleteTrash(ctx, trashCM)
+	}
+	if remaining := time.Until(expiration); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+	reqLogger.Info("Trash retention period expired; permanently deleting DevWorkspace", "devworkspace", trashCM.Labels[constants.DevWorkspaceNameLabel])
+	return reconcile.Result{}, r.deleteTrash(ctx, trashCM)
+}
+
+// restoreWorkspace recreates the DevWorkspace stored in a trash ConfigMap and deletes the ConfigMap, handing storage
+// retained by the ConfigMap over to the restored DevWorkspace.
+func (r *DevWorkspaceTrashReconciler) restoreWorkspace(ctx context.Context, trashCM *corev1.ConfigMap, log logr.Logger) error {
+	workspace, err := storage.GetTrashedWorkspace(trashCM)
+	if err != nil {
+		log.Error(err, "Failed to restore DevWorkspace from trash")
+		return nil
+	}
+	workspace.Namespace = trashCM.Namespace
+
+	existing := &dw.DevWorkspace{}
+	err = r.Get(ctx, types.NamespacedName{Name: workspace.Name, Namespace: workspace.Namespace}, existing)
+	switch {
+	case err == nil:
+		if existing.Annotations[constants.WorkspaceIdOverrideAnnotation] != trashCM.Labels[constants.DevWorkspaceIDLabel] {
+			log.Info("Cannot restore DevWorkspace from trash as a DevWorkspace with the same name already exists", "devworkspace", workspace.Name)
+			return nil
+		}
+		// DevWorkspace was already restored by a previous reconcile
+	case k8sErrors.IsNotFound(err):
+		clusterAPI := sync.ClusterAPI{
+			Ctx:    ctx,
+			Client: r.Client,
+			Scheme: r.Scheme,
+			Logger: log,
+		}
+		if err := storage.ReleaseTrashedStorage(trashCM, clusterAPI); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, workspace); err != nil {
+			return err
+		}
+		log.Info("Restored DevWorkspace from trash", "devworkspace", workspace.Name)
+	default:
+		return err
+	}
+	return r.deleteTrash(ctx, trashCM)
+}
+
+func (r *DevWorkspaceTrashReconciler) deleteTrash(ctx context.Context, trashCM *corev1.ConfigMap) error {
+	// Storage owned by the trash ConfigMap is removed by Kubernetes garbage collection
+	err := r.Delete(ctx, trashCM, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *DevWorkspaceTrashReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	isTrashConfigMap := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[constants.DevWorkspaceTrashLabel] == "true"
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("devworkspacetrash").
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isTrashConfigMap)).
+		Complete(r)
+}
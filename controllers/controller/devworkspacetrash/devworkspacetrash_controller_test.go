@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspacetrash
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const testNamespace = "test-namespace"
+
+func getTestTrashConfigMap(t *testing.T, expiration time.Time, restore bool) *corev1.ConfigMap {
+	workspace := &dw.DevWorkspace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DevWorkspace",
+			APIVersion: dw.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-workspace",
+			Namespace:   testNamespace,
+			Annotations: map[string]string{constants.WorkspaceIdOverrideAnnotation: "test-workspaceid"},
+		},
+	}
+	data, err := json.Marshal(workspace)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.TrashConfigMapName("test-workspaceid"),
+			Namespace: testNamespace,
+			UID:       "trash-uid",
+			Labels: map[string]string{
+				constants.DevWorkspaceTrashLabel: "true",
+				constants.DevWorkspaceIDLabel:    "test-workspaceid",
+				constants.DevWorkspaceNameLabel:  "test-workspace",
+			},
+			Annotations: map[string]string{
+				constants.DevWorkspaceTrashExpirationAnnotation: expiration.Format(time.RFC3339),
+			},
+		},
+		Data: map[string]string{"devworkspace.json": string(data)},
+	}
+	if restore {
+		cm.Annotations[constants.DevWorkspaceRestoreAnnotation] = "true"
+	}
+	return cm
+}
+
+func getTestPVC(trashCM *corev1.ConfigMap) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.PerWorkspacePVCName("test-workspaceid", nil),
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: "test-workspaceid"},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       trashCM.Name,
+					UID:        trashCM.UID,
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+	}
+}
+
+func getTestReconciler(objs ...client.Object) *DevWorkspaceTrashReconciler {
+	return &DevWorkspaceTrashReconciler{
+		Client: testutil.NewFakeClient(objs...),
+		Log:    zap.New(),
+		Scheme: testutil.Scheme,
+	}
+}
+
+func reconcileTrash(t *testing.T, r *DevWorkspaceTrashReconciler) ctrl.Result {
+	result, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: common.TrashConfigMapName("test-workspaceid"), Namespace: testNamespace},
+	})
+	if !assert.NoError(t, err, "Should not return error on reconcile") {
+		t.FailNow()
+	}
+	return result
+}
+
+func TestTrashRequeuesUntilExpiration(t *testing.T) {
+	trashCM := getTestTrashConfigMap(t, time.Now().Add(1*time.Hour), false)
+	r := getTestReconciler(trashCM)
+	result := reconcileTrash(t, r)
+	assert.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= 1*time.Hour, "Should requeue until trash expires")
+
+	err := r.Get(context.Background(), client.ObjectKeyFromObject(trashCM), &corev1.ConfigMap{})
+	assert.NoError(t, err, "Should not delete trash ConfigMap before expiration")
+}
+
+func TestTrashDeletesExpiredWorkspace(t *testing.T) {
+	trashCM := getTestTrashConfigMap(t, time.Now().Add(-1*time.Minute), false)
+	r := getTestReconciler(trashCM)
+	reconcileTrash(t, r)
+
+	err := r.Get(context.Background(), client.ObjectKeyFromObject(trashCM), &corev1.ConfigMap{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete expired trash ConfigMap")
+}
+
+func TestTrashRestoresWorkspace(t *testing.T) {
+	trashCM := getTestTrashConfigMap(t, time.Now().Add(1*time.Hour), true)
+	pvc := getTestPVC(trashCM)
+	r := getTestReconciler(trashCM, pvc)
+	reconcileTrash(t, r)
+
+	workspace := &dw.DevWorkspace{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-workspace", Namespace: testNamespace}, workspace),
+		"Should restore DevWorkspace") {
+		assert.Equal(t, "test-workspaceid", workspace.Annotations[constants.WorkspaceIdOverrideAnnotation])
+	}
+
+	err := r.Get(context.Background(), client.ObjectKeyFromObject(trashCM), &corev1.ConfigMap{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete trash ConfigMap after restoring")
+
+	clusterPVC := &corev1.PersistentVolumeClaim{}
+	if assert.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(pvc), clusterPVC)) {
+		assert.Empty(t, clusterPVC.OwnerReferences, "Should remove trash ConfigMap owner reference from PVC")
+	}
+}
+
+func TestTrashDoesNotOverwriteExistingWorkspace(t *testing.T) {
+	trashCM := getTestTrashConfigMap(t, time.Now().Add(1*time.Hour), true)
+	existing := &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-workspace",
+			Namespace: testNamespace,
+		},
+	}
+	r := getTestReconciler(trashCM, existing)
+	reconcileTrash(t, r)
+
+	err := r.Get(context.Background(), client.ObjectKeyFromObject(trashCM), &corev1.ConfigMap{})
+	assert.NoError(t, err, "Should keep trash ConfigMap if a DevWorkspace with the same name exists")
+}
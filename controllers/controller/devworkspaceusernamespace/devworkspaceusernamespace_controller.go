@@ -0,0 +1,367 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceusernamespace
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// DevWorkspaceUserNamespaceReconciler provisions a namespace for each user that creates a DevWorkspace
+type DevWorkspaceUserNamespaceReconciler struct {
+	client.Client
+	// NonCachingClient is used to read ResourceQuotas, LimitRanges, NetworkPolicies and RoleBindings in user
+	// namespaces, which are not otherwise watched by the controller
+	NonCachingClient client.Client
+	Log              logr.Logger
+	Scheme           *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=create;update
+// +kubebuilder:rbac:groups="",resources=resourcequotas;limitranges,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind
+
+func (r *DevWorkspaceUserNamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	nsConfig := config.GetGlobalConfig().Workspace.UserNamespaces
+	if nsConfig == nil || nsConfig.Enable == nil || !*nsConfig.Enable {
+		return reconcile.Result{}, nil
+	}
+
+	workspace := &dw.DevWorkspace{}
+	if err := r.Get(ctx, req.NamespacedName, workspace); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if workspace.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	username := workspace.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation]
+	// DevWorkspaces created by service accounts (e.g. for guest sessions and workshops) are not created by users
+	if username == "" || strings.HasPrefix(username, "system:") {
+		return reconcile.Result{}, nil
+	}
+	namespaceName := common.UserNamespaceName(nsConfig.NameTemplate, username, workspace.Labels[constants.DevWorkspaceCreatorLabel])
+	if errs := validation.IsDNS1123Label(namespaceName); len(errs) > 0 {
+		reqLogger.Info("Cannot provision user namespace: invalid namespace name", "namespace", namespaceName, "user", username, "errors", errs)
+		return reconcile.Result{}, nil
+	}
+
+	namespace, err := r.syncNamespace(ctx, namespaceName, username, nsConfig)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if namespace == nil {
+		reqLogger.Info("Namespace already exists and was not provisioned for user; skipping", "namespace", namespaceName, "user", username)
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.syncRoleBinding(ctx, namespace, username, nsConfig.ClusterRoleName); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.syncResourceQuota(ctx, namespace, nsConfig.Quota); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.syncLimitRange(ctx, namespace, nsConfig.LimitRange); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.syncNetworkPolicy(ctx, namespace, nsConfig.NetworkPolicy); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// syncNamespace creates the namespace for a user if it does not exist, and applies the labels and annotations from
+// the config to it. If the namespace exists but was not provisioned for the user, nil is returned.
+func (r *DevWorkspaceUserNamespaceReconciler) syncNamespace(ctx context.Context, name, username string, nsConfig *controllerv1alpha1.UserNamespacesConfig) (*corev1.Namespace, error) {
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: name}, namespace)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+		}
+		applyNamespaceMetadata(namespace, username, nsConfig)
+		if err := r.Create(ctx, namespace); err != nil {
+			return nil, err
+		}
+		r.Log.Info("Provisioned namespace for user", "namespace", name, "user", username)
+		return namespace, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if !isUserNamespace(namespace, username) {
+		return nil, nil
+	}
+	if namespace.DeletionTimestamp != nil {
+		return nil, fmt.Errorf("namespace %s for user %s is being deleted", name, username)
+	}
+	updated := namespace.DeepCopy()
+	applyNamespaceMetadata(updated, username, nsConfig)
+	if reflect.DeepEqual(updated.ObjectMeta, namespace.ObjectMeta) {
+		return namespace, nil
+	}
+	if err := r.Update(ctx, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *DevWorkspaceUserNamespaceReconciler) syncRoleBinding(ctx context.Context, namespace *corev1.Namespace, username, clusterRoleName string) error {
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.UserNamespaceRoleBindingName,
+			Namespace: namespace.Name,
+			Labels:    getUserNamespaceLabels(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRoleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.UserKind,
+				Name:     username,
+			},
+		},
+	}
+	existing := &rbacv1.RoleBinding{}
+	err := r.NonCachingClient.Get(ctx, types.NamespacedName{Name: roleBinding.Name, Namespace: roleBinding.Namespace}, existing)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		return r.NonCachingClient.Create(ctx, roleBinding)
+	case err != nil:
+		return err
+	}
+	if existing.RoleRef == roleBinding.RoleRef {
+		if reflect.DeepEqual(existing.Subjects, roleBinding.Subjects) {
+			return nil
+		}
+		existing.Subjects = roleBinding.Subjects
+		return r.NonCachingClient.Update(ctx, existing)
+	}
+	// The roleRef of a RoleBinding cannot be changed, so it has to be recreated
+	if err := r.NonCachingClient.Delete(ctx, existing); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return r.NonCachingClient.Create(ctx, roleBinding)
+}
+
+func (r *DevWorkspaceUserNamespaceReconciler) syncResourceQuota(ctx context.Context, namespace *corev1.Namespace, hard corev1.ResourceList) error {
+	quota := &corev1.ResourceQuota{}
+	err := r.NonCachingClient.Get(ctx, types.NamespacedName{Name: constants.UserNamespaceQuotaName, Namespace: namespace.Name}, quota)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if hard == nil {
+			return nil
+		}
+		quota = &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.UserNamespaceQuotaName,
+				Namespace: namespace.Name,
+				Labels:    getUserNamespaceLabels(),
+			},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard.DeepCopy(),
+			},
+		}
+		return r.NonCachingClient.Create(ctx, quota)
+	case err != nil:
+		return err
+	}
+	if hard == nil {
+		return r.deleteIgnoreNotFound(ctx, quota)
+	}
+	if equalResourceLists(quota.Spec.Hard, hard) {
+		return nil
+	}
+	quota.Spec.Hard = hard.DeepCopy()
+	return r.NonCachingClient.Update(ctx, quota)
+}
+
+func (r *DevWorkspaceUserNamespaceReconciler) syncLimitRange(ctx context.Context, namespace *corev1.Namespace, limits []corev1.LimitRangeItem) error {
+	limitRange := &corev1.LimitRange{}
+	err := r.NonCachingClient.Get(ctx, types.NamespacedName{Name: constants.UserNamespaceLimitRangeName, Namespace: namespace.Name}, limitRange)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if limits == nil {
+			return nil
+		}
+		limitRange = &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.UserNamespaceLimitRangeName,
+				Namespace: namespace.Name,
+				Labels:    getUserNamespaceLabels(),
+			},
+			Spec: corev1.LimitRangeSpec{
+				Limits: limits,
+			},
+		}
+		return r.NonCachingClient.Create(ctx, limitRange)
+	case err != nil:
+		return err
+	}
+	if limits == nil {
+		return r.deleteIgnoreNotFound(ctx, limitRange)
+	}
+	if reflect.DeepEqual(limitRange.Spec.Limits, limits) {
+		return nil
+	}
+	limitRange.Spec.Limits = limits
+	return r.NonCachingClient.Update(ctx, limitRange)
+}
+
+func (r *DevWorkspaceUserNamespaceReconciler) syncNetworkPolicy(ctx context.Context, namespace *corev1.Namespace, spec *networkingv1.NetworkPolicySpec) error {
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	err := r.NonCachingClient.Get(ctx, types.NamespacedName{Name: constants.UserNamespaceNetworkPolicyName, Namespace: namespace.Name}, networkPolicy)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if spec == nil {
+			return nil
+		}
+		networkPolicy = &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      constants.UserNamespaceNetworkPolicyName,
+				Namespace: namespace.Name,
+				Labels:    getUserNamespaceLabels(),
+			},
+			Spec: *spec.DeepCopy(),
+		}
+		return r.NonCachingClient.Create(ctx, networkPolicy)
+	case err != nil:
+		return err
+	}
+	if spec == nil {
+		return r.deleteIgnoreNotFound(ctx, networkPolicy)
+	}
+	if reflect.DeepEqual(networkPolicy.Spec, *spec) {
+		return nil
+	}
+	networkPolicy.Spec = *spec.DeepCopy()
+	return r.NonCachingClient.Update(ctx, networkPolicy)
+}
+
+func (r *DevWorkspaceUserNamespaceReconciler) deleteIgnoreNotFound(ctx context.Context, obj client.Object) error {
+	if err := r.NonCachingClient.Delete(ctx, obj); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// applyNamespaceMetadata adds the labels and annotations that mark a namespace as provisioned for a user, as well as
+// the labels and annotations from the config, to a namespace. Existing labels and annotations are otherwise
+// left unchanged.
+func applyNamespaceMetadata(namespace *corev1.Namespace, username string, nsConfig *controllerv1alpha1.UserNamespacesConfig) {
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	for key, value := range nsConfig.Labels {
+		namespace.Labels[key] = value
+	}
+	for key, value := range nsConfig.Annotations {
+		namespace.Annotations[key] = value
+	}
+	for key, value := range getUserNamespaceLabels() {
+		namespace.Labels[key] = value
+	}
+	namespace.Annotations[constants.DevWorkspaceUserNamespaceOwnerAnnotation] = username
+}
+
+func isUserNamespace(namespace *corev1.Namespace, username string) bool {
+	return namespace.Labels[constants.DevWorkspaceUserNamespaceLabel] == "true" &&
+		namespace.Annotations[constants.DevWorkspaceUserNamespaceOwnerAnnotation] == username
+}
+
+func getUserNamespaceLabels() map[string]string {
+	return map[string]string{
+		constants.DevWorkspaceUserNamespaceLabel: "true",
+	}
+}
+
+func equalResourceLists(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *DevWorkspaceUserNamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	// User namespaces are provisioned when a DevWorkspace is created. Since all DevWorkspaces are seen as created when
+	// the controller starts, changes to the config are applied to existing user namespaces on restart.
+	onCreate := predicate.Funcs{
+		CreateFunc:  func(_ event.CreateEvent) bool { return true },
+		UpdateFunc:  func(_ event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(_ event.DeleteEvent) bool { return false },
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("devworkspaceusernamespace").
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&dw.DevWorkspace{}, builder.WithPredicates(onCreate)).
+		Complete(r)
+}
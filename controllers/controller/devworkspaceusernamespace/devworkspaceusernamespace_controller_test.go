@@ -0,0 +1,194 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceusernamespace
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	testNamespace     = "test-namespace"
+	testUserNamespace = "test-user-devworkspaces"
+)
+
+func getTestWorkspace(username string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Namespace = testNamespace
+	workspace.Labels[constants.DevWorkspaceCreatorLabel] = "test-user-uid"
+	workspace.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation] = username
+	return workspace
+}
+
+func getTestReconciler(nsConfig *controllerv1alpha1.UserNamespacesConfig, objs ...client.Object) *DevWorkspaceUserNamespaceReconciler {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	setUserNamespacesConfig(nsConfig)
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &DevWorkspaceUserNamespaceReconciler{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Log:              zap.New(),
+		Scheme:           testutil.Scheme,
+	}
+}
+
+func setUserNamespacesConfig(nsConfig *controllerv1alpha1.UserNamespacesConfig) {
+	config.SetGlobalConfigForTesting(&controllerv1alpha1.OperatorConfiguration{
+		Workspace: &controllerv1alpha1.WorkspaceConfig{
+			UserNamespaces: nsConfig,
+		},
+	})
+}
+
+func reconcileWorkspace(t *testing.T, r *DevWorkspaceUserNamespaceReconciler) {
+	workspaceNN := types.NamespacedName{Name: "test-workspace", Namespace: testNamespace}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: workspaceNN})
+	if !assert.NoError(t, err, "Reconcile should not return error") {
+		t.FailNow()
+	}
+}
+
+func TestUserNamespaceNotProvisionedWhenDisabled(t *testing.T) {
+	r := getTestReconciler(&controllerv1alpha1.UserNamespacesConfig{Enable: pointer.Bool(false)}, getTestWorkspace("test-user"))
+	reconcileWorkspace(t, r)
+	err := r.Get(context.Background(), types.NamespacedName{Name: testUserNamespace}, &corev1.Namespace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should not provision namespace when user namespaces are disabled")
+}
+
+func TestUserNamespaceProvisioned(t *testing.T) {
+	r := getTestReconciler(&controllerv1alpha1.UserNamespacesConfig{
+		Enable: pointer.Bool(true),
+		Labels: map[string]string{"team": "developers"},
+		Quota: corev1.ResourceList{
+			corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+		},
+		LimitRange: []corev1.LimitRangeItem{
+			{
+				Type:    corev1.LimitTypeContainer,
+				Default: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		},
+		NetworkPolicy: &networkingv1.NetworkPolicySpec{
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}, getTestWorkspace("Test.User"))
+	reconcileWorkspace(t, r)
+
+	namespace := &corev1.Namespace{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: testUserNamespace}, namespace)) {
+		assert.Equal(t, "true", namespace.Labels[constants.DevWorkspaceUserNamespaceLabel])
+		assert.Equal(t, "developers", namespace.Labels["team"], "Should apply labels from config")
+		assert.Equal(t, "Test.User", namespace.Annotations[constants.DevWorkspaceUserNamespaceOwnerAnnotation])
+	}
+
+	roleBinding := &rbacv1.RoleBinding{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceRoleBindingName, Namespace: testUserNamespace}, roleBinding)) {
+		assert.Equal(t, "admin", roleBinding.RoleRef.Name, "Should use default ClusterRole")
+		if assert.Len(t, roleBinding.Subjects, 1) {
+			assert.Equal(t, "Test.User", roleBinding.Subjects[0].Name)
+		}
+	}
+	quota := &corev1.ResourceQuota{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceQuotaName, Namespace: testUserNamespace}, quota)) {
+		memoryQuota := quota.Spec.Hard[corev1.ResourceLimitsMemory]
+		assert.True(t, memoryQuota.Equal(resource.MustParse("8Gi")))
+	}
+	limitRange := &corev1.LimitRange{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceLimitRangeName, Namespace: testUserNamespace}, limitRange)) {
+		assert.Len(t, limitRange.Spec.Limits, 1)
+	}
+	networkPolicy := &networkingv1.NetworkPolicy{}
+	err := r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceNetworkPolicyName, Namespace: testUserNamespace}, networkPolicy)
+	assert.NoError(t, err, "Should create NetworkPolicy")
+}
+
+func TestUserNamespaceRemovesObjectsRemovedFromConfig(t *testing.T) {
+	nsConfig := &controllerv1alpha1.UserNamespacesConfig{
+		Enable: pointer.Bool(true),
+		Quota: corev1.ResourceList{
+			corev1.ResourceLimitsMemory: resource.MustParse("8Gi"),
+		},
+	}
+	r := getTestReconciler(nsConfig, getTestWorkspace("test-user"))
+	reconcileWorkspace(t, r)
+
+	nsConfig.Quota = nil
+	setUserNamespacesConfig(nsConfig)
+	reconcileWorkspace(t, r)
+	err := r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceQuotaName, Namespace: testUserNamespace}, &corev1.ResourceQuota{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete ResourceQuota when quota is removed from config")
+}
+
+func TestUserNamespaceUpdatesRoleBindingClusterRole(t *testing.T) {
+	nsConfig := &controllerv1alpha1.UserNamespacesConfig{Enable: pointer.Bool(true)}
+	r := getTestReconciler(nsConfig, getTestWorkspace("test-user"))
+	reconcileWorkspace(t, r)
+
+	nsConfig.ClusterRoleName = "edit"
+	setUserNamespacesConfig(nsConfig)
+	reconcileWorkspace(t, r)
+	roleBinding := &rbacv1.RoleBinding{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceRoleBindingName, Namespace: testUserNamespace}, roleBinding)) {
+		assert.Equal(t, "edit", roleBinding.RoleRef.Name, "Should recreate RoleBinding when ClusterRole changes")
+	}
+}
+
+func TestUserNamespaceDoesNotAdoptExistingNamespace(t *testing.T) {
+	existing := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: testUserNamespace,
+		},
+	}
+	r := getTestReconciler(&controllerv1alpha1.UserNamespacesConfig{Enable: pointer.Bool(true)}, getTestWorkspace("test-user"), existing)
+	reconcileWorkspace(t, r)
+
+	namespace := &corev1.Namespace{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: testUserNamespace}, namespace)) {
+		assert.NotContains(t, namespace.Labels, constants.DevWorkspaceUserNamespaceLabel, "Should not modify existing namespace")
+	}
+	err := r.Get(context.Background(), types.NamespacedName{Name: constants.UserNamespaceRoleBindingName, Namespace: testUserNamespace}, &rbacv1.RoleBinding{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should not grant user access to existing namespace")
+}
+
+func TestUserNamespaceIgnoresServiceAccounts(t *testing.T) {
+	r := getTestReconciler(&controllerv1alpha1.UserNamespacesConfig{Enable: pointer.Bool(true)},
+		getTestWorkspace("system:serviceaccount:devworkspace-controller:devworkspace-controller-serviceaccount"))
+	reconcileWorkspace(t, r)
+	namespaces := &corev1.NamespaceList{}
+	if assert.NoError(t, r.List(context.Background(), namespaces)) {
+		assert.Empty(t, namespaces.Items, "Should not provision namespaces for service accounts")
+	}
+}
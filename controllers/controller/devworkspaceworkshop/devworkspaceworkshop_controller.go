@@ -0,0 +1,311 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceworkshop
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// DevWorkspaceWorkshopReconciler reconciles a DevWorkspaceWorkshop object
+type DevWorkspaceWorkshopReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspaceworkshops,verbs=*
+// +kubebuilder:rbac:groups=controller.devfile.io,resources=devworkspaceworkshops/status,verbs=get;update;patch
+
+func (r *DevWorkspaceWorkshopReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", req.Namespace, "Request.Name", req.Name)
+
+	workshop := &controllerv1alpha1.DevWorkspaceWorkshop{}
+	if err := r.Get(ctx, req.NamespacedName, workshop); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if workshop.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.finalize(ctx, workshop)
+	}
+	if !controllerutil.ContainsFinalizer(workshop, constants.WorkshopCleanupFinalizer) {
+		controllerutil.AddFinalizer(workshop, constants.WorkshopCleanupFinalizer)
+		if err := r.Update(ctx, workshop); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	started := workshop.Spec.Started == nil || *workshop.Spec.Started
+	participants := map[string]bool{}
+	var participantStatuses []controllerv1alpha1.WorkshopParticipantStatus
+	for _, participant := range workshop.Spec.Participants {
+		if participants[participant] {
+			continue
+		}
+		participants[participant] = true
+		participantStatus, err := r.syncParticipant(ctx, workshop, participant, started)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		participantStatuses = append(participantStatuses, *participantStatus)
+	}
+
+	if err := r.deleteRemovedParticipants(ctx, workshop, participants); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	newStatus := getWorkshopStatus(participantStatuses, started)
+	if reflect.DeepEqual(workshop.Status, newStatus) {
+		return reconcile.Result{}, nil
+	}
+	reqLogger.Info("Updating workshop status", "progress", newStatus.Progress, "phase", newStatus.Phase)
+	workshop.Status = newStatus
+	return reconcile.Result{}, r.Status().Update(ctx, workshop)
+}
+
+// syncParticipant makes sure the namespace and DevWorkspace for the workshop participant exist, and returns the
+// current status of the participant's DevWorkspace.
+func (r *DevWorkspaceWorkshopReconciler) syncParticipant(ctx context.Context, workshop *controllerv1alpha1.DevWorkspaceWorkshop, participant string, started bool) (*controllerv1alpha1.WorkshopParticipantStatus, error) {
+	namespaceName := common.WorkshopParticipantNamespaceName(workshop.Name, participant)
+	participantStatus := &controllerv1alpha1.WorkshopParticipantStatus{
+		Name: participant,
+	}
+	if errs := validation.IsDNS1123Label(participant); len(errs) > 0 {
+		participantStatus.Phase = dw.DevWorkspaceStatusFailed
+		participantStatus.Message = fmt.Sprintf("Invalid participant name: %s", strings.Join(errs, ", "))
+		return participantStatus, nil
+	}
+	if errs := validation.IsDNS1123Label(namespaceName); len(errs) > 0 {
+		participantStatus.Phase = dw.DevWorkspaceStatusFailed
+		participantStatus.Message = fmt.Sprintf("Invalid namespace name %s: %s", namespaceName, strings.Join(errs, ", "))
+		return participantStatus, nil
+	}
+	participantStatus.Namespace = namespaceName
+
+	namespace := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: namespaceName}, namespace)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		namespace = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespaceName,
+				Labels: getParticipantLabels(workshop, participant),
+			},
+		}
+		if err := r.Create(ctx, namespace); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case !isParticipantNamespace(namespace, workshop):
+		participantStatus.Phase = dw.DevWorkspaceStatusFailed
+		participantStatus.Message = fmt.Sprintf("Namespace %s already exists and does not belong to this workshop", namespaceName)
+		return participantStatus, nil
+	case namespace.DeletionTimestamp != nil:
+		participantStatus.Phase = dw.DevWorkspaceStatusStarting
+		participantStatus.Message = fmt.Sprintf("Waiting for namespace %s to be deleted", namespaceName)
+		return participantStatus, nil
+	}
+
+	workspace := &dw.DevWorkspace{}
+	err = r.Get(ctx, types.NamespacedName{Name: workshop.Name, Namespace: namespaceName}, workspace)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		workspace = &dw.DevWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      workshop.Name,
+				Namespace: namespaceName,
+				Labels:    getParticipantLabels(workshop, participant),
+			},
+			Spec: dw.DevWorkspaceSpec{
+				Started:  started,
+				Template: *workshop.Spec.Template.DeepCopy(),
+			},
+		}
+		if err := r.Create(ctx, workspace); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case workspace.Spec.Started != started:
+		workspace.Spec.Started = started
+		if err := r.Update(ctx, workspace); err != nil {
+			return nil, err
+		}
+	}
+
+	participantStatus.Phase = workspace.Status.Phase
+	participantStatus.MainUrl = workspace.Status.MainUrl
+	participantStatus.Message = workspace.Status.Message
+	return participantStatus, nil
+}
+
+// deleteRemovedParticipants deletes the namespaces of participants that are no longer listed in the workshop.
+func (r *DevWorkspaceWorkshopReconciler) deleteRemovedParticipants(ctx context.Context, workshop *controllerv1alpha1.DevWorkspaceWorkshop, participants map[string]bool) error {
+	namespaces, err := r.listWorkshopNamespaces(ctx, workshop)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		if participants[namespace.Labels[constants.DevWorkspaceWorkshopParticipantLabel]] {
+			continue
+		}
+		if err := r.deleteNamespace(ctx, &namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DevWorkspaceWorkshopReconciler) finalize(ctx context.Context, workshop *controllerv1alpha1.DevWorkspaceWorkshop) error {
+	if !controllerutil.ContainsFinalizer(workshop, constants.WorkshopCleanupFinalizer) {
+		return nil
+	}
+	namespaces, err := r.listWorkshopNamespaces(ctx, workshop)
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		if err := r.deleteNamespace(ctx, &namespace); err != nil {
+			return err
+		}
+	}
+	controllerutil.RemoveFinalizer(workshop, constants.WorkshopCleanupFinalizer)
+	return r.Update(ctx, workshop)
+}
+
+func (r *DevWorkspaceWorkshopReconciler) listWorkshopNamespaces(ctx context.Context, workshop *controllerv1alpha1.DevWorkspaceWorkshop) ([]corev1.Namespace, error) {
+	namespaceList := &corev1.NamespaceList{}
+	err := r.List(ctx, namespaceList, client.MatchingLabels{
+		constants.DevWorkspaceWorkshopLabel:          workshop.Name,
+		constants.DevWorkspaceWorkshopNamespaceLabel: workshop.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return namespaceList.Items, nil
+}
+
+func (r *DevWorkspaceWorkshopReconciler) deleteNamespace(ctx context.Context, namespace *corev1.Namespace) error {
+	if namespace.DeletionTimestamp != nil {
+		return nil
+	}
+	r.Log.Info("Deleting workshop namespace", "namespace", namespace.Name)
+	err := r.Delete(ctx, namespace, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getWorkshopStatus summarizes the status of all participants' DevWorkspaces. A participant is considered ready
+// once their DevWorkspace is running, or stopped if the workshop is not started.
+func getWorkshopStatus(participantStatuses []controllerv1alpha1.WorkshopParticipantStatus, started bool) controllerv1alpha1.DevWorkspaceWorkshopStatus {
+	readyPhase := dw.DevWorkspaceStatusRunning
+	if !started {
+		readyPhase = dw.DevWorkspaceStatusStopped
+	}
+	ready, failed := 0, 0
+	for _, participantStatus := range participantStatuses {
+		switch participantStatus.Phase {
+		case readyPhase:
+			ready++
+		case dw.DevWorkspaceStatusFailed:
+			failed++
+		}
+	}
+
+	status := controllerv1alpha1.DevWorkspaceWorkshopStatus{
+		Progress:     fmt.Sprintf("%d/%d", ready, len(participantStatuses)),
+		Participants: participantStatuses,
+	}
+	switch {
+	case failed > 0:
+		status.Phase = controllerv1alpha1.WorkshopPhaseDegraded
+		status.Message = fmt.Sprintf("%d participant DevWorkspaces have failed", failed)
+	case ready == len(participantStatuses):
+		status.Phase = controllerv1alpha1.WorkshopPhaseReady
+		status.Message = "All participant DevWorkspaces are ready"
+	default:
+		status.Phase = controllerv1alpha1.WorkshopPhaseProvisioning
+		status.Message = "Waiting for participant DevWorkspaces"
+	}
+	return status
+}
+
+func getParticipantLabels(workshop *controllerv1alpha1.DevWorkspaceWorkshop, participant string) map[string]string {
+	return map[string]string{
+		constants.DevWorkspaceWorkshopLabel:            workshop.Name,
+		constants.DevWorkspaceWorkshopNamespaceLabel:   workshop.Namespace,
+		constants.DevWorkspaceWorkshopParticipantLabel: participant,
+	}
+}
+
+func isParticipantNamespace(namespace *corev1.Namespace, workshop *controllerv1alpha1.DevWorkspaceWorkshop) bool {
+	return namespace.Labels[constants.DevWorkspaceWorkshopLabel] == workshop.Name &&
+		namespace.Labels[constants.DevWorkspaceWorkshopNamespaceLabel] == workshop.Namespace
+}
+
+// workshopForWorkspace enqueues a reconcile for the workshop that created a DevWorkspace, so that the workshop's
+// progress follows the phases of participants' DevWorkspaces.
+func (r *DevWorkspaceWorkshopReconciler) workshopForWorkspace(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	workshopName, workshopNamespace := labels[constants.DevWorkspaceWorkshopLabel], labels[constants.DevWorkspaceWorkshopNamespaceLabel]
+	if workshopName == "" || workshopNamespace == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: workshopName, Namespace: workshopNamespace}},
+	}
+}
+
+func (r *DevWorkspaceWorkshopReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	maxConcurrentReconciles, err := config.GetMaxConcurrentReconciles()
+	if err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
+		For(&controllerv1alpha1.DevWorkspaceWorkshop{}).
+		Watches(&source.Kind{Type: &dw.DevWorkspace{}}, handler.EnqueueRequestsFromMapFunc(r.workshopForWorkspace)).
+		Complete(r)
+}
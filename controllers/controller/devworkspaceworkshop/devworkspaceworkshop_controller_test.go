@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package devworkspaceworkshop
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const testNamespace = "instructor"
+
+func getTestWorkshop(participants ...string) *controllerv1alpha1.DevWorkspaceWorkshop {
+	return &controllerv1alpha1.DevWorkspaceWorkshop{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "intro",
+			Namespace: testNamespace,
+		},
+		Spec: controllerv1alpha1.DevWorkspaceWorkshopSpec{
+			Template: dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Components: []dw.Component{
+						{
+							Name: "tools",
+							ComponentUnion: dw.ComponentUnion{
+								Container: &dw.ContainerComponent{
+									Container: dw.Container{Image: "quay.io/devfile/universal-developer-image:latest"},
+								},
+							},
+						},
+					},
+				},
+			},
+			Participants: participants,
+		},
+	}
+}
+
+func getTestReconciler(objs ...client.Object) *DevWorkspaceWorkshopReconciler {
+	config.SetGlobalConfigForTesting(nil)
+	return &DevWorkspaceWorkshopReconciler{
+		Client: testutil.NewFakeClient(objs...),
+		Log:    zap.New(),
+		Scheme: testutil.Scheme,
+	}
+}
+
+func reconcileWorkshop(t *testing.T, r *DevWorkspaceWorkshopReconciler) *controllerv1alpha1.DevWorkspaceWorkshop {
+	workshopNN := types.NamespacedName{Name: "intro", Namespace: testNamespace}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: workshopNN})
+	if !assert.NoError(t, err, "Reconcile should not return error") {
+		t.FailNow()
+	}
+	workshop := &controllerv1alpha1.DevWorkspaceWorkshop{}
+	err = r.Get(context.Background(), workshopNN, workshop)
+	if k8sErrors.IsNotFound(err) {
+		return nil
+	}
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return workshop
+}
+
+func setWorkspacePhase(t *testing.T, r *DevWorkspaceWorkshopReconciler, namespace string, phase dw.DevWorkspacePhase) {
+	workspace := &dw.DevWorkspace{}
+	if !assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "intro", Namespace: namespace}, workspace)) {
+		t.FailNow()
+	}
+	workspace.Status.Phase = phase
+	if !assert.NoError(t, r.Status().Update(context.Background(), workspace)) {
+		t.FailNow()
+	}
+}
+
+func TestWorkshopProvisionsParticipants(t *testing.T) {
+	r := getTestReconciler(getTestWorkshop("alice", "bob"))
+	workshop := reconcileWorkshop(t, r)
+
+	assert.Contains(t, workshop.Finalizers, constants.WorkshopCleanupFinalizer)
+	assert.Equal(t, controllerv1alpha1.WorkshopPhaseProvisioning, workshop.Status.Phase)
+	assert.Equal(t, "0/2", workshop.Status.Progress)
+	if !assert.Len(t, workshop.Status.Participants, 2) {
+		return
+	}
+	for _, participant := range []string{"alice", "bob"} {
+		namespace := &corev1.Namespace{}
+		if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "intro-" + participant}, namespace)) {
+			assert.Equal(t, participant, namespace.Labels[constants.DevWorkspaceWorkshopParticipantLabel])
+		}
+		workspace := &dw.DevWorkspace{}
+		if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "intro", Namespace: "intro-" + participant}, workspace)) {
+			assert.True(t, workspace.Spec.Started, "Should start DevWorkspace by default")
+			assert.Len(t, workspace.Spec.Template.Components, 1)
+		}
+	}
+
+	setWorkspacePhase(t, r, "intro-alice", dw.DevWorkspaceStatusRunning)
+	workshop = reconcileWorkshop(t, r)
+	assert.Equal(t, "1/2", workshop.Status.Progress)
+	setWorkspacePhase(t, r, "intro-bob", dw.DevWorkspaceStatusRunning)
+	workshop = reconcileWorkshop(t, r)
+	assert.Equal(t, "2/2", workshop.Status.Progress)
+	assert.Equal(t, controllerv1alpha1.WorkshopPhaseReady, workshop.Status.Phase)
+}
+
+func TestWorkshopStopsParticipants(t *testing.T) {
+	r := getTestReconciler(getTestWorkshop("alice"))
+	workshop := reconcileWorkshop(t, r)
+	workshop.Spec.Started = pointer.Bool(false)
+	if !assert.NoError(t, r.Update(context.Background(), workshop)) {
+		return
+	}
+	reconcileWorkshop(t, r)
+
+	workspace := &dw.DevWorkspace{}
+	if assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "intro", Namespace: "intro-alice"}, workspace)) {
+		assert.False(t, workspace.Spec.Started, "Should stop DevWorkspace")
+	}
+	setWorkspacePhase(t, r, "intro-alice", dw.DevWorkspaceStatusStopped)
+	workshop = reconcileWorkshop(t, r)
+	assert.Equal(t, controllerv1alpha1.WorkshopPhaseReady, workshop.Status.Phase, "Stopped DevWorkspaces are ready when workshop is not started")
+}
+
+func TestWorkshopDegradedOnFailure(t *testing.T) {
+	r := getTestReconciler(getTestWorkshop("alice", "Not_Valid"))
+	workshop := reconcileWorkshop(t, r)
+	assert.Equal(t, controllerv1alpha1.WorkshopPhaseDegraded, workshop.Status.Phase)
+	if assert.Len(t, workshop.Status.Participants, 2) {
+		assert.Equal(t, dw.DevWorkspaceStatusFailed, workshop.Status.Participants[1].Phase)
+		assert.Empty(t, workshop.Status.Participants[1].Namespace)
+	}
+}
+
+func TestWorkshopDoesNotUseForeignNamespace(t *testing.T) {
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "intro-alice"}}
+	r := getTestReconciler(getTestWorkshop("alice"), existing)
+	workshop := reconcileWorkshop(t, r)
+	assert.Equal(t, controllerv1alpha1.WorkshopPhaseDegraded, workshop.Status.Phase)
+	err := r.Get(context.Background(), types.NamespacedName{Name: "intro", Namespace: "intro-alice"}, &dw.DevWorkspace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should not create DevWorkspace in namespace not owned by workshop")
+}
+
+func TestWorkshopTeardown(t *testing.T) {
+	r := getTestReconciler(getTestWorkshop("alice", "bob"))
+	workshop := reconcileWorkshop(t, r)
+
+	workshop.Spec.Participants = []string{"alice"}
+	if !assert.NoError(t, r.Update(context.Background(), workshop)) {
+		return
+	}
+	workshop = reconcileWorkshop(t, r)
+	assert.Equal(t, "0/1", workshop.Status.Progress)
+	err := r.Get(context.Background(), types.NamespacedName{Name: "intro-bob"}, &corev1.Namespace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete namespace of removed participant")
+
+	if !assert.NoError(t, r.Delete(context.Background(), workshop)) {
+		return
+	}
+	workshop = reconcileWorkshop(t, r)
+	assert.Nil(t, workshop, "Workshop should be deleted once finalized")
+	err = r.Get(context.Background(), types.NamespacedName{Name: "intro-alice"}, &corev1.Namespace{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete all participant namespaces")
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+)
+
+// setDigestsAnnotation records digests resolved while reconciling the DevWorkspace (e.g. for oci:// references or
+// container images) as a JSON object in the provided annotation. If resolved is empty, the annotation is removed.
+// Returns true if the annotations were changed.
+func setDigestsAnnotation(workspace *common.DevWorkspaceWithConfig, annotation string, pinned, resolved map[string]string) (changed bool, err error) {
+	if reflect.DeepEqual(pinned, resolved) {
+		return false, nil
+	}
+	if len(resolved) == 0 {
+		if _, ok := workspace.Annotations[annotation]; !ok {
+			return false, nil
+		}
+		delete(workspace.Annotations, annotation)
+		return true, nil
+	}
+	digestsJSON, err := json.Marshal(resolved)
+	if err != nil {
+		return false, err
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[annotation] = string(digestsJSON)
+	return true, nil
+}
+
+// setResolvedParentAnnotation records the parent that the DevWorkspace was resolved with in the DevWorkspace's
+// annotations. If the DevWorkspace has no parent, the annotation is removed. Returns true if the annotations were
+// changed.
+func setResolvedParentAnnotation(workspace *common.DevWorkspaceWithConfig, resolved *flatten.ResolvedParent) (changed bool, err error) {
+	existing, hasAnnotation := workspace.Annotations[constants.DevWorkspaceResolvedParentAnnotation]
+	if workspace.Spec.Template.Parent == nil {
+		if hasAnnotation {
+			delete(workspace.Annotations, constants.DevWorkspaceResolvedParentAnnotation)
+			return true, nil
+		}
+		return false, nil
+	}
+	resolvedJSON, err := json.Marshal(resolved)
+	if err != nil {
+		return false, err
+	}
+	if existing == string(resolvedJSON) {
+		return false, nil
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceResolvedParentAnnotation] = string(resolvedJSON)
+	return true, nil
+}
+
+// setObjectNamesAnnotation records the names generated from the current naming templates for the objects of the
+// DevWorkspace in its annotations, unless names are already recorded. Returns true if the annotations were changed.
+func setObjectNamesAnnotation(workspace *common.DevWorkspaceWithConfig, workspaceId string) (changed bool, err error) {
+	if _, ok := workspace.Annotations[constants.DevWorkspaceObjectNamesAnnotation]; ok {
+		return false, nil
+	}
+	namesJSON, err := json.Marshal(common.GenerateWorkspaceObjectNames(workspaceId))
+	if err != nil {
+		return false, err
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceObjectNamesAnnotation] = string(namesJSON)
+	return true, nil
+}
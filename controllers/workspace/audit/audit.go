@@ -0,0 +1,189 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package audit records DevWorkspace lifecycle events (creation, start, stop, failure and deletion) for compliance
+// reporting. Records are written to the operator's logs and optionally to a ConfigMap in the DevWorkspace's namespace
+// and to an external event sink, as configured in the global DevWorkspaceOperatorConfig.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/controllers/workspace/eventsink"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in each namespace that holds the audit records of the namespace's
+	// DevWorkspaces.
+	ConfigMapName = "devworkspace-audit-log"
+	// ConfigMapKey is the key in the audit ConfigMap that holds audit records, one JSON-encoded record per line.
+	ConfigMapKey = "audit.log"
+
+	// terminatingPhase mirrors the phase used by the DevWorkspace controller for DevWorkspaces that are being deleted.
+	terminatingPhase dw.DevWorkspacePhase = "Terminating"
+)
+
+// GetRecords returns the audit records for a DevWorkspace whose status changed from oldStatus to its current status.
+// Records are returned when a DevWorkspace is first reconciled (created), becomes ready (started), is stopped from
+// a starting or running state (stopped), fails (failed) and when it starts being deleted (deleted).
+func GetRecords(workspace *common.DevWorkspaceWithConfig, oldStatus *dw.DevWorkspaceStatus, now time.Time) []*eventsink.Event {
+	oldPhase, newPhase := oldStatus.Phase, workspace.Status.Phase
+	if oldPhase == newPhase {
+		return nil
+	}
+	var records []*eventsink.Event
+	if oldPhase == "" {
+		record := newRecord(workspace, eventsink.EventCreated, now)
+		record.User = workspace.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation]
+		records = append(records, record)
+	}
+
+	switch newPhase {
+	case dw.DevWorkspaceStatusRunning:
+		record := newRecord(workspace, eventsink.EventStarted, now)
+		record.User = workspace.Annotations[constants.DevWorkspaceLastActorAnnotation]
+		startedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.Started)
+		readyCondition := conditions.GetConditionByType(workspace.Status.Conditions, dw.DevWorkspaceReady)
+		if startedCondition != nil && readyCondition != nil {
+			record.Duration = readyCondition.LastTransitionTime.Sub(startedCondition.LastTransitionTime.Time).String()
+		}
+		records = append(records, record)
+	case dw.DevWorkspaceStatusStopping, dw.DevWorkspaceStatusStopped:
+		if oldPhase != dw.DevWorkspaceStatusStarting && oldPhase != dw.DevWorkspaceStatusRunning {
+			break
+		}
+		record := newRecord(workspace, eventsink.EventStopped, now)
+		record.User = workspace.Annotations[constants.DevWorkspaceLastActorAnnotation]
+		record.Message = workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation]
+		record.Duration = durationSinceCondition(oldStatus.Conditions, conditions.Started, now)
+		records = append(records, record)
+	case dw.DevWorkspaceStatusFailed:
+		record := newRecord(workspace, eventsink.EventFailed, now)
+		if failedCondition := conditions.GetConditionByType(workspace.Status.Conditions, dw.DevWorkspaceFailedStart); failedCondition != nil {
+			record.Message = failedCondition.Message
+		} else {
+			record.Message = workspace.Status.Message
+		}
+		records = append(records, record)
+	case terminatingPhase:
+		record := newRecord(workspace, eventsink.EventDeleted, now)
+		record.Duration = now.Sub(workspace.CreationTimestamp.Time).Round(time.Second).String()
+		records = append(records, record)
+	}
+	return records
+}
+
+// Log writes an audit record to the provided logger as a structured log entry.
+func Log(logger logr.Logger, record *eventsink.Event) {
+	keysAndValues := []interface{}{
+		"event", record.Type,
+		"workspace", record.Workspace.Name,
+		"namespace", record.Workspace.Namespace,
+		"devworkspaceId", record.Workspace.ID,
+	}
+	if record.User != "" {
+		keysAndValues = append(keysAndValues, "user", record.User)
+	}
+	if record.Duration != "" {
+		keysAndValues = append(keysAndValues, "duration", record.Duration)
+	}
+	if record.Message != "" {
+		keysAndValues = append(keysAndValues, "reason", record.Message)
+	}
+	logger.Info("DevWorkspace audit event", keysAndValues...)
+}
+
+// RecordInConfigMap appends an audit record to the audit ConfigMap in the DevWorkspace's namespace, creating the
+// ConfigMap if necessary. Only the latest maxEntries records are kept.
+func RecordInConfigMap(ctx context.Context, c client.Client, record *eventsink.Event, maxEntries int) error {
+	if maxEntries <= 0 {
+		return nil
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to serialize audit record: %w", err)
+	}
+	namespacedName := types.NamespacedName{Name: ConfigMapName, Namespace: record.Workspace.Namespace}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		err := c.Get(ctx, namespacedName, cm)
+		if k8sErrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ConfigMapName,
+					Namespace: record.Workspace.Namespace,
+					Labels:    constants.ControllerAppLabels(),
+				},
+				Data: map[string]string{
+					ConfigMapKey: string(line) + "\n",
+				},
+			}
+			return c.Create(ctx, cm)
+		} else if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[ConfigMapKey] = appendEntry(cm.Data[ConfigMapKey], string(line), maxEntries)
+		return c.Update(ctx, cm)
+	})
+}
+
+// appendEntry appends a line to newline-separated log content, dropping the oldest lines if there are more than
+// maxEntries.
+func appendEntry(content, line string, maxEntries int) string {
+	entries := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		entries = nil
+	}
+	entries = append(entries, line)
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+	return strings.Join(entries, "\n") + "\n"
+}
+
+func newRecord(workspace *common.DevWorkspaceWithConfig, eventType eventsink.EventType, now time.Time) *eventsink.Event {
+	return &eventsink.Event{
+		Type:      eventType,
+		Timestamp: now.UTC(),
+		Workspace: eventsink.GetWorkspaceInfo(workspace),
+	}
+}
+
+func durationSinceCondition(workspaceConditions []dw.DevWorkspaceCondition, conditionType dw.DevWorkspaceConditionType, now time.Time) string {
+	condition := conditions.GetConditionByType(workspaceConditions, conditionType)
+	if condition == nil || condition.LastTransitionTime.IsZero() {
+		return ""
+	}
+	return now.Sub(condition.LastTransitionTime.Time).Round(time.Second).String()
+}
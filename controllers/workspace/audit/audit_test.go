@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/controllers/workspace/eventsink"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+var testTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func getTestWorkspace(phase dw.DevWorkspacePhase, workspaceConditions ...dw.DevWorkspaceCondition) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", phase)
+	workspace.CreationTimestamp = metav1.NewTime(testTime.Add(-2 * time.Hour))
+	workspace.Annotations = map[string]string{
+		constants.DevWorkspaceCreatorUsernameAnnotation: "creator-user",
+		constants.DevWorkspaceLastActorAnnotation:       "last-actor",
+		constants.DevWorkspaceStopReasonAnnotation:      "inactivity",
+	}
+	workspace.Status.Message = "test message"
+	workspace.Status.Conditions = workspaceConditions
+	return workspace
+}
+
+func getCondition(conditionType dw.DevWorkspaceConditionType, transitionTime time.Time) dw.DevWorkspaceCondition {
+	return dw.DevWorkspaceCondition{
+		Type:               conditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(transitionTime),
+	}
+}
+
+func TestGetRecords(t *testing.T) {
+	started := getCondition(conditions.Started, testTime.Add(-time.Hour))
+	tests := []struct {
+		name      string
+		oldStatus dw.DevWorkspaceStatus
+		workspace *common.DevWorkspaceWithConfig
+		expected  []*eventsink.Event
+	}{
+		{
+			name:      "Records creation and start of new DevWorkspace",
+			oldStatus: dw.DevWorkspaceStatus{},
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusStarting, started),
+			expected:  []*eventsink.Event{{Type: eventsink.EventCreated, User: "creator-user"}},
+		},
+		{
+			name:      "Records startup duration when DevWorkspace is running",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusStarting},
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusRunning, started, getCondition(dw.DevWorkspaceReady, testTime.Add(-59*time.Minute))),
+			expected:  []*eventsink.Event{{Type: eventsink.EventStarted, User: "last-actor", Duration: "1m0s"}},
+		},
+		{
+			name:      "Records running duration and reason when DevWorkspace is stopped",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusRunning, Conditions: []dw.DevWorkspaceCondition{started}},
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusStopping),
+			expected:  []*eventsink.Event{{Type: eventsink.EventStopped, User: "last-actor", Duration: "1h0m0s", Message: "inactivity"}},
+		},
+		{
+			name:      "Does not record stop of DevWorkspace that was not running",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusStopping},
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusStopped),
+		},
+		{
+			name:      "Records failure reason",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusStarting},
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusFailed),
+			expected:  []*eventsink.Event{{Type: eventsink.EventFailed, Message: "test message"}},
+		},
+		{
+			name:      "Records lifetime of deleted DevWorkspace",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusStopped},
+			workspace: getTestWorkspace(terminatingPhase),
+			expected:  []*eventsink.Event{{Type: eventsink.EventDeleted, Duration: "2h0m0s"}},
+		},
+		{
+			name:      "Does not record anything if phase is unchanged",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusRunning},
+			workspace: getTestWorkspace(dw.DevWorkspaceStatusRunning),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := GetRecords(tt.workspace, &tt.oldStatus, testTime)
+			if !assert.Len(t, records, len(tt.expected)) {
+				return
+			}
+			for idx, record := range records {
+				expected := tt.expected[idx]
+				assert.Equal(t, expected.Type, record.Type)
+				assert.Equal(t, expected.User, record.User)
+				assert.Equal(t, expected.Duration, record.Duration)
+				assert.Equal(t, expected.Message, record.Message)
+				assert.Equal(t, testTime, record.Timestamp)
+				assert.Equal(t, "test-workspace", record.Workspace.Name)
+				assert.Equal(t, "test-workspaceid", record.Workspace.ID)
+			}
+		})
+	}
+}
+
+func TestRecordInConfigMapKeepsLatestEntries(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	for _, eventType := range []eventsink.EventType{eventsink.EventCreated, eventsink.EventStarted, eventsink.EventStopped} {
+		record := &eventsink.Event{
+			Type:      eventType,
+			Timestamp: testTime,
+			Workspace: eventsink.WorkspaceInfo{Name: "test-workspace", Namespace: "test-namespace"},
+		}
+		require.NoError(t, RecordInConfigMap(context.Background(), c, record, 2))
+	}
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: ConfigMapName, Namespace: "test-namespace"}, cm))
+	lines := strings.Split(strings.TrimSuffix(cm.Data[ConfigMapKey], "\n"), "\n")
+	require.Len(t, lines, 2, "Should only keep latest entries")
+	var eventTypes []eventsink.EventType
+	for _, line := range lines {
+		record := &eventsink.Event{}
+		require.NoError(t, json.Unmarshal([]byte(line), record))
+		eventTypes = append(eventTypes, record.Type)
+	}
+	assert.Equal(t, []eventsink.EventType{eventsink.EventStarted, eventsink.EventStopped}, eventTypes)
+}
+
+func TestRecordInConfigMapDisabled(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	record := &eventsink.Event{Type: eventsink.EventCreated, Workspace: eventsink.WorkspaceInfo{Namespace: "test-namespace"}}
+	require.NoError(t, RecordInConfigMap(context.Background(), c, record, 0))
+
+	err := c.Get(context.Background(), types.NamespacedName{Name: ConfigMapName, Namespace: "test-namespace"}, &corev1.ConfigMap{})
+	assert.Error(t, err, "Should not create ConfigMap when configMapEntries is 0")
+}
@@ -61,6 +61,10 @@ func (r *DevWorkspaceReconciler) deleteWorkspaceOwnedObjects(ctx context.Context
 		return false, err
 	}
 	for _, deploy := range deploymentList.Items {
+		if deploy.Name == common.BackgroundDeploymentName(workspace.Status.DevWorkspaceId) {
+			// Background deployment is removed separately once its idle timeout expires
+			continue
+		}
 		didDelete = true
 		if err := deleteObj(&deploy); err != nil {
 			return false, err
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// handleCloneRequest processes the DevWorkspaceCloneToAnnotation on a DevWorkspace by creating a stopped copy of the
+// DevWorkspace with the requested name. If the DevWorkspaceCloneStorageAnnotation is also set, the copy's persistent
+// storage is populated with the DevWorkspace's data when it is first started. The clone annotations are removed once
+// the copy has been created, or if it cannot be created because a DevWorkspace with the same name exists. Returns true
+// if the DevWorkspace was updated and should be reconciled again.
+func (r *DevWorkspaceReconciler) handleCloneRequest(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (updated bool, err error) {
+	cloneName, ok := workspace.Annotations[constants.DevWorkspaceCloneToAnnotation]
+	if !ok {
+		return false, nil
+	}
+	cloneStorage := workspace.Annotations[constants.DevWorkspaceCloneStorageAnnotation] == "true"
+
+	if cloneName != "" {
+		clone := getClonedWorkspace(workspace.DevWorkspace, cloneName, cloneStorage)
+		err := r.Create(ctx, clone)
+		switch {
+		case err == nil:
+			logger.Info("Cloned DevWorkspace", "clone", cloneName, "storage", cloneStorage)
+			r.recordCloneEvent(workspace.DevWorkspace, corev1.EventTypeNormal, fmt.Sprintf("Created DevWorkspace %s as a copy of this DevWorkspace", cloneName))
+		case k8sErrors.IsAlreadyExists(err):
+			logger.Info("Cannot clone DevWorkspace as a DevWorkspace with the same name already exists", "clone", cloneName)
+			r.recordCloneEvent(workspace.DevWorkspace, corev1.EventTypeWarning, fmt.Sprintf("Cannot clone DevWorkspace: DevWorkspace %s already exists", cloneName))
+		case k8sErrors.IsInvalid(err):
+			logger.Info("Cannot clone DevWorkspace", "clone", cloneName, "error", err.Error())
+			r.recordCloneEvent(workspace.DevWorkspace, corev1.EventTypeWarning, fmt.Sprintf("Cannot clone DevWorkspace: %s", err))
+		default:
+			return false, err
+		}
+	}
+
+	delete(workspace.Annotations, constants.DevWorkspaceCloneToAnnotation)
+	delete(workspace.Annotations, constants.DevWorkspaceCloneStorageAnnotation)
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *DevWorkspaceReconciler) recordCloneEvent(workspace *dw.DevWorkspace, eventType, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(workspace, eventType, "Clone", message)
+	}
+}
+
+// getClonedWorkspace returns a stopped copy of a DevWorkspace with the given name. Labels are copied from the source
+// DevWorkspace, along with the creator annotation; other annotations are managed by the controller or the user and
+// are not copied. If cloneStorage is true, the copy uses the CloneStorageFromAttribute to copy the source
+// DevWorkspace's persistent data when it is first started, instead of restoring a snapshot.
+func getClonedWorkspace(source *dw.DevWorkspace, name string, cloneStorage bool) *dw.DevWorkspace {
+	clone := &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: source.Namespace,
+			Labels:    map[string]string{},
+			Annotations: map[string]string{
+				constants.DevWorkspaceClonedFromAnnotation: source.Name,
+			},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	for key, value := range source.Labels {
+		clone.Labels[key] = value
+	}
+	delete(clone.Labels, constants.DevWorkspaceWarmPoolLabel)
+	if creator, ok := source.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation]; ok {
+		clone.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation] = creator
+	}
+	clone.Spec.Started = false
+
+	if cloneStorage {
+		if clone.Spec.Template.Attributes == nil {
+			clone.Spec.Template.Attributes = attributes.Attributes{}
+		}
+		delete(clone.Spec.Template.Attributes, constants.RestoreFromSnapshotAttribute)
+		clone.Spec.Template.Attributes.PutString(constants.CloneStorageFromAttribute, source.Name)
+	}
+	return clone
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getClone(t *testing.T, r *DevWorkspaceReconciler, name string) *dw.DevWorkspace {
+	clone := &dw.DevWorkspace{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "test-namespace"}, clone))
+	return clone
+}
+
+func TestHandleCloneRequest(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now)
+	workspace.Labels = map[string]string{constants.DevWorkspaceCreatorLabel: "test-creator"}
+	workspace.Annotations[constants.DevWorkspaceCloneToAnnotation] = "test-clone"
+	workspace.Annotations[constants.DevWorkspaceCloneStorageAnnotation] = "true"
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.RestoreFromSnapshotAttribute, "test-snapshot")
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.handleCloneRequest(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceCloneToAnnotation)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceCloneStorageAnnotation)
+	assert.True(t, clusterWorkspace.Spec.Started, "Should not stop source DevWorkspace")
+
+	clone := getClone(t, r, "test-clone")
+	assert.False(t, clone.Spec.Started, "Clone should be stopped")
+	assert.Equal(t, "test-creator", clone.Labels[constants.DevWorkspaceCreatorLabel])
+	assert.Equal(t, "test-workspace", clone.Annotations[constants.DevWorkspaceClonedFromAnnotation])
+	assert.NotContains(t, clone.Annotations, constants.DevWorkspaceStartedAtAnnotation, "Should not copy controller annotations")
+	assert.Equal(t, "test-workspace", clone.Spec.Template.Attributes.GetString(constants.CloneStorageFromAttribute, nil))
+	assert.False(t, clone.Spec.Template.Attributes.Exists(constants.RestoreFromSnapshotAttribute),
+		"Should not restore snapshot when storage is cloned")
+	assert.Empty(t, clone.Status, "Clone should not copy status")
+}
+
+func TestHandleCloneRequestWithoutStorage(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now)
+	workspace.Annotations[constants.DevWorkspaceCloneToAnnotation] = "test-clone"
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	_, err := r.handleCloneRequest(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+	clone := getClone(t, r, "test-clone")
+	assert.False(t, clone.Spec.Template.Attributes.Exists(constants.CloneStorageFromAttribute))
+}
+
+func TestHandleCloneRequestWhenCloneExists(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now)
+	workspace.Annotations[constants.DevWorkspaceCloneToAnnotation] = "test-clone"
+	existing := &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-clone",
+			Namespace: "test-namespace",
+		},
+	}
+	r := getIdleTestReconciler(workspace.DevWorkspace, existing)
+
+	updated, err := r.handleCloneRequest(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.NotContains(t, getClusterWorkspace(t, r).Annotations, constants.DevWorkspaceCloneToAnnotation)
+	assert.NotContains(t, getClone(t, r, "test-clone").Annotations, constants.DevWorkspaceClonedFromAnnotation,
+		"Should not modify existing DevWorkspace")
+}
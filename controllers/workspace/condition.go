@@ -27,11 +27,14 @@ import (
 var conditionOrder = []dw.DevWorkspaceConditionType{
 	conditions.Started,
 	conditions.DevWorkspaceResolved,
+	conditions.LimitsSatisfied,
+	conditions.StartAdmitted,
 	conditions.StorageReady,
 	dw.DevWorkspaceRoutingReady,
 	dw.DevWorkspaceServiceAccountReady,
 	conditions.PullSecretsReady,
 	conditions.KubeComponentsReady,
+	conditions.ImagesScanned,
 	conditions.DeploymentReady,
 	dw.DevWorkspaceReady,
 }
@@ -0,0 +1,50 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// getDebugStartMessage returns the message of the DebugStart condition for a DevWorkspace with the
+// DevWorkspaceDebugStartAnnotation, which lists the DevWorkspace's pods and their phases so that users can inspect
+// them, e.g. with "kubectl logs".
+func (r *DevWorkspaceReconciler) getDebugStartMessage(ctx context.Context, workspace *common.DevWorkspaceWithConfig) (string, error) {
+	podList := &corev1.PodList{}
+	if workspace.Status.DevWorkspaceId != "" {
+		if err := r.List(ctx, podList, client.InNamespace(workspace.Namespace), client.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}); err != nil {
+			return "", err
+		}
+	}
+	var pods []string
+	for _, pod := range podList.Items {
+		pods = append(pods, fmt.Sprintf("%s (%s)", pod.Name, pod.Status.Phase))
+	}
+	if len(pods) == 0 {
+		return "Debug mode enabled; DevWorkspace has no pods", nil
+	}
+	sort.Strings(pods)
+	return fmt.Sprintf("Debug mode enabled; DevWorkspace pods: %s", strings.Join(pods, ", ")), nil
+}
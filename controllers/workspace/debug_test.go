@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getDebugTestPod(name, workspaceId string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: workspaceId},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestGetDebugStartMessage(t *testing.T) {
+	workspace := getIdleTestWorkspace(time.Now())
+	r := getIdleTestReconciler(
+		getDebugTestPod("workspace-pod-b", "test-workspaceid", corev1.PodPending),
+		getDebugTestPod("workspace-pod-a", "test-workspaceid", corev1.PodFailed),
+		getDebugTestPod("other-workspace-pod", "other-workspaceid", corev1.PodRunning),
+	)
+
+	msg, err := r.getDebugStartMessage(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, "Debug mode enabled; DevWorkspace pods: workspace-pod-a (Failed), workspace-pod-b (Pending)", msg)
+}
+
+func TestGetDebugStartMessageWithoutPods(t *testing.T) {
+	workspace := getIdleTestWorkspace(time.Now())
+	r := getIdleTestReconciler()
+
+	msg, err := r.getDebugStartMessage(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, "Debug mode enabled; DevWorkspace has no pods", msg)
+}
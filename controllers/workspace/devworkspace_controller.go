@@ -17,6 +17,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -35,17 +36,26 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/library/annotate"
 	containerlib "github.com/devfile/devworkspace-operator/pkg/library/container"
 	"github.com/devfile/devworkspace-operator/pkg/library/env"
+	"github.com/devfile/devworkspace-operator/pkg/library/factory"
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
 	"github.com/devfile/devworkspace-operator/pkg/library/home"
+	"github.com/devfile/devworkspace-operator/pkg/library/imagedigest"
+	"github.com/devfile/devworkspace-operator/pkg/library/imagemirror"
+	"github.com/devfile/devworkspace-operator/pkg/library/imagescan"
 	kubesync "github.com/devfile/devworkspace-operator/pkg/library/kubernetes"
 	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	"github.com/devfile/devworkspace-operator/pkg/library/restrictions"
+	"github.com/devfile/devworkspace-operator/pkg/library/runningbudget"
+	"github.com/devfile/devworkspace-operator/pkg/library/runschedule"
 	"github.com/devfile/devworkspace-operator/pkg/library/status"
+	"github.com/devfile/devworkspace-operator/pkg/library/usage"
 	"github.com/devfile/devworkspace-operator/pkg/provision/automount"
 	"github.com/devfile/devworkspace-operator/pkg/provision/metadata"
 	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
 	"github.com/devfile/devworkspace-operator/pkg/provision/workspace/rbac"
+	"github.com/devfile/devworkspace-operator/pkg/shard"
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
 	appsv1 "k8s.io/api/apps/v1"
@@ -55,12 +65,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -79,6 +91,7 @@ type DevWorkspaceReconciler struct {
 	NonCachingClient client.Client
 	Log              logr.Logger
 	Scheme           *runtime.Scheme
+	Recorder         record.EventRecorder
 }
 
 /////// CRD-related RBAC roles
@@ -86,18 +99,33 @@ type DevWorkspaceReconciler struct {
 // +kubebuilder:rbac:groups=controller.devfile.io,resources=*,verbs=*
 /////// Required permissions for controller
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
-// +kubebuilder:rbac:groups=apps;extensions,resources=deployments;replicasets,verbs=*
+// +kubebuilder:rbac:groups=apps;extensions,resources=deployments;replicasets;statefulsets,verbs=*
 // +kubebuilder:rbac:groups="",resources=pods;serviceaccounts;secrets;configmaps;persistentvolumeclaims,verbs=*
 // +kubebuilder:rbac:groups="",resources=namespaces;events,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=list;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get
 // +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;create;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews;localsubjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=oauth.openshift.io,resources=oauthclients,verbs=get;list;watch;create;update;patch;delete;deletecollection
-// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=podgroups,verbs=get;create;update
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get,resourceNames=cluster
 // +kubebuilder:rbac:groups=apps,resourceNames=devworkspace-controller,resources=deployments/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;create;update;delete
+// +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams;imagestreamtags,verbs=get
+// +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams,verbs=create
+// +kubebuilder:rbac:groups=build.openshift.io,resources=buildconfigs,verbs=get;create;delete
+// +kubebuilder:rbac:groups=build.openshift.io,resources=builds,verbs=get
+// +kubebuilder:rbac:groups=build.openshift.io,resources=builds/docker,verbs=create
+// +kubebuilder:rbac:groups=image.openshift.io,resources=imagestreams/layers,verbs=get
 /////// Required permissions for workspace ServiceAccount
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups=apps;extensions,resources=replicasets,verbs=get;list;watch
@@ -116,6 +144,13 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		Ctx:              ctx,
 	}
 
+	// When running as multiple shards, only reconcile DevWorkspaces in namespaces assigned to this shard
+	if ownsNamespace, err := shard.OwnsNamespace(ctx, r.Client, req.Namespace); err != nil {
+		return reconcile.Result{}, err
+	} else if !ownsNamespace {
+		return reconcile.Result{}, nil
+	}
+
 	// Fetch the Workspace instance
 	rawWorkspace := &dw.DevWorkspace{}
 	err = r.Get(ctx, req.NamespacedName, rawWorkspace)
@@ -162,6 +197,13 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			workspace.Status.Message = fmt.Sprintf("Failed to set DevWorkspace ID: %s", err.Error())
 			return reconcile.Result{}, r.Status().Update(ctx, workspace.DevWorkspace)
 		}
+		// Names generated from naming templates are recorded before the ID is assigned, so that changes to the
+		// templates do not rename objects of existing workspaces.
+		if changed, err := setObjectNamesAnnotation(workspace, workspaceId); err != nil {
+			return reconcile.Result{}, err
+		} else if changed {
+			return reconcile.Result{Requeue: true}, r.Update(ctx, workspace.DevWorkspace)
+		}
 		workspace.Status.DevWorkspaceId = workspaceId
 		err = r.Status().Update(ctx, workspace.DevWorkspace)
 		return reconcile.Result{Requeue: true}, err
@@ -169,6 +211,11 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// Stop failed workspaces
 	if workspace.Status.Phase == devworkspacePhaseFailing && workspace.Spec.Started {
+		// Workspaces that failed due to transient issues are restarted instead, if configured
+		if retrying, result, err := r.retryFailedStart(ctx, workspace, reqLogger); retrying {
+			return result, err
+		}
+
 		// If debug annotation is present, leave the deployment in place to let users
 		// view logs.
 		if workspace.Annotations[constants.DevWorkspaceDebugStartAnnotation] == "true" {
@@ -189,16 +236,34 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if updated, err := r.handleRestartRequest(ctx, workspace, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	} else if updated {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	if updated, err := r.handleCloneRequest(ctx, workspace, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	} else if updated {
+		return reconcile.Result{Requeue: true}, nil
+	}
+
 	// Handle stopped workspaces
 	if !workspace.Spec.Started {
 		r.removeStartedAtFromCluster(ctx, workspace, reqLogger)
+		r.removeStartRetriesFromCluster(ctx, workspace, reqLogger)
+		r.removeStandbyNodeFromCluster(ctx, workspace, reqLogger)
+		r.removeEnvironmentFromCluster(ctx, workspace, reqLogger)
 		return r.stopWorkspace(ctx, workspace, reqLogger)
 	}
 
 	// If this is the first reconcile for a starting workspace, mark it as starting now. This is done outside the regular
 	// updateWorkspaceStatus function to ensure it gets set immediately
 	if workspace.Status.Phase != dw.DevWorkspaceStatusStarting && workspace.Status.Phase != dw.DevWorkspaceStatusRunning {
+		r.removeStartupDiagnosticsFromCluster(ctx, workspace, reqLogger)
+		r.syncStandbyNodeToCluster(ctx, workspace, reqLogger)
 		// Set 'Started' condition as early as possible to get accurate timing metrics
+		oldStatus := workspace.Status.DeepCopy()
 		workspace.Status.Phase = dw.DevWorkspaceStatusStarting
 		workspace.Status.Message = "Initializing DevWorkspace"
 		workspace.Status.Conditions = []dw.DevWorkspaceCondition{
@@ -212,6 +277,8 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		err = r.Status().Update(ctx, workspace.DevWorkspace)
 		if err == nil {
 			metrics.WorkspaceStarted(workspace, reqLogger)
+			r.sendLifecycleEvent(workspace, oldStatus, reqLogger)
+			r.recordAuditEvents(workspace, oldStatus)
 		}
 		return reconcile.Result{}, err
 	}
@@ -219,6 +286,9 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	// Prepare handling workspace status and condition
 	reconcileStatus.phase = dw.DevWorkspaceStatusStarting
 	reconcileStatus.setConditionTrue(conditions.Started, "DevWorkspace is starting")
+	if retries := getStartRetries(workspace); retries > 0 {
+		reconcileStatus.setConditionTrue(conditions.StartRetried, fmt.Sprintf("DevWorkspace start was retried %d times after transient failures", retries))
+	}
 	clusterWorkspace := &common.DevWorkspaceWithConfig{}
 	clusterWorkspace.DevWorkspace = workspace.DevWorkspace.DeepCopy()
 	clusterWorkspace.Config = workspace.Config
@@ -236,13 +306,35 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 				} else {
 					reconcileResult = r.failWorkspace(workspace, timeoutErr.Error(), metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
 				}
+			} else if phaseTimeoutErr := checkForPhaseTimeout(clusterWorkspace); phaseTimeoutErr != nil {
+				reconcileResult = r.failWorkspace(workspace, phaseTimeoutErr.Error(), metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus)
+			}
+		}
+		if clusterWorkspace.Annotations[constants.DevWorkspaceDebugStartAnnotation] == "true" {
+			if debugMsg, debugErr := r.getDebugStartMessage(ctx, clusterWorkspace); debugErr != nil {
+				reqLogger.Info("Failed to list pods for DevWorkspace debug condition", "error", debugErr.Error())
+			} else {
+				reconcileStatus.setConditionTrue(conditions.DebugStart, debugMsg)
+			}
+		}
+		if reconcileStatus.phase == devworkspacePhaseFailing {
+			// Collect details on why the workspace failed so that users don't have to inspect the workspace's pods
+			diagnostics, diagErr := status.GetStartupDiagnostics(clusterWorkspace, clusterAPI)
+			if diagErr != nil {
+				reqLogger.Info("Failed to collect startup diagnostics for DevWorkspace", "error", diagErr.Error())
+			} else if diagnostics != "" {
+				reconcileStatus.setConditionTrue(conditions.StartupDiagnostics, diagnostics)
+				defer r.syncStartupDiagnosticsToCluster(ctx, clusterWorkspace, diagnostics, reqLogger)
 			}
 		}
 		if reconcileStatus.phase == dw.DevWorkspaceStatusRunning {
 			// defer to set the startedAt annotation after the status and metrics are updated,
 			// since WorkspaceStarted and WorkspaceRunning metrics are not updated if this annotation exists
 			defer r.syncStartedAtToCluster(ctx, clusterWorkspace, reqLogger)
+			defer r.syncReconciledVersionToCluster(ctx, clusterWorkspace, &workspace.Spec.Template, reqLogger)
 		}
+		// defer to store the explanation after the status is updated, so that it reflects the final conditions
+		defer r.syncExplanationToCluster(ctx, clusterWorkspace, clusterAPI, reqLogger)
 
 		return r.updateWorkspaceStatus(clusterWorkspace, reqLogger, &reconcileStatus, reconcileResult, err)
 	}()
@@ -260,13 +352,115 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcile.Result{Requeue: true}, err
 	}
 
+	if remainingBudget, hasBudget, budgetErr := getRemainingRunningBudget(clusterWorkspace.DevWorkspace); budgetErr != nil {
+		reqLogger.Error(budgetErr, "Failed to check running budget for DevWorkspace")
+	} else if hasBudget && remainingBudget <= 0 {
+		reqLogger.Info("Stopping DevWorkspace that exceeded its weekly running budget")
+		if clusterWorkspace.Annotations == nil {
+			clusterWorkspace.Annotations = map[string]string{}
+		}
+		clusterWorkspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = runningbudget.StopReason
+		clusterWorkspace.Spec.Started = false
+		err = r.Update(ctx, clusterWorkspace.DevWorkspace)
+		return reconcile.Result{Requeue: true}, err
+	} else if hasBudget {
+		// Make sure the DevWorkspace is reconciled again once its budget runs out
+		defer capRequeueAfter(&reconcileResult, &err, remainingBudget)
+	}
+
+	if updated, recheckAfter, maintenanceErr := r.checkMaintenance(ctx, clusterWorkspace, reqLogger); maintenanceErr != nil || updated {
+		return reconcile.Result{Requeue: true}, maintenanceErr
+	} else if recheckAfter > 0 {
+		// Make sure the DevWorkspace is reconciled again when it is scheduled to be stopped for maintenance
+		defer capRequeueAfter(&reconcileResult, &err, recheckAfter)
+	}
+
+	if stopReason, nextStop, scheduleErr := checkRunSchedule(clusterWorkspace.DevWorkspace); scheduleErr != nil {
+		reqLogger.Error(scheduleErr, "Failed to check run schedule for DevWorkspace")
+	} else if stopReason != "" {
+		reqLogger.Info("Stopping DevWorkspace according to run schedule", "stopped-by", stopReason)
+		if clusterWorkspace.Annotations == nil {
+			clusterWorkspace.Annotations = map[string]string{}
+		}
+		clusterWorkspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = stopReason
+		clusterWorkspace.Spec.Started = false
+		err = r.Update(ctx, clusterWorkspace.DevWorkspace)
+		return reconcile.Result{Requeue: true}, err
+	} else if nextStop > 0 {
+		// Make sure the DevWorkspace is reconciled again when it is scheduled to stop
+		defer capRequeueAfter(&reconcileResult, &err, nextStop)
+	}
+
+	if updated, headlessErr := r.syncHeadlessRun(ctx, clusterWorkspace, reqLogger); headlessErr != nil || updated {
+		return reconcile.Result{Requeue: true}, headlessErr
+	}
+
+	if workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+		updated, recheckAfter, idleErr := r.checkIdleStop(ctx, clusterWorkspace, reqLogger)
+		if idleErr != nil || updated {
+			return reconcile.Result{Requeue: true}, idleErr
+		}
+		if recheckAfter > 0 {
+			// Make sure the DevWorkspace is reconciled again once it needs to be warned or stopped for inactivity
+			defer capRequeueAfter(&reconcileResult, &err, recheckAfter)
+		}
+	}
+
+	if updated, err := r.syncWorkspaceEnvironment(workspace, clusterWorkspace, clusterAPI, reqLogger); err != nil {
+		return reconcile.Result{}, err
+	} else if updated {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	// Make sure running DevWorkspaces are checked for changes in proxy configuration and certificates
+	environmentCheckInterval := wkspConfig.GetEnvironmentCheckInterval(workspace.Config.Workspace.EnvironmentUpdates)
+	defer capRequeueAfter(&reconcileResult, &err, environmentCheckInterval)
+
+	if factory.NeedsResolution(clusterWorkspace.DevWorkspace) {
+		hasDefaultTemplate := workspace.Config.Workspace.DefaultTemplate != nil
+		factoryURL := clusterWorkspace.Annotations[constants.DevWorkspaceFactoryURLAnnotation]
+		token, err := factory.GetTokenForURL(ctx, r.Client, workspace.Namespace, factoryURL)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := factory.ResolveFromURL(clusterWorkspace.DevWorkspace, hasDefaultTemplate, httpClient, token); err != nil {
+			if retryErr, ok := err.(*dwerrors.RetryError); ok {
+				reqLogger.Info(retryErr.Error(), "requeueAfter", retryErr.RequeueAfter)
+				reconcileStatus.setConditionFalse(conditions.Started, "Waiting to resolve devfile from repository")
+				return reconcile.Result{RequeueAfter: retryErr.RequeueAfter}, nil
+			}
+			return r.failWorkspace(workspace, fmt.Sprintf("Failed to resolve devfile from repository: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
+		}
+		reqLogger.Info("Resolved DevWorkspace from factory URL", "devfile", clusterWorkspace.Annotations[constants.DevWorkspaceFactoryResolvedAnnotation])
+		err = r.Update(ctx, clusterWorkspace.DevWorkspace)
+		return reconcile.Result{Requeue: true}, err
+	}
+
 	flattenHelpers := flatten.ResolverTools{
 		WorkspaceNamespace:          workspace.Namespace,
 		Context:                     ctx,
 		K8sClient:                   r.Client,
 		HttpClient:                  httpClient,
 		DefaultResourceRequirements: workspace.Config.Workspace.DefaultContainerResources,
+		RegistryCache:               pluginRegistryCache,
+	}
+	if registryConfig := workspace.Config.Workspace.PluginRegistry; registryConfig != nil {
+		flattenHelpers.DefaultRegistryURL = registryConfig.DefaultRegistryURL
+		cacheTTL, err := time.ParseDuration(registryConfig.CacheTTL)
+		if err != nil {
+			reqLogger.Error(err, "Failed to parse plugin registry cache TTL; plugins will not be cached", "cacheTTL", registryConfig.CacheTTL)
+		}
+		flattenHelpers.RegistryCacheTTL = cacheTTL
+	}
+	ociClient, err := r.getOCIClient(ctx, workspace.Namespace)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
+	pinnedOCIDigests := getPinnedOCIDigests(clusterWorkspace, reqLogger)
+	flattenHelpers.OCIClient = ociClient
+	flattenHelpers.PinnedOCIDigests = pinnedOCIDigests
+	flattenHelpers.ResolvedOCIDigests = map[string]string{}
+	flattenHelpers.SCMClient = httpClient
+	flattenHelpers.ResolvedParent = &flatten.ResolvedParent{}
 
 	if wsDefaults.NeedsDefaultTemplate(workspace) {
 		wsDefaults.ApplyDefaultTemplate(workspace)
@@ -274,14 +468,32 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	flattenedWorkspace, warnings, err := flatten.ResolveDevWorkspace(&workspace.Spec.Template, workspace.Spec.Contributions, flattenHelpers)
 	if err != nil {
+		var retryErr *dwerrors.RetryError
+		if errors.As(err, &retryErr) {
+			reqLogger.Info(retryErr.Error(), "requeueAfter", retryErr.RequeueAfter)
+			reconcileStatus.setConditionFalse(conditions.DevWorkspaceResolved, "Waiting to resolve plugins and parents")
+			return reconcile.Result{RequeueAfter: retryErr.RequeueAfter}, nil
+		}
 		return r.failWorkspace(workspace, fmt.Sprintf("Error processing devfile: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
 	}
 	if warnings != nil {
 		reconcileStatus.addWarning(flatten.FormatVariablesWarning(warnings))
 	}
+	ociDigestsChanged, err := setDigestsAnnotation(clusterWorkspace, constants.DevWorkspaceOCIDigestsAnnotation, pinnedOCIDigests, flattenHelpers.ResolvedOCIDigests)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	parentChanged, err := setResolvedParentAnnotation(clusterWorkspace, flattenHelpers.ResolvedParent)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if ociDigestsChanged || parentChanged {
+		err = r.Update(ctx, clusterWorkspace.DevWorkspace)
+		return reconcile.Result{Requeue: true}, err
+	}
 	workspace.Spec.Template = *flattenedWorkspace
 
-	if workspace.Config.EnableExperimentalFeatures != nil && *workspace.Config.EnableExperimentalFeatures {
+	if wkspConfig.IsFeatureEnabledForWorkspace(workspace, wkspConfig.SSHAgentPostStart) {
 		if needsSSHAgentPostStartEvent, err := ssh.NeedsSSHPostStartEvent(clusterAPI, workspace.Namespace); err != nil {
 			reqLogger.Error(err, "Error retrieving SSH secret")
 		} else if needsSSHAgentPostStartEvent {
@@ -302,6 +514,15 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 
+	// Plugins and parents are not checked by the webhook, so check the flattened workspace for disabled devfile features
+	if err := restrictions.CheckDisabledFeatures(&workspace.Spec.Template, workspace.Config.Workspace.DisabledDevfileFeatures); err != nil {
+		return r.failWorkspace(workspace, err.Error(), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
+	}
+
+	if err := checkNamespaceScopedSupport(workspace); err != nil {
+		return r.failWorkspace(workspace, err.Error(), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
+	}
+
 	storageProvisioner, err := storage.GetProvisioner(workspace)
 	if err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Error provisioning storage: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
@@ -318,13 +539,37 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// Set finalizer on DevWorkspace if necessary
 	// Note: we need to check the flattened workspace to see if a finalizer is needed, as plugins could require storage
-	if storageProvisioner.NeedsStorage(&workspace.Spec.Template) && !controllerutil.ContainsFinalizer(clusterWorkspace, constants.StorageCleanupFinalizer) {
+	// If the workspace will be moved to the trash when deleted, a finalizer is needed for all storage types.
+	needsStorageFinalizer := storageProvisioner.NeedsStorage(&workspace.Spec.Template) ||
+		(storage.ShouldMoveToTrash(workspace) && storage.WorkspaceNeedsStorage(&workspace.Spec.Template))
+	if needsStorageFinalizer && !controllerutil.ContainsFinalizer(clusterWorkspace, constants.StorageCleanupFinalizer) {
 		controllerutil.AddFinalizer(clusterWorkspace, constants.StorageCleanupFinalizer)
 		if err := r.Update(ctx, clusterWorkspace.DevWorkspace); err != nil {
 			return reconcile.Result{}, err
 		}
 	}
 
+	// Build images of devfile image components and use them in container components that refer to them
+	err = wsprovision.BuildImageComponents(workspace, clusterAPI, httpClient)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to build image components", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
+	// Replace images of container components that refer to ImageStreamTags with internal registry pullspecs
+	err = wsprovision.ResolveImageStreamTags(workspace, clusterAPI)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to resolve ImageStreamTags", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
+	err = wsprovision.SyncNamespacePodSecurityLabels(workspace, clusterAPI)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to label namespace for Pod Security Admission", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
+	if wkspConfig.IsFeatureEnabledForWorkspace(workspace, wkspConfig.RestrictedSecurityContext) {
+		workspace.Config.Workspace.ContainerSecurityContext = wkspConfig.GetRestrictedContainerSecurityContext(workspace.Config.Workspace.ContainerSecurityContext)
+	}
+
 	devfilePodAdditions, err := containerlib.GetKubeContainersFromDevfile(
 		&workspace.Spec.Template,
 		workspace.Config.Workspace.ContainerSecurityContext,
@@ -333,16 +578,57 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Error processing devfile: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
 	}
+	// With graceful stop, preStop commands are run by the controller before the pod is stopped instead of as hooks
+	if isGracefulStopEnabled(workspace) {
+		removePreStopLifecycleHooks(devfilePodAdditions.Containers)
+	}
 
 	// Add common environment variables and env vars defined via workspaceEnv attribute
 	if err := env.AddCommonEnvironmentVariables(devfilePodAdditions, clusterWorkspace, &workspace.Spec.Template); err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Failed to process workspace environment variables: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
 	}
 
+	if limits := workspace.Config.Workspace.Limits; hasLimits(limits) {
+		if msg := checkWorkspaceResourceLimits(limits, devfilePodAdditions); msg != "" {
+			reconcileStatus.setConditionFalse(conditions.LimitsSatisfied, msg)
+			return r.failWorkspace(workspace, msg, metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
+		}
+		msg, err := r.checkRunningWorkspaceLimits(ctx, workspace)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if msg != "" {
+			if limits.ExceededPolicy == limitsRejectPolicy {
+				reconcileStatus.setConditionFalse(conditions.LimitsSatisfied, msg)
+				return r.failWorkspace(workspace, msg, metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
+			}
+			reqLogger.Info("Queueing DevWorkspace start", "reason", msg)
+			reconcileStatus.setConditionFalse(conditions.LimitsSatisfied, fmt.Sprintf("Waiting for other DevWorkspaces to stop: %s", msg))
+			return reconcile.Result{RequeueAfter: queuedWorkspaceRequeueInterval}, nil
+		}
+		reconcileStatus.setConditionTrue(conditions.LimitsSatisfied, "DevWorkspace is within configured limits")
+	}
+
+	if startQueue := wkspConfig.GetGlobalConfig().Workspace.StartQueue; startQueue != nil && startQueue.MaxConcurrentStarts != nil {
+		msg, err := r.checkStartQueue(ctx, workspace, startQueue)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if msg != "" {
+			reconcileStatus.setConditionFalse(conditions.StartAdmitted, msg)
+			return reconcile.Result{RequeueAfter: startQueueRequeueInterval}, nil
+		}
+		reconcileStatus.setConditionTrue(conditions.StartAdmitted, "DevWorkspace start admitted")
+	}
+
 	// Validate that projects, dependentProjects, and starterProjects do not collide
 	if err := projects.ValidateAllProjects(&workspace.Spec.Template); err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Invalid devfile: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
 	}
+	if projects.UsesDefaultStarterProject(&workspace.Spec.Template) && len(workspace.Spec.Template.StarterProjects) > 1 {
+		reconcileStatus.addWarning(fmt.Sprintf("Info: no starter project selected via the %s attribute; using starter project %s",
+			constants.StarterProjectAttribute, workspace.Spec.Template.StarterProjects[0].Name))
+	}
 	// Add init container to clone projects
 	projectCloneOptions := projects.Options{
 		Image:     workspace.Config.Workspace.ProjectCloneConfig.Image,
@@ -360,11 +646,29 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		devfilePodAdditions.InitContainers = append(devfilePodAdditions.InitContainers, *projectClone)
 	}
 
+	// Add containers to back up and restore projects, if enabled
+	err = wsprovision.ProvisionProjectBackup(workspace, devfilePodAdditions, clusterAPI)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to set up project backup", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
+	// Add init container to install dotfiles into the persistent home directory, if enabled
+	err = wsprovision.ProvisionDotfiles(workspace, devfilePodAdditions, projectCloneOptions.Env, clusterAPI)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to set up dotfiles", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
 	// Add ServiceAccount tokens into devfile containers
 	if err := wsprovision.ProvisionServiceAccountTokensInto(devfilePodAdditions, workspace); err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Failed to mount ServiceAccount tokens to workspace: %s", err), metrics.ReasonBadRequest, reqLogger, &reconcileStatus), nil
 	}
 
+	// Add Kubernetes API proxy sidecar, which should be the only container with access to the ServiceAccount token
+	err = wsprovision.ProvisionKubernetesAPIProxyInto(devfilePodAdditions, workspace)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to set up Kubernetes API proxy", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
 	// Add SSH ask-pass script into devfile containers
 	if err := wsprovision.ProvisionSshAskPass(clusterAPI, workspace.Namespace, devfilePodAdditions); err != nil {
 		return r.failWorkspace(workspace, fmt.Sprintf("Failed to mount SSH askpass script to workspace: %s", err), metrics.ReasonWorkspaceEngineFailure, reqLogger, &reconcileStatus), nil
@@ -376,12 +680,22 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return reconcileResult, reconcileErr
 	}
 
+	// Add SSH keys from secrets labeled as SSH keys into devfile containers
+	err = automount.ProvisionSSHKeysInto(devfilePodAdditions, clusterAPI, workspace.Namespace, workspace.Config.Workspace.SSH, home.PersistUserHomeEnabled(workspace))
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to mount SSH keys", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
 	err = storageProvisioner.ProvisionStorage(devfilePodAdditions, workspace, clusterAPI)
 	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error provisioning storage", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
 		reconcileStatus.setConditionFalse(conditions.StorageReady, fmt.Sprintf("Provisioning storage: %s", err.Error()))
 		return reconcileResult, reconcileErr
 	}
-	reconcileStatus.setConditionTrue(conditions.StorageReady, "Storage ready")
+	if storage.GetStorageType(workspace) == constants.EphemeralStorageClassType {
+		reconcileStatus.setConditionTrue(conditions.StorageReady, "Storage ready (ephemeral: data is not persisted when the workspace is stopped)")
+	} else {
+		reconcileStatus.setConditionTrue(conditions.StorageReady, "Storage ready")
+	}
 
 	// Add finalizer to ensure workspace rolebinding gets cleaned up when workspace
 	// is deleted.
@@ -391,11 +705,28 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return reconcile.Result{}, err
 		}
 	}
+	// Add finalizers for hooks that must succeed before the workspace is removed when it is deleted.
+	if addFinalizerHookFinalizers(clusterWorkspace) {
+		if err := r.Update(ctx, clusterWorkspace.DevWorkspace); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 	err = rbac.SyncRBAC(workspace, clusterAPI)
 	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error provisioning rbac", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
 		return reconcileResult, reconcileErr
 	}
 
+	// Apply endpoint exposure changes before provisioning networking, so that they take effect without a restart
+	err = wsprovision.ApplyEndpointExposureOverrides(workspace)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to apply endpoint exposure", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
+	err = wsprovision.SyncNetworkPolicy(workspace, clusterAPI)
+	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error provisioning network policy", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+		return reconcileResult, reconcileErr
+	}
+
 	// Step two: Create routing, and wait for routing to be ready
 	routingPodAdditions, exposedEndpoints, statusMsg, err := wsprovision.SyncRoutingToCluster(workspace, clusterAPI)
 	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Failed to set up networking for workspace", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
@@ -426,6 +757,8 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	// Step four: Collect all workspace deployment contributions
+	// Containers that run in the background are provisioned in a separate deployment that is not stopped with the workspace
+	backgroundPodAdditions := wsprovision.SplitBackgroundContainers(&workspace.Spec.Template, devfilePodAdditions)
 	allPodAdditions := []controllerv1alpha1.PodAdditions{*devfilePodAdditions}
 	if routingPodAdditions != nil {
 		allPodAdditions = append(allPodAdditions, *routingPodAdditions)
@@ -477,15 +810,128 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	if kubesync.HasKubelikeComponent(workspace) {
-		err := kubesync.HandleKubernetesComponents(workspace, clusterAPI)
+		kubeComponentObjs, err := kubesync.HandleKubernetesComponents(workspace, clusterAPI, httpClient)
 		if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error provisioning workspace Kubernetes components", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
 			reconcileStatus.setConditionFalse(conditions.KubeComponentsReady, "Waiting for DevWorkspace Kubernetes components to be created on cluster")
 			return reconcileResult, reconcileErr
 		}
+		err = kubesync.CheckKubernetesComponentsReady(kubeComponentObjs)
+		if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error waiting for workspace Kubernetes components", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+			reconcileStatus.setConditionFalse(conditions.KubeComponentsReady, err.Error())
+			return reconcileResult, reconcileErr
+		}
 		reconcileStatus.setConditionTrue(conditions.KubeComponentsReady, "Kubernetes components ready")
 	}
 
+	if len(workspace.Config.Workspace.ImageMirrors) > 0 {
+		mirroredPodAdditions := []*controllerv1alpha1.PodAdditions{backgroundPodAdditions}
+		for idx := range allPodAdditions {
+			mirroredPodAdditions = append(mirroredPodAdditions, &allPodAdditions[idx])
+		}
+		imagemirror.RewriteImages(mirroredPodAdditions, workspace.Config.Workspace.ImageMirrors)
+	}
+
+	if imagedigest.IsEnabled(workspace) {
+		pinnedPodAdditions := []*controllerv1alpha1.PodAdditions{backgroundPodAdditions}
+		for idx := range allPodAdditions {
+			pinnedPodAdditions = append(pinnedPodAdditions, &allPodAdditions[idx])
+		}
+		pinnedDigests, err := imagedigest.GetPinnedDigests(clusterWorkspace.DevWorkspace)
+		if err != nil {
+			reqLogger.Info(fmt.Sprintf("Ignoring recorded image digests: %s", err))
+		}
+		imageDigests, warnings := imagedigest.PinImages(ctx, pinnedPodAdditions, pinnedDigests, ociClient)
+		for _, warning := range warnings {
+			reconcileStatus.addWarning(warning)
+		}
+		changed, err := setDigestsAnnotation(clusterWorkspace, constants.DevWorkspaceImageDigestsAnnotation, pinnedDigests, imageDigests)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if changed {
+			reqLogger.Info("Recording resolved image digests")
+			err = r.Update(ctx, clusterWorkspace.DevWorkspace)
+			return reconcile.Result{Requeue: true}, err
+		}
+	}
+
+	if imageScanning := workspace.Config.Workspace.ImageScanning; imageScanning != nil && imageScanning.Scanner != "" {
+		if workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+			// Images are only scanned to gate startup, so that running DevWorkspaces are not stopped when new
+			// vulnerabilities are published. Keep the result of the scan done before the DevWorkspace started.
+			if scannedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.ImagesScanned); scannedCondition != nil {
+				reconcileStatus.setCondition(conditions.ImagesScanned, *scannedCondition)
+			}
+		} else {
+			scannedPodAdditions := append([]controllerv1alpha1.PodAdditions{}, allPodAdditions...)
+			if backgroundPodAdditions != nil {
+				scannedPodAdditions = append(scannedPodAdditions, *backgroundPodAdditions)
+			}
+			msg, err := r.checkWorkspaceImages(ctx, imagescan.GetImages(scannedPodAdditions...), imageScanning)
+			if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Image vulnerability scan failed", metrics.ReasonBadRequest, reqLogger, &reconcileStatus); shouldReturn {
+				reconcileStatus.setConditionFalse(conditions.ImagesScanned, "Waiting for image vulnerability scan results")
+				return reconcileResult, reconcileErr
+			}
+			reconcileStatus.setConditionTrue(conditions.ImagesScanned, msg)
+		}
+	}
+
+	recoveryActions, recoveryRequeueAfter, recoveryErr := wsprovision.RecoverFromNodeFailure(workspace, clusterAPI)
+	for _, action := range recoveryActions {
+		reqLogger.Info(action)
+		r.Recorder.Event(clusterWorkspace.DevWorkspace, corev1.EventTypeWarning, "NodeFailureRecovery", action)
+	}
+	if recoveryErr != nil {
+		reqLogger.Error(recoveryErr, "Failed to recover DevWorkspace from node failure")
+	} else if len(recoveryActions) > 0 {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	if recoveryRequeueAfter > 0 {
+		// Pods stuck on unavailable nodes do not get updated, so we need to check them again once the
+		// termination timeout has passed
+		defer capRequeueAfter(&reconcileResult, &err, recoveryRequeueAfter)
+	}
+
+	if resourcesMsg, err := status.CheckPodsForResourceFailures(workspace, clusterAPI); err != nil {
+		reqLogger.Error(err, "Failed to check workspace pods for resource failures")
+	} else if resourcesMsg != "" {
+		reconcileStatus.setConditionTrue(conditions.InsufficientResources, resourcesMsg)
+	}
+
+	if projectsCloned, cloneMsg, err := status.CheckProjectCloneStatus(workspace, clusterAPI); err != nil {
+		reqLogger.Error(err, "Failed to check project clone status")
+	} else if cloneMsg != "" {
+		if projectsCloned {
+			reconcileStatus.setConditionTrue(conditions.ProjectsCloned, cloneMsg)
+		} else {
+			reconcileStatus.setConditionFalse(conditions.ProjectsCloned, cloneMsg)
+		}
+	}
+
+	if eventsSucceeded, eventsMsg, err := status.CheckLifecycleEventsStatus(workspace, clusterAPI); err != nil {
+		reqLogger.Error(err, "Failed to check lifecycle event commands status")
+	} else if eventsMsg != "" {
+		if eventsSucceeded {
+			reconcileStatus.setConditionTrue(conditions.LifecycleEvents, eventsMsg)
+		} else {
+			reconcileStatus.setConditionFalse(conditions.LifecycleEvents, eventsMsg)
+		}
+	}
+
 	// Step six: Create deployment and wait for it to be ready
+	// With gang scheduling, pods in a PodGroup are only scheduled once all of them have been created, so the background
+	// deployment can't wait for the workspace deployment to be ready
+	gangScheduled := wsprovision.NeedsPodGroup(workspace)
+	if gangScheduled {
+		err = wsprovision.SyncPodGroupToCluster(workspace, clusterAPI)
+		if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error creating DevWorkspace PodGroup", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+			return reconcileResult, reconcileErr
+		}
+		err = wsprovision.SyncBackgroundDeploymentToCluster(workspace, backgroundPodAdditions, pullSecretPodAdditions, serviceAcctName, clusterAPI)
+		if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error creating DevWorkspace background deployment", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+			return reconcileResult, reconcileErr
+		}
+	}
 	if err := wsprovision.SyncDeploymentToCluster(workspace, allPodAdditions, serviceAcctName, clusterAPI); err != nil {
 		if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error creating DevWorkspace deployment", metrics.DetermineProvisioningFailureReason(err.Error()), reqLogger, &reconcileStatus); shouldReturn {
 			reqLogger.Info("Waiting on deployment to be ready")
@@ -494,6 +940,19 @@ func (r *DevWorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		}
 	}
 	reconcileStatus.setConditionTrue(conditions.DeploymentReady, "DevWorkspace deployment ready")
+	if _, ok := workspace.Annotations[constants.DevWorkspaceStandbyNodeAnnotation]; ok {
+		// Keep the standby pod running until the workspace's pod is scheduled to avoid releasing the node
+		if err := wsprovision.DeleteStandbyDeploymentIfScheduled(workspace, clusterAPI); err != nil {
+			reqLogger.Error(err, "Failed to clean up standby deployment for DevWorkspace")
+		}
+	}
+
+	if !gangScheduled {
+		err = wsprovision.SyncBackgroundDeploymentToCluster(workspace, backgroundPodAdditions, pullSecretPodAdditions, serviceAcctName, clusterAPI)
+		if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error creating DevWorkspace background deployment", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
+			return reconcileResult, reconcileErr
+		}
+	}
 
 	serverReady, serverStatusCode, err := checkServerStatus(clusterWorkspace)
 	if shouldReturn, reconcileResult, reconcileErr := r.checkDWError(workspace, err, "Error checking server status", metrics.ReasonInfrastructureFailure, reqLogger, &reconcileStatus); shouldReturn {
@@ -520,6 +979,35 @@ func (r *DevWorkspaceReconciler) stopWorkspace(ctx context.Context, workspace *c
 		if failedCondition != nil {
 			status.setCondition(dw.DevWorkspaceFailedStart, *failedCondition)
 		}
+		diagnosticsCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.StartupDiagnostics)
+		if diagnosticsCondition != nil {
+			status.setCondition(conditions.StartupDiagnostics, *diagnosticsCondition)
+		}
+		retriedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.StartRetried)
+		if retriedCondition != nil {
+			status.setCondition(conditions.StartRetried, *retriedCondition)
+		}
+		resourcesCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.InsufficientResources)
+		if resourcesCondition != nil {
+			status.setCondition(conditions.InsufficientResources, *resourcesCondition)
+		}
+	}
+
+	if preStopCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.PreStopEvents); preStopCondition != nil {
+		status.setCondition(conditions.PreStopEvents, *preStopCondition)
+	}
+	// Commands bound to preStop events are run before the workspace's pod is stopped
+	if _, pending := workspace.Annotations[constants.DevWorkspacePreStopPendingAnnotation]; pending {
+		finished, err := r.syncPreStopTasks(ctx, workspace, &status, logger)
+		if err != nil {
+			if !k8sErrors.IsConflict(err) {
+				logger.Error(err, "Failed to run preStop commands for DevWorkspace")
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+		if !finished {
+			return r.updateWorkspaceStatus(workspace, logger, &status, reconcile.Result{RequeueAfter: preStopRequeueAfter}, nil)
+		}
 	}
 
 	stopped, err := r.doStop(ctx, workspace, logger)
@@ -539,8 +1027,41 @@ func (r *DevWorkspaceReconciler) stopWorkspace(ctx context.Context, workspace *c
 	}
 	if stoppedBy, ok := workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation]; ok {
 		logger.Info("Workspace stopped with reason", "stopped-by", stoppedBy)
+		if stoppedBy == runningbudget.StopReason {
+			status.setConditionTrue(conditions.RunningBudgetExceeded, getRunningBudgetExceededMessage(workspace.DevWorkspace))
+		}
+		if stoppedBy == runschedule.StopReason || stoppedBy == runschedule.BlackoutStopReason {
+			status.setConditionTrue(conditions.StoppedBySchedule, getStoppedByScheduleMessage(workspace.DevWorkspace, stoppedBy))
+		}
+		if stoppedBy == constants.DevWorkspaceStoppedByMaintenance {
+			status.setConditionTrue(conditions.StoppedForMaintenance, getStoppedForMaintenanceMessage())
+		}
+		if stoppedBy == constants.DevWorkspaceStoppedByHeadlessRun {
+			status.setConditionTrue(conditions.HeadlessRunCompleted, workspace.Annotations[constants.DevWorkspaceHeadlessResultAnnotation])
+		}
+	}
+
+	// Background components keep running after the workspace is stopped until their idle timeout expires
+	requeueAfter, err := wsprovision.StopIdleBackgroundDeployment(ctx, workspace, r.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	// Commands bound to postStop events are run once the workspace's pod has stopped
+	if stopped {
+		if err := r.syncPostStopTasks(ctx, workspace, &status, logger); err != nil {
+			if !k8sErrors.IsConflict(err) {
+				logger.Error(err, "Failed to run postStop commands for DevWorkspace")
+			}
+			return reconcile.Result{Requeue: true}, nil
+		}
+	}
+	// Standby components keep running at minimal resources after the workspace is idled, until the standby timeout expires
+	if stopped {
+		if standbyRequeueAfter := r.syncStandbyDeployment(ctx, workspace, logger); standbyRequeueAfter > 0 && (requeueAfter == 0 || standbyRequeueAfter < requeueAfter) {
+			requeueAfter = standbyRequeueAfter
+		}
 	}
-	return r.updateWorkspaceStatus(workspace, logger, &status, reconcile.Result{}, nil)
+	return r.updateWorkspaceStatus(workspace, logger, &status, reconcile.Result{RequeueAfter: requeueAfter}, nil)
 }
 
 func (r *DevWorkspaceReconciler) doStop(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (stopped bool, err error) {
@@ -553,7 +1074,7 @@ func (r *DevWorkspaceReconciler) doStop(ctx context.Context, workspace *common.D
 	// Update DevWorkspaceRouting to have `devworkspace-started` annotation "false"
 	routing := &controllerv1alpha1.DevWorkspaceRouting{}
 	routingNN := types.NamespacedName{
-		Name:      common.DevWorkspaceRoutingName(workspace.Status.DevWorkspaceId),
+		Name:      common.DevWorkspaceRoutingName(workspace.Status.DevWorkspaceId, workspace.Annotations),
 		Namespace: workspace.Namespace,
 	}
 	err = r.Get(ctx, routingNN, routing)
@@ -572,19 +1093,13 @@ func (r *DevWorkspaceReconciler) doStop(ctx context.Context, workspace *common.D
 		}
 	}
 
-	workspaceDeployment := &appsv1.Deployment{}
-	deployNN := types.NamespacedName{
-		Name:      common.DeploymentName(workspace.Status.DevWorkspaceId),
-		Namespace: workspace.Namespace,
-	}
-	err = r.Get(ctx, deployNN, workspaceDeployment)
+	replicas, currentReplicas, found, err := wsprovision.GetWorkspaceReplicas(ctx, workspace, r.Client)
 	if err != nil {
-		if k8sErrors.IsNotFound(err) {
-			return true, nil
-		}
 		return false, err
 	}
-	replicas := workspaceDeployment.Spec.Replicas
+	if !found {
+		return true, nil
+	}
 	if replicas == nil || *replicas > 0 {
 		logger.Info("Stopping workspace")
 		err = wsprovision.ScaleDeploymentToZero(ctx, workspace, r.Client)
@@ -593,7 +1108,7 @@ func (r *DevWorkspaceReconciler) doStop(ctx context.Context, workspace *common.D
 		}
 		return false, nil
 	}
-	return workspaceDeployment.Status.Replicas == 0, nil
+	return currentReplicas == 0, nil
 }
 
 // failWorkspace marks a workspace as failed by setting relevant fields in the status struct.
@@ -609,6 +1124,31 @@ func (r *DevWorkspaceReconciler) failWorkspace(workspace *common.DevWorkspaceWit
 	return reconcile.Result{}
 }
 
+// checkWorkspaceImages retrieves vulnerability reports for workspace images from the configured scanner and applies
+// the image scanning policy. See imagescan.CheckImages for details on the returned errors.
+func (r *DevWorkspaceReconciler) checkWorkspaceImages(ctx context.Context, images []string, config *controllerv1alpha1.ImageScanningConfig) (string, error) {
+	token, err := imagescan.GetToken(ctx, r.NonCachingClient, config)
+	if err != nil {
+		return "", &dwerrors.FailError{Message: "Invalid image scanning configuration", Err: err}
+	}
+	scanner, err := imagescan.NewScanner(config, httpClient, token)
+	if err != nil {
+		return "", &dwerrors.FailError{Message: "Invalid image scanning configuration", Err: err}
+	}
+	return imagescan.CheckImages(images, config, scanner)
+}
+
+// capRequeueAfter makes sure a successful reconcile is requeued within requeueAfter. It is meant to be deferred in
+// Reconcile, so that the result and error are the ones Reconcile returns.
+func capRequeueAfter(result *reconcile.Result, err *error, requeueAfter time.Duration) {
+	if *err != nil || result.Requeue {
+		return
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > requeueAfter {
+		result.RequeueAfter = requeueAfter
+	}
+}
+
 func (r *DevWorkspaceReconciler) checkDWError(workspace *common.DevWorkspaceWithConfig, err error, failHint string, reason metrics.FailureReason, logger logr.Logger, status *currentStatus) (shouldReturn bool, res reconcile.Result, returnErr error) {
 	if err == nil {
 		return false, reconcile.Result{}, nil
@@ -659,6 +1199,28 @@ func (r *DevWorkspaceReconciler) removeStartedAtFromCluster(
 		return
 	}
 
+	// Commands bound to postStop events are run once the workspace has stopped
+	if events := workspace.Spec.Template.Events; events != nil && len(events.PostStop) > 0 {
+		workspace.Annotations[constants.DevWorkspacePostStopPendingAnnotation] = workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]
+	}
+	// Commands bound to preStop events are run before the workspace's pod is stopped, if graceful stop is enabled
+	if events := workspace.Spec.Template.Events; events != nil && len(events.PreStop) > 0 &&
+		isGracefulStopEnabled(workspace) && workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+		workspace.Annotations[constants.DevWorkspacePreStopPendingAnnotation] = workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]
+	}
+
+	// Record the time the workspace ran for before the started-at annotation is lost
+	if _, hasBudget, err := runningbudget.GetBudget(workspace.DevWorkspace, wkspConfig.GetGlobalConfig().Workspace.RunningBudget); err == nil && hasBudget {
+		if err := runningbudget.RecordRun(workspace.DevWorkspace, clock.Now()); err != nil {
+			reqLogger.Error(err, "Failed to record running budget usage for devworkspace")
+		}
+	}
+
+	var monthlyUsage map[string]usage.Usage
+	if isUsageAccountingEnabled() {
+		monthlyUsage = r.recordRunUsage(ctx, workspace, reqLogger)
+	}
+
 	delete(workspace.Annotations, constants.DevWorkspaceStartedAtAnnotation)
 	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
 		if k8sErrors.IsConflict(err) {
@@ -666,6 +1228,64 @@ func (r *DevWorkspaceReconciler) removeStartedAtFromCluster(
 		} else {
 			reqLogger.Error(err, "Error trying to apply timing annotations to devworkspace")
 		}
+		return
+	}
+	if len(monthlyUsage) > 0 {
+		r.syncUsageToConfigMap(ctx, workspace, monthlyUsage, reqLogger)
+	}
+}
+
+// syncStartupDiagnosticsToCluster stores the summary of startup diagnostics for a failed workspace in the
+// DevWorkspaceStartupDiagnosticsAnnotation annotation.
+func (r *DevWorkspaceReconciler) syncStartupDiagnosticsToCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, diagnostics string, reqLogger logr.Logger) {
+
+	if workspace.Annotations[constants.DevWorkspaceStartupDiagnosticsAnnotation] == diagnostics {
+		return
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+
+	workspace.Annotations[constants.DevWorkspaceStartupDiagnosticsAnnotation] = diagnostics
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to apply startup diagnostics annotation to workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to apply startup diagnostics annotation to devworkspace")
+		}
+	}
+}
+
+func (r *DevWorkspaceReconciler) removeStartupDiagnosticsFromCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, reqLogger logr.Logger) {
+	if _, ok := workspace.Annotations[constants.DevWorkspaceStartupDiagnosticsAnnotation]; !ok {
+		return
+	}
+
+	delete(workspace.Annotations, constants.DevWorkspaceStartupDiagnosticsAnnotation)
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to remove startup diagnostics annotation from workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to remove startup diagnostics annotation from devworkspace")
+		}
+	}
+}
+
+func (r *DevWorkspaceReconciler) removeStartRetriesFromCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, reqLogger logr.Logger) {
+	if _, ok := workspace.Annotations[constants.DevWorkspaceStartRetriesAnnotation]; !ok {
+		return
+	}
+
+	delete(workspace.Annotations, constants.DevWorkspaceStartRetriesAnnotation)
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to remove start retries annotation from workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to remove start retries annotation from devworkspace")
+		}
 	}
 }
 
@@ -695,6 +1315,9 @@ func (r *DevWorkspaceReconciler) getWorkspaceId(ctx context.Context, workspace *
 
 func (r *DevWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	setupHttpClients(mgr.GetClient(), mgr.GetLogger())
+	if r.Recorder == nil {
+		r.Recorder = mgr.GetEventRecorderFor("devworkspace-controller")
+	}
 
 	maxConcurrentReconciles, err := wkspConfig.GetMaxConcurrentReconciles()
 	if err != nil {
@@ -705,11 +1328,26 @@ func (r *DevWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return []reconcile.Request{}
 	}
 
+	// Running DevWorkspaces created by older operator versions are reconciled again through migration events
+	migrationEvents := make(chan event.GenericEvent)
+	nonCachingClient := r.NonCachingClient
+	if nonCachingClient == nil {
+		nonCachingClient = r.Client
+	}
+	if err := mgr.Add(&workspaceMigrator{
+		client:           mgr.GetClient(),
+		nonCachingClient: nonCachingClient,
+		events:           migrationEvents,
+		log:              r.Log.WithName("migration"),
+	}); err != nil {
+		return err
+	}
+
 	configWatcher := builder.WithPredicates(wkspConfig.Predicates())
 	automountWatcher := builder.WithPredicates(automountPredicates)
 
 	// TODO: Set up indexing https://book.kubebuilder.io/cronjob-tutorial/controller-implementation.html#setup
-	return ctrl.NewControllerManagedBy(mgr).
+	bld := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		For(&dw.DevWorkspace{}).
 		// List DevWorkspaceTemplates as owned to enable updating workspaces when templates
@@ -717,8 +1355,10 @@ func (r *DevWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// DevWorkspaces
 		Owns(&dw.DevWorkspaceTemplate{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{}).
 		Owns(&controllerv1alpha1.DevWorkspaceRouting{}).
+		Owns(&controllerv1alpha1.DevWorkspaceTask{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ServiceAccount{}).
@@ -728,7 +1368,13 @@ func (r *DevWorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.runningWorkspacesHandler), automountWatcher).
 		Watches(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, handler.EnqueueRequestsFromMapFunc(r.runningWorkspacesHandler), automountWatcher).
 		Watches(&source.Kind{Type: &controllerv1alpha1.DevWorkspaceOperatorConfig{}}, handler.EnqueueRequestsFromMapFunc(emptyMapper), configWatcher).
+		Watches(&source.Channel{Source: migrationEvents}, &handler.EnqueueRequestForObject{}).
 		WithEventFilter(devworkspacePredicates).
-		WithEventFilter(podPredicates).
-		Complete(r)
+		WithEventFilter(podPredicates)
+	if shard.IsEnabled() {
+		// Reconcile DevWorkspaces in a namespace when it is moved to a different shard, so that the new shard
+		// picks them up
+		bld = bld.Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(r.namespaceWorkspacesHandler), builder.WithPredicates(shard.NamespacePredicates()))
+	}
+	return bld.Complete(r)
 }
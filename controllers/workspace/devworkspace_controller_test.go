@@ -210,7 +210,7 @@ var _ = Describe("DevWorkspace Controller", func() {
 
 			By("Checking that DevWorkspaceRouting is created")
 			dwr := &controllerv1alpha1.DevWorkspaceRouting{}
-			dwrName := common.DevWorkspaceRoutingName(workspaceID)
+			dwrName := common.DevWorkspaceRoutingName(workspaceID, nil)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, namespacedName(dwrName, testNamespace), dwr)
 			}, timeout, interval).Should(Succeed(), "DevWorkspaceRouting is present on cluster")
@@ -226,7 +226,7 @@ var _ = Describe("DevWorkspace Controller", func() {
 			workspaceID := devworkspace.Status.DevWorkspaceId
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			Eventually(func() (string, error) {
 				if err := k8sClient.Get(ctx, namespacedName(devWorkspaceName, testNamespace), devworkspace); err != nil {
@@ -242,7 +242,7 @@ var _ = Describe("DevWorkspace Controller", func() {
 			workspaceID := devworkspace.Status.DevWorkspaceId
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			metadataCM := &corev1.ConfigMap{}
 			Eventually(func() error {
@@ -269,7 +269,7 @@ var _ = Describe("DevWorkspace Controller", func() {
 			workspaceID := devworkspace.Status.DevWorkspaceId
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			sa := &corev1.ServiceAccount{}
 			Eventually(func() error {
@@ -288,11 +288,11 @@ var _ = Describe("DevWorkspace Controller", func() {
 			workspaceID := devworkspace.Status.DevWorkspaceId
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			deploy := &appsv1.Deployment{}
 			Eventually(func() error {
-				deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+				deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Should create DevWorkspace Deployment")
 
@@ -316,10 +316,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 				},
 			})
 			By("Manually making Routing ready to continue")
-			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			By("Setting the deployment to have 1 ready replica")
-			markDeploymentReady(common.DeploymentName(workspaceID))
+			markDeploymentReady(common.DeploymentName(workspaceID, nil))
 
 			currDW := &dw.DevWorkspace{}
 			Eventually(func() (dw.DevWorkspacePhase, error) {
@@ -381,10 +381,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 			defer deleteObject(dockerCfgJson)
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			deploy := &appsv1.Deployment{}
-			deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+			deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Getting workspace deployment from cluster")
@@ -408,10 +408,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 			defer deleteObject(gitCredentials)
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			deploy := &appsv1.Deployment{}
-			deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+			deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Getting workspace deployment from cluster")
@@ -492,10 +492,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 			defer deleteObject(subpathSecret)
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			deploy := &appsv1.Deployment{}
-			deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+			deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Getting workspace deployment from cluster")
@@ -538,9 +538,9 @@ var _ = Describe("DevWorkspace Controller", func() {
 			defer deleteObject(secret)
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID, nil))
 			deploy := &appsv1.Deployment{}
-			deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+			deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Getting workspace deployment from cluster")
@@ -588,7 +588,7 @@ var _ = Describe("DevWorkspace Controller", func() {
 			By("Checking that workspace deployment mounts merged credentials secret")
 			Eventually(func() error {
 				deploy := &appsv1.Deployment{}
-				deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+				deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 				if err := k8sClient.Get(ctx, deployNN, deploy); err != nil {
 					return err
 				}
@@ -637,10 +637,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 			workspaceID := devworkspace.Status.DevWorkspaceId
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			deploy := &appsv1.Deployment{}
-			deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+			deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Getting workspace deployment from cluster")
@@ -670,10 +670,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 			workspaceID := devworkspace.Status.DevWorkspaceId
 
 			By("Manually making Routing ready to continue")
-			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady(testURL, common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			deploy := &appsv1.Deployment{}
-			deployNN := namespacedName(common.DeploymentName(workspaceID), testNamespace)
+			deployNN := namespacedName(common.DeploymentName(workspaceID, nil), testNamespace)
 			Eventually(func() error {
 				return k8sClient.Get(ctx, deployNN, deploy)
 			}, timeout, interval).Should(Succeed(), "Getting workspace deployment from cluster")
@@ -715,7 +715,7 @@ var _ = Describe("DevWorkspace Controller", func() {
 			By("Adds devworkspace-started annotation to false on DevWorkspaceRouting")
 			Eventually(func() (string, error) {
 				dwr := &controllerv1alpha1.DevWorkspaceRouting{}
-				if err := k8sClient.Get(ctx, namespacedName(common.DevWorkspaceRoutingName(devworkspace.Status.DevWorkspaceId), testNamespace), dwr); err != nil {
+				if err := k8sClient.Get(ctx, namespacedName(common.DevWorkspaceRoutingName(devworkspace.Status.DevWorkspaceId, nil), testNamespace), dwr); err != nil {
 					return "", err
 				}
 				annotation, ok := dwr.Annotations[constants.DevWorkspaceStartedStatusAnnotation]
@@ -728,14 +728,14 @@ var _ = Describe("DevWorkspace Controller", func() {
 			By("Checking that workspace deployment is scaled to zero")
 			Eventually(func() (replicas int32, err error) {
 				deploy := &appsv1.Deployment{}
-				if err := k8sClient.Get(ctx, namespacedName(common.DeploymentName(devworkspace.Status.DevWorkspaceId), testNamespace), deploy); err != nil {
+				if err := k8sClient.Get(ctx, namespacedName(common.DeploymentName(devworkspace.Status.DevWorkspaceId, nil), testNamespace), deploy); err != nil {
 					return -1, err
 				}
 				return *deploy.Spec.Replicas, nil
 			}, timeout, interval).Should(Equal(int32(0)), "Workspace deployment was not scaled to zero")
 
 			By("Setting DevWorkspace's deployment replicas to zero")
-			scaleDeploymentToZero(common.DeploymentName(devworkspace.Status.DevWorkspaceId))
+			scaleDeploymentToZero(common.DeploymentName(devworkspace.Status.DevWorkspaceId, nil))
 
 			currDW := &dw.DevWorkspace{}
 			Eventually(func() (dw.DevWorkspacePhase, error) {
@@ -773,9 +773,9 @@ var _ = Describe("DevWorkspace Controller", func() {
 
 			By("Checking that workspace owned objects are deleted")
 			objects := []client.Object{
-				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: common.DeploymentName(workspaceId)}},
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: common.DeploymentName(workspaceId, nil)}},
 				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.MetadataConfigMapName(workspaceId)}},
-				&controllerv1alpha1.DevWorkspaceRouting{ObjectMeta: metav1.ObjectMeta{Name: common.DevWorkspaceRoutingName(workspaceId)}},
+				&controllerv1alpha1.DevWorkspaceRouting{ObjectMeta: metav1.ObjectMeta{Name: common.DevWorkspaceRoutingName(workspaceId, nil)}},
 			}
 			for _, obj := range objects {
 				Eventually(func() error {
@@ -1027,10 +1027,10 @@ var _ = Describe("DevWorkspace Controller", func() {
 				},
 			})
 			By("Manually making Routing ready to continue")
-			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+			markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 			By("Setting the deployment to have 1 ready replica")
-			markDeploymentReady(common.DeploymentName(workspaceID))
+			markDeploymentReady(common.DeploymentName(workspaceID, nil))
 
 			currDW := &dw.DevWorkspace{}
 			Eventually(func() (dw.DevWorkspacePhase, error) {
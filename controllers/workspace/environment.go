@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+const environmentOutdatedMessage = "Proxy configuration or trusted CA certificates changed since the DevWorkspace was started; restart the DevWorkspace to apply them"
+
+// syncWorkspaceEnvironment records the proxy configuration and trusted CA certificates used by a starting DevWorkspace,
+// and applies the configured EnvironmentUpdatePolicy to running DevWorkspaces whose environment has changed since:
+//
+// - With the "Restart" policy, the recorded environment is updated, which restarts the DevWorkspace's pod.
+//
+// - Otherwise, the DevWorkspace keeps using the recorded proxy configuration and is marked as outdated.
+//
+// Returns whether the DevWorkspace was updated on the cluster, in which case it should be reconciled again.
+func (r *DevWorkspaceReconciler) syncWorkspaceEnvironment(
+	workspace, clusterWorkspace *common.DevWorkspaceWithConfig,
+	clusterAPI sync.ClusterAPI, reqLogger logr.Logger) (updated bool, err error) {
+
+	current, err := wsprovision.GetCurrentEnvironment(workspace, clusterAPI)
+	if err != nil {
+		return false, err
+	}
+	recorded, err := wsprovision.GetRecordedEnvironment(workspace.DevWorkspace)
+	if err != nil {
+		reqLogger.Error(err, "Ignoring invalid recorded environment for DevWorkspace")
+		recorded = nil
+	}
+
+	policy := getEnvironmentUpdatePolicy(workspace.Config.Workspace.EnvironmentUpdates)
+	if recorded == nil || (recorded.Hash != current.Hash && (policy == controllerv1alpha1.EnvironmentUpdateRestart || workspace.Status.Phase != dw.DevWorkspaceStatusRunning)) {
+		if recorded != nil && workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+			reqLogger.Info("Restarting DevWorkspace to apply updated proxy configuration and trusted CA certificates")
+			r.Recorder.Event(clusterWorkspace.DevWorkspace, corev1.EventTypeNormal, "EnvironmentUpdated", "Restarting DevWorkspace to apply updated proxy configuration and trusted CA certificates")
+		}
+		if err := wsprovision.RecordEnvironment(clusterWorkspace.DevWorkspace, current); err != nil {
+			return false, err
+		}
+		delete(clusterWorkspace.Annotations, constants.DevWorkspaceEnvironmentOutdatedAnnotation)
+		return true, r.Update(clusterAPI.Ctx, clusterWorkspace.DevWorkspace)
+	}
+
+	// Keep the proxy configuration the DevWorkspace was started with until it is restarted, as changing it would
+	// restart the DevWorkspace's pod
+	if workspace.Config.Routing != nil {
+		workspace.Config.Routing.ProxyConfig = recorded.Proxy
+	}
+	if recorded.Hash == current.Hash || clusterWorkspace.Annotations[constants.DevWorkspaceEnvironmentOutdatedAnnotation] == "true" {
+		return false, nil
+	}
+	reqLogger.Info("Proxy configuration or trusted CA certificates changed since DevWorkspace was started")
+	clusterWorkspace.Annotations[constants.DevWorkspaceEnvironmentOutdatedAnnotation] = "true"
+	if policy == controllerv1alpha1.EnvironmentUpdateNotify {
+		r.Recorder.Event(clusterWorkspace.DevWorkspace, corev1.EventTypeWarning, "EnvironmentOutdated", environmentOutdatedMessage)
+	}
+	return true, r.Update(clusterAPI.Ctx, clusterWorkspace.DevWorkspace)
+}
+
+func getEnvironmentUpdatePolicy(envConfig *controllerv1alpha1.EnvironmentUpdatesConfig) controllerv1alpha1.EnvironmentUpdatePolicy {
+	if envConfig == nil || envConfig.Policy == "" {
+		return controllerv1alpha1.EnvironmentUpdateAnnotateOutdated
+	}
+	return envConfig.Policy
+}
+
+func (r *DevWorkspaceReconciler) removeEnvironmentFromCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, reqLogger logr.Logger) {
+	_, hasEnvironment := workspace.Annotations[constants.DevWorkspaceEnvironmentAnnotation]
+	_, hasOutdated := workspace.Annotations[constants.DevWorkspaceEnvironmentOutdatedAnnotation]
+	if !hasEnvironment && !hasOutdated {
+		return
+	}
+
+	delete(workspace.Annotations, constants.DevWorkspaceEnvironmentAnnotation)
+	delete(workspace.Annotations, constants.DevWorkspaceEnvironmentOutdatedAnnotation)
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to remove environment annotations from workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to remove environment annotations from devworkspace")
+		}
+	}
+}
@@ -90,6 +90,9 @@ func (r *DevWorkspaceReconciler) dwPVCHandler(obj client.Object) []reconcile.Req
 
 	for _, workspace := range dwList.Items {
 		storageType := workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil)
+		if storageType == "" {
+			storageType = wkspConfig.GetGlobalConfig().Workspace.DefaultStorageType
+		}
 		if storageType == constants.CommonStorageClassType || storageType == constants.PerUserStorageClassType || storageType == "" {
 
 			// Determine workspaces to reconcile that use the current common PVC.
@@ -137,3 +140,21 @@ func (r *DevWorkspaceReconciler) runningWorkspacesHandler(obj client.Object) []r
 	}
 	return reconciles
 }
+
+// namespaceWorkspacesHandler enqueues reconciles for all DevWorkspaces in a namespace
+func (r *DevWorkspaceReconciler) namespaceWorkspacesHandler(obj client.Object) []reconcile.Request {
+	dwList := &dw.DevWorkspaceList{}
+	if err := r.Client.List(context.Background(), dwList, &client.ListOptions{Namespace: obj.GetName()}); err != nil {
+		return []reconcile.Request{}
+	}
+	var reconciles []reconcile.Request
+	for _, workspace := range dwList.Items {
+		reconciles = append(reconciles, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      workspace.GetName(),
+				Namespace: workspace.GetNamespace(),
+			},
+		})
+	}
+	return reconciles
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package eventsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/google/uuid"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	// cloudEventTypePrefix is prepended to the lifecycle event type to form the CloudEvent type, e.g.
+	// "io.devfile.devworkspace.started".
+	cloudEventTypePrefix = "io.devfile.devworkspace."
+)
+
+// cloudEventData is the data of CloudEvents sent for DevWorkspace lifecycle events.
+type cloudEventData struct {
+	Workspace     WorkspaceInfo        `json:"workspace"`
+	Message       string               `json:"message,omitempty"`
+	User          string               `json:"user,omitempty"`
+	Phase         dw.DevWorkspacePhase `json:"phase,omitempty"`
+	PreviousPhase dw.DevWorkspacePhase `json:"previousPhase,omitempty"`
+	StartedAt     *time.Time           `json:"startedAt,omitempty"`
+	Duration      string               `json:"duration,omitempty"`
+}
+
+// getCloudEventRequest returns the body and headers of an HTTP request that sends event as a CloudEvent in binary
+// content mode, where event attributes are sent as ce-* headers and the request body holds the event data.
+func getCloudEventRequest(event *Event) (body []byte, headers map[string]string, err error) {
+	body, err = json.Marshal(cloudEventData{
+		Workspace:     event.Workspace,
+		Message:       event.Message,
+		User:          event.User,
+		Phase:         event.Phase,
+		PreviousPhase: event.PreviousPhase,
+		StartedAt:     event.StartedAt,
+		Duration:      event.Duration,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	headers = map[string]string{
+		"Content-Type":   "application/json",
+		"ce-specversion": cloudEventsSpecVersion,
+		"ce-id":          uuid.New().String(),
+		"ce-type":        cloudEventTypePrefix + string(event.Type),
+		"ce-source":      getCloudEventSource(event.Workspace),
+		"ce-subject":     event.Workspace.ID,
+		"ce-time":        event.Timestamp.Format(time.RFC3339),
+	}
+	return body, headers, nil
+}
+
+// getCloudEventSource returns the API path of the DevWorkspace the event refers to, which is used as the source of
+// the CloudEvent.
+func getCloudEventSource(workspace WorkspaceInfo) string {
+	return fmt.Sprintf("/apis/%s/namespaces/%s/devworkspaces/%s", dw.SchemeGroupVersion.String(), workspace.Namespace, workspace.Name)
+}
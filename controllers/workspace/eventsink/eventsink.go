@@ -0,0 +1,262 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package eventsink sends DevWorkspace lifecycle events to the webhook configured in the global
+// DevWorkspaceOperatorConfig, so that external systems can react to them without watching the Kubernetes API.
+// Events are sent either in the DevWorkspace Operator's own JSON format or as CloudEvents.
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+type EventType string
+
+const (
+	EventStarted EventType = "started"
+	EventStopped EventType = "stopped"
+	EventFailed  EventType = "failed"
+	EventIdled   EventType = "idled"
+	// EventPhaseChanged is sent for every phase transition if phase transition events are enabled.
+	EventPhaseChanged EventType = "phaseChanged"
+	// EventCreated and EventDeleted are only recorded in the audit log.
+	EventCreated EventType = "created"
+	EventDeleted EventType = "deleted"
+)
+
+const (
+	// SignatureHeader is the HTTP header that holds the HMAC-SHA256 signature of the request body, in the format
+	// "sha256=<hex digest>".
+	SignatureHeader = "X-DevWorkspace-Signature"
+	// EventTypeHeader is the HTTP header that holds the type of the event sent in the request.
+	EventTypeHeader = "X-DevWorkspace-Event"
+	// secretKey is the key in the event sink Secret that holds the shared secret used to sign requests.
+	secretKey = "secret"
+)
+
+// Event is the JSON body sent to the event sink.
+type Event struct {
+	Type      EventType     `json:"type"`
+	Timestamp time.Time     `json:"timestamp"`
+	Workspace WorkspaceInfo `json:"workspace"`
+	// Message contains additional details on the event, e.g. the reason a DevWorkspace failed.
+	Message string `json:"message,omitempty"`
+	// User is the name of the user whose request caused the event. It is only set for audit records.
+	User string `json:"user,omitempty"`
+	// Phase and PreviousPhase are the phases of the DevWorkspace after and before a phase transition. They are only
+	// set for phaseChanged events.
+	Phase         dw.DevWorkspacePhase `json:"phase,omitempty"`
+	PreviousPhase dw.DevWorkspacePhase `json:"previousPhase,omitempty"`
+	// StartedAt is the time the DevWorkspace was last started, if it is starting or was running before the event.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// Duration is the time related to the event, e.g. how long a DevWorkspace took to start or how long it ran
+	// before it was stopped.
+	Duration string `json:"duration,omitempty"`
+}
+
+type WorkspaceInfo struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	UID             string `json:"uid"`
+	ID              string `json:"id"`
+	Creator         string `json:"creator,omitempty"`
+	CreatorUsername string `json:"creatorUsername,omitempty"`
+}
+
+// GetWorkspaceInfo returns the information on a DevWorkspace that is sent with events.
+func GetWorkspaceInfo(workspace *common.DevWorkspaceWithConfig) WorkspaceInfo {
+	return WorkspaceInfo{
+		Name:            workspace.Name,
+		Namespace:       workspace.Namespace,
+		UID:             string(workspace.UID),
+		ID:              workspace.Status.DevWorkspaceId,
+		Creator:         workspace.Labels[constants.DevWorkspaceCreatorLabel],
+		CreatorUsername: workspace.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation],
+	}
+}
+
+// GetEventForPhase returns the lifecycle event for a workspace that transitioned to a new phase, or nil if the
+// transition does not correspond to a lifecycle event.
+func GetEventForPhase(workspace *common.DevWorkspaceWithConfig, oldPhase, newPhase dw.DevWorkspacePhase, now time.Time) *Event {
+	if oldPhase == newPhase {
+		return nil
+	}
+	event := &Event{
+		Timestamp: now.UTC(),
+		Workspace: GetWorkspaceInfo(workspace),
+	}
+	switch newPhase {
+	case dw.DevWorkspaceStatusRunning:
+		event.Type = EventStarted
+		event.Duration = getStartupDuration(workspace.Status.Conditions)
+	case dw.DevWorkspaceStatusStopped:
+		if workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] == "inactivity" {
+			event.Type = EventIdled
+		} else {
+			event.Type = EventStopped
+		}
+	case dw.DevWorkspaceStatusFailed:
+		event.Type = EventFailed
+		if failedCondition := conditions.GetConditionByType(workspace.Status.Conditions, dw.DevWorkspaceFailedStart); failedCondition != nil {
+			event.Message = failedCondition.Message
+		} else {
+			event.Message = workspace.Status.Message
+		}
+	default:
+		return nil
+	}
+	return event
+}
+
+// GetPhaseChangedEvent returns a phaseChanged event for a workspace whose phase changed from the phase in oldStatus,
+// or nil if the phase did not change. If the workspace was started before the transition, the event includes the time
+// it was started and the time elapsed since then; for transitions to the Running phase, the duration is the time the
+// workspace took to start instead.
+func GetPhaseChangedEvent(workspace *common.DevWorkspaceWithConfig, oldStatus *dw.DevWorkspaceStatus, now time.Time) *Event {
+	if oldStatus.Phase == workspace.Status.Phase {
+		return nil
+	}
+	event := &Event{
+		Type:          EventPhaseChanged,
+		Timestamp:     now.UTC(),
+		Workspace:     GetWorkspaceInfo(workspace),
+		Phase:         workspace.Status.Phase,
+		PreviousPhase: oldStatus.Phase,
+		Message:       workspace.Status.Message,
+	}
+	startedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.Started)
+	if startedCondition == nil || startedCondition.Status != corev1.ConditionTrue {
+		startedCondition = conditions.GetConditionByType(oldStatus.Conditions, conditions.Started)
+	}
+	if startedCondition == nil || startedCondition.Status != corev1.ConditionTrue || startedCondition.LastTransitionTime.IsZero() {
+		return event
+	}
+	startedAt := startedCondition.LastTransitionTime.UTC()
+	event.StartedAt = &startedAt
+	if workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+		event.Duration = getStartupDuration(workspace.Status.Conditions)
+	} else {
+		event.Duration = now.Sub(startedAt).Round(time.Second).String()
+	}
+	return event
+}
+
+// getStartupDuration returns the time a workspace took to start, based on its Started and Ready conditions, or an
+// empty string if the workspace is not ready.
+func getStartupDuration(workspaceConditions []dw.DevWorkspaceCondition) string {
+	startedCondition := conditions.GetConditionByType(workspaceConditions, conditions.Started)
+	readyCondition := conditions.GetConditionByType(workspaceConditions, dw.DevWorkspaceReady)
+	if startedCondition == nil || readyCondition == nil || readyCondition.Status != corev1.ConditionTrue {
+		return ""
+	}
+	return readyCondition.LastTransitionTime.Sub(startedCondition.LastTransitionTime.Time).String()
+}
+
+// Send posts an event to the configured event sink. If a Secret is configured for the event sink, the request body
+// is signed using the shared secret it contains. Nothing is sent if no event sink URL is configured.
+func Send(ctx context.Context, event *Event, sinkConfig *controllerv1alpha1.EventSinkConfig, httpClient *http.Client, k8s client.Reader) error {
+	if sinkConfig == nil || sinkConfig.URL == "" {
+		return nil
+	}
+	timeout, err := time.ParseDuration(sinkConfig.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid duration specified for event sink timeout: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body []byte
+	var headers map[string]string
+	switch sinkConfig.Format {
+	case controllerv1alpha1.EventSinkFormatCloudEvents:
+		body, headers, err = getCloudEventRequest(event)
+	default:
+		body, err = json.Marshal(event)
+		headers = map[string]string{
+			"Content-Type":  "application/json",
+			EventTypeHeader: string(event.Type),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to serialize event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sinkConfig.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request for event sink: %w", err)
+	}
+	for header, value := range headers {
+		req.Header.Set(header, value)
+	}
+
+	if sinkConfig.SecretName != "" {
+		secret, err := getSharedSecret(ctx, sinkConfig.SecretName, k8s)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(SignatureHeader, "sha256="+Sign(body, secret))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event to event sink: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event sink returned unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using the provided secret.
+func Sign(body, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func getSharedSecret(ctx context.Context, secretName string, k8s client.Reader) ([]byte, error) {
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := k8s.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to read event sink secret %s: %w", secretName, err)
+	}
+	value, ok := secret.Data[secretKey]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("event sink secret %s does not contain key %s", secretName, secretKey)
+	}
+	return value, nil
+}
@@ -0,0 +1,277 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const testNamespace = "devworkspace-operator"
+
+func getTestWorkspace(annotations map[string]string) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Annotations = annotations
+	workspace.Status.Conditions = []dw.DevWorkspaceCondition{
+		{
+			Type:    dw.DevWorkspaceFailedStart,
+			Status:  corev1.ConditionTrue,
+			Message: "Container tools has state ImagePullBackOff",
+		},
+	}
+	return workspace
+}
+
+func TestGetEventForPhase(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		oldPhase     dw.DevWorkspacePhase
+		newPhase     dw.DevWorkspacePhase
+		expectedType EventType
+	}{
+		{
+			name:         "Workspace started",
+			oldPhase:     dw.DevWorkspaceStatusStarting,
+			newPhase:     dw.DevWorkspaceStatusRunning,
+			expectedType: EventStarted,
+		},
+		{
+			name:         "Workspace stopped",
+			oldPhase:     dw.DevWorkspaceStatusStopping,
+			newPhase:     dw.DevWorkspaceStatusStopped,
+			expectedType: EventStopped,
+		},
+		{
+			name:         "Workspace idled",
+			annotations:  map[string]string{constants.DevWorkspaceStopReasonAnnotation: "inactivity"},
+			oldPhase:     dw.DevWorkspaceStatusStopping,
+			newPhase:     dw.DevWorkspaceStatusStopped,
+			expectedType: EventIdled,
+		},
+		{
+			name:         "Workspace failed",
+			oldPhase:     "Failing",
+			newPhase:     dw.DevWorkspaceStatusFailed,
+			expectedType: EventFailed,
+		},
+		{
+			name:     "Phase not changed",
+			oldPhase: dw.DevWorkspaceStatusRunning,
+			newPhase: dw.DevWorkspaceStatusRunning,
+		},
+		{
+			name:     "Phase without lifecycle event",
+			oldPhase: dw.DevWorkspaceStatusRunning,
+			newPhase: dw.DevWorkspaceStatusStopping,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := GetEventForPhase(getTestWorkspace(tt.annotations), tt.oldPhase, tt.newPhase, now)
+			if tt.expectedType == "" {
+				assert.Nil(t, event)
+				return
+			}
+			if !assert.NotNil(t, event) {
+				return
+			}
+			assert.Equal(t, tt.expectedType, event.Type)
+			assert.Equal(t, "test-workspaceid", event.Workspace.ID)
+			assert.Equal(t, "test-creator", event.Workspace.Creator)
+			if tt.expectedType == EventFailed {
+				assert.Equal(t, "Container tools has state ImagePullBackOff", event.Message)
+			}
+		})
+	}
+}
+
+func TestSendSignsEvent(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "event-sink-secret",
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{"secret": []byte("shared-secret")},
+	}
+	fakeClient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	var received *Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		assert.Equal(t, "sha256="+Sign(body, []byte("shared-secret")), r.Header.Get(SignatureHeader))
+		assert.Equal(t, string(EventStarted), r.Header.Get(EventTypeHeader))
+		received = &Event{}
+		assert.NoError(t, json.Unmarshal(body, received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sinkConfig := &controllerv1alpha1.EventSinkConfig{
+		URL:        server.URL,
+		SecretName: "event-sink-secret",
+		Timeout:    "5s",
+	}
+	event := GetEventForPhase(getTestWorkspace(nil), dw.DevWorkspaceStatusStarting, dw.DevWorkspaceStatusRunning, time.Now())
+	err := Send(context.Background(), event, sinkConfig, server.Client(), fakeClient)
+	if assert.NoError(t, err) && assert.NotNil(t, received, "Event sink should receive event") {
+		assert.Equal(t, EventStarted, received.Type)
+		assert.Equal(t, "test-workspace", received.Workspace.Name)
+	}
+}
+
+func TestSendReturnsErrorOnFailedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sinkConfig := &controllerv1alpha1.EventSinkConfig{
+		URL:     server.URL,
+		Timeout: "5s",
+	}
+	event := GetEventForPhase(getTestWorkspace(nil), dw.DevWorkspaceStatusStarting, dw.DevWorkspaceStatusRunning, time.Now())
+	err := Send(context.Background(), event, sinkConfig, server.Client(), fake.NewClientBuilder().Build())
+	assert.EqualError(t, err, "event sink returned unexpected status code 503")
+}
+
+func TestSendCloudEvent(t *testing.T) {
+	var headers http.Header
+	var data *cloudEventData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		data = &cloudEventData{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(data))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sinkConfig := &controllerv1alpha1.EventSinkConfig{
+		URL:     server.URL,
+		Timeout: "5s",
+		Format:  controllerv1alpha1.EventSinkFormatCloudEvents,
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := GetEventForPhase(getTestWorkspace(nil), "Failing", dw.DevWorkspaceStatusFailed, now)
+	err := Send(context.Background(), event, sinkConfig, server.Client(), fake.NewClientBuilder().Build())
+	if !assert.NoError(t, err) || !assert.NotNil(t, data, "Event sink should receive event") {
+		return
+	}
+	assert.Equal(t, "1.0", headers.Get("ce-specversion"))
+	assert.Equal(t, "io.devfile.devworkspace.failed", headers.Get("ce-type"))
+	assert.Equal(t, "/apis/workspace.devfile.io/v1alpha2/namespaces/test-namespace/devworkspaces/test-workspace", headers.Get("ce-source"))
+	assert.Equal(t, "test-workspaceid", headers.Get("ce-subject"))
+	assert.Equal(t, "2024-01-01T12:00:00Z", headers.Get("ce-time"))
+	assert.NotEmpty(t, headers.Get("ce-id"))
+	assert.Equal(t, "test-workspace", data.Workspace.Name)
+	assert.Equal(t, "Container tools has state ImagePullBackOff", data.Message)
+}
+
+func TestGetPhaseChangedEvent(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	startedAt := now.Add(-time.Hour)
+	startedCondition := dw.DevWorkspaceCondition{
+		Type:               conditions.Started,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(startedAt),
+	}
+	readyCondition := dw.DevWorkspaceCondition{
+		Type:               dw.DevWorkspaceReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(startedAt.Add(90 * time.Second)),
+	}
+	tests := []struct {
+		name              string
+		oldStatus         dw.DevWorkspaceStatus
+		phase             dw.DevWorkspacePhase
+		conditions        []dw.DevWorkspaceCondition
+		expectNil         bool
+		expectedStartedAt *time.Time
+		expectedDuration  string
+	}{
+		{
+			name:      "Phase not changed",
+			oldStatus: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusRunning},
+			phase:     dw.DevWorkspaceStatusRunning,
+			expectNil: true,
+		},
+		{
+			name:      "Workspace created",
+			oldStatus: dw.DevWorkspaceStatus{},
+			phase:     dw.DevWorkspaceStatusStopped,
+		},
+		{
+			name:              "Workspace running reports startup duration",
+			oldStatus:         dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusStarting},
+			phase:             dw.DevWorkspaceStatusRunning,
+			conditions:        []dw.DevWorkspaceCondition{startedCondition, readyCondition},
+			expectedStartedAt: &startedAt,
+			expectedDuration:  "1m30s",
+		},
+		{
+			name:              "Workspace stopping reports time since start",
+			oldStatus:         dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusRunning, Conditions: []dw.DevWorkspaceCondition{startedCondition}},
+			phase:             dw.DevWorkspaceStatusStopping,
+			expectedStartedAt: &startedAt,
+			expectedDuration:  "1h0m0s",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getTestWorkspace(map[string]string{constants.DevWorkspaceCreatorUsernameAnnotation: "test-user"})
+			workspace.Status.Phase = tt.phase
+			workspace.Status.Conditions = tt.conditions
+			event := GetPhaseChangedEvent(workspace, &tt.oldStatus, now)
+			if tt.expectNil {
+				assert.Nil(t, event)
+				return
+			}
+			if !assert.NotNil(t, event) {
+				return
+			}
+			assert.Equal(t, EventPhaseChanged, event.Type)
+			assert.Equal(t, tt.phase, event.Phase)
+			assert.Equal(t, tt.oldStatus.Phase, event.PreviousPhase)
+			assert.Equal(t, "test-user", event.Workspace.CreatorUsername)
+			assert.Equal(t, tt.expectedStartedAt, event.StartedAt)
+			assert.Equal(t, tt.expectedDuration, event.Duration)
+		})
+	}
+}
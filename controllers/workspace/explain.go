@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	wkspConfig "github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+const explainConfigMapKey = "explanation.txt"
+
+// syncExplanationToCluster stores a human-readable explanation of how a workspace was provisioned in a ConfigMap if
+// the workspace has the DevWorkspaceExplainAnnotation annotation, and removes a previously stored explanation
+// otherwise. Errors are only logged, as the explanation is informational and should not affect the workspace.
+func (r *DevWorkspaceReconciler) syncExplanationToCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI, reqLogger logr.Logger) {
+
+	if workspace.Annotations[constants.DevWorkspaceExplainAnnotation] != "true" {
+		r.removeExplanationFromCluster(ctx, workspace, reqLogger)
+		return
+	}
+
+	cm := getSpecExplainConfigMap(workspace)
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, cm, clusterAPI.Scheme); err != nil {
+		reqLogger.Error(err, "Failed to set owner reference on DevWorkspace explanation ConfigMap")
+		return
+	}
+	if _, err := sync.SyncObjectWithCluster(cm, clusterAPI); err != nil {
+		switch err.(type) {
+		case *sync.NotInSyncError:
+			// Object was created or updated; nothing else to do.
+		default:
+			reqLogger.Info("Failed to sync DevWorkspace explanation ConfigMap", "error", err.Error())
+		}
+	}
+}
+
+func (r *DevWorkspaceReconciler) removeExplanationFromCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, reqLogger logr.Logger) {
+
+	cm := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{Name: common.ExplainConfigMapName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+	if err := r.Get(ctx, namespacedName, cm); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			reqLogger.Info("Failed to read DevWorkspace explanation ConfigMap", "error", err.Error())
+		}
+		return
+	}
+	if err := r.Delete(ctx, cm); err != nil && !k8sErrors.IsNotFound(err) {
+		reqLogger.Info("Failed to remove DevWorkspace explanation ConfigMap", "error", err.Error())
+	}
+}
+
+func getSpecExplainConfigMap(workspace *common.DevWorkspaceWithConfig) *corev1.ConfigMap {
+	cmLabels := constants.ControllerAppLabels()
+	cmLabels[constants.DevWorkspaceWatchConfigMapLabel] = "true"
+	cmLabels[constants.DevWorkspaceIDLabel] = workspace.Status.DevWorkspaceId
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ExplainConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels:    cmLabels,
+		},
+		Data: map[string]string{
+			explainConfigMapKey: getExplanation(workspace),
+		},
+	}
+}
+
+// getExplanation returns a human-readable document describing the current state of a workspace, the outcome of
+// each provisioning step, the decisions the controller made while provisioning it, and the configuration that was
+// used to do so.
+func getExplanation(workspace *common.DevWorkspaceWithConfig) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "DevWorkspace: %s/%s\n", workspace.Namespace, workspace.Name)
+	fmt.Fprintf(&sb, "DevWorkspace ID: %s\n", workspace.Status.DevWorkspaceId)
+	fmt.Fprintf(&sb, "Started: %t\n", workspace.Spec.Started)
+	fmt.Fprintf(&sb, "Phase: %s\n", workspace.Status.Phase)
+	fmt.Fprintf(&sb, "Message: %s\n", workspace.Status.Message)
+
+	sb.WriteString("\nProvisioning steps:\n")
+	for _, conditionType := range conditionOrder {
+		condition := conditions.GetConditionByType(workspace.Status.Conditions, conditionType)
+		if condition == nil {
+			fmt.Fprintf(&sb, "  [Pending] %s\n", conditionType)
+			continue
+		}
+		fmt.Fprintf(&sb, "  [%s] %s", condition.Status, conditionType)
+		if condition.Message != "" {
+			fmt.Fprintf(&sb, ": %s", condition.Message)
+		}
+		sb.WriteString("\n")
+	}
+	var warnings []string
+	for _, condition := range workspace.Status.Conditions {
+		if condition.Type == conditions.DevWorkspaceWarning && condition.Status == corev1.ConditionTrue {
+			warnings = append(warnings, condition.Message)
+		}
+	}
+	if len(warnings) > 0 {
+		sb.WriteString("\nWarnings:\n")
+		for _, warning := range warnings {
+			fmt.Fprintf(&sb, "  - %s\n", warning)
+		}
+	}
+
+	sb.WriteString("\nDecisions:\n")
+	for _, decision := range getExplanationDecisions(workspace) {
+		fmt.Fprintf(&sb, "  - %s\n", decision)
+	}
+
+	sb.WriteString("\nConfiguration:\n")
+	if configString := wkspConfig.GetCurrentConfigString(workspace.Config); configString != "" {
+		fmt.Fprintf(&sb, "  %s\n", configString)
+	} else {
+		sb.WriteString("  Default DevWorkspace Operator configuration\n")
+	}
+
+	return sb.String()
+}
+
+func getExplanationDecisions(workspace *common.DevWorkspaceWithConfig) []string {
+	var decisions []string
+
+	if workspace.Spec.RoutingClass != "" {
+		decisions = append(decisions, fmt.Sprintf("Routing class %q is set in the DevWorkspace", workspace.Spec.RoutingClass))
+	} else if workspace.Config.Routing != nil {
+		decisions = append(decisions, fmt.Sprintf("Routing class %q is used from config.routing.defaultRoutingClass", workspace.Config.Routing.DefaultRoutingClass))
+	}
+
+	if !storage.WorkspaceNeedsStorage(&workspace.Spec.Template) {
+		decisions = append(decisions, "No persistent storage is provisioned as the DevWorkspace does not use volumes")
+	} else if storageType := storage.GetStorageType(workspace); storageType != "" {
+		decisions = append(decisions, fmt.Sprintf("Storage type %q is used", storageType))
+	} else {
+		decisions = append(decisions, fmt.Sprintf("Storage type %q is used", constants.CommonStorageClassType))
+	}
+
+	workspaceConfig := workspace.Config.Workspace
+	if workspaceConfig == nil {
+		return decisions
+	}
+	if workspaceConfig.DeploymentStrategy != "" {
+		decisions = append(decisions, fmt.Sprintf("Deployment strategy %q is used", workspaceConfig.DeploymentStrategy))
+	}
+	if workspaceConfig.PriorityClassName != "" {
+		decisions = append(decisions, fmt.Sprintf("Priority class %q is applied to the workspace pod", workspaceConfig.PriorityClassName))
+	}
+	if workspaceConfig.RuntimeClassName != nil {
+		decisions = append(decisions, fmt.Sprintf("Runtime class %q is applied to the workspace pod", *workspaceConfig.RuntimeClassName))
+	}
+	if workspaceConfig.SchedulerName != "" {
+		decisions = append(decisions, fmt.Sprintf("Scheduler %q is used for the workspace pod", workspaceConfig.SchedulerName))
+	}
+	if len(workspaceConfig.NodeSelector) > 0 || len(workspaceConfig.Tolerations) > 0 || workspaceConfig.Affinity != nil {
+		decisions = append(decisions, "Node selector, tolerations or affinity from config.workspace are applied to the workspace pod")
+	}
+	if wsprovision.NeedsPodGroup(workspace) {
+		decisions = append(decisions, "Workspace pods are gang scheduled using a PodGroup")
+	}
+	if policy := workspaceConfig.SecurityContextPolicy; policy != nil && policy.Enable != nil && *policy.Enable {
+		decisions = append(decisions, "The security context policy from config.workspace.securityContextPolicy is enforced on workspace pods")
+	}
+
+	var enabledFeatures []string
+	for feature, enabled := range wkspConfig.GetFeatureGates(workspace.Config) {
+		if enabled {
+			enabledFeatures = append(enabledFeatures, string(feature))
+		}
+	}
+	sort.Strings(enabledFeatures)
+	if len(enabledFeatures) > 0 {
+		decisions = append(decisions, fmt.Sprintf("Enabled features: %s", strings.Join(enabledFeatures, ", ")))
+	} else {
+		decisions = append(decisions, "No optional features are enabled")
+	}
+
+	return decisions
+}
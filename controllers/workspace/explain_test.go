@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func TestSyncExplanationToCluster(t *testing.T) {
+	workspace := getRetryTestWorkspace("Error creating DevWorkspace deployment", time.Minute, "")
+	workspace.Annotations[constants.DevWorkspaceExplainAnnotation] = "true"
+	r := getRetryTestReconciler(workspace)
+	clusterAPI := sync.ClusterAPI{
+		Client: r.Client,
+		Scheme: r.Scheme,
+		Logger: zap.New(),
+		Ctx:    context.Background(),
+	}
+	cmName := types.NamespacedName{Name: common.ExplainConfigMapName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+
+	r.syncExplanationToCluster(context.Background(), workspace, clusterAPI, r.Log)
+	cm := &corev1.ConfigMap{}
+	if !assert.NoError(t, r.Get(context.Background(), cmName, cm), "Explanation ConfigMap should be created") {
+		return
+	}
+	explanation := cm.Data[explainConfigMapKey]
+	assert.Contains(t, explanation, "DevWorkspace: test-namespace/test-workspace")
+	assert.Contains(t, explanation, "Phase: Failing")
+	assert.Contains(t, explanation, "[Pending] DeploymentReady")
+	assert.Equal(t, "true", cm.Labels[constants.DevWorkspaceWatchConfigMapLabel])
+	assert.Equal(t, workspace.Status.DevWorkspaceId, cm.Labels[constants.DevWorkspaceIDLabel])
+	if assert.Len(t, cm.OwnerReferences, 1) {
+		assert.Equal(t, workspace.Name, cm.OwnerReferences[0].Name)
+	}
+
+	delete(workspace.Annotations, constants.DevWorkspaceExplainAnnotation)
+	r.syncExplanationToCluster(context.Background(), workspace, clusterAPI, r.Log)
+	err := r.Get(context.Background(), cmName, &corev1.ConfigMap{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Explanation ConfigMap should be removed when annotation is removed")
+}
+
+func TestGetExplanationDecisions(t *testing.T) {
+	workspace := getRetryTestWorkspace("", time.Minute, "")
+	workspace.Spec.RoutingClass = "internal"
+	workspace.Config.Workspace.PriorityClassName = "workspace-priority"
+	workspace.Config.Workspace.DeploymentStrategy = "Recreate"
+
+	decisions := getExplanationDecisions(workspace)
+	assert.Contains(t, decisions, `Routing class "internal" is set in the DevWorkspace`)
+	assert.Contains(t, decisions, `Priority class "workspace-priority" is applied to the workspace pod`)
+	assert.Contains(t, decisions, `Deployment strategy "Recreate" is used`)
+	assert.Contains(t, decisions, "No persistent storage is provisioned as the DevWorkspace does not use volumes")
+}
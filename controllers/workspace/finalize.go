@@ -18,8 +18,11 @@ package controllers
 import (
 	"context"
 
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/finalizerhooks"
 	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
 
@@ -66,6 +69,13 @@ func (r *DevWorkspaceReconciler) finalize(ctx context.Context, log logr.Logger,
 		return r.updateWorkspaceStatus(workspace, log, finalizeStatus, finalizeResult, finalizeErr)
 	}()
 
+	// Finalizer hooks are run first, so that they can still access the workspace's resources
+	for _, finalizer := range workspace.Finalizers {
+		if hookName, isHook := finalizerhooks.HookNameFromFinalizer(finalizer); isHook {
+			return r.finalizeHook(ctx, log, workspace, hookName, finalizeStatus)
+		}
+	}
+
 	for _, finalizer := range workspace.Finalizers {
 		switch finalizer {
 		case constants.StorageCleanupFinalizer:
@@ -99,19 +109,25 @@ func (r *DevWorkspaceReconciler) finalizeStorage(ctx context.Context, log logr.L
 		return reconcile.Result{}, r.Update(ctx, workspace.DevWorkspace)
 	}
 
-	storageProvisioner, err := storage.GetProvisioner(workspace)
-	if err != nil {
-		log.Error(err, "Failed to clean up DevWorkspace storage")
-		finalizeStatus.phase = dw.DevWorkspaceStatusError
-		finalizeStatus.setConditionTrue(dw.DevWorkspaceError, err.Error())
-		return reconcile.Result{}, nil
-	}
-	err = storageProvisioner.CleanupWorkspaceStorage(workspace, sync.ClusterAPI{
+	clusterAPI := sync.ClusterAPI{
 		Ctx:    ctx,
 		Client: r.Client,
 		Scheme: r.Scheme,
 		Logger: log,
-	})
+	}
+	moveToTrash := storage.ShouldMoveToTrash(workspace)
+	if moveToTrash {
+		err = storage.MoveToTrash(workspace, clusterAPI)
+	} else {
+		storageProvisioner, provisionerErr := storage.GetProvisioner(workspace)
+		if provisionerErr != nil {
+			log.Error(provisionerErr, "Failed to clean up DevWorkspace storage")
+			finalizeStatus.phase = dw.DevWorkspaceStatusError
+			finalizeStatus.setConditionTrue(dw.DevWorkspaceError, provisionerErr.Error())
+			return reconcile.Result{}, nil
+		}
+		err = storageProvisioner.CleanupWorkspaceStorage(workspace, clusterAPI)
+	}
 	if err != nil {
 		switch storageErr := err.(type) {
 		case *dwerrors.RetryError:
@@ -129,7 +145,11 @@ func (r *DevWorkspaceReconciler) finalizeStorage(ctx context.Context, log logr.L
 			return reconcile.Result{}, storageErr
 		}
 	}
-	log.Info("PVC clean up successful; clearing finalizer")
+	if moveToTrash {
+		log.Info("Moved DevWorkspace to trash; clearing finalizer")
+	} else {
+		log.Info("PVC clean up successful; clearing finalizer")
+	}
 	controllerutil.RemoveFinalizer(workspace, constants.StorageCleanupFinalizer)
 	return reconcile.Result{}, r.Update(ctx, workspace.DevWorkspace)
 }
@@ -172,6 +192,63 @@ func (r *DevWorkspaceReconciler) finalizeRBAC(ctx context.Context, log logr.Logg
 	return reconcile.Result{}, r.Update(ctx, workspace.DevWorkspace)
 }
 
+func (r *DevWorkspaceReconciler) finalizeHook(ctx context.Context, log logr.Logger, workspace *common.DevWorkspaceWithConfig, hookName string, finalizeStatus *currentStatus) (reconcile.Result, error) {
+	finalizer := constants.FinalizerHookPrefix + hookName
+	var hook *controllerv1alpha1.FinalizerHook
+	for _, configuredHook := range config.GetGlobalConfig().Workspace.FinalizerHooks {
+		if configuredHook.Name == hookName {
+			hook = configuredHook.DeepCopy()
+			break
+		}
+	}
+	if hook == nil {
+		log.Info("Finalizer hook is no longer configured; clearing finalizer", "hook", hookName)
+		controllerutil.RemoveFinalizer(workspace, finalizer)
+		return reconcile.Result{}, r.Update(ctx, workspace.DevWorkspace)
+	}
+
+	if err := finalizerhooks.Run(*hook, workspace, httpClient, sync.ClusterAPI{
+		Ctx:              ctx,
+		Client:           r.Client,
+		NonCachingClient: r.NonCachingClient,
+		Scheme:           r.Scheme,
+		Logger:           log,
+	}); err != nil {
+		switch hookErr := err.(type) {
+		case *dwerrors.RetryError:
+			log.Info(hookErr.Error())
+			finalizeStatus.setConditionTrue(conditions.Started, hookErr.Error())
+			return reconcile.Result{RequeueAfter: hookErr.RequeueAfter}, nil
+		case *dwerrors.FailError:
+			if workspace.Status.Phase != dw.DevWorkspaceStatusError {
+				// Avoid repeatedly logging error unless it's relevant
+				log.Error(hookErr, "Failed to run finalizer hook", "hook", hookName)
+			}
+			finalizeStatus.phase = dw.DevWorkspaceStatusError
+			finalizeStatus.setConditionTrue(dw.DevWorkspaceError, err.Error())
+			return reconcile.Result{}, nil
+		default:
+			return reconcile.Result{}, err
+		}
+	}
+	log.Info("Finalizer hook successful; clearing finalizer", "hook", hookName)
+	controllerutil.RemoveFinalizer(workspace, finalizer)
+	return reconcile.Result{}, r.Update(ctx, workspace.DevWorkspace)
+}
+
+// addFinalizerHookFinalizers adds a finalizer for each finalizer hook in the global config to the workspace, and
+// returns whether the workspace was modified.
+func addFinalizerHookFinalizers(workspace *common.DevWorkspaceWithConfig) bool {
+	modified := false
+	for _, hook := range config.GetGlobalConfig().Workspace.FinalizerHooks {
+		if finalizer := finalizerhooks.FinalizerName(hook); !controllerutil.ContainsFinalizer(workspace, finalizer) {
+			controllerutil.AddFinalizer(workspace, finalizer)
+			modified = true
+		}
+	}
+	return modified
+}
+
 // Deprecated: Only required to support old workspaces that use the service account finalizer. The service account finalizer should
 // not be added to new workspaces.
 func (r *DevWorkspaceReconciler) finalizeServiceAccount(ctx context.Context, log logr.Logger, workspace *common.DevWorkspaceWithConfig, finalizeStatus *currentStatus) (reconcile.Result, error) {
@@ -0,0 +1,257 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package finalizerhooks runs the cleanup hooks configured in the global DevWorkspaceOperatorConfig when a
+// DevWorkspace is deleted, so that platforms can deprovision external resources tied to DevWorkspaces. A hook
+// either calls a URL with the DevWorkspace's metadata or runs a Job in the DevWorkspace's namespace.
+package finalizerhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/eventsink"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	// EventTypeHeaderValue is the value of the X-DevWorkspace-Event header sent with requests to finalizer hook URLs.
+	EventTypeHeaderValue = "finalize"
+	// secretKey is the key in the hook Secret that holds the shared secret used to sign requests.
+	secretKey = "secret"
+	// retryInterval is how long to wait before retrying a failed request or checking on a running Job.
+	retryInterval = 10 * time.Second
+)
+
+var (
+	defaultTimeout      = 10 * time.Second
+	defaultBackoffLimit = int32(3)
+)
+
+// Request is the JSON body sent to finalizer hook URLs.
+type Request struct {
+	Hook      string                  `json:"hook"`
+	Timestamp time.Time               `json:"timestamp"`
+	Workspace eventsink.WorkspaceInfo `json:"workspace"`
+}
+
+// FinalizerName returns the finalizer added to DevWorkspaces for the provided hook.
+func FinalizerName(hook controllerv1alpha1.FinalizerHook) string {
+	return constants.FinalizerHookPrefix + hook.Name
+}
+
+// HookNameFromFinalizer returns the name of the hook a finalizer refers to, and whether the finalizer is a
+// finalizer hook finalizer at all.
+func HookNameFromFinalizer(finalizer string) (string, bool) {
+	if !strings.HasPrefix(finalizer, constants.FinalizerHookPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(finalizer, constants.FinalizerHookPrefix), true
+}
+
+// Run runs a finalizer hook for a deleted DevWorkspace. It returns nil once the hook has succeeded, a RetryError
+// while the hook is in progress or failed in a way that may be resolved by retrying, and a FailError if the hook
+// cannot succeed without intervention.
+func Run(hook controllerv1alpha1.FinalizerHook, workspace *common.DevWorkspaceWithConfig, httpClient *http.Client, clusterAPI sync.ClusterAPI) error {
+	switch {
+	case hook.URL != "" && hook.Job != nil:
+		return &dwerrors.FailError{Message: fmt.Sprintf("finalizer hook %s is invalid: only one of url and job may be specified", hook.Name)}
+	case hook.URL != "":
+		if err := callURL(hook, workspace, httpClient, clusterAPI); err != nil {
+			return &dwerrors.RetryError{
+				Message:      fmt.Sprintf("finalizer hook %s failed", hook.Name),
+				Err:          err,
+				RequeueAfter: retryInterval,
+			}
+		}
+		return nil
+	case hook.Job != nil:
+		return runJob(hook, workspace, clusterAPI)
+	default:
+		return &dwerrors.FailError{Message: fmt.Sprintf("finalizer hook %s is invalid: one of url and job must be specified", hook.Name)}
+	}
+}
+
+func callURL(hook controllerv1alpha1.FinalizerHook, workspace *common.DevWorkspaceWithConfig, httpClient *http.Client, clusterAPI sync.ClusterAPI) error {
+	timeout := defaultTimeout
+	if hook.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(hook.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid duration specified for timeout: %w", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(clusterAPI.Ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(Request{
+		Hook:      hook.Name,
+		Timestamp: time.Now().UTC(),
+		Workspace: eventsink.GetWorkspaceInfo(workspace),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventsink.EventTypeHeader, EventTypeHeaderValue)
+
+	if hook.SecretName != "" {
+		secret, err := getSharedSecret(ctx, hook.SecretName, clusterAPI)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(eventsink.SignatureHeader, "sha256="+eventsink.Sign(body, secret))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func runJob(hook controllerv1alpha1.FinalizerHook, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	specJob, err := getSpecJob(hook, workspace, clusterAPI)
+	if err != nil {
+		return err
+	}
+	clusterObj, err := sync.SyncObjectWithCluster(specJob, clusterAPI)
+	if err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+	clusterJob := clusterObj.(*batchv1.Job)
+	for _, condition := range clusterJob.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return nil
+		case batchv1.JobFailed:
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("finalizer hook %s failed: see logs for job %q for details", hook.Name, clusterJob.Name),
+			}
+		}
+	}
+	return &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Waiting for finalizer hook %s to complete", hook.Name),
+		RequeueAfter: retryInterval,
+	}
+}
+
+func getSpecJob(hook controllerv1alpha1.FinalizerHook, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*batchv1.Job, error) {
+	workspaceId := workspace.Status.DevWorkspaceId
+	jobName := common.FinalizerHookJobName(hook.Name, workspaceId)
+	jobLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:      workspaceId,
+		constants.DevWorkspaceNameLabel:    workspace.Name,
+		constants.DevWorkspaceCreatorLabel: workspace.Labels[constants.DevWorkspaceCreatorLabel],
+	}
+	backoffLimit := defaultBackoffLimit
+	if hook.Job.BackoffLimit != nil {
+		backoffLimit = *hook.Job.BackoffLimit
+	}
+
+	info := eventsink.GetWorkspaceInfo(workspace)
+	env := []corev1.EnvVar{
+		{Name: "DEVWORKSPACE_NAME", Value: info.Name},
+		{Name: "DEVWORKSPACE_NAMESPACE", Value: info.Namespace},
+		{Name: "DEVWORKSPACE_ID", Value: info.ID},
+		{Name: "DEVWORKSPACE_UID", Value: info.UID},
+		{Name: "DEVWORKSPACE_CREATOR", Value: info.Creator},
+	}
+	env = append(env, hook.Job.Env...)
+
+	var securityContext *corev1.PodSecurityContext
+	if infrastructure.IsOpenShift() {
+		securityContext = &corev1.PodSecurityContext{}
+	} else {
+		securityContext = workspace.Config.Workspace.PodSecurityContext
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: workspace.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					SecurityContext:    securityContext,
+					ServiceAccountName: hook.Job.ServiceAccountName,
+					Containers: []corev1.Container{
+						{
+							Name:    "finalizer-hook",
+							Image:   hook.Job.Image,
+							Command: hook.Job.Command,
+							Args:    hook.Job.Args,
+							Env:     env,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, job, clusterAPI.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func getSharedSecret(ctx context.Context, secretName string, clusterAPI sync.ClusterAPI) ([]byte, error) {
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := clusterAPI.NonCachingClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", secretName, err)
+	}
+	value, ok := secret.Data[secretKey]
+	if !ok || len(value) == 0 {
+		return nil, fmt.Errorf("secret %s does not contain key %s", secretName, secretKey)
+	}
+	return value, nil
+}
@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package finalizerhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/eventsink"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const testNamespace = "devworkspace-operator"
+
+func getTestClusterAPI(t *testing.T, initialObjects ...client.Object) sync.ClusterAPI {
+	fakeClient := testutil.NewFakeClient(initialObjects...)
+	return sync.ClusterAPI{
+		Ctx:              context.Background(),
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           testutil.Scheme,
+		Logger:           testr.New(t),
+	}
+}
+
+func TestHookNameFromFinalizer(t *testing.T) {
+	name, isHook := HookNameFromFinalizer("hooks.controller.devfile.io/licenses")
+	assert.True(t, isHook)
+	assert.Equal(t, "licenses", name)
+	_, isHook = HookNameFromFinalizer(constants.RBACCleanupFinalizer)
+	assert.False(t, isHook)
+}
+
+func TestRunCallsURL(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hook-secret",
+			Namespace: testNamespace,
+		},
+		Data: map[string][]byte{"secret": []byte("shared-secret")},
+	}
+	var received *Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		assert.Equal(t, "sha256="+eventsink.Sign(body, []byte("shared-secret")), r.Header.Get(eventsink.SignatureHeader))
+		assert.Equal(t, EventTypeHeaderValue, r.Header.Get(eventsink.EventTypeHeader))
+		received = &Request{}
+		assert.NoError(t, json.Unmarshal(body, received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hook := controllerv1alpha1.FinalizerHook{
+		Name:       "licenses",
+		URL:        server.URL,
+		SecretName: "hook-secret",
+	}
+	err := Run(hook, testutil.NewDevWorkspaceWithConfig("test-workspace", ""), server.Client(), getTestClusterAPI(t, secret))
+	if assert.NoError(t, err) && assert.NotNil(t, received, "Hook URL should receive request") {
+		assert.Equal(t, "licenses", received.Hook)
+		assert.Equal(t, "test-workspaceid", received.Workspace.ID)
+		assert.Equal(t, "test-creator", received.Workspace.Creator)
+	}
+}
+
+func TestRunRetriesFailedRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	hook := controllerv1alpha1.FinalizerHook{
+		Name: "licenses",
+		URL:  server.URL,
+	}
+	err := Run(hook, testutil.NewDevWorkspaceWithConfig("test-workspace", ""), server.Client(), getTestClusterAPI(t))
+	retryErr := &dwerrors.RetryError{}
+	if assert.ErrorAs(t, err, &retryErr, "Failed request should be retried") {
+		assert.Contains(t, retryErr.Error(), "unexpected status code 503")
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	api := getTestClusterAPI(t, workspace.DevWorkspace)
+	hook := controllerv1alpha1.FinalizerHook{
+		Name: "dns",
+		Job: &controllerv1alpha1.FinalizerHookJob{
+			Image:   "quay.io/example/dns-cleanup:latest",
+			Command: []string{"/cleanup.sh"},
+			Env:     []corev1.EnvVar{{Name: "ZONE", Value: "example.com"}},
+		},
+	}
+	retryErr := &dwerrors.RetryError{}
+	err := Run(hook, workspace, http.DefaultClient, api)
+	assert.ErrorAs(t, err, &retryErr, "Should return RetryError when job is created")
+
+	job := &batchv1.Job{}
+	jobName := types.NamespacedName{Name: common.FinalizerHookJobName("dns", "test-workspaceid"), Namespace: "test-namespace"}
+	if !assert.NoError(t, api.Client.Get(api.Ctx, jobName, job), "Job should be created") {
+		return
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "quay.io/example/dns-cleanup:latest", container.Image)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "DEVWORKSPACE_ID", Value: "test-workspaceid"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "ZONE", Value: "example.com"})
+
+	err = Run(hook, workspace, http.DefaultClient, api)
+	assert.ErrorAs(t, err, &retryErr, "Should return RetryError while job is running")
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.NoError(t, api.Client.Status().Update(api.Ctx, job))
+	err = Run(hook, workspace, http.DefaultClient, api)
+	failErr := &dwerrors.FailError{}
+	assert.ErrorAs(t, err, &failErr, "Should return FailError if job failed")
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, api.Client.Status().Update(api.Ctx, job))
+	assert.NoError(t, Run(hook, workspace, http.DefaultClient, api), "Should succeed once job completed")
+}
+
+func TestRunRejectsInvalidHook(t *testing.T) {
+	hook := controllerv1alpha1.FinalizerHook{Name: "invalid"}
+	err := Run(hook, testutil.NewDevWorkspaceWithConfig("test-workspace", ""), http.DefaultClient, getTestClusterAPI(t))
+	failErr := &dwerrors.FailError{}
+	assert.ErrorAs(t, err, &failErr)
+}
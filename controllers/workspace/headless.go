@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+// syncHeadlessRun runs the command from the HeadlessCommandAttribute of a started DevWorkspace as a DevWorkspaceTask,
+// and stops the DevWorkspace once the command completes, recording its result in the
+// DevWorkspaceHeadlessExitCodeAnnotation and DevWorkspaceHeadlessResultAnnotation. A task is created once each time the
+// DevWorkspace is started, as identified by the DevWorkspaceStartedAtAnnotation; tasks created for previous runs are
+// deleted at that point. Returns whether the DevWorkspace was updated on the cluster.
+func (r *DevWorkspaceReconciler) syncHeadlessRun(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (updated bool, err error) {
+	attributes := workspace.Spec.Template.Attributes
+	if !attributes.Exists(constants.HeadlessCommandAttribute) {
+		return false, nil
+	}
+	startedAt, ok := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]
+	if !ok {
+		// The started-at annotation is set once the DevWorkspace is running
+		return false, nil
+	}
+	var attrErr error
+	commandId := attributes.GetString(constants.HeadlessCommandAttribute, &attrErr)
+	if attrErr != nil {
+		return true, r.finishHeadlessRun(ctx, workspace, nil, fmt.Sprintf("Invalid %s attribute: %s", constants.HeadlessCommandAttribute, attrErr), logger)
+	}
+
+	task := &controllerv1alpha1.DevWorkspaceTask{}
+	taskNN := types.NamespacedName{Name: common.HeadlessTaskName(workspace.Status.DevWorkspaceId, startedAt), Namespace: workspace.Namespace}
+	err = r.Get(ctx, taskNN, task)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if _, hasResult := workspace.Annotations[constants.DevWorkspaceHeadlessResultAnnotation]; hasResult {
+			// Clear the result of the previous run before starting a new one
+			delete(workspace.Annotations, constants.DevWorkspaceHeadlessExitCodeAnnotation)
+			delete(workspace.Annotations, constants.DevWorkspaceHeadlessResultAnnotation)
+			return true, r.Update(ctx, workspace.DevWorkspace)
+		}
+		specTask, err := getSpecHeadlessTask(workspace, commandId, taskNN.Name)
+		if err != nil {
+			return true, r.finishHeadlessRun(ctx, workspace, nil, err.Error(), logger)
+		}
+		if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specTask, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.deleteHeadlessTasks(ctx, workspace); err != nil {
+			return false, err
+		}
+		logger.Info("Running headless command", "command", commandId, "task", specTask.Name)
+		if err := r.Create(ctx, specTask); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	switch task.Status.Phase {
+	case controllerv1alpha1.TaskPhaseSucceeded, controllerv1alpha1.TaskPhaseFailed:
+		result := fmt.Sprintf("Command %s: %s", commandId, task.Status.Message)
+		return true, r.finishHeadlessRun(ctx, workspace, task.Status.ExitCode, result, logger)
+	default:
+		// Status changes of the task trigger a reconcile, as the task is owned by the DevWorkspace
+		return false, nil
+	}
+}
+
+// finishHeadlessRun records the result of a headless run on a DevWorkspace and stops it.
+func (r *DevWorkspaceReconciler) finishHeadlessRun(ctx context.Context, workspace *common.DevWorkspaceWithConfig, exitCode *int32, result string, logger logr.Logger) error {
+	logger.Info("Stopping DevWorkspace as headless command completed", "result", result)
+	if exitCode != nil {
+		workspace.Annotations[constants.DevWorkspaceHeadlessExitCodeAnnotation] = strconv.Itoa(int(*exitCode))
+	} else {
+		delete(workspace.Annotations, constants.DevWorkspaceHeadlessExitCodeAnnotation)
+	}
+	workspace.Annotations[constants.DevWorkspaceHeadlessResultAnnotation] = result
+	workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = constants.DevWorkspaceStoppedByHeadlessRun
+	workspace.Spec.Started = false
+	return r.Update(ctx, workspace.DevWorkspace)
+}
+
+// getSpecHeadlessTask returns the DevWorkspaceTask that runs a DevWorkspace's headless command in its pod. Returns an
+// error if the HeadlessTimeoutAttribute is invalid.
+func getSpecHeadlessTask(workspace *common.DevWorkspaceWithConfig, commandId, name string) (*controllerv1alpha1.DevWorkspaceTask, error) {
+	task := &controllerv1alpha1.DevWorkspaceTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:           workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceHeadlessTaskLabel: "true",
+			},
+		},
+		Spec: controllerv1alpha1.DevWorkspaceTaskSpec{
+			DevWorkspaceName: workspace.Name,
+			CommandId:        commandId,
+			Mode:             controllerv1alpha1.TaskModeExec,
+			OutputConfigMap:  common.HeadlessOutputConfigMapName(workspace.Status.DevWorkspaceId),
+		},
+	}
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.HeadlessTimeoutAttribute) {
+		var attrErr error
+		timeoutStr := attributes.GetString(constants.HeadlessTimeoutAttribute, &attrErr)
+		if attrErr != nil {
+			return nil, &dwerrors.FailError{Message: fmt.Sprintf("Invalid %s attribute: %s", constants.HeadlessTimeoutAttribute, attrErr)}
+		}
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout < time.Second {
+			return nil, &dwerrors.FailError{Message: fmt.Sprintf("Invalid %s attribute: must be a duration of at least 1s, e.g. 30m", constants.HeadlessTimeoutAttribute)}
+		}
+		timeoutSeconds := int64(timeout.Seconds())
+		task.Spec.TimeoutSeconds = &timeoutSeconds
+	}
+	return task, nil
+}
+
+// deleteHeadlessTasks deletes the DevWorkspaceTasks created for previous headless runs of a DevWorkspace.
+func (r *DevWorkspaceReconciler) deleteHeadlessTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig) error {
+	taskList := &controllerv1alpha1.DevWorkspaceTaskList{}
+	labels := client.MatchingLabels{
+		constants.DevWorkspaceIDLabel:           workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceHeadlessTaskLabel: "true",
+	}
+	if err := r.List(ctx, taskList, client.InNamespace(workspace.Namespace), labels); err != nil {
+		return err
+	}
+	for _, task := range taskList.Items {
+		if err := r.Delete(ctx, &task); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
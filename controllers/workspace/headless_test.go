@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getHeadlessTestWorkspace(attrs attributes.Attributes) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		Config:       &v1alpha1.OperatorConfiguration{},
+	}
+	workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation] = "1700000000000"
+	workspace.Spec.Template.Attributes = attrs
+	return workspace
+}
+
+func getHeadlessTask(t *testing.T, r *DevWorkspaceReconciler) *v1alpha1.DevWorkspaceTask {
+	task := &v1alpha1.DevWorkspaceTask{}
+	taskNN := types.NamespacedName{Name: common.HeadlessTaskName("test-workspaceid", "1700000000000"), Namespace: "test-namespace"}
+	require.NoError(t, r.Get(context.Background(), taskNN, task))
+	return task
+}
+
+func TestSyncHeadlessRunCreatesTask(t *testing.T) {
+	workspace := getHeadlessTestWorkspace(attributes.Attributes{}.
+		PutString(constants.HeadlessCommandAttribute, "build").
+		PutString(constants.HeadlessTimeoutAttribute, "1h"))
+	oldTask := &v1alpha1.DevWorkspaceTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.HeadlessTaskName("test-workspaceid", "1600000000000"),
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:           "test-workspaceid",
+				constants.DevWorkspaceHeadlessTaskLabel: "true",
+			},
+		},
+	}
+	r := getIdleTestReconciler(workspace.DevWorkspace, oldTask)
+
+	updated, err := r.syncHeadlessRun(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	task := getHeadlessTask(t, r)
+	assert.Equal(t, "build", task.Spec.CommandId)
+	assert.Equal(t, v1alpha1.TaskModeExec, task.Spec.Mode)
+	assert.Equal(t, pointer.Int64(3600), task.Spec.TimeoutSeconds)
+	assert.Equal(t, common.HeadlessOutputConfigMapName("test-workspaceid"), task.Spec.OutputConfigMap)
+	assert.True(t, metav1.IsControlledBy(task, workspace.DevWorkspace), "Task should be owned by DevWorkspace")
+	err = r.Get(context.Background(), types.NamespacedName{Name: oldTask.Name, Namespace: "test-namespace"}, &v1alpha1.DevWorkspaceTask{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete tasks from previous runs")
+}
+
+func TestSyncHeadlessRunStopsWorkspaceWhenCommandCompletes(t *testing.T) {
+	workspace := getHeadlessTestWorkspace(attributes.Attributes{}.PutString(constants.HeadlessCommandAttribute, "build"))
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+	_, err := r.syncHeadlessRun(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+
+	task := getHeadlessTask(t, r)
+	task.Status.Phase = v1alpha1.TaskPhaseFailed
+	task.Status.Message = "Command exited with code 2"
+	task.Status.ExitCode = pointer.Int32(2)
+	require.NoError(t, r.Status().Update(context.Background(), task))
+
+	updated, err := r.syncHeadlessRun(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.False(t, clusterWorkspace.Spec.Started, "Should stop workspace")
+	assert.Equal(t, constants.DevWorkspaceStoppedByHeadlessRun, clusterWorkspace.Annotations[constants.DevWorkspaceStopReasonAnnotation])
+	assert.Equal(t, "2", clusterWorkspace.Annotations[constants.DevWorkspaceHeadlessExitCodeAnnotation])
+	assert.Equal(t, "Command build: Command exited with code 2", clusterWorkspace.Annotations[constants.DevWorkspaceHeadlessResultAnnotation])
+}
+
+func TestSyncHeadlessRunClearsPreviousResult(t *testing.T) {
+	workspace := getHeadlessTestWorkspace(attributes.Attributes{}.PutString(constants.HeadlessCommandAttribute, "build"))
+	workspace.Annotations[constants.DevWorkspaceHeadlessExitCodeAnnotation] = "0"
+	workspace.Annotations[constants.DevWorkspaceHeadlessResultAnnotation] = "Command build: Command completed"
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.syncHeadlessRun(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.True(t, clusterWorkspace.Spec.Started)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceHeadlessExitCodeAnnotation)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceHeadlessResultAnnotation)
+}
+
+func TestSyncHeadlessRunStopsWorkspaceForInvalidTimeout(t *testing.T) {
+	workspace := getHeadlessTestWorkspace(attributes.Attributes{}.
+		PutString(constants.HeadlessCommandAttribute, "build").
+		PutString(constants.HeadlessTimeoutAttribute, "forever"))
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.syncHeadlessRun(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.False(t, clusterWorkspace.Spec.Started)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceHeadlessExitCodeAnnotation)
+	assert.Equal(t, "Invalid controller.devfile.io/headless-timeout attribute: must be a duration of at least 1s, e.g. 30m",
+		clusterWorkspace.Annotations[constants.DevWorkspaceHeadlessResultAnnotation])
+}
+
+func TestSyncHeadlessRunIgnoresRegularWorkspaces(t *testing.T) {
+	workspace := getHeadlessTestWorkspace(nil)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.syncHeadlessRun(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	taskList := &v1alpha1.DevWorkspaceTaskList{}
+	require.NoError(t, r.List(context.Background(), taskList))
+	assert.Empty(t, taskList.Items)
+}
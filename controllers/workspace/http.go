@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 
 	"k8s.io/apimachinery/pkg/types"
 
@@ -35,6 +36,8 @@ import (
 var (
 	httpClient            *http.Client
 	healthCheckHttpClient *http.Client
+	// pluginRegistryCache caches plugins and parents resolved by ID from a devfile registry
+	pluginRegistryCache = network.NewTemplateCache()
 )
 
 func setupHttpClients(k8s client.Client, logger logr.Logger) {
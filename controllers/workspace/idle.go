@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/metadata"
+)
+
+// idleWarningHttpClient is used to notify DevWorkspaces that they are about to be stopped. Requests are sent to the
+// DevWorkspace's service, so the cluster proxy is not used.
+var idleWarningHttpClient = &http.Client{
+	Timeout: 5 * time.Second,
+}
+
+// idleWarning is the body of requests sent to endpoints with the IdleWarningPathAttribute.
+type idleWarning struct {
+	Namespace string    `json:"namespace"`
+	Workspace string    `json:"workspace"`
+	StopAt    time.Time `json:"stopAt"`
+}
+
+// checkIdleStop stops a running DevWorkspace once it has been idle for longer than the idle timeout, based on the
+// activity recorded in the DevWorkspaceLastActivityAnnotation. Once the DevWorkspace enters the idle warning period,
+// the DevWorkspaceIdleStopAtAnnotation is set and endpoints with the IdleWarningPathAttribute are notified. Returns
+// whether the DevWorkspace was updated on the cluster and, if not, how long until it needs to be checked again. A zero
+// duration means the DevWorkspace is not stopped when idle, e.g. as it has the DevWorkspaceDebugStartAnnotation.
+func (r *DevWorkspaceReconciler) checkIdleStop(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (updated bool, recheckAfter time.Duration, err error) {
+	lastActivityValue, ok := workspace.Annotations[constants.DevWorkspaceLastActivityAnnotation]
+	if !ok {
+		return false, 0, nil
+	}
+	if workspace.Annotations[constants.DevWorkspaceDebugStartAnnotation] == "true" {
+		// Workspaces being debugged are not stopped while users inspect them
+		if _, warned := workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation]; warned {
+			delete(workspace.Annotations, constants.DevWorkspaceIdleStopAtAnnotation)
+			return true, 0, r.Update(ctx, workspace.DevWorkspace)
+		}
+		return false, 0, nil
+	}
+	idleTimeout, err := time.ParseDuration(workspace.Config.Workspace.IdleTimeout)
+	if err != nil || idleTimeout <= 0 {
+		return false, 0, nil
+	}
+	warningPeriod, err := time.ParseDuration(workspace.Config.Workspace.IdleWarningPeriod)
+	if err != nil || warningPeriod < 0 {
+		warningPeriod = 0
+	}
+	lastActivity, err := time.Parse(time.RFC3339, lastActivityValue)
+	if err != nil {
+		logger.Info(fmt.Sprintf("Ignoring invalid %s annotation", constants.DevWorkspaceLastActivityAnnotation), "error", err.Error())
+		return false, 0, nil
+	}
+	// Activity recorded before the DevWorkspace was started does not count, as it could otherwise be stopped as
+	// soon as it is started again
+	if startedAtMillis, ok := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]; ok {
+		if millis, err := strconv.ParseInt(startedAtMillis, 10, 64); err == nil && time.UnixMilli(millis).After(lastActivity) {
+			lastActivity = time.UnixMilli(millis)
+		}
+	}
+
+	now := clock.Now()
+	stopAt := lastActivity.Add(idleTimeout)
+	warnAt := stopAt.Add(-warningPeriod)
+	switch {
+	case !now.Before(stopAt):
+		logger.Info("Stopping idle DevWorkspace", "lastActivity", lastActivity.UTC().Format(time.RFC3339))
+		workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = constants.DevWorkspaceStoppedByInactivity
+		delete(workspace.Annotations, constants.DevWorkspaceIdleStopAtAnnotation)
+		workspace.Spec.Started = false
+		return true, 0, r.Update(ctx, workspace.DevWorkspace)
+	case !now.Before(warnAt):
+		stopAtValue := stopAt.UTC().Format(time.RFC3339)
+		if workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation] == stopAtValue {
+			return false, stopAt.Sub(now), nil
+		}
+		logger.Info("Warning idle DevWorkspace before stopping it", "stopAt", stopAtValue)
+		workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation] = stopAtValue
+		if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+			return false, 0, err
+		}
+		r.sendIdleWarnings(ctx, workspace, stopAt, logger)
+		return true, 0, nil
+	default:
+		if _, warned := workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation]; warned {
+			// Activity was recorded after the DevWorkspace was warned
+			delete(workspace.Annotations, constants.DevWorkspaceIdleStopAtAnnotation)
+			return true, 0, r.Update(ctx, workspace.DevWorkspace)
+		}
+		return false, warnAt.Sub(now), nil
+	}
+}
+
+// sendIdleWarnings notifies the endpoints of a DevWorkspace that have the IdleWarningPathAttribute that the
+// DevWorkspace will be stopped at stopAt. Endpoints are read from the flattened DevWorkspace, so that endpoints
+// contributed by plugins (e.g. an editor) are included. Failures are logged, but do not prevent the DevWorkspace from
+// being stopped.
+func (r *DevWorkspaceReconciler) sendIdleWarnings(ctx context.Context, workspace *common.DevWorkspaceWithConfig, stopAt time.Time, logger logr.Logger) {
+	flattened, err := metadata.GetFlattenedTemplate(ctx, r.Client, workspace.DevWorkspace)
+	if err != nil {
+		logger.Error(err, "Failed to read flattened DevWorkspace to send idle warnings")
+		return
+	}
+	if flattened == nil {
+		return
+	}
+	body, err := json.Marshal(idleWarning{Namespace: workspace.Namespace, Workspace: workspace.Name, StopAt: stopAt.UTC()})
+	if err != nil {
+		logger.Error(err, "Failed to marshal idle warning")
+		return
+	}
+	for _, component := range flattened.Components {
+		if component.Container == nil {
+			continue
+		}
+		for _, endpoint := range component.Container.Endpoints {
+			if !endpoint.Attributes.Exists(constants.IdleWarningPathAttribute) {
+				continue
+			}
+			var attrErr error
+			path := endpoint.Attributes.GetString(constants.IdleWarningPathAttribute, &attrErr)
+			if attrErr != nil {
+				logger.Info(fmt.Sprintf("Ignoring invalid %s attribute", constants.IdleWarningPathAttribute), "endpoint", endpoint.Name, "error", attrErr.Error())
+				continue
+			}
+			url := fmt.Sprintf("http://%s.%s.svc:%d%s", common.ServiceName(workspace.Status.DevWorkspaceId, workspace.Annotations), workspace.Namespace, endpoint.TargetPort, path)
+			if err := postIdleWarning(ctx, url, body); err != nil {
+				logger.Info("Failed to send idle warning", "endpoint", endpoint.Name, "error", err.Error())
+			}
+		}
+	}
+}
+
+func postIdleWarning(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := idleWarningHttpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint responded with status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kubeclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// recordingTransport records requests and responds to them with 200 OK.
+type recordingTransport struct {
+	urls   []string
+	bodies []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.urls = append(t.urls, req.URL.String())
+	t.bodies = append(t.bodies, string(body))
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func getIdleTestWorkspace(lastActivity time.Time) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				IdleTimeout:       "15m",
+				IdleWarningPeriod: "5m",
+			},
+		},
+	}
+	workspace.Annotations[constants.DevWorkspaceLastActivityAnnotation] = lastActivity.UTC().Format(time.RFC3339)
+	return workspace
+}
+
+// getIdleTestMetadataConfigMap returns a metadata configmap for the test workspace whose flattened DevWorkspace has an
+// endpoint with the idle warning attribute.
+func getIdleTestMetadataConfigMap(t *testing.T) *corev1.ConfigMap {
+	flattened := dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Components: []dw.Component{
+				{
+					Name: "editor",
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{
+							Endpoints: []dw.Endpoint{
+								{
+									Name:       "ide",
+									TargetPort: 3100,
+									Attributes: attributes.Attributes{}.PutString(constants.IdleWarningPathAttribute, "/api/idle-warning"),
+								},
+								{
+									Name:       "debug",
+									TargetPort: 5005,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	flattenedYaml, err := yaml.Marshal(flattened)
+	require.NoError(t, err)
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.MetadataConfigMapName("test-workspaceid"),
+			Namespace: "test-namespace",
+		},
+		Data: map[string]string{
+			"flattened.devworkspace.yaml": string(flattenedYaml),
+		},
+	}
+}
+
+func getIdleTestReconciler(objs ...client.Object) *DevWorkspaceReconciler {
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(v1alpha1.AddToScheme(testScheme))
+	utilruntime.Must(dw.AddToScheme(testScheme))
+	return &DevWorkspaceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build(),
+		Log:    zap.New(),
+		Scheme: testScheme,
+	}
+}
+
+func setupIdleTest(t *testing.T) (now time.Time, transport *recordingTransport) {
+	now = time.Now().Truncate(time.Second)
+	oldClock, oldHttpClient := clock, idleWarningHttpClient
+	clock = kubeclock.NewFakeClock(now)
+	transport = &recordingTransport{}
+	idleWarningHttpClient = &http.Client{Transport: transport}
+	t.Cleanup(func() {
+		clock = oldClock
+		idleWarningHttpClient = oldHttpClient
+	})
+	return now, transport
+}
+
+func getClusterWorkspace(t *testing.T, r *DevWorkspaceReconciler) *dw.DevWorkspace {
+	clusterWorkspace := &dw.DevWorkspace{}
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-workspace", Namespace: "test-namespace"}, clusterWorkspace))
+	return clusterWorkspace
+}
+
+func TestCheckIdleStopActiveWorkspace(t *testing.T) {
+	now, transport := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-1 * time.Minute))
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, recheckAfter, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, 9*time.Minute, recheckAfter, "Should recheck workspace when warning period starts")
+	assert.Empty(t, transport.urls)
+}
+
+func TestCheckIdleStopWarnsWorkspace(t *testing.T) {
+	now, transport := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-12 * time.Minute))
+	r := getIdleTestReconciler(workspace.DevWorkspace, getIdleTestMetadataConfigMap(t))
+
+	updated, _, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	stopAt := now.Add(3 * time.Minute).UTC().Format(time.RFC3339)
+	assert.Equal(t, stopAt, getClusterWorkspace(t, r).Annotations[constants.DevWorkspaceIdleStopAtAnnotation])
+	require.Len(t, transport.urls, 1, "Should only notify endpoints with idle warning attribute")
+	assert.Equal(t, "http://"+common.ServiceName("test-workspaceid", nil)+".test-namespace.svc:3100/api/idle-warning", transport.urls[0])
+	warning := &idleWarning{}
+	require.NoError(t, json.Unmarshal([]byte(transport.bodies[0]), warning))
+	assert.Equal(t, "test-workspace", warning.Workspace)
+	assert.Equal(t, stopAt, warning.StopAt.Format(time.RFC3339))
+
+	// Reconciling again should not warn the workspace again
+	workspace.DevWorkspace = getClusterWorkspace(t, r)
+	updated, recheckAfter, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, 3*time.Minute, recheckAfter, "Should recheck workspace when it needs to be stopped")
+	assert.Len(t, transport.urls, 1)
+}
+
+func TestCheckIdleStopStopsWorkspace(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-15 * time.Minute))
+	workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation] = now.UTC().Format(time.RFC3339)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, _, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.False(t, clusterWorkspace.Spec.Started, "Should stop idle workspace")
+	assert.Equal(t, constants.DevWorkspaceStoppedByInactivity, clusterWorkspace.Annotations[constants.DevWorkspaceStopReasonAnnotation])
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceIdleStopAtAnnotation)
+}
+
+func TestCheckIdleStopKeepalive(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-1 * time.Minute))
+	workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation] = now.Add(time.Minute).UTC().Format(time.RFC3339)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, _, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.True(t, clusterWorkspace.Spec.Started)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceIdleStopAtAnnotation, "Should remove warning once activity is recorded")
+}
+
+func TestCheckIdleStopIgnoresActivityBeforeStart(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-2 * time.Hour))
+	workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation] = strconv.FormatInt(now.Add(-1*time.Minute).UnixMilli(), 10)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, recheckAfter, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated, "Should not stop workspace that was started after last activity")
+	assert.Equal(t, 9*time.Minute, recheckAfter)
+}
+
+func TestCheckIdleStopWithoutActivity(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-2 * time.Hour))
+	delete(workspace.Annotations, constants.DevWorkspaceLastActivityAnnotation)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, recheckAfter, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated, "Should not stop workspaces that do not record activity")
+	assert.Zero(t, recheckAfter)
+}
+
+func TestCheckIdleStopIgnoresDebuggedWorkspace(t *testing.T) {
+	now, transport := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now.Add(-2 * time.Hour))
+	workspace.Annotations[constants.DevWorkspaceDebugStartAnnotation] = "true"
+	workspace.Annotations[constants.DevWorkspaceIdleStopAtAnnotation] = now.Add(time.Minute).UTC().Format(time.RFC3339)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, recheckAfter, err := r.checkIdleStop(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Zero(t, recheckAfter)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.True(t, clusterWorkspace.Spec.Started, "Should not stop workspace in debug mode")
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceIdleStopAtAnnotation, "Should remove idle warning")
+	assert.Empty(t, transport.urls)
+}
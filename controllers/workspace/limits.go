@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// limitsRejectPolicy is the workspace.limits.exceededPolicy that fails DevWorkspaces that would exceed the limits
+	// on running DevWorkspaces, instead of queueing them.
+	limitsRejectPolicy = "Reject"
+
+	// queuedWorkspaceRequeueInterval is how often queued DevWorkspaces check whether they can be started.
+	queuedWorkspaceRequeueInterval = 30 * time.Second
+)
+
+// checkWorkspaceResourceLimits returns a message describing which resources requested by the containers in
+// podAdditions exceed workspace.limits.maxResources. Returns an empty string if resources are within limits.
+func checkWorkspaceResourceLimits(limits *controllerv1alpha1.WorkspaceLimitsConfig, podAdditions *controllerv1alpha1.PodAdditions) string {
+	if limits == nil || len(limits.MaxResources) == 0 {
+		return ""
+	}
+	requested := getRequestedResources(podAdditions.Containers)
+	var exceeded []string
+	for resourceName, max := range limits.MaxResources {
+		total, ok := requested[resourceName]
+		if ok && total.Cmp(max) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s (requested %s, maximum %s)", resourceName, total.String(), max.String()))
+		}
+	}
+	if len(exceeded) == 0 {
+		return ""
+	}
+	sort.Strings(exceeded)
+	return fmt.Sprintf("DevWorkspace requests more resources than allowed: %s", strings.Join(exceeded, ", "))
+}
+
+// getRequestedResources sums the resources requested by containers. For containers that do not request a resource,
+// their limit is used instead, matching how requests are defaulted by Kubernetes.
+func getRequestedResources(containers []corev1.Container) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	add := func(resourceName corev1.ResourceName, quantity resource.Quantity) {
+		sum := total[resourceName]
+		sum.Add(quantity)
+		total[resourceName] = sum
+	}
+	for _, container := range containers {
+		for resourceName, quantity := range container.Resources.Requests {
+			add(resourceName, quantity)
+		}
+		for resourceName, quantity := range container.Resources.Limits {
+			if _, hasRequest := container.Resources.Requests[resourceName]; !hasRequest {
+				add(resourceName, quantity)
+			}
+		}
+	}
+	return total
+}
+
+// checkRunningWorkspaceLimits returns a message explaining why a DevWorkspace cannot be started if starting it would
+// exceed workspace.limits.maxRunningPerUser or workspace.limits.maxRunningPerNamespace. DevWorkspaces that have already
+// been admitted (see isAdmittedWorkspace) are not checked again. Returns an empty string if the DevWorkspace may start.
+func (r *DevWorkspaceReconciler) checkRunningWorkspaceLimits(ctx context.Context, workspace *common.DevWorkspaceWithConfig) (string, error) {
+	limits := workspace.Config.Workspace.Limits
+	if limits == nil || (limits.MaxRunningPerUser == nil && limits.MaxRunningPerNamespace == nil) {
+		return "", nil
+	}
+	if isAdmittedWorkspace(workspace.DevWorkspace) {
+		return "", nil
+	}
+
+	if limits.MaxRunningPerNamespace != nil {
+		workspaces := &dw.DevWorkspaceList{}
+		if err := r.List(ctx, workspaces, client.InNamespace(workspace.Namespace)); err != nil {
+			return "", err
+		}
+		if running := countAdmittedWorkspaces(workspaces.Items, workspace.DevWorkspace); running >= int(*limits.MaxRunningPerNamespace) {
+			return fmt.Sprintf("Namespace %s already has %d running DevWorkspaces (maximum %d)", workspace.Namespace, running, *limits.MaxRunningPerNamespace), nil
+		}
+	}
+
+	creator := workspace.Labels[constants.DevWorkspaceCreatorLabel]
+	if limits.MaxRunningPerUser != nil && creator != "" {
+		workspaces := &dw.DevWorkspaceList{}
+		if err := r.List(ctx, workspaces, client.MatchingLabels{constants.DevWorkspaceCreatorLabel: creator}); err != nil {
+			return "", err
+		}
+		if running := countAdmittedWorkspaces(workspaces.Items, workspace.DevWorkspace); running >= int(*limits.MaxRunningPerUser) {
+			return fmt.Sprintf("The creator of this DevWorkspace already has %d running DevWorkspaces (maximum %d)", running, *limits.MaxRunningPerUser), nil
+		}
+	}
+	return "", nil
+}
+
+// countAdmittedWorkspaces counts the DevWorkspaces in workspaces, other than current, that are started and have been
+// admitted by the running workspace limits.
+func countAdmittedWorkspaces(workspaces []dw.DevWorkspace, current *dw.DevWorkspace) int {
+	count := 0
+	for idx := range workspaces {
+		workspace := &workspaces[idx]
+		if workspace.Namespace == current.Namespace && workspace.Name == current.Name {
+			continue
+		}
+		if workspace.Spec.Started && isAdmittedWorkspace(workspace) {
+			count++
+		}
+	}
+	return count
+}
+
+// isAdmittedWorkspace returns whether a DevWorkspace is running, or is starting and has the LimitsSatisfied condition.
+// DevWorkspaces that are starting without having been admitted yet (e.g. because they are queued) are not counted
+// towards running workspace limits.
+func isAdmittedWorkspace(workspace *dw.DevWorkspace) bool {
+	switch workspace.Status.Phase {
+	case dw.DevWorkspaceStatusRunning:
+		return true
+	case dw.DevWorkspaceStatusStarting:
+		condition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.LimitsSatisfied)
+		return condition != nil && condition.Status == corev1.ConditionTrue
+	default:
+		return false
+	}
+}
+
+// hasLimits returns whether any limits are configured in workspace.limits.
+func hasLimits(limits *controllerv1alpha1.WorkspaceLimitsConfig) bool {
+	return limits != nil && (len(limits.MaxResources) > 0 || limits.MaxRunningPerUser != nil || limits.MaxRunningPerNamespace != nil)
+}
+
+// isQueuedWorkspace returns whether a DevWorkspace is waiting to start due to running workspace limits or the
+// start queue.
+func isQueuedWorkspace(workspace *common.DevWorkspaceWithConfig) bool {
+	if isInStartQueue(workspace.DevWorkspace) {
+		return true
+	}
+	condition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.LimitsSatisfied)
+	return condition != nil && condition.Status == corev1.ConditionFalse
+}
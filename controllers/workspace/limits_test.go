@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getLimitsTestWorkspace(name, namespace, creator string, started bool, phase dw.DevWorkspacePhase, limitsSatisfied corev1.ConditionStatus) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, phase)
+	workspace.Namespace = namespace
+	workspace.Labels[constants.DevWorkspaceCreatorLabel] = creator
+	workspace.Spec.Started = started
+	if limitsSatisfied != "" {
+		workspace.Status.Conditions = []dw.DevWorkspaceCondition{{Type: conditions.LimitsSatisfied, Status: limitsSatisfied}}
+	}
+	return workspace
+}
+
+func getLimitsTestReconciler(objs ...client.Object) *DevWorkspaceReconciler {
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(dw.AddToScheme(testScheme))
+	return &DevWorkspaceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build(),
+		Log:    zap.New(),
+		Scheme: testScheme,
+	}
+}
+
+func TestCheckWorkspaceResourceLimits(t *testing.T) {
+	limits := &v1alpha1.WorkspaceLimitsConfig{
+		MaxResources: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+	podAdditions := &v1alpha1.PodAdditions{
+		Containers: []corev1.Container{
+			{
+				Name: "tools",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+				},
+			},
+			{
+				Name: "sidecar",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+				},
+			},
+		},
+	}
+	assert.Empty(t, checkWorkspaceResourceLimits(limits, podAdditions), "Resources equal to the maximum should be allowed")
+
+	podAdditions.Containers[1].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("3Gi")
+	podAdditions.Containers[1].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("1")
+	assert.Equal(t, "DevWorkspace requests more resources than allowed: cpu (requested 2500m, maximum 2), memory (requested 5Gi, maximum 4Gi)",
+		checkWorkspaceResourceLimits(limits, podAdditions))
+}
+
+func TestCheckRunningWorkspaceLimitsPerNamespace(t *testing.T) {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: getLimitsTestWorkspace("current", "test-namespace", "user-a", true, dw.DevWorkspaceStatusStarting, ""),
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				Limits: &v1alpha1.WorkspaceLimitsConfig{MaxRunningPerNamespace: pointer.Int32(2)},
+			},
+		},
+	}
+	reconciler := getLimitsTestReconciler(
+		workspace.DevWorkspace,
+		getLimitsTestWorkspace("running", "test-namespace", "user-b", true, dw.DevWorkspaceStatusRunning, ""),
+		getLimitsTestWorkspace("queued", "test-namespace", "user-b", true, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse),
+		getLimitsTestWorkspace("stopped", "test-namespace", "user-b", false, dw.DevWorkspaceStatusStopped, ""),
+		getLimitsTestWorkspace("other-namespace", "other-namespace", "user-b", true, dw.DevWorkspaceStatusRunning, ""),
+	)
+
+	msg, err := reconciler.checkRunningWorkspaceLimits(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Empty(t, msg, "Only running and admitted workspaces should be counted")
+
+	admitted := getLimitsTestWorkspace("admitted", "test-namespace", "user-b", true, dw.DevWorkspaceStatusStarting, corev1.ConditionTrue)
+	assert.NoError(t, reconciler.Create(context.Background(), admitted))
+	msg, err = reconciler.checkRunningWorkspaceLimits(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, "Namespace test-namespace already has 2 running DevWorkspaces (maximum 2)", msg)
+}
+
+func TestCheckRunningWorkspaceLimitsPerUser(t *testing.T) {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: getLimitsTestWorkspace("current", "test-namespace", "user-a", true, dw.DevWorkspaceStatusStarting, ""),
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				Limits: &v1alpha1.WorkspaceLimitsConfig{MaxRunningPerUser: pointer.Int32(1)},
+			},
+		},
+	}
+	reconciler := getLimitsTestReconciler(
+		workspace.DevWorkspace,
+		getLimitsTestWorkspace("other-user", "test-namespace", "user-b", true, dw.DevWorkspaceStatusRunning, ""),
+	)
+	msg, err := reconciler.checkRunningWorkspaceLimits(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Empty(t, msg)
+
+	assert.NoError(t, reconciler.Create(context.Background(),
+		getLimitsTestWorkspace("same-user", "other-namespace", "user-a", true, dw.DevWorkspaceStatusRunning, "")))
+	msg, err = reconciler.checkRunningWorkspaceLimits(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, "The creator of this DevWorkspace already has 1 running DevWorkspaces (maximum 1)", msg)
+
+	// Workspaces that were already admitted are not queued again
+	workspace.Status.Phase = dw.DevWorkspaceStatusRunning
+	msg, err = reconciler.checkRunningWorkspaceLimits(context.Background(), workspace)
+	assert.NoError(t, err)
+	assert.Empty(t, msg)
+}
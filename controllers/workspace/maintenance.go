@@ -0,0 +1,115 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	wkspConfig "github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const defaultMaintenanceMessage = "DevWorkspaces cannot be started while the cluster is under maintenance"
+
+// checkMaintenance stops a started DevWorkspace if the cluster is in maintenance mode. DevWorkspaces that are not
+// running yet are stopped immediately. Running DevWorkspaces are only stopped if stopRunningWorkspaces is enabled; if
+// a drain window is configured, the time at which the DevWorkspace will be stopped is stored in the
+// DevWorkspaceMaintenanceStopAtAnnotation first. Returns whether the DevWorkspace was updated on the cluster and, if
+// not, how long until it needs to be checked again. The maintenance configuration is read from the global config so
+// that it cannot be changed by external configuration.
+func (r *DevWorkspaceReconciler) checkMaintenance(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (updated bool, recheckAfter time.Duration, err error) {
+	config := wkspConfig.GetGlobalConfig().Workspace.Maintenance
+	stopAtValue, scheduled := workspace.Annotations[constants.DevWorkspaceMaintenanceStopAtAnnotation]
+	if config == nil || !pointer.BoolDeref(config.Enable, false) {
+		if scheduled {
+			// Maintenance mode was disabled before the DevWorkspace was stopped
+			delete(workspace.Annotations, constants.DevWorkspaceMaintenanceStopAtAnnotation)
+			return true, 0, r.Update(ctx, workspace.DevWorkspace)
+		}
+		return false, 0, nil
+	}
+
+	if _, running := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]; !running {
+		logger.Info("Stopping DevWorkspace as it cannot be started while the cluster is in maintenance mode")
+		return true, 0, r.stopForMaintenance(ctx, workspace)
+	}
+	if !pointer.BoolDeref(config.StopRunningWorkspaces, false) {
+		if scheduled {
+			delete(workspace.Annotations, constants.DevWorkspaceMaintenanceStopAtAnnotation)
+			return true, 0, r.Update(ctx, workspace.DevWorkspace)
+		}
+		return false, 0, nil
+	}
+
+	now := clock.Now()
+	stopAt, err := time.Parse(time.RFC3339, stopAtValue)
+	if !scheduled || err != nil {
+		stopAt = now.Add(getMaintenanceDrainOffset(workspace.DevWorkspace, config.DrainWindow))
+		if stopAt.After(now) {
+			stopAtValue := stopAt.UTC().Format(time.RFC3339)
+			logger.Info("Scheduling DevWorkspace to be stopped for maintenance", "stopAt", stopAtValue)
+			workspace.Annotations[constants.DevWorkspaceMaintenanceStopAtAnnotation] = stopAtValue
+			return true, 0, r.Update(ctx, workspace.DevWorkspace)
+		}
+	}
+	if now.Before(stopAt) {
+		return false, stopAt.Sub(now), nil
+	}
+	logger.Info("Stopping DevWorkspace as the cluster is in maintenance mode")
+	return true, 0, r.stopForMaintenance(ctx, workspace)
+}
+
+func (r *DevWorkspaceReconciler) stopForMaintenance(ctx context.Context, workspace *common.DevWorkspaceWithConfig) error {
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = constants.DevWorkspaceStoppedByMaintenance
+	delete(workspace.Annotations, constants.DevWorkspaceMaintenanceStopAtAnnotation)
+	workspace.Spec.Started = false
+	return r.Update(ctx, workspace.DevWorkspace)
+}
+
+// getMaintenanceDrainOffset returns how long after maintenance mode is enabled a running DevWorkspace is stopped. The
+// offset is derived from the DevWorkspace's UID, so that DevWorkspaces are spread evenly over the drain window. Returns
+// zero if no drain window is configured or if it cannot be parsed.
+func getMaintenanceDrainOffset(workspace *dw.DevWorkspace, drainWindow string) time.Duration {
+	if drainWindow == "" {
+		return 0
+	}
+	window, err := time.ParseDuration(drainWindow)
+	if err != nil || window <= 0 {
+		return 0
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(workspace.UID))
+	return time.Duration(hash.Sum64() % uint64(window))
+}
+
+// getStoppedForMaintenanceMessage returns the message for the StoppedForMaintenance condition.
+func getStoppedForMaintenanceMessage() string {
+	config := wkspConfig.GetGlobalConfig().Workspace.Maintenance
+	if config == nil || config.Message == "" {
+		return defaultMaintenanceMessage
+	}
+	return config.Message
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func setupMaintenanceTest(t *testing.T, maintenance *v1alpha1.MaintenanceConfig) time.Time {
+	now, _ := setupIdleTest(t)
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{Maintenance: maintenance},
+	})
+	t.Cleanup(func() { config.SetGlobalConfigForTesting(nil) })
+	return now
+}
+
+func getMaintenanceTestWorkspace(now time.Time, running bool) *common.DevWorkspaceWithConfig {
+	workspace := getIdleTestWorkspace(now)
+	workspace.UID = "test-uid"
+	if running {
+		workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation] = strconv.FormatInt(now.Add(-time.Hour).UnixMilli(), 10)
+	}
+	return workspace
+}
+
+func TestCheckMaintenanceDisabled(t *testing.T) {
+	now := setupMaintenanceTest(t, nil)
+	workspace := getMaintenanceTestWorkspace(now, false)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, recheckAfter, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.Zero(t, recheckAfter)
+	assert.True(t, getClusterWorkspace(t, r).Spec.Started)
+}
+
+func TestCheckMaintenanceBlocksStart(t *testing.T) {
+	now := setupMaintenanceTest(t, &v1alpha1.MaintenanceConfig{Enable: pointer.Bool(true), Message: "Back at 10:00"})
+	workspace := getMaintenanceTestWorkspace(now, false)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, _, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.False(t, clusterWorkspace.Spec.Started, "Should stop DevWorkspace that is starting")
+	assert.Equal(t, constants.DevWorkspaceStoppedByMaintenance, clusterWorkspace.Annotations[constants.DevWorkspaceStopReasonAnnotation])
+	assert.Equal(t, "Back at 10:00", getStoppedForMaintenanceMessage())
+}
+
+func TestCheckMaintenanceKeepsRunningWorkspaces(t *testing.T) {
+	now := setupMaintenanceTest(t, &v1alpha1.MaintenanceConfig{Enable: pointer.Bool(true)})
+	workspace := getMaintenanceTestWorkspace(now, true)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, recheckAfter, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.Zero(t, recheckAfter)
+	assert.True(t, getClusterWorkspace(t, r).Spec.Started, "Should not stop running DevWorkspace")
+	assert.Equal(t, defaultMaintenanceMessage, getStoppedForMaintenanceMessage())
+}
+
+func TestCheckMaintenanceStopsRunningWorkspaces(t *testing.T) {
+	now := setupMaintenanceTest(t, &v1alpha1.MaintenanceConfig{Enable: pointer.Bool(true), StopRunningWorkspaces: pointer.Bool(true)})
+	workspace := getMaintenanceTestWorkspace(now, true)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, _, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.False(t, clusterWorkspace.Spec.Started, "Should stop running DevWorkspace immediately without a drain window")
+	assert.Equal(t, constants.DevWorkspaceStoppedByMaintenance, clusterWorkspace.Annotations[constants.DevWorkspaceStopReasonAnnotation])
+}
+
+func TestCheckMaintenanceDrainsRunningWorkspaces(t *testing.T) {
+	now := setupMaintenanceTest(t, &v1alpha1.MaintenanceConfig{
+		Enable:                pointer.Bool(true),
+		StopRunningWorkspaces: pointer.Bool(true),
+		DrainWindow:           "1h",
+	})
+	workspace := getMaintenanceTestWorkspace(now, true)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, _, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.True(t, clusterWorkspace.Spec.Started, "Should not stop DevWorkspace before its time in the drain window")
+	offset := getMaintenanceDrainOffset(workspace.DevWorkspace, "1h")
+	stopAt := now.Add(offset)
+	assert.Equal(t, stopAt.UTC().Format(time.RFC3339), clusterWorkspace.Annotations[constants.DevWorkspaceMaintenanceStopAtAnnotation])
+
+	updated, recheckAfter, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, offset.Truncate(time.Second), recheckAfter, "Should recheck DevWorkspace when it is scheduled to stop")
+
+	workspace.Annotations[constants.DevWorkspaceMaintenanceStopAtAnnotation] = now.Add(-time.Second).UTC().Format(time.RFC3339)
+	updated, _, err = r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace = getClusterWorkspace(t, r)
+	assert.False(t, clusterWorkspace.Spec.Started, "Should stop DevWorkspace once drain time is reached")
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceMaintenanceStopAtAnnotation)
+}
+
+func TestCheckMaintenanceRemovesStopAtWhenDisabled(t *testing.T) {
+	now := setupMaintenanceTest(t, &v1alpha1.MaintenanceConfig{Enable: pointer.Bool(false)})
+	workspace := getMaintenanceTestWorkspace(now, true)
+	workspace.Annotations[constants.DevWorkspaceMaintenanceStopAtAnnotation] = now.Add(time.Minute).UTC().Format(time.RFC3339)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, _, err := r.checkMaintenance(context.Background(), workspace, zap.New())
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.True(t, clusterWorkspace.Spec.Started)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceMaintenanceStopAtAnnotation)
+}
+
+func TestGetMaintenanceDrainOffset(t *testing.T) {
+	workspace := getMaintenanceTestWorkspace(time.Now(), true)
+	offset := getMaintenanceDrainOffset(workspace.DevWorkspace, "30m")
+	assert.True(t, offset >= 0 && offset < 30*time.Minute, "Offset should be within drain window")
+	assert.Equal(t, offset, getMaintenanceDrainOffset(workspace.DevWorkspace, "30m"), "Offset should be stable")
+	assert.Zero(t, getMaintenanceDrainOffset(workspace.DevWorkspace, ""))
+	assert.Zero(t, getMaintenanceDrainOffset(workspace.DevWorkspace, "invalid"))
+}
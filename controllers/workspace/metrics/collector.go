@@ -0,0 +1,231 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	wkspConfig "github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/runningbudget"
+	"github.com/devfile/devworkspace-operator/pkg/library/usage"
+)
+
+var (
+	workspacesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("devworkspace", "", "workspaces"),
+		"Number of DevWorkspaces in each phase",
+		[]string{metricsPhaseLabel},
+		nil,
+	)
+	pvcCapacityDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("devworkspace", "pvc", "capacity_bytes"),
+		"Total capacity of PVCs used by DevWorkspaces, per namespace and storage type",
+		[]string{metricsNamespaceLabel, metricsStorageTypeLabel},
+		nil,
+	)
+	runningBudgetRemainingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("devworkspace", "running_budget", "remaining_seconds"),
+		"Remaining weekly running budget of DevWorkspaces that have a running budget",
+		[]string{metricsNamespaceLabel, metricsNameLabel},
+		nil,
+	)
+	usageRunningHoursDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("devworkspace", "usage", "running_hours_total"),
+		"Total time DevWorkspaces have been running, if usage accounting is enabled",
+		[]string{metricsNamespaceLabel, metricsNameLabel},
+		nil,
+	)
+	usageCPUHoursDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("devworkspace", "usage", "cpu_hours_total"),
+		"Total CPU core-hours requested by running DevWorkspaces, if usage accounting is enabled",
+		[]string{metricsNamespaceLabel, metricsNameLabel},
+		nil,
+	)
+	usageMemoryGiBHoursDesc = prometheus.NewDesc(
+		prometheus.BuildFQName("devworkspace", "usage", "memory_gib_hours_total"),
+		"Total memory GiB-hours requested by running DevWorkspaces, if usage accounting is enabled",
+		[]string{metricsNamespaceLabel, metricsNameLabel},
+		nil,
+	)
+)
+
+// workspaceCollector reports metrics computed from the current state of the cluster whenever metrics are scraped,
+// rather than from events observed by the controller. Objects are read from the manager's cache.
+type workspaceCollector struct {
+	client client.Reader
+	log    logr.Logger
+}
+
+// RegisterWorkspaceCollector registers a collector that reports the number of DevWorkspaces in each phase, the
+// capacity of PVCs used by DevWorkspaces, the remaining running budget of DevWorkspaces and the resource usage of
+// DevWorkspaces with the global prometheus registry.
+func RegisterWorkspaceCollector(reader client.Reader, log logr.Logger) error {
+	return ctrlmetrics.Registry.Register(&workspaceCollector{client: reader, log: log})
+}
+
+func (c *workspaceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- workspacesDesc
+	ch <- pvcCapacityDesc
+	ch <- runningBudgetRemainingDesc
+	ch <- usageRunningHoursDesc
+	ch <- usageCPUHoursDesc
+	ch <- usageMemoryGiBHoursDesc
+}
+
+func (c *workspaceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+	if err := c.collectWorkspacePhases(ctx, ch); err != nil {
+		c.log.Error(err, "Failed to collect DevWorkspace phase metrics")
+	}
+	if err := c.collectPVCCapacity(ctx, ch); err != nil {
+		c.log.Error(err, "Failed to collect DevWorkspace PVC metrics")
+	}
+	if err := c.collectRunningBudgets(ctx, ch); err != nil {
+		c.log.Error(err, "Failed to collect DevWorkspace running budget metrics")
+	}
+	if err := c.collectUsage(ctx, ch); err != nil {
+		c.log.Error(err, "Failed to collect DevWorkspace usage metrics")
+	}
+}
+
+func (c *workspaceCollector) collectWorkspacePhases(ctx context.Context, ch chan<- prometheus.Metric) error {
+	workspaceList := &dw.DevWorkspaceList{}
+	if err := c.client.List(ctx, workspaceList); err != nil {
+		return err
+	}
+	phases := map[dw.DevWorkspacePhase]int{
+		dw.DevWorkspaceStatusStarting: 0,
+		dw.DevWorkspaceStatusRunning:  0,
+		dw.DevWorkspaceStatusStopping: 0,
+		dw.DevWorkspaceStatusStopped:  0,
+		dw.DevWorkspaceStatusFailed:   0,
+	}
+	for _, workspace := range workspaceList.Items {
+		phase := workspace.Status.Phase
+		if phase == "" {
+			phase = "Unknown"
+		}
+		phases[phase]++
+	}
+	for phase, count := range phases {
+		ch <- prometheus.MustNewConstMetric(workspacesDesc, prometheus.GaugeValue, float64(count), string(phase))
+	}
+	return nil
+}
+
+func (c *workspaceCollector) collectPVCCapacity(ctx context.Context, ch chan<- prometheus.Metric) error {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := c.client.List(ctx, pvcList, client.HasLabels{constants.DevWorkspacePVCTypeLabel}); err != nil {
+		return err
+	}
+	type pvcKey struct {
+		namespace   string
+		storageType string
+	}
+	capacities := map[pvcKey]float64{}
+	for _, pvc := range pvcList.Items {
+		key := pvcKey{namespace: pvc.Namespace, storageType: pvc.Labels[constants.DevWorkspacePVCTypeLabel]}
+		// Use the requested size for PVCs that have not been bound yet
+		capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+		if !ok {
+			capacity = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+		capacities[key] += capacity.AsApproximateFloat64()
+	}
+	for key, capacity := range capacities {
+		ch <- prometheus.MustNewConstMetric(pvcCapacityDesc, prometheus.GaugeValue, capacity, key.namespace, key.storageType)
+	}
+	return nil
+}
+
+func (c *workspaceCollector) collectRunningBudgets(ctx context.Context, ch chan<- prometheus.Metric) error {
+	workspaceList := &dw.DevWorkspaceList{}
+	if err := c.client.List(ctx, workspaceList); err != nil {
+		return err
+	}
+	var budgetConfig *controllerv1alpha1.RunningBudgetConfig
+	if globalConfig := wkspConfig.GetGlobalConfig(); globalConfig != nil && globalConfig.Workspace != nil {
+		budgetConfig = globalConfig.Workspace.RunningBudget
+	}
+	now := time.Now()
+	for idx := range workspaceList.Items {
+		workspace := &workspaceList.Items[idx]
+		budget, hasBudget, err := runningbudget.GetBudget(workspace, budgetConfig)
+		if err != nil {
+			c.log.Info("Failed to read running budget for DevWorkspace", "namespace", workspace.Namespace, "name", workspace.Name, "error", err.Error())
+			continue
+		}
+		if !hasBudget {
+			continue
+		}
+		used, err := runningbudget.GetUsage(workspace, now)
+		if err != nil {
+			c.log.Info("Failed to read running budget usage for DevWorkspace", "namespace", workspace.Namespace, "name", workspace.Name, "error", err.Error())
+			continue
+		}
+		remaining := budget - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		ch <- prometheus.MustNewConstMetric(runningBudgetRemainingDesc, prometheus.GaugeValue, remaining.Seconds(), workspace.Namespace, workspace.Name)
+	}
+	return nil
+}
+
+func (c *workspaceCollector) collectUsage(ctx context.Context, ch chan<- prometheus.Metric) error {
+	globalConfig := wkspConfig.GetGlobalConfig()
+	if globalConfig == nil || globalConfig.Workspace == nil || globalConfig.Workspace.UsageAccounting == nil ||
+		globalConfig.Workspace.UsageAccounting.Enable == nil || !*globalConfig.Workspace.UsageAccounting.Enable {
+		return nil
+	}
+	workspaceList := &dw.DevWorkspaceList{}
+	if err := c.client.List(ctx, workspaceList); err != nil {
+		return err
+	}
+	deploymentList := &appsv1.DeploymentList{}
+	if err := c.client.List(ctx, deploymentList, client.HasLabels{constants.DevWorkspaceIDLabel}); err != nil {
+		return err
+	}
+	resources := map[string]usage.Resources{}
+	for idx := range deploymentList.Items {
+		deployment := &deploymentList.Items[idx]
+		resources[deployment.Labels[constants.DevWorkspaceIDLabel]] = usage.GetRequestedResources(&deployment.Spec.Template.Spec)
+	}
+	now := time.Now()
+	for idx := range workspaceList.Items {
+		workspace := &workspaceList.Items[idx]
+		total, err := usage.GetTotalUsage(workspace, resources[workspace.Status.DevWorkspaceId], now)
+		if err != nil {
+			c.log.Info("Failed to read usage for DevWorkspace", "namespace", workspace.Namespace, "name", workspace.Name, "error", err.Error())
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(usageRunningHoursDesc, prometheus.CounterValue, total.RunningHours, workspace.Namespace, workspace.Name)
+		ch <- prometheus.MustNewConstMetric(usageCPUHoursDesc, prometheus.CounterValue, total.CPUHours, workspace.Namespace, workspace.Name)
+		ch <- prometheus.MustNewConstMetric(usageMemoryGiBHoursDesc, prometheus.CounterValue, total.MemoryGiBHours, workspace.Namespace, workspace.Name)
+	}
+	return nil
+}
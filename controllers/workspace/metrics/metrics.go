@@ -25,6 +25,11 @@ const (
 	metricSourceLabel        = "source"
 	metricsRoutingClassLabel = "routingclass"
 	metricsReasonLabel       = "reason"
+	metricsPhaseLabel        = "phase"
+	metricsNamespaceLabel    = "namespace"
+	metricsNameLabel         = "name"
+	metricsStorageTypeLabel  = "storage_type"
+	metricsCanaryLabel       = "canary"
 )
 
 var (
@@ -73,9 +78,62 @@ var (
 			metricsRoutingClassLabel,
 		},
 	)
+	workspaceRolloutStarts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "rollout_started_total",
+			Help:      "Number of DevWorkspace starting events, by whether the DevWorkspace is a canary",
+		},
+		[]string{
+			metricsCanaryLabel,
+		},
+	)
+	workspaceRolloutFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "rollout_fail_total",
+			Help:      "Number of failed DevWorkspaces, by whether the DevWorkspace is a canary",
+		},
+		[]string{
+			metricsCanaryLabel,
+			metricsReasonLabel,
+		},
+	)
+	workspaceIdled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "idled_total",
+			Help:      "Number of DevWorkspaces stopped due to inactivity",
+		},
+		[]string{
+			metricSourceLabel,
+		},
+	)
+	workspaceMigrationPending = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "devworkspace",
+			Name:      "migration_pending",
+			Help:      "Number of running DevWorkspaces whose objects were generated by an older operator version in a deprecated shape",
+		},
+	)
+	workspaceMigrationReconciles = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "migration_reconciles_total",
+			Help:      "Number of reconciles triggered to migrate DevWorkspaces after an operator upgrade",
+		},
+	)
+	workspaceMigrated = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "migrated_total",
+			Help:      "Number of running DevWorkspaces reconciled by the current operator version after being reconciled by an older version",
+		},
+	)
 )
 
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(workspaceTotal, workspaceStarts, workspaceFailures, workspaceStartupTimesHist)
+	metrics.Registry.MustRegister(workspaceTotal, workspaceStarts, workspaceFailures, workspaceStartupTimesHist, workspaceIdled,
+		workspaceRolloutStarts, workspaceRolloutFailures, workspaceMigrationPending, workspaceMigrationReconciles, workspaceMigrated)
 }
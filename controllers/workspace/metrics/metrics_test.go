@@ -0,0 +1,241 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(dw.AddToScheme(scheme))
+}
+
+func TestWorkspaceCollector(t *testing.T) {
+	workspace := func(name string, phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+		return &dw.DevWorkspace{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+			Status:     dw.DevWorkspaceStatus{Phase: phase},
+		}
+	}
+	pvc := func(name, namespace, storageType string, capacity string, bound bool) *corev1.PersistentVolumeClaim {
+		claim := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{constants.DevWorkspacePVCTypeLabel: storageType},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+				},
+			},
+		}
+		if bound {
+			claim.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		}
+		return claim
+	}
+	unrelatedPVC := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ns-a"}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		workspace("running-1", dw.DevWorkspaceStatusRunning),
+		workspace("running-2", dw.DevWorkspaceStatusRunning),
+		workspace("failed", dw.DevWorkspaceStatusFailed),
+		pvc("claim-devworkspace", "ns-a", constants.PerUserStorageClassType, "10Gi", true),
+		pvc("storage-workspace1", "ns-a", constants.PerWorkspaceStorageClassType, "5Gi", false),
+		pvc("storage-workspace2", "ns-a", constants.PerWorkspaceStorageClassType, "5Gi", true),
+		pvc("claim-devworkspace", "ns-b", constants.PerUserStorageClassType, "1Gi", true),
+		unrelatedPVC,
+	).Build()
+	collector := &workspaceCollector{client: fakeClient, log: zap.New()}
+
+	expected := `
+# HELP devworkspace_workspaces Number of DevWorkspaces in each phase
+# TYPE devworkspace_workspaces gauge
+devworkspace_workspaces{phase="Failed"} 1
+devworkspace_workspaces{phase="Running"} 2
+devworkspace_workspaces{phase="Starting"} 0
+devworkspace_workspaces{phase="Stopped"} 0
+devworkspace_workspaces{phase="Stopping"} 0
+# HELP devworkspace_pvc_capacity_bytes Total capacity of PVCs used by DevWorkspaces, per namespace and storage type
+# TYPE devworkspace_pvc_capacity_bytes gauge
+devworkspace_pvc_capacity_bytes{namespace="ns-a",storage_type="per-user"} 1.073741824e+10
+devworkspace_pvc_capacity_bytes{namespace="ns-a",storage_type="per-workspace"} 1.073741824e+10
+devworkspace_pvc_capacity_bytes{namespace="ns-b",storage_type="per-user"} 1.073741824e+09
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestWorkspaceCollectorReportsUsage(t *testing.T) {
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			UsageAccounting: &v1alpha1.UsageAccountingConfig{Enable: pointer.Bool(true)},
+		},
+	})
+	t.Cleanup(func() { config.SetGlobalConfigForTesting(nil) })
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&dw.DevWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "stopped",
+				Namespace: "test-namespace",
+				Annotations: map[string]string{
+					constants.DevWorkspaceUsageAnnotation: `{"runningHours":10,"cpuHours":15,"memoryGiBHours":40}`,
+				},
+			},
+			Status: dw.DevWorkspaceStatus{Phase: dw.DevWorkspaceStatusStopped},
+		},
+	).Build()
+	collector := &workspaceCollector{client: fakeClient, log: zap.New()}
+
+	expected := `
+# HELP devworkspace_usage_running_hours_total Total time DevWorkspaces have been running, if usage accounting is enabled
+# TYPE devworkspace_usage_running_hours_total counter
+devworkspace_usage_running_hours_total{name="stopped",namespace="test-namespace"} 10
+# HELP devworkspace_usage_cpu_hours_total Total CPU core-hours requested by running DevWorkspaces, if usage accounting is enabled
+# TYPE devworkspace_usage_cpu_hours_total counter
+devworkspace_usage_cpu_hours_total{name="stopped",namespace="test-namespace"} 15
+# HELP devworkspace_usage_memory_gib_hours_total Total memory GiB-hours requested by running DevWorkspaces, if usage accounting is enabled
+# TYPE devworkspace_usage_memory_gib_hours_total counter
+devworkspace_usage_memory_gib_hours_total{name="stopped",namespace="test-namespace"} 40
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"devworkspace_usage_running_hours_total", "devworkspace_usage_cpu_hours_total", "devworkspace_usage_memory_gib_hours_total"))
+}
+
+func TestWorkspaceStoppedCountsIdling(t *testing.T) {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: &dw.DevWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{workspaceSourceLabel: "test-source"},
+			},
+		},
+	}
+	idledBefore := testutil.ToFloat64(workspaceIdled.WithLabelValues("test-source"))
+	WorkspaceStopped(workspace, zap.New())
+	assert.Equal(t, idledBefore, testutil.ToFloat64(workspaceIdled.WithLabelValues("test-source")), "Should not count workspaces stopped by user")
+
+	workspace.Annotations = map[string]string{constants.DevWorkspaceStopReasonAnnotation: "inactivity"}
+	WorkspaceStopped(workspace, zap.New())
+	assert.Equal(t, idledBefore+1, testutil.ToFloat64(workspaceIdled.WithLabelValues("test-source")), "Should count workspaces stopped due to inactivity")
+}
+
+func TestRolloutMetricsDistinguishCanaries(t *testing.T) {
+	newWorkspace := func(labels map[string]string) *common.DevWorkspaceWithConfig {
+		return &common.DevWorkspaceWithConfig{
+			DevWorkspace: &dw.DevWorkspace{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			},
+			Config: &v1alpha1.OperatorConfiguration{Routing: &v1alpha1.RoutingConfig{}},
+		}
+	}
+	canary := newWorkspace(map[string]string{constants.DevWorkspaceCanaryLabel: "true"})
+	regular := newWorkspace(nil)
+
+	canaryStartsBefore := testutil.ToFloat64(workspaceRolloutStarts.WithLabelValues("true"))
+	regularStartsBefore := testutil.ToFloat64(workspaceRolloutStarts.WithLabelValues("false"))
+	canaryFailuresBefore := testutil.ToFloat64(workspaceRolloutFailures.WithLabelValues("true", string(ReasonUnknown)))
+	regularFailuresBefore := testutil.ToFloat64(workspaceRolloutFailures.WithLabelValues("false", string(ReasonUnknown)))
+
+	WorkspaceStarted(canary, zap.New())
+	WorkspaceFailed(canary, zap.New())
+	WorkspaceStarted(regular, zap.New())
+
+	assert.Equal(t, canaryStartsBefore+1, testutil.ToFloat64(workspaceRolloutStarts.WithLabelValues("true")), "Should count canary starts")
+	assert.Equal(t, regularStartsBefore+1, testutil.ToFloat64(workspaceRolloutStarts.WithLabelValues("false")), "Should count regular starts")
+	assert.Equal(t, canaryFailuresBefore+1, testutil.ToFloat64(workspaceRolloutFailures.WithLabelValues("true", string(ReasonUnknown))), "Should count canary failures")
+	assert.Equal(t, regularFailuresBefore, testutil.ToFloat64(workspaceRolloutFailures.WithLabelValues("false", string(ReasonUnknown))), "Should not count canary failures as regular failures")
+}
+
+func TestServiceMonitorSync(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, "devworkspace-controller")
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(serviceMonitorGVK, meta.RESTScopeNamespace)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(mapper).Build()
+	manager := &ServiceMonitorManager{Client: fakeClient, Log: zap.New()}
+	assert.True(t, manager.serviceMonitorsAvailable())
+
+	getServiceMonitor := func() (*unstructured.Unstructured, error) {
+		serviceMonitor := &unstructured.Unstructured{}
+		serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: serviceMonitorName, Namespace: "devworkspace-controller"}, serviceMonitor)
+		return serviceMonitor, err
+	}
+
+	config.SetGlobalConfigForTesting(nil)
+	assert.NoError(t, manager.sync(context.Background()))
+	_, err := getServiceMonitor()
+	assert.True(t, k8sErrors.IsNotFound(err), "Should not create ServiceMonitor by default")
+
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Metrics: &v1alpha1.MetricsConfig{EnableServiceMonitor: pointer.Bool(true), ScrapeInterval: "30s"},
+	})
+	assert.NoError(t, manager.sync(context.Background()))
+	serviceMonitor, err := getServiceMonitor()
+	if assert.NoError(t, err, "Should create ServiceMonitor when enabled") {
+		endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+		if assert.Len(t, endpoints, 1) {
+			assert.Equal(t, "metrics", endpoints[0].(map[string]interface{})["port"])
+			assert.Equal(t, "30s", endpoints[0].(map[string]interface{})["interval"])
+		}
+	}
+
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Metrics: &v1alpha1.MetricsConfig{EnableServiceMonitor: pointer.Bool(true)},
+	})
+	assert.NoError(t, manager.sync(context.Background()))
+	serviceMonitor, err = getServiceMonitor()
+	if assert.NoError(t, err) {
+		endpoints, _, _ := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+		assert.NotContains(t, endpoints[0], "interval", "Should update ServiceMonitor when config changes")
+	}
+
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Metrics: &v1alpha1.MetricsConfig{EnableServiceMonitor: pointer.Bool(false)},
+	})
+	assert.NoError(t, manager.sync(context.Background()))
+	_, err = getServiceMonitor()
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete ServiceMonitor when disabled")
+}
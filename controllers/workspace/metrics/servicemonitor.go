@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	serviceMonitorName         = "devworkspace-controller"
+	serviceMonitorSyncInterval = 5 * time.Minute
+	serviceAccountTokenFile    = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+var serviceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+// operatorLabels are the labels applied to the operator's services (including the metrics service) on deployment.
+var operatorLabels = map[string]interface{}{
+	"app.kubernetes.io/name":    "devworkspace-controller",
+	"app.kubernetes.io/part-of": "devworkspace-operator",
+}
+
+// ServiceMonitorManager keeps a ServiceMonitor for the operator's metrics service in sync with the global
+// DevWorkspaceOperatorConfig's metrics field. It is intended to be added to the controller manager. If the
+// monitoring.coreos.com API is not available on the cluster, nothing is done.
+type ServiceMonitorManager struct {
+	// Client must be a non-caching client, to avoid starting an informer for ServiceMonitors
+	Client client.Client
+	Log    logr.Logger
+}
+
+// NeedLeaderElection ensures the ServiceMonitor is only managed by the manager that holds the leader lease.
+func (m *ServiceMonitorManager) NeedLeaderElection() bool {
+	return true
+}
+
+// Start syncs the ServiceMonitor periodically until ctx is cancelled.
+func (m *ServiceMonitorManager) Start(ctx context.Context) error {
+	if !m.serviceMonitorsAvailable() {
+		m.Log.Info("ServiceMonitor API is not available; metrics.enableServiceMonitor is ignored")
+		return nil
+	}
+	for {
+		if err := m.sync(ctx); err != nil {
+			m.Log.Error(err, "Failed to sync metrics ServiceMonitor")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(serviceMonitorSyncInterval):
+		}
+	}
+}
+
+func (m *ServiceMonitorManager) serviceMonitorsAvailable() bool {
+	_, err := m.Client.RESTMapper().RESTMapping(serviceMonitorGVK.GroupKind(), serviceMonitorGVK.Version)
+	if err != nil {
+		if !meta.IsNoMatchError(err) {
+			m.Log.Error(err, "Failed to check whether ServiceMonitors are supported")
+		}
+		return false
+	}
+	return true
+}
+
+func (m *ServiceMonitorManager) sync(ctx context.Context) error {
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return err
+	}
+	metricsConfig := config.GetGlobalConfig().Metrics
+	enabled := metricsConfig != nil && pointer.BoolDeref(metricsConfig.EnableServiceMonitor, false)
+
+	clusterServiceMonitor := &unstructured.Unstructured{}
+	clusterServiceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	err = m.Client.Get(ctx, types.NamespacedName{Name: serviceMonitorName, Namespace: namespace}, clusterServiceMonitor)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if !enabled {
+			return nil
+		}
+		m.Log.Info("Creating metrics ServiceMonitor", "namespace", namespace)
+		return m.Client.Create(ctx, getSpecServiceMonitor(namespace, metricsConfig.ScrapeInterval))
+	case err != nil:
+		return err
+	}
+
+	if !enabled {
+		m.Log.Info("Deleting metrics ServiceMonitor", "namespace", namespace)
+		err := m.Client.Delete(ctx, clusterServiceMonitor)
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	specServiceMonitor := getSpecServiceMonitor(namespace, metricsConfig.ScrapeInterval)
+	if reflect.DeepEqual(clusterServiceMonitor.Object["spec"], specServiceMonitor.Object["spec"]) {
+		return nil
+	}
+	clusterServiceMonitor.Object["spec"] = specServiceMonitor.Object["spec"]
+	return m.Client.Update(ctx, clusterServiceMonitor)
+}
+
+func getSpecServiceMonitor(namespace, scrapeInterval string) *unstructured.Unstructured {
+	endpoint := map[string]interface{}{
+		"port":            "metrics",
+		"scheme":          "https",
+		"bearerTokenFile": serviceAccountTokenFile,
+		"tlsConfig": map[string]interface{}{
+			"insecureSkipVerify": true,
+		},
+	}
+	if scrapeInterval != "" {
+		endpoint["interval"] = scrapeInterval
+	}
+	serviceMonitor := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": operatorLabels,
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{namespace},
+				},
+				"endpoints": []interface{}{endpoint},
+			},
+		},
+	}
+	serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+	serviceMonitor.SetName(serviceMonitorName)
+	serviceMonitor.SetNamespace(namespace)
+	labels := map[string]string{}
+	for key, value := range operatorLabels {
+		labels[key] = value.(string)
+	}
+	serviceMonitor.SetLabels(labels)
+	return serviceMonitor
+}
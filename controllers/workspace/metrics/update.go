@@ -31,6 +31,7 @@ func WorkspaceStarted(wksp *common.DevWorkspaceWithConfig, log logr.Logger) {
 	_, ok := wksp.GetAnnotations()[constants.DevWorkspaceStartedAtAnnotation]
 	if !ok {
 		incrementMetricForWorkspace(workspaceTotal, wksp, log)
+		incrementRolloutMetricForWorkspace(wksp, log)
 	}
 }
 
@@ -49,6 +50,42 @@ func WorkspaceRunning(wksp *common.DevWorkspaceWithConfig, log logr.Logger) {
 // logger is used to log the error.
 func WorkspaceFailed(wksp *common.DevWorkspaceWithConfig, log logr.Logger) {
 	incrementMetricForWorkspaceFailure(workspaceFailures, wksp, log)
+	incrementRolloutFailureMetricForWorkspace(wksp, log)
+}
+
+// WorkspaceStopped updates metrics for workspaces entering the 'Stopped' phase. If an error is encountered, the
+// provided logger is used to log the error.
+func WorkspaceStopped(wksp *common.DevWorkspaceWithConfig, log logr.Logger) {
+	if wksp.GetAnnotations()[constants.DevWorkspaceStopReasonAnnotation] != constants.DevWorkspaceStoppedByInactivity {
+		return
+	}
+	sourceLabel := wksp.Labels[workspaceSourceLabel]
+	if sourceLabel == "" {
+		sourceLabel = "unknown"
+	}
+	ctr, err := workspaceIdled.GetMetricWith(map[string]string{metricSourceLabel: sourceLabel})
+	if err != nil {
+		log.Error(err, "Failed to increment metric")
+		return
+	}
+	ctr.Inc()
+}
+
+// SetMigrationPending records the number of running DevWorkspaces that need to be reconciled again after an operator
+// upgrade as their objects use deprecated shapes.
+func SetMigrationPending(pending int) {
+	workspaceMigrationPending.Set(float64(pending))
+}
+
+// WorkspaceMigrationTriggered updates metrics when a reconcile is triggered to migrate a DevWorkspace.
+func WorkspaceMigrationTriggered() {
+	workspaceMigrationReconciles.Inc()
+}
+
+// WorkspaceMigrated updates metrics for running DevWorkspaces that are reconciled by the current operator version for
+// the first time.
+func WorkspaceMigrated() {
+	workspaceMigrated.Inc()
 }
 
 func incrementMetricForWorkspace(metric *prometheus.CounterVec, workspace *common.DevWorkspaceWithConfig, log logr.Logger) {
@@ -80,6 +117,34 @@ func incrementMetricForWorkspaceFailure(metric *prometheus.CounterVec, workspace
 	ctr.Inc()
 }
 
+func incrementRolloutMetricForWorkspace(workspace *common.DevWorkspaceWithConfig, log logr.Logger) {
+	ctr, err := workspaceRolloutStarts.GetMetricWith(map[string]string{metricsCanaryLabel: getCanaryLabel(workspace)})
+	if err != nil {
+		log.Error(err, "Failed to increment metric")
+		return
+	}
+	ctr.Inc()
+}
+
+func incrementRolloutFailureMetricForWorkspace(workspace *common.DevWorkspaceWithConfig, log logr.Logger) {
+	reason := GetFailureReason(workspace)
+	ctr, err := workspaceRolloutFailures.GetMetricWith(map[string]string{metricsCanaryLabel: getCanaryLabel(workspace), metricsReasonLabel: string(reason)})
+	if err != nil {
+		log.Error(err, "Failed to increment metric")
+		return
+	}
+	ctr.Inc()
+}
+
+// getCanaryLabel returns the value of the canary label for rollout metrics: "true" for DevWorkspaces labelled as
+// canaries, and "false" otherwise.
+func getCanaryLabel(workspace *common.DevWorkspaceWithConfig) string {
+	if workspace.Labels[constants.DevWorkspaceCanaryLabel] == "true" {
+		return "true"
+	}
+	return "false"
+}
+
 func incrementStartTimeBucketForWorkspace(workspace *common.DevWorkspaceWithConfig, log logr.Logger) {
 	sourceLabel := workspace.Labels[workspaceSourceLabel]
 	if sourceLabel == "" {
@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/devfile/devworkspace-operator/controllers/workspace/metrics"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/migration"
+	"github.com/devfile/devworkspace-operator/version"
+)
+
+// migrationInterval is how often running DevWorkspaces are checked for objects in deprecated shapes until all of them
+// have been migrated.
+const migrationInterval = time.Minute
+
+// syncReconciledVersionToCluster records the version of the DevWorkspace Operator and the hash of the flattened
+// DevWorkspace on a running DevWorkspace, if they changed since it was last reconciled.
+func (r *DevWorkspaceReconciler) syncReconciledVersionToCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, flattened *dw.DevWorkspaceTemplateSpec, reqLogger logr.Logger) {
+
+	specHash, err := migration.GetSpecHash(flattened)
+	if err != nil {
+		reqLogger.Error(err, "Failed to compute hash of flattened DevWorkspace")
+		return
+	}
+	previousVersion := workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation]
+	if previousVersion == version.Version && workspace.Annotations[constants.DevWorkspaceSpecHashAnnotation] == specHash {
+		return
+	}
+
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation] = version.Version
+	workspace.Annotations[constants.DevWorkspaceSpecHashAnnotation] = specHash
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to apply operator version annotation to workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to apply operator version annotation to devworkspace")
+		}
+		return
+	}
+	if previousVersion != "" && previousVersion != version.Version {
+		reqLogger.Info("Migrated DevWorkspace reconciled by a previous operator version", "previousVersion", previousVersion)
+		metrics.WorkspaceMigrated()
+	}
+}
+
+// workspaceMigrator triggers reconciles for running DevWorkspaces after an operator upgrade, if their deployment was
+// generated by an older version of the DevWorkspace Operator in a shape that the current version no longer uses (e.g.
+// old labels or environment variable names). DevWorkspaces are checked periodically until all of them have been
+// reconciled by the current version, after which the migrator stops.
+type workspaceMigrator struct {
+	// client is used to list DevWorkspaces, which are cached by the controller manager
+	client client.Client
+	// nonCachingClient is used to read deployments, as deployments created by older versions may not match the label
+	// selector used for the controller's cache
+	nonCachingClient client.Client
+	// events receives DevWorkspaces that should be reconciled
+	events chan<- event.GenericEvent
+	log    logr.Logger
+}
+
+// NeedLeaderElection ensures that reconciles are only triggered by the manager that holds the leader lease.
+func (m *workspaceMigrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start checks running DevWorkspaces for deprecated objects periodically until none are left or ctx is cancelled.
+func (m *workspaceMigrator) Start(ctx context.Context) error {
+	for {
+		pending, err := m.migrate(ctx)
+		if err != nil {
+			m.log.Error(err, "Failed to check DevWorkspaces for objects created by an older operator version")
+		} else if pending == 0 {
+			m.log.Info("All running DevWorkspaces are migrated to the current operator version", "version", version.Version)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(migrationInterval):
+		}
+	}
+}
+
+// migrate triggers a reconcile for every running DevWorkspace that was last reconciled by a different operator version
+// and whose deployment uses deprecated shapes. Returns the number of such DevWorkspaces.
+func (m *workspaceMigrator) migrate(ctx context.Context) (pending int, err error) {
+	workspaces := &dw.DevWorkspaceList{}
+	if err := m.client.List(ctx, workspaces); err != nil {
+		return 0, err
+	}
+	for idx := range workspaces.Items {
+		workspace := &workspaces.Items[idx]
+		if !migration.IsOutdated(workspace) {
+			continue
+		}
+		deployment := &appsv1.Deployment{}
+		deploymentNN := types.NamespacedName{Name: common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations), Namespace: workspace.Namespace}
+		if err := m.nonCachingClient.Get(ctx, deploymentNN, deployment); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+			return 0, err
+		}
+		deprecated := migration.GetDeprecatedShapes(workspace, deployment)
+		if len(deprecated) == 0 {
+			continue
+		}
+		pending++
+		m.log.Info("Reconciling DevWorkspace created by an older operator version", "namespace", workspace.Namespace,
+			"name", workspace.Name, "previousVersion", workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation],
+			"deprecated", deprecated)
+		select {
+		case m.events <- event.GenericEvent{Object: workspace}:
+			metrics.WorkspaceMigrationTriggered()
+		case <-ctx.Done():
+			return pending, nil
+		}
+	}
+	metrics.SetMigrationPending(pending)
+	return pending, nil
+}
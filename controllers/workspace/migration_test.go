@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/migration"
+	"github.com/devfile/devworkspace-operator/version"
+)
+
+func getMigrationTestDeployment(selector map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.DeploymentName("test-workspaceid", nil),
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:   "test-workspaceid",
+				constants.DevWorkspaceNameLabel: "test-workspace",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						constants.DevWorkspaceIDLabel:   "test-workspaceid",
+						constants.DevWorkspaceNameLabel: "test-workspace",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSyncReconciledVersionToCluster(t *testing.T) {
+	workspace := getIdleTestWorkspace(time.Now())
+	workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation] = "v0.1.0"
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+	flattened := &dw.DevWorkspaceTemplateSpec{}
+	flattened.Components = []dw.Component{{Name: "tools"}}
+
+	r.syncReconciledVersionToCluster(context.Background(), workspace, flattened, zap.New())
+	clusterWorkspace := getClusterWorkspace(t, r)
+	expectedHash, err := migration.GetSpecHash(flattened)
+	require.NoError(t, err)
+	assert.Equal(t, version.Version, clusterWorkspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation])
+	assert.Equal(t, expectedHash, clusterWorkspace.Annotations[constants.DevWorkspaceSpecHashAnnotation])
+
+	resourceVersion := clusterWorkspace.ResourceVersion
+	r.syncReconciledVersionToCluster(context.Background(), workspace, flattened, zap.New())
+	assert.Equal(t, resourceVersion, getClusterWorkspace(t, r).ResourceVersion, "Should not update DevWorkspace if nothing changed")
+}
+
+func TestWorkspaceMigratorTriggersReconcileForDeprecatedObjects(t *testing.T) {
+	workspace := getIdleTestWorkspace(time.Now())
+	workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation] = "v0.1.0"
+	deployment := getMigrationTestDeployment(map[string]string{"legacy-label": "test-workspaceid"})
+	r := getIdleTestReconciler(workspace.DevWorkspace, deployment)
+	events := make(chan event.GenericEvent, 1)
+	migrator := &workspaceMigrator{client: r.Client, nonCachingClient: r.Client, events: events, log: zap.New()}
+
+	pending, err := migrator.migrate(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pending)
+	if assert.Len(t, events, 1, "Should trigger reconcile for DevWorkspace") {
+		assert.Equal(t, "test-workspace", (<-events).Object.GetName())
+	}
+}
+
+func TestWorkspaceMigratorIgnoresUpToDateWorkspaces(t *testing.T) {
+	tests := []struct {
+		name            string
+		operatorVersion string
+		selector        map[string]string
+	}{
+		{
+			name:            "Reconciled by current version",
+			operatorVersion: version.Version,
+			selector:        map[string]string{"legacy-label": "test-workspaceid"},
+		},
+		{
+			name:            "Deployment uses current shape",
+			operatorVersion: "v0.1.0",
+			selector:        map[string]string{constants.DevWorkspaceIDLabel: "test-workspaceid"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getIdleTestWorkspace(time.Now())
+			workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation] = tt.operatorVersion
+			r := getIdleTestReconciler(workspace.DevWorkspace, getMigrationTestDeployment(tt.selector))
+			events := make(chan event.GenericEvent, 1)
+			migrator := &workspaceMigrator{client: r.Client, nonCachingClient: r.Client, events: events, log: zap.New()}
+
+			pending, err := migrator.migrate(context.Background())
+			assert.NoError(t, err)
+			assert.Zero(t, pending)
+			assert.Empty(t, events)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+// getOCIClient returns a client for fetching plugins and parents from OCI registries, using the image pull secrets in
+// the DevWorkspace's namespace to authenticate.
+func (r *DevWorkspaceReconciler) getOCIClient(ctx context.Context, namespace string) (*network.OCIClient, error) {
+	secrets := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secrets, client.InNamespace(namespace), client.MatchingLabels{constants.DevWorkspacePullSecretLabel: "true"}); err != nil {
+		return nil, fmt.Errorf("failed to read image pull secrets: %w", err)
+	}
+	return &network.OCIClient{
+		HTTPClient:  httpClient,
+		Credentials: network.GetOCICredentialsFromSecrets(secrets.Items),
+	}, nil
+}
+
+// getPinnedOCIDigests reads the digests that oci:// references were resolved to from the DevWorkspace's annotations.
+func getPinnedOCIDigests(workspace *common.DevWorkspaceWithConfig, logger logr.Logger) map[string]string {
+	digests := map[string]string{}
+	digestsJSON, ok := workspace.Annotations[constants.DevWorkspaceOCIDigestsAnnotation]
+	if !ok {
+		return digests
+	}
+	if err := json.Unmarshal([]byte(digestsJSON), &digests); err != nil {
+		logger.Info(fmt.Sprintf("Ignoring invalid %s annotation: %s", constants.DevWorkspaceOCIDigestsAnnotation, err))
+		return map[string]string{}
+	}
+	return digests
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// syncPostStopTasks runs the commands bound to the postStop events of a stopped workspace as DevWorkspaceTasks, and
+// sets the LifecycleEvents condition from the state of these tasks. Tasks are only created once each time the
+// workspace stops after running, as recorded by the DevWorkspacePostStopPendingAnnotation; tasks created for previous
+// runs of the workspace are deleted at that point.
+func (r *DevWorkspaceReconciler) syncPostStopTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig, status *currentStatus, logger logr.Logger) error {
+	if startedAt, pending := workspace.Annotations[constants.DevWorkspacePostStopPendingAnnotation]; pending {
+		if err := r.createPostStopTasks(ctx, workspace, startedAt, logger); err != nil {
+			return err
+		}
+		delete(workspace.Annotations, constants.DevWorkspacePostStopPendingAnnotation)
+		if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+			return err
+		}
+	}
+
+	tasks, err := r.listPostStopTasks(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	finished := true
+	for _, task := range tasks {
+		switch task.Status.Phase {
+		case controllerv1alpha1.TaskPhaseFailed:
+			status.setConditionFalse(conditions.LifecycleEvents, fmt.Sprintf("PostStop command %s failed: %s", task.Spec.CommandId, task.Status.Message))
+			return nil
+		case controllerv1alpha1.TaskPhaseSucceeded:
+			continue
+		default:
+			finished = false
+		}
+	}
+	if finished {
+		status.setConditionTrue(conditions.LifecycleEvents, "PostStop commands succeeded")
+	} else {
+		status.setConditionFalse(conditions.LifecycleEvents, "Running postStop commands")
+	}
+	return nil
+}
+
+func (r *DevWorkspaceReconciler) createPostStopTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig, startedAt string, logger logr.Logger) error {
+	taskNames := map[string]bool{}
+	var specTasks []*controllerv1alpha1.DevWorkspaceTask
+	if workspace.Spec.Template.Events != nil {
+		for idx, commandId := range workspace.Spec.Template.Events.PostStop {
+			task := &controllerv1alpha1.DevWorkspaceTask{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.PostStopTaskName(workspace.Status.DevWorkspaceId, idx, startedAt),
+					Namespace: workspace.Namespace,
+					Labels: map[string]string{
+						constants.DevWorkspaceIDLabel:           workspace.Status.DevWorkspaceId,
+						constants.DevWorkspacePostStopTaskLabel: "true",
+					},
+				},
+				Spec: controllerv1alpha1.DevWorkspaceTaskSpec{
+					DevWorkspaceName: workspace.Name,
+					CommandId:        commandId,
+					Mode:             controllerv1alpha1.TaskModeJob,
+				},
+			}
+			if err := controllerutil.SetControllerReference(workspace.DevWorkspace, task, r.Scheme); err != nil {
+				return err
+			}
+			taskNames[task.Name] = true
+			specTasks = append(specTasks, task)
+		}
+	}
+
+	oldTasks, err := r.listPostStopTasks(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	for _, task := range oldTasks {
+		if taskNames[task.Name] {
+			continue
+		}
+		if err := r.Delete(ctx, &task); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	for _, task := range specTasks {
+		logger.Info("Running postStop command", "command", task.Spec.CommandId, "task", task.Name)
+		if err := r.Create(ctx, task); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DevWorkspaceReconciler) listPostStopTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig) ([]controllerv1alpha1.DevWorkspaceTask, error) {
+	taskList := &controllerv1alpha1.DevWorkspaceTaskList{}
+	labels := client.MatchingLabels{
+		constants.DevWorkspaceIDLabel:           workspace.Status.DevWorkspaceId,
+		constants.DevWorkspacePostStopTaskLabel: "true",
+	}
+	if err := r.List(ctx, taskList, client.InNamespace(workspace.Namespace), labels); err != nil {
+		return nil, err
+	}
+	return taskList.Items, nil
+}
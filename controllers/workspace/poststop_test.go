@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getPostStopTestWorkspace(pendingStartedAt string) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: testutil.NewDevWorkspace("test-workspace", ""),
+		Config:       &v1alpha1.OperatorConfiguration{Workspace: &v1alpha1.WorkspaceConfig{}},
+	}
+	workspace.Spec.Template.Events = &dw.Events{
+		DevWorkspaceEvents: dw.DevWorkspaceEvents{
+			PostStop: []string{"deregister", "cleanup"},
+		},
+	}
+	if pendingStartedAt != "" {
+		workspace.Annotations[constants.DevWorkspacePostStopPendingAnnotation] = pendingStartedAt
+	}
+	return workspace
+}
+
+func getPostStopTestReconciler(objs ...client.Object) *DevWorkspaceReconciler {
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(v1alpha1.AddToScheme(testScheme))
+	utilruntime.Must(dw.AddToScheme(testScheme))
+	return &DevWorkspaceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(objs...).Build(),
+		Log:    zap.New(),
+		Scheme: testScheme,
+	}
+}
+
+func TestSyncPostStopTasksCreatesTasks(t *testing.T) {
+	workspace := getPostStopTestWorkspace("1000")
+	oldTask := &v1alpha1.DevWorkspaceTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.PostStopTaskName("test-workspaceid", 0, "500"),
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:           "test-workspaceid",
+				constants.DevWorkspacePostStopTaskLabel: "true",
+			},
+		},
+	}
+	r := getPostStopTestReconciler(workspace.DevWorkspace, oldTask)
+	status := &currentStatus{}
+
+	assert.NoError(t, r.syncPostStopTasks(context.Background(), workspace, status, zap.New()))
+
+	tasks, err := r.listPostStopTasks(context.Background(), workspace)
+	assert.NoError(t, err)
+	var commands []string
+	for _, task := range tasks {
+		assert.NotEqual(t, oldTask.Name, task.Name, "Should delete tasks from previous runs")
+		assert.Equal(t, v1alpha1.TaskModeJob, task.Spec.Mode)
+		assert.Equal(t, "test-workspace", task.Spec.DevWorkspaceName)
+		commands = append(commands, task.Spec.CommandId)
+	}
+	assert.ElementsMatch(t, []string{"deregister", "cleanup"}, commands)
+
+	clusterWorkspace := &dw.DevWorkspace{}
+	assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-workspace", Namespace: "test-namespace"}, clusterWorkspace))
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspacePostStopPendingAnnotation, "Should remove pending annotation")
+
+	condition := status.conditions[conditions.LifecycleEvents]
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Running postStop commands", condition.Message)
+}
+
+func TestSyncPostStopTasksSetsCondition(t *testing.T) {
+	getTask := func(idx int, command string, phase v1alpha1.DevWorkspaceTaskPhase, message string) *v1alpha1.DevWorkspaceTask {
+		return &v1alpha1.DevWorkspaceTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.PostStopTaskName("test-workspaceid", idx, "1000"),
+				Namespace: "test-namespace",
+				Labels: map[string]string{
+					constants.DevWorkspaceIDLabel:           "test-workspaceid",
+					constants.DevWorkspacePostStopTaskLabel: "true",
+				},
+			},
+			Spec:   v1alpha1.DevWorkspaceTaskSpec{CommandId: command},
+			Status: v1alpha1.DevWorkspaceTaskStatus{Phase: phase, Message: message},
+		}
+	}
+
+	workspace := getPostStopTestWorkspace("")
+	r := getPostStopTestReconciler(workspace.DevWorkspace,
+		getTask(0, "deregister", v1alpha1.TaskPhaseSucceeded, ""),
+		getTask(1, "cleanup", v1alpha1.TaskPhaseSucceeded, ""))
+	status := &currentStatus{}
+	assert.NoError(t, r.syncPostStopTasks(context.Background(), workspace, status, zap.New()))
+	condition := status.conditions[conditions.LifecycleEvents]
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, "PostStop commands succeeded", condition.Message)
+
+	r = getPostStopTestReconciler(workspace.DevWorkspace,
+		getTask(0, "deregister", v1alpha1.TaskPhaseSucceeded, ""),
+		getTask(1, "cleanup", v1alpha1.TaskPhaseFailed, "Command exited with code 1"))
+	status = &currentStatus{}
+	assert.NoError(t, r.syncPostStopTasks(context.Background(), workspace, status, zap.New()))
+	condition = status.conditions[conditions.LifecycleEvents]
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, "PostStop command cleanup failed: Command exited with code 1", condition.Message)
+}
+
+func TestSyncPostStopTasksWithoutTasks(t *testing.T) {
+	workspace := getPostStopTestWorkspace("")
+	r := getPostStopTestReconciler(workspace.DevWorkspace)
+	status := &currentStatus{}
+	assert.NoError(t, r.syncPostStopTasks(context.Background(), workspace, status, zap.New()))
+	assert.NotContains(t, status.conditions, conditions.LifecycleEvents, "Should not set condition when no postStop commands were run")
+}
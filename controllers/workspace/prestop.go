@@ -0,0 +1,185 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// defaultPreStopTimeout is used if the graceful stop timeout in the DevWorkspaceOperatorConfig cannot be parsed
+	defaultPreStopTimeout = 2 * time.Minute
+	// preStopRequeueAfter is how often a stopping workspace is checked while its preStop commands are running, in
+	// addition to reconciles triggered by changes to its DevWorkspaceTasks.
+	preStopRequeueAfter = 10 * time.Second
+)
+
+func isGracefulStopEnabled(workspace *common.DevWorkspaceWithConfig) bool {
+	gracefulStop := workspace.Config.Workspace.GracefulStop
+	return gracefulStop != nil && pointer.BoolDeref(gracefulStop.Enable, false)
+}
+
+func getPreStopTimeout(workspace *common.DevWorkspaceWithConfig) time.Duration {
+	if gracefulStop := workspace.Config.Workspace.GracefulStop; gracefulStop != nil {
+		if timeout, err := time.ParseDuration(gracefulStop.Timeout); err == nil && timeout > 0 {
+			return timeout
+		}
+	}
+	return defaultPreStopTimeout
+}
+
+// removePreStopLifecycleHooks removes the preStop lifecycle hooks added to containers for the commands bound to
+// preStop events, as these commands are run by the controller before the pod is stopped when graceful stop is enabled.
+func removePreStopLifecycleHooks(containers []corev1.Container) {
+	for idx := range containers {
+		if containers[idx].Lifecycle == nil {
+			continue
+		}
+		containers[idx].Lifecycle.PreStop = nil
+		if containers[idx].Lifecycle.PostStart == nil {
+			containers[idx].Lifecycle = nil
+		}
+	}
+}
+
+// syncPreStopTasks runs the commands bound to the preStop events of a stopping workspace as DevWorkspaceTasks in the
+// workspace's containers, and sets the PreStopEvents condition from the state of these tasks. Tasks are created once
+// each time the workspace stops after running, as recorded by the DevWorkspacePreStopPendingAnnotation. Returns true
+// once all tasks have completed, any task has failed or the graceful stop timeout has expired, at which point the
+// annotation is removed and the workspace's pod can be stopped.
+func (r *DevWorkspaceReconciler) syncPreStopTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig, status *currentStatus, logger logr.Logger) (finished bool, err error) {
+	if events := workspace.Spec.Template.Events; events == nil || len(events.PreStop) == 0 {
+		return true, r.finishPreStop(ctx, workspace)
+	}
+	startedAt := workspace.Annotations[constants.DevWorkspacePreStopPendingAnnotation]
+	allTasks, err := r.listPreStopTasks(ctx, workspace)
+	if err != nil {
+		return false, err
+	}
+	var tasks []controllerv1alpha1.DevWorkspaceTask
+	for idx := range workspace.Spec.Template.Events.PreStop {
+		taskName := common.PreStopTaskName(workspace.Status.DevWorkspaceId, idx, startedAt)
+		for _, task := range allTasks {
+			if task.Name == taskName {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	if len(tasks) == 0 {
+		if err := r.createPreStopTasks(ctx, workspace, startedAt, allTasks, logger); err != nil {
+			return false, err
+		}
+		status.setConditionFalse(conditions.PreStopEvents, "Running preStop commands")
+		return false, nil
+	}
+
+	finished = true
+	for _, task := range tasks {
+		switch task.Status.Phase {
+		case controllerv1alpha1.TaskPhaseFailed:
+			status.setConditionFalse(conditions.PreStopEvents, fmt.Sprintf("PreStop command %s failed: %s", task.Spec.CommandId, task.Status.Message))
+			return true, r.finishPreStop(ctx, workspace)
+		case controllerv1alpha1.TaskPhaseSucceeded:
+			continue
+		default:
+			finished = false
+		}
+	}
+	if finished {
+		status.setConditionTrue(conditions.PreStopEvents, "PreStop commands succeeded")
+		return true, r.finishPreStop(ctx, workspace)
+	}
+
+	timeout := getPreStopTimeout(workspace)
+	if created := tasks[0].CreationTimestamp; !created.IsZero() && clock.Since(created.Time) > timeout {
+		logger.Info("Timed out waiting for preStop commands to complete", "timeout", timeout.String())
+		status.setConditionFalse(conditions.PreStopEvents, fmt.Sprintf("PreStop commands did not complete within %s", timeout))
+		return true, r.finishPreStop(ctx, workspace)
+	}
+	status.setConditionFalse(conditions.PreStopEvents, "Running preStop commands")
+	return false, nil
+}
+
+// createPreStopTasks creates a DevWorkspaceTask for each command bound to a preStop event of the workspace, deleting
+// any tasks created for previous runs of the workspace.
+func (r *DevWorkspaceReconciler) createPreStopTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig, startedAt string, oldTasks []controllerv1alpha1.DevWorkspaceTask, logger logr.Logger) error {
+	for _, task := range oldTasks {
+		if err := r.Delete(ctx, &task); err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	timeoutSeconds := int64(getPreStopTimeout(workspace).Seconds())
+	for idx, commandId := range workspace.Spec.Template.Events.PreStop {
+		task := &controllerv1alpha1.DevWorkspaceTask{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.PreStopTaskName(workspace.Status.DevWorkspaceId, idx, startedAt),
+				Namespace: workspace.Namespace,
+				Labels: map[string]string{
+					constants.DevWorkspaceIDLabel:          workspace.Status.DevWorkspaceId,
+					constants.DevWorkspacePreStopTaskLabel: "true",
+				},
+			},
+			Spec: controllerv1alpha1.DevWorkspaceTaskSpec{
+				DevWorkspaceName: workspace.Name,
+				CommandId:        commandId,
+				Mode:             controllerv1alpha1.TaskModeExec,
+				TimeoutSeconds:   &timeoutSeconds,
+			},
+		}
+		if err := controllerutil.SetControllerReference(workspace.DevWorkspace, task, r.Scheme); err != nil {
+			return err
+		}
+		logger.Info("Running preStop command", "command", task.Spec.CommandId, "task", task.Name)
+		if err := r.Create(ctx, task); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *DevWorkspaceReconciler) finishPreStop(ctx context.Context, workspace *common.DevWorkspaceWithConfig) error {
+	delete(workspace.Annotations, constants.DevWorkspacePreStopPendingAnnotation)
+	return r.Update(ctx, workspace.DevWorkspace)
+}
+
+// listPreStopTasks returns the preStop DevWorkspaceTasks of a workspace, including tasks created for previous runs.
+func (r *DevWorkspaceReconciler) listPreStopTasks(ctx context.Context, workspace *common.DevWorkspaceWithConfig) ([]controllerv1alpha1.DevWorkspaceTask, error) {
+	taskList := &controllerv1alpha1.DevWorkspaceTaskList{}
+	labels := client.MatchingLabels{
+		constants.DevWorkspaceIDLabel:          workspace.Status.DevWorkspaceId,
+		constants.DevWorkspacePreStopTaskLabel: "true",
+	}
+	if err := r.List(ctx, taskList, client.InNamespace(workspace.Namespace), labels); err != nil {
+		return nil, err
+	}
+	return taskList.Items, nil
+}
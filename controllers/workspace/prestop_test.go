@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getPreStopTestWorkspace(pendingStartedAt string) *common.DevWorkspaceWithConfig {
+	workspace := getPostStopTestWorkspace("")
+	workspace.Spec.Template.Events.PreStop = []string{"flush", "stash"}
+	workspace.Spec.Template.Events.PostStop = nil
+	workspace.Config.Workspace.GracefulStop = &v1alpha1.GracefulStopConfig{
+		Enable:  pointer.Bool(true),
+		Timeout: "1m",
+	}
+	if pendingStartedAt != "" {
+		workspace.Annotations[constants.DevWorkspacePreStopPendingAnnotation] = pendingStartedAt
+	}
+	return workspace
+}
+
+func getPreStopTestTask(idx int, command string, phase v1alpha1.DevWorkspaceTaskPhase, message string, created time.Time) *v1alpha1.DevWorkspaceTask {
+	return &v1alpha1.DevWorkspaceTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              common.PreStopTaskName("test-workspaceid", idx, "1000"),
+			Namespace:         "test-namespace",
+			CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:          "test-workspaceid",
+				constants.DevWorkspacePreStopTaskLabel: "true",
+			},
+		},
+		Spec:   v1alpha1.DevWorkspaceTaskSpec{CommandId: command},
+		Status: v1alpha1.DevWorkspaceTaskStatus{Phase: phase, Message: message},
+	}
+}
+
+func getPreStopTestClusterWorkspace(t *testing.T, r *DevWorkspaceReconciler) *dw.DevWorkspace {
+	clusterWorkspace := &dw.DevWorkspace{}
+	assert.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: "test-workspace", Namespace: "test-namespace"}, clusterWorkspace))
+	return clusterWorkspace
+}
+
+func TestSyncPreStopTasksCreatesTasks(t *testing.T) {
+	workspace := getPreStopTestWorkspace("1000")
+	oldTask := getPreStopTestTask(0, "flush", v1alpha1.TaskPhaseSucceeded, "", time.Now())
+	oldTask.Name = common.PreStopTaskName("test-workspaceid", 0, "500")
+	r := getPostStopTestReconciler(workspace.DevWorkspace, oldTask)
+	status := &currentStatus{}
+
+	finished, err := r.syncPreStopTasks(context.Background(), workspace, status, zap.New())
+	assert.NoError(t, err)
+	assert.False(t, finished, "Should wait for preStop commands to complete")
+
+	tasks, err := r.listPreStopTasks(context.Background(), workspace)
+	assert.NoError(t, err)
+	var commands []string
+	for _, task := range tasks {
+		assert.NotEqual(t, oldTask.Name, task.Name, "Should delete tasks from previous runs")
+		assert.Equal(t, v1alpha1.TaskModeExec, task.Spec.Mode)
+		assert.Equal(t, pointer.Int64(60), task.Spec.TimeoutSeconds)
+		commands = append(commands, task.Spec.CommandId)
+	}
+	assert.ElementsMatch(t, []string{"flush", "stash"}, commands)
+	assert.Contains(t, getPreStopTestClusterWorkspace(t, r).Annotations, constants.DevWorkspacePreStopPendingAnnotation,
+		"Should keep pending annotation while commands are running")
+
+	condition := status.conditions[conditions.PreStopEvents]
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, "Running preStop commands", condition.Message)
+}
+
+func TestSyncPreStopTasksSetsCondition(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name              string
+		tasks             []*v1alpha1.DevWorkspaceTask
+		expectedFinished  bool
+		expectedCondition corev1.ConditionStatus
+		expectedMessage   string
+	}{
+		{
+			name: "Finishes when all commands succeed",
+			tasks: []*v1alpha1.DevWorkspaceTask{
+				getPreStopTestTask(0, "flush", v1alpha1.TaskPhaseSucceeded, "", now),
+				getPreStopTestTask(1, "stash", v1alpha1.TaskPhaseSucceeded, "", now),
+			},
+			expectedFinished:  true,
+			expectedCondition: corev1.ConditionTrue,
+			expectedMessage:   "PreStop commands succeeded",
+		},
+		{
+			name: "Finishes when a command fails",
+			tasks: []*v1alpha1.DevWorkspaceTask{
+				getPreStopTestTask(0, "flush", v1alpha1.TaskPhaseRunning, "", now),
+				getPreStopTestTask(1, "stash", v1alpha1.TaskPhaseFailed, "Command exited with code 1", now),
+			},
+			expectedFinished:  true,
+			expectedCondition: corev1.ConditionFalse,
+			expectedMessage:   "PreStop command stash failed: Command exited with code 1",
+		},
+		{
+			name: "Waits for running commands",
+			tasks: []*v1alpha1.DevWorkspaceTask{
+				getPreStopTestTask(0, "flush", v1alpha1.TaskPhaseSucceeded, "", now.Add(-30*time.Second)),
+				getPreStopTestTask(1, "stash", v1alpha1.TaskPhaseRunning, "", now.Add(-30*time.Second)),
+			},
+			expectedFinished:  false,
+			expectedCondition: corev1.ConditionFalse,
+			expectedMessage:   "Running preStop commands",
+		},
+		{
+			name: "Finishes when timeout expires",
+			tasks: []*v1alpha1.DevWorkspaceTask{
+				getPreStopTestTask(0, "flush", v1alpha1.TaskPhasePending, "", now.Add(-2*time.Minute)),
+				getPreStopTestTask(1, "stash", v1alpha1.TaskPhaseRunning, "", now.Add(-2*time.Minute)),
+			},
+			expectedFinished:  true,
+			expectedCondition: corev1.ConditionFalse,
+			expectedMessage:   "PreStop commands did not complete within 1m0s",
+		},
+	}
+	oldClock := clock
+	clock = kubeclock.NewFakeClock(now)
+	defer func() { clock = oldClock }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getPreStopTestWorkspace("1000")
+			objs := []client.Object{workspace.DevWorkspace}
+			for _, task := range tt.tasks {
+				objs = append(objs, task)
+			}
+			r := getPostStopTestReconciler(objs...)
+			status := &currentStatus{}
+
+			finished, err := r.syncPreStopTasks(context.Background(), workspace, status, zap.New())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFinished, finished)
+			condition := status.conditions[conditions.PreStopEvents]
+			assert.Equal(t, tt.expectedCondition, condition.Status)
+			assert.Equal(t, tt.expectedMessage, condition.Message)
+			if tt.expectedFinished {
+				assert.NotContains(t, getPreStopTestClusterWorkspace(t, r).Annotations, constants.DevWorkspacePreStopPendingAnnotation,
+					"Should remove pending annotation once finished")
+			}
+		})
+	}
+}
+
+func TestRemovePreStopLifecycleHooks(t *testing.T) {
+	handler := &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}}
+	containers := []corev1.Container{
+		{Name: "prestop-only", Lifecycle: &corev1.Lifecycle{PreStop: handler}},
+		{Name: "both", Lifecycle: &corev1.Lifecycle{PreStop: handler, PostStart: handler}},
+		{Name: "none"},
+	}
+	removePreStopLifecycleHooks(containers)
+	assert.Nil(t, containers[0].Lifecycle)
+	assert.Equal(t, &corev1.Lifecycle{PostStart: handler}, containers[1].Lifecycle)
+	assert.Nil(t, containers[2].Lifecycle)
+}
@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// handleRestartRequest processes the DevWorkspaceRestartAnnotation on a DevWorkspace. If the DevWorkspace is started,
+// the time of the restart is recorded in the DevWorkspaceRestartedAtAnnotation, which is propagated to the pod template
+// of the DevWorkspace's deployment in order to replace its pod. The restart annotation is removed in either case, as
+// stopped DevWorkspaces have no pod to restart. Returns true if the DevWorkspace was updated and should be reconciled
+// again.
+func (r *DevWorkspaceReconciler) handleRestartRequest(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (updated bool, err error) {
+	if _, ok := workspace.Annotations[constants.DevWorkspaceRestartAnnotation]; !ok {
+		return false, nil
+	}
+	restart := workspace.Annotations[constants.DevWorkspaceRestartAnnotation] == "true"
+	delete(workspace.Annotations, constants.DevWorkspaceRestartAnnotation)
+	if restart && workspace.Spec.Started {
+		workspace.Annotations[constants.DevWorkspaceRestartedAtAnnotation] = clock.Now().UTC().Format(time.RFC3339)
+		logger.Info("Restarting DevWorkspace pod")
+	}
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		return false, err
+	}
+	return true, nil
+}
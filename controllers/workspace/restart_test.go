@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestHandleRestartRequest(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now)
+	workspace.Annotations[constants.DevWorkspaceRestartAnnotation] = "true"
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.handleRestartRequest(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceRestartAnnotation)
+	assert.Equal(t, now.UTC().Format(time.RFC3339), clusterWorkspace.Annotations[constants.DevWorkspaceRestartedAtAnnotation])
+}
+
+func TestHandleRestartRequestForStoppedWorkspace(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now)
+	workspace.Spec.Started = false
+	workspace.Annotations[constants.DevWorkspaceRestartAnnotation] = "true"
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.handleRestartRequest(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	clusterWorkspace := getClusterWorkspace(t, r)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceRestartAnnotation)
+	assert.NotContains(t, clusterWorkspace.Annotations, constants.DevWorkspaceRestartedAtAnnotation, "Should not restart stopped DevWorkspace")
+}
+
+func TestHandleRestartRequestWithoutAnnotation(t *testing.T) {
+	now, _ := setupIdleTest(t)
+	workspace := getIdleTestWorkspace(now)
+	r := getIdleTestReconciler(workspace.DevWorkspace)
+
+	updated, err := r.handleRestartRequest(context.Background(), workspace, zap.New())
+	require.NoError(t, err)
+	assert.False(t, updated)
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// transientFailureReasons contains reasons that, when present in the failure message of a DevWorkspace, indicate
+// that the DevWorkspace failed to start due to a transient cluster issue and that startup may succeed if retried.
+var transientFailureReasons = []string{
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"FailedScheduling",
+	"Evicted",
+}
+
+// retryFailedStart restarts a DevWorkspace that failed to start due to a transient issue, if allowed by the
+// workspace.startRetry configuration. The failed deployment is scaled down and startup is retried once the backoff
+// for the current attempt has passed. If retrying is false, the DevWorkspace should be stopped as usual.
+func (r *DevWorkspaceReconciler) retryFailedStart(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) (retrying bool, result reconcile.Result, err error) {
+	retryConfig := workspace.Config.Workspace.StartRetry
+	if retryConfig == nil || workspace.Annotations[constants.DevWorkspaceDebugStartAnnotation] == "true" {
+		return false, reconcile.Result{}, nil
+	}
+	maxAttempts := pointer.Int32Deref(retryConfig.MaxAttempts, 0)
+	retries := getStartRetries(workspace)
+	failedCondition := conditions.GetConditionByType(workspace.Status.Conditions, dw.DevWorkspaceFailedStart)
+	if retries >= maxAttempts || failedCondition == nil || !isTransientFailure(failedCondition.Message) {
+		return false, reconcile.Result{}, nil
+	}
+	backoff, err := getStartRetryBackoff(retryConfig, retries)
+	if err != nil {
+		logger.Error(err, "Invalid configuration for retrying DevWorkspace start")
+		return false, reconcile.Result{}, nil
+	}
+
+	// Scale down the failed deployment to ensure a new pod is created when startup is retried
+	stopped, err := r.doStop(ctx, workspace, logger)
+	if err != nil || !stopped {
+		return true, reconcile.Result{}, err
+	}
+
+	retryTime := failedCondition.LastTransitionTime.Add(backoff)
+	if remaining := retryTime.Sub(clock.Now()); remaining > 0 {
+		status := currentStatus{phase: devworkspacePhaseFailing}
+		status.setCondition(dw.DevWorkspaceFailedStart, *failedCondition)
+		if diagnosticsCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.StartupDiagnostics); diagnosticsCondition != nil {
+			status.setCondition(conditions.StartupDiagnostics, *diagnosticsCondition)
+		}
+		status.setConditionTrue(conditions.StartRetried,
+			fmt.Sprintf("Retrying DevWorkspace start at %s (attempt %d of %d)", retryTime.UTC().Format(time.RFC3339), retries+1, maxAttempts))
+		result, err := r.updateWorkspaceStatus(workspace, logger, &status, reconcile.Result{RequeueAfter: remaining}, nil)
+		return true, result, err
+	}
+
+	logger.Info("Retrying DevWorkspace start after transient failure", "attempt", retries+1, "maxAttempts", maxAttempts)
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceStartRetriesAnnotation] = strconv.Itoa(int(retries + 1))
+	delete(workspace.Annotations, constants.DevWorkspaceStartupDiagnosticsAnnotation)
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		return true, reconcile.Result{}, err
+	}
+
+	workspace.Status.Phase = dw.DevWorkspaceStatusStarting
+	workspace.Status.Message = "Retrying DevWorkspace start"
+	workspace.Status.Conditions = []dw.DevWorkspaceCondition{
+		{
+			Type:               conditions.Started,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Time{Time: clock.Now()},
+			Message:            "DevWorkspace is starting",
+		},
+	}
+	return true, reconcile.Result{}, r.Status().Update(ctx, workspace.DevWorkspace)
+}
+
+// getStartRetries returns the number of times startup was retried for a workspace, as recorded in the
+// DevWorkspaceStartRetriesAnnotation annotation.
+func getStartRetries(workspace *common.DevWorkspaceWithConfig) int32 {
+	retries, err := strconv.ParseInt(workspace.Annotations[constants.DevWorkspaceStartRetriesAnnotation], 10, 32)
+	if err != nil || retries < 0 {
+		return 0
+	}
+	return int32(retries)
+}
+
+// getStartRetryBackoff returns how long to wait after a failure before retrying startup, given the number of retries
+// that were already performed. The backoff starts at the configured initial backoff and doubles for every retry, up to
+// the configured maximum backoff.
+func getStartRetryBackoff(retryConfig *controllerv1alpha1.StartRetryConfig, retries int32) (time.Duration, error) {
+	initialBackoff, err := time.ParseDuration(retryConfig.InitialBackoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration specified for initial backoff: %w", err)
+	}
+	maxBackoff, err := time.ParseDuration(retryConfig.MaxBackoff)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration specified for maximum backoff: %w", err)
+	}
+	backoff := initialBackoff
+	for i := int32(0); i < retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff, nil
+}
+
+func isTransientFailure(msg string) bool {
+	for _, reason := range transientFailureReasons {
+		if strings.Contains(msg, reason) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	kubeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getRetryTestWorkspace(failureMsg string, failedAgo time.Duration, retries string) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: testutil.NewDevWorkspace("test-workspace", devworkspacePhaseFailing),
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				StartRetry: &v1alpha1.StartRetryConfig{
+					MaxAttempts:    pointer.Int32(2),
+					InitialBackoff: "30s",
+					MaxBackoff:     "5m",
+				},
+			},
+		},
+	}
+	workspace.Spec.Started = true
+	workspace.Status.Conditions = []dw.DevWorkspaceCondition{
+		{
+			Type:               dw.DevWorkspaceFailedStart,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Time{Time: clock.Now().Add(-failedAgo)},
+			Message:            failureMsg,
+		},
+	}
+	if retries != "" {
+		workspace.Annotations[constants.DevWorkspaceStartRetriesAnnotation] = retries
+	}
+	return workspace
+}
+
+func getRetryTestReconciler(workspace *common.DevWorkspaceWithConfig) *DevWorkspaceReconciler {
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(v1alpha1.AddToScheme(testScheme))
+	utilruntime.Must(dw.AddToScheme(testScheme))
+	return &DevWorkspaceReconciler{
+		Client: fake.NewClientBuilder().WithScheme(testScheme).WithObjects(workspace.DevWorkspace).Build(),
+		Log:    zap.New(),
+		Scheme: testScheme,
+	}
+}
+
+func TestGetStartRetryBackoff(t *testing.T) {
+	retryConfig := &v1alpha1.StartRetryConfig{
+		InitialBackoff: "30s",
+		MaxBackoff:     "5m",
+	}
+	expected := []time.Duration{30 * time.Second, 1 * time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for retries, expectedBackoff := range expected {
+		backoff, err := getStartRetryBackoff(retryConfig, int32(retries))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expectedBackoff, backoff, "Unexpected backoff after %d retries", retries)
+		}
+	}
+
+	_, err := getStartRetryBackoff(&v1alpha1.StartRetryConfig{InitialBackoff: "invalid", MaxBackoff: "5m"}, 0)
+	assert.Error(t, err, "Should return error for invalid initial backoff")
+}
+
+func TestRetryFailedStart(t *testing.T) {
+	oldClock := clock
+	clock = kubeclock.NewFakeClock(time.Now().Truncate(time.Second))
+	defer func() { clock = oldClock }()
+
+	tests := []struct {
+		name             string
+		workspace        *common.DevWorkspaceWithConfig
+		expectedRetrying bool
+		expectedPhase    dw.DevWorkspacePhase
+		expectedRetries  string
+	}{
+		{
+			name:             "Does not retry non-transient failures",
+			workspace:        getRetryTestWorkspace("Error processing devfile: invalid", time.Hour, ""),
+			expectedRetrying: false,
+			expectedPhase:    devworkspacePhaseFailing,
+		},
+		{
+			name:             "Waits for backoff before retrying",
+			workspace:        getRetryTestWorkspace("Container tools has state ImagePullBackOff", 10*time.Second, ""),
+			expectedRetrying: true,
+			expectedPhase:    devworkspacePhaseFailing,
+		},
+		{
+			name:             "Retries transient failures after backoff",
+			workspace:        getRetryTestWorkspace("Container tools has state ImagePullBackOff", 1*time.Minute, "1"),
+			expectedRetrying: true,
+			expectedPhase:    dw.DevWorkspaceStatusStarting,
+			expectedRetries:  "2",
+		},
+		{
+			name:             "Does not retry after max attempts",
+			workspace:        getRetryTestWorkspace("Detected unrecoverable event FailedScheduling: 0/3 nodes are available.", time.Hour, "2"),
+			expectedRetrying: false,
+			expectedPhase:    devworkspacePhaseFailing,
+			expectedRetries:  "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := getRetryTestReconciler(tt.workspace)
+			retrying, _, err := r.retryFailedStart(context.Background(), tt.workspace, r.Log)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.expectedRetrying, retrying)
+
+			clusterWorkspace := &dw.DevWorkspace{}
+			if !assert.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(tt.workspace.DevWorkspace), clusterWorkspace)) {
+				return
+			}
+			assert.Equal(t, tt.expectedPhase, clusterWorkspace.Status.Phase)
+			assert.Equal(t, tt.expectedRetries, clusterWorkspace.Annotations[constants.DevWorkspaceStartRetriesAnnotation])
+			if tt.expectedRetrying && tt.expectedPhase == devworkspacePhaseFailing {
+				retriedCondition := conditions.GetConditionByType(clusterWorkspace.Status.Conditions, conditions.StartRetried)
+				if assert.NotNil(t, retriedCondition, "Should set StartRetried condition while waiting for backoff") {
+					assert.Contains(t, retriedCondition.Message, "attempt 1 of 2")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	wkspConfig "github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/runschedule"
+)
+
+// checkRunSchedule returns the stop reason for a started DevWorkspace that must be stopped according to the run
+// schedule, or an empty string if it may keep running. DevWorkspaces are stopped if a stop schedule fired since they
+// were started, or if they were started during a blackout window. If the DevWorkspace may keep running, also returns
+// how long until its next scheduled stop; a zero duration means it has no stop schedule. The schedule is read from
+// the global config so that it cannot be changed by external configuration.
+func checkRunSchedule(workspace *dw.DevWorkspace) (stopReason string, recheckAfter time.Duration, err error) {
+	config := wkspConfig.GetGlobalConfig().Workspace.RunSchedule
+	now := clock.Now()
+	startedAt, hasStarted, err := runschedule.GetStartedAt(workspace)
+	if err != nil {
+		return "", 0, err
+	}
+
+	window, err := runschedule.GetBlackout(workspace, config, now)
+	if err != nil {
+		return "", 0, err
+	}
+	// DevWorkspaces that were already running when the blackout window started are not affected by it
+	if window != nil && (!hasStarted || !startedAt.Before(window.Start)) {
+		return runschedule.BlackoutStopReason, 0, nil
+	}
+
+	if !hasStarted {
+		// The stop schedule is checked once the DevWorkspace is running
+		return "", 0, nil
+	}
+	nextStop, hasSchedule, err := runschedule.NextStop(workspace, config, startedAt)
+	if err != nil || !hasSchedule {
+		return "", 0, err
+	}
+	if !nextStop.After(now) {
+		return runschedule.StopReason, 0, nil
+	}
+	return "", nextStop.Sub(now), nil
+}
+
+// getStoppedByScheduleMessage returns the message for the StoppedBySchedule condition. For DevWorkspaces that were
+// started during a blackout window, this includes when the window ends.
+func getStoppedByScheduleMessage(workspace *dw.DevWorkspace, stopReason string) string {
+	if stopReason == runschedule.StopReason {
+		return "DevWorkspace was stopped by its run schedule"
+	}
+	window, err := runschedule.GetBlackout(workspace, wkspConfig.GetGlobalConfig().Workspace.RunSchedule, clock.Now())
+	if err != nil || window == nil {
+		return "DevWorkspace was stopped as it was started during a blackout window"
+	}
+	return fmt.Sprintf("DevWorkspace cannot be started during a blackout window. The window ends at %s",
+		window.End.Format(time.RFC3339))
+}
@@ -0,0 +1,54 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	wkspConfig "github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/runningbudget"
+)
+
+// getRemainingRunningBudget returns how much of its weekly running budget a DevWorkspace has left, and whether the
+// DevWorkspace has a running budget at all. The budget is read from the global config so that it cannot be raised
+// by external configuration.
+func getRemainingRunningBudget(workspace *dw.DevWorkspace) (remaining time.Duration, hasBudget bool, err error) {
+	budget, hasBudget, err := runningbudget.GetBudget(workspace, wkspConfig.GetGlobalConfig().Workspace.RunningBudget)
+	if err != nil || !hasBudget {
+		return 0, hasBudget, err
+	}
+	used, err := runningbudget.GetUsage(workspace, clock.Now())
+	if err != nil {
+		return 0, false, err
+	}
+	return budget - used, true, nil
+}
+
+// getRunningBudgetExceededMessage returns the message for the RunningBudgetExceeded condition, including when the
+// budget resets.
+func getRunningBudgetExceededMessage(workspace *dw.DevWorkspace) string {
+	resetsAt := runningbudget.WeekStart(clock.Now()).AddDate(0, 0, 7)
+	budget, _, err := runningbudget.GetBudget(workspace, wkspConfig.GetGlobalConfig().Workspace.RunningBudget)
+	if err != nil {
+		return fmt.Sprintf("DevWorkspace was stopped after exceeding its weekly running budget. The budget resets at %s",
+			resetsAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("DevWorkspace was stopped after exceeding its weekly running budget of %s. The budget resets at %s",
+		budget, resetsAt.Format(time.RFC3339))
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	wsprovision "github.com/devfile/devworkspace-operator/pkg/provision/workspace"
+)
+
+// syncStandbyDeployment keeps the standby components of a workspace that was stopped due to inactivity running until
+// the standby timeout expires. Failing to sync the standby deployment does not prevent the workspace from stopping;
+// errors are logged and the workspace is reconciled again later.
+func (r *DevWorkspaceReconciler) syncStandbyDeployment(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) time.Duration {
+	clusterAPI := sync.ClusterAPI{
+		Ctx:    ctx,
+		Client: r.Client,
+		Scheme: r.Scheme,
+		Logger: logger,
+	}
+	requeueAfter, err := wsprovision.SyncStandbyDeployment(workspace, clusterAPI)
+	if err != nil {
+		if retryErr, ok := err.(*dwerrors.RetryError); ok {
+			logger.Info(retryErr.Error())
+			return retryErr.RequeueAfter + time.Second
+		}
+		logger.Error(err, "Failed to sync standby deployment for DevWorkspace")
+		return 10 * time.Second
+	}
+	return requeueAfter
+}
+
+// syncStandbyNodeToCluster records the node running the workspace's standby pod, if any, in the
+// DevWorkspaceStandbyNodeAnnotation so that the workspace's pod is preferably scheduled on the same node when the
+// workspace is resumed. If no standby pod is running, the standby deployment is deleted instead.
+func (r *DevWorkspaceReconciler) syncStandbyNodeToCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, reqLogger logr.Logger) {
+
+	standbyNode, err := wsprovision.GetStandbyNode(ctx, workspace, r.Client)
+	if err != nil {
+		reqLogger.Error(err, "Error trying to find standby pod for devworkspace")
+		return
+	}
+	if standbyNode == "" {
+		if _, err := wsprovision.DeleteStandbyDeployment(ctx, workspace, r.Client); err != nil {
+			reqLogger.Error(err, "Error trying to delete standby deployment for devworkspace")
+		}
+		return
+	}
+	if workspace.Annotations[constants.DevWorkspaceStandbyNodeAnnotation] == standbyNode {
+		return
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+
+	workspace.Annotations[constants.DevWorkspaceStandbyNodeAnnotation] = standbyNode
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to apply standby node annotation to workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to apply standby node annotation to devworkspace")
+		}
+	}
+}
+
+func (r *DevWorkspaceReconciler) removeStandbyNodeFromCluster(
+	ctx context.Context, workspace *common.DevWorkspaceWithConfig, reqLogger logr.Logger) {
+	if _, ok := workspace.Annotations[constants.DevWorkspaceStandbyNodeAnnotation]; !ok {
+		return
+	}
+
+	delete(workspace.Annotations, constants.DevWorkspaceStandbyNodeAnnotation)
+	if err := r.Update(ctx, workspace.DevWorkspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			reqLogger.Info("Got conflict when trying to remove standby node annotation from workspace")
+		} else {
+			reqLogger.Error(err, "Error trying to remove standby node annotation from devworkspace")
+		}
+	}
+}
@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+)
+
+const (
+	// startQueueFairPerNamespaceOrdering is the workspace.startQueue.ordering that starts queued DevWorkspaces
+	// from each namespace in turn.
+	startQueueFairPerNamespaceOrdering = "FairPerNamespace"
+
+	// startQueueRequeueInterval is how often DevWorkspaces waiting in the start queue check whether they can be started.
+	startQueueRequeueInterval = 10 * time.Second
+)
+
+// checkStartQueue returns a message describing the position of a DevWorkspace in the start queue if starting it would
+// exceed workspace.startQueue.maxConcurrentStarts. DevWorkspaces that are running or that have already been admitted
+// are not checked again. Returns an empty string if the DevWorkspace may start.
+//
+// As admission is decided from the (cached) DevWorkspaces on the cluster, concurrent reconciles can briefly admit more
+// DevWorkspaces than allowed; the limit should be treated as approximate.
+func (r *DevWorkspaceReconciler) checkStartQueue(ctx context.Context, workspace *common.DevWorkspaceWithConfig, startQueue *controllerv1alpha1.StartQueueConfig) (string, error) {
+	if workspace.Status.Phase == dw.DevWorkspaceStatusRunning || isStartAdmitted(workspace.DevWorkspace) {
+		return "", nil
+	}
+	workspaces := &dw.DevWorkspaceList{}
+	if err := r.List(ctx, workspaces); err != nil {
+		return "", err
+	}
+
+	starting := 0
+	queue := []*dw.DevWorkspace{workspace.DevWorkspace}
+	for idx := range workspaces.Items {
+		other := &workspaces.Items[idx]
+		if (other.Namespace == workspace.Namespace && other.Name == workspace.Name) ||
+			!other.Spec.Started || other.Status.Phase != dw.DevWorkspaceStatusStarting {
+			continue
+		}
+		if isStartAdmitted(other) {
+			starting++
+		} else if isInStartQueue(other) {
+			queue = append(queue, other)
+		}
+	}
+	sortStartQueue(queue, startQueue.Ordering)
+
+	available := int(*startQueue.MaxConcurrentStarts) - starting
+	if available < 0 {
+		available = 0
+	}
+	for idx, queued := range queue {
+		if queued.Namespace != workspace.Namespace || queued.Name != workspace.Name {
+			continue
+		}
+		if idx < available {
+			return "", nil
+		}
+		return fmt.Sprintf("Waiting in start queue: position %d of %d (%d DevWorkspaces starting, maximum %d)",
+			idx-available+1, len(queue)-available, starting, *startQueue.MaxConcurrentStarts), nil
+	}
+	return "", nil
+}
+
+// sortStartQueue sorts queued DevWorkspaces in the order in which they should be started. DevWorkspaces are ordered
+// by the time they were started by the user. With the FairPerNamespace ordering, the first DevWorkspace from each
+// namespace is ordered before the second DevWorkspace from any namespace, and so on.
+func sortStartQueue(queue []*dw.DevWorkspace, ordering string) {
+	sort.SliceStable(queue, func(i, j int) bool {
+		return isStartedBefore(queue[i], queue[j])
+	})
+	if ordering != startQueueFairPerNamespaceOrdering {
+		return
+	}
+	rounds := map[*dw.DevWorkspace]int{}
+	perNamespace := map[string]int{}
+	for _, workspace := range queue {
+		rounds[workspace] = perNamespace[workspace.Namespace]
+		perNamespace[workspace.Namespace]++
+	}
+	sort.SliceStable(queue, func(i, j int) bool {
+		return rounds[queue[i]] < rounds[queue[j]]
+	})
+}
+
+// isStartedBefore compares DevWorkspaces by the time their Started condition was set, which is when the current start
+// of the DevWorkspace was first reconciled. Ties are broken by creation time, then namespace and name, so that the
+// order is stable across reconciles.
+func isStartedBefore(a, b *dw.DevWorkspace) bool {
+	aStart, bStart := getStartTime(a), getStartTime(b)
+	if !aStart.Equal(bStart) {
+		return aStart.Before(bStart)
+	}
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+func getStartTime(workspace *dw.DevWorkspace) time.Time {
+	startedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.Started)
+	if startedCondition == nil {
+		return time.Time{}
+	}
+	return startedCondition.LastTransitionTime.Time
+}
+
+// isStartAdmitted returns whether a DevWorkspace has been admitted by the start queue.
+func isStartAdmitted(workspace *dw.DevWorkspace) bool {
+	condition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.StartAdmitted)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// isInStartQueue returns whether a DevWorkspace is waiting in the start queue. DevWorkspaces that have not yet been
+// checked against the start queue are not considered queued, so that DevWorkspaces that are blocked earlier in their
+// startup (e.g. by running workspace limits) do not hold up the queue.
+func isInStartQueue(workspace *dw.DevWorkspace) bool {
+	condition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.StartAdmitted)
+	return condition != nil && condition.Status == corev1.ConditionFalse
+}
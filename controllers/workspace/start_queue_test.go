@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+)
+
+var startQueueTestTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func getStartQueueTestWorkspace(name, namespace string, startedMinutesAgo int, phase dw.DevWorkspacePhase, admitted corev1.ConditionStatus) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, phase)
+	workspace.Namespace = namespace
+	workspace.Spec.Started = true
+	workspace.Status.Conditions = []dw.DevWorkspaceCondition{
+		{
+			Type:               conditions.Started,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(startQueueTestTime.Add(-time.Duration(startedMinutesAgo) * time.Minute)),
+		},
+	}
+	if admitted != "" {
+		workspace.Status.Conditions = append(workspace.Status.Conditions, dw.DevWorkspaceCondition{Type: conditions.StartAdmitted, Status: admitted})
+	}
+	return workspace
+}
+
+func TestCheckStartQueueFIFO(t *testing.T) {
+	startQueue := &v1alpha1.StartQueueConfig{MaxConcurrentStarts: pointer.Int32(2), Ordering: "FIFO"}
+	current := &common.DevWorkspaceWithConfig{DevWorkspace: getStartQueueTestWorkspace("current", "ns-a", 5, dw.DevWorkspaceStatusStarting, "")}
+	reconciler := getLimitsTestReconciler(
+		current.DevWorkspace,
+		getStartQueueTestWorkspace("starting", "ns-b", 10, dw.DevWorkspaceStatusStarting, corev1.ConditionTrue),
+		getStartQueueTestWorkspace("running", "ns-b", 20, dw.DevWorkspaceStatusRunning, corev1.ConditionTrue),
+		getStartQueueTestWorkspace("queued-earlier", "ns-b", 8, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse),
+		getStartQueueTestWorkspace("queued-later", "ns-b", 2, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse),
+		getStartQueueTestWorkspace("not-checked", "ns-b", 30, dw.DevWorkspaceStatusStarting, ""),
+	)
+
+	msg, err := reconciler.checkStartQueue(context.Background(), current, startQueue)
+	assert.NoError(t, err)
+	assert.Equal(t, "Waiting in start queue: position 1 of 2 (1 DevWorkspaces starting, maximum 2)", msg,
+		"Should queue behind DevWorkspaces that were started earlier, ignoring running and unchecked DevWorkspaces")
+
+	startQueue.MaxConcurrentStarts = pointer.Int32(3)
+	msg, err = reconciler.checkStartQueue(context.Background(), current, startQueue)
+	assert.NoError(t, err)
+	assert.Empty(t, msg, "Should admit DevWorkspace when a slot is available for it")
+}
+
+func TestCheckStartQueueFairPerNamespace(t *testing.T) {
+	startQueue := &v1alpha1.StartQueueConfig{MaxConcurrentStarts: pointer.Int32(1), Ordering: "FairPerNamespace"}
+	current := &common.DevWorkspaceWithConfig{DevWorkspace: getStartQueueTestWorkspace("current", "ns-a", 1, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse)}
+	reconciler := getLimitsTestReconciler(
+		current.DevWorkspace,
+		getStartQueueTestWorkspace("starting", "ns-b", 10, dw.DevWorkspaceStatusStarting, corev1.ConditionTrue),
+		getStartQueueTestWorkspace("classroom-1", "ns-b", 9, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse),
+		getStartQueueTestWorkspace("classroom-2", "ns-b", 8, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse),
+		getStartQueueTestWorkspace("classroom-3", "ns-b", 7, dw.DevWorkspaceStatusStarting, corev1.ConditionFalse),
+	)
+
+	msg, err := reconciler.checkStartQueue(context.Background(), current, startQueue)
+	assert.NoError(t, err)
+	assert.Equal(t, "Waiting in start queue: position 2 of 4 (1 DevWorkspaces starting, maximum 1)", msg,
+		"Should queue only behind the first DevWorkspace from other namespaces")
+
+	startQueue.Ordering = "FIFO"
+	msg, err = reconciler.checkStartQueue(context.Background(), current, startQueue)
+	assert.NoError(t, err)
+	assert.Equal(t, "Waiting in start queue: position 4 of 4 (1 DevWorkspaces starting, maximum 1)", msg)
+}
+
+func TestCheckStartQueueSkipsAdmittedWorkspaces(t *testing.T) {
+	startQueue := &v1alpha1.StartQueueConfig{MaxConcurrentStarts: pointer.Int32(1)}
+	admitted := getStartQueueTestWorkspace("admitted", "ns-a", 1, dw.DevWorkspaceStatusStarting, corev1.ConditionTrue)
+	running := getStartQueueTestWorkspace("running", "ns-a", 1, dw.DevWorkspaceStatusRunning, "")
+	reconciler := getLimitsTestReconciler(
+		admitted, running,
+		getStartQueueTestWorkspace("starting", "ns-b", 10, dw.DevWorkspaceStatusStarting, corev1.ConditionTrue),
+	)
+	for _, workspace := range []*dw.DevWorkspace{admitted, running} {
+		msg, err := reconciler.checkStartQueue(context.Background(), &common.DevWorkspaceWithConfig{DevWorkspace: workspace}, startQueue)
+		assert.NoError(t, err)
+		assert.Empty(t, msg, "Should not queue DevWorkspace %s", workspace.Name)
+	}
+}
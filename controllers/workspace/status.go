@@ -26,6 +26,7 @@ import (
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 
@@ -37,8 +38,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/audit"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/eventsink"
 	"github.com/devfile/devworkspace-operator/controllers/workspace/metrics"
 	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/shard"
 )
 
 const (
@@ -66,6 +70,9 @@ var clock kubeclock.Clock = &kubeclock.RealClock{}
 // updating the status.
 func (r *DevWorkspaceReconciler) updateWorkspaceStatus(workspace *common.DevWorkspaceWithConfig, logger logr.Logger, status *currentStatus, reconcileResult reconcile.Result, reconcileError error) (reconcile.Result, error) {
 	oldWorkspace := workspace.DevWorkspace.DeepCopy()
+	if shard.IsEnabled() {
+		status.setConditionTrue(conditions.ShardAssigned, shard.GetAssignmentMessage())
+	}
 	syncConditions(&workspace.Status, status)
 	oldPhase := workspace.Status.Phase
 	workspace.Status.Phase = status.phase
@@ -93,6 +100,8 @@ func (r *DevWorkspaceReconciler) updateWorkspaceStatus(workspace *common.DevWork
 		}
 	} else {
 		updateMetricsForPhase(workspace, oldPhase, status.phase, logger)
+		r.sendLifecycleEvent(workspace, &oldWorkspace.Status, logger)
+		r.recordAuditEvents(workspace, &oldWorkspace.Status)
 	}
 
 	return reconcileResult, reconcileError
@@ -278,7 +287,69 @@ func updateMetricsForPhase(workspace *common.DevWorkspaceWithConfig, oldPhase, n
 		metrics.WorkspaceRunning(workspace, logger)
 	case dw.DevWorkspaceStatusFailed:
 		metrics.WorkspaceFailed(workspace, logger)
+	case dw.DevWorkspaceStatusStopped:
+		metrics.WorkspaceStopped(workspace, logger)
+	}
+}
+
+// sendLifecycleEvent sends events to the event sink configured in the global DevWorkspaceOperatorConfig if the
+// workspace transitioned to a phase that corresponds to a lifecycle event, and for every phase transition if phase
+// transition events are enabled. Events are sent asynchronously to avoid blocking reconciles on a slow or unavailable
+// event sink.
+func (r *DevWorkspaceReconciler) sendLifecycleEvent(workspace *common.DevWorkspaceWithConfig, oldStatus *dw.DevWorkspaceStatus, logger logr.Logger) {
+	globalConfig := config.GetGlobalConfig()
+	if globalConfig == nil || globalConfig.EventSink == nil || globalConfig.EventSink.URL == "" {
+		return
+	}
+	sinkConfig := globalConfig.EventSink
+	var events []*eventsink.Event
+	if event := eventsink.GetEventForPhase(workspace, oldStatus.Phase, workspace.Status.Phase, clock.Now()); event != nil {
+		events = append(events, event)
+	}
+	if sinkConfig.PhaseTransitions != nil && *sinkConfig.PhaseTransitions {
+		if event := eventsink.GetPhaseChangedEvent(workspace, oldStatus, clock.Now()); event != nil {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return
+	}
+	go func() {
+		for _, event := range events {
+			if err := eventsink.Send(context.Background(), event, sinkConfig, httpClient, r.NonCachingClient); err != nil {
+				logger.Info("Failed to send DevWorkspace lifecycle event", "event", event.Type, "error", err.Error())
+			}
+		}
+	}()
+}
+
+// recordAuditEvents records audit events for a DevWorkspace whose status changed from oldStatus, if auditing is
+// enabled in the global DevWorkspaceOperatorConfig. Records are always logged; writing them to the audit ConfigMap and
+// sending them to the audit event sink is done asynchronously to avoid blocking reconciles.
+func (r *DevWorkspaceReconciler) recordAuditEvents(workspace *common.DevWorkspaceWithConfig, oldStatus *dw.DevWorkspaceStatus) {
+	globalConfig := config.GetGlobalConfig()
+	if globalConfig == nil || globalConfig.Audit == nil || globalConfig.Audit.Enable == nil || !*globalConfig.Audit.Enable {
+		return
 	}
+	auditConfig := globalConfig.Audit
+	records := audit.GetRecords(workspace, oldStatus, clock.Now())
+	if len(records) == 0 {
+		return
+	}
+	auditLog := r.Log.WithName("audit")
+	for _, record := range records {
+		audit.Log(auditLog, record)
+	}
+	go func() {
+		for _, record := range records {
+			if err := audit.RecordInConfigMap(context.Background(), r.NonCachingClient, record, auditConfig.ConfigMapEntries); err != nil {
+				auditLog.Info("Failed to write audit record to ConfigMap", "event", record.Type, "namespace", record.Workspace.Namespace, "error", err.Error())
+			}
+			if err := eventsink.Send(context.Background(), record, auditConfig.Sink, httpClient, r.NonCachingClient); err != nil {
+				auditLog.Info("Failed to send audit record to event sink", "event", record.Type, "error", err.Error())
+			}
+		}
+	}()
 }
 
 // checkForStartTimeout checks if the provided workspace has not progressed for longer than the configured
@@ -289,6 +360,10 @@ func checkForStartTimeout(workspace *common.DevWorkspaceWithConfig) error {
 	if workspace.Status.Phase != dw.DevWorkspaceStatusStarting {
 		return nil
 	}
+	if isQueuedWorkspace(workspace) {
+		// Queued workspaces may need to wait for other workspaces to be stopped for longer than the progress timeout
+		return nil
+	}
 	timeout, err := time.ParseDuration(workspace.Config.Workspace.ProgressTimeout)
 	if err != nil {
 		return fmt.Errorf("invalid duration specified for timeout: %w", err)
@@ -306,6 +381,63 @@ func checkForStartTimeout(workspace *common.DevWorkspaceWithConfig) error {
 	return nil
 }
 
+// checkForPhaseTimeout checks if the current startup phase of the provided workspace has taken longer than the
+// timeout configured for it in workspace.phaseTimeouts. The current phase is the first condition (in conditionOrder)
+// that is not true, and the phase is considered to have started at the latest transition time of the conditions
+// preceding it. Workspaces that are not in the "Starting" phase cannot timeout. Returns an error with message when
+// timeout is reached.
+func checkForPhaseTimeout(workspace *common.DevWorkspaceWithConfig) error {
+	phaseTimeouts := workspace.Config.Workspace.PhaseTimeouts
+	if phaseTimeouts == nil || workspace.Status.Phase != dw.DevWorkspaceStatusStarting {
+		return nil
+	}
+	status := workspaceConditionsFromClusterObject(workspace.Status.Conditions)
+	phaseStartTime := time.Time{}
+	for _, condType := range conditionOrder {
+		condition, present := status.conditions[condType]
+		if !present || condition.Status == corev1.ConditionUnknown {
+			// Not all conditions are set for every workspace
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			if condition.LastTransitionTime.Time.After(phaseStartTime) {
+				phaseStartTime = condition.LastTransitionTime.Time
+			}
+			continue
+		}
+		timeoutStr := getPhaseTimeout(phaseTimeouts, condType)
+		if timeoutStr == "" || phaseStartTime.IsZero() {
+			return nil
+		}
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return fmt.Errorf("invalid duration specified for %s phase timeout: %w", condType, err)
+		}
+		if phaseStartTime.Add(timeout).Before(clock.Now()) {
+			return fmt.Errorf("DevWorkspace startup phase %s did not complete within timeout (%s): %s", condType, timeoutStr, condition.Message)
+		}
+		return nil
+	}
+	return nil
+}
+
+// getPhaseTimeout returns the timeout configured for the startup phase that is completed when the given condition
+// becomes true, or an empty string if no timeout applies.
+func getPhaseTimeout(phaseTimeouts *v1alpha1.PhaseTimeoutsConfig, condType dw.DevWorkspaceConditionType) string {
+	switch condType {
+	case dw.DevWorkspaceRoutingReady:
+		return phaseTimeouts.RoutingReady
+	case conditions.StorageReady:
+		return phaseTimeouts.StorageReady
+	case conditions.DeploymentReady:
+		return phaseTimeouts.DeploymentReady
+	case dw.DevWorkspaceReady:
+		return phaseTimeouts.ServersReady
+	default:
+		return ""
+	}
+}
+
 // checkForFailingTimeout checks that the current workspace has not been in the "Failing" state for longer than the
 // configured progress timeout. If the workspace is not in the Failing state or does not have a DevWorkspaceFailed
 // condition set, returns false. Otherwise, returns true if the workspace has timed out. Returns an error if
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclock "k8s.io/utils/clock/testing"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+)
+
+func getPhaseTimeoutTestWorkspace(phaseTimeouts *v1alpha1.PhaseTimeoutsConfig, workspaceConditions ...dw.DevWorkspaceCondition) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusStarting),
+		Config: &v1alpha1.OperatorConfiguration{
+			Workspace: &v1alpha1.WorkspaceConfig{
+				PhaseTimeouts: phaseTimeouts,
+			},
+		},
+	}
+	workspace.Status.Conditions = workspaceConditions
+	return workspace
+}
+
+func TestCheckForPhaseTimeout(t *testing.T) {
+	now := time.Now()
+	oldClock := clock
+	clock = kubeclock.NewFakeClock(now)
+	defer func() { clock = oldClock }()
+
+	condition := func(condType dw.DevWorkspaceConditionType, status corev1.ConditionStatus, ago time.Duration, msg string) dw.DevWorkspaceCondition {
+		return dw.DevWorkspaceCondition{
+			Type:               condType,
+			Status:             status,
+			LastTransitionTime: metav1.Time{Time: now.Add(-ago)},
+			Message:            msg,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		phaseTimeouts *v1alpha1.PhaseTimeoutsConfig
+		conditions    []dw.DevWorkspaceCondition
+		expectedErr   string
+	}{
+		{
+			name:          "No phase timeouts configured",
+			phaseTimeouts: nil,
+			conditions: []dw.DevWorkspaceCondition{
+				condition(conditions.Started, corev1.ConditionTrue, time.Hour, "DevWorkspace is starting"),
+				condition(conditions.StorageReady, corev1.ConditionFalse, time.Second, "Provisioning storage"),
+			},
+		},
+		{
+			name:          "Current phase within timeout",
+			phaseTimeouts: &v1alpha1.PhaseTimeoutsConfig{StorageReady: "5m"},
+			conditions: []dw.DevWorkspaceCondition{
+				condition(conditions.Started, corev1.ConditionTrue, time.Minute, "DevWorkspace is starting"),
+				condition(conditions.StorageReady, corev1.ConditionFalse, time.Second, "Provisioning storage"),
+			},
+		},
+		{
+			name:          "Current phase timed out even if its condition was recently updated",
+			phaseTimeouts: &v1alpha1.PhaseTimeoutsConfig{StorageReady: "5m"},
+			conditions: []dw.DevWorkspaceCondition{
+				condition(conditions.Started, corev1.ConditionTrue, 10*time.Minute, "DevWorkspace is starting"),
+				condition(conditions.StorageReady, corev1.ConditionFalse, time.Second, "Provisioning storage"),
+			},
+			expectedErr: "DevWorkspace startup phase StorageReady did not complete within timeout (5m): Provisioning storage",
+		},
+		{
+			name:          "Timeout for other phase does not apply",
+			phaseTimeouts: &v1alpha1.PhaseTimeoutsConfig{RoutingReady: "1m"},
+			conditions: []dw.DevWorkspaceCondition{
+				condition(conditions.Started, corev1.ConditionTrue, 10*time.Minute, "DevWorkspace is starting"),
+				condition(conditions.StorageReady, corev1.ConditionFalse, time.Second, "Provisioning storage"),
+			},
+		},
+		{
+			name:          "Phase starts when previous condition becomes true",
+			phaseTimeouts: &v1alpha1.PhaseTimeoutsConfig{DeploymentReady: "2m"},
+			conditions: []dw.DevWorkspaceCondition{
+				condition(conditions.Started, corev1.ConditionTrue, 10*time.Minute, "DevWorkspace is starting"),
+				condition(dw.DevWorkspaceRoutingReady, corev1.ConditionTrue, 3*time.Minute, "Networking ready"),
+				condition(conditions.DeploymentReady, corev1.ConditionFalse, 3*time.Minute, "Waiting for workspace deployment"),
+			},
+			expectedErr: "DevWorkspace startup phase DeploymentReady did not complete within timeout (2m): Waiting for workspace deployment",
+		},
+		{
+			name:          "Invalid timeout",
+			phaseTimeouts: &v1alpha1.PhaseTimeoutsConfig{ServersReady: "invalid"},
+			conditions: []dw.DevWorkspaceCondition{
+				condition(conditions.DeploymentReady, corev1.ConditionTrue, time.Minute, "DevWorkspace deployment ready"),
+				condition(dw.DevWorkspaceReady, corev1.ConditionFalse, time.Second, "Waiting for editor to start"),
+			},
+			expectedErr: "invalid duration specified for Ready phase timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkForPhaseTimeout(getPhaseTimeoutTestWorkspace(tt.phaseTimeouts, tt.conditions...))
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	wkspConfig "github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/usage"
+)
+
+// isUsageAccountingEnabled returns whether usage accounting is enabled in the global config.
+func isUsageAccountingEnabled() bool {
+	globalConfig := wkspConfig.GetGlobalConfig()
+	if globalConfig == nil || globalConfig.Workspace == nil || globalConfig.Workspace.UsageAccounting == nil {
+		return false
+	}
+	enable := globalConfig.Workspace.UsageAccounting.Enable
+	return enable != nil && *enable
+}
+
+// recordRunUsage adds the usage of the current run of a workspace to its usage annotation, and returns the usage of
+// the run by month so that it can be recorded in the usage ConfigMap once the workspace is updated. The requested
+// resources are read from the workspace's deployment, which is still present when the workspace is being stopped.
+func (r *DevWorkspaceReconciler) recordRunUsage(ctx context.Context, workspace *common.DevWorkspaceWithConfig, logger logr.Logger) map[string]usage.Usage {
+	resources := usage.Resources{}
+	deployment := &appsv1.Deployment{}
+	deployNN := types.NamespacedName{
+		Name:      common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations),
+		Namespace: workspace.Namespace,
+	}
+	if err := r.Get(ctx, deployNN, deployment); err == nil {
+		resources = usage.GetRequestedResources(&deployment.Spec.Template.Spec)
+	} else if !k8sErrors.IsNotFound(err) {
+		logger.Error(err, "Failed to read deployment to record usage for devworkspace")
+	}
+	monthlyUsage, err := usage.RecordRun(workspace.DevWorkspace, resources, clock.Now())
+	if err != nil {
+		logger.Error(err, "Failed to record usage for devworkspace")
+		return nil
+	}
+	return monthlyUsage
+}
+
+// syncUsageToConfigMap adds the usage of a workspace's run to the usage ConfigMap in the workspace's namespace.
+func (r *DevWorkspaceReconciler) syncUsageToConfigMap(ctx context.Context, workspace *common.DevWorkspaceWithConfig, monthlyUsage map[string]usage.Usage, logger logr.Logger) {
+	if err := usage.RecordInConfigMap(ctx, r.NonCachingClient, workspace.Namespace, workspace.Name, monthlyUsage); err != nil {
+		logger.Error(err, "Failed to record usage for devworkspace in ConfigMap", "configmap", usage.ConfigMapName)
+	}
+}
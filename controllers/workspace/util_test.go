@@ -64,10 +64,10 @@ func createStartedDevWorkspace(name, fromFile string) {
 	workspaceID := devworkspace.Status.DevWorkspaceId
 
 	By("Manually making Routing ready to continue")
-	markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID))
+	markRoutingReady("test-url", common.DevWorkspaceRoutingName(workspaceID, nil))
 
 	By("Setting the deployment to have 1 ready replica")
-	markDeploymentReady(common.DeploymentName(workspaceID))
+	markDeploymentReady(common.DeploymentName(workspaceID, nil))
 
 	currDW := &dw.DevWorkspace{}
 	Eventually(func() (dw.DevWorkspacePhase, error) {
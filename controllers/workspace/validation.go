@@ -20,6 +20,8 @@ import (
 
 	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/access"
 	"github.com/devfile/devworkspace-operator/pkg/webhook"
 )
 
@@ -34,6 +36,11 @@ func (r *DevWorkspaceReconciler) validateCreatorLabel(workspace *common.DevWorks
 			fmt.Errorf("devworkspace does not have creator label applied")
 	}
 
+	// Webhooks are not managed by the controller in namespace-scoped mode
+	if infrastructure.IsNamespaceScoped() {
+		return "", nil
+	}
+
 	webhooksTimestamp, err := webhook.GetWebhooksCreationTimestamp(r.Client)
 	if err != nil {
 		return "Could not read devworkspace webhooks on cluster. Contact an administrator " +
@@ -47,3 +54,23 @@ func (r *DevWorkspaceReconciler) validateCreatorLabel(workspace *common.DevWorks
 
 	return "", nil
 }
+
+// checkNamespaceScopedSupport checks that a flattened DevWorkspace does not use features that are only safe when the
+// DevWorkspace webhook server is installed, which is not the case in namespace-scoped mode. Kubernetes and OpenShift
+// components are applied with the controller's permissions and rely on the webhook server to check that the user is
+// allowed to create their objects, and restricted access is only enforced for pods and deployments by the webhook
+// server.
+func checkNamespaceScopedSupport(workspace *common.DevWorkspaceWithConfig) error {
+	if !infrastructure.IsNamespaceScoped() {
+		return nil
+	}
+	if access.IsRestrictedAccess(workspace, workspace.Config.Workspace.AccessControl) {
+		return fmt.Errorf("restricted access is not supported when the DevWorkspace Operator runs in namespace-scoped mode")
+	}
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Kubernetes != nil || component.Openshift != nil {
+			return fmt.Errorf("component %s: Kubernetes and OpenShift components are not supported when the DevWorkspace Operator runs in namespace-scoped mode", component.Name)
+		}
+	}
+	return nil
+}
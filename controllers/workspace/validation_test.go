@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+type namespaceScopedTestCase struct {
+	Name   string                    `json:"name,omitempty"`
+	Input  namespaceScopedTestInput  `json:"input,omitempty"`
+	Output namespaceScopedTestOutput `json:"output,omitempty"`
+}
+
+type namespaceScopedTestInput struct {
+	Namespaced  bool                                `json:"namespaced,omitempty"`
+	Annotations map[string]string                   `json:"annotations,omitempty"`
+	Config      *controllerv1alpha1.WorkspaceConfig `json:"config,omitempty"`
+	Workspace   dw.DevWorkspaceTemplateSpec         `json:"workspace,omitempty"`
+}
+
+type namespaceScopedTestOutput struct {
+	ErrRegexp *string `json:"errRegexp,omitempty"`
+}
+
+func loadNamespaceScopedTestCaseOrPanic(t *testing.T, testFilepath string) namespaceScopedTestCase {
+	bytes, err := os.ReadFile(testFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test namespaceScopedTestCase
+	if err := yaml.Unmarshal(bytes, &test); err != nil {
+		t.Fatal(err)
+	}
+	return test
+}
+
+func loadAllNamespaceScopedTestCasesOrPanic(t *testing.T, fromDir string) []namespaceScopedTestCase {
+	files, err := os.ReadDir(fromDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []namespaceScopedTestCase
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		tests = append(tests, loadNamespaceScopedTestCaseOrPanic(t, filepath.Join(fromDir, file.Name())))
+	}
+	return tests
+}
+
+func getNamespaceScopedTestWorkspace(input namespaceScopedTestInput) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: testutil.NewDevWorkspace("test-workspace", ""),
+		Config:       &controllerv1alpha1.OperatorConfiguration{Workspace: &controllerv1alpha1.WorkspaceConfig{}},
+	}
+	for key, value := range input.Annotations {
+		workspace.Annotations[key] = value
+	}
+	if input.Config != nil {
+		workspace.Config.Workspace = input.Config
+	}
+	workspace.Spec.Template = input.Workspace
+	return workspace
+}
+
+func TestCheckNamespaceScopedSupport(t *testing.T) {
+	tests := loadAllNamespaceScopedTestCasesOrPanic(t, "testdata/namespace-scoped")
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if tt.Input.Namespaced {
+				t.Setenv(infrastructure.WatchedNamespacesEnvVar, testutil.TestNamespace)
+			} else {
+				t.Setenv(infrastructure.WatchedNamespacesEnvVar, "")
+			}
+			err := checkNamespaceScopedSupport(getNamespaceScopedTestWorkspace(tt.Input))
+			if tt.Output.ErrRegexp != nil && assert.Error(t, err) {
+				assert.Regexp(t, *tt.Output.ErrRegexp, err.Error(), "Error message should match")
+			} else {
+				assert.NoError(t, err, "Should not return error")
+			}
+		})
+	}
+}
@@ -39,6 +39,7 @@ const (
 	asyncStorageServerImageEnvVar  = "RELATED_IMAGE_async_storage_server"
 	asyncStorageSidecarImageEnvVar = "RELATED_IMAGE_async_storage_sidecar"
 	projectCloneImageEnvVar        = "RELATED_IMAGE_project_clone"
+	workspaceSnapshotImageEnvVar   = "RELATED_IMAGE_workspace_snapshot"
 )
 
 // GetWebhookServerImage returns the image reference for the webhook server image. Returns
@@ -92,6 +93,18 @@ func GetAsyncStorageSidecarImage() string {
 	return val
 }
 
+// GetWorkspaceSnapshotImage returns the image reference used by jobs that archive or restore workspace
+// storage for DevWorkspaceSnapshots, and by containers that back up and restore projects. The image must provide
+// tar and curl.
+func GetWorkspaceSnapshotImage() string {
+	val, ok := os.LookupEnv(workspaceSnapshotImageEnvVar)
+	if !ok {
+		log.Error(fmt.Errorf("environment variable %s is not set", workspaceSnapshotImageEnvVar), "Could not get workspace snapshot image")
+		return ""
+	}
+	return val
+}
+
 func GetProjectCloneImage() string {
 	val, ok := os.LookupEnv(projectCloneImageEnvVar)
 	if !ok {
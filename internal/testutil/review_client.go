@@ -0,0 +1,56 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package testutil
+
+import (
+	"context"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReviewClient answers TokenReviews and SubjectAccessReviews, which are not supported by the fake client. All other
+// requests are passed to the wrapped client.
+type ReviewClient struct {
+	client.Client
+	// Users maps bearer tokens to the users they authenticate as. Other tokens are not authenticated.
+	Users map[string]authnv1.UserInfo
+	// Permissions maps usernames to the verb, resource and subresource they are allowed to access in any namespace.
+	// A verb or resource of "*" matches any verb or resource. Other users are denied access.
+	Permissions map[string]authzv1.ResourceAttributes
+}
+
+func (c *ReviewClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	switch review := obj.(type) {
+	case *authnv1.TokenReview:
+		if user, ok := c.Users[review.Spec.Token]; ok {
+			review.Status = authnv1.TokenReviewStatus{Authenticated: true, User: user}
+		}
+		return nil
+	case *authzv1.SubjectAccessReview:
+		allowed, ok := c.Permissions[review.Spec.User]
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = ok && attrs != nil &&
+			matches(allowed.Verb, attrs.Verb) && matches(allowed.Resource, attrs.Resource) && attrs.Subresource == allowed.Subresource
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func matches(allowed, requested string) bool {
+	return allowed == "*" || allowed == requested
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package testutil
+
+import (
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// TestNamespace is the namespace of DevWorkspaces returned by NewDevWorkspace
+	TestNamespace = "test-namespace"
+	// TestCreatorUID is the UID of the user that created DevWorkspaces returned by NewDevWorkspace
+	TestCreatorUID = "test-creator"
+)
+
+// NewDevWorkspace returns an empty DevWorkspace with the given name in TestNamespace, created by TestCreatorUID. The
+// DevWorkspace is in the given phase and is started if the phase is Starting or Running. Its ID is the name followed
+// by "id", e.g. "test-workspaceid" for "test-workspace".
+func NewDevWorkspace(name string, phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+	return &dw.DevWorkspace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DevWorkspace",
+			APIVersion: dw.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   TestNamespace,
+			UID:         types.UID(name + "-uid"),
+			Labels:      map[string]string{constants.DevWorkspaceCreatorLabel: TestCreatorUID},
+			Annotations: map[string]string{},
+		},
+		Spec: dw.DevWorkspaceSpec{
+			Started: phase == dw.DevWorkspaceStatusStarting || phase == dw.DevWorkspaceStatusRunning,
+		},
+		Status: dw.DevWorkspaceStatus{
+			DevWorkspaceId: name + "id",
+			Phase:          phase,
+		},
+	}
+}
+
+// NewDevWorkspaceWithConfig returns the DevWorkspace from NewDevWorkspace with the default operator configuration.
+func NewDevWorkspaceWithConfig(name string, phase dw.DevWorkspacePhase) *common.DevWorkspaceWithConfig {
+	return &common.DevWorkspaceWithConfig{
+		DevWorkspace: NewDevWorkspace(name, phase),
+		Config:       config.GetConfigForTesting(nil),
+	}
+}
@@ -21,13 +21,30 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacebulkoperation"
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspaceguestsession"
 	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacerouting"
 	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacerouting/solvers"
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacesnapshot"
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacetask"
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacetrash"
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspaceusernamespace"
+	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspaceworkshop"
+	"github.com/devfile/devworkspace-operator/controllers/workspace/metrics"
 	"github.com/devfile/devworkspace-operator/pkg/cache"
+	"github.com/devfile/devworkspace-operator/pkg/commandexec"
 	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/gitwebhook"
 	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
 	kubesync "github.com/devfile/devworkspace-operator/pkg/library/kubernetes"
+	"github.com/devfile/devworkspace-operator/pkg/logstream"
+	"github.com/devfile/devworkspace-operator/pkg/provision/imagepuller"
+	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
+	"github.com/devfile/devworkspace-operator/pkg/provision/warmpool"
+	"github.com/devfile/devworkspace-operator/pkg/shard"
+	"github.com/devfile/devworkspace-operator/pkg/terminal"
 	"github.com/devfile/devworkspace-operator/pkg/webhook"
 	"github.com/devfile/devworkspace-operator/version"
 
@@ -37,7 +54,9 @@ import (
 	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 	workspacecontroller "github.com/devfile/devworkspace-operator/controllers/workspace"
 
+	buildv1 "github.com/openshift/api/build/v1"
 	configv1 "github.com/openshift/api/config/v1"
+	imagev1 "github.com/openshift/api/image/v1"
 	oauthv1 "github.com/openshift/api/oauth/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	securityv1 "github.com/openshift/api/security/v1"
@@ -83,6 +102,10 @@ func init() {
 		utilruntime.Must(securityv1.Install(scheme))
 		// Enable controller to read cluster-wide proxy on OpenShift
 		utilruntime.Must(configv1.AddToScheme(scheme))
+		// Enable controller to resolve ImageStreamTags used by workspace containers
+		utilruntime.Must(imagev1.Install(scheme))
+		// Enable controller to build images of devfile image components
+		utilruntime.Must(buildv1.Install(scheme))
 	}
 
 	// +kubebuilder:scaffold:scheme
@@ -99,12 +122,27 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(config.GetDevModeEnabled())))
 
+	if err := shard.Initialize(); err != nil {
+		setupLog.Error(err, "invalid controller shard configuration")
+		os.Exit(1)
+	}
+	if shard.IsEnabled() && infrastructure.IsNamespaceScoped() {
+		setupLog.Error(fmt.Errorf("%s cannot be set when running as multiple shards", infrastructure.WatchedNamespacesEnvVar), "invalid controller shard configuration")
+		os.Exit(1)
+	}
+
 	// Print versions
 	setupLog.Info(fmt.Sprintf("Operator Version: %s", version.Version))
 	setupLog.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
 	setupLog.Info(fmt.Sprintf("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH))
 	setupLog.Info(fmt.Sprintf("Commit: %s", version.Commit))
 	setupLog.Info(fmt.Sprintf("BuildTime: %s", version.BuildTime))
+	if shard.IsEnabled() {
+		setupLog.Info(fmt.Sprintf("Running as controller shard %d of %d", shard.GetIndex(), shard.GetCount()))
+	}
+	if infrastructure.IsNamespaceScoped() {
+		setupLog.Info(fmt.Sprintf("Running in namespace-scoped mode, watching namespaces: %s", strings.Join(infrastructure.GetWatchedNamespaces(), ", ")))
+	}
 
 	if err := kubesync.InitializeDeserializer(scheme); err != nil {
 		setupLog.Error(err, "failed to initialized Kubernetes objects decoder")
@@ -122,8 +160,9 @@ func main() {
 		Port:                   9443,
 		HealthProbeBindAddress: ":6789",
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "8d217f93.devfile.io",
+		LeaderElectionID:       shard.GetLeaderElectionID("8d217f93.devfile.io"),
 		NewCache:               cacheFunc,
+		ClientDisableCacheFor:  getUncachedObjects(),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -155,7 +194,7 @@ func main() {
 		Log:          ctrl.Log.WithName("controllers").WithName("DevWorkspaceRouting"),
 		Scheme:       mgr.GetScheme(),
 		SolverGetter: &solvers.SolverGetter{},
-		DebugLogging: config.ExperimentalFeaturesEnabled(),
+		DebugLogging: config.FeatureEnabled(config.DebugLogging),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceRouting")
 		os.Exit(1)
@@ -169,6 +208,132 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "DevWorkspace")
 		os.Exit(1)
 	}
+	// The cluster proxy configuration is cluster-scoped, so it is not watched when running in namespace-scoped mode
+	if !infrastructure.IsNamespaceScoped() {
+		if err = mgr.Add(&config.ClusterProxyWatcher{
+			Client: nonCachingClient,
+			Log:    ctrl.Log.WithName("cluster-proxy"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up cluster proxy watcher")
+			os.Exit(1)
+		}
+	}
+	// When running as multiple shards, only DevWorkspaces and DevWorkspaceRoutings are sharded. All other controllers
+	// and cluster-wide components run in the primary shard only.
+	if shard.IsPrimary() {
+		if err = (&devworkspacesnapshot.DevWorkspaceSnapshotReconciler{
+			Client:           mgr.GetClient(),
+			NonCachingClient: nonCachingClient,
+			Log:              ctrl.Log.WithName("controllers").WithName("DevWorkspaceSnapshot"),
+			Scheme:           mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceSnapshot")
+			os.Exit(1)
+		}
+		// Guest sessions, user namespaces and workshops create namespaces, which requires cluster-wide permissions
+		if !infrastructure.IsNamespaceScoped() {
+			if err = (&devworkspaceguestsession.DevWorkspaceGuestSessionReconciler{
+				Client:           mgr.GetClient(),
+				NonCachingClient: nonCachingClient,
+				Log:              ctrl.Log.WithName("controllers").WithName("DevWorkspaceGuestSession"),
+				Scheme:           mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceGuestSession")
+				os.Exit(1)
+			}
+			if err = (&devworkspaceusernamespace.DevWorkspaceUserNamespaceReconciler{
+				Client:           mgr.GetClient(),
+				NonCachingClient: nonCachingClient,
+				Log:              ctrl.Log.WithName("controllers").WithName("DevWorkspaceUserNamespace"),
+				Scheme:           mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceUserNamespace")
+				os.Exit(1)
+			}
+			if err = (&devworkspaceworkshop.DevWorkspaceWorkshopReconciler{
+				Client: mgr.GetClient(),
+				Log:    ctrl.Log.WithName("controllers").WithName("DevWorkspaceWorkshop"),
+				Scheme: mgr.GetScheme(),
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceWorkshop")
+				os.Exit(1)
+			}
+		}
+		taskExecutor, err := devworkspacetask.NewPodExecutor(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create executor", "controller", "DevWorkspaceTask")
+			os.Exit(1)
+		}
+		if err = (&devworkspacetask.DevWorkspaceTaskReconciler{
+			Client:   mgr.GetClient(),
+			Executor: taskExecutor,
+			Log:      ctrl.Log.WithName("controllers").WithName("DevWorkspaceTask"),
+			Scheme:   mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceTask")
+			os.Exit(1)
+		}
+		if err = (&devworkspacebulkoperation.DevWorkspaceBulkOperationReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DevWorkspaceBulkOperation"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceBulkOperation")
+			os.Exit(1)
+		}
+		if err = (&devworkspacetrash.DevWorkspaceTrashReconciler{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("DevWorkspaceTrash"),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "DevWorkspaceTrash")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&storage.GarbageCollector{
+			Client:           mgr.GetClient(),
+			NonCachingClient: nonCachingClient,
+			Scheme:           mgr.GetScheme(),
+			Log:              ctrl.Log.WithName("storage-gc"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up common PVC garbage collection")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&imagepuller.ImagePuller{
+			Client:           mgr.GetClient(),
+			NonCachingClient: nonCachingClient,
+			Log:              ctrl.Log.WithName("image-puller"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up image puller")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&warmpool.PoolManager{
+			Client:           mgr.GetClient(),
+			NonCachingClient: nonCachingClient,
+			Log:              ctrl.Log.WithName("warm-pools"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up warm pools")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&metrics.ServiceMonitorManager{
+			Client: nonCachingClient,
+			Log:    ctrl.Log.WithName("metrics"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up metrics ServiceMonitor")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&config.EffectiveConfigWriter{
+			Client: nonCachingClient,
+			Scheme: mgr.GetScheme(),
+			Log:    ctrl.Log.WithName("effective-config"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up effective configuration writer")
+			os.Exit(1)
+		}
+		if err = metrics.RegisterWorkspaceCollector(mgr.GetClient(), ctrl.Log.WithName("metrics")); err != nil {
+			setupLog.Error(err, "unable to register DevWorkspace metrics collector")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	// Get a config to talk to the apiserver
@@ -178,10 +343,25 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupLog.Info("setting up webhooks")
-	if err := webhook.SetupWebhooks(context.Background(), cfg); err != nil {
-		setupLog.Error(err, "failed to setup webhooks")
-		os.Exit(1)
+	// Webhook configurations are cluster-scoped, so they are not managed when running in namespace-scoped mode
+	if !infrastructure.IsNamespaceScoped() {
+		setupLog.Info("setting up webhooks")
+		if err := webhook.SetupWebhooks(context.Background(), cfg); err != nil {
+			setupLog.Error(err, "failed to setup webhooks")
+			os.Exit(1)
+		}
+
+		certRotator, err := webhook.GetCertificateRotator(nonCachingClient, ctrl.Log.WithName("webhook-certs"))
+		if err != nil {
+			setupLog.Error(err, "unable to set up webhook certificate rotation")
+			os.Exit(1)
+		}
+		if certRotator != nil {
+			if err := mgr.Add(certRotator); err != nil {
+				setupLog.Error(err, "unable to set up webhook certificate rotation")
+				os.Exit(1)
+			}
+		}
 	}
 
 	if err := ctrl.NewWebhookManagedBy(mgr).For(&dwv1.DevWorkspace{}).Complete(); err != nil {
@@ -191,6 +371,48 @@ func main() {
 		setupLog.Error(err, "failed creating conversion webhook for DevWorkspaces v1alpha2")
 	}
 
+	// Serve terminal sessions over TLS on the webhook server; sessions are refused unless the broker is enabled in the config
+	terminalExecutor, err := terminal.NewExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create terminal executor")
+		os.Exit(1)
+	}
+	mgr.GetWebhookServer().Register(terminal.PathPrefix, &terminal.Broker{
+		Client:   mgr.GetClient(),
+		Executor: terminalExecutor,
+		Log:      ctrl.Log.WithName("terminal"),
+	})
+
+	// Serve workspace container logs over TLS on the webhook server; requests are refused unless enabled in the config
+	logReader, err := logstream.NewLogReader(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create log reader")
+		os.Exit(1)
+	}
+	mgr.GetWebhookServer().Register(logstream.PathPrefix, &logstream.Server{
+		Client: mgr.GetClient(),
+		Logs:   logReader,
+		Log:    ctrl.Log.WithName("logstream"),
+	})
+
+	// Run devfile commands in workspaces over TLS on the webhook server; requests are refused unless enabled in the config
+	commandExecutor, err := commandexec.NewExecutor(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create command executor")
+		os.Exit(1)
+	}
+	mgr.GetWebhookServer().Register(commandexec.PathPrefix, &commandexec.Server{
+		Client:   mgr.GetClient(),
+		Executor: commandExecutor,
+		Log:      ctrl.Log.WithName("commandexec"),
+	})
+
+	// Receive Git webhooks over TLS on the webhook server; requests are refused unless enabled in the config
+	mgr.GetWebhookServer().Register(gitwebhook.PathPrefix, &gitwebhook.Server{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("gitwebhook"),
+	})
+
 	// Setup health check
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "Unable to set up health check")
@@ -210,6 +432,16 @@ func main() {
 	}
 }
 
+// getUncachedObjects returns the objects that should be read directly from the API server rather than the cache. When
+// running in namespace-scoped mode, the controller cannot list or watch namespaces, but can still get the namespaces it
+// has permissions in.
+func getUncachedObjects() []client.Object {
+	if infrastructure.IsNamespaceScoped() {
+		return []client.Object{&corev1.Namespace{}}
+	}
+	return nil
+}
+
 func setupControllerConfig(mgr ctrl.Manager) error {
 	nonCachedClient, err := client.New(mgr.GetConfig(), client.Options{
 		Scheme: mgr.GetScheme(),
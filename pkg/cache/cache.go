@@ -25,6 +25,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
@@ -55,6 +56,9 @@ func GetCacheFunc() (cache.NewCacheFunc, error) {
 		&appsv1.Deployment{}: {
 			Label: devworkspaceObjectSelector,
 		},
+		&appsv1.StatefulSet{}: {
+			Label: devworkspaceObjectSelector,
+		},
 		&corev1.Pod{}: {
 			Label: devworkspaceObjectSelector,
 		},
@@ -70,6 +74,9 @@ func GetCacheFunc() (cache.NewCacheFunc, error) {
 		&networkingv1.Ingress{}: {
 			Label: devworkspaceObjectSelector,
 		},
+		&networkingv1.NetworkPolicy{}: {
+			Label: devworkspaceObjectSelector,
+		},
 		&corev1.ConfigMap{}: {
 			Label: configmapObjectSelector,
 		},
@@ -97,17 +104,44 @@ func GetCacheFunc() (cache.NewCacheFunc, error) {
 		}
 	}
 
+	if infrastructure.IsNamespaceScoped() {
+		namespaces, err := getNamespacesForScopedCache()
+		if err != nil {
+			return nil, err
+		}
+		return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+			opts.SelectorsByObject = selectors
+			return cache.MultiNamespacedCacheBuilder(namespaces)(config, opts)
+		}, nil
+	}
+
 	return cache.BuilderWithOptions(cache.Options{
 		SelectorsByObject: selectors,
 	}), nil
 }
 
+// getNamespacesForScopedCache returns the namespaces that should be cached when running in namespace-scoped mode:
+// the watched namespaces and the operator's namespace, which contains the DevWorkspaceOperatorConfig.
+func getNamespacesForScopedCache() ([]string, error) {
+	operatorNamespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return nil, err
+	}
+	namespaces := infrastructure.GetWatchedNamespaces()
+	for _, ns := range namespaces {
+		if ns == operatorNamespace {
+			return namespaces, nil
+		}
+	}
+	return append(namespaces, operatorNamespace), nil
+}
+
 // GetWebhooksCacheFunc returns a new cache function that restricts the cluster items we store in the webhook
 // server's internal cache. This avoids issues where the webhook server's memory usage scales with the number
 // of objects on the cluster, potentially causing out of memory errors in large clusters.
 func GetWebhooksCacheFunc() (cache.NewCacheFunc, error) {
-	// The webhooks server needs to read pods to validate pods/exec requests. These pods must have the DevWorkspace ID and restricted
-	// access labels (other pods are automatically approved)
+	// The webhooks server needs to read pods to validate pods/exec and pods/attach requests, and deployments and
+	// statefulsets to validate deployments/scale and statefulsets/scale requests. These objects must have the DevWorkspace ID label (other objects are automatically approved)
 	devworkspaceObjectSelector, err := labels.Parse(constants.DevWorkspaceIDLabel)
 	if err != nil {
 		return nil, err
@@ -117,6 +151,12 @@ func GetWebhooksCacheFunc() (cache.NewCacheFunc, error) {
 		&corev1.Pod{}: {
 			Label: devworkspaceObjectSelector,
 		},
+		&appsv1.Deployment{}: {
+			Label: devworkspaceObjectSelector,
+		},
+		&appsv1.StatefulSet{}: {
+			Label: devworkspaceObjectSelector,
+		},
 	}
 
 	return cache.BuilderWithOptions(cache.Options{
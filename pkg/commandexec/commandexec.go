@@ -0,0 +1,354 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package commandexec implements the command execution endpoint, which runs the exec commands defined in the devfiles
+// of running DevWorkspaces on behalf of their users, e.g. to run a build or tests in a DevWorkspace from a CI pipeline.
+// Commands are resolved from the flattened devfile of the DevWorkspace, so that the client does not need to know which
+// container a command runs in or which working directory it uses.
+package commandexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/access"
+	"github.com/devfile/devworkspace-operator/pkg/library/pods"
+	"github.com/devfile/devworkspace-operator/pkg/provision/metadata"
+)
+
+// PathPrefix is the path under which commands are served. The exec commands of a DevWorkspace are listed at
+// <PathPrefix><namespace>/<workspace name>, and a command is run by sending a POST request to
+// <PathPrefix><namespace>/<workspace name>/<command id>.
+const PathPrefix = "/exec/"
+
+const (
+	// defaultTimeout is the time after which a command is stopped if the request does not specify a timeout.
+	defaultTimeout = 10 * time.Minute
+	// maxOutputBytes is the amount of output from the end of a command's output that is returned to the client.
+	maxOutputBytes = 1024 * 1024
+)
+
+// Executor runs commands in pods. It is an interface to allow the streaming APIs, which are not supported by
+// controller-runtime clients, to be replaced in tests.
+type Executor interface {
+	// Exec runs command in the container of a pod, writing both stdout and stderr to output. If the command exits
+	// with a non-zero exit code, the returned error implements k8s.io/client-go/util/exec.ExitError.
+	Exec(ctx context.Context, pod *corev1.Pod, container string, command []string, output io.Writer) error
+}
+
+type clusterExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+var _ Executor = (*clusterExecutor)(nil)
+
+// NewExecutor returns an Executor that uses the Kubernetes API described by config.
+func NewExecutor(config *rest.Config) (Executor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *clusterExecutor) Exec(ctx context.Context, pod *corev1.Pod, container string, command []string, output io.Writer) error {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, clientgoscheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: output,
+		Stderr: output,
+	})
+}
+
+// Server runs the exec commands of running DevWorkspaces. It implements http.Handler and is intended to be registered
+// on the controller manager's webhook server, so that it is served over TLS by the operator's manager service.
+type Server struct {
+	// Client is used to review tokens and access and to read DevWorkspaces, their pods and their metadata configmaps.
+	Client   client.Client
+	Executor Executor
+	Log      logr.Logger
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// CommandList is the response body when listing the exec commands of a DevWorkspace.
+type CommandList struct {
+	Commands []CommandInfo `json:"commands"`
+}
+
+type CommandInfo struct {
+	Id string `json:"id"`
+	// Component is the name of the container component, and of the container, the command runs in.
+	Component   string `json:"component"`
+	CommandLine string `json:"commandLine"`
+	WorkingDir  string `json:"workingDir,omitempty"`
+	// Group is the kind of the command's group, e.g. "build" or "test", if any.
+	Group string `json:"group,omitempty"`
+}
+
+// Result is the response body when running a command.
+type Result struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	// ExitCode is the exit code of the command. It is not set if the command did not complete.
+	ExitCode *int `json:"exitCode,omitempty"`
+	// Error describes why the command did not complete, e.g. as it timed out.
+	Error string `json:"error,omitempty"`
+	// Output is the combined stdout and stderr of the command. If the command writes more than 1 MiB of output, only
+	// the end of the output is returned.
+	Output string `json:"output"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	execConfig := config.GetGlobalConfig().CommandExec
+	if execConfig == nil || !pointer.BoolDeref(execConfig.Enable, false) {
+		http.Error(w, "command execution is not enabled", http.StatusNotFound)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if (len(parts) != 2 && len(parts) != 3) || parts[0] == "" || parts[1] == "" || (len(parts) == 3 && parts[2] == "") {
+		http.Error(w, fmt.Sprintf("expected path %s<namespace>/<workspace>[/<command>]", PathPrefix), http.StatusNotFound)
+		return
+	}
+	if len(parts) == 2 && r.Method != http.MethodGet {
+		http.Error(w, "only GET requests are supported when listing commands", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(parts) == 3 && r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported when running commands", http.StatusMethodNotAllowed)
+		return
+	}
+	namespace, name := parts[0], parts[1]
+	ctx := r.Context()
+	log := s.Log.WithValues("namespace", namespace, "workspace", name)
+
+	workspace, user := access.AuthorizeRequest(w, r, s.Client, namespace, name, authzv1.ResourceAttributes{
+		Verb:        "create",
+		Resource:    "pods",
+		Subresource: "exec",
+	}, log)
+	if workspace == nil {
+		return
+	}
+	log = log.WithValues("user", user.Username)
+
+	if workspace.Status.Phase != dw.DevWorkspaceStatusRunning {
+		http.Error(w, fmt.Sprintf("workspace %s is not running", workspace.Name), http.StatusConflict)
+		return
+	}
+	flattened, err := metadata.GetFlattenedTemplate(ctx, s.Client, workspace)
+	if err != nil {
+		log.Error(err, "Failed to read flattened devfile for command request")
+		http.Error(w, "failed to read workspace devfile", http.StatusInternalServerError)
+		return
+	}
+	if flattened == nil {
+		http.Error(w, fmt.Sprintf("workspace %s is not running", workspace.Name), http.StatusConflict)
+		return
+	}
+
+	if len(parts) == 2 {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(getCommandList(flattened)); err != nil {
+			log.Error(err, "Failed to write command list")
+		}
+		return
+	}
+	s.runCommand(w, r, workspace, flattened, parts[2], log)
+}
+
+// runCommand runs an exec command in the running pod of a DevWorkspace and writes its Result to the response. The
+// request may set the timeoutSeconds query parameter to change how long the command may run for; by default, commands
+// are stopped after 10 minutes.
+func (s *Server) runCommand(w http.ResponseWriter, r *http.Request, workspace *dw.DevWorkspace, flattened *dw.DevWorkspaceTemplateSpec, commandId string, log logr.Logger) {
+	timeout := defaultTimeout
+	if timeoutSeconds := r.URL.Query().Get("timeoutSeconds"); timeoutSeconds != "" {
+		seconds, err := strconv.Atoi(timeoutSeconds)
+		if err != nil || seconds <= 0 {
+			http.Error(w, "timeoutSeconds must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	command, err := getExecCommand(flattened, commandId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	pod, err := pods.GetRunningWorkspacePod(r.Context(), s.Client, workspace.Namespace, workspace.Status.DevWorkspaceId)
+	if err != nil {
+		log.Error(err, "Failed to get pod for command request")
+		http.Error(w, "failed to get workspace pod", http.StatusInternalServerError)
+		return
+	}
+	if pod == nil {
+		http.Error(w, fmt.Sprintf("workspace %s is not running", workspace.Name), http.StatusConflict)
+		return
+	}
+	if !hasContainer(pod, command.Exec.Component) {
+		http.Error(w, fmt.Sprintf("workspace pod has no container %s", command.Exec.Component), http.StatusConflict)
+		return
+	}
+
+	log.Info("Running command", "command", command.Id, "pod", pod.Name, "container", command.Exec.Component)
+	execCtx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	output := &outputBuffer{}
+	execErr := s.Executor.Exec(execCtx, pod, command.Exec.Component, []string{"/bin/sh", "-c", getCommandScript(command.Exec)}, output)
+
+	result := &Result{
+		Pod:       pod.Name,
+		Container: command.Exec.Component,
+		Output:    output.String(),
+	}
+	var exitErr exec.ExitError
+	switch {
+	case execErr == nil:
+		result.ExitCode = pointer.Int(0)
+	case errors.As(execErr, &exitErr):
+		result.ExitCode = pointer.Int(exitErr.ExitStatus())
+	case errors.Is(execCtx.Err(), context.DeadlineExceeded):
+		result.Error = fmt.Sprintf("command did not complete within %s", timeout)
+	case r.Context().Err() != nil:
+		// The client disconnected
+		log.Info("Client disconnected while running command", "command", command.Id)
+		return
+	default:
+		log.Error(execErr, "Failed to run command", "command", command.Id)
+		result.Error = fmt.Sprintf("failed to run command: %s", execErr)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err, "Failed to write command result")
+	}
+}
+
+// getExecCommand returns the exec command with the given ID from a flattened devfile.
+func getExecCommand(flattened *dw.DevWorkspaceTemplateSpec, commandId string) (*dw.Command, error) {
+	for _, command := range flattened.Commands {
+		if command.Key() != commandId {
+			continue
+		}
+		if command.Exec == nil {
+			return nil, fmt.Errorf("command %s is not an exec command; only exec commands can be run", commandId)
+		}
+		return command.DeepCopy(), nil
+	}
+	return nil, fmt.Errorf("workspace does not define command %s", commandId)
+}
+
+func getCommandList(flattened *dw.DevWorkspaceTemplateSpec) *CommandList {
+	list := &CommandList{Commands: []CommandInfo{}}
+	for _, command := range flattened.Commands {
+		if command.Exec == nil {
+			continue
+		}
+		info := CommandInfo{
+			Id:          command.Key(),
+			Component:   command.Exec.Component,
+			CommandLine: command.Exec.CommandLine,
+			WorkingDir:  command.Exec.WorkingDir,
+		}
+		if command.Exec.Group != nil {
+			info.Group = string(command.Exec.Group.Kind)
+		}
+		list.Commands = append(list.Commands, info)
+	}
+	return list
+}
+
+// getCommandScript returns the shell script that runs an exec command. The script has the format
+//
+//	export <env name>='<env value>'
+//	cd <workingDir>
+//	<commandline>
+//
+// The working directory is not quoted, so that environment variables such as ${PROJECT_SOURCE} are expanded.
+func getCommandScript(command *dw.ExecCommand) string {
+	var lines []string
+	for _, env := range command.Env {
+		lines = append(lines, fmt.Sprintf("export %s=%s", env.Name, shellQuote(env.Value)))
+	}
+	if command.WorkingDir != "" {
+		lines = append(lines, fmt.Sprintf("cd %s", command.WorkingDir))
+	}
+	lines = append(lines, command.CommandLine)
+	return strings.Join(lines, "\n")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func hasContainer(pod *corev1.Pod, container string) bool {
+	for _, podContainer := range pod.Spec.Containers {
+		if podContainer.Name == container {
+			return true
+		}
+	}
+	return false
+}
+
+// outputBuffer is an io.Writer that retains only the last maxOutputBytes bytes written to it.
+type outputBuffer struct {
+	data []byte
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > maxOutputBytes {
+		b.data = b.data[len(b.data)-maxOutputBytes:]
+	}
+	return len(p), nil
+}
+
+func (b *outputBuffer) String() string {
+	return strings.ToValidUTF8(string(b.data), "")
+}
@@ -0,0 +1,289 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package commandexec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/exec"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	creatorToken   = "creator-token"
+	creatorUID     = testutil.TestCreatorUID
+	execUserToken  = "exec-user-token"
+	otherUserToken = "other-user-token"
+)
+
+// newReviewClient returns a fake client with objs that authenticates the test tokens and allows exec-user to exec into pods.
+func newReviewClient(objs ...client.Object) *testutil.ReviewClient {
+	return &testutil.ReviewClient{
+		Client: testutil.NewFakeClient(objs...),
+		Users: map[string]authnv1.UserInfo{
+			creatorToken:   {Username: "creator", UID: creatorUID},
+			execUserToken:  {Username: "exec-user", UID: "exec-user-uid"},
+			otherUserToken: {Username: "other-user", UID: "other-user-uid"},
+		},
+		Permissions: map[string]authzv1.ResourceAttributes{
+			"exec-user": {Verb: "create", Resource: "pods", Subresource: "exec"},
+		},
+	}
+}
+
+// testExecutor records the commands it runs, writing "<pod>/<container>" as output and exiting with exitCode.
+type testExecutor struct {
+	container string
+	command   []string
+	exitCode  int
+}
+
+func (e *testExecutor) Exec(_ context.Context, pod *corev1.Pod, container string, command []string, output io.Writer) error {
+	e.container = container
+	e.command = command
+	fmt.Fprintf(output, "%s/%s", pod.Name, container)
+	if e.exitCode != 0 {
+		return exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code %d", e.exitCode), Code: e.exitCode}
+	}
+	return nil
+}
+
+func getTestPod(name, workspaceID string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: workspaceID},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "tools"}, {Name: "plugin-tools"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+// getTestMetadataConfigMap returns the metadata configmap of a workspace, with a flattened devfile that contains a
+// command contributed by a plugin.
+func getTestMetadataConfigMap(t *testing.T, workspaceID string) *corev1.ConfigMap {
+	flattened := dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Commands: []dw.Command{
+				{
+					Id: "build",
+					CommandUnion: dw.CommandUnion{
+						Exec: &dw.ExecCommand{
+							LabeledCommand: dw.LabeledCommand{
+								BaseCommand: dw.BaseCommand{Group: &dw.CommandGroup{Kind: dw.BuildCommandGroupKind}},
+							},
+							Component:   "tools",
+							CommandLine: "make build",
+							WorkingDir:  "${PROJECT_SOURCE}",
+							Env:         []dw.EnvVar{{Name: "GOFLAGS", Value: "-mod=vendor"}},
+						},
+					},
+				},
+				{
+					Id: "plugin-lint",
+					CommandUnion: dw.CommandUnion{
+						Exec: &dw.ExecCommand{Component: "plugin-tools", CommandLine: "lint"},
+					},
+				},
+				{
+					Id: "init-db",
+					CommandUnion: dw.CommandUnion{
+						Apply: &dw.ApplyCommand{Component: "db"},
+					},
+				},
+			},
+		},
+	}
+	flattenedYaml, err := yaml.Marshal(flattened)
+	require.NoError(t, err)
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.MetadataConfigMapName(workspaceID),
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			"flattened.devworkspace.yaml": string(flattenedYaml),
+		},
+	}
+}
+
+func setupTestServer(t *testing.T, objs ...client.Object) (*httptest.Server, *testExecutor) {
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		CommandExec: &v1alpha1.CommandExecConfig{
+			Enable: pointer.Bool(true),
+		},
+	})
+	executor := &testExecutor{}
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, &Server{
+		Client:   newReviewClient(objs...),
+		Executor: executor,
+		Log:      zap.New(),
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, executor
+}
+
+func doRequest(t *testing.T, server *httptest.Server, method, path, token string) (int, string) {
+	req, err := http.NewRequest(method, server.URL+path, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestListCommands(t *testing.T) {
+	server, _ := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		getTestPod("test-pod", "test-workspaceid"),
+		getTestMetadataConfigMap(t, "test-workspaceid"))
+
+	code, body := doRequest(t, server, http.MethodGet, PathPrefix+testNamespace+"/test-workspace", creatorToken)
+	require.Equal(t, http.StatusOK, code, body)
+	list := &CommandList{}
+	require.NoError(t, json.Unmarshal([]byte(body), list))
+	assert.Equal(t, []CommandInfo{
+		{Id: "build", Component: "tools", CommandLine: "make build", WorkingDir: "${PROJECT_SOURCE}", Group: "build"},
+		{Id: "plugin-lint", Component: "plugin-tools", CommandLine: "lint"},
+	}, list.Commands, "Should list exec commands from flattened devfile")
+}
+
+func TestRunCommand(t *testing.T) {
+	server, executor := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		getTestPod("test-pod", "test-workspaceid"),
+		getTestMetadataConfigMap(t, "test-workspaceid"))
+
+	code, body := doRequest(t, server, http.MethodPost, PathPrefix+testNamespace+"/test-workspace/build", creatorToken)
+	require.Equal(t, http.StatusOK, code, body)
+	result := &Result{}
+	require.NoError(t, json.Unmarshal([]byte(body), result))
+	assert.Equal(t, "tools", result.Container)
+	assert.Equal(t, pointer.Int(0), result.ExitCode)
+	assert.Equal(t, "test-pod", result.Pod)
+	assert.Equal(t, "test-pod/tools", result.Output)
+	assert.Equal(t, "tools", executor.container)
+	assert.Equal(t, []string{"/bin/sh", "-c", "export GOFLAGS='-mod=vendor'\ncd ${PROJECT_SOURCE}\nmake build"}, executor.command)
+
+	executor.exitCode = 2
+	code, body = doRequest(t, server, http.MethodPost, PathPrefix+testNamespace+"/test-workspace/plugin-lint", creatorToken)
+	require.Equal(t, http.StatusOK, code, body)
+	result = &Result{}
+	require.NoError(t, json.Unmarshal([]byte(body), result))
+	assert.Equal(t, "plugin-tools", executor.container, "Should run commands contributed by plugins in their container")
+	assert.Equal(t, pointer.Int(2), result.ExitCode, "Should return exit code of failed command")
+	assert.Empty(t, result.Error)
+}
+
+func TestRunCommandSkipsJobPods(t *testing.T) {
+	taskPod := getTestPod("test-task-pod", "test-workspaceid")
+	taskPod.Labels["job-name"] = "test-task"
+	server, _ := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		taskPod,
+		getTestMetadataConfigMap(t, "test-workspaceid"))
+
+	code, _ := doRequest(t, server, http.MethodPost, PathPrefix+testNamespace+"/test-workspace/build", creatorToken)
+	assert.Equal(t, http.StatusConflict, code, "Should not run commands in pods created by jobs")
+}
+
+func TestCommandRequests(t *testing.T) {
+	restricted := testutil.NewDevWorkspace("restricted-workspace", dw.DevWorkspaceStatusRunning)
+	restricted.Annotations = map[string]string{constants.DevWorkspaceRestrictedAccessAnnotation: "true"}
+	stopped := testutil.NewDevWorkspace("stopped-workspace", dw.DevWorkspaceStatusRunning)
+	stopped.Status.Phase = dw.DevWorkspaceStatusStopped
+	server, _ := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		getTestPod("test-pod", "test-workspaceid"),
+		getTestMetadataConfigMap(t, "test-workspaceid"),
+		restricted,
+		getTestPod("restricted-pod", "restricted-workspaceid"),
+		getTestMetadataConfigMap(t, "restricted-workspaceid"),
+		stopped,
+		getTestMetadataConfigMap(t, "stopped-workspace-id"))
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"No token", http.MethodPost, "test-workspace/build", "", http.StatusUnauthorized},
+		{"Invalid token", http.MethodPost, "test-workspace/build", "invalid-token", http.StatusUnauthorized},
+		{"User without access", http.MethodPost, "test-workspace/build", otherUserToken, http.StatusForbidden},
+		{"User with pods/exec access", http.MethodPost, "test-workspace/build", execUserToken, http.StatusOK},
+		{"Workspace not found", http.MethodPost, "missing-workspace/build", execUserToken, http.StatusForbidden},
+		{"Restricted access workspace", http.MethodGet, "restricted-workspace", execUserToken, http.StatusForbidden},
+		{"Restricted access workspace creator", http.MethodGet, "restricted-workspace", creatorToken, http.StatusOK},
+		{"Stopped workspace", http.MethodPost, "stopped-workspace/build", creatorToken, http.StatusConflict},
+		{"Command not found", http.MethodPost, "test-workspace/missing", creatorToken, http.StatusNotFound},
+		{"Not an exec command", http.MethodPost, "test-workspace/init-db", creatorToken, http.StatusNotFound},
+		{"Invalid timeout", http.MethodPost, "test-workspace/build?timeoutSeconds=0", creatorToken, http.StatusBadRequest},
+		{"Run command with GET", http.MethodGet, "test-workspace/build", creatorToken, http.StatusMethodNotAllowed},
+		{"List commands with POST", http.MethodPost, "test-workspace", creatorToken, http.StatusMethodNotAllowed},
+		{"Invalid path", http.MethodPost, "test-workspace/build/extra", creatorToken, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := doRequest(t, server, tt.method, PathPrefix+testNamespace+"/"+tt.path, tt.token)
+			assert.Equal(t, tt.expectedCode, code, strings.TrimSpace(body))
+		})
+	}
+}
+
+func TestCommandExecDisabled(t *testing.T) {
+	server, _ := setupTestServer(t, testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning))
+	config.SetGlobalConfigForTesting(nil)
+	code, _ := doRequest(t, server, http.MethodGet, PathPrefix+testNamespace+"/test-workspace", creatorToken)
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -27,8 +27,8 @@ import (
 
 var NonAlphaNumRegexp = regexp.MustCompile(`[^a-z0-9]+`)
 
-func DevWorkspaceRoutingName(workspaceId string) string {
-	return fmt.Sprintf("routing-%s", workspaceId)
+func DevWorkspaceRoutingName(workspaceId string, annotations map[string]string) string {
+	return GetWorkspaceObjectNames(workspaceId, annotations).Routing
 }
 
 func EndpointName(endpointName string) string {
@@ -45,8 +45,8 @@ func PortName(endpoint dw.Endpoint) string {
 	return fmt.Sprintf("%d-%s", endpoint.TargetPort, endpoint.Protocol)
 }
 
-func ServiceName(workspaceId string) string {
-	return fmt.Sprintf("%s-%s", workspaceId, "service")
+func ServiceName(workspaceId string, annotations map[string]string) string {
+	return GetWorkspaceObjectNames(workspaceId, annotations).Service
 }
 
 func ServiceAccountName(workspace *DevWorkspaceWithConfig) string {
@@ -96,7 +96,19 @@ func RouteName(workspaceId, endpointName string) string {
 	return fmt.Sprintf("%s-%s", workspaceId, endpointName)
 }
 
-func DeploymentName(workspaceId string) string {
+func DeploymentName(workspaceId string, annotations map[string]string) string {
+	return GetWorkspaceObjectNames(workspaceId, annotations).Deployment
+}
+
+func BackgroundDeploymentName(workspaceId string) string {
+	return fmt.Sprintf("%s-background", workspaceId)
+}
+
+func StandbyDeploymentName(workspaceId string) string {
+	return fmt.Sprintf("%s-standby", workspaceId)
+}
+
+func PodGroupName(workspaceId string) string {
 	return workspaceId
 }
 
@@ -108,14 +120,121 @@ func PVCCleanupJobName(workspaceId string) string {
 	return fmt.Sprintf("cleanup-%s", workspaceId)
 }
 
-func PerWorkspacePVCName(workspaceId string) string {
-	return fmt.Sprintf("storage-%s", workspaceId)
+// FinalizerHookJobName is the name of the Job run for a finalizer hook when a DevWorkspace is deleted.
+func FinalizerHookJobName(hookName, workspaceId string) string {
+	return fmt.Sprintf("hook-%s-%s", hookName, workspaceId)
+}
+
+// StorageGCJobGenerateName is the prefix for names of jobs that remove orphaned DevWorkspace data from a common PVC.
+func StorageGCJobGenerateName() string {
+	return "storage-gc-"
+}
+
+// SnapshotJobName is the name of the job that archives DevWorkspace data for a DevWorkspaceSnapshot, identified
+// by its UID.
+func SnapshotJobName(snapshotUID string) string {
+	return fmt.Sprintf("snapshot-%s", snapshotUID)
+}
+
+func SnapshotRestoreJobName(workspaceId string) string {
+	return fmt.Sprintf("restore-%s", workspaceId)
+}
+
+// CloneStorageJobName is the name of the job that copies the persistent data of another DevWorkspace into the
+// storage of a cloned DevWorkspace.
+func CloneStorageJobName(workspaceId string) string {
+	return fmt.Sprintf("clone-storage-%s", workspaceId)
+}
+
+// ImageBuildName is the name of the Job or BuildConfig that builds the image of a devfile image component, and of
+// the ImageStream the image is pushed to on OpenShift if no registry is configured.
+func ImageBuildName(workspaceId, componentName string) string {
+	return fmt.Sprintf("build-%s-%s", workspaceId, componentName)
+}
+
+// ProjectBackupSecretName is the name of the secret that holds a copy of the object storage credentials used to back
+// up a workspace's projects.
+func ProjectBackupSecretName(workspaceId string) string {
+	return fmt.Sprintf("%s-project-backup", workspaceId)
+}
+
+// GuestSessionNamespaceName is the name of the namespace generated for a DevWorkspaceGuestSession
+func GuestSessionNamespaceName(prefix, sessionUID string) string {
+	return fmt.Sprintf("%s%s", prefix, sessionUID)
+}
+
+// UserNamespaceName is the name of the namespace provisioned for a user, generated from a template that may contain
+// the placeholders "<username>" and "<userid>". The result is lowercased, characters that are not valid in a
+// namespace name are replaced with '-' and it is truncated to 63 characters.
+func UserNamespaceName(template, username, userID string) string {
+	name := strings.NewReplacer("<username>", username, "<userid>", userID).Replace(template)
+	name = strings.ToLower(name)
+	name = NonAlphaNumRegexp.ReplaceAllString(name, "-")
+	if len(name) > 63 {
+		name = name[:63]
+	}
+	return strings.Trim(name, "-")
+}
+
+// WorkshopParticipantNamespaceName is the name of the namespace created for a participant in a DevWorkspaceWorkshop
+func WorkshopParticipantNamespaceName(workshopName, participant string) string {
+	return fmt.Sprintf("%s-%s", workshopName, participant)
+}
+
+// TaskJobName is the name of the job that runs the command of a DevWorkspaceTask, identified by its UID.
+func TaskJobName(taskUID string) string {
+	return fmt.Sprintf("task-%s", taskUID)
+}
+
+// PreStopTaskName is the name of the DevWorkspaceTask that runs a command bound to a preStop event of a DevWorkspace
+// when graceful stop is enabled. Tasks are identified by the time the DevWorkspace was started, so that commands are
+// run once each time it stops.
+func PreStopTaskName(workspaceId string, commandIdx int, startedAt string) string {
+	return fmt.Sprintf("%s-prestop-%d-%s", workspaceId, commandIdx, startedAt)
+}
+
+// PostStopTaskName is the name of the DevWorkspaceTask that runs a command bound to a postStop event of a DevWorkspace.
+// Tasks are identified by the time the DevWorkspace was started, so that commands are run once each time it stops.
+func PostStopTaskName(workspaceId string, commandIdx int, startedAt string) string {
+	return fmt.Sprintf("%s-poststop-%d-%s", workspaceId, commandIdx, startedAt)
+}
+
+// HeadlessTaskName is the name of the DevWorkspaceTask that runs the command from the headless-command attribute of a
+// DevWorkspace. Tasks are identified by the time the DevWorkspace was started, so that the command is run once each
+// time it starts.
+func HeadlessTaskName(workspaceId string, startedAt string) string {
+	return fmt.Sprintf("%s-headless-%s", workspaceId, startedAt)
+}
+
+// HeadlessOutputConfigMapName is the name of the ConfigMap that the output of the command from the headless-command
+// attribute of a DevWorkspace is written to.
+func HeadlessOutputConfigMapName(workspaceId string) string {
+	return fmt.Sprintf("%s-headless-output", workspaceId)
+}
+
+func PerWorkspacePVCName(workspaceId string, annotations map[string]string) string {
+	return GetWorkspaceObjectNames(workspaceId, annotations).PVC
+}
+
+// DedicatedVolumePVCName returns the name of the PVC provisioned for a volume component with the dedicated-pvc
+// attribute.
+func DedicatedVolumePVCName(workspaceId, volumeName string) string {
+	return fmt.Sprintf("%s-%s", workspaceId, volumeName)
+}
+
+// TrashConfigMapName returns the name of the ConfigMap that stores a deleted DevWorkspace while it is in the trash.
+func TrashConfigMapName(workspaceId string) string {
+	return fmt.Sprintf("%s-trash", workspaceId)
 }
 
 func MetadataConfigMapName(workspaceId string) string {
 	return fmt.Sprintf("%s-metadata", workspaceId)
 }
 
+func ExplainConfigMapName(workspaceId string) string {
+	return fmt.Sprintf("%s-explain", workspaceId)
+}
+
 // We can't add prefixes to automount volume names, as adding any characters
 // can potentially push the name over the 63 character limit (if the original
 // object has a long name)
@@ -151,6 +270,16 @@ func WorkspaceRolebindingName() string {
 	return "devworkspace-default-rolebinding"
 }
 
+// WorkspaceScopedRoleName is the name of the Role that only grants permissions to the ServiceAccount of a single
+// DevWorkspace, used when scoped RBAC is enabled.
+func WorkspaceScopedRoleName(workspaceId string) string {
+	return fmt.Sprintf("%s-role", workspaceId)
+}
+
+func WorkspaceScopedRolebindingName(workspaceId string) string {
+	return fmt.Sprintf("%s-rolebinding", workspaceId)
+}
+
 func WorkspaceSCCRoleName(sccName string) string {
 	return fmt.Sprintf("devworkspace-use-%s", sccName)
 }
@@ -159,6 +288,12 @@ func WorkspaceSCCRolebindingName(sccName string) string {
 	return fmt.Sprintf("devworkspace-use-%s", sccName)
 }
 
+// WorkspaceImagePullerRolebindingName is the name of the rolebinding that allows serviceaccounts in workspaceNamespace
+// to pull images from ImageStreams in the namespace where the rolebinding is created.
+func WorkspaceImagePullerRolebindingName(workspaceNamespace string) string {
+	return fmt.Sprintf("devworkspace-image-puller-%s", workspaceNamespace)
+}
+
 // OldWorkspaceRoleName returns the name used for the workspace serviceaccount role
 //
 // Deprecated: use WorkspaceRoleName() instead.
@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// WorkspaceIdPlaceholder is replaced by the ID of a DevWorkspace in naming templates
+const WorkspaceIdPlaceholder = "<workspace-id>"
+
+// maxWorkspaceIdLength is the maximum length of a DevWorkspace ID, including IDs set via the
+// controller.devfile.io/devworkspace_id_override annotation.
+const maxWorkspaceIdLength = 25
+
+// DefaultNamingTemplates are the templates used for object names when no naming templates are configured
+var DefaultNamingTemplates = controllerv1alpha1.NamingTemplatesConfig{
+	Deployment: WorkspaceIdPlaceholder,
+	Service:    WorkspaceIdPlaceholder + "-service",
+	Routing:    "routing-" + WorkspaceIdPlaceholder,
+	PVC:        "storage-" + WorkspaceIdPlaceholder,
+}
+
+// WorkspaceObjectNames are the names of the objects of a DevWorkspace that are generated from naming templates
+type WorkspaceObjectNames struct {
+	Deployment string `json:"deployment"`
+	Service    string `json:"service"`
+	Routing    string `json:"routing"`
+	PVC        string `json:"pvc"`
+}
+
+var (
+	namingTemplatesMutex sync.RWMutex
+	namingTemplates      = DefaultNamingTemplates
+)
+
+// SetNamingTemplates sets the templates used to generate the names of objects for DevWorkspaces. Templates that are
+// not set use the corresponding default template. Templates are expected to be validated using ValidateNamingTemplates.
+func SetNamingTemplates(templates *controllerv1alpha1.NamingTemplatesConfig) {
+	namingTemplatesMutex.Lock()
+	defer namingTemplatesMutex.Unlock()
+	namingTemplates = DefaultNamingTemplates
+	if templates == nil {
+		return
+	}
+	if templates.Deployment != "" {
+		namingTemplates.Deployment = templates.Deployment
+	}
+	if templates.Service != "" {
+		namingTemplates.Service = templates.Service
+	}
+	if templates.Routing != "" {
+		namingTemplates.Routing = templates.Routing
+	}
+	if templates.PVC != "" {
+		namingTemplates.PVC = templates.PVC
+	}
+}
+
+// ValidateNamingTemplates checks that each template that is set contains the workspace ID placeholder, so that names
+// are unique within a namespace, and that it results in a valid DNS label for any DevWorkspace ID. It also checks
+// that generated names do not collide with names of other objects of the same kind created for a DevWorkspace.
+func ValidateNamingTemplates(templates *controllerv1alpha1.NamingTemplatesConfig) error {
+	if templates == nil {
+		return nil
+	}
+	var errs []string
+	for field, template := range map[string]string{
+		"deployment": templates.Deployment,
+		"service":    templates.Service,
+		"routing":    templates.Routing,
+		"pvc":        templates.PVC,
+	} {
+		if template == "" {
+			continue
+		}
+		if err := validateNamingTemplate(template); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", field, err))
+		}
+	}
+	if templates.Deployment != "" {
+		exampleId := strings.Repeat("a", maxWorkspaceIdLength)
+		if renderNamingTemplate(templates.Deployment, exampleId) == BackgroundDeploymentName(exampleId) {
+			errs = append(errs, "deployment: name collides with the name of the background deployment")
+		}
+	}
+	if len(errs) > 0 {
+		// Sort for stable error messages, as map iteration order is random
+		sort.Strings(errs)
+		return fmt.Errorf("invalid naming templates: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func validateNamingTemplate(template string) error {
+	if !strings.Contains(template, WorkspaceIdPlaceholder) {
+		return fmt.Errorf("template %q must contain %s", template, WorkspaceIdPlaceholder)
+	}
+	longestName := renderNamingTemplate(template, strings.Repeat("a", maxWorkspaceIdLength))
+	if errs := validation.IsDNS1123Label(longestName); len(errs) > 0 {
+		return fmt.Errorf("template %q does not result in a valid name: %s", template, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func renderNamingTemplate(template, workspaceId string) string {
+	return strings.ReplaceAll(template, WorkspaceIdPlaceholder, workspaceId)
+}
+
+func getNamingTemplates() controllerv1alpha1.NamingTemplatesConfig {
+	namingTemplatesMutex.RLock()
+	defer namingTemplatesMutex.RUnlock()
+	return namingTemplates
+}
+
+// GenerateWorkspaceObjectNames renders the currently configured naming templates for a DevWorkspace ID. The result
+// is expected to be recorded in the DevWorkspaceObjectNamesAnnotation when the DevWorkspace's ID is assigned.
+func GenerateWorkspaceObjectNames(workspaceId string) WorkspaceObjectNames {
+	return renderNamingTemplates(getNamingTemplates(), workspaceId)
+}
+
+// GetWorkspaceObjectNames returns the names of the objects of the DevWorkspace with the given ID and annotations. Names
+// recorded in the DevWorkspaceObjectNamesAnnotation are used if present. DevWorkspaces without recorded names were
+// created before naming templates could be configured and so use the default names.
+func GetWorkspaceObjectNames(workspaceId string, annotations map[string]string) WorkspaceObjectNames {
+	names := renderNamingTemplates(DefaultNamingTemplates, workspaceId)
+	recordedJSON, ok := annotations[constants.DevWorkspaceObjectNamesAnnotation]
+	if !ok {
+		return names
+	}
+	recorded := WorkspaceObjectNames{}
+	if err := json.Unmarshal([]byte(recordedJSON), &recorded); err != nil {
+		// The annotation can only be set by the controller, so this should not happen
+		return names
+	}
+	if recorded.Deployment != "" {
+		names.Deployment = recorded.Deployment
+	}
+	if recorded.Service != "" {
+		names.Service = recorded.Service
+	}
+	if recorded.Routing != "" {
+		names.Routing = recorded.Routing
+	}
+	if recorded.PVC != "" {
+		names.PVC = recorded.PVC
+	}
+	return names
+}
+
+func renderNamingTemplates(templates controllerv1alpha1.NamingTemplatesConfig, workspaceId string) WorkspaceObjectNames {
+	return WorkspaceObjectNames{
+		Deployment: renderNamingTemplate(templates.Deployment, workspaceId),
+		Service:    renderNamingTemplate(templates.Service, workspaceId),
+		Routing:    renderNamingTemplate(templates.Routing, workspaceId),
+		PVC:        renderNamingTemplate(templates.PVC, workspaceId),
+	}
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+type namingTemplatesTestCase struct {
+	Name   string                                    `json:"name,omitempty"`
+	Input  *controllerv1alpha1.NamingTemplatesConfig `json:"input,omitempty"`
+	Output namingTemplatesTestOutput                 `json:"output,omitempty"`
+}
+
+type namingTemplatesTestOutput struct {
+	ErrRegexp *string `json:"errRegexp,omitempty"`
+}
+
+func loadNamingTemplatesTestCaseOrPanic(t *testing.T, testFilepath string) namingTemplatesTestCase {
+	bytes, err := os.ReadFile(testFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test namingTemplatesTestCase
+	if err := yaml.Unmarshal(bytes, &test); err != nil {
+		t.Fatal(err)
+	}
+	return test
+}
+
+func loadAllNamingTemplatesTestCasesOrPanic(t *testing.T, fromDir string) []namingTemplatesTestCase {
+	files, err := os.ReadDir(fromDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []namingTemplatesTestCase
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		tests = append(tests, loadNamingTemplatesTestCaseOrPanic(t, filepath.Join(fromDir, file.Name())))
+	}
+	return tests
+}
+
+func TestNamingTemplatesAreAppliedToNewWorkspaces(t *testing.T) {
+	SetNamingTemplates(&controllerv1alpha1.NamingTemplatesConfig{
+		Deployment: "dw-<workspace-id>",
+		PVC:        "<workspace-id>-data",
+	})
+	defer SetNamingTemplates(nil)
+
+	names := GenerateWorkspaceObjectNames("workspace1234")
+	annotations := getObjectNamesAnnotations(t, names)
+
+	assert.Equal(t, "dw-workspace1234", DeploymentName("workspace1234", annotations))
+	assert.Equal(t, "workspace1234-data", PerWorkspacePVCName("workspace1234", annotations))
+	assert.Equal(t, "workspace1234-service", ServiceName("workspace1234", annotations), "Should use default template when not set")
+	assert.Equal(t, "routing-workspace1234", DevWorkspaceRoutingName("workspace1234", annotations), "Should use default template when not set")
+}
+
+func TestRecordedObjectNamesAreKeptWhenTemplatesChange(t *testing.T) {
+	SetNamingTemplates(&controllerv1alpha1.NamingTemplatesConfig{
+		Deployment: "dw-<workspace-id>",
+		PVC:        "<workspace-id>-data",
+	})
+	defer SetNamingTemplates(nil)
+	annotations := getObjectNamesAnnotations(t, GenerateWorkspaceObjectNames("workspace1234"))
+
+	SetNamingTemplates(&controllerv1alpha1.NamingTemplatesConfig{
+		Deployment: "other-<workspace-id>",
+		PVC:        "other-<workspace-id>",
+	})
+
+	assert.Equal(t, "dw-workspace1234", DeploymentName("workspace1234", annotations))
+	assert.Equal(t, "workspace1234-data", PerWorkspacePVCName("workspace1234", annotations))
+}
+
+func TestWorkspacesWithoutRecordedObjectNamesUseDefaultNames(t *testing.T) {
+	SetNamingTemplates(&controllerv1alpha1.NamingTemplatesConfig{
+		Deployment: "dw-<workspace-id>",
+		Service:    "<workspace-id>-svc",
+		Routing:    "<workspace-id>-routing",
+		PVC:        "<workspace-id>-data",
+	})
+	defer SetNamingTemplates(nil)
+
+	assert.Equal(t, "workspace1234", DeploymentName("workspace1234", nil))
+	assert.Equal(t, "workspace1234-service", ServiceName("workspace1234", nil))
+	assert.Equal(t, "routing-workspace1234", DevWorkspaceRoutingName("workspace1234", nil))
+	assert.Equal(t, "storage-workspace1234", PerWorkspacePVCName("workspace1234", nil))
+}
+
+func getObjectNamesAnnotations(t *testing.T, names WorkspaceObjectNames) map[string]string {
+	namesJSON, err := json.Marshal(names)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return map[string]string{constants.DevWorkspaceObjectNamesAnnotation: string(namesJSON)}
+}
+
+func TestValidateNamingTemplates(t *testing.T) {
+	tests := loadAllNamingTemplatesTestCasesOrPanic(t, "testdata/naming-templates")
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := ValidateNamingTemplates(tt.Input)
+			if tt.Output.ErrRegexp != nil && assert.Error(t, err) {
+				assert.Regexp(t, *tt.Output.ErrRegexp, err.Error(), "Error message should match")
+			} else {
+				assert.NoError(t, err, "Should not return error")
+			}
+		})
+	}
+}
@@ -26,8 +26,45 @@ const (
 	DevWorkspaceResolved dw.DevWorkspaceConditionType = "DevWorkspaceResolved"
 	StorageReady         dw.DevWorkspaceConditionType = "StorageReady"
 	KubeComponentsReady  dw.DevWorkspaceConditionType = "KubernetesComponentsProvisioned"
+	ImagesScanned        dw.DevWorkspaceConditionType = "ImagesScanned"
 	DeploymentReady      dw.DevWorkspaceConditionType = "DeploymentReady"
 	DevWorkspaceWarning  dw.DevWorkspaceConditionType = "DevWorkspaceWarning"
+	StartupDiagnostics   dw.DevWorkspaceConditionType = "StartupDiagnostics"
+	StartRetried         dw.DevWorkspaceConditionType = "StartRetried"
+	// LimitsSatisfied is set when workspace.limits are configured, and is false while a workspace is queued because
+	// starting it would exceed the number of running workspaces allowed for its creator or namespace.
+	LimitsSatisfied dw.DevWorkspaceConditionType = "LimitsSatisfied"
+	// StartAdmitted is set when workspace.startQueue is configured, and is false while a workspace is waiting in the
+	// start queue for other workspaces to finish starting.
+	StartAdmitted dw.DevWorkspaceConditionType = "StartAdmitted"
+	// InsufficientResources is set when a workspace container was killed for exceeding its memory limit or
+	// a workspace pod was evicted from its node.
+	InsufficientResources dw.DevWorkspaceConditionType = "InsufficientResources"
+	// RunningBudgetExceeded is set when a workspace was stopped because it exceeded its weekly running budget.
+	RunningBudgetExceeded dw.DevWorkspaceConditionType = "RunningBudgetExceeded"
+	// StoppedBySchedule is set when a workspace was stopped by a stop schedule, or because it was started during a
+	// blackout window.
+	StoppedBySchedule dw.DevWorkspaceConditionType = "StoppedBySchedule"
+	// StoppedForMaintenance is set when a workspace was stopped, or could not be started, because the cluster is in
+	// maintenance mode.
+	StoppedForMaintenance dw.DevWorkspaceConditionType = "StoppedForMaintenance"
+	// ProjectsCloned is set when a workspace's pod has a project clone init container, and is false while projects
+	// are being cloned or if errors were encountered while cloning projects.
+	ProjectsCloned dw.DevWorkspaceConditionType = "ProjectsCloned"
+	// LifecycleEvents is set when a workspace binds commands to preStart, postStart or postStop events. It is false
+	// while the commands are running or if any of them failed.
+	LifecycleEvents dw.DevWorkspaceConditionType = "LifecycleEventsSucceeded"
+	// PreStopEvents is set when graceful stop is enabled and a workspace's preStop commands were run before its pod
+	// was stopped. It is false while the commands are running, or if any of them failed or did not complete in time.
+	PreStopEvents dw.DevWorkspaceConditionType = "PreStopCommandsSucceeded"
+	// HeadlessRunCompleted is set when a workspace with the headless-command attribute was stopped because its
+	// command completed. Its message describes the result of the command.
+	HeadlessRunCompleted dw.DevWorkspaceConditionType = "HeadlessRunCompleted"
+	// DebugStart is set when a workspace has the debug-start annotation, and lists the workspace's pods.
+	DebugStart dw.DevWorkspaceConditionType = "DebugStart"
+	// ShardAssigned is set when the controller is run as multiple shards, and describes which shard reconciles the
+	// workspace.
+	ShardAssigned dw.DevWorkspaceConditionType = "ShardAssigned"
 )
 
 func GetConditionByType(conditions []dw.DevWorkspaceCondition, t dw.DevWorkspaceConditionType) *dw.DevWorkspaceCondition {
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config/proxy"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const defaultEnvironmentCheckInterval = 5 * time.Minute
+
+// GetEnvironmentCheckInterval returns how often running DevWorkspaces and the cluster-wide proxy configuration should
+// be checked for changes, according to the provided config. If the interval is not set or invalid, the default
+// interval is returned.
+func GetEnvironmentCheckInterval(envConfig *controller.EnvironmentUpdatesConfig) time.Duration {
+	if envConfig == nil || envConfig.CheckInterval == "" {
+		return defaultEnvironmentCheckInterval
+	}
+	interval, err := time.ParseDuration(envConfig.CheckInterval)
+	if err != nil || interval <= 0 {
+		return defaultEnvironmentCheckInterval
+	}
+	return interval
+}
+
+// ClusterProxyWatcher periodically reads the cluster-wide proxy configuration on OpenShift and updates the global
+// config when it changes, so that DevWorkspaces do not keep using the proxy configuration that was present when the
+// controller started. It is intended to be added to the controller manager. As every instance of the controller
+// keeps its own copy of the global config, it runs regardless of leader election.
+type ClusterProxyWatcher struct {
+	Client crclient.Client
+	Log    logr.Logger
+}
+
+func (w *ClusterProxyWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the cluster-wide proxy configuration periodically until ctx is cancelled.
+func (w *ClusterProxyWatcher) Start(ctx context.Context) error {
+	if !infrastructure.IsOpenShift() {
+		// The cluster-wide proxy configuration is only available on OpenShift
+		return nil
+	}
+	for {
+		interval := GetEnvironmentCheckInterval(GetGlobalConfig().Workspace.EnvironmentUpdates)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		changed, err := refreshClusterProxyConfig(w.Client)
+		if err != nil {
+			w.Log.Error(err, "Failed to read cluster proxy configuration")
+			continue
+		}
+		if changed {
+			w.Log.Info("Cluster proxy configuration changed")
+		}
+	}
+}
+
+// refreshClusterProxyConfig reads the cluster-wide proxy configuration and, if it differs from the one currently in
+// use, updates the global config with it. Proxy settings from the DevWorkspaceOperatorConfig take precedence over the
+// cluster-wide proxy configuration, as when the config is synced.
+func refreshClusterProxyConfig(client crclient.Client) (changed bool, err error) {
+	clusterProxy, err := proxy.GetClusterProxyConfig(client)
+	if err != nil {
+		return false, err
+	}
+	clusterConfig, err := getClusterConfig(configNamespace, client)
+	if err != nil {
+		return false, err
+	}
+
+	configMutex.Lock()
+	defer configMutex.Unlock()
+	if reflect.DeepEqual(clusterProxy, defaultConfig.Routing.ProxyConfig) {
+		return false, nil
+	}
+	defaultConfig.Routing.ProxyConfig = clusterProxy
+	internalConfig = defaultConfig.DeepCopy()
+	if clusterConfig != nil {
+		mergeConfig(clusterConfig.Config, internalConfig)
+	}
+	syncNamingTemplates()
+	logCurrentConfig()
+	return true, nil
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+func buildClusterProxy(httpProxy string) *configv1.Proxy {
+	return &configv1.Proxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Status: configv1.ProxyStatus{
+			HTTPProxy: httpProxy,
+		},
+	}
+}
+
+func TestRefreshClusterProxyConfig(t *testing.T) {
+	setupForTest(t)
+	infrastructure.InitializeForTesting(infrastructure.OpenShiftv4)
+	internalConfig = defaultConfig.DeepCopy()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(buildClusterProxy("http://proxy.example.com")).Build()
+
+	changed, err := refreshClusterProxyConfig(client)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.True(t, changed, "Should report change in cluster proxy configuration")
+	if assert.NotNil(t, internalConfig.Routing.ProxyConfig) && assert.NotNil(t, internalConfig.Routing.ProxyConfig.HttpProxy) {
+		assert.Equal(t, "http://proxy.example.com", *internalConfig.Routing.ProxyConfig.HttpProxy)
+	}
+
+	changed, err = refreshClusterProxyConfig(client)
+	assert.NoError(t, err, "Should not return error")
+	assert.False(t, changed, "Should not report change if cluster proxy configuration is unchanged")
+}
+
+func TestRefreshClusterProxyConfigKeepsProxyFromConfig(t *testing.T) {
+	setupForTest(t)
+	infrastructure.InitializeForTesting(infrastructure.OpenShiftv4)
+	configProxy := "http://config-proxy.example.com"
+	clusterConfig := buildConfig(&v1alpha1.OperatorConfiguration{
+		Routing: &v1alpha1.RoutingConfig{
+			ProxyConfig: &v1alpha1.Proxy{
+				HttpProxy: &configProxy,
+			},
+		},
+	})
+	internalConfig = defaultConfig.DeepCopy()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterConfig, buildClusterProxy("http://proxy.example.com")).Build()
+
+	changed, err := refreshClusterProxyConfig(client)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.True(t, changed, "Should report change in cluster proxy configuration")
+	if assert.NotNil(t, internalConfig.Routing.ProxyConfig) && assert.NotNil(t, internalConfig.Routing.ProxyConfig.HttpProxy) {
+		assert.Equal(t, configProxy, *internalConfig.Routing.ProxyConfig.HttpProxy, "Proxy from DevWorkspaceOperatorConfig should take precedence")
+	}
+}
+
+func TestGetEnvironmentCheckInterval(t *testing.T) {
+	assert.Equal(t, defaultEnvironmentCheckInterval, GetEnvironmentCheckInterval(nil))
+	assert.Equal(t, 10*time.Minute, GetEnvironmentCheckInterval(&v1alpha1.EnvironmentUpdatesConfig{CheckInterval: "10m"}))
+	assert.Equal(t, defaultEnvironmentCheckInterval, GetEnvironmentCheckInterval(&v1alpha1.EnvironmentUpdatesConfig{CheckInterval: "ten minutes"}))
+}
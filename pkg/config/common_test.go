@@ -56,6 +56,7 @@ func setupForTest(t *testing.T) {
 	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
 	setDefaultPodSecurityContext()
 	setDefaultContainerSecurityContext()
+	setDefaultSecurityContextPolicy()
 	configNamespace = testNamespace
 	originalDefaultConfig := defaultConfig.DeepCopy()
 	t.Cleanup(func() {
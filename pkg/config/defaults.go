@@ -19,9 +19,11 @@ import (
 	"fmt"
 
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 )
@@ -35,12 +37,62 @@ var defaultConfig = &v1alpha1.OperatorConfiguration{
 	Webhook: &v1alpha1.WebhookConfig{
 		Replicas: pointer.Int32(2),
 	},
+	EventSink: &v1alpha1.EventSinkConfig{
+		Timeout:          "10s",
+		Format:           v1alpha1.EventSinkFormatDevWorkspace,
+		PhaseTransitions: pointer.Bool(false),
+	},
+	Audit: &v1alpha1.AuditConfig{
+		Enable: pointer.Bool(false),
+		Sink: &v1alpha1.EventSinkConfig{
+			Timeout: "10s",
+			Format:  v1alpha1.EventSinkFormatDevWorkspace,
+		},
+	},
+	LogStreaming: &v1alpha1.LogStreamingConfig{
+		Enable: pointer.Bool(false),
+	},
+	CommandExec: &v1alpha1.CommandExecConfig{
+		Enable: pointer.Bool(false),
+	},
+	GitWebhook: &v1alpha1.GitWebhookConfig{
+		Enable: pointer.Bool(false),
+	},
+	Terminal: &v1alpha1.TerminalConfig{
+		Enable:  pointer.Bool(false),
+		Command: []string{"/bin/sh", "-c", "if command -v bash >/dev/null 2>&1; then exec bash; else exec sh; fi"},
+		SessionRecording: &v1alpha1.TerminalSessionRecordingConfig{
+			Timeout: "10s",
+		},
+	},
 	Workspace: &v1alpha1.WorkspaceConfig{
 		ImagePullPolicy:    "Always",
 		DeploymentStrategy: appsv1.RecreateDeploymentStrategyType,
 		PVCName:            "claim-devworkspace",
+		NamingTemplates:    common.DefaultNamingTemplates.DeepCopy(),
 		ServiceAccount: &v1alpha1.ServiceAccountConfig{
 			DisableCreation: pointer.Bool(false),
+			ScopedRBAC: &v1alpha1.ScopedRBACConfig{
+				Enable: pointer.Bool(false),
+				Rules: []rbacv1.PolicyRule{
+					{
+						Resources: []string{"pods"},
+						APIGroups: []string{""},
+						Verbs:     []string{"get", "list", "watch"},
+					},
+					{
+						Resources: []string{"pods/exec"},
+						APIGroups: []string{""},
+						Verbs:     []string{"create"},
+					},
+					{
+						Resources:     []string{"devworkspaces"},
+						APIGroups:     []string{"workspace.devfile.io"},
+						Verbs:         []string{"get", "watch", "patch", "update"},
+						ResourceNames: []string{"<workspace-name>"},
+					},
+				},
+			},
 		},
 		DefaultStorageSize: &v1alpha1.StorageSizes{
 			Common:       &commonStorageSize,
@@ -49,12 +101,88 @@ var defaultConfig = &v1alpha1.OperatorConfiguration{
 		PersistUserHome: &v1alpha1.PersistentHomeConfig{
 			Enabled:              pointer.Bool(false),
 			DisableInitContainer: pointer.Bool(false),
+			DedicatedPVC:         pointer.Bool(false),
+		},
+		Dotfiles: &v1alpha1.DotfilesConfig{
+			Enable: pointer.Bool(false),
+		},
+		IdleTimeout:         "15m",
+		IdleWarningPeriod:   "5m",
+		ProgressTimeout:     "5m",
+		CleanupOnStop:       pointer.Bool(false),
+		AutoResizeCommonPVC: pointer.Bool(false),
+		CommonPVCGarbageCollection: &v1alpha1.CommonPVCGarbageCollectionConfig{
+			Enable:   pointer.Bool(false),
+			Interval: "24h",
+		},
+		ProjectBackup: &v1alpha1.ProjectBackupConfig{
+			Enable:   pointer.Bool(false),
+			Interval: "1h",
+		},
+		GracefulStop: &v1alpha1.GracefulStopConfig{
+			Enable:  pointer.Bool(false),
+			Timeout: "2m",
+		},
+		GuestWorkspaces: &v1alpha1.GuestWorkspacesConfig{
+			Enable:          pointer.Bool(false),
+			NamespacePrefix: "dw-guest-",
+			TTL:             "1h",
+			Quota: corev1.ResourceList{
+				corev1.ResourceLimitsCPU:              resource.MustParse("2"),
+				corev1.ResourceLimitsMemory:           resource.MustParse("4Gi"),
+				corev1.ResourcePods:                   resource.MustParse("10"),
+				corev1.ResourcePersistentVolumeClaims: resource.MustParse("0"),
+			},
+		},
+		NetworkIsolation: &v1alpha1.NetworkIsolationConfig{
+			Enable: pointer.Bool(false),
+		},
+		UserNamespaces: &v1alpha1.UserNamespacesConfig{
+			Enable:          pointer.Bool(false),
+			NameTemplate:    "<username>-devworkspaces",
+			ClusterRoleName: "admin",
+		},
+		StartRetry: &v1alpha1.StartRetryConfig{
+			MaxAttempts:    pointer.Int32(0),
+			InitialBackoff: "30s",
+			MaxBackoff:     "5m",
+		},
+		Limits: &v1alpha1.WorkspaceLimitsConfig{
+			ExceededPolicy: "Queue",
+		},
+		StartQueue: &v1alpha1.StartQueueConfig{
+			Ordering: "FIFO",
+		},
+		GangScheduling: &v1alpha1.GangSchedulingConfig{
+			Enable: pointer.Bool(false),
+		},
+		TerminationGracePeriodSeconds: pointer.Int64(10),
+		NodeFailureRecovery: &v1alpha1.NodeFailureRecoveryConfig{
+			Policy:             "None",
+			TerminationTimeout: "5m",
+		},
+		PluginRegistry: &v1alpha1.PluginRegistryConfig{
+			CacheTTL: "5m",
+		},
+		Trash: &v1alpha1.TrashConfig{
+			Enable:          pointer.Bool(false),
+			RetentionPeriod: "72h",
 		},
-		IdleTimeout:              "15m",
-		ProgressTimeout:          "5m",
-		CleanupOnStop:            pointer.Bool(false),
+		GPUProfile: &v1alpha1.GPUProfileConfig{
+			ResourceNames: []corev1.ResourceName{"nvidia.com/gpu"},
+		},
+		ImageBuild: &v1alpha1.ImageBuildConfig{
+			BuilderImage: "gcr.io/kaniko-project/executor:v1.23.2",
+			Timeout:      "15m",
+		},
+		ImageScanning: &v1alpha1.ImageScanningConfig{
+			SeverityThreshold: "High",
+			Policy:            "Warn",
+		},
+		PinImageDigests:          pointer.Bool(false),
 		PodSecurityContext:       nil, // Set per-platform in setDefaultPodSecurityContext()
 		ContainerSecurityContext: nil, // Set per-platform in setDefaultContainerSecurityContext()
+		SecurityContextPolicy:    nil, // Set per-platform in setDefaultSecurityContextPolicy()
 		DefaultTemplate:          nil,
 		ProjectCloneConfig: &v1alpha1.ProjectCloneConfig{
 			Resources: &corev1.ResourceRequirements{
@@ -76,6 +204,19 @@ var defaultConfig = &v1alpha1.OperatorConfiguration{
 				corev1.ResourceMemory: resource.MustParse("64Mi"),
 			},
 		},
+		Standby: &v1alpha1.StandbyConfig{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("64Mi"),
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+				},
+			},
+			Timeout: "4h",
+		},
+		EnvironmentUpdates: &v1alpha1.EnvironmentUpdatesConfig{
+			Policy:        v1alpha1.EnvironmentUpdateAnnotateOutdated,
+			CheckInterval: "5m",
+		},
 	},
 }
 
@@ -98,6 +239,25 @@ var (
 			},
 		},
 	}
+	defaultKubernetesSecurityContextPolicy = &v1alpha1.SecurityContextPolicyConfig{
+		Enable:                   pointer.Bool(false),
+		RunAsNonRoot:             pointer.Bool(true),
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		FSGroupStrategy:          "RunAsUser",
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+		DropCapabilities: []corev1.Capability{"ALL"},
+		LabelNamespaces:  pointer.Bool(false),
+	}
+	defaultOpenShiftSecurityContextPolicy = &v1alpha1.SecurityContextPolicyConfig{
+		Enable:                   pointer.Bool(false),
+		RunAsNonRoot:             pointer.Bool(true),
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		FSGroupStrategy:          "None",
+		DropCapabilities:         []corev1.Capability{"ALL"},
+		LabelNamespaces:          pointer.Bool(false),
+	}
 )
 
 // Necessary variables for setting pointer values
@@ -129,3 +289,15 @@ func setDefaultContainerSecurityContext() error {
 	}
 	return nil
 }
+
+func setDefaultSecurityContextPolicy() error {
+	if !infrastructure.IsInitialized() {
+		return fmt.Errorf("can not set default security context policy, infrastructure not detected")
+	}
+	if infrastructure.IsOpenShift() {
+		defaultConfig.Workspace.SecurityContextPolicy = defaultOpenShiftSecurityContextPolicy
+	} else {
+		defaultConfig.Workspace.SecurityContextPolicy = defaultKubernetesSecurityContextPolicy
+	}
+	return nil
+}
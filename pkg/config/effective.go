@@ -0,0 +1,279 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	// EffectiveConfigMapName is the name of the ConfigMap in the operator's namespace that shows the effective value of
+	// every property of the global configuration.
+	EffectiveConfigMapName = "devworkspace-effective-config"
+	// effectiveConfigMapSuffix is appended to the name of DevWorkspaceOperatorConfigs other than the global config to
+	// name the ConfigMap that shows their effective configuration.
+	effectiveConfigMapSuffix = "-effective-config"
+	effectiveConfigInterval  = time.Minute
+)
+
+// ConfigSource describes where the effective value of a configuration property comes from.
+type ConfigSource string
+
+const (
+	// SourceDefault means the property has the operator's default value.
+	SourceDefault ConfigSource = "default"
+	// SourceGlobal means the property is set in the global DevWorkspaceOperatorConfig.
+	SourceGlobal ConfigSource = "global"
+	// SourceExternal means the property is set in a DevWorkspaceOperatorConfig referenced by DevWorkspaces through the
+	// controller.devfile.io/devworkspace-config attribute, overriding the global config.
+	SourceExternal ConfigSource = "external"
+)
+
+var effectiveConfigLabels = map[string]string{
+	"app.kubernetes.io/name":    EffectiveConfigMapName,
+	"app.kubernetes.io/part-of": "devworkspace-operator",
+}
+
+// EffectiveProperty is the effective value of a configuration property, serialized as JSON, and its source. The value
+// is null if the property is not set.
+type EffectiveProperty struct {
+	Value  json.RawMessage `json:"value"`
+	Source ConfigSource    `json:"source"`
+}
+
+// GetEffectiveConfig returns every property of the operator configuration, keyed by its path (e.g.
+// "workspace.idleTimeout"), along with its effective value and source. If external is not nil, it is merged over the
+// global config, as for DevWorkspaces that use an external DevWorkspaceOperatorConfig. A property's source is the most
+// specific configuration that changes its value: properties set to their default value are reported as defaults.
+func GetEffectiveConfig(external *controller.OperatorConfiguration) map[string]EffectiveProperty {
+	configMutex.Lock()
+	defaults := getPropertyValues(defaultConfig)
+	global := getPropertyValues(internalConfig)
+	effective := global
+	if external != nil {
+		effective = getPropertyValues(getMergedConfig(external, internalConfig))
+	}
+	configMutex.Unlock()
+
+	properties := map[string]EffectiveProperty{}
+	for path, value := range effective {
+		source := SourceDefault
+		switch {
+		case external != nil && value != global[path]:
+			source = SourceExternal
+		case global[path] != defaults[path]:
+			source = SourceGlobal
+		}
+		if value == "" {
+			value = "null"
+		}
+		properties[path] = EffectiveProperty{Value: json.RawMessage(value), Source: source}
+	}
+	return properties
+}
+
+// getPropertyValues returns the JSON-serialized value of every property of config, keyed by its path. Properties are
+// the fields of the operator configuration types; fields with types from other packages (e.g. Kubernetes types) are
+// treated as single properties. Unset properties have an empty value.
+func getPropertyValues(config *controller.OperatorConfiguration) map[string]string {
+	values := map[string]string{}
+	collectPropertyValues("", reflect.ValueOf(config), values)
+	return values
+}
+
+func collectPropertyValues(path string, value reflect.Value, values map[string]string) {
+	valueType := value.Type()
+	if valueType.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value = reflect.Zero(valueType.Elem())
+		} else {
+			value = value.Elem()
+		}
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() != reflect.Struct || valueType.PkgPath() != reflect.TypeOf(controller.OperatorConfiguration{}).PkgPath() {
+		if value.IsZero() {
+			values[path] = ""
+			return
+		}
+		serialized, err := json.Marshal(value.Interface())
+		if err != nil {
+			values[path] = ""
+			return
+		}
+		values[path] = string(serialized)
+		return
+	}
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		collectPropertyValues(fieldPath, value.Field(i), values)
+	}
+}
+
+// getEffectiveConfigMapData serializes effective configuration properties as ConfigMap data, with one key per property.
+func getEffectiveConfigMapData(properties map[string]EffectiveProperty) (map[string]string, error) {
+	data := map[string]string{}
+	for path, property := range properties {
+		serialized, err := json.Marshal(property)
+		if err != nil {
+			return nil, err
+		}
+		data[path] = string(serialized)
+	}
+	return data, nil
+}
+
+// EffectiveConfigWriter periodically writes the effective configuration of the global DevWorkspaceOperatorConfig to
+// the EffectiveConfigMapName ConfigMap in the operator's namespace, and the effective configuration of every other
+// DevWorkspaceOperatorConfig to a ConfigMap named after it in its namespace, for debugging configuration precedence.
+// It is intended to be added to the controller manager.
+type EffectiveConfigWriter struct {
+	// Client is used to read DevWorkspaceOperatorConfigs and manage ConfigMaps. It should not be a caching client, as
+	// the ConfigMaps do not match the label selector used for the controller's cache.
+	Client crclient.Client
+	Scheme *runtime.Scheme
+	Log    logr.Logger
+}
+
+// NeedLeaderElection ensures the ConfigMaps are only managed by the manager that holds the leader lease.
+func (w *EffectiveConfigWriter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start writes the effective configuration periodically until ctx is cancelled.
+func (w *EffectiveConfigWriter) Start(ctx context.Context) error {
+	for {
+		if err := w.sync(ctx); err != nil {
+			w.Log.Error(err, "Failed to write effective operator configuration")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(effectiveConfigInterval):
+		}
+	}
+}
+
+func (w *EffectiveConfigWriter) sync(ctx context.Context) error {
+	if err := w.syncConfigMap(ctx, EffectiveConfigMapName, configNamespace, GetEffectiveConfig(nil), nil); err != nil {
+		return err
+	}
+	externalConfigs, err := w.listExternalConfigs(ctx)
+	if err != nil {
+		return err
+	}
+	for idx := range externalConfigs {
+		external := &externalConfigs[idx]
+		if external.Config == nil {
+			continue
+		}
+		name := external.Name + effectiveConfigMapSuffix
+		if err := w.syncConfigMap(ctx, name, external.Namespace, GetEffectiveConfig(external.Config), external); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listExternalConfigs returns all DevWorkspaceOperatorConfigs other than the global config. In namespace-scoped mode,
+// only the watched namespaces are searched.
+func (w *EffectiveConfigWriter) listExternalConfigs(ctx context.Context) ([]controller.DevWorkspaceOperatorConfig, error) {
+	var namespaces []string
+	if infrastructure.IsNamespaceScoped() {
+		namespaces = append(infrastructure.GetWatchedNamespaces(), configNamespace)
+	} else {
+		namespaces = []string{""}
+	}
+	var externalConfigs []controller.DevWorkspaceOperatorConfig
+	seen := map[types.NamespacedName]bool{}
+	for _, namespace := range namespaces {
+		configList := &controller.DevWorkspaceOperatorConfigList{}
+		if err := w.Client.List(ctx, configList, crclient.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for _, config := range configList.Items {
+			namespacedName := types.NamespacedName{Name: config.Name, Namespace: config.Namespace}
+			if seen[namespacedName] || (config.Name == OperatorConfigName && config.Namespace == configNamespace) {
+				continue
+			}
+			seen[namespacedName] = true
+			externalConfigs = append(externalConfigs, config)
+		}
+	}
+	return externalConfigs, nil
+}
+
+// syncConfigMap creates or updates a ConfigMap holding effective configuration properties. If owner is not nil, the
+// ConfigMap is owned by it so that it is deleted along with the owner.
+func (w *EffectiveConfigWriter) syncConfigMap(ctx context.Context, name, namespace string, properties map[string]EffectiveProperty, owner *controller.DevWorkspaceOperatorConfig) error {
+	data, err := getEffectiveConfigMapData(properties)
+	if err != nil {
+		return err
+	}
+	specConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    effectiveConfigLabels,
+		},
+		Data: data,
+	}
+	if owner != nil {
+		if err := controllerutil.SetControllerReference(owner, specConfigMap, w.Scheme); err != nil {
+			return err
+		}
+	}
+
+	clusterConfigMap := &corev1.ConfigMap{}
+	err = w.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, clusterConfigMap)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		w.Log.Info("Creating effective configuration ConfigMap", "name", name, "namespace", namespace)
+		return w.Client.Create(ctx, specConfigMap)
+	case err != nil:
+		return err
+	}
+	if reflect.DeepEqual(clusterConfigMap.Data, specConfigMap.Data) && reflect.DeepEqual(clusterConfigMap.Labels, specConfigMap.Labels) {
+		return nil
+	}
+	clusterConfigMap.Labels = specConfigMap.Labels
+	clusterConfigMap.Data = specConfigMap.Data
+	return w.Client.Update(ctx, clusterConfigMap)
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+func TestGetEffectiveConfigReportsSources(t *testing.T) {
+	setupForTest(t)
+	internalConfig = defaultConfig.DeepCopy()
+	internalConfig.Workspace.ImagePullPolicy = "IfNotPresent"
+	external := &v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			IdleTimeout: "5h",
+		},
+	}
+
+	globalProperties := GetEffectiveConfig(nil)
+	assert.Equal(t, SourceGlobal, globalProperties["workspace.imagePullPolicy"].Source)
+	assert.JSONEq(t, `"IfNotPresent"`, string(globalProperties["workspace.imagePullPolicy"].Value))
+	assert.Equal(t, SourceDefault, globalProperties["workspace.idleTimeout"].Source)
+	assert.JSONEq(t, `"15m"`, string(globalProperties["workspace.idleTimeout"].Value))
+
+	externalProperties := GetEffectiveConfig(external)
+	assert.Equal(t, SourceExternal, externalProperties["workspace.idleTimeout"].Source)
+	assert.JSONEq(t, `"5h"`, string(externalProperties["workspace.idleTimeout"].Value))
+	assert.Equal(t, SourceGlobal, externalProperties["workspace.imagePullPolicy"].Source)
+}
+
+func TestGetEffectiveConfigIncludesUnsetProperties(t *testing.T) {
+	setupForTest(t)
+	internalConfig = defaultConfig.DeepCopy()
+
+	properties := GetEffectiveConfig(nil)
+	if assert.Contains(t, properties, "workspace.maintenance.message") {
+		assert.Equal(t, SourceDefault, properties["workspace.maintenance.message"].Source)
+		assert.Equal(t, "null", string(properties["workspace.maintenance.message"].Value))
+	}
+	assert.Contains(t, properties, "routing.clusterHostSuffix")
+	assert.NotContains(t, properties, "workspace", "Should only include leaf properties")
+}
+
+func TestEffectiveConfigWriterWritesConfigMaps(t *testing.T) {
+	setupForTest(t)
+	internalConfig = defaultConfig.DeepCopy()
+	external := buildExternalConfig(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			IdleTimeout: "5h",
+		},
+	})
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(buildConfig(nil), external).Build()
+	writer := &EffectiveConfigWriter{Client: client, Scheme: scheme, Log: zap.New()}
+
+	require.NoError(t, writer.sync(context.Background()))
+
+	globalConfigMap := &corev1.ConfigMap{}
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: EffectiveConfigMapName, Namespace: testNamespace}, globalConfigMap))
+	property := &EffectiveProperty{}
+	require.NoError(t, json.Unmarshal([]byte(globalConfigMap.Data["workspace.idleTimeout"]), property))
+	assert.Equal(t, SourceDefault, property.Source)
+
+	externalConfigMap := &corev1.ConfigMap{}
+	externalNN := types.NamespacedName{Name: externalConfigName + effectiveConfigMapSuffix, Namespace: externalConfigNamespace}
+	require.NoError(t, client.Get(context.Background(), externalNN, externalConfigMap))
+	require.NoError(t, json.Unmarshal([]byte(externalConfigMap.Data["workspace.idleTimeout"]), property))
+	assert.Equal(t, SourceExternal, property.Source)
+	assert.JSONEq(t, `"5h"`, string(property.Value))
+	if assert.Len(t, externalConfigMap.OwnerReferences, 1) {
+		assert.Equal(t, externalConfigName, externalConfigMap.OwnerReferences[0].Name)
+	}
+
+	internalConfig.Workspace.IdleTimeout = "1h"
+	require.NoError(t, writer.sync(context.Background()))
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: EffectiveConfigMapName, Namespace: testNamespace}, globalConfigMap))
+	require.NoError(t, json.Unmarshal([]byte(globalConfigMap.Data["workspace.idleTimeout"]), property))
+	assert.Equal(t, SourceGlobal, property.Source, "Should update ConfigMap when config changes")
+}
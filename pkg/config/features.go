@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// Feature is the name of a controller feature that can be enabled or disabled via the featureGates field in the
+// DevWorkspaceOperatorConfig.
+type Feature string
+
+const (
+	// SSHAgentPostStart enables starting an ssh-agent in workspaces when the SSH key mounted in the workspace
+	// is protected by a passphrase.
+	SSHAgentPostStart Feature = "sshAgentPostStart"
+	// DebugLogging enables verbose logging in the controller, including diffs between spec and cluster objects
+	// when objects are updated.
+	DebugLogging Feature = "debugLogging"
+	// RestrictedSecurityContext defaults workspace containers to a security context that satisfies the restricted
+	// Pod Security Standard. Fields set in the configured containerSecurityContext take precedence.
+	RestrictedSecurityContext Feature = "restrictedSecurityContext"
+)
+
+// knownFeatures contains all features supported by the controller, mapped to whether they are enabled by default.
+var knownFeatures = map[Feature]bool{
+	SSHAgentPostStart:         false,
+	DebugLogging:              false,
+	RestrictedSecurityContext: false,
+}
+
+// restrictedContainerSecurityContext is the container security context required by the restricted Pod Security
+// Standard: https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted
+var restrictedContainerSecurityContext = &corev1.SecurityContext{
+	AllowPrivilegeEscalation: pointer.Bool(false),
+	RunAsNonRoot:             pointer.Bool(true),
+	Capabilities: &corev1.Capabilities{
+		Drop: []corev1.Capability{"ALL"},
+	},
+	SeccompProfile: &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	},
+}
+
+var featureEnabledGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "devworkspace",
+		Name:      "feature_enabled",
+		Help:      "Whether a controller feature gate is enabled (1) or disabled (0)",
+	},
+	[]string{"feature"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(featureEnabledGauge)
+}
+
+// IsFeatureEnabled returns whether a feature is enabled in the provided configuration. A feature is enabled if it is
+// explicitly enabled in the featureGates field. Features not listed in featureGates use their default value, or are
+// enabled if the deprecated enableExperimentalFeatures field is true.
+func IsFeatureEnabled(config *controller.OperatorConfiguration, feature Feature) bool {
+	defaultValue, ok := knownFeatures[feature]
+	if !ok || config == nil {
+		return false
+	}
+	gates, _ := parseFeatureGates(config.FeatureGates)
+	if enabled, ok := gates[feature]; ok {
+		return enabled
+	}
+	if config.EnableExperimentalFeatures != nil && *config.EnableExperimentalFeatures {
+		return true
+	}
+	return defaultValue
+}
+
+// IsFeatureEnabledForWorkspace returns whether a feature is enabled for a DevWorkspace. For canary DevWorkspaces (see
+// IsCanaryWorkspace), features configured in the canaryFeatureGates field take precedence over the featureGates field.
+// Otherwise, this is equivalent to IsFeatureEnabled for the DevWorkspace's config.
+func IsFeatureEnabledForWorkspace(workspace *common.DevWorkspaceWithConfig, feature Feature) bool {
+	if workspace.Config != nil && IsCanaryWorkspace(workspace) {
+		if _, ok := knownFeatures[feature]; !ok {
+			return false
+		}
+		gates, _ := parseFeatureGates(workspace.Config.CanaryFeatureGates)
+		if enabled, ok := gates[feature]; ok {
+			return enabled
+		}
+	}
+	return IsFeatureEnabled(workspace.Config, feature)
+}
+
+// IsCanaryWorkspace returns whether a DevWorkspace is labelled as a canary, and so uses the canaryFeatureGates field in
+// the DevWorkspaceOperatorConfig.
+func IsCanaryWorkspace(workspace *common.DevWorkspaceWithConfig) bool {
+	return workspace.Labels[constants.DevWorkspaceCanaryLabel] == "true"
+}
+
+// GetRestrictedContainerSecurityContext returns the provided container security context, with any unset fields set
+// to the values required by the restricted Pod Security Standard.
+func GetRestrictedContainerSecurityContext(securityContext *corev1.SecurityContext) *corev1.SecurityContext {
+	if securityContext == nil {
+		return restrictedContainerSecurityContext.DeepCopy()
+	}
+	return mergeContainerSecurityContext(restrictedContainerSecurityContext.DeepCopy(), securityContext)
+}
+
+// FeatureEnabled returns whether a feature is enabled in the global DevWorkspaceOperatorConfig.
+func FeatureEnabled(feature Feature) bool {
+	return IsFeatureEnabled(internalConfig, feature)
+}
+
+// GetFeatureGates returns the enabled state of all features supported by the controller for the provided
+// configuration.
+func GetFeatureGates(config *controller.OperatorConfiguration) map[Feature]bool {
+	features := map[Feature]bool{}
+	for feature := range knownFeatures {
+		features[feature] = IsFeatureEnabled(config, feature)
+	}
+	return features
+}
+
+// parseFeatureGates parses a comma-separated list of <feature>=<true|false> pairs. Entries that are malformed or that
+// refer to features not supported by the controller are ignored, and returned as an error.
+func parseFeatureGates(featureGates string) (map[Feature]bool, error) {
+	gates := map[Feature]bool{}
+	var invalid []string
+	for _, entry := range strings.Split(featureGates, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			invalid = append(invalid, entry)
+			continue
+		}
+		feature := Feature(strings.TrimSpace(name))
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if _, known := knownFeatures[feature]; !known || err != nil {
+			invalid = append(invalid, entry)
+			continue
+		}
+		gates[feature] = enabled
+	}
+	if len(invalid) > 0 {
+		return gates, fmt.Errorf("ignoring invalid or unknown feature gates: %s", strings.Join(invalid, ", "))
+	}
+	return gates, nil
+}
+
+// logFeatureGates logs the state of all features in the global config and updates the feature gate metric.
+func logFeatureGates() {
+	if _, err := parseFeatureGates(internalConfig.FeatureGates); err != nil {
+		log.Error(err, "Invalid featureGates in DevWorkspaceOperatorConfig")
+	}
+	if _, err := parseFeatureGates(internalConfig.CanaryFeatureGates); err != nil {
+		log.Error(err, "Invalid canaryFeatureGates in DevWorkspaceOperatorConfig")
+	}
+	features := GetFeatureGates(internalConfig)
+	var names []string
+	for feature, enabled := range features {
+		names = append(names, fmt.Sprintf("%s=%t", feature, enabled))
+		if enabled {
+			featureEnabledGauge.WithLabelValues(string(feature)).Set(1)
+		} else {
+			featureEnabledGauge.WithLabelValues(string(feature)).Set(0)
+		}
+	}
+	sort.Strings(names)
+	log.Info(fmt.Sprintf("Feature gates: [%s]", strings.Join(names, ",")))
+	if internalConfig.CanaryFeatureGates != "" {
+		log.Info(fmt.Sprintf("Canary feature gates: [%s]", internalConfig.CanaryFeatureGates))
+	}
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package config
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestIsFeatureEnabled(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          *v1alpha1.OperatorConfiguration
+		feature         Feature
+		expectedEnabled bool
+	}{
+		{
+			name:            "Uses default when feature gates are not set",
+			config:          &v1alpha1.OperatorConfiguration{},
+			feature:         SSHAgentPostStart,
+			expectedEnabled: false,
+		},
+		{
+			name:            "Enables feature from feature gates",
+			config:          &v1alpha1.OperatorConfiguration{FeatureGates: "debugLogging=false, sshAgentPostStart=true"},
+			feature:         SSHAgentPostStart,
+			expectedEnabled: true,
+		},
+		{
+			name:            "Disables feature from feature gates",
+			config:          &v1alpha1.OperatorConfiguration{FeatureGates: "sshAgentPostStart=true,debugLogging=false"},
+			feature:         DebugLogging,
+			expectedEnabled: false,
+		},
+		{
+			name:            "Enables features when experimental features are enabled",
+			config:          &v1alpha1.OperatorConfiguration{EnableExperimentalFeatures: pointer.Bool(true)},
+			feature:         DebugLogging,
+			expectedEnabled: true,
+		},
+		{
+			name: "Feature gates take precedence over experimental features",
+			config: &v1alpha1.OperatorConfiguration{
+				EnableExperimentalFeatures: pointer.Bool(true),
+				FeatureGates:               "debugLogging=false",
+			},
+			feature:         DebugLogging,
+			expectedEnabled: false,
+		},
+		{
+			name:            "Ignores invalid feature gates",
+			config:          &v1alpha1.OperatorConfiguration{FeatureGates: "sshAgentPostStart=yes,debugLogging"},
+			feature:         SSHAgentPostStart,
+			expectedEnabled: false,
+		},
+		{
+			name:            "Unknown features are disabled",
+			config:          &v1alpha1.OperatorConfiguration{EnableExperimentalFeatures: pointer.Bool(true), FeatureGates: "warmPool=true"},
+			feature:         Feature("warmPool"),
+			expectedEnabled: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedEnabled, IsFeatureEnabled(tt.config, tt.feature))
+		})
+	}
+}
+
+func TestParseFeatureGatesReportsInvalidEntries(t *testing.T) {
+	gates, err := parseFeatureGates("sshAgentPostStart=true,warmPool=true,debugLogging")
+	if assert.Error(t, err, "Should return error for invalid feature gates") {
+		assert.Regexp(t, "ignoring invalid or unknown feature gates: warmPool=true, debugLogging", err.Error())
+	}
+	assert.Equal(t, map[Feature]bool{SSHAgentPostStart: true}, gates, "Should parse valid feature gates")
+}
+
+func TestIsFeatureEnabledForWorkspace(t *testing.T) {
+	config := &v1alpha1.OperatorConfiguration{
+		FeatureGates:       "sshAgentPostStart=true",
+		CanaryFeatureGates: "restrictedSecurityContext=true,sshAgentPostStart=false",
+	}
+	canary := &common.DevWorkspaceWithConfig{
+		DevWorkspace: &dw.DevWorkspace{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{constants.DevWorkspaceCanaryLabel: "true"}},
+		},
+		Config: config,
+	}
+	regular := &common.DevWorkspaceWithConfig{
+		DevWorkspace: &dw.DevWorkspace{},
+		Config:       config,
+	}
+
+	assert.True(t, IsFeatureEnabledForWorkspace(canary, RestrictedSecurityContext), "Should enable canary features for canary workspaces")
+	assert.False(t, IsFeatureEnabledForWorkspace(canary, SSHAgentPostStart), "Canary feature gates should take precedence for canary workspaces")
+	assert.False(t, IsFeatureEnabledForWorkspace(canary, DebugLogging), "Should use default for features not in canary feature gates")
+	assert.False(t, IsFeatureEnabledForWorkspace(regular, RestrictedSecurityContext), "Should not enable canary features for regular workspaces")
+	assert.True(t, IsFeatureEnabledForWorkspace(regular, SSHAgentPostStart), "Should use feature gates for regular workspaces")
+}
+
+func TestGetRestrictedContainerSecurityContext(t *testing.T) {
+	configured := &corev1.SecurityContext{
+		RunAsNonRoot: pointer.Bool(false),
+		Capabilities: &corev1.Capabilities{
+			Add: []corev1.Capability{"SETGID"},
+		},
+	}
+	expected := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		RunAsNonRoot:             pointer.Bool(false),
+		Capabilities: &corev1.Capabilities{
+			Add:  []corev1.Capability{"SETGID"},
+			Drop: []corev1.Capability{"ALL"},
+		},
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+	assert.Equal(t, expected, GetRestrictedContainerSecurityContext(configured), "Configured fields should take precedence")
+	assert.Equal(t, restrictedContainerSecurityContext, GetRestrictedContainerSecurityContext(nil))
+}
@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/devworkspace-operator/pkg/config/proxy"
@@ -36,6 +37,7 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
 )
@@ -61,7 +63,24 @@ func GetGlobalConfig() *controller.OperatorConfiguration {
 // If the `controller.devfile.io/devworkspace-config` is not set, the global DevWorkspaceOperatorConfig is returned.
 // If the `controller.devfile.io/devworkspace-config` attribute is incorrectly set, or the specified DevWorkspaceOperatorConfig
 // does not exist on the cluster, an error is returned.
+//
+// If the DevWorkspace has the DevWorkspaceIdleTimeoutAnnotation, its value overrides the idle timeout from the config.
+// An error is returned if the annotation is not a valid duration.
 func ResolveConfigForWorkspace(workspace *dw.DevWorkspace, client crclient.Client) (*controller.OperatorConfiguration, error) {
+	config, err := resolveExternalConfigForWorkspace(workspace, client)
+	if err != nil {
+		return nil, err
+	}
+	if idleTimeout, ok := workspace.Annotations[constants.DevWorkspaceIdleTimeoutAnnotation]; ok {
+		if _, err := time.ParseDuration(idleTimeout); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation %q: must be a duration, e.g. 30m", constants.DevWorkspaceIdleTimeoutAnnotation, idleTimeout)
+		}
+		config.Workspace.IdleTimeout = idleTimeout
+	}
+	return config, nil
+}
+
+func resolveExternalConfigForWorkspace(workspace *dw.DevWorkspace, client crclient.Client) (*controller.OperatorConfiguration, error) {
 	if !workspace.Spec.Template.Attributes.Exists(constants.ExternalDevWorkspaceConfiguration) {
 		return GetGlobalConfig(), nil
 	}
@@ -89,11 +108,17 @@ func ResolveConfigForWorkspace(workspace *dw.DevWorkspace, client crclient.Clien
 }
 
 func GetConfigForTesting(customConfig *controller.OperatorConfiguration) *controller.OperatorConfiguration {
+	return MergeWithDefaultConfig(customConfig)
+}
+
+// MergeWithDefaultConfig returns the result of merging customConfig into the operator's default configuration. It can
+// be used to determine the effective configuration where the global config is not synced, e.g. in the webhook server.
+func MergeWithDefaultConfig(customConfig *controller.OperatorConfiguration) *controller.OperatorConfiguration {
 	configMutex.Lock()
 	defer configMutex.Unlock()
-	testConfig := defaultConfig.DeepCopy()
-	mergeConfig(customConfig, testConfig)
-	return testConfig
+	mergedConfig := defaultConfig.DeepCopy()
+	mergeConfig(customConfig, mergedConfig)
+	return mergedConfig
 }
 
 func SetGlobalConfigForTesting(testConfig *controller.OperatorConfiguration) {
@@ -101,8 +126,10 @@ func SetGlobalConfigForTesting(testConfig *controller.OperatorConfiguration) {
 	defer configMutex.Unlock()
 	setDefaultPodSecurityContext()
 	setDefaultContainerSecurityContext()
+	setDefaultSecurityContextPolicy()
 	internalConfig = defaultConfig.DeepCopy()
 	mergeConfig(testConfig, internalConfig)
+	syncNamingTemplates()
 }
 
 func SetupControllerConfig(client crclient.Client) error {
@@ -115,6 +142,9 @@ func SetupControllerConfig(client crclient.Client) error {
 	if err := setDefaultContainerSecurityContext(); err != nil {
 		return err
 	}
+	if err := setDefaultSecurityContextPolicy(); err != nil {
+		return err
+	}
 
 	internalConfig = &controller.OperatorConfiguration{}
 
@@ -130,6 +160,7 @@ func SetupControllerConfig(client crclient.Client) error {
 	}
 	if config == nil {
 		internalConfig = defaultConfig.DeepCopy()
+		syncNamingTemplates()
 	} else {
 		syncConfigFrom(config)
 	}
@@ -158,13 +189,6 @@ func IsSetUp() bool {
 	return internalConfig != nil
 }
 
-func ExperimentalFeaturesEnabled() bool {
-	if internalConfig == nil || internalConfig.EnableExperimentalFeatures == nil {
-		return false
-	}
-	return *internalConfig.EnableExperimentalFeatures
-}
-
 func getClusterConfig(namespace string, client crclient.Client) (*controller.DevWorkspaceOperatorConfig, error) {
 	clusterConfig := &controller.DevWorkspaceOperatorConfig{}
 	if err := client.Get(context.Background(), types.NamespacedName{Name: OperatorConfigName, Namespace: namespace}, clusterConfig); err != nil {
@@ -191,6 +215,7 @@ func syncConfigFrom(newConfig *controller.DevWorkspaceOperatorConfig) {
 	defer configMutex.Unlock()
 	internalConfig = defaultConfig.DeepCopy()
 	mergeConfig(newConfig.Config, internalConfig)
+	syncNamingTemplates()
 	logCurrentConfig()
 }
 
@@ -198,9 +223,21 @@ func restoreDefaultConfig() {
 	configMutex.Lock()
 	defer configMutex.Unlock()
 	internalConfig = defaultConfig.DeepCopy()
+	syncNamingTemplates()
 	logCurrentConfig()
 }
 
+// syncNamingTemplates applies the naming templates from the global config to the names of objects generated for
+// DevWorkspaces. If the naming templates are invalid, the default naming templates are used instead.
+func syncNamingTemplates() {
+	if err := common.ValidateNamingTemplates(internalConfig.Workspace.NamingTemplates); err != nil {
+		log.Error(err, "Ignoring invalid namingTemplates in DevWorkspaceOperatorConfig")
+		common.SetNamingTemplates(nil)
+		return
+	}
+	common.SetNamingTemplates(internalConfig.Workspace.NamingTemplates)
+}
+
 // discoverRouteSuffix attempts to determine a clusterHostSuffix that is compatible with the current cluster.
 // On OpenShift, this is done by creating a temporary route and reading the auto-filled .spec.host. On Kubernetes,
 // there's no way to determine this value automatically so ("", nil) is returned.
@@ -253,6 +290,12 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 	if from.EnableExperimentalFeatures != nil {
 		to.EnableExperimentalFeatures = from.EnableExperimentalFeatures
 	}
+	if from.FeatureGates != "" {
+		to.FeatureGates = from.FeatureGates
+	}
+	if from.CanaryFeatureGates != "" {
+		to.CanaryFeatureGates = from.CanaryFeatureGates
+	}
 	if from.Webhook != nil {
 		if to.Webhook == nil {
 			to.Webhook = &controller.WebhookConfig{}
@@ -267,6 +310,114 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 			to.Webhook.Replicas = from.Webhook.Replicas
 		}
 	}
+	if from.Metrics != nil {
+		if to.Metrics == nil {
+			to.Metrics = &controller.MetricsConfig{}
+		}
+		if from.Metrics.EnableServiceMonitor != nil {
+			to.Metrics.EnableServiceMonitor = from.Metrics.EnableServiceMonitor
+		}
+		if from.Metrics.ScrapeInterval != "" {
+			to.Metrics.ScrapeInterval = from.Metrics.ScrapeInterval
+		}
+	}
+	if from.EventSink != nil {
+		if to.EventSink == nil {
+			to.EventSink = &controller.EventSinkConfig{}
+		}
+		if from.EventSink.URL != "" {
+			to.EventSink.URL = from.EventSink.URL
+		}
+		if from.EventSink.SecretName != "" {
+			to.EventSink.SecretName = from.EventSink.SecretName
+		}
+		if from.EventSink.Timeout != "" {
+			to.EventSink.Timeout = from.EventSink.Timeout
+		}
+		if from.EventSink.Format != "" {
+			to.EventSink.Format = from.EventSink.Format
+		}
+		if from.EventSink.PhaseTransitions != nil {
+			to.EventSink.PhaseTransitions = from.EventSink.PhaseTransitions
+		}
+	}
+	if from.Audit != nil {
+		if to.Audit == nil {
+			to.Audit = &controller.AuditConfig{}
+		}
+		if from.Audit.Enable != nil {
+			to.Audit.Enable = from.Audit.Enable
+		}
+		if from.Audit.ConfigMapEntries != 0 {
+			to.Audit.ConfigMapEntries = from.Audit.ConfigMapEntries
+		}
+		if from.Audit.Sink != nil {
+			if to.Audit.Sink == nil {
+				to.Audit.Sink = &controller.EventSinkConfig{}
+			}
+			if from.Audit.Sink.URL != "" {
+				to.Audit.Sink.URL = from.Audit.Sink.URL
+			}
+			if from.Audit.Sink.SecretName != "" {
+				to.Audit.Sink.SecretName = from.Audit.Sink.SecretName
+			}
+			if from.Audit.Sink.Timeout != "" {
+				to.Audit.Sink.Timeout = from.Audit.Sink.Timeout
+			}
+			if from.Audit.Sink.Format != "" {
+				to.Audit.Sink.Format = from.Audit.Sink.Format
+			}
+			if from.Audit.Sink.PhaseTransitions != nil {
+				to.Audit.Sink.PhaseTransitions = from.Audit.Sink.PhaseTransitions
+			}
+		}
+	}
+	if from.LogStreaming != nil {
+		if to.LogStreaming == nil {
+			to.LogStreaming = &controller.LogStreamingConfig{}
+		}
+		if from.LogStreaming.Enable != nil {
+			to.LogStreaming.Enable = from.LogStreaming.Enable
+		}
+	}
+	if from.CommandExec != nil {
+		if to.CommandExec == nil {
+			to.CommandExec = &controller.CommandExecConfig{}
+		}
+		if from.CommandExec.Enable != nil {
+			to.CommandExec.Enable = from.CommandExec.Enable
+		}
+	}
+	if from.GitWebhook != nil {
+		if to.GitWebhook == nil {
+			to.GitWebhook = &controller.GitWebhookConfig{}
+		}
+		if from.GitWebhook.Enable != nil {
+			to.GitWebhook.Enable = from.GitWebhook.Enable
+		}
+	}
+	if from.Terminal != nil {
+		if to.Terminal == nil {
+			to.Terminal = &controller.TerminalConfig{}
+		}
+		if from.Terminal.Enable != nil {
+			to.Terminal.Enable = from.Terminal.Enable
+		}
+		if from.Terminal.Command != nil {
+			to.Terminal.Command = from.Terminal.Command
+		}
+		if from.Terminal.SessionRecording != nil {
+			if to.Terminal.SessionRecording == nil {
+				to.Terminal.SessionRecording = &controller.TerminalSessionRecordingConfig{}
+			}
+			if from.Terminal.SessionRecording.URL != "" {
+				to.Terminal.SessionRecording.URL = from.Terminal.SessionRecording.URL
+			}
+			if from.Terminal.SessionRecording.Timeout != "" {
+				to.Terminal.SessionRecording.Timeout = from.Terminal.SessionRecording.Timeout
+			}
+		}
+	}
 	if from.Routing != nil {
 		if to.Routing == nil {
 			to.Routing = &controller.RoutingConfig{}
@@ -297,12 +448,116 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 		if from.Workspace.StorageClassName != nil {
 			to.Workspace.StorageClassName = from.Workspace.StorageClassName
 		}
+		if from.Workspace.StorageAccessModes != nil {
+			if to.Workspace.StorageAccessModes == nil {
+				to.Workspace.StorageAccessModes = make(map[string][]corev1.PersistentVolumeAccessMode)
+			}
+			for storageClass, accessModes := range from.Workspace.StorageAccessModes {
+				to.Workspace.StorageAccessModes[storageClass] = accessModes
+			}
+		}
 		if from.Workspace.RuntimeClassName != nil {
 			to.Workspace.RuntimeClassName = from.Workspace.RuntimeClassName
 		}
+		if from.Workspace.PriorityClassName != "" {
+			to.Workspace.PriorityClassName = from.Workspace.PriorityClassName
+		}
+		if from.Workspace.PreemptionPolicy != nil {
+			to.Workspace.PreemptionPolicy = from.Workspace.PreemptionPolicy
+		}
+		if from.Workspace.TerminationGracePeriodSeconds != nil {
+			to.Workspace.TerminationGracePeriodSeconds = from.Workspace.TerminationGracePeriodSeconds
+		}
+		if from.Workspace.SharedMemorySize != nil {
+			sharedMemorySize := from.Workspace.SharedMemorySize.DeepCopy()
+			to.Workspace.SharedMemorySize = &sharedMemorySize
+		}
+		if from.Workspace.Sysctls != nil {
+			to.Workspace.Sysctls = from.Workspace.Sysctls
+		}
+		if from.Workspace.NodeSelector != nil {
+			to.Workspace.NodeSelector = from.Workspace.NodeSelector
+		}
+		if from.Workspace.Tolerations != nil {
+			to.Workspace.Tolerations = from.Workspace.Tolerations
+		}
+		if from.Workspace.Affinity != nil {
+			to.Workspace.Affinity = from.Workspace.Affinity.DeepCopy()
+		}
+		if from.Workspace.GPUProfile != nil {
+			if to.Workspace.GPUProfile == nil {
+				to.Workspace.GPUProfile = &controller.GPUProfileConfig{}
+			}
+			if from.Workspace.GPUProfile.ResourceNames != nil {
+				to.Workspace.GPUProfile.ResourceNames = from.Workspace.GPUProfile.ResourceNames
+			}
+			if from.Workspace.GPUProfile.NodeSelector != nil {
+				to.Workspace.GPUProfile.NodeSelector = from.Workspace.GPUProfile.NodeSelector
+			}
+			if from.Workspace.GPUProfile.Tolerations != nil {
+				to.Workspace.GPUProfile.Tolerations = from.Workspace.GPUProfile.Tolerations
+			}
+		}
+		if from.Workspace.ImageBuild != nil {
+			if to.Workspace.ImageBuild == nil {
+				to.Workspace.ImageBuild = &controller.ImageBuildConfig{}
+			}
+			if from.Workspace.ImageBuild.Registry != "" {
+				to.Workspace.ImageBuild.Registry = from.Workspace.ImageBuild.Registry
+			}
+			if from.Workspace.ImageBuild.PushSecretName != "" {
+				to.Workspace.ImageBuild.PushSecretName = from.Workspace.ImageBuild.PushSecretName
+			}
+			if from.Workspace.ImageBuild.BuilderImage != "" {
+				to.Workspace.ImageBuild.BuilderImage = from.Workspace.ImageBuild.BuilderImage
+			}
+			if from.Workspace.ImageBuild.Timeout != "" {
+				to.Workspace.ImageBuild.Timeout = from.Workspace.ImageBuild.Timeout
+			}
+		}
+		if from.Workspace.ImageScanning != nil {
+			if to.Workspace.ImageScanning == nil {
+				to.Workspace.ImageScanning = &controller.ImageScanningConfig{}
+			}
+			if from.Workspace.ImageScanning.Scanner != "" {
+				to.Workspace.ImageScanning.Scanner = from.Workspace.ImageScanning.Scanner
+			}
+			if from.Workspace.ImageScanning.URL != "" {
+				to.Workspace.ImageScanning.URL = from.Workspace.ImageScanning.URL
+			}
+			if from.Workspace.ImageScanning.TokenSecretName != "" {
+				to.Workspace.ImageScanning.TokenSecretName = from.Workspace.ImageScanning.TokenSecretName
+			}
+			if from.Workspace.ImageScanning.SeverityThreshold != "" {
+				to.Workspace.ImageScanning.SeverityThreshold = from.Workspace.ImageScanning.SeverityThreshold
+			}
+			if from.Workspace.ImageScanning.Policy != "" {
+				to.Workspace.ImageScanning.Policy = from.Workspace.ImageScanning.Policy
+			}
+		}
+		if from.Workspace.PinImageDigests != nil {
+			to.Workspace.PinImageDigests = from.Workspace.PinImageDigests
+		}
 		if from.Workspace.PVCName != "" {
 			to.Workspace.PVCName = from.Workspace.PVCName
 		}
+		if from.Workspace.NamingTemplates != nil {
+			if to.Workspace.NamingTemplates == nil {
+				to.Workspace.NamingTemplates = &controller.NamingTemplatesConfig{}
+			}
+			if from.Workspace.NamingTemplates.Deployment != "" {
+				to.Workspace.NamingTemplates.Deployment = from.Workspace.NamingTemplates.Deployment
+			}
+			if from.Workspace.NamingTemplates.Service != "" {
+				to.Workspace.NamingTemplates.Service = from.Workspace.NamingTemplates.Service
+			}
+			if from.Workspace.NamingTemplates.Routing != "" {
+				to.Workspace.NamingTemplates.Routing = from.Workspace.NamingTemplates.Routing
+			}
+			if from.Workspace.NamingTemplates.PVC != "" {
+				to.Workspace.NamingTemplates.PVC = from.Workspace.NamingTemplates.PVC
+			}
+		}
 		if from.Workspace.ServiceAccount != nil {
 			if to.Workspace.ServiceAccount == nil {
 				to.Workspace.ServiceAccount = &controller.ServiceAccountConfig{}
@@ -316,6 +571,17 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 			if from.Workspace.ServiceAccount.ServiceAccountTokens != nil {
 				to.Workspace.ServiceAccount.ServiceAccountTokens = from.Workspace.ServiceAccount.ServiceAccountTokens
 			}
+			if from.Workspace.ServiceAccount.ScopedRBAC != nil {
+				if to.Workspace.ServiceAccount.ScopedRBAC == nil {
+					to.Workspace.ServiceAccount.ScopedRBAC = &controller.ScopedRBACConfig{}
+				}
+				if from.Workspace.ServiceAccount.ScopedRBAC.Enable != nil {
+					to.Workspace.ServiceAccount.ScopedRBAC.Enable = pointer.Bool(*from.Workspace.ServiceAccount.ScopedRBAC.Enable)
+				}
+				if from.Workspace.ServiceAccount.ScopedRBAC.Rules != nil {
+					to.Workspace.ServiceAccount.ScopedRBAC.Rules = from.Workspace.ServiceAccount.ScopedRBAC.Rules
+				}
+			}
 		}
 		if from.Workspace.ImagePullPolicy != "" {
 			to.Workspace.ImagePullPolicy = from.Workspace.ImagePullPolicy
@@ -323,24 +589,343 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 		if from.Workspace.DeploymentStrategy != "" {
 			to.Workspace.DeploymentStrategy = from.Workspace.DeploymentStrategy
 		}
+		if from.Workspace.StatefulSetStorageTypes != nil {
+			to.Workspace.StatefulSetStorageTypes = from.Workspace.StatefulSetStorageTypes
+		}
 		if from.Workspace.IdleTimeout != "" {
 			to.Workspace.IdleTimeout = from.Workspace.IdleTimeout
 		}
+		if from.Workspace.IdleWarningPeriod != "" {
+			to.Workspace.IdleWarningPeriod = from.Workspace.IdleWarningPeriod
+		}
 		if from.Workspace.ProgressTimeout != "" {
 			to.Workspace.ProgressTimeout = from.Workspace.ProgressTimeout
 		}
+		if from.Workspace.PhaseTimeouts != nil {
+			if to.Workspace.PhaseTimeouts == nil {
+				to.Workspace.PhaseTimeouts = &controller.PhaseTimeoutsConfig{}
+			}
+			if from.Workspace.PhaseTimeouts.RoutingReady != "" {
+				to.Workspace.PhaseTimeouts.RoutingReady = from.Workspace.PhaseTimeouts.RoutingReady
+			}
+			if from.Workspace.PhaseTimeouts.StorageReady != "" {
+				to.Workspace.PhaseTimeouts.StorageReady = from.Workspace.PhaseTimeouts.StorageReady
+			}
+			if from.Workspace.PhaseTimeouts.DeploymentReady != "" {
+				to.Workspace.PhaseTimeouts.DeploymentReady = from.Workspace.PhaseTimeouts.DeploymentReady
+			}
+			if from.Workspace.PhaseTimeouts.ServersReady != "" {
+				to.Workspace.PhaseTimeouts.ServersReady = from.Workspace.PhaseTimeouts.ServersReady
+			}
+		}
 		if from.Workspace.IgnoredUnrecoverableEvents != nil {
 			to.Workspace.IgnoredUnrecoverableEvents = from.Workspace.IgnoredUnrecoverableEvents
 		}
+		if from.Workspace.DisabledDevfileFeatures != nil {
+			to.Workspace.DisabledDevfileFeatures = from.Workspace.DisabledDevfileFeatures
+		}
+		if from.Workspace.AccessControl != nil {
+			if to.Workspace.AccessControl == nil {
+				to.Workspace.AccessControl = &controller.AccessControlConfig{}
+			}
+			if from.Workspace.AccessControl.RestrictToCreator != nil {
+				to.Workspace.AccessControl.RestrictToCreator = from.Workspace.AccessControl.RestrictToCreator
+			}
+			if from.Workspace.AccessControl.AdminGroups != nil {
+				to.Workspace.AccessControl.AdminGroups = from.Workspace.AccessControl.AdminGroups
+			}
+		}
 		if from.Workspace.CleanupOnStop != nil {
 			to.Workspace.CleanupOnStop = from.Workspace.CleanupOnStop
 		}
+		if from.Workspace.GracefulStop != nil {
+			if to.Workspace.GracefulStop == nil {
+				to.Workspace.GracefulStop = &controller.GracefulStopConfig{}
+			}
+			if from.Workspace.GracefulStop.Enable != nil {
+				to.Workspace.GracefulStop.Enable = from.Workspace.GracefulStop.Enable
+			}
+			if from.Workspace.GracefulStop.Timeout != "" {
+				to.Workspace.GracefulStop.Timeout = from.Workspace.GracefulStop.Timeout
+			}
+		}
 		if from.Workspace.PodSecurityContext != nil {
 			to.Workspace.PodSecurityContext = mergePodSecurityContext(to.Workspace.PodSecurityContext, from.Workspace.PodSecurityContext)
 		}
 		if from.Workspace.ContainerSecurityContext != nil {
 			to.Workspace.ContainerSecurityContext = mergeContainerSecurityContext(to.Workspace.ContainerSecurityContext, from.Workspace.ContainerSecurityContext)
 		}
+		if from.Workspace.SecurityContextPolicy != nil {
+			if to.Workspace.SecurityContextPolicy == nil {
+				to.Workspace.SecurityContextPolicy = &controller.SecurityContextPolicyConfig{}
+			}
+			if from.Workspace.SecurityContextPolicy.Enable != nil {
+				to.Workspace.SecurityContextPolicy.Enable = from.Workspace.SecurityContextPolicy.Enable
+			}
+			if from.Workspace.SecurityContextPolicy.RunAsNonRoot != nil {
+				to.Workspace.SecurityContextPolicy.RunAsNonRoot = from.Workspace.SecurityContextPolicy.RunAsNonRoot
+			}
+			if from.Workspace.SecurityContextPolicy.AllowPrivilegeEscalation != nil {
+				to.Workspace.SecurityContextPolicy.AllowPrivilegeEscalation = from.Workspace.SecurityContextPolicy.AllowPrivilegeEscalation
+			}
+			if from.Workspace.SecurityContextPolicy.FSGroupStrategy != "" {
+				to.Workspace.SecurityContextPolicy.FSGroupStrategy = from.Workspace.SecurityContextPolicy.FSGroupStrategy
+			}
+			if from.Workspace.SecurityContextPolicy.SeccompProfile != nil {
+				to.Workspace.SecurityContextPolicy.SeccompProfile = from.Workspace.SecurityContextPolicy.SeccompProfile.DeepCopy()
+			}
+			if from.Workspace.SecurityContextPolicy.DropCapabilities != nil {
+				to.Workspace.SecurityContextPolicy.DropCapabilities = from.Workspace.SecurityContextPolicy.DropCapabilities
+			}
+			if from.Workspace.SecurityContextPolicy.LabelNamespaces != nil {
+				to.Workspace.SecurityContextPolicy.LabelNamespaces = from.Workspace.SecurityContextPolicy.LabelNamespaces
+			}
+		}
+		if from.Workspace.AutoResizeCommonPVC != nil {
+			to.Workspace.AutoResizeCommonPVC = from.Workspace.AutoResizeCommonPVC
+		}
+		if from.Workspace.CommonPVCGarbageCollection != nil {
+			if to.Workspace.CommonPVCGarbageCollection == nil {
+				to.Workspace.CommonPVCGarbageCollection = &controller.CommonPVCGarbageCollectionConfig{}
+			}
+			if from.Workspace.CommonPVCGarbageCollection.Enable != nil {
+				to.Workspace.CommonPVCGarbageCollection.Enable = from.Workspace.CommonPVCGarbageCollection.Enable
+			}
+			if from.Workspace.CommonPVCGarbageCollection.Interval != "" {
+				to.Workspace.CommonPVCGarbageCollection.Interval = from.Workspace.CommonPVCGarbageCollection.Interval
+			}
+		}
+		if from.Workspace.ProjectBackup != nil {
+			if to.Workspace.ProjectBackup == nil {
+				to.Workspace.ProjectBackup = &controller.ProjectBackupConfig{}
+			}
+			if from.Workspace.ProjectBackup.Enable != nil {
+				to.Workspace.ProjectBackup.Enable = from.Workspace.ProjectBackup.Enable
+			}
+			if from.Workspace.ProjectBackup.Interval != "" {
+				to.Workspace.ProjectBackup.Interval = from.Workspace.ProjectBackup.Interval
+			}
+			if from.Workspace.ProjectBackup.URL != "" {
+				to.Workspace.ProjectBackup.URL = from.Workspace.ProjectBackup.URL
+			}
+			if from.Workspace.ProjectBackup.Region != "" {
+				to.Workspace.ProjectBackup.Region = from.Workspace.ProjectBackup.Region
+			}
+			if from.Workspace.ProjectBackup.CredentialsSecretName != "" {
+				to.Workspace.ProjectBackup.CredentialsSecretName = from.Workspace.ProjectBackup.CredentialsSecretName
+			}
+		}
+		if from.Workspace.GuestWorkspaces != nil {
+			if to.Workspace.GuestWorkspaces == nil {
+				to.Workspace.GuestWorkspaces = &controller.GuestWorkspacesConfig{}
+			}
+			if from.Workspace.GuestWorkspaces.Enable != nil {
+				to.Workspace.GuestWorkspaces.Enable = from.Workspace.GuestWorkspaces.Enable
+			}
+			if from.Workspace.GuestWorkspaces.NamespacePrefix != "" {
+				to.Workspace.GuestWorkspaces.NamespacePrefix = from.Workspace.GuestWorkspaces.NamespacePrefix
+			}
+			if from.Workspace.GuestWorkspaces.TTL != "" {
+				to.Workspace.GuestWorkspaces.TTL = from.Workspace.GuestWorkspaces.TTL
+			}
+			if from.Workspace.GuestWorkspaces.Quota != nil {
+				to.Workspace.GuestWorkspaces.Quota = from.Workspace.GuestWorkspaces.Quota.DeepCopy()
+			}
+		}
+		if from.Workspace.NetworkIsolation != nil {
+			if to.Workspace.NetworkIsolation == nil {
+				to.Workspace.NetworkIsolation = &controller.NetworkIsolationConfig{}
+			}
+			if from.Workspace.NetworkIsolation.Enable != nil {
+				to.Workspace.NetworkIsolation.Enable = from.Workspace.NetworkIsolation.Enable
+			}
+			if from.Workspace.NetworkIsolation.IngressNamespaceSelector != nil {
+				to.Workspace.NetworkIsolation.IngressNamespaceSelector = from.Workspace.NetworkIsolation.IngressNamespaceSelector.DeepCopy()
+			}
+			if from.Workspace.NetworkIsolation.AllowedNamespaces != nil {
+				to.Workspace.NetworkIsolation.AllowedNamespaces = from.Workspace.NetworkIsolation.AllowedNamespaces
+			}
+			if from.Workspace.NetworkIsolation.AllowedCIDRs != nil {
+				to.Workspace.NetworkIsolation.AllowedCIDRs = from.Workspace.NetworkIsolation.AllowedCIDRs
+			}
+		}
+		if from.Workspace.UserNamespaces != nil {
+			if to.Workspace.UserNamespaces == nil {
+				to.Workspace.UserNamespaces = &controller.UserNamespacesConfig{}
+			}
+			if from.Workspace.UserNamespaces.Enable != nil {
+				to.Workspace.UserNamespaces.Enable = from.Workspace.UserNamespaces.Enable
+			}
+			if from.Workspace.UserNamespaces.NameTemplate != "" {
+				to.Workspace.UserNamespaces.NameTemplate = from.Workspace.UserNamespaces.NameTemplate
+			}
+			if from.Workspace.UserNamespaces.Labels != nil {
+				to.Workspace.UserNamespaces.Labels = from.Workspace.UserNamespaces.Labels
+			}
+			if from.Workspace.UserNamespaces.Annotations != nil {
+				to.Workspace.UserNamespaces.Annotations = from.Workspace.UserNamespaces.Annotations
+			}
+			if from.Workspace.UserNamespaces.ClusterRoleName != "" {
+				to.Workspace.UserNamespaces.ClusterRoleName = from.Workspace.UserNamespaces.ClusterRoleName
+			}
+			if from.Workspace.UserNamespaces.Quota != nil {
+				to.Workspace.UserNamespaces.Quota = from.Workspace.UserNamespaces.Quota.DeepCopy()
+			}
+			if from.Workspace.UserNamespaces.LimitRange != nil {
+				to.Workspace.UserNamespaces.LimitRange = from.Workspace.UserNamespaces.LimitRange
+			}
+			if from.Workspace.UserNamespaces.NetworkPolicy != nil {
+				to.Workspace.UserNamespaces.NetworkPolicy = from.Workspace.UserNamespaces.NetworkPolicy.DeepCopy()
+			}
+		}
+		if from.Workspace.StartRetry != nil {
+			if to.Workspace.StartRetry == nil {
+				to.Workspace.StartRetry = &controller.StartRetryConfig{}
+			}
+			if from.Workspace.StartRetry.MaxAttempts != nil {
+				to.Workspace.StartRetry.MaxAttempts = from.Workspace.StartRetry.MaxAttempts
+			}
+			if from.Workspace.StartRetry.InitialBackoff != "" {
+				to.Workspace.StartRetry.InitialBackoff = from.Workspace.StartRetry.InitialBackoff
+			}
+			if from.Workspace.StartRetry.MaxBackoff != "" {
+				to.Workspace.StartRetry.MaxBackoff = from.Workspace.StartRetry.MaxBackoff
+			}
+		}
+		if from.Workspace.Limits != nil {
+			if to.Workspace.Limits == nil {
+				to.Workspace.Limits = &controller.WorkspaceLimitsConfig{}
+			}
+			if from.Workspace.Limits.MaxResources != nil {
+				if to.Workspace.Limits.MaxResources == nil {
+					to.Workspace.Limits.MaxResources = corev1.ResourceList{}
+				}
+				for resourceName, quantity := range from.Workspace.Limits.MaxResources {
+					to.Workspace.Limits.MaxResources[resourceName] = quantity.DeepCopy()
+				}
+			}
+			if from.Workspace.Limits.MaxRunningPerUser != nil {
+				to.Workspace.Limits.MaxRunningPerUser = from.Workspace.Limits.MaxRunningPerUser
+			}
+			if from.Workspace.Limits.MaxRunningPerNamespace != nil {
+				to.Workspace.Limits.MaxRunningPerNamespace = from.Workspace.Limits.MaxRunningPerNamespace
+			}
+			if from.Workspace.Limits.ExceededPolicy != "" {
+				to.Workspace.Limits.ExceededPolicy = from.Workspace.Limits.ExceededPolicy
+			}
+		}
+		if from.Workspace.StartQueue != nil {
+			if to.Workspace.StartQueue == nil {
+				to.Workspace.StartQueue = &controller.StartQueueConfig{}
+			}
+			if from.Workspace.StartQueue.MaxConcurrentStarts != nil {
+				to.Workspace.StartQueue.MaxConcurrentStarts = from.Workspace.StartQueue.MaxConcurrentStarts
+			}
+			if from.Workspace.StartQueue.Ordering != "" {
+				to.Workspace.StartQueue.Ordering = from.Workspace.StartQueue.Ordering
+			}
+		}
+		if from.Workspace.RunningBudget != nil {
+			if to.Workspace.RunningBudget == nil {
+				to.Workspace.RunningBudget = &controller.RunningBudgetConfig{}
+			}
+			if from.Workspace.RunningBudget.Weekly != "" {
+				to.Workspace.RunningBudget.Weekly = from.Workspace.RunningBudget.Weekly
+			}
+		}
+		if from.Workspace.UsageAccounting != nil {
+			if to.Workspace.UsageAccounting == nil {
+				to.Workspace.UsageAccounting = &controller.UsageAccountingConfig{}
+			}
+			if from.Workspace.UsageAccounting.Enable != nil {
+				to.Workspace.UsageAccounting.Enable = from.Workspace.UsageAccounting.Enable
+			}
+		}
+		if from.Workspace.RunSchedule != nil {
+			if to.Workspace.RunSchedule == nil {
+				to.Workspace.RunSchedule = &controller.RunScheduleConfig{}
+			}
+			if from.Workspace.RunSchedule.TimeZone != "" {
+				to.Workspace.RunSchedule.TimeZone = from.Workspace.RunSchedule.TimeZone
+			}
+			if from.Workspace.RunSchedule.Stop != nil {
+				to.Workspace.RunSchedule.Stop = from.Workspace.RunSchedule.Stop
+			}
+			if from.Workspace.RunSchedule.Blackouts != nil {
+				to.Workspace.RunSchedule.Blackouts = from.Workspace.RunSchedule.Blackouts
+			}
+			if from.Workspace.RunSchedule.Selector != nil {
+				to.Workspace.RunSchedule.Selector = from.Workspace.RunSchedule.Selector.DeepCopy()
+			}
+		}
+		if from.Workspace.Maintenance != nil {
+			if to.Workspace.Maintenance == nil {
+				to.Workspace.Maintenance = &controller.MaintenanceConfig{}
+			}
+			if from.Workspace.Maintenance.Enable != nil {
+				to.Workspace.Maintenance.Enable = from.Workspace.Maintenance.Enable
+			}
+			if from.Workspace.Maintenance.Message != "" {
+				to.Workspace.Maintenance.Message = from.Workspace.Maintenance.Message
+			}
+			if from.Workspace.Maintenance.StopRunningWorkspaces != nil {
+				to.Workspace.Maintenance.StopRunningWorkspaces = from.Workspace.Maintenance.StopRunningWorkspaces
+			}
+			if from.Workspace.Maintenance.DrainWindow != "" {
+				to.Workspace.Maintenance.DrainWindow = from.Workspace.Maintenance.DrainWindow
+			}
+		}
+		if from.Workspace.GangScheduling != nil {
+			if to.Workspace.GangScheduling == nil {
+				to.Workspace.GangScheduling = &controller.GangSchedulingConfig{}
+			}
+			if from.Workspace.GangScheduling.Enable != nil {
+				to.Workspace.GangScheduling.Enable = from.Workspace.GangScheduling.Enable
+			}
+			if from.Workspace.GangScheduling.ScheduleTimeoutSeconds != nil {
+				to.Workspace.GangScheduling.ScheduleTimeoutSeconds = from.Workspace.GangScheduling.ScheduleTimeoutSeconds
+			}
+		}
+		if from.Workspace.NodeFailureRecovery != nil {
+			if to.Workspace.NodeFailureRecovery == nil {
+				to.Workspace.NodeFailureRecovery = &controller.NodeFailureRecoveryConfig{}
+			}
+			if from.Workspace.NodeFailureRecovery.Policy != "" {
+				to.Workspace.NodeFailureRecovery.Policy = from.Workspace.NodeFailureRecovery.Policy
+			}
+			if from.Workspace.NodeFailureRecovery.TerminationTimeout != "" {
+				to.Workspace.NodeFailureRecovery.TerminationTimeout = from.Workspace.NodeFailureRecovery.TerminationTimeout
+			}
+		}
+		if from.Workspace.PluginRegistry != nil {
+			if to.Workspace.PluginRegistry == nil {
+				to.Workspace.PluginRegistry = &controller.PluginRegistryConfig{}
+			}
+			if from.Workspace.PluginRegistry.DefaultRegistryURL != "" {
+				to.Workspace.PluginRegistry.DefaultRegistryURL = from.Workspace.PluginRegistry.DefaultRegistryURL
+			}
+			if from.Workspace.PluginRegistry.CacheTTL != "" {
+				to.Workspace.PluginRegistry.CacheTTL = from.Workspace.PluginRegistry.CacheTTL
+			}
+		}
+
+		if from.Workspace.Trash != nil {
+			if to.Workspace.Trash == nil {
+				to.Workspace.Trash = &controller.TrashConfig{}
+			}
+			if from.Workspace.Trash.Enable != nil {
+				to.Workspace.Trash.Enable = from.Workspace.Trash.Enable
+			}
+			if from.Workspace.Trash.RetentionPeriod != "" {
+				to.Workspace.Trash.RetentionPeriod = from.Workspace.Trash.RetentionPeriod
+			}
+		}
+		if from.Workspace.DefaultStorageType != "" {
+			to.Workspace.DefaultStorageType = from.Workspace.DefaultStorageType
+		}
+		if from.Workspace.AsyncStorageSyncInterval != "" {
+			to.Workspace.AsyncStorageSyncInterval = from.Workspace.AsyncStorageSyncInterval
+		}
 		if from.Workspace.DefaultStorageSize != nil {
 			if to.Workspace.DefaultStorageSize == nil {
 				to.Workspace.DefaultStorageSize = &controller.StorageSizes{}
@@ -364,6 +949,20 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 			if from.Workspace.PersistUserHome.DisableInitContainer != nil {
 				to.Workspace.PersistUserHome.DisableInitContainer = from.Workspace.PersistUserHome.DisableInitContainer
 			}
+			if from.Workspace.PersistUserHome.DedicatedPVC != nil {
+				to.Workspace.PersistUserHome.DedicatedPVC = from.Workspace.PersistUserHome.DedicatedPVC
+			}
+		}
+		if from.Workspace.Dotfiles != nil {
+			if to.Workspace.Dotfiles == nil {
+				to.Workspace.Dotfiles = &controller.DotfilesConfig{}
+			}
+			if from.Workspace.Dotfiles.Enable != nil {
+				to.Workspace.Dotfiles.Enable = from.Workspace.Dotfiles.Enable
+			}
+			if from.Workspace.Dotfiles.Image != "" {
+				to.Workspace.Dotfiles.Image = from.Workspace.Dotfiles.Image
+			}
 		}
 		if from.Workspace.DefaultTemplate != nil {
 			templateSpecContentCopy := from.Workspace.DefaultTemplate.DeepCopy()
@@ -410,6 +1009,102 @@ func mergeConfig(from, to *controller.OperatorConfiguration) {
 				to.Workspace.PodAnnotations[key] = value
 			}
 		}
+
+		if from.Workspace.ImageMirrors != nil {
+			if to.Workspace.ImageMirrors == nil {
+				to.Workspace.ImageMirrors = make(map[string]string)
+			}
+			for source, mirror := range from.Workspace.ImageMirrors {
+				to.Workspace.ImageMirrors[source] = mirror
+			}
+		}
+		if from.Workspace.FinalizerHooks != nil {
+			to.Workspace.FinalizerHooks = from.Workspace.FinalizerHooks
+		}
+		if from.Workspace.SSH != nil {
+			if to.Workspace.SSH == nil {
+				to.Workspace.SSH = &controller.SSHConfig{}
+			}
+			if from.Workspace.SSH.KnownHosts != nil {
+				to.Workspace.SSH.KnownHosts = from.Workspace.SSH.KnownHosts
+			}
+			if from.Workspace.SSH.Hosts != nil {
+				to.Workspace.SSH.Hosts = from.Workspace.SSH.Hosts
+			}
+			if from.Workspace.SSH.Agent != nil {
+				if to.Workspace.SSH.Agent == nil {
+					to.Workspace.SSH.Agent = &controller.SSHAgentConfig{}
+				}
+				if from.Workspace.SSH.Agent.Enable != nil {
+					to.Workspace.SSH.Agent.Enable = from.Workspace.SSH.Agent.Enable
+				}
+			}
+			if from.Workspace.SSH.Image != "" {
+				to.Workspace.SSH.Image = from.Workspace.SSH.Image
+			}
+		}
+		if from.Workspace.Standby != nil {
+			if to.Workspace.Standby == nil {
+				to.Workspace.Standby = &controller.StandbyConfig{}
+			}
+			if from.Workspace.Standby.Resources != nil {
+				if to.Workspace.Standby.Resources == nil {
+					to.Workspace.Standby.Resources = &corev1.ResourceRequirements{}
+				}
+				to.Workspace.Standby.Resources = mergeResources(from.Workspace.Standby.Resources, to.Workspace.Standby.Resources)
+			}
+			if from.Workspace.Standby.Timeout != "" {
+				to.Workspace.Standby.Timeout = from.Workspace.Standby.Timeout
+			}
+		}
+		if from.Workspace.EnvironmentUpdates != nil {
+			if to.Workspace.EnvironmentUpdates == nil {
+				to.Workspace.EnvironmentUpdates = &controller.EnvironmentUpdatesConfig{}
+			}
+			if from.Workspace.EnvironmentUpdates.Policy != "" {
+				to.Workspace.EnvironmentUpdates.Policy = from.Workspace.EnvironmentUpdates.Policy
+			}
+			if from.Workspace.EnvironmentUpdates.CheckInterval != "" {
+				to.Workspace.EnvironmentUpdates.CheckInterval = from.Workspace.EnvironmentUpdates.CheckInterval
+			}
+		}
+		if from.Workspace.KubernetesAPIProxy != nil {
+			if to.Workspace.KubernetesAPIProxy == nil {
+				to.Workspace.KubernetesAPIProxy = &controller.KubernetesAPIProxyConfig{}
+			}
+			if from.Workspace.KubernetesAPIProxy.Enable != nil {
+				to.Workspace.KubernetesAPIProxy.Enable = from.Workspace.KubernetesAPIProxy.Enable
+			}
+			if from.Workspace.KubernetesAPIProxy.Image != "" {
+				to.Workspace.KubernetesAPIProxy.Image = from.Workspace.KubernetesAPIProxy.Image
+			}
+			if from.Workspace.KubernetesAPIProxy.AllowedVerbs != nil {
+				to.Workspace.KubernetesAPIProxy.AllowedVerbs = from.Workspace.KubernetesAPIProxy.AllowedVerbs
+			}
+		}
+		if from.Workspace.ImagePuller != nil {
+			if to.Workspace.ImagePuller == nil {
+				to.Workspace.ImagePuller = &controller.ImagePullerConfig{}
+			}
+			if from.Workspace.ImagePuller.Enable != nil {
+				to.Workspace.ImagePuller.Enable = from.Workspace.ImagePuller.Enable
+			}
+			if from.Workspace.ImagePuller.Image != "" {
+				to.Workspace.ImagePuller.Image = from.Workspace.ImagePuller.Image
+			}
+			if from.Workspace.ImagePuller.MaxImages != nil {
+				to.Workspace.ImagePuller.MaxImages = from.Workspace.ImagePuller.MaxImages
+			}
+			if from.Workspace.ImagePuller.Images != nil {
+				to.Workspace.ImagePuller.Images = from.Workspace.ImagePuller.Images
+			}
+			if from.Workspace.ImagePuller.Interval != "" {
+				to.Workspace.ImagePuller.Interval = from.Workspace.ImagePuller.Interval
+			}
+		}
+		if from.Workspace.WarmPools != nil {
+			to.Workspace.WarmPools = from.Workspace.WarmPools
+		}
 	}
 }
 
@@ -553,9 +1248,26 @@ func GetCurrentConfigString(currConfig *controller.OperatorConfiguration) string
 		if workspace.DeploymentStrategy != defaultConfig.Workspace.DeploymentStrategy {
 			config = append(config, fmt.Sprintf("workspace.deploymentStrategy=%s", workspace.DeploymentStrategy))
 		}
+		if workspace.StatefulSetStorageTypes != nil {
+			config = append(config, fmt.Sprintf("workspace.statefulSetStorageTypes=%s", strings.Join(workspace.StatefulSetStorageTypes, ";")))
+		}
 		if workspace.PVCName != defaultConfig.Workspace.PVCName {
 			config = append(config, fmt.Sprintf("workspace.pvcName=%s", workspace.PVCName))
 		}
+		if workspace.NamingTemplates != nil {
+			if workspace.NamingTemplates.Deployment != defaultConfig.Workspace.NamingTemplates.Deployment {
+				config = append(config, fmt.Sprintf("workspace.namingTemplates.deployment=%s", workspace.NamingTemplates.Deployment))
+			}
+			if workspace.NamingTemplates.Service != defaultConfig.Workspace.NamingTemplates.Service {
+				config = append(config, fmt.Sprintf("workspace.namingTemplates.service=%s", workspace.NamingTemplates.Service))
+			}
+			if workspace.NamingTemplates.Routing != defaultConfig.Workspace.NamingTemplates.Routing {
+				config = append(config, fmt.Sprintf("workspace.namingTemplates.routing=%s", workspace.NamingTemplates.Routing))
+			}
+			if workspace.NamingTemplates.PVC != defaultConfig.Workspace.NamingTemplates.PVC {
+				config = append(config, fmt.Sprintf("workspace.namingTemplates.pvc=%s", workspace.NamingTemplates.PVC))
+			}
+		}
 		if workspace.ServiceAccount != nil {
 			if workspace.ServiceAccount.ServiceAccountName != defaultConfig.Workspace.ServiceAccount.ServiceAccountName {
 				config = append(config, fmt.Sprintf("workspace.serviceAccount.serviceAccountName=%s", workspace.ServiceAccount.ServiceAccountName))
@@ -570,26 +1282,174 @@ func GetCurrentConfigString(currConfig *controller.OperatorConfiguration) string
 				}
 				config = append(config, fmt.Sprintf("workspace.serviceAccount.serviceAccountTokens=[%s]", strings.Join(serviceAccountTokens, ", ")))
 			}
+			if workspace.ServiceAccount.ScopedRBAC != nil {
+				if workspace.ServiceAccount.ScopedRBAC.Enable != nil && *workspace.ServiceAccount.ScopedRBAC.Enable != *defaultConfig.Workspace.ServiceAccount.ScopedRBAC.Enable {
+					config = append(config, fmt.Sprintf("workspace.serviceAccount.scopedRBAC.enable=%t", *workspace.ServiceAccount.ScopedRBAC.Enable))
+				}
+				if !reflect.DeepEqual(workspace.ServiceAccount.ScopedRBAC.Rules, defaultConfig.Workspace.ServiceAccount.ScopedRBAC.Rules) {
+					config = append(config, "workspace.serviceAccount.scopedRBAC.rules is set")
+				}
+			}
 		}
 		if workspace.StorageClassName != nil && workspace.StorageClassName != defaultConfig.Workspace.StorageClassName {
 			config = append(config, fmt.Sprintf("workspace.storageClassName=%s", *workspace.StorageClassName))
 		}
+		if workspace.StorageAccessModes != nil {
+			storageAccessModes := make([]string, 0)
+			for storageClass, accessModes := range workspace.StorageAccessModes {
+				storageAccessModes = append(storageAccessModes, fmt.Sprintf("%s=%v", storageClass, accessModes))
+			}
+			config = append(config, fmt.Sprintf("workspace.storageAccessModes=[%s]", strings.Join(storageAccessModes, ", ")))
+		}
 		if workspace.RuntimeClassName != nil && workspace.RuntimeClassName != defaultConfig.Workspace.RuntimeClassName {
 			config = append(config, fmt.Sprintf("workspace.runtimeClassName=%s", *workspace.RuntimeClassName))
 		}
+		if workspace.PriorityClassName != "" {
+			config = append(config, fmt.Sprintf("workspace.priorityClassName=%s", workspace.PriorityClassName))
+		}
+		if workspace.PreemptionPolicy != nil {
+			config = append(config, fmt.Sprintf("workspace.preemptionPolicy=%s", *workspace.PreemptionPolicy))
+		}
+		if workspace.TerminationGracePeriodSeconds != nil && *workspace.TerminationGracePeriodSeconds != *defaultConfig.Workspace.TerminationGracePeriodSeconds {
+			config = append(config, fmt.Sprintf("workspace.terminationGracePeriodSeconds=%d", *workspace.TerminationGracePeriodSeconds))
+		}
+		if workspace.SharedMemorySize != nil {
+			config = append(config, fmt.Sprintf("workspace.sharedMemorySize=%s", workspace.SharedMemorySize.String()))
+		}
+		if workspace.Sysctls != nil {
+			var sysctls []string
+			for _, sysctl := range workspace.Sysctls {
+				sysctls = append(sysctls, fmt.Sprintf("%s=%s", sysctl.Name, sysctl.Value))
+			}
+			config = append(config, fmt.Sprintf("workspace.sysctls=%s", strings.Join(sysctls, ";")))
+		}
+		if workspace.NodeSelector != nil {
+			workspaceNodeSelectors := make([]string, 0)
+			for label, value := range workspace.NodeSelector {
+				workspaceNodeSelectors = append(workspaceNodeSelectors, fmt.Sprintf("%s=%s", label, value))
+			}
+			config = append(config, fmt.Sprintf("workspace.nodeSelector=[%s]", strings.Join(workspaceNodeSelectors, ", ")))
+		}
+		if workspace.Tolerations != nil {
+			workspaceTolerations := make([]string, 0)
+			for _, toleration := range workspace.Tolerations {
+				workspaceTolerations = append(workspaceTolerations, toleration.String())
+			}
+			config = append(config, fmt.Sprintf("workspace.tolerations=[%s]", strings.Join(workspaceTolerations, ", ")))
+		}
+		if workspace.Affinity != nil {
+			config = append(config, "workspace.affinity is set")
+		}
+		if workspace.GPUProfile != nil {
+			if !reflect.DeepEqual(workspace.GPUProfile.ResourceNames, defaultConfig.Workspace.GPUProfile.ResourceNames) {
+				var resourceNames []string
+				for _, resourceName := range workspace.GPUProfile.ResourceNames {
+					resourceNames = append(resourceNames, string(resourceName))
+				}
+				config = append(config, fmt.Sprintf("workspace.gpuProfile.resourceNames=[%s]", strings.Join(resourceNames, ", ")))
+			}
+			if workspace.GPUProfile.NodeSelector != nil {
+				gpuNodeSelectors := make([]string, 0)
+				for label, value := range workspace.GPUProfile.NodeSelector {
+					gpuNodeSelectors = append(gpuNodeSelectors, fmt.Sprintf("%s=%s", label, value))
+				}
+				config = append(config, fmt.Sprintf("workspace.gpuProfile.nodeSelector=[%s]", strings.Join(gpuNodeSelectors, ", ")))
+			}
+			if workspace.GPUProfile.Tolerations != nil {
+				gpuTolerations := make([]string, 0)
+				for _, toleration := range workspace.GPUProfile.Tolerations {
+					gpuTolerations = append(gpuTolerations, toleration.String())
+				}
+				config = append(config, fmt.Sprintf("workspace.gpuProfile.tolerations=[%s]", strings.Join(gpuTolerations, ", ")))
+			}
+		}
+		if workspace.ImageBuild != nil {
+			if workspace.ImageBuild.Registry != defaultConfig.Workspace.ImageBuild.Registry {
+				config = append(config, fmt.Sprintf("workspace.imageBuild.registry=%s", workspace.ImageBuild.Registry))
+			}
+			if workspace.ImageBuild.PushSecretName != defaultConfig.Workspace.ImageBuild.PushSecretName {
+				config = append(config, fmt.Sprintf("workspace.imageBuild.pushSecretName=%s", workspace.ImageBuild.PushSecretName))
+			}
+			if workspace.ImageBuild.BuilderImage != defaultConfig.Workspace.ImageBuild.BuilderImage {
+				config = append(config, fmt.Sprintf("workspace.imageBuild.builderImage=%s", workspace.ImageBuild.BuilderImage))
+			}
+			if workspace.ImageBuild.Timeout != defaultConfig.Workspace.ImageBuild.Timeout {
+				config = append(config, fmt.Sprintf("workspace.imageBuild.timeout=%s", workspace.ImageBuild.Timeout))
+			}
+		}
+		if workspace.ImageScanning != nil {
+			if workspace.ImageScanning.Scanner != defaultConfig.Workspace.ImageScanning.Scanner {
+				config = append(config, fmt.Sprintf("workspace.imageScanning.scanner=%s", workspace.ImageScanning.Scanner))
+			}
+			if workspace.ImageScanning.URL != defaultConfig.Workspace.ImageScanning.URL {
+				config = append(config, fmt.Sprintf("workspace.imageScanning.url=%s", workspace.ImageScanning.URL))
+			}
+			if workspace.ImageScanning.TokenSecretName != defaultConfig.Workspace.ImageScanning.TokenSecretName {
+				config = append(config, fmt.Sprintf("workspace.imageScanning.tokenSecretName=%s", workspace.ImageScanning.TokenSecretName))
+			}
+			if workspace.ImageScanning.SeverityThreshold != defaultConfig.Workspace.ImageScanning.SeverityThreshold {
+				config = append(config, fmt.Sprintf("workspace.imageScanning.severityThreshold=%s", workspace.ImageScanning.SeverityThreshold))
+			}
+			if workspace.ImageScanning.Policy != defaultConfig.Workspace.ImageScanning.Policy {
+				config = append(config, fmt.Sprintf("workspace.imageScanning.policy=%s", workspace.ImageScanning.Policy))
+			}
+		}
+		if workspace.PinImageDigests != nil && *workspace.PinImageDigests != *defaultConfig.Workspace.PinImageDigests {
+			config = append(config, fmt.Sprintf("workspace.pinImageDigests=%t", *workspace.PinImageDigests))
+		}
 		if workspace.IdleTimeout != defaultConfig.Workspace.IdleTimeout {
 			config = append(config, fmt.Sprintf("workspace.idleTimeout=%s", workspace.IdleTimeout))
 		}
+		if workspace.IdleWarningPeriod != defaultConfig.Workspace.IdleWarningPeriod {
+			config = append(config, fmt.Sprintf("workspace.idleWarningPeriod=%s", workspace.IdleWarningPeriod))
+		}
 		if workspace.ProgressTimeout != "" && workspace.ProgressTimeout != defaultConfig.Workspace.ProgressTimeout {
 			config = append(config, fmt.Sprintf("workspace.progressTimeout=%s", workspace.ProgressTimeout))
 		}
+		if workspace.PhaseTimeouts != nil {
+			if workspace.PhaseTimeouts.RoutingReady != "" {
+				config = append(config, fmt.Sprintf("workspace.phaseTimeouts.routingReady=%s", workspace.PhaseTimeouts.RoutingReady))
+			}
+			if workspace.PhaseTimeouts.StorageReady != "" {
+				config = append(config, fmt.Sprintf("workspace.phaseTimeouts.storageReady=%s", workspace.PhaseTimeouts.StorageReady))
+			}
+			if workspace.PhaseTimeouts.DeploymentReady != "" {
+				config = append(config, fmt.Sprintf("workspace.phaseTimeouts.deploymentReady=%s", workspace.PhaseTimeouts.DeploymentReady))
+			}
+			if workspace.PhaseTimeouts.ServersReady != "" {
+				config = append(config, fmt.Sprintf("workspace.phaseTimeouts.serversReady=%s", workspace.PhaseTimeouts.ServersReady))
+			}
+		}
 		if workspace.IgnoredUnrecoverableEvents != nil {
 			config = append(config, fmt.Sprintf("workspace.ignoredUnrecoverableEvents=%s",
 				strings.Join(workspace.IgnoredUnrecoverableEvents, ";")))
 		}
+		if workspace.DisabledDevfileFeatures != nil {
+			var features []string
+			for _, feature := range workspace.DisabledDevfileFeatures {
+				features = append(features, string(feature))
+			}
+			config = append(config, fmt.Sprintf("workspace.disabledDevfileFeatures=%s", strings.Join(features, ";")))
+		}
+		if workspace.AccessControl != nil {
+			if workspace.AccessControl.RestrictToCreator != nil && *workspace.AccessControl.RestrictToCreator {
+				config = append(config, "workspace.accessControl.restrictToCreator=true")
+			}
+			if workspace.AccessControl.AdminGroups != nil {
+				config = append(config, fmt.Sprintf("workspace.accessControl.adminGroups=%s", strings.Join(workspace.AccessControl.AdminGroups, ";")))
+			}
+		}
 		if workspace.CleanupOnStop != nil && *workspace.CleanupOnStop != *defaultConfig.Workspace.CleanupOnStop {
 			config = append(config, fmt.Sprintf("workspace.cleanupOnStop=%t", *workspace.CleanupOnStop))
 		}
+		if workspace.GracefulStop != nil {
+			if workspace.GracefulStop.Enable != nil && *workspace.GracefulStop.Enable {
+				config = append(config, "workspace.gracefulStop.enable=true")
+			}
+			if workspace.GracefulStop.Timeout != defaultConfig.Workspace.GracefulStop.Timeout {
+				config = append(config, fmt.Sprintf("workspace.gracefulStop.timeout=%s", workspace.GracefulStop.Timeout))
+			}
+		}
 		if workspace.DefaultStorageSize != nil {
 			if workspace.DefaultStorageSize.Common != nil && workspace.DefaultStorageSize.Common.String() != defaultConfig.Workspace.DefaultStorageSize.Common.String() {
 				config = append(config, fmt.Sprintf("workspace.defaultStorageSize.common=%s", workspace.DefaultStorageSize.Common.String()))
@@ -598,6 +1458,195 @@ func GetCurrentConfigString(currConfig *controller.OperatorConfiguration) string
 				config = append(config, fmt.Sprintf("workspace.defaultStorageSize.perWorkspace=%s", workspace.DefaultStorageSize.PerWorkspace.String()))
 			}
 		}
+		if workspace.AutoResizeCommonPVC != nil && *workspace.AutoResizeCommonPVC != *defaultConfig.Workspace.AutoResizeCommonPVC {
+			config = append(config, fmt.Sprintf("workspace.autoResizeCommonPVC=%t", *workspace.AutoResizeCommonPVC))
+		}
+		if workspace.CommonPVCGarbageCollection != nil {
+			if workspace.CommonPVCGarbageCollection.Enable != nil && *workspace.CommonPVCGarbageCollection.Enable != *defaultConfig.Workspace.CommonPVCGarbageCollection.Enable {
+				config = append(config, fmt.Sprintf("workspace.commonPVCGarbageCollection.enable=%t", *workspace.CommonPVCGarbageCollection.Enable))
+			}
+			if workspace.CommonPVCGarbageCollection.Interval != defaultConfig.Workspace.CommonPVCGarbageCollection.Interval {
+				config = append(config, fmt.Sprintf("workspace.commonPVCGarbageCollection.interval=%s", workspace.CommonPVCGarbageCollection.Interval))
+			}
+		}
+		if workspace.ProjectBackup != nil {
+			if workspace.ProjectBackup.Enable != nil && *workspace.ProjectBackup.Enable != *defaultConfig.Workspace.ProjectBackup.Enable {
+				config = append(config, fmt.Sprintf("workspace.projectBackup.enable=%t", *workspace.ProjectBackup.Enable))
+			}
+			if workspace.ProjectBackup.Interval != defaultConfig.Workspace.ProjectBackup.Interval {
+				config = append(config, fmt.Sprintf("workspace.projectBackup.interval=%s", workspace.ProjectBackup.Interval))
+			}
+			if workspace.ProjectBackup.URL != defaultConfig.Workspace.ProjectBackup.URL {
+				config = append(config, fmt.Sprintf("workspace.projectBackup.url=%s", workspace.ProjectBackup.URL))
+			}
+			if workspace.ProjectBackup.Region != defaultConfig.Workspace.ProjectBackup.Region {
+				config = append(config, fmt.Sprintf("workspace.projectBackup.region=%s", workspace.ProjectBackup.Region))
+			}
+			if workspace.ProjectBackup.CredentialsSecretName != defaultConfig.Workspace.ProjectBackup.CredentialsSecretName {
+				config = append(config, fmt.Sprintf("workspace.projectBackup.credentialsSecretName=%s", workspace.ProjectBackup.CredentialsSecretName))
+			}
+		}
+		if workspace.GuestWorkspaces != nil {
+			if workspace.GuestWorkspaces.Enable != nil && *workspace.GuestWorkspaces.Enable != *defaultConfig.Workspace.GuestWorkspaces.Enable {
+				config = append(config, fmt.Sprintf("workspace.guestWorkspaces.enable=%t", *workspace.GuestWorkspaces.Enable))
+			}
+			if workspace.GuestWorkspaces.NamespacePrefix != defaultConfig.Workspace.GuestWorkspaces.NamespacePrefix {
+				config = append(config, fmt.Sprintf("workspace.guestWorkspaces.namespacePrefix=%s", workspace.GuestWorkspaces.NamespacePrefix))
+			}
+			if workspace.GuestWorkspaces.TTL != defaultConfig.Workspace.GuestWorkspaces.TTL {
+				config = append(config, fmt.Sprintf("workspace.guestWorkspaces.ttl=%s", workspace.GuestWorkspaces.TTL))
+			}
+			if !reflect.DeepEqual(workspace.GuestWorkspaces.Quota, defaultConfig.Workspace.GuestWorkspaces.Quota) {
+				config = append(config, "workspace.guestWorkspaces.quota is set")
+			}
+		}
+		if workspace.NetworkIsolation != nil {
+			if workspace.NetworkIsolation.Enable != nil && *workspace.NetworkIsolation.Enable != *defaultConfig.Workspace.NetworkIsolation.Enable {
+				config = append(config, fmt.Sprintf("workspace.networkIsolation.enable=%t", *workspace.NetworkIsolation.Enable))
+			}
+			if workspace.NetworkIsolation.IngressNamespaceSelector != nil {
+				config = append(config, "workspace.networkIsolation.ingressNamespaceSelector is set")
+			}
+			if workspace.NetworkIsolation.AllowedNamespaces != nil {
+				config = append(config, fmt.Sprintf("workspace.networkIsolation.allowedNamespaces=[%s]", strings.Join(workspace.NetworkIsolation.AllowedNamespaces, ", ")))
+			}
+			if workspace.NetworkIsolation.AllowedCIDRs != nil {
+				config = append(config, fmt.Sprintf("workspace.networkIsolation.allowedCIDRs=[%s]", strings.Join(workspace.NetworkIsolation.AllowedCIDRs, ", ")))
+			}
+		}
+		if workspace.UserNamespaces != nil {
+			if workspace.UserNamespaces.Enable != nil && *workspace.UserNamespaces.Enable != *defaultConfig.Workspace.UserNamespaces.Enable {
+				config = append(config, fmt.Sprintf("workspace.userNamespaces.enable=%t", *workspace.UserNamespaces.Enable))
+			}
+			if workspace.UserNamespaces.NameTemplate != defaultConfig.Workspace.UserNamespaces.NameTemplate {
+				config = append(config, fmt.Sprintf("workspace.userNamespaces.nameTemplate=%s", workspace.UserNamespaces.NameTemplate))
+			}
+			if workspace.UserNamespaces.Labels != nil {
+				config = append(config, "workspace.userNamespaces.labels is set")
+			}
+			if workspace.UserNamespaces.Annotations != nil {
+				config = append(config, "workspace.userNamespaces.annotations is set")
+			}
+			if workspace.UserNamespaces.ClusterRoleName != defaultConfig.Workspace.UserNamespaces.ClusterRoleName {
+				config = append(config, fmt.Sprintf("workspace.userNamespaces.clusterRoleName=%s", workspace.UserNamespaces.ClusterRoleName))
+			}
+			if workspace.UserNamespaces.Quota != nil {
+				config = append(config, "workspace.userNamespaces.quota is set")
+			}
+			if workspace.UserNamespaces.LimitRange != nil {
+				config = append(config, "workspace.userNamespaces.limitRange is set")
+			}
+			if workspace.UserNamespaces.NetworkPolicy != nil {
+				config = append(config, "workspace.userNamespaces.networkPolicy is set")
+			}
+		}
+		if workspace.StartRetry != nil {
+			if workspace.StartRetry.MaxAttempts != nil && *workspace.StartRetry.MaxAttempts != *defaultConfig.Workspace.StartRetry.MaxAttempts {
+				config = append(config, fmt.Sprintf("workspace.startRetry.maxAttempts=%d", *workspace.StartRetry.MaxAttempts))
+			}
+			if workspace.StartRetry.InitialBackoff != defaultConfig.Workspace.StartRetry.InitialBackoff {
+				config = append(config, fmt.Sprintf("workspace.startRetry.initialBackoff=%s", workspace.StartRetry.InitialBackoff))
+			}
+			if workspace.StartRetry.MaxBackoff != defaultConfig.Workspace.StartRetry.MaxBackoff {
+				config = append(config, fmt.Sprintf("workspace.startRetry.maxBackoff=%s", workspace.StartRetry.MaxBackoff))
+			}
+		}
+		if workspace.Limits != nil {
+			if !reflect.DeepEqual(workspace.Limits.MaxResources, defaultConfig.Workspace.Limits.MaxResources) {
+				config = append(config, "workspace.limits.maxResources is set")
+			}
+			if workspace.Limits.MaxRunningPerUser != nil {
+				config = append(config, fmt.Sprintf("workspace.limits.maxRunningPerUser=%d", *workspace.Limits.MaxRunningPerUser))
+			}
+			if workspace.Limits.MaxRunningPerNamespace != nil {
+				config = append(config, fmt.Sprintf("workspace.limits.maxRunningPerNamespace=%d", *workspace.Limits.MaxRunningPerNamespace))
+			}
+			if workspace.Limits.ExceededPolicy != defaultConfig.Workspace.Limits.ExceededPolicy {
+				config = append(config, fmt.Sprintf("workspace.limits.exceededPolicy=%s", workspace.Limits.ExceededPolicy))
+			}
+		}
+		if workspace.StartQueue != nil {
+			if workspace.StartQueue.MaxConcurrentStarts != nil {
+				config = append(config, fmt.Sprintf("workspace.startQueue.maxConcurrentStarts=%d", *workspace.StartQueue.MaxConcurrentStarts))
+			}
+			if workspace.StartQueue.Ordering != defaultConfig.Workspace.StartQueue.Ordering {
+				config = append(config, fmt.Sprintf("workspace.startQueue.ordering=%s", workspace.StartQueue.Ordering))
+			}
+		}
+		if workspace.RunningBudget != nil && workspace.RunningBudget.Weekly != "" {
+			config = append(config, fmt.Sprintf("workspace.runningBudget.weekly=%s", workspace.RunningBudget.Weekly))
+		}
+		if workspace.UsageAccounting != nil && workspace.UsageAccounting.Enable != nil && *workspace.UsageAccounting.Enable {
+			config = append(config, "workspace.usageAccounting.enable=true")
+		}
+		if workspace.RunSchedule != nil {
+			if workspace.RunSchedule.TimeZone != "" {
+				config = append(config, fmt.Sprintf("workspace.runSchedule.timeZone=%s", workspace.RunSchedule.TimeZone))
+			}
+			if workspace.RunSchedule.Stop != nil {
+				config = append(config, fmt.Sprintf("workspace.runSchedule.stop=[%s]", strings.Join(workspace.RunSchedule.Stop, ", ")))
+			}
+			if workspace.RunSchedule.Blackouts != nil {
+				var blackouts []string
+				for _, blackout := range workspace.RunSchedule.Blackouts {
+					blackouts = append(blackouts, fmt.Sprintf("%s for %s", blackout.Start, blackout.Duration))
+				}
+				config = append(config, fmt.Sprintf("workspace.runSchedule.blackouts=[%s]", strings.Join(blackouts, ", ")))
+			}
+			if workspace.RunSchedule.Selector != nil {
+				config = append(config, "workspace.runSchedule.selector is set")
+			}
+		}
+		if workspace.Maintenance != nil {
+			if workspace.Maintenance.Enable != nil && *workspace.Maintenance.Enable {
+				config = append(config, "workspace.maintenance.enable=true")
+			}
+			if workspace.Maintenance.StopRunningWorkspaces != nil && *workspace.Maintenance.StopRunningWorkspaces {
+				config = append(config, "workspace.maintenance.stopRunningWorkspaces=true")
+			}
+			if workspace.Maintenance.DrainWindow != "" {
+				config = append(config, fmt.Sprintf("workspace.maintenance.drainWindow=%s", workspace.Maintenance.DrainWindow))
+			}
+		}
+		if workspace.GangScheduling != nil {
+			if workspace.GangScheduling.Enable != nil && *workspace.GangScheduling.Enable != *defaultConfig.Workspace.GangScheduling.Enable {
+				config = append(config, fmt.Sprintf("workspace.gangScheduling.enable=%t", *workspace.GangScheduling.Enable))
+			}
+			if workspace.GangScheduling.ScheduleTimeoutSeconds != nil {
+				config = append(config, fmt.Sprintf("workspace.gangScheduling.scheduleTimeoutSeconds=%d", *workspace.GangScheduling.ScheduleTimeoutSeconds))
+			}
+		}
+		if workspace.NodeFailureRecovery != nil {
+			if workspace.NodeFailureRecovery.Policy != defaultConfig.Workspace.NodeFailureRecovery.Policy {
+				config = append(config, fmt.Sprintf("workspace.nodeFailureRecovery.policy=%s", workspace.NodeFailureRecovery.Policy))
+			}
+			if workspace.NodeFailureRecovery.TerminationTimeout != defaultConfig.Workspace.NodeFailureRecovery.TerminationTimeout {
+				config = append(config, fmt.Sprintf("workspace.nodeFailureRecovery.terminationTimeout=%s", workspace.NodeFailureRecovery.TerminationTimeout))
+			}
+		}
+		if workspace.PluginRegistry != nil {
+			if workspace.PluginRegistry.DefaultRegistryURL != defaultConfig.Workspace.PluginRegistry.DefaultRegistryURL {
+				config = append(config, fmt.Sprintf("workspace.pluginRegistry.defaultRegistryURL=%s", workspace.PluginRegistry.DefaultRegistryURL))
+			}
+			if workspace.PluginRegistry.CacheTTL != defaultConfig.Workspace.PluginRegistry.CacheTTL {
+				config = append(config, fmt.Sprintf("workspace.pluginRegistry.cacheTTL=%s", workspace.PluginRegistry.CacheTTL))
+			}
+		}
+
+		if workspace.Trash != nil {
+			if workspace.Trash.Enable != nil && *workspace.Trash.Enable != *defaultConfig.Workspace.Trash.Enable {
+				config = append(config, fmt.Sprintf("workspace.trash.enable=%t", *workspace.Trash.Enable))
+			}
+			if workspace.Trash.RetentionPeriod != defaultConfig.Workspace.Trash.RetentionPeriod {
+				config = append(config, fmt.Sprintf("workspace.trash.retentionPeriod=%s", workspace.Trash.RetentionPeriod))
+			}
+		}
+		if workspace.DefaultStorageType != defaultConfig.Workspace.DefaultStorageType {
+			config = append(config, fmt.Sprintf("workspace.defaultStorageType=%s", workspace.DefaultStorageType))
+		}
+		if workspace.AsyncStorageSyncInterval != defaultConfig.Workspace.AsyncStorageSyncInterval {
+			config = append(config, fmt.Sprintf("workspace.asyncStorageSyncInterval=%s", workspace.AsyncStorageSyncInterval))
+		}
 		if workspace.PersistUserHome != nil {
 			if workspace.PersistUserHome.Enabled != nil && *workspace.PersistUserHome.Enabled != *defaultConfig.Workspace.PersistUserHome.Enabled {
 				config = append(config, fmt.Sprintf("workspace.persistUserHome.enabled=%t", *workspace.PersistUserHome.Enabled))
@@ -605,6 +1654,17 @@ func GetCurrentConfigString(currConfig *controller.OperatorConfiguration) string
 			if workspace.PersistUserHome.DisableInitContainer != nil && *workspace.PersistUserHome.DisableInitContainer != *defaultConfig.Workspace.PersistUserHome.DisableInitContainer {
 				config = append(config, fmt.Sprintf("workspace.persistUserHome.disableInitContainer=%t", *workspace.PersistUserHome.DisableInitContainer))
 			}
+			if workspace.PersistUserHome.DedicatedPVC != nil && *workspace.PersistUserHome.DedicatedPVC != *defaultConfig.Workspace.PersistUserHome.DedicatedPVC {
+				config = append(config, fmt.Sprintf("workspace.persistUserHome.dedicatedPVC=%t", *workspace.PersistUserHome.DedicatedPVC))
+			}
+		}
+		if workspace.Dotfiles != nil {
+			if workspace.Dotfiles.Enable != nil && *workspace.Dotfiles.Enable != *defaultConfig.Workspace.Dotfiles.Enable {
+				config = append(config, fmt.Sprintf("workspace.dotfiles.enable=%t", *workspace.Dotfiles.Enable))
+			}
+			if workspace.Dotfiles.Image != defaultConfig.Workspace.Dotfiles.Image {
+				config = append(config, fmt.Sprintf("workspace.dotfiles.image=%s", workspace.Dotfiles.Image))
+			}
 		}
 		if !reflect.DeepEqual(workspace.PodSecurityContext, defaultConfig.Workspace.PodSecurityContext) {
 			config = append(config, "workspace.podSecurityContext is set")
@@ -612,6 +1672,31 @@ func GetCurrentConfigString(currConfig *controller.OperatorConfiguration) string
 		if !reflect.DeepEqual(workspace.ContainerSecurityContext, defaultConfig.Workspace.ContainerSecurityContext) {
 			config = append(config, "workspace.containerSecurityContext is set")
 		}
+		if workspace.SecurityContextPolicy != nil && defaultConfig.Workspace.SecurityContextPolicy != nil {
+			policy := workspace.SecurityContextPolicy
+			defaultPolicy := defaultConfig.Workspace.SecurityContextPolicy
+			if policy.Enable != nil && *policy.Enable != *defaultPolicy.Enable {
+				config = append(config, fmt.Sprintf("workspace.securityContextPolicy.enable=%t", *policy.Enable))
+			}
+			if policy.RunAsNonRoot != nil && *policy.RunAsNonRoot != *defaultPolicy.RunAsNonRoot {
+				config = append(config, fmt.Sprintf("workspace.securityContextPolicy.runAsNonRoot=%t", *policy.RunAsNonRoot))
+			}
+			if policy.AllowPrivilegeEscalation != nil && *policy.AllowPrivilegeEscalation != *defaultPolicy.AllowPrivilegeEscalation {
+				config = append(config, fmt.Sprintf("workspace.securityContextPolicy.allowPrivilegeEscalation=%t", *policy.AllowPrivilegeEscalation))
+			}
+			if policy.FSGroupStrategy != defaultPolicy.FSGroupStrategy {
+				config = append(config, fmt.Sprintf("workspace.securityContextPolicy.fsGroupStrategy=%s", policy.FSGroupStrategy))
+			}
+			if !reflect.DeepEqual(policy.SeccompProfile, defaultPolicy.SeccompProfile) {
+				config = append(config, "workspace.securityContextPolicy.seccompProfile is set")
+			}
+			if !reflect.DeepEqual(policy.DropCapabilities, defaultPolicy.DropCapabilities) {
+				config = append(config, fmt.Sprintf("workspace.securityContextPolicy.dropCapabilities=%v", policy.DropCapabilities))
+			}
+			if policy.LabelNamespaces != nil && *policy.LabelNamespaces != *defaultPolicy.LabelNamespaces {
+				config = append(config, fmt.Sprintf("workspace.securityContextPolicy.labelNamespaces=%t", *policy.LabelNamespaces))
+			}
+		}
 		if workspace.DefaultTemplate != nil {
 			config = append(config, "workspace.defaultTemplate is set")
 		}
@@ -638,10 +1723,169 @@ func GetCurrentConfigString(currConfig *controller.OperatorConfiguration) string
 		if !reflect.DeepEqual(workspace.PodAnnotations, defaultConfig.Workspace.PodAnnotations) {
 			config = append(config, "workspace.podAnnotations is set")
 		}
+		if !reflect.DeepEqual(workspace.ImageMirrors, defaultConfig.Workspace.ImageMirrors) {
+			config = append(config, "workspace.imageMirrors is set")
+		}
+		if len(workspace.FinalizerHooks) > 0 {
+			var hooks []string
+			for _, hook := range workspace.FinalizerHooks {
+				hooks = append(hooks, hook.Name)
+			}
+			config = append(config, fmt.Sprintf("workspace.finalizerHooks=[%s]", strings.Join(hooks, ", ")))
+		}
+		if workspace.SSH != nil {
+			if len(workspace.SSH.KnownHosts) > 0 {
+				config = append(config, "workspace.ssh.knownHosts is set")
+			}
+			if len(workspace.SSH.Hosts) > 0 {
+				config = append(config, "workspace.ssh.hosts is set")
+			}
+			if workspace.SSH.Agent != nil && workspace.SSH.Agent.Enable != nil {
+				config = append(config, fmt.Sprintf("workspace.ssh.agent.enable=%t", *workspace.SSH.Agent.Enable))
+			}
+			if workspace.SSH.Image != "" {
+				config = append(config, fmt.Sprintf("workspace.ssh.image=%s", workspace.SSH.Image))
+			}
+		}
+		if workspace.Standby != nil {
+			if !reflect.DeepEqual(workspace.Standby.Resources, defaultConfig.Workspace.Standby.Resources) {
+				config = append(config, "workspace.standby.resources is set")
+			}
+			if workspace.Standby.Timeout != defaultConfig.Workspace.Standby.Timeout {
+				config = append(config, fmt.Sprintf("workspace.standby.timeout=%s", workspace.Standby.Timeout))
+			}
+		}
+		if workspace.EnvironmentUpdates != nil {
+			if workspace.EnvironmentUpdates.Policy != defaultConfig.Workspace.EnvironmentUpdates.Policy {
+				config = append(config, fmt.Sprintf("workspace.environmentUpdates.policy=%s", workspace.EnvironmentUpdates.Policy))
+			}
+			if workspace.EnvironmentUpdates.CheckInterval != defaultConfig.Workspace.EnvironmentUpdates.CheckInterval {
+				config = append(config, fmt.Sprintf("workspace.environmentUpdates.checkInterval=%s", workspace.EnvironmentUpdates.CheckInterval))
+			}
+		}
+		if workspace.KubernetesAPIProxy != nil {
+			if workspace.KubernetesAPIProxy.Enable != nil {
+				config = append(config, fmt.Sprintf("workspace.kubernetesAPIProxy.enable=%t", *workspace.KubernetesAPIProxy.Enable))
+			}
+			if workspace.KubernetesAPIProxy.Image != "" {
+				config = append(config, fmt.Sprintf("workspace.kubernetesAPIProxy.image=%s", workspace.KubernetesAPIProxy.Image))
+			}
+			if workspace.KubernetesAPIProxy.AllowedVerbs != nil {
+				config = append(config, fmt.Sprintf("workspace.kubernetesAPIProxy.allowedVerbs=%s", strings.Join(workspace.KubernetesAPIProxy.AllowedVerbs, ",")))
+			}
+		}
+		if workspace.ImagePuller != nil {
+			if workspace.ImagePuller.Enable != nil {
+				config = append(config, fmt.Sprintf("workspace.imagePuller.enable=%t", *workspace.ImagePuller.Enable))
+			}
+			if workspace.ImagePuller.Image != "" {
+				config = append(config, fmt.Sprintf("workspace.imagePuller.image=%s", workspace.ImagePuller.Image))
+			}
+			if workspace.ImagePuller.MaxImages != nil {
+				config = append(config, fmt.Sprintf("workspace.imagePuller.maxImages=%d", *workspace.ImagePuller.MaxImages))
+			}
+			if workspace.ImagePuller.Images != nil {
+				config = append(config, fmt.Sprintf("workspace.imagePuller.images=%s", strings.Join(workspace.ImagePuller.Images, ",")))
+			}
+			if workspace.ImagePuller.Interval != "" {
+				config = append(config, fmt.Sprintf("workspace.imagePuller.interval=%s", workspace.ImagePuller.Interval))
+			}
+		}
+		if len(workspace.WarmPools) > 0 {
+			var pools []string
+			for _, pool := range workspace.WarmPools {
+				pools = append(pools, pool.Name)
+			}
+			config = append(config, fmt.Sprintf("workspace.warmPools=[%s]", strings.Join(pools, ", ")))
+		}
+	}
+	if currConfig.Metrics != nil {
+		if currConfig.Metrics.EnableServiceMonitor != nil && *currConfig.Metrics.EnableServiceMonitor {
+			config = append(config, "metrics.enableServiceMonitor=true")
+		}
+		if currConfig.Metrics.ScrapeInterval != "" {
+			config = append(config, fmt.Sprintf("metrics.scrapeInterval=%s", currConfig.Metrics.ScrapeInterval))
+		}
+	}
+	if currConfig.EventSink != nil {
+		if currConfig.EventSink.URL != "" {
+			config = append(config, fmt.Sprintf("eventSink.url=%s", currConfig.EventSink.URL))
+		}
+		if currConfig.EventSink.SecretName != "" {
+			config = append(config, fmt.Sprintf("eventSink.secretName=%s", currConfig.EventSink.SecretName))
+		}
+		if currConfig.EventSink.Timeout != defaultConfig.EventSink.Timeout {
+			config = append(config, fmt.Sprintf("eventSink.timeout=%s", currConfig.EventSink.Timeout))
+		}
+		if currConfig.EventSink.Format != defaultConfig.EventSink.Format {
+			config = append(config, fmt.Sprintf("eventSink.format=%s", currConfig.EventSink.Format))
+		}
+		if currConfig.EventSink.PhaseTransitions != nil && *currConfig.EventSink.PhaseTransitions {
+			config = append(config, "eventSink.phaseTransitions=true")
+		}
+	}
+	if currConfig.Audit != nil {
+		if currConfig.Audit.Enable != nil && *currConfig.Audit.Enable {
+			config = append(config, "audit.enable=true")
+		}
+		if currConfig.Audit.ConfigMapEntries != 0 {
+			config = append(config, fmt.Sprintf("audit.configMapEntries=%d", currConfig.Audit.ConfigMapEntries))
+		}
+		if currConfig.Audit.Sink != nil {
+			if currConfig.Audit.Sink.URL != "" {
+				config = append(config, fmt.Sprintf("audit.sink.url=%s", currConfig.Audit.Sink.URL))
+			}
+			if currConfig.Audit.Sink.SecretName != "" {
+				config = append(config, fmt.Sprintf("audit.sink.secretName=%s", currConfig.Audit.Sink.SecretName))
+			}
+			if currConfig.Audit.Sink.Timeout != defaultConfig.Audit.Sink.Timeout {
+				config = append(config, fmt.Sprintf("audit.sink.timeout=%s", currConfig.Audit.Sink.Timeout))
+			}
+			if currConfig.Audit.Sink.Format != defaultConfig.Audit.Sink.Format {
+				config = append(config, fmt.Sprintf("audit.sink.format=%s", currConfig.Audit.Sink.Format))
+			}
+		}
+	}
+	if currConfig.LogStreaming != nil {
+		if currConfig.LogStreaming.Enable != nil && *currConfig.LogStreaming.Enable {
+			config = append(config, "logStreaming.enable=true")
+		}
+	}
+	if currConfig.CommandExec != nil {
+		if currConfig.CommandExec.Enable != nil && *currConfig.CommandExec.Enable {
+			config = append(config, "commandExec.enable=true")
+		}
+	}
+	if currConfig.GitWebhook != nil {
+		if currConfig.GitWebhook.Enable != nil && *currConfig.GitWebhook.Enable {
+			config = append(config, "gitWebhook.enable=true")
+		}
+	}
+	if currConfig.Terminal != nil {
+		if currConfig.Terminal.Enable != nil && *currConfig.Terminal.Enable {
+			config = append(config, "terminal.enable=true")
+		}
+		if !reflect.DeepEqual(currConfig.Terminal.Command, defaultConfig.Terminal.Command) {
+			config = append(config, fmt.Sprintf("terminal.command=%s", strings.Join(currConfig.Terminal.Command, " ")))
+		}
+		if currConfig.Terminal.SessionRecording != nil {
+			if currConfig.Terminal.SessionRecording.URL != "" {
+				config = append(config, fmt.Sprintf("terminal.sessionRecording.url=%s", currConfig.Terminal.SessionRecording.URL))
+			}
+			if currConfig.Terminal.SessionRecording.Timeout != defaultConfig.Terminal.SessionRecording.Timeout {
+				config = append(config, fmt.Sprintf("terminal.sessionRecording.timeout=%s", currConfig.Terminal.SessionRecording.Timeout))
+			}
+		}
 	}
 	if currConfig.EnableExperimentalFeatures != nil && *currConfig.EnableExperimentalFeatures {
 		config = append(config, "enableExperimentalFeatures=true")
 	}
+	if currConfig.FeatureGates != "" {
+		config = append(config, fmt.Sprintf("featureGates=%s", currConfig.FeatureGates))
+	}
+	if currConfig.CanaryFeatureGates != "" {
+		config = append(config, fmt.Sprintf("canaryFeatureGates=%s", currConfig.CanaryFeatureGates))
+	}
 	if len(config) == 0 {
 		return ""
 	} else {
@@ -661,4 +1905,6 @@ func logCurrentConfig() {
 	if internalConfig.Routing.ProxyConfig != nil {
 		log.Info("Resolved proxy configuration", "proxy", internalConfig.Routing.ProxyConfig)
 	}
+
+	logFeatureGates()
 }
@@ -107,6 +107,21 @@ func TestMergesAllFieldsFromClusterConfig(t *testing.T) {
 				*deploymentStrategy = appsv1.RecreateDeploymentStrategyType
 			}
 		},
+		func(format *v1alpha1.EventSinkFormat, c fuzz.Continue) {
+			if c.Int()%2 == 0 {
+				*format = v1alpha1.EventSinkFormatCloudEvents
+			} else {
+				*format = v1alpha1.EventSinkFormatDevWorkspace
+			}
+		},
+		func(policy *v1alpha1.EnvironmentUpdatePolicy, c fuzz.Continue) {
+			policies := []v1alpha1.EnvironmentUpdatePolicy{
+				v1alpha1.EnvironmentUpdateAnnotateOutdated,
+				v1alpha1.EnvironmentUpdateNotify,
+				v1alpha1.EnvironmentUpdateRestart,
+			}
+			*policy = policies[c.Intn(len(policies))]
+		},
 		fuzzQuantity,
 		fuzzResourceList,
 		fuzzResourceRequirements,
@@ -218,6 +233,27 @@ func TestMergeExternalConfig(t *testing.T) {
 	}
 }
 
+func TestResolveConfigForWorkspaceUsesIdleTimeoutAnnotation(t *testing.T) {
+	setupForTest(t)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(buildConfig(defaultConfig.DeepCopy())).Build()
+	if !assert.NoError(t, SetupControllerConfig(client)) {
+		return
+	}
+	workspace := &dw.DevWorkspace{}
+	workspace.Annotations = map[string]string{constants.DevWorkspaceIdleTimeoutAnnotation: "2h"}
+
+	resolvedConfig, err := ResolveConfigForWorkspace(workspace, client)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, "2h", resolvedConfig.Workspace.IdleTimeout, "Idle timeout annotation should override config")
+	assert.Equal(t, defaultConfig.Workspace.IdleTimeout, internalConfig.Workspace.IdleTimeout, "Global config should not be modified")
+
+	workspace.Annotations[constants.DevWorkspaceIdleTimeoutAnnotation] = "two hours"
+	_, err = ResolveConfigForWorkspace(workspace, client)
+	assert.EqualError(t, err, `invalid controller.devfile.io/idle-timeout annotation "two hours": must be a duration, e.g. 30m`)
+}
+
 func TestSetupControllerAlwaysSetsDefaultClusterRoutingSuffix(t *testing.T) {
 	setupForTest(t)
 	infrastructure.InitializeForTesting(infrastructure.OpenShiftv4)
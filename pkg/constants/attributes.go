@@ -28,6 +28,17 @@ const (
 	//                    stopped.
 	DevWorkspaceStorageTypeAttribute = "controller.devfile.io/storage-type"
 
+	// PerWorkspaceStorageSizeAttribute is an attribute applied to a DevWorkspace to override the size of the PVC
+	// provisioned for it when the "per-workspace" storage strategy is used. The value must be a valid Kubernetes
+	// quantity (e.g. "10Gi"). If set, it takes precedence over the size calculated from the workspace's volumes and
+	// over the default size defined in the DevWorkspaceOperatorConfig.
+	PerWorkspaceStorageSizeAttribute = "controller.devfile.io/storage-size"
+
+	// PerWorkspaceStorageClassAttribute is an attribute applied to a DevWorkspace to override the storage class used
+	// for the PVC provisioned for it when the "per-workspace" storage strategy is used. If empty, the storage class
+	// defined in the DevWorkspaceOperatorConfig is used.
+	PerWorkspaceStorageClassAttribute = "controller.devfile.io/storage-class"
+
 	// ExternalDevWorkspaceConfiguration is an attribute that allows for specifying an (optional) external DevWorkspaceOperatorConfig
 	// which will merged with the internal/global DevWorkspaceOperatorConfig. The DevWorkspaceOperatorConfig resulting from the merge will be used for the workspace.
 	// The fields which are set in the external DevWorkspaceOperatorConfig will overwrite those existing in the
@@ -43,10 +54,72 @@ const (
 	//         namespace: some-namespace
 	ExternalDevWorkspaceConfiguration = "controller.devfile.io/devworkspace-config"
 
+	// TemplateParametersAttribute is an attribute on DevWorkspaceTemplates that declares the parameters accepted by the
+	// template. Parameters are referenced in the template using the devfile variable syntax (e.g. "{{team}}") and are
+	// substituted when the DevWorkspace referencing the template is flattened. The value of the attribute is a list of
+	// parameters:
+	//
+	//   attributes:
+	//     controller.devfile.io/template-parameters:
+	//       - name: team
+	//         description: Name of the team using the workspace
+	//         required: true
+	//         pattern: "[a-z]+"
+	//       - name: jdk-version
+	//         default: "17"
+	//         enum: ["11", "17", "21"]
+	TemplateParametersAttribute = "controller.devfile.io/template-parameters"
+
+	// TemplateParameterValuesAttribute is an attribute that provides values for the parameters of a DevWorkspaceTemplate.
+	// It is applied to plugin components to provide values for the plugin's template, and to the DevWorkspace itself
+	// to provide values for the DevWorkspace's parent. The value of the attribute is a map of parameter names to values:
+	//
+	//   attributes:
+	//     controller.devfile.io/template-parameter-values:
+	//       team: payments
+	TemplateParameterValuesAttribute = "controller.devfile.io/template-parameter-values"
+
 	// RuntimeClassNameAttribute is an attribute added to a DevWorkspace to specify a runtimeClassName for container
 	// components in the DevWorkspace (pod.spec.runtimeClassName). If empty, no runtimeClassName is added.
 	RuntimeClassNameAttribute = "controller.devfile.io/runtime-class"
 
+	// DeploymentStrategyAttribute is an attribute added to a DevWorkspace to override the strategy used to replace its
+	// pod when its deployment is updated or it is restarted. Valid values are "Recreate" and "RollingUpdate". If not set,
+	// the workspace.deploymentStrategy from the DevWorkspaceOperatorConfig is used. Note that the "RollingUpdate"
+	// strategy can prevent the new pod from starting if the DevWorkspace uses a ReadWriteOnce PVC and the new pod is
+	// scheduled on a different node than the existing pod.
+	DeploymentStrategyAttribute = "controller.devfile.io/deployment-strategy"
+
+	// PriorityClassNameAttribute is an attribute added to a DevWorkspace to specify the priorityClassName of its pods
+	// (pod.spec.priorityClassName), overriding the value in the DevWorkspaceOperatorConfig.
+	PriorityClassNameAttribute = "controller.devfile.io/priority-class"
+
+	// PreemptionPolicyAttribute is an attribute added to a DevWorkspace to specify the preemptionPolicy of its pods
+	// (pod.spec.preemptionPolicy), overriding the value in the DevWorkspaceOperatorConfig. Supported values are
+	// "PreemptLowerPriority" and "Never".
+	PreemptionPolicyAttribute = "controller.devfile.io/preemption-policy"
+
+	// TerminationGracePeriodAttribute is an attribute added to a DevWorkspace to specify the termination grace period
+	// of its pods in seconds (pod.spec.terminationGracePeriodSeconds), overriding the value in the
+	// DevWorkspaceOperatorConfig.
+	TerminationGracePeriodAttribute = "controller.devfile.io/termination-grace-period"
+
+	// SharedMemorySizeAttribute is an attribute added to a DevWorkspace to specify the size of the memory-backed
+	// volume mounted at /dev/shm in its containers, e.g. "2Gi", overriding the value in the DevWorkspaceOperatorConfig.
+	// The size "0" disables the volume.
+	SharedMemorySizeAttribute = "controller.devfile.io/shm-size"
+
+	// SysctlsAttribute is an attribute added to a DevWorkspace to set sysctls on its pods, as a map of sysctl names to
+	// values, e.g.
+	//
+	//   attributes:
+	//     controller.devfile.io/sysctls:
+	//       net.ipv4.ip_local_port_range: "1024 65535"
+	//
+	// Only sysctls that are considered safe by Kubernetes can be set with this attribute. They are added to the sysctls
+	// from the DevWorkspaceOperatorConfig, overriding them for the same name.
+	SysctlsAttribute = "controller.devfile.io/sysctls"
+
 	// WorkspaceEnvAttribute is an attribute that specifies a set of environment variables provided by a component
 	// that should be added to all workspace containers. The structure of the attribute value should be a list of
 	// Devfile 2.0 EnvVar, e.g.
@@ -90,6 +163,11 @@ const (
 	// was created to route to this endpoint
 	EndpointURLAttribute = "controller.devfile.io/endpoint-url"
 
+	// EndpointExposureAttribute is an attribute added to exposed endpoints by routing classes that only expose
+	// endpoints within the cluster. Its value is "internal", denoting that the endpoint URL is only resolvable
+	// via in-cluster DNS and that no Ingress or Route was created for it.
+	EndpointExposureAttribute = "controller.devfile.io/endpoint-exposure"
+
 	// ContainerContributionAttribute defines a container component as a container contribution that should be merged
 	// into an existing container in the devfile if possible. If no suitable container exists, this component
 	// is treated as a regular container component
@@ -142,6 +220,75 @@ const (
 	//         image: ...
 	ContainerOverridesAttribute = "container-overrides"
 
+	// EndpointProbeAttribute is an attribute applied to an endpoint of a container component to configure the
+	// readiness, liveness and/or startup probes generated for the container from the endpoint. HTTP endpoints are
+	// checked with an HTTP GET request to the probe's path, defaulting to the endpoint's path; other endpoints are
+	// checked by opening a TCP connection. If types is not specified, only a readiness probe is generated. Each type
+	// of probe may only be generated from one endpoint per container.
+	//
+	// Without this attribute, a readiness probe that opens a TCP connection to the target port of a container's first
+	// endpoint is generated, unless another endpoint generates a readiness probe. Setting the attribute to false
+	// prevents generating probes from an endpoint, e.g. for endpoints of servers that the user starts later, and true
+	// generates a readiness probe with the default settings. For example:
+	//
+	//   endpoints:
+	//     - name: language-server
+	//       targetPort: 3000
+	//       protocol: http
+	//       attributes:
+	//         controller.devfile.io/probe:
+	//           types: [readiness, liveness]
+	//           path: /healthz
+	//           periodSeconds: 10
+	//           failureThreshold: 3
+	EndpointProbeAttribute = "controller.devfile.io/probe"
+
+	// IdleWarningPathAttribute is an attribute applied to an http endpoint of a container component to have the
+	// DevWorkspace Operator notify the DevWorkspace before stopping it for inactivity (see
+	// DevWorkspaceLastActivityAnnotation). The operator sends a POST request to the endpoint's port on the
+	// DevWorkspace's service, with the attribute's value as path. For example:
+	//
+	//   endpoints:
+	//     - name: ide
+	//       targetPort: 3100
+	//       attributes:
+	//         controller.devfile.io/idle-warning-path: /api/idle-warning
+	IdleWarningPathAttribute = "controller.devfile.io/idle-warning-path"
+
+	// BackgroundComponentAttribute is an attribute applied to a container component to run that container in a
+	// separate deployment that keeps running after the DevWorkspace is stopped, e.g. to finish a long-running test
+	// run. The background deployment is removed once the DevWorkspace has been stopped for longer than the idle
+	// timeout defined by the BackgroundIdleTimeoutAttribute. For example:
+	//
+	//   components:
+	//     - name: test-runner
+	//       attributes:
+	//         controller.devfile.io/run-in-background: true
+	//         controller.devfile.io/background-idle-timeout: 4h
+	//       container:
+	//         image: ...
+	BackgroundComponentAttribute = "controller.devfile.io/run-in-background"
+
+	// BackgroundIdleTimeoutAttribute is an attribute applied to a container component that uses the
+	// BackgroundComponentAttribute to specify how long its background deployment should keep running after the
+	// DevWorkspace is stopped. The value must be a duration, e.g. "30m" or "4h". If multiple background components
+	// define a timeout, the longest timeout is used. If not specified, a timeout of 1h is used.
+	BackgroundIdleTimeoutAttribute = "controller.devfile.io/background-idle-timeout"
+
+	// StandbyComponentAttribute is an attribute applied to a container component (usually the editor) to keep its
+	// container running with minimal resources in a standby deployment when the DevWorkspace is stopped due to
+	// inactivity. When the DevWorkspace is restarted, it is preferably scheduled on the node running the standby
+	// deployment, so that it starts faster. The resources of standby containers and how long the standby deployment
+	// is kept are defined by config.workspace.standby in the DevWorkspaceOperatorConfig. For example:
+	//
+	//   components:
+	//     - name: editor
+	//       attributes:
+	//         controller.devfile.io/standby: true
+	//       container:
+	//         image: ...
+	StandbyComponentAttribute = "controller.devfile.io/standby"
+
 	// StarterProjectAttribute is an attribute applied to the top-level attributes in a DevWorkspace to specify which
 	// starterProject in the workspace should be cloned.
 	StarterProjectAttribute = "controller.devfile.io/use-starter-project"
@@ -151,4 +298,103 @@ const (
 	// of a cloned project. If the bootstrap process is successful, project-clone will automatically remove this attribute
 	// from the DevWorkspace
 	BootstrapDevWorkspaceAttribute = "controller.devfile.io/bootstrap-devworkspace"
+
+	// RestoreFromSnapshotAttribute is an attribute applied to the top-level attributes in a DevWorkspace to specify the
+	// name of a DevWorkspaceSnapshot, in the same namespace, whose data should be used to populate the DevWorkspace's
+	// persistent storage when it is first started. The snapshot must be in the "Ready" phase before the DevWorkspace
+	// can start.
+	RestoreFromSnapshotAttribute = "controller.devfile.io/restore-from-snapshot"
+
+	// CloneStorageFromAttribute is an attribute applied to the top-level attributes in a DevWorkspace to specify the
+	// name of another DevWorkspace, in the same namespace, whose persistent data should be copied into the
+	// DevWorkspace's storage when it is first started. It is set by the controller on DevWorkspaces cloned using the
+	// DevWorkspaceCloneToAnnotation. The source DevWorkspace must be stopped while its data is copied.
+	CloneStorageFromAttribute = "controller.devfile.io/clone-storage-from"
+
+	// DevWorkspaceProtectedAttribute is an attribute applied to the DevWorkspace template. If set to true, deleting the
+	// DevWorkspace or the PVC that stores its data is rejected by the webhook server. The annotation
+	// DevWorkspaceProtectedAnnotation can be used to the same effect without modifying the DevWorkspace spec.
+	DevWorkspaceProtectedAttribute = "controller.devfile.io/protected"
+
+	// PinImageDigestsAttribute is an attribute applied to the top-level attributes in a DevWorkspace to override the
+	// workspace.pinImageDigests setting in the DevWorkspaceOperatorConfig for that DevWorkspace. If set to true, the tags
+	// of workspace container images are resolved to digests when the DevWorkspace is started.
+	PinImageDigestsAttribute = "controller.devfile.io/pin-image-digests"
+
+	// ImageStreamTagAttribute is an attribute applied to a container component in a DevWorkspace to use the image of an
+	// OpenShift ImageStreamTag for that container. The value should be formatted as "<name>:<tag>" for ImageStreamTags
+	// in the DevWorkspace's namespace or "<namespace>/<name>:<tag>" otherwise. When this attribute is set, the image
+	// field of the container component is replaced by the ImageStreamTag's internal registry pullspec. This attribute is
+	// only supported on OpenShift.
+	ImageStreamTagAttribute = "controller.devfile.io/image-stream-tag"
+
+	// ExtendedResourcesAttribute is an attribute applied to a container component in a DevWorkspace to request
+	// extended resources (e.g. GPUs) or hugepages for that container, as a map of resource names to quantities, e.g.
+	//
+	//   components:
+	//     - name: tools
+	//       attributes:
+	//         controller.devfile.io/extended-resources:
+	//           nvidia.com/gpu: 1
+	//           hugepages-2Mi: 512Mi
+	//
+	// Each resource is added to both the requests and the limits of the container, as Kubernetes does not allow
+	// overcommitting extended resources or hugepages. CPU and memory cannot be set with this attribute. If a resource
+	// is listed in workspace.gpuProfile.resourceNames in the DevWorkspaceOperatorConfig, the node selector and
+	// tolerations of the GPU profile are added to the DevWorkspace's pod.
+	ExtendedResourcesAttribute = "controller.devfile.io/extended-resources"
+
+	// WeeklyRunningBudgetAttribute is an attribute applied to the top-level attributes in a DevWorkspace to limit how
+	// long the DevWorkspace may run each week, e.g. "10h". If workspace.runningBudget.weekly is also set in the
+	// DevWorkspaceOperatorConfig, the smaller of the two budgets is used.
+	WeeklyRunningBudgetAttribute = "controller.devfile.io/weekly-running-budget"
+
+	// HeadlessCommandAttribute is an attribute applied to the top-level attributes in a DevWorkspace to run it
+	// headless: once the DevWorkspace is running, the exec command with the ID specified by this attribute is run in
+	// the DevWorkspace's pod, and the DevWorkspace is stopped when the command completes. The command's exit code is
+	// recorded in the DevWorkspaceHeadlessExitCodeAnnotation and its output is written to the ConfigMap named
+	// "<workspace ID>-headless-output".
+	HeadlessCommandAttribute = "controller.devfile.io/headless-command"
+
+	// HeadlessTimeoutAttribute is an attribute applied to the top-level attributes in a DevWorkspace with the
+	// HeadlessCommandAttribute to limit how long the command may run for, e.g. "1h". Defaults to 10 minutes.
+	HeadlessTimeoutAttribute = "controller.devfile.io/headless-timeout"
+
+	// VolumeAccessModeAttribute is an attribute applied to a volume component in a DevWorkspace to request an access
+	// mode for the volume, e.g. "ReadWriteMany". Volumes that request an access mode other than ReadWriteOnce are
+	// always provisioned on a dedicated PVC (see DedicatedPVCAttribute), and the access mode must be listed for the
+	// storage class used by the DevWorkspace in workspace.storageAccessModes in the DevWorkspaceOperatorConfig. This
+	// attribute is only supported with the "common", "per-user" and "per-workspace" storage types.
+	VolumeAccessModeAttribute = "controller.devfile.io/access-mode"
+
+	// DedicatedPVCAttribute is an attribute applied to a volume component in a DevWorkspace to provision the volume on
+	// its own PVC, named "<workspace ID>-<volume name>", instead of on a subpath of the DevWorkspace's PVC. If the
+	// volume defines a size, it is used as the size of the PVC. Dedicated PVCs are deleted along with the DevWorkspace
+	// unless the DevWorkspace has the DevWorkspaceRetainStorageAnnotation, and are not included in snapshots or clones
+	// of the DevWorkspace's storage.
+	DedicatedPVCAttribute = "controller.devfile.io/dedicated-pvc"
+
+	// VolumeSubPathAttribute is an attribute applied to a volume component in a DevWorkspace to set the path within
+	// the volume's PVC where the volume's data is stored. The path must be relative and must not contain "..". By
+	// default, volumes are stored in a directory named after the volume (prefixed by the workspace ID when using the
+	// "common" storage type), and volumes on a dedicated PVC are stored at the root of the PVC.
+	VolumeSubPathAttribute = "controller.devfile.io/subpath"
+
+	// PersistUserHomeAttribute is an attribute applied to the top-level attributes in a DevWorkspace to enable ("true")
+	// or disable ("false") persisting the /home/user/ directory for that DevWorkspace, overriding the
+	// workspace.persistUserHome.enabled setting in the DevWorkspaceOperatorConfig.
+	PersistUserHomeAttribute = "controller.devfile.io/persist-user-home"
+
+	// DotfilesRepositoryAttribute is an attribute applied to the top-level attributes in a DevWorkspace to set the git
+	// repository dotfiles are installed from, overriding the repository configured in the DevWorkspace's namespace.
+	// Dotfiles are only installed if enabled in the DevWorkspaceOperatorConfig and if the DevWorkspace persists the
+	// /home/user/ directory.
+	DotfilesRepositoryAttribute = "controller.devfile.io/dotfiles-repository"
+
+	// KubernetesComponentDigestAttribute is an attribute applied to a Kubernetes or OpenShift component that defines
+	// its objects via a URI. It is set by the DevWorkspace webhook server to the digest ("sha256:<hex>") of the manifest
+	// that was reviewed when the component was added or changed, and cannot be set by users. The DevWorkspace Operator
+	// only creates objects from a URI if the content served at the URI matches this digest. Components contributed by
+	// plugins or the parent without this attribute are reviewed by the controller instead.
+	KubernetesComponentDigestAttribute = "controller.devfile.io/manifest-digest"
 )
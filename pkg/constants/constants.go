@@ -39,6 +39,10 @@ const (
 
 	HomeUserDirectory = "/home/user/"
 
+	// HomeInitDirectory is where the persistent home volume is mounted in the init container that initializes it, so
+	// that the image's home directory remains visible to the init container.
+	HomeInitDirectory = "/home/persistent-home/"
+
 	HomeVolumeName = "persistent-home"
 
 	HomeInitComponentName = "init-persistent-home"
@@ -47,6 +51,14 @@ const (
 
 	SshAgentStartEventId = "init-ssh-agent-command"
 
+	SSHKeysInitContainerName = "init-ssh-keys"
+
+	SSHAgentContainerName = "ssh-agent"
+
+	// KubernetesAPIProxyContainerName is the name of the sidecar that exposes a restricted Kubernetes API proxy to
+	// workspace containers
+	KubernetesAPIProxyContainerName = "kube-api-proxy"
+
 	ServiceAccount = "devworkspace"
 
 	PVCStorageSize = "10Gi"
@@ -69,6 +81,49 @@ const (
 	// PVCCleanupPodCPURequest is the cpu request used for PVC clean up pods
 	PVCCleanupPodCPURequest = "5m"
 
+	// ProjectBackupSecretName is the name of a secret in a DevWorkspace's namespace that configures the bucket and
+	// credentials used to back up the DevWorkspace's projects. It overrides the bucket and credentials set in the
+	// DevWorkspaceOperatorConfig.
+	ProjectBackupSecretName = "devworkspace-project-backup"
+
+	// DotfilesConfigName is the name of a secret or configmap in a DevWorkspace's namespace that configures the
+	// repository dotfiles are installed from, using the keys "repository", "ref" and "installScript". A secret takes
+	// precedence over a configmap with the same name.
+	DotfilesConfigName = "devworkspace-dotfiles"
+
+	// GuestSessionQuotaName is the name of the ResourceQuota created in namespaces generated for
+	// DevWorkspaceGuestSessions
+	GuestSessionQuotaName = "devworkspace-guest-quota"
+
+	// UserNamespaceQuotaName is the name of the ResourceQuota created in namespaces provisioned for users
+	UserNamespaceQuotaName = "devworkspace-user-quota"
+
+	// UserNamespaceLimitRangeName is the name of the LimitRange created in namespaces provisioned for users
+	UserNamespaceLimitRangeName = "devworkspace-user-limits"
+
+	// UserNamespaceNetworkPolicyName is the name of the NetworkPolicy created in namespaces provisioned for users
+	UserNamespaceNetworkPolicyName = "devworkspace-user-network-policy"
+
+	// UserNamespaceRoleBindingName is the name of the RoleBinding that grants a user access to the namespace
+	// provisioned for them
+	UserNamespaceRoleBindingName = "devworkspace-user"
+
+	// WorkspaceNetworkPolicyName is the name of the NetworkPolicy that isolates workspace pods, created in namespaces
+	// that contain DevWorkspaces when network isolation is enabled
+	WorkspaceNetworkPolicyName = "devworkspace-network-isolation"
+
+	// SnapshotPodMemoryLimit is the memory limit used for pods that archive or restore DevWorkspace snapshots
+	SnapshotPodMemoryLimit = "256Mi"
+
+	// SnapshotPodMemoryRequest is the memory request used for pods that archive or restore DevWorkspace snapshots
+	SnapshotPodMemoryRequest = "64Mi"
+
+	// SnapshotPodCPULimit is the cpu limit used for pods that archive or restore DevWorkspace snapshots
+	SnapshotPodCPULimit = "500m"
+
+	// SnapshotPodCPURequest is the cpu request used for pods that archive or restore DevWorkspace snapshots
+	SnapshotPodCPURequest = "50m"
+
 	// Constants describing storage classes supported by the controller
 
 	// CommonStorageClassType defines the 'common' storage policy, which is an alias of the 'per-user' storage policy, and operates in the same fashion as the 'per-user' storage policy.
@@ -39,4 +39,12 @@ const (
 	DevWorkspaceComponentName = "DEVWORKSPACE_COMPONENT_NAME"
 	DISPLAY                   = "DISPLAY"
 	SSHAskPass                = "SSH_ASKPASS"
+
+	// DevWorkspaceDebug contains env var name which is set to "true" in all containers of a DevWorkspace with the
+	// DevWorkspaceDebugStartAnnotation, so that tools in the containers can enable verbose logging
+	DevWorkspaceDebug = "DEVWORKSPACE_DEBUG"
+
+	// GitTrace contains env var name which enables tracing of git commands, e.g. when cloning projects. It is set for
+	// DevWorkspaces with the DevWorkspaceDebugStartAnnotation.
+	GitTrace = "GIT_TRACE"
 )
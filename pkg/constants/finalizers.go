@@ -29,4 +29,14 @@ const (
 	// serviceaccount is added to the workspace rolebinding, it is necessary to remove it
 	// when a workspace is deleted
 	RBACCleanupFinalizer = "rbac.controller.devfile.io"
+	// GuestSessionCleanupFinalizer is used to block DevWorkspaceGuestSession deletion until
+	// the namespace generated for the session is deleted.
+	GuestSessionCleanupFinalizer = "guestsession.controller.devfile.io"
+	// WorkshopCleanupFinalizer is used to block DevWorkspaceWorkshop deletion until the
+	// namespaces created for its participants are deleted.
+	WorkshopCleanupFinalizer = "workshop.controller.devfile.io"
+	// FinalizerHookPrefix is the prefix of finalizers used to block DevWorkspace deletion until
+	// a finalizer hook configured in the DevWorkspaceOperatorConfig has succeeded. The full
+	// finalizer is the prefix followed by the name of the hook.
+	FinalizerHookPrefix = "hooks.controller.devfile.io/"
 )
@@ -31,6 +31,15 @@ const (
 	// DevWorkspaceCreatorLabel is the label key for storing the UID of the user who created the workspace
 	DevWorkspaceCreatorLabel = "controller.devfile.io/creator"
 
+	// DevWorkspaceCreatorUsernameAnnotation is the annotation key for storing the name of the user who created the
+	// workspace. Like DevWorkspaceCreatorLabel, it is set when the workspace is created and cannot be changed.
+	DevWorkspaceCreatorUsernameAnnotation = "controller.devfile.io/creator-username"
+
+	// DevWorkspaceLastActorAnnotation is the annotation key for storing the name of the user whose request last
+	// created, started or stopped the workspace. It is set by the DevWorkspace webhook server and recorded in the
+	// audit log.
+	DevWorkspaceLastActorAnnotation = "controller.devfile.io/last-actor"
+
 	// DevWorkspaceNameLabel is the label key to store workspace name
 	DevWorkspaceNameLabel = "controller.devfile.io/devworkspace_name"
 
@@ -83,6 +92,12 @@ const (
 	// If the git host is not defined then the certificate will be used for all http repositories.
 	DevWorkspaceGitTLSLabel = "controller.devfile.io/git-tls-credential"
 
+	// DevWorkspaceSSHKeyLabel is the label key to specify if the secret contains SSH keys that should be provided to
+	// workspaces in its namespace. All keys in the secret (except for the optional passphrase) are copied to ~/.ssh in
+	// workspace containers, so key names must be unique across all such secrets in a namespace. Files ending in .pub
+	// are made world-readable; all other files are only readable by the workspace user.
+	DevWorkspaceSSHKeyLabel = "controller.devfile.io/ssh-key"
+
 	// GitCredentialsConfigMapName is the name used for the configmap that stores the Git configuration for workspaces
 	// in a given namespace. It is used when e.g. adding Git credentials via secret
 	GitCredentialsConfigMapName = "devworkspace-gitconfig"
@@ -131,16 +146,317 @@ const (
 	// this annotation will be cleared
 	DevWorkspaceStopReasonAnnotation = "controller.devfile.io/stopped-by"
 
+	// DevWorkspaceStoppedByInactivity is the value of the DevWorkspaceStopReasonAnnotation set on DevWorkspaces that
+	// are stopped after being idle
+	DevWorkspaceStoppedByInactivity = "inactivity"
+
+	// DevWorkspaceStoppedByHeadlessRun is the value of the DevWorkspaceStopReasonAnnotation set on DevWorkspaces with
+	// the HeadlessCommandAttribute that are stopped once their command completes
+	DevWorkspaceStoppedByHeadlessRun = "headless-run"
+
+	// DevWorkspaceHeadlessExitCodeAnnotation is set by the DevWorkspace Operator on a DevWorkspace with the
+	// HeadlessCommandAttribute once its command completes, and holds the command's exit code. It is not set if the
+	// command did not complete, e.g. as it timed out; the DevWorkspaceHeadlessResultAnnotation explains why.
+	DevWorkspaceHeadlessExitCodeAnnotation = "controller.devfile.io/headless-exit-code"
+
+	// DevWorkspaceHeadlessResultAnnotation is set by the DevWorkspace Operator on a DevWorkspace with the
+	// HeadlessCommandAttribute once its command completes, and holds a user-readable description of the result.
+	DevWorkspaceHeadlessResultAnnotation = "controller.devfile.io/headless-result"
+
+	// DevWorkspaceHeadlessTaskLabel is applied to DevWorkspaceTasks created by the controller to run the command from
+	// the HeadlessCommandAttribute of a DevWorkspace, along with the DevWorkspaceIDLabel of the DevWorkspace.
+	DevWorkspaceHeadlessTaskLabel = "controller.devfile.io/headless-run"
+
+	// DevWorkspaceLastActivityAnnotation records the last time a user was active in a DevWorkspace, as an RFC 3339
+	// timestamp. It is updated by editors and other clients of the DevWorkspace. Running DevWorkspaces with this
+	// annotation are stopped by the DevWorkspace Operator once they have been idle for the configured idle timeout;
+	// updating the annotation keeps the DevWorkspace running.
+	DevWorkspaceLastActivityAnnotation = "controller.devfile.io/last-activity"
+
+	// DevWorkspaceIdleTimeoutAnnotation overrides the idle timeout from the operator configuration for a DevWorkspace.
+	// Its value is a duration, e.g. "30m"; a zero duration disables stopping the DevWorkspace when idle. It is set to
+	// the configured idle timeout by the DevWorkspace webhook server when a DevWorkspace is created without it.
+	DevWorkspaceIdleTimeoutAnnotation = "controller.devfile.io/idle-timeout"
+
+	// DevWorkspaceIdleStopAtAnnotation is set by the DevWorkspace Operator on an idle DevWorkspace shortly before
+	// stopping it for inactivity, so that editors can warn users. Its value is the RFC 3339 timestamp at which the
+	// DevWorkspace will be stopped. It is removed if activity is recorded in the DevWorkspaceLastActivityAnnotation
+	// before then.
+	DevWorkspaceIdleStopAtAnnotation = "controller.devfile.io/idle-stop-at"
+
+	// DevWorkspaceStoppedByBulkOperation is the value of the DevWorkspaceStopReasonAnnotation set on DevWorkspaces that
+	// are stopped by a DevWorkspaceBulkOperation
+	DevWorkspaceStoppedByBulkOperation = "bulk-operation"
+
+	// DevWorkspaceStoppedByWarmPool is the value of the DevWorkspaceStopReasonAnnotation set on DevWorkspaces that
+	// are stopped after being prepared for a warm pool
+	DevWorkspaceStoppedByWarmPool = "warm-pool"
+
+	// DevWorkspaceStoppedByMaintenance is the value of the DevWorkspaceStopReasonAnnotation set on DevWorkspaces that
+	// are stopped because the cluster is in maintenance mode
+	DevWorkspaceStoppedByMaintenance = "maintenance"
+
+	// DevWorkspaceMaintenanceStopAtAnnotation is set by the DevWorkspace Operator on a running DevWorkspace when
+	// maintenance mode is enabled with a drain window. Its value is the RFC 3339 timestamp at which the DevWorkspace
+	// will be stopped. It is removed if maintenance mode is disabled before then.
+	DevWorkspaceMaintenanceStopAtAnnotation = "controller.devfile.io/maintenance-stop-at"
+
+	// DevWorkspaceProtectedAnnotation protects a DevWorkspace from deletion if set to "true". Deleting a protected
+	// DevWorkspace, or the PVC that stores its data, is rejected by the webhook server until this annotation is
+	// removed. See also DevWorkspaceProtectedAttribute.
+	DevWorkspaceProtectedAnnotation = "controller.devfile.io/protected"
+
 	// DevWorkspaceDebugStartAnnotation enables debugging workspace startup if set to "true". If a workspace with this annotation
 	// fails to start (i.e. enters the "Failed" phase), its deployment will not be scaled down in order to allow viewing logs, etc.
+	// Additionally, verbose logging is enabled in the workspace's containers through environment variables, the workspace is
+	// not stopped when idle, and the names of the workspace's pods are listed in its DebugStart condition.
 	DevWorkspaceDebugStartAnnotation = "controller.devfile.io/debug-start"
 
+	// DevWorkspaceRetainStorageAnnotation can be set to "true" on a DevWorkspace that uses the "per-workspace" storage
+	// strategy to keep its PVC when the DevWorkspace is deleted. When set, the DevWorkspace is not added as an owner of
+	// the PVC, and the PVC must be cleaned up manually.
+	DevWorkspaceRetainStorageAnnotation = "controller.devfile.io/retain-storage"
+
+	// DevWorkspaceBackgroundIDLabel is applied to pods of the background deployment of a DevWorkspace (see
+	// BackgroundComponentAttribute) instead of the DevWorkspaceIDLabel, so that they are not selected by the
+	// DevWorkspace's main deployment. Its value is the DevWorkspace ID.
+	DevWorkspaceBackgroundIDLabel = "controller.devfile.io/background-devworkspace_id"
+
+	// DevWorkspaceBackgroundIdleTimeoutAnnotation is applied to the background deployment of a DevWorkspace to
+	// store how long it should keep running after the DevWorkspace is stopped.
+	DevWorkspaceBackgroundIdleTimeoutAnnotation = "controller.devfile.io/background-idle-timeout"
+
+	// DevWorkspaceStandbyIDLabel is applied to pods of the standby deployment of a DevWorkspace (see
+	// StandbyComponentAttribute) instead of the DevWorkspaceIDLabel, so that they are not selected by the
+	// DevWorkspace's main deployment. Its value is the DevWorkspace ID.
+	DevWorkspaceStandbyIDLabel = "controller.devfile.io/standby-devworkspace_id"
+
+	// DevWorkspaceStandbyTimeoutAnnotation is applied to the standby deployment of a DevWorkspace to store how long
+	// it should keep running after the DevWorkspace is stopped.
+	DevWorkspaceStandbyTimeoutAnnotation = "controller.devfile.io/standby-timeout"
+
+	// DevWorkspaceStandbyNodeAnnotation is applied to a DevWorkspace that is started while its standby deployment is
+	// running, and stores the name of the node running the standby pod. The DevWorkspace's pod is preferably scheduled
+	// on this node. The annotation is removed when the DevWorkspace is stopped.
+	DevWorkspaceStandbyNodeAnnotation = "controller.devfile.io/standby-node"
+
+	// DevWorkspaceEnvironmentAnnotation is applied to started DevWorkspaces to store the proxy configuration and a hash
+	// of the trusted CA certificates that the DevWorkspace's pod uses. It is removed when the DevWorkspace is stopped.
+	DevWorkspaceEnvironmentAnnotation = "controller.devfile.io/environment"
+
+	// DevWorkspaceEnvironmentHashAnnotation is applied to the pod template of a DevWorkspace's deployment to store the
+	// hash from the DevWorkspaceEnvironmentAnnotation, so that the pod is restarted when the hash is updated.
+	DevWorkspaceEnvironmentHashAnnotation = "controller.devfile.io/environment-hash"
+
+	// DevWorkspaceOperatorVersionAnnotation is applied to DevWorkspaces while they are running to store the version of
+	// the DevWorkspace Operator that last reconciled them. After an operator upgrade, running DevWorkspaces whose objects
+	// were generated in a shape that is no longer used are reconciled again until this annotation is updated.
+	DevWorkspaceOperatorVersionAnnotation = "controller.devfile.io/operator-version"
+
+	// DevWorkspaceSpecHashAnnotation is applied to DevWorkspaces while they are running to store a hash of the
+	// flattened DevWorkspace that they were last reconciled with.
+	DevWorkspaceSpecHashAnnotation = "controller.devfile.io/flattened-spec-hash"
+
+	// DevWorkspaceGitWebhookBranchAnnotation can be set on a DevWorkspace to start or restart it when commits are pushed
+	// to the branch named by its value in the repository of one of its projects. Push events are received by the Git
+	// webhook endpoint, if enabled in the global DevWorkspaceOperatorConfig.
+	DevWorkspaceGitWebhookBranchAnnotation = "controller.devfile.io/git-webhook-branch"
+
+	// DevWorkspaceGitWebhookCommitAnnotation is applied to DevWorkspaces started by a Git webhook, and to the pod
+	// template of their deployment, to store the pushed commit, so that the pod is restarted on every push.
+	DevWorkspaceGitWebhookCommitAnnotation = "controller.devfile.io/git-webhook-commit"
+
+	// DevWorkspaceGitWebhookSecretLabel marks secrets that hold the secret shared with Git providers that send webhooks
+	// for DevWorkspaces in their namespace, under the "secret" key. As with other secrets read by the controller, such
+	// secrets must also have the DevWorkspaceWatchSecretLabel.
+	DevWorkspaceGitWebhookSecretLabel = "controller.devfile.io/git-webhook-secret"
+
+	// DevWorkspaceRestartAnnotation can be set to "true" on a running DevWorkspace to restart its pod. The controller
+	// removes the annotation and records the time of the restart in the DevWorkspaceRestartedAtAnnotation. The pod is
+	// replaced using the DevWorkspace's deployment strategy, and its routing and storage are preserved.
+	DevWorkspaceRestartAnnotation = "controller.devfile.io/restart"
+
+	// DevWorkspaceRestartedAtAnnotation is applied to DevWorkspaces that were restarted through the
+	// DevWorkspaceRestartAnnotation, and to the pod template of their deployment, to store the time of the last restart
+	// in RFC3339 format, so that the pod is restarted when it is updated.
+	DevWorkspaceRestartedAtAnnotation = "controller.devfile.io/restarted-at-time"
+
+	// DevWorkspaceEnvironmentOutdatedAnnotation is set to "true" on running DevWorkspaces whose proxy configuration or
+	// trusted CA certificates have changed since they were started. It is removed when the DevWorkspace is restarted.
+	DevWorkspaceEnvironmentOutdatedAnnotation = "controller.devfile.io/environment-outdated"
+
+	// DevWorkspaceEndpointExposureAnnotation can be applied to a DevWorkspace to override the exposure of its endpoints
+	// without changing the DevWorkspace's spec. Value should be a json-encoded map of endpoint names to exposures
+	// ("public" or "internal"), e.g. '{"http-8080": "public"}'. Changes are applied to running DevWorkspaces without
+	// restarting them.
+	DevWorkspaceEndpointExposureAnnotation = "controller.devfile.io/endpoint-exposure-overrides"
+
+	// OpenShiftTrustedCABundleLabel is the label used on OpenShift to request that the cluster's trusted CA bundle is
+	// injected into a configmap. Configmaps with this label are treated as trusted CA certificates for DevWorkspaces
+	// in their namespace.
+	OpenShiftTrustedCABundleLabel = "config.openshift.io/inject-trusted-cabundle"
+
+	// DevWorkspaceStorageGCLabel is applied to jobs (and their pods) that remove data for deleted DevWorkspaces from
+	// the common PVC in a namespace. Its value is always "true".
+	DevWorkspaceStorageGCLabel = "controller.devfile.io/storage-gc"
+
+	// DevWorkspaceGuestSessionLabel is applied to namespaces and DevWorkspaces created for a DevWorkspaceGuestSession.
+	// Its value is the name of the guest session.
+	DevWorkspaceGuestSessionLabel = "controller.devfile.io/guest-session"
+
+	// DevWorkspaceGuestSessionNamespaceLabel is applied along with DevWorkspaceGuestSessionLabel to store the namespace
+	// of the guest session.
+	DevWorkspaceGuestSessionNamespaceLabel = "controller.devfile.io/guest-session-namespace"
+
+	// DevWorkspaceUserNamespaceLabel is applied to namespaces provisioned for users that create DevWorkspaces. Its
+	// value is always "true".
+	DevWorkspaceUserNamespaceLabel = "controller.devfile.io/user-namespace"
+
+	// DevWorkspaceUserNamespaceOwnerAnnotation is applied along with DevWorkspaceUserNamespaceLabel to store the name of
+	// the user a namespace was provisioned for.
+	DevWorkspaceUserNamespaceOwnerAnnotation = "controller.devfile.io/user-namespace-owner"
+
+	// DevWorkspaceWorkshopLabel is applied to namespaces and DevWorkspaces created for a DevWorkspaceWorkshop. Its value
+	// is the name of the workshop.
+	DevWorkspaceWorkshopLabel = "controller.devfile.io/workshop"
+
+	// DevWorkspaceWorkshopNamespaceLabel is applied along with DevWorkspaceWorkshopLabel to store the namespace of the
+	// workshop.
+	DevWorkspaceWorkshopNamespaceLabel = "controller.devfile.io/workshop-namespace"
+
+	// DevWorkspaceWorkshopParticipantLabel is applied along with DevWorkspaceWorkshopLabel to store the name of the
+	// workshop participant a namespace was created for.
+	DevWorkspaceWorkshopParticipantLabel = "controller.devfile.io/workshop-participant"
+
+	// DevWorkspaceCanaryLabel marks a DevWorkspace as a canary when set to "true". Feature gates configured in the
+	// canaryFeatureGates field of the DevWorkspaceOperatorConfig only apply to canary DevWorkspaces.
+	DevWorkspaceCanaryLabel = "controller.devfile.io/canary"
+
+	// DevWorkspaceFactoryURLAnnotation is an annotation applied to a DevWorkspace to specify the URL of a repository that
+	// the DevWorkspace should be created from. When this annotation is set, the DevWorkspace Operator looks for a
+	// devfile.yaml or .devfile.yaml file at the root of the repository and uses it as the DevWorkspace's template. If
+	// the URL points directly at a yaml file, that file is used instead. If no devfile is found, the default template
+	// from the DevWorkspaceOperatorConfig is used.
+	DevWorkspaceFactoryURLAnnotation = "controller.devfile.io/factory-url"
+
+	// DevWorkspaceFactoryResolvedAnnotation is applied by the controller to a DevWorkspace once the devfile referenced by
+	// the factory URL annotation has been applied to the DevWorkspace. Its value is the location of the resolved devfile,
+	// or "default" if the default template was used. Removing this annotation triggers resolving the devfile again.
+	DevWorkspaceFactoryResolvedAnnotation = "controller.devfile.io/factory-resolved-devfile"
+
+	// DevWorkspaceOCIDigestsAnnotation is applied by the controller to a DevWorkspace that uses plugins or parents
+	// stored in OCI registries (referenced by oci:// URIs). Its value is a JSON object mapping each reference to the
+	// digest of the artifact it was resolved to. Afterwards, the DevWorkspace uses the recorded digests even if tags
+	// in the registry are moved. Removing this annotation causes references to be resolved again.
+	DevWorkspaceOCIDigestsAnnotation = "controller.devfile.io/oci-digests"
+
+	// DevWorkspaceResolvedParentAnnotation is applied by the controller to a DevWorkspace that has a parent. Its value is
+	// a JSON object containing the parent reference (with defaults applied) and the sha256 digest of the parent's content
+	// when the DevWorkspace was last resolved, e.g. {"uri":"https://example.com/devfile.yaml","digest":"sha256:..."}.
+	DevWorkspaceResolvedParentAnnotation = "controller.devfile.io/resolved-parent"
+
+	// DevWorkspaceImageDigestsAnnotation is applied by the controller to a DevWorkspace when image digest pinning is
+	// enabled. Its value is a JSON object mapping each container image used by the DevWorkspace to the digest it was
+	// resolved to. Removing this annotation causes images to be resolved again the next time the DevWorkspace is started.
+	DevWorkspaceImageDigestsAnnotation = "controller.devfile.io/image-digests"
+
+	// DevWorkspaceObjectNamesAnnotation is applied by the controller to a DevWorkspace when its ID is assigned. Its value
+	// is a JSON object containing the names generated from the configured naming templates for the DevWorkspace's
+	// objects, e.g. {"deployment":"...","service":"...","routing":"...","pvc":"..."}. The recorded names are used for
+	// the lifetime of the DevWorkspace, so that changing naming templates only affects DevWorkspaces created afterwards.
+	// This annotation is set once and cannot be changed by users.
+	DevWorkspaceObjectNamesAnnotation = "controller.devfile.io/object-names"
+
 	// WebhookRestartedAtAnnotation holds the the time (unixnano) of when the webhook server was forced to restart by controller
 	WebhookRestartedAtAnnotation = "controller.devfile.io/restarted-at"
 
 	// DevWorkspaceStartedAtAnnotation holds the the time (unixnano) of when the devworkspace was started
 	DevWorkspaceStartedAtAnnotation = "controller.devfile.io/started-at"
 
+	// DevWorkspaceTrashLabel is applied to the ConfigMaps that store deleted DevWorkspaces that were moved to the trash.
+	// These ConfigMaps also have the DevWorkspaceIDLabel label with the ID of the deleted DevWorkspace.
+	DevWorkspaceTrashLabel = "controller.devfile.io/devworkspace-trash"
+
+	// DevWorkspaceTrashExpirationAnnotation is applied to trash ConfigMaps and holds the time (in RFC3339 format) after
+	// which the deleted DevWorkspace and its storage are permanently removed.
+	DevWorkspaceTrashExpirationAnnotation = "controller.devfile.io/trash-expiration"
+
+	// DevWorkspaceRestoreAnnotation can be set to "true" on a trash ConfigMap to restore the deleted DevWorkspace it
+	// stores. The DevWorkspace is recreated in a stopped state and reuses its previous storage.
+	DevWorkspaceRestoreAnnotation = "controller.devfile.io/restore"
+
+	// DevWorkspaceCloneToAnnotation can be set on a DevWorkspace to create a copy of it with the name given as the
+	// annotation's value, in the same namespace. The copy is created in a stopped state and the annotation is removed
+	// once it has been created.
+	DevWorkspaceCloneToAnnotation = "controller.devfile.io/clone-to"
+
+	// DevWorkspaceCloneStorageAnnotation can be set to "true" along with the DevWorkspaceCloneToAnnotation to also
+	// copy the DevWorkspace's persistent data to the copy when it is first started.
+	DevWorkspaceCloneStorageAnnotation = "controller.devfile.io/clone-storage"
+
+	// DevWorkspaceClonedFromAnnotation is applied by the controller to DevWorkspaces created using the
+	// DevWorkspaceCloneToAnnotation, and holds the name of the DevWorkspace they were copied from.
+	DevWorkspaceClonedFromAnnotation = "controller.devfile.io/cloned-from"
+
+	// DevWorkspaceStartupDiagnosticsAnnotation holds a summary of the pod events, container statuses and PVC states
+	// that were observed when the DevWorkspace failed to start. It is removed when the DevWorkspace is started again.
+	DevWorkspaceStartupDiagnosticsAnnotation = "controller.devfile.io/startup-diagnostics"
+
+	// DevWorkspaceStartRetriesAnnotation holds the number of times the startup of a DevWorkspace was retried after a
+	// transient failure. It is removed when the DevWorkspace is stopped.
+	DevWorkspaceStartRetriesAnnotation = "controller.devfile.io/start-retries"
+
+	// DevWorkspaceExplainAnnotation can be set to "true" on a DevWorkspace to make the controller store a
+	// human-readable explanation of how the DevWorkspace was provisioned in a ConfigMap named "<workspace-id>-explain".
+	// The ConfigMap is removed when the DevWorkspace is started without the annotation.
+	DevWorkspaceExplainAnnotation = "controller.devfile.io/explain"
+
+	// DevWorkspaceRunningBudgetUsageAnnotation holds the time a DevWorkspace with a running budget has run during the
+	// current week, as JSON. The time of the current run is added when the DevWorkspace is stopped.
+	DevWorkspaceRunningBudgetUsageAnnotation = "controller.devfile.io/running-budget-usage"
+
+	// DevWorkspaceUsageAnnotation holds the total running time and requested resources of a DevWorkspace as JSON, if
+	// usage accounting is enabled. The usage of the current run is added when the DevWorkspace is stopped.
+	DevWorkspaceUsageAnnotation = "controller.devfile.io/usage"
+
+	// DevWorkspaceStopScheduleAnnotation can be set on a DevWorkspace to stop it at scheduled times, in addition to
+	// the stop schedules in the global DevWorkspaceOperatorConfig. Its value is a cron expression, e.g. "0 20 * * *",
+	// which is evaluated in the time zone from workspace.runSchedule.timeZone.
+	DevWorkspaceStopScheduleAnnotation = "controller.devfile.io/stop-schedule"
+
+	// DevWorkspacePreStopPendingAnnotation is applied by the controller to a running DevWorkspace that defines preStop
+	// events when it is stopped, if graceful stop is enabled. Its value is the time the DevWorkspace was started
+	// (unixnano), which identifies the DevWorkspaceTasks created to run the preStop commands. The DevWorkspace's pod is
+	// not stopped while this annotation is present.
+	DevWorkspacePreStopPendingAnnotation = "controller.devfile.io/pre-stop-pending"
+
+	// DevWorkspacePreStopTaskLabel is applied to DevWorkspaceTasks created by the controller to run the commands
+	// bound to a DevWorkspace's preStop events, along with the DevWorkspaceIDLabel of the DevWorkspace.
+	DevWorkspacePreStopTaskLabel = "controller.devfile.io/pre-stop-event"
+
+	// DevWorkspacePostStopPendingAnnotation is applied by the controller to a DevWorkspace that defines postStop events
+	// when it is stopped after running. Its value is the time the DevWorkspace was started (unixnano), which identifies
+	// the DevWorkspaceTasks created to run the postStop commands once the DevWorkspace's pod has stopped.
+	DevWorkspacePostStopPendingAnnotation = "controller.devfile.io/post-stop-pending"
+
+	// DevWorkspacePostStopTaskLabel is applied to DevWorkspaceTasks created by the controller to run the commands
+	// bound to a DevWorkspace's postStop events, along with the DevWorkspaceIDLabel of the DevWorkspace.
+	DevWorkspacePostStopTaskLabel = "controller.devfile.io/post-stop-event"
+
+	// DevWorkspaceWarmPoolLabel is applied to DevWorkspaces created for a warm pool configured in the global
+	// DevWorkspaceOperatorConfig. Its value is the name of the pool.
+	DevWorkspaceWarmPoolLabel = "controller.devfile.io/warm-pool"
+
+	// DevWorkspaceWarmPoolStateLabel is applied to DevWorkspaces created for a warm pool and holds the state of the
+	// pooled DevWorkspace: "warming" while it is started for the first time, "ready" once it is stopped and can be
+	// assigned, and "assigned" after it is assigned to a user. The label is set to "assigned" by the client that
+	// assigns the DevWorkspace; assigned DevWorkspaces are no longer managed by the pool.
+	DevWorkspaceWarmPoolStateLabel = "controller.devfile.io/warm-pool-state"
+
+	// DevWorkspaceWarmPoolTemplateGenerationAnnotation is applied to DevWorkspaces created for a warm pool and holds
+	// the generation of the DevWorkspaceTemplate the DevWorkspace was created from.
+	DevWorkspaceWarmPoolTemplateGenerationAnnotation = "controller.devfile.io/warm-pool-template-generation"
+
 	// RoutingAnnotationInfix is the infix of the annotations of DevWorkspace that are passed down as annotation to the DevWorkspaceRouting objects.
 	// The full annotation name is supposed to be "<routingClass>.routing.controller.devfile.io/<anything>"
 	RoutingAnnotationInfix = ".routing.controller.devfile.io/"
@@ -168,4 +484,39 @@ const (
 	// NamespaceNodeSelectorAnnotation is an annotation applied to a namespace to configure the node selector for all workspaces
 	// in that namespace. Value should be json-encoded map[string]string
 	NamespaceNodeSelectorAnnotation = "controller.devfile.io/node-selector"
+
+	// NamespacePodAffinityAnnotation is an annotation applied to a namespace to configure the pod affinity for all workspaces
+	// in that namespace. Value should be json-encoded corev1.Affinity struct.
+	NamespacePodAffinityAnnotation = "controller.devfile.io/pod-affinity"
+
+	// ImageStreamAllowImportFromAnnotation is an annotation applied to an ImageStream to allow DevWorkspaces in other
+	// namespaces to use its tags via the controller.devfile.io/image-stream-tag attribute. The value should be a
+	// comma-separated list of namespaces, or "*" to allow all namespaces.
+	ImageStreamAllowImportFromAnnotation = "controller.devfile.io/allow-import-from"
+
+	// ImageBuildHashAnnotation is applied to BuildConfigs created for devfile image components to store a hash of
+	// the build inputs. BuildConfigs are recreated when the hash changes, so that the image is rebuilt.
+	ImageBuildHashAnnotation = "controller.devfile.io/image-build-hash"
+
+	// AttributeSchemaLabel marks a configmap in the operator's namespace as containing a JSON schema for a DevWorkspace
+	// attribute. Only configmaps with the value 'true' are used. The name of the attribute is read from the
+	// AttributeSchemaNameAnnotation annotation and the schema is read from the AttributeSchemaDataKey key.
+	AttributeSchemaLabel = "controller.devfile.io/attribute-schema"
+
+	// AttributeSchemaNameAnnotation is applied to configmaps with the AttributeSchemaLabel to define the name of
+	// the DevWorkspace attribute that is validated by the schema in the configmap, e.g. "example.com/editor-settings".
+	AttributeSchemaNameAnnotation = "controller.devfile.io/attribute-name"
+
+	// AttributeSchemaDataKey is the key in configmaps with the AttributeSchemaLabel that holds the JSON schema.
+	AttributeSchemaDataKey = "schema.json"
+
+	// DevWorkspaceShardLabel can be applied to namespaces to assign them to a specific controller shard when the
+	// controller is run as multiple shards. Its value is the index of the shard, starting from 0. Namespaces without
+	// this label (or with an invalid value) are assigned to a shard based on a hash of their name.
+	DevWorkspaceShardLabel = "controller.devfile.io/shard"
+
+	// WebhookCertsManagedLabel marks the secret holding the webhook server's serving certificate on Kubernetes as
+	// generated by the operator. Only secrets with the value 'true' are rotated by the operator; secrets created by other
+	// means, e.g. cert-manager, are left untouched.
+	WebhookCertsManagedLabel = "controller.devfile.io/webhook-certs-managed"
 )
@@ -0,0 +1,347 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package gitwebhook implements the Git webhook endpoint, which receives push events from Git providers and starts or
+// restarts the DevWorkspaces that track the pushed branch, e.g. to keep preview environments up to date. Requests are
+// authenticated using a secret shared with the Git provider, as Git providers cannot send Kubernetes bearer tokens.
+package gitwebhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// PathPrefix is the path under which Git webhooks are received. Push events for DevWorkspaces in a namespace are sent
+// to <PathPrefix><namespace>.
+const PathPrefix = "/git-webhook/"
+
+const (
+	// maxPayloadBytes is the maximum size of webhook payloads, which matches the limit used by GitHub.
+	maxPayloadBytes = 25 * 1024 * 1024
+	// secretKey is the key in webhook secrets that holds the secret shared with the Git provider.
+	secretKey = "secret"
+	// deletedCommit is the commit a branch points to after a push event when the branch was deleted.
+	deletedCommit = "0000000000000000000000000000000000000000"
+)
+
+// Server receives push events from Git providers. It implements http.Handler and is intended to be registered on the
+// controller manager's webhook server, so that it is served over TLS by the operator's manager service.
+type Server struct {
+	// Client is used to read webhook secrets and to read and update DevWorkspaces.
+	Client client.Client
+	Log    logr.Logger
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// Response is the response body for push events.
+type Response struct {
+	// Workspaces are the names of the DevWorkspaces that were started or restarted.
+	Workspaces []string `json:"workspaces"`
+}
+
+// pushEvent is the information from a push event that is needed to find the DevWorkspaces to start.
+type pushEvent struct {
+	// Ref is the Git ref that was pushed, e.g. refs/heads/main.
+	Ref string
+	// Commit is the commit the ref points to after the push.
+	Commit string
+	// RepositoryURLs are the URLs the repository can be cloned from.
+	RepositoryURLs []string
+}
+
+// provider reads webhooks from a Git provider.
+type provider struct {
+	name string
+	// eventHeader is the header that contains the event type.
+	eventHeader string
+	// pushEvent is the value of eventHeader for push events.
+	pushEvent string
+	// verify checks that a request was sent using the given secret.
+	verify func(r *http.Request, payload []byte, secret []byte) bool
+	// parse reads a push event from the payload.
+	parse func(payload []byte) (*pushEvent, error)
+}
+
+var providers = []provider{
+	{
+		name:        "GitHub",
+		eventHeader: "X-GitHub-Event",
+		pushEvent:   "push",
+		verify:      verifyHubSignature,
+		parse:       parseGitHubPush,
+	},
+	{
+		name:        "GitLab",
+		eventHeader: "X-Gitlab-Event",
+		pushEvent:   "Push Hook",
+		verify:      verifyGitLabToken,
+		parse:       parseGitLabPush,
+	},
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	webhookConfig := config.GetGlobalConfig().GitWebhook
+	if webhookConfig == nil || !pointer.BoolDeref(webhookConfig.Enable, false) {
+		http.Error(w, "git webhooks are not enabled", http.StatusNotFound)
+		return
+	}
+	namespace := strings.TrimPrefix(r.URL.Path, PathPrefix)
+	if namespace == "" || strings.Contains(namespace, "/") {
+		http.Error(w, fmt.Sprintf("expected path %s<namespace>", PathPrefix), http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+	log := s.Log.WithValues("namespace", namespace)
+
+	var gitProvider *provider
+	for idx := range providers {
+		if r.Header.Get(providers[idx].eventHeader) != "" {
+			gitProvider = &providers[idx]
+			break
+		}
+	}
+	if gitProvider == nil {
+		http.Error(w, "unsupported webhook: only GitHub and GitLab webhooks are supported", http.StatusBadRequest)
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadBytes+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(payload) > maxPayloadBytes {
+		http.Error(w, "request body is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	verified, err := s.verify(ctx, namespace, gitProvider, r, payload)
+	if err != nil {
+		log.Error(err, "Failed to read Git webhook secrets")
+		http.Error(w, "failed to verify webhook", http.StatusInternalServerError)
+		return
+	}
+	if !verified {
+		http.Error(w, "webhook could not be verified", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Header.Get(gitProvider.eventHeader) != gitProvider.pushEvent {
+		// Other events (e.g. the ping event sent by GitHub when a webhook is created) are accepted but ignored
+		writeResponse(w, &Response{Workspaces: []string{}}, log)
+		return
+	}
+	event, err := gitProvider.parse(payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid %s push event: %s", gitProvider.name, err), http.StatusBadRequest)
+		return
+	}
+	if event.Commit == deletedCommit {
+		writeResponse(w, &Response{Workspaces: []string{}}, log)
+		return
+	}
+
+	started, err := s.startWorkspaces(ctx, namespace, event, log)
+	if err != nil {
+		log.Error(err, "Failed to start DevWorkspaces for Git webhook")
+		http.Error(w, "failed to start workspaces", http.StatusInternalServerError)
+		return
+	}
+	writeResponse(w, &Response{Workspaces: started}, log)
+}
+
+// verify checks that a request was sent using one of the webhook secrets in the namespace.
+func (s *Server) verify(ctx context.Context, namespace string, gitProvider *provider, r *http.Request, payload []byte) (bool, error) {
+	secrets := &corev1.SecretList{}
+	err := s.Client.List(ctx, secrets,
+		client.InNamespace(namespace),
+		client.MatchingLabels{constants.DevWorkspaceGitWebhookSecretLabel: "true"})
+	if err != nil {
+		return false, err
+	}
+	for _, secret := range secrets.Items {
+		if value := secret.Data[secretKey]; len(value) > 0 && gitProvider.verify(r, payload, value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// startWorkspaces starts or restarts the DevWorkspaces in a namespace that track the branch of a push event and that
+// have a project cloned from the pushed repository. Returns the names of the DevWorkspaces that were updated.
+func (s *Server) startWorkspaces(ctx context.Context, namespace string, event *pushEvent, log logr.Logger) ([]string, error) {
+	branch, isBranch := strings.CutPrefix(event.Ref, "refs/heads/")
+	started := []string{}
+	if !isBranch {
+		return started, nil
+	}
+	workspaces := &dw.DevWorkspaceList{}
+	if err := s.Client.List(ctx, workspaces, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, workspace := range workspaces.Items {
+		if workspace.DeletionTimestamp != nil || workspace.Annotations[constants.DevWorkspaceGitWebhookBranchAnnotation] != branch {
+			continue
+		}
+		if !hasProjectFromRepository(&workspace, event.RepositoryURLs) {
+			continue
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := s.Client.Get(ctx, client.ObjectKeyFromObject(&workspace), &workspace); err != nil {
+				return err
+			}
+			if workspace.Annotations == nil {
+				workspace.Annotations = map[string]string{}
+			}
+			// Updating the commit restarts running DevWorkspaces, as it is copied to the pod template
+			workspace.Annotations[constants.DevWorkspaceGitWebhookCommitAnnotation] = event.Commit
+			workspace.Spec.Started = true
+			return s.Client.Update(ctx, &workspace)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start DevWorkspace %s: %w", workspace.Name, err)
+		}
+		log.Info("Starting DevWorkspace for Git push", "workspace", workspace.Name, "branch", branch, "commit", event.Commit)
+		started = append(started, workspace.Name)
+	}
+	return started, nil
+}
+
+// hasProjectFromRepository returns whether any Git remote of the DevWorkspace's projects refers to one of the
+// repository URLs.
+func hasProjectFromRepository(workspace *dw.DevWorkspace, repositoryURLs []string) bool {
+	for _, project := range workspace.Spec.Template.Projects {
+		if project.Git == nil {
+			continue
+		}
+		for _, remote := range project.Git.Remotes {
+			for _, repositoryURL := range repositoryURLs {
+				if repositoryURL != "" && normalizeRepositoryURL(remote) == normalizeRepositoryURL(repositoryURL) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// normalizeRepositoryURL returns the host and path of a Git repository URL, so that HTTP(S), SSH and scp-like URLs of
+// the same repository can be compared, e.g. https://github.com/org/repo.git and git@github.com:org/repo both become
+// github.com/org/repo.
+func normalizeRepositoryURL(repositoryURL string) string {
+	host, path := "", ""
+	if parsed, err := url.Parse(repositoryURL); err == nil && parsed.Host != "" {
+		host, path = parsed.Hostname(), parsed.Path
+	} else if at := strings.Index(repositoryURL, "@"); at >= 0 && strings.Contains(repositoryURL[at:], ":") {
+		// scp-like syntax, e.g. git@github.com:org/repo.git
+		host, path, _ = strings.Cut(repositoryURL[at+1:], ":")
+	} else {
+		return strings.ToLower(repositoryURL)
+	}
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return strings.ToLower(host + "/" + path)
+}
+
+func writeResponse(w http.ResponseWriter, response *Response, log logr.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error(err, "Failed to write Git webhook response")
+	}
+}
+
+// verifyHubSignature checks the HMAC-SHA256 signature of the payload that GitHub sends in the X-Hub-Signature-256
+// header.
+func verifyHubSignature(r *http.Request, payload []byte, secret []byte) bool {
+	signature, ok := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return false
+	}
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(decoded, mac.Sum(nil))
+}
+
+// verifyGitLabToken checks the secret token that GitLab sends in the X-Gitlab-Token header.
+func verifyGitLabToken(r *http.Request, _ []byte, secret []byte) bool {
+	token := r.Header.Get("X-Gitlab-Token")
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), secret) == 1
+}
+
+func parseGitHubPush(payload []byte) (*pushEvent, error) {
+	event := struct {
+		Ref        string `json:"ref"`
+		After      string `json:"after"`
+		Repository struct {
+			CloneURL string `json:"clone_url"`
+			SSHURL   string `json:"ssh_url"`
+			HTMLURL  string `json:"html_url"`
+		} `json:"repository"`
+	}{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &pushEvent{
+		Ref:            event.Ref,
+		Commit:         event.After,
+		RepositoryURLs: []string{event.Repository.CloneURL, event.Repository.SSHURL, event.Repository.HTMLURL},
+	}, nil
+}
+
+func parseGitLabPush(payload []byte) (*pushEvent, error) {
+	event := struct {
+		Ref     string `json:"ref"`
+		After   string `json:"after"`
+		Project struct {
+			HTTPURL string `json:"git_http_url"`
+			SSHURL  string `json:"git_ssh_url"`
+			WebURL  string `json:"web_url"`
+		} `json:"project"`
+	}{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &pushEvent{
+		Ref:            event.Ref,
+		Commit:         event.After,
+		RepositoryURLs: []string{event.Project.HTTPURL, event.Project.SSHURL, event.Project.WebURL},
+	}, nil
+}
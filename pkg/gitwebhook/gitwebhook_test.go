@@ -0,0 +1,261 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package gitwebhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testSecret    = "webhook-secret"
+	testCommit    = "4f0c8d1b2a9e7d6c5b4a39281706f5e4d3c2b1a0"
+)
+
+func getTestWorkspace(name, branch, remote string, started bool) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, "")
+	workspace.Spec.Started = started
+	workspace.Spec.Template.Projects = []dw.Project{
+		{
+			Name: "project",
+			ProjectSource: dw.ProjectSource{
+				Git: &dw.GitProjectSource{
+					GitLikeProjectSource: dw.GitLikeProjectSource{
+						Remotes: map[string]string{"origin": remote},
+					},
+				},
+			},
+		},
+	}
+	if branch != "" {
+		workspace.Annotations[constants.DevWorkspaceGitWebhookBranchAnnotation] = branch
+	}
+	return workspace
+}
+
+func getTestSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "git-webhook",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceGitWebhookSecretLabel: "true",
+				constants.DevWorkspaceWatchSecretLabel:      "true",
+			},
+		},
+		Data: map[string][]byte{
+			"secret": []byte(testSecret),
+		},
+	}
+}
+
+func setupTestServer(t *testing.T, objs ...client.Object) (*httptest.Server, client.Client) {
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		GitWebhook: &v1alpha1.GitWebhookConfig{
+			Enable: pointer.Bool(true),
+		},
+	})
+	fakeClient := testutil.NewFakeClient(objs...)
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, &Server{
+		Client: fakeClient,
+		Log:    zap.New(),
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, fakeClient
+}
+
+func getGitHubPayload(t *testing.T, ref string) []byte {
+	payload, err := json.Marshal(map[string]interface{}{
+		"ref":   ref,
+		"after": testCommit,
+		"repository": map[string]string{
+			"clone_url": "https://github.com/org/repo.git",
+			"ssh_url":   "git@github.com:org/repo.git",
+			"html_url":  "https://github.com/org/repo",
+		},
+	})
+	require.NoError(t, err)
+	return payload
+}
+
+func signPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func doRequest(t *testing.T, server *httptest.Server, method, path string, headers map[string]string, payload []byte) (int, string) {
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(payload))
+	require.NoError(t, err)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func getClusterWorkspace(t *testing.T, c client.Client, name string) *dw.DevWorkspace {
+	workspace := &dw.DevWorkspace{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: testNamespace}, workspace))
+	return workspace
+}
+
+func TestGitHubPushStartsWorkspaces(t *testing.T) {
+	server, fakeClient := setupTestServer(t,
+		getTestSecret(),
+		getTestWorkspace("preview", "main", "https://github.com/org/repo.git", false),
+		getTestWorkspace("preview-ssh", "main", "git@github.com:org/repo.git", true),
+		getTestWorkspace("other-branch", "develop", "https://github.com/org/repo.git", false),
+		getTestWorkspace("other-repo", "main", "https://github.com/org/other-repo.git", false),
+		getTestWorkspace("not-tracking", "", "https://github.com/org/repo.git", false))
+
+	payload := getGitHubPayload(t, "refs/heads/main")
+	code, body := doRequest(t, server, http.MethodPost, PathPrefix+testNamespace, map[string]string{
+		"X-GitHub-Event":      "push",
+		"X-Hub-Signature-256": signPayload(payload, testSecret),
+	}, payload)
+	require.Equal(t, http.StatusOK, code, body)
+	response := &Response{}
+	require.NoError(t, json.Unmarshal([]byte(body), response))
+	assert.ElementsMatch(t, []string{"preview", "preview-ssh"}, response.Workspaces)
+
+	for _, name := range []string{"preview", "preview-ssh"} {
+		workspace := getClusterWorkspace(t, fakeClient, name)
+		assert.True(t, workspace.Spec.Started, "Should start workspace %s", name)
+		assert.Equal(t, testCommit, workspace.Annotations[constants.DevWorkspaceGitWebhookCommitAnnotation])
+	}
+	for _, name := range []string{"other-branch", "other-repo", "not-tracking"} {
+		workspace := getClusterWorkspace(t, fakeClient, name)
+		assert.False(t, workspace.Spec.Started, "Should not start workspace %s", name)
+		assert.NotContains(t, workspace.Annotations, constants.DevWorkspaceGitWebhookCommitAnnotation)
+	}
+}
+
+func TestGitLabPushRestartsWorkspace(t *testing.T) {
+	workspace := getTestWorkspace("preview", "main", "git@gitlab.com:org/repo.git", true)
+	workspace.Annotations[constants.DevWorkspaceGitWebhookCommitAnnotation] = "previous-commit"
+	server, fakeClient := setupTestServer(t, getTestSecret(), workspace)
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"ref":   "refs/heads/main",
+		"after": testCommit,
+		"project": map[string]string{
+			"git_http_url": "https://gitlab.com/org/repo.git",
+			"git_ssh_url":  "git@gitlab.com:org/repo.git",
+			"web_url":      "https://gitlab.com/org/repo",
+		},
+	})
+	require.NoError(t, err)
+	code, body := doRequest(t, server, http.MethodPost, PathPrefix+testNamespace, map[string]string{
+		"X-Gitlab-Event": "Push Hook",
+		"X-Gitlab-Token": testSecret,
+	}, payload)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, testCommit, getClusterWorkspace(t, fakeClient, "preview").Annotations[constants.DevWorkspaceGitWebhookCommitAnnotation],
+		"Should update commit to restart running workspace")
+}
+
+func TestWebhookRequests(t *testing.T) {
+	server, fakeClient := setupTestServer(t,
+		getTestSecret(),
+		getTestWorkspace("preview", "main", "https://github.com/org/repo.git", false))
+
+	payload := getGitHubPayload(t, "refs/heads/main")
+	deletePayload := bytes.Replace(payload, []byte(testCommit), []byte(deletedCommit), 1)
+	tagPayload := getGitHubPayload(t, "refs/tags/main")
+	signed := map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": signPayload(payload, testSecret)}
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		headers      map[string]string
+		payload      []byte
+		expectedCode int
+	}{
+		{"Unsupported provider", http.MethodPost, testNamespace, map[string]string{}, payload, http.StatusBadRequest},
+		{"Missing signature", http.MethodPost, testNamespace, map[string]string{"X-GitHub-Event": "push"}, payload, http.StatusUnauthorized},
+		{"Invalid signature", http.MethodPost, testNamespace, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": signPayload(payload, "wrong-secret")}, payload, http.StatusUnauthorized},
+		{"Invalid GitLab token", http.MethodPost, testNamespace, map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": "wrong-secret"}, payload, http.StatusUnauthorized},
+		{"Namespace without secret", http.MethodPost, "other-namespace", signed, payload, http.StatusUnauthorized},
+		{"GET request", http.MethodGet, testNamespace, signed, payload, http.StatusMethodNotAllowed},
+		{"Invalid path", http.MethodPost, testNamespace + "/preview", signed, payload, http.StatusNotFound},
+		{"Ping event", http.MethodPost, testNamespace, map[string]string{"X-GitHub-Event": "ping", "X-Hub-Signature-256": signPayload([]byte("{}"), testSecret)}, []byte("{}"), http.StatusOK},
+		{"Branch deleted", http.MethodPost, testNamespace, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": signPayload(deletePayload, testSecret)}, deletePayload, http.StatusOK},
+		{"Tag pushed", http.MethodPost, testNamespace, map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": signPayload(tagPayload, testSecret)}, tagPayload, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := doRequest(t, server, tt.method, PathPrefix+tt.path, tt.headers, tt.payload)
+			assert.Equal(t, tt.expectedCode, code, strings.TrimSpace(body))
+			assert.False(t, getClusterWorkspace(t, fakeClient, "preview").Spec.Started, "Should not start workspace")
+		})
+	}
+}
+
+func TestGitWebhookDisabled(t *testing.T) {
+	server, _ := setupTestServer(t, getTestSecret())
+	config.SetGlobalConfigForTesting(nil)
+	payload := getGitHubPayload(t, "refs/heads/main")
+	code, _ := doRequest(t, server, http.MethodPost, PathPrefix+testNamespace, map[string]string{
+		"X-GitHub-Event":      "push",
+		"X-Hub-Signature-256": signPayload(payload, testSecret),
+	}, payload)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestNormalizeRepositoryURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/repo.git":     "github.com/org/repo",
+		"https://GitHub.com/Org/Repo/":        "github.com/org/repo",
+		"http://user@github.com:443/org/repo": "github.com/org/repo",
+		"ssh://git@github.com/org/repo.git":   "github.com/org/repo",
+		"git@github.com:org/repo.git":         "github.com/org/repo",
+	}
+	for repositoryURL, expected := range tests {
+		assert.Equal(t, expected, normalizeRepositoryURL(repositoryURL), repositoryURL)
+	}
+}
@@ -23,6 +23,10 @@ import (
 
 const (
 	WatchNamespaceEnvVar = "WATCH_NAMESPACE"
+	// WatchedNamespacesEnvVar is a comma-separated list of namespaces the controller should reconcile DevWorkspaces
+	// in. If set, the controller runs in namespace-scoped mode and only watches objects in these namespaces and the
+	// namespace it is installed in.
+	WatchedNamespacesEnvVar = "WATCHED_NAMESPACES"
 )
 
 // GetOperatorNamespace returns the namespace the operator should be running in.
@@ -64,3 +68,26 @@ func GetNamespace() (string, error) {
 	}
 	return ns, nil
 }
+
+// GetWatchedNamespaces returns the namespaces listed in the WATCHED_NAMESPACES environment variable, or nil if the
+// controller is not running in namespace-scoped mode.
+func GetWatchedNamespaces() []string {
+	var namespaces []string
+	seen := map[string]bool{}
+	for _, ns := range strings.Split(os.Getenv(WatchedNamespacesEnvVar), ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// IsNamespaceScoped returns true if the controller is running in namespace-scoped mode, i.e. the WATCHED_NAMESPACES
+// environment variable is set. In this mode, the controller only requires permissions in the watched namespaces and
+// its own namespace, and components that require cluster-wide permissions are disabled.
+func IsNamespaceScoped() bool {
+	return len(GetWatchedNamespaces()) > 0
+}
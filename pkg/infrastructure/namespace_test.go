@@ -0,0 +1,34 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWatchedNamespaces(t *testing.T) {
+	t.Setenv(WatchedNamespacesEnvVar, "team-a, team-b,,team-a")
+	assert.Equal(t, []string{"team-a", "team-b"}, GetWatchedNamespaces())
+	assert.True(t, IsNamespaceScoped())
+}
+
+func TestGetWatchedNamespacesUnset(t *testing.T) {
+	t.Setenv(WatchedNamespacesEnvVar, "")
+	assert.Empty(t, GetWatchedNamespaces())
+	assert.False(t, IsNamespaceScoped())
+}
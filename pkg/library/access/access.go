@@ -0,0 +1,196 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package access authenticates and authorizes users of the endpoints served by the DevWorkspace Operator that give
+// access to DevWorkspaces, such as the terminal broker.
+package access
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// BearerProtocolPrefix is the prefix of the WebSocket subprotocol used to pass a bearer token from clients that cannot
+// set the Authorization header, such as web browsers. This follows the convention used by the Kubernetes API server.
+const BearerProtocolPrefix = "base64url.bearer.authorization.k8s.io."
+
+var errNoToken = errors.New("no bearer token provided")
+
+// GetBearerToken returns the bearer token provided in the Authorization header of a request or, if the header is not
+// set, in its WebSocket subprotocols.
+func GetBearerToken(r *http.Request) (string, error) {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if token == authorization || token == "" {
+			return "", fmt.Errorf("unsupported Authorization header")
+		}
+		return token, nil
+	}
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocol = strings.TrimSpace(protocol)
+			if !strings.HasPrefix(protocol, BearerProtocolPrefix) {
+				continue
+			}
+			token, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(protocol, BearerProtocolPrefix))
+			if err != nil || len(token) == 0 {
+				return "", fmt.Errorf("invalid bearer token subprotocol")
+			}
+			return string(token), nil
+		}
+	}
+	return "", errNoToken
+}
+
+// Authenticate resolves the user a bearer token belongs to using a TokenReview. If the token is not valid, the returned
+// user is nil.
+func Authenticate(ctx context.Context, c client.Client, token string) (*authnv1.UserInfo, error) {
+	review := &authnv1.TokenReview{
+		Spec: authnv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	if err := c.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
+
+// Authorize checks whether a user may access a workspace. The creator of a workspace is always allowed to do so.
+// Other users must be allowed to perform the action described by resourceAttributes in the workspace's namespace, as
+// they would need to be to access the workspace's pod directly. If the workspace has restricted access, only members
+// of the admin groups configured in the global DevWorkspaceOperatorConfig may access it in addition to its creator;
+// being a member of an admin group does not grant access to namespaces the user could not access otherwise.
+func Authorize(ctx context.Context, c client.Client, user *authnv1.UserInfo, workspace *dw.DevWorkspace, resourceAttributes authzv1.ResourceAttributes) (bool, error) {
+	if creator := workspace.Labels[constants.DevWorkspaceCreatorLabel]; creator != "" && creator == user.UID {
+		return true, nil
+	}
+	accessControl := config.GetGlobalConfig().Workspace.AccessControl
+	if IsRestrictedAccess(workspace, accessControl) && !IsAdmin(user.Groups, accessControl) {
+		return false, nil
+	}
+	extra := map[string]authzv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authzv1.ExtraValue(value)
+	}
+	resourceAttributes.Namespace = workspace.Namespace
+	review := &authzv1.SubjectAccessReview{
+		Spec: authzv1.SubjectAccessReviewSpec{
+			User:               user.Username,
+			UID:                user.UID,
+			Groups:             user.Groups,
+			Extra:              extra,
+			ResourceAttributes: &resourceAttributes,
+		},
+	}
+	if err := c.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("failed to review access: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
+
+// AuthorizeRequest authenticates a request to a DevWorkspace's endpoint using its bearer token, reads the DevWorkspace
+// and checks that the user may access it as described for Authorize. If any of these steps fails, an error response is
+// written and nil is returned. Responses for DevWorkspaces that do not exist are the same as for unauthorized users,
+// so that it is not disclosed which DevWorkspaces exist.
+func AuthorizeRequest(w http.ResponseWriter, r *http.Request, c client.Client, namespace, name string, resourceAttributes authzv1.ResourceAttributes, log logr.Logger) (*dw.DevWorkspace, *authnv1.UserInfo) {
+	ctx := r.Context()
+	token, err := GetBearerToken(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return nil, nil
+	}
+	user, err := Authenticate(ctx, c, token)
+	if err != nil {
+		log.Error(err, "Failed to authenticate user")
+		http.Error(w, "failed to authenticate user", http.StatusInternalServerError)
+		return nil, nil
+	}
+	if user == nil {
+		http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+		return nil, nil
+	}
+	log = log.WithValues("user", user.Username)
+
+	workspace := &dw.DevWorkspace{}
+	err = c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, workspace)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		http.Error(w, "access to workspace denied", http.StatusForbidden)
+		return nil, nil
+	case err != nil:
+		log.Error(err, "Failed to read DevWorkspace")
+		http.Error(w, "failed to read workspace", http.StatusInternalServerError)
+		return nil, nil
+	}
+	allowed, err := Authorize(ctx, c, user, workspace, resourceAttributes)
+	if err != nil {
+		log.Error(err, "Failed to authorize user")
+		http.Error(w, "failed to authorize user", http.StatusInternalServerError)
+		return nil, nil
+	}
+	if !allowed {
+		log.Info("Denied access to DevWorkspace")
+		http.Error(w, "access to workspace denied", http.StatusForbidden)
+		return nil, nil
+	}
+	return workspace, user
+}
+
+// IsRestrictedAccess returns whether access to a workspace, or an object that belongs to it, is restricted to the
+// workspace's creator. This is the case if the object has the restricted-access annotation, or if all workspaces are
+// restricted by the access control configuration.
+func IsRestrictedAccess(obj metav1.Object, accessControl *controller.AccessControlConfig) bool {
+	if obj.GetAnnotations()[constants.DevWorkspaceRestrictedAccessAnnotation] == "true" {
+		return true
+	}
+	return accessControl != nil && accessControl.RestrictToCreator != nil && *accessControl.RestrictToCreator
+}
+
+// IsAdmin returns whether a user who is a member of groups may access restricted-access workspaces that they did not
+// create, according to the access control configuration.
+func IsAdmin(groups []string, accessControl *controller.AccessControlConfig) bool {
+	if accessControl == nil {
+		return false
+	}
+	for _, adminGroup := range accessControl.AdminGroups {
+		for _, group := range groups {
+			if group == adminGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package access
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetBearerToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		expectedToken string
+		expectedErr   string
+	}{
+		{
+			name:          "Authorization header",
+			headers:       map[string]string{"Authorization": "Bearer test-token"},
+			expectedToken: "test-token",
+		},
+		{
+			name:          "WebSocket subprotocol",
+			headers:       map[string]string{"Sec-WebSocket-Protocol": "channel.k8s.io, " + BearerProtocolPrefix + base64.RawURLEncoding.EncodeToString([]byte("test-token"))},
+			expectedToken: "test-token",
+		},
+		{
+			name:        "Basic authentication",
+			headers:     map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			expectedErr: "unsupported Authorization header",
+		},
+		{
+			name:        "Invalid subprotocol",
+			headers:     map[string]string{"Sec-WebSocket-Protocol": BearerProtocolPrefix + "not base64!"},
+			expectedErr: "invalid bearer token subprotocol",
+		},
+		{
+			name:        "No token",
+			headers:     map[string]string{"Sec-WebSocket-Protocol": "channel.k8s.io"},
+			expectedErr: "no bearer token provided",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for header, value := range tt.headers {
+				req.Header.Set(header, value)
+			}
+			token, err := GetBearerToken(req)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, token)
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	config.SetGlobalConfigForTesting(&controller.OperatorConfiguration{
+		Workspace: &controller.WorkspaceConfig{
+			AccessControl: &controller.AccessControlConfig{AdminGroups: []string{"workspace-admins"}},
+		},
+	})
+	defer config.SetGlobalConfigForTesting(nil)
+	execAttributes := authzv1.ResourceAttributes{Verb: "create", Resource: "pods", Subresource: "exec"}
+	c := &testutil.ReviewClient{
+		Client: fake.NewClientBuilder().Build(),
+		Permissions: map[string]authzv1.ResourceAttributes{
+			"admin":     execAttributes,
+			"exec-user": execAttributes,
+		},
+	}
+	creator := &authnv1.UserInfo{Username: "creator", UID: "creator-uid"}
+	admin := &authnv1.UserInfo{Username: "admin", UID: "admin-uid", Groups: []string{"workspace-admins"}}
+	adminWithoutAccess := &authnv1.UserInfo{Username: "other-admin", UID: "other-admin-uid", Groups: []string{"workspace-admins"}}
+	execUser := &authnv1.UserInfo{Username: "exec-user", UID: "exec-user-uid"}
+	otherUser := &authnv1.UserInfo{Username: "other-user", UID: "other-user-uid"}
+
+	tests := []struct {
+		name            string
+		user            *authnv1.UserInfo
+		restricted      bool
+		expectedAllowed bool
+	}{
+		{name: "Creator is allowed", user: creator, expectedAllowed: true},
+		{name: "User with access to namespace is allowed", user: execUser, expectedAllowed: true},
+		{name: "User without access to namespace is denied", user: otherUser, expectedAllowed: false},
+		{name: "Creator is allowed for restricted workspace", user: creator, restricted: true, expectedAllowed: true},
+		{name: "Admin is allowed for restricted workspace", user: admin, restricted: true, expectedAllowed: true},
+		{name: "Admin without access to namespace is denied for restricted workspace", user: adminWithoutAccess, restricted: true, expectedAllowed: false},
+		{name: "User with access to namespace is denied for restricted workspace", user: execUser, restricted: true, expectedAllowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &dw.DevWorkspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace",
+					Namespace: "test-namespace",
+					Labels:    map[string]string{constants.DevWorkspaceCreatorLabel: "creator-uid"},
+				},
+			}
+			if tt.restricted {
+				workspace.Annotations = map[string]string{constants.DevWorkspaceRestrictedAccessAnnotation: "true"}
+			}
+			allowed, err := Authorize(context.Background(), c, tt.user, workspace, execAttributes)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expectedAllowed, allowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package attributeschema validates DevWorkspace attributes against JSON schemas registered by cluster administrators,
+// so that errors in platform-specific attributes are caught when a DevWorkspace is created or updated instead of when
+// it is started.
+package attributeschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// Schemas maps attribute names to the schema used to validate the attribute's value.
+type Schemas map[string]*jsonschema.Schema
+
+// LoadSchemas reads attribute schemas from configmaps in a namespace that have the AttributeSchemaLabel set to
+// 'true'. Configmaps that do not define an attribute name or contain an invalid schema are skipped; an error for each
+// skipped configmap is returned in invalid.
+func LoadSchemas(ctx context.Context, reader client.Reader, namespace string) (schemas Schemas, invalid []error, err error) {
+	configmaps := &corev1.ConfigMapList{}
+	selector := client.MatchingLabels{constants.AttributeSchemaLabel: "true"}
+	if err := reader.List(ctx, configmaps, client.InNamespace(namespace), selector); err != nil {
+		return nil, nil, fmt.Errorf("failed to list attribute schemas: %w", err)
+	}
+	schemas = Schemas{}
+	for _, cm := range configmaps.Items {
+		attributeName := cm.Annotations[constants.AttributeSchemaNameAnnotation]
+		if attributeName == "" {
+			invalid = append(invalid, fmt.Errorf("configmap %s does not define an attribute name in the %s annotation", cm.Name, constants.AttributeSchemaNameAnnotation))
+			continue
+		}
+		schema, err := compileSchema(&cm)
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("configmap %s does not contain a valid schema for attribute %s: %w", cm.Name, attributeName, err))
+			continue
+		}
+		if _, exists := schemas[attributeName]; exists {
+			invalid = append(invalid, fmt.Errorf("configmap %s defines a schema for attribute %s, which already has a schema", cm.Name, attributeName))
+			continue
+		}
+		schemas[attributeName] = schema
+	}
+	return schemas, invalid, nil
+}
+
+func compileSchema(cm *corev1.ConfigMap) (*jsonschema.Schema, error) {
+	schemaJSON, ok := cm.Data[constants.AttributeSchemaDataKey]
+	if !ok {
+		return nil, fmt.Errorf("key %s is not set", constants.AttributeSchemaDataKey)
+	}
+	url := fmt.Sprintf("configmap:///%s/%s", cm.Namespace, cm.Name)
+	compiler := jsonschema.NewCompiler()
+	// Schemas must be self-contained; don't allow references to read files or other resources
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("loading external schema %s is not supported", s)
+	}
+	if err := compiler.AddResource(url, strings.NewReader(schemaJSON)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(url)
+}
+
+// ValidateAttributes validates each attribute that has a schema. If changedOnly is not nil, only attributes whose
+// values differ from changedOnly are validated, so that invalid attributes that are already present can be left as-is.
+// Returns an error describing all invalid attributes.
+func ValidateAttributes(attrs attributes.Attributes, schemas Schemas, changedOnly attributes.Attributes) error {
+	var attributeErrors []string
+	for name, value := range attrs {
+		schema, ok := schemas[name]
+		if !ok {
+			continue
+		}
+		if changedOnly != nil {
+			if oldValue, exists := changedOnly[name]; exists && string(oldValue.Raw) == string(value.Raw) {
+				continue
+			}
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(value.Raw, &decoded); err != nil {
+			attributeErrors = append(attributeErrors, fmt.Sprintf("attribute %s is not valid JSON: %s", name, err))
+			continue
+		}
+		if err := schema.Validate(decoded); err != nil {
+			attributeErrors = append(attributeErrors, fmt.Sprintf("attribute %s is invalid: %s", name, formatValidationError(err)))
+		}
+	}
+	if len(attributeErrors) == 0 {
+		return nil
+	}
+	sort.Strings(attributeErrors)
+	return fmt.Errorf("%s", strings.Join(attributeErrors, "; "))
+}
+
+// formatValidationError returns the messages of the leaf causes of a validation error, prefixed with the location
+// in the attribute value that they refer to.
+func formatValidationError(err error) string {
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err.Error()
+	}
+	var messages []string
+	var collect func(ve *jsonschema.ValidationError)
+	collect = func(ve *jsonschema.ValidationError) {
+		if len(ve.Causes) == 0 {
+			location := ve.InstanceLocation
+			if location == "" {
+				location = "/"
+			}
+			messages = append(messages, fmt.Sprintf("%s: %s", location, ve.Message))
+			return
+		}
+		for _, cause := range ve.Causes {
+			collect(cause)
+		}
+	}
+	collect(validationErr)
+	return strings.Join(messages, ", ")
+}
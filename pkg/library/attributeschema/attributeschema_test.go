@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package attributeschema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	testNamespace = "devworkspace-controller"
+
+	editorSettingsSchema = `{
+  "type": "object",
+  "properties": {
+    "theme": {"type": "string", "enum": ["light", "dark"]},
+    "fontSize": {"type": "integer", "minimum": 8}
+  },
+  "additionalProperties": false
+}`
+)
+
+func getSchemaConfigMap(name, attributeName, schema string) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.AttributeSchemaLabel: "true",
+			},
+			Annotations: map[string]string{},
+		},
+		Data: map[string]string{
+			constants.AttributeSchemaDataKey: schema,
+		},
+	}
+	if attributeName != "" {
+		cm.Annotations[constants.AttributeSchemaNameAnnotation] = attributeName
+	}
+	return cm
+}
+
+func getTestClient(objs ...client.Object) client.Client {
+	return testutil.NewFakeClient(objs...)
+}
+
+func loadTestSchemas(t *testing.T) Schemas {
+	schemas, invalid, err := LoadSchemas(context.Background(), getTestClient(
+		getSchemaConfigMap("editor-settings", "example.com/editor-settings", editorSettingsSchema)), testNamespace)
+	assert.NoError(t, err)
+	assert.Empty(t, invalid)
+	return schemas
+}
+
+func TestLoadSchemasSkipsInvalidConfigMaps(t *testing.T) {
+	unlabelled := getSchemaConfigMap("unlabelled", "example.com/unlabelled", `{"type": "string"}`)
+	unlabelled.Labels = nil
+	fakeClient := getTestClient(
+		getSchemaConfigMap("editor-settings", "example.com/editor-settings", editorSettingsSchema),
+		getSchemaConfigMap("no-attribute-name", "", `{"type": "string"}`),
+		getSchemaConfigMap("invalid-schema", "example.com/invalid", `{"type": 1}`),
+		getSchemaConfigMap("external-ref", "example.com/external", `{"$ref": "file:///etc/passwd"}`),
+		unlabelled,
+	)
+
+	schemas, invalid, err := LoadSchemas(context.Background(), fakeClient, testNamespace)
+	assert.NoError(t, err)
+	assert.Len(t, invalid, 3)
+	assert.Len(t, schemas, 1)
+	assert.Contains(t, schemas, "example.com/editor-settings")
+}
+
+func TestValidateAttributesAcceptsValidAttributes(t *testing.T) {
+	attrs := attributes.Attributes{}.
+		PutString("example.com/other", "not validated").
+		FromMap(map[string]interface{}{
+			"example.com/editor-settings": map[string]interface{}{"theme": "dark", "fontSize": 12},
+		}, nil)
+	assert.NoError(t, ValidateAttributes(attrs, loadTestSchemas(t), nil))
+}
+
+func TestValidateAttributesRejectsInvalidAttributes(t *testing.T) {
+	attrs := attributes.Attributes{}.FromMap(map[string]interface{}{
+		"example.com/editor-settings": map[string]interface{}{"theme": "dark", "fontsize": 12},
+	}, nil)
+	err := ValidateAttributes(attrs, loadTestSchemas(t), nil)
+	if assert.Error(t, err) {
+		assert.Equal(t, "attribute example.com/editor-settings is invalid: /: additionalProperties 'fontsize' not allowed", err.Error())
+	}
+
+	attrs = attributes.Attributes{}.FromMap(map[string]interface{}{
+		"example.com/editor-settings": map[string]interface{}{"theme": "blue", "fontSize": 4},
+	}, nil)
+	err = ValidateAttributes(attrs, loadTestSchemas(t), nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "/theme: value must be one of")
+		assert.Contains(t, err.Error(), "/fontSize: must be >= 8 but found 4")
+	}
+}
+
+func TestValidateAttributesIgnoresUnchangedAttributes(t *testing.T) {
+	attrs := attributes.Attributes{}.FromMap(map[string]interface{}{
+		"example.com/editor-settings": map[string]interface{}{"theme": "blue"},
+	}, nil)
+	assert.NoError(t, ValidateAttributes(attrs, loadTestSchemas(t), attrs))
+	assert.Error(t, ValidateAttributes(attrs, loadTestSchemas(t), attributes.Attributes{}))
+}
@@ -67,6 +67,9 @@ func GetKubeContainersFromDevfile(workspace *dw.DevWorkspaceTemplateSpec, securi
 		if err := handleMountSources(k8sContainer, component.Container, workspace); err != nil {
 			return nil, err
 		}
+		if err := addEndpointProbes(component, k8sContainer); err != nil {
+			return nil, err
+		}
 		if overrides.NeedsContainerOverride(&component) {
 			patchedContainer, err := overrides.ApplyContainerOverrides(&component, k8sContainer)
 			if err != nil {
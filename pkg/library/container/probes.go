@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package container
+
+import (
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	readinessProbeType = "readiness"
+	livenessProbeType  = "liveness"
+	startupProbeType   = "startup"
+
+	// Probe fields are always set explicitly, as Kubernetes fills in defaults for unset fields, which would otherwise
+	// cause the deployment to be seen as changed on every reconcile.
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+	defaultProbeTimeoutSeconds   = 1
+	defaultProbePeriodSeconds    = 10
+	// defaultStartupProbeFailureThreshold allows servers 5 minutes to start with the default period before the
+	// container is restarted.
+	defaultStartupProbeFailureThreshold = 30
+)
+
+// endpointProbe is the value of the controller.devfile.io/probe attribute on an endpoint.
+type endpointProbe struct {
+	// Types is the list of probes to generate from the endpoint: "readiness", "liveness" and/or "startup".
+	// Defaults to readiness only.
+	Types []string `json:"types,omitempty"`
+	// Path is the path requested by probes for HTTP endpoints. Defaults to the endpoint's path, or "/".
+	Path                string `json:"path,omitempty"`
+	InitialDelaySeconds int32  `json:"initialDelaySeconds,omitempty"`
+	PeriodSeconds       int32  `json:"periodSeconds,omitempty"`
+	TimeoutSeconds      int32  `json:"timeoutSeconds,omitempty"`
+	FailureThreshold    int32  `json:"failureThreshold,omitempty"`
+}
+
+// addEndpointProbes adds probes derived from the endpoints of a container component to the container. By default, a
+// readiness probe checking that the first endpoint's target port accepts TCP connections is added. The probes
+// generated from an endpoint can be configured, or disabled by setting it to false, with the
+// controller.devfile.io/probe attribute on the endpoint. Probes specified through the container-overrides attribute
+// take precedence, as overrides are applied afterwards.
+func addEndpointProbes(component dw.Component, container *corev1.Container) error {
+	var defaultProbeEndpoint *dw.Endpoint
+	for idx, endpoint := range component.Container.Endpoints {
+		if !endpoint.Attributes.Exists(constants.EndpointProbeAttribute) {
+			if defaultProbeEndpoint == nil && endpoint.Protocol != dw.UDPEndpointProtocol {
+				defaultProbeEndpoint = &component.Container.Endpoints[idx]
+			}
+			continue
+		}
+		probeAttr := endpointProbe{}
+		var err error
+		if enabled := endpoint.Attributes.GetBoolean(constants.EndpointProbeAttribute, &err); err == nil {
+			if !enabled {
+				continue
+			}
+		} else if err := endpoint.Attributes.GetInto(constants.EndpointProbeAttribute, &probeAttr); err != nil {
+			return fmt.Errorf("failed to parse %s attribute on endpoint %s: %w", constants.EndpointProbeAttribute, endpoint.Name, err)
+		}
+		types := probeAttr.Types
+		if len(types) == 0 {
+			types = []string{readinessProbeType}
+		}
+		for _, probeType := range types {
+			probe, err := getEndpointProbe(endpoint, probeAttr, probeType)
+			if err != nil {
+				return err
+			}
+			var target **corev1.Probe
+			switch probeType {
+			case readinessProbeType:
+				target = &container.ReadinessProbe
+			case livenessProbeType:
+				target = &container.LivenessProbe
+			case startupProbeType:
+				target = &container.StartupProbe
+			default:
+				return fmt.Errorf("invalid probe type %q on endpoint %s: must be one of %s, %s or %s", probeType, endpoint.Name, readinessProbeType, livenessProbeType, startupProbeType)
+			}
+			if *target != nil {
+				return fmt.Errorf("container %s defines more than one %s probe through its endpoints", component.Name, probeType)
+			}
+			*target = probe
+		}
+	}
+	if defaultProbeEndpoint != nil && container.ReadinessProbe == nil {
+		container.ReadinessProbe = getDefaultEndpointProbe(*defaultProbeEndpoint)
+	}
+	return nil
+}
+
+// getDefaultEndpointProbe returns the readiness probe derived from an endpoint without the
+// controller.devfile.io/probe attribute. As the paths served by an endpoint are not known, the probe only checks
+// that the endpoint's target port accepts TCP connections.
+func getDefaultEndpointProbe(endpoint dw.Endpoint) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(endpoint.TargetPort),
+			},
+		},
+		PeriodSeconds:    defaultProbePeriodSeconds,
+		TimeoutSeconds:   defaultProbeTimeoutSeconds,
+		SuccessThreshold: defaultProbeSuccessThreshold,
+		FailureThreshold: defaultProbeFailureThreshold,
+	}
+}
+
+func getEndpointProbe(endpoint dw.Endpoint, probeAttr endpointProbe, probeType string) (*corev1.Probe, error) {
+	probe := &corev1.Probe{
+		InitialDelaySeconds: probeAttr.InitialDelaySeconds,
+		PeriodSeconds:       probeAttr.PeriodSeconds,
+		TimeoutSeconds:      probeAttr.TimeoutSeconds,
+		FailureThreshold:    probeAttr.FailureThreshold,
+		SuccessThreshold:    defaultProbeSuccessThreshold,
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = defaultProbePeriodSeconds
+	}
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = defaultProbeTimeoutSeconds
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = defaultProbeFailureThreshold
+		if probeType == startupProbeType {
+			probe.FailureThreshold = defaultStartupProbeFailureThreshold
+		}
+	}
+
+	switch endpoint.Protocol {
+	case dw.HTTPEndpointProtocol, dw.HTTPSEndpointProtocol, "":
+		// TLS for https endpoints is terminated by the routing, so the container itself is probed over HTTP
+		path := probeAttr.Path
+		if path == "" {
+			path = endpoint.Path
+		}
+		if path == "" {
+			path = "/"
+		}
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Path:   path,
+			Port:   intstr.FromInt(endpoint.TargetPort),
+			Scheme: corev1.URISchemeHTTP,
+		}
+	case dw.WSEndpointProtocol, dw.WSSEndpointProtocol, dw.TCPEndpointProtocol:
+		probe.TCPSocket = &corev1.TCPSocketAction{
+			Port: intstr.FromInt(endpoint.TargetPort),
+		}
+	default:
+		return nil, fmt.Errorf("cannot generate probe for endpoint %s with protocol %s", endpoint.Name, endpoint.Protocol)
+	}
+	return probe, nil
+}
@@ -86,6 +86,9 @@ func commonEnvironmentVariables(workspaceWithConfig *common.DevWorkspaceWithConf
 
 	envvars = append(envvars, getProxyEnvVars(workspaceWithConfig.Config.Routing.ProxyConfig)...)
 	envvars = append(envvars, getSshAskPassEnvVars()...)
+	if workspaceWithConfig.Annotations[constants.DevWorkspaceDebugStartAnnotation] == "true" {
+		envvars = append(envvars, getDebugEnvVars()...)
+	}
 
 	return envvars
 }
@@ -103,6 +106,21 @@ func getSshAskPassEnvVars() []corev1.EnvVar {
 	}
 }
 
+// getDebugEnvVars returns the environment variables that enable verbose logging for DevWorkspaces with the
+// DevWorkspaceDebugStartAnnotation.
+func getDebugEnvVars() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  constants.DevWorkspaceDebug,
+			Value: "true",
+		},
+		{
+			Name:  constants.GitTrace,
+			Value: "1",
+		},
+	}
+}
+
 func getProxyEnvVars(proxyConfig *v1alpha1.Proxy) []corev1.EnvVar {
 	if proxyConfig == nil {
 		return nil
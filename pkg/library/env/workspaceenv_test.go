@@ -20,9 +20,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 )
 
 func TestResolveDevWorkspaceWorkspaceEnv(t *testing.T) {
@@ -45,6 +50,24 @@ func TestResolveDevWorkspaceWorkspaceEnv(t *testing.T) {
 	}
 }
 
+func TestCommonEnvironmentVariablesForDebugStart(t *testing.T) {
+	workspace := &common.DevWorkspaceWithConfig{
+		DevWorkspace: &dw.DevWorkspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-workspace",
+				Namespace: "test-namespace",
+			},
+		},
+		Config: config.GetConfigForTesting(nil),
+	}
+	assert.NotContains(t, commonEnvironmentVariables(workspace), corev1.EnvVar{Name: constants.DevWorkspaceDebug, Value: "true"})
+
+	workspace.Annotations = map[string]string{constants.DevWorkspaceDebugStartAnnotation: "true"}
+	envvars := commonEnvironmentVariables(workspace)
+	assert.Contains(t, envvars, corev1.EnvVar{Name: constants.DevWorkspaceDebug, Value: "true"})
+	assert.Contains(t, envvars, corev1.EnvVar{Name: constants.GitTrace, Value: "1"})
+}
+
 type TestCase struct {
 	Name   string     `json:"name"`
 	Input  TestInput  `json:"input"`
@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// azureReposProvider reads files using the Azure DevOps Git items API.
+type azureReposProvider struct {
+	// baseURL is the URL of the Azure DevOps organization and project
+	baseURL string
+	repo    string
+	ref     string
+}
+
+// parseAzureReposURL parses URLs of the forms
+//
+//	https://dev.azure.com/<organization>/<project>/_git/<repo>
+//	https://<organization>.visualstudio.com/<project>/_git/<repo>
+//
+// A branch may be specified using the 'version' query parameter, e.g. ?version=GBmain
+func parseAzureReposURL(repoURL *url.URL) scmProvider {
+	host := strings.ToLower(repoURL.Host)
+	if host != "dev.azure.com" && !strings.HasSuffix(host, ".visualstudio.com") {
+		return nil
+	}
+	segments := getPathSegments(repoURL.Path)
+	for idx, segment := range segments {
+		if segment != "_git" || idx == 0 || len(segments) != idx+2 {
+			continue
+		}
+		provider := &azureReposProvider{
+			baseURL: fmt.Sprintf("%s://%s/%s", repoURL.Scheme, repoURL.Host, strings.Join(segments[:idx], "/")),
+			repo:    segments[idx+1],
+		}
+		if version := repoURL.Query().Get("version"); strings.HasPrefix(version, "GB") {
+			provider.ref = strings.TrimPrefix(version, "GB")
+		}
+		return provider
+	}
+	return nil
+}
+
+func (p *azureReposProvider) fileRequest(filePath, token string) (*http.Request, error) {
+	query := url.Values{}
+	query.Set("path", "/"+filePath)
+	query.Set("api-version", "7.0")
+	query.Set("$format", "octetStream")
+	if p.ref != "" {
+		query.Set("versionDescriptor.version", p.ref)
+		query.Set("versionDescriptor.versionType", "branch")
+	}
+	location := fmt.Sprintf("%s/_apis/git/repositories/%s/items?%s", p.baseURL, p.repo, query.Encode())
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		// Azure DevOps personal access tokens are passed as the password in basic auth with an empty username
+		req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+token)))
+	}
+	return req, nil
+}
+
+func (p *azureReposProvider) cloneURL() string {
+	return fmt.Sprintf("%s/_git/%s", p.baseURL, p.repo)
+}
+
+func (p *azureReposProvider) revision() string {
+	return p.ref
+}
@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// bitbucketServerProvider reads files using the Bitbucket Server (and Bitbucket Data Center) REST API.
+type bitbucketServerProvider struct {
+	// baseURL is the URL of the Bitbucket Server instance, including its context path if any
+	baseURL string
+	// owner is either "projects/<project key>" or "users/<user slug>" for personal repositories
+	owner string
+	repo  string
+	ref   string
+}
+
+// parseBitbucketServerURL parses URLs of the forms
+//
+//	https://<host>/scm/<project>/<repo>.git
+//	https://<host>/scm/~<user>/<repo>.git
+//	https://<host>/projects/<project>/repos/<repo>[/browse]
+//	https://<host>/users/<user>/repos/<repo>[/browse]
+//
+// where the <host> may include a context path. A branch or ref may be specified using the 'at' query parameter.
+// Bitbucket Cloud (bitbucket.org) is not handled by this provider.
+func parseBitbucketServerURL(repoURL *url.URL) scmProvider {
+	if strings.ToLower(repoURL.Host) == "bitbucket.org" {
+		return nil
+	}
+	segments := getPathSegments(repoURL.Path)
+	for idx, segment := range segments {
+		var owner, repo string
+		switch {
+		case segment == "scm" && len(segments) == idx+3:
+			if strings.HasPrefix(segments[idx+1], "~") {
+				owner = "users/" + strings.TrimPrefix(segments[idx+1], "~")
+			} else {
+				owner = "projects/" + segments[idx+1]
+			}
+			repo = segments[idx+2]
+		case (segment == "projects" || segment == "users") && len(segments) >= idx+4 && segments[idx+2] == "repos":
+			owner = segment + "/" + segments[idx+1]
+			repo = segments[idx+3]
+		default:
+			continue
+		}
+		baseURL := fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host)
+		if idx > 0 {
+			baseURL = fmt.Sprintf("%s/%s", baseURL, strings.Join(segments[:idx], "/"))
+		}
+		return &bitbucketServerProvider{
+			baseURL: baseURL,
+			owner:   owner,
+			repo:    repo,
+			ref:     repoURL.Query().Get("at"),
+		}
+	}
+	return nil
+}
+
+func (p *bitbucketServerProvider) fileRequest(filePath, token string) (*http.Request, error) {
+	location := fmt.Sprintf("%s/rest/api/1.0/%s/repos/%s/raw/%s", p.baseURL, p.owner, p.repo, filePath)
+	if p.ref != "" {
+		location = fmt.Sprintf("%s?at=%s", location, url.QueryEscape(p.ref))
+	}
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func (p *bitbucketServerProvider) cloneURL() string {
+	scmOwner := strings.TrimPrefix(p.owner, "projects/")
+	if strings.HasPrefix(p.owner, "users/") {
+		scmOwner = "~" + strings.TrimPrefix(p.owner, "users/")
+	}
+	return fmt.Sprintf("%s/scm/%s/%s.git", p.baseURL, strings.ToLower(scmOwner), p.repo)
+}
+
+func (p *bitbucketServerProvider) revision() string {
+	return strings.TrimPrefix(p.ref, "refs/heads/")
+}
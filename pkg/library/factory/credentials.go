@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// gitCredentialsSecretKey is the key in git credential secrets that stores credentials in the git-credentials
+// file format, i.e. one https://<username>:<token>@<host>[/<path>] URL per line.
+const gitCredentialsSecretKey = "credentials"
+
+// GetTokenForURL returns the token stored in the namespace's git credential secrets (secrets labelled with
+// controller.devfile.io/git-credential) for the host of repoURL. If the credentials URL contains a path, it is
+// only used for repositories under that path. If no credentials match, an empty string is returned.
+func GetTokenForURL(ctx context.Context, client k8sclient.Client, namespace, repoURL string) (string, error) {
+	parsedRepoURL, err := url.Parse(repoURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid factory URL %s: %w", repoURL, err)
+	}
+
+	secretList := &corev1.SecretList{}
+	err = client.List(ctx, secretList, k8sclient.InNamespace(namespace), k8sclient.MatchingLabels{
+		constants.DevWorkspaceGitCredentialLabel: "true",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list git credentials secrets: %w", err)
+	}
+	secrets := secretList.Items
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	for _, secret := range secrets {
+		credentials, ok := secret.Data[gitCredentialsSecretKey]
+		if !ok {
+			continue
+		}
+		for _, line := range strings.Split(string(credentials), "\n") {
+			if token := getTokenFromCredentials(strings.TrimSpace(line), parsedRepoURL); token != "" {
+				return token, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// getTokenFromCredentials returns the token from a single git-credentials entry if the entry applies to repoURL.
+// If the entry has a password, it is used as the token; otherwise, the username is used.
+func getTokenFromCredentials(credentials string, repoURL *url.URL) string {
+	credentialsURL, err := url.Parse(credentials)
+	if err != nil || credentialsURL.User == nil {
+		return ""
+	}
+	if credentialsURL.Scheme != repoURL.Scheme || !strings.EqualFold(credentialsURL.Host, repoURL.Host) {
+		return ""
+	}
+	credentialsPath := strings.TrimSuffix(credentialsURL.Path, "/")
+	if credentialsPath != "" && repoURL.Path != credentialsPath && !strings.HasPrefix(repoURL.Path, credentialsPath+"/") {
+		return ""
+	}
+	if password, ok := credentialsURL.User.Password(); ok && password != "" {
+		return password
+	}
+	return credentialsURL.User.Username()
+}
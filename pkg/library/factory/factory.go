@@ -0,0 +1,256 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package factory resolves the spec of a DevWorkspace from a repository URL, discovering the devfile
+// stored in the repository and converting it into a DevWorkspace template.
+package factory
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+// DefaultDevfileLocation is stored in the factory-resolved-devfile annotation when no devfile is found
+// in a repository and the DevWorkspace falls back to the configured default template.
+const DefaultDevfileLocation = "default"
+
+// devfileNames is the list of filenames checked (in order) when looking for a devfile in a repository.
+var devfileNames = []string{"devfile.yaml", ".devfile.yaml"}
+
+var invalidProjectNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// NeedsResolution returns true if the DevWorkspace defines a factory URL annotation and the devfile
+// from that URL has not yet been applied to the DevWorkspace.
+func NeedsResolution(workspace *dw.DevWorkspace) bool {
+	if workspace.Annotations == nil {
+		return false
+	}
+	if workspace.Annotations[constants.DevWorkspaceFactoryURLAnnotation] == "" {
+		return false
+	}
+	_, resolved := workspace.Annotations[constants.DevWorkspaceFactoryResolvedAnnotation]
+	return !resolved
+}
+
+// ResolveFromURL applies the devfile discovered at the DevWorkspace's factory URL to the DevWorkspace's
+// template. Any projects already defined in the DevWorkspace are preserved and, if no projects are defined, the
+// repository itself is added as a project. If no devfile can be found in the repository, only the project is added
+// and the DevWorkspace is expected to use the default template from the operator configuration.
+//
+// The devfile is read using the API of the SCM provider hosting the repository (GitHub, GitLab, Bitbucket Server,
+// Gitea or Azure Repos), authenticating with token if it is not empty. For other hosts, the devfile is read from the
+// repository URL joined with the devfile name.
+//
+// On success, the factory-resolved-devfile annotation is set to the location of the resolved devfile. Returns a
+// RetryError if the SCM provider rate-limits requests, or an error if the factory URL is invalid or the devfile cannot
+// be retrieved or parsed.
+func ResolveFromURL(workspace *dw.DevWorkspace, hasDefaultTemplate bool, httpClient HTTPClient, token string) error {
+	repoURL := workspace.Annotations[constants.DevWorkspaceFactoryURLAnnotation]
+	parsedURL, err := parseFactoryURL(repoURL)
+	if err != nil {
+		return err
+	}
+
+	var provider scmProvider
+	if !isYamlFile(parsedURL.Path) {
+		provider = getSCMProvider(parsedURL)
+	}
+	resolvedTemplate, resolvedLocation, triedLocations, err := findDevfile(repoURL, provider, httpClient, token)
+	if err != nil {
+		return err
+	}
+	if resolvedTemplate == nil {
+		resolvedLocation = DefaultDevfileLocation
+	}
+	if resolvedTemplate == nil && !hasDefaultTemplate {
+		return fmt.Errorf("could not find a devfile in repository %s (tried %s) and no default template is configured",
+			repoURL, strings.Join(triedLocations, ", "))
+	}
+
+	if resolvedTemplate != nil {
+		originalProjects := workspace.Spec.Template.Projects
+		originalDependentProjects := workspace.Spec.Template.DependentProjects
+		workspace.Spec.Template = *resolvedTemplate
+		if len(originalProjects) > 0 {
+			workspace.Spec.Template.Projects = originalProjects
+		}
+		workspace.Spec.Template.DependentProjects = append(workspace.Spec.Template.DependentProjects, originalDependentProjects...)
+	}
+	if len(workspace.Spec.Template.Projects) == 0 && provider != nil {
+		workspace.Spec.Template.Projects = append(workspace.Spec.Template.Projects, getProjectForRepository(provider))
+	}
+
+	workspace.Annotations[constants.DevWorkspaceFactoryResolvedAnnotation] = resolvedLocation
+	return nil
+}
+
+// IsRepositoryURL returns true if location is the URL of a repository hosted on a supported SCM provider (GitHub,
+// GitLab, Bitbucket Server, Gitea or Azure Repos), rather than the URL of a devfile.
+func IsRepositoryURL(location string) bool {
+	parsedURL, err := parseFactoryURL(location)
+	if err != nil || isYamlFile(parsedURL.Path) {
+		return false
+	}
+	for _, parse := range scmProviderParsers {
+		if parse(parsedURL) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchDevfileFromRepository reads the devfile stored at the root of the repository at repoURL, using the API of the
+// SCM provider hosting the repository and authenticating with token if it is not empty. Returns the devfile and the
+// location it was read from, or an error if the repository does not contain a devfile.
+func FetchDevfileFromRepository(repoURL string, httpClient HTTPClient, token string) (*dw.DevWorkspaceTemplateSpec, string, error) {
+	parsedURL, err := parseFactoryURL(repoURL)
+	if err != nil {
+		return nil, "", err
+	}
+	template, location, triedLocations, err := findDevfile(repoURL, getSCMProvider(parsedURL), httpClient, token)
+	if err != nil {
+		return nil, "", err
+	}
+	if template == nil {
+		return nil, "", fmt.Errorf("could not find a devfile in repository %s (tried %s)", repoURL, strings.Join(triedLocations, ", "))
+	}
+	return template, location, nil
+}
+
+// findDevfile reads the devfile for repoURL. If provider is nil, repoURL is expected to be the URL of a devfile;
+// otherwise, each of the devfileNames is tried in the repository. If no devfile is found, the returned template is nil
+// and triedLocations contains the locations that were checked.
+func findDevfile(repoURL string, provider scmProvider, httpClient HTTPClient, token string) (template *dw.DevWorkspaceTemplateSpec, location string, triedLocations []string, err error) {
+	var requests []*http.Request
+	if provider == nil {
+		req, err := http.NewRequest(http.MethodGet, repoURL, nil)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("invalid factory URL %s: %w", repoURL, err)
+		}
+		requests = append(requests, req)
+	} else {
+		for _, devfileName := range devfileNames {
+			req, err := provider.fileRequest(devfileName, token)
+			if err != nil {
+				return nil, "", nil, fmt.Errorf("failed to construct request for devfile %s in repository %s: %w", devfileName, repoURL, err)
+			}
+			requests = append(requests, req)
+		}
+	}
+
+	for _, req := range requests {
+		template, err := fetchDevfile(req, httpClient)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if template != nil {
+			return template, req.URL.String(), nil, nil
+		}
+		triedLocations = append(triedLocations, req.URL.String())
+	}
+	return nil, "", triedLocations, nil
+}
+
+func parseFactoryURL(repoURL string) (*url.URL, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid factory URL %s: %w", repoURL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("invalid factory URL %s: only http and https URLs are supported", repoURL)
+	}
+	if parsed.Host == "" {
+		return nil, fmt.Errorf("invalid factory URL %s: URL must contain a host", repoURL)
+	}
+	return parsed, nil
+}
+
+// fetchDevfile performs req to read a devfile. Returns nil, nil if the devfile does not exist.
+func fetchDevfile(req *http.Request, httpClient HTTPClient) (*dw.DevWorkspaceTemplateSpec, error) {
+	location := req.URL.String()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch devfile from %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		break
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case isRateLimited(resp):
+		return nil, &dwerrors.RetryError{
+			Message:      fmt.Sprintf("Rate limited by %s while fetching devfile", req.URL.Host),
+			RequeueAfter: getRetryAfter(resp),
+		}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("could not fetch devfile from %s: got status %d. A git credentials secret with a token for %s may be required",
+			location, resp.StatusCode, req.URL.Host)
+	default:
+		return nil, fmt.Errorf("could not fetch devfile from %s: got status %d", location, resp.StatusCode)
+	}
+	bytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read data from %s: %w", location, err)
+	}
+	return network.ParseDevWorkspaceTemplate(bytes, location)
+}
+
+// getProjectForRepository returns a Git project that clones the repository from provider.
+func getProjectForRepository(provider scmProvider) dw.Project {
+	cloneURL := provider.cloneURL()
+	projectName := "project"
+	if parsed, err := url.Parse(cloneURL); err == nil {
+		projectName = strings.ToLower(path.Base(strings.TrimSuffix(parsed.Path, ".git")))
+		projectName = strings.Trim(invalidProjectNameChars.ReplaceAllString(projectName, "-"), "-")
+		if projectName == "" {
+			projectName = "project"
+		}
+	}
+	project := dw.Project{
+		Name: projectName,
+		ProjectSource: dw.ProjectSource{
+			Git: &dw.GitProjectSource{
+				GitLikeProjectSource: dw.GitLikeProjectSource{
+					Remotes: map[string]string{
+						"origin": cloneURL,
+					},
+				},
+			},
+		},
+	}
+	if revision := provider.revision(); revision != "" {
+		project.Git.CheckoutFrom = &dw.CheckoutFrom{
+			Remote:   "origin",
+			Revision: revision,
+		}
+	}
+	return project
+}
+
+func isYamlFile(filePath string) bool {
+	return strings.HasSuffix(filePath, ".yaml") || strings.HasSuffix(filePath, ".yml")
+}
@@ -0,0 +1,295 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+const testDevfile = `
+schemaVersion: 2.2.0
+metadata:
+  name: test-devfile
+components:
+  - name: tools
+    container:
+      image: quay.io/devfile/universal-developer-image:latest
+`
+
+type fakeHTTPClient struct {
+	files     map[string]string
+	responses map[string]*http.Response
+	requests  []*http.Request
+}
+
+func (f *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req)
+	location := req.URL.String()
+	if resp, ok := f.responses[location]; ok {
+		return resp, nil
+	}
+	if content, ok := f.files[location]; ok {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(content)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBuffer([]byte{})),
+	}, nil
+}
+
+func getTestWorkspace(factoryURL string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Annotations[constants.DevWorkspaceFactoryURLAnnotation] = factoryURL
+	return workspace
+}
+
+func TestNeedsResolution(t *testing.T) {
+	workspace := getTestWorkspace("https://example.com/org/repo")
+	assert.True(t, NeedsResolution(workspace), "Should need resolution when factory URL is set")
+	workspace.Annotations[constants.DevWorkspaceFactoryResolvedAnnotation] = DefaultDevfileLocation
+	assert.False(t, NeedsResolution(workspace), "Should not need resolution when devfile is already resolved")
+	assert.False(t, NeedsResolution(&dw.DevWorkspace{}), "Should not need resolution when factory URL is not set")
+}
+
+func TestResolveFromURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		factoryURL       string
+		files            map[string]string
+		existingProjects []dw.Project
+		hasDefault       bool
+		expectedLocation string
+		expectedProject  string
+		expectComponents bool
+		errRegexp        string
+	}{
+		{
+			name:             "Resolves devfile.yaml",
+			factoryURL:       "https://example.com/org/repo",
+			files:            map[string]string{"https://example.com/org/repo/devfile.yaml": testDevfile},
+			expectedLocation: "https://example.com/org/repo/devfile.yaml",
+			expectedProject:  "repo",
+			expectComponents: true,
+		},
+		{
+			name:             "Falls back to .devfile.yaml",
+			factoryURL:       "https://example.com/org/My_Repo.git",
+			files:            map[string]string{"https://example.com/org/My_Repo/.devfile.yaml": testDevfile},
+			expectedLocation: "https://example.com/org/My_Repo/.devfile.yaml",
+			expectedProject:  "my-repo",
+			expectComponents: true,
+		},
+		{
+			name:             "Uses direct devfile link",
+			factoryURL:       "https://example.com/devfiles/go.yaml",
+			files:            map[string]string{"https://example.com/devfiles/go.yaml": testDevfile},
+			expectedLocation: "https://example.com/devfiles/go.yaml",
+			expectComponents: true,
+		},
+		{
+			name:       "Preserves existing projects",
+			factoryURL: "https://example.com/org/repo",
+			files:      map[string]string{"https://example.com/org/repo/devfile.yaml": testDevfile},
+			existingProjects: []dw.Project{
+				{Name: "existing-project"},
+			},
+			expectedLocation: "https://example.com/org/repo/devfile.yaml",
+			expectedProject:  "existing-project",
+			expectComponents: true,
+		},
+		{
+			name:             "Uses default template when no devfile found",
+			factoryURL:       "https://example.com/org/repo",
+			hasDefault:       true,
+			expectedLocation: DefaultDevfileLocation,
+			expectedProject:  "repo",
+		},
+		{
+			name:       "Fails when no devfile found and no default template",
+			factoryURL: "https://example.com/org/repo",
+			errRegexp:  "could not find a devfile in repository https://example.com/org/repo",
+		},
+		{
+			name:       "Fails for unsupported URL scheme",
+			factoryURL: "git@example.com:org/repo.git",
+			errRegexp:  "invalid factory URL",
+		},
+		{
+			name:             "Resolves devfile using GitHub API",
+			factoryURL:       "https://github.com/org/repo/tree/dev",
+			files:            map[string]string{"https://api.github.com/repos/org/repo/contents/devfile.yaml?ref=dev": testDevfile},
+			expectedLocation: "https://api.github.com/repos/org/repo/contents/devfile.yaml?ref=dev",
+			expectedProject:  "repo",
+			expectComponents: true,
+		},
+		{
+			name:       "Fails for invalid devfile",
+			factoryURL: "https://example.com/org/repo",
+			files:      map[string]string{"https://example.com/org/repo/devfile.yaml": "schemaVersion: 1.0.0"},
+			errRegexp:  "unsupported schemaVersion",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getTestWorkspace(tt.factoryURL)
+			workspace.Spec.Template.Projects = tt.existingProjects
+			err := ResolveFromURL(workspace, tt.hasDefault, &fakeHTTPClient{files: tt.files}, "")
+			if tt.errRegexp != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+				}
+				assert.True(t, NeedsResolution(workspace), "Should not mark workspace as resolved on error")
+				return
+			}
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			assert.Equal(t, tt.expectedLocation, workspace.Annotations[constants.DevWorkspaceFactoryResolvedAnnotation])
+			assert.Equal(t, tt.expectComponents, len(workspace.Spec.Template.Components) > 0,
+				fmt.Sprintf("Components should be set: %t", tt.expectComponents))
+			if tt.expectedProject != "" && assert.Len(t, workspace.Spec.Template.Projects, 1) {
+				assert.Equal(t, tt.expectedProject, workspace.Spec.Template.Projects[0].Name)
+			} else if tt.expectedProject == "" {
+				assert.Empty(t, workspace.Spec.Template.Projects, "Should not add project for direct devfile link")
+			}
+		})
+	}
+}
+
+func TestResolveFromURLProjectUsesCloneURLAndRevision(t *testing.T) {
+	workspace := getTestWorkspace("https://gitlab.com/group/subgroup/repo/-/tree/feature")
+	err := ResolveFromURL(workspace, true, &fakeHTTPClient{}, "")
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	if !assert.Len(t, workspace.Spec.Template.Projects, 1, "Should add project for repository") {
+		return
+	}
+	project := workspace.Spec.Template.Projects[0]
+	assert.Equal(t, "repo", project.Name)
+	assert.Equal(t, "https://gitlab.com/group/subgroup/repo.git", project.Git.Remotes["origin"])
+	if assert.NotNil(t, project.Git.CheckoutFrom, "Should set checkoutFrom for revision in URL") {
+		assert.Equal(t, "feature", project.Git.CheckoutFrom.Revision)
+	}
+}
+
+func TestResolveFromURLAuthenticatesRequests(t *testing.T) {
+	client := &fakeHTTPClient{
+		files: map[string]string{"https://api.github.com/repos/org/repo/contents/devfile.yaml": testDevfile},
+	}
+	err := ResolveFromURL(getTestWorkspace("https://github.com/org/repo"), false, client, "test-token")
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	if assert.Len(t, client.requests, 1) {
+		assert.Equal(t, "Bearer test-token", client.requests[0].Header.Get("Authorization"))
+	}
+}
+
+func TestResolveFromURLHandlesRateLimiting(t *testing.T) {
+	rateLimitedResponse := func(header http.Header) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusForbidden,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewBuffer([]byte{})),
+		}
+	}
+	tests := []struct {
+		name          string
+		response      *http.Response
+		expectedRetry time.Duration
+		errRegexp     string
+	}{
+		{
+			name:          "Uses Retry-After header",
+			response:      rateLimitedResponse(http.Header{"X-Ratelimit-Remaining": []string{"0"}, "Retry-After": []string{"30"}}),
+			expectedRetry: 30 * time.Second,
+		},
+		{
+			name:          "Uses default when reset time is not specified",
+			response:      rateLimitedResponse(http.Header{"X-Ratelimit-Remaining": []string{"0"}}),
+			expectedRetry: defaultRateLimitRetryAfter,
+		},
+		{
+			name:      "Forbidden without rate limit is an error",
+			response:  rateLimitedResponse(http.Header{"X-Ratelimit-Remaining": []string{"10"}}),
+			errRegexp: "A git credentials secret with a token for api.github.com may be required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeHTTPClient{
+				responses: map[string]*http.Response{"https://api.github.com/repos/org/repo/contents/devfile.yaml": tt.response},
+			}
+			err := ResolveFromURL(getTestWorkspace("https://github.com/org/repo"), false, client, "")
+			if !assert.Error(t, err, "Should return error") {
+				return
+			}
+			if tt.errRegexp != "" {
+				assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+				return
+			}
+			var retryErr *dwerrors.RetryError
+			if assert.ErrorAs(t, err, &retryErr, "Should return RetryError when rate limited") {
+				assert.Equal(t, tt.expectedRetry, retryErr.RequeueAfter)
+			}
+		})
+	}
+}
+
+func TestIsRepositoryURL(t *testing.T) {
+	assert.True(t, IsRepositoryURL("https://github.com/org/repo"))
+	assert.True(t, IsRepositoryURL("https://gitlab.com/group/repo/-/tree/main"))
+	assert.True(t, IsRepositoryURL("https://dev.azure.com/org/project/_git/repo"))
+	assert.False(t, IsRepositoryURL("https://github.com/org/repo/raw/main/devfile.yaml"), "Devfile URLs are not repository URLs")
+	assert.False(t, IsRepositoryURL("https://example.com/org/repo"), "URLs on unknown hosts are not repository URLs")
+	assert.False(t, IsRepositoryURL("oci://quay.io/org/repo"), "Only http and https URLs are repository URLs")
+}
+
+func TestFetchDevfileFromRepository(t *testing.T) {
+	client := &fakeHTTPClient{
+		files: map[string]string{"https://api.github.com/repos/org/repo/contents/.devfile.yaml?ref=v1.0.0": testDevfile},
+	}
+	template, location, err := FetchDevfileFromRepository("https://github.com/org/repo/tree/v1.0.0", client, "test-token")
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, "https://api.github.com/repos/org/repo/contents/.devfile.yaml?ref=v1.0.0", location)
+	if assert.Len(t, template.Components, 1) {
+		assert.Equal(t, "tools", template.Components[0].Name)
+	}
+	for _, req := range client.requests {
+		assert.Equal(t, "Bearer test-token", req.Header.Get("Authorization"))
+	}
+
+	_, _, err = FetchDevfileFromRepository("https://github.com/org/other-repo", client, "")
+	assert.Regexp(t, "could not find a devfile in repository https://github.com/org/other-repo", err)
+}
@@ -0,0 +1,75 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// giteaProvider reads files using the Gitea raw file API. Both public instances (gitea.com, codeberg.org) and
+// self-hosted instances (gitea.<domain>) are supported.
+type giteaProvider struct {
+	baseURL string
+	owner   string
+	repo    string
+	ref     string
+}
+
+// parseGiteaURL parses URLs of the form https://gitea.com/<owner>/<repo>[/src/branch/<ref>]
+func parseGiteaURL(repoURL *url.URL) scmProvider {
+	if !isHost(repoURL.Host, "gitea.com", "gitea") && strings.ToLower(repoURL.Host) != "codeberg.org" {
+		return nil
+	}
+	segments := getPathSegments(repoURL.Path)
+	if len(segments) < 2 {
+		return nil
+	}
+	provider := &giteaProvider{
+		baseURL: fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host),
+		owner:   segments[0],
+		repo:    segments[1],
+	}
+	if len(segments) >= 5 && segments[2] == "src" && segments[3] == "branch" {
+		provider.ref = segments[4]
+	}
+	return provider
+}
+
+func (p *giteaProvider) fileRequest(filePath, token string) (*http.Request, error) {
+	location := fmt.Sprintf("%s/api/v1/repos/%s/%s/raw/%s", p.baseURL, p.owner, p.repo, filePath)
+	if p.ref != "" {
+		location = fmt.Sprintf("%s?ref=%s", location, url.QueryEscape(p.ref))
+	}
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	return req, nil
+}
+
+func (p *giteaProvider) cloneURL() string {
+	return fmt.Sprintf("%s/%s/%s.git", p.baseURL, p.owner, p.repo)
+}
+
+func (p *giteaProvider) revision() string {
+	return p.ref
+}
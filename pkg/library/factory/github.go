@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// githubProvider reads files using the GitHub contents API. Both github.com and GitHub Enterprise
+// (github.<domain>) are supported.
+type githubProvider struct {
+	apiURL  string
+	baseURL string
+	owner   string
+	repo    string
+	ref     string
+}
+
+// parseGitHubURL parses URLs of the form https://github.com/<owner>/<repo>[/tree/<ref>]
+func parseGitHubURL(repoURL *url.URL) scmProvider {
+	if !isHost(repoURL.Host, "github.com", "github") {
+		return nil
+	}
+	segments := getPathSegments(repoURL.Path)
+	if len(segments) < 2 {
+		return nil
+	}
+	provider := &githubProvider{
+		apiURL:  fmt.Sprintf("%s://%s/api/v3", repoURL.Scheme, repoURL.Host),
+		baseURL: fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host),
+		owner:   segments[0],
+		repo:    segments[1],
+	}
+	if strings.ToLower(repoURL.Host) == "github.com" {
+		provider.apiURL = "https://api.github.com"
+	}
+	if len(segments) >= 4 && segments[2] == "tree" {
+		provider.ref = segments[3]
+	}
+	return provider
+}
+
+func (p *githubProvider) fileRequest(filePath, token string) (*http.Request, error) {
+	location := fmt.Sprintf("%s/repos/%s/%s/contents/%s", p.apiURL, p.owner, p.repo, filePath)
+	if p.ref != "" {
+		location = fmt.Sprintf("%s?ref=%s", location, url.QueryEscape(p.ref))
+	}
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func (p *githubProvider) cloneURL() string {
+	return fmt.Sprintf("%s/%s/%s.git", p.baseURL, p.owner, p.repo)
+}
+
+func (p *githubProvider) revision() string {
+	return p.ref
+}
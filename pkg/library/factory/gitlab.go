@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gitlabProvider reads files using the GitLab repository files API. Both gitlab.com and self-hosted instances
+// (gitlab.<domain>) are supported.
+type gitlabProvider struct {
+	baseURL     string
+	projectPath string
+	ref         string
+}
+
+// parseGitLabURL parses URLs of the form https://gitlab.com/<group>/[<subgroup>/]<project>[/-/tree/<ref>]
+func parseGitLabURL(repoURL *url.URL) scmProvider {
+	if !isHost(repoURL.Host, "gitlab.com", "gitlab") {
+		return nil
+	}
+	segments := getPathSegments(repoURL.Path)
+	provider := &gitlabProvider{
+		baseURL: fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host),
+	}
+	for idx, segment := range segments {
+		if segment == "-" {
+			if len(segments) > idx+2 && segments[idx+1] == "tree" {
+				provider.ref = segments[idx+2]
+			}
+			segments = segments[:idx]
+			break
+		}
+	}
+	if len(segments) < 2 {
+		return nil
+	}
+	provider.projectPath = strings.Join(segments, "/")
+	return provider
+}
+
+func (p *gitlabProvider) fileRequest(filePath, token string) (*http.Request, error) {
+	ref := p.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	location := fmt.Sprintf("%s/api/v4/projects/%s/repository/files/%s/raw?ref=%s",
+		p.baseURL, url.PathEscape(p.projectPath), url.PathEscape(filePath), url.QueryEscape(ref))
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+	return req, nil
+}
+
+func (p *gitlabProvider) cloneURL() string {
+	return fmt.Sprintf("%s/%s.git", p.baseURL, p.projectPath)
+}
+
+func (p *gitlabProvider) revision() string {
+	return p.ref
+}
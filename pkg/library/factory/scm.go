@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRateLimitRetryAfter is used when a provider rate-limits a request without specifying when it can be retried.
+const defaultRateLimitRetryAfter = 1 * time.Minute
+
+// HTTPClient is the interface used to perform requests against SCM providers. It is implemented by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// scmProvider abstracts reading files from a repository hosted on a specific SCM provider.
+type scmProvider interface {
+	// fileRequest returns a request that reads the raw content of filePath at the root of the repository. If token is
+	// not empty, it is used to authenticate the request against the provider's API.
+	fileRequest(filePath, token string) (*http.Request, error)
+	// cloneURL returns the URL that should be used to clone the repository.
+	cloneURL() string
+	// revision returns the branch or ref referenced by the repository URL, or an empty string if the URL refers to
+	// the default branch.
+	revision() string
+}
+
+// scmProviderParsers are used, in order, to find the provider for a repository URL. If no parser recognizes
+// the URL, the genericProvider is used.
+var scmProviderParsers = []func(repoURL *url.URL) scmProvider{
+	parseGitHubURL,
+	parseGitLabURL,
+	parseBitbucketServerURL,
+	parseGiteaURL,
+	parseAzureReposURL,
+}
+
+// getSCMProvider returns the scmProvider for a repository URL.
+func getSCMProvider(repoURL *url.URL) scmProvider {
+	for _, parse := range scmProviderParsers {
+		if provider := parse(repoURL); provider != nil {
+			return provider
+		}
+	}
+	return &genericProvider{repoURL: *repoURL}
+}
+
+// genericProvider reads files from a repository by assuming raw file contents are served at the repository URL joined
+// with the file path. Requests are not authenticated.
+type genericProvider struct {
+	repoURL url.URL
+}
+
+func (p *genericProvider) fileRequest(filePath, _ string) (*http.Request, error) {
+	location := p.repoURL
+	location.Path = path.Join("/", strings.TrimSuffix(p.repoURL.Path, ".git"), filePath)
+	return http.NewRequest(http.MethodGet, location.String(), nil)
+}
+
+func (p *genericProvider) cloneURL() string {
+	return p.repoURL.String()
+}
+
+func (p *genericProvider) revision() string {
+	return ""
+}
+
+// isRateLimited returns whether a response indicates the request was rejected due to rate limiting. Providers signal
+// this either with a 429 status or, for GitHub and GitLab, a 403 status with no remaining requests.
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("RateLimit-Remaining") == "0"
+	}
+	return false
+}
+
+// getRetryAfter returns how long to wait before retrying a rate-limited request, based on the Retry-After header or
+// the rate limit reset time (in Unix seconds) reported by the provider.
+func getRetryAfter(resp *http.Response) time.Duration {
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && retryAfter > 0 {
+		return time.Duration(retryAfter) * time.Second
+	}
+	for _, header := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		if reset, err := strconv.ParseInt(resp.Header.Get(header), 10, 64); err == nil {
+			if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+				return wait
+			}
+		}
+	}
+	return defaultRateLimitRetryAfter
+}
+
+// getPathSegments returns the non-empty segments of a URL path, with any .git suffix removed from the last segment.
+func getPathSegments(urlPath string) []string {
+	var segments []string
+	for _, segment := range strings.Split(strings.TrimSuffix(urlPath, ".git"), "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// isHost returns whether host is the provider's public host, or a self-hosted instance using a subdomain
+// of the form <provider>.<domain>.
+func isHost(host, publicHost, prefix string) bool {
+	host = strings.ToLower(host)
+	return host == publicHost || strings.HasPrefix(host, prefix+".")
+}
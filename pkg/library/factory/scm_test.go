@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package factory
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSCMProviderFileRequests(t *testing.T) {
+	tests := []struct {
+		name             string
+		repoURL          string
+		token            string
+		expectedURL      string
+		expectedHeaders  map[string]string
+		expectedCloneURL string
+		expectedRevision string
+	}{
+		{
+			name:             "GitHub",
+			repoURL:          "https://github.com/org/repo.git",
+			token:            "test-token",
+			expectedURL:      "https://api.github.com/repos/org/repo/contents/devfile.yaml",
+			expectedHeaders:  map[string]string{"Authorization": "Bearer test-token", "Accept": "application/vnd.github.raw"},
+			expectedCloneURL: "https://github.com/org/repo.git",
+		},
+		{
+			name:             "GitHub Enterprise with branch",
+			repoURL:          "https://github.example.com/org/repo/tree/dev",
+			expectedURL:      "https://github.example.com/api/v3/repos/org/repo/contents/devfile.yaml?ref=dev",
+			expectedCloneURL: "https://github.example.com/org/repo.git",
+			expectedRevision: "dev",
+		},
+		{
+			name:             "GitLab with subgroups",
+			repoURL:          "https://gitlab.com/group/subgroup/repo",
+			token:            "test-token",
+			expectedURL:      "https://gitlab.com/api/v4/projects/group%2Fsubgroup%2Frepo/repository/files/devfile.yaml/raw?ref=HEAD",
+			expectedHeaders:  map[string]string{"PRIVATE-TOKEN": "test-token"},
+			expectedCloneURL: "https://gitlab.com/group/subgroup/repo.git",
+		},
+		{
+			name:             "Self-hosted GitLab with branch",
+			repoURL:          "https://gitlab.example.com/group/repo/-/tree/main",
+			expectedURL:      "https://gitlab.example.com/api/v4/projects/group%2Frepo/repository/files/devfile.yaml/raw?ref=main",
+			expectedCloneURL: "https://gitlab.example.com/group/repo.git",
+			expectedRevision: "main",
+		},
+		{
+			name:             "Bitbucket Server clone URL",
+			repoURL:          "https://bitbucket.example.com/scm/proj/repo.git",
+			token:            "test-token",
+			expectedURL:      "https://bitbucket.example.com/rest/api/1.0/projects/proj/repos/repo/raw/devfile.yaml",
+			expectedHeaders:  map[string]string{"Authorization": "Bearer test-token"},
+			expectedCloneURL: "https://bitbucket.example.com/scm/proj/repo.git",
+		},
+		{
+			name:             "Bitbucket Server browse URL with context path and branch",
+			repoURL:          "https://example.com/bitbucket/projects/PROJ/repos/repo/browse?at=refs/heads/dev",
+			expectedURL:      "https://example.com/bitbucket/rest/api/1.0/projects/PROJ/repos/repo/raw/devfile.yaml?at=refs%2Fheads%2Fdev",
+			expectedCloneURL: "https://example.com/bitbucket/scm/proj/repo.git",
+			expectedRevision: "dev",
+		},
+		{
+			name:             "Bitbucket Server personal repository",
+			repoURL:          "https://bitbucket.example.com/scm/~user/repo.git",
+			expectedURL:      "https://bitbucket.example.com/rest/api/1.0/users/user/repos/repo/raw/devfile.yaml",
+			expectedCloneURL: "https://bitbucket.example.com/scm/~user/repo.git",
+		},
+		{
+			name:             "Gitea with branch",
+			repoURL:          "https://gitea.com/org/repo/src/branch/dev",
+			token:            "test-token",
+			expectedURL:      "https://gitea.com/api/v1/repos/org/repo/raw/devfile.yaml?ref=dev",
+			expectedHeaders:  map[string]string{"Authorization": "token test-token"},
+			expectedCloneURL: "https://gitea.com/org/repo.git",
+			expectedRevision: "dev",
+		},
+		{
+			name:             "Azure Repos",
+			repoURL:          "https://dev.azure.com/org/project/_git/repo",
+			token:            "test-token",
+			expectedURL:      "https://dev.azure.com/org/project/_apis/git/repositories/repo/items?%24format=octetStream&api-version=7.0&path=%2Fdevfile.yaml",
+			expectedHeaders:  map[string]string{"Authorization": "Basic OnRlc3QtdG9rZW4="},
+			expectedCloneURL: "https://dev.azure.com/org/project/_git/repo",
+		},
+		{
+			name:             "Azure Repos on visualstudio.com with branch",
+			repoURL:          "https://org.visualstudio.com/project/_git/repo?version=GBdev",
+			expectedURL:      "https://org.visualstudio.com/project/_apis/git/repositories/repo/items?%24format=octetStream&api-version=7.0&path=%2Fdevfile.yaml&versionDescriptor.version=dev&versionDescriptor.versionType=branch",
+			expectedCloneURL: "https://org.visualstudio.com/project/_git/repo",
+			expectedRevision: "dev",
+		},
+		{
+			name:             "Unknown provider uses repository URL",
+			repoURL:          "https://example.com/org/repo.git",
+			token:            "test-token",
+			expectedURL:      "https://example.com/org/repo/devfile.yaml",
+			expectedHeaders:  map[string]string{"Authorization": ""},
+			expectedCloneURL: "https://example.com/org/repo.git",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repoURL, err := url.Parse(tt.repoURL)
+			if !assert.NoError(t, err) {
+				return
+			}
+			provider := getSCMProvider(repoURL)
+			req, err := provider.fileRequest("devfile.yaml", tt.token)
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			assert.Equal(t, tt.expectedURL, req.URL.String(), "Request URL should match")
+			for header, value := range tt.expectedHeaders {
+				assert.Equal(t, value, req.Header.Get(header), "Header %s should match", header)
+			}
+			assert.Equal(t, tt.expectedCloneURL, provider.cloneURL(), "Clone URL should match")
+			assert.Equal(t, tt.expectedRevision, provider.revision(), "Revision should match")
+		})
+	}
+}
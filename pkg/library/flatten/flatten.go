@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/api/v2/pkg/utils/overriding"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/devfile/devworkspace-operator/pkg/library/annotate"
+	"github.com/devfile/devworkspace-operator/pkg/library/factory"
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 )
 
@@ -50,6 +52,30 @@ type ResolverTools struct {
 	K8sClient                   client.Client
 	HttpClient                  network.HTTPGetter
 	DefaultResourceRequirements *corev1.ResourceRequirements
+	// DefaultRegistryURL is the registry used to resolve plugins and parents specified by ID that do not
+	// specify a registryUrl.
+	DefaultRegistryURL string
+	// RegistryCache is used to cache plugins and parents resolved by ID from a registry or from a Git repository.
+	// If nil, elements are fetched every time they are resolved.
+	RegistryCache *network.TemplateCache
+	// RegistryCacheTTL defines how long elements are stored in the RegistryCache. If zero, the RegistryCache
+	// is not used.
+	RegistryCacheTTL time.Duration
+	// OCIClient is used to resolve plugins and parents stored in OCI registries (i.e. referenced by oci:// URIs).
+	OCIClient *network.OCIClient
+	// PinnedOCIDigests maps oci:// references to the digests they were previously resolved to. References in this
+	// map are resolved using the stored digest rather than their tag, so that the same artifact is used even if the
+	// tag is moved.
+	PinnedOCIDigests map[string]string
+	// ResolvedOCIDigests, if not nil, is filled with the digest of each oci:// reference resolved.
+	ResolvedOCIDigests map[string]string
+	// SCMClient is used to resolve plugins and parents referenced by the URL of a Git repository hosted on a supported
+	// SCM provider (e.g. https://github.com/my-org/my-repo), by reading the devfile stored in the repository. Tokens
+	// are read from the git credential secrets in the WorkspaceNamespace. If nil, such URLs are fetched directly.
+	SCMClient factory.HTTPClient
+	// ResolvedParent, if not nil, is filled with the parent reference and digest of the parent that the DevWorkspace
+	// is resolved with.
+	ResolvedParent *ResolvedParent
 }
 
 // ResolveDevWorkspace takes a devworkspace and returns a "resolved" version of it -- i.e. one where all plugins and parents
@@ -88,6 +114,9 @@ func recursiveResolve(workspace *dw.DevWorkspaceTemplateSpec, contributions []dw
 		if err != nil {
 			return nil, err
 		}
+		if err := resolveTemplateParameters(contribution.Name, pluginComponent, nil); err != nil {
+			return nil, err
+		}
 		newCtx := resolveCtx.addPlugin(contribution.Name, &contribution.PluginComponent)
 		if err := newCtx.hasCycle(); err != nil {
 			return nil, err
@@ -114,6 +143,9 @@ func recursiveResolve(workspace *dw.DevWorkspaceTemplateSpec, contributions []dw
 			if err != nil {
 				return nil, err
 			}
+			if err := resolveTemplateParameters("parent", resolvedParentSpec, workspace.Attributes); err != nil {
+				return nil, err
+			}
 			if !DevWorkspaceIsFlattened(resolvedParentSpec, nil) {
 				// TODO: implemenent this
 				return nil, fmt.Errorf("parents containing plugins or parents are not supported")
@@ -131,6 +163,9 @@ func recursiveResolve(workspace *dw.DevWorkspaceTemplateSpec, contributions []dw
 				if err != nil {
 					return nil, err
 				}
+				if err := resolveTemplateParameters(component.Name, pluginComponent, component.Attributes); err != nil {
+					return nil, err
+				}
 				newCtx := resolveCtx.addPlugin(component.Name, component.Plugin)
 				if err := newCtx.hasCycle(); err != nil {
 					return nil, err
@@ -169,13 +204,18 @@ func resolveParentComponent(parent *dw.Parent, tools ResolverTools) (resolvedPar
 	case parent.Uri != "":
 		resolvedParent, err = resolveElementByURI("parent", parent.Uri, tools)
 	case parent.Id != "":
-		resolvedParent, err = resolveElementById("parent", parent.Id, parent.RegistryUrl, tools)
+		resolvedParent, err = resolveElementById("parent", parent.Id, parent.RegistryUrl, parent.Version, tools)
 	default:
 		err = fmt.Errorf("devfile parent does not define any resources")
 	}
 	if err != nil {
 		return nil, err
 	}
+	if tools.ResolvedParent != nil {
+		if err := recordResolvedParent(tools.ResolvedParent, parent, resolvedParent, tools); err != nil {
+			return nil, err
+		}
+	}
 	if parent.Components != nil || parent.Commands != nil || parent.Projects != nil || parent.StarterProjects != nil {
 		overrideSpec, err := overriding.OverrideDevWorkspaceTemplateSpec(&resolvedParent.DevWorkspaceTemplateSpecContent, parent.ParentOverrides)
 
@@ -199,7 +239,7 @@ func resolvePluginComponent(
 	case plugin.Uri != "":
 		resolvedPlugin, err = resolveElementByURI(name, plugin.Uri, tools)
 	case plugin.Id != "":
-		resolvedPlugin, err = resolveElementById(name, plugin.Id, plugin.RegistryUrl, tools)
+		resolvedPlugin, err = resolveElementById(name, plugin.Id, plugin.RegistryUrl, plugin.Version, tools)
 	default:
 		err = fmt.Errorf("plugin %s does not define any resources", name)
 	}
@@ -263,15 +303,29 @@ func resolveElementByKubernetesImport(
 
 // resolveElementById resolves a component specified by ID and registry URL. The name parameter is used to
 // construct meaningful error messages (e.g. issue resolving plugin 'name'). When registry URL is empty,
-// the DefaultRegistryURL from tools is used.
+// the DefaultRegistryURL from tools is used. If version is set, the specified version of the element is
+// resolved. Resolved elements are cached in the RegistryCache from tools, if configured.
 func resolveElementById(
 	name string,
 	id string,
 	registryUrl string,
+	version string,
 	tools ResolverTools) (resolvedPlugin *dw.DevWorkspaceTemplateSpec, err error) {
 
 	if registryUrl == "" {
-		return nil, fmt.Errorf("plugin %s does not specify a registryUrl", name)
+		if tools.DefaultRegistryURL == "" {
+			return nil, fmt.Errorf("plugin %s does not specify a registryUrl and no default registry is configured", name)
+		}
+		registryUrl = tools.DefaultRegistryURL
+	}
+
+	if network.IsOCIReference(registryUrl) {
+		// convention: elements specified by id are stored in OCI registries at <registryUrl>/<id>:<version>
+		if version == "" {
+			version = "latest"
+		}
+		reference := fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(registryUrl, "/"), id, version)
+		return resolveElementByOCIReference(name, reference, tools)
 	}
 
 	if tools.HttpClient == nil {
@@ -283,13 +337,25 @@ func resolveElementById(
 		return nil, fmt.Errorf("failed to parse registry URL for component %s: %w", name, err)
 	}
 
-	// convention: elements specified by id are served at <registryUrl>/devfiles/<id>
-	pluginURL.Path = path.Join(pluginURL.Path, "devfiles", id)
+	// convention: elements specified by id are served at <registryUrl>/devfiles/<id>, and specific versions
+	// of elements are served at <registryUrl>/devfiles/<id>/<version>
+	pluginURL.Path = path.Join(pluginURL.Path, "devfiles", id, version)
+	location := pluginURL.String()
+
+	useCache := tools.RegistryCache != nil && tools.RegistryCacheTTL > 0
+	if useCache {
+		if dwt, ok := tools.RegistryCache.Get(location, tools.RegistryCacheTTL); ok {
+			return dwt, nil
+		}
+	}
 
-	dwt, err := network.FetchDevWorkspaceTemplate(pluginURL.String(), tools.HttpClient)
+	dwt, err := network.FetchDevWorkspaceTemplate(location, tools.HttpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve component %s from registry %s: %w", name, registryUrl, err)
 	}
+	if useCache {
+		tools.RegistryCache.Add(location, dwt)
+	}
 	return dwt, nil
 }
 
@@ -300,6 +366,14 @@ func resolveElementByURI(
 	uri string,
 	tools ResolverTools) (resolvedPlugin *dw.DevWorkspaceTemplateSpec, err error) {
 
+	if network.IsOCIReference(uri) {
+		return resolveElementByOCIReference(name, uri, tools)
+	}
+
+	if tools.SCMClient != nil && factory.IsRepositoryURL(uri) {
+		return resolveElementByRepositoryURL(name, uri, tools)
+	}
+
 	if tools.HttpClient == nil {
 		return nil, fmt.Errorf("cannot resolve resources by id: no HTTP client provided")
 	}
@@ -311,6 +385,89 @@ func resolveElementByURI(
 	return dwt, nil
 }
 
+// resolveElementByRepositoryURL resolves a plugin defined by the URL of a Git repository, using the devfile stored in
+// the repository. The name parameter is used to construct meaningful error messages (e.g. issue resolving plugin 'name')
+func resolveElementByRepositoryURL(
+	name string,
+	repoURL string,
+	tools ResolverTools) (resolvedPlugin *dw.DevWorkspaceTemplateSpec, err error) {
+
+	useCache := tools.RegistryCache != nil && tools.RegistryCacheTTL > 0
+	if useCache {
+		if dwt, ok := tools.RegistryCache.Get(repoURL, tools.RegistryCacheTTL); ok {
+			return dwt, nil
+		}
+	}
+
+	token := ""
+	if tools.K8sClient != nil {
+		ctx := tools.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		token, err = factory.GetTokenForURL(ctx, tools.K8sClient, tools.WorkspaceNamespace, repoURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read git credentials for component %s: %w", name, err)
+		}
+	}
+	dwt, _, err := factory.FetchDevfileFromRepository(repoURL, tools.SCMClient, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve component %s from repository: %w", name, err)
+	}
+	if useCache {
+		tools.RegistryCache.Add(repoURL, dwt)
+	}
+	return dwt, nil
+}
+
+// resolveElementByOCIReference resolves a plugin stored as an artifact in an OCI registry. If the reference was
+// resolved previously (i.e. is present in the PinnedOCIDigests from tools), the artifact with the recorded digest is used.
+// The name parameter is used to construct meaningful error messages (e.g. issue resolving plugin 'name')
+func resolveElementByOCIReference(
+	name string,
+	reference string,
+	tools ResolverTools) (resolvedPlugin *dw.DevWorkspaceTemplateSpec, err error) {
+
+	if tools.OCIClient == nil {
+		return nil, fmt.Errorf("cannot resolve resources from OCI registries: no OCI client provided")
+	}
+
+	ref, err := network.ParseOCIReference(reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OCI reference for component %s: %w", name, err)
+	}
+	if digest, ok := tools.PinnedOCIDigests[reference]; ok && ref.Digest == "" {
+		ref = ref.WithDigest(digest)
+	}
+
+	// Artifacts referenced by digest are immutable, so they can be cached
+	useCache := tools.RegistryCache != nil && tools.RegistryCacheTTL > 0 && ref.Digest != ""
+	if useCache {
+		if dwt, ok := tools.RegistryCache.Get(ref.String(), tools.RegistryCacheTTL); ok {
+			if tools.ResolvedOCIDigests != nil {
+				tools.ResolvedOCIDigests[reference] = ref.Digest
+			}
+			return dwt, nil
+		}
+	}
+
+	ctx := tools.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	dwt, digest, err := tools.OCIClient.FetchDevWorkspaceTemplate(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve component %s from OCI registry: %w", name, err)
+	}
+	if tools.ResolvedOCIDigests != nil {
+		tools.ResolvedOCIDigests[reference] = digest
+	}
+	if tools.RegistryCache != nil && tools.RegistryCacheTTL > 0 {
+		tools.RegistryCache.Add(ref.WithDigest(digest).String(), dwt)
+	}
+	return dwt, nil
+}
+
 // canImportDW returns true if a DevWorkspace in dwNamespace is allowed to reference the provided DevWorkspaceTemplate
 // DevWorkspaces can by default only read DevWorkspaceTemplates in their own namespace, unless the DevWorkspaceTemplate
 // has the controller.devfile.io/allow-import-from annotation.
@@ -18,9 +18,12 @@ package flatten
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
@@ -117,6 +120,188 @@ func TestResolveDevWorkspaceParents(t *testing.T) {
 	}
 }
 
+func TestResolveDevWorkspaceDefaultPluginRegistry(t *testing.T) {
+	tests := testutil.LoadAllTestsOrPanic(t, "testdata/plugin-registry")
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s (%s)", tt.Name, tt.TestPath), func(t *testing.T) {
+			// sanity check: input defines components
+			assert.True(t, len(tt.Input.DevWorkspace.Components) > 0, "Test case defines workspace with no components")
+			testResolverTools := getTestingTools(tt.Input, "test-ignored")
+			testResolverTools.DefaultRegistryURL = "https://default-registry.io"
+
+			outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace, nil, testResolverTools)
+			if tt.Output.ErrRegexp != nil && assert.Error(t, err) {
+				assert.Regexp(t, *tt.Output.ErrRegexp, err.Error(), "Error message should match")
+			} else {
+				if !assert.NoError(t, err, "Should not return error") {
+					return
+				}
+				assert.Truef(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts),
+					"DevWorkspace should match expected output:\n%s",
+					cmp.Diff(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+			}
+		})
+	}
+}
+
+func TestResolveDevWorkspacePluginRegistryCache(t *testing.T) {
+	tt := testutil.LoadTestCaseOrPanic(t, "testdata/plugin-registry/resolve-plugin-from-default-registry.yaml")
+	testResolverTools := getTestingTools(tt.Input, "test-ignored")
+	testResolverTools.DefaultRegistryURL = "https://default-registry.io"
+	testResolverTools.RegistryCache = network.NewTemplateCache()
+	testResolverTools.RegistryCacheTTL = time.Hour
+
+	outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.True(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+
+	// Remove plugin from registry; subsequent resolves should use the cached plugin
+	testResolverTools.HttpClient = &testutil.FakeHTTPGetter{}
+	outputWorkspace, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if !assert.NoError(t, err, "Should resolve plugin from cache") {
+		return
+	}
+	assert.True(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts),
+		"DevWorkspace should match expected output:\n%s",
+		cmp.Diff(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+
+	// Plugins are fetched again once the cached entry expires
+	testResolverTools.RegistryCacheTTL = time.Nanosecond
+	_, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	assert.Error(t, err, "Should not use expired cache entries")
+}
+
+func TestResolveDevWorkspaceOCIReferences(t *testing.T) {
+	tests := testutil.LoadAllTestsOrPanic(t, "testdata/oci")
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s (%s)", tt.Name, tt.TestPath), func(t *testing.T) {
+			// sanity check: input defines components
+			assert.True(t, len(tt.Input.DevWorkspace.Components) > 0, "Test case defines workspace with no components")
+			testResolverTools := getTestingTools(tt.Input, "test-ignored")
+
+			outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace, nil, testResolverTools)
+			if tt.Output.ErrRegexp != nil && assert.Error(t, err) {
+				assert.Regexp(t, *tt.Output.ErrRegexp, err.Error(), "Error message should match")
+			} else {
+				if !assert.NoError(t, err, "Should not return error") {
+					return
+				}
+				assert.Truef(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts),
+					"DevWorkspace should match expected output:\n%s",
+					cmp.Diff(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+			}
+		})
+	}
+}
+
+func TestResolveDevWorkspaceOCIDigests(t *testing.T) {
+	tt := testutil.LoadTestCaseOrPanic(t, "testdata/oci/resolve-plugin-from-oci-uri.yaml")
+	reference := "oci://quay.io/test/plugin:1.0.0"
+	digest, err := testutil.GetFakeOCIDigest(tt.Input.DevfileResources[reference])
+	if !assert.NoError(t, err) {
+		return
+	}
+	testResolverTools := getTestingTools(tt.Input, "test-ignored")
+	testResolverTools.ResolvedOCIDigests = map[string]string{}
+
+	_, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, map[string]string{reference: digest}, testResolverTools.ResolvedOCIDigests, "Should record resolved digest")
+
+	// Pinned digests are used in place of the reference's tag
+	testResolverTools.PinnedOCIDigests = map[string]string{reference: digest}
+	testResolverTools.ResolvedOCIDigests = map[string]string{}
+	outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if assert.NoError(t, err, "Should resolve pinned digest") {
+		assert.True(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+		assert.Equal(t, map[string]string{reference: digest}, testResolverTools.ResolvedOCIDigests)
+	}
+
+	testResolverTools.PinnedOCIDigests = map[string]string{reference: "sha256:0000"}
+	_, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	assert.Regexp(t, "got status 404", err, "Should not resolve tag when digest is pinned")
+}
+
+func TestResolveDevWorkspaceRepositoryURLs(t *testing.T) {
+	tests := testutil.LoadAllTestsOrPanic(t, "testdata/repository-uri")
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s (%s)", tt.Name, tt.TestPath), func(t *testing.T) {
+			// sanity check: input defines components
+			assert.True(t, len(tt.Input.DevWorkspace.Components) > 0, "Test case defines workspace with no components")
+			testResolverTools := getTestingTools(tt.Input, "test-ignored")
+
+			outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace, nil, testResolverTools)
+			if tt.Output.ErrRegexp != nil && assert.Error(t, err) {
+				assert.Regexp(t, *tt.Output.ErrRegexp, err.Error(), "Error message should match")
+			} else {
+				if !assert.NoError(t, err, "Should not return error") {
+					return
+				}
+				assert.Truef(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts),
+					"DevWorkspace should match expected output:\n%s",
+					cmp.Diff(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+			}
+		})
+	}
+}
+
+func TestResolveDevWorkspaceRecordsResolvedParent(t *testing.T) {
+	tt := testutil.LoadTestCaseOrPanic(t, "testdata/plugin-registry/resolve-parent-from-default-registry.yaml")
+	testResolverTools := getTestingTools(tt.Input, "test-ignored")
+	testResolverTools.DefaultRegistryURL = "https://default-registry.io"
+	testResolverTools.ResolvedParent = &ResolvedParent{}
+
+	_, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	resolvedParent := *testResolverTools.ResolvedParent
+	assert.Equal(t, tt.Input.DevWorkspace.Parent.Id, resolvedParent.Id, "Should record parent reference")
+	assert.Equal(t, "https://default-registry.io", resolvedParent.RegistryUrl, "Should record default registry URL")
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", resolvedParent.Digest, "Should record digest of parent")
+
+	// Digest should only change when the parent's content changes
+	_, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if assert.NoError(t, err) {
+		assert.Equal(t, resolvedParent, *testResolverTools.ResolvedParent, "Digest should be stable")
+	}
+	for location, devfile := range tt.Input.DevfileResources {
+		devfile.Components[0].Container.Image = "updated-image"
+		tt.Input.DevfileResources[location] = devfile
+	}
+	_, _, err = ResolveDevWorkspace(tt.Input.DevWorkspace.DeepCopy(), nil, testResolverTools)
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, resolvedParent.Digest, testResolverTools.ResolvedParent.Digest, "Digest should change when parent changes")
+	}
+}
+
+func TestResolveDevWorkspaceTemplateParameters(t *testing.T) {
+	tests := testutil.LoadAllTestsOrPanic(t, "testdata/template-parameters")
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s (%s)", tt.Name, tt.TestPath), func(t *testing.T) {
+			// sanity check: input defines components
+			assert.True(t, len(tt.Input.DevWorkspace.Components) > 0, "Test case defines workspace with no components")
+			testResolverTools := getTestingTools(tt.Input, "test-ignored")
+
+			outputWorkspace, _, err := ResolveDevWorkspace(tt.Input.DevWorkspace, nil, testResolverTools)
+			if tt.Output.ErrRegexp != nil && assert.Error(t, err) {
+				assert.Regexp(t, *tt.Output.ErrRegexp, err.Error(), "Error message should match")
+			} else {
+				if !assert.NoError(t, err, "Should not return error") {
+					return
+				}
+				assert.Truef(t, cmp.Equal(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts),
+					"DevWorkspace should match expected output:\n%s",
+					cmp.Diff(tt.Output.DevWorkspace, outputWorkspace, testutil.WorkspaceTemplateDiffOpts))
+			}
+		})
+	}
+}
+
 func TestResolveDevWorkspaceMissingDefaults(t *testing.T) {
 	tests := []testutil.TestCase{
 		testutil.LoadTestCaseOrPanic(t, "testdata/general/fail-nicely-when-no-registry-url.yaml"),
@@ -315,5 +500,9 @@ func getTestingTools(input testutil.TestInput, testNamespace string) ResolverToo
 		K8sClient:          testK8sClient,
 		HttpClient:         testHttpGetter,
 		WorkspaceNamespace: testNamespace,
+		OCIClient: &network.OCIClient{
+			HTTPClient: &http.Client{Transport: &testutil.FakeOCIRegistry{DevfileResources: input.DevfileResources}},
+		},
+		SCMClient: testHttpGetter,
 	}
 }
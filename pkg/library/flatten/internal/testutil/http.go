@@ -25,6 +25,7 @@ import (
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"sigs.k8s.io/yaml"
 
+	"github.com/devfile/devworkspace-operator/pkg/library/factory"
 	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 )
 
@@ -35,6 +36,7 @@ type FakeHTTPGetter struct {
 }
 
 var _ network.HTTPGetter = (*FakeHTTPGetter)(nil)
+var _ factory.HTTPClient = (*FakeHTTPGetter)(nil)
 
 type fakeRespBody struct {
 	io.Reader
@@ -42,6 +44,19 @@ type fakeRespBody struct {
 
 func (_ *fakeRespBody) Close() error { return nil }
 
+// Do serves requests from SCM provider APIs in the same way as Get. Devfiles in repositories are looked up by the
+// URL of the API request for the file, e.g. https://api.github.com/repos/org/repo/contents/devfile.yaml
+func (reg *FakeHTTPGetter) Do(req *http.Request) (*http.Response, error) {
+	resp, err := reg.Get(req.URL.String())
+	if err != nil {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       &fakeRespBody{bytes.NewBuffer([]byte{})},
+		}, nil
+	}
+	return resp, nil
+}
+
 func (reg *FakeHTTPGetter) Get(location string) (*http.Response, error) {
 	if plugin, ok := reg.DevfileResources[location]; ok {
 		yamlBytes, err := yaml.Marshal(plugin)
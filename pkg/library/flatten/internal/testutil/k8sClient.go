@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -50,3 +51,11 @@ func (client *FakeK8sClient) Get(_ context.Context, namespacedName client.Object
 	}
 	return fmt.Errorf("test does not define an entry for plugin %s", namespacedName.Name)
 }
+
+// List supports listing secrets, e.g. when reading git credentials; no secrets are returned.
+func (client *FakeK8sClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	if _, ok := list.(*corev1.SecretList); !ok {
+		return fmt.Errorf("called List() in fake client with non-SecretList")
+	}
+	return nil
+}
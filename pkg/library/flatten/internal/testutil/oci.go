@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package testutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+var ociPathRegexp = regexp.MustCompile(`^/v2/(.+)/(manifests|blobs)/([^/]+)$`)
+
+// FakeOCIRegistry is a http.RoundTripper that serves devfiles as single-layer OCI artifacts. Devfiles are looked up
+// by their oci:// reference, e.g. oci://quay.io/test/plugin:1.0.0
+type FakeOCIRegistry struct {
+	DevfileResources map[string]dw.Devfile
+}
+
+var _ http.RoundTripper = (*FakeOCIRegistry)(nil)
+
+type fakeOCIArtifact struct {
+	manifest       []byte
+	manifestDigest string
+	blob           []byte
+	blobDigest     string
+}
+
+func (reg *FakeOCIRegistry) RoundTrip(req *http.Request) (*http.Response, error) {
+	matches := ociPathRegexp.FindStringSubmatch(req.URL.Path)
+	if matches == nil {
+		return fakeOCIResponse(http.StatusNotFound, nil), nil
+	}
+	repository, kind, object := matches[1], matches[2], matches[3]
+	for location, devfile := range reg.DevfileResources {
+		ref, err := network.ParseOCIReference(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCI reference in test: %w", err)
+		}
+		if ref.Registry != req.URL.Host || ref.Repository != repository {
+			continue
+		}
+		artifact, err := getFakeOCIArtifact(devfile)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case kind == "manifests" && (object == ref.Tag || object == artifact.manifestDigest):
+			resp := fakeOCIResponse(http.StatusOK, artifact.manifest)
+			resp.Header.Set("Docker-Content-Digest", artifact.manifestDigest)
+			return resp, nil
+		case kind == "blobs" && object == artifact.blobDigest:
+			return fakeOCIResponse(http.StatusOK, artifact.blob), nil
+		}
+	}
+	return fakeOCIResponse(http.StatusNotFound, nil), nil
+}
+
+// GetFakeOCIDigest returns the manifest digest that a FakeOCIRegistry serves for a devfile
+func GetFakeOCIDigest(devfile dw.Devfile) (string, error) {
+	artifact, err := getFakeOCIArtifact(devfile)
+	if err != nil {
+		return "", err
+	}
+	return artifact.manifestDigest, nil
+}
+
+func getFakeOCIArtifact(devfile dw.Devfile) (*fakeOCIArtifact, error) {
+	blob, err := yaml.Marshal(devfile)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling plugin in test: %w", err)
+	}
+	blobDigest := fakeOCIDigest(blob)
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+		"layers": []map[string]interface{}{
+			{
+				"mediaType": "application/vnd.devfile.layer.v1",
+				"digest":    blobDigest,
+				"size":      len(blob),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling manifest in test: %w", err)
+	}
+	return &fakeOCIArtifact{
+		manifest:       manifest,
+		manifestDigest: fakeOCIDigest(manifest),
+		blob:           blob,
+		blobDigest:     blobDigest,
+	}, nil
+}
+
+func fakeOCIDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func fakeOCIResponse(statusCode int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{},
+		Body:       &fakeRespBody{bytes.NewBuffer(body)},
+	}
+}
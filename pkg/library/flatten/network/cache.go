@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+)
+
+// TemplateCache stores DevWorkspaceTemplateSpecs fetched from remote locations (e.g. plugin registries) to avoid
+// fetching the same template on every reconcile. It is safe for concurrent use.
+type TemplateCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedTemplate
+}
+
+type cachedTemplate struct {
+	template  *dw.DevWorkspaceTemplateSpec
+	fetchedAt time.Time
+}
+
+func NewTemplateCache() *TemplateCache {
+	return &TemplateCache{
+		entries: map[string]cachedTemplate{},
+	}
+}
+
+// Get returns a copy of the template cached for location, if it was cached less than maxAge ago. Expired entries are
+// removed from the cache.
+func (c *TemplateCache) Get(location string, maxAge time.Duration) (template *dw.DevWorkspaceTemplateSpec, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[location]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.fetchedAt) >= maxAge {
+		delete(c.entries, location)
+		return nil, false
+	}
+	return entry.template.DeepCopy(), true
+}
+
+// Add stores a copy of template in the cache for location, replacing any existing entry.
+func (c *TemplateCache) Add(location string, template *dw.DevWorkspaceTemplateSpec) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[location] = cachedTemplate{
+		template:  template.DeepCopy(),
+		fetchedAt: time.Now(),
+	}
+}
@@ -41,7 +41,12 @@ func FetchDevWorkspaceTemplate(location string, httpClient HTTPGetter) (*dw.DevW
 	if err != nil {
 		return nil, fmt.Errorf("could not read data from %s: %w", location, err)
 	}
+	return ParseDevWorkspaceTemplate(bytes, location)
+}
 
+// ParseDevWorkspaceTemplate reads a devfile, DevWorkspace, or DevWorkspaceTemplate from yaml and returns its
+// template spec. Location is used to format error messages.
+func ParseDevWorkspaceTemplate(bytes []byte, location string) (*dw.DevWorkspaceTemplateSpec, error) {
 	devfile := &dw.Devfile{}
 	if err := yaml.Unmarshal(bytes, devfile); err != nil {
 		return nil, fmt.Errorf("could not unmarshal devfile from response: %w", err)
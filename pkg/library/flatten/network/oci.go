@@ -0,0 +1,453 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+)
+
+const (
+	// OCIScheme is the URI scheme used to reference devfiles and DevWorkspaceTemplates stored in OCI registries, e.g.
+	// oci://quay.io/my-org/my-plugin:1.0.0
+	OCIScheme = "oci://"
+
+	ociImageManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	ociArtifactManifestMediaType = "application/vnd.oci.artifact.manifest.v1+json"
+	dockerManifestMediaType      = "application/vnd.docker.distribution.manifest.v2+json"
+	ociImageIndexMediaType       = "application/vnd.oci.image.index.v1+json"
+	dockerManifestListMediaType  = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// ociTitleAnnotation is used by ORAS to store the filename of a layer
+	ociTitleAnnotation = "org.opencontainers.image.title"
+
+	// maxOCIBlobSize limits the size of devfiles that are read from OCI registries
+	maxOCIBlobSize = 4 * 1024 * 1024
+)
+
+// devfileFilenames are the layer titles that are recognized as devfiles or DevWorkspaceTemplates when an artifact
+// contains multiple layers
+var devfileFilenames = []string{"devfile.yaml", ".devfile.yaml", "devworkspace.yaml", "devworkspacetemplate.yaml"}
+
+// OCICredentials are the credentials used to authenticate to an OCI registry.
+type OCICredentials struct {
+	Username string
+	Password string
+}
+
+// OCIClient fetches devfiles and DevWorkspaceTemplates stored as artifacts in OCI registries, e.g. artifacts pushed
+// with `oras push <registry>/<repository>:<tag> devfile.yaml`.
+type OCIClient struct {
+	HTTPClient *http.Client
+	// Credentials maps registry hosts (e.g. quay.io) to the credentials that should be used for that registry.
+	Credentials map[string]OCICredentials
+
+	tokensMu sync.Mutex
+	tokens   map[string]string
+}
+
+// OCIReference is a parsed reference to an artifact in an OCI registry.
+type OCIReference struct {
+	// Registry is the host (and optional port) of the registry
+	Registry string
+	// Repository is the path of the repository within the registry
+	Repository string
+	// Tag is the tag of the artifact. Empty if the reference uses a digest.
+	Tag string
+	// Digest is the digest of the artifact. Empty if the reference uses a tag.
+	Digest string
+}
+
+// String returns the oci:// URI for the reference. If the reference has a digest, the tag is omitted.
+func (r OCIReference) String() string {
+	if r.Digest != "" {
+		return fmt.Sprintf("%s%s/%s@%s", OCIScheme, r.Registry, r.Repository, r.Digest)
+	}
+	return fmt.Sprintf("%s%s/%s:%s", OCIScheme, r.Registry, r.Repository, r.Tag)
+}
+
+// WithDigest returns a copy of the reference that refers to the artifact with the given digest.
+func (r OCIReference) WithDigest(digest string) OCIReference {
+	r.Tag = ""
+	r.Digest = digest
+	return r
+}
+
+// IsOCIReference returns whether a location refers to an artifact in an OCI registry.
+func IsOCIReference(location string) bool {
+	return strings.HasPrefix(location, OCIScheme)
+}
+
+// ParseOCIReference parses a reference of the form oci://<registry>/<repository>[:<tag>][@<digest>]. If neither a tag
+// nor a digest is specified, the tag "latest" is used.
+func ParseOCIReference(location string) (OCIReference, error) {
+	if !IsOCIReference(location) {
+		return OCIReference{}, fmt.Errorf("reference %s does not start with %s", location, OCIScheme)
+	}
+	ref := strings.TrimPrefix(location, OCIScheme)
+	registry, repository, found := strings.Cut(ref, "/")
+	if !found || registry == "" || repository == "" {
+		return OCIReference{}, fmt.Errorf("reference %s must be of the form %s<registry>/<repository>[:<tag>]", location, OCIScheme)
+	}
+
+	result := OCIReference{Registry: registry}
+	if repo, digest, found := strings.Cut(repository, "@"); found {
+		if !strings.Contains(digest, ":") {
+			return OCIReference{}, fmt.Errorf("reference %s has invalid digest %s", location, digest)
+		}
+		result.Digest = digest
+		repository = repo
+	}
+	// A tag is separated from the repository by the last ':' in the last path segment
+	if idx := strings.LastIndex(repository, ":"); idx > strings.LastIndex(repository, "/") {
+		result.Tag = repository[idx+1:]
+		repository = repository[:idx]
+	}
+	if result.Tag == "" && result.Digest == "" {
+		result.Tag = "latest"
+	}
+	if result.Digest != "" {
+		result.Tag = ""
+	}
+	result.Repository = strings.Trim(repository, "/")
+	if result.Repository == "" {
+		return OCIReference{}, fmt.Errorf("reference %s does not specify a repository", location)
+	}
+	return result, nil
+}
+
+// ParseImageReference parses a container image reference of the form [registry/]repository[:tag][@digest], as used in
+// container specs. If no registry is specified, docker.io is used, and if neither a tag nor a digest is specified, the
+// tag "latest" is used.
+func ParseImageReference(image string) (OCIReference, error) {
+	if image == "" || strings.Contains(image, "://") {
+		return OCIReference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	registry := "docker.io"
+	remainder := image
+	if first, rest, found := strings.Cut(image, "/"); found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry = first
+		remainder = rest
+	}
+	if registry == "docker.io" && !strings.Contains(strings.Split(remainder, "@")[0], "/") {
+		remainder = "library/" + remainder
+	}
+	return ParseOCIReference(OCIScheme + registry + "/" + remainder)
+}
+
+// ResolveImageDigest returns the digest of the manifest (or image index) that an image reference refers to, using a
+// HEAD request against the registry. If the reference already includes a digest, it is returned as-is.
+func (c *OCIClient) ResolveImageDigest(ctx context.Context, image string) (string, error) {
+	ref, err := ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	if ref.Digest != "" {
+		return ref.Digest, nil
+	}
+	manifestURL := url.URL{
+		Scheme: "https",
+		Host:   registryAPIHost(ref.Registry),
+		Path:   path.Join("/v2", ref.Repository, "manifests", ref.Tag),
+	}
+	accept := strings.Join([]string{ociImageIndexMediaType, dockerManifestListMediaType, ociImageManifestMediaType, dockerManifestMediaType}, ", ")
+	resp, err := c.doWithAuth(ctx, http.MethodHead, ref, manifestURL.String(), accept)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve digest for image %s: %w", image, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to resolve digest for image %s: got status %d from %s", image, resp.StatusCode, manifestURL.String())
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("failed to resolve digest for image %s: registry did not return a digest", image)
+	}
+	return digest, nil
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+	// Blobs is used instead of Layers in OCI artifact manifests
+	Blobs []ociDescriptor `json:"blobs"`
+}
+
+// FetchDevWorkspaceTemplate fetches the devfile or DevWorkspaceTemplate stored in the OCI artifact referenced by ref.
+// Returns the parsed template and the digest of the artifact's manifest, which can be used to fetch the same artifact
+// again even if its tag is moved.
+func (c *OCIClient) FetchDevWorkspaceTemplate(ctx context.Context, ref OCIReference) (*dw.DevWorkspaceTemplateSpec, string, error) {
+	manifestRef := ref.Tag
+	if ref.Digest != "" {
+		manifestRef = ref.Digest
+	}
+	manifestBytes, headers, err := c.get(ctx, ref, "manifests", manifestRef,
+		strings.Join([]string{ociImageManifestMediaType, ociArtifactManifestMediaType, dockerManifestMediaType}, ", "))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+	manifestDigest := computeDigest(manifestBytes)
+	if ref.Digest != "" && ref.Digest != manifestDigest {
+		return nil, "", fmt.Errorf("manifest for %s has digest %s", ref, manifestDigest)
+	}
+	if headerDigest := headers.Get("Docker-Content-Digest"); headerDigest != "" && headerDigest != manifestDigest {
+		return nil, "", fmt.Errorf("manifest for %s has digest %s, but registry reported digest %s", ref, manifestDigest, headerDigest)
+	}
+
+	manifest := &ociManifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse manifest for %s: %w", ref, err)
+	}
+	layer, err := getDevfileLayer(manifest)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read artifact %s: %w", ref, err)
+	}
+	if layer.Size > maxOCIBlobSize {
+		return nil, "", fmt.Errorf("devfile in artifact %s is too large (%d bytes)", ref, layer.Size)
+	}
+
+	blobBytes, _, err := c.get(ctx, ref, "blobs", layer.Digest, "")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch devfile from %s: %w", ref, err)
+	}
+	if blobDigest := computeDigest(blobBytes); blobDigest != layer.Digest {
+		return nil, "", fmt.Errorf("devfile in artifact %s has digest %s, expected %s", ref, blobDigest, layer.Digest)
+	}
+
+	dwt, err := ParseDevWorkspaceTemplate(blobBytes, ref.String())
+	if err != nil {
+		return nil, "", err
+	}
+	return dwt, manifestDigest, nil
+}
+
+// getDevfileLayer returns the layer of the manifest that contains a devfile. If the manifest has a single layer, it
+// is used. Otherwise, the layer whose title annotation is a known devfile filename is used.
+func getDevfileLayer(manifest *ociManifest) (*ociDescriptor, error) {
+	layers := manifest.Layers
+	if len(layers) == 0 {
+		layers = manifest.Blobs
+	}
+	switch len(layers) {
+	case 0:
+		return nil, fmt.Errorf("artifact does not contain any files")
+	case 1:
+		return &layers[0], nil
+	}
+	for _, filename := range devfileFilenames {
+		for idx, layer := range layers {
+			if layer.Annotations[ociTitleAnnotation] == filename {
+				return &layers[idx], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("artifact contains multiple files but none of them is named %s", strings.Join(devfileFilenames, ", "))
+}
+
+// get reads an object (manifest or blob) from the registry, authenticating if required
+func (c *OCIClient) get(ctx context.Context, ref OCIReference, kind, object, accept string) ([]byte, http.Header, error) {
+	objectURL := url.URL{
+		Scheme: "https",
+		Host:   registryAPIHost(ref.Registry),
+		Path:   path.Join("/v2", ref.Repository, kind, object),
+	}
+
+	resp, err := c.doWithAuth(ctx, http.MethodGet, ref, objectURL.String(), accept)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("got status %d from %s", resp.StatusCode, objectURL.String())
+	}
+	bytes, err := io.ReadAll(io.LimitReader(resp.Body, maxOCIBlobSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read data from %s: %w", objectURL.String(), err)
+	}
+	if len(bytes) > maxOCIBlobSize {
+		return nil, nil, fmt.Errorf("response from %s is too large", objectURL.String())
+	}
+	return bytes, resp.Header, nil
+}
+
+// doWithAuth performs a request against the registry. If the registry responds with a challenge, the request is
+// retried with credentials, exchanging them for a bearer token if required by the registry.
+func (c *OCIClient) doWithAuth(ctx context.Context, method string, ref OCIReference, location, accept string) (*http.Response, error) {
+	tokenKey := ref.Registry + "/" + ref.Repository
+	c.tokensMu.Lock()
+	token := c.tokens[tokenKey]
+	c.tokensMu.Unlock()
+
+	authorization := ""
+	if token != "" {
+		authorization = "Bearer " + token
+	}
+	resp, err := c.do(ctx, method, location, accept, authorization)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	scheme, params := parseAuthChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		token, err := c.fetchToken(ctx, ref, params)
+		if err != nil {
+			return nil, err
+		}
+		c.tokensMu.Lock()
+		if c.tokens == nil {
+			c.tokens = map[string]string{}
+		}
+		c.tokens[tokenKey] = token
+		c.tokensMu.Unlock()
+		return c.do(ctx, method, location, accept, "Bearer "+token)
+	case "basic":
+		creds, ok := c.Credentials[normalizeRegistryHost(ref.Registry)]
+		if !ok {
+			return nil, fmt.Errorf("registry %s requires authentication but no credentials are available", ref.Registry)
+		}
+		return c.do(ctx, method, location, accept, basicAuth(creds))
+	default:
+		return nil, fmt.Errorf("registry %s requested unsupported authentication scheme %q", ref.Registry, scheme)
+	}
+}
+
+func (c *OCIClient) do(ctx context.Context, method, location, accept, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(req)
+}
+
+// fetchToken requests a bearer token with pull access to the repository from the token service described by the
+// registry's challenge, using credentials for the registry if available.
+func (c *OCIClient) fetchToken(ctx context.Context, ref OCIReference, params map[string]string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry %s did not provide a token realm", ref.Registry)
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("registry %s provided invalid token realm: %w", ref.Registry, err)
+	}
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	authorization := ""
+	if creds, ok := c.Credentials[normalizeRegistryHost(ref.Registry)]; ok {
+		authorization = basicAuth(creds)
+	}
+	resp, err := c.do(ctx, http.MethodGet, tokenURL.String(), "", authorization)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch token for registry %s: %w", ref.Registry, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch token for registry %s: got status %d", ref.Registry, resp.StatusCode)
+	}
+	tokenResp := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse token response from registry %s: %w", ref.Registry, err)
+	}
+	if tokenResp.Token != "" {
+		return tokenResp.Token, nil
+	}
+	if tokenResp.AccessToken != "" {
+		return tokenResp.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry %s returned an empty token", ref.Registry)
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header, e.g.
+// Bearer realm="https://auth.example.com/token",service="registry.example.com"
+func parseAuthChallenge(challenge string) (scheme string, params map[string]string) {
+	params = map[string]string{}
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+	return scheme, params
+}
+
+func basicAuth(creds OCICredentials) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password))
+}
+
+func computeDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// registryAPIHost returns the host that serves the registry API for a registry. Docker Hub is referenced as docker.io
+// but serves its API from registry-1.docker.io.
+func registryAPIHost(registry string) string {
+	if registry == "docker.io" || registry == "index.docker.io" {
+		return "registry-1.docker.io"
+	}
+	return registry
+}
@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// GetOCICredentialsFromSecrets reads registry credentials from image pull secrets of type kubernetes.io/dockerconfigjson
+// or kubernetes.io/dockercfg. Secrets of other types, and entries that cannot be parsed, are ignored. If multiple
+// secrets define credentials for the same registry, the first one is used.
+func GetOCICredentialsFromSecrets(secrets []corev1.Secret) map[string]OCICredentials {
+	credentials := map[string]OCICredentials{}
+	for _, secret := range secrets {
+		var entries map[string]dockerConfigEntry
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson:
+			config := &dockerConfigJSON{}
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], config); err != nil {
+				continue
+			}
+			entries = config.Auths
+		case corev1.SecretTypeDockercfg:
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &entries); err != nil {
+				continue
+			}
+		default:
+			continue
+		}
+		for server, entry := range entries {
+			registry := normalizeRegistryHost(server)
+			if _, exists := credentials[registry]; exists {
+				continue
+			}
+			if creds, ok := parseDockerConfigEntry(entry); ok {
+				credentials[registry] = creds
+			}
+		}
+	}
+	return credentials
+}
+
+func parseDockerConfigEntry(entry dockerConfigEntry) (OCICredentials, bool) {
+	if entry.Username != "" || entry.Password != "" {
+		return OCICredentials{Username: entry.Username, Password: entry.Password}, true
+	}
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return OCICredentials{}, false
+	}
+	username, password, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return OCICredentials{}, false
+	}
+	return OCICredentials{Username: username, Password: password}, true
+}
+
+// normalizeRegistryHost converts server entries in docker config files, which may include a scheme and path (e.g.
+// https://index.docker.io/v1/), to registry hosts as used in OCI references.
+func normalizeRegistryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return "docker.io"
+	}
+	return host
+}
@@ -0,0 +1,343 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testDevfile = `
+schemaVersion: 2.2.0
+metadata:
+  name: test-plugin
+components:
+  - name: test-container
+    container:
+      image: test-image
+`
+
+// testRegistry is a minimal OCI registry serving a single repository
+type testRegistry struct {
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	// token, if set, is required as a bearer token for all requests
+	token string
+	// credentials, if set, are required when requesting a bearer token
+	credentials *OCICredentials
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+}
+
+// push stores an artifact containing the provided files as layers and tags it. Returns the digest of the manifest.
+func (r *testRegistry) push(tag string, files map[string]string) string {
+	manifest := ociManifest{MediaType: ociImageManifestMediaType}
+	for filename, content := range files {
+		digest := computeDigest([]byte(content))
+		r.blobs[digest] = []byte(content)
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   "application/vnd.devfile.layer.v1",
+			Digest:      digest,
+			Size:        int64(len(content)),
+			Annotations: map[string]string{ociTitleAnnotation: filename},
+		})
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		panic(err)
+	}
+	digest := computeDigest(manifestBytes)
+	r.manifests[tag] = manifestBytes
+	r.manifests[digest] = manifestBytes
+	return digest
+}
+
+func (r *testRegistry) start(t *testing.T) (*httptest.Server, *OCIClient) {
+	server := httptest.NewTLSServer(http.HandlerFunc(r.serve))
+	t.Cleanup(server.Close)
+	return server, &OCIClient{HTTPClient: server.Client()}
+}
+
+func (r *testRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		if r.credentials != nil {
+			username, password, ok := req.BasicAuth()
+			if !ok || username != r.credentials.Username || password != r.credentials.Password {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		fmt.Fprintf(w, `{"token": %q}`, r.token)
+		return
+	}
+	if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="test-registry"`, req.Host))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(req.URL.Path, "/v2/test/plugin/manifests/"):
+		manifest, ok := r.manifests[strings.TrimPrefix(req.URL.Path, "/v2/test/plugin/manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ociImageManifestMediaType)
+		w.Header().Set("Docker-Content-Digest", computeDigest(manifest))
+		w.Write(manifest)
+	case strings.HasPrefix(req.URL.Path, "/v2/test/plugin/blobs/"):
+		blob, ok := r.blobs[strings.TrimPrefix(req.URL.Path, "/v2/test/plugin/blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(blob)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testReference(server *httptest.Server, tagOrDigest string) OCIReference {
+	ref, err := ParseOCIReference(OCIScheme + server.Listener.Addr().String() + "/test/plugin" + tagOrDigest)
+	if err != nil {
+		panic(err)
+	}
+	return ref
+}
+
+func TestParseOCIReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		location  string
+		expected  OCIReference
+		errRegexp string
+	}{
+		{
+			name:     "Parses reference with tag",
+			location: "oci://quay.io/test/plugin:1.0.0",
+			expected: OCIReference{Registry: "quay.io", Repository: "test/plugin", Tag: "1.0.0"},
+		},
+		{
+			name:     "Uses latest tag if no tag is specified",
+			location: "oci://quay.io/test/plugin",
+			expected: OCIReference{Registry: "quay.io", Repository: "test/plugin", Tag: "latest"},
+		},
+		{
+			name:     "Parses reference with registry port",
+			location: "oci://localhost:5000/plugin:v1",
+			expected: OCIReference{Registry: "localhost:5000", Repository: "plugin", Tag: "v1"},
+		},
+		{
+			name:     "Parses reference with digest",
+			location: "oci://quay.io/test/plugin:1.0.0@sha256:abc",
+			expected: OCIReference{Registry: "quay.io", Repository: "test/plugin", Digest: "sha256:abc"},
+		},
+		{
+			name:      "Rejects reference without repository",
+			location:  "oci://quay.io",
+			errRegexp: "must be of the form",
+		},
+		{
+			name:      "Rejects invalid digest",
+			location:  "oci://quay.io/test/plugin@abc",
+			errRegexp: "invalid digest",
+		},
+		{
+			name:      "Rejects non-OCI references",
+			location:  "https://quay.io/test/plugin",
+			errRegexp: "does not start with oci://",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseOCIReference(tt.location)
+			if tt.errRegexp != "" {
+				assert.Regexp(t, tt.errRegexp, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, ref)
+			}
+		})
+	}
+}
+
+func TestFetchDevWorkspaceTemplate(t *testing.T) {
+	registry := newTestRegistry()
+	digest := registry.push("1.0.0", map[string]string{"devfile.yaml": testDevfile})
+	server, client := registry.start(t)
+
+	dwt, resolvedDigest, err := client.FetchDevWorkspaceTemplate(context.Background(), testReference(server, ":1.0.0"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, digest, resolvedDigest, "Should return digest of manifest")
+	if assert.Len(t, dwt.Components, 1) {
+		assert.Equal(t, "test-container", dwt.Components[0].Name)
+	}
+
+	_, resolvedDigest, err = client.FetchDevWorkspaceTemplate(context.Background(), testReference(server, "@"+digest))
+	if assert.NoError(t, err, "Should fetch artifact by digest") {
+		assert.Equal(t, digest, resolvedDigest)
+	}
+}
+
+func TestFetchDevWorkspaceTemplateMultipleFiles(t *testing.T) {
+	registry := newTestRegistry()
+	registry.push("1.0.0", map[string]string{
+		"README.md":    "# Test plugin",
+		"devfile.yaml": testDevfile,
+	})
+	registry.push("no-devfile", map[string]string{
+		"README.md":  "# Test plugin",
+		"LICENSE.md": "license",
+	})
+	server, client := registry.start(t)
+
+	dwt, _, err := client.FetchDevWorkspaceTemplate(context.Background(), testReference(server, ":1.0.0"))
+	if assert.NoError(t, err) && assert.Len(t, dwt.Components, 1) {
+		assert.Equal(t, "test-container", dwt.Components[0].Name)
+	}
+
+	_, _, err = client.FetchDevWorkspaceTemplate(context.Background(), testReference(server, ":no-devfile"))
+	assert.Regexp(t, "artifact contains multiple files but none of them is named devfile.yaml", err)
+}
+
+func TestFetchDevWorkspaceTemplateVerifiesDigest(t *testing.T) {
+	registry := newTestRegistry()
+	registry.push("1.0.0", map[string]string{"devfile.yaml": testDevfile})
+	server, client := registry.start(t)
+
+	// Serve the artifact tagged 1.0.0 under a different digest
+	wrongDigest := computeDigest([]byte("wrong"))
+	registry.manifests[wrongDigest] = registry.manifests["1.0.0"]
+	_, _, err := client.FetchDevWorkspaceTemplate(context.Background(), testReference(server, "@"+wrongDigest))
+	assert.Regexp(t, "manifest for .* has digest sha256:", err)
+
+	// Serve modified content for the devfile layer
+	for digest := range registry.blobs {
+		registry.blobs[digest] = []byte("modified")
+	}
+	_, _, err = client.FetchDevWorkspaceTemplate(context.Background(), testReference(server, ":1.0.0"))
+	assert.Regexp(t, "devfile in artifact .* has digest sha256:.*, expected sha256:", err)
+}
+
+func TestFetchDevWorkspaceTemplateBearerAuth(t *testing.T) {
+	registry := newTestRegistry()
+	registry.token = "test-token"
+	registry.credentials = &OCICredentials{Username: "test-user", Password: "test-password"}
+	registry.push("1.0.0", map[string]string{"devfile.yaml": testDevfile})
+	server, client := registry.start(t)
+	ref := testReference(server, ":1.0.0")
+
+	_, _, err := client.FetchDevWorkspaceTemplate(context.Background(), ref)
+	assert.Regexp(t, "failed to fetch token for registry .*: got status 401", err, "Should fail without credentials")
+
+	client.Credentials = map[string]OCICredentials{ref.Registry: *registry.credentials}
+	_, _, err = client.FetchDevWorkspaceTemplate(context.Background(), ref)
+	assert.NoError(t, err, "Should authenticate using credentials for registry")
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected OCIReference
+	}{
+		{image: "ubuntu", expected: OCIReference{Registry: "docker.io", Repository: "library/ubuntu", Tag: "latest"}},
+		{image: "org/image:1.0", expected: OCIReference{Registry: "docker.io", Repository: "org/image", Tag: "1.0"}},
+		{image: "quay.io/org/image:1.0", expected: OCIReference{Registry: "quay.io", Repository: "org/image", Tag: "1.0"}},
+		{image: "localhost:5000/image", expected: OCIReference{Registry: "localhost:5000", Repository: "image", Tag: "latest"}},
+		{image: "ubuntu@sha256:abc", expected: OCIReference{Registry: "docker.io", Repository: "library/ubuntu", Digest: "sha256:abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := ParseImageReference(tt.image)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expected, ref)
+			}
+		})
+	}
+}
+
+func TestResolveImageDigest(t *testing.T) {
+	registry := newTestRegistry()
+	registry.token = "test-token"
+	digest := registry.push("1.0.0", map[string]string{"layer": "content"})
+	server, client := registry.start(t)
+	image := server.Listener.Addr().String() + "/test/plugin"
+
+	resolved, err := client.ResolveImageDigest(context.Background(), image+":1.0.0")
+	if assert.NoError(t, err, "Should resolve digest") {
+		assert.Equal(t, digest, resolved)
+	}
+
+	resolved, err = client.ResolveImageDigest(context.Background(), image+"@sha256:abc")
+	if assert.NoError(t, err, "Should not resolve images with digests") {
+		assert.Equal(t, "sha256:abc", resolved)
+	}
+
+	_, err = client.ResolveImageDigest(context.Background(), image+":2.0.0")
+	assert.Regexp(t, "failed to resolve digest for image .*:2.0.0: got status 404", err)
+}
+
+func TestGetOCICredentialsFromSecrets(t *testing.T) {
+	secrets := []corev1.Secret{
+		{
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths": {
+					"quay.io": {"auth": "dGVzdC11c2VyOnRlc3QtcGFzc3dvcmQ="},
+					"https://index.docker.io/v1/": {"username": "docker-user", "password": "docker-password"}
+				}}`),
+			},
+		},
+		{
+			Type: corev1.SecretTypeDockercfg,
+			Data: map[string][]byte{
+				corev1.DockerConfigKey: []byte(`{
+					"quay.io": {"username": "ignored", "password": "ignored"},
+					"registry.example.com:5000": {"username": "example-user", "password": "example-password"}
+				}`),
+			},
+		},
+		{
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths": {"opaque.io": {"username": "ignored"}}}`),
+			},
+		},
+	}
+	expected := map[string]OCICredentials{
+		"quay.io":                   {Username: "test-user", Password: "test-password"},
+		"docker.io":                 {Username: "docker-user", Password: "docker-password"},
+		"registry.example.com:5000": {Username: "example-user", Password: "example-password"},
+	}
+	assert.Equal(t, expected, GetOCICredentialsFromSecrets(secrets))
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package flatten
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/devfile/api/v2/pkg/validation/variables"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// TemplateParameter is a parameter declared by a DevWorkspaceTemplate in the TemplateParametersAttribute attribute.
+type TemplateParameter struct {
+	// Name of the parameter, used to reference it in the template as "{{name}}"
+	Name string `json:"name"`
+	// Description of the parameter
+	Description string `json:"description,omitempty"`
+	// Default is the value used when a DevWorkspace does not provide a value for the parameter
+	Default *string `json:"default,omitempty"`
+	// Required parameters must either have a default or have a value provided by the DevWorkspace
+	Required bool `json:"required,omitempty"`
+	// Pattern is a regular expression that the value of the parameter must match completely
+	Pattern string `json:"pattern,omitempty"`
+	// Enum is the list of allowed values for the parameter
+	Enum []string `json:"enum,omitempty"`
+}
+
+// resolveTemplateParameters substitutes the parameters declared by a DevWorkspaceTemplate with the values provided
+// in the TemplateParameterValuesAttribute of paramAttributes, falling back to each parameter's default value. An error
+// is returned if a value is provided for an undeclared parameter, if a required parameter has no value, or if a value
+// does not match the parameter's pattern or allowed values. The name parameter is used to construct meaningful error
+// messages.
+func resolveTemplateParameters(name string, template *dw.DevWorkspaceTemplateSpec, paramAttributes attributes.Attributes) error {
+	values := map[string]string{}
+	if paramAttributes.Exists(constants.TemplateParameterValuesAttribute) {
+		if err := paramAttributes.GetInto(constants.TemplateParameterValuesAttribute, &values); err != nil {
+			return fmt.Errorf("failed to read parameter values for %s: %w", name, err)
+		}
+	}
+	var params []TemplateParameter
+	if template.Attributes.Exists(constants.TemplateParametersAttribute) {
+		if err := template.Attributes.GetInto(constants.TemplateParametersAttribute, &params); err != nil {
+			return fmt.Errorf("failed to read parameters declared by template for %s: %w", name, err)
+		}
+		// Parameters are resolved for each template separately; avoid conflicts when merging templates.
+		delete(template.Attributes, constants.TemplateParametersAttribute)
+	}
+	if len(params) == 0 && len(values) == 0 {
+		return nil
+	}
+
+	resolvedValues := map[string]string{}
+	for _, param := range params {
+		value, ok := values[param.Name]
+		if !ok && param.Default != nil {
+			value, ok = *param.Default, true
+		}
+		if !ok {
+			if param.Required {
+				return fmt.Errorf("template for %s requires a value for parameter %s", name, param.Name)
+			}
+			resolvedValues[param.Name] = ""
+			continue
+		}
+		if err := validateParameterValue(param, value); err != nil {
+			return fmt.Errorf("invalid value for parameter %s of template for %s: %w", param.Name, name, err)
+		}
+		resolvedValues[param.Name] = value
+	}
+	for paramName := range values {
+		if _, ok := resolvedValues[paramName]; !ok {
+			return fmt.Errorf("template for %s does not declare parameter %s", name, paramName)
+		}
+	}
+
+	// Substitute parameters using the devfile variable syntax. References to variables that are not parameters are left
+	// unchanged, to be replaced by the DevWorkspace's variables once it is flattened.
+	variablesSpec := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Variables:       resolvedValues,
+			Components:      template.Components,
+			Commands:        template.Commands,
+			Projects:        template.Projects,
+			StarterProjects: template.StarterProjects,
+		},
+	}
+	variables.ValidateAndReplaceGlobalVariable(variablesSpec)
+	return nil
+}
+
+func validateParameterValue(param TemplateParameter, value string) error {
+	if len(param.Enum) > 0 {
+		allowed := false
+		for _, enumValue := range param.Enum {
+			if value == enumValue {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("value %q must be one of [%s]", value, strings.Join(param.Enum, ", "))
+		}
+	}
+	if param.Pattern != "" {
+		pattern, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", param.Pattern))
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", param.Pattern, err)
+		}
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("value %q does not match pattern %q", value, param.Pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package flatten
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+)
+
+// ResolvedParent identifies the parent that a DevWorkspace was resolved with, so that it can be determined whether a
+// DevWorkspace was started with the same parent as before.
+type ResolvedParent struct {
+	// ImportReference is the DevWorkspace's parent reference. If the parent is referenced by ID without a registry URL,
+	// the default registry URL is used, and if it is a DevWorkspaceTemplate referenced without a namespace, the
+	// DevWorkspace's namespace is used.
+	dw.ImportReference `json:",inline"`
+	// Digest is the sha256 digest of the parent's content, before any parent overrides are applied.
+	Digest string `json:"digest"`
+}
+
+// recordResolvedParent fills in resolved with the reference and digest of the parent resolved from parent.
+func recordResolvedParent(resolved *ResolvedParent, parent *dw.Parent, resolvedParent *dw.DevWorkspaceTemplateSpec, tools ResolverTools) error {
+	content, err := json.Marshal(resolvedParent)
+	if err != nil {
+		return fmt.Errorf("failed to compute digest of parent: %w", err)
+	}
+	sum := sha256.Sum256(content)
+
+	reference := *parent.ImportReference.DeepCopy()
+	if reference.Id != "" && reference.RegistryUrl == "" {
+		reference.RegistryUrl = tools.DefaultRegistryURL
+	}
+	*resolved = ResolvedParent{
+		ImportReference: reference,
+		Digest:          "sha256:" + hex.EncodeToString(sum[:]),
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package home
+
+import (
+	"fmt"
+
+	dwResources "github.com/devfile/devworkspace-operator/pkg/library/resources"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// DotfilesContainerName is the name of the init container that installs dotfiles into the persistent home volume
+	DotfilesContainerName = "install-dotfiles"
+	dotfilesVolumeName    = "dotfiles"
+	dotfilesMountPath     = "/dotfiles"
+
+	// dotfilesScript clones the dotfiles repository and runs its install script, if it has one. Otherwise, the dotfiles
+	// in the repository (except .git) are copied into the home directory, overwriting existing files. Failing to install
+	// dotfiles must not prevent the workspace from starting, so errors are only logged.
+	dotfilesScript = `(set -e
+REPO_DIR="` + dotfilesMountPath + `/repository"
+rm -rf "${REPO_DIR}"
+echo "Cloning dotfiles from ${DOTFILES_REPOSITORY}"
+if [ -n "${DOTFILES_REF}" ]; then
+  git clone --depth 1 --branch "${DOTFILES_REF}" "${DOTFILES_REPOSITORY}" "${REPO_DIR}"
+else
+  git clone --depth 1 "${DOTFILES_REPOSITORY}" "${REPO_DIR}"
+fi
+cd "${REPO_DIR}"
+if [ -n "${DOTFILES_INSTALL_SCRIPT}" ] && [ ! -f "${DOTFILES_INSTALL_SCRIPT}" ]; then
+  echo "Install script ${DOTFILES_INSTALL_SCRIPT} does not exist in dotfiles repository"
+  exit 1
+fi
+for script in ${DOTFILES_INSTALL_SCRIPT:-install.sh install bootstrap.sh bootstrap setup.sh setup}; do
+  if [ -f "${script}" ]; then
+    echo "Running ${script}"
+    if [ -x "${script}" ]; then
+      "./${script}"
+    else
+      sh "./${script}"
+    fi
+    exit 0
+  fi
+done
+echo "No install script found; copying dotfiles into ${HOME}"
+for file in .[!.]* ..?*; do
+  if [ -e "${file}" ] && [ "${file}" != ".git" ]; then
+    cp -R -f "${file}" "${HOME}/"
+  fi
+done) || echo "Failed to install dotfiles"`
+)
+
+type DotfilesOptions struct {
+	Image      string
+	PullPolicy corev1.PullPolicy
+	Resources  *corev1.ResourceRequirements
+	// Env must define DOTFILES_REPOSITORY, and may define DOTFILES_REF and DOTFILES_INSTALL_SCRIPT to select the
+	// branch or tag to clone and the script to run from the repository. Any other environment variables required to
+	// clone the repository (e.g. proxy settings) should also be included.
+	Env []corev1.EnvVar
+}
+
+// GetDotfilesInitContainer returns an init container that installs dotfiles from a git repository into the persistent
+// home volume, and the scratch volume it clones the repository into. The init container must run after the init
+// container that initializes the persistent home volume.
+func GetDotfilesInitContainer(options DotfilesOptions) (*corev1.Container, *corev1.Volume, error) {
+	resources := &corev1.ResourceRequirements{}
+	if options.Resources != nil {
+		resources = dwResources.FilterResources(options.Resources)
+	}
+	if err := dwResources.ValidateResources(resources); err != nil {
+		return nil, nil, fmt.Errorf("invalid resources for dotfiles container: %w", err)
+	}
+
+	env := append([]corev1.EnvVar{{Name: "HOME", Value: constants.HomeUserDirectory}}, options.Env...)
+	container := &corev1.Container{
+		Name:      DotfilesContainerName,
+		Image:     options.Image,
+		Command:   []string{"/bin/sh"},
+		Args:      []string{"-c", dotfilesScript},
+		Env:       env,
+		Resources: *resources,
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      constants.HomeVolumeName,
+				MountPath: constants.HomeUserDirectory,
+			},
+			{
+				Name:      dotfilesVolumeName,
+				MountPath: dotfilesMountPath,
+			},
+		},
+		ImagePullPolicy: options.PullPolicy,
+	}
+	volume := &corev1.Volume{
+		Name: dotfilesVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	return container, volume, nil
+}
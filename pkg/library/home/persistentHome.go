@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
 	devfilevalidation "github.com/devfile/api/v2/pkg/validation"
 	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
 	"k8s.io/utils/pointer"
@@ -27,26 +28,43 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 )
 
-const initScript = `(echo "Checking for stow command"
-STOW_COMPLETE=/home/user/.stow_completed
+// initScript initializes the persistent home volume, which is mounted at /home/persistent-home/ in the init container.
+// Symlinks created by stow are relative, so they resolve to /home/tooling/ once the volume is mounted at /home/user/.
+// Once stow has run, the contents of the image's home directory are copied into the volume without overwriting
+// existing files. Home directories that were initialized before copying was supported are not copied into.
+const initScript = `(PERSISTENT_HOME=/home/persistent-home
+HOME_COPIED=$PERSISTENT_HOME/.home_copied
+STOW_COMPLETE=$PERSISTENT_HOME/.stow_completed
+if [ -f $STOW_COMPLETE ]; then
+  touch $HOME_COPIED
+fi
+echo "Checking for stow command"
 if command -v stow &> /dev/null; then
   if  [ ! -f $STOW_COMPLETE ]; then
     echo "Running stow command"
-    stow . -t /home/user/ -d /home/tooling/ --no-folding -v 2 > /home/user/.stow.log 2>&1
-    cp -n /home/tooling/.viminfo /home/user/.viminfo
-    cp -n /home/tooling/.bashrc /home/user/.bashrc
-    cp -n /home/tooling/.bash_profile /home/user/.bash_profile
+    stow . -t $PERSISTENT_HOME/ -d /home/tooling/ --no-folding -v 2 > $PERSISTENT_HOME/.stow.log 2>&1
+    cp -n /home/tooling/.viminfo $PERSISTENT_HOME/.viminfo
+    cp -n /home/tooling/.bashrc $PERSISTENT_HOME/.bashrc
+    cp -n /home/tooling/.bash_profile $PERSISTENT_HOME/.bash_profile
     touch $STOW_COMPLETE
   else
     echo "Stow command already run. If you wish to re-run it, delete $STOW_COMPLETE from the persistent volume and restart the workspace."
   fi
 else
   echo "Stow command not found"
+fi
+if [ ! -f $HOME_COPIED ]; then
+  echo "Copying home directory from image"
+  cp -R -n /home/user/. $PERSISTENT_HOME/
+  touch $HOME_COPIED
+else
+  echo "Home directory already copied from image. If you wish to copy it again, delete $HOME_COPIED from the persistent volume and restart the workspace."
 fi) || true
 `
 
 // Returns a modified copy of the given DevWorkspace's Template Spec which contains an additional
 // Devfile volume 'persistentHome' that is mounted  to `/home/user/` for every container component defined in the DevWorkspace.
+// If persistUserHome.dedicatedPVC is enabled, the volume is provisioned on a dedicated PVC.
 // An error is returned if the addition of the 'persistentHome' volume would result
 // in an invalid DevWorkspace.
 func AddPersistentHomeVolume(workspace *common.DevWorkspaceWithConfig) (*v1alpha2.DevWorkspaceTemplateSpec, error) {
@@ -57,6 +75,9 @@ func AddPersistentHomeVolume(workspace *common.DevWorkspaceWithConfig) (*v1alpha
 			Volume: &v1alpha2.VolumeComponent{},
 		},
 	}
+	if usesDedicatedPVC(workspace) {
+		homeVolume.Attributes = attributes.Attributes{}.PutBoolean(constants.DedicatedPVCAttribute, true)
+	}
 	homeVolumeMount := v1alpha2.VolumeMount{
 		Name: constants.HomeVolumeName,
 		Path: constants.HomeUserDirectory,
@@ -71,7 +92,7 @@ func AddPersistentHomeVolume(workspace *common.DevWorkspaceWithConfig) (*v1alpha
 
 	dwTemplateSpecCopy.Components = append(dwTemplateSpecCopy.Components, homeVolume)
 	for _, component := range dwTemplateSpecCopy.Components {
-		if component.Container == nil {
+		if component.Container == nil || component.Name == constants.HomeInitComponentName {
 			continue
 		}
 		component.Container.VolumeMounts = append(component.Container.VolumeMounts, homeVolumeMount)
@@ -110,16 +131,31 @@ func NeedsPersistentHomeDirectory(workspace *common.DevWorkspaceWithConfig) bool
 	return storage.WorkspaceNeedsStorage(&workspace.Spec.Template)
 }
 
+// PersistUserHomeEnabled returns whether home persistence is enabled for the workspace, either through the
+// persist-user-home attribute or the DevWorkspaceOperatorConfig. Invalid values for the attribute are ignored.
 func PersistUserHomeEnabled(workspace *common.DevWorkspaceWithConfig) bool {
+	if workspace.Spec.Template.Attributes.Exists(constants.PersistUserHomeAttribute) {
+		var attrErr error
+		enabled := workspace.Spec.Template.Attributes.GetBoolean(constants.PersistUserHomeAttribute, &attrErr)
+		if attrErr == nil {
+			return enabled
+		}
+	}
 	return pointer.BoolDeref(workspace.Config.Workspace.PersistUserHome.Enabled, false)
 }
 
+// usesDedicatedPVC returns whether the persistent home volume should be provisioned on a dedicated PVC. Dedicated
+// PVCs are not supported by the async storage strategy.
+func usesDedicatedPVC(workspace *common.DevWorkspaceWithConfig) bool {
+	return pointer.BoolDeref(workspace.Config.Workspace.PersistUserHome.DedicatedPVC, false) &&
+		storage.GetStorageType(workspace) != constants.AsyncStorageClassType
+}
+
 // Returns true if the workspace's storage strategy supports persisting the user home directory.
 // The storage strategies which support home persistence are: per-user/common, per-workspace & async.
 // The ephemeral storage strategy does not support home persistence.
 func storageStrategySupportsPersistentHome(workspace *common.DevWorkspaceWithConfig) bool {
-	storageClass := workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil)
-	return storageClass != constants.EphemeralStorageClassType
+	return storage.GetStorageType(workspace) != constants.EphemeralStorageClassType
 }
 
 func addInitContainer(dwTemplateSpec *v1alpha2.DevWorkspaceTemplateSpec) error {
@@ -212,6 +248,10 @@ func addInitContainerComponent(dwTemplateSpec *v1alpha2.DevWorkspaceTemplateSpec
 			},
 		},
 	}
+	initComponent.Container.VolumeMounts = append(initComponent.Container.VolumeMounts, v1alpha2.VolumeMount{
+		Name: constants.HomeVolumeName,
+		Path: constants.HomeInitDirectory,
+	})
 	dwTemplateSpec.Components = append(dwTemplateSpec.Components, initComponent)
 }
 
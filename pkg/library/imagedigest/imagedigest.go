@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package imagedigest resolves the tags of workspace container images to digests, so that a DevWorkspace uses the same
+// images each time it is started, even if the tags are moved to different images.
+package imagedigest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// Resolver resolves an image reference to the digest of the image it currently refers to.
+type Resolver interface {
+	ResolveImageDigest(ctx context.Context, image string) (string, error)
+}
+
+// IsEnabled returns whether image digests should be pinned for a DevWorkspace. The pin-image-digests attribute on the
+// DevWorkspace takes precedence over the workspace.pinImageDigests field in the DevWorkspaceOperatorConfig.
+func IsEnabled(workspace *common.DevWorkspaceWithConfig) bool {
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.PinImageDigestsAttribute) {
+		var err error
+		enabled := attributes.GetBoolean(constants.PinImageDigestsAttribute, &err)
+		if err == nil {
+			return enabled
+		}
+	}
+	return pointer.BoolDeref(workspace.Config.Workspace.PinImageDigests, false)
+}
+
+// GetPinnedDigests returns the image digests recorded in the image-digests annotation on a DevWorkspace. Returns an
+// empty map if the annotation is not set.
+func GetPinnedDigests(workspace *dw.DevWorkspace) (map[string]string, error) {
+	digests := map[string]string{}
+	digestsJSON, ok := workspace.Annotations[constants.DevWorkspaceImageDigestsAnnotation]
+	if !ok {
+		return digests, nil
+	}
+	if err := json.Unmarshal([]byte(digestsJSON), &digests); err != nil {
+		return map[string]string{}, fmt.Errorf("invalid %s annotation: %w", constants.DevWorkspaceImageDigestsAnnotation, err)
+	}
+	return digests, nil
+}
+
+// PinImages replaces the image of each container and init container in podAdditions with a reference to the image's
+// digest (e.g. quay.io/my-org/image:latest@sha256:...). Digests are read from pinned if present, and are otherwise
+// resolved using resolver. Images that already include a digest are not modified.
+//
+// Returns the digest used for each image, and a warning for each image whose digest could not be resolved. Images
+// that could not be resolved are left unchanged.
+func PinImages(ctx context.Context, podAdditions []*controllerv1alpha1.PodAdditions, pinned map[string]string, resolver Resolver) (digests map[string]string, warnings []string) {
+	digests = map[string]string{}
+	failed := map[string]bool{}
+	pinContainer := func(container *corev1.Container) {
+		image := container.Image
+		if image == "" || strings.Contains(image, "@") || failed[image] {
+			return
+		}
+		digest, ok := digests[image]
+		if !ok {
+			digest, ok = pinned[image]
+		}
+		if !ok {
+			var err error
+			digest, err = resolver.ResolveImageDigest(ctx, image)
+			if err != nil {
+				failed[image] = true
+				warnings = append(warnings, fmt.Sprintf("Could not pin digest for image %s: %s", image, err))
+				return
+			}
+		}
+		digests[image] = digest
+		container.Image = fmt.Sprintf("%s@%s", image, digest)
+	}
+
+	for _, additions := range podAdditions {
+		if additions == nil {
+			continue
+		}
+		for idx := range additions.InitContainers {
+			pinContainer(&additions.InitContainers[idx])
+		}
+		for idx := range additions.Containers {
+			pinContainer(&additions.Containers[idx])
+		}
+	}
+	return digests, warnings
+}
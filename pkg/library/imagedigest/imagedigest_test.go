@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagedigest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+type fakeResolver struct {
+	digests  map[string]string
+	resolved []string
+}
+
+func (f *fakeResolver) ResolveImageDigest(_ context.Context, image string) (string, error) {
+	f.resolved = append(f.resolved, image)
+	if digest, ok := f.digests[image]; ok {
+		return digest, nil
+	}
+	return "", fmt.Errorf("image not found")
+}
+
+func TestIsEnabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		configVal *bool
+		attribute interface{}
+		expected  bool
+	}{
+		{name: "Disabled by default", expected: false},
+		{name: "Enabled in config", configVal: pointer.Bool(true), expected: true},
+		{name: "Attribute enables pinning", configVal: pointer.Bool(false), attribute: true, expected: true},
+		{name: "Attribute disables pinning", configVal: pointer.Bool(true), attribute: false, expected: false},
+		{name: "Ignores invalid attribute", configVal: pointer.Bool(true), attribute: "not-a-bool", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &common.DevWorkspaceWithConfig{
+				DevWorkspace: &dw.DevWorkspace{},
+				Config: &controllerv1alpha1.OperatorConfiguration{
+					Workspace: &controllerv1alpha1.WorkspaceConfig{PinImageDigests: tt.configVal},
+				},
+			}
+			if tt.attribute != nil {
+				workspace.Spec.Template.Attributes = attributes.Attributes{}
+				workspace.Spec.Template.Attributes.Put(constants.PinImageDigestsAttribute, tt.attribute, nil)
+			}
+			assert.Equal(t, tt.expected, IsEnabled(workspace))
+		})
+	}
+}
+
+func TestGetPinnedDigests(t *testing.T) {
+	workspace := &dw.DevWorkspace{}
+	digests, err := GetPinnedDigests(workspace)
+	if assert.NoError(t, err) {
+		assert.Empty(t, digests, "Should return empty map when annotation is not set")
+	}
+
+	workspace.ObjectMeta = metav1.ObjectMeta{
+		Annotations: map[string]string{constants.DevWorkspaceImageDigestsAnnotation: `{"quay.io/test/image:latest":"sha256:abc"}`},
+	}
+	digests, err = GetPinnedDigests(workspace)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"quay.io/test/image:latest": "sha256:abc"}, digests)
+	}
+
+	workspace.Annotations[constants.DevWorkspaceImageDigestsAnnotation] = "invalid"
+	_, err = GetPinnedDigests(workspace)
+	assert.Error(t, err, "Should return error for invalid annotation")
+}
+
+func TestPinImages(t *testing.T) {
+	podAdditions := &controllerv1alpha1.PodAdditions{
+		InitContainers: []corev1.Container{
+			{Name: "init", Image: "quay.io/test/init:1.0"},
+		},
+		Containers: []corev1.Container{
+			{Name: "tools", Image: "quay.io/test/tools:latest"},
+			{Name: "tools-2", Image: "quay.io/test/tools:latest"},
+			{Name: "pinned", Image: "quay.io/test/pinned@sha256:123"},
+			{Name: "missing", Image: "quay.io/test/missing:latest"},
+		},
+	}
+	resolver := &fakeResolver{
+		digests: map[string]string{
+			"quay.io/test/tools:latest": "sha256:new",
+			"quay.io/test/init:1.0":     "sha256:init",
+		},
+	}
+	recorded := map[string]string{
+		"quay.io/test/tools:latest": "sha256:recorded",
+		"quay.io/test/unused:1.0":   "sha256:unused",
+	}
+
+	digests, warnings := PinImages(context.Background(), []*controllerv1alpha1.PodAdditions{nil, podAdditions}, recorded, resolver)
+
+	assert.Equal(t, map[string]string{
+		"quay.io/test/tools:latest": "sha256:recorded",
+		"quay.io/test/init:1.0":     "sha256:init",
+	}, digests, "Should return digests of images in use")
+	assert.Equal(t, []string{"quay.io/test/init:1.0", "quay.io/test/missing:latest"}, resolver.resolved,
+		"Should only resolve images that are not recorded, once each")
+	assert.Equal(t, "quay.io/test/init:1.0@sha256:init", podAdditions.InitContainers[0].Image)
+	assert.Equal(t, "quay.io/test/tools:latest@sha256:recorded", podAdditions.Containers[0].Image, "Should use recorded digest")
+	assert.Equal(t, "quay.io/test/tools:latest@sha256:recorded", podAdditions.Containers[1].Image)
+	assert.Equal(t, "quay.io/test/pinned@sha256:123", podAdditions.Containers[2].Image, "Should not modify images with digests")
+	assert.Equal(t, "quay.io/test/missing:latest", podAdditions.Containers[3].Image, "Should not modify images that cannot be resolved")
+	if assert.Len(t, warnings, 1) {
+		assert.Regexp(t, "Could not pin digest for image quay.io/test/missing:latest: image not found", warnings[0])
+	}
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package imagemirror rewrites workspace container images to use mirror registries, e.g. to run DevWorkspaces on
+// clusters that cannot pull images from public registries.
+package imagemirror
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+// RewriteImage returns image with its prefix replaced according to mirrors, which maps image prefixes (e.g. a registry
+// such as "quay.io" or a repository such as "quay.io/devfile") to the prefix that should be used instead. A prefix only
+// matches whole components of an image reference: "quay.io/devfile" matches "quay.io/devfile/universal-developer-image:latest"
+// but not "quay.io/devfiles/image". If multiple prefixes match, the longest one is used. Images that do not match any
+// prefix are returned unchanged.
+func RewriteImage(image string, mirrors map[string]string) string {
+	var matchedSource, matchedPrefix string
+	for source := range mirrors {
+		prefix := strings.TrimSuffix(source, "/")
+		if prefix == "" || len(prefix) <= len(matchedPrefix) {
+			continue
+		}
+		if hasImagePrefix(image, prefix) {
+			matchedSource, matchedPrefix = source, prefix
+		}
+	}
+	if matchedPrefix == "" {
+		return image
+	}
+	return strings.TrimSuffix(mirrors[matchedSource], "/") + strings.TrimPrefix(image, matchedPrefix)
+}
+
+// RewriteImages rewrites the images of all containers and init containers in podAdditions using RewriteImage.
+func RewriteImages(podAdditions []*controllerv1alpha1.PodAdditions, mirrors map[string]string) {
+	if len(mirrors) == 0 {
+		return
+	}
+	rewriteContainer := func(container *corev1.Container) {
+		container.Image = RewriteImage(container.Image, mirrors)
+	}
+	for _, additions := range podAdditions {
+		if additions == nil {
+			continue
+		}
+		for idx := range additions.InitContainers {
+			rewriteContainer(&additions.InitContainers[idx])
+		}
+		for idx := range additions.Containers {
+			rewriteContainer(&additions.Containers[idx])
+		}
+	}
+}
+
+func hasImagePrefix(image, prefix string) bool {
+	if !strings.HasPrefix(image, prefix) {
+		return false
+	}
+	if len(image) == len(prefix) {
+		return true
+	}
+	switch image[len(prefix)] {
+	case '/', ':', '@':
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagemirror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+func TestRewriteImage(t *testing.T) {
+	mirrors := map[string]string{
+		"quay.io":               "mirror.example.com/quay",
+		"quay.io/devfile":       "mirror.example.com/devfile/",
+		"registry.example.com/": "mirror.example.com/example",
+		"docker.io/library/go":  "mirror.example.com/golang",
+	}
+	tests := []struct {
+		name     string
+		image    string
+		expected string
+	}{
+		{name: "Rewrites registry", image: "quay.io/org/image:latest", expected: "mirror.example.com/quay/org/image:latest"},
+		{name: "Uses longest matching prefix", image: "quay.io/devfile/universal-developer-image:ubi8-latest", expected: "mirror.example.com/devfile/universal-developer-image:ubi8-latest"},
+		{name: "Matches whole path components", image: "quay.io/devfiles/image:latest", expected: "mirror.example.com/quay/devfiles/image:latest"},
+		{name: "Ignores trailing slash in prefix", image: "registry.example.com/image", expected: "mirror.example.com/example/image"},
+		{name: "Rewrites repository with tag", image: "docker.io/library/go:1.20", expected: "mirror.example.com/golang:1.20"},
+		{name: "Rewrites repository with digest", image: "docker.io/library/go@sha256:abc", expected: "mirror.example.com/golang@sha256:abc"},
+		{name: "Does not match partial component", image: "docker.io/library/golang:1.20", expected: "docker.io/library/golang:1.20"},
+		{name: "Does not match partial registry", image: "quay.io.example.com/image", expected: "quay.io.example.com/image"},
+		{name: "Leaves unmatched image unchanged", image: "ghcr.io/org/image:latest", expected: "ghcr.io/org/image:latest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RewriteImage(tt.image, mirrors))
+		})
+	}
+}
+
+func TestRewriteImages(t *testing.T) {
+	podAdditions := []*controllerv1alpha1.PodAdditions{
+		{
+			Containers:     []corev1.Container{{Name: "tools", Image: "quay.io/devfile/universal-developer-image:latest"}},
+			InitContainers: []corev1.Container{{Name: "project-clone", Image: "quay.io/devfile/project-clone:next"}},
+		},
+		nil,
+		{
+			Containers: []corev1.Container{{Name: "sidecar", Image: "ghcr.io/org/sidecar:latest"}},
+		},
+	}
+	RewriteImages(podAdditions, map[string]string{"quay.io": "mirror.example.com"})
+	assert.Equal(t, "mirror.example.com/devfile/universal-developer-image:latest", podAdditions[0].Containers[0].Image)
+	assert.Equal(t, "mirror.example.com/devfile/project-clone:next", podAdditions[0].InitContainers[0].Image)
+	assert.Equal(t, "ghcr.io/org/sidecar:latest", podAdditions[2].Containers[0].Image)
+}
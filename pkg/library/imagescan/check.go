@@ -0,0 +1,195 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagescan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	tokenSecretKey = "token"
+	// reportCacheTTL is how long scanned reports for images that are not referenced by digest are reused before the
+	// scanner API is queried again. Reports for images referenced by digest are reused until the digest changes.
+	reportCacheTTL = 15 * time.Minute
+	// pendingRequeueAfter is how long to wait before checking reports that are not yet available again
+	pendingRequeueAfter = 10 * time.Second
+)
+
+type cachedReport struct {
+	report *Report
+	// expires is zero for reports that do not expire
+	expires time.Time
+}
+
+var (
+	reportCache      = map[string]cachedReport{}
+	reportCacheMutex sync.Mutex
+)
+
+// GetImages returns the deduplicated list of images used by containers and init containers in podAdditions.
+func GetImages(podAdditions ...controller.PodAdditions) []string {
+	imageSet := map[string]bool{}
+	for _, additions := range podAdditions {
+		for _, container := range additions.InitContainers {
+			if container.Image != "" {
+				imageSet[container.Image] = true
+			}
+		}
+		for _, container := range additions.Containers {
+			if container.Image != "" {
+				imageSet[container.Image] = true
+			}
+		}
+	}
+	var images []string
+	for image := range imageSet {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	return images
+}
+
+// GetToken reads the token used to authenticate against the scanner API from the secret configured in the image
+// scanning config. Returns an empty string if no secret is configured.
+func GetToken(ctx context.Context, k8sClient client.Client, config *controller.ImageScanningConfig) (string, error) {
+	if config.TokenSecretName == "" {
+		return "", nil
+	}
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: config.TokenSecretName, Namespace: namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to read image scanner token: %w", err)
+	}
+	token, ok := secret.Data[tokenSecretKey]
+	if !ok {
+		return "", fmt.Errorf("secret %s does not contain key %q", config.TokenSecretName, tokenSecretKey)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// CheckImages retrieves vulnerability reports for images and applies the configured policy. The returned message
+// summarizes the results and is suitable for use in a status condition. If the policy is "Block", a FailError is
+// returned when any image has vulnerabilities at or above the severity threshold, and a RetryError is returned while
+// reports are not yet available. If the policy is "Warn", a WarningError is returned instead of failing.
+func CheckImages(images []string, config *controller.ImageScanningConfig, scanner Scanner) (message string, err error) {
+	threshold := ParseSeverity(config.SeverityThreshold)
+	if threshold == SeverityUnknown {
+		threshold = SeverityHigh
+	}
+	blocking := config.Policy == PolicyBlock
+
+	var findings, pending, unsupported []string
+	for _, image := range images {
+		report, err := getReport(config.URL, image, scanner)
+		if err != nil {
+			if blocking {
+				return "", &dwerrors.RetryError{Message: fmt.Sprintf("Failed to retrieve vulnerability report for image %s", image), Err: err, RequeueAfter: pendingRequeueAfter}
+			}
+			return "", &dwerrors.WarningError{Message: fmt.Sprintf("Failed to retrieve vulnerability report for image %s: %s", image, err)}
+		}
+		switch report.Status {
+		case ReportPending:
+			pending = append(pending, image)
+		case ReportUnsupported:
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)", image, report.Reason))
+		case ReportScanned:
+			if summary := summarizeVulnerabilities(report.Vulnerabilities, threshold); summary != "" {
+				findings = append(findings, fmt.Sprintf("%s (%s)", image, summary))
+			}
+		}
+	}
+
+	if len(findings) > 0 {
+		msg := fmt.Sprintf("Images have vulnerabilities with severity %s or higher: %s", threshold, strings.Join(findings, "; "))
+		if blocking {
+			return msg, &dwerrors.FailError{Message: msg}
+		}
+		return msg, &dwerrors.WarningError{Message: msg}
+	}
+	if len(pending) > 0 && blocking {
+		return "", &dwerrors.RetryError{
+			Message:      fmt.Sprintf("Waiting for vulnerability reports for images: %s", strings.Join(pending, ", ")),
+			RequeueAfter: pendingRequeueAfter,
+		}
+	}
+
+	message = fmt.Sprintf("No vulnerabilities with severity %s or higher found", threshold)
+	if len(pending) > 0 {
+		message = fmt.Sprintf("%s; scan pending for: %s", message, strings.Join(pending, ", "))
+	}
+	if len(unsupported) > 0 {
+		message = fmt.Sprintf("%s; not scanned: %s", message, strings.Join(unsupported, ", "))
+	}
+	return message, nil
+}
+
+// summarizeVulnerabilities returns a count of vulnerabilities at or above threshold, grouped by severity, e.g.
+// "2 Critical, 5 High". Returns an empty string if no vulnerability meets the threshold.
+func summarizeVulnerabilities(vulnerabilities []Vulnerability, threshold Severity) string {
+	counts := map[Severity]int{}
+	for _, vuln := range vulnerabilities {
+		if vuln.Severity >= threshold {
+			counts[vuln.Severity]++
+		}
+	}
+	var summary []string
+	for severity := SeverityCritical; severity >= threshold; severity-- {
+		if counts[severity] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	return strings.Join(summary, ", ")
+}
+
+func getReport(scannerURL, image string, scanner Scanner) (*Report, error) {
+	cacheKey, expires := scannerURL+"|"+image, time.Now().Add(reportCacheTTL)
+	if digestKey := parseImageReference(image).digestKey(); digestKey != "" {
+		cacheKey, expires = scannerURL+"|"+digestKey, time.Time{}
+	}
+	reportCacheMutex.Lock()
+	cached, ok := reportCache[cacheKey]
+	reportCacheMutex.Unlock()
+	if ok && (cached.expires.IsZero() || time.Now().Before(cached.expires)) {
+		return cached.report, nil
+	}
+
+	report, err := scanner.GetReport(image)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != ReportPending {
+		reportCacheMutex.Lock()
+		reportCache[cacheKey] = cachedReport{report: report, expires: expires}
+		reportCacheMutex.Unlock()
+	}
+	return report, nil
+}
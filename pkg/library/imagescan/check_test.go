@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagescan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+type fakeScanner struct {
+	reports map[string]*Report
+	err     error
+}
+
+func (f *fakeScanner) GetReport(image string) (*Report, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.reports[image], nil
+}
+
+func TestCheckImages(t *testing.T) {
+	reports := map[string]*Report{
+		"vulnerable": {
+			Image:  "vulnerable",
+			Status: ReportScanned,
+			Vulnerabilities: []Vulnerability{
+				{ID: "CVE-1", Severity: SeverityCritical},
+				{ID: "CVE-2", Severity: SeverityHigh},
+				{ID: "CVE-3", Severity: SeverityHigh},
+				{ID: "CVE-4", Severity: SeverityLow},
+			},
+		},
+		"clean":   {Image: "clean", Status: ReportScanned, Vulnerabilities: []Vulnerability{{ID: "CVE-5", Severity: SeverityLow}}},
+		"pending": {Image: "pending", Status: ReportPending},
+		"other":   {Image: "other", Status: ReportUnsupported, Reason: "image is not hosted in quay.io"},
+	}
+	tests := []struct {
+		name        string
+		images      []string
+		config      controller.ImageScanningConfig
+		scannerErr  error
+		expectedMsg string
+		expectedErr error
+		errRegexp   string
+	}{
+		{
+			name:        "Reports no vulnerabilities above threshold",
+			images:      []string{"clean", "other"},
+			config:      controller.ImageScanningConfig{SeverityThreshold: "High", Policy: PolicyBlock},
+			expectedMsg: "No vulnerabilities with severity High or higher found; not scanned: other (image is not hosted in quay.io)",
+		},
+		{
+			name:        "Warns for vulnerabilities above threshold",
+			images:      []string{"vulnerable", "clean"},
+			config:      controller.ImageScanningConfig{SeverityThreshold: "High", Policy: PolicyWarn},
+			expectedMsg: "Images have vulnerabilities with severity High or higher: vulnerable (1 Critical, 2 High)",
+			expectedErr: &dwerrors.WarningError{},
+		},
+		{
+			name:        "Blocks for vulnerabilities above threshold",
+			images:      []string{"vulnerable"},
+			config:      controller.ImageScanningConfig{SeverityThreshold: "Critical", Policy: PolicyBlock},
+			expectedErr: &dwerrors.FailError{},
+			errRegexp:   "vulnerable \\(1 Critical\\)",
+		},
+		{
+			name:        "Applies threshold",
+			images:      []string{"clean"},
+			config:      controller.ImageScanningConfig{SeverityThreshold: "Low", Policy: PolicyBlock},
+			expectedErr: &dwerrors.FailError{},
+			errRegexp:   "clean \\(1 Low\\)",
+		},
+		{
+			name:        "Waits for pending reports when blocking",
+			images:      []string{"pending"},
+			config:      controller.ImageScanningConfig{Policy: PolicyBlock},
+			expectedErr: &dwerrors.RetryError{},
+			errRegexp:   "Waiting for vulnerability reports for images: pending",
+		},
+		{
+			name:        "Does not wait for pending reports when warning",
+			images:      []string{"pending"},
+			config:      controller.ImageScanningConfig{Policy: PolicyWarn},
+			expectedMsg: "No vulnerabilities with severity High or higher found; scan pending for: pending",
+		},
+		{
+			name:        "Retries scanner errors when blocking",
+			images:      []string{"clean"},
+			config:      controller.ImageScanningConfig{Policy: PolicyBlock, URL: "https://unreachable"},
+			scannerErr:  errors.New("connection refused"),
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name:        "Warns for scanner errors when not blocking",
+			images:      []string{"clean"},
+			config:      controller.ImageScanningConfig{Policy: PolicyWarn, URL: "https://unreachable"},
+			scannerErr:  errors.New("connection refused"),
+			expectedErr: &dwerrors.WarningError{},
+			errRegexp:   "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := CheckImages(tt.images, &tt.config, &fakeScanner{reports: reports, err: tt.scannerErr})
+			if tt.expectedErr != nil {
+				if assert.Error(t, err, "Should return error") {
+					assert.IsType(t, tt.expectedErr, err, "Should return expected error type")
+					if tt.errRegexp != "" {
+						assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+					}
+				}
+			} else {
+				assert.NoError(t, err, "Should not return error")
+			}
+			if tt.expectedMsg != "" {
+				assert.Equal(t, tt.expectedMsg, msg)
+			}
+		})
+	}
+}
+
+func TestReportsForImagesByDigestDoNotExpire(t *testing.T) {
+	image := "quay.io/org/image:v1@sha256:5678"
+	scanner := &fakeScanner{reports: map[string]*Report{
+		image:                           {Image: image, Status: ReportScanned},
+		"quay.io/org/image@sha256:5678": {Image: image, Status: ReportScanned},
+	}}
+	_, err := getReport("https://digest-cache-test", image, scanner)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	reportCacheMutex.Lock()
+	cached, ok := reportCache["https://digest-cache-test|quay.io/org/image@sha256:5678"]
+	reportCacheMutex.Unlock()
+	if assert.True(t, ok, "Report should be cached by digest") {
+		assert.True(t, cached.expires.IsZero(), "Report for digest should not expire")
+	}
+
+	scanner.err = errors.New("scanner should not be called")
+	report, err := getReport("https://digest-cache-test", "quay.io/org/image@sha256:5678", scanner)
+	if assert.NoError(t, err, "Should reuse cached report for same digest with another tag") {
+		assert.Equal(t, ReportScanned, report.Status)
+	}
+}
+
+func TestGetImages(t *testing.T) {
+	images := GetImages(
+		controller.PodAdditions{
+			Containers:     []corev1.Container{{Image: "tools"}, {Image: "editor"}},
+			InitContainers: []corev1.Container{{Image: "project-clone"}},
+		},
+		controller.PodAdditions{
+			Containers: []corev1.Container{{Image: "tools"}},
+		},
+	)
+	assert.Equal(t, []string{"editor", "project-clone", "tools"}, images, "Should return sorted, deduplicated images")
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// clairScanner reads vulnerability reports from a Clair v4 matcher. Clair identifies images by manifest digest, so
+// only images referenced by digest can be checked, and images must have been indexed by Clair beforehand (e.g. by a
+// registry integration or clairctl).
+type clairScanner struct {
+	baseURL string
+	client  HTTPClient
+	token   string
+}
+
+type clairVulnerabilityReport struct {
+	Vulnerabilities map[string]struct {
+		Name               string `json:"name"`
+		NormalizedSeverity string `json:"normalized_severity"`
+	} `json:"vulnerabilities"`
+}
+
+func (s *clairScanner) GetReport(image string) (*Report, error) {
+	ref := parseImageReference(image)
+	if ref.digest == "" {
+		return &Report{Image: image, Status: ReportUnsupported, Reason: "image must be referenced by digest"}, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/matcher/api/v1/vulnerability_report/%s", s.baseURL, ref.digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	clairReport := &clairVulnerabilityReport{}
+	if err := doJSONRequest(s.client, req, clairReport); err != nil {
+		if isNotFound(err) {
+			// Clair returns 404 for manifests that have not been indexed yet
+			return &Report{Image: image, Status: ReportPending}, nil
+		}
+		return nil, err
+	}
+
+	report := &Report{Image: image, Status: ReportScanned}
+	for id, vuln := range clairReport.Vulnerabilities {
+		name := vuln.Name
+		if name == "" {
+			name = id
+		}
+		report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+			ID:       name,
+			Severity: ParseSeverity(vuln.NormalizedSeverity),
+		})
+	}
+	return report, nil
+}
+
+type statusError struct {
+	location   string
+	statusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request to %s returned status %d", e.location, e.statusCode)
+}
+
+func isNotFound(err error) bool {
+	httpErr, ok := err.(*statusError)
+	return ok && httpErr.statusCode == http.StatusNotFound
+}
+
+// doJSONRequest performs a request and decodes the JSON response body into the provided object.
+func doJSONRequest(client HTTPClient, req *http.Request, into interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach scanner API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{location: req.URL.Redacted(), statusCode: resp.StatusCode}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response from scanner API: %w", err)
+	}
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("failed to parse response from scanner API: %w", err)
+	}
+	return nil
+}
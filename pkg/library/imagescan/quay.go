@@ -0,0 +1,134 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagescan
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// quayScanner reads security scan results for images hosted in a Quay registry.
+type quayScanner struct {
+	baseURL string
+	client  HTTPClient
+	token   string
+}
+
+type quayTagList struct {
+	Tags []struct {
+		Name           string `json:"name"`
+		ManifestDigest string `json:"manifest_digest"`
+	} `json:"tags"`
+}
+
+type quaySecurityReport struct {
+	Status string `json:"status"`
+	Data   *struct {
+		Layer struct {
+			Features []struct {
+				Name            string `json:"Name"`
+				Vulnerabilities []struct {
+					Name     string `json:"Name"`
+					Severity string `json:"Severity"`
+				} `json:"Vulnerabilities"`
+			} `json:"Features"`
+		} `json:"Layer"`
+	} `json:"data"`
+}
+
+func (s *quayScanner) GetReport(image string) (*Report, error) {
+	ref := parseImageReference(image)
+	registryURL, err := url.Parse(s.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Quay URL: %w", err)
+	}
+	if !strings.EqualFold(ref.registry, registryURL.Host) {
+		return &Report{Image: image, Status: ReportUnsupported, Reason: fmt.Sprintf("image is not hosted in %s", registryURL.Host)}, nil
+	}
+
+	digest := ref.digest
+	if digest == "" {
+		digest, err = s.resolveTag(ref)
+		if isNotFound(err) {
+			return &Report{Image: image, Status: ReportUnsupported, Reason: "repository not found"}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if digest == "" {
+			return &Report{Image: image, Status: ReportUnsupported, Reason: fmt.Sprintf("tag %s not found", ref.tag)}, nil
+		}
+	}
+
+	securityReport := &quaySecurityReport{}
+	location := fmt.Sprintf("%s/api/v1/repository/%s/manifest/%s/security?vulnerabilities=true", s.baseURL, ref.repository, digest)
+	if err := s.get(location, securityReport); isNotFound(err) {
+		return &Report{Image: image, Status: ReportUnsupported, Reason: "manifest not found"}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Image: image}
+	switch securityReport.Status {
+	case "scanned":
+		report.Status = ReportScanned
+	case "queued":
+		report.Status = ReportPending
+		return report, nil
+	default:
+		report.Status = ReportUnsupported
+		report.Reason = fmt.Sprintf("scan status is %q", securityReport.Status)
+		return report, nil
+	}
+	if securityReport.Data != nil {
+		for _, feature := range securityReport.Data.Layer.Features {
+			for _, vuln := range feature.Vulnerabilities {
+				report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+					ID:       vuln.Name,
+					Severity: ParseSeverity(vuln.Severity),
+				})
+			}
+		}
+	}
+	return report, nil
+}
+
+// resolveTag returns the manifest digest for the image's tag, or an empty string if the tag does not exist.
+func (s *quayScanner) resolveTag(ref imageReference) (string, error) {
+	tags := &quayTagList{}
+	location := fmt.Sprintf("%s/api/v1/repository/%s/tag/?specificTag=%s&onlyActiveTags=true", s.baseURL, ref.repository, url.QueryEscape(ref.tag))
+	if err := s.get(location, tags); err != nil {
+		return "", err
+	}
+	for _, tag := range tags.Tags {
+		if tag.Name == ref.tag {
+			return tag.ManifestDigest, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *quayScanner) get(location string, into interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, location, nil)
+	if err != nil {
+		return err
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	return doJSONRequest(s.client, req, into)
+}
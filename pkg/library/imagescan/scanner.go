@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package imagescan checks workspace container images against vulnerability reports provided by external
+// scanner APIs.
+package imagescan
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+const (
+	ScannerQuay  = "quay"
+	ScannerClair = "clair"
+	ScannerTrivy = "trivy"
+
+	PolicyWarn  = "Warn"
+	PolicyBlock = "Block"
+)
+
+// Severity is the normalized severity of a vulnerability. Higher values are more severe.
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityNegligible
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityUnknown:    "Unknown",
+	SeverityNegligible: "Negligible",
+	SeverityLow:        "Low",
+	SeverityMedium:     "Medium",
+	SeverityHigh:       "High",
+	SeverityCritical:   "Critical",
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// ParseSeverity converts a severity reported by a scanner into a Severity. Matching is case-insensitive, and
+// unrecognized values are treated as SeverityUnknown.
+func ParseSeverity(severity string) Severity {
+	for value, name := range severityNames {
+		if strings.EqualFold(name, severity) {
+			return value
+		}
+	}
+	return SeverityUnknown
+}
+
+// ReportStatus describes whether a vulnerability report is available for an image.
+type ReportStatus string
+
+const (
+	// ReportScanned means the image has been scanned and its vulnerabilities are listed in the report.
+	ReportScanned ReportStatus = "Scanned"
+	// ReportPending means the scanner is aware of the image but has not finished scanning it.
+	ReportPending ReportStatus = "Pending"
+	// ReportUnsupported means the scanner cannot provide a report for the image, e.g. because the image is hosted
+	// in a different registry.
+	ReportUnsupported ReportStatus = "Unsupported"
+)
+
+type Vulnerability struct {
+	ID       string
+	Severity Severity
+}
+
+// Report is the result of scanning a single image.
+type Report struct {
+	Image           string
+	Status          ReportStatus
+	Reason          string
+	Vulnerabilities []Vulnerability
+}
+
+// HTTPClient is the interface used to perform requests against scanner APIs. It is implemented by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Scanner retrieves vulnerability reports for images.
+type Scanner interface {
+	GetReport(image string) (*Report, error)
+}
+
+// NewScanner returns the Scanner defined by the image scanning configuration. The token, if not empty, is used to
+// authenticate requests to the scanner API.
+func NewScanner(config *controller.ImageScanningConfig, client HTTPClient, token string) (Scanner, error) {
+	baseURL := strings.TrimSuffix(config.URL, "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("image scanning URL must be set")
+	}
+	switch config.Scanner {
+	case ScannerQuay:
+		return &quayScanner{baseURL: baseURL, client: client, token: token}, nil
+	case ScannerClair:
+		return &clairScanner{baseURL: baseURL, client: client, token: token}, nil
+	case ScannerTrivy:
+		return &trivyScanner{baseURL: baseURL, client: withoutRedirects(client), token: token}, nil
+	default:
+		return nil, fmt.Errorf("unsupported image scanner %q", config.Scanner)
+	}
+}
+
+// imageReference is a parsed container image reference of the form [registry/]repository[:tag][@digest]
+type imageReference struct {
+	registry   string
+	repository string
+	tag        string
+	digest     string
+}
+
+func parseImageReference(image string) imageReference {
+	ref := imageReference{}
+	if idx := strings.Index(image, "@"); idx >= 0 {
+		ref.digest = image[idx+1:]
+		image = image[:idx]
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		ref.tag = image[idx+1:]
+		image = image[:idx]
+	}
+	segments := strings.SplitN(image, "/", 2)
+	if len(segments) == 2 && (strings.ContainsAny(segments[0], ".:") || segments[0] == "localhost") {
+		ref.registry = segments[0]
+		ref.repository = segments[1]
+	} else {
+		ref.registry = "docker.io"
+		ref.repository = image
+	}
+	if ref.tag == "" && ref.digest == "" {
+		ref.tag = "latest"
+	}
+	return ref
+}
+
+// digestKey returns the image's registry, repository and digest, e.g. "quay.io/org/image@sha256:...", which identifies
+// the image's content regardless of its tag. Returns an empty string if the image is not referenced by digest.
+func (ref imageReference) digestKey() string {
+	if ref.digest == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s@%s", ref.registry, ref.repository, ref.digest)
+}
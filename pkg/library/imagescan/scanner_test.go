@@ -0,0 +1,231 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+type fakeHTTPClient struct {
+	responses map[string]string
+	requests  []*http.Request
+}
+
+func (f *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, req)
+	if body, ok := f.responses[req.URL.String()]; ok {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(bytes.NewBuffer([]byte{})),
+	}, nil
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image    string
+		expected imageReference
+	}{
+		{
+			image:    "quay.io/devfile/universal-developer-image:ubi8-latest",
+			expected: imageReference{registry: "quay.io", repository: "devfile/universal-developer-image", tag: "ubi8-latest"},
+		},
+		{
+			image:    "registry.example.com:5000/org/image@sha256:abcd",
+			expected: imageReference{registry: "registry.example.com:5000", repository: "org/image", digest: "sha256:abcd"},
+		},
+		{
+			image:    "golang",
+			expected: imageReference{registry: "docker.io", repository: "golang", tag: "latest"},
+		},
+		{
+			image:    "library/golang:1.20@sha256:abcd",
+			expected: imageReference{registry: "docker.io", repository: "library/golang", tag: "1.20", digest: "sha256:abcd"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseImageReference(tt.image))
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	assert.Equal(t, SeverityCritical, ParseSeverity("critical"))
+	assert.Equal(t, SeverityMedium, ParseSeverity("Medium"))
+	assert.Equal(t, SeverityUnknown, ParseSeverity("Important"))
+}
+
+func TestQuayScanner(t *testing.T) {
+	client := &fakeHTTPClient{
+		responses: map[string]string{
+			"https://quay.io/api/v1/repository/org/image/tag/?specificTag=v1&onlyActiveTags=true": `{"tags": [{"name": "v1", "manifest_digest": "sha256:1234"}]}`,
+			"https://quay.io/api/v1/repository/org/image/manifest/sha256:1234/security?vulnerabilities=true": `{
+				"status": "scanned",
+				"data": {"Layer": {"Features": [
+					{"Name": "openssl", "Vulnerabilities": [{"Name": "CVE-2024-0001", "Severity": "High"}]},
+					{"Name": "bash", "Vulnerabilities": [{"Name": "CVE-2024-0002", "Severity": "Low"}]}
+				]}}
+			}`,
+			"https://quay.io/api/v1/repository/org/queued/manifest/sha256:5678/security?vulnerabilities=true": `{"status": "queued"}`,
+		},
+	}
+	scanner := &quayScanner{baseURL: "https://quay.io", client: client, token: "test-token"}
+
+	report, err := scanner.GetReport("quay.io/org/image:v1")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportScanned, report.Status)
+		assert.Equal(t, []Vulnerability{
+			{ID: "CVE-2024-0001", Severity: SeverityHigh},
+			{ID: "CVE-2024-0002", Severity: SeverityLow},
+		}, report.Vulnerabilities)
+	}
+	assert.Equal(t, "Bearer test-token", client.requests[0].Header.Get("Authorization"), "Should authenticate requests")
+
+	report, err = scanner.GetReport("quay.io/org/queued@sha256:5678")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportPending, report.Status, "Should report queued scans as pending")
+	}
+
+	report, err = scanner.GetReport("docker.io/library/golang:1.20")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportUnsupported, report.Status, "Should not scan images from other registries")
+	}
+
+	report, err = scanner.GetReport("quay.io/org/missing:v1")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportUnsupported, report.Status, "Should not fail for missing repositories")
+	}
+}
+
+func TestClairScanner(t *testing.T) {
+	client := &fakeHTTPClient{
+		responses: map[string]string{
+			"http://clair:8080/matcher/api/v1/vulnerability_report/sha256:1234": `{
+				"vulnerabilities": {"1": {"name": "CVE-2024-0001", "normalized_severity": "Critical"}}
+			}`,
+		},
+	}
+	scanner := &clairScanner{baseURL: "http://clair:8080", client: client}
+
+	report, err := scanner.GetReport("quay.io/org/image@sha256:1234")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportScanned, report.Status)
+		assert.Equal(t, []Vulnerability{{ID: "CVE-2024-0001", Severity: SeverityCritical}}, report.Vulnerabilities)
+	}
+
+	report, err = scanner.GetReport("quay.io/org/image:v1")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportUnsupported, report.Status, "Should not scan images without digest")
+	}
+
+	report, err = scanner.GetReport("quay.io/org/image@sha256:5678")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportPending, report.Status, "Should report unindexed manifests as pending")
+	}
+}
+
+func TestTrivyScanner(t *testing.T) {
+	reportReady := false
+	var scanRequests []trivyScanRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"), "Should authenticate requests")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/scan":
+			assert.Equal(t, trivyScanRequestType, r.Header.Get("Content-Type"))
+			scanRequest := trivyScanRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&scanRequest))
+			scanRequests = append(scanRequests, scanRequest)
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id": "scan-1"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/scan/scan-1/report":
+			assert.Equal(t, trivyReportType, r.Header.Get("Accept"))
+			if !reportReady {
+				w.Header().Set("Location", r.URL.String())
+				w.WriteHeader(http.StatusFound)
+				return
+			}
+			w.Write([]byte(`{"vulnerabilities": [
+				{"id": "CVE-2024-0001", "severity": "Critical"},
+				{"id": "CVE-2024-0002", "severity": "Medium"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	scanner, err := NewScanner(&controller.ImageScanningConfig{Scanner: ScannerTrivy, URL: server.URL}, server.Client(), "test-token")
+	if !assert.NoError(t, err, "Should create Trivy scanner") {
+		return
+	}
+
+	report, err := scanner.GetReport("quay.io/org/image:v1")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportUnsupported, report.Status, "Should not scan images without digest")
+	}
+
+	report, err = scanner.GetReport("golang@sha256:1234")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportPending, report.Status, "Should report scans in progress as pending")
+	}
+	report, err = scanner.GetReport("golang@sha256:1234")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportPending, report.Status, "Should report scans in progress as pending")
+	}
+	if assert.Len(t, scanRequests, 1, "Should only request scan once") {
+		assert.Equal(t, "https://registry-1.docker.io", scanRequests[0].Registry.URL)
+		assert.Equal(t, "golang", scanRequests[0].Artifact.Repository)
+		assert.Equal(t, "sha256:1234", scanRequests[0].Artifact.Digest)
+	}
+
+	reportReady = true
+	report, err = scanner.GetReport("golang@sha256:1234")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportScanned, report.Status)
+		assert.Equal(t, []Vulnerability{
+			{ID: "CVE-2024-0001", Severity: SeverityCritical},
+			{ID: "CVE-2024-0002", Severity: SeverityMedium},
+		}, report.Vulnerabilities)
+	}
+
+	report, err = scanner.GetReport("golang:1.21@sha256:1234")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportScanned, report.Status, "Should reuse scan for same digest")
+	}
+	assert.Len(t, scanRequests, 1, "Should not request scan again for same digest")
+
+	report, err = scanner.GetReport("golang@sha256:5678")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, ReportScanned, report.Status)
+	}
+	if assert.Len(t, scanRequests, 2, "Should request scan for new digest") {
+		assert.Equal(t, "sha256:5678", scanRequests[1].Artifact.Digest)
+	}
+}
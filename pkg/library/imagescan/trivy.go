@@ -0,0 +1,187 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagescan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const (
+	trivyScanRequestType = "application/vnd.scanner.adapter.scan.request+json; version=1.0"
+	trivyReportType      = "application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+)
+
+// trivyScanIDs maps image digests to the ID of the scan requested for them, so that each digest is only scanned once.
+// IDs are kept after the report is read, as the report for a digest does not change. Scanners are created on every
+// reconcile, so the IDs are stored globally.
+var (
+	trivyScanIDs      = map[string]string{}
+	trivyScanIDsMutex sync.Mutex
+)
+
+// trivyScanner requests scans from a Trivy server through the scanner adapter API used by Harbor
+// (harbor-scanner-trivy). Scans are asynchronous: the first request for an image submits a scan, and its report is
+// retrieved by later requests once it is available.
+type trivyScanner struct {
+	baseURL string
+	client  HTTPClient
+	token   string
+}
+
+type trivyScanRequest struct {
+	Registry struct {
+		URL string `json:"url"`
+	} `json:"registry"`
+	Artifact struct {
+		Repository string `json:"repository"`
+		Digest     string `json:"digest"`
+		Tag        string `json:"tag,omitempty"`
+	} `json:"artifact"`
+}
+
+type trivyScanResponse struct {
+	ID string `json:"id"`
+}
+
+type trivyReport struct {
+	Vulnerabilities []struct {
+		ID       string `json:"id"`
+		Severity string `json:"severity"`
+	} `json:"vulnerabilities"`
+}
+
+func (s *trivyScanner) GetReport(image string) (*Report, error) {
+	ref := parseImageReference(image)
+	if ref.digest == "" {
+		return &Report{Image: image, Status: ReportUnsupported, Reason: "image must be referenced by digest"}, nil
+	}
+
+	cacheKey := s.baseURL + "|" + ref.digestKey()
+	trivyScanIDsMutex.Lock()
+	scanID, ok := trivyScanIDs[cacheKey]
+	trivyScanIDsMutex.Unlock()
+	if !ok {
+		var err error
+		scanID, err = s.requestScan(ref)
+		if err != nil {
+			return nil, err
+		}
+		trivyScanIDsMutex.Lock()
+		trivyScanIDs[cacheKey] = scanID
+		trivyScanIDsMutex.Unlock()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/scan/%s/report", s.baseURL, scanID), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.authenticate(req)
+	req.Header.Set("Accept", trivyReportType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach scanner API: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		break
+	case http.StatusFound:
+		// The scan is still in progress
+		return &Report{Image: image, Status: ReportPending}, nil
+	default:
+		// The scan failed or expired; request a new scan on the next check
+		trivyScanIDsMutex.Lock()
+		delete(trivyScanIDs, cacheKey)
+		trivyScanIDsMutex.Unlock()
+		return nil, &statusError{location: req.URL.Redacted(), statusCode: resp.StatusCode}
+	}
+
+	trivyReport := &trivyReport{}
+	if err := json.NewDecoder(resp.Body).Decode(trivyReport); err != nil {
+		return nil, fmt.Errorf("failed to parse response from scanner API: %w", err)
+	}
+
+	report := &Report{Image: image, Status: ReportScanned}
+	for _, vuln := range trivyReport.Vulnerabilities {
+		report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+			ID:       vuln.ID,
+			Severity: ParseSeverity(vuln.Severity),
+		})
+	}
+	return report, nil
+}
+
+func (s *trivyScanner) requestScan(ref imageReference) (string, error) {
+	scanRequest := trivyScanRequest{}
+	scanRequest.Registry.URL = "https://" + ref.registry
+	if ref.registry == "docker.io" {
+		scanRequest.Registry.URL = "https://registry-1.docker.io"
+	}
+	scanRequest.Artifact.Repository = ref.repository
+	scanRequest.Artifact.Digest = ref.digest
+	scanRequest.Artifact.Tag = ref.tag
+	body, err := json.Marshal(scanRequest)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.baseURL+"/api/v1/scan", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	s.authenticate(req)
+	req.Header.Set("Content-Type", trivyScanRequestType)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach scanner API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return "", &statusError{location: req.URL.Redacted(), statusCode: resp.StatusCode}
+	}
+	scanResponse := &trivyScanResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(scanResponse); err != nil {
+		return "", fmt.Errorf("failed to parse response from scanner API: %w", err)
+	}
+	if scanResponse.ID == "" {
+		return "", fmt.Errorf("scanner API did not return a scan ID")
+	}
+	return scanResponse.ID, nil
+}
+
+func (s *trivyScanner) authenticate(req *http.Request) {
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+}
+
+// withoutRedirects returns a copy of client that does not follow redirects, as the scanner adapter API responds with
+// a redirect while a scan is in progress. Clients other than *http.Client are returned unchanged.
+func withoutRedirects(client HTTPClient) HTTPClient {
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		return client
+	}
+	noRedirects := *httpClient
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &noRedirects
+}
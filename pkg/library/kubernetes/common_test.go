@@ -14,6 +14,10 @@
 package kubernetes
 
 import (
+	"io"
+	"net/http"
+	"strings"
+
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
@@ -34,3 +38,14 @@ func init() {
 	utilruntime.Must(v1alpha1.AddToScheme(testScheme))
 	utilruntime.Must(dw.AddToScheme(testScheme))
 }
+
+// fakeHTTPGetter serves manifests from a map of URIs to content. Requests for other URIs return 404.
+type fakeHTTPGetter map[string]string
+
+func (f fakeHTTPGetter) Get(location string) (*http.Response, error) {
+	content, ok := f[location]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(content))}, nil
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+)
+
+const (
+	// maxManifestSize is the maximum size of a manifest fetched from the URI of a Kubernetes or OpenShift component
+	maxManifestSize = 1024 * 1024
+	// maxCachedManifests is the maximum number of verified manifests kept in memory by the controller
+	maxCachedManifests = 64
+)
+
+// verifiedManifests caches manifests fetched from URIs by their digest, so that the content of a component is only
+// fetched again when its digest changes rather than on every reconcile.
+var verifiedManifests = struct {
+	sync.Mutex
+	content map[string][]byte
+}{content: map[string][]byte{}}
+
+// ReviewComponentManifests returns the manifests of the objects defined by a Kubernetes or OpenShift component, one
+// per YAML document in the component's inlined content or in the content fetched from its URI. If the component uses
+// a URI, the digest of the fetched content is also returned, so that the objects that are created later can be
+// checked against the content that was reviewed.
+func ReviewComponentManifests(component *dw.K8sLikeComponent, httpClient network.HTTPGetter) (manifests [][]byte, digest string, err error) {
+	switch {
+	case component.Inlined != "":
+		manifests, err = SplitManifests([]byte(component.Inlined))
+		return manifests, "", err
+	case component.Uri != "":
+		content, err := fetchManifest(component.Uri, httpClient)
+		if err != nil {
+			return nil, "", err
+		}
+		manifests, err = SplitManifests(content)
+		return manifests, ManifestDigest(content), err
+	default:
+		return nil, "", fmt.Errorf("component does not define inlined content or a URI")
+	}
+}
+
+// GetComponentManifests returns the manifests of the objects defined by a Kubernetes or OpenShift component, one
+// per YAML document in the component's inlined content or in the content fetched from its URI. Content fetched from
+// a URI is only used if it matches the digest that was recorded when the component was reviewed; an error is
+// returned if the component does not have a digest or if the content served at the URI has changed since.
+func GetComponentManifests(component *dw.K8sLikeComponent, digest string, httpClient network.HTTPGetter) ([][]byte, error) {
+	switch {
+	case component.Inlined != "":
+		return SplitManifests([]byte(component.Inlined))
+	case component.Uri != "":
+		if digest == "" {
+			return nil, fmt.Errorf("manifest from %s has not been reviewed by the DevWorkspace webhook server", component.Uri)
+		}
+		content, err := getVerifiedManifest(component.Uri, digest, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return SplitManifests(content)
+	default:
+		return nil, fmt.Errorf("component does not define inlined content or a URI")
+	}
+}
+
+// ManifestDigest returns the digest of a manifest, in the format "sha256:<hex>"
+func ManifestDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func getVerifiedManifest(uri, digest string, httpClient network.HTTPGetter) ([]byte, error) {
+	verifiedManifests.Lock()
+	content, ok := verifiedManifests.content[digest]
+	verifiedManifests.Unlock()
+	if ok {
+		return content, nil
+	}
+
+	content, err := fetchManifest(uri, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	if ManifestDigest(content) != digest {
+		return nil, fmt.Errorf("manifest served at %s has changed since it was reviewed; update the component to review it again", uri)
+	}
+
+	verifiedManifests.Lock()
+	defer verifiedManifests.Unlock()
+	if len(verifiedManifests.content) >= maxCachedManifests {
+		verifiedManifests.content = map[string][]byte{}
+	}
+	verifiedManifests.content[digest] = content
+	return content, nil
+}
+
+// ValidateObjectKind returns an error if objects of the given kind may not be created through Kubernetes or
+// OpenShift components, as they could be used to escalate the privileges of the DevWorkspace's owner.
+func ValidateObjectKind(kind string) error {
+	switch kind {
+	case "List":
+		return fmt.Errorf("lists are not supported in Kubernetes or OpenShift components")
+	case "Role", "RoleBinding", "Rolebinding", "ClusterRole", "ClusterRoleBinding":
+		return fmt.Errorf("kubernetes RBAC objects are not permitted within DevWorkspace components")
+	case "DevWorkspace", "DevWorkspaceTemplate":
+		return fmt.Errorf("DevWorkspace objects are not permitted within DevWorkspace components")
+	}
+	return nil
+}
+
+func fetchManifest(uri string, httpClient network.HTTPGetter) ([]byte, error) {
+	if httpClient == nil {
+		return nil, fmt.Errorf("cannot fetch manifest from %s: no HTTP client configured", uri)
+	}
+	resp, err := httpClient.Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest from %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch manifest from %s: got status %d", uri, resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest from %s: %w", uri, err)
+	}
+	if len(content) > maxManifestSize {
+		return nil, fmt.Errorf("manifest at %s is larger than %d bytes", uri, maxManifestSize)
+	}
+	return content, nil
+}
+
+// SplitManifests splits YAML content into its documents, ignoring documents that are empty or only contain comments
+func SplitManifests(content []byte) ([][]byte, error) {
+	var manifests [][]byte
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		var parsed map[string]interface{}
+		if err := yaml.Unmarshal(document, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse manifest: %w", err)
+		}
+		if len(parsed) == 0 {
+			continue
+		}
+		manifests = append(manifests, document)
+	}
+	if len(manifests) == 0 {
+		return nil, fmt.Errorf("manifest does not define any objects")
+	}
+	return manifests, nil
+}
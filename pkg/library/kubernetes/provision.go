@@ -14,6 +14,7 @@
 package kubernetes
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -21,6 +22,7 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,50 +34,67 @@ import (
 // creating/updating objects on the cluster. This function does not verify if the workspace owner
 // has the correct permissions to create/update/delete these objects and instead assumes the
 // workspace owner has all applicable RBAC permissions.
-// Only Kubernetes/OpenShift components that are inlined are supported; components that define
-// a URI will cause a WarningError to be returned
-func HandleKubernetesComponents(workspace *common.DevWorkspaceWithConfig, api sync.ClusterAPI) error {
-	kubeComponents, warnings, err := filterForKubeLikeComponents(workspace.Spec.Template.Components)
-	if err != nil {
-		return err
-	}
-	if len(kubeComponents) == 0 {
-		if len(warnings) > 0 {
-			return &dwerrors.WarningError{
-				Message: fmt.Sprintf("Ignored components that use unsupported features: %s", strings.Join(warnings, ", ")),
-			}
-		}
-		return nil
-	}
+// Components may define their objects inline or via a URI, in which case the manifest is fetched
+// using httpClient and must match the digest recorded in the component's KubernetesComponentDigestAttribute
+// when the webhook server reviewed it. Components with a URI that are contributed by plugins or the parent of the
+// DevWorkspace are not reviewed by the webhook server; instead, their manifests are reviewed against the permissions
+// of the DevWorkspace's creator whenever their content changes. Each YAML document in a component's manifest is created as a separate object.
+// If all objects are in sync with the cluster, they are returned as they exist on the cluster.
+func HandleKubernetesComponents(workspace *common.DevWorkspaceWithConfig, api sync.ClusterAPI, httpClient network.HTTPGetter) ([]client.Object, error) {
+	kubeComponents := filterForKubeLikeComponents(workspace.Spec.Template.Components)
+	var clusterObjs []client.Object
+	var warnings []string
 	for _, component := range kubeComponents {
 		// Ignore error as we filtered list above
 		k8sLikeComponent, _ := getK8sLikeComponent(component)
-		obj, err := deserializeToObject([]byte(k8sLikeComponent.Inlined), api)
-		if err != nil {
-			return &dwerrors.FailError{Message: fmt.Sprintf("could not process component %s", component.Name), Err: err}
-		}
-		if err := addMetadata(obj, workspace, api); err != nil {
-			return &dwerrors.RetryError{Message: fmt.Sprintf("failed to add ownerref for component %s", component.Name), Err: err}
-		}
-		if err := checkForExistingObject(obj, api); err != nil {
-			return &dwerrors.FailError{Message: fmt.Sprintf("could not process component %s", component.Name), Err: err}
-		}
-		var syncErr error
-		if sync.IsRecognizedObject(obj) {
-			_, syncErr = sync.SyncObjectWithCluster(obj, api)
+		var manifests [][]byte
+		var err error
+		if isImportedURIComponent(component, k8sLikeComponent) {
+			manifests, err = reviewImportedComponentManifests(workspace, component.Name, k8sLikeComponent, api, httpClient)
 		} else {
-			_, syncErr = sync.SyncUnrecognizedObjectWithCluster(obj, api)
+			digest := component.Attributes.GetString(constants.KubernetesComponentDigestAttribute, nil)
+			manifests, err = GetComponentManifests(k8sLikeComponent, digest, httpClient)
 		}
-		if syncErr != nil {
-			return dwerrors.WrapSyncError(syncErr)
+		if err != nil {
+			return nil, &dwerrors.FailError{Message: fmt.Sprintf("could not process component %s", component.Name), Err: err}
+		}
+		for _, manifest := range manifests {
+			obj, err := deserializeToObject(manifest, api)
+			if err != nil {
+				return nil, &dwerrors.FailError{Message: fmt.Sprintf("could not process component %s", component.Name), Err: err}
+			}
+			if err := ValidateObjectKind(obj.GetObjectKind().GroupVersionKind().Kind); err != nil {
+				return nil, &dwerrors.FailError{Message: fmt.Sprintf("could not process component %s", component.Name), Err: err}
+			}
+			if err := addMetadata(obj, workspace, api); err != nil {
+				return nil, &dwerrors.RetryError{Message: fmt.Sprintf("failed to add ownerref for component %s", component.Name), Err: err}
+			}
+			if err := checkForExistingObject(obj, api); err != nil {
+				return nil, &dwerrors.FailError{Message: fmt.Sprintf("could not process component %s", component.Name), Err: err}
+			}
+			var clusterObj client.Object
+			var syncErr error
+			if sync.IsRecognizedObject(obj) {
+				clusterObj, syncErr = sync.SyncObjectWithCluster(obj, api)
+			} else {
+				clusterObj, syncErr = sync.SyncUnrecognizedObjectWithCluster(obj, api)
+			}
+			var warningErr *sync.WarningError
+			switch {
+			case errors.As(syncErr, &warningErr):
+				warnings = append(warnings, fmt.Sprintf("component %s: %s", component.Name, warningErr.Error()))
+			case syncErr != nil:
+				return nil, dwerrors.WrapSyncError(syncErr)
+			}
+			clusterObjs = append(clusterObjs, clusterObj)
 		}
 	}
 	if len(warnings) > 0 {
-		return &dwerrors.WarningError{
-			Message: fmt.Sprintf("Ignored components that use unsupported features: %s", strings.Join(warnings, ", ")),
+		return clusterObjs, &dwerrors.WarningError{
+			Message: fmt.Sprintf("Kubernetes components may be out of sync with the cluster: %s", strings.Join(warnings, ", ")),
 		}
 	}
-	return nil
+	return clusterObjs, nil
 }
 
 func checkForExistingObject(obj client.Object, api sync.ClusterAPI) error {
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
 	"github.com/devfile/devworkspace-operator/pkg/common"
 	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
@@ -30,6 +31,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/assert"
+	authzv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,6 +51,8 @@ type testCase struct {
 type testInput struct {
 	Components      []dw.Component `json:"components,omitempty"`
 	ExistingObjects clusterObjects `json:"existingObjects,omitempty"`
+	// Manifests maps URIs used by components to the manifests served at them
+	Manifests map[string]string `json:"manifests,omitempty"`
 }
 
 type testOutput struct {
@@ -57,13 +61,15 @@ type testOutput struct {
 }
 
 type clusterObjects struct {
-	Pods     []corev1.Pod     `json:"pods,omitempty"`
-	Services []corev1.Service `json:"services,omitempty"`
+	Pods       []corev1.Pod       `json:"pods,omitempty"`
+	Services   []corev1.Service   `json:"services,omitempty"`
+	ConfigMaps []corev1.ConfigMap `json:"configMaps,omitempty"`
 }
 
 const (
 	testID               = "test-devworkspaceID"
 	testCreatorID        = "test-creatorID"
+	testCreatorUsername  = "test-creator"
 	testDevWorkspaceName = "test-devworkspace"
 	testDevWorkspaceUID  = "test-UID"
 	testNamespace        = "test-devworkspace"
@@ -76,6 +82,9 @@ var testDevWorkspace = &dw.DevWorkspace{
 		Labels: map[string]string{
 			constants.DevWorkspaceCreatorLabel: testCreatorID,
 		},
+		Annotations: map[string]string{
+			constants.DevWorkspaceCreatorUsernameAnnotation: testCreatorUsername,
+		},
 		UID: testDevWorkspaceUID,
 	},
 	Spec: dw.DevWorkspaceSpec{
@@ -98,7 +107,12 @@ func TestHandleKubernetesComponents(t *testing.T) {
 		t.Run(fmt.Sprintf("%s (%s)", tt.Name, tt.originalFilename), func(t *testing.T) {
 			testClient := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(collectClusterObj(tt.Input.ExistingObjects)...).Build()
 			api := sync.ClusterAPI{
-				Client: testClient,
+				Client: &testutil.ReviewClient{
+					Client: testClient,
+					Permissions: map[string]authzv1.ResourceAttributes{
+						testCreatorUsername: {Verb: "*", Resource: "*"},
+					},
+				},
 				Scheme: testScheme,
 				Logger: testr.New(t),
 			}
@@ -111,7 +125,8 @@ func TestHandleKubernetesComponents(t *testing.T) {
 			maxIters := 30
 			var err error
 			retryErr := &dwerrors.RetryError{}
-			for err = HandleKubernetesComponents(wksp, api); errors.As(err, &retryErr); err = HandleKubernetesComponents(wksp, api) {
+			httpClient := fakeHTTPGetter(tt.Input.Manifests)
+			for _, err = HandleKubernetesComponents(wksp, api, httpClient); errors.As(err, &retryErr); _, err = HandleKubernetesComponents(wksp, api, httpClient) {
 				i += 1
 				assert.LessOrEqual(t, i, maxIters, "HandleKubernetesComponents did no complete within %d iterations", maxIters)
 			}
@@ -198,7 +213,7 @@ func TestSecretAndConfigMapProvisioning(t *testing.T) {
 	maxIters := 30
 	var err error
 	retryErr := &dwerrors.RetryError{}
-	for err = HandleKubernetesComponents(wksp, api); errors.As(err, &retryErr); err = HandleKubernetesComponents(wksp, api) {
+	for _, err = HandleKubernetesComponents(wksp, api, nil); errors.As(err, &retryErr); _, err = HandleKubernetesComponents(wksp, api, nil) {
 		i += 1
 		assert.LessOrEqual(t, i, maxIters, "HandleKubernetesComponents did no complete within %d iterations", maxIters)
 	}
@@ -278,5 +293,10 @@ func collectClusterObj(clusterObjs clusterObjects) []client.Object {
 		svc.Namespace = testNamespace
 		objs = append(objs, &svc)
 	}
+	for _, cm := range clusterObjs.ConfigMaps {
+		cm := cm
+		cm.Namespace = testNamespace
+		objs = append(objs, &cm)
+	}
 	return objs
 }
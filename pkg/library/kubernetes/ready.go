@@ -0,0 +1,112 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+// componentReadyRequeueDelay is how long to wait before checking again whether the objects created for Kubernetes
+// components are ready, as these objects are not necessarily watched by the controller.
+const componentReadyRequeueDelay = 5 * time.Second
+
+// CheckKubernetesComponentsReady checks whether the objects created for the Kubernetes and OpenShift components of a
+// DevWorkspace are available, returning a RetryError if any of them is not ready yet and a FailError if any of
+// them has failed. Deployments and StatefulSets must have all their replicas available, Pods must be ready (or have
+// completed successfully) and Jobs must have completed. Other objects are considered ready once they exist on the
+// cluster.
+func CheckKubernetesComponentsReady(clusterObjs []client.Object) error {
+	for _, obj := range clusterObjs {
+		ready, err := checkObjectReady(obj)
+		if err != nil {
+			return &dwerrors.FailError{Message: fmt.Sprintf("%s %s failed", objectKind(obj), obj.GetName()), Err: err}
+		}
+		if !ready {
+			return &dwerrors.RetryError{
+				Message:      fmt.Sprintf("Waiting for %s %s to be ready", objectKind(obj), obj.GetName()),
+				RequeueAfter: componentReadyRequeueDelay,
+			}
+		}
+	}
+	return nil
+}
+
+// objectKind returns the kind of an object. Objects read from the cluster do not necessarily have their TypeMeta set,
+// in which case the name of the object's type is used.
+func objectKind(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	return reflect.TypeOf(obj).Elem().Name()
+}
+
+func checkObjectReady(obj client.Object) (ready bool, err error) {
+	switch typedObj := obj.(type) {
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if typedObj.Spec.Replicas != nil {
+			replicas = *typedObj.Spec.Replicas
+		}
+		if typedObj.Generation > typedObj.Status.ObservedGeneration {
+			return false, nil
+		}
+		return typedObj.Status.UpdatedReplicas >= replicas && typedObj.Status.AvailableReplicas >= replicas, nil
+	case *appsv1.StatefulSet:
+		replicas := int32(1)
+		if typedObj.Spec.Replicas != nil {
+			replicas = *typedObj.Spec.Replicas
+		}
+		if typedObj.Generation > typedObj.Status.ObservedGeneration {
+			return false, nil
+		}
+		return typedObj.Status.ReadyReplicas >= replicas, nil
+	case *corev1.Pod:
+		switch typedObj.Status.Phase {
+		case corev1.PodSucceeded:
+			return true, nil
+		case corev1.PodFailed:
+			return false, fmt.Errorf("pod is in phase %s: %s", typedObj.Status.Phase, typedObj.Status.Message)
+		}
+		for _, condition := range typedObj.Status.Conditions {
+			if condition.Type == corev1.PodReady {
+				return condition.Status == corev1.ConditionTrue, nil
+			}
+		}
+		return false, nil
+	case *batchv1.Job:
+		for _, condition := range typedObj.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job failed: %s", condition.Message)
+			}
+		}
+		return false, nil
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+func TestCheckKubernetesComponentsReady(t *testing.T) {
+	objectMeta := metav1.ObjectMeta{Name: "test-object", Namespace: testNamespace, Generation: 1}
+
+	tests := []struct {
+		name        string
+		obj         client.Object
+		expectedErr error
+	}{
+		{
+			name: "Objects without status are ready",
+			obj:  &corev1.ConfigMap{ObjectMeta: objectMeta},
+		},
+		{
+			name: "Deployment with available replicas is ready",
+			obj: &appsv1.Deployment{
+				ObjectMeta: objectMeta,
+				Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+		},
+		{
+			name: "Deployment without all replicas available is not ready",
+			obj: &appsv1.Deployment{
+				ObjectMeta: objectMeta,
+				Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(2)},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, AvailableReplicas: 1},
+			},
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name: "StatefulSet with outdated status is not ready",
+			obj: &appsv1.StatefulSet{
+				ObjectMeta: objectMeta,
+				Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+			},
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name: "Ready pod is ready",
+			obj: &corev1.Pod{
+				ObjectMeta: objectMeta,
+				Status: corev1.PodStatus{
+					Phase:      corev1.PodRunning,
+					Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+				},
+			},
+		},
+		{
+			name: "Failed pod fails",
+			obj: &corev1.Pod{
+				ObjectMeta: objectMeta,
+				Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+			},
+			expectedErr: &dwerrors.FailError{},
+		},
+		{
+			name:        "Running job is not ready",
+			obj:         &batchv1.Job{ObjectMeta: objectMeta},
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name: "Completed job is ready",
+			obj: &batchv1.Job{
+				ObjectMeta: objectMeta,
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+				},
+			},
+		},
+		{
+			name: "Failed job fails",
+			obj: &batchv1.Job{
+				ObjectMeta: objectMeta,
+				Status: batchv1.JobStatus{
+					Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}},
+				},
+			},
+			expectedErr: &dwerrors.FailError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKubernetesComponentsReady([]client.Object{tt.obj})
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.IsType(t, tt.expectedErr, err)
+		})
+	}
+}
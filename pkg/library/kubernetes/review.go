@@ -0,0 +1,129 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+	dwsync "github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// maxReviewedManifests is the maximum number of manifest reviews kept in memory by the controller
+const maxReviewedManifests = 256
+
+// ReviewVerbs are the verbs a user must be allowed to use on each object defined in a Kubernetes or OpenShift
+// component. We cannot use '*' as a verb as the SAR will check for literal '*' permissions rather than "all verbs".
+var ReviewVerbs = []string{"get", "create", "update", "delete"}
+
+// reviewedManifests records the manifests from plugins and parents that were reviewed for a user, so that they are
+// only reviewed again when their content changes.
+var reviewedManifests = struct {
+	sync.Mutex
+	reviewed map[string]bool
+}{reviewed: map[string]bool{}}
+
+// ResourceForKind returns the resource type for a kind, e.g. Pod -> pods, Deployment -> deployments.
+// This is a workaround to avoid using discovery -- probably fragile.
+func ResourceForKind(kind string) string {
+	return fmt.Sprintf("%ss", strings.ToLower(kind))
+}
+
+// isImportedURIComponent returns whether a component with a URI was contributed by a plugin or the parent of the
+// DevWorkspace without being reviewed by the webhook server. Components from DevWorkspaceTemplates keep the digest
+// recorded when the template was reviewed.
+func isImportedURIComponent(component dw.Component, k8sLikeComponent *dw.K8sLikeComponent) bool {
+	if k8sLikeComponent.Uri == "" {
+		return false
+	}
+	if component.Attributes.GetString(constants.KubernetesComponentDigestAttribute, nil) != "" {
+		return false
+	}
+	return component.Attributes.GetString(constants.PluginSourceAttribute, nil) != ""
+}
+
+// reviewImportedComponentManifests fetches the manifest of a Kubernetes or OpenShift component contributed by a
+// plugin or the parent of a DevWorkspace and checks that the creator of the DevWorkspace has the permissions required
+// to manage each object in it, as the webhook server does for components in the DevWorkspace itself. Reviews are
+// cached by the digest of the manifest, so the manifest is reviewed again if its content changes.
+func reviewImportedComponentManifests(workspace *common.DevWorkspaceWithConfig, componentName string, component *dw.K8sLikeComponent, api dwsync.ClusterAPI, httpClient network.HTTPGetter) ([][]byte, error) {
+	username := workspace.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation]
+	if username == "" {
+		return nil, fmt.Errorf("manifest from %s cannot be reviewed as the DevWorkspace does not record the name of its creator in the %s annotation",
+			component.Uri, constants.DevWorkspaceCreatorUsernameAnnotation)
+	}
+	manifests, digest, err := ReviewComponentManifests(component, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewKey := fmt.Sprintf("%s|%s|%s", workspace.Namespace, username, digest)
+	reviewedManifests.Lock()
+	reviewed := reviewedManifests.reviewed[reviewKey]
+	reviewedManifests.Unlock()
+	if reviewed {
+		return manifests, nil
+	}
+
+	for _, manifest := range manifests {
+		typeMeta := &metav1.TypeMeta{}
+		if err := yaml.Unmarshal(manifest, typeMeta); err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if err := ValidateObjectKind(typeMeta.Kind); err != nil {
+			return nil, err
+		}
+		for _, verb := range ReviewVerbs {
+			sar := &authv1.SubjectAccessReview{
+				Spec: authv1.SubjectAccessReviewSpec{
+					ResourceAttributes: &authv1.ResourceAttributes{
+						Namespace: workspace.Namespace,
+						Verb:      verb,
+						Group:     typeMeta.GroupVersionKind().Group,
+						Version:   typeMeta.GroupVersionKind().Version,
+						Resource:  ResourceForKind(typeMeta.Kind),
+					},
+					User: username,
+					UID:  workspace.Labels[constants.DevWorkspaceCreatorLabel],
+					// The other groups of the creator are not known to the controller
+					Groups: []string{"system:authenticated"},
+				},
+			}
+			if err := api.Client.Create(api.Ctx, sar); err != nil {
+				return nil, fmt.Errorf("failed to create subjectaccessreview: %w", err)
+			}
+			if !sar.Status.Allowed {
+				return nil, fmt.Errorf("user %s does not have permissions to '%s' objects of kind %s defined in component %s",
+					username, verb, typeMeta.GroupVersionKind().String(), componentName)
+			}
+		}
+	}
+
+	reviewedManifests.Lock()
+	defer reviewedManifests.Unlock()
+	if len(reviewedManifests.reviewed) >= maxReviewedManifests {
+		reviewedManifests.reviewed = map[string]bool{}
+	}
+	reviewedManifests.reviewed[reviewKey] = true
+	return manifests, nil
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	testPluginURI   = "https://example.com/plugin.yaml"
+	testManifestURI = "https://example.com/manifests.yaml"
+	testPlugin      = `
+kind: DevWorkspaceTemplate
+apiVersion: workspace.devfile.io/v1alpha2
+spec:
+  components:
+    - name: plugin-objects
+      kubernetes:
+        deployByDefault: true
+        uri: https://example.com/manifests.yaml
+`
+	testManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plugin-configmap
+data:
+  test: data
+`
+)
+
+// getFlattenedPluginWorkspace returns a DevWorkspace that uses a plugin containing a Kubernetes component with a URI,
+// flattened as it is by the controller.
+func getFlattenedPluginWorkspace(t *testing.T, httpClient fakeHTTPGetter) *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{DevWorkspace: testDevWorkspace.DeepCopy()}
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "test-plugin",
+			ComponentUnion: dw.ComponentUnion{
+				Plugin: &dw.PluginComponent{
+					ImportReference: dw.ImportReference{
+						ImportReferenceUnion: dw.ImportReferenceUnion{Uri: testPluginURI},
+					},
+				},
+			},
+		},
+	}
+	flattened, _, err := flatten.ResolveDevWorkspace(&workspace.Spec.Template, nil, flatten.ResolverTools{
+		Context:    context.Background(),
+		HttpClient: httpClient,
+	})
+	if !assert.NoError(t, err, "Should flatten DevWorkspace") {
+		t.FailNow()
+	}
+	workspace.Spec.Template = *flattened
+	return workspace
+}
+
+// handleKubernetesComponents repeats HandleKubernetesComponents as long as it returns a RetryError
+func handleKubernetesComponents(workspace *common.DevWorkspaceWithConfig, api sync.ClusterAPI, httpClient fakeHTTPGetter) error {
+	retryErr := &dwerrors.RetryError{}
+	_, err := HandleKubernetesComponents(workspace, api, httpClient)
+	for i := 0; i < 30 && errors.As(err, &retryErr); i++ {
+		_, err = HandleKubernetesComponents(workspace, api, httpClient)
+	}
+	return err
+}
+
+func getReviewTestAPI(t *testing.T, permissions map[string]authzv1.ResourceAttributes) sync.ClusterAPI {
+	return sync.ClusterAPI{
+		Client: &testutil.ReviewClient{
+			Client:      fake.NewClientBuilder().WithScheme(testScheme).Build(),
+			Permissions: permissions,
+		},
+		Scheme: testScheme,
+		Logger: testr.New(t),
+	}
+}
+
+func TestComponentFromPluginURIIsReviewedForCreator(t *testing.T) {
+	if err := InitializeDeserializer(testScheme); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer func() { decoder = nil }()
+	reviewedManifests.reviewed = map[string]bool{}
+
+	httpClient := fakeHTTPGetter{testPluginURI: testPlugin, testManifestURI: testManifest}
+	workspace := getFlattenedPluginWorkspace(t, httpClient)
+
+	deniedAPI := getReviewTestAPI(t, map[string]authzv1.ResourceAttributes{
+		testCreatorUsername: {Verb: "get", Resource: "configmaps"},
+	})
+	err := handleKubernetesComponents(workspace, deniedAPI, httpClient)
+	if assert.Error(t, err, "Should not create objects the creator cannot manage") {
+		assert.Regexp(t, "user test-creator does not have permissions to 'create' objects of kind /v1, Kind=ConfigMap defined in component plugin-objects", err.Error())
+	}
+
+	allowedAPI := getReviewTestAPI(t, map[string]authzv1.ResourceAttributes{
+		testCreatorUsername: {Verb: "*", Resource: "configmaps"},
+	})
+	err = handleKubernetesComponents(workspace, allowedAPI, httpClient)
+	if assert.NoError(t, err, "Should create objects the creator can manage") {
+		configMap := &corev1.ConfigMap{}
+		err := allowedAPI.Client.Get(context.Background(), types.NamespacedName{Name: "plugin-configmap", Namespace: testNamespace}, configMap)
+		assert.NoError(t, err, "ConfigMap from plugin manifest should be created")
+	}
+
+	// Changed content is reviewed again
+	httpClient[testManifestURI] = testManifest + `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: plugin-secret
+`
+	err = handleKubernetesComponents(workspace, allowedAPI, httpClient)
+	if assert.Error(t, err, "Should review changed manifest") {
+		assert.Regexp(t, "user test-creator does not have permissions to 'get' objects of kind /v1, Kind=Secret defined in component plugin-objects", err.Error())
+	}
+}
+
+func TestComponentFromPluginURIRequiresCreatorUsername(t *testing.T) {
+	if err := InitializeDeserializer(testScheme); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer func() { decoder = nil }()
+	reviewedManifests.reviewed = map[string]bool{}
+
+	httpClient := fakeHTTPGetter{testPluginURI: testPlugin, testManifestURI: testManifest}
+	workspace := getFlattenedPluginWorkspace(t, httpClient)
+	delete(workspace.Annotations, constants.DevWorkspaceCreatorUsernameAnnotation)
+
+	api := getReviewTestAPI(t, map[string]authzv1.ResourceAttributes{
+		testCreatorUsername: {Verb: "*", Resource: "*"},
+	})
+	err := handleKubernetesComponents(workspace, api, httpClient)
+	if assert.Error(t, err, "Should not create objects if the creator is unknown") {
+		assert.Regexp(t, "cannot be reviewed as the DevWorkspace does not record the name of its creator", err.Error())
+	}
+}
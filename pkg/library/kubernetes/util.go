@@ -30,7 +30,7 @@ func HasKubelikeComponent(workspace *common.DevWorkspaceWithConfig) bool {
 	return false
 }
 
-func filterForKubeLikeComponents(components []dw.Component) (kubeComponents []dw.Component, warnings []string, err error) {
+func filterForKubeLikeComponents(components []dw.Component) []dw.Component {
 	var k8sLikeComponents []dw.Component
 	for _, component := range components {
 		k8sLikeComponent, err := getK8sLikeComponent(component)
@@ -44,21 +44,13 @@ func filterForKubeLikeComponents(components []dw.Component) (kubeComponents []dw
 			continue
 		}
 
-		if k8sLikeComponent.Uri != "" {
-			// Not currently supported; return a warning and ignore it but do not fail startup
-			warnings = append(warnings, fmt.Sprintf("component %s defines a Kubernetes/OpenShift component via URI", component.Name))
+		if k8sLikeComponent.Inlined == "" && k8sLikeComponent.Uri == "" {
 			continue
 		}
 
-		if k8sLikeComponent.Inlined == "" {
-			continue
-		}
-
-		if k8sLikeComponent.GetDeployByDefault() {
-			k8sLikeComponents = append(k8sLikeComponents, component)
-		}
+		k8sLikeComponents = append(k8sLikeComponents, component)
 	}
-	return k8sLikeComponents, warnings, nil
+	return k8sLikeComponents
 }
 
 // getK8sLikeComponent returns the K8sLikeComponent from a DevWorkspace component,
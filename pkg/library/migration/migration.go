@@ -0,0 +1,104 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package migration detects DevWorkspaces whose objects were generated by an older version of the DevWorkspace
+// Operator in a shape that the current version no longer uses, so that they can be reconciled again after an operator
+// upgrade.
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/version"
+)
+
+// requiredEnvVars are the environment variables that the current version of the DevWorkspace Operator adds to all
+// containers of a DevWorkspace. Containers that lack any of them were created with older environment variable names.
+var requiredEnvVars = []string{
+	constants.DevWorkspaceNamespace,
+	constants.DevWorkspaceName,
+	constants.DevWorkspaceId,
+}
+
+// GetSpecHash returns a hash of a flattened DevWorkspace template, which is recorded on DevWorkspaces to identify the
+// spec that they were last reconciled with.
+func GetSpecHash(flattened *dw.DevWorkspaceTemplateSpec) (string, error) {
+	content, err := json.Marshal(flattened)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize flattened DevWorkspace: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// IsOutdated returns whether a running DevWorkspace was last reconciled by a different version of the DevWorkspace
+// Operator, according to its DevWorkspaceOperatorVersionAnnotation. DevWorkspaces that are not running are not
+// outdated, as their objects are regenerated when they are started.
+func IsOutdated(workspace *dw.DevWorkspace) bool {
+	if !workspace.Spec.Started || workspace.Status.Phase != dw.DevWorkspaceStatusRunning {
+		return false
+	}
+	return workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation] != version.Version
+}
+
+// GetDeprecatedShapes returns a sorted list of descriptions of the ways in which a DevWorkspace's deployment differs
+// from the deployment the current version of the DevWorkspace Operator would create, e.g. because it uses old labels
+// or environment variable names. Returns an empty list if the deployment is up to date.
+func GetDeprecatedShapes(workspace *dw.DevWorkspace, deployment *appsv1.Deployment) []string {
+	var shapes []string
+	workspaceId := workspace.Status.DevWorkspaceId
+	selector := deployment.Spec.Selector
+	if selector == nil || len(selector.MatchExpressions) > 0 || len(selector.MatchLabels) != 1 ||
+		selector.MatchLabels[constants.DevWorkspaceIDLabel] != workspaceId {
+		shapes = append(shapes, "deployment label selector")
+	}
+	if deployment.Labels[constants.DevWorkspaceIDLabel] != workspaceId || deployment.Labels[constants.DevWorkspaceNameLabel] != workspace.Name {
+		shapes = append(shapes, "deployment labels")
+	}
+	podLabels := deployment.Spec.Template.Labels
+	if podLabels[constants.DevWorkspaceIDLabel] != workspaceId || podLabels[constants.DevWorkspaceNameLabel] != workspace.Name {
+		shapes = append(shapes, "pod labels")
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if missing := getMissingEnvVars(container); len(missing) > 0 {
+			shapes = append(shapes, fmt.Sprintf("environment variables of container %s", container.Name))
+		}
+	}
+	sort.Strings(shapes)
+	return shapes
+}
+
+func getMissingEnvVars(container corev1.Container) []string {
+	present := map[string]bool{}
+	for _, env := range container.Env {
+		present[env.Name] = true
+	}
+	var missing []string
+	for _, name := range requiredEnvVars {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package migration
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/version"
+)
+
+func getTestWorkspace(phase dw.DevWorkspacePhase, operatorVersion string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", phase)
+	workspace.Spec.Started = true
+	if operatorVersion != "" {
+		workspace.Annotations[constants.DevWorkspaceOperatorVersionAnnotation] = operatorVersion
+	}
+	return workspace
+}
+
+func getTestDeployment() *appsv1.Deployment {
+	labels := map[string]string{
+		constants.DevWorkspaceIDLabel:   "test-workspaceid",
+		constants.DevWorkspaceNameLabel: "test-workspace",
+	}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{constants.DevWorkspaceIDLabel: "test-workspaceid"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "tools",
+							Env: []corev1.EnvVar{
+								{Name: constants.DevWorkspaceNamespace, Value: "test-namespace"},
+								{Name: constants.DevWorkspaceName, Value: "test-workspace"},
+								{Name: constants.DevWorkspaceId, Value: "test-workspaceid"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestIsOutdated(t *testing.T) {
+	assert.True(t, IsOutdated(getTestWorkspace(dw.DevWorkspaceStatusRunning, "v0.1.0")), "Should be outdated if reconciled by other version")
+	assert.True(t, IsOutdated(getTestWorkspace(dw.DevWorkspaceStatusRunning, "")), "Should be outdated if version is not recorded")
+	assert.False(t, IsOutdated(getTestWorkspace(dw.DevWorkspaceStatusRunning, version.Version)))
+	assert.False(t, IsOutdated(getTestWorkspace(dw.DevWorkspaceStatusStopped, "v0.1.0")), "Should ignore DevWorkspaces that are not running")
+}
+
+func TestGetDeprecatedShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*appsv1.Deployment)
+		expected []string
+	}{
+		{
+			name:   "Up to date deployment",
+			modify: func(*appsv1.Deployment) {},
+		},
+		{
+			name: "Old label selector",
+			modify: func(deployment *appsv1.Deployment) {
+				deployment.Spec.Selector.MatchLabels = map[string]string{"legacy-label": "test-workspaceid"}
+			},
+			expected: []string{"deployment label selector"},
+		},
+		{
+			name: "Old labels",
+			modify: func(deployment *appsv1.Deployment) {
+				deployment.Labels = map[string]string{constants.DevWorkspaceIDLabel: "test-workspaceid"}
+				deployment.Spec.Template.Labels = map[string]string{"legacy-label": "test-workspaceid"}
+			},
+			expected: []string{"deployment labels", "pod labels"},
+		},
+		{
+			name: "Old environment variable names",
+			modify: func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "CHE_WORKSPACE_ID", Value: "test-workspaceid"}}
+			},
+			expected: []string{"environment variables of container tools"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployment := getTestDeployment()
+			tt.modify(deployment)
+			assert.Equal(t, tt.expected, GetDeprecatedShapes(getTestWorkspace(dw.DevWorkspaceStatusRunning, ""), deployment))
+		})
+	}
+}
+
+func TestGetSpecHash(t *testing.T) {
+	template := &dw.DevWorkspaceTemplateSpec{}
+	template.Components = []dw.Component{{Name: "tools"}}
+	hash, err := GetSpecHash(template)
+	assert.NoError(t, err)
+	sameHash, err := GetSpecHash(template.DeepCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash, "Hash should be stable")
+
+	template.Components[0].Name = "other"
+	otherHash, err := GetSpecHash(template)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash, "Hash should change when spec changes")
+}
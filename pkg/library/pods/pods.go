@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package pods looks up the pods that run DevWorkspaces.
+package pods
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// GetWorkspacePods returns the pods of the DevWorkspace with ID workspaceId that are not being deleted. Pods created
+// by jobs for the DevWorkspace (e.g. for DevWorkspaceTasks or snapshots) share its ID label, but are not returned.
+func GetWorkspacePods(ctx context.Context, c client.Reader, namespace, workspaceId string) ([]corev1.Pod, error) {
+	if workspaceId == "" {
+		return nil, nil
+	}
+	podList := &corev1.PodList{}
+	err := c.List(ctx, podList,
+		client.InNamespace(namespace),
+		client.MatchingLabels{constants.DevWorkspaceIDLabel: workspaceId})
+	if err != nil {
+		return nil, err
+	}
+	var workspacePods []corev1.Pod
+	for _, pod := range podList.Items {
+		if _, isJobPod := pod.Labels["job-name"]; isJobPod || pod.DeletionTimestamp != nil {
+			continue
+		}
+		workspacePods = append(workspacePods, pod)
+	}
+	return workspacePods, nil
+}
+
+// GetRunningWorkspacePod returns a running pod of a DevWorkspace, or nil if there is none.
+func GetRunningWorkspacePod(ctx context.Context, c client.Reader, namespace, workspaceId string) (*corev1.Pod, error) {
+	workspacePods, err := GetWorkspacePods(ctx, c, namespace, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	for idx, pod := range workspacePods {
+		if pod.Status.Phase == corev1.PodRunning {
+			return &workspacePods[idx], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetNewestWorkspacePod returns the most recently created pod of a DevWorkspace, or nil if there is none. The pod is
+// not necessarily running; e.g. while a DevWorkspace starts or is updated, it may still be pending.
+func GetNewestWorkspacePod(ctx context.Context, c client.Reader, namespace, workspaceId string) (*corev1.Pod, error) {
+	workspacePods, err := GetWorkspacePods(ctx, c, namespace, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	var newest *corev1.Pod
+	for idx, pod := range workspacePods {
+		if newest == nil || newest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			newest = &workspacePods[idx]
+		}
+	}
+	return newest, nil
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package pods
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	testNamespace   = "test-namespace"
+	testWorkspaceID = "test-workspace-id"
+)
+
+var creationTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func getTestPod(name string, phase corev1.PodPhase, createdMinutesAgo int) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			CreationTimestamp: metav1.NewTime(creationTime.Add(-time.Duration(createdMinutesAgo) * time.Minute)),
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel: testWorkspaceID,
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func getTestClient(objs ...client.Object) client.Client {
+	jobPod := getTestPod("job-pod", corev1.PodRunning, 0)
+	jobPod.Labels["job-name"] = "test-job"
+	deletedPod := getTestPod("deleted-pod", corev1.PodRunning, 0)
+	deletionTime := metav1.NewTime(creationTime)
+	deletedPod.DeletionTimestamp = &deletionTime
+	deletedPod.Finalizers = []string{"test-finalizer"}
+	otherWorkspacePod := getTestPod("other-workspace-pod", corev1.PodRunning, 0)
+	otherWorkspacePod.Labels[constants.DevWorkspaceIDLabel] = "other-workspace-id"
+	objs = append(objs, jobPod, deletedPod, otherWorkspacePod)
+	return testutil.NewFakeClient(objs...)
+}
+
+func TestGetWorkspacePodsIgnoresJobAndDeletedPods(t *testing.T) {
+	c := getTestClient(getTestPod("workspace-pod", corev1.PodRunning, 1))
+
+	workspacePods, err := GetWorkspacePods(context.TODO(), c, testNamespace, testWorkspaceID)
+	assert.NoError(t, err)
+	if assert.Len(t, workspacePods, 1) {
+		assert.Equal(t, "workspace-pod", workspacePods[0].Name)
+	}
+}
+
+func TestGetWorkspacePodsWithoutWorkspaceID(t *testing.T) {
+	c := getTestClient(getTestPod("workspace-pod", corev1.PodRunning, 1))
+
+	workspacePods, err := GetWorkspacePods(context.TODO(), c, testNamespace, "")
+	assert.NoError(t, err)
+	assert.Empty(t, workspacePods)
+}
+
+func TestGetRunningWorkspacePod(t *testing.T) {
+	c := getTestClient(
+		getTestPod("pending-pod", corev1.PodPending, 1),
+		getTestPod("running-pod", corev1.PodRunning, 2),
+	)
+
+	pod, err := GetRunningWorkspacePod(context.TODO(), c, testNamespace, testWorkspaceID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, pod) {
+		assert.Equal(t, "running-pod", pod.Name)
+	}
+}
+
+func TestGetRunningWorkspacePodReturnsNilIfNoPodIsRunning(t *testing.T) {
+	c := getTestClient(getTestPod("pending-pod", corev1.PodPending, 1))
+
+	pod, err := GetRunningWorkspacePod(context.TODO(), c, testNamespace, testWorkspaceID)
+	assert.NoError(t, err)
+	assert.Nil(t, pod)
+}
+
+func TestGetNewestWorkspacePod(t *testing.T) {
+	c := getTestClient(
+		getTestPod("old-pod", corev1.PodRunning, 10),
+		getTestPod("new-pod", corev1.PodPending, 1),
+	)
+
+	pod, err := GetNewestWorkspacePod(context.TODO(), c, testNamespace, testWorkspaceID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, pod) {
+		assert.Equal(t, "new-pod", pod.Name)
+	}
+}
@@ -0,0 +1,186 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package projects
+
+import (
+	"fmt"
+	"time"
+
+	devfileConstants "github.com/devfile/devworkspace-operator/pkg/library/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	projectBackupContainerName  = "project-backup"
+	projectRestoreContainerName = "project-restore"
+	projectBackupVolumeName     = "project-backup"
+	projectBackupMountPath      = "/project-backup"
+
+	// projectBackupScript periodically archives the projects volume and uploads it to object storage. A final backup
+	// is attempted when the container is terminated. Empty projects volumes are never uploaded, to avoid overwriting
+	// an existing backup.
+	projectBackupScript = `backup() {
+  if [ -z "$(ls -A "${PROJECTS_ROOT}")" ]; then
+    return 0
+  fi
+  tar -czf "` + projectBackupMountPath + `/projects.tar.gz" -C "${PROJECTS_ROOT}" . && \
+  curl --fail --silent --show-error --aws-sigv4 "aws:amz:${AWS_REGION}:s3" --user "${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}" \
+    --upload-file "` + projectBackupMountPath + `/projects.tar.gz" "${BACKUP_URL}" && \
+  echo "Backed up ${PROJECTS_ROOT} at $(date -u +%Y-%m-%dT%H:%M:%SZ)"
+}
+trap 'backup; exit 0' TERM
+while true; do
+  sleep "${BACKUP_INTERVAL}" &
+  wait $!
+  backup || echo "Failed to back up ${PROJECTS_ROOT}"
+done`
+
+	// projectRestoreScript downloads the latest backup into an empty projects volume. A missing backup is not an error,
+	// but any other failure is, to avoid a later backup overwriting the existing one with freshly-cloned projects.
+	projectRestoreScript = `set -e
+if [ -n "$(ls -A "${PROJECTS_ROOT}")" ]; then
+  echo "${PROJECTS_ROOT} is not empty; not restoring backup"
+  exit 0
+fi
+status=$(curl --silent --show-error --aws-sigv4 "aws:amz:${AWS_REGION}:s3" --user "${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}" \
+  --output "` + projectBackupMountPath + `/projects.tar.gz" --write-out "%{http_code}" "${BACKUP_URL}")
+case "${status}" in
+  200)
+    tar -xzf "` + projectBackupMountPath + `/projects.tar.gz" -C "${PROJECTS_ROOT}"
+    echo "Restored ${PROJECTS_ROOT} from backup"
+    ;;
+  404)
+    echo "No backup found for ${PROJECTS_ROOT}"
+    ;;
+  *)
+    echo "Failed to download backup: HTTP status ${status}"
+    exit 1
+    ;;
+esac`
+)
+
+var (
+	projectBackupMemoryLimit   = resource.MustParse(constants.SnapshotPodMemoryLimit)
+	projectBackupMemoryRequest = resource.MustParse(constants.SnapshotPodMemoryRequest)
+	projectBackupCPULimit      = resource.MustParse(constants.SnapshotPodCPULimit)
+	projectBackupCPURequest    = resource.MustParse(constants.SnapshotPodCPURequest)
+)
+
+type BackupOptions struct {
+	Image      string
+	PullPolicy corev1.PullPolicy
+	// URL is the URL of the backup archive in object storage
+	URL    string
+	Region string
+	// Interval is the time between backups while the workspace is running
+	Interval time.Duration
+	// CredentialsSecretName is the name of a secret in the workspace's namespace that contains the keys
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+	CredentialsSecretName string
+}
+
+// UsesProjectsVolume returns whether any of the containers mounts the projects volume
+func UsesProjectsVolume(containers []corev1.Container) bool {
+	for _, container := range containers {
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name == devfileConstants.ProjectsVolumeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetProjectBackupContainers returns an init container that restores the projects volume from a backup if the
+// volume is empty, a sidecar container that periodically backs up the projects volume, and the scratch volume
+// both containers use to store the backup archive. The restore container must run before the project-clone
+// init container.
+func GetProjectBackupContainers(options BackupOptions) (restore, backup *corev1.Container, volume *corev1.Volume) {
+	credentialEnv := func(key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: options.CredentialsSecretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+	env := []corev1.EnvVar{
+		{Name: "BACKUP_URL", Value: options.URL},
+		{Name: "BACKUP_INTERVAL", Value: fmt.Sprintf("%d", int64(options.Interval.Seconds()))},
+		{Name: "AWS_REGION", Value: options.Region},
+		{Name: "PROJECTS_ROOT", Value: constants.DefaultProjectsSourcesRoot},
+		credentialEnv("AWS_ACCESS_KEY_ID"),
+		credentialEnv("AWS_SECRET_ACCESS_KEY"),
+	}
+	getContainer := func(name, script string) *corev1.Container {
+		return &corev1.Container{
+			Name:    name,
+			Image:   options.Image,
+			Command: []string{"/bin/sh"},
+			Args:    []string{"-c", script},
+			Env:     env,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: projectBackupMemoryRequest,
+					corev1.ResourceCPU:    projectBackupCPURequest,
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: projectBackupMemoryLimit,
+					corev1.ResourceCPU:    projectBackupCPULimit,
+				},
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      devfileConstants.ProjectsVolumeName,
+					MountPath: constants.DefaultProjectsSourcesRoot,
+				},
+				{
+					Name:      projectBackupVolumeName,
+					MountPath: projectBackupMountPath,
+				},
+			},
+			ImagePullPolicy: options.PullPolicy,
+		}
+	}
+
+	volume = &corev1.Volume{
+		Name: projectBackupVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	return getContainer(projectRestoreContainerName, projectRestoreScript), getContainer(projectBackupContainerName, projectBackupScript), volume
+}
+
+// InsertProjectRestoreContainer adds the project restore init container to a list of init containers, ensuring it
+// runs before the project-clone init container so that restored projects are not cloned again.
+func InsertProjectRestoreContainer(initContainers []corev1.Container, restore corev1.Container) []corev1.Container {
+	for idx, container := range initContainers {
+		if container.Name == ProjectCloneContainerName {
+			result := make([]corev1.Container, 0, len(initContainers)+1)
+			result = append(result, initContainers[:idx]...)
+			result = append(result, restore)
+			return append(result, initContainers[idx:]...)
+		}
+	}
+	return append(initContainers, restore)
+}
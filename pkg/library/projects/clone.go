@@ -31,7 +31,8 @@ import (
 )
 
 const (
-	projectClonerContainerName = "project-clone"
+	// ProjectCloneContainerName is the name of the init container that clones projects into the workspace
+	ProjectCloneContainerName = "project-clone"
 )
 
 type Options struct {
@@ -118,7 +119,7 @@ func GetProjectCloneInitContainer(workspace *dw.DevWorkspaceTemplateSpec, option
 	}
 
 	return &corev1.Container{
-		Name:      projectClonerContainerName,
+		Name:      ProjectCloneContainerName,
 		Image:     cloneImage,
 		Env:       options.Env,
 		Resources: *resources,
@@ -132,8 +133,15 @@ func GetProjectCloneInitContainer(workspace *dw.DevWorkspaceTemplateSpec, option
 	}, nil
 }
 
+// GetStarterProject returns the starter project that should be cloned into the workspace. If the workspace selects
+// a starter project via the StarterProjectAttribute, that starter project is returned. Otherwise, if the workspace
+// does not define any projects, the first starter project is used. Returns nil if no starter project should be cloned.
 func GetStarterProject(workspace *dw.DevWorkspaceTemplateSpec) (*dw.StarterProject, error) {
 	if !workspace.Attributes.Exists(constants.StarterProjectAttribute) {
+		if UsesDefaultStarterProject(workspace) {
+			starterProject := workspace.StarterProjects[0]
+			return &starterProject, nil
+		}
 		return nil, nil
 	}
 	var err error
@@ -150,6 +158,16 @@ func GetStarterProject(workspace *dw.DevWorkspaceTemplateSpec) (*dw.StarterProje
 	return nil, fmt.Errorf("selected starter project %s not found in workspace starterProjects", selectedStarterProject)
 }
 
+// UsesDefaultStarterProject returns whether the first starter project in the workspace will be cloned because the
+// workspace defines starter projects but no projects, and does not select a starter project via the
+// StarterProjectAttribute.
+func UsesDefaultStarterProject(workspace *dw.DevWorkspaceTemplateSpec) bool {
+	if workspace.Attributes.Exists(constants.StarterProjectAttribute) {
+		return false
+	}
+	return len(workspace.Projects) == 0 && len(workspace.StarterProjects) > 0
+}
+
 // GetClonePath gets the correct clonePath for a project, given the semantics in devfile/api
 func GetClonePath(project *dw.Project) string {
 	if project.ClonePath != "" {
@@ -0,0 +1,97 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package projects
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetStarterProject(t *testing.T) {
+	starterProjects := []dw.StarterProject{{Name: "first-starter"}, {Name: "second-starter"}}
+	tests := []struct {
+		name            string
+		workspace       *dw.DevWorkspaceTemplateSpec
+		expectedStarter string
+		expectedDefault bool
+		errRegexp       string
+	}{
+		{
+			name: "Uses starter project from attribute",
+			workspace: &dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Attributes:      attributes.Attributes{}.PutString(constants.StarterProjectAttribute, "second-starter"),
+					StarterProjects: starterProjects,
+				},
+			},
+			expectedStarter: "second-starter",
+		},
+		{
+			name: "Defaults to first starter project when no projects are defined",
+			workspace: &dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					StarterProjects: starterProjects,
+				},
+			},
+			expectedStarter: "first-starter",
+			expectedDefault: true,
+		},
+		{
+			name: "Does not use starter project when projects are defined",
+			workspace: &dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Projects:        []dw.Project{{Name: "project"}},
+					StarterProjects: starterProjects,
+				},
+			},
+		},
+		{
+			name: "Returns error when selected starter project does not exist",
+			workspace: &dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Attributes:      attributes.Attributes{}.PutString(constants.StarterProjectAttribute, "missing-starter"),
+					StarterProjects: starterProjects,
+				},
+			},
+			errRegexp: "selected starter project missing-starter not found in workspace starterProjects",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starterProject, err := GetStarterProject(tt.workspace)
+			if tt.errRegexp != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+				}
+				return
+			}
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			if tt.expectedStarter == "" {
+				assert.Nil(t, starterProject, "Should not return a starter project")
+			} else if assert.NotNil(t, starterProject, "Should return a starter project") {
+				assert.Equal(t, tt.expectedStarter, starterProject.Name)
+			}
+			assert.Equal(t, tt.expectedDefault, UsesDefaultStarterProject(tt.workspace))
+		})
+	}
+}
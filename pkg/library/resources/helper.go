@@ -17,11 +17,16 @@ package resources
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 )
 
 // Takes a component and returns the resource requests and limits that it defines.
@@ -76,9 +81,51 @@ func ParseResourcesFromComponent(component *dw.Component) (*corev1.ResourceRequi
 		resources.Requests[corev1.ResourceCPU] = cpuRequest
 	}
 
+	if err := parseExtendedResources(component, resources); err != nil {
+		return nil, err
+	}
+
 	return resources, nil
 }
 
+// parseExtendedResources adds the extended resources and hugepages defined in the component's
+// ExtendedResourcesAttribute to both the requests and limits in resources. Returns an error if the attribute
+// cannot be read, or if it defines a resource that is not an extended resource or hugepages.
+func parseExtendedResources(component *dw.Component, resources *corev1.ResourceRequirements) error {
+	if !component.Attributes.Exists(constants.ExtendedResourcesAttribute) {
+		return nil
+	}
+	extendedResources := map[string]intstr.IntOrString{}
+	if err := component.Attributes.GetInto(constants.ExtendedResourcesAttribute, &extendedResources); err != nil {
+		return fmt.Errorf("failed to read attribute %s on container component %s: %w", constants.ExtendedResourcesAttribute, component.Name, err)
+	}
+	for name, value := range extendedResources {
+		if !isExtendedResourceName(name) {
+			return fmt.Errorf("resource %s in attribute %s on container component %s is not an extended resource or hugepages", name, constants.ExtendedResourcesAttribute, component.Name)
+		}
+		quantity, err := resource.ParseQuantity(value.String())
+		if err != nil {
+			return fmt.Errorf("failed to parse quantity of resource %s for container component %s: %w", name, component.Name, err)
+		}
+		// Extended resources and hugepages cannot be overcommitted, so requests must be equal to limits
+		resources.Limits[corev1.ResourceName(name)] = quantity
+		resources.Requests[corev1.ResourceName(name)] = quantity.DeepCopy()
+	}
+	return nil
+}
+
+// isExtendedResourceName returns whether name can be requested via the ExtendedResourcesAttribute, i.e. whether it
+// is hugepages or a fully-qualified resource name outside the kubernetes.io domain (e.g. nvidia.com/gpu).
+func isExtendedResourceName(name string) bool {
+	if strings.HasPrefix(name, corev1.ResourceHugePagesPrefix) {
+		return true
+	}
+	if !strings.Contains(name, "/") || strings.Contains(name, corev1.ResourceDefaultNamespacePrefix) {
+		return false
+	}
+	return len(validation.IsQualifiedName(name)) == 0
+}
+
 // Adds the resource limits and requests that are set in the component "toAdd" to "resources".
 // Returns an error if the "resources" defined in "toAdd" could not be parsed.
 //
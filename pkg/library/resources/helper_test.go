@@ -17,10 +17,14 @@ import (
 	"testing"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/yaml"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 )
 
 func TestParseResourcesFromComponent(t *testing.T) {
@@ -82,6 +86,71 @@ func TestParseResourcesFromComponent(t *testing.T) {
 	}
 }
 
+func TestParseExtendedResourcesFromComponent(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources map[string]interface{}
+		expected  *corev1.ResourceRequirements
+		errRegexp string
+	}{
+		{
+			name: "Adds extended resources to requests and limits",
+			resources: map[string]interface{}{
+				"nvidia.com/gpu": 1,
+				"hugepages-2Mi":  "512Mi",
+			},
+			expected: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory:                  resource.MustParse("1000Mi"),
+					"nvidia.com/gpu":                       resource.MustParse("1"),
+					corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("512Mi"),
+				},
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory:                  resource.MustParse("100Mi"),
+					"nvidia.com/gpu":                       resource.MustParse("1"),
+					corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("512Mi"),
+				},
+			},
+		},
+		{
+			name:      "Returns error for standard resources",
+			resources: map[string]interface{}{"cpu": "1"},
+			errRegexp: "resource cpu in attribute controller.devfile.io/extended-resources on container component test-component is not an extended resource or hugepages",
+		},
+		{
+			name:      "Returns error for resources in kubernetes.io domain",
+			resources: map[string]interface{}{"kubernetes.io/test": "1"},
+			errRegexp: "resource kubernetes.io/test in attribute .* is not an extended resource or hugepages",
+		},
+		{
+			name:      "Returns error when cannot parse quantity",
+			resources: map[string]interface{}{"nvidia.com/gpu": "test"},
+			errRegexp: "failed to parse quantity of resource nvidia.com/gpu for container component test-component.*",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := &dw.Component{
+				Name: "test-component",
+				Attributes: attributes.Attributes{}.FromMap(map[string]interface{}{
+					constants.ExtendedResourcesAttribute: tt.resources,
+				}, nil),
+			}
+			component.Container = getContainerComponent("1000Mi", "100Mi", "", "")
+			actual, err := ParseResourcesFromComponent(component)
+			if tt.errRegexp != "" {
+				if assert.Error(t, err) {
+					assert.Regexp(t, tt.errRegexp, err.Error())
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.True(t, equality.Semantic.DeepEqual(tt.expected, actual), "Expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
 func TestAddResourceRequirements(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package restrictions
+
+import (
+	"fmt"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/overrides"
+)
+
+// GetDisabledFeatureUsages returns a description of each use of a disabled devfile feature in a DevWorkspace template,
+// e.g. "component 'my-component' is a kubernetes component (kubernetes-components)". If the template does not use
+// any disabled features, an empty slice is returned.
+func GetDisabledFeatureUsages(template *dw.DevWorkspaceTemplateSpec, disabledFeatures []controller.DevfileFeature) []string {
+	disabled := map[controller.DevfileFeature]bool{}
+	for _, feature := range disabledFeatures {
+		disabled[feature] = true
+	}
+	if len(disabled) == 0 {
+		return nil
+	}
+
+	var usages []string
+	addUsage := func(feature controller.DevfileFeature, format string, args ...interface{}) {
+		if disabled[feature] {
+			usages = append(usages, fmt.Sprintf("%s (%s)", fmt.Sprintf(format, args...), feature))
+		}
+	}
+
+	if template.Attributes.Exists(constants.PodOverridesAttribute) {
+		addUsage(controller.PodOverridesFeature, "DevWorkspace uses the %s attribute", constants.PodOverridesAttribute)
+	}
+	if template.Attributes.Exists(constants.WorkspaceSCCAttribute) {
+		addUsage(controller.SCCFeature, "DevWorkspace uses the %s attribute", constants.WorkspaceSCCAttribute)
+	}
+	for _, component := range template.Components {
+		switch {
+		case component.Kubernetes != nil:
+			addUsage(controller.KubernetesComponentsFeature, "component '%s' is a kubernetes component", component.Name)
+		case component.Openshift != nil:
+			addUsage(controller.OpenShiftComponentsFeature, "component '%s' is an openshift component", component.Name)
+		case component.Image != nil:
+			addUsage(controller.ImageComponentsFeature, "component '%s' is an image component", component.Name)
+		}
+		if component.Attributes.Exists(constants.PodOverridesAttribute) {
+			addUsage(controller.PodOverridesFeature, "component '%s' uses the %s attribute", component.Name, constants.PodOverridesAttribute)
+		}
+		if overrides.NeedsContainerOverride(&component) {
+			addUsage(controller.ContainerOverridesFeature, "component '%s' uses the %s attribute", component.Name, constants.ContainerOverridesAttribute)
+		}
+	}
+	return usages
+}
+
+// CheckDisabledFeatures returns an error listing all uses of disabled devfile features in a DevWorkspace template, or
+// nil if the template does not use any disabled features.
+func CheckDisabledFeatures(template *dw.DevWorkspaceTemplateSpec, disabledFeatures []controller.DevfileFeature) error {
+	usages := GetDisabledFeatureUsages(template, disabledFeatures)
+	if len(usages) == 0 {
+		return nil
+	}
+	return FormatDisabledFeaturesError(usages)
+}
+
+// FormatDisabledFeaturesError formats uses of disabled features, as returned by GetDisabledFeatureUsages, into an
+// error suitable for showing to users.
+func FormatDisabledFeaturesError(usages []string) error {
+	return fmt.Errorf("DevWorkspace uses devfile features that are disabled on this cluster: %s", strings.Join(usages, "; "))
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package restrictions
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getTestTemplate() *dw.DevWorkspaceTemplateSpec {
+	return &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Attributes: attributes.Attributes{}.PutString(constants.WorkspaceSCCAttribute, "anyuid"),
+			Components: []dw.Component{
+				{
+					Name: "tools",
+					Attributes: attributes.Attributes{}.
+						PutString(constants.ContainerOverridesAttribute, `{"resources": {}}`),
+					ComponentUnion: dw.ComponentUnion{
+						Container: &dw.ContainerComponent{},
+					},
+				},
+				{
+					Name: "database",
+					ComponentUnion: dw.ComponentUnion{
+						Kubernetes: &dw.KubernetesComponent{},
+					},
+				},
+				{
+					Name: "builder",
+					ComponentUnion: dw.ComponentUnion{
+						Image: &dw.ImageComponent{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetDisabledFeatureUsages(t *testing.T) {
+	tests := []struct {
+		name             string
+		disabledFeatures []controller.DevfileFeature
+		expectedUsages   []string
+	}{
+		{
+			name:             "No features disabled",
+			disabledFeatures: nil,
+			expectedUsages:   nil,
+		},
+		{
+			name:             "Unused feature disabled",
+			disabledFeatures: []controller.DevfileFeature{controller.OpenShiftComponentsFeature, controller.PodOverridesFeature},
+			expectedUsages:   nil,
+		},
+		{
+			name:             "Component features disabled",
+			disabledFeatures: []controller.DevfileFeature{controller.KubernetesComponentsFeature, controller.ImageComponentsFeature},
+			expectedUsages: []string{
+				"component 'database' is a kubernetes component (kubernetes-components)",
+				"component 'builder' is an image component (image-components)",
+			},
+		},
+		{
+			name:             "Attribute features disabled",
+			disabledFeatures: []controller.DevfileFeature{controller.SCCFeature, controller.ContainerOverridesFeature},
+			expectedUsages: []string{
+				"DevWorkspace uses the controller.devfile.io/scc attribute (scc)",
+				"component 'tools' uses the container-overrides attribute (container-overrides)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usages := GetDisabledFeatureUsages(getTestTemplate(), tt.disabledFeatures)
+			assert.Equal(t, tt.expectedUsages, usages)
+		})
+	}
+}
+
+func TestCheckDisabledFeatures(t *testing.T) {
+	err := CheckDisabledFeatures(getTestTemplate(), []controller.DevfileFeature{controller.KubernetesComponentsFeature})
+	if assert.Error(t, err) {
+		assert.Equal(t, "DevWorkspace uses devfile features that are disabled on this cluster: component 'database' is a kubernetes component (kubernetes-components)", err.Error())
+	}
+	assert.NoError(t, CheckDisabledFeatures(getTestTemplate(), nil))
+}
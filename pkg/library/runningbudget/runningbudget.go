@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package runningbudget tracks how long DevWorkspaces run each week, so that DevWorkspaces can be stopped once they
+// exceed a weekly running budget. The time of previous runs in the current week is stored in an annotation on the
+// DevWorkspace, and the time of the current run is computed from the started-at annotation.
+package runningbudget
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// StopReason is the value of the stopped-by annotation on DevWorkspaces that were stopped because they exceeded
+// their running budget.
+const StopReason = "running-budget"
+
+// Usage is stored in the running-budget-usage annotation.
+type Usage struct {
+	// WeekStart is the start of the week the usage was recorded in.
+	WeekStart time.Time `json:"weekStart"`
+	// UsedSeconds is the total duration of runs that ended during the week.
+	UsedSeconds int64 `json:"usedSeconds"`
+}
+
+// GetBudget returns the weekly running budget of a DevWorkspace, and whether the DevWorkspace has a running budget
+// at all. If both the config and the weekly-running-budget attribute set a budget, the smaller one is used.
+func GetBudget(workspace *dw.DevWorkspace, config *controllerv1alpha1.RunningBudgetConfig) (budget time.Duration, hasBudget bool, err error) {
+	if config != nil && config.Weekly != "" {
+		budget, err = time.ParseDuration(config.Weekly)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid weekly running budget in DevWorkspaceOperatorConfig: %w", err)
+		}
+		hasBudget = true
+	}
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.WeeklyRunningBudgetAttribute) {
+		var attrErr error
+		attrValue := attributes.GetString(constants.WeeklyRunningBudgetAttribute, &attrErr)
+		if attrErr != nil {
+			return 0, false, fmt.Errorf("failed to read attribute %s: %w", constants.WeeklyRunningBudgetAttribute, attrErr)
+		}
+		attrBudget, err := time.ParseDuration(attrValue)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid value for attribute %s: %w", constants.WeeklyRunningBudgetAttribute, err)
+		}
+		if !hasBudget || attrBudget < budget {
+			budget = attrBudget
+		}
+		hasBudget = true
+	}
+	return budget, hasBudget, nil
+}
+
+// WeekStart returns the start of the week containing t, i.e. the preceding Monday at 00:00 UTC.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// GetUsage returns how long a DevWorkspace has run during the week containing now, including its current run.
+func GetUsage(workspace *dw.DevWorkspace, now time.Time) (time.Duration, error) {
+	weekStart := WeekStart(now)
+	var used time.Duration
+	if usageJSON, ok := workspace.Annotations[constants.DevWorkspaceRunningBudgetUsageAnnotation]; ok {
+		usage := &Usage{}
+		if err := json.Unmarshal([]byte(usageJSON), usage); err != nil {
+			return 0, fmt.Errorf("failed to read annotation %s: %w", constants.DevWorkspaceRunningBudgetUsageAnnotation, err)
+		}
+		// Usage from previous weeks does not count against the budget
+		if usage.WeekStart.Equal(weekStart) {
+			used = time.Duration(usage.UsedSeconds) * time.Second
+		}
+	}
+	if startedAtMillis, ok := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]; ok {
+		millis, err := strconv.ParseInt(startedAtMillis, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read annotation %s: %w", constants.DevWorkspaceStartedAtAnnotation, err)
+		}
+		runStart := time.UnixMilli(millis)
+		if runStart.Before(weekStart) {
+			runStart = weekStart
+		}
+		if now.After(runStart) {
+			used += now.Sub(runStart)
+		}
+	}
+	return used, nil
+}
+
+// RecordRun adds the duration of the current run of a DevWorkspace to the running-budget-usage annotation. It
+// should be called when the started-at annotation is removed from the DevWorkspace, in the same update.
+func RecordRun(workspace *dw.DevWorkspace, now time.Time) error {
+	used, err := GetUsage(workspace, now)
+	if err != nil {
+		return err
+	}
+	usageJSON, err := json.Marshal(Usage{
+		WeekStart:   WeekStart(now),
+		UsedSeconds: int64(used.Seconds()),
+	})
+	if err != nil {
+		return err
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceRunningBudgetUsageAnnotation] = string(usageJSON)
+	return nil
+}
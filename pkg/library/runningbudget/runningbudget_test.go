@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runningbudget
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// Wednesday
+var testNow = time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC)
+
+func getTestWorkspace(budgetAttribute string, annotations map[string]string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Annotations = annotations
+	if budgetAttribute != "" {
+		workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.WeeklyRunningBudgetAttribute, budgetAttribute)
+	}
+	return workspace
+}
+
+func TestGetBudget(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            *controllerv1alpha1.RunningBudgetConfig
+		attribute         string
+		expectedBudget    time.Duration
+		expectedHasBudget bool
+		expectErr         bool
+	}{
+		{
+			name:              "No budget configured",
+			expectedHasBudget: false,
+		},
+		{
+			name:              "Uses budget from config",
+			config:            &controllerv1alpha1.RunningBudgetConfig{Weekly: "40h"},
+			expectedBudget:    40 * time.Hour,
+			expectedHasBudget: true,
+		},
+		{
+			name:              "Uses budget from attribute",
+			attribute:         "10h",
+			expectedBudget:    10 * time.Hour,
+			expectedHasBudget: true,
+		},
+		{
+			name:              "Uses smaller budget when both are set",
+			config:            &controllerv1alpha1.RunningBudgetConfig{Weekly: "40h"},
+			attribute:         "60h",
+			expectedBudget:    40 * time.Hour,
+			expectedHasBudget: true,
+		},
+		{
+			name:      "Returns error for invalid attribute",
+			attribute: "forty hours",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, hasBudget, err := GetBudget(getTestWorkspace(tt.attribute, nil), tt.config)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHasBudget, hasBudget)
+			assert.Equal(t, tt.expectedBudget, budget)
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	expected := time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, expected, WeekStart(testNow))
+	assert.Equal(t, expected, WeekStart(expected), "Start of week should be in the same week")
+	assert.Equal(t, expected, WeekStart(time.Date(2024, time.May, 19, 23, 59, 0, 0, time.UTC)), "Sunday should be in the same week")
+}
+
+func TestGetUsage(t *testing.T) {
+	weekStart := WeekStart(testNow)
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		expectedUsage time.Duration
+	}{
+		{
+			name:          "No usage",
+			expectedUsage: 0,
+		},
+		{
+			name: "Counts current run",
+			annotations: map[string]string{
+				constants.DevWorkspaceStartedAtAnnotation: fmt.Sprint(testNow.Add(-2 * time.Hour).UnixMilli()),
+			},
+			expectedUsage: 2 * time.Hour,
+		},
+		{
+			name: "Counts recorded usage and current run",
+			annotations: map[string]string{
+				constants.DevWorkspaceStartedAtAnnotation:          fmt.Sprint(testNow.Add(-2 * time.Hour).UnixMilli()),
+				constants.DevWorkspaceRunningBudgetUsageAnnotation: fmt.Sprintf(`{"weekStart":"%s","usedSeconds":3600}`, weekStart.Format(time.RFC3339)),
+			},
+			expectedUsage: 3 * time.Hour,
+		},
+		{
+			name: "Ignores usage from previous weeks",
+			annotations: map[string]string{
+				constants.DevWorkspaceRunningBudgetUsageAnnotation: fmt.Sprintf(`{"weekStart":"%s","usedSeconds":3600}`, weekStart.AddDate(0, 0, -7).Format(time.RFC3339)),
+			},
+			expectedUsage: 0,
+		},
+		{
+			name: "Only counts current run from start of week",
+			annotations: map[string]string{
+				constants.DevWorkspaceStartedAtAnnotation: fmt.Sprint(weekStart.Add(-24 * time.Hour).UnixMilli()),
+			},
+			expectedUsage: testNow.Sub(weekStart),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := GetUsage(getTestWorkspace("", tt.annotations), testNow)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedUsage, usage)
+		})
+	}
+}
+
+func TestRecordRun(t *testing.T) {
+	workspace := getTestWorkspace("", map[string]string{
+		constants.DevWorkspaceStartedAtAnnotation: fmt.Sprint(testNow.Add(-2 * time.Hour).UnixMilli()),
+	})
+	assert.NoError(t, RecordRun(workspace, testNow))
+	delete(workspace.Annotations, constants.DevWorkspaceStartedAtAnnotation)
+
+	usage, err := GetUsage(workspace, testNow.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Hour, usage, "Recorded run should count towards usage after workspace is stopped")
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runschedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvalidExpressions(t *testing.T) {
+	tests := []string{
+		"",
+		"0 20 * *",
+		"0 20 * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"30-10 * * * *",
+		"a * * * *",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Parse(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, time.May, 15, 12, 30, 15, 0, time.UTC)
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.May, 15, 12, 31, 0, 0, time.UTC)},
+		{"0 20 * * *", time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC)},
+		{"0 8 * * *", time.Date(2024, time.May, 16, 8, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.May, 15, 12, 45, 0, 0, time.UTC)},
+		{"0,30 12 * * *", time.Date(2024, time.May, 16, 12, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, time.May, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week match either
+		{"0 0 20 * 5", time.Date(2024, time.May, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(schedule.Next(from)), "Expected %s, got %s", tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestNextUsesLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	schedule, err := Parse("0 20 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC).In(berlin))
+	assert.True(t, time.Date(2024, time.May, 15, 18, 0, 0, 0, time.UTC).Equal(next), "Should evaluate schedule in Berlin time, got %s", next)
+
+	// Skips the hour that does not exist when daylight saving time starts
+	schedule, err = Parse("30 2 * * *")
+	require.NoError(t, err)
+	next = schedule.Next(time.Date(2024, time.March, 31, 0, 0, 0, 0, berlin))
+	assert.True(t, time.Date(2024, time.April, 1, 2, 30, 0, 0, berlin).Equal(next), "Got %s", next)
+}
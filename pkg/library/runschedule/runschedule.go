@@ -0,0 +1,162 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package runschedule implements the run schedule configured in the global DevWorkspaceOperatorConfig: DevWorkspaces
+// are stopped at scheduled times and cannot be started during blackout windows. Schedules are cron expressions
+// evaluated in the time zone from the config.
+package runschedule
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	// Time zones are embedded as the operator image may not include them
+	_ "time/tzdata"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// StopReason is the value of the stopped-by annotation on DevWorkspaces that were stopped by a stop schedule.
+	StopReason = "schedule"
+	// BlackoutStopReason is the value of the stopped-by annotation on DevWorkspaces that were stopped because they
+	// were started during a blackout window.
+	BlackoutStopReason = "blackout-window"
+)
+
+// Window is a blackout window during which DevWorkspaces cannot be started.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NextStop returns the first time after the given time at which a DevWorkspace is stopped, and whether the
+// DevWorkspace has any stop schedule. Stop schedules are read from the config, if its selector matches the
+// DevWorkspace, and from the DevWorkspace's stop-schedule annotation.
+func NextStop(workspace *dw.DevWorkspace, config *controllerv1alpha1.RunScheduleConfig, after time.Time) (next time.Time, hasSchedule bool, err error) {
+	loc, err := getLocation(config)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	var exprs []string
+	if matches, err := matchesSelector(workspace, config); err != nil {
+		return time.Time{}, false, err
+	} else if matches {
+		exprs = append(exprs, config.Stop...)
+	}
+	if annotation, ok := workspace.Annotations[constants.DevWorkspaceStopScheduleAnnotation]; ok {
+		exprs = append(exprs, annotation)
+	}
+	for _, expr := range exprs {
+		schedule, err := Parse(expr)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		scheduleNext := schedule.Next(after.In(loc))
+		if scheduleNext.IsZero() {
+			continue
+		}
+		if !hasSchedule || scheduleNext.Before(next) {
+			next = scheduleNext
+		}
+		hasSchedule = true
+	}
+	return next, hasSchedule, nil
+}
+
+// GetBlackout returns the blackout window that the given time falls within, or nil if it is not within a blackout
+// window for the DevWorkspace. If multiple windows overlap, the returned window spans all of them.
+func GetBlackout(workspace *dw.DevWorkspace, config *controllerv1alpha1.RunScheduleConfig, t time.Time) (*Window, error) {
+	if matches, err := matchesSelector(workspace, config); err != nil || !matches {
+		return nil, err
+	}
+	loc, err := getLocation(config)
+	if err != nil {
+		return nil, err
+	}
+	t = t.In(loc)
+	var window *Window
+	for _, blackout := range config.Blackouts {
+		schedule, err := Parse(blackout.Start)
+		if err != nil {
+			return nil, err
+		}
+		duration, err := time.ParseDuration(blackout.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blackout window duration %q: %w", blackout.Duration, err)
+		}
+		// The earliest start of a window that has not ended by t
+		start := schedule.Next(t.Add(-duration))
+		if start.IsZero() || start.After(t) {
+			continue
+		}
+		end := start.Add(duration)
+		if window == nil {
+			window = &Window{Start: start, End: end}
+			continue
+		}
+		if start.Before(window.Start) {
+			window.Start = start
+		}
+		if end.After(window.End) {
+			window.End = end
+		}
+	}
+	return window, nil
+}
+
+// GetStartedAt returns the time the current run of a DevWorkspace started, from the started-at annotation. Returns
+// false if the DevWorkspace has not finished starting.
+func GetStartedAt(workspace *dw.DevWorkspace) (startedAt time.Time, ok bool, err error) {
+	startedAtMillis, ok := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	millis, err := strconv.ParseInt(startedAtMillis, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to read annotation %s: %w", constants.DevWorkspaceStartedAtAnnotation, err)
+	}
+	return time.UnixMilli(millis), true, nil
+}
+
+func matchesSelector(workspace *dw.DevWorkspace, config *controllerv1alpha1.RunScheduleConfig) (bool, error) {
+	if config == nil {
+		return false, nil
+	}
+	if config.Selector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(config.Selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid run schedule selector: %w", err)
+	}
+	return selector.Matches(labels.Set(workspace.Labels)), nil
+}
+
+func getLocation(config *controllerv1alpha1.RunScheduleConfig) (*time.Location, error) {
+	if config == nil || config.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(config.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run schedule time zone: %w", err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,224 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package runschedule
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// Wednesday
+var testNow = time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC)
+
+func getTestWorkspace(labels, annotations map[string]string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Labels = labels
+	workspace.Annotations = annotations
+	return workspace
+}
+
+func TestNextStop(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              *controllerv1alpha1.RunScheduleConfig
+		labels              map[string]string
+		annotations         map[string]string
+		expectedNext        time.Time
+		expectedHasSchedule bool
+		expectErr           bool
+	}{
+		{
+			name:                "No schedule configured",
+			expectedHasSchedule: false,
+		},
+		{
+			name:                "Uses schedule from config",
+			config:              &controllerv1alpha1.RunScheduleConfig{Stop: []string{"0 20 * * *"}},
+			expectedNext:        time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC),
+			expectedHasSchedule: true,
+		},
+		{
+			name:                "Uses schedule from annotation",
+			annotations:         map[string]string{constants.DevWorkspaceStopScheduleAnnotation: "0 18 * * *"},
+			expectedNext:        time.Date(2024, time.May, 15, 18, 0, 0, 0, time.UTC),
+			expectedHasSchedule: true,
+		},
+		{
+			name:                "Uses earliest schedule",
+			config:              &controllerv1alpha1.RunScheduleConfig{Stop: []string{"0 20 * * *", "0 0 * * 6"}},
+			annotations:         map[string]string{constants.DevWorkspaceStopScheduleAnnotation: "0 22 * * *"},
+			expectedNext:        time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC),
+			expectedHasSchedule: true,
+		},
+		{
+			name:                "Evaluates schedule in configured time zone",
+			config:              &controllerv1alpha1.RunScheduleConfig{TimeZone: "America/New_York", Stop: []string{"0 20 * * *"}},
+			expectedNext:        time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC),
+			expectedHasSchedule: true,
+		},
+		{
+			name: "Ignores config schedule for workspaces not matching selector",
+			config: &controllerv1alpha1.RunScheduleConfig{
+				Stop:     []string{"0 20 * * *"},
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+			labels:              map[string]string{"team": "b"},
+			expectedHasSchedule: false,
+		},
+		{
+			name: "Uses config schedule for workspaces matching selector",
+			config: &controllerv1alpha1.RunScheduleConfig{
+				Stop:     []string{"0 20 * * *"},
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			},
+			labels:              map[string]string{"team": "a"},
+			expectedNext:        time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC),
+			expectedHasSchedule: true,
+		},
+		{
+			name:        "Returns error for invalid annotation",
+			annotations: map[string]string{constants.DevWorkspaceStopScheduleAnnotation: "every night"},
+			expectErr:   true,
+		},
+		{
+			name:      "Returns error for invalid time zone",
+			config:    &controllerv1alpha1.RunScheduleConfig{TimeZone: "Mars/Olympus_Mons", Stop: []string{"0 20 * * *"}},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, hasSchedule, err := NextStop(getTestWorkspace(tt.labels, tt.annotations), tt.config, testNow)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHasSchedule, hasSchedule)
+			assert.True(t, tt.expectedNext.Equal(next), "Expected %s, got %s", tt.expectedNext, next)
+		})
+	}
+}
+
+func TestGetBlackout(t *testing.T) {
+	nightly := controllerv1alpha1.BlackoutWindowConfig{Start: "0 22 * * *", Duration: "10h"}
+	tests := []struct {
+		name           string
+		config         *controllerv1alpha1.RunScheduleConfig
+		now            time.Time
+		expectedWindow *Window
+		expectErr      bool
+	}{
+		{
+			name: "No blackout windows configured",
+			now:  testNow,
+		},
+		{
+			name:   "Outside blackout window",
+			config: &controllerv1alpha1.RunScheduleConfig{Blackouts: []controllerv1alpha1.BlackoutWindowConfig{nightly}},
+			now:    testNow,
+		},
+		{
+			name:   "Inside blackout window",
+			config: &controllerv1alpha1.RunScheduleConfig{Blackouts: []controllerv1alpha1.BlackoutWindowConfig{nightly}},
+			now:    time.Date(2024, time.May, 15, 6, 0, 0, 0, time.UTC),
+			expectedWindow: &Window{
+				Start: time.Date(2024, time.May, 14, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.May, 15, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "Window start is inclusive",
+			config: &controllerv1alpha1.RunScheduleConfig{Blackouts: []controllerv1alpha1.BlackoutWindowConfig{nightly}},
+			now:    time.Date(2024, time.May, 15, 22, 0, 0, 0, time.UTC),
+			expectedWindow: &Window{
+				Start: time.Date(2024, time.May, 15, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.May, 16, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "Window end is exclusive",
+			config: &controllerv1alpha1.RunScheduleConfig{Blackouts: []controllerv1alpha1.BlackoutWindowConfig{nightly}},
+			now:    time.Date(2024, time.May, 15, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Merges overlapping windows",
+			config: &controllerv1alpha1.RunScheduleConfig{Blackouts: []controllerv1alpha1.BlackoutWindowConfig{
+				nightly,
+				{Start: "0 0 * * *", Duration: "12h"},
+			}},
+			now: time.Date(2024, time.May, 15, 6, 0, 0, 0, time.UTC),
+			expectedWindow: &Window{
+				Start: time.Date(2024, time.May, 14, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.May, 15, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Ignores windows for workspaces not matching selector",
+			config: &controllerv1alpha1.RunScheduleConfig{
+				Blackouts: []controllerv1alpha1.BlackoutWindowConfig{nightly},
+				Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+			},
+			now: time.Date(2024, time.May, 15, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "Returns error for invalid duration",
+			config:    &controllerv1alpha1.RunScheduleConfig{Blackouts: []controllerv1alpha1.BlackoutWindowConfig{{Start: "0 22 * * *", Duration: "all night"}}},
+			now:       testNow,
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := GetBlackout(getTestWorkspace(map[string]string{"team": "a"}, nil), tt.config, tt.now)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedWindow == nil {
+				assert.Nil(t, window)
+				return
+			}
+			require.NotNil(t, window)
+			assert.True(t, tt.expectedWindow.Start.Equal(window.Start), "Expected start %s, got %s", tt.expectedWindow.Start, window.Start)
+			assert.True(t, tt.expectedWindow.End.Equal(window.End), "Expected end %s, got %s", tt.expectedWindow.End, window.End)
+		})
+	}
+}
+
+func TestGetStartedAt(t *testing.T) {
+	_, ok, err := GetStartedAt(getTestWorkspace(nil, nil))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	startedAt, ok, err := GetStartedAt(getTestWorkspace(nil, map[string]string{
+		constants.DevWorkspaceStartedAtAnnotation: strconv.FormatInt(testNow.UnixMilli(), 10),
+	}))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, testNow.Equal(startedAt))
+}
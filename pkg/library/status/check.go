@@ -77,6 +77,21 @@ func checkRollingUpdateDeploymentStatus(deployment *appsv1.Deployment) (ready bo
 	return true
 }
 
+// CheckStatefulSetStatus returns whether a workspace StatefulSet has a ready pod that matches its current spec. As
+// StatefulSets replace their pods only after the previous pod has terminated, a ready pod from a previous revision
+// does not make the StatefulSet ready.
+func CheckStatefulSetStatus(statefulSet *appsv1.StatefulSet) (ready bool) {
+	if statefulSet.Generation > statefulSet.Status.ObservedGeneration {
+		// Current StatefulSet spec not observed by cluster
+		return false
+	}
+	if statefulSet.Status.UpdateRevision != "" && statefulSet.Status.CurrentRevision != statefulSet.Status.UpdateRevision {
+		// Pod has not been updated to the current revision
+		return false
+	}
+	return statefulSet.Status.ReadyReplicas > 0
+}
+
 func CheckDeploymentConditions(deployment *appsv1.Deployment) (healthy bool, errorMsg string) {
 	conditions := deployment.Status.Conditions
 	for _, condition := range conditions {
@@ -92,8 +107,9 @@ func CheckDeploymentConditions(deployment *appsv1.Deployment) (healthy bool, err
 // checkPodsState checks if workspace-related pods are in an unrecoverable state. A pod is considered to be unrecoverable
 // if it has a container with one of the containerFailureStateReasons states, or if an unrecoverable event (with reason
 // matching unrecoverablePodEventReasons) has the pod as the involved object.
+// The serviceName is the name of the workspace's service, used to identify volumes provisioned for it.
 // Returns optional message with detected unrecoverable state details or error if any happens during check
-func CheckPodsState(workspaceID string, namespace string, labelSelector k8sclient.MatchingLabels, ignoredEvents []string,
+func CheckPodsState(serviceName string, namespace string, labelSelector k8sclient.MatchingLabels, ignoredEvents []string,
 	clusterAPI sync.ClusterAPI) (stateMsg string, checkFailure error) {
 	podList := &corev1.PodList{}
 	if err := clusterAPI.Client.List(context.TODO(), podList, k8sclient.InNamespace(namespace), labelSelector); err != nil {
@@ -104,6 +120,9 @@ func CheckPodsState(workspaceID string, namespace string, labelSelector k8sclien
 		for _, containerStatus := range pod.Status.ContainerStatuses {
 			ok, reason := CheckContainerStatusForFailure(&containerStatus, ignoredEvents)
 			if !ok {
+				if isOOMKilled(&containerStatus) {
+					return getOOMKilledMessage(&pod, containerStatus.Name), nil
+				}
 				return fmt.Sprintf("Container %s has state %s", containerStatus.Name, reason), nil
 			}
 		}
@@ -113,14 +132,14 @@ func CheckPodsState(workspaceID string, namespace string, labelSelector k8sclien
 				return fmt.Sprintf("Init Container %s has state %s", initContainerStatus.Name, reason), nil
 			}
 		}
-		if msg, err := CheckPodEvents(&pod, workspaceID, ignoredEvents, clusterAPI); err != nil || msg != "" {
+		if msg, err := CheckPodEvents(&pod, serviceName, ignoredEvents, clusterAPI); err != nil || msg != "" {
 			return msg, err
 		}
 	}
 	return "", nil
 }
 
-func CheckPodEvents(pod *corev1.Pod, workspaceID string, ignoredEvents []string, clusterAPI sync.ClusterAPI) (msg string, err error) {
+func CheckPodEvents(pod *corev1.Pod, serviceName string, ignoredEvents []string, clusterAPI sync.ClusterAPI) (msg string, err error) {
 	evs := &corev1.EventList{}
 	selector, err := fields.ParseSelector(fmt.Sprintf("involvedObject.name=%s", pod.Name))
 	if err != nil {
@@ -138,7 +157,7 @@ func CheckPodEvents(pod *corev1.Pod, workspaceID string, ignoredEvents []string,
 		// operator. To avoid this, we always ignore FailedMount events if the message refers to the DWO-provisioned volume
 		if infrastructure.IsOpenShift() &&
 			ev.Reason == "FailedMount" &&
-			strings.Contains(ev.Message, common.ServingCertVolumeName(common.ServiceName(workspaceID))) {
+			strings.Contains(ev.Message, common.ServingCertVolumeName(serviceName)) {
 			continue
 		}
 
@@ -182,7 +201,7 @@ func CheckContainerStatusForFailure(containerStatus *corev1.ContainerStatus, ign
 func CheckForIgnoredWorkspacePodEvents(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (errMsg string) {
 	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
 	// CheckPodsState returns either a message or error, not both.
-	errMsg, checkErr := CheckPodsState(workspace.Status.DevWorkspaceId, workspace.Namespace, workspaceIDLabel, []string{}, clusterAPI)
+	errMsg, checkErr := CheckPodsState(common.ServiceName(workspace.Status.DevWorkspaceId, workspace.Annotations), workspace.Namespace, workspaceIDLabel, []string{}, clusterAPI)
 	if checkErr != nil {
 		return checkErr.Error()
 	}
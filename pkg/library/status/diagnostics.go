@@ -0,0 +1,221 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// maxDiagnosticsLength is the maximum length of the diagnostics summary returned by GetStartupDiagnostics. Longer
+// summaries are truncated to avoid bloating the DevWorkspace object.
+const maxDiagnosticsLength = 2048
+
+// containerWaitingReasonsIgnored contains container waiting reasons that are expected while a pod is starting and are
+// not reported as diagnostics.
+var containerWaitingReasonsIgnored = []string{
+	"",
+	"ContainerCreating",
+	"PodInitializing",
+}
+
+// GetStartupDiagnostics gathers information that may explain why a DevWorkspace failed to start: unrecoverable
+// deployment conditions, pending PVCs used by the workspace deployment, unschedulable pods, failing containers
+// and warning events involving these objects. Returns a human-readable summary, or an empty string if nothing
+// relevant was found.
+func GetStartupDiagnostics(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (string, error) {
+	var diagnostics []string
+
+	var podTemplate *corev1.PodTemplateSpec
+	deployment := &appsv1.Deployment{}
+	deployNN := types.NamespacedName{Name: common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations), Namespace: workspace.Namespace}
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, deployNN, deployment)
+	switch {
+	case err == nil:
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue {
+				diagnostics = append(diagnostics, fmt.Sprintf("Deployment %s: %s: %s", deployment.Name, condition.Reason, condition.Message))
+			}
+		}
+		podTemplate = &deployment.Spec.Template
+	case k8sErrors.IsNotFound(err):
+		// The workspace may be run as a StatefulSet instead
+		statefulSet := &appsv1.StatefulSet{}
+		if err := clusterAPI.Client.Get(clusterAPI.Ctx, deployNN, statefulSet); err != nil {
+			if !k8sErrors.IsNotFound(err) {
+				return "", err
+			}
+			// Workspace failed before deployment was created; no diagnostics to collect
+			return "", nil
+		}
+		podTemplate = &statefulSet.Spec.Template
+	default:
+		return "", err
+	}
+
+	pvcDiagnostics, err := getPVCDiagnostics(podTemplate, workspace.Namespace, clusterAPI)
+	if err != nil {
+		return "", err
+	}
+	diagnostics = append(diagnostics, pvcDiagnostics...)
+
+	podList := &corev1.PodList{}
+	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, podList, k8sclient.InNamespace(workspace.Namespace), workspaceIDLabel); err != nil {
+		return "", err
+	}
+	for _, pod := range podList.Items {
+		podDiagnostics, err := getPodDiagnostics(&pod, clusterAPI)
+		if err != nil {
+			return "", err
+		}
+		diagnostics = append(diagnostics, podDiagnostics...)
+	}
+
+	summary := strings.Join(diagnostics, "; ")
+	if len(summary) > maxDiagnosticsLength {
+		summary = summary[:maxDiagnosticsLength-3] + "..."
+	}
+	return summary, nil
+}
+
+func getPVCDiagnostics(podTemplate *corev1.PodTemplateSpec, namespace string, clusterAPI sync.ClusterAPI) ([]string, error) {
+	var diagnostics []string
+	for _, volume := range podTemplate.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvcNN := types.NamespacedName{Name: volume.PersistentVolumeClaim.ClaimName, Namespace: namespace}
+		if err := clusterAPI.Client.Get(clusterAPI.Ctx, pvcNN, pvc); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				diagnostics = append(diagnostics, fmt.Sprintf("PVC %s does not exist", pvcNN.Name))
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Status.Phase != corev1.ClaimPending {
+			continue
+		}
+		msg := fmt.Sprintf("PVC %s is pending", pvc.Name)
+		events, err := getWarningEventMessages(pvc.Name, "PersistentVolumeClaim", pvc.Namespace, clusterAPI)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			msg = fmt.Sprintf("%s: %s", msg, strings.Join(events, ", "))
+		}
+		diagnostics = append(diagnostics, msg)
+	}
+	return diagnostics, nil
+}
+
+func getPodDiagnostics(pod *corev1.Pod, clusterAPI sync.ClusterAPI) ([]string, error) {
+	var diagnostics []string
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			diagnostics = append(diagnostics, fmt.Sprintf("Pod %s cannot be scheduled: %s", pod.Name, condition.Message))
+		}
+	}
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		if msg := getContainerStatusDiagnostics(&containerStatus); msg != "" {
+			diagnostics = append(diagnostics, fmt.Sprintf("Init container %s %s", containerStatus.Name, msg))
+		}
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if msg := getContainerStatusDiagnostics(&containerStatus); msg != "" {
+			diagnostics = append(diagnostics, fmt.Sprintf("Container %s %s", containerStatus.Name, msg))
+		}
+	}
+	events, err := getWarningEventMessages(pod.Name, "Pod", pod.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		diagnostics = append(diagnostics, fmt.Sprintf("Pod %s events: %s", pod.Name, strings.Join(events, ", ")))
+	}
+	return diagnostics, nil
+}
+
+// getContainerStatusDiagnostics returns a description of why a container is not running, or an empty string if the
+// container is running or starting normally.
+func getContainerStatusDiagnostics(containerStatus *corev1.ContainerStatus) string {
+	if waiting := containerStatus.State.Waiting; waiting != nil {
+		for _, ignored := range containerWaitingReasonsIgnored {
+			if waiting.Reason == ignored {
+				return ""
+			}
+		}
+		msg := fmt.Sprintf("is waiting: %s", waiting.Reason)
+		if waiting.Message != "" {
+			msg = fmt.Sprintf("%s: %s", msg, waiting.Message)
+		}
+		if lastTerminated := containerStatus.LastTerminationState.Terminated; lastTerminated != nil {
+			msg = fmt.Sprintf("%s (last exited with code %d: %s)", msg, lastTerminated.ExitCode, lastTerminated.Reason)
+		}
+		return msg
+	}
+	if terminated := containerStatus.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+		msg := fmt.Sprintf("exited with code %d: %s", terminated.ExitCode, terminated.Reason)
+		if terminated.Message != "" {
+			msg = fmt.Sprintf("%s: %s", msg, terminated.Message)
+		}
+		return msg
+	}
+	return ""
+}
+
+// getWarningEventMessages returns the deduplicated reasons and messages of warning events involving an object, sorted
+// by reason.
+func getWarningEventMessages(name, kind, namespace string, clusterAPI sync.ClusterAPI) ([]string, error) {
+	evs := &corev1.EventList{}
+	selector, err := fields.ParseSelector(fmt.Sprintf("involvedObject.name=%s", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse field selector: %s", err)
+	}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, evs, k8sclient.InNamespace(namespace), k8sclient.MatchingFieldsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list events in namespace %s: %w", namespace, err)
+	}
+	seen := map[string]bool{}
+	var messages []string
+	for _, ev := range evs.Items {
+		if ev.InvolvedObject.Kind != kind || ev.Type != corev1.EventTypeWarning {
+			continue
+		}
+		msg := fmt.Sprintf("%s: %s", ev.Reason, ev.Message)
+		if count := getEventCount(ev); count > 1 {
+			msg = fmt.Sprintf("%s (x%d)", msg, count)
+		}
+		if !seen[msg] {
+			seen[msg] = true
+			messages = append(messages, msg)
+		}
+	}
+	sort.Strings(messages)
+	return messages, nil
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	testNamespace   = "test-namespace"
+	testWorkspaceID = "workspace-test-id"
+)
+
+func getDiagnosticsClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testutil.Scheme).
+		WithObjects(objs...).
+		WithIndex(&corev1.Event{}, "involvedObject.name", func(obj client.Object) []string {
+			return []string{obj.(*corev1.Event).InvolvedObject.Name}
+		}).
+		Build()
+	return sync.ClusterAPI{Client: fakeClient, Ctx: context.Background()}
+}
+
+func getDiagnosticsTestWorkspace() *common.DevWorkspaceWithConfig {
+	workspace := &common.DevWorkspaceWithConfig{}
+	workspace.DevWorkspace = testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Namespace = testNamespace
+	workspace.Status.DevWorkspaceId = testWorkspaceID
+	return workspace
+}
+
+func getDiagnosticsTestDeployment(pvcName string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.DeploymentName(testWorkspaceID, nil),
+			Namespace: testNamespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "workspace-storage",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getDiagnosticsTestEvent(name, kind, involvedName, reason, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Name:      involvedName,
+			Namespace: testNamespace,
+		},
+		Type:    corev1.EventTypeWarning,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func TestStartupDiagnosticsWithoutDeployment(t *testing.T) {
+	clusterAPI := getDiagnosticsClusterAPI()
+	diagnostics, err := GetStartupDiagnostics(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, diagnostics)
+}
+
+func TestStartupDiagnosticsReportsPendingPVC(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "claim-devworkspace",
+			Namespace: testNamespace,
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimPending,
+		},
+	}
+	event := getDiagnosticsTestEvent("pvc-event", "PersistentVolumeClaim", "claim-devworkspace",
+		"ProvisioningFailed", "storageclass.storage.k8s.io \"missing\" not found")
+	clusterAPI := getDiagnosticsClusterAPI(getDiagnosticsTestDeployment("claim-devworkspace"), pvc, event)
+
+	diagnostics, err := GetStartupDiagnostics(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, `PVC claim-devworkspace is pending: ProvisioningFailed: storageclass.storage.k8s.io "missing" not found`, diagnostics)
+}
+
+func TestStartupDiagnosticsReportsPodFailures(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "claim-devworkspace",
+			Namespace: testNamespace,
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase: corev1.ClaimBound,
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel: testWorkspaceID,
+			},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: "0/3 nodes are available: 3 Insufficient memory.",
+				},
+			},
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "project-clone",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"},
+					},
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "tools",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason:  "ImagePullBackOff",
+							Message: "Back-off pulling image \"quay.io/invalid/image\"",
+						},
+					},
+				},
+				{
+					Name: "starting",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+					},
+				},
+			},
+		},
+	}
+	warningEvent := getDiagnosticsTestEvent("pod-event", "Pod", "test-pod", "Failed", "Failed to pull image \"quay.io/invalid/image\"")
+	normalEvent := getDiagnosticsTestEvent("pod-normal-event", "Pod", "test-pod", "Pulling", "Pulling image \"quay.io/invalid/image\"")
+	normalEvent.Type = corev1.EventTypeNormal
+	clusterAPI := getDiagnosticsClusterAPI(getDiagnosticsTestDeployment("claim-devworkspace"), pvc, pod, warningEvent, normalEvent)
+
+	diagnostics, err := GetStartupDiagnostics(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"Pod test-pod cannot be scheduled: 0/3 nodes are available: 3 Insufficient memory.",
+		"Container tools is waiting: ImagePullBackOff: Back-off pulling image \"quay.io/invalid/image\"",
+		"Pod test-pod events: Failed: Failed to pull image \"quay.io/invalid/image\"",
+	}, "; "), diagnostics)
+}
+
+func TestStartupDiagnosticsAreTruncated(t *testing.T) {
+	deployment := getDiagnosticsTestDeployment("claim-devworkspace")
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentReplicaFailure,
+			Status:  corev1.ConditionTrue,
+			Reason:  "FailedCreate",
+			Message: strings.Repeat("a", 2*maxDiagnosticsLength),
+		},
+	}
+	clusterAPI := getDiagnosticsClusterAPI(deployment)
+
+	diagnostics, err := GetStartupDiagnostics(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Len(t, diagnostics, maxDiagnosticsLength)
+	assert.True(t, strings.HasPrefix(diagnostics, "Deployment workspace-test-id: FailedCreate: aaa"))
+	assert.True(t, strings.HasSuffix(diagnostics, "..."))
+}
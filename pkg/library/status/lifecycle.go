@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"fmt"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const failedPostStartHookReason = "FailedPostStartHook"
+
+// CheckLifecycleEventsStatus checks whether the commands bound to the preStart and postStart events of a (flattened)
+// DevWorkspace succeeded in the DevWorkspace's pod. preStart commands run in init containers, and postStart commands
+// run in postStart lifecycle hooks of the containers of their components. Returns whether all commands succeeded and a
+// user-readable message describing their progress or failure. If the DevWorkspace does not bind commands to these
+// events, or its pod has not been created yet, an empty message is returned.
+func CheckLifecycleEventsStatus(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (succeeded bool, msg string, err error) {
+	events := workspace.Spec.Template.Events
+	if events == nil || (len(events.PreStart) == 0 && len(events.PostStart) == 0) {
+		return false, "", nil
+	}
+	preStartComponents := getEventComponents(events.PreStart, workspace.Spec.Template.Commands)
+	postStartComponents := getEventComponents(events.PostStart, workspace.Spec.Template.Commands)
+
+	podList := &corev1.PodList{}
+	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, podList, k8sclient.InNamespace(workspace.Namespace), workspaceIDLabel); err != nil {
+		return false, "", err
+	}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if _, isJobPod := pod.Labels["job-name"]; isJobPod {
+			continue
+		}
+		for _, containerStatus := range pod.Status.InitContainerStatuses {
+			if !preStartComponents[containerStatus.Name] {
+				continue
+			}
+			if terminated := containerStatus.State.Terminated; terminated != nil {
+				if terminated.ExitCode != 0 {
+					return false, fmt.Sprintf("PreStart commands in component %s failed: %s", containerStatus.Name, getTerminationDetails(terminated)), nil
+				}
+				continue
+			}
+			if lastTerminated := containerStatus.LastTerminationState.Terminated; lastTerminated != nil {
+				return false, fmt.Sprintf("Retrying preStart commands in component %s after failure: %s", containerStatus.Name, getTerminationDetails(lastTerminated)), nil
+			}
+			return false, "Running preStart commands", nil
+		}
+
+		if len(postStartComponents) > 0 {
+			hookMsg, err := getFailedPostStartHookMessage(&pod, clusterAPI)
+			if err != nil {
+				return false, "", err
+			}
+			if hookMsg != "" {
+				return false, fmt.Sprintf("PostStart commands failed: %s", hookMsg), nil
+			}
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			// Containers are only reported as running once their postStart hook has completed
+			if postStartComponents[containerStatus.Name] && containerStatus.State.Running == nil {
+				return false, "Waiting for postStart commands to complete", nil
+			}
+		}
+		if len(pod.Status.ContainerStatuses) == 0 {
+			return false, "Waiting for postStart commands to complete", nil
+		}
+		return true, "Lifecycle event commands succeeded", nil
+	}
+	return false, "", nil
+}
+
+// getEventComponents returns the names of the components the commands bound to an event run in.
+func getEventComponents(eventCommands []string, commands []dw.Command) map[string]bool {
+	components := map[string]bool{}
+	for _, commandKey := range eventCommands {
+		for _, command := range commands {
+			if command.Key() != commandKey {
+				continue
+			}
+			switch {
+			case command.Exec != nil:
+				components[command.Exec.Component] = true
+			case command.Apply != nil:
+				components[command.Apply.Component] = true
+			}
+		}
+	}
+	return components
+}
+
+func getFailedPostStartHookMessage(pod *corev1.Pod, clusterAPI sync.ClusterAPI) (string, error) {
+	evs := &corev1.EventList{}
+	selector, err := fields.ParseSelector(fmt.Sprintf("involvedObject.name=%s", pod.Name))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse field selector: %s", err)
+	}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, evs, k8sclient.InNamespace(pod.Namespace), k8sclient.MatchingFieldsSelector{Selector: selector}); err != nil {
+		return "", fmt.Errorf("failed to list events in namespace %s: %w", pod.Namespace, err)
+	}
+	for _, ev := range evs.Items {
+		if ev.InvolvedObject.Kind == "Pod" && ev.Reason == failedPostStartHookReason {
+			return ev.Message, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getLifecycleTestWorkspace() *common.DevWorkspaceWithConfig {
+	workspace := getDiagnosticsTestWorkspace()
+	workspace.Spec.Template.Commands = []dw.Command{
+		{
+			Id: "init-db",
+			CommandUnion: dw.CommandUnion{
+				Apply: &dw.ApplyCommand{Component: "init-db"},
+			},
+		},
+		{
+			Id: "start-server",
+			CommandUnion: dw.CommandUnion{
+				Exec: &dw.ExecCommand{Component: "tools", CommandLine: "./start.sh"},
+			},
+		},
+	}
+	workspace.Spec.Template.Events = &dw.Events{
+		DevWorkspaceEvents: dw.DevWorkspaceEvents{
+			PreStart:  []string{"init-db"},
+			PostStart: []string{"start-server"},
+		},
+	}
+	return workspace
+}
+
+func getLifecycleTestPod(initState, toolsState corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workspace-pod",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel: testWorkspaceID,
+			},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "init-db", State: initState}},
+			ContainerStatuses:     []corev1.ContainerStatus{{Name: "tools", State: toolsState}},
+		},
+	}
+}
+
+func TestCheckLifecycleEventsStatus(t *testing.T) {
+	completed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}
+	tests := []struct {
+		name              string
+		pod               *corev1.Pod
+		expectedSucceeded bool
+		expectedMsg       string
+	}{
+		{
+			name:        "Reports preStart commands running",
+			pod:         getLifecycleTestPod(running, waiting),
+			expectedMsg: "Running preStart commands",
+		},
+		{
+			name:        "Reports failed preStart commands",
+			pod:         getLifecycleTestPod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"}}, waiting),
+			expectedMsg: "PreStart commands in component init-db failed: container exited with code 2 (Error)",
+		},
+		{
+			name:        "Reports postStart commands running",
+			pod:         getLifecycleTestPod(completed, waiting),
+			expectedMsg: "Waiting for postStart commands to complete",
+		},
+		{
+			name:              "Reports commands succeeded",
+			pod:               getLifecycleTestPod(completed, running),
+			expectedSucceeded: true,
+			expectedMsg:       "Lifecycle event commands succeeded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusterAPI := getDiagnosticsClusterAPI(tt.pod)
+			succeeded, msg, err := CheckLifecycleEventsStatus(getLifecycleTestWorkspace(), clusterAPI)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSucceeded, succeeded)
+			assert.Equal(t, tt.expectedMsg, msg)
+		})
+	}
+}
+
+func TestCheckLifecycleEventsStatusReportsFailedPostStartHook(t *testing.T) {
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workspace-pod.hook",
+			Namespace: testNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "workspace-pod", Namespace: testNamespace},
+		Reason:         "FailedPostStartHook",
+		Message:        "Exec lifecycle hook for Container \"tools\" failed",
+	}
+	clusterAPI := getDiagnosticsClusterAPI(getLifecycleTestPod(
+		corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+		corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}), event)
+
+	succeeded, msg, err := CheckLifecycleEventsStatus(getLifecycleTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.False(t, succeeded)
+	assert.Equal(t, "PostStart commands failed: Exec lifecycle hook for Container \"tools\" failed", msg)
+}
+
+func TestCheckLifecycleEventsStatusWithoutEvents(t *testing.T) {
+	clusterAPI := getDiagnosticsClusterAPI(getResourcesTestPod(""))
+	succeeded, msg, err := CheckLifecycleEventsStatus(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.False(t, succeeded)
+	assert.Empty(t, msg, "Should not report status if DevWorkspace does not bind commands to events")
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// CheckProjectCloneStatus checks the state of the project clone init container in a DevWorkspace's pod. Returns whether
+// all projects were cloned successfully and a user-readable message describing the progress of project cloning or any
+// errors encountered. If the DevWorkspace's pod does not have a project clone init container (e.g. it has not been
+// created yet), an empty message is returned.
+func CheckProjectCloneStatus(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (cloned bool, msg string, err error) {
+	podList := &corev1.PodList{}
+	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, podList, k8sclient.InNamespace(workspace.Namespace), workspaceIDLabel); err != nil {
+		return false, "", err
+	}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, containerStatus := range pod.Status.InitContainerStatuses {
+			if containerStatus.Name != projects.ProjectCloneContainerName {
+				continue
+			}
+			cloned, msg := getProjectCloneStatus(&containerStatus)
+			return cloned, msg, nil
+		}
+	}
+	return false, "", nil
+}
+
+func getProjectCloneStatus(containerStatus *corev1.ContainerStatus) (cloned bool, msg string) {
+	terminated := containerStatus.State.Terminated
+	if terminated == nil {
+		if lastTerminated := containerStatus.LastTerminationState.Terminated; lastTerminated != nil {
+			return false, fmt.Sprintf("Retrying project clone after failure: %s", getTerminationDetails(lastTerminated))
+		}
+		return false, "Cloning projects"
+	}
+	if terminated.ExitCode != 0 {
+		return false, fmt.Sprintf("Project clone failed: %s", getTerminationDetails(terminated))
+	}
+	// The project clone container exits successfully when projects could not be set up, so that the workspace can
+	// still start, and reports the errors in its termination message instead
+	if terminated.Message != "" {
+		return false, fmt.Sprintf("Failed to set up projects: %s", terminated.Message)
+	}
+	return true, "Projects cloned"
+}
+
+func getTerminationDetails(terminated *corev1.ContainerStateTerminated) string {
+	if terminated.Message != "" {
+		return terminated.Message
+	}
+	return fmt.Sprintf("container exited with code %d (%s)", terminated.ExitCode, terminated.Reason)
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+)
+
+func getProjectCloneTestPod(state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workspace-pod",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel: testWorkspaceID,
+			},
+		},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  projects.ProjectCloneContainerName,
+					State: state,
+				},
+			},
+		},
+	}
+}
+
+func TestCheckProjectCloneStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		state          corev1.ContainerState
+		expectedCloned bool
+		expectedMsg    string
+	}{
+		{
+			name:        "Reports projects being cloned",
+			state:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			expectedMsg: "Cloning projects",
+		},
+		{
+			name:           "Reports projects cloned",
+			state:          corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}},
+			expectedCloned: true,
+			expectedMsg:    "Projects cloned",
+		},
+		{
+			name: "Reports errors from termination message",
+			state: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 0,
+				Reason:   "Completed",
+				Message:  "my-project: failed to clone repository",
+			}},
+			expectedMsg: "Failed to set up projects: my-project: failed to clone repository",
+		},
+		{
+			name:        "Reports failed project clone container",
+			state:       corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+			expectedMsg: "Project clone failed: container exited with code 1 (Error)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusterAPI := getDiagnosticsClusterAPI(getProjectCloneTestPod(tt.state))
+			cloned, msg, err := CheckProjectCloneStatus(getDiagnosticsTestWorkspace(), clusterAPI)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCloned, cloned)
+			assert.Equal(t, tt.expectedMsg, msg)
+		})
+	}
+}
+
+func TestCheckProjectCloneStatusWithoutProjectClone(t *testing.T) {
+	clusterAPI := getDiagnosticsClusterAPI(getResourcesTestPod(""))
+	cloned, msg, err := CheckProjectCloneStatus(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.False(t, cloned)
+	assert.Empty(t, msg, "Should not report status if pod has no project clone init container")
+}
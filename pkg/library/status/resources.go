@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	oomKilledReason = "OOMKilled"
+	evictedReason   = "Evicted"
+
+	// Annotations set by the kubelet on evicted pods to describe which resource was starved and which containers
+	// used more of that resource than they requested
+	starvedResourceAnnotation          = "starved_resource"
+	offendingContainersAnnotation      = "offending_containers"
+	offendingContainersUsageAnnotation = "offending_containers_usage"
+)
+
+// memoryLimitIncrement is the granularity of suggested memory limits
+var memoryLimitIncrement = resource.MustParse("128Mi")
+
+// CheckPodsForResourceFailures checks whether containers in a DevWorkspace's pods were killed for exceeding their memory
+// limit, or whether the DevWorkspace's pods were evicted from their node due to resource pressure. Returns a user-readable
+// explanation that suggests how the DevWorkspace's resources should be adjusted, or an empty string if no such failures
+// occurred.
+func CheckPodsForResourceFailures(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (string, error) {
+	podList := &corev1.PodList{}
+	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, podList, k8sclient.InNamespace(workspace.Namespace), workspaceIDLabel); err != nil {
+		return "", err
+	}
+	var messages []string
+	for _, pod := range podList.Items {
+		if _, isJobPod := pod.Labels["job-name"]; isJobPod {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == evictedReason {
+			messages = append(messages, getEvictionMessage(&pod))
+			continue
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if isOOMKilled(&containerStatus) {
+				messages = append(messages, getOOMKilledMessage(&pod, containerStatus.Name))
+			}
+		}
+	}
+	return strings.Join(messages, "; "), nil
+}
+
+func isOOMKilled(containerStatus *corev1.ContainerStatus) bool {
+	if terminated := containerStatus.State.Terminated; terminated != nil && terminated.Reason == oomKilledReason {
+		return true
+	}
+	if lastTerminated := containerStatus.LastTerminationState.Terminated; lastTerminated != nil && lastTerminated.Reason == oomKilledReason {
+		return true
+	}
+	return false
+}
+
+// getOOMKilledMessage explains that a container ran out of memory and suggests a new memory limit for the container.
+func getOOMKilledMessage(pod *corev1.Pod, containerName string) string {
+	container := getPodContainer(pod, containerName)
+	if container == nil {
+		return fmt.Sprintf("Container %s was killed because it ran out of memory", containerName)
+	}
+	limit, hasLimit := container.Resources.Limits[corev1.ResourceMemory]
+	if !hasLimit || limit.IsZero() {
+		return fmt.Sprintf("Container %s was killed because its node ran out of memory; consider setting a memoryLimit and memoryRequest for the container", containerName)
+	}
+	suggested := suggestMemoryLimit(limit)
+	return fmt.Sprintf("Container %s exceeded its %s memory limit; consider increasing its memoryLimit to %s", containerName, limit.String(), suggested.String())
+}
+
+// getEvictionMessage explains why a pod was evicted from its node, using the annotations set on the pod by the kubelet
+// when available.
+func getEvictionMessage(pod *corev1.Pod) string {
+	starvedResource := pod.Annotations[starvedResourceAnnotation]
+	offendingContainers := pod.Annotations[offendingContainersAnnotation]
+	if starvedResource == "" || offendingContainers == "" {
+		if pod.Status.Message == "" {
+			return fmt.Sprintf("Pod %s was evicted from its node", pod.Name)
+		}
+		return fmt.Sprintf("Pod %s was evicted: %s", pod.Name, pod.Status.Message)
+	}
+
+	containerNames := strings.Split(offendingContainers, ",")
+	usages := strings.Split(pod.Annotations[offendingContainersUsageAnnotation], ",")
+	var details []string
+	for idx, containerName := range containerNames {
+		if idx >= len(usages) || usages[idx] == "" {
+			continue
+		}
+		usage, err := resource.ParseQuantity(usages[idx])
+		if err != nil {
+			continue
+		}
+		detail := fmt.Sprintf("container %s was using %s of %s", containerName, usage.String(), starvedResource)
+		var request resource.Quantity
+		if container := getPodContainer(pod, containerName); container != nil {
+			request = container.Resources.Requests[corev1.ResourceName(starvedResource)]
+		}
+		if !request.IsZero() {
+			detail = fmt.Sprintf("%s, more than its request of %s", detail, request.String())
+		}
+		if corev1.ResourceName(starvedResource) == corev1.ResourceMemory {
+			suggested := roundUpQuantity(usage, memoryLimitIncrement)
+			detail = fmt.Sprintf("%s; consider increasing its memoryRequest to at least %s", detail, suggested.String())
+		}
+		details = append(details, detail)
+	}
+	msg := fmt.Sprintf("Pod %s was evicted because its node was low on %s", pod.Name, starvedResource)
+	if len(details) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, strings.Join(details, "; "))
+	}
+	return msg
+}
+
+func getPodContainer(pod *corev1.Pod, containerName string) *corev1.Container {
+	for idx, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return &pod.Spec.Containers[idx]
+		}
+	}
+	return nil
+}
+
+// suggestMemoryLimit returns a memory limit 50% larger than the current limit, rounded up to a multiple of
+// memoryLimitIncrement.
+func suggestMemoryLimit(limit resource.Quantity) resource.Quantity {
+	suggested := resource.NewQuantity(limit.Value()+limit.Value()/2, resource.BinarySI)
+	return roundUpQuantity(*suggested, memoryLimitIncrement)
+}
+
+func roundUpQuantity(quantity, increment resource.Quantity) resource.Quantity {
+	value, step := quantity.Value(), increment.Value()
+	if remainder := value % step; remainder != 0 {
+		value += step - remainder
+	}
+	return *resource.NewQuantity(value, resource.BinarySI)
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getResourcesTestPod(memoryLimit string) *corev1.Pod {
+	container := corev1.Container{Name: "tools"}
+	if memoryLimit != "" {
+		container.Resources = corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse(memoryLimit),
+			},
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+		}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workspace-pod",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel: testWorkspaceID,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{container},
+		},
+	}
+}
+
+func TestNoResourceFailuresForHealthyPod(t *testing.T) {
+	pod := getResourcesTestPod("2Gi")
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:  "tools",
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		},
+	}
+	clusterAPI := getDiagnosticsClusterAPI(pod)
+
+	msg, err := CheckPodsForResourceFailures(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, msg)
+}
+
+func TestDetectsOOMKilledContainer(t *testing.T) {
+	pod := getResourcesTestPod("2Gi")
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: "tools",
+			State: corev1.ContainerState{
+				Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+			},
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+			},
+		},
+	}
+	clusterAPI := getDiagnosticsClusterAPI(pod)
+
+	msg, err := CheckPodsForResourceFailures(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, "Container tools exceeded its 2Gi memory limit; consider increasing its memoryLimit to 3Gi", msg)
+
+	stateMsg, err := CheckPodsState(testWorkspaceID, testNamespace,
+		k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: testWorkspaceID}, nil, clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, stateMsg, "CheckPodsState should explain containers in CrashLoopBackOff due to OOM kills")
+}
+
+func TestDetectsOOMKilledContainerWithoutLimit(t *testing.T) {
+	pod := getResourcesTestPod("")
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: "tools",
+			State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+			},
+		},
+	}
+	clusterAPI := getDiagnosticsClusterAPI(pod)
+
+	msg, err := CheckPodsForResourceFailures(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, "Container tools was killed because its node ran out of memory; consider setting a memoryLimit and memoryRequest for the container", msg)
+}
+
+func TestDetectsEvictedPod(t *testing.T) {
+	pod := getResourcesTestPod("2Gi")
+	pod.Annotations = map[string]string{
+		starvedResourceAnnotation:          "memory",
+		offendingContainersAnnotation:      "tools",
+		offendingContainersUsageAnnotation: "1100Mi",
+	}
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.Reason = "Evicted"
+	pod.Status.Message = "The node was low on resource: memory."
+	clusterAPI := getDiagnosticsClusterAPI(pod)
+
+	msg, err := CheckPodsForResourceFailures(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pod workspace-pod was evicted because its node was low on memory: container tools was using 1100Mi of memory, "+
+		"more than its request of 512Mi; consider increasing its memoryRequest to at least 1152Mi", msg)
+}
+
+func TestDetectsEvictedPodWithoutAnnotations(t *testing.T) {
+	pod := getResourcesTestPod("2Gi")
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.Reason = "Evicted"
+	pod.Status.Message = "The node was low on resource: ephemeral-storage."
+	clusterAPI := getDiagnosticsClusterAPI(pod)
+
+	msg, err := CheckPodsForResourceFailures(getDiagnosticsTestWorkspace(), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, "Pod workspace-pod was evicted: The node was low on resource: ephemeral-storage.", msg)
+}
+
+func TestSuggestMemoryLimit(t *testing.T) {
+	tests := map[string]string{
+		"2Gi":   "3Gi",
+		"1Gi":   "1536Mi",
+		"512Mi": "768Mi",
+		"100Mi": "256Mi",
+		"1G":    "1536Mi",
+	}
+	for limit, expected := range tests {
+		t.Run(limit, func(t *testing.T) {
+			suggested := suggestMemoryLimit(resource.MustParse(limit))
+			assert.Equal(t, expected, suggested.String())
+		})
+	}
+}
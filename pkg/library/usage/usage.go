@@ -0,0 +1,232 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package usage tracks how long DevWorkspaces run and how much CPU and memory they request while running, so that
+// chargeback reports can be generated. The total usage of a DevWorkspace is stored in an annotation on the
+// DevWorkspace, and monthly totals for all DevWorkspaces in a namespace are stored in a ConfigMap. The usage of the
+// current run is computed from the started-at annotation and is recorded when the DevWorkspace is stopped.
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in each namespace that holds the monthly usage of the namespace's
+	// DevWorkspaces. Each key is a month in the format "2006-01", and holds a JSON object mapping DevWorkspace names
+	// to their usage in that month.
+	ConfigMapName = "devworkspace-usage"
+
+	monthFormat = "2006-01"
+	bytesPerGiB = 1024 * 1024 * 1024
+)
+
+// Usage is the resource usage of a DevWorkspace over a period of time.
+type Usage struct {
+	// RunningHours is the time the DevWorkspace was running.
+	RunningHours float64 `json:"runningHours"`
+	// CPUHours is the number of CPU cores requested by the DevWorkspace's pod, multiplied by the time it was running.
+	CPUHours float64 `json:"cpuHours"`
+	// MemoryGiBHours is the memory in GiB requested by the DevWorkspace's pod, multiplied by the time it was running.
+	MemoryGiBHours float64 `json:"memoryGiBHours"`
+}
+
+// Add adds other to u. Values are rounded to four decimal places.
+func (u *Usage) Add(other Usage) {
+	u.RunningHours = round(u.RunningHours + other.RunningHours)
+	u.CPUHours = round(u.CPUHours + other.CPUHours)
+	u.MemoryGiBHours = round(u.MemoryGiBHours + other.MemoryGiBHours)
+}
+
+// Resources are the resources requested by a DevWorkspace's pod.
+type Resources struct {
+	CPUCores  float64
+	MemoryGiB float64
+}
+
+// GetRequestedResources returns the CPU and memory requested by all containers in a pod spec. Init containers are
+// not included, as they do not run for the lifetime of the pod.
+func GetRequestedResources(podSpec *corev1.PodSpec) Resources {
+	var resources Resources
+	for _, container := range podSpec.Containers {
+		if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			resources.CPUCores += cpu.AsApproximateFloat64()
+		}
+		if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			resources.MemoryGiB += memory.AsApproximateFloat64() / bytesPerGiB
+		}
+	}
+	return resources
+}
+
+// GetRecordedUsage returns the usage stored in the usage annotation of a DevWorkspace, which does not include the
+// current run.
+func GetRecordedUsage(workspace *dw.DevWorkspace) (Usage, error) {
+	usage := Usage{}
+	usageJSON, ok := workspace.Annotations[constants.DevWorkspaceUsageAnnotation]
+	if !ok {
+		return usage, nil
+	}
+	if err := json.Unmarshal([]byte(usageJSON), &usage); err != nil {
+		return usage, fmt.Errorf("failed to read annotation %s: %w", constants.DevWorkspaceUsageAnnotation, err)
+	}
+	return usage, nil
+}
+
+// GetCurrentRunUsage returns the usage of the current run of a DevWorkspace, split by month. An empty map is returned
+// if the DevWorkspace is not running.
+func GetCurrentRunUsage(workspace *dw.DevWorkspace, resources Resources, now time.Time) (map[string]Usage, error) {
+	startedAtMillis, ok := workspace.Annotations[constants.DevWorkspaceStartedAtAnnotation]
+	if !ok {
+		return map[string]Usage{}, nil
+	}
+	millis, err := strconv.ParseInt(startedAtMillis, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotation %s: %w", constants.DevWorkspaceStartedAtAnnotation, err)
+	}
+	return getMonthlyUsage(time.UnixMilli(millis), now, resources), nil
+}
+
+// GetTotalUsage returns the total usage of a DevWorkspace, including its current run.
+func GetTotalUsage(workspace *dw.DevWorkspace, resources Resources, now time.Time) (Usage, error) {
+	total, err := GetRecordedUsage(workspace)
+	if err != nil {
+		return total, err
+	}
+	currentRun, err := GetCurrentRunUsage(workspace, resources, now)
+	if err != nil {
+		return total, err
+	}
+	for _, monthlyUsage := range currentRun {
+		total.Add(monthlyUsage)
+	}
+	return total, nil
+}
+
+// RecordRun adds the usage of the current run of a DevWorkspace to its usage annotation and returns the usage of the
+// run split by month, which should be recorded in the usage ConfigMap using RecordInConfigMap once the DevWorkspace
+// is updated. It should be called when the started-at annotation is removed from the DevWorkspace, in the same update.
+func RecordRun(workspace *dw.DevWorkspace, resources Resources, now time.Time) (map[string]Usage, error) {
+	total, err := GetRecordedUsage(workspace)
+	if err != nil {
+		return nil, err
+	}
+	currentRun, err := GetCurrentRunUsage(workspace, resources, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, monthlyUsage := range currentRun {
+		total.Add(monthlyUsage)
+	}
+	usageJSON, err := json.Marshal(total)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceUsageAnnotation] = string(usageJSON)
+	return currentRun, nil
+}
+
+// RecordInConfigMap adds the monthly usage of a DevWorkspace to the usage ConfigMap in its namespace, creating the
+// ConfigMap if necessary.
+func RecordInConfigMap(ctx context.Context, c client.Client, namespace, workspaceName string, monthlyUsage map[string]Usage) error {
+	if len(monthlyUsage) == 0 {
+		return nil
+	}
+	namespacedName := types.NamespacedName{Name: ConfigMapName, Namespace: namespace}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm := &corev1.ConfigMap{}
+		err := c.Get(ctx, namespacedName, cm)
+		create := k8sErrors.IsNotFound(err)
+		if create {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ConfigMapName,
+					Namespace: namespace,
+					Labels:    constants.ControllerAppLabels(),
+				},
+			}
+		} else if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		for month, usage := range monthlyUsage {
+			workspaces := map[string]Usage{}
+			if monthJSON, ok := cm.Data[month]; ok {
+				if err := json.Unmarshal([]byte(monthJSON), &workspaces); err != nil {
+					return fmt.Errorf("failed to read usage for %s from ConfigMap %s: %w", month, ConfigMapName, err)
+				}
+			}
+			workspaceUsage := workspaces[workspaceName]
+			workspaceUsage.Add(usage)
+			workspaces[workspaceName] = workspaceUsage
+			monthJSON, err := json.Marshal(workspaces)
+			if err != nil {
+				return err
+			}
+			cm.Data[month] = string(monthJSON)
+		}
+		if create {
+			return c.Create(ctx, cm)
+		}
+		return c.Update(ctx, cm)
+	})
+}
+
+// getMonthlyUsage returns the usage of a run from start to end, split at month boundaries (in UTC).
+func getMonthlyUsage(start, end time.Time, resources Resources) map[string]Usage {
+	monthlyUsage := map[string]Usage{}
+	start, end = start.UTC(), end.UTC()
+	for start.Before(end) {
+		nextMonth := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		periodEnd := end
+		if nextMonth.Before(end) {
+			periodEnd = nextMonth
+		}
+		hours := periodEnd.Sub(start).Hours()
+		usage := monthlyUsage[start.Format(monthFormat)]
+		usage.Add(Usage{
+			RunningHours:   hours,
+			CPUHours:       hours * resources.CPUCores,
+			MemoryGiBHours: hours * resources.MemoryGiB,
+		})
+		monthlyUsage[start.Format(monthFormat)] = usage
+		start = periodEnd
+	}
+	return monthlyUsage
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
@@ -0,0 +1,134 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+var testNow = time.Date(2024, time.May, 1, 2, 0, 0, 0, time.UTC)
+
+var testResources = Resources{CPUCores: 1.5, MemoryGiB: 4}
+
+func getTestWorkspace(annotations map[string]string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Annotations = annotations
+	return workspace
+}
+
+func startedAt(t time.Time) string {
+	return fmt.Sprintf("%d", t.UnixMilli())
+}
+
+func TestGetRequestedResources(t *testing.T) {
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1"),
+						corev1.ResourceMemory: resource.MustParse("3Gi"),
+					},
+				},
+			},
+			{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1024Mi"),
+					},
+				},
+			},
+		},
+		InitContainers: []corev1.Container{
+			{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				},
+			},
+		},
+	}
+	assert.Equal(t, testResources, GetRequestedResources(podSpec))
+}
+
+func TestGetCurrentRunUsageSplitsByMonth(t *testing.T) {
+	workspace := getTestWorkspace(map[string]string{
+		constants.DevWorkspaceStartedAtAnnotation: startedAt(testNow.Add(-3 * time.Hour)),
+	})
+	monthlyUsage, err := GetCurrentRunUsage(workspace, testResources, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Usage{
+		"2024-04": {RunningHours: 1, CPUHours: 1.5, MemoryGiBHours: 4},
+		"2024-05": {RunningHours: 2, CPUHours: 3, MemoryGiBHours: 8},
+	}, monthlyUsage)
+}
+
+func TestGetCurrentRunUsageNotRunning(t *testing.T) {
+	monthlyUsage, err := GetCurrentRunUsage(getTestWorkspace(nil), testResources, testNow)
+	require.NoError(t, err)
+	assert.Empty(t, monthlyUsage)
+}
+
+func TestRecordRun(t *testing.T) {
+	workspace := getTestWorkspace(map[string]string{
+		constants.DevWorkspaceStartedAtAnnotation: startedAt(testNow.Add(-90 * time.Minute)),
+		constants.DevWorkspaceUsageAnnotation:     `{"runningHours":10,"cpuHours":15,"memoryGiBHours":40}`,
+	})
+	monthlyUsage, err := RecordRun(workspace, testResources, testNow)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Usage{"2024-05": {RunningHours: 1.5, CPUHours: 2.25, MemoryGiBHours: 6}}, monthlyUsage)
+
+	recorded, err := GetRecordedUsage(workspace)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{RunningHours: 11.5, CPUHours: 17.25, MemoryGiBHours: 46}, recorded)
+}
+
+func TestGetRecordedUsageReturnsErrorForInvalidAnnotation(t *testing.T) {
+	_, err := GetRecordedUsage(getTestWorkspace(map[string]string{constants.DevWorkspaceUsageAnnotation: "invalid"}))
+	assert.Error(t, err)
+}
+
+func TestRecordInConfigMap(t *testing.T) {
+	c := fake.NewClientBuilder().Build()
+	run := map[string]Usage{"2024-05": {RunningHours: 2, CPUHours: 3, MemoryGiBHours: 8}}
+	require.NoError(t, RecordInConfigMap(context.Background(), c, "test-namespace", "test-workspace", run))
+	require.NoError(t, RecordInConfigMap(context.Background(), c, "test-namespace", "test-workspace", run))
+	require.NoError(t, RecordInConfigMap(context.Background(), c, "test-namespace", "other-workspace", run))
+
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: ConfigMapName, Namespace: "test-namespace"}, cm))
+	workspaces := map[string]Usage{}
+	require.NoError(t, json.Unmarshal([]byte(cm.Data["2024-05"]), &workspaces))
+	assert.Equal(t, map[string]Usage{
+		"test-workspace":  {RunningHours: 4, CPUHours: 6, MemoryGiBHours: 16},
+		"other-workspace": {RunningHours: 2, CPUHours: 3, MemoryGiBHours: 8},
+	}, workspaces)
+}
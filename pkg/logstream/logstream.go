@@ -0,0 +1,303 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package logstream implements the log streaming endpoint, which streams the logs of the containers of DevWorkspaces
+// to their users, e.g. to show the progress of a starting DevWorkspace in an IDE or dashboard, without requiring users
+// to have access to pods/log through the Kubernetes API.
+package logstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/access"
+	"github.com/devfile/devworkspace-operator/pkg/library/pods"
+)
+
+// PathPrefix is the path under which logs are served. The containers of a DevWorkspace are listed at
+// <PathPrefix><namespace>/<workspace name>, and the logs of a container are streamed from
+// <PathPrefix><namespace>/<workspace name>/<container name>.
+const PathPrefix = "/logs/"
+
+// containerStartPollInterval is how often the state of a container is checked while waiting for it to start.
+const containerStartPollInterval = 2 * time.Second
+
+// LogReader reads the logs of pods. It is an interface to allow the streaming APIs, which are not supported by
+// controller-runtime clients, to be replaced in tests.
+type LogReader interface {
+	// Stream returns a stream of the logs of a container of a pod.
+	Stream(ctx context.Context, pod *corev1.Pod, options *corev1.PodLogOptions) (io.ReadCloser, error)
+}
+
+type clusterLogReader struct {
+	clientset kubernetes.Interface
+}
+
+var _ LogReader = (*clusterLogReader)(nil)
+
+// NewLogReader returns a LogReader that uses the Kubernetes API described by config.
+func NewLogReader(config *rest.Config) (LogReader, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterLogReader{clientset: clientset}, nil
+}
+
+func (r *clusterLogReader) Stream(ctx context.Context, pod *corev1.Pod, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return r.clientset.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream(ctx)
+}
+
+// Server serves the logs of the containers of DevWorkspaces. It implements http.Handler and is intended to be
+// registered on the controller manager's webhook server, so that it is served over TLS by the operator's manager
+// service.
+type Server struct {
+	// Client is used to review tokens and access and to read DevWorkspaces and their pods.
+	Client client.Client
+	Logs   LogReader
+	Log    logr.Logger
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// ContainerList is the response body when listing the containers of a DevWorkspace.
+type ContainerList struct {
+	Pod        string          `json:"pod"`
+	Phase      corev1.PodPhase `json:"phase"`
+	Containers []ContainerInfo `json:"containers"`
+}
+
+type ContainerInfo struct {
+	Name string `json:"name"`
+	// Init is true for init containers, which run to completion before the workspace containers are started.
+	Init bool `json:"init,omitempty"`
+	// State is one of "waiting", "running" or "terminated".
+	State string `json:"state"`
+	// Reason is the reason for the state of the container, if any, e.g. "ImagePullBackOff" or "Completed".
+	Reason string `json:"reason,omitempty"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logConfig := config.GetGlobalConfig().LogStreaming
+	if logConfig == nil || !pointer.BoolDeref(logConfig.Enable, false) {
+		http.Error(w, "log streaming is not enabled", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, PathPrefix), "/")
+	if (len(parts) != 2 && len(parts) != 3) || parts[0] == "" || parts[1] == "" || (len(parts) == 3 && parts[2] == "") {
+		http.Error(w, fmt.Sprintf("expected path %s<namespace>/<workspace>[/<container>]", PathPrefix), http.StatusNotFound)
+		return
+	}
+	namespace, name := parts[0], parts[1]
+	ctx := r.Context()
+	log := s.Log.WithValues("namespace", namespace, "workspace", name)
+
+	workspace, user := access.AuthorizeRequest(w, r, s.Client, namespace, name, authzv1.ResourceAttributes{
+		Verb:        "get",
+		Resource:    "pods",
+		Subresource: "log",
+	}, log)
+	if workspace == nil {
+		return
+	}
+	log = log.WithValues("user", user.Username)
+
+	pod, err := pods.GetNewestWorkspacePod(ctx, s.Client, workspace.Namespace, workspace.Status.DevWorkspaceId)
+	if err != nil {
+		log.Error(err, "Failed to get pod for log request")
+		http.Error(w, "failed to get workspace pod", http.StatusInternalServerError)
+		return
+	}
+	if pod == nil {
+		http.Error(w, fmt.Sprintf("workspace %s has no pod", workspace.Name), http.StatusConflict)
+		return
+	}
+
+	if len(parts) == 2 {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(getContainerList(pod)); err != nil {
+			log.Error(err, "Failed to write container list")
+		}
+		return
+	}
+	s.streamLogs(w, r, pod, parts[2], log)
+}
+
+// streamLogs writes the logs of a container to the response, flushing as logs are read. The following query
+// parameters are supported:
+//
+//   - follow: if "true", logs are streamed until the container terminates or the client disconnects. If the container
+//     has not started yet, the response waits until it starts.
+//   - tailLines: the number of lines from the end of the logs to start at.
+//   - previous: if "true", return the logs of the previous instance of the container, e.g. after it was restarted.
+func (s *Server) streamLogs(w http.ResponseWriter, r *http.Request, pod *corev1.Pod, container string, log logr.Logger) {
+	ctx := r.Context()
+	if !hasContainer(pod, container) {
+		http.Error(w, fmt.Sprintf("workspace pod has no container %s", container), http.StatusNotFound)
+		return
+	}
+	options := &corev1.PodLogOptions{
+		Container: container,
+		Follow:    r.URL.Query().Get("follow") == "true",
+		Previous:  r.URL.Query().Get("previous") == "true",
+	}
+	if tailLines := r.URL.Query().Get("tailLines"); tailLines != "" {
+		lines, err := strconv.ParseInt(tailLines, 10, 64)
+		if err != nil || lines < 0 {
+			http.Error(w, "tailLines must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		options.TailLines = &lines
+	}
+
+	if !options.Previous && !isContainerStarted(pod, container) {
+		if !options.Follow {
+			http.Error(w, fmt.Sprintf("container %s has not started yet", container), http.StatusConflict)
+			return
+		}
+		var err error
+		pod, err = s.waitForContainerStart(ctx, pod, container)
+		if err != nil {
+			// The client disconnected, or the pod was removed while waiting
+			http.Error(w, fmt.Sprintf("container %s did not start: %s", container, err), http.StatusConflict)
+			return
+		}
+	}
+
+	logs, err := s.Logs.Stream(ctx, pod, options)
+	if err != nil {
+		log.Error(err, "Failed to read container logs", "container", container)
+		http.Error(w, "failed to read container logs", http.StatusBadGateway)
+		return
+	}
+	defer logs.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := logs.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr != nil {
+			if readErr != io.EOF && ctx.Err() == nil {
+				log.Info("Log stream ended with error", "container", container, "error", readErr.Error())
+			}
+			return
+		}
+	}
+}
+
+// waitForContainerStart waits until a container of a pod is running or terminated, returning the updated pod.
+func (s *Server) waitForContainerStart(ctx context.Context, pod *corev1.Pod, container string) (*corev1.Pod, error) {
+	current := &corev1.Pod{}
+	err := wait.PollImmediateUntilWithContext(ctx, containerStartPollInterval, func(ctx context.Context) (bool, error) {
+		if err := s.Client.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, current); err != nil {
+			return false, err
+		}
+		if current.DeletionTimestamp != nil {
+			return false, fmt.Errorf("pod %s is being deleted", pod.Name)
+		}
+		return isContainerStarted(current, container), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+func getContainerList(pod *corev1.Pod) *ContainerList {
+	list := &ContainerList{
+		Pod:        pod.Name,
+		Phase:      pod.Status.Phase,
+		Containers: []ContainerInfo{},
+	}
+	addContainers := func(containers []corev1.Container, init bool) {
+		for _, container := range containers {
+			info := ContainerInfo{Name: container.Name, Init: init, State: "waiting"}
+			if status := getContainerStatus(pod, container.Name); status != nil {
+				switch {
+				case status.State.Running != nil:
+					info.State = "running"
+				case status.State.Terminated != nil:
+					info.State = "terminated"
+					info.Reason = status.State.Terminated.Reason
+				case status.State.Waiting != nil:
+					info.Reason = status.State.Waiting.Reason
+				}
+			}
+			list.Containers = append(list.Containers, info)
+		}
+	}
+	addContainers(pod.Spec.InitContainers, true)
+	addContainers(pod.Spec.Containers, false)
+	return list
+}
+
+func hasContainer(pod *corev1.Pod, container string) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, podContainer := range containers {
+			if podContainer.Name == container {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getContainerStatus(pod *corev1.Pod, container string) *corev1.ContainerStatus {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for idx := range statuses {
+			if statuses[idx].Name == container {
+				return &statuses[idx]
+			}
+		}
+	}
+	return nil
+}
+
+// isContainerStarted returns whether a container is running or has terminated, i.e. whether it has logs.
+func isContainerStarted(pod *corev1.Pod, container string) bool {
+	status := getContainerStatus(pod, container)
+	return status != nil && (status.State.Running != nil || status.State.Terminated != nil)
+}
@@ -0,0 +1,240 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package logstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	creatorToken   = "creator-token"
+	creatorUID     = testutil.TestCreatorUID
+	logUserToken   = "log-user-token"
+	otherUserToken = "other-user-token"
+)
+
+// newReviewClient returns a fake client with objs that authenticates the test tokens and allows log-user to read pod logs.
+func newReviewClient(objs ...client.Object) *testutil.ReviewClient {
+	return &testutil.ReviewClient{
+		Client: testutil.NewFakeClient(objs...),
+		Users: map[string]authnv1.UserInfo{
+			creatorToken:   {Username: "creator", UID: creatorUID},
+			logUserToken:   {Username: "log-user", UID: "log-user-uid"},
+			otherUserToken: {Username: "other-user", UID: "other-user-uid"},
+		},
+		Permissions: map[string]authzv1.ResourceAttributes{
+			"log-user": {Verb: "get", Resource: "pods", Subresource: "log"},
+		},
+	}
+}
+
+// testLogReader returns "<pod>/<container>: line <n>" log lines.
+type testLogReader struct {
+	options []*corev1.PodLogOptions
+}
+
+func (r *testLogReader) Stream(_ context.Context, pod *corev1.Pod, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	r.options = append(r.options, options)
+	logs := ""
+	for i := 1; i <= 3; i++ {
+		logs += fmt.Sprintf("%s/%s: line %d\n", pod.Name, options.Container, i)
+	}
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+func getTestPod(name, workspaceID string, created time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			Labels:            map[string]string{constants.DevWorkspaceIDLabel: workspaceID},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "project-clone"}},
+			Containers:     []corev1.Container{{Name: "tools"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "project-clone",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "tools",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+			}},
+		},
+	}
+}
+
+func setupTestServer(t *testing.T, objs ...client.Object) (*httptest.Server, *testLogReader, client.Client) {
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		LogStreaming: &v1alpha1.LogStreamingConfig{
+			Enable: pointer.Bool(true),
+		},
+	})
+	logReader := &testLogReader{}
+	fakeClient := newReviewClient(objs...)
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, &Server{
+		Client: fakeClient,
+		Logs:   logReader,
+		Log:    zap.New(),
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, logReader, fakeClient
+}
+
+func doRequest(t *testing.T, server *httptest.Server, path, token string) (int, string) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestListContainers(t *testing.T) {
+	now := time.Now()
+	oldPod := getTestPod("old-pod", "test-workspaceid", now.Add(-time.Hour))
+	oldPod.DeletionTimestamp = &metav1.Time{Time: now}
+	oldPod.Finalizers = []string{"test-finalizer"}
+	server, _, _ := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusStarting),
+		oldPod,
+		getTestPod("new-pod", "test-workspaceid", now))
+
+	code, body := doRequest(t, server, PathPrefix+testNamespace+"/test-workspace", creatorToken)
+	require.Equal(t, http.StatusOK, code, body)
+	list := &ContainerList{}
+	require.NoError(t, json.Unmarshal([]byte(body), list))
+	assert.Equal(t, &ContainerList{
+		Pod:   "new-pod",
+		Phase: corev1.PodPending,
+		Containers: []ContainerInfo{
+			{Name: "project-clone", Init: true, State: "running"},
+			{Name: "tools", State: "waiting", Reason: "PodInitializing"},
+		},
+	}, list, "Should list containers of the newest pod that is not being deleted")
+}
+
+func TestStreamLogs(t *testing.T) {
+	server, logReader, _ := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusStarting),
+		getTestPod("test-pod", "test-workspaceid", time.Now()))
+
+	code, body := doRequest(t, server, PathPrefix+testNamespace+"/test-workspace/project-clone?follow=true&tailLines=10", creatorToken)
+	assert.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, "test-pod/project-clone: line 1\ntest-pod/project-clone: line 2\ntest-pod/project-clone: line 3\n", body)
+	if assert.Len(t, logReader.options, 1) {
+		assert.Equal(t, &corev1.PodLogOptions{Container: "project-clone", Follow: true, TailLines: pointer.Int64(10)}, logReader.options[0])
+	}
+
+	code, _ = doRequest(t, server, PathPrefix+testNamespace+"/test-workspace/tools", creatorToken)
+	assert.Equal(t, http.StatusConflict, code, "Should not wait for container to start when not following logs")
+	code, _ = doRequest(t, server, PathPrefix+testNamespace+"/test-workspace/project-clone?tailLines=-1", creatorToken)
+	assert.Equal(t, http.StatusBadRequest, code, "Should reject invalid tailLines")
+}
+
+func TestStreamLogsWaitsForContainerStart(t *testing.T) {
+	pod := getTestPod("test-pod", "test-workspaceid", time.Now())
+	server, _, fakeClient := setupTestServer(t, testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusStarting), pod)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+		_ = fakeClient.Status().Update(context.Background(), pod)
+	}()
+	code, body := doRequest(t, server, PathPrefix+testNamespace+"/test-workspace/tools?follow=true", creatorToken)
+	assert.Equal(t, http.StatusOK, code, body)
+	assert.True(t, strings.HasPrefix(body, "test-pod/tools: line 1\n"), "Should stream logs once container starts")
+}
+
+func TestLogsRequireAccess(t *testing.T) {
+	restricted := testutil.NewDevWorkspace("restricted-workspace", dw.DevWorkspaceStatusStarting)
+	restricted.Annotations = map[string]string{constants.DevWorkspaceRestrictedAccessAnnotation: "true"}
+	notStarted := testutil.NewDevWorkspace("not-started-workspace", dw.DevWorkspaceStatusStarting)
+	notStarted.Status.DevWorkspaceId = ""
+	server, _, _ := setupTestServer(t,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusStarting),
+		getTestPod("test-pod", "test-workspaceid", time.Now()),
+		restricted,
+		getTestPod("restricted-pod", "restricted-workspaceid", time.Now()),
+		notStarted)
+
+	tests := []struct {
+		name         string
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"No token", "test-workspace", "", http.StatusUnauthorized},
+		{"Invalid token", "test-workspace", "invalid-token", http.StatusUnauthorized},
+		{"User without access", "test-workspace", otherUserToken, http.StatusForbidden},
+		{"User with pods/log access", "test-workspace/project-clone", logUserToken, http.StatusOK},
+		{"Workspace not found", "missing-workspace", logUserToken, http.StatusForbidden},
+		{"Restricted access workspace", "restricted-workspace", logUserToken, http.StatusForbidden},
+		{"Restricted access workspace creator", "restricted-workspace", creatorToken, http.StatusOK},
+		{"Workspace without pod", "not-started-workspace", creatorToken, http.StatusConflict},
+		{"Container not found", "test-workspace/missing", creatorToken, http.StatusNotFound},
+		{"Invalid path", "test-workspace/tools/extra", creatorToken, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, body := doRequest(t, server, PathPrefix+testNamespace+"/"+tt.path, tt.token)
+			assert.Equal(t, tt.expectedCode, code, body)
+		})
+	}
+}
+
+func TestLogStreamingDisabled(t *testing.T) {
+	server, _, _ := setupTestServer(t, testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusStarting))
+	config.SetGlobalConfigForTesting(nil)
+	code, _ := doRequest(t, server, PathPrefix+testNamespace+"/test-workspace", creatorToken)
+	assert.Equal(t, http.StatusNotFound, code)
+}
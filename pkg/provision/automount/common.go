@@ -73,7 +73,7 @@ func ProvisionAutoMountResourcesInto(podAdditions *v1alpha1.PodAdditions, api sy
 		podAdditions.Volumes = append(podAdditions.Volumes, resources.Volumes...)
 	}
 
-	return nil
+	return checkAutomountEnvVarsForCollision(api, namespace)
 }
 
 func getAutomountResources(api sync.ClusterAPI, namespace string) (*Resources, error) {
@@ -354,3 +354,59 @@ func sortConfigmaps(cms []corev1.ConfigMap) {
 		return cms[i].Name < cms[j].Name
 	})
 }
+
+// checkAutomountEnvVarsForCollision checks whether configmaps and secrets that are automatically mounted as environment
+// variables define the same keys. As Kubernetes silently uses the value from the last source in this case, collisions
+// are returned as a WarningError rather than failing the workspace.
+func checkAutomountEnvVarsForCollision(api sync.ClusterAPI, namespace string) error {
+	envVarSources := map[string][]string{}
+
+	configmaps := &corev1.ConfigMapList{}
+	if err := api.Client.List(api.Ctx, configmaps, k8sclient.InNamespace(namespace), k8sclient.MatchingLabels{
+		constants.DevWorkspaceMountLabel: "true",
+	}); err != nil {
+		return err
+	}
+	for _, configmap := range configmaps.Items {
+		if configmap.Annotations[constants.DevWorkspaceMountAsAnnotation] != constants.DevWorkspaceMountAsEnv {
+			continue
+		}
+		for key := range configmap.Data {
+			envVarSources[key] = append(envVarSources[key], fmt.Sprintf("configmap %s", configmap.Name))
+		}
+		for key := range configmap.BinaryData {
+			envVarSources[key] = append(envVarSources[key], fmt.Sprintf("configmap %s", configmap.Name))
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := api.Client.List(api.Ctx, secrets, k8sclient.InNamespace(namespace), k8sclient.MatchingLabels{
+		constants.DevWorkspaceMountLabel: "true",
+	}); err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if secret.Annotations[constants.DevWorkspaceMountAsAnnotation] != constants.DevWorkspaceMountAsEnv {
+			continue
+		}
+		for key := range secret.Data {
+			envVarSources[key] = append(envVarSources[key], fmt.Sprintf("secret %s", secret.Name))
+		}
+	}
+
+	var collisions []string
+	for envVar, sources := range envVarSources {
+		if len(sources) > 1 {
+			sort.Strings(sources)
+			collisions = append(collisions, fmt.Sprintf("%s (%s)", envVar, strings.Join(sources, ", ")))
+		}
+	}
+	if len(collisions) == 0 {
+		return nil
+	}
+	// Sort to avoid random map iteration order
+	sort.Strings(collisions)
+	return &dwerrors.WarningError{
+		Message: fmt.Sprintf("environment variables are defined by multiple automatically mounted resources: %s", strings.Join(collisions, "; ")),
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automount
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	sshKeysVolumeName = "ssh-keys"
+	sshKeysMountPath  = "/etc/ssh-keys/"
+	sshDirVolumeName  = "ssh-dir"
+	sshDirMountPath   = constants.HomeUserDirectory + ".ssh"
+	sshAgentSocket    = sshDirMountPath + "/agent.sock"
+)
+
+// sshKeysInitScript copies the SSH keys mounted from secrets into ~/.ssh. Secret volumes cannot be mounted with
+// permissions that SSH clients accept for private keys (as group permissions are added for the pod's fsGroup), so
+// keys are copied into an emptyDir volume that is mounted at ~/.ssh in all workspace containers instead.
+const sshKeysInitScript = `set -e
+for file in ` + sshKeysMountPath + `*/*; do
+  [ -f "$file" ] || continue
+  name=$(basename "$file")
+  [ "$name" = "` + constants.SSHSecretPassphraseKey + `" ] && continue
+  cp "$file" "$SSH_DIR/$name"
+  case "$name" in
+    *.pub) chmod 644 "$SSH_DIR/$name" ;;
+    *) chmod 600 "$SSH_DIR/$name" ;;
+  esac
+done
+if [ -n "$SSH_KNOWN_HOSTS" ]; then
+  printf '%s\n' "$SSH_KNOWN_HOSTS" > "$SSH_DIR/known_hosts"
+  chmod 644 "$SSH_DIR/known_hosts"
+fi
+if [ -n "$SSH_CONFIG" ]; then
+  printf '%s\n' "$SSH_CONFIG" > "$SSH_DIR/config"
+  chmod 600 "$SSH_DIR/config"
+fi
+chmod 700 "$SSH_DIR"
+`
+
+// sshAgentScript runs an ssh-agent and adds all private keys copied to ~/.ssh to it. If the secret that provided a
+// key contains a passphrase, it is passed to ssh-add through SSH_ASKPASS.
+const sshAgentScript = `rm -f "$SSH_AUTH_SOCK"
+ssh-agent -D -a "$SSH_AUTH_SOCK" &
+agent=$!
+while [ ! -S "$SSH_AUTH_SOCK" ]; do sleep 1; done
+printf '#!/bin/sh\ncat "$SSH_KEY_PASSPHRASE_FILE"\n' > /tmp/ssh-askpass
+chmod 700 /tmp/ssh-askpass
+for dir in ` + sshKeysMountPath + `*/; do
+  for file in "$dir"*; do
+    [ -f "$file" ] || continue
+    name=$(basename "$file")
+    case "$name" in
+      *.pub|` + constants.SSHSecretPassphraseKey + `) continue ;;
+    esac
+    SSH_KEY_PASSPHRASE_FILE="${dir}` + constants.SSHSecretPassphraseKey + `" SSH_ASKPASS=/tmp/ssh-askpass SSH_ASKPASS_REQUIRE=force DISPLAY=none \
+      ssh-add "$SSH_DIR/$name" < /dev/null || echo "Could not add $name to ssh-agent"
+  done
+done
+wait $agent
+`
+
+var (
+	sshContainerResources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("16Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+)
+
+// ProvisionSSHKeysInto mounts the SSH keys from secrets labeled with DevWorkspaceSSHKeyLabel in the namespace at
+// ~/.ssh in all containers in podAdditions. The keys, along with known_hosts and config files generated from the
+// provided config, are written by an init container that runs before all other init containers. If enabled, an
+// ssh-agent sidecar that loads the keys is added as well.
+func ProvisionSSHKeysInto(podAdditions *v1alpha1.PodAdditions, api sync.ClusterAPI, namespace string, config *v1alpha1.SSHConfig, persistentHome bool) error {
+	secrets, err := getSSHKeySecrets(api, namespace)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		config = &v1alpha1.SSHConfig{}
+	}
+	if len(secrets) == 0 && len(config.KnownHosts) == 0 && len(config.Hosts) == 0 {
+		return nil
+	}
+
+	keysVolume, err := getSSHKeysVolume(secrets)
+	if err != nil {
+		return err
+	}
+	sshDirVolume := corev1.Volume{
+		Name: sshDirVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	for _, volume := range podAdditions.Volumes {
+		if volume.Name == sshKeysVolumeName || volume.Name == sshDirVolumeName {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("DevWorkspace volume '%s' conflicts with volume used to mount SSH keys", volume.Name),
+			}
+		}
+	}
+	podAdditions.Volumes = append(podAdditions.Volumes, keysVolume, sshDirVolume)
+
+	sshDirVolumeMount := corev1.VolumeMount{
+		Name:      sshDirVolumeName,
+		MountPath: sshDirMountPath,
+	}
+	keysVolumeMount := corev1.VolumeMount{
+		Name:      sshKeysVolumeName,
+		MountPath: sshKeysMountPath,
+		ReadOnly:  true,
+	}
+	sshDirEnv := corev1.EnvVar{Name: "SSH_DIR", Value: sshDirMountPath}
+
+	image := config.Image
+	if image == "" {
+		image = images.GetProjectCloneImage()
+	}
+	initContainer := corev1.Container{
+		Name:            constants.SSHKeysInitContainerName,
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{sshKeysInitScript},
+		Env: []corev1.EnvVar{
+			sshDirEnv,
+			{Name: "SSH_KNOWN_HOSTS", Value: strings.Join(config.KnownHosts, "\n")},
+			{Name: "SSH_CONFIG", Value: getSSHConfig(config.Hosts)},
+		},
+		VolumeMounts: []corev1.VolumeMount{keysVolumeMount, sshDirVolumeMount},
+		Resources:    sshContainerResources,
+	}
+
+	agentEnabled := config.Agent != nil && pointer.BoolDeref(config.Agent.Enable, false) && len(secrets) > 0
+	var containerEnv []corev1.EnvVar
+	if agentEnabled {
+		containerEnv = append(containerEnv, corev1.EnvVar{Name: "SSH_AUTH_SOCK", Value: sshAgentSocket})
+	}
+	for idx, container := range podAdditions.Containers {
+		podAdditions.Containers[idx].VolumeMounts = append(container.VolumeMounts, sshDirVolumeMount)
+		podAdditions.Containers[idx].Env = append(container.Env, containerEnv...)
+	}
+	for idx, container := range podAdditions.InitContainers {
+		// Don't mount ~/.ssh into the persistent home init container, as it would be stowed into the home volume
+		if persistentHome && container.Name == constants.HomeInitComponentName {
+			continue
+		}
+		podAdditions.InitContainers[idx].VolumeMounts = append(container.VolumeMounts, sshDirVolumeMount)
+	}
+	podAdditions.InitContainers = append([]corev1.Container{initContainer}, podAdditions.InitContainers...)
+
+	if agentEnabled {
+		podAdditions.Containers = append(podAdditions.Containers, corev1.Container{
+			Name:            constants.SSHAgentContainerName,
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/bin/sh", "-c"},
+			Args:            []string{sshAgentScript},
+			Env:             append([]corev1.EnvVar{sshDirEnv}, containerEnv...),
+			VolumeMounts:    []corev1.VolumeMount{keysVolumeMount, sshDirVolumeMount},
+			Resources:       sshContainerResources,
+		})
+	}
+	return nil
+}
+
+func getSSHKeySecrets(api sync.ClusterAPI, namespace string) ([]corev1.Secret, error) {
+	secretList := &corev1.SecretList{}
+	if err := api.Client.List(api.Ctx, secretList, k8sclient.InNamespace(namespace), k8sclient.MatchingLabels{
+		constants.DevWorkspaceSSHKeyLabel: "true",
+	}); err != nil {
+		return nil, err
+	}
+	secrets := secretList.Items
+	sortSecrets(secrets)
+	return secrets, nil
+}
+
+// getSSHKeysVolume returns a projected volume that contains the keys of each secret in a directory named after the
+// secret. As all keys are copied into the same directory, it is an error for two secrets to contain the same key.
+func getSSHKeysVolume(secrets []corev1.Secret) (corev1.Volume, error) {
+	keyOwners := map[string]string{}
+	var sources []corev1.VolumeProjection
+	for _, secret := range secrets {
+		var keys []string
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var items []corev1.KeyToPath
+		for _, key := range keys {
+			if key != constants.SSHSecretPassphraseKey {
+				if owner, exists := keyOwners[key]; exists {
+					return corev1.Volume{}, &dwerrors.FailError{
+						Message: fmt.Sprintf("SSH key secrets '%s' and '%s' both contain key '%s'", owner, secret.Name, key),
+					}
+				}
+				keyOwners[key] = secret.Name
+			}
+			items = append(items, corev1.KeyToPath{Key: key, Path: path.Join(secret.Name, key)})
+		}
+		sources = append(sources, corev1.VolumeProjection{
+			Secret: &corev1.SecretProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+				Items:                items,
+			},
+		})
+	}
+	return corev1.Volume{
+		Name: sshKeysVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources:     sources,
+				DefaultMode: defaultAccessMode,
+			},
+		},
+	}, nil
+}
+
+// getSSHConfig formats the configured hosts as an ssh_config file. Options are sorted by name so that the generated
+// file does not change between reconciles.
+func getSSHConfig(hosts []v1alpha1.SSHHostConfig) string {
+	var entries []string
+	for _, host := range hosts {
+		entry := fmt.Sprintf("Host %s\n", host.Host)
+		var options []string
+		for option := range host.Options {
+			options = append(options, option)
+		}
+		sort.Strings(options)
+		for _, option := range options {
+			entry += fmt.Sprintf("  %s %s\n", option, host.Options[option])
+		}
+		entries = append(entries, entry)
+	}
+	return strings.Join(entries, "\n")
+}
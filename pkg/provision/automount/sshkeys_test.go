@@ -0,0 +1,133 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automount
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func buildSSHKeySecret(name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceSSHKeyLabel:      "true",
+				constants.DevWorkspaceWatchSecretLabel: "true",
+			},
+		},
+		Data: data,
+	}
+}
+
+func getSSHTestPodAdditions() *v1alpha1.PodAdditions {
+	return &v1alpha1.PodAdditions{
+		Containers:     []corev1.Container{{Name: "tools"}},
+		InitContainers: []corev1.Container{{Name: "project-clone"}},
+	}
+}
+
+func TestProvisionSSHKeysDoesNothingWithoutKeysOrConfig(t *testing.T) {
+	clusterAPI := sync.ClusterAPI{
+		Client: fake.NewClientBuilder().Build(),
+		Logger: zap.New(),
+	}
+	podAdditions := getSSHTestPodAdditions()
+	err := ProvisionSSHKeysInto(podAdditions, clusterAPI, testNamespace, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, getSSHTestPodAdditions(), podAdditions, "Should not modify pod additions")
+}
+
+func TestProvisionSSHKeys(t *testing.T) {
+	clusterAPI := sync.ClusterAPI{
+		Client: fake.NewClientBuilder().WithObjects(
+			buildSSHKeySecret("github-key", map[string][]byte{
+				"id_github":                      []byte("private"),
+				"id_github.pub":                  []byte("public"),
+				constants.SSHSecretPassphraseKey: []byte("passphrase"),
+			}),
+		).Build(),
+		Logger: zap.New(),
+	}
+	config := &v1alpha1.SSHConfig{
+		KnownHosts: []string{"github.com ssh-ed25519 AAAA"},
+		Hosts: []v1alpha1.SSHHostConfig{
+			{Host: "github.com", Options: map[string]string{"User": "git", "IdentityFile": "~/.ssh/id_github"}},
+		},
+		Agent: &v1alpha1.SSHAgentConfig{Enable: pointer.Bool(true)},
+		Image: "ssh-image",
+	}
+	podAdditions := getSSHTestPodAdditions()
+	err := ProvisionSSHKeysInto(podAdditions, clusterAPI, testNamespace, config, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, podAdditions.Volumes, 2, "Should add volumes for SSH keys and ~/.ssh")
+	if assert.Len(t, podAdditions.InitContainers, 2) {
+		initContainer := podAdditions.InitContainers[0]
+		assert.Equal(t, constants.SSHKeysInitContainerName, initContainer.Name, "SSH keys init container should run first")
+		assert.Equal(t, "ssh-image", initContainer.Image)
+		assert.Contains(t, initContainer.Env, corev1.EnvVar{Name: "SSH_KNOWN_HOSTS", Value: "github.com ssh-ed25519 AAAA"})
+		assert.Contains(t, initContainer.Env, corev1.EnvVar{Name: "SSH_CONFIG", Value: "Host github.com\n  IdentityFile ~/.ssh/id_github\n  User git\n"})
+		assert.Contains(t, podAdditions.InitContainers[1].VolumeMounts, corev1.VolumeMount{Name: sshDirVolumeName, MountPath: sshDirMountPath})
+	}
+	if assert.Len(t, podAdditions.Containers, 2, "Should add ssh-agent sidecar") {
+		assert.Contains(t, podAdditions.Containers[0].VolumeMounts, corev1.VolumeMount{Name: sshDirVolumeName, MountPath: sshDirMountPath})
+		assert.Contains(t, podAdditions.Containers[0].Env, corev1.EnvVar{Name: "SSH_AUTH_SOCK", Value: sshAgentSocket})
+		assert.Equal(t, constants.SSHAgentContainerName, podAdditions.Containers[1].Name)
+	}
+}
+
+func TestProvisionSSHKeysFailsOnDuplicateKeys(t *testing.T) {
+	clusterAPI := sync.ClusterAPI{
+		Client: fake.NewClientBuilder().WithObjects(
+			buildSSHKeySecret("key-1", map[string][]byte{"id_rsa": []byte("private")}),
+			buildSSHKeySecret("key-2", map[string][]byte{"id_rsa": []byte("other")}),
+		).Build(),
+		Logger: zap.New(),
+	}
+	err := ProvisionSSHKeysInto(getSSHTestPodAdditions(), clusterAPI, testNamespace, nil, false)
+	if assert.Error(t, err) {
+		assert.IsType(t, &dwerrors.FailError{}, err)
+		assert.Contains(t, err.Error(), "SSH key secrets 'key-1' and 'key-2' both contain key 'id_rsa'")
+	}
+}
+
+func TestSSHKeysVolumeMapsKeysToSecretDirectories(t *testing.T) {
+	secret := buildSSHKeySecret("my-key", map[string][]byte{
+		"id_ed25519":                     []byte("private"),
+		constants.SSHSecretPassphraseKey: []byte("passphrase"),
+	})
+	volume, err := getSSHKeysVolume([]corev1.Secret{*secret})
+	if !assert.NoError(t, err) || !assert.NotNil(t, volume.Projected) {
+		return
+	}
+	assert.Equal(t, []corev1.KeyToPath{
+		{Key: "id_ed25519", Path: "my-key/id_ed25519"},
+		{Key: constants.SSHSecretPassphraseKey, Path: "my-key/passphrase"},
+	}, volume.Projected.Sources[0].Secret.Items)
+}
@@ -106,3 +106,24 @@ func GetNamespacePodTolerationsAndNodeSelector(namespace string, api sync.Cluste
 
 	return podTolerations, nodeSelector, nil
 }
+
+// GetNamespacePodAffinity gets the pod affinity that should be applied to all workspace pods in a given namespace. The
+// affinity is unmarshalled from a json-formatted annotation on the namespace itself. Returns nil if the namespace does
+// not define an affinity, and an error if the annotation is not valid JSON.
+func GetNamespacePodAffinity(namespace string, api sync.ClusterAPI) (*corev1.Affinity, error) {
+	ns := &corev1.Namespace{}
+	err := api.Client.Get(api.Ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	affinityAnnot, ok := ns.Annotations[constants.NamespacePodAffinityAnnotation]
+	if !ok || affinityAnnot == "" {
+		return nil, nil
+	}
+	affinity := &corev1.Affinity{}
+	if err := json.Unmarshal([]byte(affinityAnnot), affinity); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", constants.NamespacePodAffinityAnnotation, err)
+	}
+	return affinity, nil
+}
@@ -0,0 +1,298 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagepuller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	imagePullerName             = "devworkspace-image-puller"
+	defaultImagePullerInterval  = time.Hour
+	defaultImagePullerMaxImages = int32(10)
+	imagePullerSleepVolumeName  = "image-puller-sleep"
+	imagePullerSleepMountPath   = "/image-puller"
+	// imagePullerSleepDuration is the duration containers for pre-pulled images sleep for before restarting
+	imagePullerSleepDuration = "720h"
+)
+
+var imagePullerLabels = map[string]string{
+	"app.kubernetes.io/name":    imagePullerName,
+	"app.kubernetes.io/part-of": "devworkspace-operator",
+}
+
+var imagePullerResources = corev1.ResourceRequirements{
+	Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("5m"),
+		corev1.ResourceMemory: resource.MustParse("10Mi"),
+	},
+	Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("20m"),
+		corev1.ResourceMemory: resource.MustParse("20Mi"),
+	},
+}
+
+var prePulledImages = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Namespace: "devworkspace",
+		Name:      "image_puller_images",
+		Help:      "Number of images pre-pulled on nodes by the image puller DaemonSet",
+	},
+)
+
+func init() {
+	metrics.Registry.MustRegister(prePulledImages)
+}
+
+// ImagePuller periodically ranks the images used by DevWorkspaces by the number of DevWorkspaces that use them, and
+// keeps a DaemonSet that pre-pulls the most frequently used images in sync. It is intended to be added to the
+// controller manager, and is configured through the global DevWorkspaceOperatorConfig's workspace.imagePuller field.
+type ImagePuller struct {
+	// Client is used to read DevWorkspace deployments, which are cached by the controller manager
+	Client k8sclient.Client
+	// NonCachingClient is used to manage the DaemonSet, to avoid starting an informer for DaemonSets
+	NonCachingClient k8sclient.Client
+	Log              logr.Logger
+}
+
+// NeedLeaderElection ensures the DaemonSet is only managed by the manager that holds the leader lease.
+func (p *ImagePuller) NeedLeaderElection() bool {
+	return true
+}
+
+// Start syncs the image puller DaemonSet periodically until ctx is cancelled.
+func (p *ImagePuller) Start(ctx context.Context) error {
+	for {
+		pullerConfig := config.GetGlobalConfig().Workspace.ImagePuller
+		if err := p.sync(ctx, pullerConfig); err != nil {
+			p.Log.Error(err, "Failed to sync image puller DaemonSet")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(getImagePullerInterval(pullerConfig, p.Log)):
+		}
+	}
+}
+
+func (p *ImagePuller) sync(ctx context.Context, pullerConfig *controller.ImagePullerConfig) error {
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return err
+	}
+	enabled := pullerConfig != nil && pointer.BoolDeref(pullerConfig.Enable, false)
+	if enabled && pullerConfig.Image == "" {
+		p.Log.Info("An image must be configured for the image puller; workspace.imagePuller.enable is ignored")
+		enabled = false
+	}
+
+	var images []string
+	if enabled {
+		usage, err := p.getImageUsage(ctx)
+		if err != nil {
+			return err
+		}
+		images = getImagesToPull(pullerConfig, usage)
+	}
+	prePulledImages.Set(float64(len(images)))
+
+	clusterDaemonSet := &appsv1.DaemonSet{}
+	err = p.NonCachingClient.Get(ctx, types.NamespacedName{Name: imagePullerName, Namespace: namespace}, clusterDaemonSet)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if len(images) == 0 {
+			return nil
+		}
+		p.Log.Info("Creating image puller DaemonSet", "namespace", namespace, "images", images)
+		return p.NonCachingClient.Create(ctx, getSpecDaemonSet(namespace, pullerConfig.Image, images))
+	case err != nil:
+		return err
+	}
+
+	if len(images) == 0 {
+		p.Log.Info("Deleting image puller DaemonSet", "namespace", namespace)
+		err := p.NonCachingClient.Delete(ctx, clusterDaemonSet)
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	specDaemonSet := getSpecDaemonSet(namespace, pullerConfig.Image, images)
+	if equality.Semantic.DeepDerivative(specDaemonSet.Spec.Template, clusterDaemonSet.Spec.Template) {
+		return nil
+	}
+	p.Log.Info("Updating image puller DaemonSet", "namespace", namespace, "images", images)
+	clusterDaemonSet.Spec.Template = specDaemonSet.Spec.Template
+	return p.NonCachingClient.Update(ctx, clusterDaemonSet)
+}
+
+// getImageUsage returns the number of DevWorkspace deployments that use each image, including images used by init
+// containers.
+func (p *ImagePuller) getImageUsage(ctx context.Context) (map[string]int, error) {
+	deploymentList := &appsv1.DeploymentList{}
+	if err := p.Client.List(ctx, deploymentList, k8sclient.HasLabels{constants.DevWorkspaceIDLabel}); err != nil {
+		return nil, err
+	}
+	usage := map[string]int{}
+	for _, deployment := range deploymentList.Items {
+		deploymentImages := map[string]bool{}
+		for _, container := range deployment.Spec.Template.Spec.InitContainers {
+			deploymentImages[container.Image] = true
+		}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			deploymentImages[container.Image] = true
+		}
+		for image := range deploymentImages {
+			if image == "" {
+				continue
+			}
+			usage[image]++
+		}
+	}
+	return usage, nil
+}
+
+// getImagesToPull returns the images configured to always be pre-pulled, followed by up to maxImages of the most
+// frequently used images. Images used by the same number of DevWorkspaces are sorted by name.
+func getImagesToPull(pullerConfig *controller.ImagePullerConfig, usage map[string]int) []string {
+	var images []string
+	included := map[string]bool{}
+	for _, image := range pullerConfig.Images {
+		if image != "" && !included[image] {
+			images = append(images, image)
+			included[image] = true
+		}
+	}
+
+	var usedImages []string
+	for image := range usage {
+		if !included[image] {
+			usedImages = append(usedImages, image)
+		}
+	}
+	sort.Slice(usedImages, func(i, j int) bool {
+		if usage[usedImages[i]] != usage[usedImages[j]] {
+			return usage[usedImages[i]] > usage[usedImages[j]]
+		}
+		return usedImages[i] < usedImages[j]
+	})
+	maxImages := defaultImagePullerMaxImages
+	if pullerConfig.MaxImages != nil {
+		maxImages = *pullerConfig.MaxImages
+	}
+	if len(usedImages) > int(maxImages) {
+		usedImages = usedImages[:maxImages]
+	}
+	return append(images, usedImages...)
+}
+
+// getSpecDaemonSet returns a DaemonSet with a container for each image. The containers run a sleep binary copied
+// from sleepImage by an init container, and are scheduled on the same nodes as DevWorkspace pods.
+func getSpecDaemonSet(namespace, sleepImage string, images []string) *appsv1.DaemonSet {
+	workspaceConfig := config.GetGlobalConfig().Workspace
+	sleepVolumeMount := corev1.VolumeMount{
+		Name:      imagePullerSleepVolumeName,
+		MountPath: imagePullerSleepMountPath,
+	}
+	var containers []corev1.Container
+	for idx, image := range images {
+		containers = append(containers, corev1.Container{
+			Name:            fmt.Sprintf("image-%d", idx),
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{imagePullerSleepMountPath + "/sleep"},
+			Args:            []string{imagePullerSleepDuration},
+			Resources:       imagePullerResources,
+			VolumeMounts:    []corev1.VolumeMount{sleepVolumeMount},
+		})
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      imagePullerName,
+			Namespace: namespace,
+			Labels:    imagePullerLabels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: imagePullerLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: imagePullerLabels,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name:            "copy-sleep",
+							Image:           sleepImage,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Command:         []string{"cp", "/bin/sleep", imagePullerSleepMountPath + "/sleep"},
+							Resources:       imagePullerResources,
+							VolumeMounts:    []corev1.VolumeMount{sleepVolumeMount},
+						},
+					},
+					Containers: containers,
+					Volumes: []corev1.Volume{
+						{
+							Name: imagePullerSleepVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					NodeSelector:                  workspaceConfig.NodeSelector,
+					Tolerations:                   workspaceConfig.Tolerations,
+					AutomountServiceAccountToken:  pointer.Bool(false),
+					TerminationGracePeriodSeconds: pointer.Int64(1),
+				},
+			},
+		},
+	}
+}
+
+func getImagePullerInterval(pullerConfig *controller.ImagePullerConfig, log logr.Logger) time.Duration {
+	if pullerConfig == nil || pullerConfig.Interval == "" {
+		return defaultImagePullerInterval
+	}
+	interval, err := time.ParseDuration(pullerConfig.Interval)
+	if err != nil || interval <= 0 {
+		log.Error(err, "Invalid image puller interval, using default", "interval", pullerConfig.Interval, "default", defaultImagePullerInterval)
+		return defaultImagePullerInterval
+	}
+	return interval
+}
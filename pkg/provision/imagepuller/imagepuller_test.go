@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package imagepuller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const testNamespace = "devworkspace-controller"
+
+func getTestDeployment(name string, images ...string) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "user-namespace",
+			Labels:    map[string]string{constants.DevWorkspaceIDLabel: name},
+		},
+	}
+	deployment.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "project-clone", Image: "project-clone-image"}}
+	for _, image := range images {
+		deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{Name: image, Image: image})
+	}
+	return deployment
+}
+
+func getTestImagePuller(t *testing.T, objs ...client.Object) *ImagePuller {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	config.SetGlobalConfigForTesting(nil)
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &ImagePuller{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Log:              zap.New(),
+	}
+}
+
+func getDaemonSetImages(t *testing.T, p *ImagePuller) []string {
+	daemonSet := &appsv1.DaemonSet{}
+	err := p.NonCachingClient.Get(context.Background(), types.NamespacedName{Name: imagePullerName, Namespace: testNamespace}, daemonSet)
+	if k8sErrors.IsNotFound(err) {
+		return nil
+	}
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	var images []string
+	for _, container := range daemonSet.Spec.Template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+func TestGetImagesToPull(t *testing.T) {
+	usage := map[string]int{
+		"image-a": 1,
+		"image-b": 5,
+		"image-c": 3,
+		"image-d": 3,
+	}
+	images := getImagesToPull(&controller.ImagePullerConfig{MaxImages: pointer.Int32(3)}, usage)
+	assert.Equal(t, []string{"image-b", "image-c", "image-d"}, images, "Should pull most frequently used images")
+
+	images = getImagesToPull(&controller.ImagePullerConfig{
+		MaxImages: pointer.Int32(2),
+		Images:    []string{"always-pulled", "image-c"},
+	}, usage)
+	assert.Equal(t, []string{"always-pulled", "image-c", "image-b", "image-d"}, images, "Should always pull configured images")
+}
+
+func TestSyncCreatesAndUpdatesDaemonSet(t *testing.T) {
+	p := getTestImagePuller(t,
+		getTestDeployment("workspace-a", "udi"),
+		getTestDeployment("workspace-b", "udi", "postgres"))
+	pullerConfig := &controller.ImagePullerConfig{
+		Enable:    pointer.Bool(true),
+		Image:     "sleep-image",
+		MaxImages: pointer.Int32(2),
+	}
+
+	assert.NoError(t, p.sync(context.Background(), pullerConfig))
+	assert.Equal(t, []string{"project-clone-image", "udi"}, getDaemonSetImages(t, p))
+
+	daemonSet := &appsv1.DaemonSet{}
+	assert.NoError(t, p.NonCachingClient.Get(context.Background(), types.NamespacedName{Name: imagePullerName, Namespace: testNamespace}, daemonSet))
+	if assert.Len(t, daemonSet.Spec.Template.Spec.InitContainers, 1) {
+		assert.Equal(t, "sleep-image", daemonSet.Spec.Template.Spec.InitContainers[0].Image)
+	}
+	assert.Equal(t, []string{"/image-puller/sleep"}, daemonSet.Spec.Template.Spec.Containers[0].Command)
+
+	pullerConfig.MaxImages = pointer.Int32(3)
+	assert.NoError(t, p.sync(context.Background(), pullerConfig))
+	assert.Equal(t, []string{"project-clone-image", "udi", "postgres"}, getDaemonSetImages(t, p), "Should update DaemonSet when images change")
+}
+
+func TestSyncDeletesDaemonSetWhenDisabled(t *testing.T) {
+	p := getTestImagePuller(t, getTestDeployment("workspace-a", "udi"))
+	assert.NoError(t, p.sync(context.Background(), &controller.ImagePullerConfig{Enable: pointer.Bool(true), Image: "sleep-image"}))
+	assert.NotEmpty(t, getDaemonSetImages(t, p))
+
+	assert.NoError(t, p.sync(context.Background(), &controller.ImagePullerConfig{Enable: pointer.Bool(false), Image: "sleep-image"}))
+	assert.Nil(t, getDaemonSetImages(t, p), "Should delete DaemonSet")
+}
+
+func TestSyncRequiresImage(t *testing.T) {
+	p := getTestImagePuller(t, getTestDeployment("workspace-a", "udi"))
+	assert.NoError(t, p.sync(context.Background(), &controller.ImagePullerConfig{Enable: pointer.Bool(true)}))
+	assert.Nil(t, getDaemonSetImages(t, p), "Should not create DaemonSet without sleep image")
+}
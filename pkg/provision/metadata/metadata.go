@@ -16,13 +16,18 @@
 package metadata
 
 import (
+	"context"
 	"fmt"
 
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
 
@@ -75,6 +80,24 @@ func ProvisionWorkspaceMetadata(podAdditions *v1alpha1.PodAdditions, original, f
 	return nil
 }
 
+// GetFlattenedTemplate returns the flattened DevWorkspace template stored in the metadata configmap of a DevWorkspace,
+// i.e. the template as of the last time the DevWorkspace was started. Returns nil if the configmap does not exist.
+func GetFlattenedTemplate(ctx context.Context, c client.Client, workspace *dw.DevWorkspace) (*dw.DevWorkspaceTemplateSpec, error) {
+	cm := &corev1.ConfigMap{}
+	namespacedName := types.NamespacedName{Name: common.MetadataConfigMapName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+	if err := c.Get(ctx, namespacedName, cm); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	flattened := &dw.DevWorkspaceTemplateSpec{}
+	if err := yaml.Unmarshal([]byte(cm.Data[flattenedYamlFilename]), flattened); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal flattened DevWorkspace yaml: %w", err)
+	}
+	return flattened, nil
+}
+
 func getSpecMetadataConfigMap(original, flattened *common.DevWorkspaceWithConfig) (*corev1.ConfigMap, error) {
 	originalYaml, err := yaml.Marshal(original.Spec.Template)
 	if err != nil {
@@ -55,8 +55,11 @@ func (p *AsyncStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1.PodAdd
 			Err:     err,
 		}
 	}
+	if err := checkVolumeAttributesNotUsed(&workspace.Spec.Template, constants.AsyncStorageClassType); err != nil {
+		return err
+	}
 
-	numWorkspaces, _, err := p.getAsyncWorkspaceCount(workspace.Namespace, clusterAPI)
+	numWorkspaces, _, err := p.getAsyncWorkspaceCount(workspace.Namespace, workspace.Config.Workspace.DefaultStorageType, clusterAPI)
 	if err != nil {
 		return err
 	}
@@ -169,7 +172,14 @@ func (p *AsyncStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1.PodAdd
 	}
 
 	sshSecretVolume := asyncstorage.GetVolumeFromSecret(secret)
-	asyncSidecar := asyncstorage.GetAsyncSidecar(workspace.Status.DevWorkspaceId, sshSecretVolume.Name, volumes)
+	syncInterval, err := getAsyncSyncInterval(workspace)
+	if err != nil {
+		return &dwerrors.FailError{
+			Message: "Invalid configuration for async storage",
+			Err:     err,
+		}
+	}
+	asyncSidecar := asyncstorage.GetAsyncSidecar(workspace.Status.DevWorkspaceId, sshSecretVolume.Name, volumes, syncInterval)
 	podAdditions.Containers = append(podAdditions.Containers, *asyncSidecar)
 	podAdditions.Volumes = append(podAdditions.Volumes, *sshSecretVolume)
 
@@ -188,7 +198,7 @@ func (p *AsyncStorageProvisioner) CleanupWorkspaceStorage(workspace *common.DevW
 	}
 
 	// Check if another workspace is currently using the async server
-	numWorkspaces, totalWorkspaces, err := p.getAsyncWorkspaceCount(workspace.Namespace, clusterAPI)
+	numWorkspaces, totalWorkspaces, err := p.getAsyncWorkspaceCount(workspace.Namespace, workspace.Config.Workspace.DefaultStorageType, clusterAPI)
 	if err != nil {
 		return err
 	}
@@ -286,15 +296,15 @@ func (*AsyncStorageProvisioner) addVolumesForAsyncStorage(podAdditions *v1alpha1
 
 // getAsyncWorkspaceCount returns whether the async storage provider can support starting a workspace.
 // Due to how cleanup for the async storage PVC is implemented, only one workspace that uses the async storage
-// type can be running at a time.
-func (*AsyncStorageProvisioner) getAsyncWorkspaceCount(namespace string, api sync.ClusterAPI) (started, total int, err error) {
+// type can be running at a time. Workspaces that do not set the storage-type attribute are assumed to use defaultStorageType.
+func (*AsyncStorageProvisioner) getAsyncWorkspaceCount(namespace, defaultStorageType string, api sync.ClusterAPI) (started, total int, err error) {
 	workspaces := &dw.DevWorkspaceList{}
 	err = api.Client.List(api.Ctx, workspaces, &client.ListOptions{Namespace: namespace})
 	if err != nil {
 		return 0, 0, err
 	}
 	for _, workspace := range workspaces.Items {
-		storageClass := workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil)
+		storageClass := getStorageTypeWithDefault(&workspace, defaultStorageType)
 		if storageClass == constants.AsyncStorageClassType {
 			total++
 			if workspace.Spec.Started {
@@ -306,6 +316,22 @@ func (*AsyncStorageProvisioner) getAsyncWorkspaceCount(namespace string, api syn
 	return started, total, nil
 }
 
+// getAsyncSyncInterval returns the interval at which the async storage sidecar should synchronize data, as set
+// in the workspace's configuration. Returns 0 if no interval is configured.
+func getAsyncSyncInterval(workspace *common.DevWorkspaceWithConfig) (time.Duration, error) {
+	if workspace.Config.Workspace.AsyncStorageSyncInterval == "" {
+		return 0, nil
+	}
+	syncInterval, err := time.ParseDuration(workspace.Config.Workspace.AsyncStorageSyncInterval)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse async storage sync interval: %w", err)
+	}
+	if syncInterval < time.Second {
+		return 0, fmt.Errorf("async storage sync interval must be at least one second, got %s", workspace.Config.Workspace.AsyncStorageSyncInterval)
+	}
+	return syncInterval, nil
+}
+
 func checkConfigured() error {
 	if images.GetAsyncStorageServerImage() == "" {
 		return fmt.Errorf("asynchronous storage server image is not configured")
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAsyncSyncInterval(t *testing.T) {
+	tests := []struct {
+		name             string
+		syncInterval     string
+		expectedInterval time.Duration
+		errRegexp        string
+	}{
+		{
+			name:             "Uses sidecar default when not configured",
+			expectedInterval: 0,
+		},
+		{
+			name:             "Parses configured interval",
+			syncInterval:     "5m",
+			expectedInterval: 5 * time.Minute,
+		},
+		{
+			name:         "Returns error for invalid interval",
+			syncInterval: "five minutes",
+			errRegexp:    "failed to parse async storage sync interval",
+		},
+		{
+			name:         "Returns error for interval shorter than one second",
+			syncInterval: "100ms",
+			errRegexp:    "async storage sync interval must be at least one second",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getDevWorkspaceWithConfig(&dw.DevWorkspace{})
+			workspace.Config = workspace.Config.DeepCopy()
+			workspace.Config.Workspace.AsyncStorageSyncInterval = tt.syncInterval
+			interval, err := getAsyncSyncInterval(workspace)
+			if tt.errRegexp != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+				}
+				return
+			}
+			if assert.NoError(t, err, "Should not return error") {
+				assert.Equal(t, tt.expectedInterval, interval)
+			}
+		})
+	}
+}
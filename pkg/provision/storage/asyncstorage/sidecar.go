@@ -18,6 +18,7 @@ package asyncstorage
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/devfile/devworkspace-operator/internal/images"
 	corev1 "k8s.io/api/core/v1"
@@ -27,8 +28,12 @@ import (
 // GetAsyncSidecar gets the definition for the async storage sidecar. Within this sidecar, all provided volumes
 // are mounted to `/volume.Name`, and the sshVolume is mounted to /etc/ssh/private as read-only.
 //
+// If syncInterval is non-zero, the sidecar is configured to synchronize data to the async storage server at that
+// interval; otherwise, the default interval of the sidecar image is used. Data is always synchronized when the
+// workspace is stopped via the sidecar's preStop hook.
+//
 // Note: in the current implementation, the image used for the async sidecar only syncs from ${CHE_PROJECTS_ROOT}
-func GetAsyncSidecar(devworkspaceID, sshVolumeName string, volumes []corev1.Volume) *corev1.Container {
+func GetAsyncSidecar(devworkspaceID, sshVolumeName string, volumes []corev1.Volume, syncInterval time.Duration) *corev1.Container {
 	volumeMounts := []corev1.VolumeMount{
 		{
 			Name:      sshVolumeName,
@@ -84,5 +89,11 @@ func GetAsyncSidecar(devworkspaceID, sshVolumeName string, volumes []corev1.Volu
 			},
 		},
 	}
+	if syncInterval > 0 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "SYNC_INTERVAL",
+			Value: strconv.Itoa(int(syncInterval.Seconds())),
+		})
+	}
 	return container
 }
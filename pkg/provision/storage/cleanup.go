@@ -87,7 +87,7 @@ func runCommonPVCCleanupJob(workspace *common.DevWorkspaceWithConfig, clusterAPI
 	}
 
 	jobLabels := k8sclient.MatchingLabels{"job-name": common.PVCCleanupJobName(workspace.Status.DevWorkspaceId)}
-	msg, err := status.CheckPodsState(workspace.Status.DevWorkspaceId, clusterJob.Namespace, jobLabels, workspace.Config.Workspace.IgnoredUnrecoverableEvents, clusterAPI)
+	msg, err := status.CheckPodsState(common.ServiceName(workspace.Status.DevWorkspaceId, workspace.Annotations), clusterJob.Namespace, jobLabels, workspace.Config.Workspace.IgnoredUnrecoverableEvents, clusterAPI)
 	if err != nil {
 		return &dwerrors.FailError{
 			Message: "Error while checking cleanup job pods state",
@@ -0,0 +1,251 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	cloneSourceMountPath = "/source-storage"
+	cloneTargetMountPath = "/target-storage"
+	cloneContainerName   = "clone-storage"
+
+	// cloneStorageScript copies a DevWorkspace's data directory into another DevWorkspace's data directory. A marker
+	// file is created once the copy completes, so that data is not overwritten if the job is ever run again.
+	cloneStorageScript = `set -e
+target="` + cloneTargetMountPath + `/${TARGET_PATH}"
+if [ -f "${target}/` + snapshotRestoredMarker + `" ]; then
+  echo "Storage already copied"
+  exit 0
+fi
+mkdir -p "${target}"
+if [ -d "` + cloneSourceMountPath + `/${SOURCE_PATH}" ]; then
+  cp -a "` + cloneSourceMountPath + `/${SOURCE_PATH}/." "${target}/"
+fi
+touch "${target}/` + snapshotRestoredMarker + `"`
+)
+
+// copyStorageFromCloneSource populates a DevWorkspace's storage with the data of the DevWorkspace named by the
+// clone-storage-from attribute, by running a job that copies the source DevWorkspace's data directory. The source
+// DevWorkspace must be stopped while its data is copied. No action is taken if the attribute is not set or if the
+// data has already been copied.
+func copyStorageFromCloneSource(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	if !workspace.Spec.Template.Attributes.Exists(constants.CloneStorageFromAttribute) {
+		return nil
+	}
+	var attrErr error
+	sourceName := workspace.Spec.Template.Attributes.GetString(constants.CloneStorageFromAttribute, &attrErr)
+	if attrErr != nil {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("Failed to read attribute %s", constants.CloneStorageFromAttribute),
+			Err:     attrErr,
+		}
+	}
+	if sourceName == "" {
+		return nil
+	}
+
+	jobName := common.CloneStorageJobName(workspace.Status.DevWorkspaceId)
+	clusterJob := &batchv1.Job{}
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: jobName, Namespace: workspace.Namespace}, clusterJob)
+	switch {
+	case err == nil:
+		return checkCloneStorageJob(clusterJob, sourceName)
+	case !k8sErrors.IsNotFound(err):
+		return err
+	}
+
+	source := &dw.DevWorkspace{}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: sourceName, Namespace: workspace.Namespace}, source); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Cannot copy storage from DevWorkspace %s: DevWorkspace does not exist", sourceName),
+			}
+		}
+		return err
+	}
+	if source.Status.DevWorkspaceId == "" || source.Spec.Started || source.Status.Phase != dw.DevWorkspaceStatusStopped {
+		return &dwerrors.RetryError{
+			Message:      fmt.Sprintf("Waiting for DevWorkspace %s to be stopped to copy its storage", sourceName),
+			RequeueAfter: 5 * time.Second,
+		}
+	}
+
+	// The source DevWorkspace is assumed to use the same configuration as the DevWorkspace it was cloned into
+	sourceWithConfig := &common.DevWorkspaceWithConfig{DevWorkspace: source, Config: workspace.Config}
+	sourcePVC, sourcePath, err := GetWorkspaceDataLocation(sourceWithConfig, clusterAPI)
+	if err != nil {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot copy storage from DevWorkspace %s", sourceName),
+			Err:     err,
+		}
+	}
+	targetPVC, targetPath, err := GetWorkspaceDataLocation(workspace, clusterAPI)
+	if err != nil {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot copy storage from DevWorkspace %s", sourceName),
+			Err:     err,
+		}
+	}
+
+	specJob, err := getSpecCloneStorageJob(workspace, sourcePVC, sourcePath, targetPVC, targetPath, clusterAPI)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specJob, clusterAPI.Scheme); err != nil {
+		return err
+	}
+	clusterObj, err := sync.SyncObjectWithCluster(specJob, clusterAPI)
+	if err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+	return checkCloneStorageJob(clusterObj.(*batchv1.Job), sourceName)
+}
+
+func checkCloneStorageJob(job *batchv1.Job, sourceName string) error {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return nil
+		case batchv1.JobFailed:
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Failed to copy storage from DevWorkspace %s: see logs for job %q for details", sourceName, job.Name),
+			}
+		}
+	}
+	return &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Copying storage from DevWorkspace %s", sourceName),
+		RequeueAfter: 5 * time.Second,
+	}
+}
+
+// getSpecCloneStorageJob returns a job that copies data from sourcePath in the source PVC to targetPath in the target
+// PVC. If both paths are in the same PVC, it is only mounted once.
+func getSpecCloneStorageJob(workspace *common.DevWorkspaceWithConfig, sourcePVC, sourcePath, targetPVC, targetPath string, clusterAPI sync.ClusterAPI) (*batchv1.Job, error) {
+	jobLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:      workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:    workspace.Name,
+		constants.DevWorkspaceCreatorLabel: workspace.Labels[constants.DevWorkspaceCreatorLabel],
+	}
+	if restrictedAccess, needsRestrictedAccess := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; needsRestrictedAccess {
+		jobLabels[constants.DevWorkspaceRestrictedAccessAnnotation] = restrictedAccess
+	}
+
+	var securityContext *corev1.PodSecurityContext
+	if infrastructure.IsOpenShift() {
+		securityContext = &corev1.PodSecurityContext{}
+	} else {
+		securityContext = workspace.Config.Workspace.PodSecurityContext
+	}
+
+	getPVCVolume := func(pvcName string) corev1.Volume {
+		return corev1.Volume{
+			Name: pvcName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvcName,
+				},
+			},
+		}
+	}
+	volumes := []corev1.Volume{getPVCVolume(sourcePVC)}
+	volumeMounts := []corev1.VolumeMount{
+		{Name: sourcePVC, MountPath: cloneSourceMountPath},
+		{Name: targetPVC, MountPath: cloneTargetMountPath},
+	}
+	if targetPVC != sourcePVC {
+		volumes = append(volumes, getPVCVolume(targetPVC))
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.CloneStorageJobName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			Completions:  &cleanupJobCompletions,
+			BackoffLimit: &snapshotJobBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:   "Never",
+					SecurityContext: securityContext,
+					Volumes:         volumes,
+					Containers: []corev1.Container{
+						{
+							Name:    cloneContainerName,
+							Image:   images.GetPVCCleanupJobImage(),
+							Command: []string{"/bin/sh"},
+							Args:    []string{"-c", cloneStorageScript},
+							Env: []corev1.EnvVar{
+								{Name: "SOURCE_PATH", Value: path.Clean(sourcePath)},
+								{Name: "TARGET_PATH", Value: path.Clean(targetPath)},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: pvcCleanupPodMemoryRequest,
+									corev1.ResourceCPU:    pvcCleanupPodCPURequest,
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: pvcCleanupPodMemoryLimit,
+									corev1.ResourceCPU:    pvcCleanupPodCPULimit,
+								},
+							},
+							VolumeMounts: volumeMounts,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	if len(podTolerations) > 0 {
+		job.Spec.Template.Spec.Tolerations = podTolerations
+	}
+	if len(nodeSelector) > 0 {
+		job.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+	return job, nil
+}
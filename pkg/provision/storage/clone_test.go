@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+func getCloneTestWorkspace(storageType string) *common.DevWorkspaceWithConfig {
+	workspace := getSnapshotTestWorkspace(storageType)
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.
+		PutString(constants.DevWorkspaceStorageTypeAttribute, storageType).
+		PutString(constants.CloneStorageFromAttribute, "source-workspace")
+	return workspace
+}
+
+func getCloneSourceWorkspace(storageType string, phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+	source := testutil.NewDevWorkspace("source-workspace", phase)
+	source.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.DevWorkspaceStorageTypeAttribute, storageType)
+	return source
+}
+
+func TestCopyStorageFromCloneSource(t *testing.T) {
+	tests := []struct {
+		name           string
+		storageType    string
+		expectedSource string
+		expectedTarget string
+		expectedPVCs   []string
+	}{
+		{
+			name:           "Copies subpath within common PVC",
+			storageType:    constants.CommonStorageClassType,
+			expectedSource: "source-workspaceid",
+			expectedTarget: "test-workspaceid",
+			expectedPVCs:   []string{"claim-devworkspace"},
+		},
+		{
+			name:           "Copies between per-workspace PVCs",
+			storageType:    constants.PerWorkspaceStorageClassType,
+			expectedSource: ".",
+			expectedTarget: ".",
+			expectedPVCs:   []string{common.PerWorkspacePVCName("source-workspaceid", nil), common.PerWorkspacePVCName("test-workspaceid", nil)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getCloneTestWorkspace(tt.storageType)
+			clusterAPI := getSnapshotTestClusterAPI(workspace.DevWorkspace, getCloneSourceWorkspace(tt.storageType, dw.DevWorkspaceStatusStopped))
+
+			err := copyStorageFromCloneSource(workspace, clusterAPI)
+			if !assert.IsType(t, &dwerrors.RetryError{}, err, "Should wait for copy job to complete") {
+				return
+			}
+			job := &batchv1.Job{}
+			jobNN := types.NamespacedName{Name: common.CloneStorageJobName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+			if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, jobNN, job), "Copy job should be created") {
+				return
+			}
+			container := job.Spec.Template.Spec.Containers[0]
+			assert.Contains(t, container.Env, corev1.EnvVar{Name: "SOURCE_PATH", Value: tt.expectedSource})
+			assert.Contains(t, container.Env, corev1.EnvVar{Name: "TARGET_PATH", Value: tt.expectedTarget})
+			var pvcs []string
+			for _, volume := range job.Spec.Template.Spec.Volumes {
+				pvcs = append(pvcs, volume.PersistentVolumeClaim.ClaimName)
+			}
+			assert.Equal(t, tt.expectedPVCs, pvcs)
+			assert.True(t, metav1.IsControlledBy(job, workspace.DevWorkspace), "Copy job should be owned by workspace")
+
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			if !assert.NoError(t, clusterAPI.Client.Status().Update(clusterAPI.Ctx, job)) {
+				return
+			}
+			source := getCloneSourceWorkspace(tt.storageType, dw.DevWorkspaceStatusStopped)
+			assert.NoError(t, clusterAPI.Client.Delete(clusterAPI.Ctx, source))
+			assert.NoError(t, copyStorageFromCloneSource(workspace, clusterAPI), "Should not require source once storage is copied")
+		})
+	}
+}
+
+func TestCopyStorageFromCloneSourceErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      *dw.DevWorkspace
+		expectedErr interface{}
+	}{
+		{
+			name:        "Waits for source to be stopped",
+			source:      getCloneSourceWorkspace(constants.CommonStorageClassType, dw.DevWorkspaceStatusRunning),
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name:        "Fails when source does not exist",
+			expectedErr: &dwerrors.FailError{},
+		},
+		{
+			name:        "Fails when source does not use persistent storage",
+			source:      getCloneSourceWorkspace(constants.EphemeralStorageClassType, dw.DevWorkspaceStatusStopped),
+			expectedErr: &dwerrors.FailError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getCloneTestWorkspace(constants.CommonStorageClassType)
+			objs := []client.Object{workspace.DevWorkspace}
+			if tt.source != nil {
+				objs = append(objs, tt.source)
+			}
+			err := copyStorageFromCloneSource(workspace, getSnapshotTestClusterAPI(objs...))
+			assert.IsType(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestCopyStorageFromCloneSourceIgnoresWorkspacesWithoutAttribute(t *testing.T) {
+	workspace := getCloneTestWorkspace(constants.CommonStorageClassType)
+	workspace.Spec.Template.Attributes = attributes.Attributes{}
+	assert.NoError(t, copyStorageFromCloneSource(workspace, getSnapshotTestClusterAPI()))
+}
@@ -50,6 +50,11 @@ func (p *CommonStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1.PodAd
 		return err
 	}
 
+	allVolumeOptions, err := provisionVolumeOptions(workspace, workspace.Config.Workspace.StorageClassName, clusterAPI)
+	if err != nil {
+		return err
+	}
+
 	// If persistent storage is not needed, we're done
 	if !p.NeedsStorage(&workspace.Spec.Template) {
 		return nil
@@ -73,33 +78,60 @@ func (p *CommonStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1.PodAd
 		}
 	}
 
+	var resizeWarning error
 	if !usingAlternatePVC {
 		commonPVC, err := syncCommonPVC(workspace.Namespace, workspace.Config, clusterAPI)
 		if err != nil {
 			return err
 		}
 		pvcName = commonPVC.Name
+
+		if *workspace.Config.Workspace.AutoResizeCommonPVC {
+			if err := resizeCommonPVCIfNeeded(commonPVC, workspace, clusterAPI); err != nil {
+				if _, ok := err.(*dwerrors.WarningError); !ok {
+					return err
+				}
+				resizeWarning = err
+			}
+		}
 	}
 
-	if err := p.rewriteContainerVolumeMounts(workspace.Status.DevWorkspaceId, pvcName, podAdditions, &workspace.Spec.Template); err != nil {
+	restoreSnapshot, err := getRestoreSnapshot(workspace, clusterAPI)
+	if err != nil {
+		return err
+	}
+	if err := restoreFromSnapshot(restoreSnapshot, workspace, clusterAPI); err != nil {
+		return err
+	}
+	if err := copyStorageFromCloneSource(workspace, clusterAPI); err != nil {
+		return err
+	}
+
+	if err := p.rewriteContainerVolumeMounts(workspace.Status.DevWorkspaceId, pvcName, allVolumeOptions, podAdditions, &workspace.Spec.Template); err != nil {
 		return &dwerrors.FailError{
 			Err:     err,
 			Message: "Could not rewrite container volume mounts",
 		}
 	}
 
-	return nil
+	return resizeWarning
 }
 
 func (p *CommonStorageProvisioner) CleanupWorkspaceStorage(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
-	totalWorkspaces, err := getSharedPVCWorkspaceCount(workspace.Namespace, clusterAPI)
+	totalWorkspaces, err := getSharedPVCWorkspaceCount(workspace.Namespace, workspace.Config.Workspace.DefaultStorageType, clusterAPI)
+	if err != nil {
+		return err
+	}
+
+	// Workspaces in the trash may still have data on the common PVC
+	trashedWorkspaceIDs, err := getTrashedWorkspaceIDs(workspace.Namespace, clusterAPI)
 	if err != nil {
 		return err
 	}
 
 	// If the number of common + async workspaces that exist (started or stopped) is zero,
 	// delete common PVC instead of running cleanup job
-	if totalWorkspaces > 0 {
+	if totalWorkspaces > 0 || len(trashedWorkspaceIDs) > 0 {
 		return runCommonPVCCleanupJob(workspace, clusterAPI)
 	} else {
 		sharedPVC := &corev1.PersistentVolumeClaim{}
@@ -126,7 +158,7 @@ func (p *CommonStorageProvisioner) CleanupWorkspaceStorage(workspace *common.Dev
 // (i.e. all volume mounts are subpaths into a common PVC used by all workspaces in the namespace).
 //
 // Also adds appropriate k8s Volumes to PodAdditions to accomodate the rewritten VolumeMounts.
-func (p *CommonStorageProvisioner) rewriteContainerVolumeMounts(workspaceId, pvcName string, podAdditions *v1alpha1.PodAdditions, workspace *dw.DevWorkspaceTemplateSpec) error {
+func (p *CommonStorageProvisioner) rewriteContainerVolumeMounts(workspaceId, pvcName string, allVolumeOptions map[string]volumeOptions, podAdditions *v1alpha1.PodAdditions, workspace *dw.DevWorkspaceTemplateSpec) error {
 	devfileVolumes := map[string]dw.VolumeComponent{}
 
 	// Construct map of volume name -> volume Component
@@ -175,8 +207,9 @@ func (p *CommonStorageProvisioner) rewriteContainerVolumeMounts(workspaceId, pvc
 					return fmt.Errorf("container '%s' references undefined volume '%s'", container.Name, vm.Name)
 				}
 				if !isEphemeral(&volume) {
-					containers[cIdx].VolumeMounts[vmIdx].SubPath = fmt.Sprintf("%s/%s", workspaceId, vm.Name)
-					containers[cIdx].VolumeMounts[vmIdx].Name = pvcName
+					mountName, subPath := getVolumeMountSubPath(workspaceId, vm.Name, pvcName, workspaceId, allVolumeOptions)
+					containers[cIdx].VolumeMounts[vmIdx].SubPath = subPath
+					containers[cIdx].VolumeMounts[vmIdx].Name = mountName
 				}
 			}
 		}
@@ -197,6 +230,7 @@ func (p *CommonStorageProvisioner) rewriteContainerVolumeMounts(workspaceId, pvc
 			},
 		},
 	})
+	podAdditions.Volumes = append(podAdditions.Volumes, getDedicatedPVCVolumes(workspaceId, allVolumeOptions)...)
 
 	return nil
 }
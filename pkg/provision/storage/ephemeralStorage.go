@@ -21,6 +21,7 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/library/container"
 )
 
@@ -37,6 +38,9 @@ func (e EphemeralStorageProvisioner) NeedsStorage(_ *dw.DevWorkspaceTemplateSpec
 }
 
 func (e EphemeralStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1.PodAdditions, workspace *common.DevWorkspaceWithConfig, _ sync.ClusterAPI) error {
+	if err := checkVolumeAttributesNotUsed(&workspace.Spec.Template, constants.EphemeralStorageClassType); err != nil {
+		return err
+	}
 	persistent, ephemeral, projects := getWorkspaceVolumes(workspace)
 	if _, err := addEphemeralVolumesToPodAdditions(podAdditions, persistent); err != nil {
 		return err
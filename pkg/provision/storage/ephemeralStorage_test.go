@@ -19,6 +19,10 @@ import (
 	"testing"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +53,21 @@ func TestRewriteContainerVolumeMountsForEphemeralStorageClass(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProvisionerUsesDefaultStorageTypeFromConfig(t *testing.T) {
+	workspace := getDevWorkspaceWithConfig(&dw.DevWorkspace{})
+	workspace.Config = config.GetConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			DefaultStorageType: constants.EphemeralStorageClassType,
+		},
+	})
+
+	provisioner, err := GetProvisioner(workspace)
+	assert.NoError(t, err, "Should not return error")
+	assert.IsType(t, &EphemeralStorageProvisioner{}, provisioner, "Should use default storage type from config")
+
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.DevWorkspaceStorageTypeAttribute, constants.PerWorkspaceStorageClassType)
+	provisioner, err = GetProvisioner(workspace)
+	assert.NoError(t, err, "Should not return error")
+	assert.IsType(t, &PerWorkspaceStorageProvisioner{}, provisioner, "Storage type attribute should override default from config")
+}
@@ -0,0 +1,407 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	defaultGarbageCollectionInterval = 24 * time.Hour
+	// garbageCollectionJobDeadline is the maximum time a garbage collection job can run for. This ensures jobs
+	// that cannot be scheduled (e.g. as the PVC is mounted on another node) do not block garbage collection.
+	garbageCollectionJobDeadline = int64(600)
+	// garbageCollectionScript removes directories on the common PVC for DevWorkspace IDs that are not listed in
+	// $ACTIVE_WORKSPACES. Directories modified within the last hour are skipped to avoid racing with DevWorkspaces
+	// created after the job's spec was computed. The number of bytes reclaimed and directories removed are written
+	// to the container's termination message to be read by the controller.
+	garbageCollectionScript = `reclaimed=0
+removed=0
+now=$(date +%s)
+for dir in ` + pvcClaimMountPath + `workspace*; do
+  [ -d "$dir" ] || continue
+  id=$(basename "$dir")
+  case " $ACTIVE_WORKSPACES " in *" $id "*) continue ;; esac
+  [ $((now - $(stat -c %Y "$dir"))) -lt 3600 ] && continue
+  size=$(du -sk "$dir" | cut -f1)
+  if rm -rf "$dir"; then
+    reclaimed=$((reclaimed + size * 1024))
+    removed=$((removed + 1))
+  fi
+done
+echo "$reclaimed $removed" > /dev/termination-log`
+)
+
+var (
+	gcReclaimedBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "storage_gc_reclaimed_bytes_total",
+			Help:      "Total bytes reclaimed by removing data for deleted DevWorkspaces from common PVCs",
+		},
+	)
+	gcRemovedDirectories = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "storage_gc_removed_directories_total",
+			Help:      "Total number of directories for deleted DevWorkspaces removed from common PVCs",
+		},
+	)
+	gcJobFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "devworkspace",
+			Name:      "storage_gc_failed_jobs_total",
+			Help:      "Total number of failed common PVC garbage collection jobs",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(gcReclaimedBytes, gcRemovedDirectories, gcJobFailures)
+}
+
+// GarbageCollector periodically removes data left on common PVCs by DevWorkspaces that no longer exist. It is
+// intended to be added to the controller manager, and is configured through the global DevWorkspaceOperatorConfig's
+// workspace.commonPVCGarbageCollection field.
+type GarbageCollector struct {
+	Client           k8sclient.Client
+	NonCachingClient k8sclient.Client
+	Scheme           *runtime.Scheme
+	Log              logr.Logger
+}
+
+// NeedLeaderElection ensures garbage collection is only run by the manager that holds the leader lease.
+func (gc *GarbageCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs garbage collection periodically until ctx is cancelled.
+func (gc *GarbageCollector) Start(ctx context.Context) error {
+	for {
+		gcConfig := config.GetGlobalConfig().Workspace.CommonPVCGarbageCollection
+		interval := getGarbageCollectionInterval(gcConfig, gc.Log)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+		gcConfig = config.GetGlobalConfig().Workspace.CommonPVCGarbageCollection
+		if gcConfig == nil || !pointer.BoolDeref(gcConfig.Enable, false) {
+			continue
+		}
+		if err := gc.collect(ctx); err != nil {
+			gc.Log.Error(err, "Failed to remove orphaned DevWorkspace data from common PVCs")
+		}
+	}
+}
+
+func getGarbageCollectionInterval(gcConfig *controller.CommonPVCGarbageCollectionConfig, log logr.Logger) time.Duration {
+	if gcConfig == nil || gcConfig.Interval == "" {
+		return defaultGarbageCollectionInterval
+	}
+	interval, err := time.ParseDuration(gcConfig.Interval)
+	if err != nil || interval <= 0 {
+		log.Error(err, "Invalid common PVC garbage collection interval, using default", "interval", gcConfig.Interval, "default", defaultGarbageCollectionInterval)
+		return defaultGarbageCollectionInterval
+	}
+	return interval
+}
+
+// namespaceWorkspaces stores the IDs of DevWorkspaces in a namespace, and whether any DevWorkspace in the namespace
+// may currently be using the common PVC.
+type namespaceWorkspaces struct {
+	ids   []string
+	inUse bool
+}
+
+func (gc *GarbageCollector) collect(ctx context.Context) error {
+	workspaceList := &dw.DevWorkspaceList{}
+	if err := gc.Client.List(ctx, workspaceList); err != nil {
+		return err
+	}
+	namespaces := map[string]*namespaceWorkspaces{}
+	for _, workspace := range workspaceList.Items {
+		if _, ok := namespaces[workspace.Namespace]; !ok {
+			namespaces[workspace.Namespace] = &namespaceWorkspaces{}
+		}
+		nsWorkspaces := namespaces[workspace.Namespace]
+		if workspace.Status.DevWorkspaceId != "" {
+			nsWorkspaces.ids = append(nsWorkspaces.ids, workspace.Status.DevWorkspaceId)
+		}
+		isStopped := workspace.Status.Phase == dw.DevWorkspaceStatusStopped || workspace.Status.Phase == dw.DevWorkspaceStatusFailed
+		if workspace.Spec.Started || !isStopped || workspace.DeletionTimestamp != nil {
+			nsWorkspaces.inUse = true
+		}
+	}
+
+	// Data for workspaces in the trash is kept until the trash ConfigMap expires
+	trashList := &corev1.ConfigMapList{}
+	if err := gc.Client.List(ctx, trashList, k8sclient.MatchingLabels{constants.DevWorkspaceTrashLabel: "true"}); err != nil {
+		return err
+	}
+	for _, trashCM := range trashList.Items {
+		if _, ok := namespaces[trashCM.Namespace]; !ok {
+			namespaces[trashCM.Namespace] = &namespaceWorkspaces{}
+		}
+		if id := trashCM.Labels[constants.DevWorkspaceIDLabel]; id != "" {
+			namespaces[trashCM.Namespace].ids = append(namespaces[trashCM.Namespace].ids, id)
+		}
+	}
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := gc.Client.List(ctx, pvcList); err != nil {
+		return err
+	}
+	pvcName := config.GetGlobalConfig().Workspace.PVCName
+	for _, pvc := range pvcList.Items {
+		if pvc.Name != pvcName && pvc.Name != constants.CheCommonPVCName {
+			continue
+		}
+		nsWorkspaces := namespaces[pvc.Namespace]
+		if nsWorkspaces == nil {
+			nsWorkspaces = &namespaceWorkspaces{}
+		}
+		if err := gc.collectForPVC(ctx, &pvc, nsWorkspaces); err != nil {
+			gc.Log.Error(err, "Failed to remove orphaned DevWorkspace data from PVC", "namespace", pvc.Namespace, "pvc", pvc.Name)
+		}
+	}
+	return nil
+}
+
+// collectForPVC records the results of previous garbage collection jobs for a PVC and, if no job is currently
+// running and the PVC is not in use by DevWorkspaces, starts a new job.
+func (gc *GarbageCollector) collectForPVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim, nsWorkspaces *namespaceWorkspaces) error {
+	log := gc.Log.WithValues("namespace", pvc.Namespace, "pvc", pvc.Name)
+	jobList := &batchv1.JobList{}
+	listOpts := []k8sclient.ListOption{
+		k8sclient.InNamespace(pvc.Namespace),
+		k8sclient.MatchingLabels{constants.DevWorkspaceStorageGCLabel: "true"},
+	}
+	if err := gc.NonCachingClient.List(ctx, jobList, listOpts...); err != nil {
+		return err
+	}
+	jobRunning := false
+	for _, job := range jobList.Items {
+		if !isJobFinished(&job) {
+			jobRunning = true
+			continue
+		}
+		if err := gc.recordJobResult(ctx, &job, log); err != nil {
+			return err
+		}
+		err := gc.NonCachingClient.Delete(ctx, &job, k8sclient.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if jobRunning || nsWorkspaces.inUse {
+		return nil
+	}
+
+	clusterAPI := sync.ClusterAPI{
+		Client:           gc.Client,
+		NonCachingClient: gc.NonCachingClient,
+		Scheme:           gc.Scheme,
+		Logger:           log,
+		Ctx:              ctx,
+	}
+	job, err := getSpecGarbageCollectionJob(pvc, nsWorkspaces.ids, clusterAPI)
+	if err != nil {
+		return err
+	}
+	log.Info("Starting job to remove orphaned DevWorkspace data from PVC")
+	return gc.NonCachingClient.Create(ctx, job)
+}
+
+func (gc *GarbageCollector) recordJobResult(ctx context.Context, job *batchv1.Job, log logr.Logger) error {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			log.Info("Common PVC garbage collection job failed", "job", job.Name, "reason", condition.Reason, "message", condition.Message)
+			gcJobFailures.Inc()
+			return nil
+		}
+	}
+
+	podList := &corev1.PodList{}
+	listOpts := []k8sclient.ListOption{
+		k8sclient.InNamespace(job.Namespace),
+		k8sclient.MatchingLabels{"job-name": job.Name},
+	}
+	if err := gc.NonCachingClient.List(ctx, podList, listOpts...); err != nil {
+		return err
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.State.Terminated == nil {
+				continue
+			}
+			reclaimedBytes, removedDirs, err := parseGarbageCollectionResult(containerStatus.State.Terminated.Message)
+			if err != nil {
+				log.Error(err, "Failed to read result of common PVC garbage collection job", "job", job.Name)
+				return nil
+			}
+			log.Info("Removed orphaned DevWorkspace data from PVC", "directories", removedDirs, "reclaimed-bytes", reclaimedBytes)
+			gcReclaimedBytes.Add(float64(reclaimedBytes))
+			gcRemovedDirectories.Add(float64(removedDirs))
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseGarbageCollectionResult parses the termination message written by garbageCollectionScript.
+func parseGarbageCollectionResult(message string) (reclaimedBytes, removedDirs int64, err error) {
+	fields := strings.Fields(message)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected termination message %q", message)
+	}
+	if reclaimedBytes, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if removedDirs, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return reclaimedBytes, removedDirs, nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func getSpecGarbageCollectionJob(pvc *corev1.PersistentVolumeClaim, activeWorkspaceIds []string, clusterAPI sync.ClusterAPI) (*batchv1.Job, error) {
+	jobLabels := map[string]string{
+		constants.DevWorkspaceStorageGCLabel: "true",
+	}
+
+	var securityContext *corev1.PodSecurityContext
+	if infrastructure.IsOpenShift() {
+		securityContext = &corev1.PodSecurityContext{}
+	} else {
+		securityContext = config.GetGlobalConfig().Workspace.PodSecurityContext
+	}
+
+	sortedIds := append([]string{}, activeWorkspaceIds...)
+	sort.Strings(sortedIds)
+
+	deadline := garbageCollectionJobDeadline
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: common.StorageGCJobGenerateName(),
+			Namespace:    pvc.Namespace,
+			Labels:       jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			Completions:           &cleanupJobCompletions,
+			BackoffLimit:          &cleanupJobBackoffLimit,
+			ActiveDeadlineSeconds: &deadline,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:   "Never",
+					SecurityContext: securityContext,
+					Volumes: []corev1.Volume{
+						{
+							Name: pvc.Name,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvc.Name,
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "storage-gc",
+							Image:   images.GetPVCCleanupJobImage(),
+							Command: []string{"/bin/sh"},
+							Args:    []string{"-c", garbageCollectionScript},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "ACTIVE_WORKSPACES",
+									Value: strings.Join(sortedIds, " "),
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: pvcCleanupPodMemoryRequest,
+									corev1.ResourceCPU:    pvcCleanupPodCPURequest,
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: pvcCleanupPodMemoryLimit,
+									corev1.ResourceCPU:    pvcCleanupPodCPULimit,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      pvc.Name,
+									MountPath: pvcClaimMountPath,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(pvc.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	if len(podTolerations) > 0 {
+		job.Spec.Template.Spec.Tolerations = podTolerations
+	}
+	if len(nodeSelector) > 0 {
+		job.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+	return job, nil
+}
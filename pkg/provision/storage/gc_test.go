@@ -0,0 +1,165 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+func getGCTestWorkspace(name, id string, phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, phase)
+	workspace.Status.DevWorkspaceId = id
+	return workspace
+}
+
+func getGCTestCollector(objs ...client.Object) *GarbageCollector {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			CommonPVCGarbageCollection: &v1alpha1.CommonPVCGarbageCollectionConfig{
+				Enable: pointer.Bool(true),
+			},
+		},
+	})
+	pvc, _ := getPVCSpec("claim-devworkspace", "test-namespace", nil, resource.MustParse("10Gi"))
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, pvc, namespace)...).Build()
+	return &GarbageCollector{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           scheme,
+		Log:              zap.New(),
+	}
+}
+
+func listGCJobs(t *testing.T, gc *GarbageCollector) []batchv1.Job {
+	jobList := &batchv1.JobList{}
+	err := gc.Client.List(context.Background(), jobList, client.MatchingLabels{constants.DevWorkspaceStorageGCLabel: "true"})
+	if !assert.NoError(t, err, "Should not return error listing jobs") {
+		t.FailNow()
+	}
+	return jobList.Items
+}
+
+func TestGarbageCollectorCreatesJobForStoppedNamespace(t *testing.T) {
+	gc := getGCTestCollector(
+		getGCTestWorkspace("workspace-b", "workspaceb", dw.DevWorkspaceStatusStopped),
+		getGCTestWorkspace("workspace-a", "workspacea", dw.DevWorkspaceStatusFailed),
+	)
+	err := gc.collect(context.Background())
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	jobs := listGCJobs(t, gc)
+	if !assert.Len(t, jobs, 1, "Should create garbage collection job") {
+		return
+	}
+	container := jobs[0].Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []corev1.EnvVar{{Name: "ACTIVE_WORKSPACES", Value: "workspacea workspaceb"}}, container.Env,
+		"Should pass IDs of existing workspaces to job")
+	assert.Equal(t, "claim-devworkspace", jobs[0].Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	err = gc.collect(context.Background())
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Len(t, listGCJobs(t, gc), 1, "Should not create another job while job is running")
+}
+
+func TestGarbageCollectorSkipsNamespaceInUse(t *testing.T) {
+	runningWorkspace := getGCTestWorkspace("workspace-a", "workspacea", dw.DevWorkspaceStatusRunning)
+	runningWorkspace.Spec.Started = true
+	gc := getGCTestCollector(runningWorkspace)
+	err := gc.collect(context.Background())
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Empty(t, listGCJobs(t, gc), "Should not create job when workspaces in namespace are running")
+}
+
+func TestGarbageCollectorRecordsJobResult(t *testing.T) {
+	finishedJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "storage-gc-abcde",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{constants.DevWorkspaceStorageGCLabel: "true"},
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+		},
+	}
+	jobPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "storage-gc-abcde-12345",
+			Namespace: "test-namespace",
+			Labels:    map[string]string{"job-name": finishedJob.Name},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "storage-gc",
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{Message: "2048 2\n"},
+					},
+				},
+			},
+		},
+	}
+	gc := getGCTestCollector(finishedJob, jobPod)
+	reclaimedBefore := promtestutil.ToFloat64(gcReclaimedBytes)
+	removedBefore := promtestutil.ToFloat64(gcRemovedDirectories)
+
+	err := gc.collect(context.Background())
+	if !assert.NoError(t, err, "Should not return error") {
+		return
+	}
+	assert.Equal(t, float64(2048), promtestutil.ToFloat64(gcReclaimedBytes)-reclaimedBefore, "Should record reclaimed bytes")
+	assert.Equal(t, float64(2), promtestutil.ToFloat64(gcRemovedDirectories)-removedBefore, "Should record removed directories")
+	jobs := listGCJobs(t, gc)
+	if assert.Len(t, jobs, 1, "Should replace finished job with a new job") {
+		assert.NotEqual(t, finishedJob.Name, jobs[0].Name, "Should delete finished job")
+	}
+}
+
+func TestParseGarbageCollectionResult(t *testing.T) {
+	reclaimed, removed, err := parseGarbageCollectionResult("1024 1\n")
+	if assert.NoError(t, err, "Should not return error") {
+		assert.Equal(t, int64(1024), reclaimed)
+		assert.Equal(t, int64(1), removed)
+	}
+	_, _, err = parseGarbageCollectionResult("")
+	assert.Error(t, err, "Should return error for empty termination message")
+}
@@ -29,7 +29,9 @@ import (
 	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -52,13 +54,27 @@ func (p *PerWorkspaceStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1
 		return err
 	}
 
+	storageClass, err := getPerWorkspaceStorageClass(workspace)
+	if err != nil {
+		return err
+	}
+	allVolumeOptions, err := provisionVolumeOptions(workspace, storageClass, clusterAPI)
+	if err != nil {
+		return err
+	}
+
 	// If persistent storage is not needed, we're done
 	if !WorkspaceNeedsStorage(&workspace.Spec.Template) {
 		return nil
 	}
 
+	restoreSnapshot, err := getRestoreSnapshot(workspace, clusterAPI)
+	if err != nil {
+		return err
+	}
+
 	// Get perWorkspace PVC spec and sync it with cluster
-	perWorkspacePVC, err := syncPerWorkspacePVC(workspace, clusterAPI)
+	perWorkspacePVC, err := syncPerWorkspacePVC(workspace, restoreSnapshot, clusterAPI)
 	if err != nil {
 		return err
 	}
@@ -71,8 +87,15 @@ func (p *PerWorkspaceStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1
 		}
 	}
 
+	if err := restoreFromSnapshot(restoreSnapshot, workspace, clusterAPI); err != nil {
+		return err
+	}
+	if err := copyStorageFromCloneSource(workspace, clusterAPI); err != nil {
+		return err
+	}
+
 	// Rewrite container volume mounts
-	if err := p.rewriteContainerVolumeMounts(workspace.Status.DevWorkspaceId, pvcName, podAdditions, &workspace.Spec.Template); err != nil {
+	if err := p.rewriteContainerVolumeMounts(workspace.Status.DevWorkspaceId, pvcName, allVolumeOptions, podAdditions, &workspace.Spec.Template); err != nil {
 		return &dwerrors.FailError{
 			Err:     err,
 			Message: "Could not rewrite container volume mounts",
@@ -83,6 +106,7 @@ func (p *PerWorkspaceStorageProvisioner) ProvisionStorage(podAdditions *v1alpha1
 }
 
 // We rely on Kubernetes to use the owner reference to automatically delete the PVC once the workspace is set for deletion.
+// If the workspace has the retain-storage annotation, no owner reference is set and the PVC is kept.
 func (*PerWorkspaceStorageProvisioner) CleanupWorkspaceStorage(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
 	return nil
 }
@@ -91,7 +115,7 @@ func (*PerWorkspaceStorageProvisioner) CleanupWorkspaceStorage(workspace *common
 // (i.e. all volume mounts are subpaths into a PVC used by a single workspace in the namespace).
 //
 // Also adds appropriate k8s Volumes to PodAdditions to accomodate the rewritten VolumeMounts.
-func (p *PerWorkspaceStorageProvisioner) rewriteContainerVolumeMounts(workspaceId, pvcName string, podAdditions *v1alpha1.PodAdditions, workspace *dw.DevWorkspaceTemplateSpec) error {
+func (p *PerWorkspaceStorageProvisioner) rewriteContainerVolumeMounts(workspaceId, pvcName string, allVolumeOptions map[string]volumeOptions, podAdditions *v1alpha1.PodAdditions, workspace *dw.DevWorkspaceTemplateSpec) error {
 	devfileVolumes := map[string]dw.VolumeComponent{}
 
 	// Construct map of volume name -> volume Component
@@ -140,8 +164,9 @@ func (p *PerWorkspaceStorageProvisioner) rewriteContainerVolumeMounts(workspaceI
 					return fmt.Errorf("container '%s' references undefined volume '%s'", container.Name, vm.Name)
 				}
 				if !isEphemeral(&volume) {
-					containers[cIdx].VolumeMounts[vmIdx].SubPath = vm.Name
-					containers[cIdx].VolumeMounts[vmIdx].Name = pvcName
+					mountName, subPath := getVolumeMountSubPath(workspaceId, vm.Name, pvcName, "", allVolumeOptions)
+					containers[cIdx].VolumeMounts[vmIdx].SubPath = subPath
+					containers[cIdx].VolumeMounts[vmIdx].Name = mountName
 				}
 			}
 		}
@@ -162,11 +187,25 @@ func (p *PerWorkspaceStorageProvisioner) rewriteContainerVolumeMounts(workspaceI
 			},
 		},
 	})
+	podAdditions.Volumes = append(podAdditions.Volumes, getDedicatedPVCVolumes(workspaceId, allVolumeOptions)...)
 
 	return nil
 }
 
 func getPVCSize(workspace *common.DevWorkspaceWithConfig, namespacedConfig *nsconfig.NamespacedConfig) (*resource.Quantity, error) {
+	if workspace.Spec.Template.Attributes.Exists(constants.PerWorkspaceStorageSizeAttribute) {
+		var attrErr error
+		sizeAttr := workspace.Spec.Template.Attributes.GetString(constants.PerWorkspaceStorageSizeAttribute, &attrErr)
+		if attrErr != nil {
+			return nil, fmt.Errorf("failed to read attribute %s: %w", constants.PerWorkspaceStorageSizeAttribute, attrErr)
+		}
+		pvcSize, err := resource.ParseQuantity(sizeAttr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for attribute %s: %w", constants.PerWorkspaceStorageSizeAttribute, err)
+		}
+		return &pvcSize, nil
+	}
+
 	defaultPVCSize := *workspace.Config.Workspace.DefaultStorageSize.PerWorkspace
 
 	// Calculate required PVC size based on workspace volumes
@@ -177,6 +216,16 @@ func getPVCSize(workspace *common.DevWorkspaceWithConfig, namespacedConfig *nsco
 			if isEphemeral(component.Volume) {
 				continue
 			}
+			// Volumes on a dedicated PVC do not use space in the per-workspace PVC
+			if hasVolumeAttributes(&component) {
+				options, err := getVolumeOptions(&component)
+				if err != nil {
+					return nil, err
+				}
+				if options.dedicatedPVC {
+					continue
+				}
+			}
 
 			if component.Volume.Size == "" {
 				allVolumeSizesDefined = false
@@ -207,30 +256,50 @@ func getPVCSize(workspace *common.DevWorkspaceWithConfig, namespacedConfig *nsco
 	return &defaultPVCSize, nil
 }
 
-func syncPerWorkspacePVC(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*corev1.PersistentVolumeClaim, error) {
+// syncPerWorkspacePVC syncs the per-workspace PVC to the cluster. If restoreSnapshot captured the workspace's data as a
+// VolumeSnapshot, the PVC is created from that VolumeSnapshot.
+func syncPerWorkspacePVC(workspace *common.DevWorkspaceWithConfig, restoreSnapshot *v1alpha1.DevWorkspaceSnapshot, clusterAPI sync.ClusterAPI) (*corev1.PersistentVolumeClaim, error) {
 	namespacedConfig, err := nsconfig.ReadNamespacedConfig(workspace.Namespace, clusterAPI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read namespace-specific configuration: %w", err)
 	}
 
 	pvcSize, err := getPVCSize(workspace, namespacedConfig)
+	if err != nil {
+		return nil, &dwerrors.FailError{
+			Message: "Could not determine size of per-workspace PVC",
+			Err:     err,
+		}
+	}
+
+	storageClass, err := getPerWorkspaceStorageClass(workspace)
 	if err != nil {
 		return nil, err
 	}
 
-	storageClass := workspace.Config.Workspace.StorageClassName
-	pvc, err := getPVCSpec(common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId), workspace.Namespace, storageClass, *pvcSize)
+	dataSource := getVolumeSnapshotDataSource(restoreSnapshot)
+	if dataSource != nil && restoreSnapshot.Status.Size != "" {
+		// The PVC must be at least as large as the volume the snapshot was taken from
+		if restoreSize, err := resource.ParseQuantity(restoreSnapshot.Status.Size); err == nil && restoreSize.Cmp(*pvcSize) > 0 {
+			pvcSize = &restoreSize
+		}
+	}
+	pvc, err := getPVCSpec(common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, workspace.Annotations), workspace.Namespace, storageClass, *pvcSize)
 	if err != nil {
 		return nil, err
 	}
+	pvc.Spec.DataSource = dataSource
 	if pvc.Labels == nil {
 		pvc.Labels = map[string]string{}
 	}
 	pvc.Labels[constants.DevWorkspaceIDLabel] = workspace.Status.DevWorkspaceId
 	pvc.Labels[constants.DevWorkspacePVCTypeLabel] = constants.PerWorkspaceStorageClassType
 
-	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, pvc, clusterAPI.Scheme); err != nil {
-		return nil, err
+	retainStorage := shouldRetainStorage(workspace)
+	if !retainStorage {
+		if err := controllerutil.SetControllerReference(workspace.DevWorkspace, pvc, clusterAPI.Scheme); err != nil {
+			return nil, err
+		}
 	}
 
 	currObject, err := sync.SyncObjectWithCluster(pvc, clusterAPI)
@@ -255,5 +324,68 @@ func syncPerWorkspacePVC(workspace *common.DevWorkspaceWithConfig, clusterAPI sy
 		return nil, errors.New("tried to sync per-workspace PVC to cluster but did not get a PVC back")
 	}
 
+	// PVCs are not updated by the sync package, so we need to add or remove the owner reference manually if the
+	// retain-storage annotation is changed after the PVC is created.
+	if isOwnedByWorkspace(currPVC, workspace) == retainStorage {
+		if retainStorage {
+			removeWorkspaceOwnerReference(currPVC, workspace)
+		} else if err := controllerutil.SetControllerReference(workspace.DevWorkspace, currPVC, clusterAPI.Scheme); err != nil {
+			return nil, err
+		}
+		if err := clusterAPI.Client.Update(clusterAPI.Ctx, currPVC); err != nil {
+			if k8sErrors.IsConflict(err) {
+				return nil, &dwerrors.RetryError{Message: fmt.Sprintf("Conflict updating %s PVC on cluster", currPVC.Name)}
+			}
+			return nil, err
+		}
+		return nil, &dwerrors.RetryError{
+			Message: fmt.Sprintf("Updated owner references for %s PVC on cluster", currPVC.Name),
+		}
+	}
+
 	return currPVC, nil
 }
+
+// getPerWorkspaceStorageClass returns the storage class used for the PVCs of a DevWorkspace using the per-workspace
+// storage type: the storage class from the storage-class attribute if it is set, or the configured storage class.
+func getPerWorkspaceStorageClass(workspace *common.DevWorkspaceWithConfig) (*string, error) {
+	storageClass := workspace.Config.Workspace.StorageClassName
+	if workspace.Spec.Template.Attributes.Exists(constants.PerWorkspaceStorageClassAttribute) {
+		var attrErr error
+		storageClassAttr := workspace.Spec.Template.Attributes.GetString(constants.PerWorkspaceStorageClassAttribute, &attrErr)
+		if attrErr != nil {
+			return nil, &dwerrors.FailError{
+				Message: fmt.Sprintf("Failed to read attribute %s", constants.PerWorkspaceStorageClassAttribute),
+				Err:     attrErr,
+			}
+		}
+		if storageClassAttr != "" {
+			storageClass = &storageClassAttr
+		}
+	}
+	return storageClass, nil
+}
+
+// shouldRetainStorage returns whether the workspace's PVC should be kept when the workspace is deleted.
+func shouldRetainStorage(workspace *common.DevWorkspaceWithConfig) bool {
+	return workspace.Annotations[constants.DevWorkspaceRetainStorageAnnotation] == "true"
+}
+
+func isOwnedByWorkspace(pvc *corev1.PersistentVolumeClaim, workspace *common.DevWorkspaceWithConfig) bool {
+	for _, ownerRef := range pvc.OwnerReferences {
+		if ownerRef.UID == workspace.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeWorkspaceOwnerReference(pvc *corev1.PersistentVolumeClaim, workspace *common.DevWorkspaceWithConfig) {
+	var ownerRefs []metav1.OwnerReference
+	for _, ownerRef := range pvc.OwnerReferences {
+		if ownerRef.UID != workspace.UID {
+			ownerRefs = append(ownerRefs, ownerRef)
+		}
+	}
+	pvc.OwnerReferences = ownerRefs
+}
@@ -20,7 +20,9 @@ import (
 	"testing"
 
 	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
 	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 	"github.com/google/go-cmp/cmp"
 
@@ -66,10 +68,10 @@ func TestRewriteContainerVolumeMountsForPerWorkspaceStorageClass(t *testing.T) {
 					return
 				}
 
-				assert.Regexp(t, err.Error(), fmt.Sprintf("Updated %s PVC on cluster", common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId)))
+				assert.Regexp(t, err.Error(), fmt.Sprintf("Updated %s PVC on cluster", common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, nil)))
 
 				retrievedPVC := &corev1.PersistentVolumeClaim{}
-				namespacedName := types.NamespacedName{Name: common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+				namespacedName := types.NamespacedName{Name: common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, nil), Namespace: workspace.Namespace}
 
 				err = clusterAPI.Client.Get(clusterAPI.Ctx, namespacedName, retrievedPVC)
 
@@ -111,3 +113,78 @@ func TestRewriteContainerVolumeMountsForPerWorkspaceStorageClass(t *testing.T) {
 		})
 	}
 }
+
+func TestPerWorkspacePVCStorageClassAndRetainOverrides(t *testing.T) {
+	storageClass := "test-storage-class"
+	workspace := getDevWorkspaceWithConfig(&dw.DevWorkspace{})
+	workspace.Name = "test-workspace"
+	workspace.Namespace = "test-namespace"
+	workspace.UID = "test-uid"
+	workspace.Status.DevWorkspaceId = "test-workspaceid"
+	workspace.Annotations = map[string]string{
+		constants.DevWorkspaceRetainStorageAnnotation: "true",
+	}
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.PerWorkspaceStorageClassAttribute, storageClass)
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "testing-container",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{
+						Image: "testing-image",
+					},
+				},
+			},
+		},
+	}
+
+	clusterAPI := sync.ClusterAPI{
+		Scheme: scheme,
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger: zap.New(),
+	}
+	namespacedName := types.NamespacedName{Name: common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, nil), Namespace: workspace.Namespace}
+
+	_, err := syncPerWorkspacePVC(workspace, nil, clusterAPI)
+	assert.Error(t, err, "Should get a retry error when creating PVC")
+	pvc := &corev1.PersistentVolumeClaim{}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, namespacedName, pvc), "PVC should be created on cluster") {
+		return
+	}
+	if assert.NotNil(t, pvc.Spec.StorageClassName, "PVC should have storage class set") {
+		assert.Equal(t, storageClass, *pvc.Spec.StorageClassName, "PVC should use storage class from attribute")
+	}
+	assert.Empty(t, pvc.OwnerReferences, "PVC should not be owned by workspace when retain-storage annotation is set")
+
+	_, err = syncPerWorkspacePVC(workspace, nil, clusterAPI)
+	assert.NoError(t, err, "Should not return error when PVC is in sync")
+
+	delete(workspace.Annotations, constants.DevWorkspaceRetainStorageAnnotation)
+	_, err = syncPerWorkspacePVC(workspace, nil, clusterAPI)
+	if assert.Error(t, err, "Should get a retry error when updating PVC owner references") {
+		assert.Regexp(t, "Updated owner references for storage-test-workspaceid PVC on cluster", err.Error())
+	}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, namespacedName, pvc)) {
+		return
+	}
+	if assert.Len(t, pvc.OwnerReferences, 1, "PVC should be owned by workspace when retain-storage annotation is removed") {
+		assert.Equal(t, "DevWorkspace", pvc.OwnerReferences[0].Kind)
+	}
+
+	workspace.Annotations[constants.DevWorkspaceRetainStorageAnnotation] = "true"
+	_, err = syncPerWorkspacePVC(workspace, nil, clusterAPI)
+	assert.Error(t, err, "Should get a retry error when removing PVC owner references")
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, namespacedName, pvc)) {
+		return
+	}
+	assert.Empty(t, pvc.OwnerReferences, "Owner reference should be removed when retain-storage annotation is added")
+}
+
+func TestPerWorkspacePVCInvalidStorageSizeAttribute(t *testing.T) {
+	workspace := getDevWorkspaceWithConfig(&dw.DevWorkspace{})
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.PerWorkspaceStorageSizeAttribute, "not-a-size")
+	_, err := getPVCSize(workspace, nil)
+	if assert.Error(t, err, "Should return error for invalid storage-size attribute") {
+		assert.Regexp(t, "invalid value for attribute controller.devfile.io/storage-size", err.Error())
+	}
+}
@@ -47,7 +47,7 @@ type Provisioner interface {
 
 // GetProvisioner returns the storage provisioner that should be used for the current workspace
 func GetProvisioner(workspace *common.DevWorkspaceWithConfig) (Provisioner, error) {
-	storageClass := workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil)
+	storageClass := GetStorageType(workspace)
 	if storageClass == "" {
 		return &CommonStorageProvisioner{}, nil
 	}
@@ -66,3 +66,24 @@ func GetProvisioner(workspace *common.DevWorkspaceWithConfig) (Provisioner, erro
 		return nil, UnsupportedStorageStrategy
 	}
 }
+
+// GetStorageType returns the storage strategy used for the current workspace. If the workspace does not define the
+// storage-type attribute, the default storage type from the workspace's config is used. An empty string is returned
+// if neither is set, in which case the common storage strategy applies.
+func GetStorageType(workspace *common.DevWorkspaceWithConfig) string {
+	var defaultStorageType string
+	if workspace.Config != nil && workspace.Config.Workspace != nil {
+		defaultStorageType = workspace.Config.Workspace.DefaultStorageType
+	}
+	return getStorageTypeWithDefault(workspace.DevWorkspace, defaultStorageType)
+}
+
+// getStorageTypeWithDefault returns the storage-type attribute of a workspace, or defaultStorageType if the attribute
+// is not set.
+func getStorageTypeWithDefault(workspace *dw.DevWorkspace, defaultStorageType string) string {
+	storageClass := workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil)
+	if storageClass == "" {
+		return defaultStorageType
+	}
+	return storageClass
+}
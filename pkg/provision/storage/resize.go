@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const pvcResizeRequeueAfter = 5 * time.Second
+
+// resizeCommonPVCIfNeeded expands the common PVC when the sum of the sizes of volumes defined by workspaces using
+// the common PVC exceeds the size of the PVC, if the PVC's storage class allows volume expansion. Returns a
+// RetryError while the PVC is being resized, and a WarningError if the PVC needs to be resized but its storage
+// class does not support expansion.
+func resizeCommonPVCIfNeeded(pvc *corev1.PersistentVolumeClaim, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	if pvc.Status.Phase != corev1.ClaimBound {
+		// Requested size can only be increased for bound PVCs
+		return nil
+	}
+
+	currentSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if isPVCResizing(pvc) {
+		return &dwerrors.RetryError{
+			Message:      fmt.Sprintf("Waiting for PVC %s to be resized to %s", pvc.Name, currentSize.String()),
+			RequeueAfter: pvcResizeRequeueAfter,
+		}
+	}
+
+	requiredSize, err := getRequiredCommonPVCSize(workspace, clusterAPI)
+	if err != nil {
+		return err
+	}
+	if requiredSize.Cmp(currentSize) <= 0 {
+		return nil
+	}
+
+	if infrastructure.IsNamespaceScoped() {
+		// StorageClasses are cluster-scoped, so it cannot be checked whether the PVC can be expanded
+		return &dwerrors.WarningError{
+			Message: fmt.Sprintf("Volumes in workspaces using PVC %s request %s, but the PVC's size is %s. The PVC must be expanded manually, as storage classes cannot be read in namespace-scoped mode",
+				pvc.Name, requiredSize.String(), currentSize.String()),
+		}
+	}
+	canExpand, err := storageClassAllowsExpansion(pvc, clusterAPI)
+	if err != nil {
+		return err
+	}
+	if !canExpand {
+		return &dwerrors.WarningError{
+			Message: fmt.Sprintf("Volumes in workspaces using PVC %s request %s, but the PVC's size is %s and its storage class does not support volume expansion",
+				pvc.Name, requiredSize.String(), currentSize.String()),
+		}
+	}
+
+	patch := client.MergeFrom(pvc.DeepCopy())
+	pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *requiredSize
+	if err := clusterAPI.Client.Patch(clusterAPI.Ctx, pvc, patch); err != nil {
+		return err
+	}
+	clusterAPI.Logger.Info("Expanding common PVC", "name", pvc.Name, "from", currentSize.String(), "to", requiredSize.String())
+	return &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Expanding PVC %s from %s to %s", pvc.Name, currentSize.String(), requiredSize.String()),
+		RequeueAfter: pvcResizeRequeueAfter,
+	}
+}
+
+// getRequiredCommonPVCSize returns the sum of the sizes of all persistent volumes defined in workspaces in the
+// namespace that use the common PVC. Volumes that do not define a size are not included. For the current workspace,
+// the flattened template is used; for other workspaces, only volumes defined directly in the DevWorkspace are counted.
+func getRequiredCommonPVCSize(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*resource.Quantity, error) {
+	workspaces := &dw.DevWorkspaceList{}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, workspaces, &client.ListOptions{Namespace: workspace.Namespace}); err != nil {
+		return nil, err
+	}
+
+	requiredSize, err := getVolumeSizeTotal(&workspace.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	for _, otherWorkspace := range workspaces.Items {
+		if otherWorkspace.UID == workspace.UID || otherWorkspace.DeletionTimestamp != nil {
+			continue
+		}
+		if !usesCommonPVC(getStorageTypeWithDefault(&otherWorkspace, workspace.Config.Workspace.DefaultStorageType)) {
+			continue
+		}
+		workspaceSize, err := getVolumeSizeTotal(&otherWorkspace.Spec.Template)
+		if err != nil {
+			// Invalid sizes in other workspaces will be reported when those workspaces are started
+			continue
+		}
+		requiredSize.Add(*workspaceSize)
+	}
+	return requiredSize, nil
+}
+
+// getVolumeSizeTotal returns the sum of the sizes of all non-ephemeral volumes in a workspace that define a size.
+func getVolumeSizeTotal(workspace *dw.DevWorkspaceTemplateSpec) (*resource.Quantity, error) {
+	total := resource.NewQuantity(0, resource.BinarySI)
+	for _, component := range workspace.Components {
+		if component.Volume == nil || isEphemeral(component.Volume) || component.Volume.Size == "" {
+			continue
+		}
+		size, err := resource.ParseQuantity(component.Volume.Size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse size for volume %s: %w", component.Name, err)
+		}
+		total.Add(size)
+	}
+	return total, nil
+}
+
+func usesCommonPVC(storageType string) bool {
+	switch storageType {
+	case "", constants.CommonStorageClassType, constants.PerUserStorageClassType, constants.AsyncStorageClassType:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPVCResizing returns whether a resize of the PVC's underlying volume is in progress.
+func isPVCResizing(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimResizing && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func storageClassAllowsExpansion(pvc *corev1.PersistentVolumeClaim, clusterAPI sync.ClusterAPI) (bool, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false, nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	// StorageClasses are read using the non-caching client to avoid watching all StorageClasses in the cluster
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass)
+	if err != nil {
+		return false, fmt.Errorf("failed to read storage class %s for PVC %s: %w", *pvc.Spec.StorageClassName, pvc.Name, err)
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
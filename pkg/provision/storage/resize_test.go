@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getResizeTestWorkspace(name string, volumeSizes ...string) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, "")
+	for idx, size := range volumeSizes {
+		workspace.Spec.Template.Components = append(workspace.Spec.Template.Components, dw.Component{
+			Name: name + "-volume-" + string(rune('a'+idx)),
+			ComponentUnion: dw.ComponentUnion{
+				Volume: &dw.VolumeComponent{
+					Volume: dw.Volume{Size: size},
+				},
+			},
+		})
+	}
+	return workspace
+}
+
+func getResizeTestPVC(size string, resizing bool) *corev1.PersistentVolumeClaim {
+	pvc, _ := getPVCSpec("claim-devworkspace", "test-namespace", pointer.String("test-storage-class"), resource.MustParse(size))
+	pvc.Status.Phase = corev1.ClaimBound
+	if resizing {
+		pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+			{Type: corev1.PersistentVolumeClaimResizing, Status: corev1.ConditionTrue},
+		}
+	}
+	return pvc
+}
+
+func TestResizeCommonPVCIfNeeded(t *testing.T) {
+	tests := []struct {
+		name            string
+		pvc             *corev1.PersistentVolumeClaim
+		allowExpansion  bool
+		namespaceScoped bool
+		otherWorkspaces []*dw.DevWorkspace
+		expectedSize    string
+		expectedErr     error
+		errRegexp       string
+	}{
+		{
+			name:         "Does not resize when volumes fit in PVC",
+			pvc:          getResizeTestPVC("10Gi", false),
+			expectedSize: "10Gi",
+		},
+		{
+			name:            "Expands PVC when sum of volumes is larger than PVC",
+			pvc:             getResizeTestPVC("10Gi", false),
+			allowExpansion:  true,
+			otherWorkspaces: []*dw.DevWorkspace{getResizeTestWorkspace("other-workspace", "6Gi")},
+			expectedSize:    "11Gi",
+			expectedErr:     &dwerrors.RetryError{},
+			errRegexp:       "Expanding PVC claim-devworkspace from 10Gi to 11Gi",
+		},
+		{
+			name:            "Returns warning when storage class does not allow expansion",
+			pvc:             getResizeTestPVC("10Gi", false),
+			otherWorkspaces: []*dw.DevWorkspace{getResizeTestWorkspace("other-workspace", "6Gi")},
+			expectedSize:    "10Gi",
+			expectedErr:     &dwerrors.WarningError{},
+			errRegexp:       "storage class does not support volume expansion",
+		},
+		{
+			name:            "Returns warning in namespace-scoped mode",
+			pvc:             getResizeTestPVC("10Gi", false),
+			allowExpansion:  true,
+			namespaceScoped: true,
+			otherWorkspaces: []*dw.DevWorkspace{getResizeTestWorkspace("other-workspace", "6Gi")},
+			expectedSize:    "10Gi",
+			expectedErr:     &dwerrors.WarningError{},
+			errRegexp:       "The PVC must be expanded manually, as storage classes cannot be read in namespace-scoped mode",
+		},
+		{
+			name:         "Waits for PVC resize to complete",
+			pvc:          getResizeTestPVC("12Gi", true),
+			expectedSize: "12Gi",
+			expectedErr:  &dwerrors.RetryError{},
+			errRegexp:    "Waiting for PVC claim-devworkspace to be resized to 12Gi",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.namespaceScoped {
+				t.Setenv(infrastructure.WatchedNamespacesEnvVar, "test-namespace")
+			}
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "test-storage-class"},
+				Provisioner:          "test-provisioner",
+				AllowVolumeExpansion: pointer.Bool(tt.allowExpansion),
+			}
+			workspace := getResizeTestWorkspace("test-workspace", "2Gi", "3Gi")
+			objects := []client.Object{storageClass, tt.pvc, workspace}
+			for _, otherWorkspace := range tt.otherWorkspaces {
+				objects = append(objects, otherWorkspace)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			clusterAPI := sync.ClusterAPI{
+				Client:           fakeClient,
+				NonCachingClient: fakeClient,
+				Scheme:           scheme,
+				Logger:           zap.New(),
+			}
+
+			err := resizeCommonPVCIfNeeded(tt.pvc, getDevWorkspaceWithConfig(workspace), clusterAPI)
+			if tt.expectedErr != nil {
+				if assert.Error(t, err, "Should return error") {
+					assert.IsType(t, tt.expectedErr, err)
+					assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+				}
+			} else {
+				assert.NoError(t, err, "Should not return error")
+			}
+
+			clusterPVC := &corev1.PersistentVolumeClaim{}
+			if assert.NoError(t, fakeClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: tt.pvc.Name, Namespace: tt.pvc.Namespace}, clusterPVC)) {
+				clusterSize := clusterPVC.Spec.Resources.Requests[corev1.ResourceStorage]
+				assert.Equal(t, tt.expectedSize, clusterSize.String(), "PVC size on cluster should match")
+			}
+		})
+	}
+}
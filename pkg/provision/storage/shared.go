@@ -244,8 +244,9 @@ func checkForAlternatePVC(namespace string, api sync.ClusterAPI) (exists bool, n
 
 // getSharedPVCWorkspaceCount returns the total number of workspaces which are using a shared PVC
 // (i.e the workspaces storage-class attribute is set to "common", "async", or unset which defaults to "common")
+// Workspaces that do not set the storage-class attribute are assumed to use defaultStorageType.
 // Note that workspaces that are have been deleted (i.e. have a deletion timestamp) are not counted.
-func getSharedPVCWorkspaceCount(namespace string, api sync.ClusterAPI) (total int, err error) {
+func getSharedPVCWorkspaceCount(namespace, defaultStorageType string, api sync.ClusterAPI) (total int, err error) {
 	workspaces := &dw.DevWorkspaceList{}
 	err = api.Client.List(api.Ctx, workspaces, &client.ListOptions{Namespace: namespace})
 	if err != nil {
@@ -256,9 +257,9 @@ func getSharedPVCWorkspaceCount(namespace string, api sync.ClusterAPI) (total in
 			// Ignore terminating workspaces
 			continue
 		}
-		storageClass := workspace.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil)
+		storageClass := getStorageTypeWithDefault(&workspace, defaultStorageType)
 		// Note, if the storageClass attribute isn't set (ie. storageClass == ""), then the storage class being used is "common"
-		if storageClass == constants.AsyncStorageClassType || storageClass == constants.CommonStorageClassType || storageClass == constants.PerUserStorageClassType || storageClass == "" {
+		if usesCommonPVC(storageClass) {
 			total++
 		}
 	}
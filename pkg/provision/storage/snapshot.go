@@ -0,0 +1,418 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// VolumeSnapshotAPIGroup is the API group of CSI VolumeSnapshots
+	VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	// VolumeSnapshotKind is the kind of CSI VolumeSnapshots
+	VolumeSnapshotKind = "VolumeSnapshot"
+
+	defaultSnapshotRegion = "us-east-1"
+
+	snapshotDataMountPath    = "/workspace-storage"
+	snapshotArchiveMountPath = "/snapshot"
+	snapshotArchiveVolume    = "snapshot-archive"
+	snapshotContainerName    = "snapshot"
+
+	// snapshotRestoredMarker is created at the root of a DevWorkspace's data once a snapshot has been restored into it,
+	// so that the restore job does not overwrite data if it is ever run again.
+	snapshotRestoredMarker = ".devworkspace-snapshot-restored"
+
+	// snapshotArchiveScript archives a DevWorkspace's data and uploads it to an S3-compatible bucket. The size of
+	// the uploaded archive is written to the container's termination message.
+	snapshotArchiveScript = `set -e
+tar -czf "` + snapshotArchiveMountPath + `/snapshot.tar.gz" --exclude="./` + snapshotRestoredMarker + `" -C "` + snapshotDataMountPath + `/${DATA_PATH}" .
+curl --fail --silent --show-error --aws-sigv4 "aws:amz:${AWS_REGION}:s3" --user "${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}" \
+  --upload-file "` + snapshotArchiveMountPath + `/snapshot.tar.gz" "${SNAPSHOT_URL}"
+stat -c %s "` + snapshotArchiveMountPath + `/snapshot.tar.gz" > /dev/termination-log`
+
+	// snapshotRestoreScript downloads an archive created by snapshotArchiveScript and extracts it into a
+	// DevWorkspace's data directory.
+	snapshotRestoreScript = `set -e
+target="` + snapshotDataMountPath + `/${DATA_PATH}"
+if [ -f "${target}/` + snapshotRestoredMarker + `" ]; then
+  echo "Snapshot already restored"
+  exit 0
+fi
+curl --fail --silent --show-error --aws-sigv4 "aws:amz:${AWS_REGION}:s3" --user "${AWS_ACCESS_KEY_ID}:${AWS_SECRET_ACCESS_KEY}" \
+  --output "` + snapshotArchiveMountPath + `/snapshot.tar.gz" "${SNAPSHOT_URL}"
+mkdir -p "${target}"
+tar -xzf "` + snapshotArchiveMountPath + `/snapshot.tar.gz" -C "${target}"
+touch "${target}/` + snapshotRestoredMarker + `"`
+)
+
+var (
+	snapshotJobBackoffLimit  = int32(2)
+	snapshotPodMemoryLimit   = resource.MustParse(constants.SnapshotPodMemoryLimit)
+	snapshotPodMemoryRequest = resource.MustParse(constants.SnapshotPodMemoryRequest)
+	snapshotPodCPULimit      = resource.MustParse(constants.SnapshotPodCPULimit)
+	snapshotPodCPURequest    = resource.MustParse(constants.SnapshotPodCPURequest)
+)
+
+// GetWorkspaceDataLocation returns the name of the PVC that stores a DevWorkspace's persistent data and the path
+// within that PVC under which the data is stored. An error is returned if the DevWorkspace's storage type does not
+// store data in a PVC that can be snapshotted.
+func GetWorkspaceDataLocation(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (pvcName, subPath string, err error) {
+	switch storageType := GetStorageType(workspace); storageType {
+	case constants.PerWorkspaceStorageClassType:
+		return common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, workspace.Annotations), "", nil
+	case "", constants.CommonStorageClassType, constants.PerUserStorageClassType:
+		_, pvcName, err := checkForAlternatePVC(workspace.Namespace, clusterAPI)
+		if err != nil {
+			return "", "", err
+		}
+		if pvcName == "" {
+			pvcName = workspace.Config.Workspace.PVCName
+		}
+		return pvcName, workspace.Status.DevWorkspaceId, nil
+	default:
+		return "", "", fmt.Errorf("storage type %q does not support snapshots", storageType)
+	}
+}
+
+// GetSnapshotArchiveURL returns the URL an archive of a DevWorkspace's data is uploaded to for a snapshot that
+// uses the ObjectStorage method.
+func GetSnapshotArchiveURL(snapshot *v1alpha1.DevWorkspaceSnapshot) string {
+	if snapshot.Spec.ObjectStorage == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/%s.tar.gz", strings.TrimSuffix(snapshot.Spec.ObjectStorage.URL, "/"), snapshot.Namespace, snapshot.Name)
+}
+
+// GetSpecSnapshotJob returns the job that archives a DevWorkspace's data and uploads it to object storage for a
+// DevWorkspaceSnapshot. The job is owned by the DevWorkspaceSnapshot.
+func GetSpecSnapshotJob(snapshot *v1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*batchv1.Job, error) {
+	pvcName, subPath, err := GetWorkspaceDataLocation(workspace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	job, err := getSpecSnapshotTransferJob(common.SnapshotJobName(string(snapshot.UID)), snapshotArchiveScript, GetSnapshotArchiveURL(snapshot),
+		snapshot, workspace, pvcName, subPath, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err := controllerutil.SetControllerReference(snapshot, job, clusterAPI.Scheme); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// getRestoreSnapshot returns the DevWorkspaceSnapshot a DevWorkspace's storage should be restored from, as specified
+// by the restore-from-snapshot attribute, or nil if no restore is necessary. A restore is not necessary if the workspace
+// does not use the attribute or if the snapshot was already restored.
+func getRestoreSnapshot(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*v1alpha1.DevWorkspaceSnapshot, error) {
+	if !workspace.Spec.Template.Attributes.Exists(constants.RestoreFromSnapshotAttribute) {
+		return nil, nil
+	}
+	var attrErr error
+	snapshotName := workspace.Spec.Template.Attributes.GetString(constants.RestoreFromSnapshotAttribute, &attrErr)
+	if attrErr != nil {
+		return nil, &dwerrors.FailError{
+			Message: fmt.Sprintf("Failed to read attribute %s", constants.RestoreFromSnapshotAttribute),
+			Err:     attrErr,
+		}
+	}
+	if snapshotName == "" {
+		return nil, nil
+	}
+
+	restored, err := isSnapshotRestored(workspace, clusterAPI)
+	if err != nil || restored {
+		return nil, err
+	}
+
+	snapshot := &v1alpha1.DevWorkspaceSnapshot{}
+	namespacedName := types.NamespacedName{Name: snapshotName, Namespace: workspace.Namespace}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, namespacedName, snapshot); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, &dwerrors.FailError{
+				Message: fmt.Sprintf("DevWorkspaceSnapshot %s does not exist", snapshotName),
+			}
+		}
+		return nil, err
+	}
+	switch snapshot.Status.Phase {
+	case v1alpha1.SnapshotPhaseReady:
+		return snapshot, nil
+	case v1alpha1.SnapshotPhaseFailed:
+		return nil, &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot restore from DevWorkspaceSnapshot %s: snapshot failed", snapshotName),
+		}
+	default:
+		return nil, &dwerrors.RetryError{
+			Message:      fmt.Sprintf("Waiting for DevWorkspaceSnapshot %s to be ready", snapshotName),
+			RequeueAfter: 5 * time.Second,
+		}
+	}
+}
+
+// isSnapshotRestored checks whether a DevWorkspace's storage has already been populated from a snapshot: either the
+// restore job completed, or the per-workspace PVC was created from a VolumeSnapshot. This allows the DevWorkspace to
+// be restarted even if the snapshot it was restored from is later deleted.
+func isSnapshotRestored(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (bool, error) {
+	job := &batchv1.Job{}
+	jobNN := types.NamespacedName{Name: common.SnapshotRestoreJobName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, jobNN, job)
+	switch {
+	case err == nil:
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobComplete && condition.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+	case !k8sErrors.IsNotFound(err):
+		return false, err
+	}
+
+	if GetStorageType(workspace) != constants.PerWorkspaceStorageClassType {
+		return false, nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvcNN := types.NamespacedName{Name: common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, workspace.Annotations), Namespace: workspace.Namespace}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, pvcNN, pvc); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	dataSource := pvc.Spec.DataSource
+	return dataSource != nil && dataSource.Kind == VolumeSnapshotKind, nil
+}
+
+// getVolumeSnapshotDataSource returns the data source for a per-workspace PVC that restores the VolumeSnapshot
+// captured by a DevWorkspaceSnapshot, or nil if the snapshot does not use the VolumeSnapshot method.
+func getVolumeSnapshotDataSource(snapshot *v1alpha1.DevWorkspaceSnapshot) *corev1.TypedLocalObjectReference {
+	if snapshot == nil || snapshot.Status.Method != v1alpha1.SnapshotMethodVolumeSnapshot {
+		return nil
+	}
+	apiGroup := VolumeSnapshotAPIGroup
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     VolumeSnapshotKind,
+		Name:     snapshot.Status.VolumeSnapshotName,
+	}
+}
+
+// restoreFromSnapshot populates a DevWorkspace's storage with the contents of a snapshot archived to object storage,
+// by running a job that extracts the archive into the DevWorkspace's data directory. Snapshots that use the
+// VolumeSnapshot method are restored when the per-workspace PVC is created and require no further action here.
+func restoreFromSnapshot(snapshot *v1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	if snapshot == nil {
+		return nil
+	}
+	if snapshot.Status.Method == v1alpha1.SnapshotMethodVolumeSnapshot {
+		if GetStorageType(workspace) != constants.PerWorkspaceStorageClassType {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("DevWorkspaceSnapshot %s uses a VolumeSnapshot and can only be restored into a DevWorkspace that uses %s storage",
+					snapshot.Name, constants.PerWorkspaceStorageClassType),
+			}
+		}
+		return nil
+	}
+
+	pvcName, subPath, err := GetWorkspaceDataLocation(workspace, clusterAPI)
+	if err != nil {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot restore from DevWorkspaceSnapshot %s", snapshot.Name),
+			Err:     err,
+		}
+	}
+	specJob, err := getSpecSnapshotTransferJob(common.SnapshotRestoreJobName(workspace.Status.DevWorkspaceId), snapshotRestoreScript, snapshot.Status.Location,
+		snapshot, workspace, pvcName, subPath, clusterAPI)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specJob, clusterAPI.Scheme); err != nil {
+		return err
+	}
+	clusterObj, err := sync.SyncObjectWithCluster(specJob, clusterAPI)
+	if err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+
+	clusterJob := clusterObj.(*batchv1.Job)
+	for _, condition := range clusterJob.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return nil
+		case batchv1.JobFailed:
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Failed to restore DevWorkspaceSnapshot %s: see logs for job %q for details", snapshot.Name, clusterJob.Name),
+			}
+		}
+	}
+	return &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Restoring DevWorkspaceSnapshot %s", snapshot.Name),
+		RequeueAfter: 5 * time.Second,
+	}
+}
+
+// getSpecSnapshotTransferJob returns a job that runs script with the DevWorkspace's data directory and a scratch volume
+// for the archive mounted, and with the archive URL and the snapshot's object storage credentials available as
+// environment variables.
+func getSpecSnapshotTransferJob(name, script, archiveURL string, snapshot *v1alpha1.DevWorkspaceSnapshot, workspace *common.DevWorkspaceWithConfig,
+	pvcName, subPath string, clusterAPI sync.ClusterAPI) (*batchv1.Job, error) {
+
+	objectStorage := snapshot.Spec.ObjectStorage
+	if objectStorage == nil {
+		return nil, &dwerrors.FailError{
+			Message: fmt.Sprintf("DevWorkspaceSnapshot %s does not define objectStorage", snapshot.Name),
+		}
+	}
+	region := objectStorage.Region
+	if region == "" {
+		region = defaultSnapshotRegion
+	}
+
+	jobLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:      workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:    workspace.Name,
+		constants.DevWorkspaceCreatorLabel: workspace.Labels[constants.DevWorkspaceCreatorLabel],
+	}
+	if restrictedAccess, needsRestrictedAccess := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; needsRestrictedAccess {
+		jobLabels[constants.DevWorkspaceRestrictedAccessAnnotation] = restrictedAccess
+	}
+
+	var securityContext *corev1.PodSecurityContext
+	if infrastructure.IsOpenShift() {
+		securityContext = &corev1.PodSecurityContext{}
+	} else {
+		securityContext = workspace.Config.Workspace.PodSecurityContext
+	}
+
+	credentialEnv := func(key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: objectStorage.CredentialsSecretName},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: workspace.Namespace,
+			Labels:    jobLabels,
+		},
+		Spec: batchv1.JobSpec{
+			Completions:  &cleanupJobCompletions,
+			BackoffLimit: &snapshotJobBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:   "Never",
+					SecurityContext: securityContext,
+					Volumes: []corev1.Volume{
+						{
+							Name: pvcName,
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: pvcName,
+								},
+							},
+						},
+						{
+							Name: snapshotArchiveVolume,
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    snapshotContainerName,
+							Image:   images.GetWorkspaceSnapshotImage(),
+							Command: []string{"/bin/sh"},
+							Args:    []string{"-c", script},
+							Env: []corev1.EnvVar{
+								{Name: "SNAPSHOT_URL", Value: archiveURL},
+								{Name: "DATA_PATH", Value: path.Clean(subPath)},
+								{Name: "AWS_REGION", Value: region},
+								credentialEnv("AWS_ACCESS_KEY_ID"),
+								credentialEnv("AWS_SECRET_ACCESS_KEY"),
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: snapshotPodMemoryRequest,
+									corev1.ResourceCPU:    snapshotPodCPURequest,
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: snapshotPodMemoryLimit,
+									corev1.ResourceCPU:    snapshotPodCPULimit,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      pvcName,
+									MountPath: snapshotDataMountPath,
+								},
+								{
+									Name:      snapshotArchiveVolume,
+									MountPath: snapshotArchiveMountPath,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	if len(podTolerations) > 0 {
+		job.Spec.Template.Spec.Tolerations = podTolerations
+	}
+	if len(nodeSelector) > 0 {
+		job.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+	return job, nil
+}
@@ -0,0 +1,221 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getSnapshotTestWorkspace(storageType string) *common.DevWorkspaceWithConfig {
+	workspace := getDevWorkspaceWithConfig(testutil.NewDevWorkspace("test-workspace", ""))
+	workspace.Spec.Template.Attributes = attributes.Attributes{}.
+		PutString(constants.DevWorkspaceStorageTypeAttribute, storageType).
+		PutString(constants.RestoreFromSnapshotAttribute, "test-snapshot")
+	return workspace
+}
+
+func getTestSnapshot(method v1alpha1.DevWorkspaceSnapshotMethod, phase v1alpha1.DevWorkspaceSnapshotPhase) *v1alpha1.DevWorkspaceSnapshot {
+	return &v1alpha1.DevWorkspaceSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-snapshot",
+			Namespace: "test-namespace",
+			UID:       "snapshot-uid",
+		},
+		Spec: v1alpha1.DevWorkspaceSnapshotSpec{
+			DevWorkspaceName: "source-workspace",
+			ObjectStorage: &v1alpha1.SnapshotObjectStorage{
+				URL:                   "https://s3.example.com/bucket/",
+				CredentialsSecretName: "s3-credentials",
+			},
+		},
+		Status: v1alpha1.DevWorkspaceSnapshotStatus{
+			Phase:              phase,
+			Method:             method,
+			VolumeSnapshotName: "test-snapshot",
+			Location:           "https://s3.example.com/bucket/test-namespace/test-snapshot.tar.gz",
+			Size:               "20Gi",
+		},
+	}
+}
+
+func getSnapshotTestClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, namespace)...).Build()
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           scheme,
+		Logger:           zap.New(),
+		Ctx:              context.Background(),
+	}
+}
+
+func TestGetRestoreSnapshot(t *testing.T) {
+	tests := []struct {
+		name           string
+		snapshot       *v1alpha1.DevWorkspaceSnapshot
+		expectSnapshot bool
+		expectedErr    interface{}
+	}{
+		{
+			name:           "Returns snapshot when ready",
+			snapshot:       getTestSnapshot(v1alpha1.SnapshotMethodObjectStorage, v1alpha1.SnapshotPhaseReady),
+			expectSnapshot: true,
+		},
+		{
+			name:        "Waits for snapshot in progress",
+			snapshot:    getTestSnapshot(v1alpha1.SnapshotMethodObjectStorage, v1alpha1.SnapshotPhaseInProgress),
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name:        "Fails when snapshot failed",
+			snapshot:    getTestSnapshot(v1alpha1.SnapshotMethodObjectStorage, v1alpha1.SnapshotPhaseFailed),
+			expectedErr: &dwerrors.FailError{},
+		},
+		{
+			name:        "Fails when snapshot does not exist",
+			expectedErr: &dwerrors.FailError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var objs []client.Object
+			if tt.snapshot != nil {
+				objs = append(objs, tt.snapshot)
+			}
+			clusterAPI := getSnapshotTestClusterAPI(objs...)
+			snapshot, err := getRestoreSnapshot(getSnapshotTestWorkspace(constants.CommonStorageClassType), clusterAPI)
+			if tt.expectedErr != nil {
+				assert.IsType(t, tt.expectedErr, err)
+				assert.Nil(t, snapshot)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectSnapshot, snapshot != nil)
+		})
+	}
+}
+
+func TestGetRestoreSnapshotIgnoresWorkspacesWithoutAttribute(t *testing.T) {
+	workspace := getSnapshotTestWorkspace(constants.CommonStorageClassType)
+	workspace.Spec.Template.Attributes = attributes.Attributes{}
+	snapshot, err := getRestoreSnapshot(workspace, getSnapshotTestClusterAPI())
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestPerWorkspacePVCIsCreatedFromVolumeSnapshot(t *testing.T) {
+	workspace := getSnapshotTestWorkspace(constants.PerWorkspaceStorageClassType)
+	snapshot := getTestSnapshot(v1alpha1.SnapshotMethodVolumeSnapshot, v1alpha1.SnapshotPhaseReady)
+	clusterAPI := getSnapshotTestClusterAPI(snapshot)
+
+	_, err := syncPerWorkspacePVC(workspace, snapshot, clusterAPI)
+	assert.Error(t, err, "Should get a retry error when creating PVC")
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvcNN := types.NamespacedName{Name: common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, nil), Namespace: workspace.Namespace}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, pvcNN, pvc), "PVC should be created on cluster") {
+		return
+	}
+	if assert.NotNil(t, pvc.Spec.DataSource, "PVC should have data source set") {
+		assert.Equal(t, VolumeSnapshotKind, pvc.Spec.DataSource.Kind)
+		assert.Equal(t, "test-snapshot", pvc.Spec.DataSource.Name)
+	}
+	requestedSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	assert.Zero(t, requestedSize.Cmp(resource.MustParse("20Gi")), "PVC should be at least as large as the snapshot")
+
+	restoreSnapshot, err := getRestoreSnapshot(workspace, clusterAPI)
+	assert.NoError(t, err)
+	assert.Nil(t, restoreSnapshot, "Should not restore again once PVC is created from VolumeSnapshot")
+	assert.NoError(t, restoreFromSnapshot(snapshot, workspace, clusterAPI), "Should not need restore job for VolumeSnapshots")
+}
+
+func TestRestoreVolumeSnapshotIntoCommonStorageFails(t *testing.T) {
+	workspace := getSnapshotTestWorkspace(constants.CommonStorageClassType)
+	snapshot := getTestSnapshot(v1alpha1.SnapshotMethodVolumeSnapshot, v1alpha1.SnapshotPhaseReady)
+	err := restoreFromSnapshot(snapshot, workspace, getSnapshotTestClusterAPI(snapshot))
+	assert.IsType(t, &dwerrors.FailError{}, err)
+}
+
+func TestRestoreFromObjectStorage(t *testing.T) {
+	workspace := getSnapshotTestWorkspace(constants.CommonStorageClassType)
+	snapshot := getTestSnapshot(v1alpha1.SnapshotMethodObjectStorage, v1alpha1.SnapshotPhaseReady)
+	clusterAPI := getSnapshotTestClusterAPI(snapshot, workspace.DevWorkspace)
+
+	err := restoreFromSnapshot(snapshot, workspace, clusterAPI)
+	if !assert.IsType(t, &dwerrors.RetryError{}, err, "Should wait for restore job to complete") {
+		return
+	}
+	job := &batchv1.Job{}
+	jobNN := types.NamespacedName{Name: common.SnapshotRestoreJobName(workspace.Status.DevWorkspaceId), Namespace: workspace.Namespace}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, jobNN, job), "Restore job should be created") {
+		return
+	}
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "SNAPSHOT_URL", Value: snapshot.Status.Location})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "DATA_PATH", Value: workspace.Status.DevWorkspaceId})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "AWS_REGION", Value: defaultSnapshotRegion})
+	assert.Equal(t, workspace.Config.Workspace.PVCName, job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	assert.True(t, metav1.IsControlledBy(job, workspace.DevWorkspace), "Restore job should be owned by workspace")
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	if !assert.NoError(t, clusterAPI.Client.Status().Update(clusterAPI.Ctx, job)) {
+		return
+	}
+	assert.NoError(t, restoreFromSnapshot(snapshot, workspace, clusterAPI), "Should succeed once restore job completes")
+
+	assert.NoError(t, clusterAPI.Client.Delete(clusterAPI.Ctx, snapshot))
+	restoreSnapshot, err := getRestoreSnapshot(workspace, clusterAPI)
+	assert.NoError(t, err, "Should not require snapshot once it has been restored")
+	assert.Nil(t, restoreSnapshot)
+}
+
+func TestGetSpecSnapshotJob(t *testing.T) {
+	workspace := getSnapshotTestWorkspace(constants.PerWorkspaceStorageClassType)
+	snapshot := getTestSnapshot("", "")
+	snapshot.Spec.ObjectStorage.Region = "eu-west-1"
+	job, err := GetSpecSnapshotJob(snapshot, workspace, getSnapshotTestClusterAPI())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "snapshot-snapshot-uid", job.Name)
+	assert.True(t, metav1.IsControlledBy(job, snapshot), "Snapshot job should be owned by snapshot")
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "SNAPSHOT_URL", Value: "https://s3.example.com/bucket/test-namespace/test-snapshot.tar.gz"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "DATA_PATH", Value: "."})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "AWS_REGION", Value: "eu-west-1"})
+	assert.Equal(t, common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, nil), job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+}
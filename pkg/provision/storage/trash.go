@@ -0,0 +1,234 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// trashDevWorkspaceKey is the key in trash ConfigMaps that stores the deleted DevWorkspace
+const trashDevWorkspaceKey = "devworkspace.json"
+
+// trashIgnoredAnnotations lists annotations that are not kept when a DevWorkspace is moved to the trash, as they
+// describe the state of the deleted DevWorkspace and not its configuration.
+var trashIgnoredAnnotations = []string{
+	constants.DevWorkspaceStartedAtAnnotation,
+	constants.DevWorkspaceStopReasonAnnotation,
+	constants.DevWorkspaceStartupDiagnosticsAnnotation,
+	"kubectl.kubernetes.io/last-applied-configuration",
+}
+
+// ShouldMoveToTrash returns whether the storage of a deleted workspace should be retained in the trash instead of
+// being cleaned up. This is the case when the trash is enabled and the workspace uses the per-user (common) or
+// per-workspace storage types. Workspaces with the retain-storage annotation are not moved to the trash, as their
+// storage is never removed.
+func ShouldMoveToTrash(workspace *common.DevWorkspaceWithConfig) bool {
+	if !pointer.BoolDeref(workspace.Config.Workspace.Trash.Enable, false) || shouldRetainStorage(workspace) {
+		return false
+	}
+	provisioner, err := GetProvisioner(workspace)
+	if err != nil {
+		return false
+	}
+	switch provisioner.(type) {
+	case *CommonStorageProvisioner, *PerWorkspaceStorageProvisioner:
+		return true
+	default:
+		return false
+	}
+}
+
+// MoveToTrash stores a copy of a deleted workspace in a trash ConfigMap, which expires after the configured retention
+// period. For the per-workspace storage type, the workspace's PVC becomes owned by the trash ConfigMap so that it is
+// removed only once the ConfigMap is deleted. For the per-user storage type, the workspace's data on the common PVC
+// is kept until the trash ConfigMap is deleted and it is removed by the common PVC garbage collector.
+func MoveToTrash(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	retention, err := time.ParseDuration(workspace.Config.Workspace.Trash.RetentionPeriod)
+	if err != nil {
+		return &dwerrors.FailError{Message: "Invalid trash retention period", Err: err}
+	}
+
+	specCM, err := getSpecTrashConfigMap(workspace, time.Now().Add(retention))
+	if err != nil {
+		return err
+	}
+	clusterCM := &corev1.ConfigMap{}
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: specCM.Name, Namespace: specCM.Namespace}, clusterCM)
+	if k8sErrors.IsNotFound(err) {
+		if err := clusterAPI.Client.Create(clusterAPI.Ctx, specCM); err != nil {
+			return err
+		}
+		clusterCM = specCM
+	} else if err != nil {
+		return err
+	}
+
+	if GetStorageType(workspace) != constants.PerWorkspaceStorageClassType {
+		return nil
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvcNN := types.NamespacedName{Name: common.PerWorkspacePVCName(workspace.Status.DevWorkspaceId, workspace.Annotations), Namespace: workspace.Namespace}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, pvcNN, pvc); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if metav1.IsControlledBy(pvc, clusterCM) {
+		return nil
+	}
+	removeWorkspaceOwnerReference(pvc, workspace)
+	if err := controllerutil.SetControllerReference(clusterCM, pvc, clusterAPI.Scheme); err != nil {
+		return err
+	}
+	if err := clusterAPI.Client.Update(clusterAPI.Ctx, pvc); err != nil {
+		if k8sErrors.IsConflict(err) {
+			return &dwerrors.RetryError{Message: fmt.Sprintf("Conflict updating %s PVC on cluster", pvc.Name)}
+		}
+		return err
+	}
+	return nil
+}
+
+// GetTrashedWorkspace returns the DevWorkspace stored in a trash ConfigMap.
+func GetTrashedWorkspace(trashCM *corev1.ConfigMap) (*dw.DevWorkspace, error) {
+	data, ok := trashCM.Data[trashDevWorkspaceKey]
+	if !ok {
+		return nil, fmt.Errorf("trash ConfigMap %s does not contain key %s", trashCM.Name, trashDevWorkspaceKey)
+	}
+	workspace := &dw.DevWorkspace{}
+	if err := json.Unmarshal([]byte(data), workspace); err != nil {
+		return nil, fmt.Errorf("failed to read DevWorkspace from trash ConfigMap %s: %w", trashCM.Name, err)
+	}
+	return workspace, nil
+}
+
+// GetTrashExpiration returns the time after which a trash ConfigMap and the storage it retains should be removed.
+func GetTrashExpiration(trashCM *corev1.ConfigMap) (time.Time, error) {
+	return time.Parse(time.RFC3339, trashCM.Annotations[constants.DevWorkspaceTrashExpirationAnnotation])
+}
+
+// ReleaseTrashedStorage removes the owner reference from a trash ConfigMap on the per-workspace PVC it retains (if
+// any), so that the PVC is not removed when the ConfigMap is deleted and can be adopted by the restored workspace.
+func ReleaseTrashedStorage(trashCM *corev1.ConfigMap, clusterAPI sync.ClusterAPI) error {
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, pvcList, k8sclient.InNamespace(trashCM.Namespace),
+		k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: trashCM.Labels[constants.DevWorkspaceIDLabel]}); err != nil {
+		return err
+	}
+	for _, pvc := range pvcList.Items {
+		if !metav1.IsControlledBy(&pvc, trashCM) {
+			continue
+		}
+		var ownerRefs []metav1.OwnerReference
+		for _, ownerRef := range pvc.OwnerReferences {
+			if ownerRef.UID != trashCM.UID {
+				ownerRefs = append(ownerRefs, ownerRef)
+			}
+		}
+		pvc.OwnerReferences = ownerRefs
+		if err := clusterAPI.Client.Update(clusterAPI.Ctx, &pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTrashedWorkspaceIDs returns the IDs of DevWorkspaces in a namespace that are in the trash.
+func getTrashedWorkspaceIDs(namespace string, api sync.ClusterAPI) ([]string, error) {
+	cmList := &corev1.ConfigMapList{}
+	if err := api.Client.List(api.Ctx, cmList, k8sclient.InNamespace(namespace), k8sclient.MatchingLabels{constants.DevWorkspaceTrashLabel: "true"}); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, cm := range cmList.Items {
+		if id := cm.Labels[constants.DevWorkspaceIDLabel]; id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func getSpecTrashConfigMap(workspace *common.DevWorkspaceWithConfig, expiration time.Time) (*corev1.ConfigMap, error) {
+	trashedWorkspace := &dw.DevWorkspace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DevWorkspace",
+			APIVersion: dw.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        workspace.Name,
+			Namespace:   workspace.Namespace,
+			Labels:      workspace.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: *workspace.Spec.DeepCopy(),
+	}
+	for key, value := range workspace.Annotations {
+		trashedWorkspace.Annotations[key] = value
+	}
+	for _, ignored := range trashIgnoredAnnotations {
+		delete(trashedWorkspace.Annotations, ignored)
+	}
+	// Restored workspaces reuse the ID and storage type of the deleted workspace to find their storage
+	trashedWorkspace.Annotations[constants.WorkspaceIdOverrideAnnotation] = workspace.Status.DevWorkspaceId
+	if trashedWorkspace.Spec.Template.Attributes == nil {
+		trashedWorkspace.Spec.Template.Attributes = attributes.Attributes{}
+	}
+	if !trashedWorkspace.Spec.Template.Attributes.Exists(constants.DevWorkspaceStorageTypeAttribute) && GetStorageType(workspace) != "" {
+		trashedWorkspace.Spec.Template.Attributes.PutString(constants.DevWorkspaceStorageTypeAttribute, GetStorageType(workspace))
+	}
+	trashedWorkspace.Spec.Started = false
+
+	data, err := json.Marshal(trashedWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.TrashConfigMapName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceTrashLabel:          "true",
+				constants.DevWorkspaceIDLabel:             workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceNameLabel:           workspace.Name,
+				constants.DevWorkspaceWatchConfigMapLabel: "true",
+			},
+			Annotations: map[string]string{
+				constants.DevWorkspaceTrashExpirationAnnotation: expiration.UTC().Format(time.RFC3339),
+			},
+		},
+		Data: map[string]string{
+			trashDevWorkspaceKey: string(data),
+		},
+	}, nil
+}
@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getTrashTestWorkspace(storageType string) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspace("test-workspace", "")
+	workspace.Annotations = map[string]string{
+		"test-annotation":                         "true",
+		constants.DevWorkspaceStartedAtAnnotation: "1234",
+	}
+	workspace.Spec.Started = true
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "test-volume",
+			ComponentUnion: dw.ComponentUnion{
+				Volume: &dw.VolumeComponent{},
+			},
+		},
+	}
+	workspace.Status.DevWorkspaceId = "workspace-test-id"
+	if storageType != "" {
+		workspace.Spec.Template.Attributes = attributes.Attributes{}.PutString(constants.DevWorkspaceStorageTypeAttribute, storageType)
+	}
+	workspaceWithConfig := &common.DevWorkspaceWithConfig{}
+	workspaceWithConfig.DevWorkspace = workspace
+	workspaceWithConfig.Config = config.GetConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			DefaultStorageType: constants.PerUserStorageClassType,
+			Trash: &v1alpha1.TrashConfig{
+				Enable:          pointer.Bool(true),
+				RetentionPeriod: "1h",
+			},
+		},
+	})
+	return workspaceWithConfig
+}
+
+func getTrashTestClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	return sync.ClusterAPI{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme: scheme,
+		Logger: zap.New(),
+		Ctx:    context.Background(),
+	}
+}
+
+func TestShouldMoveToTrash(t *testing.T) {
+	assert.True(t, ShouldMoveToTrash(getTrashTestWorkspace("")), "Should move workspaces using default per-user storage to trash")
+	assert.True(t, ShouldMoveToTrash(getTrashTestWorkspace(constants.PerWorkspaceStorageClassType)), "Should move per-workspace workspaces to trash")
+	assert.False(t, ShouldMoveToTrash(getTrashTestWorkspace(constants.EphemeralStorageClassType)), "Should not move ephemeral workspaces to trash")
+	assert.False(t, ShouldMoveToTrash(getTrashTestWorkspace(constants.AsyncStorageClassType)), "Should not move async workspaces to trash")
+
+	retained := getTrashTestWorkspace(constants.PerWorkspaceStorageClassType)
+	retained.Annotations[constants.DevWorkspaceRetainStorageAnnotation] = "true"
+	assert.False(t, ShouldMoveToTrash(retained), "Should not move workspaces with retained storage to trash")
+
+	disabled := getTrashTestWorkspace("")
+	disabled.Config.Workspace.Trash.Enable = pointer.Bool(false)
+	assert.False(t, ShouldMoveToTrash(disabled), "Should not move workspaces to trash when trash is disabled")
+}
+
+func TestMoveToTrashStoresWorkspace(t *testing.T) {
+	workspace := getTrashTestWorkspace("")
+	clusterAPI := getTrashTestClusterAPI()
+
+	before := time.Now()
+	if !assert.NoError(t, MoveToTrash(workspace, clusterAPI)) {
+		return
+	}
+
+	trashCM := &corev1.ConfigMap{}
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: common.TrashConfigMapName("workspace-test-id"), Namespace: "test-namespace"}, trashCM)
+	if !assert.NoError(t, err, "Should create trash ConfigMap") {
+		return
+	}
+	assert.Equal(t, "true", trashCM.Labels[constants.DevWorkspaceTrashLabel])
+	assert.Equal(t, "workspace-test-id", trashCM.Labels[constants.DevWorkspaceIDLabel])
+	assert.Equal(t, "true", trashCM.Labels[constants.DevWorkspaceWatchConfigMapLabel], "Trash ConfigMap should be cached by the controller")
+
+	expiration, err := GetTrashExpiration(trashCM)
+	if assert.NoError(t, err) {
+		assert.WithinDuration(t, before.Add(time.Hour), expiration, 5*time.Second)
+	}
+
+	trashed, err := GetTrashedWorkspace(trashCM)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "test-workspace", trashed.Name)
+	assert.False(t, trashed.Spec.Started, "Restored workspace should be stopped")
+	assert.Equal(t, workspace.Spec.Template.Components, trashed.Spec.Template.Components)
+	assert.Equal(t, "workspace-test-id", trashed.Annotations[constants.WorkspaceIdOverrideAnnotation], "Restored workspace should reuse workspace ID")
+	assert.Equal(t, "true", trashed.Annotations["test-annotation"])
+	assert.NotContains(t, trashed.Annotations, constants.DevWorkspaceStartedAtAnnotation)
+	assert.Equal(t, constants.PerUserStorageClassType, trashed.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil),
+		"Restored workspace should use the same storage type")
+}
+
+func TestMoveToTrashTransfersPerWorkspacePVC(t *testing.T) {
+	workspace := getTrashTestWorkspace(constants.PerWorkspaceStorageClassType)
+	pvc, err := getPVCSpec(common.PerWorkspacePVCName("workspace-test-id", nil), "test-namespace", nil, resource.MustParse("5Gi"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	pvc.Labels = map[string]string{constants.DevWorkspaceIDLabel: "workspace-test-id"}
+	if !assert.NoError(t, controllerutil.SetControllerReference(workspace.DevWorkspace, pvc, scheme)) {
+		return
+	}
+	clusterAPI := getTrashTestClusterAPI(pvc)
+
+	if !assert.NoError(t, MoveToTrash(workspace, clusterAPI)) {
+		return
+	}
+
+	trashCM := &corev1.ConfigMap{}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: common.TrashConfigMapName("workspace-test-id"), Namespace: "test-namespace"}, trashCM)) {
+		return
+	}
+	clusterPVC := &corev1.PersistentVolumeClaim{}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, client.ObjectKeyFromObject(pvc), clusterPVC)) {
+		return
+	}
+	if assert.Len(t, clusterPVC.OwnerReferences, 1, "PVC should only be owned by trash ConfigMap") {
+		assert.Equal(t, "ConfigMap", clusterPVC.OwnerReferences[0].Kind)
+		assert.Equal(t, trashCM.Name, clusterPVC.OwnerReferences[0].Name)
+	}
+
+	if !assert.NoError(t, ReleaseTrashedStorage(trashCM, clusterAPI)) {
+		return
+	}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, client.ObjectKeyFromObject(pvc), clusterPVC)) {
+		return
+	}
+	assert.Empty(t, clusterPVC.OwnerReferences, "Releasing storage should remove trash ConfigMap owner reference")
+}
@@ -0,0 +1,313 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// defaultStorageAccessModesKey is the key in workspace.storageAccessModes that applies to PVCs without a storage class.
+const defaultStorageAccessModesKey = "default"
+
+var volumeAttributes = []string{
+	constants.VolumeAccessModeAttribute,
+	constants.DedicatedPVCAttribute,
+	constants.VolumeSubPathAttribute,
+}
+
+var validAccessModes = map[corev1.PersistentVolumeAccessMode]bool{
+	corev1.ReadWriteOnce:    true,
+	corev1.ReadOnlyMany:     true,
+	corev1.ReadWriteMany:    true,
+	corev1.ReadWriteOncePod: true,
+}
+
+// volumeOptions stores the options set on a volume component through the access-mode, dedicated-pvc and subpath
+// attributes.
+type volumeOptions struct {
+	accessMode   corev1.PersistentVolumeAccessMode
+	dedicatedPVC bool
+	subPath      string
+}
+
+// hasVolumeAttributes returns whether any of the volume attributes are set on a component.
+func hasVolumeAttributes(component *dw.Component) bool {
+	for _, attribute := range volumeAttributes {
+		if component.Attributes.Exists(attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// getVolumeOptions reads the options for a volume component from its attributes. Volumes that request an access mode
+// other than ReadWriteOnce are always provisioned on a dedicated PVC. Returns an error if any attribute is invalid.
+func getVolumeOptions(component *dw.Component) (*volumeOptions, error) {
+	options := &volumeOptions{accessMode: corev1.ReadWriteOnce}
+	attributes := component.Attributes
+	if attributes.Exists(constants.VolumeAccessModeAttribute) {
+		var err error
+		accessMode := corev1.PersistentVolumeAccessMode(attributes.GetString(constants.VolumeAccessModeAttribute, &err))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attribute %s on volume %s: %w", constants.VolumeAccessModeAttribute, component.Name, err)
+		}
+		if !validAccessModes[accessMode] {
+			return nil, fmt.Errorf("invalid access mode %q for volume %s: must be one of %s, %s, %s or %s", accessMode, component.Name,
+				corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany, corev1.ReadWriteOncePod)
+		}
+		options.accessMode = accessMode
+	}
+	if attributes.Exists(constants.DedicatedPVCAttribute) {
+		var err error
+		options.dedicatedPVC = attributes.GetBoolean(constants.DedicatedPVCAttribute, &err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attribute %s on volume %s: %w", constants.DedicatedPVCAttribute, component.Name, err)
+		}
+	}
+	if options.accessMode != corev1.ReadWriteOnce {
+		options.dedicatedPVC = true
+	}
+	if attributes.Exists(constants.VolumeSubPathAttribute) {
+		var err error
+		subPath := attributes.GetString(constants.VolumeSubPathAttribute, &err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attribute %s on volume %s: %w", constants.VolumeSubPathAttribute, component.Name, err)
+		}
+		if subPath == "" || subPath == "." || path.IsAbs(subPath) || path.Clean(subPath) != subPath ||
+			subPath == ".." || strings.HasPrefix(subPath, "../") {
+			return nil, fmt.Errorf("invalid subpath %q for volume %s: must be a non-empty, normalized relative path that does not contain '..'", subPath, component.Name)
+		}
+		options.subPath = subPath
+	}
+	return options, nil
+}
+
+// getWorkspaceVolumeOptions returns the options of all volume components in a DevWorkspace that set any of the volume
+// attributes, keyed by volume name. Returns a FailError if an attribute is invalid or is set on an ephemeral volume.
+func getWorkspaceVolumeOptions(workspace *dw.DevWorkspaceTemplateSpec) (map[string]volumeOptions, error) {
+	allOptions := map[string]volumeOptions{}
+	for idx, component := range workspace.Components {
+		if component.Volume == nil || !hasVolumeAttributes(&workspace.Components[idx]) {
+			continue
+		}
+		if isEphemeral(component.Volume) {
+			return nil, &dwerrors.FailError{
+				Message: fmt.Sprintf("Volume %s is ephemeral and cannot set attributes %s", component.Name, strings.Join(volumeAttributes, ", ")),
+			}
+		}
+		options, err := getVolumeOptions(&workspace.Components[idx])
+		if err != nil {
+			return nil, &dwerrors.FailError{Message: "Invalid volume attributes", Err: err}
+		}
+		allOptions[component.Name] = *options
+	}
+	return allOptions, nil
+}
+
+// checkVolumeAttributesNotUsed returns a FailError if any volume component in the DevWorkspace sets one of the volume
+// attributes, for storage types that do not support them.
+func checkVolumeAttributesNotUsed(workspace *dw.DevWorkspaceTemplateSpec, storageType string) error {
+	for idx, component := range workspace.Components {
+		if component.Volume != nil && hasVolumeAttributes(&workspace.Components[idx]) {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Volume %s sets attributes that are not supported with the %s storage type: %s",
+					component.Name, storageType, strings.Join(volumeAttributes, ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// checkVolumeAccessModes verifies that the access modes requested by volumes are supported by the storage class,
+// according to workspace.storageAccessModes in the DevWorkspaceOperatorConfig. ReadWriteOnce is always supported.
+func checkVolumeAccessModes(workspace *common.DevWorkspaceWithConfig, allOptions map[string]volumeOptions, storageClass *string) error {
+	storageClassKey := defaultStorageAccessModesKey
+	if storageClass != nil && *storageClass != "" {
+		storageClassKey = *storageClass
+	}
+	supportedModes := []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	supportedModes = append(supportedModes, workspace.Config.Workspace.StorageAccessModes[storageClassKey]...)
+
+	for _, volumeName := range getSortedVolumeNames(allOptions) {
+		accessMode := allOptions[volumeName].accessMode
+		supported := false
+		for _, supportedMode := range supportedModes {
+			if supportedMode == accessMode {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			var supportedModeNames []string
+			for _, supportedMode := range supportedModes {
+				supportedModeNames = append(supportedModeNames, string(supportedMode))
+			}
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Volume %s requests access mode %s, which is not supported by storage class %s (supported access modes: %s)",
+					volumeName, accessMode, storageClassKey, strings.Join(supportedModeNames, ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// provisionVolumeOptions reads the volume attributes of a DevWorkspace, checks that the requested access modes are
+// supported by the storage class and syncs a dedicated PVC for each volume that requires one. Returns the options
+// for each volume that sets volume attributes, keyed by volume name.
+func provisionVolumeOptions(workspace *common.DevWorkspaceWithConfig, storageClass *string, clusterAPI sync.ClusterAPI) (map[string]volumeOptions, error) {
+	allOptions, err := getWorkspaceVolumeOptions(&workspace.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVolumeAccessModes(workspace, allOptions, storageClass); err != nil {
+		return nil, err
+	}
+	for _, component := range workspace.Spec.Template.Components {
+		options, ok := allOptions[component.Name]
+		if !ok || component.Volume == nil || !options.dedicatedPVC {
+			continue
+		}
+		if err := syncDedicatedVolumePVC(workspace, component.Name, component.Volume, options, storageClass, clusterAPI); err != nil {
+			return nil, err
+		}
+	}
+	return allOptions, nil
+}
+
+// syncDedicatedVolumePVC syncs the dedicated PVC for a volume component to the cluster. As PVC access modes cannot be
+// changed, a FailError is returned if the PVC already exists with different access modes.
+func syncDedicatedVolumePVC(workspace *common.DevWorkspaceWithConfig, volumeName string, volume *dw.VolumeComponent, options volumeOptions, storageClass *string, clusterAPI sync.ClusterAPI) error {
+	pvcSize := *workspace.Config.Workspace.DefaultStorageSize.PerWorkspace
+	if volume.Size != "" {
+		size, err := resource.ParseQuantity(volume.Size)
+		if err != nil {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Failed to parse size for volume %s", volumeName),
+				Err:     err,
+			}
+		}
+		pvcSize = size
+	}
+
+	pvc, err := getPVCSpec(common.DedicatedVolumePVCName(workspace.Status.DevWorkspaceId, volumeName), workspace.Namespace, storageClass, pvcSize)
+	if err != nil {
+		return err
+	}
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{options.accessMode}
+	pvc.Labels = map[string]string{
+		constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId,
+		// Dedicated PVCs are used by a single DevWorkspace, like per-workspace PVCs
+		constants.DevWorkspacePVCTypeLabel: constants.PerWorkspaceStorageClassType,
+	}
+	if !shouldRetainStorage(workspace) {
+		if err := controllerutil.SetControllerReference(workspace.DevWorkspace, pvc, clusterAPI.Scheme); err != nil {
+			return err
+		}
+	}
+
+	currObject, err := sync.SyncObjectWithCluster(pvc, clusterAPI)
+	switch t := err.(type) {
+	case nil:
+		break
+	case *sync.NotInSyncError:
+		return &dwerrors.RetryError{
+			Message: fmt.Sprintf("Updated %s PVC on cluster", pvc.Name),
+		}
+	case *sync.UnrecoverableSyncError:
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("Failed to sync %s PVC to cluster", pvc.Name),
+			Err:     t.Cause,
+		}
+	default:
+		return err
+	}
+
+	currPVC, ok := currObject.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return errors.New("tried to sync dedicated volume PVC to cluster but did not get a PVC back")
+	}
+	if currPVC.DeletionTimestamp != nil {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("PVC %s for volume %s is being deleted", currPVC.Name, volumeName),
+		}
+	}
+	if len(currPVC.Spec.AccessModes) != 1 || currPVC.Spec.AccessModes[0] != options.accessMode {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("PVC %s for volume %s has access modes %v, but the volume requests access mode %s. Access modes of existing PVCs cannot be changed",
+				currPVC.Name, volumeName, currPVC.Spec.AccessModes, options.accessMode),
+		}
+	}
+	return nil
+}
+
+// getVolumeMountSubPath returns the subpath used to mount a persistent volume, and the name of the PVC volume it should be
+// mounted from. Volumes on a dedicated PVC are mounted at the subpath from the subpath attribute, or the root of
+// the PVC. Other volumes are mounted from the DevWorkspace's PVC, at the subpath from the subpath attribute or at
+// the volume's name, prefixed by subPathPrefix.
+func getVolumeMountSubPath(workspaceId, volumeName, pvcName, subPathPrefix string, allOptions map[string]volumeOptions) (mountName, subPath string) {
+	options := allOptions[volumeName]
+	if options.dedicatedPVC {
+		return common.DedicatedVolumePVCName(workspaceId, volumeName), options.subPath
+	}
+	subPath = volumeName
+	if options.subPath != "" {
+		subPath = options.subPath
+	}
+	return pvcName, path.Join(subPathPrefix, subPath)
+}
+
+// getDedicatedPVCVolumes returns the pod volumes for all dedicated PVCs of a DevWorkspace, sorted by name.
+func getDedicatedPVCVolumes(workspaceId string, allOptions map[string]volumeOptions) []corev1.Volume {
+	var volumes []corev1.Volume
+	for _, volumeName := range getSortedVolumeNames(allOptions) {
+		if !allOptions[volumeName].dedicatedPVC {
+			continue
+		}
+		pvcName := common.DedicatedVolumePVCName(workspaceId, volumeName)
+		volumes = append(volumes, corev1.Volume{
+			Name: pvcName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvcName,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+func getSortedVolumeNames(allOptions map[string]volumeOptions) []string {
+	var names []string
+	for name := range allOptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,208 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package storage
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getVolumeTestWorkspace(volumeAttributes attributes.Attributes) *common.DevWorkspaceWithConfig {
+	workspace := getDevWorkspaceWithConfig(testutil.NewDevWorkspace("test-workspace", ""))
+	volume := dw.Component{Name: "shared-cache", Attributes: volumeAttributes}
+	volume.Volume = &dw.VolumeComponent{}
+	volume.Volume.Size = "5Gi"
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "tools",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{
+						Image:        "testing-image",
+						VolumeMounts: []dw.VolumeMount{{Name: "shared-cache", Path: "/cache"}},
+					},
+				},
+			},
+		},
+		volume,
+	}
+	return workspace
+}
+
+func getVolumeTestPodAdditions() *v1alpha1.PodAdditions {
+	return &v1alpha1.PodAdditions{
+		Containers: []corev1.Container{
+			{
+				Name:         "tools",
+				Image:        "testing-image",
+				VolumeMounts: []corev1.VolumeMount{{Name: "shared-cache", MountPath: "/cache"}},
+			},
+		},
+	}
+}
+
+func TestGetVolumeOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		attributes      attributes.Attributes
+		expectedOptions *volumeOptions
+		expectedErr     string
+	}{
+		{
+			name:            "Defaults to ReadWriteOnce on workspace PVC",
+			attributes:      attributes.Attributes{},
+			expectedOptions: &volumeOptions{accessMode: corev1.ReadWriteOnce},
+		},
+		{
+			name:            "ReadWriteMany uses dedicated PVC",
+			attributes:      attributes.Attributes{}.PutString(constants.VolumeAccessModeAttribute, "ReadWriteMany"),
+			expectedOptions: &volumeOptions{accessMode: corev1.ReadWriteMany, dedicatedPVC: true},
+		},
+		{
+			name: "Reads dedicated PVC and subpath",
+			attributes: attributes.Attributes{}.
+				PutBoolean(constants.DedicatedPVCAttribute, true).
+				PutString(constants.VolumeSubPathAttribute, "data/cache"),
+			expectedOptions: &volumeOptions{accessMode: corev1.ReadWriteOnce, dedicatedPVC: true, subPath: "data/cache"},
+		},
+		{
+			name:        "Invalid access mode",
+			attributes:  attributes.Attributes{}.PutString(constants.VolumeAccessModeAttribute, "ReadWriteSometimes"),
+			expectedErr: `invalid access mode "ReadWriteSometimes" for volume shared-cache: must be one of ReadWriteOnce, ReadOnlyMany, ReadWriteMany or ReadWriteOncePod`,
+		},
+		{
+			name:        "Absolute subpath",
+			attributes:  attributes.Attributes{}.PutString(constants.VolumeSubPathAttribute, "/data"),
+			expectedErr: `invalid subpath "/data" for volume shared-cache: must be a non-empty, normalized relative path that does not contain '..'`,
+		},
+		{
+			name:        "Subpath that is not normalized",
+			attributes:  attributes.Attributes{}.PutString(constants.VolumeSubPathAttribute, "data/../../other"),
+			expectedErr: `invalid subpath "data/../../other" for volume shared-cache: must be a non-empty, normalized relative path that does not contain '..'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := &dw.Component{Name: "shared-cache", Attributes: tt.attributes}
+			options, err := getVolumeOptions(component)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedOptions, options)
+		})
+	}
+}
+
+func TestAccessModeMustBeSupportedByStorageClass(t *testing.T) {
+	workspace := getVolumeTestWorkspace(attributes.Attributes{}.PutString(constants.VolumeAccessModeAttribute, "ReadWriteMany"))
+	allOptions, err := getWorkspaceVolumeOptions(&workspace.Spec.Template)
+	require.NoError(t, err)
+
+	err = checkVolumeAccessModes(workspace, allOptions, nil)
+	assert.IsType(t, &dwerrors.FailError{}, err)
+	assert.EqualError(t, err, "Volume shared-cache requests access mode ReadWriteMany, which is not supported by storage class default (supported access modes: ReadWriteOnce)")
+
+	workspace.Config = workspace.Config.DeepCopy()
+	workspace.Config.Workspace.StorageAccessModes = map[string][]corev1.PersistentVolumeAccessMode{
+		"nfs-client": {corev1.ReadWriteMany},
+	}
+	assert.NoError(t, checkVolumeAccessModes(workspace, allOptions, pointer.String("nfs-client")))
+	assert.Error(t, checkVolumeAccessModes(workspace, allOptions, pointer.String("gp2")), "Should check access modes for storage class in use")
+}
+
+func TestVolumeAttributesNotSupportedOnEphemeralVolumes(t *testing.T) {
+	workspace := getVolumeTestWorkspace(attributes.Attributes{}.PutString(constants.VolumeSubPathAttribute, "cache"))
+	workspace.Spec.Template.Components[1].Volume.Ephemeral = pointer.Bool(true)
+	_, err := getWorkspaceVolumeOptions(&workspace.Spec.Template)
+	assert.IsType(t, &dwerrors.FailError{}, err)
+}
+
+func TestVolumeAttributesNotSupportedForEphemeralStorage(t *testing.T) {
+	workspace := getVolumeTestWorkspace(attributes.Attributes{}.PutBoolean(constants.DedicatedPVCAttribute, true))
+	err := EphemeralStorageProvisioner{}.ProvisionStorage(getVolumeTestPodAdditions(), workspace, sync.ClusterAPI{})
+	if assert.IsType(t, &dwerrors.FailError{}, err) {
+		assert.Regexp(t, "Volume shared-cache sets attributes that are not supported with the ephemeral storage type", err.Error())
+	}
+}
+
+func TestProvisionsDedicatedPVCForVolume(t *testing.T) {
+	workspace := getVolumeTestWorkspace(attributes.Attributes{}.
+		PutString(constants.VolumeAccessModeAttribute, "ReadWriteMany").
+		PutString(constants.VolumeSubPathAttribute, "cache"))
+	workspace.Config = workspace.Config.DeepCopy()
+	workspace.Config.Workspace.StorageAccessModes = map[string][]corev1.PersistentVolumeAccessMode{
+		"default": {corev1.ReadWriteMany},
+	}
+	clusterAPI := sync.ClusterAPI{
+		Scheme: scheme,
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Logger: zap.New(),
+	}
+	provisioner := PerWorkspaceStorageProvisioner{}
+	dedicatedPVCName := common.DedicatedVolumePVCName(workspace.Status.DevWorkspaceId, "shared-cache")
+
+	err := provisioner.ProvisionStorage(getVolumeTestPodAdditions(), workspace, clusterAPI)
+	if assert.IsType(t, &dwerrors.RetryError{}, err, "Should get a retry error when creating dedicated PVC") {
+		assert.Regexp(t, "Updated test-workspaceid-shared-cache PVC on cluster", err.Error())
+	}
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: dedicatedPVCName, Namespace: workspace.Namespace}, pvc))
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, pvc.Spec.AccessModes)
+	assert.Equal(t, "5Gi", pvc.Spec.Resources.Requests.Storage().String(), "Should use volume size for dedicated PVC")
+	assert.Equal(t, workspace.Status.DevWorkspaceId, pvc.Labels[constants.DevWorkspaceIDLabel])
+	if assert.Len(t, pvc.OwnerReferences, 1) {
+		assert.Equal(t, "DevWorkspace", pvc.OwnerReferences[0].Kind)
+	}
+
+	err = provisioner.ProvisionStorage(getVolumeTestPodAdditions(), workspace, clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should get a retry error when creating per-workspace PVC")
+
+	podAdditions := getVolumeTestPodAdditions()
+	require.NoError(t, provisioner.ProvisionStorage(podAdditions, workspace, clusterAPI))
+	assert.Equal(t, []corev1.VolumeMount{{Name: dedicatedPVCName, MountPath: "/cache", SubPath: "cache"}}, podAdditions.Containers[0].VolumeMounts)
+	assert.Contains(t, podAdditions.Volumes, corev1.Volume{
+		Name: dedicatedPVCName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: dedicatedPVCName},
+		},
+	})
+
+	workspace.Spec.Template.Components[1].Attributes.
+		PutString(constants.VolumeAccessModeAttribute, "ReadWriteOnce").
+		PutBoolean(constants.DedicatedPVCAttribute, true)
+	err = provisioner.ProvisionStorage(getVolumeTestPodAdditions(), workspace, clusterAPI)
+	if assert.IsType(t, &dwerrors.FailError{}, err, "Should fail if access mode of existing PVC does not match") {
+		assert.Regexp(t, "Access modes of existing PVCs cannot be changed", err.Error())
+	}
+}
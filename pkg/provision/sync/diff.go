@@ -42,6 +42,7 @@ var diffFuncs = map[reflect.Type]diffFunc{
 	reflect.TypeOf(rbacv1.RoleBinding{}):           allDiffFuncs(metadataDiffFunc, basicDiffFunc(rolebindingDiffOpts)),
 	reflect.TypeOf(corev1.ServiceAccount{}):        metadataDiffFunc,
 	reflect.TypeOf(appsv1.Deployment{}):            allDiffFuncs(deploymentDiffFunc, metadataDiffFunc, basicDiffFunc(deploymentDiffOpts)),
+	reflect.TypeOf(appsv1.StatefulSet{}):           allDiffFuncs(statefulSetDiffFunc, metadataDiffFunc, basicDiffFunc(statefulSetDiffOpts)),
 	reflect.TypeOf(corev1.Pod{}):                   allDiffFuncs(podDiffFunc, metadataDiffFunc),
 	reflect.TypeOf(corev1.ConfigMap{}):             allDiffFuncs(metadataDiffFunc, basicDiffFunc(configmapDiffOpts)),
 	reflect.TypeOf(corev1.Secret{}):                allDiffFuncs(metadataDiffFunc, basicDiffFunc(secretDiffOpts)),
@@ -50,6 +51,7 @@ var diffFuncs = map[reflect.Type]diffFunc{
 	reflect.TypeOf(corev1.Service{}):               allDiffFuncs(metadataDiffFunc, serviceDiffFunc),
 	reflect.TypeOf(networkingv1.Ingress{}):         allDiffFuncs(metadataDiffFunc, basicDiffFunc(ingressDiffOpts)),
 	reflect.TypeOf(routev1.Route{}):                allDiffFuncs(metadataDiffFunc, basicDiffFunc(routeDiffOpts)),
+	reflect.TypeOf(networkingv1.NetworkPolicy{}):   allDiffFuncs(metadataDiffFunc, basicDiffFunc(networkPolicyDiffOpts)),
 }
 
 // basicDiffFunc returns a diffFunc that specifies an object needs an update if cmp.Equal fails
@@ -107,6 +109,18 @@ func deploymentDiffFunc(spec, cluster crclient.Object) (delete, update bool) {
 	return false, false
 }
 
+// statefulSetDiffFunc requires a StatefulSet to be recreated if any of its immutable fields differ.
+func statefulSetDiffFunc(spec, cluster crclient.Object) (delete, update bool) {
+	specStatefulSet := spec.(*appsv1.StatefulSet)
+	clusterStatefulSet := cluster.(*appsv1.StatefulSet)
+	if !cmp.Equal(specStatefulSet.Spec.Selector, clusterStatefulSet.Spec.Selector) ||
+		specStatefulSet.Spec.ServiceName != clusterStatefulSet.Spec.ServiceName ||
+		specStatefulSet.Spec.PodManagementPolicy != clusterStatefulSet.Spec.PodManagementPolicy {
+		return true, false
+	}
+	return false, false
+}
+
 func podDiffFunc(spec, cluster crclient.Object) (delete, update bool) {
 	specPod := spec.(*corev1.Pod)
 	clusterPod := cluster.(*corev1.Pod)
@@ -39,6 +39,17 @@ var rolebindingDiffOpts = cmp.Options{
 var deploymentDiffOpts = cmp.Options{
 	cmpopts.IgnoreFields(appsv1.Deployment{}, "TypeMeta", "ObjectMeta", "Status"),
 	cmpopts.IgnoreFields(appsv1.DeploymentSpec{}, "RevisionHistoryLimit", "ProgressDeadlineSeconds"),
+	podTemplateDiffOpts,
+}
+
+var statefulSetDiffOpts = cmp.Options{
+	cmpopts.IgnoreFields(appsv1.StatefulSet{}, "TypeMeta", "ObjectMeta", "Status"),
+	cmpopts.IgnoreFields(appsv1.StatefulSetSpec{}, "RevisionHistoryLimit", "UpdateStrategy", "PersistentVolumeClaimRetentionPolicy"),
+	podTemplateDiffOpts,
+}
+
+// podTemplateDiffOpts are the options used to compare the pod templates of workloads
+var podTemplateDiffOpts = cmp.Options{
 	cmpopts.IgnoreFields(corev1.PodSpec{}, "DNSPolicy", "SchedulerName", "DeprecatedServiceAccount"),
 	cmpopts.IgnoreFields(corev1.Container{}, "TerminationMessagePath", "TerminationMessagePolicy", "ImagePullPolicy"),
 	cmpopts.SortSlices(func(a, b corev1.Container) bool {
@@ -69,6 +80,10 @@ var podDiffOpts = cmp.Options{
 	cmpopts.IgnoreFields(corev1.Pod{}, "TypeMeta", "ObjectMeta", "Status"),
 }
 
+var networkPolicyDiffOpts = cmp.Options{
+	cmpopts.IgnoreFields(networkingv1.NetworkPolicy{}, "TypeMeta", "ObjectMeta", "Status"),
+}
+
 var configmapDiffOpts = cmp.Options{
 	cmpopts.IgnoreFields(corev1.ConfigMap{}, "TypeMeta", "ObjectMeta"),
 }
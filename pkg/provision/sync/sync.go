@@ -27,6 +27,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -60,6 +61,11 @@ func SyncObjectWithCluster(specObj crclient.Object, api ClusterAPI) (crclient.Ob
 		return clusterObj, nil
 	}
 
+	if isControlledByOtherObject(specObj, clusterObj) {
+		// Avoid taking over objects that share the generated name, e.g. due to naming templates set in the config
+		return nil, &UnrecoverableSyncError{fmt.Errorf("%s %s already exists and is controlled by another object", objType.String(), specObj.GetName())}
+	}
+
 	diffFunc := diffFuncs[objType]
 	if diffFunc == nil {
 		return nil, &UnrecoverableSyncError{fmt.Errorf("attempting to sync unrecognized object %s", objType)}
@@ -177,6 +183,17 @@ func updateObjectGeneric(specObj, clusterObj crclient.Object, api ClusterAPI) er
 	}
 }
 
+// isControlledByOtherObject returns whether specObj has a controller reference and clusterObj is controlled by a
+// different object.
+func isControlledByOtherObject(specObj, clusterObj crclient.Object) bool {
+	specController := metav1.GetControllerOf(specObj)
+	clusterController := metav1.GetControllerOf(clusterObj)
+	if specController == nil || clusterController == nil {
+		return false
+	}
+	return specController.UID != clusterController.UID
+}
+
 func isMutableObject(obj crclient.Object) bool {
 	switch obj.(type) {
 	case *corev1.PersistentVolumeClaim:
@@ -187,7 +204,7 @@ func isMutableObject(obj crclient.Object) bool {
 }
 
 func printDiff(specObj, clusterObj crclient.Object, log logr.Logger) {
-	if config.IsSetUp() && config.ExperimentalFeaturesEnabled() {
+	if config.IsSetUp() && config.FeatureEnabled(config.DebugLogging) {
 		var diffOpts cmp.Options
 		switch specObj.(type) {
 		case *rbacv1.Role:
@@ -196,6 +213,8 @@ func printDiff(specObj, clusterObj crclient.Object, log logr.Logger) {
 			diffOpts = rolebindingDiffOpts
 		case *appsv1.Deployment:
 			diffOpts = deploymentDiffOpts
+		case *appsv1.StatefulSet:
+			diffOpts = statefulSetDiffOpts
 		case *corev1.Pod:
 			diffOpts = podDiffOpts
 		case *corev1.ConfigMap:
@@ -206,6 +225,8 @@ func printDiff(specObj, clusterObj crclient.Object, log logr.Logger) {
 			diffOpts = ingressDiffOpts
 		case *routev1.Route:
 			diffOpts = routeDiffOpts
+		case *networkingv1.NetworkPolicy:
+			diffOpts = networkPolicyDiffOpts
 		case *corev1.Secret:
 			log.Info(fmt.Sprintf("Diff: secret %s data upated", specObj.GetName()))
 			return
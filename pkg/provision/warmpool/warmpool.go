@@ -0,0 +1,225 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package warmpool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	warmPoolSyncInterval = 30 * time.Second
+
+	warmPoolStateWarming  = "warming"
+	warmPoolStateReady    = "ready"
+	warmPoolStateAssigned = "assigned"
+)
+
+// PoolManager keeps the warm pools configured in the global DevWorkspaceOperatorConfig's workspace.warmPools field
+// filled. Each pool is kept at its configured number of unassigned DevWorkspaces, which are created from the pool's
+// DevWorkspaceTemplate, started once so that their storage, routing and images are prepared, and then stopped.
+// DevWorkspaces are assigned from a pool by setting their controller.devfile.io/warm-pool-state label to "assigned"
+// and starting them, after which they are replaced in the pool. It is intended to be added to the controller manager.
+type PoolManager struct {
+	// Client is used to manage pooled DevWorkspaces, which are cached by the controller manager
+	Client k8sclient.Client
+	// NonCachingClient is used to read DevWorkspaceTemplates, to avoid starting an informer for them
+	NonCachingClient k8sclient.Client
+	Log              logr.Logger
+}
+
+// NeedLeaderElection ensures pools are only managed by the manager that holds the leader lease.
+func (m *PoolManager) NeedLeaderElection() bool {
+	return true
+}
+
+// Start syncs the configured warm pools periodically until ctx is cancelled.
+func (m *PoolManager) Start(ctx context.Context) error {
+	for {
+		pools := config.GetGlobalConfig().Workspace.WarmPools
+		for _, pool := range pools {
+			if err := m.syncPool(ctx, pool); err != nil {
+				m.Log.Error(err, "Failed to sync warm pool", "pool", pool.Name, "namespace", pool.Namespace)
+			}
+		}
+		if err := m.deleteRemovedPools(ctx, pools); err != nil {
+			m.Log.Error(err, "Failed to clean up removed warm pools")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(warmPoolSyncInterval):
+		}
+	}
+}
+
+// syncPool removes outdated and failed DevWorkspaces from the pool, stops DevWorkspaces that finished warming up and
+// creates or deletes DevWorkspaces until the pool contains the configured number of unassigned DevWorkspaces.
+func (m *PoolManager) syncPool(ctx context.Context, pool controller.WarmPoolConfig) error {
+	if errs := validation.IsDNS1123Label(pool.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid warm pool name %q: %s", pool.Name, strings.Join(errs, ", "))
+	}
+	if pool.Namespace == "" || pool.Template == "" {
+		return fmt.Errorf("warm pool %s must specify a namespace and a template", pool.Name)
+	}
+
+	template := &dw.DevWorkspaceTemplate{}
+	if err := m.NonCachingClient.Get(ctx, types.NamespacedName{Name: pool.Template, Namespace: pool.Namespace}, template); err != nil {
+		return fmt.Errorf("failed to read DevWorkspaceTemplate %s for warm pool: %w", pool.Template, err)
+	}
+	templateGeneration := strconv.FormatInt(template.Generation, 10)
+
+	workspaceList := &dw.DevWorkspaceList{}
+	if err := m.Client.List(ctx, workspaceList, k8sclient.InNamespace(pool.Namespace), k8sclient.MatchingLabels{constants.DevWorkspaceWarmPoolLabel: pool.Name}); err != nil {
+		return err
+	}
+
+	var pooled []dw.DevWorkspace
+	for _, workspace := range workspaceList.Items {
+		workspace := workspace
+		if workspace.DeletionTimestamp != nil || workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel] == warmPoolStateAssigned {
+			continue
+		}
+		if workspace.Annotations[constants.DevWorkspaceWarmPoolTemplateGenerationAnnotation] != templateGeneration || workspace.Status.Phase == dw.DevWorkspaceStatusFailed {
+			m.Log.Info("Replacing DevWorkspace in warm pool", "pool", pool.Name, "devworkspace", workspace.Name, "phase", workspace.Status.Phase)
+			if err := m.deleteWorkspace(ctx, &workspace); err != nil {
+				return err
+			}
+			continue
+		}
+		if workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel] == warmPoolStateWarming && workspace.Status.Phase == dw.DevWorkspaceStatusRunning {
+			if err := m.stopWarmWorkspace(ctx, &workspace); err != nil {
+				return err
+			}
+		}
+		pooled = append(pooled, workspace)
+	}
+
+	if len(pooled) > int(pool.Size) {
+		// Prefer deleting DevWorkspaces that are still warming up, then the most recently created ones.
+		sort.SliceStable(pooled, func(i, j int) bool {
+			iReady := pooled[i].Labels[constants.DevWorkspaceWarmPoolStateLabel] == warmPoolStateReady
+			jReady := pooled[j].Labels[constants.DevWorkspaceWarmPoolStateLabel] == warmPoolStateReady
+			if iReady != jReady {
+				return !iReady
+			}
+			return pooled[j].CreationTimestamp.Before(&pooled[i].CreationTimestamp)
+		})
+		for idx := range pooled[:len(pooled)-int(pool.Size)] {
+			if err := m.deleteWorkspace(ctx, &pooled[idx]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for idx := len(pooled); idx < int(pool.Size); idx++ {
+		workspace := getSpecWorkspace(pool, template, templateGeneration)
+		if err := m.Client.Create(ctx, workspace); err != nil {
+			return err
+		}
+		m.Log.Info("Created DevWorkspace for warm pool", "pool", pool.Name, "devworkspace", workspace.Name)
+	}
+	return nil
+}
+
+// deleteRemovedPools deletes unassigned DevWorkspaces that belong to pools that are no longer configured.
+func (m *PoolManager) deleteRemovedPools(ctx context.Context, pools []controller.WarmPoolConfig) error {
+	configured := map[types.NamespacedName]bool{}
+	for _, pool := range pools {
+		configured[types.NamespacedName{Name: pool.Name, Namespace: pool.Namespace}] = true
+	}
+	workspaceList := &dw.DevWorkspaceList{}
+	if err := m.Client.List(ctx, workspaceList, k8sclient.HasLabels{constants.DevWorkspaceWarmPoolLabel}); err != nil {
+		return err
+	}
+	for _, workspace := range workspaceList.Items {
+		workspace := workspace
+		pool := types.NamespacedName{Name: workspace.Labels[constants.DevWorkspaceWarmPoolLabel], Namespace: workspace.Namespace}
+		if configured[pool] || workspace.DeletionTimestamp != nil || workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel] == warmPoolStateAssigned {
+			continue
+		}
+		m.Log.Info("Deleting DevWorkspace from removed warm pool", "pool", pool.Name, "devworkspace", workspace.Name)
+		if err := m.deleteWorkspace(ctx, &workspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stopWarmWorkspace stops a pooled DevWorkspace that finished starting and marks it as ready to be assigned.
+func (m *PoolManager) stopWarmWorkspace(ctx context.Context, workspace *dw.DevWorkspace) error {
+	workspace.Spec.Started = false
+	workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel] = warmPoolStateReady
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = constants.DevWorkspaceStoppedByWarmPool
+	if err := m.Client.Update(ctx, workspace); err != nil {
+		if k8sErrors.IsConflict(err) {
+			// The DevWorkspace may have been assigned in the meantime; it is checked again on the next sync.
+			return nil
+		}
+		return err
+	}
+	m.Log.Info("DevWorkspace in warm pool is ready", "pool", workspace.Labels[constants.DevWorkspaceWarmPoolLabel], "devworkspace", workspace.Name)
+	return nil
+}
+
+func (m *PoolManager) deleteWorkspace(ctx context.Context, workspace *dw.DevWorkspace) error {
+	// Deleting with a precondition on the resource version avoids deleting a DevWorkspace that was just assigned.
+	err := m.Client.Delete(ctx, workspace, k8sclient.Preconditions{ResourceVersion: &workspace.ResourceVersion})
+	if err != nil && !k8sErrors.IsNotFound(err) && !k8sErrors.IsConflict(err) {
+		return err
+	}
+	return nil
+}
+
+func getSpecWorkspace(pool controller.WarmPoolConfig, template *dw.DevWorkspaceTemplate, templateGeneration string) *dw.DevWorkspace {
+	return &dw.DevWorkspace{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pool.Name + "-",
+			Namespace:    pool.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceWarmPoolLabel:      pool.Name,
+				constants.DevWorkspaceWarmPoolStateLabel: warmPoolStateWarming,
+			},
+			Annotations: map[string]string{
+				constants.DevWorkspaceWarmPoolTemplateGenerationAnnotation: templateGeneration,
+			},
+		},
+		Spec: dw.DevWorkspaceSpec{
+			Started:  true,
+			Template: *template.Spec.DeepCopy(),
+		},
+	}
+}
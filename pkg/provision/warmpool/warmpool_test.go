@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package warmpool
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const testNamespace = "pool-namespace"
+
+var testPool = controller.WarmPoolConfig{
+	Name:      "test-pool",
+	Namespace: testNamespace,
+	Template:  "test-template",
+	Size:      2,
+}
+
+func getTestTemplate() *dw.DevWorkspaceTemplate {
+	return &dw.DevWorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "test-template",
+			Namespace:  testNamespace,
+			Generation: 1,
+		},
+		Spec: dw.DevWorkspaceTemplateSpec{
+			DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+				Components: []dw.Component{{Name: "tools"}},
+			},
+		},
+	}
+}
+
+func getTestPooledWorkspace(name, state string, phase dw.DevWorkspacePhase) *dw.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, phase)
+	workspace.Namespace = testNamespace
+	workspace.Labels = map[string]string{
+		constants.DevWorkspaceWarmPoolLabel:      testPool.Name,
+		constants.DevWorkspaceWarmPoolStateLabel: state,
+	}
+	workspace.Annotations[constants.DevWorkspaceWarmPoolTemplateGenerationAnnotation] = "1"
+	workspace.Spec.Started = state == warmPoolStateWarming
+	workspace.Status.DevWorkspaceId = ""
+	return workspace
+}
+
+func getTestPoolManager(objs ...client.Object) *PoolManager {
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &PoolManager{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Log:              zap.New(),
+	}
+}
+
+func listPooledWorkspaces(t *testing.T, m *PoolManager) []dw.DevWorkspace {
+	workspaceList := &dw.DevWorkspaceList{}
+	if !assert.NoError(t, m.Client.List(context.Background(), workspaceList, client.MatchingLabels{constants.DevWorkspaceWarmPoolLabel: testPool.Name})) {
+		t.FailNow()
+	}
+	return workspaceList.Items
+}
+
+func TestSyncPoolCreatesWorkspaces(t *testing.T) {
+	m := getTestPoolManager(getTestTemplate())
+
+	assert.NoError(t, m.syncPool(context.Background(), testPool))
+	workspaces := listPooledWorkspaces(t, m)
+	if assert.Len(t, workspaces, 2) {
+		for _, workspace := range workspaces {
+			assert.True(t, workspace.Spec.Started, "Pooled DevWorkspaces should be started to warm up")
+			assert.Equal(t, warmPoolStateWarming, workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel])
+			assert.Equal(t, getTestTemplate().Spec, workspace.Spec.Template, "Pooled DevWorkspaces should use template content")
+		}
+	}
+}
+
+func TestSyncPoolStopsRunningWorkspaces(t *testing.T) {
+	m := getTestPoolManager(getTestTemplate(),
+		getTestPooledWorkspace("running", warmPoolStateWarming, dw.DevWorkspaceStatusRunning),
+		getTestPooledWorkspace("starting", warmPoolStateWarming, dw.DevWorkspaceStatusStarting))
+
+	assert.NoError(t, m.syncPool(context.Background(), testPool))
+	assert.Len(t, listPooledWorkspaces(t, m), 2)
+
+	running := &dw.DevWorkspace{}
+	assert.NoError(t, m.Client.Get(context.Background(), types.NamespacedName{Name: "running", Namespace: testNamespace}, running))
+	assert.False(t, running.Spec.Started, "Should stop DevWorkspace once it is running")
+	assert.Equal(t, warmPoolStateReady, running.Labels[constants.DevWorkspaceWarmPoolStateLabel])
+	assert.Equal(t, constants.DevWorkspaceStoppedByWarmPool, running.Annotations[constants.DevWorkspaceStopReasonAnnotation])
+
+	starting := &dw.DevWorkspace{}
+	assert.NoError(t, m.Client.Get(context.Background(), types.NamespacedName{Name: "starting", Namespace: testNamespace}, starting))
+	assert.True(t, starting.Spec.Started, "Should not stop DevWorkspace that is still starting")
+}
+
+func TestSyncPoolReplacesAssignedWorkspaces(t *testing.T) {
+	m := getTestPoolManager(getTestTemplate(),
+		getTestPooledWorkspace("ready", warmPoolStateReady, dw.DevWorkspaceStatusStopped),
+		getTestPooledWorkspace("assigned", warmPoolStateAssigned, dw.DevWorkspaceStatusRunning))
+
+	assert.NoError(t, m.syncPool(context.Background(), testPool))
+	workspaces := listPooledWorkspaces(t, m)
+	assert.Len(t, workspaces, 3, "Should create a DevWorkspace to replace the assigned one")
+	for _, workspace := range workspaces {
+		if workspace.Name == "assigned" {
+			assert.Equal(t, warmPoolStateAssigned, workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel], "Should not modify assigned DevWorkspace")
+		}
+	}
+}
+
+func TestSyncPoolReplacesOutdatedAndFailedWorkspaces(t *testing.T) {
+	outdated := getTestPooledWorkspace("outdated", warmPoolStateReady, dw.DevWorkspaceStatusStopped)
+	outdated.Annotations[constants.DevWorkspaceWarmPoolTemplateGenerationAnnotation] = "0"
+	m := getTestPoolManager(getTestTemplate(),
+		outdated,
+		getTestPooledWorkspace("failed", warmPoolStateWarming, dw.DevWorkspaceStatusFailed))
+
+	assert.NoError(t, m.syncPool(context.Background(), testPool))
+	workspaces := listPooledWorkspaces(t, m)
+	assert.Len(t, workspaces, 2)
+	for _, workspace := range workspaces {
+		assert.NotEqual(t, "outdated", workspace.Name, "Should delete DevWorkspace created from outdated template")
+		assert.NotEqual(t, "failed", workspace.Name, "Should delete failed DevWorkspace")
+	}
+}
+
+func TestSyncPoolShrinksPool(t *testing.T) {
+	m := getTestPoolManager(getTestTemplate(),
+		getTestPooledWorkspace("ready-1", warmPoolStateReady, dw.DevWorkspaceStatusStopped),
+		getTestPooledWorkspace("ready-2", warmPoolStateReady, dw.DevWorkspaceStatusStopped),
+		getTestPooledWorkspace("warming", warmPoolStateWarming, dw.DevWorkspaceStatusStarting))
+
+	assert.NoError(t, m.syncPool(context.Background(), testPool))
+	workspaces := listPooledWorkspaces(t, m)
+	if assert.Len(t, workspaces, 2) {
+		for _, workspace := range workspaces {
+			assert.Equal(t, warmPoolStateReady, workspace.Labels[constants.DevWorkspaceWarmPoolStateLabel], "Should delete warming DevWorkspace first")
+		}
+	}
+}
+
+func TestDeleteRemovedPools(t *testing.T) {
+	removed := getTestPooledWorkspace("removed", warmPoolStateReady, dw.DevWorkspaceStatusStopped)
+	removed.Labels[constants.DevWorkspaceWarmPoolLabel] = "removed-pool"
+	removedAssigned := getTestPooledWorkspace("removed-assigned", warmPoolStateAssigned, dw.DevWorkspaceStatusRunning)
+	removedAssigned.Labels[constants.DevWorkspaceWarmPoolLabel] = "removed-pool"
+	m := getTestPoolManager(removed, removedAssigned,
+		getTestPooledWorkspace("configured", warmPoolStateReady, dw.DevWorkspaceStatusStopped))
+
+	assert.NoError(t, m.deleteRemovedPools(context.Background(), []controller.WarmPoolConfig{testPool}))
+	workspaceList := &dw.DevWorkspaceList{}
+	assert.NoError(t, m.Client.List(context.Background(), workspaceList))
+	var names []string
+	for _, workspace := range workspaceList.Items {
+		names = append(names, workspace.Name)
+	}
+	assert.ElementsMatch(t, []string{"configured", "removed-assigned"}, names, "Should only delete unassigned DevWorkspaces from removed pools")
+}
+
+func TestSyncPoolRequiresTemplate(t *testing.T) {
+	m := getTestPoolManager()
+	assert.Error(t, m.syncPool(context.Background(), testPool))
+	assert.Empty(t, listPooledWorkspaces(t, m))
+
+	invalidPool := testPool
+	invalidPool.Name = "Invalid_Name"
+	assert.Error(t, m.syncPool(context.Background(), invalidPool))
+}
@@ -0,0 +1,273 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	maputils "github.com/devfile/devworkspace-operator/internal/map"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const defaultBackgroundIdleTimeout = 1 * time.Hour
+
+// SplitBackgroundContainers removes containers for components that define the BackgroundComponentAttribute from
+// podAdditions and returns PodAdditions that contain those containers along with the volumes, volumeMounts and pull
+// secrets from podAdditions. Returns nil if the workspace does not define any background components.
+func SplitBackgroundContainers(workspace *dw.DevWorkspaceTemplateSpec, podAdditions *v1alpha1.PodAdditions) *v1alpha1.PodAdditions {
+	backgroundComponents := map[string]bool{}
+	for _, component := range workspace.Components {
+		if component.Container != nil && component.Attributes.GetBoolean(constants.BackgroundComponentAttribute, nil) {
+			backgroundComponents[component.Name] = true
+		}
+	}
+	if len(backgroundComponents) == 0 {
+		return nil
+	}
+
+	backgroundPodAdditions := &v1alpha1.PodAdditions{
+		Volumes:      podAdditions.Volumes,
+		VolumeMounts: podAdditions.VolumeMounts,
+		PullSecrets:  podAdditions.PullSecrets,
+	}
+	var workspaceContainers []corev1.Container
+	for _, container := range podAdditions.Containers {
+		if backgroundComponents[container.Name] {
+			backgroundPodAdditions.Containers = append(backgroundPodAdditions.Containers, container)
+		} else {
+			workspaceContainers = append(workspaceContainers, container)
+		}
+	}
+	podAdditions.Containers = workspaceContainers
+	return backgroundPodAdditions
+}
+
+// SyncBackgroundDeploymentToCluster creates or updates the background deployment for a workspace, which runs the
+// containers in backgroundPodAdditions. If backgroundPodAdditions is nil, any existing background deployment is
+// deleted. Unlike the main workspace deployment, readiness of the background deployment is not checked.
+func SyncBackgroundDeploymentToCluster(
+	workspace *common.DevWorkspaceWithConfig,
+	backgroundPodAdditions *v1alpha1.PodAdditions,
+	pullSecretPodAdditions *v1alpha1.PodAdditions,
+	saName string,
+	clusterAPI sync.ClusterAPI) error {
+
+	if backgroundPodAdditions == nil {
+		_, err := DeleteBackgroundDeployment(clusterAPI.Ctx, workspace, clusterAPI.Client)
+		return err
+	}
+
+	idleTimeout, err := getBackgroundIdleTimeout(&workspace.Spec.Template)
+	if err != nil {
+		return &dwerrors.FailError{Message: "Invalid background component configuration", Err: err}
+	}
+
+	scheduling, err := getPodScheduling(workspace, clusterAPI)
+	if err != nil {
+		return &dwerrors.FailError{Message: "Failed to read pod tolerations, node selector and affinity from namespace", Err: err}
+	}
+
+	podAdditionsList := []v1alpha1.PodAdditions{*backgroundPodAdditions}
+	if pullSecretPodAdditions != nil {
+		podAdditionsList = append(podAdditionsList, *pullSecretPodAdditions)
+	}
+	podAdditions, err := mergePodAdditions(podAdditionsList)
+	if err != nil {
+		return &dwerrors.FailError{Message: "Error while creating background deployment", Err: err}
+	}
+	for idx := range podAdditions.Containers {
+		podAdditions.Containers[idx].VolumeMounts = append(podAdditions.Containers[idx].VolumeMounts, podAdditions.VolumeMounts...)
+	}
+
+	specDeployment := getSpecBackgroundDeployment(workspace, podAdditions, saName, idleTimeout)
+	if err := setPodPriority(workspace, &specDeployment.Spec.Template.Spec); err != nil {
+		return &dwerrors.FailError{Message: "Error while creating background deployment", Err: err}
+	}
+	scheduling.applyTo(&specDeployment.Spec.Template.Spec)
+	applySecurityContextPolicy(workspace, &specDeployment.Spec.Template.Spec)
+	if needPVC, pvcName := needsPVCWorkaround(podAdditions, workspace.Config.Workspace.PVCName); needPVC {
+		// See getSpecDeployment: the workspace subpath must be mounted first to get the correct directory permissions
+		volumeMounts := specDeployment.Spec.Template.Spec.Containers[0].VolumeMounts
+		volumeMounts = append([]corev1.VolumeMount{getWorkspaceSubpathVolumeMount(workspace.Status.DevWorkspaceId, pvcName)}, volumeMounts...)
+		specDeployment.Spec.Template.Spec.Containers[0].VolumeMounts = volumeMounts
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specDeployment, clusterAPI.Scheme); err != nil {
+		return err
+	}
+
+	_, err = sync.SyncObjectWithCluster(specDeployment, clusterAPI)
+	return dwerrors.WrapSyncError(err)
+}
+
+func getSpecBackgroundDeployment(workspace *common.DevWorkspaceWithConfig, podAdditions *v1alpha1.PodAdditions, saName string, idleTimeout time.Duration) *appsv1.Deployment {
+	replicas := int32(1)
+	terminationGracePeriod := int64(10)
+
+	podLabels := map[string]string{
+		constants.DevWorkspaceBackgroundIDLabel: workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:         workspace.Name,
+	}
+	if creator, ok := workspace.Labels[constants.DevWorkspaceCreatorLabel]; ok {
+		podLabels[constants.DevWorkspaceCreatorLabel] = creator
+	}
+	addPodGroupLabel(workspace, podLabels)
+	var podAnnotations map[string]string
+	if restrictedAccess, ok := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; ok {
+		podAnnotations = maputils.Append(podAnnotations, constants.DevWorkspaceRestrictedAccessAnnotation, restrictedAccess)
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.BackgroundDeploymentName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:   workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceNameLabel: workspace.Name,
+			},
+			Annotations: map[string]string{
+				constants.DevWorkspaceBackgroundIdleTimeoutAnnotation: idleTimeout.String(),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					constants.DevWorkspaceBackgroundIDLabel: workspace.Status.DevWorkspaceId,
+				},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: podAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers:                    podAdditions.Containers,
+					ImagePullSecrets:              podAdditions.PullSecrets,
+					Volumes:                       podAdditions.Volumes,
+					RestartPolicy:                 corev1.RestartPolicyAlways,
+					TerminationGracePeriodSeconds: &terminationGracePeriod,
+					SchedulerName:                 workspace.Config.Workspace.SchedulerName,
+					SecurityContext:               workspace.Config.Workspace.PodSecurityContext,
+					ServiceAccountName:            saName,
+					RuntimeClassName:              workspace.Config.Workspace.RuntimeClassName,
+				},
+			},
+		},
+	}
+}
+
+// getBackgroundIdleTimeout returns the longest idle timeout defined by background components in the workspace, or
+// the default timeout if no background component defines one.
+func getBackgroundIdleTimeout(workspace *dw.DevWorkspaceTemplateSpec) (time.Duration, error) {
+	var idleTimeout time.Duration
+	for _, component := range workspace.Components {
+		if component.Container == nil || !component.Attributes.GetBoolean(constants.BackgroundComponentAttribute, nil) {
+			continue
+		}
+		if !component.Attributes.Exists(constants.BackgroundIdleTimeoutAttribute) {
+			continue
+		}
+		var attrErr error
+		timeoutStr := component.Attributes.GetString(constants.BackgroundIdleTimeoutAttribute, &attrErr)
+		if attrErr != nil {
+			return 0, fmt.Errorf("failed to read attribute %s on component %s: %w", constants.BackgroundIdleTimeoutAttribute, component.Name, attrErr)
+		}
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value for attribute %s on component %s: %w", constants.BackgroundIdleTimeoutAttribute, component.Name, err)
+		}
+		if timeout > idleTimeout {
+			idleTimeout = timeout
+		}
+	}
+	if idleTimeout == 0 {
+		return defaultBackgroundIdleTimeout, nil
+	}
+	return idleTimeout, nil
+}
+
+// StopIdleBackgroundDeployment deletes the background deployment of a stopped workspace once the workspace has been
+// stopped for longer than the deployment's idle timeout. If the background deployment should keep running, returns
+// the duration after which it should be checked again.
+func StopIdleBackgroundDeployment(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) (requeueAfter time.Duration, err error) {
+	deployment := &appsv1.Deployment{}
+	deployNN := types.NamespacedName{
+		Name:      common.BackgroundDeploymentName(workspace.Status.DevWorkspaceId),
+		Namespace: workspace.Namespace,
+	}
+	if err := client.Get(ctx, deployNN, deployment); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	idleTimeout, err := time.ParseDuration(deployment.Annotations[constants.DevWorkspaceBackgroundIdleTimeoutAnnotation])
+	if err != nil {
+		idleTimeout = defaultBackgroundIdleTimeout
+	}
+	if remaining := time.Until(getStoppedAt(workspace).Add(idleTimeout)); remaining > 0 {
+		return remaining, nil
+	}
+	_, err = DeleteBackgroundDeployment(ctx, workspace, client)
+	return 0, err
+}
+
+// getStoppedAt returns the time the workspace was stopped, based on its Started condition. If the workspace's
+// Started condition does not reflect it being stopped yet, the current time is returned.
+func getStoppedAt(workspace *common.DevWorkspaceWithConfig) time.Time {
+	startedCondition := conditions.GetConditionByType(workspace.Status.Conditions, conditions.Started)
+	if startedCondition != nil && startedCondition.Status == corev1.ConditionFalse {
+		return startedCondition.LastTransitionTime.Time
+	}
+	return time.Now()
+}
+
+// DeleteBackgroundDeployment deletes the background deployment for the DevWorkspace
+func DeleteBackgroundDeployment(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) (deleted bool, err error) {
+	err = client.Delete(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: workspace.Namespace,
+			Name:      common.BackgroundDeploymentName(workspace.Status.DevWorkspaceId),
+		},
+	})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,191 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getBackgroundTestComponent(name string, background bool, idleTimeout string) dw.Component {
+	component := dw.Component{
+		Name: name,
+		ComponentUnion: dw.ComponentUnion{
+			Container: &dw.ContainerComponent{},
+		},
+	}
+	if background {
+		component.Attributes = attributes.Attributes{}.PutBoolean(constants.BackgroundComponentAttribute, true)
+		if idleTimeout != "" {
+			component.Attributes.PutString(constants.BackgroundIdleTimeoutAttribute, idleTimeout)
+		}
+	}
+	return component
+}
+
+func TestSplitBackgroundContainers(t *testing.T) {
+	workspace := &dw.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+			Components: []dw.Component{
+				getBackgroundTestComponent("tools", false, ""),
+				getBackgroundTestComponent("sync", true, ""),
+			},
+		},
+	}
+	podAdditions := &v1alpha1.PodAdditions{
+		Containers:   []corev1.Container{{Name: "tools"}, {Name: "sync"}},
+		Volumes:      []corev1.Volume{{Name: "projects"}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "projects", MountPath: "/projects"}},
+	}
+
+	backgroundPodAdditions := SplitBackgroundContainers(workspace, podAdditions)
+	if !assert.NotNil(t, backgroundPodAdditions, "Should return pod additions for background components") {
+		return
+	}
+	assert.Equal(t, []corev1.Container{{Name: "tools"}}, podAdditions.Containers, "Should remove background containers from workspace")
+	assert.Equal(t, []corev1.Container{{Name: "sync"}}, backgroundPodAdditions.Containers, "Should move background containers")
+	assert.Equal(t, podAdditions.Volumes, backgroundPodAdditions.Volumes, "Should copy volumes to background pod additions")
+	assert.Equal(t, podAdditions.VolumeMounts, backgroundPodAdditions.VolumeMounts, "Should copy volume mounts to background pod additions")
+
+	workspace.Components = workspace.Components[:1]
+	assert.Nil(t, SplitBackgroundContainers(workspace, podAdditions), "Should return nil when there are no background components")
+}
+
+func TestGetBackgroundIdleTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		components      []dw.Component
+		expectedTimeout time.Duration
+		errRegexp       string
+	}{
+		{
+			name:            "Uses default timeout when not specified",
+			components:      []dw.Component{getBackgroundTestComponent("sync", true, "")},
+			expectedTimeout: defaultBackgroundIdleTimeout,
+		},
+		{
+			name: "Uses longest timeout across background components",
+			components: []dw.Component{
+				getBackgroundTestComponent("sync", true, "30m"),
+				getBackgroundTestComponent("build", true, "3h"),
+			},
+			expectedTimeout: 3 * time.Hour,
+		},
+		{
+			name:       "Returns error for invalid timeout",
+			components: []dw.Component{getBackgroundTestComponent("sync", true, "forever")},
+			errRegexp:  "invalid value for attribute controller.devfile.io/background-idle-timeout on component sync",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &dw.DevWorkspaceTemplateSpec{
+				DevWorkspaceTemplateSpecContent: dw.DevWorkspaceTemplateSpecContent{
+					Components: tt.components,
+				},
+			}
+			timeout, err := getBackgroundIdleTimeout(workspace)
+			if tt.errRegexp != "" {
+				if assert.Error(t, err, "Should return error") {
+					assert.Regexp(t, tt.errRegexp, err.Error(), "Error message should match")
+				}
+				return
+			}
+			assert.NoError(t, err, "Should not return error")
+			assert.Equal(t, tt.expectedTimeout, timeout)
+		})
+	}
+}
+
+func TestStopIdleBackgroundDeployment(t *testing.T) {
+	tests := []struct {
+		name          string
+		stoppedFor    time.Duration
+		expectDeleted bool
+	}{
+		{
+			name:          "Keeps background deployment running until idle timeout",
+			stoppedFor:    10 * time.Minute,
+			expectDeleted: false,
+		},
+		{
+			name:          "Deletes background deployment after idle timeout",
+			stoppedFor:    2 * time.Hour,
+			expectDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &common.DevWorkspaceWithConfig{
+				DevWorkspace: &dw.DevWorkspace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-workspace",
+						Namespace: "test-namespace",
+					},
+					Status: dw.DevWorkspaceStatus{
+						DevWorkspaceId: "test-id",
+						Conditions: []dw.DevWorkspaceCondition{
+							{
+								Type:               conditions.Started,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: metav1.NewTime(time.Now().Add(-tt.stoppedFor)),
+							},
+						},
+					},
+				},
+			}
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.BackgroundDeploymentName("test-id"),
+					Namespace: "test-namespace",
+					Annotations: map[string]string{
+						constants.DevWorkspaceBackgroundIdleTimeoutAnnotation: "1h",
+					},
+				},
+			}
+			client := fake.NewClientBuilder().WithObjects(deployment).Build()
+
+			requeueAfter, err := StopIdleBackgroundDeployment(context.Background(), workspace, client)
+			if !assert.NoError(t, err, "Should not return error") {
+				return
+			}
+			err = client.Get(context.Background(), types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, &appsv1.Deployment{})
+			if tt.expectDeleted {
+				assert.True(t, k8sErrors.IsNotFound(err), "Should delete background deployment")
+				assert.Zero(t, requeueAfter, "Should not requeue after deleting background deployment")
+			} else {
+				assert.NoError(t, err, "Should not delete background deployment")
+				assert.InDelta(t, float64(50*time.Minute), float64(requeueAfter), float64(time.Minute), "Should requeue when idle timeout expires")
+			}
+		})
+	}
+}
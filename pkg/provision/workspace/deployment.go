@@ -25,7 +25,6 @@ import (
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
 	"github.com/devfile/devworkspace-operator/pkg/library/overrides"
 	"github.com/devfile/devworkspace-operator/pkg/library/status"
-	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
 
 	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
@@ -39,6 +38,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
@@ -49,14 +49,14 @@ func SyncDeploymentToCluster(
 	saName string,
 	clusterAPI sync.ClusterAPI) error {
 
-	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, clusterAPI)
+	scheduling, err := getPodScheduling(workspace, clusterAPI)
 	if err != nil {
-		return &dwerrors.FailError{Message: "Failed to read pod tolerations and node selector from namespace", Err: err}
+		return &dwerrors.FailError{Message: "Failed to read pod tolerations, node selector and affinity from namespace", Err: err}
 	}
 
 	// [design] we have to pass components and routing pod additions separately because we need mountsources from each
 	// component.
-	specDeployment, err := getSpecDeployment(workspace, podAdditions, saName, podTolerations, nodeSelector, clusterAPI.Scheme)
+	specDeployment, err := getSpecDeployment(workspace, podAdditions, saName, scheduling, clusterAPI.Scheme)
 	if err != nil {
 		return &dwerrors.FailError{Message: "Error while creating workspace deployment", Err: err}
 	}
@@ -65,6 +65,17 @@ func SyncDeploymentToCluster(
 		return nil
 	}
 
+	if UsesStatefulSet(workspace) {
+		return syncStatefulSetToCluster(workspace, specDeployment, clusterAPI)
+	}
+	if err := deletePreviousWorkload(workspace, &appsv1.StatefulSet{}, clusterAPI); err != nil {
+		return err
+	}
+
+	if err := reconcileLegacyDeployment(workspace, specDeployment, clusterAPI); err != nil {
+		return err
+	}
+
 	clusterObj, err := sync.SyncObjectWithCluster(specDeployment, clusterAPI)
 	if err != nil {
 		return dwerrors.WrapSyncError(err)
@@ -77,63 +88,95 @@ func SyncDeploymentToCluster(
 		if !deploymentHealthy {
 			return &dwerrors.FailError{Message: deploymentErrMsg}
 		}
-
-		workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
-		ignoredEvents := workspace.Config.Workspace.IgnoredUnrecoverableEvents
-		failureMsg, checkErr := status.CheckPodsState(workspace.Status.DevWorkspaceId, workspace.Namespace, workspaceIDLabel, ignoredEvents, clusterAPI)
-		if checkErr != nil {
+		if err := checkWorkspacePodsState(workspace, clusterAPI); err != nil {
 			return err
 		}
-		if failureMsg != "" {
-			return &dwerrors.FailError{Message: failureMsg}
-		}
-
 		return &dwerrors.RetryError{Message: "Deployment is not ready"}
 	}
 
 	return nil
 }
 
-// DeleteWorkspaceDeployment deletes the deployment for the DevWorkspace
+// checkWorkspacePodsState returns a FailError if the workspace's pods are in an unrecoverable state.
+func checkWorkspacePodsState(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
+	ignoredEvents := workspace.Config.Workspace.IgnoredUnrecoverableEvents
+	failureMsg, checkErr := status.CheckPodsState(common.ServiceName(workspace.Status.DevWorkspaceId, workspace.Annotations), workspace.Namespace, workspaceIDLabel, ignoredEvents, clusterAPI)
+	if checkErr != nil {
+		return checkErr
+	}
+	if failureMsg != "" {
+		return &dwerrors.FailError{Message: failureMsg}
+	}
+	return nil
+}
+
+// DeleteWorkspaceDeployment deletes the deployment (or StatefulSet) for the DevWorkspace
 func DeleteWorkspaceDeployment(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) (wait bool, err error) {
-	err = client.Delete(ctx, &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: workspace.Namespace,
-			Name:      common.DeploymentName(workspace.Status.DevWorkspaceId),
-		},
-	})
-	if err != nil {
-		if k8sErrors.IsNotFound(err) {
-			return false, nil
+	for _, workload := range getWorkspaceWorkloads(workspace) {
+		err = client.Delete(ctx, workload)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
 		}
-		return false, err
+		wait = true
 	}
-	return true, nil
+	return wait, nil
 }
 
-// ScaleDeploymentToZero scales the cluster deployment to zero
+// ScaleDeploymentToZero scales the cluster deployment (or StatefulSet) to zero
 func ScaleDeploymentToZero(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) error {
 	patch := []byte(`{"spec":{"replicas": 0}}`)
-	err := client.Patch(ctx, &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: workspace.Namespace,
-			Name:      common.DeploymentName(workspace.Status.DevWorkspaceId),
-		},
-	}, k8sclient.RawPatch(types.StrategicMergePatchType, patch))
+	for _, workload := range getWorkspaceWorkloads(workspace) {
+		err := client.Patch(ctx, workload, k8sclient.RawPatch(types.StrategicMergePatchType, patch))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
 
-	if err != nil && !k8sErrors.IsNotFound(err) {
-		return err
+// GetWorkspaceReplicas returns the desired and current number of replicas of the DevWorkspace's deployment or, if
+// the DevWorkspace is run as a StatefulSet, its StatefulSet. Returns found=false if neither exists on the cluster.
+func GetWorkspaceReplicas(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) (replicas *int32, currentReplicas int32, found bool, err error) {
+	workloadNN := types.NamespacedName{Name: common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations), Namespace: workspace.Namespace}
+	deployment := &appsv1.Deployment{}
+	err = client.Get(ctx, workloadNN, deployment)
+	if err == nil {
+		return deployment.Spec.Replicas, deployment.Status.Replicas, true, nil
+	} else if !k8sErrors.IsNotFound(err) {
+		return nil, 0, false, err
+	}
+	statefulSet := &appsv1.StatefulSet{}
+	err = client.Get(ctx, workloadNN, statefulSet)
+	if err == nil {
+		return statefulSet.Spec.Replicas, statefulSet.Status.Replicas, true, nil
+	} else if !k8sErrors.IsNotFound(err) {
+		return nil, 0, false, err
 	}
+	return nil, 0, false, nil
+}
 
-	return nil
+// getWorkspaceWorkloads returns the Deployment and StatefulSet that may be used to run the DevWorkspace's pod, with
+// only their name and namespace set.
+func getWorkspaceWorkloads(workspace *common.DevWorkspaceWithConfig) []k8sclient.Object {
+	objectMeta := metav1.ObjectMeta{
+		Namespace: workspace.Namespace,
+		Name:      common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations),
+	}
+	return []k8sclient.Object{
+		&appsv1.Deployment{ObjectMeta: objectMeta},
+		&appsv1.StatefulSet{ObjectMeta: objectMeta},
+	}
 }
 
 func getSpecDeployment(
 	workspace *common.DevWorkspaceWithConfig,
 	podAdditionsList []v1alpha1.PodAdditions,
 	saName string,
-	podTolerations []corev1.Toleration,
-	nodeSelector map[string]string,
+	scheduling *podScheduling,
 	scheme *runtime.Scheme) (*appsv1.Deployment, error) {
 	replicas := int32(1)
 	terminationGracePeriod := int64(10)
@@ -159,14 +202,9 @@ func getSpecDeployment(
 		return nil, err
 	}
 
-	deploymentStrategy := appsv1.DeploymentStrategy{
-		Type: workspace.Config.Workspace.DeploymentStrategy,
-	}
-	if workspace.Config.Workspace.DeploymentStrategy == appsv1.RollingUpdateDeploymentStrategyType {
-		deploymentStrategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
-			MaxUnavailable: &constants.RollingUpdateMaxUnavailable,
-			MaxSurge:       &constants.RollingUpdateMaximumSurge,
-		}
+	deploymentStrategy, err := getDeploymentStrategy(workspace)
+	if err != nil {
+		return nil, err
 	}
 	progressDeadlineSeconds, err := getProgressDeadlineSeconds(workspace.Config)
 	if err != nil {
@@ -175,7 +213,7 @@ func getSpecDeployment(
 
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        common.DeploymentName(workspace.Status.DevWorkspaceId),
+			Name:        common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations),
 			Namespace:   workspace.Namespace,
 			Labels:      labels,
 			Annotations: annotations,
@@ -209,13 +247,23 @@ func getSpecDeployment(
 					SchedulerName:                 workspace.Config.Workspace.SchedulerName,
 					SecurityContext:               workspace.Config.Workspace.PodSecurityContext,
 					ServiceAccountName:            saName,
-					AutomountServiceAccountToken:  nil,
+					AutomountServiceAccountToken:  getAutomountServiceAccountToken(workspace),
 					RuntimeClassName:              workspace.Config.Workspace.RuntimeClassName,
 				},
 			},
 		},
 	}
 
+	if err := setPodPriority(workspace, &deployment.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := addSharedMemoryVolume(workspace, &deployment.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+	if err := setPodSysctls(workspace, &deployment.Spec.Template.Spec); err != nil {
+		return nil, err
+	}
+
 	if overrides.NeedsPodOverrides(workspace) {
 		patchedDeployment, err := overrides.ApplyPodOverrides(workspace, deployment)
 		if err != nil {
@@ -224,12 +272,11 @@ func getSpecDeployment(
 		deployment = patchedDeployment
 	}
 
-	if len(podTolerations) > 0 {
-		deployment.Spec.Template.Spec.Tolerations = podTolerations
-	}
-	if len(nodeSelector) > 0 {
-		deployment.Spec.Template.Spec.NodeSelector = nodeSelector
-	}
+	scheduling.applyTo(&deployment.Spec.Template.Spec)
+	applyGPUProfile(workspace, &deployment.Spec.Template.Spec)
+	applyStandbyNodeAffinity(workspace, &deployment.Spec.Template.Spec)
+	applySecurityContextPolicy(workspace, &deployment.Spec.Template.Spec)
+	addPodGroupLabel(workspace, deployment.Spec.Template.Labels)
 	if workspace.Spec.Template.Attributes.Exists(constants.RuntimeClassNameAttribute) {
 		runtimeClassName := workspace.Spec.Template.Attributes.GetString(constants.RuntimeClassNameAttribute, nil)
 		if runtimeClassName != "" {
@@ -267,6 +314,22 @@ func getSpecDeployment(
 		deployment.Spec.Template.Annotations = maputils.Append(deployment.Spec.Template.Annotations, constants.DevWorkspaceRestrictedAccessAnnotation, restrictedAccess)
 	}
 
+	environment, err := GetRecordedEnvironment(workspace.DevWorkspace)
+	if err != nil {
+		return nil, err
+	}
+	if environment != nil {
+		deployment.Spec.Template.Annotations = maputils.Append(deployment.Spec.Template.Annotations, constants.DevWorkspaceEnvironmentHashAnnotation, environment.Hash)
+	}
+
+	if commit, ok := workspace.Annotations[constants.DevWorkspaceGitWebhookCommitAnnotation]; ok {
+		deployment.Spec.Template.Annotations = maputils.Append(deployment.Spec.Template.Annotations, constants.DevWorkspaceGitWebhookCommitAnnotation, commit)
+	}
+
+	if restartedAt, ok := workspace.Annotations[constants.DevWorkspaceRestartedAtAnnotation]; ok {
+		deployment.Spec.Template.Annotations = maputils.Append(deployment.Spec.Template.Annotations, constants.DevWorkspaceRestartedAtAnnotation, restartedAt)
+	}
+
 	err = controllerutil.SetControllerReference(workspace.DevWorkspace, deployment, scheme)
 	if err != nil {
 		return nil, err
@@ -275,6 +338,38 @@ func getSpecDeployment(
 	return deployment, nil
 }
 
+// getDeploymentStrategy returns the strategy used to replace a DevWorkspace's pod when its deployment is updated, or
+// when it is restarted. The strategy from the DeploymentStrategyAttribute is used if present; otherwise the strategy
+// from the DevWorkspaceOperatorConfig is used.
+func getDeploymentStrategy(workspace *common.DevWorkspaceWithConfig) (appsv1.DeploymentStrategy, error) {
+	strategyType := workspace.Config.Workspace.DeploymentStrategy
+	if workspace.Spec.Template.Attributes.Exists(constants.DeploymentStrategyAttribute) {
+		var attrErr error
+		attrValue := workspace.Spec.Template.Attributes.GetString(constants.DeploymentStrategyAttribute, &attrErr)
+		if attrErr != nil {
+			return appsv1.DeploymentStrategy{}, fmt.Errorf("failed to read attribute %s: %w", constants.DeploymentStrategyAttribute, attrErr)
+		}
+		switch appsv1.DeploymentStrategyType(attrValue) {
+		case appsv1.RecreateDeploymentStrategyType, appsv1.RollingUpdateDeploymentStrategyType:
+			strategyType = appsv1.DeploymentStrategyType(attrValue)
+		default:
+			return appsv1.DeploymentStrategy{}, fmt.Errorf("invalid value %q for attribute %s: must be %q or %q", attrValue,
+				constants.DeploymentStrategyAttribute, appsv1.RecreateDeploymentStrategyType, appsv1.RollingUpdateDeploymentStrategyType)
+		}
+	}
+
+	deploymentStrategy := appsv1.DeploymentStrategy{
+		Type: strategyType,
+	}
+	if strategyType == appsv1.RollingUpdateDeploymentStrategyType {
+		deploymentStrategy.RollingUpdate = &appsv1.RollingUpdateDeployment{
+			MaxUnavailable: &constants.RollingUpdateMaxUnavailable,
+			MaxSurge:       &constants.RollingUpdateMaximumSurge,
+		}
+	}
+	return deploymentStrategy, nil
+}
+
 // Returns the ProgressDeadlineSeconds to use for workspace deployments as an int32.
 // The ProgressDeadLineSeconds returned is the same length of time as DWOC's workspace.ProgressTimeout.
 // Returns an error if ProgressTimeout could not be properly parsed,
@@ -395,3 +490,13 @@ func getAdditionalDeploymentAnnotations(workspace *common.DevWorkspaceWithConfig
 
 	return annotations, nil
 }
+
+// getAutomountServiceAccountToken returns whether the ServiceAccount token should be mounted in the workspace pod. If
+// the Kubernetes API proxy is enabled, the token is only mounted in the proxy sidecar; otherwise, the ServiceAccount's
+// setting is used.
+func getAutomountServiceAccountToken(workspace *common.DevWorkspaceWithConfig) *bool {
+	if IsKubernetesAPIProxyEnabled(workspace) {
+		return pointer.Bool(false)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestGetDeploymentStrategy(t *testing.T) {
+	workspaceConfig := &v1alpha1.WorkspaceConfig{DeploymentStrategy: appsv1.RecreateDeploymentStrategyType}
+
+	tests := []struct {
+		name             string
+		attributes       attributes.Attributes
+		expectedStrategy appsv1.DeploymentStrategyType
+		expectedErr      string
+	}{
+		{
+			name:             "Uses strategy from config",
+			expectedStrategy: appsv1.RecreateDeploymentStrategyType,
+		},
+		{
+			name:             "Attribute overrides config",
+			attributes:       attributes.Attributes{}.PutString(constants.DeploymentStrategyAttribute, "RollingUpdate"),
+			expectedStrategy: appsv1.RollingUpdateDeploymentStrategyType,
+		},
+		{
+			name:        "Invalid attribute",
+			attributes:  attributes.Attributes{}.PutString(constants.DeploymentStrategyAttribute, "BlueGreen"),
+			expectedErr: `invalid value "BlueGreen" for attribute controller.devfile.io/deployment-strategy: must be "Recreate" or "RollingUpdate"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getPriorityTestWorkspace(workspaceConfig, tt.attributes)
+			strategy, err := getDeploymentStrategy(workspace)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStrategy, strategy.Type)
+			if tt.expectedStrategy == appsv1.RollingUpdateDeploymentStrategyType {
+				assert.NotNil(t, strategy.RollingUpdate)
+			} else {
+				assert.Nil(t, strategy.RollingUpdate)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/home"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	dotfilesRepositoryKey    = "repository"
+	dotfilesRefKey           = "ref"
+	dotfilesInstallScriptKey = "installScript"
+)
+
+// ProvisionDotfiles adds an init container to the workspace pod that installs dotfiles from a git repository into the
+// persistent home directory. The repository is read from the DotfilesRepositoryAttribute, or from a secret or configmap
+// in the workspace's namespace. Nothing is added if dotfiles are disabled or if no repository is configured. If a
+// repository is configured but cannot be used, a WarningError is returned. The environment variables in cloneEnv (e.g.
+// proxy settings) are added to the init container so that it can clone the repository.
+func ProvisionDotfiles(workspace *common.DevWorkspaceWithConfig, podAdditions *v1alpha1.PodAdditions, cloneEnv []corev1.EnvVar, clusterAPI sync.ClusterAPI) error {
+	dotfilesConfig := workspace.Config.Workspace.Dotfiles
+	if dotfilesConfig == nil || dotfilesConfig.Enable == nil || !*dotfilesConfig.Enable {
+		return nil
+	}
+
+	sourceEnv, err := getDotfilesSourceEnv(workspace, clusterAPI)
+	if err != nil || sourceEnv == nil {
+		return err
+	}
+	if !usesPersistentHome(podAdditions.Containers) {
+		return &dwerrors.WarningError{
+			Message: "Dotfiles are not installed: the home directory is not persisted for this workspace",
+		}
+	}
+
+	image := dotfilesConfig.Image
+	if image == "" {
+		image = workspace.Config.Workspace.ProjectCloneConfig.Image
+	}
+	if image == "" {
+		image = images.GetProjectCloneImage()
+	}
+	if image == "" {
+		return &dwerrors.WarningError{
+			Message: "Dotfiles are not installed: no image is configured for installing dotfiles",
+		}
+	}
+	pullPolicy := corev1.PullPolicy(workspace.Config.Workspace.ImagePullPolicy)
+	if workspace.Config.Workspace.ProjectCloneConfig.ImagePullPolicy != "" {
+		pullPolicy = workspace.Config.Workspace.ProjectCloneConfig.ImagePullPolicy
+	}
+
+	container, volume, err := home.GetDotfilesInitContainer(home.DotfilesOptions{
+		Image:      image,
+		PullPolicy: pullPolicy,
+		Resources:  workspace.Config.Workspace.ProjectCloneConfig.Resources,
+		Env:        append(sourceEnv, cloneEnv...),
+	})
+	if err != nil {
+		return &dwerrors.FailError{Message: "Failed to set up dotfiles init container", Err: err}
+	}
+	podAdditions.InitContainers = append(podAdditions.InitContainers, *container)
+	podAdditions.Volumes = append(podAdditions.Volumes, *volume)
+	return nil
+}
+
+// getDotfilesSourceEnv returns the environment variables that configure the dotfiles repository for the dotfiles init
+// container. The DotfilesRepositoryAttribute takes precedence over a secret in the workspace's namespace, which takes
+// precedence over a configmap. Values from a secret or configmap are referenced rather than copied, so that they are
+// not exposed in the workspace's deployment. Returns nil if no repository is configured.
+func getDotfilesSourceEnv(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) ([]corev1.EnvVar, error) {
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.DotfilesRepositoryAttribute) {
+		var err error
+		repository := attributes.GetString(constants.DotfilesRepositoryAttribute, &err)
+		if err != nil || repository == "" {
+			return nil, &dwerrors.WarningError{
+				Message: "Dotfiles are not installed: attribute " + constants.DotfilesRepositoryAttribute + " must be a non-empty string",
+			}
+		}
+		return []corev1.EnvVar{{Name: "DOTFILES_REPOSITORY", Value: repository}}, nil
+	}
+
+	namespacedName := types.NamespacedName{Name: constants.DotfilesConfigName, Namespace: workspace.Namespace}
+	secret := &corev1.Secret{}
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, namespacedName, secret)
+	switch {
+	case err == nil:
+		if len(secret.Data[dotfilesRepositoryKey]) == 0 {
+			return nil, dotfilesMissingRepositoryWarning("secret")
+		}
+		return getDotfilesRefEnv(func(key string, optional bool) *corev1.EnvVarSource {
+			return &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  key,
+					Optional:             &optional,
+				},
+			}
+		}), nil
+	case !k8sErrors.IsNotFound(err):
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, namespacedName, configMap)
+	switch {
+	case err == nil:
+		if configMap.Data[dotfilesRepositoryKey] == "" {
+			return nil, dotfilesMissingRepositoryWarning("configmap")
+		}
+		return getDotfilesRefEnv(func(key string, optional bool) *corev1.EnvVarSource {
+			return &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+					Key:                  key,
+					Optional:             &optional,
+				},
+			}
+		}), nil
+	case k8sErrors.IsNotFound(err):
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+func getDotfilesRefEnv(valueFrom func(key string, optional bool) *corev1.EnvVarSource) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "DOTFILES_REPOSITORY", ValueFrom: valueFrom(dotfilesRepositoryKey, false)},
+		{Name: "DOTFILES_REF", ValueFrom: valueFrom(dotfilesRefKey, true)},
+		{Name: "DOTFILES_INSTALL_SCRIPT", ValueFrom: valueFrom(dotfilesInstallScriptKey, true)},
+	}
+}
+
+func dotfilesMissingRepositoryWarning(kind string) error {
+	return &dwerrors.WarningError{
+		Message: "Dotfiles are not installed: " + kind + " " + constants.DotfilesConfigName + " does not define key " + dotfilesRepositoryKey,
+	}
+}
+
+// usesPersistentHome returns whether any of the containers mounts the persistent home volume
+func usesPersistentHome(containers []corev1.Container) bool {
+	for _, container := range containers {
+		for _, volumeMount := range container.VolumeMounts {
+			if volumeMount.Name == constants.HomeVolumeName {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/home"
+)
+
+func getDotfilesTestWorkspace(enable bool, workspaceAttributes attributes.Attributes) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Spec.Template.Attributes = workspaceAttributes
+	workspace.Config.Workspace.ImagePullPolicy = "Always"
+	workspace.Config.Workspace.Dotfiles = &v1alpha1.DotfilesConfig{Enable: pointer.Bool(enable)}
+	workspace.Config.Workspace.ProjectCloneConfig = &v1alpha1.ProjectCloneConfig{
+		Image: "project-clone:latest",
+	}
+	return workspace
+}
+
+func getDotfilesTestPodAdditions(persistHome bool) *v1alpha1.PodAdditions {
+	container := corev1.Container{Name: "tools"}
+	if persistHome {
+		container.VolumeMounts = []corev1.VolumeMount{{Name: constants.HomeVolumeName, MountPath: constants.HomeUserDirectory}}
+	}
+	return &v1alpha1.PodAdditions{
+		Containers:     []corev1.Container{container},
+		InitContainers: []corev1.Container{{Name: constants.HomeInitComponentName}},
+	}
+}
+
+func TestProvisionDotfiles(t *testing.T) {
+	dotfilesSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.DotfilesConfigName, Namespace: "test-namespace"},
+		Data:       map[string][]byte{"repository": []byte("https://github.com/user/secret-dotfiles.git")},
+	}
+	dotfilesConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.DotfilesConfigName, Namespace: "test-namespace"},
+		Data:       map[string]string{"repository": "https://github.com/user/configmap-dotfiles.git", "ref": "main"},
+	}
+	repositoryAttribute := attributes.Attributes{}.PutString(constants.DotfilesRepositoryAttribute, "https://github.com/user/dotfiles.git")
+
+	tests := []struct {
+		name               string
+		enable             bool
+		attributes         attributes.Attributes
+		objs               []client.Object
+		expectedRepository string
+		expectedSource     string
+	}{
+		{
+			name:       "Does nothing when disabled",
+			attributes: repositoryAttribute,
+			objs:       []client.Object{dotfilesSecret},
+		},
+		{
+			name:   "Does nothing when no repository is configured",
+			enable: true,
+		},
+		{
+			name:               "Uses repository from attribute",
+			enable:             true,
+			attributes:         repositoryAttribute,
+			objs:               []client.Object{dotfilesSecret, dotfilesConfigMap},
+			expectedRepository: "https://github.com/user/dotfiles.git",
+		},
+		{
+			name:           "Uses repository from secret",
+			enable:         true,
+			objs:           []client.Object{dotfilesSecret, dotfilesConfigMap},
+			expectedSource: "secret",
+		},
+		{
+			name:           "Uses repository from configmap",
+			enable:         true,
+			objs:           []client.Object{dotfilesConfigMap},
+			expectedSource: "configmap",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getDotfilesTestWorkspace(tt.enable, tt.attributes)
+			podAdditions := getDotfilesTestPodAdditions(true)
+			cloneEnv := []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}}
+			err := ProvisionDotfiles(workspace, podAdditions, cloneEnv, getProjectBackupTestClusterAPI(t, tt.objs...))
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tt.expectedRepository == "" && tt.expectedSource == "" {
+				assert.Equal(t, getDotfilesTestPodAdditions(true), podAdditions, "Should not modify pod additions")
+				return
+			}
+			if !assert.Len(t, podAdditions.InitContainers, 2) || !assert.Len(t, podAdditions.Volumes, 1) {
+				return
+			}
+			dotfiles := podAdditions.InitContainers[1]
+			assert.Equal(t, home.DotfilesContainerName, dotfiles.Name, "Should install dotfiles after persistent home is initialized")
+			assert.Equal(t, "project-clone:latest", dotfiles.Image, "Should default to project clone image")
+			assert.Equal(t, corev1.PullAlways, dotfiles.ImagePullPolicy)
+			assert.Equal(t, "http://proxy:3128", getEnvValue(dotfiles, "HTTPS_PROXY"), "Should add clone environment variables")
+			assert.Contains(t, dotfiles.VolumeMounts, corev1.VolumeMount{Name: constants.HomeVolumeName, MountPath: constants.HomeUserDirectory})
+			if tt.expectedRepository != "" {
+				assert.Equal(t, tt.expectedRepository, getEnvValue(dotfiles, "DOTFILES_REPOSITORY"))
+				return
+			}
+			for _, env := range dotfiles.Env {
+				if env.Name != "DOTFILES_REPOSITORY" && env.Name != "DOTFILES_REF" && env.Name != "DOTFILES_INSTALL_SCRIPT" {
+					continue
+				}
+				if !assert.NotNil(t, env.ValueFrom, "Should reference %s rather than copy its values", tt.expectedSource) {
+					continue
+				}
+				switch tt.expectedSource {
+				case "secret":
+					assert.Equal(t, constants.DotfilesConfigName, env.ValueFrom.SecretKeyRef.Name)
+				case "configmap":
+					assert.Equal(t, constants.DotfilesConfigName, env.ValueFrom.ConfigMapKeyRef.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestProvisionDotfilesWarnings(t *testing.T) {
+	tests := []struct {
+		name        string
+		persistHome bool
+		attributes  attributes.Attributes
+		objs        []client.Object
+	}{
+		{
+			name:       "Home directory is not persisted",
+			attributes: attributes.Attributes{}.PutString(constants.DotfilesRepositoryAttribute, "https://github.com/user/dotfiles.git"),
+		},
+		{
+			name:        "Empty repository attribute",
+			persistHome: true,
+			attributes:  attributes.Attributes{}.PutString(constants.DotfilesRepositoryAttribute, ""),
+		},
+		{
+			name:        "Secret does not define repository",
+			persistHome: true,
+			objs: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: constants.DotfilesConfigName, Namespace: "test-namespace"},
+				Data:       map[string][]byte{"ref": []byte("main")},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podAdditions := getDotfilesTestPodAdditions(tt.persistHome)
+			err := ProvisionDotfiles(getDotfilesTestWorkspace(true, tt.attributes), podAdditions, nil, getProjectBackupTestClusterAPI(t, tt.objs...))
+			assert.IsType(t, &dwerrors.WarningError{}, err)
+			assert.Equal(t, getDotfilesTestPodAdditions(tt.persistHome), podAdditions, "Should not add dotfiles init container")
+		})
+	}
+}
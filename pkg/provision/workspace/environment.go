@@ -0,0 +1,112 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// Environment describes the proxy configuration and trusted CA certificates used by a DevWorkspace's pod.
+type Environment struct {
+	// Hash identifies the proxy configuration and the contents of the trusted CA certificates.
+	Hash string `json:"hash"`
+	// Proxy is the proxy configuration used for the DevWorkspace.
+	Proxy *v1alpha1.Proxy `json:"proxy,omitempty"`
+}
+
+type certificateData struct {
+	Name string            `json:"name"`
+	Data map[string]string `json:"data"`
+}
+
+// GetCurrentEnvironment returns the environment a DevWorkspace would use if it were started now. Trusted CA
+// certificates are read from configmaps in the DevWorkspace's namespace that have either the DevWorkspaceGitTLSLabel
+// or the OpenShiftTrustedCABundleLabel.
+func GetCurrentEnvironment(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*Environment, error) {
+	var proxyConfig *v1alpha1.Proxy
+	if workspace.Config.Routing != nil {
+		proxyConfig = workspace.Config.Routing.ProxyConfig
+	}
+
+	var certificates []certificateData
+	for _, label := range []string{constants.DevWorkspaceGitTLSLabel, constants.OpenShiftTrustedCABundleLabel} {
+		configmaps := &corev1.ConfigMapList{}
+		if err := clusterAPI.Client.List(clusterAPI.Ctx, configmaps, k8sclient.InNamespace(workspace.Namespace), k8sclient.MatchingLabels{
+			label: "true",
+		}); err != nil {
+			return nil, err
+		}
+		for _, cm := range configmaps.Items {
+			certificates = append(certificates, certificateData{Name: cm.Name, Data: cm.Data})
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].Name < certificates[j].Name
+	})
+
+	// Maps are serialized with sorted keys, so the hash does not depend on ordering
+	hashInput, err := json.Marshal(struct {
+		Proxy        *v1alpha1.Proxy   `json:"proxy,omitempty"`
+		Certificates []certificateData `json:"certificates,omitempty"`
+	}{proxyConfig, certificates})
+	if err != nil {
+		return nil, err
+	}
+	return &Environment{
+		Hash:  fmt.Sprintf("%x", sha256.Sum256(hashInput))[:16],
+		Proxy: proxyConfig.DeepCopy(),
+	}, nil
+}
+
+// GetRecordedEnvironment returns the environment stored in the DevWorkspaceEnvironmentAnnotation of a DevWorkspace,
+// or nil if the annotation is not set.
+func GetRecordedEnvironment(workspace *dw.DevWorkspace) (*Environment, error) {
+	annotation, ok := workspace.Annotations[constants.DevWorkspaceEnvironmentAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	environment := &Environment{}
+	if err := json.Unmarshal([]byte(annotation), environment); err != nil {
+		return nil, fmt.Errorf("failed to read %s annotation: %w", constants.DevWorkspaceEnvironmentAnnotation, err)
+	}
+	return environment, nil
+}
+
+// RecordEnvironment stores an environment in the DevWorkspaceEnvironmentAnnotation of a DevWorkspace. Changes are
+// not synced to the cluster.
+func RecordEnvironment(workspace *dw.DevWorkspace, environment *Environment) error {
+	annotation, err := json.Marshal(environment)
+	if err != nil {
+		return err
+	}
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[constants.DevWorkspaceEnvironmentAnnotation] = string(annotation)
+	return nil
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getEnvironmentTestWorkspace(httpProxy string) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Config.Routing.ProxyConfig = &v1alpha1.Proxy{
+		HttpProxy: &httpProxy,
+	}
+	return workspace
+}
+
+func getCertificateConfigMap(name, label, certificate string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				label: "true",
+			},
+		},
+		Data: map[string]string{
+			"certificate": certificate,
+		},
+	}
+}
+
+func getTestEnvironment(t *testing.T, workspace *common.DevWorkspaceWithConfig, objs ...client.Object) *Environment {
+	clusterAPI := sync.ClusterAPI{
+		Ctx:    context.Background(),
+		Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
+		Logger: zap.New(),
+	}
+	environment, err := GetCurrentEnvironment(workspace, clusterAPI)
+	if !assert.NoError(t, err, "Should not return error") {
+		t.FailNow()
+	}
+	return environment
+}
+
+func TestGetCurrentEnvironment(t *testing.T) {
+	gitCert := getCertificateConfigMap("git-cert", constants.DevWorkspaceGitTLSLabel, "git-cert")
+	caBundle := getCertificateConfigMap("ca-bundle", constants.OpenShiftTrustedCABundleLabel, "ca-bundle")
+	unrelated := getCertificateConfigMap("unrelated", constants.DevWorkspaceMountLabel, "unrelated")
+
+	base := getTestEnvironment(t, getEnvironmentTestWorkspace("http://proxy.example.com"), gitCert, caBundle)
+	if assert.NotNil(t, base.Proxy) && assert.NotNil(t, base.Proxy.HttpProxy) {
+		assert.Equal(t, "http://proxy.example.com", *base.Proxy.HttpProxy)
+	}
+
+	same := getTestEnvironment(t, getEnvironmentTestWorkspace("http://proxy.example.com"), caBundle, gitCert, unrelated)
+	assert.Equal(t, base.Hash, same.Hash, "Hash should not depend on order of certificates or unrelated configmaps")
+
+	changedProxy := getTestEnvironment(t, getEnvironmentTestWorkspace("http://other-proxy.example.com"), gitCert, caBundle)
+	assert.NotEqual(t, base.Hash, changedProxy.Hash, "Hash should change when proxy configuration changes")
+
+	updatedBundle := caBundle.DeepCopy()
+	updatedBundle.Data["certificate"] = "updated-ca-bundle"
+	changedCerts := getTestEnvironment(t, getEnvironmentTestWorkspace("http://proxy.example.com"), gitCert, updatedBundle)
+	assert.NotEqual(t, base.Hash, changedCerts.Hash, "Hash should change when certificates change")
+}
+
+func TestRecordEnvironment(t *testing.T) {
+	workspace := &dw.DevWorkspace{}
+	recorded, err := GetRecordedEnvironment(workspace)
+	assert.NoError(t, err, "Should not return error")
+	assert.Nil(t, recorded, "Should return nil if no environment is recorded")
+
+	httpProxy := "http://proxy.example.com"
+	environment := &Environment{
+		Hash:  "abc123",
+		Proxy: &v1alpha1.Proxy{HttpProxy: &httpProxy},
+	}
+	if !assert.NoError(t, RecordEnvironment(workspace, environment)) {
+		return
+	}
+	recorded, err = GetRecordedEnvironment(workspace)
+	assert.NoError(t, err, "Should not return error")
+	assert.Equal(t, environment, recorded, "Should read recorded environment")
+
+	workspace.Annotations[constants.DevWorkspaceEnvironmentAnnotation] = "invalid"
+	_, err = GetRecordedEnvironment(workspace)
+	assert.Error(t, err, "Should return error for invalid annotation")
+}
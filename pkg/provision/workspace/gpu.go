@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+)
+
+// applyGPUProfile adds the node selector and tolerations of the GPU profile in the config to a DevWorkspace pod if
+// any of its containers requests or limits one of the profile's resources. Node selectors from the GPU profile take
+// precedence over existing node selectors for the same key.
+func applyGPUProfile(workspace *common.DevWorkspaceWithConfig, podSpec *corev1.PodSpec) {
+	gpuProfile := workspace.Config.Workspace.GPUProfile
+	if gpuProfile == nil || !requestsAnyResource(podSpec, gpuProfile.ResourceNames) {
+		return
+	}
+
+	if len(gpuProfile.NodeSelector) > 0 {
+		nodeSelector := map[string]string{}
+		for key, value := range podSpec.NodeSelector {
+			nodeSelector[key] = value
+		}
+		for key, value := range gpuProfile.NodeSelector {
+			nodeSelector[key] = value
+		}
+		podSpec.NodeSelector = nodeSelector
+	}
+
+	var tolerations []corev1.Toleration
+	tolerations = append(tolerations, podSpec.Tolerations...)
+	for _, toleration := range gpuProfile.Tolerations {
+		if !containsToleration(tolerations, toleration) {
+			tolerations = append(tolerations, toleration)
+		}
+	}
+	if len(tolerations) > 0 {
+		podSpec.Tolerations = tolerations
+	}
+}
+
+func requestsAnyResource(podSpec *corev1.PodSpec, resourceNames []corev1.ResourceName) bool {
+	var containers []corev1.Container
+	containers = append(containers, podSpec.InitContainers...)
+	containers = append(containers, podSpec.Containers...)
+	for _, container := range containers {
+		for _, resourceName := range resourceNames {
+			if limit, ok := container.Resources.Limits[resourceName]; ok && !limit.IsZero() {
+				return true
+			}
+			if request, ok := container.Resources.Requests[resourceName]; ok && !request.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+func TestApplyGPUProfile(t *testing.T) {
+	gpuToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	existingToleration := corev1.Toleration{Key: "workspaces", Operator: corev1.TolerationOpExists}
+	workspaceConfig := &v1alpha1.WorkspaceConfig{
+		GPUProfile: &v1alpha1.GPUProfileConfig{
+			ResourceNames: []corev1.ResourceName{"nvidia.com/gpu"},
+			NodeSelector:  map[string]string{"node-pool": "gpu"},
+			Tolerations:   []corev1.Toleration{gpuToleration},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		resources            corev1.ResourceRequirements
+		expectedNodeSelector map[string]string
+		expectedTolerations  []corev1.Toleration
+	}{
+		{
+			name:                 "Does not apply profile if GPU is not requested",
+			expectedNodeSelector: map[string]string{"node-pool": "default", "zone": "a"},
+			expectedTolerations:  []corev1.Toleration{existingToleration},
+		},
+		{
+			name: "Applies profile if GPU is requested",
+			resources: corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				Requests: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+			},
+			expectedNodeSelector: map[string]string{"node-pool": "gpu", "zone": "a"},
+			expectedTolerations:  []corev1.Toleration{existingToleration, gpuToleration},
+		},
+		{
+			name: "Does not apply profile if zero GPUs are requested",
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("0")},
+			},
+			expectedNodeSelector: map[string]string{"node-pool": "default", "zone": "a"},
+			expectedTolerations:  []corev1.Toleration{existingToleration},
+		},
+		{
+			name: "Does not apply profile for other extended resources",
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+			},
+			expectedNodeSelector: map[string]string{"node-pool": "default", "zone": "a"},
+			expectedTolerations:  []corev1.Toleration{existingToleration},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getPriorityTestWorkspace(workspaceConfig, nil)
+			podSpec := &corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: "tools", Resources: tt.resources},
+				},
+				NodeSelector: map[string]string{"node-pool": "default", "zone": "a"},
+				Tolerations:  []corev1.Toleration{existingToleration},
+			}
+			applyGPUProfile(workspace, podSpec)
+			assert.Equal(t, tt.expectedNodeSelector, podSpec.NodeSelector)
+			assert.Equal(t, tt.expectedTolerations, podSpec.Tolerations)
+			assert.Equal(t, "gpu", workspaceConfig.GPUProfile.NodeSelector["node-pool"], "Should not modify config")
+		})
+	}
+}
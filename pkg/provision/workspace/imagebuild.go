@@ -0,0 +1,684 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	imageBuildRequeue = 5 * time.Second
+	// maxDockerfileSize is the maximum size of a Dockerfile fetched from the URI of an image component
+	maxDockerfileSize = 1024 * 1024
+
+	kanikoContainerName      = "build"
+	kanikoContextVolume      = "build-context"
+	kanikoContextPath        = "/workspace/context"
+	kanikoDockerfileVolume   = "dockerfile"
+	kanikoDockerfilePath     = "/workspace/dockerfile"
+	kanikoDockerConfigVolume = "docker-config"
+	kanikoDockerConfigPath   = "/kaniko/.docker"
+	dockerfileKey            = "Dockerfile"
+)
+
+var imageBuildJobBackoffLimit int32 = 0
+
+// imageBuild holds the information required to build the image of an image component
+type imageBuild struct {
+	// name is the name of the Job or BuildConfig that builds the image
+	name      string
+	component dw.Component
+	config    *v1alpha1.ImageBuildConfig
+	buildArgs []corev1.EnvVar
+	// hash identifies the inputs of the build and is used as the tag of the built image
+	hash string
+	// image is the image that is pushed to the configured registry. It is empty if no registry is configured, in
+	// which case the image is pushed to an ImageStream on OpenShift.
+	image string
+	// timeoutSeconds is the maximum duration of the build, or zero if builds are not limited
+	timeoutSeconds int64
+}
+
+// BuildImageComponents builds the images of the devfile image components of a DevWorkspace and replaces the image of
+// container components that refer to an image component's imageName with the built image. Image components are built
+// if autoBuild is true, or if autoBuild is not set and a container component uses their imageName. On OpenShift,
+// images are built by a BuildConfig; on Kubernetes, images are built by a Kaniko Job.
+//
+// Images are only rebuilt when the image component changes. Returns a RetryError while builds are in progress, and a
+// FailError if a build fails or an image component cannot be built.
+func BuildImageComponents(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI, httpClient network.HTTPGetter) error {
+	builtImages := map[string]string{}
+	var retryErr error
+	for _, component := range getImageComponentsToBuild(workspace.Spec.Template.Components) {
+		image, err := buildImageComponent(workspace, component, clusterAPI, httpClient)
+		if err != nil {
+			// Start all builds before waiting, so that images are built in parallel
+			if _, ok := err.(*dwerrors.RetryError); ok {
+				if retryErr == nil {
+					retryErr = err
+				}
+				continue
+			}
+			return err
+		}
+		builtImages[component.Image.ImageName] = image
+	}
+	if retryErr != nil {
+		return retryErr
+	}
+
+	for idx, component := range workspace.Spec.Template.Components {
+		if component.Container == nil {
+			continue
+		}
+		if image, ok := builtImages[component.Container.Image]; ok {
+			workspace.Spec.Template.Components[idx].Container.Image = image
+		}
+	}
+	return nil
+}
+
+// getImageComponentsToBuild returns the image components that define a Dockerfile and either have autoBuild set to
+// true, or do not set autoBuild and have their imageName used by a container component.
+func getImageComponentsToBuild(components []dw.Component) []dw.Component {
+	usedImages := map[string]bool{}
+	for _, component := range components {
+		if component.Container != nil {
+			usedImages[component.Container.Image] = true
+		}
+	}
+	var toBuild []dw.Component
+	for _, component := range components {
+		if component.Image == nil || component.Image.Dockerfile == nil {
+			continue
+		}
+		autoBuild := component.Image.AutoBuild
+		if (autoBuild != nil && *autoBuild) || (autoBuild == nil && usedImages[component.Image.ImageName]) {
+			toBuild = append(toBuild, component)
+		}
+	}
+	return toBuild
+}
+
+// buildImageComponent returns the built image for an image component, starting a build if required.
+func buildImageComponent(workspace *common.DevWorkspaceWithConfig, component dw.Component, clusterAPI sync.ClusterAPI, httpClient network.HTTPGetter) (string, error) {
+	buildConfig := workspace.Config.Workspace.ImageBuild
+	if buildConfig == nil {
+		buildConfig = &v1alpha1.ImageBuildConfig{}
+	}
+	dockerfile := component.Image.Dockerfile
+	if dockerfile.Git == nil && !strings.HasPrefix(dockerfile.Uri, "http://") && !strings.HasPrefix(dockerfile.Uri, "https://") {
+		return "", &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot build image component %s: only Dockerfiles in a Git repository or at an absolute HTTP(S) URI are supported", component.Name),
+		}
+	}
+	buildArgs, err := getBuildArgs(dockerfile.Args)
+	if err != nil {
+		return "", &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot build image component %s", component.Name),
+			Err:     err,
+		}
+	}
+	hash, err := getImageBuildHash(component.Image, buildConfig)
+	if err != nil {
+		return "", err
+	}
+	build := &imageBuild{
+		name:      common.ImageBuildName(workspace.Status.DevWorkspaceId, component.Name),
+		component: component,
+		config:    buildConfig,
+		buildArgs: buildArgs,
+		hash:      hash,
+	}
+	if buildConfig.Registry != "" {
+		build.image = fmt.Sprintf("%s/%s-%s:%s", strings.TrimSuffix(buildConfig.Registry, "/"), workspace.Status.DevWorkspaceId, component.Name, hash)
+	}
+	if buildConfig.Timeout != "" {
+		timeout, err := time.ParseDuration(buildConfig.Timeout)
+		if err != nil {
+			return "", &dwerrors.FailError{
+				Message: "Invalid image build timeout in DevWorkspaceOperatorConfig",
+				Err:     err,
+			}
+		}
+		build.timeoutSeconds = int64(timeout.Seconds())
+	}
+
+	if infrastructure.IsOpenShift() {
+		return syncImageBuildConfig(workspace, build, clusterAPI, httpClient)
+	}
+	if build.image == "" {
+		return "", &dwerrors.FailError{
+			Message: fmt.Sprintf("Cannot build image component %s: no image build registry is configured", component.Name),
+		}
+	}
+	return syncImageBuildJob(workspace, build, clusterAPI, httpClient)
+}
+
+// syncImageBuildJob creates the Kaniko Job that builds the image of an image component, and returns the built image
+// once the Job has completed. If the image component has changed since the Job was created, the Job is deleted so
+// that the image is rebuilt.
+func syncImageBuildJob(workspace *common.DevWorkspaceWithConfig, build *imageBuild, clusterAPI sync.ClusterAPI, httpClient network.HTTPGetter) (string, error) {
+	clusterJob := &batchv1.Job{}
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: build.name, Namespace: workspace.Namespace}, clusterJob)
+	switch {
+	case err == nil:
+		if clusterJob.Annotations[constants.ImageBuildHashAnnotation] != build.hash {
+			return "", deleteOutdatedImageBuild(clusterJob, build, clusterAPI)
+		}
+		if err := checkImageBuildJob(clusterJob, build.component.Name); err != nil {
+			return "", err
+		}
+		return build.image, nil
+	case !k8sErrors.IsNotFound(err):
+		return "", err
+	}
+
+	specJob, dockerfileCM, err := getSpecImageBuildJob(workspace, build, clusterAPI, httpClient)
+	if err != nil {
+		return "", err
+	}
+	if dockerfileCM != nil {
+		if err := controllerutil.SetControllerReference(workspace.DevWorkspace, dockerfileCM, clusterAPI.Scheme); err != nil {
+			return "", err
+		}
+		if _, err := sync.SyncObjectWithCluster(dockerfileCM, clusterAPI); err != nil {
+			return "", dwerrors.WrapSyncError(err)
+		}
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specJob, clusterAPI.Scheme); err != nil {
+		return "", err
+	}
+	if err := clusterAPI.Client.Create(clusterAPI.Ctx, specJob); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return "", err
+	}
+	return "", &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Building image for component %s", build.component.Name),
+		RequeueAfter: imageBuildRequeue,
+	}
+}
+
+func checkImageBuildJob(job *batchv1.Job, componentName string) error {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return nil
+		case batchv1.JobFailed:
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Failed to build image for component %s: see logs for job %q for details", componentName, job.Name),
+			}
+		}
+	}
+	return &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Building image for component %s", componentName),
+		RequeueAfter: imageBuildRequeue,
+	}
+}
+
+// getSpecImageBuildJob returns the Kaniko Job that builds the image of an image component. If the Dockerfile is
+// fetched from a URI, a ConfigMap containing the Dockerfile is returned as well; the image is then built with an
+// empty build context.
+func getSpecImageBuildJob(workspace *common.DevWorkspaceWithConfig, build *imageBuild, clusterAPI sync.ClusterAPI, httpClient network.HTTPGetter) (*batchv1.Job, *corev1.ConfigMap, error) {
+	jobLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:      workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:    workspace.Name,
+		constants.DevWorkspaceCreatorLabel: workspace.Labels[constants.DevWorkspaceCreatorLabel],
+	}
+	if restrictedAccess, needsRestrictedAccess := workspace.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation]; needsRestrictedAccess {
+		jobLabels[constants.DevWorkspaceRestrictedAccessAnnotation] = restrictedAccess
+	}
+
+	args := []string{fmt.Sprintf("--destination=%s", build.image)}
+	for _, buildArg := range build.buildArgs {
+		args = append(args, fmt.Sprintf("--build-arg=%s=%s", buildArg.Name, buildArg.Value))
+	}
+	var volumes []corev1.Volume
+	var volumeMounts []corev1.VolumeMount
+	var dockerfileCM *corev1.ConfigMap
+
+	dockerfile := build.component.Image.Dockerfile
+	if dockerfile.Git != nil {
+		url, revision, err := getDockerfileGitSource(dockerfile.Git)
+		if err != nil {
+			return nil, nil, imageBuildFailError(build, err)
+		}
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			return nil, nil, imageBuildFailError(build, fmt.Errorf("only HTTP(S) Git remotes are supported on Kubernetes, got %s", url))
+		}
+		dockerfilePath, err := getDockerfilePathInContext(dockerfile)
+		if err != nil {
+			return nil, nil, imageBuildFailError(build, err)
+		}
+		gitContext := "git://" + strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://")
+		if revision != "" {
+			// Kaniko requires a full reference name; revisions that are not references are assumed to be branches
+			if !strings.HasPrefix(revision, "refs/") {
+				revision = "refs/heads/" + revision
+			}
+			gitContext = gitContext + "#" + revision
+		}
+		args = append(args, fmt.Sprintf("--context=%s", gitContext), fmt.Sprintf("--dockerfile=%s", dockerfilePath))
+		if dockerfile.BuildContext != "" {
+			args = append(args, fmt.Sprintf("--context-sub-path=%s", filepath.Clean(dockerfile.BuildContext)))
+		}
+	} else {
+		content, err := fetchDockerfile(dockerfile.Uri, httpClient)
+		if err != nil {
+			return nil, nil, imageBuildFailError(build, err)
+		}
+		cmLabels := map[string]string{constants.DevWorkspaceWatchConfigMapLabel: "true"}
+		for label, value := range jobLabels {
+			cmLabels[label] = value
+		}
+		dockerfileCM = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      build.name,
+				Namespace: workspace.Namespace,
+				Labels:    cmLabels,
+			},
+			Data: map[string]string{
+				dockerfileKey: string(content),
+			},
+		}
+		volumes = append(volumes,
+			corev1.Volume{
+				Name:         kanikoContextVolume,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+			corev1.Volume{
+				Name: kanikoDockerfileVolume,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: build.name},
+					},
+				},
+			})
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{Name: kanikoContextVolume, MountPath: kanikoContextPath},
+			corev1.VolumeMount{Name: kanikoDockerfileVolume, MountPath: kanikoDockerfilePath, ReadOnly: true})
+		args = append(args, fmt.Sprintf("--context=dir://%s", kanikoContextPath), fmt.Sprintf("--dockerfile=%s/%s", kanikoDockerfilePath, dockerfileKey))
+	}
+
+	if build.config.PushSecretName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: kanikoDockerConfigVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: build.config.PushSecretName,
+					Items:      []corev1.KeyToPath{{Key: corev1.DockerConfigJsonKey, Path: "config.json"}},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{Name: kanikoDockerConfigVolume, MountPath: kanikoDockerConfigPath, ReadOnly: true})
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      build.name,
+			Namespace: workspace.Namespace,
+			Labels:    jobLabels,
+			Annotations: map[string]string{
+				constants.ImageBuildHashAnnotation: build.hash,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &imageBuildJobBackoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes:       volumes,
+					Containers: []corev1.Container{
+						{
+							Name:         kanikoContainerName,
+							Image:        build.config.BuilderImage,
+							Args:         args,
+							VolumeMounts: volumeMounts,
+						},
+					},
+				},
+			},
+		},
+	}
+	if build.timeoutSeconds > 0 {
+		job.Spec.ActiveDeadlineSeconds = &build.timeoutSeconds
+	}
+
+	podTolerations, nodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(podTolerations) > 0 {
+		job.Spec.Template.Spec.Tolerations = podTolerations
+	}
+	if len(nodeSelector) > 0 {
+		job.Spec.Template.Spec.NodeSelector = nodeSelector
+	}
+	return job, dockerfileCM, nil
+}
+
+// syncImageBuildConfig creates the BuildConfig that builds the image of an image component, and returns the built
+// image once the latest build of the BuildConfig has completed. If the image component has changed since the
+// BuildConfig was created, the BuildConfig is deleted so that the image is rebuilt.
+func syncImageBuildConfig(workspace *common.DevWorkspaceWithConfig, build *imageBuild, clusterAPI sync.ClusterAPI, httpClient network.HTTPGetter) (string, error) {
+	// BuildConfigs and Builds are not cached by the controller
+	clusterBuildConfig := &buildv1.BuildConfig{}
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: build.name, Namespace: workspace.Namespace}, clusterBuildConfig)
+	switch {
+	case err == nil:
+		if clusterBuildConfig.Annotations[constants.ImageBuildHashAnnotation] != build.hash {
+			return "", deleteOutdatedImageBuild(clusterBuildConfig, build, clusterAPI)
+		}
+		return checkImageBuildConfig(clusterBuildConfig, build, clusterAPI)
+	case !k8sErrors.IsNotFound(err):
+		return "", err
+	}
+
+	specBuildConfig, err := getSpecImageBuildConfig(workspace, build, httpClient)
+	if err != nil {
+		return "", err
+	}
+	if build.image == "" {
+		// Images are pushed to an ImageStream in the internal registry if no registry is configured
+		imageStream := &imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      build.name,
+				Namespace: workspace.Namespace,
+				Labels:    specBuildConfig.Labels,
+			},
+		}
+		if err := controllerutil.SetControllerReference(workspace.DevWorkspace, imageStream, clusterAPI.Scheme); err != nil {
+			return "", err
+		}
+		if err := clusterAPI.NonCachingClient.Create(clusterAPI.Ctx, imageStream); err != nil && !k8sErrors.IsAlreadyExists(err) {
+			return "", err
+		}
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specBuildConfig, clusterAPI.Scheme); err != nil {
+		return "", err
+	}
+	if err := clusterAPI.NonCachingClient.Create(clusterAPI.Ctx, specBuildConfig); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return "", err
+	}
+	return "", &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Building image for component %s", build.component.Name),
+		RequeueAfter: imageBuildRequeue,
+	}
+}
+
+// checkImageBuildConfig returns the image built by the latest build of a BuildConfig, or an error if the build has
+// not completed successfully.
+func checkImageBuildConfig(buildConfig *buildv1.BuildConfig, build *imageBuild, clusterAPI sync.ClusterAPI) (string, error) {
+	waitErr := &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Building image for component %s", build.component.Name),
+		RequeueAfter: imageBuildRequeue,
+	}
+	if buildConfig.Status.LastVersion == 0 {
+		return "", waitErr
+	}
+	buildName := fmt.Sprintf("%s-%d", buildConfig.Name, buildConfig.Status.LastVersion)
+	clusterBuild := &buildv1.Build{}
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: buildName, Namespace: buildConfig.Namespace}, clusterBuild)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return "", waitErr
+		}
+		return "", err
+	}
+	switch clusterBuild.Status.Phase {
+	case buildv1.BuildPhaseComplete:
+		if clusterBuild.Status.OutputDockerImageReference != "" {
+			return clusterBuild.Status.OutputDockerImageReference, nil
+		}
+		if build.image == "" {
+			return "", &dwerrors.FailError{
+				Message: fmt.Sprintf("Build %s for component %s did not report the built image", buildName, build.component.Name),
+			}
+		}
+		return build.image, nil
+	case buildv1.BuildPhaseFailed, buildv1.BuildPhaseError, buildv1.BuildPhaseCancelled:
+		return "", &dwerrors.FailError{
+			Message: fmt.Sprintf("Failed to build image for component %s: see logs for build %q for details", build.component.Name, buildName),
+		}
+	}
+	return "", waitErr
+}
+
+// getSpecImageBuildConfig returns the BuildConfig that builds the image of an image component using the Docker
+// strategy. The BuildConfig starts a build when it is created.
+func getSpecImageBuildConfig(workspace *common.DevWorkspaceWithConfig, build *imageBuild, httpClient network.HTTPGetter) (*buildv1.BuildConfig, error) {
+	buildLabels := map[string]string{
+		constants.DevWorkspaceIDLabel:      workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:    workspace.Name,
+		constants.DevWorkspaceCreatorLabel: workspace.Labels[constants.DevWorkspaceCreatorLabel],
+	}
+
+	strategy := &buildv1.DockerBuildStrategy{
+		BuildArgs: build.buildArgs,
+	}
+	var source buildv1.BuildSource
+	dockerfile := build.component.Image.Dockerfile
+	if dockerfile.Git != nil {
+		url, revision, err := getDockerfileGitSource(dockerfile.Git)
+		if err != nil {
+			return nil, imageBuildFailError(build, err)
+		}
+		dockerfilePath, err := getDockerfilePathInContext(dockerfile)
+		if err != nil {
+			return nil, imageBuildFailError(build, err)
+		}
+		source = buildv1.BuildSource{
+			Type:       buildv1.BuildSourceGit,
+			Git:        &buildv1.GitBuildSource{URI: url, Ref: revision},
+			ContextDir: dockerfile.BuildContext,
+		}
+		strategy.DockerfilePath = dockerfilePath
+	} else {
+		content, err := fetchDockerfile(dockerfile.Uri, httpClient)
+		if err != nil {
+			return nil, imageBuildFailError(build, err)
+		}
+		contentStr := string(content)
+		source = buildv1.BuildSource{
+			Type:       buildv1.BuildSourceDockerfile,
+			Dockerfile: &contentStr,
+		}
+	}
+
+	output := buildv1.BuildOutput{}
+	if build.image != "" {
+		output.To = &corev1.ObjectReference{Kind: "DockerImage", Name: build.image}
+	} else {
+		output.To = &corev1.ObjectReference{Kind: "ImageStreamTag", Name: fmt.Sprintf("%s:%s", build.name, build.hash)}
+	}
+	if build.config.PushSecretName != "" {
+		output.PushSecret = &corev1.LocalObjectReference{Name: build.config.PushSecretName}
+	}
+
+	buildConfig := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      build.name,
+			Namespace: workspace.Namespace,
+			Labels:    buildLabels,
+			Annotations: map[string]string{
+				constants.ImageBuildHashAnnotation: build.hash,
+			},
+		},
+		Spec: buildv1.BuildConfigSpec{
+			Triggers: []buildv1.BuildTriggerPolicy{
+				{Type: buildv1.ConfigChangeBuildTriggerType},
+			},
+			RunPolicy: buildv1.BuildRunPolicySerial,
+			CommonSpec: buildv1.CommonSpec{
+				Source: source,
+				Strategy: buildv1.BuildStrategy{
+					Type:           buildv1.DockerBuildStrategyType,
+					DockerStrategy: strategy,
+				},
+				Output: output,
+			},
+		},
+	}
+	if build.timeoutSeconds > 0 {
+		buildConfig.Spec.CompletionDeadlineSeconds = &build.timeoutSeconds
+	}
+	return buildConfig, nil
+}
+
+// deleteOutdatedImageBuild deletes the Job or BuildConfig that built the image of an image component that has since
+// changed, along with its pods or builds, and returns a RetryError so that the image is rebuilt once it is removed.
+func deleteOutdatedImageBuild(obj client.Object, build *imageBuild, clusterAPI sync.ClusterAPI) error {
+	err := clusterAPI.NonCachingClient.Delete(clusterAPI.Ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return &dwerrors.RetryError{
+		Message:      fmt.Sprintf("Image component %s has changed, rebuilding image", build.component.Name),
+		RequeueAfter: imageBuildRequeue,
+	}
+}
+
+func imageBuildFailError(build *imageBuild, err error) error {
+	return &dwerrors.FailError{
+		Message: fmt.Sprintf("Cannot build image component %s", build.component.Name),
+		Err:     err,
+	}
+}
+
+// getBuildArgs parses the args of a Dockerfile image component, which must be of the form KEY=VALUE, into build args.
+func getBuildArgs(args []string) ([]corev1.EnvVar, error) {
+	var buildArgs []corev1.EnvVar
+	for _, arg := range args {
+		name, value, found := strings.Cut(arg, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid build argument %q: expected format KEY=VALUE", arg)
+		}
+		buildArgs = append(buildArgs, corev1.EnvVar{Name: name, Value: value})
+	}
+	return buildArgs, nil
+}
+
+// getImageBuildHash returns a hash of the inputs of an image build, used to detect changes to image components and
+// as the tag of built images. Changes to the content of a Dockerfile fetched from a URI are not detected.
+func getImageBuildHash(image *dw.ImageComponent, buildConfig *v1alpha1.ImageBuildConfig) (string, error) {
+	hashInput, err := json.Marshal(struct {
+		Image          *dw.ImageComponent `json:"image"`
+		Registry       string             `json:"registry"`
+		PushSecretName string             `json:"pushSecretName"`
+	}{image, buildConfig.Registry, buildConfig.PushSecretName})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute image build hash: %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(hashInput))[:16], nil
+}
+
+// getDockerfileGitSource returns the URL of the Git remote and the revision to build a Dockerfile image component from.
+// The remote can be omitted from checkoutFrom if only one remote is defined.
+func getDockerfileGitSource(git *dw.DockerfileGitProjectSource) (url, revision string, err error) {
+	if len(git.Remotes) == 0 {
+		return "", "", fmt.Errorf("git source does not define remotes")
+	}
+	var remoteName string
+	if git.CheckoutFrom != nil {
+		remoteName = git.CheckoutFrom.Remote
+		revision = git.CheckoutFrom.Revision
+	}
+	if remoteName == "" {
+		if len(git.Remotes) > 1 {
+			return "", "", fmt.Errorf("git source checkoutFrom remote can't be omitted with multiple remotes")
+		}
+		for name := range git.Remotes {
+			remoteName = name
+		}
+	}
+	url, ok := git.Remotes[remoteName]
+	if !ok {
+		return "", "", fmt.Errorf("git source checkoutFrom refers to non-existing remote %s", remoteName)
+	}
+	return url, revision, nil
+}
+
+// getDockerfilePathInContext returns the path of the Dockerfile of a Git image component relative to its build
+// context. The fileLocation and buildContext of the component are both relative to the root of the repository.
+func getDockerfilePathInContext(dockerfile *dw.DockerfileImage) (string, error) {
+	fileLocation := dockerfile.Git.FileLocation
+	if fileLocation == "" {
+		fileLocation = dockerfileKey
+	}
+	buildContext := dockerfile.BuildContext
+	if buildContext == "" {
+		buildContext = "."
+	}
+	relPath, err := filepath.Rel(filepath.Clean(buildContext), filepath.Clean(fileLocation))
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, "../") {
+		return "", fmt.Errorf("dockerfile %s is not within build context %s", fileLocation, buildContext)
+	}
+	return relPath, nil
+}
+
+func fetchDockerfile(uri string, httpClient network.HTTPGetter) ([]byte, error) {
+	if httpClient == nil {
+		return nil, fmt.Errorf("cannot fetch Dockerfile from %s: no HTTP client configured", uri)
+	}
+	resp, err := httpClient.Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Dockerfile from %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch Dockerfile from %s: got status %d", uri, resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxDockerfileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not read Dockerfile from %s: %w", uri, err)
+	}
+	if len(content) > maxDockerfileSize {
+		return nil, fmt.Errorf("dockerfile at %s is larger than %d bytes", uri, maxDockerfileSize)
+	}
+	return content, nil
+}
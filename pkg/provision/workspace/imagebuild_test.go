@@ -0,0 +1,367 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const testDockerfileURI = "https://example.com/Dockerfile"
+
+// fakeDockerfileGetter serves Dockerfiles from a map of URIs to content. Requests for other URIs return 404.
+type fakeDockerfileGetter map[string]string
+
+func (f fakeDockerfileGetter) Get(location string) (*http.Response, error) {
+	content, ok := f[location]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(content))}, nil
+}
+
+func getImageBuildTestWorkspace(registry string, dockerfile *dw.DockerfileImage) *common.DevWorkspaceWithConfig {
+	workspace := getLegacyTestWorkspace()
+	workspace.Config = &v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			ImageBuild: &v1alpha1.ImageBuildConfig{
+				Registry:       registry,
+				PushSecretName: "push-secret",
+				BuilderImage:   "kaniko:test",
+				Timeout:        "10m",
+			},
+		},
+	}
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "tools",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{Image: "tools-image"},
+				},
+			},
+		},
+		{
+			Name: "tools-build",
+			ComponentUnion: dw.ComponentUnion{
+				Image: &dw.ImageComponent{
+					Image: dw.Image{
+						ImageName: "tools-image",
+						ImageUnion: dw.ImageUnion{
+							Dockerfile: dockerfile,
+						},
+					},
+				},
+			},
+		},
+	}
+	return workspace
+}
+
+func getGitDockerfile() *dw.DockerfileImage {
+	return &dw.DockerfileImage{
+		DockerfileSrc: dw.DockerfileSrc{
+			Git: &dw.DockerfileGitProjectSource{
+				GitProjectSource: dw.GitProjectSource{
+					GitLikeProjectSource: dw.GitLikeProjectSource{
+						Remotes:      map[string]string{"origin": "https://github.com/example/tools.git"},
+						CheckoutFrom: &dw.CheckoutFrom{Revision: "main"},
+					},
+				},
+				FileLocation: "images/tools/Dockerfile",
+			},
+		},
+		Dockerfile: dw.Dockerfile{
+			BuildContext: "images",
+			Args:         []string{"VERSION=1.0"},
+		},
+	}
+}
+
+func getImageBuildTestClusterAPI(infra infrastructure.Type, objs ...client.Object) sync.ClusterAPI {
+	infrastructure.InitializeForTesting(infra)
+	utilruntime.Must(buildv1.Install(testutil.Scheme))
+	utilruntime.Must(imagev1.Install(testutil.Scheme))
+	objs = append(objs, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: legacyTestNamespace}})
+	fakeClient := testutil.NewFakeClient(objs...)
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           testutil.Scheme,
+		Logger:           zap.New(),
+		Ctx:              context.Background(),
+	}
+}
+
+func TestGetImageComponentsToBuild(t *testing.T) {
+	imageComponent := func(name string, autoBuild *bool) dw.Component {
+		return dw.Component{
+			Name: name,
+			ComponentUnion: dw.ComponentUnion{
+				Image: &dw.ImageComponent{
+					Image: dw.Image{
+						ImageName: name + "-image",
+						ImageUnion: dw.ImageUnion{
+							Dockerfile: getGitDockerfile(),
+							AutoBuild:  autoBuild,
+						},
+					},
+				},
+			},
+		}
+	}
+	components := []dw.Component{
+		{
+			Name: "tools",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{Image: "used-image"},
+				},
+			},
+		},
+		imageComponent("used", nil),
+		imageComponent("unused", nil),
+		imageComponent("auto-build", pointer.Bool(true)),
+		imageComponent("no-auto-build", pointer.Bool(false)),
+		{
+			Name: "no-dockerfile",
+			ComponentUnion: dw.ComponentUnion{
+				Image: &dw.ImageComponent{Image: dw.Image{ImageName: "used-image"}},
+			},
+		},
+	}
+
+	var names []string
+	for _, component := range getImageComponentsToBuild(components) {
+		names = append(names, component.Name)
+	}
+	assert.Equal(t, []string{"used", "auto-build"}, names)
+}
+
+func TestBuildImageComponentWithKanikoJob(t *testing.T) {
+	workspace := getImageBuildTestWorkspace("quay.io/example/", getGitDockerfile())
+	clusterAPI := getImageBuildTestClusterAPI(infrastructure.Kubernetes)
+
+	err := BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should wait for image to be built")
+	assert.Equal(t, "tools-image", workspace.Spec.Template.Components[0].Container.Image, "Should not replace image before build completes")
+
+	job := &batchv1.Job{}
+	jobName := common.ImageBuildName(legacyTestWorkspaceID, "tools-build")
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: jobName, Namespace: legacyTestNamespace}, job))
+	hash := job.Annotations[constants.ImageBuildHashAnnotation]
+	require.NotEmpty(t, hash)
+	expectedImage := "quay.io/example/" + legacyTestWorkspaceID + "-tools-build:" + hash
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "kaniko:test", container.Image)
+	assert.Equal(t, []string{
+		"--destination=" + expectedImage,
+		"--build-arg=VERSION=1.0",
+		"--context=git://github.com/example/tools.git#refs/heads/main",
+		"--dockerfile=tools/Dockerfile",
+		"--context-sub-path=images",
+	}, container.Args)
+	assert.Equal(t, []corev1.VolumeMount{{Name: kanikoDockerConfigVolume, MountPath: kanikoDockerConfigPath, ReadOnly: true}}, container.VolumeMounts)
+	assert.Equal(t, "push-secret", job.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+	assert.Equal(t, pointer.Int64(600), job.Spec.ActiveDeadlineSeconds)
+
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	require.NoError(t, clusterAPI.Client.Status().Update(clusterAPI.Ctx, job))
+	err = BuildImageComponents(workspace, clusterAPI, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedImage, workspace.Spec.Template.Components[0].Container.Image, "Should replace image with built image")
+}
+
+func TestBuildImageComponentFromURIWithKanikoJob(t *testing.T) {
+	dockerfile := &dw.DockerfileImage{
+		DockerfileSrc: dw.DockerfileSrc{Uri: testDockerfileURI},
+	}
+	workspace := getImageBuildTestWorkspace("quay.io/example", dockerfile)
+	clusterAPI := getImageBuildTestClusterAPI(infrastructure.Kubernetes)
+	httpClient := fakeDockerfileGetter{testDockerfileURI: "FROM quay.io/devfile/universal-developer-image"}
+	buildName := common.ImageBuildName(legacyTestWorkspaceID, "tools-build")
+
+	// The first reconcile creates the ConfigMap containing the Dockerfile, and the second creates the Job
+	for i := 0; i < 2; i++ {
+		err := BuildImageComponents(workspace, clusterAPI, httpClient)
+		assert.IsType(t, &dwerrors.RetryError{}, err, "Should wait for image to be built")
+	}
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: buildName, Namespace: legacyTestNamespace}, configMap))
+	assert.Equal(t, "FROM quay.io/devfile/universal-developer-image", configMap.Data[dockerfileKey])
+	assert.Equal(t, "true", configMap.Labels[constants.DevWorkspaceWatchConfigMapLabel])
+
+	job := &batchv1.Job{}
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: buildName, Namespace: legacyTestNamespace}, job))
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args, "--context=dir:///workspace/context")
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args, "--dockerfile=/workspace/dockerfile/Dockerfile")
+}
+
+func TestImageIsRebuiltWhenComponentChanges(t *testing.T) {
+	workspace := getImageBuildTestWorkspace("quay.io/example", getGitDockerfile())
+	clusterAPI := getImageBuildTestClusterAPI(infrastructure.Kubernetes)
+	jobName := common.ImageBuildName(legacyTestWorkspaceID, "tools-build")
+
+	err := BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.RetryError{}, err)
+	workspace.Spec.Template.Components[1].Image.Dockerfile.Args = []string{"VERSION=2.0"}
+	err = BuildImageComponents(workspace, clusterAPI, nil)
+	if assert.IsType(t, &dwerrors.RetryError{}, err) {
+		assert.Equal(t, "Image component tools-build has changed, rebuilding image", err.Error())
+	}
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: jobName, Namespace: legacyTestNamespace}, &batchv1.Job{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete outdated job")
+
+	err = BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.RetryError{}, err)
+	job := &batchv1.Job{}
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: jobName, Namespace: legacyTestNamespace}, job))
+	assert.Contains(t, job.Spec.Template.Spec.Containers[0].Args, "--build-arg=VERSION=2.0")
+}
+
+func TestFailedImageBuildJob(t *testing.T) {
+	workspace := getImageBuildTestWorkspace("quay.io/example", getGitDockerfile())
+	clusterAPI := getImageBuildTestClusterAPI(infrastructure.Kubernetes)
+
+	err := BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.RetryError{}, err)
+	job := &batchv1.Job{}
+	jobName := common.ImageBuildName(legacyTestWorkspaceID, "tools-build")
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: jobName, Namespace: legacyTestNamespace}, job))
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	require.NoError(t, clusterAPI.Client.Status().Update(clusterAPI.Ctx, job))
+
+	err = BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.FailError{}, err, "Should fail DevWorkspace if build fails")
+}
+
+func TestImageBuildRequiresRegistryOnKubernetes(t *testing.T) {
+	workspace := getImageBuildTestWorkspace("", getGitDockerfile())
+	clusterAPI := getImageBuildTestClusterAPI(infrastructure.Kubernetes)
+	err := BuildImageComponents(workspace, clusterAPI, nil)
+	if assert.IsType(t, &dwerrors.FailError{}, err) {
+		assert.Equal(t, "Cannot build image component tools-build: no image build registry is configured", err.Error())
+	}
+}
+
+func TestBuildImageComponentWithBuildConfig(t *testing.T) {
+	workspace := getImageBuildTestWorkspace("", getGitDockerfile())
+	clusterAPI := getImageBuildTestClusterAPI(infrastructure.OpenShiftv4)
+	buildName := common.ImageBuildName(legacyTestWorkspaceID, "tools-build")
+
+	err := BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should wait for image to be built")
+
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: buildName, Namespace: legacyTestNamespace}, &imagev1.ImageStream{}),
+		"Should create ImageStream if no registry is configured")
+	buildConfig := &buildv1.BuildConfig{}
+	require.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: buildName, Namespace: legacyTestNamespace}, buildConfig))
+	hash := buildConfig.Annotations[constants.ImageBuildHashAnnotation]
+	assert.Equal(t, &corev1.ObjectReference{Kind: "ImageStreamTag", Name: buildName + ":" + hash}, buildConfig.Spec.Output.To)
+	assert.Equal(t, &corev1.LocalObjectReference{Name: "push-secret"}, buildConfig.Spec.Output.PushSecret)
+	assert.Equal(t, &buildv1.GitBuildSource{URI: "https://github.com/example/tools.git", Ref: "main"}, buildConfig.Spec.Source.Git)
+	assert.Equal(t, "images", buildConfig.Spec.Source.ContextDir)
+	assert.Equal(t, "tools/Dockerfile", buildConfig.Spec.Strategy.DockerStrategy.DockerfilePath)
+	assert.Equal(t, []corev1.EnvVar{{Name: "VERSION", Value: "1.0"}}, buildConfig.Spec.Strategy.DockerStrategy.BuildArgs)
+	assert.Equal(t, pointer.Int64(600), buildConfig.Spec.CompletionDeadlineSeconds)
+
+	builtImage := "image-registry.openshift-image-registry.svc:5000/test-namespace/" + buildName + ":" + hash
+	buildConfig.Status.LastVersion = 1
+	require.NoError(t, clusterAPI.Client.Update(clusterAPI.Ctx, buildConfig))
+	build := &buildv1.Build{
+		ObjectMeta: metav1.ObjectMeta{Name: buildName + "-1", Namespace: legacyTestNamespace},
+		Status: buildv1.BuildStatus{
+			Phase:                      buildv1.BuildPhaseRunning,
+			OutputDockerImageReference: builtImage,
+		},
+	}
+	require.NoError(t, clusterAPI.Client.Create(clusterAPI.Ctx, build))
+	err = BuildImageComponents(workspace, clusterAPI, nil)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should wait for build to complete")
+
+	build.Status.Phase = buildv1.BuildPhaseComplete
+	require.NoError(t, clusterAPI.Client.Update(clusterAPI.Ctx, build))
+	err = BuildImageComponents(workspace, clusterAPI, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, builtImage, workspace.Spec.Template.Components[0].Container.Image, "Should replace image with built image")
+}
+
+func TestGetDockerfilePathInContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		buildContext string
+		fileLocation string
+		expectedPath string
+		expectedErr  string
+	}{
+		{
+			name:         "Defaults to Dockerfile in repository root",
+			expectedPath: "Dockerfile",
+		},
+		{
+			name:         "Dockerfile in build context",
+			buildContext: "images/",
+			fileLocation: "images/tools/Containerfile",
+			expectedPath: "tools/Containerfile",
+		},
+		{
+			name:         "Dockerfile outside build context",
+			buildContext: "images",
+			fileLocation: "Dockerfile",
+			expectedErr:  "dockerfile Dockerfile is not within build context images",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerfile := getGitDockerfile()
+			dockerfile.BuildContext = tt.buildContext
+			dockerfile.Git.FileLocation = tt.fileLocation
+			path, err := getDockerfilePathInContext(dockerfile)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
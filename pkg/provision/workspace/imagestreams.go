@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+	"github.com/devfile/devworkspace-operator/pkg/provision/workspace/rbac"
+)
+
+const imageStreamTagRequeue = 5 * time.Second
+
+// ResolveImageStreamTags replaces the image of container components that use the controller.devfile.io/image-stream-tag
+// attribute with the internal registry pullspec of the referenced ImageStreamTag. If an ImageStreamTag is in a different
+// namespace than the DevWorkspace, its ImageStream must allow imports from the DevWorkspace's namespace, and workspace
+// serviceaccounts are granted permission to pull images from that namespace.
+//
+// ImageStreamTags that do not exist yet (e.g. because a build is in progress) are waited for.
+func ResolveImageStreamTags(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	for idx, component := range workspace.Spec.Template.Components {
+		if component.Container == nil || !component.Attributes.Exists(constants.ImageStreamTagAttribute) {
+			continue
+		}
+		if !infrastructure.IsOpenShift() {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Attribute %s on component %s is only supported on OpenShift", constants.ImageStreamTagAttribute, component.Name),
+			}
+		}
+		var attrErr error
+		reference := component.Attributes.GetString(constants.ImageStreamTagAttribute, &attrErr)
+		if attrErr != nil {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Failed to read attribute %s on component %s", constants.ImageStreamTagAttribute, component.Name),
+				Err:     attrErr,
+			}
+		}
+		namespace, name, err := parseImageStreamTagReference(reference, workspace.Namespace)
+		if err != nil {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("Invalid attribute %s on component %s", constants.ImageStreamTagAttribute, component.Name),
+				Err:     err,
+			}
+		}
+		image, err := getImageStreamTagPullspec(namespace, name, workspace.Namespace, clusterAPI)
+		if err != nil {
+			return err
+		}
+		if namespace != workspace.Namespace {
+			if err := rbac.SyncImagePullerRolebinding(namespace, workspace, clusterAPI); err != nil {
+				return err
+			}
+		}
+		workspace.Spec.Template.Components[idx].Container.Image = image
+	}
+	return nil
+}
+
+// parseImageStreamTagReference parses a reference of the form "[<namespace>/]<name>:<tag>", returning the namespace
+// and ImageStreamTag name ("<name>:<tag>"). If no namespace is specified, defaultNamespace is used.
+func parseImageStreamTagReference(reference, defaultNamespace string) (namespace, name string, err error) {
+	namespace = defaultNamespace
+	name = reference
+	if parts := strings.Split(reference, "/"); len(parts) == 2 {
+		namespace, name = parts[0], parts[1]
+	} else if len(parts) > 2 {
+		return "", "", fmt.Errorf("expected format [<namespace>/]<name>:<tag>, got %q", reference)
+	}
+	if namespace == "" {
+		return "", "", fmt.Errorf("expected format [<namespace>/]<name>:<tag>, got %q", reference)
+	}
+	if imageStream, tag, found := strings.Cut(name, ":"); !found || imageStream == "" || tag == "" || strings.Contains(tag, ":") {
+		return "", "", fmt.Errorf("expected format [<namespace>/]<name>:<tag>, got %q", reference)
+	}
+	return namespace, name, nil
+}
+
+// getImageStreamTagPullspec returns the image that should be used to pull the ImageStreamTag name in namespace. Images
+// are referenced by digest through the internal registry when it is available.
+func getImageStreamTagPullspec(namespace, name, workspaceNamespace string, clusterAPI sync.ClusterAPI) (string, error) {
+	imageStreamName, _, _ := strings.Cut(name, ":")
+	imageStream := &imagev1.ImageStream{}
+	// ImageStreams and ImageStreamTags are not cached by the controller
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: imageStreamName, Namespace: namespace}, imageStream)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return "", &dwerrors.RetryError{
+				Message:      fmt.Sprintf("Waiting for ImageStream %s in namespace %s to be created", imageStreamName, namespace),
+				RequeueAfter: imageStreamTagRequeue,
+			}
+		}
+		return "", err
+	}
+	if !canImportImageStream(workspaceNamespace, imageStream) {
+		return "", &dwerrors.FailError{
+			Message: fmt.Sprintf("ImageStream %s in namespace %s does not allow imports from namespace %s; the %s annotation must be set on the ImageStream",
+				imageStreamName, namespace, workspaceNamespace, constants.ImageStreamAllowImportFromAnnotation),
+		}
+	}
+
+	imageStreamTag := &imagev1.ImageStreamTag{}
+	err = clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: name, Namespace: namespace}, imageStreamTag)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return "", &dwerrors.RetryError{
+				Message:      fmt.Sprintf("Waiting for ImageStreamTag %s in namespace %s to be available", name, namespace),
+				RequeueAfter: imageStreamTagRequeue,
+			}
+		}
+		return "", err
+	}
+
+	if imageStream.Status.DockerImageRepository != "" && imageStreamTag.Image.Name != "" {
+		return fmt.Sprintf("%s@%s", imageStream.Status.DockerImageRepository, imageStreamTag.Image.Name), nil
+	}
+	if imageStreamTag.Image.DockerImageReference == "" {
+		return "", &dwerrors.FailError{
+			Message: fmt.Sprintf("ImageStreamTag %s in namespace %s does not reference an image", name, namespace),
+		}
+	}
+	return imageStreamTag.Image.DockerImageReference, nil
+}
+
+// canImportImageStream returns true if DevWorkspaces in workspaceNamespace are allowed to use images from imageStream.
+// ImageStreams in other namespaces can only be used if they have the controller.devfile.io/allow-import-from annotation.
+func canImportImageStream(workspaceNamespace string, imageStream *imagev1.ImageStream) bool {
+	if workspaceNamespace == imageStream.Namespace {
+		return true
+	}
+	switch allowed := imageStream.Annotations[constants.ImageStreamAllowImportFromAnnotation]; allowed {
+	case "":
+		return false
+	case "*":
+		return true
+	default:
+		for _, namespace := range strings.Split(allowed, ",") {
+			if strings.TrimSpace(namespace) == workspaceNamespace {
+				return true
+			}
+		}
+	}
+	return false
+}
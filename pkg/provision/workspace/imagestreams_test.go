@@ -0,0 +1,194 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const testImageDigest = "sha256:1234567890abcdef"
+
+func getImageStreamTestWorkspace(imageStreamTag string) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name:       "tools",
+			Attributes: attributes.Attributes{}.PutString(constants.ImageStreamTagAttribute, imageStreamTag),
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{Image: "placeholder"},
+				},
+			},
+		},
+		{
+			Name: "other",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Container: dw.Container{Image: "quay.io/example/other:latest"},
+				},
+			},
+		},
+	}
+	return workspace
+}
+
+func getImageStreamTestObjects(namespace string, annotations map[string]string) []client.Object {
+	return []client.Object{
+		&imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "tools",
+				Namespace:   namespace,
+				Annotations: annotations,
+			},
+			Status: imagev1.ImageStreamStatus{
+				DockerImageRepository: "image-registry.openshift-image-registry.svc:5000/" + namespace + "/tools",
+			},
+		},
+		&imagev1.ImageStreamTag{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tools:latest",
+				Namespace: namespace,
+			},
+			Image: imagev1.Image{
+				ObjectMeta:           metav1.ObjectMeta{Name: testImageDigest},
+				DockerImageReference: "quay.io/example/tools@" + testImageDigest,
+			},
+		},
+	}
+}
+
+func getImageStreamTestClusterAPI(t *testing.T, infra infrastructure.Type, objs ...client.Object) sync.ClusterAPI {
+	infrastructure.InitializeForTesting(infra)
+	utilruntime.Must(imagev1.Install(testutil.Scheme))
+	fakeClient := testutil.NewFakeClient(objs...)
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           testutil.Scheme,
+		Logger:           zap.New(),
+		Ctx:              context.Background(),
+	}
+}
+
+func TestResolveImageStreamTagInWorkspaceNamespace(t *testing.T) {
+	workspace := getImageStreamTestWorkspace("tools:latest")
+	clusterAPI := getImageStreamTestClusterAPI(t, infrastructure.OpenShiftv4, getImageStreamTestObjects("test-namespace", nil)...)
+	err := ResolveImageStreamTags(workspace, clusterAPI)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "image-registry.openshift-image-registry.svc:5000/test-namespace/tools@"+testImageDigest,
+		workspace.Spec.Template.Components[0].Container.Image)
+	assert.Equal(t, "quay.io/example/other:latest", workspace.Spec.Template.Components[1].Container.Image,
+		"Should not modify components without attribute")
+
+	rolebindings := &rbacv1.RoleBindingList{}
+	assert.NoError(t, clusterAPI.Client.List(context.Background(), rolebindings))
+	assert.Empty(t, rolebindings.Items, "Should not create rolebinding for ImageStreams in the workspace's namespace")
+}
+
+func TestResolveImageStreamTagInOtherNamespace(t *testing.T) {
+	workspace := getImageStreamTestWorkspace("shared-images/tools:latest")
+	objs := getImageStreamTestObjects("shared-images", map[string]string{constants.ImageStreamAllowImportFromAnnotation: "other-namespace,test-namespace"})
+	clusterAPI := getImageStreamTestClusterAPI(t, infrastructure.OpenShiftv4, objs...)
+	err := ResolveImageStreamTags(workspace, clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should retry after creating rolebinding")
+	err = ResolveImageStreamTags(workspace, clusterAPI)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "image-registry.openshift-image-registry.svc:5000/shared-images/tools@"+testImageDigest,
+		workspace.Spec.Template.Components[0].Container.Image)
+
+	rolebinding := &rbacv1.RoleBinding{}
+	rolebindingNN := types.NamespacedName{Name: common.WorkspaceImagePullerRolebindingName("test-namespace"), Namespace: "shared-images"}
+	if assert.NoError(t, clusterAPI.Client.Get(context.Background(), rolebindingNN, rolebinding), "Should create image puller rolebinding") {
+		assert.Equal(t, "system:image-puller", rolebinding.RoleRef.Name)
+		assert.Equal(t, "system:serviceaccounts:test-namespace", rolebinding.Subjects[0].Name)
+	}
+}
+
+func TestResolveImageStreamTagRequiresImportAnnotation(t *testing.T) {
+	workspace := getImageStreamTestWorkspace("shared-images/tools:latest")
+	clusterAPI := getImageStreamTestClusterAPI(t, infrastructure.OpenShiftv4, getImageStreamTestObjects("shared-images", nil)...)
+	err := ResolveImageStreamTags(workspace, clusterAPI)
+	assert.IsType(t, &dwerrors.FailError{}, err)
+	assert.Equal(t, "placeholder", workspace.Spec.Template.Components[0].Container.Image)
+}
+
+func TestResolveImageStreamTagWaitsForTag(t *testing.T) {
+	workspace := getImageStreamTestWorkspace("tools:v2")
+	clusterAPI := getImageStreamTestClusterAPI(t, infrastructure.OpenShiftv4, getImageStreamTestObjects("test-namespace", nil)...)
+	err := ResolveImageStreamTags(workspace, clusterAPI)
+	if assert.IsType(t, &dwerrors.RetryError{}, err) {
+		assert.Equal(t, "Waiting for ImageStreamTag tools:v2 in namespace test-namespace to be available", err.(*dwerrors.RetryError).Message)
+	}
+}
+
+func TestResolveImageStreamTagFailsOnKubernetes(t *testing.T) {
+	workspace := getImageStreamTestWorkspace("tools:latest")
+	clusterAPI := getImageStreamTestClusterAPI(t, infrastructure.Kubernetes, getImageStreamTestObjects("test-namespace", nil)...)
+	err := ResolveImageStreamTags(workspace, clusterAPI)
+	assert.IsType(t, &dwerrors.FailError{}, err)
+}
+
+func TestParseImageStreamTagReference(t *testing.T) {
+	tests := []struct {
+		reference         string
+		expectedNamespace string
+		expectedName      string
+		expectErr         bool
+	}{
+		{reference: "tools:latest", expectedNamespace: "default-namespace", expectedName: "tools:latest"},
+		{reference: "shared/tools:v1", expectedNamespace: "shared", expectedName: "tools:v1"},
+		{reference: "tools", expectErr: true},
+		{reference: "tools:", expectErr: true},
+		{reference: "/tools:latest", expectErr: true},
+		{reference: "a/b/tools:latest", expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			namespace, name, err := parseImageStreamTagReference(tt.reference, "default-namespace")
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.expectedNamespace, namespace)
+				assert.Equal(t, tt.expectedName, name)
+			}
+		})
+	}
+}
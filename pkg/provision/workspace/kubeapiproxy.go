@@ -0,0 +1,251 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+const (
+	kubeAPIProxyPort              = 8001
+	kubeAPIProxyConfigVolumeName  = "kube-api-proxy-config"
+	kubeAPIProxyConfigMountPath   = "/var/run/kube-api-proxy"
+	kubeAPIProxyKubeconfigPath    = kubeAPIProxyConfigMountPath + "/kubeconfig"
+	kubeAPIProxyTokenVolumeName   = "kube-api-proxy-token"
+	kubeAPIProxyTokenMountPath    = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubeAPIProxyTokenExpiration   = int64(3607)
+	kubeAPIProxyRootCAConfigMap   = "kube-root-ca.crt"
+	kubeAPIProxyDefaultVerb       = "get"
+	kubeAPIProxyNeverMatchPattern = "^$"
+)
+
+// kubeAPIProxyScript writes a kubeconfig that uses the proxy to a volume shared with workspace containers and starts
+// the proxy. The proxy authenticates to the Kubernetes API using the ServiceAccount token mounted in the sidecar.
+const kubeAPIProxyScript = `set -e
+printf '%s' "$PROXY_KUBECONFIG" > "` + kubeAPIProxyKubeconfigPath + `"
+exec kubectl proxy --address=127.0.0.1 --port="$PROXY_PORT" --accept-hosts='^(localhost|127\.0\.0\.1)$' \
+  --accept-paths="$PROXY_ACCEPT_PATHS" --reject-methods="$PROXY_REJECT_METHODS"
+`
+
+// kubeAPIProxyVerbMethods maps the verbs that can be allowed in the proxy configuration to the HTTP methods used for them
+var kubeAPIProxyVerbMethods = map[string]string{
+	"get":    "GET",
+	"create": "POST",
+	"update": "PUT",
+	"patch":  "PATCH",
+	"delete": "DELETE",
+}
+
+// kubeAPIProxyMethods is the ordered list of HTTP methods handled by the proxy
+var kubeAPIProxyMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+var kubeAPIProxyResources = corev1.ResourceRequirements{
+	Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+		corev1.ResourceMemory: resource.MustParse("32Mi"),
+	},
+	Limits: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	},
+}
+
+// IsKubernetesAPIProxyEnabled returns whether the Kubernetes API proxy sidecar should be added to the DevWorkspace.
+// When it is enabled, the ServiceAccount token is not mounted in the DevWorkspace's pod by default.
+func IsKubernetesAPIProxyEnabled(workspace *common.DevWorkspaceWithConfig) bool {
+	proxyConfig := workspace.Config.Workspace.KubernetesAPIProxy
+	return proxyConfig != nil && pointer.BoolDeref(proxyConfig.Enable, false)
+}
+
+// ProvisionKubernetesAPIProxyInto adds a sidecar that runs a proxy to the Kubernetes API to podAdditions, if enabled.
+// The proxy only forwards requests for resources in the DevWorkspace's namespace that use one of the allowed verbs,
+// and workspace containers are configured to use it through the KUBECONFIG environment variable.
+func ProvisionKubernetesAPIProxyInto(podAdditions *v1alpha1.PodAdditions, workspace *common.DevWorkspaceWithConfig) error {
+	if !IsKubernetesAPIProxyEnabled(workspace) {
+		return nil
+	}
+	proxyConfig := workspace.Config.Workspace.KubernetesAPIProxy
+	if proxyConfig.Image == "" {
+		return &dwerrors.FailError{Message: "An image must be configured for the Kubernetes API proxy"}
+	}
+	rejectMethods, err := getKubeAPIProxyRejectMethods(proxyConfig.AllowedVerbs)
+	if err != nil {
+		return &dwerrors.FailError{Message: "Invalid Kubernetes API proxy configuration", Err: err}
+	}
+	for _, volume := range podAdditions.Volumes {
+		if volume.Name == kubeAPIProxyConfigVolumeName || volume.Name == kubeAPIProxyTokenVolumeName {
+			return &dwerrors.FailError{
+				Message: fmt.Sprintf("DevWorkspace volume '%s' conflicts with volume used by the Kubernetes API proxy", volume.Name),
+			}
+		}
+	}
+
+	configVolumeMount := corev1.VolumeMount{
+		Name:      kubeAPIProxyConfigVolumeName,
+		MountPath: kubeAPIProxyConfigMountPath,
+	}
+	for idx, container := range podAdditions.Containers {
+		podAdditions.Containers[idx].VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      kubeAPIProxyConfigVolumeName,
+			MountPath: kubeAPIProxyConfigMountPath,
+			ReadOnly:  true,
+		})
+		podAdditions.Containers[idx].Env = append(container.Env, corev1.EnvVar{Name: "KUBECONFIG", Value: kubeAPIProxyKubeconfigPath})
+	}
+
+	podAdditions.Volumes = append(podAdditions.Volumes, corev1.Volume{
+		Name: kubeAPIProxyConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}, getKubeAPIProxyTokenVolume())
+
+	podAdditions.Containers = append(podAdditions.Containers, corev1.Container{
+		Name:            constants.KubernetesAPIProxyContainerName,
+		Image:           proxyConfig.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{kubeAPIProxyScript},
+		Env: []corev1.EnvVar{
+			{Name: "PROXY_KUBECONFIG", Value: getKubeAPIProxyKubeconfig(workspace.Namespace)},
+			{Name: "PROXY_PORT", Value: fmt.Sprintf("%d", kubeAPIProxyPort)},
+			{Name: "PROXY_ACCEPT_PATHS", Value: strings.Join(getKubeAPIProxyAcceptPaths(workspace.Namespace), ",")},
+			{Name: "PROXY_REJECT_METHODS", Value: rejectMethods},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			configVolumeMount,
+			{
+				Name:      kubeAPIProxyTokenVolumeName,
+				MountPath: kubeAPIProxyTokenMountPath,
+				ReadOnly:  true,
+			},
+		},
+		Resources: kubeAPIProxyResources,
+	})
+	return nil
+}
+
+// getKubeAPIProxyAcceptPaths returns the paths the proxy forwards: API discovery endpoints, and namespaced resources in
+// the DevWorkspace's namespace. The namespace itself is not accessible through the proxy.
+func getKubeAPIProxyAcceptPaths(namespace string) []string {
+	quotedNamespace := regexp.QuoteMeta(namespace)
+	return []string{
+		"^/version$",
+		"^/api$",
+		"^/api/v1$",
+		"^/apis$",
+		"^/apis/[^/]+$",
+		"^/apis/[^/]+/[^/]+$",
+		"^/openapi/.*",
+		fmt.Sprintf("^/api/v1/namespaces/%s/.*", quotedNamespace),
+		fmt.Sprintf("^/apis/[^/]+/[^/]+/namespaces/%s/.*", quotedNamespace),
+	}
+}
+
+// getKubeAPIProxyRejectMethods returns a regular expression that matches the HTTP methods for verbs that are not in
+// allowedVerbs. If allowedVerbs is empty, only "get" is allowed.
+func getKubeAPIProxyRejectMethods(allowedVerbs []string) (string, error) {
+	if len(allowedVerbs) == 0 {
+		allowedVerbs = []string{kubeAPIProxyDefaultVerb}
+	}
+	allowedMethods := map[string]bool{}
+	for _, verb := range allowedVerbs {
+		method, ok := kubeAPIProxyVerbMethods[verb]
+		if !ok {
+			return "", fmt.Errorf("unsupported verb '%s'", verb)
+		}
+		allowedMethods[method] = true
+	}
+	var rejectedMethods []string
+	for _, method := range kubeAPIProxyMethods {
+		if !allowedMethods[method] {
+			rejectedMethods = append(rejectedMethods, method)
+		}
+	}
+	if len(rejectedMethods) == 0 {
+		return kubeAPIProxyNeverMatchPattern, nil
+	}
+	return fmt.Sprintf("^(%s)$", strings.Join(rejectedMethods, "|")), nil
+}
+
+func getKubeAPIProxyKubeconfig(namespace string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: kube-api-proxy
+  cluster:
+    server: http://127.0.0.1:%d
+contexts:
+- name: kube-api-proxy
+  context:
+    cluster: kube-api-proxy
+    namespace: %s
+current-context: kube-api-proxy
+`, kubeAPIProxyPort, namespace)
+}
+
+// getKubeAPIProxyTokenVolume returns a projected volume equivalent to the one Kubernetes mounts for the pod's
+// ServiceAccount, so that it can be mounted only in the proxy sidecar.
+func getKubeAPIProxyTokenVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: kubeAPIProxyTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				DefaultMode: pointer.Int32(0644),
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							ExpirationSeconds: pointer.Int64(kubeAPIProxyTokenExpiration),
+							Path:              "token",
+						},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: kubeAPIProxyRootCAConfigMap},
+							Items: []corev1.KeyToPath{
+								{Key: "ca.crt", Path: "ca.crt"},
+							},
+						},
+					},
+					{
+						DownwardAPI: &corev1.DownwardAPIProjection{
+							Items: []corev1.DownwardAPIVolumeFile{
+								{
+									Path: "namespace",
+									FieldRef: &corev1.ObjectFieldSelector{
+										APIVersion: "v1",
+										FieldPath:  "metadata.namespace",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
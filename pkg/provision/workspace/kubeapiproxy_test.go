@@ -0,0 +1,138 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+func getKubeAPIProxyTestWorkspace(proxyConfig *v1alpha1.KubernetesAPIProxyConfig) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Config.Workspace.KubernetesAPIProxy = proxyConfig
+	return workspace
+}
+
+func getKubeAPIProxyTestPodAdditions() *v1alpha1.PodAdditions {
+	return &v1alpha1.PodAdditions{
+		Containers: []corev1.Container{{Name: "tools", Image: "tools-image"}},
+	}
+}
+
+func TestProvisionKubernetesAPIProxyDisabled(t *testing.T) {
+	podAdditions := getKubeAPIProxyTestPodAdditions()
+	workspace := getKubeAPIProxyTestWorkspace(&v1alpha1.KubernetesAPIProxyConfig{Enable: pointer.Bool(false), Image: "proxy-image"})
+
+	err := ProvisionKubernetesAPIProxyInto(podAdditions, workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, getKubeAPIProxyTestPodAdditions(), podAdditions, "Should not modify pod additions when proxy is disabled")
+	assert.Nil(t, getAutomountServiceAccountToken(workspace), "Should use ServiceAccount setting for mounting token")
+}
+
+func TestProvisionKubernetesAPIProxy(t *testing.T) {
+	podAdditions := getKubeAPIProxyTestPodAdditions()
+	workspace := getKubeAPIProxyTestWorkspace(&v1alpha1.KubernetesAPIProxyConfig{
+		Enable:       pointer.Bool(true),
+		Image:        "proxy-image",
+		AllowedVerbs: []string{"get", "create"},
+	})
+
+	err := ProvisionKubernetesAPIProxyInto(podAdditions, workspace)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, podAdditions.Containers, 2, "Should add proxy sidecar") {
+		return
+	}
+	tools := podAdditions.Containers[0]
+	assert.Contains(t, tools.Env, corev1.EnvVar{Name: "KUBECONFIG", Value: kubeAPIProxyKubeconfigPath})
+	assert.Contains(t, tools.VolumeMounts, corev1.VolumeMount{Name: kubeAPIProxyConfigVolumeName, MountPath: kubeAPIProxyConfigMountPath, ReadOnly: true})
+	for _, vm := range tools.VolumeMounts {
+		assert.NotEqual(t, kubeAPIProxyTokenVolumeName, vm.Name, "ServiceAccount token should not be mounted in workspace containers")
+	}
+
+	sidecar := podAdditions.Containers[1]
+	assert.Equal(t, constants.KubernetesAPIProxyContainerName, sidecar.Name)
+	assert.Equal(t, "proxy-image", sidecar.Image)
+	assert.Contains(t, sidecar.Env, corev1.EnvVar{Name: "PROXY_REJECT_METHODS", Value: "^(PUT|PATCH|DELETE)$"})
+	assert.Contains(t, sidecar.VolumeMounts, corev1.VolumeMount{Name: kubeAPIProxyTokenVolumeName, MountPath: kubeAPIProxyTokenMountPath, ReadOnly: true})
+	assert.Len(t, podAdditions.Volumes, 2)
+	assert.Equal(t, pointer.Bool(false), getAutomountServiceAccountToken(workspace), "Should not mount token in workspace pod")
+}
+
+func TestProvisionKubernetesAPIProxyRequiresImage(t *testing.T) {
+	workspace := getKubeAPIProxyTestWorkspace(&v1alpha1.KubernetesAPIProxyConfig{Enable: pointer.Bool(true)})
+	err := ProvisionKubernetesAPIProxyInto(getKubeAPIProxyTestPodAdditions(), workspace)
+	assert.IsType(t, &dwerrors.FailError{}, err)
+}
+
+func TestGetKubeAPIProxyRejectMethods(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowedVerbs  []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Allows only get by default",
+			expected: "^(POST|PUT|PATCH|DELETE)$",
+		},
+		{
+			name:         "Rejects nothing when all verbs are allowed",
+			allowedVerbs: []string{"get", "create", "update", "patch", "delete"},
+			expected:     kubeAPIProxyNeverMatchPattern,
+		},
+		{
+			name:         "Allows write-only access",
+			allowedVerbs: []string{"create", "delete"},
+			expected:     "^(GET|PUT|PATCH)$",
+		},
+		{
+			name:          "Returns error for unsupported verb",
+			allowedVerbs:  []string{"get", "escalate"},
+			expectedError: "unsupported verb 'escalate'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejectMethods, err := getKubeAPIProxyRejectMethods(tt.allowedVerbs)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, rejectMethods)
+		})
+	}
+}
+
+func TestGetKubeAPIProxyAcceptPathsOnlyAllowsNamespace(t *testing.T) {
+	paths := getKubeAPIProxyAcceptPaths("test-namespace")
+	assert.Contains(t, paths, "^/api/v1/namespaces/test-namespace/.*")
+	assert.Contains(t, paths, "^/apis/[^/]+/[^/]+/namespaces/test-namespace/.*")
+	for _, path := range paths {
+		assert.NotEqual(t, "^/api/v1/namespaces/test-namespace$", path, "Should not allow access to namespace object")
+	}
+}
@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// legacyDeploymentRequeueDelay is how long to wait before checking whether a legacy deployment has been drained. Pods
+// created by legacy deployments may not be watched by the controller, so we cannot rely on events to trigger a
+// reconcile.
+const legacyDeploymentRequeueDelay = 5 * time.Second
+
+// reconcileLegacyDeployment checks whether the workspace deployment on the cluster was created by an older version of
+// the DevWorkspace Operator in a way that cannot be updated to match specDeployment (e.g. its label selector, which
+// is immutable, differs). Such deployments are recreated: the deployment is first scaled down and its pods are
+// allowed to terminate, so that persistent volumes used by the workspace are detached, and then the deployment is
+// deleted so that it can be recreated. Workspace PVCs are not owned by the deployment and are reattached when the new
+// deployment starts.
+//
+// Returns nil if no legacy deployment exists, or a RetryError while the legacy deployment is being recreated.
+func reconcileLegacyDeployment(workspace *common.DevWorkspaceWithConfig, specDeployment *appsv1.Deployment, clusterAPI sync.ClusterAPI) error {
+	// Legacy deployments may not match the label selector used for the controller's cache, so we read them directly
+	// from the cluster.
+	client := clusterAPI.NonCachingClient
+	if client == nil {
+		client = clusterAPI.Client
+	}
+
+	clusterDeployment := &appsv1.Deployment{}
+	namespacedName := types.NamespacedName{Name: specDeployment.Name, Namespace: specDeployment.Namespace}
+	if err := client.Get(clusterAPI.Ctx, namespacedName, clusterDeployment); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if clusterDeployment.DeletionTimestamp != nil {
+		return &dwerrors.RetryError{
+			Message:      "Waiting for previous workspace deployment to be deleted",
+			RequeueAfter: legacyDeploymentRequeueDelay,
+		}
+	}
+
+	if isCompatibleDeployment(specDeployment, clusterDeployment) {
+		return nil
+	}
+
+	if !metav1.IsControlledBy(clusterDeployment, workspace.DevWorkspace) {
+		return &dwerrors.FailError{
+			Message: fmt.Sprintf("Deployment %s already exists and is not owned by this DevWorkspace", clusterDeployment.Name),
+		}
+	}
+
+	log := clusterAPI.Logger.WithValues("deployment", clusterDeployment.Name)
+
+	// Scale down the legacy deployment and wait for its pods to terminate before deleting it.
+	if clusterDeployment.Spec.Replicas == nil || *clusterDeployment.Spec.Replicas != 0 {
+		log.Info("Scaling down deployment created by an older version of the DevWorkspace Operator before recreating it")
+		clusterDeployment.Spec.Replicas = pointer.Int32(0)
+		if err := client.Update(clusterAPI.Ctx, clusterDeployment); err != nil {
+			if k8sErrors.IsConflict(err) {
+				return &dwerrors.RetryError{Message: "Scaling down previous workspace deployment", Err: err}
+			}
+			return err
+		}
+		return &dwerrors.RetryError{
+			Message:      "Scaling down previous workspace deployment",
+			RequeueAfter: legacyDeploymentRequeueDelay,
+		}
+	}
+
+	drained, err := legacyDeploymentPodsTerminated(clusterDeployment, client, clusterAPI)
+	if err != nil {
+		return err
+	}
+	if !drained {
+		return &dwerrors.RetryError{
+			Message:      "Waiting for pods of previous workspace deployment to terminate",
+			RequeueAfter: legacyDeploymentRequeueDelay,
+		}
+	}
+
+	log.Info("Deleting deployment created by an older version of the DevWorkspace Operator so that it can be recreated")
+	if err := client.Delete(clusterAPI.Ctx, clusterDeployment, k8sclient.Preconditions{UID: &clusterDeployment.UID}); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return &dwerrors.RetryError{
+		Message:      "Recreating workspace deployment created by an older version of the DevWorkspace Operator",
+		RequeueAfter: legacyDeploymentRequeueDelay,
+	}
+}
+
+// isCompatibleDeployment returns whether the cluster deployment can be updated to match the spec deployment. Fields
+// that are immutable after a deployment is created must match.
+func isCompatibleDeployment(specDeployment, clusterDeployment *appsv1.Deployment) bool {
+	return cmp.Equal(specDeployment.Spec.Selector, clusterDeployment.Spec.Selector)
+}
+
+// legacyDeploymentPodsTerminated returns whether all pods matching the label selector of a deployment have terminated.
+func legacyDeploymentPodsTerminated(deployment *appsv1.Deployment, client k8sclient.Client, clusterAPI sync.ClusterAPI) (bool, error) {
+	if deployment.Status.Replicas != 0 {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return false, &dwerrors.FailError{Message: fmt.Sprintf("Failed to read label selector of deployment %s", deployment.Name), Err: err}
+	}
+	pods := &corev1.PodList{}
+	if err := client.List(clusterAPI.Ctx, pods, k8sclient.InNamespace(deployment.Namespace), k8sclient.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return false, err
+	}
+	return len(pods.Items) == 0, nil
+}
@@ -0,0 +1,158 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	legacyTestNamespace   = "test-namespace"
+	legacyTestWorkspaceID = "test-workspaceid"
+)
+
+func getLegacyTestWorkspace() *common.DevWorkspaceWithConfig {
+	return testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+}
+
+func getLegacyTestDeployment(workspace *common.DevWorkspaceWithConfig, selector map[string]string, replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.DeploymentName(legacyTestWorkspaceID, nil),
+			Namespace: legacyTestNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: dw.SchemeGroupVersion.String(),
+					Kind:       "DevWorkspace",
+					Name:       workspace.Name,
+					UID:        workspace.UID,
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas: replicas,
+		},
+	}
+}
+
+func getLegacyTestClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	fakeClient := fake.NewClientBuilder().WithObjects(objs...).Build()
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Logger:           logr.Discard(),
+		Ctx:              context.Background(),
+	}
+}
+
+func TestCompatibleDeploymentIsNotRecreated(t *testing.T) {
+	workspace := getLegacyTestWorkspace()
+	specDeployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	clusterAPI := getLegacyTestClusterAPI(specDeployment.DeepCopy())
+
+	err := reconcileLegacyDeployment(workspace, specDeployment, clusterAPI)
+	assert.NoError(t, err, "Should not return error for compatible deployment")
+
+	err = reconcileLegacyDeployment(workspace, specDeployment, getLegacyTestClusterAPI())
+	assert.NoError(t, err, "Should not return error if deployment does not exist")
+}
+
+func TestLegacyDeploymentIsDrainedAndRecreated(t *testing.T) {
+	workspace := getLegacyTestWorkspace()
+	legacySelector := map[string]string{"legacy-label": legacyTestWorkspaceID}
+	specDeployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	legacyDeployment := getLegacyTestDeployment(workspace, legacySelector, 1)
+	legacyPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy-pod",
+			Namespace: legacyTestNamespace,
+			Labels:    legacySelector,
+		},
+	}
+	clusterAPI := getLegacyTestClusterAPI(legacyDeployment, legacyPod)
+	deploymentNN := types.NamespacedName{Name: legacyDeployment.Name, Namespace: legacyDeployment.Namespace}
+
+	// Legacy deployment should be scaled down first
+	err := reconcileLegacyDeployment(workspace, specDeployment, clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should return RetryError while scaling down deployment")
+	clusterDeployment := &appsv1.Deployment{}
+	if !assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, deploymentNN, clusterDeployment)) {
+		return
+	}
+	assert.Equal(t, int32(0), *clusterDeployment.Spec.Replicas, "Should scale down legacy deployment")
+
+	// Deployment should not be deleted until its pods terminate
+	clusterDeployment.Status.Replicas = 0
+	if !assert.NoError(t, clusterAPI.Client.Update(clusterAPI.Ctx, clusterDeployment)) {
+		return
+	}
+	err = reconcileLegacyDeployment(workspace, specDeployment, clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should return RetryError while pods are terminating")
+	assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, deploymentNN, &appsv1.Deployment{}), "Should not delete deployment while pods exist")
+
+	// Deployment should be deleted once its pods have terminated
+	if !assert.NoError(t, clusterAPI.Client.Delete(clusterAPI.Ctx, legacyPod)) {
+		return
+	}
+	err = reconcileLegacyDeployment(workspace, specDeployment, clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should return RetryError when deployment is deleted")
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, deploymentNN, &appsv1.Deployment{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete legacy deployment")
+
+	err = reconcileLegacyDeployment(workspace, specDeployment, clusterAPI)
+	assert.NoError(t, err, "Should not return error once legacy deployment is removed")
+}
+
+func TestLegacyDeploymentNotOwnedByWorkspaceIsNotDeleted(t *testing.T) {
+	workspace := getLegacyTestWorkspace()
+	specDeployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	otherDeployment := getLegacyTestDeployment(workspace, map[string]string{"other-label": "other"}, 1)
+	otherDeployment.OwnerReferences = nil
+	clusterAPI := getLegacyTestClusterAPI(otherDeployment)
+
+	err := reconcileLegacyDeployment(workspace, specDeployment, clusterAPI)
+	assert.IsType(t, &dwerrors.FailError{}, err, "Should return FailError for deployments not owned by workspace")
+	clusterDeployment := &appsv1.Deployment{}
+	if assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: otherDeployment.Name, Namespace: legacyTestNamespace}, clusterDeployment)) {
+		assert.Equal(t, int32(1), *clusterDeployment.Spec.Replicas, "Should not scale down deployment not owned by workspace")
+	}
+}
@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// namespaceNameLabel is the label set automatically on all namespaces that contains the namespace's name
+const namespaceNameLabel = "kubernetes.io/metadata.name"
+
+var defaultOpenShiftIngressNamespaceSelector = &metav1.LabelSelector{
+	MatchLabels: map[string]string{
+		"network.openshift.io/policy-group": "ingress",
+	},
+}
+
+var defaultKubernetesIngressNamespaceSelector = &metav1.LabelSelector{
+	MatchLabels: map[string]string{
+		namespaceNameLabel: "ingress-nginx",
+	},
+}
+
+var networkPolicyLabels = map[string]string{
+	"app.kubernetes.io/name":      "devworkspace-workspaces",
+	"app.kubernetes.io/part-of":   "devworkspace-operator",
+	constants.DevWorkspaceIDLabel: "",
+}
+
+// SyncNetworkPolicy creates or updates the NetworkPolicy that isolates workspace pods in the DevWorkspace's namespace
+// if network isolation is enabled, and removes it otherwise. The NetworkPolicy is shared by all DevWorkspaces in the
+// namespace, and only allows traffic to workspace pods from the same namespace, the ingress controller, the operator's
+// namespace and any additionally allowed namespaces and IP ranges.
+func SyncNetworkPolicy(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	isolationConfig := workspace.Config.Workspace.NetworkIsolation
+	if isolationConfig == nil || !pointer.BoolDeref(isolationConfig.Enable, false) {
+		return deleteNetworkPolicy(workspace.Namespace, clusterAPI)
+	}
+	specPolicy, err := getSpecNetworkPolicy(workspace.Namespace, isolationConfig)
+	if err != nil {
+		return err
+	}
+	if _, err := sync.SyncObjectWithCluster(specPolicy, clusterAPI); err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+	return nil
+}
+
+func deleteNetworkPolicy(namespace string, clusterAPI sync.ClusterAPI) error {
+	policy := &networkingv1.NetworkPolicy{}
+	namespacedName := types.NamespacedName{
+		Name:      constants.WorkspaceNetworkPolicyName,
+		Namespace: namespace,
+	}
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, namespacedName, policy)
+	switch {
+	case err == nil:
+		clusterAPI.Logger.Info("Deleting network policy as network isolation is disabled", "name", constants.WorkspaceNetworkPolicyName)
+		if err := clusterAPI.Client.Delete(clusterAPI.Ctx, policy); err != nil && !k8sErrors.IsNotFound(err) {
+			return &dwerrors.RetryError{Message: fmt.Sprintf("failed to delete network policy %s in namespace %s", constants.WorkspaceNetworkPolicyName, namespace), Err: err}
+		}
+		return nil
+	case k8sErrors.IsNotFound(err):
+		return nil
+	default:
+		return err
+	}
+}
+
+func getSpecNetworkPolicy(namespace string, isolationConfig *v1alpha1.NetworkIsolationConfig) (*networkingv1.NetworkPolicy, error) {
+	operatorNamespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return nil, err
+	}
+
+	ingressNamespaceSelector := isolationConfig.IngressNamespaceSelector
+	if ingressNamespaceSelector == nil {
+		if infrastructure.IsOpenShift() {
+			ingressNamespaceSelector = defaultOpenShiftIngressNamespaceSelector
+		} else {
+			ingressNamespaceSelector = defaultKubernetesIngressNamespaceSelector
+		}
+	}
+
+	peers := []networkingv1.NetworkPolicyPeer{
+		{
+			// Allow traffic from all pods in the same namespace
+			PodSelector: &metav1.LabelSelector{},
+		},
+		{
+			NamespaceSelector: ingressNamespaceSelector.DeepCopy(),
+		},
+	}
+	allowedNamespaces := append([]string{operatorNamespace}, isolationConfig.AllowedNamespaces...)
+	for _, allowedNamespace := range allowedNamespaces {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					namespaceNameLabel: allowedNamespace,
+				},
+			},
+		})
+	}
+
+	var invalidCIDRs []string
+	for _, cidr := range isolationConfig.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			invalidCIDRs = append(invalidCIDRs, cidr)
+			continue
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{
+				CIDR: cidr,
+			},
+		})
+	}
+	if len(invalidCIDRs) > 0 {
+		return nil, &dwerrors.FailError{Message: fmt.Sprintf("Invalid CIDRs in network isolation configuration: %s", strings.Join(invalidCIDRs, ", "))}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.WorkspaceNetworkPolicyName,
+			Namespace: namespace,
+			Labels:    networkPolicyLabels,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      constants.DevWorkspaceIDLabel,
+						Operator: metav1.LabelSelectorOpExists,
+					},
+				},
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					From: peers,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}, nil
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+func getNetworkPolicyTestConfig(enable bool) *v1alpha1.WorkspaceConfig {
+	return &v1alpha1.WorkspaceConfig{
+		NetworkIsolation: &v1alpha1.NetworkIsolationConfig{
+			Enable:            pointer.Bool(enable),
+			AllowedNamespaces: []string{"monitoring"},
+			AllowedCIDRs:      []string{"10.0.0.0/16"},
+		},
+	}
+}
+
+func TestSyncNetworkPolicy(t *testing.T) {
+	clusterAPI := getProjectBackupTestClusterAPI(t)
+	workspace := getPriorityTestWorkspace(getNetworkPolicyTestConfig(true), nil)
+	workspace.Namespace = "test-namespace"
+
+	err := SyncNetworkPolicy(workspace, clusterAPI)
+	if err != nil {
+		assert.IsType(t, &dwerrors.RetryError{}, err, "Should retry after creating network policy")
+		err = SyncNetworkPolicy(workspace, clusterAPI)
+	}
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	policy := &networkingv1.NetworkPolicy{}
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: constants.WorkspaceNetworkPolicyName, Namespace: "test-namespace"}, policy)
+	if !assert.NoError(t, err, "Network policy should be created") {
+		return
+	}
+	assert.Contains(t, policy.Labels, constants.DevWorkspaceIDLabel, "Network policy should be labelled to be cached")
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+	assert.Equal(t, constants.DevWorkspaceIDLabel, policy.Spec.PodSelector.MatchExpressions[0].Key, "Network policy should select workspace pods")
+	if !assert.Len(t, policy.Spec.Ingress, 1) {
+		return
+	}
+	assert.Equal(t, []networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{}},
+		{NamespaceSelector: defaultKubernetesIngressNamespaceSelector},
+		{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: "devworkspace-controller"}}},
+		{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: "monitoring"}}},
+		{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}},
+	}, policy.Spec.Ingress[0].From)
+
+	workspace.Config.Workspace.NetworkIsolation.Enable = pointer.Bool(false)
+	assert.NoError(t, SyncNetworkPolicy(workspace, clusterAPI))
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: constants.WorkspaceNetworkPolicyName, Namespace: "test-namespace"}, policy)
+	assert.True(t, k8sErrors.IsNotFound(err), "Network policy should be deleted when network isolation is disabled")
+}
+
+func TestSyncNetworkPolicyUsesIngressNamespaceSelector(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, "devworkspace-controller")
+	infrastructure.InitializeForTesting(infrastructure.OpenShiftv4)
+	workspace := getPriorityTestWorkspace(getNetworkPolicyTestConfig(true), nil)
+
+	policy, err := getSpecNetworkPolicy("test-namespace", workspace.Config.Workspace.NetworkIsolation)
+	if assert.NoError(t, err) {
+		assert.Equal(t, defaultOpenShiftIngressNamespaceSelector, policy.Spec.Ingress[0].From[1].NamespaceSelector)
+	}
+
+	customSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"ingress": "true"}}
+	workspace.Config.Workspace.NetworkIsolation.IngressNamespaceSelector = customSelector
+	policy, err = getSpecNetworkPolicy("test-namespace", workspace.Config.Workspace.NetworkIsolation)
+	if assert.NoError(t, err) {
+		assert.Equal(t, customSelector, policy.Spec.Ingress[0].From[1].NamespaceSelector)
+	}
+}
+
+func TestSyncNetworkPolicyFailsOnInvalidCIDR(t *testing.T) {
+	clusterAPI := getProjectBackupTestClusterAPI(t)
+	workspaceConfig := getNetworkPolicyTestConfig(true)
+	workspaceConfig.NetworkIsolation.AllowedCIDRs = []string{"10.0.0.0/16", "not-a-cidr"}
+	workspace := getPriorityTestWorkspace(workspaceConfig, nil)
+
+	err := SyncNetworkPolicy(workspace, clusterAPI)
+	if assert.IsType(t, &dwerrors.FailError{}, err) {
+		assert.Contains(t, err.Error(), "not-a-cidr")
+	}
+}
@@ -0,0 +1,225 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// NodeFailureRecoveryForceDeletePolicy is the workspace.nodeFailureRecovery.policy that enables force-deleting
+// DevWorkspace pods stuck on unavailable nodes.
+const NodeFailureRecoveryForceDeletePolicy = "ForceDelete"
+
+// RecoverFromNodeFailure handles DevWorkspace pods that cannot be cleaned up because the node they were running on is
+// no longer available, if enabled by the workspace.nodeFailureRecovery configuration. Recovery is done in two steps,
+// each in a separate reconcile:
+//
+// 1. Pods that have been terminating on an unavailable node for longer than the configured timeout are force-deleted,
+// allowing the workspace deployment to create a replacement pod.
+//
+// 2. Once no DevWorkspace pods remain on the unavailable node, VolumeAttachments that keep the persistent volumes
+// used by pending DevWorkspace pods attached to that node are deleted, allowing the volumes to be attached to the
+// node the replacement pod is scheduled on.
+//
+// Returns a description of each action taken, and how long to wait before pods that are not yet past the timeout
+// should be checked again.
+func RecoverFromNodeFailure(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (actions []string, requeueAfter time.Duration, err error) {
+	recoveryConfig := workspace.Config.Workspace.NodeFailureRecovery
+	if recoveryConfig == nil || recoveryConfig.Policy != NodeFailureRecoveryForceDeletePolicy {
+		return nil, 0, nil
+	}
+	// Nodes and VolumeAttachments are cluster-scoped, so they cannot be read in namespace-scoped mode
+	if infrastructure.IsNamespaceScoped() {
+		return nil, 0, nil
+	}
+	terminationTimeout, err := time.ParseDuration(recoveryConfig.TerminationTimeout)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid workspace.nodeFailureRecovery.terminationTimeout: %w", err)
+	}
+
+	podList := &corev1.PodList{}
+	workspaceIDLabel := k8sclient.MatchingLabels{constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, podList, k8sclient.InNamespace(workspace.Namespace), workspaceIDLabel); err != nil {
+		return nil, 0, err
+	}
+
+	nodes := &nodeAvailability{clusterAPI: clusterAPI, available: map[string]bool{}}
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		if pod.DeletionTimestamp == nil || pod.Spec.NodeName == "" || isJobPod(pod) {
+			continue
+		}
+		available, err := nodes.isAvailable(pod.Spec.NodeName)
+		if err != nil {
+			return actions, 0, err
+		}
+		if available {
+			continue
+		}
+		if remaining := terminationTimeout - time.Since(pod.DeletionTimestamp.Time); remaining > 0 {
+			if requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+		err = clusterAPI.Client.Delete(clusterAPI.Ctx, pod, k8sclient.GracePeriodSeconds(0))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return actions, 0, fmt.Errorf("failed to force-delete pod %s: %w", pod.Name, err)
+		}
+		actions = append(actions, fmt.Sprintf("Force-deleted pod %s, which was stuck terminating on unavailable node %s", pod.Name, pod.Spec.NodeName))
+	}
+	if len(actions) > 0 {
+		// Volume attachments are only removed once pods on the unavailable node have been deleted
+		return actions, 0, nil
+	}
+
+	for idx := range podList.Items {
+		pod := &podList.Items[idx]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName == "" || isJobPod(pod) {
+			continue
+		}
+		detached, err := detachVolumesFromUnavailableNodes(pod, nodes, clusterAPI)
+		actions = append(actions, detached...)
+		if err != nil {
+			return actions, 0, err
+		}
+	}
+	return actions, requeueAfter, nil
+}
+
+// detachVolumesFromUnavailableNodes deletes VolumeAttachments that attach the persistent volumes used by a pod
+// to unavailable nodes other than the one the pod is scheduled on. Volumes that are still used by DevWorkspace pods on
+// the unavailable node are not detached.
+func detachVolumesFromUnavailableNodes(pod *corev1.Pod, nodes *nodeAvailability, clusterAPI sync.ClusterAPI) (actions []string, err error) {
+	var attachments *storagev1.VolumeAttachmentList
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: claimName, Namespace: pod.Namespace}, pvc)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+			return actions, err
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+		if attachments == nil {
+			attachments = &storagev1.VolumeAttachmentList{}
+			if err := clusterAPI.NonCachingClient.List(clusterAPI.Ctx, attachments); err != nil {
+				return actions, fmt.Errorf("failed to list volume attachments: %w", err)
+			}
+		}
+		for attachmentIdx := range attachments.Items {
+			attachment := &attachments.Items[attachmentIdx]
+			source := attachment.Spec.Source.PersistentVolumeName
+			if source == nil || *source != pvc.Spec.VolumeName || attachment.Spec.NodeName == pod.Spec.NodeName || attachment.DeletionTimestamp != nil {
+				continue
+			}
+			available, err := nodes.isAvailable(attachment.Spec.NodeName)
+			if err != nil {
+				return actions, err
+			}
+			if available {
+				continue
+			}
+			inUse, err := isClaimUsedOnNode(claimName, pod.Namespace, attachment.Spec.NodeName, clusterAPI)
+			if err != nil {
+				return actions, err
+			}
+			if inUse {
+				continue
+			}
+			if err := clusterAPI.NonCachingClient.Delete(clusterAPI.Ctx, attachment); err != nil && !k8sErrors.IsNotFound(err) {
+				return actions, fmt.Errorf("failed to delete volume attachment %s: %w", attachment.Name, err)
+			}
+			actions = append(actions, fmt.Sprintf("Detached volume %s for PVC %s from unavailable node %s", pvc.Spec.VolumeName, claimName, attachment.Spec.NodeName))
+		}
+	}
+	return actions, nil
+}
+
+// isClaimUsedOnNode checks whether any DevWorkspace pod in a namespace that uses a PVC is still present on a node.
+// This is necessary as PVCs using the per-user storage strategy are shared by all DevWorkspaces in a namespace.
+func isClaimUsedOnNode(claimName, namespace, nodeName string, clusterAPI sync.ClusterAPI) (bool, error) {
+	podList := &corev1.PodList{}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, podList, k8sclient.InNamespace(namespace), k8sclient.HasLabels{constants.DevWorkspaceIDLabel}); err != nil {
+		return false, err
+	}
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != nodeName {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// nodeAvailability checks whether nodes are available, caching results for the duration of a reconcile. Nodes are
+// read using the non-caching client as the operator does not otherwise need to watch nodes.
+type nodeAvailability struct {
+	clusterAPI sync.ClusterAPI
+	available  map[string]bool
+}
+
+// isAvailable returns whether a node exists and is ready.
+func (n *nodeAvailability) isAvailable(nodeName string) (bool, error) {
+	if available, ok := n.available[nodeName]; ok {
+		return available, nil
+	}
+	node := &corev1.Node{}
+	available := false
+	err := n.clusterAPI.NonCachingClient.Get(n.clusterAPI.Ctx, types.NamespacedName{Name: nodeName}, node)
+	switch {
+	case err == nil:
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				available = condition.Status == corev1.ConditionTrue
+			}
+		}
+	case k8sErrors.IsNotFound(err):
+		available = false
+	default:
+		return false, fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	n.available[nodeName] = available
+	return available, nil
+}
+
+func isJobPod(pod *corev1.Pod) bool {
+	_, ok := pod.Labels["job-name"]
+	return ok
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	recoveryTestNamespace   = "test-namespace"
+	recoveryTestWorkspaceID = "test-workspaceid"
+	recoveryTestClaim       = "claim-devworkspace"
+	recoveryTestVolume      = "pvc-1234"
+)
+
+func getRecoveryTestWorkspace(policy string) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Config.Workspace.NodeFailureRecovery = &controllerv1alpha1.NodeFailureRecoveryConfig{
+		Policy:             policy,
+		TerminationTimeout: "5m",
+	}
+	return workspace
+}
+
+func getRecoveryTestPod(name, nodeName string, deletedSince time.Duration) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: recoveryTestNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel: recoveryTestWorkspaceID,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Volumes: []corev1.Volume{
+				{
+					Name: "storage",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: recoveryTestClaim},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+		},
+	}
+	if deletedSince > 0 {
+		pod.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-deletedSince)}
+		pod.Status.Phase = corev1.PodRunning
+	}
+	return pod
+}
+
+func getRecoveryTestNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func getRecoveryTestObjects() []client.Object {
+	return []client.Object{
+		getRecoveryTestNode("failed-node", corev1.ConditionUnknown),
+		getRecoveryTestNode("healthy-node", corev1.ConditionTrue),
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: recoveryTestClaim, Namespace: recoveryTestNamespace},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: recoveryTestVolume},
+		},
+		&storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-attachment"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: "csi.example.com",
+				NodeName: "failed-node",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: pointer.String(recoveryTestVolume)},
+			},
+		},
+	}
+}
+
+func getRecoveryTestClusterAPI(objs ...client.Object) sync.ClusterAPI {
+	fakeClient := testutil.NewFakeClient(objs...)
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           testutil.Scheme,
+		Ctx:              context.Background(),
+	}
+}
+
+func TestNodeFailureRecoveryDisabledByDefault(t *testing.T) {
+	objs := append(getRecoveryTestObjects(), getRecoveryTestPod("stuck-pod", "failed-node", 10*time.Minute))
+	clusterAPI := getRecoveryTestClusterAPI(objs...)
+
+	actions, requeueAfter, err := RecoverFromNodeFailure(getRecoveryTestWorkspace("None"), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.Zero(t, requeueAfter)
+	err = clusterAPI.Client.Get(context.Background(), types.NamespacedName{Name: "stuck-pod", Namespace: recoveryTestNamespace}, &corev1.Pod{})
+	assert.NoError(t, err, "Pod should not be deleted when recovery is disabled")
+}
+
+func TestNodeFailureRecoveryDisabledInNamespaceScopedMode(t *testing.T) {
+	t.Setenv(infrastructure.WatchedNamespacesEnvVar, recoveryTestNamespace)
+	objs := append(getRecoveryTestObjects(), getRecoveryTestPod("stuck-pod", "failed-node", 10*time.Minute))
+	clusterAPI := getRecoveryTestClusterAPI(objs...)
+
+	actions, requeueAfter, err := RecoverFromNodeFailure(getRecoveryTestWorkspace(NodeFailureRecoveryForceDeletePolicy), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.Zero(t, requeueAfter)
+	err = clusterAPI.Client.Get(context.Background(), types.NamespacedName{Name: "stuck-pod", Namespace: recoveryTestNamespace}, &corev1.Pod{})
+	assert.NoError(t, err, "Pod should not be deleted in namespace-scoped mode")
+}
+
+func TestNodeFailureRecoveryWaitsForTerminationTimeout(t *testing.T) {
+	objs := append(getRecoveryTestObjects(), getRecoveryTestPod("stuck-pod", "failed-node", 2*time.Minute))
+	clusterAPI := getRecoveryTestClusterAPI(objs...)
+
+	actions, requeueAfter, err := RecoverFromNodeFailure(getRecoveryTestWorkspace(NodeFailureRecoveryForceDeletePolicy), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.InDelta(t, float64(3*time.Minute), float64(requeueAfter), float64(time.Second))
+}
+
+func TestNodeFailureRecoveryIgnoresPodsOnHealthyNodes(t *testing.T) {
+	objs := append(getRecoveryTestObjects(), getRecoveryTestPod("terminating-pod", "healthy-node", 10*time.Minute))
+	clusterAPI := getRecoveryTestClusterAPI(objs...)
+
+	actions, requeueAfter, err := RecoverFromNodeFailure(getRecoveryTestWorkspace(NodeFailureRecoveryForceDeletePolicy), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	assert.Zero(t, requeueAfter)
+}
+
+func TestNodeFailureRecoveryForceDeletesPodBeforeDetachingVolume(t *testing.T) {
+	objs := append(getRecoveryTestObjects(),
+		getRecoveryTestPod("stuck-pod", "failed-node", 10*time.Minute),
+		getRecoveryTestPod("new-pod", "healthy-node", 0))
+	clusterAPI := getRecoveryTestClusterAPI(objs...)
+	workspace := getRecoveryTestWorkspace(NodeFailureRecoveryForceDeletePolicy)
+
+	actions, _, err := RecoverFromNodeFailure(workspace, clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Force-deleted pod stuck-pod, which was stuck terminating on unavailable node failed-node"}, actions)
+	err = clusterAPI.Client.Get(context.Background(), types.NamespacedName{Name: "stuck-pod", Namespace: recoveryTestNamespace}, &corev1.Pod{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Stuck pod should be deleted")
+	err = clusterAPI.Client.Get(context.Background(), types.NamespacedName{Name: "csi-attachment"}, &storagev1.VolumeAttachment{})
+	assert.NoError(t, err, "Volume should not be detached while the pod on the failed node exists")
+
+	actions, _, err = RecoverFromNodeFailure(workspace, clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Detached volume pvc-1234 for PVC claim-devworkspace from unavailable node failed-node"}, actions)
+	err = clusterAPI.Client.Get(context.Background(), types.NamespacedName{Name: "csi-attachment"}, &storagev1.VolumeAttachment{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Volume attachment should be deleted")
+}
+
+func TestNodeFailureRecoveryDoesNotDetachVolumeUsedOnNode(t *testing.T) {
+	otherWorkspacePod := getRecoveryTestPod("other-workspace-pod", "failed-node", 1*time.Minute)
+	otherWorkspacePod.Labels[constants.DevWorkspaceIDLabel] = "other-workspaceid"
+	objs := append(getRecoveryTestObjects(), getRecoveryTestPod("new-pod", "healthy-node", 0), otherWorkspacePod)
+	clusterAPI := getRecoveryTestClusterAPI(objs...)
+
+	actions, _, err := RecoverFromNodeFailure(getRecoveryTestWorkspace(NodeFailureRecoveryForceDeletePolicy), clusterAPI)
+	assert.NoError(t, err)
+	assert.Empty(t, actions)
+	err = clusterAPI.Client.Get(context.Background(), types.NamespacedName{Name: "csi-attachment"}, &storagev1.VolumeAttachment{})
+	assert.NoError(t, err, "Volume should not be detached while it is used by another workspace's pod on the node")
+}
+
+func TestNodeFailureRecoveryTreatsMissingNodeAsUnavailable(t *testing.T) {
+	clusterAPI := getRecoveryTestClusterAPI(getRecoveryTestPod("stuck-pod", "deleted-node", 10*time.Minute))
+
+	actions, _, err := RecoverFromNodeFailure(getRecoveryTestWorkspace(NodeFailureRecoveryForceDeletePolicy), clusterAPI)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Force-deleted pod stuck-pod, which was stuck terminating on unavailable node deleted-node"}, actions)
+}
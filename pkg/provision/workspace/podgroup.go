@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"reflect"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// podGroupLabel is the label used by the co-scheduling plugin to assign pods to a PodGroup
+const podGroupLabel = "scheduling.x-k8s.io/pod-group"
+
+var podGroupGVK = schema.GroupVersionKind{
+	Group:   "scheduling.x-k8s.io",
+	Version: "v1alpha1",
+	Kind:    "PodGroup",
+}
+
+// NeedsPodGroup returns whether the pods of a DevWorkspace should be scheduled together using a PodGroup. This is
+// the case if gang scheduling is enabled and the DevWorkspace runs in more than one pod.
+func NeedsPodGroup(workspace *common.DevWorkspaceWithConfig) bool {
+	gangScheduling := workspace.Config.Workspace.GangScheduling
+	if gangScheduling == nil || !pointer.BoolDeref(gangScheduling.Enable, false) {
+		return false
+	}
+	return countWorkspacePods(&workspace.Spec.Template) > 1
+}
+
+// SyncPodGroupToCluster creates or updates the PodGroup for a DevWorkspace if its pods should be scheduled together.
+// A PodGroup that is no longer needed is left in place, as it does not affect pods that are not labelled with it, and
+// is removed along with the DevWorkspace.
+func SyncPodGroupToCluster(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	if !NeedsPodGroup(workspace) {
+		return nil
+	}
+	specPodGroup := getSpecPodGroup(workspace)
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specPodGroup, clusterAPI.Scheme); err != nil {
+		return err
+	}
+
+	// PodGroups are read using the non-caching client to avoid starting an informer for them
+	clusterPodGroup := &unstructured.Unstructured{}
+	clusterPodGroup.SetGroupVersionKind(podGroupGVK)
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: specPodGroup.GetName(), Namespace: workspace.Namespace}, clusterPodGroup)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		clusterAPI.Logger.Info("Creating PodGroup for workspace pods", "name", specPodGroup.GetName())
+		err := clusterAPI.Client.Create(clusterAPI.Ctx, specPodGroup)
+		if meta.IsNoMatchError(err) {
+			return &dwerrors.FailError{Message: "Gang scheduling is enabled but the PodGroup API is not available on the cluster", Err: err}
+		}
+		return err
+	case meta.IsNoMatchError(err):
+		return &dwerrors.FailError{Message: "Gang scheduling is enabled but the PodGroup API is not available on the cluster", Err: err}
+	case err != nil:
+		return err
+	}
+
+	if reflect.DeepEqual(clusterPodGroup.Object["spec"], specPodGroup.Object["spec"]) {
+		return nil
+	}
+	clusterAPI.Logger.Info("Updating PodGroup for workspace pods", "name", specPodGroup.GetName())
+	clusterPodGroup.Object["spec"] = specPodGroup.Object["spec"]
+	return clusterAPI.Client.Update(clusterAPI.Ctx, clusterPodGroup)
+}
+
+func getSpecPodGroup(workspace *common.DevWorkspaceWithConfig) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"minMember": int64(countWorkspacePods(&workspace.Spec.Template)),
+	}
+	if timeout := workspace.Config.Workspace.GangScheduling.ScheduleTimeoutSeconds; timeout != nil {
+		spec["scheduleTimeoutSeconds"] = int64(*timeout)
+	}
+	podGroup := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	podGroup.SetGroupVersionKind(podGroupGVK)
+	podGroup.SetName(common.PodGroupName(workspace.Status.DevWorkspaceId))
+	podGroup.SetNamespace(workspace.Namespace)
+	podGroup.SetLabels(map[string]string{
+		constants.DevWorkspaceIDLabel:   workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel: workspace.Name,
+	})
+	return podGroup
+}
+
+// addPodGroupLabel adds the PodGroup label to the pod template labels of a DevWorkspace deployment if the DevWorkspace's
+// pods should be scheduled together.
+func addPodGroupLabel(workspace *common.DevWorkspaceWithConfig, podLabels map[string]string) {
+	if NeedsPodGroup(workspace) {
+		podLabels[podGroupLabel] = common.PodGroupName(workspace.Status.DevWorkspaceId)
+	}
+}
+
+// countWorkspacePods returns the number of pods a DevWorkspace runs in: one for the DevWorkspace's containers and one
+// for its background containers.
+func countWorkspacePods(workspace *dw.DevWorkspaceTemplateSpec) int32 {
+	var hasWorkspaceContainers, hasBackgroundContainers bool
+	for _, component := range workspace.Components {
+		if component.Container == nil {
+			continue
+		}
+		if component.Attributes.GetBoolean(constants.BackgroundComponentAttribute, nil) {
+			hasBackgroundContainers = true
+		} else {
+			hasWorkspaceContainers = true
+		}
+	}
+	var count int32
+	if hasWorkspaceContainers {
+		count++
+	}
+	if hasBackgroundContainers {
+		count++
+	}
+	return count
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getPodGroupTestWorkspace(enable bool, background bool) *common.DevWorkspaceWithConfig {
+	components := []dw.Component{getBackgroundTestComponent("tools", false, "")}
+	if background {
+		components = append(components, getBackgroundTestComponent("sync", true, ""))
+	}
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Spec.Template.Components = components
+	workspace.Config.Workspace.GangScheduling = &v1alpha1.GangSchedulingConfig{
+		Enable:                 pointer.Bool(enable),
+		ScheduleTimeoutSeconds: pointer.Int32(60),
+	}
+	return workspace
+}
+
+func getPodGroupTestClusterAPI() sync.ClusterAPI {
+	fakeClient := fake.NewClientBuilder().WithScheme(testutil.Scheme).Build()
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           testutil.Scheme,
+		Logger:           logr.Discard(),
+		Ctx:              context.Background(),
+	}
+}
+
+func TestNeedsPodGroup(t *testing.T) {
+	assert.True(t, NeedsPodGroup(getPodGroupTestWorkspace(true, true)), "Should need PodGroup for workspace with background components")
+	assert.False(t, NeedsPodGroup(getPodGroupTestWorkspace(true, false)), "Should not need PodGroup for workspace that runs in a single pod")
+	assert.False(t, NeedsPodGroup(getPodGroupTestWorkspace(false, true)), "Should not need PodGroup when gang scheduling is disabled")
+
+	allBackground := getPodGroupTestWorkspace(true, true)
+	allBackground.Spec.Template.Components[0].Attributes = attributes.Attributes{}.PutBoolean(constants.BackgroundComponentAttribute, true)
+	assert.False(t, NeedsPodGroup(allBackground), "Should not need PodGroup when all components run in the background")
+}
+
+func TestSyncPodGroupToCluster(t *testing.T) {
+	workspace := getPodGroupTestWorkspace(true, true)
+	clusterAPI := getPodGroupTestClusterAPI()
+
+	if !assert.NoError(t, SyncPodGroupToCluster(workspace, clusterAPI)) {
+		return
+	}
+	podGroup := &unstructured.Unstructured{}
+	podGroup.SetGroupVersionKind(podGroupGVK)
+	err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: "test-workspaceid", Namespace: "test-namespace"}, podGroup)
+	if !assert.NoError(t, err, "Should create PodGroup") {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{"minMember": int64(2), "scheduleTimeoutSeconds": int64(60)}, podGroup.Object["spec"])
+	if assert.Len(t, podGroup.GetOwnerReferences(), 1) {
+		assert.Equal(t, "test-workspace", podGroup.GetOwnerReferences()[0].Name, "PodGroup should be owned by workspace")
+	}
+
+	workspace.Config.Workspace.GangScheduling.ScheduleTimeoutSeconds = nil
+	if !assert.NoError(t, SyncPodGroupToCluster(workspace, clusterAPI)) {
+		return
+	}
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: "test-workspaceid", Namespace: "test-namespace"}, podGroup)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{"minMember": int64(2)}, podGroup.Object["spec"], "Should update PodGroup")
+	}
+}
+
+func TestWorkspacePodsAreLabelledWithPodGroup(t *testing.T) {
+	workspace := getPodGroupTestWorkspace(true, true)
+	podAdditions := &v1alpha1.PodAdditions{}
+
+	deployment := getSpecBackgroundDeployment(workspace, podAdditions, "test-sa", defaultBackgroundIdleTimeout)
+	assert.Equal(t, "test-workspaceid", deployment.Spec.Template.Labels[podGroupLabel], "Background pod should be in PodGroup")
+
+	workspace.Config.Workspace.GangScheduling.Enable = pointer.Bool(false)
+	deployment = getSpecBackgroundDeployment(workspace, podAdditions, "test-sa", defaultBackgroundIdleTimeout)
+	assert.NotContains(t, deployment.Spec.Template.Labels, podGroupLabel, "Should not add PodGroup label when gang scheduling is disabled")
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// setPodPriority sets the priorityClassName, preemptionPolicy and terminationGracePeriodSeconds of a DevWorkspace pod
+// from the DevWorkspace Operator configuration. Each value can be overridden for a DevWorkspace using the corresponding
+// attribute. Returns an error if an attribute has an invalid value.
+func setPodPriority(workspace *common.DevWorkspaceWithConfig, podSpec *corev1.PodSpec) error {
+	workspaceConfig := workspace.Config.Workspace
+	if workspaceConfig.PriorityClassName != "" {
+		podSpec.PriorityClassName = workspaceConfig.PriorityClassName
+	}
+	if workspaceConfig.PreemptionPolicy != nil {
+		preemptionPolicy := *workspaceConfig.PreemptionPolicy
+		podSpec.PreemptionPolicy = &preemptionPolicy
+	}
+	if workspaceConfig.TerminationGracePeriodSeconds != nil {
+		terminationGracePeriod := *workspaceConfig.TerminationGracePeriodSeconds
+		podSpec.TerminationGracePeriodSeconds = &terminationGracePeriod
+	}
+
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.PriorityClassNameAttribute) {
+		var err error
+		priorityClassName := attributes.GetString(constants.PriorityClassNameAttribute, &err)
+		if err != nil {
+			return fmt.Errorf("failed to read attribute %s: %w", constants.PriorityClassNameAttribute, err)
+		}
+		podSpec.PriorityClassName = priorityClassName
+	}
+	if attributes.Exists(constants.PreemptionPolicyAttribute) {
+		var err error
+		preemptionPolicy := corev1.PreemptionPolicy(attributes.GetString(constants.PreemptionPolicyAttribute, &err))
+		if err != nil {
+			return fmt.Errorf("failed to read attribute %s: %w", constants.PreemptionPolicyAttribute, err)
+		}
+		if preemptionPolicy != corev1.PreemptLowerPriority && preemptionPolicy != corev1.PreemptNever {
+			return fmt.Errorf("invalid value %q for attribute %s: must be %s or %s",
+				preemptionPolicy, constants.PreemptionPolicyAttribute, corev1.PreemptLowerPriority, corev1.PreemptNever)
+		}
+		podSpec.PreemptionPolicy = &preemptionPolicy
+	}
+	if attributes.Exists(constants.TerminationGracePeriodAttribute) {
+		terminationGracePeriod, err := getTerminationGracePeriodAttribute(workspace)
+		if err != nil {
+			return err
+		}
+		podSpec.TerminationGracePeriodSeconds = &terminationGracePeriod
+	}
+	return nil
+}
+
+// getTerminationGracePeriodAttribute reads the termination grace period in seconds from a DevWorkspace's attributes.
+// The value may be specified either as a number or as a string, e.g. 30 or "30".
+func getTerminationGracePeriodAttribute(workspace *common.DevWorkspaceWithConfig) (int64, error) {
+	value := intstr.IntOrString{}
+	if err := workspace.Spec.Template.Attributes.GetInto(constants.TerminationGracePeriodAttribute, &value); err != nil {
+		return 0, fmt.Errorf("failed to read attribute %s: %w", constants.TerminationGracePeriodAttribute, err)
+	}
+	seconds := int64(value.IntVal)
+	if value.Type == intstr.String {
+		parsed, err := strconv.ParseInt(value.StrVal, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for attribute %s: must be a number of seconds", value.StrVal, constants.TerminationGracePeriodAttribute)
+		}
+		seconds = parsed
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("invalid value %d for attribute %s: must not be negative", seconds, constants.TerminationGracePeriodAttribute)
+	}
+	return seconds, nil
+}
@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getPriorityTestWorkspace(workspaceConfig *v1alpha1.WorkspaceConfig, workspaceAttributes attributes.Attributes) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Spec.Template.Attributes = workspaceAttributes
+	workspace.Config.Workspace = workspaceConfig
+	return workspace
+}
+
+func TestSetPodPriority(t *testing.T) {
+	never := corev1.PreemptNever
+	preemptLowerPriority := corev1.PreemptLowerPriority
+	workspaceConfig := &v1alpha1.WorkspaceConfig{
+		PriorityClassName:             "workspaces-low",
+		PreemptionPolicy:              &never,
+		TerminationGracePeriodSeconds: pointer.Int64(30),
+	}
+
+	tests := []struct {
+		name                   string
+		attributes             attributes.Attributes
+		expectedPriorityClass  string
+		expectedPreemption     *corev1.PreemptionPolicy
+		expectedTerminationSec *int64
+		expectedErr            string
+	}{
+		{
+			name:                   "Uses values from config",
+			expectedPriorityClass:  "workspaces-low",
+			expectedPreemption:     &never,
+			expectedTerminationSec: pointer.Int64(30),
+		},
+		{
+			name: "Attributes override config",
+			attributes: attributes.Attributes{}.
+				PutString(constants.PriorityClassNameAttribute, "workspaces-high").
+				PutString(constants.PreemptionPolicyAttribute, "PreemptLowerPriority").
+				PutInteger(constants.TerminationGracePeriodAttribute, 120),
+			expectedPriorityClass:  "workspaces-high",
+			expectedPreemption:     &preemptLowerPriority,
+			expectedTerminationSec: pointer.Int64(120),
+		},
+		{
+			name:                   "Termination grace period can be a string",
+			attributes:             attributes.Attributes{}.PutString(constants.TerminationGracePeriodAttribute, "0"),
+			expectedPriorityClass:  "workspaces-low",
+			expectedPreemption:     &never,
+			expectedTerminationSec: pointer.Int64(0),
+		},
+		{
+			name:        "Invalid preemption policy",
+			attributes:  attributes.Attributes{}.PutString(constants.PreemptionPolicyAttribute, "Always"),
+			expectedErr: `invalid value "Always" for attribute controller.devfile.io/preemption-policy: must be PreemptLowerPriority or Never`,
+		},
+		{
+			name:        "Invalid termination grace period",
+			attributes:  attributes.Attributes{}.PutString(constants.TerminationGracePeriodAttribute, "30s"),
+			expectedErr: `invalid value "30s" for attribute controller.devfile.io/termination-grace-period: must be a number of seconds`,
+		},
+		{
+			name:        "Negative termination grace period",
+			attributes:  attributes.Attributes{}.PutInteger(constants.TerminationGracePeriodAttribute, -1),
+			expectedErr: "invalid value -1 for attribute controller.devfile.io/termination-grace-period: must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podSpec := &corev1.PodSpec{TerminationGracePeriodSeconds: pointer.Int64(10)}
+			err := setPodPriority(getPriorityTestWorkspace(workspaceConfig, tt.attributes), podSpec)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.expectedPriorityClass, podSpec.PriorityClassName)
+			assert.Equal(t, tt.expectedPreemption, podSpec.PreemptionPolicy)
+			assert.Equal(t, tt.expectedTerminationSec, podSpec.TerminationGracePeriodSeconds)
+		})
+	}
+}
+
+func TestSetPodPriorityKeepsDefaults(t *testing.T) {
+	podSpec := &corev1.PodSpec{TerminationGracePeriodSeconds: pointer.Int64(10)}
+	assert.NoError(t, setPodPriority(getPriorityTestWorkspace(&v1alpha1.WorkspaceConfig{}, nil), podSpec))
+	assert.Empty(t, podSpec.PriorityClassName, "Should not set priority class when not configured")
+	assert.Nil(t, podSpec.PreemptionPolicy, "Should not set preemption policy when not configured")
+	assert.Equal(t, pointer.Int64(10), podSpec.TerminationGracePeriodSeconds, "Should keep default termination grace period")
+}
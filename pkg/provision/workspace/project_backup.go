@@ -0,0 +1,168 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/images"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/projects"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	defaultProjectBackupRegion = "us-east-1"
+	accessKeyIdSecretKey       = "AWS_ACCESS_KEY_ID"
+	secretAccessKeySecretKey   = "AWS_SECRET_ACCESS_KEY"
+)
+
+// ProvisionProjectBackup adds containers to the workspace pod that periodically back up the projects volume to
+// object storage and restore it when the workspace starts with an empty projects volume. Nothing is added if
+// project backups are disabled or if no container in the workspace mounts the projects volume. If backups are
+// enabled but the bucket or credentials are not configured, a WarningError is returned.
+func ProvisionProjectBackup(workspace *common.DevWorkspaceWithConfig, podAdditions *v1alpha1.PodAdditions, clusterAPI sync.ClusterAPI) error {
+	backupConfig := workspace.Config.Workspace.ProjectBackup
+	if backupConfig == nil || backupConfig.Enable == nil || !*backupConfig.Enable {
+		return nil
+	}
+	if !projects.UsesProjectsVolume(podAdditions.Containers) {
+		return nil
+	}
+
+	interval, err := time.ParseDuration(backupConfig.Interval)
+	if err != nil || interval <= 0 {
+		return &dwerrors.WarningError{
+			Message: fmt.Sprintf("Project backups are disabled: invalid backup interval %q", backupConfig.Interval),
+		}
+	}
+
+	options, err := getProjectBackupOptions(workspace, backupConfig, clusterAPI)
+	if err != nil || options == nil {
+		return err
+	}
+	options.Interval = interval
+
+	restore, backup, volume := projects.GetProjectBackupContainers(*options)
+	podAdditions.InitContainers = projects.InsertProjectRestoreContainer(podAdditions.InitContainers, *restore)
+	podAdditions.Containers = append(podAdditions.Containers, *backup)
+	podAdditions.Volumes = append(podAdditions.Volumes, *volume)
+	return nil
+}
+
+// getProjectBackupOptions resolves the bucket and credentials used to back up a workspace's projects. A secret in the
+// workspace's namespace takes precedence over the global configuration; credentials from the global configuration are
+// copied into the workspace's namespace so that they can be referenced by the workspace pod.
+func getProjectBackupOptions(workspace *common.DevWorkspaceWithConfig, backupConfig *v1alpha1.ProjectBackupConfig, clusterAPI sync.ClusterAPI) (*projects.BackupOptions, error) {
+	bucketURL := backupConfig.URL
+	region := backupConfig.Region
+	var credentialsSecretName string
+
+	namespaceSecret := &corev1.Secret{}
+	namespacedName := types.NamespacedName{Name: constants.ProjectBackupSecretName, Namespace: workspace.Namespace}
+	err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, namespacedName, namespaceSecret)
+	switch {
+	case err == nil:
+		if url := string(namespaceSecret.Data["url"]); url != "" {
+			bucketURL = url
+		}
+		if secretRegion := string(namespaceSecret.Data["region"]); secretRegion != "" {
+			region = secretRegion
+		}
+		credentialsSecretName = namespaceSecret.Name
+	case !k8sErrors.IsNotFound(err):
+		return nil, err
+	case backupConfig.CredentialsSecretName != "":
+		credentialsSecretName, err = syncProjectBackupCredentials(workspace, backupConfig.CredentialsSecretName, clusterAPI)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, &dwerrors.WarningError{
+			Message: "Project backups are disabled: no credentials are configured for the backup bucket",
+		}
+	}
+	if bucketURL == "" {
+		return nil, &dwerrors.WarningError{
+			Message: "Project backups are disabled: no backup bucket URL is configured",
+		}
+	}
+	if region == "" {
+		region = defaultProjectBackupRegion
+	}
+
+	pullPolicy := corev1.PullPolicy(workspace.Config.Workspace.ImagePullPolicy)
+	return &projects.BackupOptions{
+		Image:                 images.GetWorkspaceSnapshotImage(),
+		PullPolicy:            pullPolicy,
+		URL:                   fmt.Sprintf("%s/%s/%s/projects.tar.gz", strings.TrimSuffix(bucketURL, "/"), workspace.Namespace, workspace.Name),
+		Region:                region,
+		CredentialsSecretName: credentialsSecretName,
+	}, nil
+}
+
+// syncProjectBackupCredentials copies the object storage credentials from a secret in the operator's namespace to a
+// secret owned by the workspace, and returns the name of the copied secret.
+func syncProjectBackupCredentials(workspace *common.DevWorkspaceWithConfig, secretName string, clusterAPI sync.ClusterAPI) (string, error) {
+	operatorNamespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return "", err
+	}
+	credentials := &corev1.Secret{}
+	if err := clusterAPI.NonCachingClient.Get(clusterAPI.Ctx, types.NamespacedName{Name: secretName, Namespace: operatorNamespace}, credentials); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return "", &dwerrors.WarningError{
+				Message: fmt.Sprintf("Project backups are disabled: credentials secret %s does not exist", secretName),
+			}
+		}
+		return "", err
+	}
+
+	specSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ProjectBackupSecretName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:          workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceWatchSecretLabel: "true",
+			},
+		},
+		Data: map[string][]byte{
+			accessKeyIdSecretKey:     credentials.Data[accessKeyIdSecretKey],
+			secretAccessKeySecretKey: credentials.Data[secretAccessKeySecretKey],
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specSecret, clusterAPI.Scheme); err != nil {
+		return "", err
+	}
+	if _, err := sync.SyncObjectWithCluster(specSecret, clusterAPI); err != nil {
+		return "", dwerrors.WrapSyncError(err)
+	}
+	return specSecret.Name, nil
+}
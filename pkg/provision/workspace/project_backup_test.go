@@ -0,0 +1,189 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	devfileConstants "github.com/devfile/devworkspace-operator/pkg/library/constants"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getProjectBackupTestWorkspace(backupConfig *v1alpha1.ProjectBackupConfig) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Config.Workspace.ImagePullPolicy = "Always"
+	workspace.Config.Workspace.ProjectBackup = backupConfig
+	return workspace
+}
+
+func getProjectBackupTestPodAdditions() *v1alpha1.PodAdditions {
+	return &v1alpha1.PodAdditions{
+		Containers: []corev1.Container{
+			{
+				Name:         "tools",
+				VolumeMounts: []corev1.VolumeMount{{Name: devfileConstants.ProjectsVolumeName, MountPath: "/projects"}},
+			},
+		},
+		InitContainers: []corev1.Container{{Name: "project-clone"}},
+	}
+}
+
+func getProjectBackupTestClusterAPI(t *testing.T, objs ...client.Object) sync.ClusterAPI {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, "devworkspace-controller")
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	fakeClient := testutil.NewFakeClient(objs...)
+	return sync.ClusterAPI{
+		Client:           fakeClient,
+		NonCachingClient: fakeClient,
+		Scheme:           testutil.Scheme,
+		Logger:           zap.New(),
+		Ctx:              context.Background(),
+	}
+}
+
+func getEnvValue(container corev1.Container, name string) string {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+func TestProvisionProjectBackupDisabled(t *testing.T) {
+	workspace := getProjectBackupTestWorkspace(&v1alpha1.ProjectBackupConfig{Enable: pointer.Bool(false)})
+	podAdditions := getProjectBackupTestPodAdditions()
+	err := ProvisionProjectBackup(workspace, podAdditions, getProjectBackupTestClusterAPI(t))
+	assert.NoError(t, err)
+	assert.Equal(t, getProjectBackupTestPodAdditions(), podAdditions, "Should not modify pod additions when backups are disabled")
+}
+
+func TestProvisionProjectBackupUsesNamespaceSecret(t *testing.T) {
+	workspace := getProjectBackupTestWorkspace(&v1alpha1.ProjectBackupConfig{
+		Enable:   pointer.Bool(true),
+		Interval: "30m",
+		URL:      "https://s3.example.com/global-bucket",
+	})
+	namespaceSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.ProjectBackupSecretName, Namespace: "test-namespace"},
+		Data: map[string][]byte{
+			"url":    []byte("https://s3.example.com/team-bucket/"),
+			"region": []byte("eu-west-1"),
+		},
+	}
+	podAdditions := getProjectBackupTestPodAdditions()
+	err := ProvisionProjectBackup(workspace, podAdditions, getProjectBackupTestClusterAPI(t, namespaceSecret))
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, podAdditions.InitContainers, 2) || !assert.Len(t, podAdditions.Containers, 2) {
+		return
+	}
+	assert.Equal(t, "project-restore", podAdditions.InitContainers[0].Name, "Should restore projects before project-clone runs")
+	assert.Equal(t, "project-clone", podAdditions.InitContainers[1].Name)
+
+	backup := podAdditions.Containers[1]
+	assert.Equal(t, "project-backup", backup.Name)
+	assert.Equal(t, "https://s3.example.com/team-bucket/test-namespace/test-workspace/projects.tar.gz", getEnvValue(backup, "BACKUP_URL"))
+	assert.Equal(t, "eu-west-1", getEnvValue(backup, "AWS_REGION"))
+	assert.Equal(t, "1800", getEnvValue(backup, "BACKUP_INTERVAL"))
+	for _, env := range backup.Env {
+		if env.ValueFrom != nil {
+			assert.Equal(t, constants.ProjectBackupSecretName, env.ValueFrom.SecretKeyRef.Name, "Should read credentials from namespace secret")
+		}
+	}
+	assert.Len(t, podAdditions.Volumes, 1, "Should add scratch volume for backup archive")
+}
+
+func TestProvisionProjectBackupCopiesGlobalCredentials(t *testing.T) {
+	workspace := getProjectBackupTestWorkspace(&v1alpha1.ProjectBackupConfig{
+		Enable:                pointer.Bool(true),
+		Interval:              "1h",
+		URL:                   "https://s3.example.com/global-bucket",
+		CredentialsSecretName: "backup-credentials",
+	})
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-credentials", Namespace: "devworkspace-controller"},
+		Data: map[string][]byte{
+			"AWS_ACCESS_KEY_ID":     []byte("access-key"),
+			"AWS_SECRET_ACCESS_KEY": []byte("secret-key"),
+			"unrelated":             []byte("value"),
+		},
+	}
+	clusterAPI := getProjectBackupTestClusterAPI(t, credentials)
+
+	err := ProvisionProjectBackup(workspace, getProjectBackupTestPodAdditions(), clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should retry after creating credentials secret")
+
+	copied := &corev1.Secret{}
+	copiedNN := types.NamespacedName{Name: common.ProjectBackupSecretName("test-workspaceid"), Namespace: "test-namespace"}
+	if !assert.NoError(t, clusterAPI.Client.Get(context.Background(), copiedNN, copied), "Should copy credentials into workspace namespace") {
+		return
+	}
+	assert.Equal(t, map[string][]byte{
+		"AWS_ACCESS_KEY_ID":     []byte("access-key"),
+		"AWS_SECRET_ACCESS_KEY": []byte("secret-key"),
+	}, copied.Data, "Should only copy credentials")
+
+	podAdditions := getProjectBackupTestPodAdditions()
+	if !assert.NoError(t, ProvisionProjectBackup(workspace, podAdditions, clusterAPI)) {
+		return
+	}
+	assert.Equal(t, common.ProjectBackupSecretName("test-workspaceid"), podAdditions.Containers[1].Env[4].ValueFrom.SecretKeyRef.Name)
+}
+
+func TestProvisionProjectBackupWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *v1alpha1.ProjectBackupConfig
+	}{
+		{
+			name:   "No credentials configured",
+			config: &v1alpha1.ProjectBackupConfig{Enable: pointer.Bool(true), Interval: "1h", URL: "https://s3.example.com/bucket"},
+		},
+		{
+			name:   "Credentials secret does not exist",
+			config: &v1alpha1.ProjectBackupConfig{Enable: pointer.Bool(true), Interval: "1h", URL: "https://s3.example.com/bucket", CredentialsSecretName: "missing"},
+		},
+		{
+			name:   "Invalid interval",
+			config: &v1alpha1.ProjectBackupConfig{Enable: pointer.Bool(true), Interval: "often"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podAdditions := getProjectBackupTestPodAdditions()
+			err := ProvisionProjectBackup(getProjectBackupTestWorkspace(tt.config), podAdditions, getProjectBackupTestClusterAPI(t))
+			assert.IsType(t, &dwerrors.WarningError{}, err)
+			assert.Equal(t, getProjectBackupTestPodAdditions(), podAdditions, "Should not add backup containers")
+		})
+	}
+}
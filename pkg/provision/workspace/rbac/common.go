@@ -31,6 +31,9 @@ func SyncRBAC(workspace *common.DevWorkspaceWithConfig, api sync.ClusterAPI) err
 	if err := syncRoles(workspace, api); err != nil {
 		return err
 	}
+	if err := syncScopedRBAC(workspace, api); err != nil {
+		return err
+	}
 	if err := syncRolebindings(workspace, api); err != nil {
 		return err
 	}
@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package rbac
+
+import (
+	"fmt"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// imagePullerClusterRole is the OpenShift ClusterRole that grants permission to pull images from ImageStreams
+const imagePullerClusterRole = "system:image-puller"
+
+// SyncImagePullerRolebinding allows all serviceaccounts in a DevWorkspace's namespace to pull images from ImageStreams
+// in sourceNamespace, by binding the system:image-puller ClusterRole to the namespace's serviceaccount group in
+// sourceNamespace. The rolebinding is shared by all DevWorkspaces in the namespace and is not removed when
+// DevWorkspaces are deleted.
+func SyncImagePullerRolebinding(sourceNamespace string, workspace *common.DevWorkspaceWithConfig, api sync.ClusterAPI) error {
+	rolebinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.WorkspaceImagePullerRolebindingName(workspace.Namespace),
+			Namespace: sourceNamespace,
+			Labels:    rbacLabels,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     imagePullerClusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.GroupKind,
+				Name:     fmt.Sprintf("system:serviceaccounts:%s", workspace.Namespace),
+			},
+		},
+	}
+	if _, err := sync.SyncObjectWithCluster(rolebinding, api); err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+	return nil
+}
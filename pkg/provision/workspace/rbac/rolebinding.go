@@ -31,7 +31,12 @@ func syncRolebindings(workspace *common.DevWorkspaceWithConfig, api sync.Cluster
 	saName := common.ServiceAccountName(workspace)
 	defaultRoleName := common.WorkspaceRoleName()
 	defaultRolebindingName := common.WorkspaceRolebindingName()
-	if err := addServiceAccountToRolebinding(saName, workspace.Namespace, defaultRoleName, defaultRolebindingName, api); err != nil {
+	if isScopedRBACEnabled(workspace) {
+		// The ServiceAccount is granted permissions by its own Role instead
+		if err := removeServiceAccountFromRolebinding(saName, workspace.Namespace, defaultRolebindingName, api); err != nil {
+			return err
+		}
+	} else if err := addServiceAccountToRolebinding(saName, workspace.Namespace, defaultRoleName, defaultRolebindingName, api); err != nil {
 		return err
 	}
 	if !workspace.Spec.Template.Attributes.Exists(constants.WorkspaceSCCAttribute) {
@@ -0,0 +1,121 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"strings"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// workspaceNamePlaceholder is replaced by the DevWorkspace's name in the resourceNames of scoped RBAC rules
+const workspaceNamePlaceholder = "<workspace-name>"
+
+func isScopedRBACEnabled(workspace *common.DevWorkspaceWithConfig) bool {
+	saConfig := workspace.Config.Workspace.ServiceAccount
+	return saConfig != nil && saConfig.ScopedRBAC != nil && pointer.BoolDeref(saConfig.ScopedRBAC.Enable, false)
+}
+
+// syncScopedRBAC creates or updates the Role and RoleBinding that grant permissions to only the DevWorkspace's
+// ServiceAccount if scoped RBAC is enabled, and removes them otherwise.
+func syncScopedRBAC(workspace *common.DevWorkspaceWithConfig, api sync.ClusterAPI) error {
+	workspaceId := workspace.Status.DevWorkspaceId
+	if !isScopedRBACEnabled(workspace) {
+		if err := deleteRole(common.WorkspaceScopedRoleName(workspaceId), workspace.Namespace, api); err != nil {
+			return err
+		}
+		return deleteRolebinding(common.WorkspaceScopedRolebindingName(workspaceId), workspace.Namespace, api)
+	}
+	if workspace.Config.Workspace.ServiceAccount.ServiceAccountName != "" {
+		return &dwerrors.FailError{Message: "Scoped RBAC cannot be used when a ServiceAccount is shared by all DevWorkspaces"}
+	}
+
+	role := generateScopedRole(workspace)
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, role, api.Scheme); err != nil {
+		return err
+	}
+	if _, err := sync.SyncObjectWithCluster(role, api); err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+
+	rolebinding := generateScopedRolebinding(workspace)
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, rolebinding, api.Scheme); err != nil {
+		return err
+	}
+	if _, err := sync.SyncObjectWithCluster(rolebinding, api); err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+	return nil
+}
+
+func generateScopedRole(workspace *common.DevWorkspaceWithConfig) *rbacv1.Role {
+	var rules []rbacv1.PolicyRule
+	for _, rule := range workspace.Config.Workspace.ServiceAccount.ScopedRBAC.Rules {
+		// Copy rule to avoid modifying the rules in the operator's configuration
+		rule := *rule.DeepCopy()
+		for idx, resourceName := range rule.ResourceNames {
+			resourceName = strings.ReplaceAll(resourceName, workspaceNamePlaceholder, workspace.Name)
+			rule.ResourceNames[idx] = strings.ReplaceAll(resourceName, common.WorkspaceIdPlaceholder, workspace.Status.DevWorkspaceId)
+		}
+		rules = append(rules, rule)
+	}
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.WorkspaceScopedRoleName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels:    scopedRBACLabels(workspace),
+		},
+		Rules: rules,
+	}
+}
+
+func generateScopedRolebinding(workspace *common.DevWorkspaceWithConfig) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.WorkspaceScopedRolebindingName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels:    scopedRBACLabels(workspace),
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind: "Role",
+			Name: common.WorkspaceScopedRoleName(workspace.Status.DevWorkspaceId),
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      common.ServiceAccountName(workspace),
+				Namespace: workspace.Namespace,
+			},
+		},
+	}
+}
+
+func scopedRBACLabels(workspace *common.DevWorkspaceWithConfig) map[string]string {
+	labels := map[string]string{
+		constants.DevWorkspaceIDLabel:   workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel: workspace.Name,
+	}
+	for k, v := range rbacLabels {
+		labels[k] = v
+	}
+	return labels
+}
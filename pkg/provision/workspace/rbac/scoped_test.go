@@ -0,0 +1,102 @@
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rbac
+
+import (
+	"testing"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+func TestCreatesScopedRoleAndRolebinding(t *testing.T) {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	testdw := getTestDevWorkspace("test-devworkspace")
+	testdw.Config.Workspace.ServiceAccount.ScopedRBAC.Enable = pointer.Bool(true)
+	api := getTestClusterAPI(t, testdw.DevWorkspace)
+	retryErr := &dwerrors.RetryError{}
+	err := syncScopedRBAC(testdw, api)
+	if assert.Error(t, err, "Should return RetryError to indicate that role was created") {
+		assert.ErrorAs(t, err, &retryErr, "Error should have RetryError type")
+	}
+	err = syncScopedRBAC(testdw, api)
+	if assert.Error(t, err, "Should return RetryError to indicate that rolebinding was created") {
+		assert.ErrorAs(t, err, &retryErr, "Error should have RetryError type")
+	}
+	err = syncScopedRBAC(testdw, api)
+	assert.NoError(t, err, "Should not return error if scoped RBAC is in sync")
+
+	actualRole := &rbacv1.Role{}
+	err = api.Client.Get(api.Ctx, types.NamespacedName{
+		Name:      common.WorkspaceScopedRoleName(testdw.Status.DevWorkspaceId),
+		Namespace: testNamespace,
+	}, actualRole)
+	if assert.NoError(t, err, "Scoped role should be created") {
+		for _, rule := range actualRole.Rules {
+			for _, resourceName := range rule.ResourceNames {
+				assert.NotContains(t, resourceName, workspaceNamePlaceholder, "Placeholders in resourceNames should be replaced")
+			}
+		}
+		assert.Contains(t, actualRole.Rules[2].ResourceNames, testdw.Name, "Workspace name should be used in resourceNames")
+	}
+	actualRB := &rbacv1.RoleBinding{}
+	err = api.Client.Get(api.Ctx, types.NamespacedName{
+		Name:      common.WorkspaceScopedRolebindingName(testdw.Status.DevWorkspaceId),
+		Namespace: testNamespace,
+	}, actualRB)
+	if assert.NoError(t, err, "Scoped rolebinding should be created") {
+		assert.Equal(t, actualRole.Name, actualRB.RoleRef.Name, "Scoped rolebinding should reference scoped role")
+		assert.True(t, testHasSubject(common.ServiceAccountName(testdw), testNamespace, actualRB), "Scoped rolebinding should have workspace SA as subject")
+		assert.Len(t, actualRB.Subjects, 1, "Scoped rolebinding should only have workspace SA as subject")
+	}
+}
+
+func TestScopedRBACRemovesSAFromDefaultRolebinding(t *testing.T) {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	testdw := getTestDevWorkspace("test-devworkspace")
+	api := getTestClusterAPI(t, testdw.DevWorkspace)
+	err := syncRolebindings(testdw, api)
+	assert.Error(t, err, "Should return RetryError to indicate that rolebinding was created")
+	testdw.Config.Workspace.ServiceAccount.ScopedRBAC.Enable = pointer.Bool(true)
+	err = syncRolebindings(testdw, api)
+	assert.Error(t, err, "Should return RetryError to indicate that rolebinding was updated")
+	err = syncRolebindings(testdw, api)
+	assert.NoError(t, err, "Should not return error if rolebinding is in sync")
+
+	actualRB := &rbacv1.RoleBinding{}
+	err = api.Client.Get(api.Ctx, types.NamespacedName{
+		Name:      common.WorkspaceRolebindingName(),
+		Namespace: testNamespace,
+	}, actualRB)
+	assert.NoError(t, err, "Default rolebinding should exist")
+	assert.False(t, testHasSubject(common.ServiceAccountName(testdw), testNamespace, actualRB), "Default rolebinding should not have workspace SA as subject")
+}
+
+func TestScopedRBACFailsWithSharedServiceAccount(t *testing.T) {
+	infrastructure.InitializeForTesting(infrastructure.Kubernetes)
+	testdw := getTestDevWorkspace("test-devworkspace")
+	testdw.Config.Workspace.ServiceAccount.ScopedRBAC.Enable = pointer.Bool(true)
+	testdw.Config.Workspace.ServiceAccount.ServiceAccountName = "shared-sa"
+	api := getTestClusterAPI(t, testdw.DevWorkspace)
+	err := syncScopedRBAC(testdw, api)
+	failErr := &dwerrors.FailError{}
+	if assert.Error(t, err, "Should return error when shared ServiceAccount is configured") {
+		assert.ErrorAs(t, err, &failErr, "Error should have FailError type")
+	}
+}
@@ -16,9 +16,14 @@
 package workspace
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+
 	"github.com/devfile/devworkspace-operator/controllers/controller/devworkspacerouting/conversion"
 	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
 	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
@@ -48,13 +53,10 @@ func SyncRoutingToCluster(
 	}
 
 	clusterRouting := clusterObj.(*v1alpha1.DevWorkspaceRouting)
-	statusMsg := clusterRouting.Status.Message
-	if clusterRouting.Status.Phase == v1alpha1.RoutingFailed {
-		return nil, nil, statusMsg, &dwerrors.FailError{Message: statusMsg}
-	}
-	if clusterRouting.Status.Phase != v1alpha1.RoutingReady {
-		return nil, nil, statusMsg, &dwerrors.RetryError{Message: statusMsg, RequeueAfter: 5 * time.Second}
+	if statusMsg, err := checkRoutingStatus(clusterRouting); err != nil {
+		return nil, nil, statusMsg, err
 	}
+	statusMsg := clusterRouting.Status.Message
 
 	// Configure securityContext for pod additions, for example che-gateway container
 	// https://github.com/eclipse-che/che/issues/22747
@@ -72,6 +74,89 @@ func SyncRoutingToCluster(
 	return clusterRouting.Status.PodAdditions, clusterRouting.Status.ExposedEndpoints, statusMsg, nil
 }
 
+// checkRoutingStatus interprets the status of a DevWorkspaceRouting as reported by the controller that handles its
+// routingClass, which may be the built-in controller or an external one. Returns a FailError if the routing failed,
+// a RetryError if the routing is not ready for the current spec, and nil if it is ready. The returned message
+// explains the state of the routing and is used for the DevWorkspace's RoutingReady condition.
+func checkRoutingStatus(routing *v1alpha1.DevWorkspaceRouting) (statusMsg string, err error) {
+	status := routing.Status
+	switch {
+	case routing.DeletionTimestamp != nil:
+		// Routing controllers may add finalizers to clean up resources outside the workspace's namespace.
+		statusMsg = fmt.Sprintf("Waiting for DevWorkspaceRouting %s to be deleted", routing.Name)
+		if len(routing.Finalizers) > 0 {
+			statusMsg = fmt.Sprintf("%s (finalizers: %s)", statusMsg, strings.Join(routing.Finalizers, ", "))
+		}
+		return statusMsg, &dwerrors.RetryError{Message: statusMsg, RequeueAfter: 5 * time.Second}
+	case status.Phase == v1alpha1.RoutingFailed:
+		statusMsg = status.Message
+		if statusMsg == "" {
+			statusMsg = fmt.Sprintf("DevWorkspaceRouting for routingClass '%s' failed", routing.Spec.RoutingClass)
+		}
+		return statusMsg, &dwerrors.FailError{Message: statusMsg}
+	case status.Phase == "":
+		// No controller has processed the routing yet; if this persists, no controller handles its routingClass.
+		statusMsg = fmt.Sprintf("Waiting for a controller to handle DevWorkspaceRouting with routingClass '%s'", routing.Spec.RoutingClass)
+		return statusMsg, &dwerrors.RetryError{Message: statusMsg, RequeueAfter: 5 * time.Second}
+	case status.ObservedGeneration != 0 && status.ObservedGeneration < routing.Generation:
+		// The reported status applies to an earlier spec, e.g. before endpoints were added.
+		statusMsg = "Waiting for DevWorkspaceRouting controller to process changes"
+		return statusMsg, &dwerrors.RetryError{Message: statusMsg, RequeueAfter: 5 * time.Second}
+	case status.Phase != v1alpha1.RoutingReady:
+		return status.Message, &dwerrors.RetryError{Message: status.Message, RequeueAfter: 5 * time.Second}
+	}
+	return status.Message, nil
+}
+
+// ApplyEndpointExposureOverrides updates the exposure of endpoints in the DevWorkspace's flattened template according to
+// the DevWorkspaceEndpointExposureAnnotation, so that endpoints can be made public or internal without changing the
+// DevWorkspace's spec. Returns a WarningError if the annotation is invalid or refers to endpoints that are not defined
+// in the DevWorkspace; valid overrides are applied regardless.
+func ApplyEndpointExposureOverrides(workspace *common.DevWorkspaceWithConfig) error {
+	annotation, ok := workspace.Annotations[constants.DevWorkspaceEndpointExposureAnnotation]
+	if !ok {
+		return nil
+	}
+	overrides := map[string]dw.EndpointExposure{}
+	if err := json.Unmarshal([]byte(annotation), &overrides); err != nil {
+		return &dwerrors.WarningError{
+			Message: fmt.Sprintf("Ignoring invalid %s annotation: %s", constants.DevWorkspaceEndpointExposureAnnotation, err),
+		}
+	}
+
+	var problems []string
+	validOverrides := map[string]dw.EndpointExposure{}
+	for endpointName, exposure := range overrides {
+		if exposure != dw.PublicEndpointExposure && exposure != dw.InternalEndpointExposure {
+			problems = append(problems, fmt.Sprintf("unsupported exposure '%s' for endpoint %s", exposure, endpointName))
+			continue
+		}
+		validOverrides[endpointName] = exposure
+	}
+	for _, component := range workspace.Spec.Template.Components {
+		if component.Container == nil {
+			continue
+		}
+		for idx, endpoint := range component.Container.Endpoints {
+			if exposure, ok := validOverrides[endpoint.Name]; ok {
+				component.Container.Endpoints[idx].Exposure = exposure
+				delete(validOverrides, endpoint.Name)
+			}
+		}
+	}
+	for endpointName := range validOverrides {
+		problems = append(problems, fmt.Sprintf("endpoint %s is not defined", endpointName))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	// Sort to avoid random map iteration order
+	sort.Strings(problems)
+	return &dwerrors.WarningError{
+		Message: fmt.Sprintf("Ignoring entries in %s annotation: %s", constants.DevWorkspaceEndpointExposureAnnotation, strings.Join(problems, "; ")),
+	}
+}
+
 func getSpecRouting(
 	workspace *common.DevWorkspaceWithConfig,
 	scheme *runtime.Scheme) (*v1alpha1.DevWorkspaceRouting, error) {
@@ -92,6 +177,10 @@ func getSpecRouting(
 		annotations = maputils.Append(annotations, constants.DevWorkspaceRestrictedAccessAnnotation, val)
 	}
 	annotations = maputils.Append(annotations, constants.DevWorkspaceStartedStatusAnnotation, "true")
+	// The routing controller uses the recorded object names to find the service for the workspace's endpoints
+	if val, ok := workspace.Annotations[constants.DevWorkspaceObjectNamesAnnotation]; ok {
+		annotations = maputils.Append(annotations, constants.DevWorkspaceObjectNamesAnnotation, val)
+	}
 
 	// copy the annotations for the specific routingClass from the workspace object to the routing
 	expectedAnnotationPrefix := workspace.Spec.RoutingClass + constants.RoutingAnnotationInfix
@@ -108,7 +197,7 @@ func getSpecRouting(
 
 	routing := &v1alpha1.DevWorkspaceRouting{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      common.DevWorkspaceRoutingName(workspace.Status.DevWorkspaceId),
+			Name:      common.DevWorkspaceRoutingName(workspace.Status.DevWorkspaceId, workspace.Annotations),
 			Namespace: workspace.Namespace,
 			Labels: map[string]string{
 				constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId,
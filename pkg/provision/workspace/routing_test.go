@@ -0,0 +1,191 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+func getEndpointExposureTestWorkspace(exposureAnnotation string) *common.DevWorkspaceWithConfig {
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	if exposureAnnotation != "" {
+		workspace.Annotations[constants.DevWorkspaceEndpointExposureAnnotation] = exposureAnnotation
+	}
+	workspace.Spec.Template.Components = []dw.Component{
+		{
+			Name: "tools",
+			ComponentUnion: dw.ComponentUnion{
+				Container: &dw.ContainerComponent{
+					Endpoints: []dw.Endpoint{
+						{Name: "http-8080", TargetPort: 8080, Exposure: dw.InternalEndpointExposure},
+						{Name: "http-3000", TargetPort: 3000, Exposure: dw.PublicEndpointExposure},
+					},
+				},
+			},
+		},
+		{
+			Name: "volume",
+			ComponentUnion: dw.ComponentUnion{
+				Volume: &dw.VolumeComponent{},
+			},
+		},
+	}
+	return workspace
+}
+
+func getEndpointExposures(workspace *common.DevWorkspaceWithConfig) map[string]dw.EndpointExposure {
+	exposures := map[string]dw.EndpointExposure{}
+	for _, endpoint := range workspace.Spec.Template.Components[0].Container.Endpoints {
+		exposures[endpoint.Name] = endpoint.Exposure
+	}
+	return exposures
+}
+
+func TestApplyEndpointExposureOverrides(t *testing.T) {
+	workspace := getEndpointExposureTestWorkspace(`{"http-8080": "public", "http-3000": "internal"}`)
+	err := ApplyEndpointExposureOverrides(workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]dw.EndpointExposure{
+		"http-8080": dw.PublicEndpointExposure,
+		"http-3000": dw.InternalEndpointExposure,
+	}, getEndpointExposures(workspace))
+}
+
+func TestApplyEndpointExposureOverridesWithoutAnnotation(t *testing.T) {
+	workspace := getEndpointExposureTestWorkspace("")
+	err := ApplyEndpointExposureOverrides(workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]dw.EndpointExposure{
+		"http-8080": dw.InternalEndpointExposure,
+		"http-3000": dw.PublicEndpointExposure,
+	}, getEndpointExposures(workspace))
+}
+
+func TestApplyEndpointExposureOverridesWarnsForInvalidEntries(t *testing.T) {
+	workspace := getEndpointExposureTestWorkspace(`{"http-8080": "public", "http-3000": "none", "unknown": "public"}`)
+	err := ApplyEndpointExposureOverrides(workspace)
+	if assert.IsType(t, &dwerrors.WarningError{}, err) {
+		assert.Equal(t, "Ignoring entries in controller.devfile.io/endpoint-exposure-overrides annotation: endpoint unknown is not defined; unsupported exposure 'none' for endpoint http-3000", err.Error())
+	}
+	assert.Equal(t, map[string]dw.EndpointExposure{
+		"http-8080": dw.PublicEndpointExposure,
+		"http-3000": dw.PublicEndpointExposure,
+	}, getEndpointExposures(workspace), "Valid entries should be applied")
+}
+
+func TestApplyEndpointExposureOverridesWarnsForInvalidAnnotation(t *testing.T) {
+	workspace := getEndpointExposureTestWorkspace(`http-8080=public`)
+	err := ApplyEndpointExposureOverrides(workspace)
+	assert.IsType(t, &dwerrors.WarningError{}, err)
+	assert.Equal(t, dw.InternalEndpointExposure, getEndpointExposures(workspace)["http-8080"])
+}
+
+func getRoutingStatusTestRouting(generation int64, status v1alpha1.DevWorkspaceRoutingStatus) *v1alpha1.DevWorkspaceRouting {
+	return &v1alpha1.DevWorkspaceRouting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "routing-test-workspaceid",
+			Namespace:  "test-namespace",
+			Generation: generation,
+		},
+		Spec: v1alpha1.DevWorkspaceRoutingSpec{
+			RoutingClass: "external",
+		},
+		Status: status,
+	}
+}
+
+func TestCheckRoutingStatus(t *testing.T) {
+	deletionTimestamp := metav1.Now()
+	tests := []struct {
+		name        string
+		routing     *v1alpha1.DevWorkspaceRouting
+		expectedMsg string
+		expectedErr interface{}
+	}{
+		{
+			name:        "Ready routing",
+			routing:     getRoutingStatusTestRouting(2, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingReady, Message: "Routing ready", ObservedGeneration: 2}),
+			expectedMsg: "Routing ready",
+		},
+		{
+			name:        "Ready routing without observed generation",
+			routing:     getRoutingStatusTestRouting(2, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingReady, Message: "Routing ready"}),
+			expectedMsg: "Routing ready",
+		},
+		{
+			name:        "Ready routing for earlier generation",
+			routing:     getRoutingStatusTestRouting(2, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingReady, Message: "Routing ready", ObservedGeneration: 1}),
+			expectedMsg: "Waiting for DevWorkspaceRouting controller to process changes",
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name:        "Routing not handled by any controller",
+			routing:     getRoutingStatusTestRouting(1, v1alpha1.DevWorkspaceRoutingStatus{}),
+			expectedMsg: "Waiting for a controller to handle DevWorkspaceRouting with routingClass 'external'",
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name:        "Preparing routing",
+			routing:     getRoutingStatusTestRouting(1, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingPreparing, Message: "Creating gateway", ObservedGeneration: 1}),
+			expectedMsg: "Creating gateway",
+			expectedErr: &dwerrors.RetryError{},
+		},
+		{
+			name:        "Failed routing",
+			routing:     getRoutingStatusTestRouting(1, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingFailed, Message: "Invalid endpoint", ObservedGeneration: 1}),
+			expectedMsg: "Invalid endpoint",
+			expectedErr: &dwerrors.FailError{},
+		},
+		{
+			name:        "Failed routing without message",
+			routing:     getRoutingStatusTestRouting(1, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingFailed}),
+			expectedMsg: "DevWorkspaceRouting for routingClass 'external' failed",
+			expectedErr: &dwerrors.FailError{},
+		},
+		{
+			name: "Deleted routing with finalizers",
+			routing: func() *v1alpha1.DevWorkspaceRouting {
+				routing := getRoutingStatusTestRouting(1, v1alpha1.DevWorkspaceRoutingStatus{Phase: v1alpha1.RoutingReady, ObservedGeneration: 1})
+				routing.DeletionTimestamp = &deletionTimestamp
+				routing.Finalizers = []string{"external.example.com/dns"}
+				return routing
+			}(),
+			expectedMsg: "Waiting for DevWorkspaceRouting routing-test-workspaceid to be deleted (finalizers: external.example.com/dns)",
+			expectedErr: &dwerrors.RetryError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := checkRoutingStatus(tt.routing)
+			assert.Equal(t, tt.expectedMsg, msg)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	nsconfig "github.com/devfile/devworkspace-operator/pkg/provision/config"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// podScheduling holds the node selector, tolerations and affinity that are applied to DevWorkspace pods.
+type podScheduling struct {
+	nodeSelector map[string]string
+	tolerations  []corev1.Toleration
+	affinity     *corev1.Affinity
+}
+
+// getPodScheduling returns the scheduling constraints for a DevWorkspace's pods, combining the DevWorkspace Operator
+// configuration with the annotations on the DevWorkspace's namespace:
+//   - Node selectors from the namespace are added to those from the configuration, and take precedence for the same key
+//   - Tolerations from the namespace are added to those from the configuration
+//   - An affinity from the namespace replaces the affinity from the configuration
+func getPodScheduling(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (*podScheduling, error) {
+	nsTolerations, nsNodeSelector, err := nsconfig.GetNamespacePodTolerationsAndNodeSelector(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	nsAffinity, err := nsconfig.GetNamespacePodAffinity(workspace.Namespace, clusterAPI)
+	if err != nil {
+		return nil, err
+	}
+	return mergePodScheduling(workspace, nsNodeSelector, nsTolerations, nsAffinity), nil
+}
+
+func mergePodScheduling(
+	workspace *common.DevWorkspaceWithConfig,
+	nsNodeSelector map[string]string,
+	nsTolerations []corev1.Toleration,
+	nsAffinity *corev1.Affinity) *podScheduling {
+
+	workspaceConfig := workspace.Config.Workspace
+	scheduling := &podScheduling{}
+
+	if len(workspaceConfig.NodeSelector) > 0 || len(nsNodeSelector) > 0 {
+		scheduling.nodeSelector = map[string]string{}
+		for key, value := range workspaceConfig.NodeSelector {
+			scheduling.nodeSelector[key] = value
+		}
+		for key, value := range nsNodeSelector {
+			scheduling.nodeSelector[key] = value
+		}
+	}
+
+	scheduling.tolerations = append(scheduling.tolerations, workspaceConfig.Tolerations...)
+	for _, nsToleration := range nsTolerations {
+		if !containsToleration(scheduling.tolerations, nsToleration) {
+			scheduling.tolerations = append(scheduling.tolerations, nsToleration)
+		}
+	}
+
+	if nsAffinity != nil {
+		scheduling.affinity = nsAffinity
+	} else if workspaceConfig.Affinity != nil {
+		scheduling.affinity = workspaceConfig.Affinity.DeepCopy()
+	}
+
+	return scheduling
+}
+
+// applyTo sets the scheduling constraints on a pod spec. Fields that are not configured are left unchanged, so that
+// values set via pod overrides are preserved.
+func (s *podScheduling) applyTo(podSpec *corev1.PodSpec) {
+	if len(s.tolerations) > 0 {
+		podSpec.Tolerations = s.tolerations
+	}
+	if len(s.nodeSelector) > 0 {
+		podSpec.NodeSelector = s.nodeSelector
+	}
+	if s.affinity != nil {
+		podSpec.Affinity = s.affinity
+	}
+}
+
+func containsToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for _, t := range tolerations {
+		if t.MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
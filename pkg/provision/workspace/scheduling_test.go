@@ -0,0 +1,126 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+var schedulingTestAffinity = &corev1.Affinity{
+	NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "topology.kubernetes.io/zone",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"zone-a"},
+				}},
+			}},
+		},
+	},
+}
+
+func getSchedulingTestNamespace(annotations map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "test-namespace",
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestGetPodSchedulingUsesConfig(t *testing.T) {
+	workspace := getPriorityTestWorkspace(&v1alpha1.WorkspaceConfig{
+		NodeSelector: map[string]string{"node-pool": "workspaces"},
+		Tolerations:  []corev1.Toleration{{Key: "workspaces", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Affinity:     schedulingTestAffinity,
+	}, nil)
+	workspace.Namespace = "test-namespace"
+	clusterAPI := getLegacyTestClusterAPI(getSchedulingTestNamespace(nil))
+
+	scheduling, err := getPodScheduling(workspace, clusterAPI)
+	if !assert.NoError(t, err) {
+		return
+	}
+	podSpec := &corev1.PodSpec{}
+	scheduling.applyTo(podSpec)
+	assert.Equal(t, map[string]string{"node-pool": "workspaces"}, podSpec.NodeSelector)
+	assert.Equal(t, workspace.Config.Workspace.Tolerations, podSpec.Tolerations)
+	assert.Equal(t, schedulingTestAffinity, podSpec.Affinity)
+}
+
+func TestGetPodSchedulingMergesNamespaceAnnotations(t *testing.T) {
+	workspace := getPriorityTestWorkspace(&v1alpha1.WorkspaceConfig{
+		NodeSelector: map[string]string{"node-pool": "workspaces", "arch": "amd64"},
+		Tolerations:  []corev1.Toleration{{Key: "workspaces", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+		Affinity:     schedulingTestAffinity,
+	}, nil)
+	workspace.Namespace = "test-namespace"
+	clusterAPI := getLegacyTestClusterAPI(getSchedulingTestNamespace(map[string]string{
+		constants.NamespaceNodeSelectorAnnotation:   `{"node-pool": "team-a"}`,
+		constants.NamespacePodTolerationsAnnotation: `[{"key": "workspaces", "operator": "Exists", "effect": "NoSchedule"}, {"key": "team-a", "operator": "Exists"}]`,
+		constants.NamespacePodAffinityAnnotation:    `{"podAntiAffinity": {"preferredDuringSchedulingIgnoredDuringExecution": [{"weight": 100, "podAffinityTerm": {"topologyKey": "kubernetes.io/hostname"}}]}}`,
+	}))
+
+	scheduling, err := getPodScheduling(workspace, clusterAPI)
+	if !assert.NoError(t, err) {
+		return
+	}
+	podSpec := &corev1.PodSpec{}
+	scheduling.applyTo(podSpec)
+	assert.Equal(t, map[string]string{"node-pool": "team-a", "arch": "amd64"}, podSpec.NodeSelector, "Namespace node selector should take precedence")
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "workspaces", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		{Key: "team-a", Operator: corev1.TolerationOpExists},
+	}, podSpec.Tolerations, "Should add namespace tolerations without duplicates")
+	if assert.NotNil(t, podSpec.Affinity) {
+		assert.Nil(t, podSpec.Affinity.NodeAffinity, "Namespace affinity should replace affinity from config")
+		assert.NotNil(t, podSpec.Affinity.PodAntiAffinity)
+	}
+	assert.Equal(t, map[string]string{"node-pool": "workspaces", "arch": "amd64"}, workspace.Config.Workspace.NodeSelector, "Should not modify config")
+}
+
+func TestGetPodSchedulingInvalidAffinityAnnotation(t *testing.T) {
+	workspace := getPriorityTestWorkspace(&v1alpha1.WorkspaceConfig{}, nil)
+	workspace.Namespace = "test-namespace"
+	clusterAPI := getLegacyTestClusterAPI(getSchedulingTestNamespace(map[string]string{
+		constants.NamespacePodAffinityAnnotation: "not json",
+	}))
+
+	_, err := getPodScheduling(workspace, clusterAPI)
+	assert.ErrorContains(t, err, "failed to parse controller.devfile.io/pod-affinity annotation")
+}
+
+func TestPodSchedulingPreservesPodSpecWhenUnset(t *testing.T) {
+	workspace := getPriorityTestWorkspace(&v1alpha1.WorkspaceConfig{}, nil)
+	scheduling := mergePodScheduling(workspace, map[string]string{}, nil, nil)
+	podSpec := &corev1.PodSpec{
+		NodeSelector: map[string]string{"override": "true"},
+		Tolerations:  []corev1.Toleration{{Key: "override"}},
+		Affinity:     schedulingTestAffinity,
+	}
+	scheduling.applyTo(podSpec)
+	assert.Equal(t, map[string]string{"override": "true"}, podSpec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{{Key: "override"}}, podSpec.Tolerations)
+	assert.Equal(t, schedulingTestAffinity, podSpec.Affinity)
+}
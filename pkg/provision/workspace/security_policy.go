@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const (
+	fsGroupStrategyRunAsUser = "RunAsUser"
+	fsGroupStrategyNone      = "None"
+
+	// podSecurityEnforceLabel is the namespace label used by Pod Security Admission to select the enforced Pod Security
+	// Standard
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	podSecurityRestricted   = "restricted"
+)
+
+// applySecurityContextPolicy enforces the security context policy from the DevWorkspace Operator configuration on a
+// DevWorkspace pod spec and all of its containers. It should be called after any overrides have been applied to the
+// pod spec so that they can not be used to circumvent the policy.
+func applySecurityContextPolicy(workspace *common.DevWorkspaceWithConfig, podSpec *corev1.PodSpec) {
+	policy := workspace.Config.Workspace.SecurityContextPolicy
+	if policy == nil || !pointer.BoolDeref(policy.Enable, false) {
+		return
+	}
+
+	// The pod security context may be shared with the DevWorkspace Operator configuration, so it must be copied
+	podSecurityContext := podSpec.SecurityContext.DeepCopy()
+	if podSecurityContext == nil {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	}
+	if pointer.BoolDeref(policy.RunAsNonRoot, false) {
+		podSecurityContext.RunAsNonRoot = pointer.Bool(true)
+	}
+	switch policy.FSGroupStrategy {
+	case fsGroupStrategyRunAsUser:
+		if podSecurityContext.RunAsUser != nil {
+			podSecurityContext.FSGroup = pointer.Int64(*podSecurityContext.RunAsUser)
+		}
+	case fsGroupStrategyNone:
+		podSecurityContext.FSGroup = nil
+		podSecurityContext.FSGroupChangePolicy = nil
+	}
+	if policy.SeccompProfile != nil {
+		podSecurityContext.SeccompProfile = policy.SeccompProfile.DeepCopy()
+	}
+	podSpec.SecurityContext = podSecurityContext
+
+	for idx := range podSpec.InitContainers {
+		applyContainerSecurityContextPolicy(policy, &podSpec.InitContainers[idx])
+	}
+	for idx := range podSpec.Containers {
+		applyContainerSecurityContextPolicy(policy, &podSpec.Containers[idx])
+	}
+}
+
+func applyContainerSecurityContextPolicy(policy *v1alpha1.SecurityContextPolicyConfig, container *corev1.Container) {
+	securityContext := container.SecurityContext.DeepCopy()
+	if securityContext == nil {
+		securityContext = &corev1.SecurityContext{}
+	}
+	if pointer.BoolDeref(policy.RunAsNonRoot, false) {
+		securityContext.RunAsNonRoot = pointer.Bool(true)
+	}
+	if policy.AllowPrivilegeEscalation != nil {
+		securityContext.AllowPrivilegeEscalation = pointer.Bool(*policy.AllowPrivilegeEscalation)
+		if !*policy.AllowPrivilegeEscalation && pointer.BoolDeref(securityContext.Privileged, false) {
+			// Privileged containers always allow privilege escalation
+			securityContext.Privileged = pointer.Bool(false)
+		}
+	}
+	if len(policy.DropCapabilities) > 0 {
+		if securityContext.Capabilities == nil {
+			securityContext.Capabilities = &corev1.Capabilities{}
+		}
+		for _, capability := range policy.DropCapabilities {
+			if !containsCapability(securityContext.Capabilities.Drop, capability) {
+				securityContext.Capabilities.Drop = append(securityContext.Capabilities.Drop, capability)
+			}
+		}
+	}
+	container.SecurityContext = securityContext
+}
+
+// SyncNamespacePodSecurityLabels labels the namespace of a DevWorkspace to enforce the restricted Pod Security Standard,
+// if enabled in the security context policy. Namespaces that already define an enforced Pod Security Standard are not
+// changed.
+func SyncNamespacePodSecurityLabels(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	policy := workspace.Config.Workspace.SecurityContextPolicy
+	if policy == nil || !pointer.BoolDeref(policy.Enable, false) || !pointer.BoolDeref(policy.LabelNamespaces, false) {
+		return nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: workspace.Namespace}, namespace); err != nil {
+		return err
+	}
+	if _, ok := namespace.Labels[podSecurityEnforceLabel]; ok {
+		return nil
+	}
+	patch := client.MergeFrom(namespace.DeepCopy())
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	namespace.Labels[podSecurityEnforceLabel] = podSecurityRestricted
+	clusterAPI.Logger.Info("Labelling namespace to enforce restricted Pod Security Standard", "namespace", workspace.Namespace)
+	return clusterAPI.Client.Patch(clusterAPI.Ctx, namespace, patch)
+}
+
+func containsCapability(capabilities []corev1.Capability, capability corev1.Capability) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
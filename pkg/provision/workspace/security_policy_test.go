@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+func getSecurityPolicyTestConfig() *v1alpha1.WorkspaceConfig {
+	return &v1alpha1.WorkspaceConfig{
+		SecurityContextPolicy: &v1alpha1.SecurityContextPolicyConfig{
+			Enable:                   pointer.Bool(true),
+			RunAsNonRoot:             pointer.Bool(true),
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			FSGroupStrategy:          fsGroupStrategyRunAsUser,
+			SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			DropCapabilities:         []corev1.Capability{"ALL"},
+			LabelNamespaces:          pointer.Bool(true),
+		},
+	}
+}
+
+func TestApplySecurityContextPolicy(t *testing.T) {
+	workspace := getPriorityTestWorkspace(getSecurityPolicyTestConfig(), nil)
+	sharedPodSecurityContext := &corev1.PodSecurityContext{
+		RunAsUser:    pointer.Int64(1234),
+		RunAsNonRoot: pointer.Bool(false),
+		FSGroup:      pointer.Int64(0),
+	}
+	podSpec := &corev1.PodSpec{
+		SecurityContext: sharedPodSecurityContext,
+		InitContainers:  []corev1.Container{{Name: "project-clone"}},
+		Containers: []corev1.Container{
+			{
+				Name: "tools",
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: pointer.Bool(true),
+					Capabilities: &corev1.Capabilities{
+						Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+						Drop: []corev1.Capability{"NET_RAW"},
+					},
+				},
+			},
+		},
+	}
+
+	applySecurityContextPolicy(workspace, podSpec)
+
+	assert.Equal(t, &corev1.PodSecurityContext{
+		RunAsUser:      pointer.Int64(1234),
+		RunAsNonRoot:   pointer.Bool(true),
+		FSGroup:        pointer.Int64(1234),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}, podSpec.SecurityContext)
+	assert.False(t, *sharedPodSecurityContext.RunAsNonRoot, "Should not modify shared pod security context")
+	assert.Equal(t, &corev1.SecurityContext{
+		RunAsNonRoot:             pointer.Bool(true),
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}, podSpec.InitContainers[0].SecurityContext)
+	assert.Equal(t, &corev1.SecurityContext{
+		Privileged:               pointer.Bool(false),
+		RunAsNonRoot:             pointer.Bool(true),
+		AllowPrivilegeEscalation: pointer.Bool(false),
+		Capabilities: &corev1.Capabilities{
+			Add:  []corev1.Capability{"NET_BIND_SERVICE"},
+			Drop: []corev1.Capability{"NET_RAW", "ALL"},
+		},
+	}, podSpec.Containers[0].SecurityContext)
+}
+
+func TestApplySecurityContextPolicyFSGroupNone(t *testing.T) {
+	workspaceConfig := getSecurityPolicyTestConfig()
+	workspaceConfig.SecurityContextPolicy.FSGroupStrategy = fsGroupStrategyNone
+	workspaceConfig.SecurityContextPolicy.SeccompProfile = nil
+	workspace := getPriorityTestWorkspace(workspaceConfig, nil)
+	podSpec := &corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{FSGroup: pointer.Int64(1234)},
+	}
+
+	applySecurityContextPolicy(workspace, podSpec)
+
+	assert.Nil(t, podSpec.SecurityContext.FSGroup, "Should remove fsGroup")
+	assert.Nil(t, podSpec.SecurityContext.SeccompProfile, "Should not set seccomp profile")
+}
+
+func TestApplySecurityContextPolicyDisabled(t *testing.T) {
+	workspaceConfig := getSecurityPolicyTestConfig()
+	workspaceConfig.SecurityContextPolicy.Enable = pointer.Bool(false)
+	workspace := getPriorityTestWorkspace(workspaceConfig, nil)
+	podSpec := &corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "tools"}},
+	}
+
+	applySecurityContextPolicy(workspace, podSpec)
+
+	assert.Equal(t, &corev1.PodSpec{Containers: []corev1.Container{{Name: "tools"}}}, podSpec, "Should not modify pod spec")
+}
+
+func TestSyncNamespacePodSecurityLabels(t *testing.T) {
+	tests := []struct {
+		name           string
+		labels         map[string]string
+		labelNamespace bool
+		expectedLabel  string
+	}{
+		{
+			name:           "Labels namespace",
+			labelNamespace: true,
+			expectedLabel:  "restricted",
+		},
+		{
+			name:           "Does not change existing label",
+			labels:         map[string]string{podSecurityEnforceLabel: "baseline"},
+			labelNamespace: true,
+			expectedLabel:  "baseline",
+		},
+		{
+			name:           "Does not label namespace when disabled",
+			labelNamespace: false,
+			expectedLabel:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspaceConfig := getSecurityPolicyTestConfig()
+			workspaceConfig.SecurityContextPolicy.LabelNamespaces = pointer.Bool(tt.labelNamespace)
+			workspace := getPriorityTestWorkspace(workspaceConfig, nil)
+			workspace.Namespace = "test-namespace"
+			namespace := getSchedulingTestNamespace(nil)
+			namespace.Labels = tt.labels
+			clusterAPI := getLegacyTestClusterAPI(namespace)
+
+			if !assert.NoError(t, SyncNamespacePodSecurityLabels(workspace, clusterAPI)) {
+				return
+			}
+			clusterNamespace := &corev1.Namespace{}
+			if assert.NoError(t, clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: "test-namespace"}, clusterNamespace)) {
+				assert.Equal(t, tt.expectedLabel, clusterNamespace.Labels[podSecurityEnforceLabel])
+			}
+		})
+	}
+}
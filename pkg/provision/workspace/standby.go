@@ -0,0 +1,279 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+const defaultStandbyTimeout = 4 * time.Hour
+
+// HasStandbyComponents returns whether the workspace defines any container components with the
+// StandbyComponentAttribute.
+func HasStandbyComponents(workspace *dw.DevWorkspaceTemplateSpec) bool {
+	return len(getStandbyComponents(workspace)) > 0
+}
+
+func getStandbyComponents(workspace *dw.DevWorkspaceTemplateSpec) map[string]bool {
+	standbyComponents := map[string]bool{}
+	for _, component := range workspace.Components {
+		if component.Container != nil && component.Attributes.GetBoolean(constants.StandbyComponentAttribute, nil) {
+			standbyComponents[component.Name] = true
+		}
+	}
+	return standbyComponents
+}
+
+// SyncStandbyDeployment keeps the standby deployment of a stopped workspace running if the workspace was stopped due
+// to inactivity and defines standby components, until the workspace has been stopped for longer than the standby
+// timeout. Otherwise, any existing standby deployment is deleted. The standby deployment runs the standby containers
+// from the pod template of the workspace's (scaled down) deployment, with their resources replaced by the standby
+// resources from the config. If the standby deployment should keep running, returns the duration after which it
+// should be checked again.
+func SyncStandbyDeployment(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) (requeueAfter time.Duration, err error) {
+	if workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] != constants.DevWorkspaceStoppedByInactivity ||
+		!HasStandbyComponents(&workspace.Spec.Template) {
+		_, err := DeleteStandbyDeployment(clusterAPI.Ctx, workspace, clusterAPI.Client)
+		return 0, err
+	}
+
+	timeout := getStandbyTimeout(workspace)
+	remaining := time.Until(getStoppedAt(workspace).Add(timeout))
+	if remaining <= 0 {
+		_, err := DeleteStandbyDeployment(clusterAPI.Ctx, workspace, clusterAPI.Client)
+		return 0, err
+	}
+
+	workspaceDeployment := &appsv1.Deployment{}
+	deployNN := types.NamespacedName{
+		Name:      common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations),
+		Namespace: workspace.Namespace,
+	}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, deployNN, workspaceDeployment); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			// Workspace deployment was cleaned up on stop; there is nothing to keep on standby
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	specDeployment := getSpecStandbyDeployment(workspace, workspaceDeployment, timeout)
+	if specDeployment == nil {
+		return 0, nil
+	}
+	if err := controllerutil.SetControllerReference(workspace.DevWorkspace, specDeployment, clusterAPI.Scheme); err != nil {
+		return 0, err
+	}
+	if _, err := sync.SyncObjectWithCluster(specDeployment, clusterAPI); err != nil {
+		return 0, dwerrors.WrapSyncError(err)
+	}
+	return remaining, nil
+}
+
+func getSpecStandbyDeployment(workspace *common.DevWorkspaceWithConfig, workspaceDeployment *appsv1.Deployment, timeout time.Duration) *appsv1.Deployment {
+	standbyComponents := getStandbyComponents(&workspace.Spec.Template)
+	var standbyResources *corev1.ResourceRequirements
+	if workspace.Config.Workspace.Standby != nil {
+		standbyResources = workspace.Config.Workspace.Standby.Resources
+	}
+	podSpec := workspaceDeployment.Spec.Template.Spec.DeepCopy()
+	var standbyContainers []corev1.Container
+	for _, container := range podSpec.Containers {
+		if !standbyComponents[container.Name] {
+			continue
+		}
+		applyStandbyResources(&container, standbyResources)
+		standbyContainers = append(standbyContainers, container)
+	}
+	if len(standbyContainers) == 0 {
+		return nil
+	}
+	podSpec.Containers = standbyContainers
+	// Init containers already ran when the workspace was started
+	podSpec.InitContainers = nil
+
+	replicas := int32(1)
+	podLabels := map[string]string{
+		constants.DevWorkspaceStandbyIDLabel: workspace.Status.DevWorkspaceId,
+		constants.DevWorkspaceNameLabel:      workspace.Name,
+	}
+	if creator, ok := workspace.Labels[constants.DevWorkspaceCreatorLabel]; ok {
+		podLabels[constants.DevWorkspaceCreatorLabel] = creator
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.StandbyDeploymentName(workspace.Status.DevWorkspaceId),
+			Namespace: workspace.Namespace,
+			Labels: map[string]string{
+				constants.DevWorkspaceIDLabel:   workspace.Status.DevWorkspaceId,
+				constants.DevWorkspaceNameLabel: workspace.Name,
+			},
+			Annotations: map[string]string{
+				constants.DevWorkspaceStandbyTimeoutAnnotation: timeout.String(),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					constants.DevWorkspaceStandbyIDLabel: workspace.Status.DevWorkspaceId,
+				},
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: workspaceDeployment.Spec.Template.Annotations,
+				},
+				Spec: *podSpec,
+			},
+		},
+	}
+}
+
+// applyStandbyResources replaces the requests and limits of a container with those defined in resources. Resources
+// that are not defined are kept.
+func applyStandbyResources(container *corev1.Container, resources *corev1.ResourceRequirements) {
+	if resources == nil {
+		return
+	}
+	for resourceName, quantity := range resources.Requests {
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+		container.Resources.Requests[resourceName] = quantity
+	}
+	for resourceName, quantity := range resources.Limits {
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		container.Resources.Limits[resourceName] = quantity
+	}
+}
+
+func getStandbyTimeout(workspace *common.DevWorkspaceWithConfig) time.Duration {
+	if workspace.Config.Workspace.Standby == nil || workspace.Config.Workspace.Standby.Timeout == "" {
+		return defaultStandbyTimeout
+	}
+	timeout, err := time.ParseDuration(workspace.Config.Workspace.Standby.Timeout)
+	if err != nil {
+		return defaultStandbyTimeout
+	}
+	return timeout
+}
+
+// GetStandbyNode returns the name of the node running the standby pod of a workspace, or an empty string if no
+// standby pod is running.
+func GetStandbyNode(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) (string, error) {
+	pods := &corev1.PodList{}
+	if err := client.List(ctx, pods, k8sclient.InNamespace(workspace.Namespace), k8sclient.MatchingLabels{
+		constants.DevWorkspaceStandbyIDLabel: workspace.Status.DevWorkspaceId,
+	}); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil && pod.Spec.NodeName != "" {
+			return pod.Spec.NodeName, nil
+		}
+	}
+	return "", nil
+}
+
+// DeleteStandbyDeploymentIfScheduled deletes the standby deployment of a started workspace once the workspace's pod
+// has been scheduled. The standby deployment is kept until then so that volumes used by the workspace remain
+// attached to the node the workspace's pod is preferably scheduled on.
+func DeleteStandbyDeploymentIfScheduled(workspace *common.DevWorkspaceWithConfig, clusterAPI sync.ClusterAPI) error {
+	pods := &corev1.PodList{}
+	if err := clusterAPI.Client.List(clusterAPI.Ctx, pods, k8sclient.InNamespace(workspace.Namespace), k8sclient.MatchingLabels{
+		constants.DevWorkspaceIDLabel: workspace.Status.DevWorkspaceId,
+	}); err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			_, err := DeleteStandbyDeployment(clusterAPI.Ctx, workspace, clusterAPI.Client)
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteStandbyDeployment deletes the standby deployment for the DevWorkspace
+func DeleteStandbyDeployment(ctx context.Context, workspace *common.DevWorkspaceWithConfig, client k8sclient.Client) (deleted bool, err error) {
+	err = client.Delete(ctx, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: workspace.Namespace,
+			Name:      common.StandbyDeploymentName(workspace.Status.DevWorkspaceId),
+		},
+	})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// applyStandbyNodeAffinity prefers scheduling the workspace's pod on the node recorded in the
+// DevWorkspaceStandbyNodeAnnotation, where the workspace's images and volumes are already available.
+func applyStandbyNodeAffinity(workspace *common.DevWorkspaceWithConfig, podSpec *corev1.PodSpec) {
+	standbyNode := workspace.Annotations[constants.DevWorkspaceStandbyNodeAnnotation]
+	if standbyNode == "" {
+		return
+	}
+	// Affinity may be shared with the workspace config
+	affinity := podSpec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		corev1.PreferredSchedulingTerm{
+			Weight: 100,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{
+						Key:      corev1.LabelHostname,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{standbyNode},
+					},
+				},
+			},
+		})
+	podSpec.Affinity = affinity
+}
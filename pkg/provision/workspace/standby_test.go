@@ -0,0 +1,206 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/conditions"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+func getStandbyTestWorkspace(stopReason string, stoppedFor time.Duration) *common.DevWorkspaceWithConfig {
+	editor := dw.Component{
+		Name:       "editor",
+		Attributes: attributes.Attributes{}.PutBoolean(constants.StandbyComponentAttribute, true),
+		ComponentUnion: dw.ComponentUnion{
+			Container: &dw.ContainerComponent{},
+		},
+	}
+	tools := dw.Component{
+		Name: "tools",
+		ComponentUnion: dw.ComponentUnion{
+			Container: &dw.ContainerComponent{},
+		},
+	}
+	workspace := testutil.NewDevWorkspaceWithConfig("test-workspace", "")
+	workspace.Annotations[constants.DevWorkspaceStopReasonAnnotation] = stopReason
+	workspace.Spec.Template.Components = []dw.Component{editor, tools}
+	workspace.Status.Conditions = []dw.DevWorkspaceCondition{
+		{
+			Type:               conditions.Started,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-stoppedFor)),
+		},
+	}
+	workspace.Config.Workspace.Standby = &v1alpha1.StandbyConfig{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("100m"),
+			},
+		},
+		Timeout: "1h",
+	}
+	return workspace
+}
+
+func getStandbyTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.DeploymentName("test-workspaceid", nil),
+			Namespace: "test-namespace",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "project-clone"}},
+					Containers: []corev1.Container{
+						{
+							Name: "editor",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("1Gi"),
+									corev1.ResourceCPU:    resource.MustParse("500m"),
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("2Gi"),
+									corev1.ResourceCPU:    resource.MustParse("1"),
+								},
+							},
+						},
+						{Name: "tools"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetSpecStandbyDeployment(t *testing.T) {
+	workspace := getStandbyTestWorkspace(constants.DevWorkspaceStoppedByInactivity, 0)
+	deployment := getSpecStandbyDeployment(workspace, getStandbyTestDeployment(), time.Hour)
+	if !assert.NotNil(t, deployment, "Should return standby deployment") {
+		return
+	}
+	assert.Equal(t, common.StandbyDeploymentName("test-workspaceid"), deployment.Name)
+	assert.Equal(t, "1h0m0s", deployment.Annotations[constants.DevWorkspaceStandbyTimeoutAnnotation])
+	assert.Equal(t, "test-workspaceid", deployment.Spec.Template.Labels[constants.DevWorkspaceStandbyIDLabel])
+	assert.Empty(t, deployment.Spec.Template.Spec.InitContainers, "Should not run init containers in standby deployment")
+	if assert.Len(t, deployment.Spec.Template.Spec.Containers, 1, "Should only run standby containers") {
+		container := deployment.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "editor", container.Name)
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+		}, container.Resources.Requests, "Should override requests defined in standby config")
+		assert.Equal(t, corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+		}, container.Resources.Limits, "Should override limits defined in standby config")
+	}
+}
+
+func TestSyncStandbyDeployment(t *testing.T) {
+	tests := []struct {
+		name          string
+		stopReason    string
+		stoppedFor    time.Duration
+		expectStandby bool
+	}{
+		{
+			name:          "Keeps standby deployment running for idled workspace",
+			stopReason:    constants.DevWorkspaceStoppedByInactivity,
+			stoppedFor:    10 * time.Minute,
+			expectStandby: true,
+		},
+		{
+			name:          "Deletes standby deployment after standby timeout",
+			stopReason:    constants.DevWorkspaceStoppedByInactivity,
+			stoppedFor:    2 * time.Hour,
+			expectStandby: false,
+		},
+		{
+			name:          "Does not keep standby deployment for workspaces stopped for other reasons",
+			stopReason:    "user",
+			stoppedFor:    10 * time.Minute,
+			expectStandby: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getStandbyTestWorkspace(tt.stopReason, tt.stoppedFor)
+			client := testutil.NewFakeClient(getStandbyTestDeployment())
+			clusterAPI := sync.ClusterAPI{
+				Ctx:    context.Background(),
+				Client: client,
+				Scheme: testutil.Scheme,
+				Logger: zap.New(),
+			}
+
+			requeueAfter, err := SyncStandbyDeployment(workspace, clusterAPI)
+			// Creating an object through the sync package returns a RetryError to requeue once it exists
+			if err != nil && !assert.IsType(t, &dwerrors.RetryError{}, err, "Should not return unexpected error") {
+				return
+			}
+			err = client.Get(context.Background(), types.NamespacedName{Name: common.StandbyDeploymentName("test-workspaceid"), Namespace: "test-namespace"}, &appsv1.Deployment{})
+			if tt.expectStandby {
+				assert.NoError(t, err, "Should create standby deployment")
+			} else {
+				assert.True(t, k8sErrors.IsNotFound(err), "Should not create standby deployment")
+				assert.Zero(t, requeueAfter, "Should not requeue when standby deployment is not needed")
+			}
+		})
+	}
+}
+
+func TestApplyStandbyNodeAffinity(t *testing.T) {
+	workspace := getStandbyTestWorkspace("", 0)
+	workspace.Annotations[constants.DevWorkspaceStandbyNodeAnnotation] = "node-1"
+	sharedAffinity := &corev1.Affinity{}
+	podSpec := &corev1.PodSpec{Affinity: sharedAffinity}
+
+	applyStandbyNodeAffinity(workspace, podSpec)
+
+	assert.Nil(t, sharedAffinity.NodeAffinity, "Should not modify affinity shared with config")
+	if assert.NotNil(t, podSpec.Affinity.NodeAffinity) {
+		terms := podSpec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		if assert.Len(t, terms, 1) {
+			assert.Equal(t, []string{"node-1"}, terms[0].Preference.MatchExpressions[0].Values)
+			assert.Equal(t, corev1.LabelHostname, terms[0].Preference.MatchExpressions[0].Key)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+	"github.com/devfile/devworkspace-operator/pkg/library/status"
+	"github.com/devfile/devworkspace-operator/pkg/provision/storage"
+	"github.com/devfile/devworkspace-operator/pkg/provision/sync"
+)
+
+// previousWorkloadRequeueDelay is how long to wait before checking whether the workload previously used to run a
+// DevWorkspace has been deleted, after the DevWorkspace is switched between Deployments and StatefulSets.
+const previousWorkloadRequeueDelay = 5 * time.Second
+
+// UsesStatefulSet returns whether the DevWorkspace is run as a StatefulSet instead of a Deployment, which is the case
+// if its storage type is listed in the StatefulSetStorageTypes in the config.
+func UsesStatefulSet(workspace *common.DevWorkspaceWithConfig) bool {
+	storageType := storage.GetStorageType(workspace)
+	for _, statefulSetStorageType := range workspace.Config.Workspace.StatefulSetStorageTypes {
+		if statefulSetStorageType == storageType {
+			return true
+		}
+	}
+	return false
+}
+
+// syncStatefulSetToCluster runs the DevWorkspace as a StatefulSet with the same metadata and pod template as
+// specDeployment. If the DevWorkspace was previously run as a Deployment, the Deployment is deleted first.
+func syncStatefulSetToCluster(workspace *common.DevWorkspaceWithConfig, specDeployment *appsv1.Deployment, clusterAPI sync.ClusterAPI) error {
+	if err := deletePreviousWorkload(workspace, &appsv1.Deployment{}, clusterAPI); err != nil {
+		return err
+	}
+
+	specStatefulSet := getSpecStatefulSet(workspace, specDeployment)
+	clusterObj, err := sync.SyncObjectWithCluster(specStatefulSet, clusterAPI)
+	if err != nil {
+		return dwerrors.WrapSyncError(err)
+	}
+
+	clusterStatefulSet := clusterObj.(*appsv1.StatefulSet)
+	if status.CheckStatefulSetStatus(clusterStatefulSet) {
+		return nil
+	}
+	if err := checkWorkspacePodsState(workspace, clusterAPI); err != nil {
+		return err
+	}
+	return &dwerrors.RetryError{Message: "StatefulSet is not ready"}
+}
+
+// getSpecStatefulSet returns a StatefulSet that runs the pod template of the DevWorkspace's deployment. The
+// StatefulSet's pods are replaced only after the previous pod has terminated, regardless of the deployment strategy.
+// Persistent volumes are provisioned by the storage provisioners and referenced by the pod template, so no volume
+// claim templates are used.
+func getSpecStatefulSet(workspace *common.DevWorkspaceWithConfig, specDeployment *appsv1.Deployment) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: *specDeployment.ObjectMeta.DeepCopy(),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:            specDeployment.Spec.Replicas,
+			Selector:            specDeployment.Spec.Selector.DeepCopy(),
+			Template:            *specDeployment.Spec.Template.DeepCopy(),
+			ServiceName:         common.ServiceName(workspace.Status.DevWorkspaceId, workspace.Annotations),
+			PodManagementPolicy: appsv1.OrderedReadyPodManagement,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+		},
+	}
+}
+
+// deletePreviousWorkload deletes the Deployment or StatefulSet (depending on the type of workload) that was used to
+// run the DevWorkspace before it was switched to the other kind of workload. The workload is deleted in the foreground,
+// so that its pods have terminated and released the DevWorkspace's persistent volumes once it is removed. Returns a
+// RetryError until the workload is removed from the cluster.
+func deletePreviousWorkload(workspace *common.DevWorkspaceWithConfig, workload k8sclient.Object, clusterAPI sync.ClusterAPI) error {
+	workloadNN := types.NamespacedName{Name: common.DeploymentName(workspace.Status.DevWorkspaceId, workspace.Annotations), Namespace: workspace.Namespace}
+	if err := clusterAPI.Client.Get(clusterAPI.Ctx, workloadNN, workload); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(workload, workspace.DevWorkspace) {
+		return nil
+	}
+	if workload.GetDeletionTimestamp() == nil {
+		clusterAPI.Logger.Info("Deleting previous workload for DevWorkspace", "name", workload.GetName(), "usesStatefulSet", UsesStatefulSet(workspace))
+		err := clusterAPI.Client.Delete(clusterAPI.Ctx, workload, k8sclient.PropagationPolicy(metav1.DeletePropagationForeground))
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return &dwerrors.RetryError{
+		Message:      "Waiting for previous workspace workload to be deleted",
+		RequeueAfter: previousWorkloadRequeueDelay,
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/dwerrors"
+)
+
+func getStatefulSetTestWorkspace() *common.DevWorkspaceWithConfig {
+	workspace := getLegacyTestWorkspace()
+	workspace.Config = &v1alpha1.OperatorConfiguration{
+		Workspace: &v1alpha1.WorkspaceConfig{
+			StatefulSetStorageTypes: []string{constants.PerWorkspaceStorageClassType},
+		},
+	}
+	return workspace
+}
+
+func TestUsesStatefulSet(t *testing.T) {
+	workspaceConfig := &v1alpha1.WorkspaceConfig{
+		DefaultStorageType:      constants.PerUserStorageClassType,
+		StatefulSetStorageTypes: []string{constants.PerWorkspaceStorageClassType},
+	}
+
+	tests := []struct {
+		name        string
+		storageType string
+		expected    bool
+	}{
+		{
+			name:     "Uses Deployment for default storage type",
+			expected: false,
+		},
+		{
+			name:        "Uses StatefulSet for listed storage type",
+			storageType: constants.PerWorkspaceStorageClassType,
+			expected:    true,
+		},
+		{
+			name:        "Uses Deployment for storage type not listed",
+			storageType: constants.EphemeralStorageClassType,
+			expected:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspaceAttributes := attributes.Attributes{}
+			if tt.storageType != "" {
+				workspaceAttributes.PutString(constants.DevWorkspaceStorageTypeAttribute, tt.storageType)
+			}
+			workspace := getPriorityTestWorkspace(workspaceConfig, workspaceAttributes)
+			assert.Equal(t, tt.expected, UsesStatefulSet(workspace))
+		})
+	}
+}
+
+func TestGetSpecStatefulSet(t *testing.T) {
+	workspace := getStatefulSetTestWorkspace()
+	specDeployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	specDeployment.Spec.Template.Spec.Hostname = "test-hostname"
+
+	statefulSet := getSpecStatefulSet(workspace, specDeployment)
+	assert.Equal(t, specDeployment.Name, statefulSet.Name)
+	assert.Equal(t, specDeployment.OwnerReferences, statefulSet.OwnerReferences, "Should be owned by DevWorkspace")
+	assert.Equal(t, specDeployment.Spec.Selector, statefulSet.Spec.Selector)
+	assert.Equal(t, specDeployment.Spec.Template, statefulSet.Spec.Template)
+	assert.Equal(t, common.ServiceName(legacyTestWorkspaceID, nil), statefulSet.Spec.ServiceName)
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, statefulSet.Spec.PodManagementPolicy)
+	assert.Empty(t, statefulSet.Spec.VolumeClaimTemplates, "Should not use volume claim templates")
+}
+
+func TestDeploymentIsDeletedWhenSwitchingToStatefulSet(t *testing.T) {
+	workspace := getStatefulSetTestWorkspace()
+	deployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	clusterAPI := getLegacyTestClusterAPI(deployment)
+
+	err := deletePreviousWorkload(workspace, &appsv1.Deployment{}, clusterAPI)
+	assert.IsType(t, &dwerrors.RetryError{}, err, "Should return RetryError while deployment is deleted")
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, &appsv1.Deployment{})
+	assert.True(t, k8sErrors.IsNotFound(err), "Should delete deployment")
+
+	err = deletePreviousWorkload(workspace, &appsv1.Deployment{}, clusterAPI)
+	assert.NoError(t, err, "Should not return error once deployment is removed")
+}
+
+func TestPreviousWorkloadNotOwnedByWorkspaceIsNotDeleted(t *testing.T) {
+	workspace := getStatefulSetTestWorkspace()
+	deployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	deployment.OwnerReferences = nil
+	clusterAPI := getLegacyTestClusterAPI(deployment)
+
+	err := deletePreviousWorkload(workspace, &appsv1.Deployment{}, clusterAPI)
+	assert.NoError(t, err)
+	err = clusterAPI.Client.Get(clusterAPI.Ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, &appsv1.Deployment{})
+	assert.NoError(t, err, "Should not delete deployment not owned by DevWorkspace")
+}
+
+func TestStatefulSetIsScaledToZero(t *testing.T) {
+	workspace := getStatefulSetTestWorkspace()
+	deployment := getLegacyTestDeployment(workspace, map[string]string{constants.DevWorkspaceIDLabel: legacyTestWorkspaceID}, 1)
+	statefulSet := getSpecStatefulSet(workspace, deployment)
+	statefulSet.Status.Replicas = 1
+	clusterAPI := getLegacyTestClusterAPI(statefulSet)
+
+	replicas, currentReplicas, found, err := GetWorkspaceReplicas(clusterAPI.Ctx, workspace, clusterAPI.Client)
+	require.NoError(t, err)
+	assert.True(t, found, "Should find StatefulSet")
+	assert.Equal(t, pointer.Int32(1), replicas)
+	assert.Equal(t, int32(1), currentReplicas)
+
+	require.NoError(t, ScaleDeploymentToZero(clusterAPI.Ctx, workspace, clusterAPI.Client))
+	replicas, _, _, err = GetWorkspaceReplicas(clusterAPI.Ctx, workspace, clusterAPI.Client)
+	require.NoError(t, err)
+	assert.Equal(t, pointer.Int32(0), replicas, "Should scale StatefulSet to zero")
+
+	wait, err := DeleteWorkspaceDeployment(clusterAPI.Ctx, workspace, clusterAPI.Client)
+	require.NoError(t, err)
+	assert.True(t, wait, "Should wait for StatefulSet to be deleted")
+	_, _, found, err = GetWorkspaceReplicas(clusterAPI.Ctx, workspace, clusterAPI.Client)
+	require.NoError(t, err)
+	assert.False(t, found, "Should delete StatefulSet")
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/devfile/devworkspace-operator/pkg/common"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	sharedMemoryVolumeName = "dev-shm"
+	sharedMemoryMountPath  = "/dev/shm"
+)
+
+// safeSysctls are the sysctls that Kubernetes considers safe, i.e. that are isolated between pods and allowed by
+// default on all nodes. Some of them are only supported by recent versions of Kubernetes.
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+	"net.ipv4.ip_local_reserved_ports":    true,
+	"net.ipv4.tcp_keepalive_time":         true,
+	"net.ipv4.tcp_fin_timeout":            true,
+	"net.ipv4.tcp_keepalive_intvl":        true,
+	"net.ipv4.tcp_keepalive_probes":       true,
+}
+
+// addSharedMemoryVolume mounts a memory-backed emptyDir volume at /dev/shm in all containers of a DevWorkspace pod,
+// if a shared memory size is set in the config or the SharedMemorySizeAttribute. Containers that already mount a
+// volume at /dev/shm are left unchanged. Returns an error if the attribute has an invalid value.
+func addSharedMemoryVolume(workspace *common.DevWorkspaceWithConfig, podSpec *corev1.PodSpec) error {
+	var size *resource.Quantity
+	if workspace.Config.Workspace.SharedMemorySize != nil {
+		configSize := workspace.Config.Workspace.SharedMemorySize.DeepCopy()
+		size = &configSize
+	}
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.SharedMemorySizeAttribute) {
+		var err error
+		sizeAttr := attributes.GetString(constants.SharedMemorySizeAttribute, &err)
+		if err != nil {
+			return fmt.Errorf("failed to read attribute %s: %w", constants.SharedMemorySizeAttribute, err)
+		}
+		attrSize, err := resource.ParseQuantity(sizeAttr)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for attribute %s: %w", sizeAttr, constants.SharedMemorySizeAttribute, err)
+		}
+		size = &attrSize
+	}
+	if size == nil || size.IsZero() {
+		return nil
+	}
+	if size.Sign() < 0 {
+		return fmt.Errorf("invalid shared memory size %s: must not be negative", size.String())
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: sharedMemoryVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium:    corev1.StorageMediumMemory,
+				SizeLimit: size,
+			},
+		},
+	})
+	for idx := range podSpec.Containers {
+		container := &podSpec.Containers[idx]
+		if mountsPath(container, sharedMemoryMountPath) {
+			continue
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      sharedMemoryVolumeName,
+			MountPath: sharedMemoryMountPath,
+		})
+	}
+	return nil
+}
+
+// setPodSysctls sets the sysctls of a DevWorkspace pod from the config and the SysctlsAttribute. Sysctls from the
+// attribute override sysctls from the config with the same name. Returns an error if the attribute cannot be read or
+// sets a sysctl that is not considered safe by Kubernetes.
+func setPodSysctls(workspace *common.DevWorkspaceWithConfig, podSpec *corev1.PodSpec) error {
+	sysctls := map[string]string{}
+	for _, sysctl := range workspace.Config.Workspace.Sysctls {
+		sysctls[sysctl.Name] = sysctl.Value
+	}
+	attributes := workspace.Spec.Template.Attributes
+	if attributes.Exists(constants.SysctlsAttribute) {
+		attrSysctls := map[string]intstr.IntOrString{}
+		if err := attributes.GetInto(constants.SysctlsAttribute, &attrSysctls); err != nil {
+			return fmt.Errorf("failed to read attribute %s: %w", constants.SysctlsAttribute, err)
+		}
+		for name, value := range attrSysctls {
+			if !safeSysctls[name] {
+				return fmt.Errorf("sysctl %s cannot be set with attribute %s as it is not a safe sysctl", name, constants.SysctlsAttribute)
+			}
+			sysctls[name] = value.String()
+		}
+	}
+	if len(sysctls) == 0 {
+		return nil
+	}
+
+	// The pod security context may be shared with the DevWorkspace Operator configuration, so it must be copied
+	podSecurityContext := podSpec.SecurityContext.DeepCopy()
+	if podSecurityContext == nil {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	}
+	for _, sysctl := range podSecurityContext.Sysctls {
+		if _, ok := sysctls[sysctl.Name]; !ok {
+			sysctls[sysctl.Name] = sysctl.Value
+		}
+	}
+	var names []string
+	for name := range sysctls {
+		names = append(names, name)
+	}
+	// Sort sysctls so that the deployment does not change between reconciles
+	sort.Strings(names)
+	podSecurityContext.Sysctls = nil
+	for _, name := range names {
+		podSecurityContext.Sysctls = append(podSecurityContext.Sysctls, corev1.Sysctl{Name: name, Value: sysctls[name]})
+	}
+	podSpec.SecurityContext = podSecurityContext
+	return nil
+}
+
+func mountsPath(container *corev1.Container, mountPath string) bool {
+	for _, volumeMount := range container.VolumeMounts {
+		if volumeMount.MountPath == mountPath {
+			return true
+		}
+	}
+	return false
+}
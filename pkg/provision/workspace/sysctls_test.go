@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package workspace
+
+import (
+	"testing"
+
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getSysctlsTestPodSpec() *corev1.PodSpec {
+	return &corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "tools"},
+			{
+				Name: "browser",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "custom-shm", MountPath: "/dev/shm"},
+				},
+			},
+		},
+	}
+}
+
+func TestAddSharedMemoryVolume(t *testing.T) {
+	configSize := resource.MustParse("1Gi")
+	workspaceConfig := &v1alpha1.WorkspaceConfig{SharedMemorySize: &configSize}
+
+	tests := []struct {
+		name         string
+		config       *v1alpha1.WorkspaceConfig
+		attributes   attributes.Attributes
+		expectedSize string
+		expectedErr  string
+	}{
+		{
+			name:   "No volume if size is not set",
+			config: &v1alpha1.WorkspaceConfig{},
+		},
+		{
+			name:         "Uses size from config",
+			config:       workspaceConfig,
+			expectedSize: "1Gi",
+		},
+		{
+			name:         "Attribute overrides config",
+			config:       workspaceConfig,
+			attributes:   attributes.Attributes{}.PutString(constants.SharedMemorySizeAttribute, "2Gi"),
+			expectedSize: "2Gi",
+		},
+		{
+			name:       "Attribute disables volume",
+			config:     workspaceConfig,
+			attributes: attributes.Attributes{}.PutString(constants.SharedMemorySizeAttribute, "0"),
+		},
+		{
+			name:        "Invalid attribute",
+			config:      workspaceConfig,
+			attributes:  attributes.Attributes{}.PutString(constants.SharedMemorySizeAttribute, "lots"),
+			expectedErr: `invalid value "lots" for attribute controller.devfile.io/shm-size: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getPriorityTestWorkspace(tt.config, tt.attributes)
+			podSpec := getSysctlsTestPodSpec()
+			err := addSharedMemoryVolume(workspace, podSpec)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectedSize == "" {
+				assert.Empty(t, podSpec.Volumes, "Should not add shared memory volume")
+				assert.Empty(t, podSpec.Containers[0].VolumeMounts)
+				return
+			}
+			if assert.Len(t, podSpec.Volumes, 1) && assert.NotNil(t, podSpec.Volumes[0].EmptyDir) {
+				assert.Equal(t, corev1.StorageMediumMemory, podSpec.Volumes[0].EmptyDir.Medium)
+				assert.Equal(t, tt.expectedSize, podSpec.Volumes[0].EmptyDir.SizeLimit.String())
+			}
+			assert.Equal(t, []corev1.VolumeMount{{Name: sharedMemoryVolumeName, MountPath: "/dev/shm"}}, podSpec.Containers[0].VolumeMounts)
+			assert.Len(t, podSpec.Containers[1].VolumeMounts, 1, "Should not change containers that already mount /dev/shm")
+		})
+	}
+}
+
+func TestSetPodSysctls(t *testing.T) {
+	workspaceConfig := &v1alpha1.WorkspaceConfig{
+		Sysctls: []corev1.Sysctl{
+			{Name: "net.ipv4.tcp_syncookies", Value: "0"},
+			{Name: "kernel.msgmax", Value: "65536"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		attributes      attributes.Attributes
+		expectedSysctls []corev1.Sysctl
+		expectedErr     string
+	}{
+		{
+			name: "Uses sysctls from config",
+			expectedSysctls: []corev1.Sysctl{
+				{Name: "kernel.msgmax", Value: "65536"},
+				{Name: "net.ipv4.tcp_syncookies", Value: "0"},
+			},
+		},
+		{
+			name: "Attribute adds and overrides sysctls",
+			attributes: attributes.Attributes{}.FromMap(map[string]interface{}{
+				constants.SysctlsAttribute: map[string]interface{}{
+					"net.ipv4.tcp_syncookies":      1,
+					"net.ipv4.ip_local_port_range": "1024 65535",
+				},
+			}, nil),
+			expectedSysctls: []corev1.Sysctl{
+				{Name: "kernel.msgmax", Value: "65536"},
+				{Name: "net.ipv4.ip_local_port_range", Value: "1024 65535"},
+				{Name: "net.ipv4.tcp_syncookies", Value: "1"},
+			},
+		},
+		{
+			name: "Attribute cannot set unsafe sysctls",
+			attributes: attributes.Attributes{}.FromMap(map[string]interface{}{
+				constants.SysctlsAttribute: map[string]interface{}{
+					"kernel.msgmax": "1",
+				},
+			}, nil),
+			expectedErr: "sysctl kernel.msgmax cannot be set with attribute controller.devfile.io/sysctls as it is not a safe sysctl",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := getPriorityTestWorkspace(workspaceConfig, tt.attributes)
+			podSecurityContext := &corev1.PodSecurityContext{}
+			podSpec := &corev1.PodSpec{SecurityContext: podSecurityContext}
+			err := setPodSysctls(workspace, podSpec)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSysctls, podSpec.SecurityContext.Sysctls)
+			assert.Empty(t, podSecurityContext.Sysctls, "Should not modify shared pod security context")
+		})
+	}
+}
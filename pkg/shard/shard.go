@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package shard allows running multiple instances of the controller that each reconcile the DevWorkspaces in a
+// subset of namespaces. Each instance (shard) is configured with the total number of shards and its own index through
+// environment variables. Namespaces are assigned to shards by the DevWorkspaceShardLabel label, if present, and by
+// a hash of the namespace's name otherwise.
+package shard
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const (
+	// ShardCountEnvVar is the environment variable that defines the total number of shards. Sharding is disabled if
+	// it is unset or set to 1.
+	ShardCountEnvVar = "CONTROLLER_SHARD_COUNT"
+	// ShardIndexEnvVar is the environment variable that defines the index of this shard, from 0 to the shard count
+	// minus one.
+	ShardIndexEnvVar = "CONTROLLER_SHARD_INDEX"
+)
+
+var (
+	index = 0
+	count = 1
+)
+
+// Initialize reads the shard configuration from the environment. It returns an error if the configuration is invalid.
+func Initialize() error {
+	countEnv, ok := os.LookupEnv(ShardCountEnvVar)
+	if !ok || countEnv == "" {
+		return nil
+	}
+	shardCount, err := strconv.Atoi(countEnv)
+	if err != nil || shardCount < 1 {
+		return fmt.Errorf("environment variable %s must be a positive integer, got %q", ShardCountEnvVar, countEnv)
+	}
+	if shardCount == 1 {
+		return nil
+	}
+	indexEnv := os.Getenv(ShardIndexEnvVar)
+	shardIndex, err := strconv.Atoi(indexEnv)
+	if err != nil || shardIndex < 0 || shardIndex >= shardCount {
+		return fmt.Errorf("environment variable %s must be an integer between 0 and %d when %s is set, got %q",
+			ShardIndexEnvVar, shardCount-1, ShardCountEnvVar, indexEnv)
+	}
+	index, count = shardIndex, shardCount
+	return nil
+}
+
+// InitializeForTesting is used to mock running as a specific shard in testing code.
+func InitializeForTesting(shardIndex, shardCount int) {
+	index, count = shardIndex, shardCount
+}
+
+// IsEnabled returns true if the controller is running as one of multiple shards.
+func IsEnabled() bool {
+	return count > 1
+}
+
+// IsPrimary returns true if this is the first shard, or if sharding is disabled. Components that are not sharded
+// (e.g. cluster-wide controllers) should only run in the primary shard.
+func IsPrimary() bool {
+	return index == 0
+}
+
+// GetIndex returns the index of this shard.
+func GetIndex() int {
+	return index
+}
+
+// GetCount returns the total number of shards.
+func GetCount() int {
+	return count
+}
+
+// GetLeaderElectionID returns the leader election ID for this shard, so that each shard elects its own leader.
+func GetLeaderElectionID(baseID string) string {
+	if !IsEnabled() {
+		return baseID
+	}
+	return fmt.Sprintf("%s-shard-%d", baseID, index)
+}
+
+// GetAssignmentMessage returns a human-readable description of this shard, for use in DevWorkspace status.
+func GetAssignmentMessage() string {
+	return fmt.Sprintf("Reconciled by controller shard %d of %d", index, count)
+}
+
+// GetShardForNamespace returns the index of the shard responsible for a namespace. If the namespace has the
+// DevWorkspaceShardLabel label with a valid shard index, that shard is used; otherwise, the shard is selected by
+// hashing the namespace's name.
+func GetShardForNamespace(namespace *corev1.Namespace) int {
+	if label, ok := namespace.Labels[constants.DevWorkspaceShardLabel]; ok {
+		if labelIndex, err := strconv.Atoi(label); err == nil && labelIndex >= 0 && labelIndex < count {
+			return labelIndex
+		}
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(namespace.Name))
+	return int(hash.Sum32() % uint32(count))
+}
+
+// OwnsNamespace returns whether this shard is responsible for objects in the given namespace. It always returns
+// true if sharding is disabled.
+func OwnsNamespace(ctx context.Context, c client.Reader, namespaceName string) (bool, error) {
+	if !IsEnabled() {
+		return true, nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespaceName}, namespace); err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return false, err
+		}
+		namespace.Name = namespaceName
+	}
+	return GetShardForNamespace(namespace) == index, nil
+}
+
+// NamespacePredicates filters namespace events to only those where the namespace's shard label is changed, which
+// may move the namespace to a different shard.
+func NamespacePredicates() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(_ event.CreateEvent) bool { return false },
+		DeleteFunc: func(_ event.DeleteEvent) bool { return false },
+		UpdateFunc: func(ev event.UpdateEvent) bool {
+			return ev.ObjectOld.GetLabels()[constants.DevWorkspaceShardLabel] != ev.ObjectNew.GetLabels()[constants.DevWorkspaceShardLabel]
+		},
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package shard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func getTestNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func TestInitialize(t *testing.T) {
+	tests := []struct {
+		name          string
+		count         string
+		index         string
+		expectedIndex int
+		expectedCount int
+		expectedErr   bool
+	}{
+		{name: "Sharding disabled when unset", expectedCount: 1},
+		{name: "Sharding disabled for single shard", count: "1", expectedCount: 1},
+		{name: "Reads shard index and count", count: "4", index: "2", expectedIndex: 2, expectedCount: 4},
+		{name: "Error if index is unset", count: "4", expectedErr: true},
+		{name: "Error if index is out of range", count: "4", index: "4", expectedErr: true},
+		{name: "Error if count is invalid", count: "zero", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitializeForTesting(0, 1)
+			defer InitializeForTesting(0, 1)
+			if tt.count != "" {
+				t.Setenv(ShardCountEnvVar, tt.count)
+			}
+			if tt.index != "" {
+				t.Setenv(ShardIndexEnvVar, tt.index)
+			}
+			err := Initialize()
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIndex, GetIndex())
+			assert.Equal(t, tt.expectedCount, GetCount())
+		})
+	}
+}
+
+func TestGetShardForNamespace(t *testing.T) {
+	InitializeForTesting(0, 4)
+	defer InitializeForTesting(0, 1)
+
+	assert.Equal(t, 3, GetShardForNamespace(getTestNamespace("test-namespace", map[string]string{constants.DevWorkspaceShardLabel: "3"})),
+		"Should use shard from namespace label")
+
+	hashed := GetShardForNamespace(getTestNamespace("test-namespace", nil))
+	assert.Equal(t, hashed, GetShardForNamespace(getTestNamespace("test-namespace", map[string]string{constants.DevWorkspaceShardLabel: "7"})),
+		"Should ignore out of range shard label")
+
+	counts := map[int]int{}
+	for i := 0; i < 1000; i++ {
+		counts[GetShardForNamespace(getTestNamespace(fmt.Sprintf("user%d-devspaces", i), nil))]++
+	}
+	assert.Len(t, counts, 4, "Should distribute namespaces across all shards")
+}
+
+func TestOwnsNamespace(t *testing.T) {
+	c := fake.NewClientBuilder().WithObjects(
+		getTestNamespace("shard-0", map[string]string{constants.DevWorkspaceShardLabel: "0"}),
+		getTestNamespace("shard-1", map[string]string{constants.DevWorkspaceShardLabel: "1"}),
+	).Build()
+	defer InitializeForTesting(0, 1)
+
+	InitializeForTesting(1, 2)
+	owned, err := OwnsNamespace(context.Background(), c, "shard-1")
+	require.NoError(t, err)
+	assert.True(t, owned)
+	owned, err = OwnsNamespace(context.Background(), c, "shard-0")
+	require.NoError(t, err)
+	assert.False(t, owned)
+
+	InitializeForTesting(0, 1)
+	owned, err = OwnsNamespace(context.Background(), c, "shard-1")
+	require.NoError(t, err)
+	assert.True(t, owned, "Should own all namespaces when sharding is disabled")
+}
+
+func TestGetLeaderElectionID(t *testing.T) {
+	defer InitializeForTesting(0, 1)
+	InitializeForTesting(0, 1)
+	assert.Equal(t, "test-id", GetLeaderElectionID("test-id"))
+	InitializeForTesting(2, 3)
+	assert.Equal(t, "test-id-shard-2", GetLeaderElectionID("test-id"))
+}
+
+func TestNamespacePredicates(t *testing.T) {
+	predicates := NamespacePredicates()
+	oldNamespace := getTestNamespace("test-namespace", map[string]string{constants.DevWorkspaceShardLabel: "0"})
+	assert.False(t, predicates.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: oldNamespace.DeepCopy()}))
+	newNamespace := getTestNamespace("test-namespace", map[string]string{constants.DevWorkspaceShardLabel: "1"})
+	assert.True(t, predicates.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: newNamespace}))
+	assert.False(t, predicates.Create(event.CreateEvent{Object: newNamespace}))
+}
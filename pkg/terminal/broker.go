@@ -0,0 +1,224 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package terminal implements the terminal broker, which provides authenticated shell access to the containers of
+// running DevWorkspaces over WebSockets. Sessions can optionally be reported to a session recording endpoint, e.g. to
+// meet auditing requirements in regulated environments.
+package terminal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/go-logr/logr"
+	"golang.org/x/net/websocket"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/library/access"
+	"github.com/devfile/devworkspace-operator/pkg/library/pods"
+)
+
+// PathPrefix is the path under which the broker serves terminal sessions. Sessions are opened by connecting to
+// <PathPrefix><namespace>/<workspace name>/<container name>.
+const PathPrefix = "/terminal/"
+
+// Broker serves terminal sessions for the containers of running DevWorkspaces. It implements http.Handler and is
+// intended to be registered on the controller manager's webhook server, so that it is served over TLS by the
+// operator's manager service.
+type Broker struct {
+	// Client is used to review tokens and access and to read DevWorkspaces and their pods.
+	Client   client.Client
+	Executor Executor
+	// Hook, if set, is notified of terminal sessions instead of the session recording endpoint configured in the
+	// global DevWorkspaceOperatorConfig.
+	Hook       SessionHook
+	HTTPClient *http.Client
+	Log        logr.Logger
+}
+
+var _ http.Handler = (*Broker)(nil)
+
+type sessionTarget struct {
+	namespace string
+	workspace string
+	container string
+}
+
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	terminalConfig := config.GetGlobalConfig().Terminal
+	if terminalConfig == nil || !pointer.BoolDeref(terminalConfig.Enable, false) {
+		http.Error(w, "terminal broker is not enabled", http.StatusNotFound)
+		return
+	}
+	target, err := parsePath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	ctx := r.Context()
+	log := b.Log.WithValues("namespace", target.namespace, "workspace", target.workspace, "container", target.container)
+
+	workspace, user := access.AuthorizeRequest(w, r, b.Client, target.namespace, target.workspace, authzv1.ResourceAttributes{
+		Verb:        "create",
+		Resource:    "pods",
+		Subresource: "exec",
+	}, log)
+	if workspace == nil {
+		return
+	}
+	log = log.WithValues("user", user.Username)
+
+	if workspace.Status.Phase != dw.DevWorkspaceStatusRunning {
+		http.Error(w, fmt.Sprintf("workspace %s is not running", workspace.Name), http.StatusConflict)
+		return
+	}
+	pod, err := pods.GetRunningWorkspacePod(ctx, b.Client, workspace.Namespace, workspace.Status.DevWorkspaceId)
+	if err != nil {
+		log.Error(err, "Failed to get pod for terminal session")
+		http.Error(w, "failed to get workspace pod", http.StatusInternalServerError)
+		return
+	}
+	if pod == nil {
+		http.Error(w, fmt.Sprintf("workspace %s is not running", workspace.Name), http.StatusConflict)
+		return
+	}
+	if !hasContainer(pod, target.container) {
+		http.Error(w, fmt.Sprintf("workspace %s has no container %s", workspace.Name, target.container), http.StatusNotFound)
+		return
+	}
+
+	session := &Session{
+		ID:        string(uuid.NewUUID()),
+		User:      user.Username,
+		UID:       user.UID,
+		Namespace: workspace.Namespace,
+		Workspace: workspace.Name,
+		Pod:       pod.Name,
+		Container: target.container,
+		StartTime: time.Now().UTC(),
+	}
+	log = log.WithValues("session", session.ID)
+	hook := b.getSessionHook(terminalConfig)
+	var recording *Recording
+	if hook != nil {
+		if err := hook.SessionStarted(ctx, session); err != nil {
+			log.Error(err, "Refusing terminal session as it could not be recorded")
+			http.Error(w, "terminal session could not be recorded", http.StatusServiceUnavailable)
+			return
+		}
+		recording = newRecording(session.StartTime)
+	}
+
+	server := websocket.Server{
+		Handshake: func(wsConfig *websocket.Config, _ *http.Request) error {
+			// Only accept the channel protocol; the bearer token subprotocol must not be echoed back to the client
+			protocols := wsConfig.Protocol
+			wsConfig.Protocol = nil
+			for _, protocol := range protocols {
+				if protocol == channelProtocol {
+					wsConfig.Protocol = []string{channelProtocol}
+				}
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			log.Info("Terminal session started", "pod", pod.Name)
+			sessionErr := b.serveSession(ctx, ws, pod, target.container, terminalConfig.Command, recording)
+			endTime := time.Now().UTC()
+			session.EndTime = &endTime
+			if sessionErr != nil {
+				log.Info("Terminal session ended with error", "error", sessionErr.Error())
+			} else {
+				log.Info("Terminal session ended")
+			}
+			if hook != nil {
+				// The request context is cancelled once the session ends, so it cannot be used to send the recording
+				if err := hook.SessionEnded(context.Background(), session, recording); err != nil {
+					log.Error(err, "Failed to send terminal session recording")
+				}
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// serveSession runs a shell in the container, connected to the WebSocket connection, until either the shell exits or
+// the client disconnects.
+func (b *Broker) serveSession(ctx context.Context, ws *websocket.Conn, pod *corev1.Pod, container string, command []string, recording *Recording) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	conn := newChannelConn(ws, recording)
+	defer conn.close()
+	go func() {
+		conn.readLoop()
+		// The client disconnected
+		cancel()
+	}()
+
+	err := b.Executor.Stream(ctx, pod, container, command, conn.streams())
+	if err != nil && !errors.Is(err, context.Canceled) {
+		// Report the error to the client, which may still be connected
+		_ = conn.write(errorChannel, []byte(err.Error()))
+		return err
+	}
+	return nil
+}
+
+// getSessionHook returns the hook notified of terminal sessions, or nil if sessions are not recorded.
+func (b *Broker) getSessionHook(terminalConfig *controllerv1alpha1.TerminalConfig) SessionHook {
+	if b.Hook != nil {
+		return b.Hook
+	}
+	if terminalConfig.SessionRecording == nil || terminalConfig.SessionRecording.URL == "" {
+		return nil
+	}
+	httpClient := b.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &webhookSessionHook{config: terminalConfig.SessionRecording, httpClient: httpClient}
+}
+
+func hasContainer(pod *corev1.Pod, container string) bool {
+	for _, podContainer := range pod.Spec.Containers {
+		if podContainer.Name == container {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePath(path string) (*sessionTarget, error) {
+	parts := strings.Split(strings.TrimPrefix(path, PathPrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return nil, fmt.Errorf("expected path %s<namespace>/<workspace>/<container>", PathPrefix)
+	}
+	return &sessionTarget{
+		namespace: parts[0],
+		workspace: parts[1],
+		container: parts[2],
+	}, nil
+}
@@ -0,0 +1,361 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dw "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+	authnv1 "k8s.io/api/authentication/v1"
+	authzv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/access"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	creatorToken   = "creator-token"
+	creatorUID     = testutil.TestCreatorUID
+	execUserToken  = "exec-user-token"
+	otherUserToken = "other-user-token"
+)
+
+// newReviewClient returns a fake client with objs that authenticates the test tokens and allows exec-user to exec into pods.
+func newReviewClient(objs ...client.Object) *testutil.ReviewClient {
+	return &testutil.ReviewClient{
+		Client: testutil.NewFakeClient(objs...),
+		Users: map[string]authnv1.UserInfo{
+			creatorToken:   {Username: "creator", UID: creatorUID},
+			execUserToken:  {Username: "exec-user", UID: "exec-user-uid"},
+			otherUserToken: {Username: "other-user", UID: "other-user-uid"},
+		},
+		Permissions: map[string]authzv1.ResourceAttributes{
+			"exec-user": {Verb: "create", Resource: "pods", Subresource: "exec"},
+		},
+	}
+}
+
+// echoExecutor writes a prompt and echoes stdin back to stdout until stdin is closed or "exit" is received.
+type echoExecutor struct {
+	mu      sync.Mutex
+	sizes   []string
+	command []string
+}
+
+func (e *echoExecutor) Stream(ctx context.Context, pod *corev1.Pod, container string, command []string, streams Streams) error {
+	e.mu.Lock()
+	e.command = command
+	e.mu.Unlock()
+	go func() {
+		for size := streams.Sizes.Next(); size != nil; size = streams.Sizes.Next() {
+			e.mu.Lock()
+			e.sizes = append(e.sizes, fmt.Sprintf("%dx%d", size.Width, size.Height))
+			e.mu.Unlock()
+		}
+	}()
+	if _, err := fmt.Fprintf(streams.Stdout, "%s/%s$ ", pod.Name, container); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(streams.Stdin)
+	for scanner.Scan() {
+		if scanner.Text() == "exit" {
+			return nil
+		}
+		if _, err := fmt.Fprintln(streams.Stdout, scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type testHook struct {
+	mu        sync.Mutex
+	refuse    bool
+	started   []*Session
+	ended     []*Session
+	recording []byte
+	endedCh   chan struct{}
+}
+
+func (h *testHook) SessionStarted(_ context.Context, session *Session) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.refuse {
+		return fmt.Errorf("recording unavailable")
+	}
+	h.started = append(h.started, session)
+	return nil
+}
+
+func (h *testHook) SessionEnded(_ context.Context, session *Session, recording *Recording) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ended = append(h.ended, session)
+	h.recording, _ = recording.Bytes()
+	close(h.endedCh)
+	return nil
+}
+
+func getTestPod(name, workspaceID string, labels map[string]string) *corev1.Pod {
+	podLabels := map[string]string{constants.DevWorkspaceIDLabel: workspaceID}
+	for key, value := range labels {
+		podLabels[key] = value
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    podLabels,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "tools"}, {Name: "db"}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+}
+
+func setupTestBroker(t *testing.T, hook SessionHook, objs ...client.Object) (*httptest.Server, *echoExecutor) {
+	config.SetGlobalConfigForTesting(&v1alpha1.OperatorConfiguration{
+		Terminal: &v1alpha1.TerminalConfig{
+			Enable: pointer.Bool(true),
+		},
+	})
+	executor := &echoExecutor{}
+	broker := &Broker{
+		Client:   newReviewClient(objs...),
+		Executor: executor,
+		Log:      zap.New(),
+	}
+	if hook != nil {
+		broker.Hook = hook
+	}
+	mux := http.NewServeMux()
+	mux.Handle(PathPrefix, broker)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, executor
+}
+
+func getStatusCode(t *testing.T, server *httptest.Server, path, token string) int {
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func dialTerminal(t *testing.T, server *httptest.Server, path, token string) *websocket.Conn {
+	wsConfig, err := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+path, server.URL)
+	require.NoError(t, err)
+	wsConfig.Protocol = []string{channelProtocol, access.BearerProtocolPrefix + base64.RawURLEncoding.EncodeToString([]byte(token))}
+	ws, err := websocket.DialConfig(wsConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func sendMessage(t *testing.T, ws *websocket.Conn, channel byte, data string) {
+	require.NoError(t, websocket.Message.Send(ws, append([]byte{channel}, []byte(data)...)))
+}
+
+// readOutput reads stdout messages from the broker until output contains expected.
+func readOutput(t *testing.T, ws *websocket.Conn, expected string) string {
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+	output := ""
+	for !strings.Contains(output, expected) {
+		var message []byte
+		require.NoError(t, websocket.Message.Receive(ws, &message), "Should receive output %q, got %q", expected, output)
+		require.Equal(t, stdoutChannel, message[0], "Should only receive stdout messages")
+		output += string(message[1:])
+	}
+	return output
+}
+
+func TestTerminalSessionForCreator(t *testing.T) {
+	workspace := testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning)
+	hook := &testHook{endedCh: make(chan struct{})}
+	server, executor := setupTestBroker(t, hook,
+		workspace,
+		getTestPod("test-workspace-task", "test-workspaceid", map[string]string{"job-name": "test-task"}),
+		getTestPod("test-workspace-pod", "test-workspaceid", nil))
+
+	ws := dialTerminal(t, server, PathPrefix+testNamespace+"/test-workspace/db", creatorToken)
+	assert.Equal(t, "test-workspace-pod/db$ ", readOutput(t, ws, "$ "), "Should open shell in workspace pod, not task pod")
+	sendMessage(t, ws, resizeChannel, `{"Width":120,"Height":40}`)
+	sendMessage(t, ws, stdinChannel, "echo hello\n")
+	assert.Equal(t, "echo hello\n", readOutput(t, ws, "\n"))
+	sendMessage(t, ws, stdinChannel, "exit\n")
+
+	select {
+	case <-hook.endedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Session should end when the shell exits")
+	}
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+	assert.Equal(t, []string{"120x40"}, executor.sizes, "Should forward terminal size to executor")
+	assert.Equal(t, config.GetGlobalConfig().Terminal.Command, executor.command, "Should run configured command")
+
+	if assert.Len(t, hook.started, 1) && assert.Len(t, hook.ended, 1) {
+		session := hook.ended[0]
+		assert.Equal(t, "creator", session.User)
+		assert.Equal(t, "test-workspace", session.Workspace)
+		assert.Equal(t, "test-workspace-pod", session.Pod)
+		assert.Equal(t, "db", session.Container)
+		assert.NotNil(t, session.EndTime, "Should set end time for ended session")
+	}
+	lines := strings.Split(strings.TrimSpace(string(hook.recording)), "\n")
+	header := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header), "Recording should start with asciicast header")
+	assert.EqualValues(t, 2, header["version"])
+	var eventTypes, eventData []string
+	for _, line := range lines[1:] {
+		var event []interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "Recording events should be JSON arrays")
+		eventTypes = append(eventTypes, event[1].(string))
+		eventData = append(eventData, event[2].(string))
+	}
+	assert.Equal(t, []string{"o", "r", "i", "o", "i"}, eventTypes)
+	assert.Equal(t, []string{"test-workspace-pod/db$ ", "120x40", "echo hello\n", "echo hello\n", "exit\n"}, eventData)
+}
+
+func TestTerminalSessionRequiresAccess(t *testing.T) {
+	restricted := testutil.NewDevWorkspace("restricted-workspace", dw.DevWorkspaceStatusRunning)
+	restricted.Annotations = map[string]string{constants.DevWorkspaceRestrictedAccessAnnotation: "true"}
+	server, _ := setupTestBroker(t, nil,
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		getTestPod("test-workspace-pod", "test-workspaceid", nil),
+		restricted,
+		getTestPod("restricted-workspace-pod", "restricted-workspaceid", nil),
+		testutil.NewDevWorkspace("stopped-workspace", dw.DevWorkspaceStatusStopped))
+
+	tests := []struct {
+		name         string
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"No token", "test-workspace/tools", "", http.StatusUnauthorized},
+		{"Invalid token", "test-workspace/tools", "invalid-token", http.StatusUnauthorized},
+		{"User without access", "test-workspace/tools", otherUserToken, http.StatusForbidden},
+		{"Workspace not found", "missing-workspace/tools", execUserToken, http.StatusForbidden},
+		{"Restricted access workspace", "restricted-workspace/tools", execUserToken, http.StatusForbidden},
+		{"Workspace not running", "stopped-workspace/tools", creatorToken, http.StatusConflict},
+		{"Container not found", "test-workspace/missing", creatorToken, http.StatusNotFound},
+		{"Invalid path", "test-workspace", creatorToken, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedCode, getStatusCode(t, server, PathPrefix+testNamespace+"/"+tt.path, tt.token))
+		})
+	}
+
+	ws := dialTerminal(t, server, PathPrefix+testNamespace+"/test-workspace/tools", execUserToken)
+	assert.Equal(t, "test-workspace-pod/tools$ ", readOutput(t, ws, "$ "), "Should allow users with pods/exec access")
+}
+
+func TestTerminalSessionRefusedWhenNotRecorded(t *testing.T) {
+	server, _ := setupTestBroker(t, &testHook{refuse: true},
+		testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning),
+		getTestPod("test-workspace-pod", "test-workspaceid", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, getStatusCode(t, server, PathPrefix+testNamespace+"/test-workspace/tools", creatorToken))
+}
+
+func TestTerminalBrokerDisabled(t *testing.T) {
+	server, _ := setupTestBroker(t, nil, testutil.NewDevWorkspace("test-workspace", dw.DevWorkspaceStatusRunning))
+	config.SetGlobalConfigForTesting(nil)
+	assert.Equal(t, http.StatusNotFound, getStatusCode(t, server, PathPrefix+testNamespace+"/test-workspace/tools", creatorToken))
+}
+
+func TestWebhookSessionHook(t *testing.T) {
+	var records []SessionRecord
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		record := SessionRecord{}
+		if err := json.Unmarshal(body, &record); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		records = append(records, record)
+	}))
+	defer endpoint.Close()
+
+	hook := &webhookSessionHook{
+		config:     &v1alpha1.TerminalSessionRecordingConfig{URL: endpoint.URL, Timeout: "5s"},
+		httpClient: endpoint.Client(),
+	}
+	session := &Session{ID: "test-session", User: "test-user", Workspace: "test-workspace"}
+	recording := newRecording(time.Now())
+	recording.RecordOutput([]byte("$ "))
+	assert.NoError(t, hook.SessionStarted(context.Background(), session))
+	assert.NoError(t, hook.SessionEnded(context.Background(), session, recording))
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, SessionStarted, records[0].Type)
+		assert.Empty(t, records[0].Recording, "Should not send recording when session starts")
+		assert.Equal(t, SessionEnded, records[1].Type)
+		assert.Equal(t, "test-session", records[1].Session.ID)
+		assert.Contains(t, records[1].Recording, `"o","$ "]`)
+	}
+
+	hook.config.URL = endpoint.URL + "/missing"
+	assert.Error(t, hook.SessionStarted(context.Background(), session), "Should return error if endpoint does not accept record")
+}
+
+func TestRecordingTruncated(t *testing.T) {
+	recording := newRecording(time.Now())
+	chunk := []byte(strings.Repeat("a", 1024*1024))
+	for i := 0; i < 10; i++ {
+		recording.RecordOutput(chunk)
+	}
+	data, truncated := recording.Bytes()
+	assert.True(t, truncated, "Should mark recording as truncated")
+	assert.LessOrEqual(t, len(data), maxRecordingSize+200, "Should limit size of recording")
+}
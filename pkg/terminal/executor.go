@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package terminal
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Executor starts interactive commands in the containers of pods. It is an interface to allow the streaming APIs,
+// which are not supported by controller-runtime clients, to be replaced in tests.
+type Executor interface {
+	// Stream runs command in the container of a pod with a TTY attached, connecting it to the provided streams
+	// until the command exits or ctx is cancelled.
+	Stream(ctx context.Context, pod *corev1.Pod, container string, command []string, streams Streams) error
+}
+
+// Streams are the streams connected to a command started by an Executor. As a TTY is attached, the command's
+// stderr is written to Stdout.
+type Streams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	// Sizes provides changes to the size of the user's terminal
+	Sizes remotecommand.TerminalSizeQueue
+}
+
+type clusterExecutor struct {
+	config    *rest.Config
+	clientset kubernetes.Interface
+}
+
+var _ Executor = (*clusterExecutor)(nil)
+
+// NewExecutor returns an Executor that uses the Kubernetes API described by config.
+func NewExecutor(config *rest.Config) (Executor, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterExecutor{config: config, clientset: clientset}, nil
+}
+
+func (e *clusterExecutor) Stream(ctx context.Context, pod *corev1.Pod, container string, command []string, streams Streams) error {
+	req := e.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     true,
+			Stdout:    true,
+			TTY:       true,
+		}, clientgoscheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:             streams.Stdin,
+		Stdout:            streams.Stdout,
+		Tty:               true,
+		TerminalSizeQueue: streams.Sizes,
+	})
+}
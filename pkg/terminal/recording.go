@@ -0,0 +1,198 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	controllerv1alpha1 "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+)
+
+// maxRecordingSize is the maximum size of a session recording. Once a recording reaches this size, further events
+// are dropped and the recording is marked as truncated.
+const maxRecordingSize = 8 * 1024 * 1024
+
+// Default terminal size used in the header of recordings; the actual size is recorded as a resize event once the
+// client reports it.
+const (
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+type SessionRecordType string
+
+const (
+	SessionStarted SessionRecordType = "started"
+	SessionEnded   SessionRecordType = "ended"
+)
+
+// Session describes a terminal session opened through the broker.
+type Session struct {
+	ID        string     `json:"id"`
+	User      string     `json:"user"`
+	UID       string     `json:"uid,omitempty"`
+	Namespace string     `json:"namespace"`
+	Workspace string     `json:"workspace"`
+	Pod       string     `json:"pod"`
+	Container string     `json:"container"`
+	StartTime time.Time  `json:"startTime"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+}
+
+// SessionRecord is the JSON body sent to the session recording endpoint.
+type SessionRecord struct {
+	Type    SessionRecordType `json:"type"`
+	Session *Session          `json:"session"`
+	// Recording is the recording of the session in asciicast v2 format. It is only set for ended sessions.
+	Recording string `json:"recording,omitempty"`
+	// Truncated is true if the session produced more data than can be held in a recording.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// SessionHook is notified of terminal sessions, e.g. to audit or record them.
+type SessionHook interface {
+	// SessionStarted is called before a session is opened. If an error is returned, the session is refused.
+	SessionStarted(ctx context.Context, session *Session) error
+	// SessionEnded is called once a session is closed, with the recording of the session.
+	SessionEnded(ctx context.Context, session *Session, recording *Recording) error
+}
+
+// webhookSessionHook sends session records to the session recording endpoint configured in the global
+// DevWorkspaceOperatorConfig.
+type webhookSessionHook struct {
+	config     *controllerv1alpha1.TerminalSessionRecordingConfig
+	httpClient *http.Client
+}
+
+var _ SessionHook = (*webhookSessionHook)(nil)
+
+func (h *webhookSessionHook) SessionStarted(ctx context.Context, session *Session) error {
+	return h.send(ctx, &SessionRecord{
+		Type:    SessionStarted,
+		Session: session,
+	})
+}
+
+func (h *webhookSessionHook) SessionEnded(ctx context.Context, session *Session, recording *Recording) error {
+	data, truncated := recording.Bytes()
+	return h.send(ctx, &SessionRecord{
+		Type:      SessionEnded,
+		Session:   session,
+		Recording: string(data),
+		Truncated: truncated,
+	})
+}
+
+func (h *webhookSessionHook) send(ctx context.Context, record *SessionRecord) error {
+	timeout, err := time.ParseDuration(h.config.Timeout)
+	if err != nil {
+		return fmt.Errorf("invalid duration specified for session recording timeout: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to serialize session record: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request for session recording endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send session record: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("session recording endpoint returned unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Recording records the input, output and terminal size changes of a session in asciicast v2 format.
+// See https://docs.asciinema.org/manual/asciicast/v2/
+type Recording struct {
+	mu        sync.Mutex
+	start     time.Time
+	events    bytes.Buffer
+	truncated bool
+}
+
+func newRecording(start time.Time) *Recording {
+	return &Recording{start: start}
+}
+
+// RecordInput records data sent by the user.
+func (r *Recording) RecordInput(data []byte) {
+	r.record("i", string(data))
+}
+
+// RecordOutput records data written to the user's terminal.
+func (r *Recording) RecordOutput(data []byte) {
+	r.record("o", string(data))
+}
+
+// RecordResize records a change to the size of the user's terminal.
+func (r *Recording) RecordResize(width, height uint16) {
+	r.record("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *Recording) record(eventType, data string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.truncated {
+		return
+	}
+	event, err := json.Marshal([]interface{}{time.Since(r.start).Seconds(), eventType, data})
+	if err != nil {
+		return
+	}
+	if r.events.Len()+len(event)+1 > maxRecordingSize {
+		r.truncated = true
+		return
+	}
+	r.events.Write(event)
+	r.events.WriteByte('\n')
+}
+
+// Bytes returns the recording in asciicast v2 format, and whether events were dropped because the recording
+// exceeded its maximum size.
+func (r *Recording) Bytes() ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	header, _ := json.Marshal(map[string]interface{}{
+		"version":   2,
+		"width":     defaultTerminalWidth,
+		"height":    defaultTerminalHeight,
+		"timestamp": r.start.Unix(),
+	})
+	data := make([]byte, 0, len(header)+1+r.events.Len())
+	data = append(data, header...)
+	data = append(data, '\n')
+	data = append(data, r.events.Bytes()...)
+	return data, r.truncated
+}
@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package terminal
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"golang.org/x/net/websocket"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// channelProtocol is the WebSocket subprotocol spoken by the broker. Each message is prefixed by a byte identifying
+// the stream it belongs to, following the convention used by the Kubernetes API server for pods/exec.
+const channelProtocol = "channel.k8s.io"
+
+const (
+	stdinChannel  byte = 0
+	stdoutChannel byte = 1
+	errorChannel  byte = 3
+	resizeChannel byte = 4
+)
+
+// channelConn multiplexes the streams of a terminal session over a WebSocket connection.
+type channelConn struct {
+	ws        *websocket.Conn
+	writeMu   sync.Mutex
+	recording *Recording
+
+	stdinReader *io.PipeReader
+	stdinWriter *io.PipeWriter
+	sizes       chan remotecommand.TerminalSize
+	done        chan struct{}
+}
+
+func newChannelConn(ws *websocket.Conn, recording *Recording) *channelConn {
+	ws.PayloadType = websocket.BinaryFrame
+	stdinReader, stdinWriter := io.Pipe()
+	return &channelConn{
+		ws:          ws,
+		recording:   recording,
+		stdinReader: stdinReader,
+		stdinWriter: stdinWriter,
+		sizes:       make(chan remotecommand.TerminalSize, 1),
+		done:        make(chan struct{}),
+	}
+}
+
+func (c *channelConn) streams() Streams {
+	return Streams{
+		Stdin:  c.stdinReader,
+		Stdout: &channelWriter{conn: c, channel: stdoutChannel},
+		Sizes:  c,
+	}
+}
+
+// readLoop reads messages sent by the client until the connection is closed. It must be run in a separate goroutine.
+func (c *channelConn) readLoop() {
+	defer close(c.sizes)
+	defer c.stdinWriter.Close()
+	for {
+		var message []byte
+		if err := websocket.Message.Receive(c.ws, &message); err != nil {
+			return
+		}
+		if len(message) == 0 {
+			continue
+		}
+		switch message[0] {
+		case stdinChannel:
+			c.recording.RecordInput(message[1:])
+			if _, err := c.stdinWriter.Write(message[1:]); err != nil {
+				return
+			}
+		case resizeChannel:
+			size := remotecommand.TerminalSize{}
+			if err := json.Unmarshal(message[1:], &size); err != nil || size.Width == 0 || size.Height == 0 {
+				continue
+			}
+			c.recording.RecordResize(size.Width, size.Height)
+			select {
+			case c.sizes <- size:
+			case <-c.done:
+				return
+			}
+		}
+	}
+}
+
+// Next implements remotecommand.TerminalSizeQueue
+func (c *channelConn) Next() *remotecommand.TerminalSize {
+	size, ok := <-c.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+func (c *channelConn) write(channel byte, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	message := make([]byte, 0, len(data)+1)
+	message = append(message, channel)
+	message = append(message, data...)
+	return websocket.Message.Send(c.ws, message)
+}
+
+// close stops the session, closing the connection to the client.
+func (c *channelConn) close() {
+	close(c.done)
+	c.stdinReader.Close()
+	c.ws.Close()
+}
+
+type channelWriter struct {
+	conn    *channelConn
+	channel byte
+}
+
+func (w *channelWriter) Write(data []byte) (int, error) {
+	if w.channel == stdoutChannel {
+		w.conn.recording.RecordOutput(data)
+	}
+	if err := w.conn.write(w.channel, data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -25,6 +25,7 @@ import (
 	webhook_k8s "github.com/devfile/devworkspace-operator/pkg/webhook/kubernetes"
 	webhook_openshift "github.com/devfile/devworkspace-operator/pkg/webhook/openshift"
 
+	"github.com/go-logr/logr"
 	"k8s.io/client-go/rest"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -74,6 +75,10 @@ func SetupWebhooks(ctx context.Context, cfg *rest.Config) error {
 		if err != nil {
 			return err
 		}
+		err = webhook_k8s.SyncWebhookCertificates(ctx, client, secretName, namespace)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Set up the deployment
@@ -86,6 +91,28 @@ func SetupWebhooks(ctx context.Context, cfg *rest.Config) error {
 	return nil
 }
 
+// GetCertificateRotator returns a runnable that renews the webhook server's certificates on Kubernetes, if they are
+// generated by the operator. On OpenShift, certificates are managed by the Service CA operator and nil is returned.
+func GetCertificateRotator(client crclient.Client, logger logr.Logger) (*webhook_k8s.CertificateRotator, error) {
+	if infrastructure.IsOpenShift() {
+		return nil, nil
+	}
+	namespace, err := infrastructure.GetOperatorNamespace()
+	if err != nil {
+		namespace = os.Getenv(infrastructure.WatchNamespaceEnvVar)
+	}
+	secretName, err := config.GetWebhooksSecretName()
+	if err != nil {
+		return nil, err
+	}
+	return &webhook_k8s.CertificateRotator{
+		Client:     client,
+		SecretName: secretName,
+		Namespace:  namespace,
+		Log:        logger,
+	}, nil
+}
+
 // setUpWebhookServerRBAC sets required service account, cluster role, and cluster role binding
 // for creating a webhook server
 func setUpWebhookServerRBAC(ctx context.Context, err error, client crclient.Client, namespace string) error {
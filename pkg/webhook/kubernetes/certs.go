@@ -0,0 +1,408 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhook_k8s
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/go-logr/logr"
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/webhook/server"
+	"github.com/devfile/devworkspace-operator/webhook/workspace"
+)
+
+const (
+	// caCertKey is the key in the serving certificate secret holding the CA bundle trusted for the webhook server
+	caCertKey = "ca.crt"
+	// previousCACertKey is the key in the CA secret holding the CA certificate that was replaced by the current one. It
+	// is kept in the CA bundle until it expires, so that certificates it issued remain trusted during rotation.
+	previousCACertKey = "previous.crt"
+
+	caValidity             = 3 * 365 * 24 * time.Hour
+	caRenewBefore          = 90 * 24 * time.Hour
+	servingCertValidity    = 365 * 24 * time.Hour
+	servingCertRenewBefore = 30 * 24 * time.Hour
+
+	// certCheckInterval is how often the CertificateRotator checks whether certificates need to be renewed
+	certCheckInterval = 1 * time.Hour
+)
+
+// now is used to get the current time when generating and checking certificates, and can be replaced in tests.
+var now = time.Now
+
+// CertificateRotator periodically renews the webhook server's serving certificate and the CA that issued it, when they
+// are generated by the operator. See SyncWebhookCertificates.
+type CertificateRotator struct {
+	Client     crclient.Client
+	SecretName string
+	Namespace  string
+	Log        logr.Logger
+}
+
+func (r *CertificateRotator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start checks the webhook server's certificates periodically until ctx is cancelled.
+func (r *CertificateRotator) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(certCheckInterval):
+		}
+		if err := SyncWebhookCertificates(ctx, r.Client, r.SecretName, r.Namespace); err != nil {
+			r.Log.Error(err, "Failed to sync webhook server certificates")
+		}
+	}
+}
+
+// SyncWebhookCertificates generates the webhook server's serving certificate in the secret secretName, along with a
+// self-signed CA that issues it, and renews them before they expire. The CA is stored in a separate secret that is
+// not mounted to the webhook server. If the serving certificate secret exists and was not created by the operator (i.e.
+// it does not have the WebhookCertsManagedLabel), it is assumed to be managed externally and nothing is done.
+//
+// To avoid downtime during rotation, the CA bundle is injected into the webhook configurations before a certificate
+// issued by a new CA is written, and the previous CA is kept in the bundle until it expires. The webhook server
+// reloads the serving certificate from its mounted secret once it is updated, without needing to be restarted.
+func SyncWebhookCertificates(ctx context.Context, client crclient.Client, secretName, namespace string) error {
+	secret := &corev1.Secret{}
+	err := client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		return err
+	default:
+		if secret.Labels[constants.WebhookCertsManagedLabel] != "true" {
+			return nil
+		}
+	}
+
+	ca, caBundle, err := syncCA(ctx, client, getCASecretName(secretName), namespace)
+	if err != nil {
+		return fmt.Errorf("failed to sync webhook server CA: %w", err)
+	}
+	if err := injectCABundle(ctx, client, caBundle); err != nil {
+		return fmt.Errorf("failed to inject CA bundle into webhook configurations: %w", err)
+	}
+	if err := syncServingCert(ctx, client, secret, secretName, namespace, ca, caBundle); err != nil {
+		return fmt.Errorf("failed to sync webhook server serving certificate: %w", err)
+	}
+	return nil
+}
+
+// syncCA reads the webhook server's CA from the secret caSecretName, generating a new one if it does not exist or
+// expires soon. Returns the current CA and the CA bundle to be trusted for the webhook server.
+func syncCA(ctx context.Context, client crclient.Client, caSecretName, namespace string) (ca *keyPair, caBundle []byte, err error) {
+	secret := &corev1.Secret{}
+	err = client.Get(ctx, types.NamespacedName{Name: caSecretName, Namespace: namespace}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      caSecretName,
+				Namespace: namespace,
+				Labels:    getManagedSecretLabels(),
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	case err != nil:
+		return nil, nil, err
+	default:
+		ca, err = parseKeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			log.Info("Replacing invalid webhook server CA", "secret", caSecretName, "error", err.Error())
+			ca = nil
+		}
+	}
+
+	previousCACert := secret.Data[previousCACertKey]
+	if ca == nil || expiresWithin(ca.cert, caRenewBefore) {
+		if ca != nil && !expiresWithin(ca.cert, 0) {
+			previousCACert = ca.certPEM
+		}
+		ca, err = generateCA()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(previousCACert) > 0 {
+		if previous, err := parseCert(previousCACert); err != nil || expiresWithin(previous, 0) {
+			previousCACert = nil
+		}
+	}
+
+	data := map[string][]byte{
+		corev1.TLSCertKey:       ca.certPEM,
+		corev1.TLSPrivateKeyKey: ca.keyPEM,
+	}
+	if len(previousCACert) > 0 {
+		data[previousCACertKey] = previousCACert
+	}
+	if err := syncSecretData(ctx, client, secret, data); err != nil {
+		return nil, nil, err
+	}
+
+	caBundle = append(append([]byte{}, ca.certPEM...), previousCACert...)
+	return ca, caBundle, nil
+}
+
+// syncServingCert ensures the serving certificate secret contains a valid certificate issued by ca for the webhook
+// server's service, along with the CA bundle. If secret is nil, it is created.
+func syncServingCert(ctx context.Context, client crclient.Client, secret *corev1.Secret, secretName, namespace string, ca *keyPair, caBundle []byte) error {
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+				Labels:    getManagedSecretLabels(),
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+
+	certPEM, keyPEM := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	if !isValidServingCert(certPEM, keyPEM, ca) {
+		servingCert, err := generateServingCert(ca, namespace)
+		if err != nil {
+			return err
+		}
+		log.Info("Issuing new webhook server serving certificate", "secret", secretName, "expires", servingCert.cert.NotAfter)
+		certPEM, keyPEM = servingCert.certPEM, servingCert.keyPEM
+	}
+
+	return syncSecretData(ctx, client, secret, map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+		caCertKey:               caBundle,
+	})
+}
+
+// isValidServingCert returns whether certPEM and keyPEM are a valid key pair, issued by ca, that is not due for renewal.
+func isValidServingCert(certPEM, keyPEM []byte, ca *keyPair) bool {
+	servingCert, err := parseKeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false
+	}
+	if servingCert.cert.CheckSignatureFrom(ca.cert) != nil {
+		return false
+	}
+	return !expiresWithin(servingCert.cert, servingCertRenewBefore)
+}
+
+// injectCABundle sets the CA bundle on all webhooks in the operator's webhook configurations. Webhook configurations
+// that do not exist yet are skipped, as the webhook server reads the CA bundle from its mounted secret when creating them.
+func injectCABundle(ctx context.Context, client crclient.Client, caBundle []byte) error {
+	mutatingCfg := &admregv1.MutatingWebhookConfiguration{}
+	err := client.Get(ctx, types.NamespacedName{Name: workspace.MutateWebhookCfgName}, mutatingCfg)
+	switch {
+	case apierrors.IsNotFound(err):
+		break
+	case err != nil:
+		return err
+	default:
+		needsUpdate := false
+		for idx := range mutatingCfg.Webhooks {
+			if !bytes.Equal(mutatingCfg.Webhooks[idx].ClientConfig.CABundle, caBundle) {
+				mutatingCfg.Webhooks[idx].ClientConfig.CABundle = caBundle
+				needsUpdate = true
+			}
+		}
+		if needsUpdate {
+			if err := client.Update(ctx, mutatingCfg); err != nil {
+				return err
+			}
+			log.Info("Updated CA bundle in mutating webhook configuration")
+		}
+	}
+
+	validatingCfg := &admregv1.ValidatingWebhookConfiguration{}
+	err = client.Get(ctx, types.NamespacedName{Name: workspace.ValidateWebhookCfgName}, validatingCfg)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	needsUpdate := false
+	for idx := range validatingCfg.Webhooks {
+		if !bytes.Equal(validatingCfg.Webhooks[idx].ClientConfig.CABundle, caBundle) {
+			validatingCfg.Webhooks[idx].ClientConfig.CABundle = caBundle
+			needsUpdate = true
+		}
+	}
+	if needsUpdate {
+		if err := client.Update(ctx, validatingCfg); err != nil {
+			return err
+		}
+		log.Info("Updated CA bundle in validating webhook configuration")
+	}
+	return nil
+}
+
+// syncSecretData creates secret with data if it does not exist on the cluster, or updates it if its data differs.
+func syncSecretData(ctx context.Context, client crclient.Client, secret *corev1.Secret, data map[string][]byte) error {
+	if secret.ResourceVersion == "" {
+		secret.Data = data
+		return client.Create(ctx, secret)
+	}
+	if len(secret.Data) == len(data) {
+		upToDate := true
+		for key, value := range data {
+			if !bytes.Equal(secret.Data[key], value) {
+				upToDate = false
+				break
+			}
+		}
+		if upToDate {
+			return nil
+		}
+	}
+	secret.Data = data
+	return client.Update(ctx, secret)
+}
+
+func getCASecretName(secretName string) string {
+	return secretName + "-ca"
+}
+
+func getManagedSecretLabels() map[string]string {
+	labels := server.WebhookServerAppLabels()
+	labels[constants.WebhookCertsManagedLabel] = "true"
+	return labels
+}
+
+// expiresWithin returns whether cert is no longer valid after the duration d.
+func expiresWithin(cert *x509.Certificate, d time.Duration) bool {
+	return now().Add(d).After(cert.NotAfter)
+}
+
+// keyPair is a parsed certificate and its private key, along with their PEM encodings.
+type keyPair struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func generateCA() (*keyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s-ca", server.WebhookServerAppName)},
+		NotBefore:             now().Add(-1 * time.Hour),
+		NotAfter:              now().Add(caValidity),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	return newKeyPair(template, nil)
+}
+
+func generateServingCert(ca *keyPair, namespace string) (*keyPair, error) {
+	serviceHost := fmt.Sprintf("%s.%s.svc", server.WebhookServerServiceName, namespace)
+	notAfter := now().Add(servingCertValidity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: serviceHost},
+		DNSNames:    []string{serviceHost, serviceHost + ".cluster.local"},
+		NotBefore:   now().Add(-1 * time.Hour),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return newKeyPair(template, ca)
+}
+
+// newKeyPair generates a new key and a certificate for it from template, signed by issuer. If issuer is nil, the
+// certificate is self-signed.
+func newKeyPair(template *x509.Certificate, issuer *keyPair) (*keyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	parentCert, parentKey := template, crypto.Signer(key)
+	if issuer != nil {
+		parentCert, parentKey = issuer.cert, issuer.key
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, parentCert, key.Public(), parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+func parseKeyPair(certPEM, keyPEM []byte) (*keyPair, error) {
+	tlsCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := tlsCert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot be used for signing")
+	}
+	cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	return &keyPair{cert: cert, key: key, certPEM: certPEM, keyPEM: keyPEM}, nil
+}
+
+func parseCert(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
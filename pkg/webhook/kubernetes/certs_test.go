@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package webhook_k8s
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admregv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/devfile/devworkspace-operator/webhook/workspace"
+)
+
+const (
+	testSecretName = "webhook-certs"
+	testNamespace  = "devworkspace-controller"
+)
+
+func getTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = admregv1.AddToScheme(scheme)
+	mutatingCfg := &admregv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: workspace.MutateWebhookCfgName},
+		Webhooks:   []admregv1.MutatingWebhook{{Name: "mutate.devfile.io"}, {Name: "mutate-ws-resources.devfile.io"}},
+	}
+	validatingCfg := &admregv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: workspace.ValidateWebhookCfgName},
+		Webhooks:   []admregv1.ValidatingWebhook{{Name: "validate-exec.devworkspace.devfile.io"}},
+	}
+	objs = append(objs, mutatingCfg, validatingCfg)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func getSecret(t *testing.T, c client.Client, name string) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: testNamespace}, secret))
+	return secret
+}
+
+// verifyServingCert checks that the serving certificate secret contains a certificate for the webhook server's service
+// that is trusted by the CA bundle in the secret and in the webhook configurations.
+func verifyServingCert(t *testing.T, c client.Client) *x509.Certificate {
+	secret := getSecret(t, c, testSecretName)
+	tlsCert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	require.NoError(t, err, "Serving certificate should be a valid key pair")
+	cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(secret.Data[caCertKey]))
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:     "devworkspace-webhookserver.devworkspace-controller.svc",
+		Roots:       roots,
+		CurrentTime: now(),
+	})
+	assert.NoError(t, err, "Serving certificate should be trusted by CA bundle")
+
+	mutatingCfg := &admregv1.MutatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: workspace.MutateWebhookCfgName}, mutatingCfg))
+	for _, webhook := range mutatingCfg.Webhooks {
+		assert.Equal(t, secret.Data[caCertKey], webhook.ClientConfig.CABundle, "Should inject CA bundle into mutating webhooks")
+	}
+	validatingCfg := &admregv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: workspace.ValidateWebhookCfgName}, validatingCfg))
+	for _, webhook := range validatingCfg.Webhooks {
+		assert.Equal(t, secret.Data[caCertKey], webhook.ClientConfig.CABundle, "Should inject CA bundle into validating webhooks")
+	}
+	return cert
+}
+
+func setNow(t *testing.T, currentTime time.Time) {
+	now = func() time.Time { return currentTime }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestSyncWebhookCertificatesGeneratesCertificates(t *testing.T) {
+	c := getTestClient()
+
+	err := SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace)
+	require.NoError(t, err)
+
+	verifyServingCert(t, c)
+	secret := getSecret(t, c, testSecretName)
+	assert.Equal(t, "true", secret.Labels["controller.devfile.io/webhook-certs-managed"])
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	caSecret := getSecret(t, c, getCASecretName(testSecretName))
+	assert.NotContains(t, caSecret.Data, previousCACertKey)
+
+	// Syncing again should not change anything
+	err = SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, secret.ResourceVersion, getSecret(t, c, testSecretName).ResourceVersion)
+	assert.Equal(t, caSecret.ResourceVersion, getSecret(t, c, getCASecretName(testSecretName)).ResourceVersion)
+}
+
+func TestSyncWebhookCertificatesRenewsServingCert(t *testing.T) {
+	c := getTestClient()
+	require.NoError(t, SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace))
+	oldCert := verifyServingCert(t, c)
+	oldCASecret := getSecret(t, c, getCASecretName(testSecretName))
+
+	setNow(t, oldCert.NotAfter.Add(-servingCertRenewBefore).Add(time.Hour))
+	require.NoError(t, SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace))
+
+	newCert := verifyServingCert(t, c)
+	assert.NotEqual(t, oldCert.SerialNumber, newCert.SerialNumber, "Should issue new serving certificate")
+	assert.Equal(t, oldCASecret.Data, getSecret(t, c, getCASecretName(testSecretName)).Data, "Should not rotate CA")
+}
+
+func TestSyncWebhookCertificatesRotatesCA(t *testing.T) {
+	c := getTestClient()
+	require.NoError(t, SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace))
+	oldCASecret := getSecret(t, c, getCASecretName(testSecretName))
+	oldCA, err := parseCert(oldCASecret.Data[corev1.TLSCertKey])
+	require.NoError(t, err)
+
+	setNow(t, oldCA.NotAfter.Add(-caRenewBefore).Add(time.Hour))
+	require.NoError(t, SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace))
+
+	caSecret := getSecret(t, c, getCASecretName(testSecretName))
+	assert.NotEqual(t, oldCASecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSCertKey], "Should generate new CA")
+	assert.Equal(t, oldCASecret.Data[corev1.TLSCertKey], caSecret.Data[previousCACertKey], "Should keep previous CA")
+	verifyServingCert(t, c)
+	assert.Contains(t, string(getSecret(t, c, testSecretName).Data[caCertKey]), string(oldCASecret.Data[corev1.TLSCertKey]),
+		"CA bundle should include previous CA until it expires")
+
+	setNow(t, oldCA.NotAfter.Add(time.Hour))
+	require.NoError(t, SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace))
+	assert.NotContains(t, getSecret(t, c, getCASecretName(testSecretName)).Data, previousCACertKey, "Should drop expired CA")
+	assert.Equal(t, caSecret.Data[corev1.TLSCertKey], getSecret(t, c, testSecretName).Data[caCertKey])
+	verifyServingCert(t, c)
+}
+
+func TestSyncWebhookCertificatesIgnoresExternallyManagedSecret(t *testing.T) {
+	externalSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testSecretName,
+			Namespace: testNamespace,
+			Annotations: map[string]string{
+				"cert-manager.io/certificate-name": "serving-cert",
+			},
+		},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("external-cert"),
+			corev1.TLSPrivateKeyKey: []byte("external-key"),
+		},
+	}
+	c := getTestClient(externalSecret)
+
+	err := SyncWebhookCertificates(context.Background(), c, testSecretName, testNamespace)
+	require.NoError(t, err)
+
+	assert.Equal(t, externalSecret.Data, getSecret(t, c, testSecretName).Data, "Should not modify externally managed secret")
+	err = c.Get(context.Background(), types.NamespacedName{Name: getCASecretName(testSecretName), Namespace: testNamespace}, &corev1.Secret{})
+	assert.Error(t, err, "Should not create CA secret")
+}
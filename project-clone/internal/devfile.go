@@ -30,6 +30,11 @@ import (
 const (
 	ProjectSparseCheckout = "sparseCheckout"
 	ProjectSubDir         = "subDir"
+	// ProjectLFS is a boolean project attribute that enables fetching Git LFS objects after a project is cloned.
+	ProjectLFS = "lfs"
+	// ProjectSubmodules is a boolean project attribute that controls whether submodules are initialized after a
+	// project is cloned. If unset, submodules are initialized on a best-effort basis.
+	ProjectSubmodules = "submodules"
 )
 
 // ReadFlattenedDevWorkspace reads the flattened DevWorkspaceTemplateSpec from disk. The location of the flattened
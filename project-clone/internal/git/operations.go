@@ -120,11 +120,18 @@ func SetupRemotes(repo *git.Repository, project *dw.Project, projectPath string)
 	return nil
 }
 
+// SetupSubmodules initializes submodules in the project, unless disabled via the ProjectSubmodules attribute
 func SetupSubmodules(project *dw.Project, projectPath string) error {
-	if _, err := os.Stat(path.Join(projectPath, ".gitmodules")); os.IsNotExist(err) {
+	if !hasSubmodules(projectPath) {
 		// No submodules; do nothing
 		return nil
 	}
+	if enabled, isSet, err := getBooleanAttribute(project, internal.ProjectSubmodules); err != nil {
+		return err
+	} else if isSet && !enabled {
+		log.Printf("Skipping submodule initialization for project %s", project.Name)
+		return nil
+	}
 	log.Printf("Initializing submodules for project %s", project.Name)
 	if err := shell.GitInitSubmodules(projectPath); err != nil {
 		return fmt.Errorf("git submodule update --init --recursive failed: %s", err)
@@ -132,6 +139,49 @@ func SetupSubmodules(project *dw.Project, projectPath string) error {
 	return nil
 }
 
+// SetupLFS fetches Git LFS objects for the project and its submodules if enabled via the ProjectLFS attribute
+func SetupLFS(project *dw.Project, projectPath string) error {
+	enabled, _, err := getBooleanAttribute(project, internal.ProjectLFS)
+	if err != nil || !enabled {
+		return err
+	}
+	log.Printf("Fetching Git LFS objects for project %s", project.Name)
+	if err := shell.GitLFSInstall(projectPath); err != nil {
+		return fmt.Errorf("git lfs install failed: %s", err)
+	}
+	if err := shell.GitLFSPull(projectPath); err != nil {
+		return fmt.Errorf("git lfs pull failed: %s", err)
+	}
+	if !hasSubmodules(projectPath) {
+		return nil
+	}
+	if submodulesEnabled, isSet, _ := getBooleanAttribute(project, internal.ProjectSubmodules); isSet && !submodulesEnabled {
+		return nil
+	}
+	if err := shell.GitLFSPullSubmodules(projectPath); err != nil {
+		return fmt.Errorf("git lfs pull failed for submodules: %s", err)
+	}
+	return nil
+}
+
+func hasSubmodules(projectPath string) bool {
+	_, err := os.Stat(path.Join(projectPath, ".gitmodules"))
+	return err == nil
+}
+
+// getBooleanAttribute reads a boolean attribute from a project. Returns whether the attribute is set, and an error if
+// it is set but is not a boolean.
+func getBooleanAttribute(project *dw.Project, attribute string) (value, isSet bool, err error) {
+	if !project.Attributes.Exists(attribute) {
+		return false, false, nil
+	}
+	value = project.Attributes.GetBoolean(attribute, &err)
+	if err != nil {
+		return false, true, fmt.Errorf("failed to read %s attribute: %w", attribute, err)
+	}
+	return value, true, nil
+}
+
 // CheckoutReference sets the current HEAD in repo to point at the revision and remote referenced by checkoutFrom
 func CheckoutReference(project *dw.Project, projectPath string) error {
 	checkoutFrom := project.Git.CheckoutFrom
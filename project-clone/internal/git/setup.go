@@ -73,9 +73,16 @@ func doInitialGitClone(project *dw.Project) error {
 	}
 
 	if err := SetupSubmodules(project, tmpClonePath); err != nil {
+		if project.Attributes.Exists(internal.ProjectSubmodules) {
+			return fmt.Errorf("failed to set up submodules: %w", err)
+		}
 		log.Printf("Failed to set up submodules in project: %s", err)
 	}
 
+	if err := SetupLFS(project, tmpClonePath); err != nil {
+		return fmt.Errorf("failed to fetch Git LFS objects: %w", err)
+	}
+
 	if err := copyProjectFromTmpDir(project, tmpClonePath); err != nil {
 		return err
 	}
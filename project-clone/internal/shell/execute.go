@@ -103,6 +103,18 @@ func GitInitSubmodules(projectPath string) error {
 	return executeCommand("git", "-C", projectPath, "submodule", "update", "--init", "--recursive")
 }
 
+func GitLFSInstall(projectPath string) error {
+	return executeCommand("git", "-C", projectPath, "lfs", "install", "--local")
+}
+
+func GitLFSPull(projectPath string) error {
+	return executeCommand("git", "-C", projectPath, "lfs", "pull")
+}
+
+func GitLFSPullSubmodules(projectPath string) error {
+	return executeCommand("git", "-C", projectPath, "submodule", "foreach", "--recursive", "git lfs install --local && git lfs pull")
+}
+
 func executeCommand(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Stderr = log.Writer()
@@ -17,12 +17,14 @@ package main
 
 import (
 	"crypto/tls"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"path"
+	"strings"
 	"syscall"
 
 	projectslib "github.com/devfile/devworkspace-operator/pkg/library/projects"
@@ -37,6 +39,9 @@ import (
 const (
 	logFileName    = "project-clone-errors.log"
 	tmpLogFilePath = "/tmp/" + logFileName
+	// terminationMessagePath is the default path from which Kubernetes reads a container's termination message. The
+	// DevWorkspace Operator reads the termination message to report project clone errors in the DevWorkspace's status
+	terminationMessagePath = "/dev/termination-log"
 )
 
 func main() {
@@ -95,7 +100,7 @@ func main() {
 		gitclient.InstallProtocol("https", githttp.NewClient(httpClient))
 	}
 
-	encounteredError := false
+	var projectErrors []string
 	for _, project := range projects {
 		log.Printf("Processing project %s", project.Name)
 		var err error
@@ -111,11 +116,12 @@ func main() {
 		}
 		if err != nil {
 			log.Printf("Encountered error while setting up project %s: %s", project.Name, err)
-			encounteredError = true
+			projectErrors = append(projectErrors, fmt.Sprintf("%s: %s", project.Name, err))
 		}
 	}
-	if encounteredError {
+	if len(projectErrors) > 0 {
 		copyLogFileToProjectsRoot()
+		writeTerminationMessage(strings.Join(projectErrors, "; "))
 		os.Exit(0)
 	}
 
@@ -151,3 +157,11 @@ func copyLogFileToProjectsRoot() {
 		log.Printf("Failed to copy log file to $PROJECTS_ROOT: %s", err)
 	}
 }
+
+// writeTerminationMessage writes a summary of errors encountered while setting up projects to the container's
+// termination message. Errors are not fatal, as the workspace should start even if some projects could not be set up.
+func writeTerminationMessage(message string) {
+	if err := os.WriteFile(terminationMessagePath, []byte(message), 0644); err != nil {
+		log.Printf("Failed to write termination message: %s", err)
+	}
+}
@@ -85,7 +85,12 @@ func ConfigureWebhookServer(mgr manager.Manager) error {
 		return nil
 	}
 
-	CABundle, err = os.ReadFile(WebhookServerCertDir + "/tls.crt")
+	// Prefer the CA bundle if it is provided along with the serving certificate, e.g. when certificates are generated by
+	// the operator, and otherwise assume the serving certificate is self-signed
+	CABundle, err = os.ReadFile(WebhookServerCertDir + "/ca.crt")
+	if os.IsNotExist(err) {
+		CABundle, err = os.ReadFile(WebhookServerCertDir + "/tls.crt")
+	}
 	if os.IsNotExist(err) {
 		return errors.New("CA certificate is not found. Unable to setup webhook server")
 	}
@@ -0,0 +1,54 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/attributeschema"
+)
+
+// validateAttributeSchemas returns an error if attributes in a DevWorkspace's template do not match the schemas
+// registered for them in the operator's namespace. On updates (oldWksp is not nil), only attributes that are changed
+// by the update are validated.
+func (h *WebhookHandler) validateAttributeSchemas(ctx context.Context, newWksp, oldWksp *dwv2.DevWorkspace) error {
+	if len(newWksp.Spec.Template.Attributes) == 0 {
+		return nil
+	}
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return err
+	}
+	schemas, invalid, err := attributeschema.LoadSchemas(ctx, h.APIReader, namespace)
+	if err != nil {
+		return err
+	}
+	for _, invalidErr := range invalid {
+		log.Info("Ignoring invalid attribute schema", "error", invalidErr.Error())
+	}
+	var oldAttributes attributes.Attributes
+	if oldWksp != nil {
+		oldAttributes = oldWksp.Spec.Template.Attributes
+		if oldAttributes == nil {
+			oldAttributes = attributes.Attributes{}
+		}
+	}
+	return attributeschema.ValidateAttributes(newWksp.Spec.Template.Attributes, schemas, oldAttributes)
+}
@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/library/access"
+)
+
+// checkCreatorAccess checks whether a user may access or modify a DevWorkspace object, such as its pod or deployment.
+// Access to restricted-access DevWorkspaces is limited to the DevWorkspace's creator, as recorded in the creator label
+// when the DevWorkspace is created, members of the admin groups configured in the global DevWorkspaceOperatorConfig,
+// and the DevWorkspace Operator itself. A DevWorkspace is restricted-access if it has the restricted-access annotation,
+// or if config.workspace.accessControl.restrictToCreator is enabled.
+//
+// Requests reach the webhook only after they were authorized by the API server, so admin group membership only lifts
+// the restriction to the creator: admins still need RBAC permissions for the request, e.g. to exec into pods in the
+// DevWorkspace's namespace.
+func (h *WebhookHandler) checkCreatorAccess(ctx context.Context, obj metav1.Object, userInfo authenticationv1.UserInfo) (bool, error) {
+	if userInfo.UID == h.ControllerUID {
+		return true, nil
+	}
+	if creator := obj.GetLabels()[constants.DevWorkspaceCreatorLabel]; creator != "" && creator == userInfo.UID {
+		return true, nil
+	}
+	accessControl, err := h.getAccessControlConfig(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !access.IsRestrictedAccess(obj, accessControl) {
+		return true, nil
+	}
+	return access.IsAdmin(userInfo.Groups, accessControl), nil
+}
+
+// getAccessControlConfig reads the access control configuration from the global DevWorkspaceOperatorConfig.
+func (h *WebhookHandler) getAccessControlConfig(ctx context.Context) (*controller.AccessControlConfig, error) {
+	globalConfig, err := h.getGlobalOperatorConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if globalConfig == nil || globalConfig.Workspace == nil {
+		return nil, nil
+	}
+	return globalConfig.Workspace.AccessControl, nil
+}
@@ -0,0 +1,322 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+const (
+	testCreatorUID    = "creator-uid"
+	testControllerUID = "controller-uid"
+	testAdminGroup    = "workspace-admins"
+)
+
+var (
+	creatorUser    = authenticationv1.UserInfo{Username: "creator", UID: testCreatorUID}
+	adminUser      = authenticationv1.UserInfo{Username: "admin", UID: "admin-uid", Groups: []string{"system:authenticated", testAdminGroup}}
+	otherUser      = authenticationv1.UserInfo{Username: "other", UID: "other-uid", Groups: []string{"system:authenticated"}}
+	controllerUser = authenticationv1.UserInfo{Username: "system:serviceaccount:devworkspace-controller:devworkspace-controller-serviceaccount", UID: testControllerUID}
+)
+
+func getAccessControlTestHandler(t *testing.T, accessControl *controller.AccessControlConfig, objs ...client.Object) *WebhookHandler {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	decoder, err := admission.NewDecoder(testutil.Scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dwoc := &controller.DevWorkspaceOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.OperatorConfigName,
+			Namespace: testNamespace,
+		},
+		Config: &controller.OperatorConfiguration{
+			Workspace: &controller.WorkspaceConfig{
+				AccessControl: accessControl,
+			},
+		},
+	}
+	objs = append(objs, dwoc)
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &WebhookHandler{
+		ControllerUID: testControllerUID,
+		Client:        fakeClient,
+		APIReader:     fakeClient,
+		Decoder:       decoder,
+	}
+}
+
+func getWorkspaceObjectMeta(name string, restricted bool) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: testNamespace,
+		Labels: map[string]string{
+			constants.DevWorkspaceIDLabel:      "workspace-id",
+			constants.DevWorkspaceCreatorLabel: testCreatorUID,
+		},
+	}
+	if restricted {
+		meta.Annotations = map[string]string{constants.DevWorkspaceRestrictedAccessAnnotation: "true"}
+	}
+	return meta
+}
+
+func getUpdateRequest(t *testing.T, kind metav1.GroupVersionKind, userInfo authenticationv1.UserInfo, oldObj, newObj runtime.Object) admission.Request {
+	oldRaw, err := json.Marshal(oldObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newRaw, err := json.Marshal(newObj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      kind,
+			Operation: admissionv1.Update,
+			Name:      oldObj.(metav1.Object).GetName(),
+			Namespace: testNamespace,
+			UserInfo:  userInfo,
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+			Object:    runtime.RawExtension{Raw: newRaw},
+		},
+	}
+}
+
+func TestCheckCreatorAccess(t *testing.T) {
+	tests := []struct {
+		name          string
+		accessControl *controller.AccessControlConfig
+		restricted    bool
+		userInfo      authenticationv1.UserInfo
+		allowed       bool
+	}{
+		{name: "Creator can access restricted-access workspace", restricted: true, userInfo: creatorUser, allowed: true},
+		{name: "Controller can access restricted-access workspace", restricted: true, userInfo: controllerUser, allowed: true},
+		{name: "Other user cannot access restricted-access workspace", restricted: true, userInfo: otherUser, allowed: false},
+		{name: "Other user can access workspace without restricted access", restricted: false, userInfo: otherUser, allowed: true},
+		{
+			name:          "Admin group can access restricted-access workspace",
+			accessControl: &controller.AccessControlConfig{AdminGroups: []string{testAdminGroup}},
+			restricted:    true,
+			userInfo:      adminUser,
+			allowed:       true,
+		},
+		{
+			name:          "Admin group membership is required",
+			accessControl: &controller.AccessControlConfig{AdminGroups: []string{testAdminGroup}},
+			restricted:    true,
+			userInfo:      otherUser,
+			allowed:       false,
+		},
+		{
+			name:          "All workspaces are restricted when restrictToCreator is enabled",
+			accessControl: &controller.AccessControlConfig{RestrictToCreator: pointer.Bool(true)},
+			restricted:    false,
+			userInfo:      otherUser,
+			allowed:       false,
+		},
+		{
+			name:          "Admin group can access workspaces when restrictToCreator is enabled",
+			accessControl: &controller.AccessControlConfig{RestrictToCreator: pointer.Bool(true), AdminGroups: []string{testAdminGroup}},
+			restricted:    false,
+			userInfo:      adminUser,
+			allowed:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := getAccessControlTestHandler(t, tt.accessControl)
+			pod := &corev1.Pod{ObjectMeta: getWorkspaceObjectMeta("workspace-pod", tt.restricted)}
+			allowed, err := h.checkCreatorAccess(context.Background(), pod, tt.userInfo)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.allowed, allowed)
+			}
+		})
+	}
+}
+
+func TestExecAndAttachAreRestrictedToCreator(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: getWorkspaceObjectMeta("workspace-pod", false)}
+	h := getAccessControlTestHandler(t, &controller.AccessControlConfig{
+		RestrictToCreator: pointer.Bool(true),
+		AdminGroups:       []string{testAdminGroup},
+	}, pod)
+
+	getConnectRequest := func(kind metav1.GroupVersionKind, userInfo authenticationv1.UserInfo) admission.Request {
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Kind:      kind,
+				Operation: admissionv1.Connect,
+				Name:      pod.Name,
+				Namespace: testNamespace,
+				UserInfo:  userInfo,
+			},
+		}
+	}
+
+	resp := h.ValidateExecOnConnect(context.Background(), getConnectRequest(V1PodExecOptionKind, otherUser))
+	assert.False(t, resp.Allowed, "Should deny exec by other users")
+	assert.Equal(t, "The only devworkspace creator has exec access", string(resp.Result.Reason))
+	resp = h.ValidateExecOnConnect(context.Background(), getConnectRequest(V1PodAttachOptionKind, otherUser))
+	assert.False(t, resp.Allowed, "Should deny attach by other users")
+	assert.Equal(t, "The only devworkspace creator has attach access", string(resp.Result.Reason))
+
+	for _, userInfo := range []authenticationv1.UserInfo{creatorUser, adminUser, controllerUser} {
+		resp = h.ValidateExecOnConnect(context.Background(), getConnectRequest(V1PodExecOptionKind, userInfo))
+		assert.True(t, resp.Allowed, "Should allow exec by %s", userInfo.Username)
+		resp = h.ValidateExecOnConnect(context.Background(), getConnectRequest(V1PodAttachOptionKind, userInfo))
+		assert.True(t, resp.Allowed, "Should allow attach by %s", userInfo.Username)
+	}
+}
+
+func TestScalingDeploymentIsRestrictedToCreator(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: getWorkspaceObjectMeta("workspace-deployment", true)}
+	otherDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other-deployment", Namespace: testNamespace}}
+	h := getAccessControlTestHandler(t, &controller.AccessControlConfig{AdminGroups: []string{testAdminGroup}}, deployment, otherDeployment)
+
+	getScale := func(name string, replicas int32) *autoscalingv1.Scale {
+		return &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+		}
+	}
+
+	resp := h.ValidateScaleOnUpdate(context.Background(),
+		getUpdateRequest(t, AutoscalingV1ScaleKind, otherUser, getScale(deployment.Name, 1), getScale(deployment.Name, 0)))
+	assert.False(t, resp.Allowed, "Should deny scaling by other users")
+	assert.Equal(t, scaleDeniedMessage, string(resp.Result.Reason))
+
+	for _, userInfo := range []authenticationv1.UserInfo{creatorUser, adminUser, controllerUser} {
+		resp = h.ValidateScaleOnUpdate(context.Background(),
+			getUpdateRequest(t, AutoscalingV1ScaleKind, userInfo, getScale(deployment.Name, 1), getScale(deployment.Name, 0)))
+		assert.True(t, resp.Allowed, "Should allow scaling by %s", userInfo.Username)
+	}
+
+	resp = h.ValidateScaleOnUpdate(context.Background(),
+		getUpdateRequest(t, AutoscalingV1ScaleKind, otherUser, getScale(otherDeployment.Name, 1), getScale(otherDeployment.Name, 0)))
+	assert.True(t, resp.Allowed, "Should allow scaling deployments that do not belong to a DevWorkspace")
+}
+
+func TestScalingStatefulSetIsRestrictedToCreator(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: getWorkspaceObjectMeta("workspace-statefulset", true)}
+	otherStatefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "other-statefulset", Namespace: testNamespace}}
+	h := getAccessControlTestHandler(t, &controller.AccessControlConfig{AdminGroups: []string{testAdminGroup}}, statefulSet, otherStatefulSet)
+
+	getScaleRequest := func(name string, userInfo authenticationv1.UserInfo) admission.Request {
+		oldScale := &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: 1},
+		}
+		newScale := oldScale.DeepCopy()
+		newScale.Spec.Replicas = 0
+		req := getUpdateRequest(t, AutoscalingV1ScaleKind, userInfo, oldScale, newScale)
+		req.Resource = metav1.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+		req.SubResource = "scale"
+		return req
+	}
+
+	resp := h.ValidateScaleOnUpdate(context.Background(), getScaleRequest(statefulSet.Name, otherUser))
+	assert.False(t, resp.Allowed, "Should deny scaling by other users")
+	assert.Equal(t, scaleDeniedMessage, string(resp.Result.Reason))
+
+	for _, userInfo := range []authenticationv1.UserInfo{creatorUser, adminUser, controllerUser} {
+		resp = h.ValidateScaleOnUpdate(context.Background(), getScaleRequest(statefulSet.Name, userInfo))
+		assert.True(t, resp.Allowed, "Should allow scaling by %s", userInfo.Username)
+	}
+
+	resp = h.ValidateScaleOnUpdate(context.Background(), getScaleRequest(otherStatefulSet.Name, otherUser))
+	assert.True(t, resp.Allowed, "Should allow scaling statefulsets that do not belong to a DevWorkspace")
+}
+
+func TestUpdatingDeploymentReplicasIsRestrictedToCreator(t *testing.T) {
+	h := getAccessControlTestHandler(t, &controller.AccessControlConfig{RestrictToCreator: pointer.Bool(true)})
+	oldDeployment := &appsv1.Deployment{
+		ObjectMeta: getWorkspaceObjectMeta("workspace-deployment", false),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32(1),
+			Template: corev1.PodTemplateSpec{ObjectMeta: getWorkspaceObjectMeta("", false)},
+		},
+	}
+	newDeployment := oldDeployment.DeepCopy()
+	newDeployment.Spec.Replicas = pointer.Int32(0)
+
+	resp := h.MutateDeploymentOnUpdate(context.Background(), getUpdateRequest(t, AppsV1DeploymentKind, otherUser, oldDeployment, newDeployment))
+	assert.False(t, resp.Allowed, "Should deny changing replicas by other users")
+	assert.Equal(t, scaleDeniedMessage, string(resp.Result.Reason))
+
+	resp = h.MutateDeploymentOnUpdate(context.Background(), getUpdateRequest(t, AppsV1DeploymentKind, creatorUser, oldDeployment, newDeployment))
+	assert.True(t, resp.Allowed, "Should allow changing replicas by creator")
+}
+
+func TestUpdatingStatefulSetReplicasIsRestrictedToCreator(t *testing.T) {
+	h := getAccessControlTestHandler(t, &controller.AccessControlConfig{RestrictToCreator: pointer.Bool(true)})
+	oldStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: getWorkspaceObjectMeta("workspace-statefulset", false),
+		Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32(1)},
+	}
+	newStatefulSet := oldStatefulSet.DeepCopy()
+	newStatefulSet.Spec.Replicas = pointer.Int32(0)
+
+	resp := h.ValidateStatefulSetOnUpdate(context.Background(), getUpdateRequest(t, AppsV1StatefulSetKind, otherUser, oldStatefulSet, newStatefulSet))
+	assert.False(t, resp.Allowed, "Should deny changing replicas by other users")
+	assert.Equal(t, scaleDeniedMessage, string(resp.Result.Reason))
+
+	resp = h.ValidateStatefulSetOnUpdate(context.Background(), getUpdateRequest(t, AppsV1StatefulSetKind, creatorUser, oldStatefulSet, newStatefulSet))
+	assert.True(t, resp.Allowed, "Should allow changing replicas by creator")
+
+	resp = h.ValidateStatefulSetOnUpdate(context.Background(), getUpdateRequest(t, AppsV1StatefulSetKind, otherUser, oldStatefulSet, oldStatefulSet.DeepCopy()))
+	assert.True(t, resp.Allowed, "Should allow updates that do not change replicas")
+}
+
+func TestUpdatingPodImagesIsRestrictedToCreator(t *testing.T) {
+	h := getAccessControlTestHandler(t, &controller.AccessControlConfig{
+		RestrictToCreator: pointer.Bool(true),
+		AdminGroups:       []string{testAdminGroup},
+	})
+	oldPod := &corev1.Pod{
+		ObjectMeta: getWorkspaceObjectMeta("workspace-pod", false),
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "tools", Image: "quay.io/devfile/universal-developer-image:latest"}}},
+	}
+	newPod := oldPod.DeepCopy()
+	newPod.Spec.Containers[0].Image = "quay.io/example/image:latest"
+
+	resp := h.MutatePodOnUpdate(context.Background(), getUpdateRequest(t, V1PodKind, otherUser, oldPod, newPod))
+	assert.False(t, resp.Allowed, "Should deny changing images by other users")
+
+	resp = h.MutatePodOnUpdate(context.Background(), getUpdateRequest(t, V1PodKind, adminUser, oldPod, newPod))
+	assert.True(t, resp.Allowed, "Should allow changing images by admin group members")
+}
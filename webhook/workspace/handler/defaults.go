@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+
+	maputils "github.com/devfile/devworkspace-operator/internal/map"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// injectDefaults sets the routingClass, storage type attribute and idle timeout annotation of a new DevWorkspace to the
+// defaults from the global DevWorkspaceOperatorConfig, if they are not already set, so that the configuration used for
+// the DevWorkspace is visible on the object. DevWorkspaces that use an external DevWorkspaceOperatorConfig are not
+// modified, as their defaults are resolved by the controller.
+func (h *WebhookHandler) injectDefaults(ctx context.Context, wksp *dwv2.DevWorkspace) error {
+	if wksp.Spec.Template.Attributes.Exists(constants.ExternalDevWorkspaceConfiguration) {
+		return nil
+	}
+	globalConfig, err := h.getGlobalOperatorConfig(ctx)
+	if err != nil {
+		return err
+	}
+	defaults := config.MergeWithDefaultConfig(globalConfig)
+
+	if wksp.Spec.RoutingClass == "" {
+		wksp.Spec.RoutingClass = defaults.Routing.DefaultRoutingClass
+	}
+	if !wksp.Spec.Template.Attributes.Exists(constants.DevWorkspaceStorageTypeAttribute) {
+		storageType := defaults.Workspace.DefaultStorageType
+		if storageType == "" {
+			storageType = constants.PerUserStorageClassType
+		}
+		if wksp.Spec.Template.Attributes == nil {
+			wksp.Spec.Template.Attributes = attributes.Attributes{}
+		}
+		wksp.Spec.Template.Attributes.PutString(constants.DevWorkspaceStorageTypeAttribute, storageType)
+	}
+	if _, ok := wksp.Annotations[constants.DevWorkspaceIdleTimeoutAnnotation]; !ok {
+		wksp.Annotations = maputils.Append(wksp.Annotations, constants.DevWorkspaceIdleTimeoutAnnotation, defaults.Workspace.IdleTimeout)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+type defaultsTestCase struct {
+	Name   string             `json:"name,omitempty"`
+	Input  defaultsTestInput  `json:"input,omitempty"`
+	Output defaultsTestOutput `json:"output,omitempty"`
+}
+
+type defaultsTestInput struct {
+	Config    *controller.OperatorConfiguration `json:"config,omitempty"`
+	Workspace *dwv2.DevWorkspace                `json:"workspace,omitempty"`
+}
+
+type defaultsTestOutput struct {
+	RoutingClass string  `json:"routingClass,omitempty"`
+	StorageType  *string `json:"storageType,omitempty"`
+	IdleTimeout  *string `json:"idleTimeout,omitempty"`
+}
+
+func loadDefaultsTestCaseOrPanic(t *testing.T, testFilepath string) defaultsTestCase {
+	bytes, err := os.ReadFile(testFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test defaultsTestCase
+	if err := yaml.Unmarshal(bytes, &test); err != nil {
+		t.Fatal(err)
+	}
+	return test
+}
+
+func loadAllDefaultsTestCasesOrPanic(t *testing.T, fromDir string) []defaultsTestCase {
+	files, err := os.ReadDir(fromDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []defaultsTestCase
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		tests = append(tests, loadDefaultsTestCaseOrPanic(t, filepath.Join(fromDir, file.Name())))
+	}
+	return tests
+}
+
+func getDefaultsTestHandler(globalConfig *controller.OperatorConfiguration) *WebhookHandler {
+	var objs []client.Object
+	if globalConfig != nil {
+		objs = append(objs, &controller.DevWorkspaceOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      config.OperatorConfigName,
+				Namespace: testNamespace,
+			},
+			Config: globalConfig,
+		})
+	}
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &WebhookHandler{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+	}
+}
+
+func TestInjectDefaults(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	tests := loadAllDefaultsTestCasesOrPanic(t, "testdata/defaults")
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// sanity check that file is read correctly.
+			require.NotNil(t, tt.Input.Workspace, "Input does not define a workspace")
+			handler := getDefaultsTestHandler(tt.Input.Config)
+			wksp := tt.Input.Workspace
+
+			require.NoError(t, handler.injectDefaults(context.Background(), wksp))
+			assert.Equal(t, tt.Output.RoutingClass, wksp.Spec.RoutingClass, "RoutingClass should match")
+			if tt.Output.StorageType != nil {
+				assert.Equal(t, *tt.Output.StorageType, wksp.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil), "Storage type should match")
+			} else {
+				assert.False(t, wksp.Spec.Template.Attributes.Exists(constants.DevWorkspaceStorageTypeAttribute), "Storage type should not be set")
+			}
+			if tt.Output.IdleTimeout != nil {
+				assert.Equal(t, *tt.Output.IdleTimeout, wksp.Annotations[constants.DevWorkspaceIdleTimeoutAnnotation], "Idle timeout should match")
+			} else {
+				assert.NotContains(t, wksp.Annotations, constants.DevWorkspaceIdleTimeoutAnnotation, "Idle timeout should not be set")
+			}
+		})
+	}
+}
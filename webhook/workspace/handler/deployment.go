@@ -20,6 +20,7 @@ import (
 	"net/http"
 
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -44,7 +45,7 @@ func (h *WebhookHandler) MutateDeploymentOnCreate(_ context.Context, req admissi
 	return admission.Allowed("The deployment is valid")
 }
 
-func (h *WebhookHandler) MutateDeploymentOnUpdate(_ context.Context, req admission.Request) admission.Response {
+func (h *WebhookHandler) MutateDeploymentOnUpdate(ctx context.Context, req admission.Request) admission.Response {
 	oldD := &appsv1.Deployment{}
 	newD := &appsv1.Deployment{}
 
@@ -58,6 +59,16 @@ func (h *WebhookHandler) MutateDeploymentOnUpdate(_ context.Context, req admissi
 		return admission.Denied(msg)
 	}
 
+	if !equality.Semantic.DeepEqual(oldD.Spec.Replicas, newD.Spec.Replicas) {
+		allowed, err := h.checkCreatorAccess(ctx, oldD, req.UserInfo)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if !allowed {
+			return admission.Denied(scaleDeniedMessage)
+		}
+	}
+
 	patchedMeta, err := h.mutateMetadataOnUpdate(&oldD.ObjectMeta, &newD.ObjectMeta)
 	if err != nil {
 		return admission.Denied(".metadata validation failed: " + err.Error())
@@ -17,6 +17,7 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/devfile/devworkspace-operator/pkg/constants"
@@ -28,8 +29,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-var V1PodExecOptionKind = metav1.GroupVersionKind{Kind: "PodExecOptions", Group: "", Version: "v1"}
+var (
+	V1PodExecOptionKind   = metav1.GroupVersionKind{Kind: "PodExecOptions", Group: "", Version: "v1"}
+	V1PodAttachOptionKind = metav1.GroupVersionKind{Kind: "PodAttachOptions", Group: "", Version: "v1"}
+)
 
+// ValidateExecOnConnect validates pods/exec and pods/attach requests for DevWorkspace pods. Access to restricted-access
+// DevWorkspace pods is only permitted for the creator of the DevWorkspace and configured admin groups.
 func (h *WebhookHandler) ValidateExecOnConnect(ctx context.Context, req admission.Request) admission.Response {
 	p := corev1.Pod{}
 	err := h.Client.Get(ctx, types.NamespacedName{
@@ -49,14 +55,21 @@ func (h *WebhookHandler) ValidateExecOnConnect(ctx context.Context, req admissio
 		return admission.Allowed("Not a devworkspace related pod")
 	}
 
-	creator, ok := p.Labels[constants.DevWorkspaceCreatorLabel]
+	_, ok = p.Labels[constants.DevWorkspaceCreatorLabel]
 	if !ok {
 		return admission.Denied("The workspace info is missing in the devworkspace-related pod")
 	}
 
-	if p.Annotations[constants.DevWorkspaceRestrictedAccessAnnotation] == "true" &&
-		creator != req.UserInfo.UID {
-		return admission.Denied("The only devworkspace creator has exec access")
+	allowed, err := h.checkCreatorAccess(ctx, &p, req.UserInfo)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !allowed {
+		operation := "exec"
+		if req.Kind == V1PodAttachOptionKind {
+			operation = "attach"
+		}
+		return admission.Denied(fmt.Sprintf("The only devworkspace creator has %s access", operation))
 	}
 
 	return admission.Allowed("The current user and devworkspace are matched")
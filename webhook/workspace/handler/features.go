@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+	"github.com/devfile/devworkspace-operator/pkg/library/restrictions"
+)
+
+// validateDisabledFeaturesOnCreate returns an error if a DevWorkspace uses devfile features that are disabled in the
+// global DevWorkspaceOperatorConfig.
+func (h *WebhookHandler) validateDisabledFeaturesOnCreate(ctx context.Context, wksp *dwv2.DevWorkspace) error {
+	disabledFeatures, err := h.getDisabledDevfileFeatures(ctx)
+	if err != nil {
+		return err
+	}
+	return restrictions.CheckDisabledFeatures(&wksp.Spec.Template, disabledFeatures)
+}
+
+// validateDisabledFeaturesOnUpdate returns an error if an update adds uses of devfile features that are disabled in the
+// global DevWorkspaceOperatorConfig. Uses of disabled features that are already present in the old DevWorkspace are
+// allowed, so that existing DevWorkspaces can still be updated (e.g. stopped) after a feature is disabled.
+func (h *WebhookHandler) validateDisabledFeaturesOnUpdate(ctx context.Context, newWksp, oldWksp *dwv2.DevWorkspace) error {
+	disabledFeatures, err := h.getDisabledDevfileFeatures(ctx)
+	if err != nil {
+		return err
+	}
+	newUsages := restrictions.GetDisabledFeatureUsages(&newWksp.Spec.Template, disabledFeatures)
+	if len(newUsages) == 0 {
+		return nil
+	}
+	oldUsages := map[string]bool{}
+	for _, usage := range restrictions.GetDisabledFeatureUsages(&oldWksp.Spec.Template, disabledFeatures) {
+		oldUsages[usage] = true
+	}
+	var addedUsages []string
+	for _, usage := range newUsages {
+		if !oldUsages[usage] {
+			addedUsages = append(addedUsages, usage)
+		}
+	}
+	if len(addedUsages) == 0 {
+		return nil
+	}
+	return restrictions.FormatDisabledFeaturesError(addedUsages)
+}
+
+// getDisabledDevfileFeatures reads the list of disabled devfile features from the global DevWorkspaceOperatorConfig.
+func (h *WebhookHandler) getDisabledDevfileFeatures(ctx context.Context) ([]controller.DevfileFeature, error) {
+	globalConfig, err := h.getGlobalOperatorConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if globalConfig == nil || globalConfig.Workspace == nil {
+		return nil, nil
+	}
+	return globalConfig.Workspace.DisabledDevfileFeatures, nil
+}
+
+// getGlobalOperatorConfig reads the configuration from the global DevWorkspaceOperatorConfig, returning nil if it does
+// not exist. The webhook server does not sync the operator configuration, so the config is read from the cluster on
+// each request.
+func (h *WebhookHandler) getGlobalOperatorConfig(ctx context.Context) (*controller.OperatorConfiguration, error) {
+	namespace, err := infrastructure.GetNamespace()
+	if err != nil {
+		return nil, err
+	}
+	dwoc := &controller.DevWorkspaceOperatorConfig{}
+	if err := h.APIReader.Get(ctx, types.NamespacedName{Name: config.OperatorConfigName, Namespace: namespace}, dwoc); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read DevWorkspaceOperatorConfig: %w", err)
+	}
+	return dwoc.Config, nil
+}
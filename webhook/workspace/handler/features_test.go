@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	controller "github.com/devfile/devworkspace-operator/apis/controller/v1alpha1"
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/config"
+	"github.com/devfile/devworkspace-operator/pkg/infrastructure"
+)
+
+func getFeaturesTestHandler(disabledFeatures ...controller.DevfileFeature) *WebhookHandler {
+	dwoc := &controller.DevWorkspaceOperatorConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.OperatorConfigName,
+			Namespace: testNamespace,
+		},
+		Config: &controller.OperatorConfiguration{
+			Workspace: &controller.WorkspaceConfig{
+				DisabledDevfileFeatures: disabledFeatures,
+			},
+		},
+	}
+	fakeClient := testutil.NewFakeClient(dwoc)
+	return &WebhookHandler{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+	}
+}
+
+func withKubernetesComponent(wksp *dwv2.DevWorkspace, name string) *dwv2.DevWorkspace {
+	wksp.Spec.Template.Components = append(wksp.Spec.Template.Components, dwv2.Component{
+		Name: name,
+		ComponentUnion: dwv2.ComponentUnion{
+			Kubernetes: &dwv2.KubernetesComponent{},
+		},
+	})
+	return wksp
+}
+
+func TestDisabledFeaturesAreDeniedOnCreate(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	handler := getFeaturesTestHandler(controller.KubernetesComponentsFeature)
+
+	wksp := withKubernetesComponent(getTestWorkspace("test-workspace", nil, nil), "database")
+	err := handler.validateDisabledFeaturesOnCreate(context.Background(), wksp)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "component 'database' is a kubernetes component (kubernetes-components)")
+	}
+
+	err = getFeaturesTestHandler().validateDisabledFeaturesOnCreate(context.Background(), wksp)
+	assert.NoError(t, err, "Should allow features that are not disabled")
+}
+
+func TestExistingUsesOfDisabledFeaturesAreAllowedOnUpdate(t *testing.T) {
+	t.Setenv(infrastructure.WatchNamespaceEnvVar, testNamespace)
+	handler := getFeaturesTestHandler(controller.KubernetesComponentsFeature)
+
+	oldWksp := withKubernetesComponent(getTestWorkspace("test-workspace", nil, nil), "database")
+	newWksp := oldWksp.DeepCopy()
+	newWksp.Spec.Started = true
+	err := handler.validateDisabledFeaturesOnUpdate(context.Background(), newWksp, oldWksp)
+	assert.NoError(t, err, "Should allow updates that do not add uses of disabled features")
+
+	newWksp = withKubernetesComponent(newWksp, "cache")
+	err = handler.validateDisabledFeaturesOnUpdate(context.Background(), newWksp, oldWksp)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "component 'cache' is a kubernetes component")
+		assert.NotContains(t, err.Error(), "component 'database'")
+	}
+}
@@ -22,13 +22,20 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/library/flatten/network"
 )
 
 type WebhookHandler struct {
 	ControllerUID    string
 	ControllerSAName string
 	Client           client.Client
-	Decoder          *admission.Decoder
+	// APIReader reads objects directly from the API server, for objects that are not cached by the webhook server
+	APIReader client.Reader
+	Decoder   *admission.Decoder
+	// HTTPClient is used to fetch the manifests of Kubernetes/OpenShift components that are defined via a URI. If nil,
+	// a default client is used.
+	HTTPClient network.HTTPGetter
 }
 
 // parse decodes the old and new objects in an admission request. Returns an error if req.OldObject is empty (the field
@@ -21,6 +21,7 @@ import (
 
 	dwv1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha1"
 	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/devfile/devworkspace-operator/pkg/constants"
@@ -131,17 +132,10 @@ func (h *WebhookHandler) handleImmutableObj(oldObj, newObj runtime.Object, uid s
 	return changePermitted(oldObj, newObj)
 }
 
-func (h *WebhookHandler) handleImmutablePod(oldObj, newObj *corev1.Pod, uid string) (allowed bool, msg string) {
-	if uid == h.ControllerUID {
-		return true, ""
-	}
-	oldAnnotations := oldObj.GetObjectMeta().GetAnnotations()
-	if oldAnnotations[constants.DevWorkspaceRestrictedAccessAnnotation] != "true" {
-		return true, ""
-	}
-	oldLabels := oldObj.GetObjectMeta().GetLabels()
-	if oldLabels[constants.DevWorkspaceCreatorLabel] == uid {
-		return true, ""
+func (h *WebhookHandler) handleImmutablePod(ctx context.Context, oldObj, newObj *corev1.Pod, userInfo authenticationv1.UserInfo) (allowed bool, msg string, err error) {
+	allowed, err = h.checkCreatorAccess(ctx, oldObj, userInfo)
+	if err != nil || allowed {
+		return allowed, "", err
 	}
 
 	// Edge case -- it's possible to update images in a Pod and this is seemingly not reverted by the ReplicaSet (tested on OpenShift 4.10)
@@ -151,7 +145,7 @@ func (h *WebhookHandler) handleImmutablePod(oldObj, newObj *corev1.Pod, uid stri
 	}
 	for _, oldContainer := range oldObj.Spec.Containers {
 		if newContainerImages[oldContainer.Name] != oldContainer.Image {
-			return false, "Not permitted to update container images for restricted-access pod"
+			return false, "Not permitted to update container images for restricted-access pod", nil
 		}
 	}
 
@@ -161,11 +155,11 @@ func (h *WebhookHandler) handleImmutablePod(oldObj, newObj *corev1.Pod, uid stri
 	}
 	for _, oldInitContainer := range oldObj.Spec.InitContainers {
 		if newContainerImages[oldInitContainer.Name] != oldInitContainer.Image {
-			return false, "Not permitted to update init container images for restricted-access pod"
+			return false, "Not permitted to update init container images for restricted-access pod", nil
 		}
 	}
 
-	return true, ""
+	return true, "", nil
 }
 
 func (h *WebhookHandler) handleImmutableRoute(oldObj, newObj runtime.Object, username string) (allowed bool, msg string) {
@@ -25,10 +25,14 @@ var (
 	V1alpha1DevWorkspaceRoutingKind = metav1.GroupVersionKind{Kind: "DevWorkspaceRouting", Group: "controller.devfile.io", Version: "v1alpha1"}
 	V1alpha1ComponentKind           = metav1.GroupVersionKind{Kind: "Component", Group: "controller.devfile.io", Version: "v1alpha1"}
 
-	AppsV1DeploymentKind = metav1.GroupVersionKind{Kind: "Deployment", Group: "apps", Version: "v1"}
-	V1PodKind            = metav1.GroupVersionKind{Kind: "Pod", Group: "", Version: "v1"}
-	V1ServiceKind        = metav1.GroupVersionKind{Kind: "Service", Group: "", Version: "v1"}
-	V1IngressKind        = metav1.GroupVersionKind{Kind: "Ingress", Group: "networking.k8s.io", Version: "v1"}
-	V1JobKind            = metav1.GroupVersionKind{Kind: "Job", Group: "batch", Version: "v1"}
-	V1RouteKind          = metav1.GroupVersionKind{Kind: "Route", Group: "route.openshift.io", Version: "v1"}
+	AppsV1DeploymentKind  = metav1.GroupVersionKind{Kind: "Deployment", Group: "apps", Version: "v1"}
+	AppsV1StatefulSetKind = metav1.GroupVersionKind{Kind: "StatefulSet", Group: "apps", Version: "v1"}
+	V1PodKind             = metav1.GroupVersionKind{Kind: "Pod", Group: "", Version: "v1"}
+	V1PVCKind             = metav1.GroupVersionKind{Kind: "PersistentVolumeClaim", Group: "", Version: "v1"}
+	V1ServiceKind         = metav1.GroupVersionKind{Kind: "Service", Group: "", Version: "v1"}
+	V1IngressKind         = metav1.GroupVersionKind{Kind: "Ingress", Group: "networking.k8s.io", Version: "v1"}
+	V1JobKind             = metav1.GroupVersionKind{Kind: "Job", Group: "batch", Version: "v1"}
+	V1RouteKind           = metav1.GroupVersionKind{Kind: "Route", Group: "route.openshift.io", Version: "v1"}
+
+	AutoscalingV1ScaleKind = metav1.GroupVersionKind{Kind: "Scale", Group: "autoscaling", Version: "v1"}
 )
@@ -16,98 +16,134 @@ package handler
 import (
 	"context"
 	"fmt"
-	"strings"
+	"net/http"
+	"time"
 
 	dwv1 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha1"
 	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
 	authv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	"sigs.k8s.io/yaml"
-)
 
-var (
-	// Verbs used in SAR checks when users attempt to use a Kubernetes/OpenShift component. A longer list results in
-	// more SAR checks per request. We cannot use '*' as a verb as the SAR will check for literal '*' permissions
-	// rather than "all verbs"
-	userVerbs = []string{"get", "create", "update", "delete"}
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	kubesync "github.com/devfile/devworkspace-operator/pkg/library/kubernetes"
 )
 
-func (h *WebhookHandler) validateKubernetesObjectPermissionsOnCreate(ctx context.Context, req admission.Request, wksp *dwv2.DevWorkspaceTemplateSpec) error {
-	kubeComponents := getKubeComponentsFromWorkspace(wksp)
-	for componentName, component := range kubeComponents {
-		if !component.GetDeployByDefault() {
+// defaultHTTPClient is used to fetch the manifests of Kubernetes/OpenShift components that are defined via a URI.
+// Its timeout is shorter than the timeout of the webhook itself.
+var defaultHTTPClient = &http.Client{Timeout: 3 * time.Second}
+
+// validateKubernetesObjectPermissionsOnCreate checks the permissions required for each Kubernetes or OpenShift
+// component in a DevWorkspace or DevWorkspaceTemplate, and records the digest of the manifests fetched from URIs in
+// the KubernetesComponentDigestAttribute of the components. Returns whether wksp was modified.
+func (h *WebhookHandler) validateKubernetesObjectPermissionsOnCreate(ctx context.Context, req admission.Request, wksp *dwv2.DevWorkspaceTemplateSpec) (bool, error) {
+	return h.validateKubernetesObjectPermissionsOnUpdate(ctx, req, wksp, nil)
+}
+
+// validateKubernetesObjectPermissionsOnUpdate checks the permissions required for Kubernetes or OpenShift components
+// that were added or changed in an update, and records the digest of the manifests fetched from URIs in the
+// KubernetesComponentDigestAttribute of the components. A component is reviewed again if its digest attribute is
+// modified, so that the attribute always matches content that was reviewed. Returns whether newWksp was modified.
+func (h *WebhookHandler) validateKubernetesObjectPermissionsOnUpdate(ctx context.Context, req admission.Request, newWksp, oldWksp *dwv2.DevWorkspaceTemplateSpec) (bool, error) {
+	oldComponents := map[string]*dwv2.Component{}
+	if oldWksp != nil {
+		for idx := range oldWksp.Components {
+			oldComponents[oldWksp.Components[idx].Name] = &oldWksp.Components[idx]
+		}
+	}
+
+	modified := false
+	for idx := range newWksp.Components {
+		component := &newWksp.Components[idx]
+		newKubeComponent, err := getKubeLikeComponent(component)
+		if err != nil {
+			continue
+		}
+		if !newKubeComponent.GetDeployByDefault() {
 			// Intended to be applied later, will not be handled by DWO. It's up to whoever applies it to make
 			// sure that's safe to do (e.g. by using the user's token to apply the yaml)
 			continue
 		}
-		if component.Uri != "" {
-			// We're going to ignore URI components for now
+		if oldComponent, ok := oldComponents[component.Name]; ok && !kubeComponentChanged(oldComponent, component) {
 			continue
 		}
-		if component.Inlined == "" {
-			return fmt.Errorf("kubernetes component does not define inlined content")
+
+		// Review new or changed components
+		digest, err := h.validatePermissionsOnComponent(ctx, req, component.Name, newKubeComponent)
+		if err != nil {
+			return false, err
 		}
-		if err := h.validatePermissionsOnObject(ctx, req, componentName, component.Inlined); err != nil {
-			return err
+		if newKubeComponent.Uri != "" {
+			if component.Attributes == nil {
+				component.Attributes = attributes.Attributes{}
+			}
+			component.Attributes.PutString(constants.KubernetesComponentDigestAttribute, digest)
+			modified = true
 		}
 	}
-	return nil
+	return modified, nil
 }
 
-func (h *WebhookHandler) validateKubernetesObjectPermissionsOnUpdate(ctx context.Context, req admission.Request, newWksp, oldWksp *dwv2.DevWorkspaceTemplateSpec) error {
-	newKubeComponents := getKubeComponentsFromWorkspace(newWksp)
-	oldKubeComponents := getKubeComponentsFromWorkspace(oldWksp)
-
-	for componentName, newComponent := range newKubeComponents {
-		if !newComponent.GetDeployByDefault() {
-			// Intended to be applied later, will not be handled by DWO. It's up to whoever applies it to make
-			// sure that's safe to do (e.g. by using the user's token to apply the yaml)
-			continue
-		}
-
-		if newComponent.Uri != "" {
-			// We're going to ignore URI components for now
-			continue
-		}
-		if newComponent.Inlined == "" {
-			return fmt.Errorf("kubernetes component does not define inlined content")
-		}
+// kubeComponentChanged returns whether a Kubernetes or OpenShift component needs to be reviewed again, i.e. whether
+// its content, the URI it is fetched from, or the digest recorded when it was last reviewed has changed, or whether
+// it was not previously deployed by the DevWorkspace Operator.
+func kubeComponentChanged(oldComponent, newComponent *dwv2.Component) bool {
+	oldKubeComponent, err := getKubeLikeComponent(oldComponent)
+	if err != nil {
+		return true
+	}
+	newKubeComponent, err := getKubeLikeComponent(newComponent)
+	if err != nil {
+		return true
+	}
+	oldDigest := oldComponent.Attributes.GetString(constants.KubernetesComponentDigestAttribute, nil)
+	newDigest := newComponent.Attributes.GetString(constants.KubernetesComponentDigestAttribute, nil)
+	return !oldKubeComponent.GetDeployByDefault() ||
+		oldKubeComponent.Inlined != newKubeComponent.Inlined ||
+		oldKubeComponent.Uri != newKubeComponent.Uri ||
+		oldDigest != newDigest
+}
 
-		oldComponent, ok := oldKubeComponents[componentName]
-		if !ok || oldComponent.Inlined != newComponent.Inlined {
-			// Review new components
-			if err := h.validatePermissionsOnObject(ctx, req, componentName, newComponent.Inlined); err != nil {
-				return err
-			}
+// validatePermissionsOnComponent checks that the user and the DevWorkspace Operator have the permissions required
+// to manage each object defined in a Kubernetes or OpenShift component. If the component defines its objects via a
+// URI, the manifest is fetched to review the objects it contains, and its digest is returned.
+func (h *WebhookHandler) validatePermissionsOnComponent(ctx context.Context, req admission.Request, componentName string, component *dwv2.K8sLikeComponent) (string, error) {
+	if component.Inlined == "" && component.Uri == "" {
+		return "", fmt.Errorf("kubernetes component does not define inlined content or a URI")
+	}
+	httpClient := h.HTTPClient
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+	manifests, digest, err := kubesync.ReviewComponentManifests(component, httpClient)
+	if err != nil {
+		return "", fmt.Errorf("failed to read content for component %s: %w", componentName, err)
+	}
+	for _, manifest := range manifests {
+		if err := h.validatePermissionsOnObject(ctx, req, componentName, manifest); err != nil {
+			return "", err
 		}
 	}
-	return nil
+	return digest, nil
 }
 
-func (h *WebhookHandler) validatePermissionsOnObject(ctx context.Context, req admission.Request, componentName, component string) error {
+func (h *WebhookHandler) validatePermissionsOnObject(ctx context.Context, req admission.Request, componentName string, manifest []byte) error {
 
 	typeMeta := &metav1.TypeMeta{}
-	if err := yaml.Unmarshal([]byte(component), typeMeta); err != nil {
+	if err := yaml.Unmarshal(manifest, typeMeta); err != nil {
 		return fmt.Errorf("failed to read content for component %s", componentName)
 	}
 	kind := typeMeta.Kind
-	if kind == "List" {
-		return fmt.Errorf("lists are not supported in Kubernetes or OpenShift components")
-	}
-	if kind == "Role" || kind == "Rolebinding" || kind == "ClusterRole" || kind == "ClusterRoleBinding" {
-		return fmt.Errorf("kubernetes RBAC objects are not permitted within DevWorkspace components")
-	}
-	if kind == "DevWorkspace" || kind == "DevWorkspaceTemplate" {
-		return fmt.Errorf("DevWorkspace objects are not permitted within DevWorkspace components")
+	if err := kubesync.ValidateObjectKind(kind); err != nil {
+		return err
 	}
 
-	// Workaround to get the correct resource type for a given kind -- probably fragile
-	// Convert e.g. Pod -> pods, Deployment -> deployments
-	resourceType := fmt.Sprintf("%ss", strings.ToLower(typeMeta.Kind))
+	resourceType := kubesync.ResourceForKind(typeMeta.Kind)
 
 	// Check that user has permissions to use the resource
-	for _, verb := range userVerbs {
+	for _, verb := range kubesync.ReviewVerbs {
 		if err := h.checkSAR(ctx, req, typeMeta, resourceType, verb, componentName); err != nil {
 			return err
 		}
@@ -143,20 +179,6 @@ func (h *WebhookHandler) validatePermissionsOnObject(ctx context.Context, req ad
 	return nil
 }
 
-// getKubeComponentsFromWorkspace returns the Kubernetes (and OpenShift) components in a workspace
-// in a map with component names as keys.
-func getKubeComponentsFromWorkspace(wksp *dwv2.DevWorkspaceTemplateSpec) map[string]dwv2.K8sLikeComponent {
-	kubeComponents := map[string]dwv2.K8sLikeComponent{}
-	for _, component := range wksp.Components {
-		kubeComponent, err := getKubeLikeComponent(&component)
-		if err != nil {
-			continue
-		}
-		kubeComponents[component.Name] = *kubeComponent
-	}
-	return kubeComponents
-}
-
 // getKubeLikeComponent returns the definition of the Kubernetes or OpenShift
 // component defined by a general DevWorkspace Component. If the component does
 // not specify the Kubernetes or OpenShift field, an error is returned.
@@ -182,7 +204,7 @@ func (h *WebhookHandler) validateKubernetesObjectPermissionsOnCreate_v1alpha1(ct
 		// v1alpha1 DevWorkspace/DevWorkspaceTemplates do not have a deployByDefault field, and the default
 		// value in v1alpha2 is false (i.e. do not deploy at start time); however, for safety we check permissions
 		// even if the object will not be deployed (v1alpha1 should not be used, in general)
-		if err := h.validatePermissionsOnObject(ctx, req, componentName, component.Inlined); err != nil {
+		if err := h.validatePermissionsOnInlinedContent(ctx, req, componentName, component.Inlined); err != nil {
 			return err
 		}
 	}
@@ -207,7 +229,7 @@ func (h *WebhookHandler) validateKubernetesObjectPermissionsOnUpdate_v1alpha1(ct
 		oldComponent, ok := oldKubeComponents[componentName]
 		if !ok || oldComponent.Inlined != newComponent.Inlined {
 			// Review new components
-			if err := h.validatePermissionsOnObject(ctx, req, componentName, newComponent.Inlined); err != nil {
+			if err := h.validatePermissionsOnInlinedContent(ctx, req, componentName, newComponent.Inlined); err != nil {
 				return err
 			}
 		}
@@ -215,6 +237,19 @@ func (h *WebhookHandler) validateKubernetesObjectPermissionsOnUpdate_v1alpha1(ct
 	return nil
 }
 
+func (h *WebhookHandler) validatePermissionsOnInlinedContent(ctx context.Context, req admission.Request, componentName, inlined string) error {
+	manifests, err := kubesync.SplitManifests([]byte(inlined))
+	if err != nil {
+		return fmt.Errorf("failed to read content for component %s: %w", componentName, err)
+	}
+	for _, manifest := range manifests {
+		if err := h.validatePermissionsOnObject(ctx, req, componentName, manifest); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getKubeComponentsFromWorkspace_v1alpha1(wksp *dwv1.DevWorkspaceTemplateSpec) map[string]dwv1.K8sLikeComponent {
 	kubeComponents := map[string]dwv1.K8sLikeComponent{}
 	for _, component := range wksp.Components {
@@ -0,0 +1,194 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+	kubesync "github.com/devfile/devworkspace-operator/pkg/library/kubernetes"
+)
+
+const (
+	testManifestURI = "https://example.com/manifests.yaml"
+	testManifest    = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-configmap
+`
+)
+
+// allowingSARClient approves all SubjectAccessReviews created through it
+type allowingSARClient struct {
+	client.Client
+}
+
+func (c allowingSARClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if sar, ok := obj.(*authv1.LocalSubjectAccessReview); ok {
+		sar.Status.Allowed = true
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// countingHTTPGetter serves a manifest at testManifestURI and counts the requests made
+type countingHTTPGetter struct {
+	content  string
+	requests int
+}
+
+func (g *countingHTTPGetter) Get(location string) (*http.Response, error) {
+	g.requests++
+	if location != testManifestURI {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(g.content))}, nil
+}
+
+func getKubernetesTestHandler(httpClient *countingHTTPGetter) *WebhookHandler {
+	return &WebhookHandler{
+		Client:     allowingSARClient{fake.NewClientBuilder().WithScheme(testutil.Scheme).Build()},
+		HTTPClient: httpClient,
+	}
+}
+
+func getKubernetesTestTemplate(digest string) *dwv2.DevWorkspaceTemplateSpec {
+	component := dwv2.Component{
+		Name: "test-objects",
+		ComponentUnion: dwv2.ComponentUnion{
+			Kubernetes: &dwv2.KubernetesComponent{
+				K8sLikeComponent: dwv2.K8sLikeComponent{
+					DeployByDefault: pointer.Bool(true),
+					K8sLikeComponentLocation: dwv2.K8sLikeComponentLocation{
+						Uri: testManifestURI,
+					},
+				},
+			},
+		},
+	}
+	if digest != "" {
+		component.Attributes = attributes.Attributes{}.PutString(constants.KubernetesComponentDigestAttribute, digest)
+	}
+	return &dwv2.DevWorkspaceTemplateSpec{
+		DevWorkspaceTemplateSpecContent: dwv2.DevWorkspaceTemplateSpecContent{
+			Components: []dwv2.Component{component},
+		},
+	}
+}
+
+func getKubernetesTestRequest() admission.Request {
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Namespace: testNamespace,
+			UserInfo:  authenticationv1.UserInfo{Username: "test-user", UID: "test-uid"},
+		},
+	}
+}
+
+func TestKubernetesComponentDigestIsRecordedOnCreate(t *testing.T) {
+	httpClient := &countingHTTPGetter{content: testManifest}
+	handler := getKubernetesTestHandler(httpClient)
+	wksp := getKubernetesTestTemplate("sha256:user-provided")
+
+	modified, err := handler.validateKubernetesObjectPermissionsOnCreate(context.Background(), getKubernetesTestRequest(), wksp)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, modified, "Should record digest of reviewed manifest")
+	assert.Equal(t, kubesync.ManifestDigest([]byte(testManifest)),
+		wksp.Components[0].Attributes.GetString(constants.KubernetesComponentDigestAttribute, nil),
+		"Should overwrite digest set by user")
+}
+
+func TestKubernetesComponentDigestOnUpdate(t *testing.T) {
+	reviewedDigest := kubesync.ManifestDigest([]byte(testManifest))
+	tests := []struct {
+		name             string
+		newDigest        string
+		served           string
+		expectedRequests int
+		expectedDigest   string
+		expectedErr      string
+	}{
+		{
+			name:             "Does not review unchanged component",
+			newDigest:        reviewedDigest,
+			served:           "changed content",
+			expectedRequests: 0,
+			expectedDigest:   reviewedDigest,
+		},
+		{
+			name:             "Reviews component again if digest is changed",
+			newDigest:        "sha256:user-provided",
+			served:           testManifest,
+			expectedRequests: 1,
+			expectedDigest:   reviewedDigest,
+		},
+		{
+			name:             "Reviews component again if digest is removed",
+			served:           testManifest,
+			expectedRequests: 1,
+			expectedDigest:   reviewedDigest,
+		},
+		{
+			name:      "Rejects changed content that cannot be reviewed",
+			newDigest: "sha256:user-provided",
+			served: `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: test-rolebinding
+`,
+			expectedRequests: 1,
+			expectedErr:      "kubernetes RBAC objects are not permitted within DevWorkspace components",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &countingHTTPGetter{content: tt.served}
+			handler := getKubernetesTestHandler(httpClient)
+			oldWksp := getKubernetesTestTemplate(reviewedDigest)
+			newWksp := getKubernetesTestTemplate(tt.newDigest)
+
+			modified, err := handler.validateKubernetesObjectPermissionsOnUpdate(context.Background(), getKubernetesTestRequest(), newWksp, oldWksp)
+			assert.Equal(t, tt.expectedRequests, httpClient.requests)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.expectedRequests > 0, modified)
+			assert.Equal(t, tt.expectedDigest, newWksp.Components[0].Attributes.GetString(constants.KubernetesComponentDigestAttribute, nil))
+		})
+	}
+}
@@ -18,3 +18,7 @@ package handler
 import logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 var log = logf.Log.WithName("webhook.workspace.handler")
+
+// auditLog is used to record requests that are relevant to the DevWorkspace audit log but are only visible to the
+// webhook server, such as the user that deleted a DevWorkspace.
+var auditLog = logf.Log.WithName("audit")
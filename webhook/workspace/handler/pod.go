@@ -39,7 +39,7 @@ func (h *WebhookHandler) MutatePodOnCreate(_ context.Context, req admission.Requ
 	return admission.Allowed("The object is valid")
 }
 
-func (h *WebhookHandler) MutatePodOnUpdate(_ context.Context, req admission.Request) admission.Response {
+func (h *WebhookHandler) MutatePodOnUpdate(ctx context.Context, req admission.Request) admission.Response {
 	oldP := &corev1.Pod{}
 	newP := &corev1.Pod{}
 
@@ -52,7 +52,11 @@ func (h *WebhookHandler) MutatePodOnUpdate(_ context.Context, req admission.Requ
 		return admission.Denied(msg)
 	}
 
-	if ok, msg := h.handleImmutablePod(oldP, newP, req.UserInfo.UID); !ok {
+	ok, msg, err := h.handleImmutablePod(ctx, oldP, newP, req.UserInfo)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !ok {
 		return admission.Denied(msg)
 	}
 
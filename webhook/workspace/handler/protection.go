@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+// ValidateDeletion rejects deletion of protected DevWorkspaces and of PVCs that store data for protected DevWorkspaces.
+// A DevWorkspace is protected if it has the annotation or attribute 'controller.devfile.io/protected: true'.
+func (h *WebhookHandler) ValidateDeletion(ctx context.Context, req admission.Request) admission.Response {
+	switch req.Kind {
+	case V1alpha2DevWorkspaceKind:
+		wksp := &dwv2.DevWorkspace{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, wksp); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if isProtected(wksp) {
+			return admission.Denied(fmt.Sprintf("DevWorkspace %s is protected from deletion. Remove the %s annotation or attribute to allow it to be deleted",
+				wksp.Name, constants.DevWorkspaceProtectedAnnotation))
+		}
+		h.auditDeletion(ctx, req, wksp)
+		return admission.Allowed("DevWorkspace is not protected")
+	case V1PVCKind:
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, pvc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		protected, err := h.getProtectedWorkspacesForPVC(ctx, pvc)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if len(protected) > 0 {
+			return admission.Denied(fmt.Sprintf("PVC %s stores data for protected DevWorkspace(s) %v. Remove the %s annotation or attribute from these DevWorkspaces to allow it to be deleted",
+				pvc.Name, protected, constants.DevWorkspaceProtectedAnnotation))
+		}
+		return admission.Allowed("PVC is not used by protected DevWorkspaces")
+	default:
+		return admission.Allowed("Deletion of this resource is not restricted")
+	}
+}
+
+// auditDeletion logs the user that requested deletion of a DevWorkspace if auditing is enabled in the global
+// DevWorkspaceOperatorConfig. The deletion itself is recorded by the DevWorkspace controller, which does not know
+// which user requested it.
+func (h *WebhookHandler) auditDeletion(ctx context.Context, req admission.Request, wksp *dwv2.DevWorkspace) {
+	if req.DryRun != nil && *req.DryRun {
+		return
+	}
+	globalConfig, err := h.getGlobalOperatorConfig(ctx)
+	if err != nil {
+		log.Error(err, "Failed to read global DevWorkspaceOperatorConfig")
+		return
+	}
+	if globalConfig == nil || globalConfig.Audit == nil || globalConfig.Audit.Enable == nil || !*globalConfig.Audit.Enable {
+		return
+	}
+	auditLog.Info("DevWorkspace audit event",
+		"event", "deletion-requested",
+		"workspace", wksp.Name,
+		"namespace", wksp.Namespace,
+		"devworkspaceId", wksp.Status.DevWorkspaceId,
+		"user", req.UserInfo.Username)
+}
+
+// isProtected returns whether a DevWorkspace is protected from deletion, either via the
+// DevWorkspaceProtectedAnnotation annotation or the DevWorkspaceProtectedAttribute attribute.
+func isProtected(wksp *dwv2.DevWorkspace) bool {
+	if wksp.Annotations[constants.DevWorkspaceProtectedAnnotation] == "true" {
+		return true
+	}
+	if wksp.Spec.Template.Attributes.Exists(constants.DevWorkspaceProtectedAttribute) {
+		var attrErr error
+		protected := wksp.Spec.Template.Attributes.GetBoolean(constants.DevWorkspaceProtectedAttribute, &attrErr)
+		return attrErr == nil && protected
+	}
+	return false
+}
+
+// getProtectedWorkspacesForPVC returns the names of protected DevWorkspaces that store data in the given PVC. PVCs that
+// are not labelled as DevWorkspace PVCs are never considered to be in use by a protected DevWorkspace.
+func (h *WebhookHandler) getProtectedWorkspacesForPVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) ([]string, error) {
+	pvcType, ok := pvc.Labels[constants.DevWorkspacePVCTypeLabel]
+	if !ok {
+		return nil, nil
+	}
+	var protected []string
+	if pvcType == constants.PerWorkspaceStorageClassType {
+		// Per-workspace PVCs are owned by the DevWorkspace that uses them
+		for _, ownerRef := range pvc.OwnerReferences {
+			if ownerRef.Kind != "DevWorkspace" || ownerRef.APIVersion != dwv2.SchemeGroupVersion.String() {
+				continue
+			}
+			wksp := &dwv2.DevWorkspace{}
+			err := h.APIReader.Get(ctx, client.ObjectKey{Name: ownerRef.Name, Namespace: pvc.Namespace}, wksp)
+			if err != nil {
+				if k8sErrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			if wksp.UID == ownerRef.UID && isProtected(wksp) {
+				protected = append(protected, wksp.Name)
+			}
+		}
+		return protected, nil
+	}
+
+	// Common and per-user PVCs are shared by all DevWorkspaces in the namespace that do not use a different storage type
+	workspaces := &dwv2.DevWorkspaceList{}
+	if err := h.APIReader.List(ctx, workspaces, client.InNamespace(pvc.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range workspaces.Items {
+		wksp := &workspaces.Items[i]
+		if !isProtected(wksp) {
+			continue
+		}
+		switch wksp.Spec.Template.Attributes.GetString(constants.DevWorkspaceStorageTypeAttribute, nil) {
+		case constants.PerWorkspaceStorageClassType, constants.EphemeralStorageClassType, constants.AsyncStorageClassType:
+			continue
+		}
+		protected = append(protected, wksp.Name)
+	}
+	return protected, nil
+}
@@ -0,0 +1,186 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/devfile/api/v2/pkg/attributes"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/internal/testutil"
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const testNamespace = "test-namespace"
+
+func getProtectionTestHandler(t *testing.T, objs ...client.Object) *WebhookHandler {
+	decoder, err := admission.NewDecoder(testutil.Scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeClient := testutil.NewFakeClient(objs...)
+	return &WebhookHandler{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+		Decoder:   decoder,
+	}
+}
+
+func getDeleteRequest(t *testing.T, kind metav1.GroupVersionKind, obj client.Object) admission.Request {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      kind,
+			Operation: admissionv1.Delete,
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			OldObject: runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func getTestWorkspace(name string, annotations map[string]string, attrs attributes.Attributes) *dwv2.DevWorkspace {
+	workspace := testutil.NewDevWorkspace(name, "")
+	workspace.Annotations = annotations
+	workspace.Spec.Template.Attributes = attrs
+	return workspace
+}
+
+func getTestPVC(name, pvcType string, owner *dwv2.DevWorkspace) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				constants.DevWorkspacePVCTypeLabel: pvcType,
+			},
+		},
+	}
+	if owner != nil {
+		pvc.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: dwv2.SchemeGroupVersion.String(),
+				Kind:       "DevWorkspace",
+				Name:       owner.Name,
+				UID:        owner.UID,
+			},
+		}
+	}
+	return pvc
+}
+
+func TestDeletionOfProtectedDevWorkspaceIsDenied(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace *dwv2.DevWorkspace
+		allowed   bool
+	}{
+		{
+			name:      "Unprotected DevWorkspace",
+			workspace: getTestWorkspace("test-dw", nil, nil),
+			allowed:   true,
+		},
+		{
+			name:      "Protected via annotation",
+			workspace: getTestWorkspace("test-dw", map[string]string{constants.DevWorkspaceProtectedAnnotation: "true"}, nil),
+			allowed:   false,
+		},
+		{
+			name:      "Annotation set to false",
+			workspace: getTestWorkspace("test-dw", map[string]string{constants.DevWorkspaceProtectedAnnotation: "false"}, nil),
+			allowed:   true,
+		},
+		{
+			name:      "Protected via attribute",
+			workspace: getTestWorkspace("test-dw", nil, attributes.Attributes{}.PutBoolean(constants.DevWorkspaceProtectedAttribute, true)),
+			allowed:   false,
+		},
+		{
+			name:      "Attribute set to false",
+			workspace: getTestWorkspace("test-dw", nil, attributes.Attributes{}.PutBoolean(constants.DevWorkspaceProtectedAttribute, false)),
+			allowed:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := getProtectionTestHandler(t, tt.workspace)
+			resp := h.ValidateDeletion(context.Background(), getDeleteRequest(t, V1alpha2DevWorkspaceKind, tt.workspace))
+			assert.Equal(t, tt.allowed, resp.Allowed, "Unexpected admission response: %s", resp.Result.Reason)
+		})
+	}
+}
+
+func TestDeletionOfPerWorkspacePVC(t *testing.T) {
+	protected := getTestWorkspace("protected-dw", map[string]string{constants.DevWorkspaceProtectedAnnotation: "true"}, nil)
+	unprotected := getTestWorkspace("unprotected-dw", nil, nil)
+
+	h := getProtectionTestHandler(t, protected, unprotected)
+
+	pvc := getTestPVC("storage-protected-dw", constants.PerWorkspaceStorageClassType, protected)
+	resp := h.ValidateDeletion(context.Background(), getDeleteRequest(t, V1PVCKind, pvc))
+	assert.False(t, resp.Allowed, "Should deny deleting PVC owned by protected DevWorkspace")
+	assert.Contains(t, string(resp.Result.Reason), "protected-dw")
+
+	pvc = getTestPVC("storage-unprotected-dw", constants.PerWorkspaceStorageClassType, unprotected)
+	resp = h.ValidateDeletion(context.Background(), getDeleteRequest(t, V1PVCKind, pvc))
+	assert.True(t, resp.Allowed, "Should allow deleting PVC owned by unprotected DevWorkspace: %s", resp.Result.Reason)
+}
+
+func TestDeletionOfCommonPVC(t *testing.T) {
+	perWorkspaceAttrs := attributes.Attributes{}.
+		PutString(constants.DevWorkspaceStorageTypeAttribute, constants.PerWorkspaceStorageClassType).
+		PutBoolean(constants.DevWorkspaceProtectedAttribute, true)
+	perWorkspace := getTestWorkspace("per-workspace-dw", nil, perWorkspaceAttrs)
+	unprotected := getTestWorkspace("unprotected-dw", nil, nil)
+	protected := getTestWorkspace("protected-dw", map[string]string{constants.DevWorkspaceProtectedAnnotation: "true"}, nil)
+	pvc := getTestPVC("claim-devworkspace", constants.PerUserStorageClassType, nil)
+
+	h := getProtectionTestHandler(t, perWorkspace, unprotected)
+	resp := h.ValidateDeletion(context.Background(), getDeleteRequest(t, V1PVCKind, pvc))
+	assert.True(t, resp.Allowed, "Should allow deleting common PVC when no protected DevWorkspace uses it: %s", resp.Result.Reason)
+
+	h = getProtectionTestHandler(t, perWorkspace, unprotected, protected)
+	resp = h.ValidateDeletion(context.Background(), getDeleteRequest(t, V1PVCKind, pvc))
+	assert.False(t, resp.Allowed, "Should deny deleting common PVC used by protected DevWorkspace")
+	assert.Contains(t, string(resp.Result.Reason), "protected-dw")
+	assert.NotContains(t, string(resp.Result.Reason), "per-workspace-dw")
+}
+
+func TestDeletionOfUnlabelledPVCIsAllowed(t *testing.T) {
+	protected := getTestWorkspace("protected-dw", map[string]string{constants.DevWorkspaceProtectedAnnotation: "true"}, nil)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-pvc",
+			Namespace: testNamespace,
+		},
+	}
+	h := getProtectionTestHandler(t, protected)
+	resp := h.ValidateDeletion(context.Background(), getDeleteRequest(t, V1PVCKind, pvc))
+	assert.True(t, resp.Allowed, "Should allow deleting PVCs not used by DevWorkspaces: %s", resp.Result.Reason)
+}
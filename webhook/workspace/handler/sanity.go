@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"fmt"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// checkSpecSanity returns errors for content of a DevWorkspace's template that is invalid regardless of the parents and
+// plugins it uses: duplicate component names, components without a supported type, invalid memory and CPU quantities
+// and endpoints whose names or ports conflict. These would otherwise only be detected after the DevWorkspace is
+// flattened, failing it during reconciliation.
+func checkSpecSanity(template *dwv2.DevWorkspaceTemplateSpec) []string {
+	var errs []string
+	componentNames := map[string]bool{}
+	for _, component := range template.Components {
+		if componentNames[component.Name] {
+			errs = append(errs, fmt.Sprintf("component name %q is used by more than one component; component names must be unique", component.Name))
+		}
+		componentNames[component.Name] = true
+
+		if !hasComponentType(component) {
+			errs = append(errs, fmt.Sprintf("component %q does not define a supported component type; it must define one of: container, kubernetes, openshift, volume, image, plugin or custom", component.Name))
+		}
+		if component.Container != nil {
+			errs = append(errs, checkContainerResources(component.Name, component.Container)...)
+		}
+	}
+	errs = append(errs, checkEndpointConflicts(template.Components)...)
+	return errs
+}
+
+func hasComponentType(component dwv2.Component) bool {
+	return component.Container != nil ||
+		component.Kubernetes != nil ||
+		component.Openshift != nil ||
+		component.Volume != nil ||
+		component.Image != nil ||
+		component.Plugin != nil ||
+		component.Custom != nil
+}
+
+// checkContainerResources checks that the memory and CPU requirements of a container component are valid quantities,
+// and that requests do not exceed limits.
+func checkContainerResources(componentName string, container *dwv2.ContainerComponent) []string {
+	var errs []string
+	parse := func(field, value, example string) *resource.Quantity {
+		if value == "" {
+			return nil
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("component %q has invalid %s %q; use a quantity such as %s", componentName, field, value, example))
+			return nil
+		}
+		return &quantity
+	}
+	memoryLimit := parse("memoryLimit", container.MemoryLimit, `"512Mi" or "2Gi"`)
+	memoryRequest := parse("memoryRequest", container.MemoryRequest, `"512Mi" or "2Gi"`)
+	cpuLimit := parse("cpuLimit", container.CpuLimit, `"500m" or "2"`)
+	cpuRequest := parse("cpuRequest", container.CpuRequest, `"500m" or "2"`)
+
+	if memoryLimit != nil && memoryRequest != nil && memoryRequest.Cmp(*memoryLimit) > 0 {
+		errs = append(errs, fmt.Sprintf("component %q has memoryRequest %s greater than memoryLimit %s", componentName, container.MemoryRequest, container.MemoryLimit))
+	}
+	if cpuLimit != nil && cpuRequest != nil && cpuRequest.Cmp(*cpuLimit) > 0 {
+		errs = append(errs, fmt.Sprintf("component %q has cpuRequest %s greater than cpuLimit %s", componentName, container.CpuRequest, container.CpuLimit))
+	}
+	return errs
+}
+
+// checkEndpointConflicts checks that endpoint names are unique across container components, and that a port is not
+// used by endpoints in more than one container component, as all containers of a DevWorkspace share the same pod.
+// Multiple endpoints in the same container may use the same port, e.g. to expose different paths.
+func checkEndpointConflicts(components []dwv2.Component) []string {
+	type endpointRef struct {
+		componentName string
+		endpointName  string
+	}
+	var errs []string
+	endpointNames := map[string]string{}
+	endpointPorts := map[int]endpointRef{}
+	for _, component := range components {
+		if component.Container == nil {
+			continue
+		}
+		for _, endpoint := range component.Container.Endpoints {
+			if otherComponent, exists := endpointNames[endpoint.Name]; exists {
+				errs = append(errs, fmt.Sprintf("endpoint name %q is used in components %q and %q; endpoint names must be unique", endpoint.Name, otherComponent, component.Name))
+			} else {
+				endpointNames[endpoint.Name] = component.Name
+			}
+			if other, exists := endpointPorts[endpoint.TargetPort]; exists && other.componentName != component.Name {
+				errs = append(errs, fmt.Sprintf("endpoints %q in component %q and %q in component %q both use port %d; containers in a DevWorkspace share a pod, so a port can only be used by one container",
+					other.endpointName, other.componentName, endpoint.Name, component.Name, endpoint.TargetPort))
+			} else if !exists {
+				endpointPorts[endpoint.TargetPort] = endpointRef{componentName: component.Name, endpointName: endpoint.Name}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/yaml"
+)
+
+type sanityTestCase struct {
+	Name   string                         `json:"name,omitempty"`
+	Input  *dwv2.DevWorkspaceTemplateSpec `json:"input,omitempty"`
+	Output sanityTestOutput               `json:"output,omitempty"`
+}
+
+type sanityTestOutput struct {
+	Errors []string `json:"errors,omitempty"`
+}
+
+func loadSanityTestCaseOrPanic(t *testing.T, testFilepath string) sanityTestCase {
+	bytes, err := os.ReadFile(testFilepath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var test sanityTestCase
+	if err := yaml.Unmarshal(bytes, &test); err != nil {
+		t.Fatal(err)
+	}
+	return test
+}
+
+func loadAllSanityTestCasesOrPanic(t *testing.T, fromDir string) []sanityTestCase {
+	files, err := os.ReadDir(fromDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests []sanityTestCase
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		tests = append(tests, loadSanityTestCaseOrPanic(t, filepath.Join(fromDir, file.Name())))
+	}
+	return tests
+}
+
+func getContainerComponent(name string, container dwv2.ContainerComponent) dwv2.Component {
+	if container.Image == "" {
+		container.Image = "quay.io/devfile/universal-developer-image:latest"
+	}
+	return dwv2.Component{
+		Name: name,
+		ComponentUnion: dwv2.ComponentUnion{
+			Container: &container,
+		},
+	}
+}
+
+func TestCheckSpecSanity(t *testing.T) {
+	tests := loadAllSanityTestCasesOrPanic(t, "testdata/sanity")
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			// sanity check that file is read correctly.
+			assert.NotNil(t, tt.Input, "Input does not define a workspace template")
+			assert.Equal(t, tt.Output.Errors, checkSpecSanity(tt.Input), "Errors should match")
+		})
+	}
+}
+
+func getValidateRequest(t *testing.T, operation admissionv1.Operation, oldWksp, newWksp *dwv2.DevWorkspace) admission.Request {
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: operation,
+			Name:      newWksp.Name,
+			Namespace: newWksp.Namespace,
+		},
+	}
+	raw, err := json.Marshal(newWksp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Object = runtime.RawExtension{Raw: raw}
+	if oldWksp != nil {
+		raw, err := json.Marshal(oldWksp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+func TestValidateDevfileChecksSpecSanity(t *testing.T) {
+	h := getProtectionTestHandler(t)
+	invalidWksp := getTestWorkspace("test-workspace", nil, nil)
+	invalidWksp.Spec.Template.Components = []dwv2.Component{
+		getContainerComponent("tools", dwv2.ContainerComponent{Container: dwv2.Container{MemoryLimit: "2 GB"}}),
+	}
+
+	resp := h.ValidateDevfile(context.Background(), getValidateRequest(t, admissionv1.Create, nil, invalidWksp))
+	assert.False(t, resp.Allowed, "Should reject DevWorkspace with invalid content on create")
+	assert.Contains(t, string(resp.Result.Reason), `component "tools" has invalid memoryLimit "2 GB"`)
+
+	validWksp := invalidWksp.DeepCopy()
+	validWksp.Spec.Template.Components[0].Container.MemoryLimit = "2Gi"
+	resp = h.ValidateDevfile(context.Background(), getValidateRequest(t, admissionv1.Update, validWksp, invalidWksp))
+	assert.False(t, resp.Allowed, "Should reject update that adds invalid content")
+
+	stoppedWksp := invalidWksp.DeepCopy()
+	stoppedWksp.Spec.Started = false
+	invalidWksp.Spec.Started = true
+	resp = h.ValidateDevfile(context.Background(), getValidateRequest(t, admissionv1.Update, invalidWksp, stoppedWksp))
+	assert.True(t, resp.Allowed, "Should allow updates to existing DevWorkspaces that do not change the template")
+}
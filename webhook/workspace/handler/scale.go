@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+const scaleDeniedMessage = "Only the devworkspace creator can scale the workspace"
+
+// ValidateScaleOnUpdate validates updates to the scale subresource of deployments and statefulsets. Scaling the
+// workload of a restricted-access DevWorkspace is only permitted for the creator of the DevWorkspace and configured admin groups.
+func (h *WebhookHandler) ValidateScaleOnUpdate(ctx context.Context, req admission.Request) admission.Response {
+	oldScale := &autoscalingv1.Scale{}
+	newScale := &autoscalingv1.Scale{}
+	if err := h.parse(req, oldScale, newScale); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if oldScale.Spec.Replicas == newScale.Spec.Replicas {
+		return admission.Allowed("Replicas are not changed")
+	}
+
+	var workload client.Object
+	switch req.Resource.Resource {
+	case "statefulsets":
+		workload = &appsv1.StatefulSet{}
+	default:
+		workload = &appsv1.Deployment{}
+	}
+	err := h.Client.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, workload)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return admission.Allowed("Not a devworkspace workload")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if _, ok := workload.GetLabels()[constants.DevWorkspaceIDLabel]; !ok {
+		return admission.Allowed("Not a devworkspace workload")
+	}
+
+	allowed, err := h.checkCreatorAccess(ctx, workload, req.UserInfo)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !allowed {
+		log.Info(fmt.Sprintf("Denied request to scale %s '%s' by user %s", req.Resource.Resource, req.Name, req.UserInfo.Username))
+		return admission.Denied(scaleDeniedMessage)
+	}
+	return admission.Allowed("The current user and devworkspace are matched")
+}
+
+// ValidateStatefulSetOnUpdate validates updates to DevWorkspace statefulsets. As with the scale subresource, changing
+// the replicas of a restricted-access DevWorkspace's statefulset is only permitted for the creator of the DevWorkspace
+// and configured admin groups.
+func (h *WebhookHandler) ValidateStatefulSetOnUpdate(ctx context.Context, req admission.Request) admission.Response {
+	oldSS := &appsv1.StatefulSet{}
+	newSS := &appsv1.StatefulSet{}
+	if err := h.parse(req, oldSS, newSS); err != nil {
+		return admission.Denied(err.Error())
+	}
+	if equality.Semantic.DeepEqual(oldSS.Spec.Replicas, newSS.Spec.Replicas) {
+		return admission.Allowed("Replicas are not changed")
+	}
+
+	allowed, err := h.checkCreatorAccess(ctx, oldSS, req.UserInfo)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !allowed {
+		log.Info(fmt.Sprintf("Denied request to scale statefulsets '%s' by user %s", req.Name, req.UserInfo.Username))
+		return admission.Denied(scaleDeniedMessage)
+	}
+	return admission.Allowed("The current user and devworkspace are matched")
+}
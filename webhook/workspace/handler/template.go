@@ -45,7 +45,7 @@ func (h *WebhookHandler) MutateWorkspaceTemplateV1alpha2OnCreate(ctx context.Con
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	if err := h.validateKubernetesObjectPermissionsOnCreate(ctx, req, &wksp.Spec); err != nil {
+	if _, err := h.validateKubernetesObjectPermissionsOnCreate(ctx, req, &wksp.Spec); err != nil {
 		return admission.Denied(err.Error())
 	}
 
@@ -93,9 +93,13 @@ func (h *WebhookHandler) MutateWorkspaceTemplateV1alpha2OnUpdate(ctx context.Con
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	if err := h.validateKubernetesObjectPermissionsOnUpdate(ctx, req, &newWksp.Spec, &oldWksp.Spec); err != nil {
+	pinnedDigests, err := h.validateKubernetesObjectPermissionsOnUpdate(ctx, req, &newWksp.Spec, &oldWksp.Spec)
+	if err != nil {
 		return admission.Denied(err.Error())
 	}
+	if pinnedDigests {
+		return h.returnPatched(req, newWksp)
+	}
 
 	return admission.Allowed("new workspace has the same devworkspace as old one")
 }
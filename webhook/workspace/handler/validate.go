@@ -23,6 +23,8 @@ import (
 
 	dwv2 "github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	devfilevalidation "github.com/devfile/api/v2/pkg/validation"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
@@ -75,6 +77,20 @@ func (h *WebhookHandler) ValidateDevfile(ctx context.Context, req admission.Requ
 		}
 	}
 
+	// Existing DevWorkspaces may already contain content rejected by these checks, so only check them if the template is
+	// changed, to allow e.g. stopping or deleting such DevWorkspaces
+	templateChanged := true
+	if req.Operation == admissionv1.Update {
+		oldWksp := &dwv2.DevWorkspace{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, oldWksp); err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		templateChanged = !equality.Semantic.DeepEqual(oldWksp.Spec.Template, wksp.Spec.Template)
+	}
+	if templateChanged {
+		devfileErrors = append(devfileErrors, checkSpecSanity(workspace)...)
+	}
+
 	if len(devfileErrors) > 0 {
 		return admission.Denied(fmt.Sprintf("\n%s\n", strings.Join(devfileErrors, "\n")))
 	}
@@ -54,8 +54,12 @@ func checkUnsupportedFeatures(devWorkspaceSpec dwv2.DevWorkspaceTemplateSpec) (w
 			}
 		}
 
+		// Image components are only built from Dockerfiles in a Git repository or fetched from an absolute HTTP(S) URI
 		if component.Image != nil {
-			warnings.imageComponent[component.Name] = true
+			dockerfile := component.Image.Dockerfile
+			if dockerfile == nil || (dockerfile.Git == nil && !isHTTPURI(dockerfile.Uri)) {
+				warnings.imageComponent[component.Name] = true
+			}
 		}
 		if component.Custom != nil {
 			warnings.customComponent[component.Name] = true
@@ -71,6 +75,10 @@ func checkUnsupportedFeatures(devWorkspaceSpec dwv2.DevWorkspaceTemplateSpec) (w
 	return warnings
 }
 
+func isHTTPURI(uri string) bool {
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://")
+}
+
 func unsupportedWarningsPresent(warnings *unsupportedWarnings) bool {
 	return len(warnings.serviceAnnotations) > 0 ||
 		len(warnings.dedicatedPod) > 0 ||
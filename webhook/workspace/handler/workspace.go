@@ -52,13 +52,22 @@ func (h *WebhookHandler) MutateWorkspaceV1alpha2OnCreate(ctx context.Context, re
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	wksp.Labels = maputils.Append(wksp.Labels, constants.DevWorkspaceCreatorLabel, req.UserInfo.UID)
+	// DevWorkspaces created by the controller on behalf of a user, e.g. clones, keep the creator they were created with
+	if req.UserInfo.UID != h.ControllerUID || wksp.Labels[constants.DevWorkspaceCreatorLabel] == "" {
+		wksp.Labels = maputils.Append(wksp.Labels, constants.DevWorkspaceCreatorLabel, req.UserInfo.UID)
+		wksp.Annotations = maputils.Append(wksp.Annotations, constants.DevWorkspaceCreatorUsernameAnnotation, req.UserInfo.Username)
+		wksp.Annotations = maputils.Append(wksp.Annotations, constants.DevWorkspaceLastActorAnnotation, req.UserInfo.Username)
+	}
+	// Object names are recorded by the controller when the DevWorkspace's ID is assigned; names set by users are ignored
+	if req.UserInfo.UID != h.ControllerUID {
+		delete(wksp.Annotations, constants.DevWorkspaceObjectNamesAnnotation)
+	}
 
 	if err := h.validateUserPermissions(ctx, req, wksp, nil); err != nil {
 		return admission.Denied(err.Error())
 	}
 
-	if err := h.validateKubernetesObjectPermissionsOnCreate(ctx, req, &wksp.Spec.Template); err != nil {
+	if _, err := h.validateKubernetesObjectPermissionsOnCreate(ctx, req, &wksp.Spec.Template); err != nil {
 		return admission.Denied(err.Error())
 	}
 
@@ -66,6 +75,18 @@ func (h *WebhookHandler) MutateWorkspaceV1alpha2OnCreate(ctx context.Context, re
 		return admission.Denied(err.Error())
 	}
 
+	if err := h.validateDisabledFeaturesOnCreate(ctx, wksp); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if err := h.validateAttributeSchemas(ctx, wksp, nil); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if err := h.injectDefaults(ctx, wksp); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
 	if warnings := checkUnsupportedFeatures(wksp.Spec.Template); unsupportedWarningsPresent(warnings) {
 		return h.returnPatched(req, wksp).WithWarnings(formatUnsupportedFeaturesWarning(warnings))
 	}
@@ -170,7 +191,8 @@ func (h *WebhookHandler) MutateWorkspaceV1alpha2OnUpdate(ctx context.Context, re
 		return admission.Denied(err.Error())
 	}
 
-	if err := h.validateKubernetesObjectPermissionsOnUpdate(ctx, req, &newWksp.Spec.Template, &oldWksp.Spec.Template); err != nil {
+	pinnedDigests, err := h.validateKubernetesObjectPermissionsOnUpdate(ctx, req, &newWksp.Spec.Template, &oldWksp.Spec.Template)
+	if err != nil {
 		return admission.Denied(err.Error())
 	}
 
@@ -178,6 +200,30 @@ func (h *WebhookHandler) MutateWorkspaceV1alpha2OnUpdate(ctx context.Context, re
 		return admission.Denied(err.Error())
 	}
 
+	if err := h.validateDisabledFeaturesOnUpdate(ctx, newWksp, oldWksp); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if err := h.validateAttributeSchemas(ctx, newWksp, oldWksp); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	// DevWorkspaces created before the creator username annotation was introduced do not have it, so it is only
+	// required to be unchanged when it is already present.
+	restoredUsername := false
+	if oldUsername, found := oldWksp.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation]; found {
+		newUsername, found := newWksp.Annotations[constants.DevWorkspaceCreatorUsernameAnnotation]
+		if !found {
+			newWksp.Annotations = maputils.Append(newWksp.Annotations, constants.DevWorkspaceCreatorUsernameAnnotation, oldUsername)
+			restoredUsername = true
+		} else if newUsername != oldUsername {
+			return admission.Denied(fmt.Sprintf("annotation '%s' is assigned once devworkspace is created and is immutable", constants.DevWorkspaceCreatorUsernameAnnotation))
+		}
+	}
+
+	updatedLastActor := syncLastActor(oldWksp, newWksp, req.UserInfo.Username)
+	updatedObjectNames := syncObjectNames(oldWksp, newWksp, req.UserInfo.UID == h.ControllerUID)
+
 	oldCreator, found := oldWksp.Labels[constants.DevWorkspaceCreatorLabel]
 	if !found {
 		return admission.Denied(fmt.Sprintf("label '%s' is missing. Please recreate devworkspace to get it initialized", constants.DevWorkspaceCreatorLabel))
@@ -200,12 +246,57 @@ func (h *WebhookHandler) MutateWorkspaceV1alpha2OnUpdate(ctx context.Context, re
 		return admission.Denied(fmt.Sprintf("label '%s' is assigned once devworkspace is created and is immutable", constants.DevWorkspaceCreatorLabel))
 	}
 
+	if restoredUsername || updatedLastActor || updatedObjectNames || pinnedDigests {
+		response := h.returnPatched(req, newWksp)
+		if warnings != "" {
+			return response.WithWarnings(warnings)
+		}
+		return response
+	}
+
 	if warnings != "" {
 		return admission.Allowed("").WithWarnings(warnings)
 	}
 	return admission.Allowed("new workspace has the same devworkspace as old one")
 }
 
+// syncLastActor records the user that started or stopped a DevWorkspace in the last-actor annotation, which is used to
+// attribute audit records. The annotation can only be set by the webhook: for updates that do not start or stop the
+// DevWorkspace, any change to it is reverted. Returns whether the annotation was modified in newWksp.
+func syncLastActor(oldWksp, newWksp *dwv2.DevWorkspace, username string) bool {
+	expectedActor, hasActor := oldWksp.Annotations[constants.DevWorkspaceLastActorAnnotation]
+	if oldWksp.Spec.Started != newWksp.Spec.Started {
+		expectedActor, hasActor = username, true
+	}
+	actor, found := newWksp.Annotations[constants.DevWorkspaceLastActorAnnotation]
+	if actor == expectedActor && found == hasActor {
+		return false
+	}
+	if hasActor {
+		newWksp.Annotations = maputils.Append(newWksp.Annotations, constants.DevWorkspaceLastActorAnnotation, expectedActor)
+	} else {
+		delete(newWksp.Annotations, constants.DevWorkspaceLastActorAnnotation)
+	}
+	return true
+}
+
+// syncObjectNames keeps the object-names annotation unchanged once it is set. The annotation records the names of objects
+// created for the DevWorkspace and can only be added by the controller; any other change to it is reverted. Returns
+// whether the annotation was modified in newWksp.
+func syncObjectNames(oldWksp, newWksp *dwv2.DevWorkspace, isController bool) bool {
+	oldNames, hadNames := oldWksp.Annotations[constants.DevWorkspaceObjectNamesAnnotation]
+	newNames, hasNames := newWksp.Annotations[constants.DevWorkspaceObjectNamesAnnotation]
+	switch {
+	case hadNames && newNames != oldNames:
+		newWksp.Annotations = maputils.Append(newWksp.Annotations, constants.DevWorkspaceObjectNamesAnnotation, oldNames)
+		return true
+	case !hadNames && hasNames && !isController:
+		delete(newWksp.Annotations, constants.DevWorkspaceObjectNamesAnnotation)
+		return true
+	}
+	return false
+}
+
 func hasFinalizer(obj client.Object, finalizer string) bool {
 	for _, f := range obj.GetFinalizers() {
 		if f == finalizer {
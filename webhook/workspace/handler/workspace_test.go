@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2019-2024 Red Hat, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/devfile/devworkspace-operator/pkg/constants"
+)
+
+func TestSyncLastActor(t *testing.T) {
+	tests := []struct {
+		name            string
+		oldAnnotations  map[string]string
+		newAnnotations  map[string]string
+		startedChanged  bool
+		expectedActor   string
+		expectedPatched bool
+	}{
+		{
+			name:            "Sets last actor when DevWorkspace is started or stopped",
+			oldAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "old-user"},
+			newAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "old-user"},
+			startedChanged:  true,
+			expectedActor:   "test-user",
+			expectedPatched: true,
+		},
+		{
+			name:            "Keeps last actor for other updates",
+			oldAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "old-user"},
+			newAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "old-user"},
+			expectedActor:   "old-user",
+			expectedPatched: false,
+		},
+		{
+			name:            "Reverts changes to last actor",
+			oldAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "old-user"},
+			newAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "other-user"},
+			expectedActor:   "old-user",
+			expectedPatched: true,
+		},
+		{
+			name:            "Removes last actor added without starting or stopping DevWorkspace",
+			newAnnotations:  map[string]string{constants.DevWorkspaceLastActorAnnotation: "other-user"},
+			expectedPatched: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldWksp := getTestWorkspace("test-workspace", tt.oldAnnotations, nil)
+			newWksp := getTestWorkspace("test-workspace", tt.newAnnotations, nil)
+			newWksp.Spec.Started = tt.startedChanged
+
+			patched := syncLastActor(oldWksp, newWksp, "test-user")
+
+			assert.Equal(t, tt.expectedPatched, patched)
+			assert.Equal(t, tt.expectedActor, newWksp.Annotations[constants.DevWorkspaceLastActorAnnotation])
+		})
+	}
+}
+
+func TestSyncObjectNames(t *testing.T) {
+	const recordedNames = `{"deployment":"workspace1234","service":"workspace1234-service","routing":"routing-workspace1234","pvc":"storage-workspace1234"}`
+	tests := []struct {
+		name            string
+		oldAnnotations  map[string]string
+		newAnnotations  map[string]string
+		isController    bool
+		expectedNames   string
+		expectedPatched bool
+	}{
+		{
+			name:            "Allows controller to record object names",
+			newAnnotations:  map[string]string{constants.DevWorkspaceObjectNamesAnnotation: recordedNames},
+			isController:    true,
+			expectedNames:   recordedNames,
+			expectedPatched: false,
+		},
+		{
+			name:            "Removes object names added by users",
+			newAnnotations:  map[string]string{constants.DevWorkspaceObjectNamesAnnotation: recordedNames},
+			expectedPatched: true,
+		},
+		{
+			name:            "Reverts changes to object names",
+			oldAnnotations:  map[string]string{constants.DevWorkspaceObjectNamesAnnotation: recordedNames},
+			newAnnotations:  map[string]string{constants.DevWorkspaceObjectNamesAnnotation: `{"pvc":"other-pvc"}`},
+			isController:    true,
+			expectedNames:   recordedNames,
+			expectedPatched: true,
+		},
+		{
+			name:            "Restores removed object names",
+			oldAnnotations:  map[string]string{constants.DevWorkspaceObjectNamesAnnotation: recordedNames},
+			expectedNames:   recordedNames,
+			expectedPatched: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldWksp := getTestWorkspace("test-workspace", tt.oldAnnotations, nil)
+			newWksp := getTestWorkspace("test-workspace", tt.newAnnotations, nil)
+
+			patched := syncObjectNames(oldWksp, newWksp, tt.isController)
+
+			assert.Equal(t, tt.expectedPatched, patched)
+			assert.Equal(t, tt.expectedNames, newWksp.Annotations[constants.DevWorkspaceObjectNamesAnnotation])
+		})
+	}
+}
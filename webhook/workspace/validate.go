@@ -25,7 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
-// ResourcesValidator validates execs process all exec requests and:
+// ResourcesValidator validates execs process all exec and attach requests and:
 // if related pod DOES NOT have workspace_id label - just skip it
 // if related pod DOES have workspace_id label - make sure that exec is requested by workspace creator
 type ResourcesValidator struct {
@@ -37,12 +37,21 @@ func NewResourcesValidator(controllerUID, controllerSAName string) *ResourcesVal
 }
 
 func (v *ResourcesValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
-	if req.Kind == handler.V1PodExecOptionKind && req.Operation == admissionv1.Connect {
+	if (req.Kind == handler.V1PodExecOptionKind || req.Kind == handler.V1PodAttachOptionKind) && req.Operation == admissionv1.Connect {
 		return v.ValidateExecOnConnect(ctx, req)
 	}
+	if req.Kind == handler.AutoscalingV1ScaleKind && req.Operation == admissionv1.Update {
+		return v.ValidateScaleOnUpdate(ctx, req)
+	}
+	if req.Kind == handler.AppsV1StatefulSetKind && req.Operation == admissionv1.Update {
+		return v.ValidateStatefulSetOnUpdate(ctx, req)
+	}
 	if req.Kind == handler.V1alpha2DevWorkspaceKind && (req.Operation == admissionv1.Create || req.Operation == admissionv1.Update) {
 		return v.ValidateDevfile(ctx, req)
 	}
+	if (req.Kind == handler.V1alpha2DevWorkspaceKind || req.Kind == handler.V1PVCKind) && req.Operation == admissionv1.Delete {
+		return v.ValidateDeletion(ctx, req)
+	}
 
 	// Do not allow operation if the corresponding handler is not found
 	// It indicates that the webhooks configuration is not a valid or incompatible with this version of controller
@@ -58,6 +67,12 @@ func (v *ResourcesValidator) InjectClient(c client.Client) error {
 	return nil
 }
 
+// InjectAPIReader injects the API reader.
+func (v *ResourcesValidator) InjectAPIReader(r client.Reader) error {
+	v.APIReader = r
+	return nil
+}
+
 // WorkspaceMutator implements admission.DecoderInjector.
 // A decoder will be automatically injected.
 
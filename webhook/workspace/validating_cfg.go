@@ -19,6 +19,7 @@ import (
 	admregv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/devfile/devworkspace-operator/pkg/constants"
 	"github.com/devfile/devworkspace-operator/webhook/server"
 )
 
@@ -56,7 +57,64 @@ func buildValidatingWebhookCfg(namespace string) *admregv1.ValidatingWebhookConf
 						Rule: admregv1.Rule{
 							APIGroups:   []string{""},
 							APIVersions: []string{"v1"},
-							Resources:   []string{"pods/exec"},
+							Resources:   []string{"pods/exec", "pods/attach"},
+						},
+					},
+				},
+				AdmissionReviewVersions: []string{"v1beta1", "v1"},
+			},
+			{
+				Name:          "validate-scale.devworkspace-controller.svc",
+				FailurePolicy: &validateWebhookFailurePolicy,
+				SideEffects:   &sideEffectsNone,
+				ClientConfig: admregv1.WebhookClientConfig{
+					Service: &admregv1.ServiceReference{
+						Name:      server.WebhookServerServiceName,
+						Namespace: namespace,
+						Path:      &validateWebhookPath,
+					},
+					CABundle: server.CABundle,
+				},
+				Rules: []admregv1.RuleWithOperations{
+					{
+						Operations: []admregv1.OperationType{admregv1.Update},
+						Rule: admregv1.Rule{
+							APIGroups:   []string{"apps"},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"deployments/scale", "statefulsets/scale"},
+						},
+					},
+				},
+				AdmissionReviewVersions: []string{"v1beta1", "v1"},
+			},
+			{
+				Name:          "validate-statefulset.devworkspace-controller.svc",
+				FailurePolicy: &validateWebhookFailurePolicy,
+				SideEffects:   &sideEffectsNone,
+				ClientConfig: admregv1.WebhookClientConfig{
+					Service: &admregv1.ServiceReference{
+						Name:      server.WebhookServerServiceName,
+						Namespace: namespace,
+						Path:      &validateWebhookPath,
+					},
+					CABundle: server.CABundle,
+				},
+				// Only StatefulSets provisioned for DevWorkspaces need to be checked
+				ObjectSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      constants.DevWorkspaceIDLabel,
+							Operator: metav1.LabelSelectorOpExists,
+						},
+					},
+				},
+				Rules: []admregv1.RuleWithOperations{
+					{
+						Operations: []admregv1.OperationType{admregv1.Update},
+						Rule: admregv1.Rule{
+							APIGroups:   []string{"apps"},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"statefulsets"},
 						},
 					},
 				},
@@ -86,6 +144,63 @@ func buildValidatingWebhookCfg(namespace string) *admregv1.ValidatingWebhookConf
 				},
 				AdmissionReviewVersions: []string{"v1beta1", "v1"},
 			},
+			{
+				Name:          "validate-deletion.devworkspace-controller.svc",
+				FailurePolicy: &validateWebhookFailurePolicy,
+				SideEffects:   &sideEffectsNone,
+				ClientConfig: admregv1.WebhookClientConfig{
+					Service: &admregv1.ServiceReference{
+						Name:      server.WebhookServerServiceName,
+						Namespace: namespace,
+						Path:      &validateWebhookPath,
+					},
+					CABundle: server.CABundle,
+				},
+				Rules: []admregv1.RuleWithOperations{
+					{
+						Operations: []admregv1.OperationType{admregv1.Delete},
+						Rule: admregv1.Rule{
+							APIGroups:   []string{"workspace.devfile.io"},
+							APIVersions: []string{"v1alpha2"},
+							Resources:   []string{"devworkspaces"},
+						},
+					},
+				},
+				AdmissionReviewVersions: []string{"v1beta1", "v1"},
+			},
+			{
+				Name:          "validate-pvc-deletion.devworkspace-controller.svc",
+				FailurePolicy: &validateWebhookFailurePolicy,
+				SideEffects:   &sideEffectsNone,
+				ClientConfig: admregv1.WebhookClientConfig{
+					Service: &admregv1.ServiceReference{
+						Name:      server.WebhookServerServiceName,
+						Namespace: namespace,
+						Path:      &validateWebhookPath,
+					},
+					CABundle: server.CABundle,
+				},
+				// Only PVCs provisioned for DevWorkspaces need to be checked
+				ObjectSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      constants.DevWorkspacePVCTypeLabel,
+							Operator: metav1.LabelSelectorOpExists,
+						},
+					},
+				},
+				Rules: []admregv1.RuleWithOperations{
+					{
+						Operations: []admregv1.OperationType{admregv1.Delete},
+						Rule: admregv1.Rule{
+							APIGroups:   []string{""},
+							APIVersions: []string{"v1"},
+							Resources:   []string{"persistentvolumeclaims"},
+						},
+					},
+				},
+				AdmissionReviewVersions: []string{"v1beta1", "v1"},
+			},
 		},
 	}
 }